@@ -181,6 +181,13 @@ func (s *basicSuite) doRawGet(url string) (int, []byte) {
 	return resp.StatusCode, body
 }
 
+// timeTravel moves the application's clock forward by d, so retries scheduled
+// within d become due without waiting out their backoff. Time only moves
+// forward, and the jump persists for the rest of the suite.
+func (s *e2eSuite) timeTravel(d time.Duration) {
+	s.clock.Travel(d)
+}
+
 // waitForEventInLogstore polls until the event appears in the logstore API.
 // Returns the raw response body to preserve JSON key order.
 func (s *basicSuite) waitForEventInLogstore(eventID string) []byte {
@@ -2,6 +2,7 @@ package e2e_test
 
 import (
 	"net/http"
+	"time"
 
 	"github.com/hookdeck/outpost/internal/idgen"
 )
@@ -18,6 +19,10 @@ func (s *basicSuite) TestRetry_FailedDeliveryAutoRetries() {
 		withPublishMetadata(map[string]string{"should_err": "true"}),
 	)
 
+	// Skip the retry backoff rather than waiting it out.
+	s.waitForNewMockServerEvents(dest.mockID, 1)
+	s.timeTravel(time.Minute)
+
 	// Wait for at least 2 delivery attempts (initial + retry)
 	s.waitForNewMockServerEvents(dest.mockID, 2)
 
@@ -12,6 +12,7 @@ import (
 	"github.com/hookdeck/outpost/cmd/e2e/configs"
 	opeventsmock "github.com/hookdeck/outpost/cmd/e2e/opevents"
 	"github.com/hookdeck/outpost/internal/app"
+	"github.com/hookdeck/outpost/internal/clock"
 	"github.com/hookdeck/outpost/internal/config"
 	"github.com/hookdeck/outpost/internal/redis"
 	"github.com/hookdeck/outpost/internal/util/testinfra"
@@ -46,6 +47,9 @@ type e2eSuite struct {
 	mockServerInfra   *testinfra.MockServerInfra
 	cleanup           func()
 	appDone           chan struct{}
+	// clock drives retry scheduling and log retention in the application
+	// under test; see timeTravel.
+	clock *clock.Offset
 }
 
 func (suite *e2eSuite) SetupSuite() {
@@ -53,9 +57,10 @@ func (suite *e2eSuite) SetupSuite() {
 	suite.ctx = ctx
 	suite.cancel = cancel
 	suite.appDone = make(chan struct{})
+	suite.clock = clock.NewOffset()
 	go func() {
 		defer close(suite.appDone)
-		application := app.New(&suite.config)
+		application := app.New(&suite.config, app.WithClock(suite.clock))
 		if err := application.Run(suite.ctx); err != nil {
 			log.Println("Application failed to run", err)
 		}
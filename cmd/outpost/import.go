@@ -0,0 +1,162 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/importer"
+	"github.com/urfave/cli/v3"
+)
+
+// newImportCommand builds the `outpost import` command, which uploads an
+// export from another webhook service to a running Outpost's import API.
+func newImportCommand() *cli.Command {
+	sources := make([]string, len(importer.Sources))
+	for i, source := range importer.Sources {
+		sources[i] = string(source)
+	}
+	return &cli.Command{
+		Name:      "import",
+		Usage:     "Import tenants and destinations from another webhook service (" + strings.Join(sources, ", ") + ")",
+		ArgsUsage: "<source> <export.json>",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "api-url",
+				Usage:   "Base URL of the Outpost API",
+				Value:   "http://localhost:3333",
+				Sources: cli.EnvVars("OUTPOST_API_URL"),
+			},
+			&cli.StringFlag{
+				Name:     "api-key",
+				Usage:    "Outpost API key",
+				Sources:  cli.EnvVars("API_KEY"),
+				Required: true,
+			},
+			&cli.StringFlag{
+				Name:  "signing-secret",
+				Usage: "Signing secret of the exported project, for sources that do not include it in the export (hookdeck)",
+			},
+			&cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "Only validate the export",
+			},
+		},
+		Action: runImport,
+	}
+}
+
+func runImport(ctx context.Context, c *cli.Command) error {
+	if c.NArg() != 2 {
+		return errors.New("usage: outpost import <source> <export.json>")
+	}
+	source, path := c.Args().Get(0), c.Args().Get(1)
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read export: %w", err)
+	}
+	if secret := c.String("signing-secret"); secret != "" {
+		if data, err = withSigningSecret(data, secret); err != nil {
+			return err
+		}
+	}
+	// Parse locally first so a malformed export fails before any request.
+	if _, err := importer.Parse(importer.Source(source), data); err != nil {
+		return err
+	}
+
+	query := url.Values{"source": {source}, "dry_run": {strconv.FormatBool(c.Bool("dry-run"))}}
+	endpoint := strings.TrimSuffix(c.String("api-url"), "/") + "/api/v1/tenants/import?" + query.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.String("api-key"))
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("import request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("import request: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("import request: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+
+	var result apirouter.TenantImportResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return fmt.Errorf("decode import response: %w", err)
+	}
+	return printImportResult(os.Stdout, result)
+}
+
+// withSigningSecret sets the signing_secret field of a JSON export.
+func withSigningSecret(data []byte, secret string) ([]byte, error) {
+	var export map[string]json.RawMessage
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("invalid export: %w", err)
+	}
+	encoded, err := json.Marshal(secret)
+	if err != nil {
+		return nil, err
+	}
+	export["signing_secret"] = encoded
+	return json.Marshal(export)
+}
+
+// printImportResult writes a summary of the import and returns an error when
+// any tenant or destination failed.
+func printImportResult(w io.Writer, result apirouter.TenantImportResponse) error {
+	for _, warning := range result.Warnings {
+		fmt.Fprintf(w, "warning: %s\n", warning)
+	}
+
+	failed := 0
+	for _, tenant := range result.Tenants {
+		if tenant.Status == apirouter.TenantImportStatusFailed {
+			failed++
+			fmt.Fprintf(w, "tenant %s: failed: %s\n", tenant.ID, strings.Join(tenant.Errors, "; "))
+			continue
+		}
+		fmt.Fprintf(w, "tenant %s: %s, %d of %d destinations imported\n",
+			tenant.ID, tenant.Status, tenant.Destinations.Succeeded, tenant.Destinations.Total)
+		for _, destination := range tenant.Destinations.Results {
+			if destination.Status != apirouter.DestinationImportStatusFailed {
+				continue
+			}
+			failed++
+			fmt.Fprintf(w, "  destination %s: %s\n", firstNonEmpty(destination.ID, "#"+strconv.Itoa(destination.Row)), strings.Join(destination.Errors, "; "))
+		}
+	}
+
+	if result.DryRun {
+		fmt.Fprintln(w, "Dry run, nothing was created.")
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d tenants or destinations failed to import", failed)
+	}
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
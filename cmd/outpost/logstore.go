@@ -0,0 +1,226 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clickhouse"
+	"github.com/hookdeck/outpost/internal/config"
+	"github.com/hookdeck/outpost/internal/logarchive"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/logstore/tuning"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/urfave/cli/v3"
+)
+
+// newLogStoreCommand builds the `outpost logstore` subcommand tree for log
+// store schema maintenance.
+func newLogStoreCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "logstore",
+		Usage: "Log store schema tools",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to config file",
+				Sources: cli.EnvVars("CONFIG"),
+			},
+		},
+		Commands: []*cli.Command{
+			{
+				Name: "migrate-schema",
+				Usage: "Apply the LOGSTORE_* tuning (indexes, compression, PostgreSQL partitions) to existing log tables. " +
+					"Run after 'outpost migrate apply'; with PostgreSQL partitioning, run it regularly (e.g. daily) to create upcoming partitions.",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Only print the statements",
+					},
+					&cli.BoolFlag{
+						Name:  "rebuild-tables",
+						Usage: "ClickHouse only: recreate the log tables with the configured partition interval and index granularity, copying their rows. Meant for new installations",
+					},
+					&cli.BoolFlag{
+						Name:    "yes",
+						Aliases: []string{"y"},
+						Usage:   "Skip confirmation prompt",
+					},
+				},
+				Action: runLogStoreMigrateSchema,
+			},
+			{
+				Name: "restore",
+				Usage: "Restore archived delivery logs of a time range from the LOG_ARCHIVE_* bucket into the log store. " +
+					"Restoring a range twice is safe. Restored logs older than the log retention are pruned again on the next retention run.",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "from",
+						Usage:    "Start of the range, inclusive (RFC 3339 time or YYYY-MM-DD)",
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:     "to",
+						Usage:    "End of the range, exclusive (RFC 3339 time or YYYY-MM-DD)",
+						Required: true,
+					},
+					&cli.BoolFlag{
+						Name:    "yes",
+						Aliases: []string{"y"},
+						Usage:   "Skip confirmation prompt",
+					},
+				},
+				Action: runLogStoreRestore,
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			return cli.ShowSubcommandHelp(c)
+		},
+	}
+}
+
+func runLogStoreMigrateSchema(ctx context.Context, c *cli.Command) error {
+	cfg, err := config.Parse(config.Flags{Config: c.String("config")})
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	tuningCfg := cfg.LogStore.ToConfig()
+
+	var (
+		stmts []string
+		exec  func(ctx context.Context, stmt string) error
+	)
+	switch cfg.LogStoreDriver() {
+	case tuning.DriverPostgres:
+		if c.Bool("rebuild-tables") {
+			return fmt.Errorf("--rebuild-tables is only supported with ClickHouse")
+		}
+		stmts = tuning.PostgresStatements(tuningCfg, time.Now())
+		if !c.Bool("dry-run") {
+			pool, err := pgxpool.New(ctx, cfg.PostgresURL)
+			if err != nil {
+				return fmt.Errorf("connect to postgres: %w", err)
+			}
+			defer pool.Close()
+			exec = func(ctx context.Context, stmt string) error {
+				_, err := pool.Exec(ctx, stmt)
+				return err
+			}
+		}
+	case tuning.DriverClickHouse:
+		if c.Bool("rebuild-tables") {
+			stmts = tuning.ClickHouseRebuildStatements(tuningCfg, cfg.DeploymentID)
+		}
+		stmts = append(stmts, tuning.ClickHouseStatements(tuningCfg, cfg.DeploymentID)...)
+		if !c.Bool("dry-run") {
+			conn, err := clickhouse.New(cfg.ClickHouse.ToConfig())
+			if err != nil {
+				return fmt.Errorf("connect to clickhouse: %w", err)
+			}
+			defer conn.Close()
+			exec = func(ctx context.Context, stmt string) error {
+				return conn.Exec(ctx, stmt)
+			}
+		}
+	}
+	for _, stmt := range stmts {
+		fmt.Fprintln(os.Stdout, stmt+";")
+	}
+	if c.Bool("dry-run") {
+		return nil
+	}
+
+	if !c.Bool("yes") {
+		fmt.Fprintf(os.Stdout, "\nApply %d statements? [y/N]: ", len(stmts))
+		var response string
+		if _, err := fmt.Fscanln(os.Stdin, &response); err != nil {
+			fmt.Fprintln(os.Stdout, "Cancelled.")
+			return nil
+		}
+		if response != "y" && response != "Y" && response != "yes" {
+			fmt.Fprintln(os.Stdout, "Cancelled.")
+			return nil
+		}
+	}
+
+	for i, stmt := range stmts {
+		if err := exec(ctx, stmt); err != nil {
+			return fmt.Errorf("statement %d of %d failed: %w", i+1, len(stmts), err)
+		}
+	}
+	fmt.Fprintf(os.Stdout, "\nApplied %d statements.\n", len(stmts))
+	return nil
+}
+
+func runLogStoreRestore(ctx context.Context, c *cli.Command) error {
+	from, err := parseRestoreTime(c.String("from"))
+	if err != nil {
+		return fmt.Errorf("invalid --from: %w", err)
+	}
+	to, err := parseRestoreTime(c.String("to"))
+	if err != nil {
+		return fmt.Errorf("invalid --to: %w", err)
+	}
+	if !from.Before(to) {
+		return fmt.Errorf("--from must be before --to")
+	}
+
+	cfg, err := config.Parse(config.Flags{Config: c.String("config")})
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if cfg.LogArchive.Bucket == "" {
+		return fmt.Errorf("log_archive.bucket is not set")
+	}
+
+	store, err := logarchive.NewS3Store(ctx, cfg.LogArchive.ToConfig())
+	if err != nil {
+		return fmt.Errorf("create archive store: %w", err)
+	}
+
+	if !c.Bool("yes") {
+		fmt.Fprintf(os.Stdout, "Restore delivery logs from %s to %s? [y/N]: ",
+			from.Format(time.RFC3339), to.Format(time.RFC3339))
+		var response string
+		if _, err := fmt.Fscanln(os.Stdin, &response); err != nil {
+			fmt.Fprintln(os.Stdout, "Cancelled.")
+			return nil
+		}
+		if response != "y" && response != "Y" && response != "yes" {
+			fmt.Fprintln(os.Stdout, "Cancelled.")
+			return nil
+		}
+	}
+
+	driverOpts, err := logstore.MakeDriverOpts(logstore.Config{
+		ClickHouse:    cfg.ClickHouse.ToConfig(),
+		Postgres:      &cfg.PostgresURL,
+		DeploymentID:  cfg.DeploymentID,
+		HotTierMaxAge: cfg.HotTierMaxAge(),
+	})
+	if err != nil {
+		return fmt.Errorf("connect to log store: %w", err)
+	}
+	defer driverOpts.Close()
+	logStore, err := logstore.NewLogStore(ctx, driverOpts)
+	if err != nil {
+		return fmt.Errorf("create log store: %w", err)
+	}
+
+	result, err := logarchive.Restore(ctx, store, logStore, from, to)
+	if err != nil {
+		return fmt.Errorf("restore failed after %d records: %w", result.Records, err)
+	}
+	fmt.Fprintf(os.Stdout, "Restored %d records from %d chunks (%d days).\n", result.Records, result.Chunks, result.Days)
+	return nil
+}
+
+// parseRestoreTime accepts an RFC 3339 time or a UTC date.
+func parseRestoreTime(s string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	return time.Parse("2006-01-02", s)
+}
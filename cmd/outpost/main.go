@@ -17,14 +17,16 @@ func main() {
 		Usage:   "Outpost - Event delivery platform",
 		Version: version.Version(),
 		Commands: []*cli.Command{
-			{
-				Name:  "serve",
-				Usage: "Run the Outpost server",
-				Action: func(ctx context.Context, c *cli.Command) error {
-					return delegateToBinary("outpost-server", c)
-				},
-			},
+			newServeCommand(),
 			newMigrateCommand(),
+			newSecretsCommand(),
+			newReceiptsCommand(),
+			newLogStoreCommand(),
+			newImportCommand(),
+			newSeedCommand(),
+			newRedisCommand(),
+			newScaffoldCommand(),
+			newUpgradeCommand(),
 		},
 		Action: func(ctx context.Context, c *cli.Command) error {
 			// Default action - show help
@@ -33,21 +35,21 @@ func main() {
 	}
 
 	if err := app.Run(context.Background(), os.Args); err != nil {
+		fmt.Fprintf(os.Stderr, "%s\n", err)
 		os.Exit(1)
 	}
 }
 
-func delegateToBinary(binaryName string, c *cli.Command) error {
+// delegateToBinary runs the named binary with args, looking it up next to
+// this binary and then in PATH.
+func delegateToBinary(binaryName string, args []string) error {
 	// Find the binary
 	binary, err := findBinary(binaryName)
 	if err != nil {
 		// In development mode, fall back to go run
-		return runWithGo(binaryName, c)
+		return runWithGo(binaryName, args)
 	}
 
-	// Build arguments
-	args := buildArgs(c)
-
 	// Execute the binary
 	cmd := exec.Command(binary, args...)
 	cmd.Stdin = os.Stdin
@@ -57,7 +59,7 @@ func delegateToBinary(binaryName string, c *cli.Command) error {
 	return cmd.Run()
 }
 
-func runWithGo(binaryName string, c *cli.Command) error {
+func runWithGo(binaryName string, args []string) error {
 	// Map binary names to their cmd directories
 	cmdPath := map[string]string{
 		"outpost-server": "./cmd/outpost-server",
@@ -68,19 +70,14 @@ func runWithGo(binaryName string, c *cli.Command) error {
 		return fmt.Errorf("unknown binary: %s", binaryName)
 	}
 
-	// Build arguments for go run
-	args := []string{"run", path}
-	args = append(args, buildArgs(c)...)
-
 	// Execute with go run
-	cmd := exec.Command("go", args...)
+	cmd := exec.Command("go", append([]string{"run", path}, args...)...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
 	return cmd.Run()
 }
-
 func findBinary(name string) (string, error) {
 	// First, try to find it in the same directory as this binary
 	execPath, err := os.Executable()
@@ -99,68 +96,3 @@ func findBinary(name string) (string, error) {
 
 	return "", fmt.Errorf("binary %s not found in the same directory or PATH", name)
 }
-
-func buildArgs(c *cli.Command) []string {
-	// If SkipFlagParsing is set, just pass through all raw arguments
-	if c.Command("") != nil && c.Command("").SkipFlagParsing {
-		// Get all args after the command name
-		return c.Args().Slice()
-	}
-
-	var args []string
-
-	// Get the root command
-	root := c.Root()
-
-	// Add global flags from root context if they are set
-	for _, flag := range root.Flags {
-		flagName := flag.Names()[0]
-		if c.IsSet(flagName) {
-			switch flag.(type) {
-			case *cli.StringFlag:
-				if val := c.String(flagName); val != "" {
-					args = append(args, fmt.Sprintf("--%s=%s", flagName, val))
-				}
-			case *cli.BoolFlag:
-				if c.Bool(flagName) {
-					args = append(args, fmt.Sprintf("--%s", flagName))
-				}
-			case *cli.IntFlag:
-				if c.IsSet(flagName) {
-					args = append(args, fmt.Sprintf("--%s=%d", flagName, c.Int(flagName)))
-				}
-			}
-		}
-	}
-
-	// Get the current command being executed
-	currentCmd := c.Command("") // Get current command info
-
-	// Add command-specific flags
-	if currentCmd != nil && currentCmd.Flags != nil {
-		for _, flag := range currentCmd.Flags {
-			flagName := flag.Names()[0]
-			if c.IsSet(flagName) {
-				switch flag.(type) {
-				case *cli.StringFlag:
-					if val := c.String(flagName); val != "" {
-						args = append(args, fmt.Sprintf("--%s=%s", flagName, val))
-					}
-				case *cli.BoolFlag:
-					if c.Bool(flagName) {
-						args = append(args, fmt.Sprintf("--%s", flagName))
-					}
-				case *cli.IntFlag:
-					args = append(args, fmt.Sprintf("--%s=%d", flagName, c.Int(flagName)))
-				}
-			}
-		}
-	}
-
-	// Pass through any additional arguments
-	if c.NArg() > 0 {
-		args = append(args, c.Args().Slice()...)
-	}
-
-	return args
-}
@@ -64,6 +64,18 @@ func newMigrateCommand() *cli.Command {
 				},
 				Action: runMigrateApply,
 			},
+			{
+				Name:  "redis",
+				Usage: "Apply pending Redis migrations only (same as apply --redis-only)",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:    "yes",
+						Aliases: []string{"y"},
+						Usage:   "Skip confirmation prompt",
+					},
+				},
+				Action: runMigrateApply,
+			},
 			{
 				Name:   "verify",
 				Usage:  "Verify that migrations were applied correctly",
@@ -90,7 +102,8 @@ func newMigrateCommand() *cli.Command {
 
 // withCoordinator loads config, constructs all the migration subsystem
 // inputs, builds a Coordinator, and invokes fn. It centralizes setup so
-// each subcommand action stays short.
+// each subcommand action stays short. With a tiered log store, fn runs
+// again with a SQL-only Coordinator over the ClickHouse log tier.
 func withCoordinator(ctx context.Context, c *cli.Command, fn func(*coordinator.Coordinator) error) error {
 	configPath := c.String("config")
 	verbose := c.Bool("verbose")
@@ -100,14 +113,55 @@ func withCoordinator(ctx context.Context, c *cli.Command, fn func(*coordinator.C
 		return fmt.Errorf("load config: %w", err)
 	}
 
+	logger, err := newMigrateLogger(verbose)
+	if err != nil {
+		return err
+	}
+
+	withLogTier := c.Name != "unlock" && c.Name != "redis"
+	coord, tierCoord, closeAll, err := newCoordinators(ctx, cfg, logger, verbose, withLogTier)
+	if err != nil {
+		return err
+	}
+	defer closeAll()
+
+	if err := fn(coord); err != nil {
+		return err
+	}
+	if tierCoord == nil {
+		return nil
+	}
+	fmt.Fprintln(os.Stdout, "\nClickHouse log tier:")
+	return fn(tierCoord)
+}
+
+func newMigrateLogger(verbose bool) (*logging.Logger, error) {
 	logLevel := "info"
 	if verbose {
 		logLevel = "debug"
 	}
 	logger, err := logging.NewLogger(logging.WithLogLevel(logLevel))
 	if err != nil {
-		return fmt.Errorf("create logger: %w", err)
+		return nil, fmt.Errorf("create logger: %w", err)
+	}
+	return logger, nil
+}
+
+// newCoordinators builds the Coordinator of cfg and, when withLogTier is set
+// and the log store is tiered, the SQL-only Coordinator of the ClickHouse log
+// tier. closeAll releases the connections they hold.
+func newCoordinators(ctx context.Context, cfg *config.Config, logger *logging.Logger, verbose, withLogTier bool) (coord, tierCoord *coordinator.Coordinator, closeAll func(), err error) {
+	var closers []func()
+	closeAll = func() {
+		for i := len(closers) - 1; i >= 0; i-- {
+			closers[i]()
+		}
 	}
+	defer func() {
+		if err != nil {
+			closeAll()
+		}
+	}()
 
 	// SQL migrator is optional — construct it only if a database URL is
 	// configured. Config validation happens later, so we tolerate missing
@@ -117,27 +171,27 @@ func withCoordinator(ctx context.Context, c *cli.Command, fn func(*coordinator.C
 	if opts.PG.URL != "" || opts.CH.Addr != "" {
 		sqlMigrator, err = migrator.New(opts)
 		if err != nil {
-			return fmt.Errorf("create sql migrator: %w", err)
+			return nil, nil, nil, fmt.Errorf("create sql migrator: %w", err)
 		}
-		defer func() {
+		closers = append(closers, func() {
 			if sourceErr, dbErr := sqlMigrator.Close(ctx); sourceErr != nil || dbErr != nil {
 				logger.Warn("failed to close sql migrator")
 			}
-		}()
+		})
 	}
 
 	// Redis client + migrations.
 	redisClient, err := redis.New(ctx, cfg.Redis.ToConfig())
 	if err != nil {
-		return fmt.Errorf("connect to redis: %w", err)
+		return nil, nil, nil, fmt.Errorf("connect to redis: %w", err)
 	}
-	defer redisClient.Close()
+	closers = append(closers, func() { redisClient.Close() })
 
 	redisMigrations := migrations.AllRedisMigrationsWithLogging(
 		redisClient, logger, verbose, cfg.DeploymentID,
 	)
 
-	coord := coordinator.New(coordinator.Config{
+	coord = coordinator.New(coordinator.Config{
 		SQLMigrator:     sqlMigrator,
 		RedisClient:     redisClient,
 		RedisMigrations: redisMigrations,
@@ -145,7 +199,24 @@ func withCoordinator(ctx context.Context, c *cli.Command, fn func(*coordinator.C
 		Logger:          logger,
 	})
 
-	return fn(coord)
+	tierOpts, ok := cfg.ToLogTierMigratorOpts()
+	if !ok || !withLogTier {
+		return coord, nil, closeAll, nil
+	}
+	tierMigrator, err := migrator.New(tierOpts)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("create log tier sql migrator: %w", err)
+	}
+	closers = append(closers, func() {
+		if sourceErr, dbErr := tierMigrator.Close(ctx); sourceErr != nil || dbErr != nil {
+			logger.Warn("failed to close log tier sql migrator")
+		}
+	})
+	tierCoord = coordinator.New(coordinator.Config{
+		SQLMigrator: tierMigrator,
+		Logger:      logger,
+	})
+	return coord, tierCoord, closeAll, nil
 }
 
 func runMigrateList(ctx context.Context, c *cli.Command) error {
@@ -200,7 +271,7 @@ func runMigrateApply(ctx context.Context, c *cli.Command) error {
 
 		opts := coordinator.ApplyOptions{
 			SQLOnly:   c.Bool("sql-only"),
-			RedisOnly: c.Bool("redis-only"),
+			RedisOnly: c.Bool("redis-only") || c.Name == "redis",
 		}
 		if err := coord.Apply(ctx, opts); err != nil {
 			return err
@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hookdeck/outpost/internal/receipts"
+	"github.com/urfave/cli/v3"
+)
+
+// newReceiptsCommand builds the `outpost receipts` subcommand tree for signing
+// keys and verifying delivery receipts.
+func newReceiptsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "receipts",
+		Usage: "Delivery receipt signing and verification tools",
+		Commands: []*cli.Command{
+			{
+				Name:   "keygen",
+				Usage:  "Generate an Ed25519 key pair for signing receipts (RECEIPTS_SIGNING_KEY)",
+				Action: runReceiptsKeygen,
+			},
+			{
+				Name:      "verify",
+				Usage:     "Verify the digest and signature of receipt files",
+				ArgsUsage: "<receipt.json>...",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "public-key",
+						Usage:    "Base64-encoded Ed25519 public key of the deployment, or @path to a file containing it",
+						Sources:  cli.EnvVars("RECEIPTS_PUBLIC_KEY"),
+						Required: true,
+					},
+				},
+				Action: runReceiptsVerify,
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			return cli.ShowSubcommandHelp(c)
+		},
+	}
+}
+
+func runReceiptsKeygen(ctx context.Context, c *cli.Command) error {
+	signingKey, publicKey, err := receipts.GenerateSigningKey()
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stdout, "RECEIPTS_SIGNING_KEY=%s\n", signingKey)
+	fmt.Fprintf(os.Stdout, "Public key (share with auditors): %s\n", publicKey)
+	return nil
+}
+
+func runReceiptsVerify(ctx context.Context, c *cli.Command) error {
+	if c.NArg() == 0 {
+		return errors.New("at least one receipt file is required")
+	}
+
+	encodedKey := c.String("public-key")
+	if path, ok := strings.CutPrefix(encodedKey, "@"); ok {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("read public key: %w", err)
+		}
+		encodedKey = strings.TrimSpace(string(b))
+	}
+	publicKey, err := receipts.ParsePublicKey(encodedKey)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, path := range c.Args().Slice() {
+		receipt, err := readReceipt(path)
+		if err == nil {
+			err = receipts.Verify(receipt, publicKey)
+		}
+		if err != nil {
+			failed++
+			fmt.Fprintf(os.Stdout, "FAIL %s: %v\n", path, err)
+			continue
+		}
+		fmt.Fprintf(os.Stdout, "OK   %s (tenant %s, %s, %d deliveries)\n", path, receipt.TenantID, receipt.Date, receipt.Count)
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d receipts failed verification", failed, c.NArg())
+	}
+	return nil
+}
+
+func readReceipt(path string) (*receipts.Receipt, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var receipt receipts.Receipt
+	if err := json.Unmarshal(b, &receipt); err != nil {
+		return nil, fmt.Errorf("invalid receipt: %w", err)
+	}
+	return &receipt, nil
+}
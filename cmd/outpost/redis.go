@@ -0,0 +1,122 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/hookdeck/outpost/internal/config"
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/redismemory"
+	"github.com/urfave/cli/v3"
+)
+
+// newRedisCommand builds the `outpost redis` subcommand tree for Redis
+// capacity tools.
+func newRedisCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "redis",
+		Usage: "Redis capacity tools",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to config file",
+				Sources: cli.EnvVars("CONFIG"),
+			},
+		},
+		Commands: []*cli.Command{
+			{
+				Name: "memory",
+				Usage: "Report the memory used by each family of Outpost keys (tenants, destinations, retries, queues...), " +
+					"estimated by running MEMORY USAGE on a sample of the keys. The scan uses SCAN and is safe to run against production.",
+				Flags: []cli.Flag{
+					&cli.IntFlag{
+						Name:  "sample-every",
+						Usage: "Measure one in every N keys of each family. 1 measures every key",
+						Value: redismemory.DefaultSampleEvery,
+					},
+					&cli.IntFlag{
+						Name:  "max-keys",
+						Usage: "Stop after scanning this many keys. 0 scans every key",
+					},
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print the report as JSON",
+					},
+				},
+				Action: runRedisMemory,
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			return cli.ShowSubcommandHelp(c)
+		},
+	}
+}
+
+func runRedisMemory(ctx context.Context, c *cli.Command) error {
+	if c.Int("sample-every") < 1 {
+		return fmt.Errorf("--sample-every must be at least 1")
+	}
+	if c.Int("max-keys") < 0 {
+		return fmt.Errorf("--max-keys must not be negative")
+	}
+
+	cfg, err := config.Parse(config.Flags{Config: c.String("config")})
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	redisClient, err := redis.New(ctx, cfg.Redis.ToConfig())
+	if err != nil {
+		return fmt.Errorf("connect to redis: %w", err)
+	}
+	defer redisClient.Close()
+
+	report, err := redismemory.New(redisClient, cfg.DeploymentID).Analyze(ctx, redismemory.Options{
+		SampleEvery: int(c.Int("sample-every")),
+		MaxKeys:     int(c.Int("max-keys")),
+	})
+	if err != nil {
+		return err
+	}
+
+	if c.Bool("json") {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	printRedisMemoryReport(report)
+	return nil
+}
+
+func printRedisMemoryReport(report *redismemory.Report) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	fmt.Fprintln(w, "FAMILY\tKEYS\tSAMPLED\tESTIMATED\tSHARE\t")
+	for _, family := range report.Families {
+		share := 0.0
+		if report.EstimatedBytes > 0 {
+			share = float64(family.EstimatedBytes) / float64(report.EstimatedBytes) * 100
+		}
+		fmt.Fprintf(w, "%s\t%d\t%d\t%s\t%.1f%%\t\n", family.Family, family.Keys, family.SampledKeys, formatBytes(family.EstimatedBytes), share)
+	}
+	fmt.Fprintf(w, "total\t%d\t%d\t%s\t\t\n", report.ScannedKeys, report.SampledKeys, formatBytes(report.EstimatedBytes))
+	w.Flush()
+	if report.Truncated {
+		fmt.Fprintf(os.Stdout, "\nStopped after %d keys: the report only covers part of the keyspace.\n", report.ScannedKeys)
+	}
+}
+
+func formatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
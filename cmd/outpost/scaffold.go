@@ -0,0 +1,164 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hookdeck/outpost/internal/config"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/scaffold"
+	"github.com/urfave/cli/v3"
+)
+
+// newScaffoldCommand builds the `outpost scaffold` subcommand tree for
+// generating code that integrates with this deployment.
+func newScaffoldCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "scaffold",
+		Usage: "Generate code that integrates with this deployment",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to config file",
+				Sources: cli.EnvVars("CONFIG"),
+			},
+		},
+		Commands: []*cli.Command{
+			{
+				Name: "consumer",
+				Usage: "Generate a webhook consumer endpoint that verifies this deployment's signatures " +
+					"and dispatches events to one handler per configured topic",
+				Flags: []cli.Flag{
+					&cli.StringFlag{
+						Name:     "language",
+						Aliases:  []string{"l"},
+						Usage:    "Language of the consumer: " + strings.Join(scaffold.Languages, ", "),
+						Required: true,
+					},
+					&cli.StringFlag{
+						Name:    "out",
+						Aliases: []string{"o"},
+						Usage:   "Directory to write the consumer to",
+						Value:   "outpost-consumer",
+					},
+					&cli.StringSliceFlag{
+						Name:  "topics",
+						Usage: "Topics to generate handlers for. Defaults to the configured topics",
+					},
+					&cli.StringFlag{
+						Name: "scheme",
+						Usage: "Signature scheme of the destinations the consumer receives from: " +
+							"'default' follows the deployment's webhook config, 'stripe' and 'svix' match the destination signature_scheme",
+						Value: destwebhook.SignatureSchemeDefault,
+					},
+					&cli.BoolFlag{
+						Name:  "force",
+						Usage: "Overwrite existing files",
+					},
+				},
+				Action: runScaffoldConsumer,
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			return cli.ShowSubcommandHelp(c)
+		},
+	}
+}
+
+func runScaffoldConsumer(ctx context.Context, c *cli.Command) error {
+	cfg, err := config.Parse(config.Flags{Config: c.String("config")})
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	webhook := &cfg.Destinations.Webhook
+
+	signature, err := consumerSignature(webhook, c.String("scheme"))
+	if err != nil {
+		return err
+	}
+	topics := c.StringSlice("topics")
+	if len(topics) == 0 {
+		topics = cfg.Topics
+	}
+
+	files, err := scaffold.Generate(scaffold.Options{
+		Language:      c.String("language"),
+		Topics:        topics,
+		Signature:     signature,
+		EventIDHeader: webhook.HeaderName("event-id"),
+		TopicHeader:   webhook.HeaderName("topic"),
+	})
+	if err != nil {
+		return err
+	}
+
+	out := c.String("out")
+	if !c.Bool("force") {
+		for _, file := range files {
+			path := filepath.Join(out, file.Path)
+			if _, err := os.Stat(path); err == nil {
+				return fmt.Errorf("%s already exists, use --force to overwrite it", path)
+			} else if !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	if err := os.MkdirAll(out, 0o755); err != nil {
+		return err
+	}
+	for _, file := range files {
+		path := filepath.Join(out, file.Path)
+		if err := os.WriteFile(path, file.Content, 0o644); err != nil {
+			return err
+		}
+		fmt.Printf("wrote %s\n", path)
+	}
+	return nil
+}
+
+// consumerSignature returns how deliveries to destinations with the given
+// signature scheme are signed.
+func consumerSignature(webhook *config.DestinationWebhookConfig, scheme string) (scaffold.Signature, error) {
+	switch scheme {
+	case destwebhook.SignatureSchemeStripe:
+		return scaffold.Signature{Scheme: scaffold.SchemeStripe, Header: "Stripe-Signature"}, nil
+	case destwebhook.SignatureSchemeSvix:
+		return scaffold.Signature{
+			Scheme:          scaffold.SchemeStandard,
+			Header:          "svix-signature",
+			IDHeader:        "svix-id",
+			TimestampHeader: "svix-timestamp",
+		}, nil
+	case destwebhook.SignatureSchemeDefault:
+	default:
+		return scaffold.Signature{}, fmt.Errorf("unknown --scheme %q: must be default, stripe or svix", scheme)
+	}
+
+	if webhook.Mode == "standard" {
+		return scaffold.Signature{
+			Scheme:          scaffold.SchemeStandard,
+			Header:          webhook.HeaderName("signature"),
+			IDHeader:        webhook.HeaderName("event-id"),
+			TimestampHeader: webhook.HeaderName("timestamp"),
+		}, nil
+	}
+	return scaffold.NewDefaultSignature(
+		webhook.HeaderName("signature"),
+		valueOr(webhook.SignatureContentTemplate, destwebhook.DefaultSignatureContentTmpl),
+		valueOr(webhook.SignatureHeaderTemplate, destwebhook.DefaultSignatureHeaderTmpl),
+		valueOr(webhook.SignatureAlgorithm, destwebhook.DefaultAlgorithm),
+		valueOr(webhook.SignatureEncoding, destwebhook.DefaultEncoding),
+	)
+}
+
+func valueOr(value, fallback string) string {
+	if value == "" {
+		return fallback
+	}
+	return value
+}
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/hookdeck/outpost/internal/config"
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/urfave/cli/v3"
+)
+
+// newSecretsCommand builds the `outpost secrets` subcommand tree for managing
+// the encryption of data at rest.
+func newSecretsCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "secrets",
+		Usage: "Encryption secret management tools",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to config file",
+				Sources: cli.EnvVars("CONFIG"),
+			},
+		},
+		Commands: []*cli.Command{
+			{
+				Name: "reencrypt",
+				Usage: "Re-encrypt stored destination credentials with the primary encryption key " +
+					"(AES_ENCRYPTION_PRIMARY_KEY_ID, or AES_ENCRYPTION_SECRET when no key ring is configured).",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "dry-run",
+						Usage: "Only report how many destinations need re-encryption",
+					},
+					&cli.BoolFlag{
+						Name:    "yes",
+						Aliases: []string{"y"},
+						Usage:   "Skip confirmation prompt",
+					},
+				},
+				Action: runSecretsReEncrypt,
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			return cli.ShowSubcommandHelp(c)
+		},
+	}
+}
+
+func runSecretsReEncrypt(ctx context.Context, c *cli.Command) error {
+	cfg, err := config.Parse(config.Flags{Config: c.String("config")})
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	if cfg.AESEncryptionSecret == "" {
+		return config.ErrMissingAESSecret
+	}
+	encryptionKeys, err := cfg.AESEncryptionKeyRing()
+	if err != nil {
+		return err
+	}
+	if len(cfg.AESEncryptionPreviousSecrets) == 0 && len(encryptionKeys) == 0 {
+		return errors.New("nothing to rotate: set AES_ENCRYPTION_PREVIOUS_SECRETS or AES_ENCRYPTION_KEYS")
+	}
+
+	redisClient, err := redis.New(ctx, cfg.Redis.ToConfig())
+	if err != nil {
+		return fmt.Errorf("connect to redis: %w", err)
+	}
+	defer redisClient.Close()
+
+	store := tenantstore.New(tenantstore.Config{
+		RedisClient:     redisClient,
+		Secret:          cfg.AESEncryptionSecret,
+		PreviousSecrets: cfg.AESEncryptionPreviousSecrets,
+		EncryptionKeys:  encryptionKeys,
+		PrimaryKeyID:    cfg.AESEncryptionPrimaryKeyID,
+		DeploymentID:    cfg.DeploymentID,
+	})
+	reEncrypter, ok := store.(tenantstore.CredentialReEncrypter)
+	if !ok {
+		return errors.New("tenant store does not support re-encryption")
+	}
+
+	plan, err := reEncrypter.ReEncryptCredentials(ctx, tenantstore.ReEncryptOptions{DryRun: true})
+	if err != nil {
+		return err
+	}
+	printReEncryptResult(plan, true)
+	if c.Bool("dry-run") || plan.ReEncrypted == 0 {
+		return nil
+	}
+
+	if !c.Bool("yes") {
+		fmt.Fprintf(os.Stdout, "\nRe-encrypt %d destinations? [y/N]: ", plan.ReEncrypted)
+		var response string
+		if _, err := fmt.Fscanln(os.Stdin, &response); err != nil {
+			fmt.Fprintln(os.Stdout, "Cancelled.")
+			return nil
+		}
+		if response != "y" && response != "Y" && response != "yes" {
+			fmt.Fprintln(os.Stdout, "Cancelled.")
+			return nil
+		}
+	}
+
+	result, err := reEncrypter.ReEncryptCredentials(ctx, tenantstore.ReEncryptOptions{})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stdout)
+	printReEncryptResult(result, false)
+	if result.Failed > 0 {
+		return fmt.Errorf("%d destinations could not be decrypted with the current or previous secrets", result.Failed)
+	}
+	fmt.Fprintln(os.Stdout, "\nAll destinations use the primary key. Previous secrets and retired key ring entries can now be removed.")
+	return nil
+}
+
+func printReEncryptResult(result *tenantstore.ReEncryptResult, dryRun bool) {
+	verb := "Re-encrypted"
+	if dryRun {
+		verb = "Need re-encryption"
+	}
+	fmt.Fprintf(os.Stdout, "Records scanned (destinations and versions): %d\n", result.Scanned)
+	fmt.Fprintf(os.Stdout, "%s: %d\n", verb, result.ReEncrypted)
+	if result.Failed > 0 {
+		fmt.Fprintf(os.Stdout, "Undecryptable: %d\n", result.Failed)
+	}
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hookdeck/outpost/internal/seed"
+	"github.com/urfave/cli/v3"
+)
+
+// newSeedCommand builds the `outpost seed` command, which fills a running
+// Outpost with test tenants and destinations through its API.
+func newSeedCommand() *cli.Command {
+	defaults := seed.DefaultOptions()
+	return &cli.Command{
+		Name:  "seed",
+		Usage: "Create test tenants and destinations through the Outpost API",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "api-url",
+				Usage:   "Base URL of the Outpost API",
+				Value:   "http://localhost:3333",
+				Sources: cli.EnvVars("OUTPOST_API_URL"),
+			},
+			&cli.StringFlag{
+				Name:    "api-key",
+				Usage:   "Outpost API key",
+				Value:   defaults.APIKey,
+				Sources: cli.EnvVars("API_KEY"),
+			},
+			&cli.IntFlag{
+				Name:  "tenants",
+				Usage: "Number of tenants to create",
+				Value: defaults.Tenants,
+			},
+			&cli.IntFlag{
+				Name:  "min-destinations",
+				Usage: "Minimum destinations per tenant",
+				Value: defaults.MinDestinations,
+			},
+			&cli.IntFlag{
+				Name:  "max-destinations",
+				Usage: "Maximum destinations per tenant",
+				Value: defaults.MaxDestinations,
+			},
+			&cli.IntFlag{
+				Name:  "concurrency",
+				Usage: "Number of concurrent workers",
+				Value: defaults.Concurrency,
+			},
+			&cli.BoolFlag{
+				Name:  "verbose",
+				Usage: "Enable verbose output",
+			},
+			&cli.BoolFlag{
+				Name:    "yes",
+				Aliases: []string{"y"},
+				Usage:   "Skip confirmation prompt",
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			return seed.Run(ctx, seed.Options{
+				ServerURL:       strings.TrimSuffix(c.String("api-url"), "/") + "/api/v1",
+				APIKey:          c.String("api-key"),
+				Tenants:         int(c.Int("tenants")),
+				MinDestinations: int(c.Int("min-destinations")),
+				MaxDestinations: int(c.Int("max-destinations")),
+				Concurrency:     int(c.Int("concurrency")),
+				Verbose:         c.Bool("verbose"),
+				SkipConfirm:     c.Bool("yes"),
+			})
+		},
+	}
+}
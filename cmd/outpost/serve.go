@@ -0,0 +1,63 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hookdeck/outpost/internal/app"
+	"github.com/hookdeck/outpost/internal/config"
+	"github.com/urfave/cli/v3"
+)
+
+// newServeCommand builds the `outpost serve` command. The server runs in
+// this process so a single binary is enough, e.g. in distroless images
+// where there is no PATH to look up outpost-server in. --delegate keeps
+// the previous behavior of exec'ing a separate outpost-server binary.
+func newServeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "serve",
+		Usage: "Run the Outpost server",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to config file",
+				Sources: cli.EnvVars("CONFIG"),
+			},
+			&cli.StringFlag{
+				Name:  "service",
+				Usage: "Service to run (api, delivery, log, archiver). If empty, all services will run",
+			},
+			&cli.BoolFlag{
+				Name:    "delegate",
+				Usage:   "Run the server through a separate outpost-server binary",
+				Sources: cli.EnvVars("OUTPOST_SERVE_DELEGATE"),
+			},
+		},
+		Action: runServe,
+	}
+}
+
+func runServe(ctx context.Context, c *cli.Command) error {
+	flags := config.Flags{
+		Config:  c.String("config"),
+		Service: c.String("service"),
+	}
+
+	if c.Bool("delegate") {
+		var args []string
+		if flags.Config != "" {
+			args = append(args, "--config="+flags.Config)
+		}
+		if flags.Service != "" {
+			args = append(args, "--service="+flags.Service)
+		}
+		return delegateToBinary("outpost-server", append(args, c.Args().Slice()...))
+	}
+
+	cfg, err := config.Parse(flags)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	return app.New(cfg).Run(ctx)
+}
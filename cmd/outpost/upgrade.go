@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/hookdeck/outpost/internal/config"
+	"github.com/hookdeck/outpost/internal/upgradecheck"
+	"github.com/hookdeck/outpost/internal/version"
+	"github.com/urfave/cli/v3"
+)
+
+// newUpgradeCommand builds the `outpost upgrade` subcommand tree.
+func newUpgradeCommand() *cli.Command {
+	return &cli.Command{
+		Name:  "upgrade",
+		Usage: "Upgrade assistant",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:    "config",
+				Aliases: []string{"c"},
+				Usage:   "Path to config file",
+				Sources: cli.EnvVars("CONFIG"),
+			},
+			&cli.BoolFlag{
+				Name:  "verbose",
+				Usage: "Enable verbose logging",
+			},
+		},
+		Commands: []*cli.Command{
+			{
+				Name: "check",
+				Usage: "Check the Redis schema, log store schema and config against this version, " +
+					"and print the actions upgrading to it takes, in order. Run it with the binary of the target version before deploying it.",
+				Flags: []cli.Flag{
+					&cli.BoolFlag{
+						Name:  "json",
+						Usage: "Print the report as JSON",
+					},
+				},
+				Action: runUpgradeCheck,
+			},
+		},
+		Action: func(ctx context.Context, c *cli.Command) error {
+			return cli.ShowSubcommandHelp(c)
+		},
+	}
+}
+
+func runUpgradeCheck(ctx context.Context, c *cli.Command) error {
+	flags := config.Flags{Config: c.String("config")}
+	cfg, err := config.ParseUnvalidated(flags)
+	if err != nil {
+		return fmt.Errorf("load config: %w", err)
+	}
+	// An invalid config is reported as a step of the upgrade. The stores are
+	// still reached with it to inspect their schemas.
+	configErr := cfg.Validate(flags)
+
+	logger, err := newMigrateLogger(c.Bool("verbose"))
+	if err != nil {
+		return err
+	}
+	coord, tierCoord, closeAll, err := newCoordinators(ctx, cfg, logger, c.Bool("verbose"), true)
+	if err != nil {
+		return err
+	}
+	defer closeAll()
+
+	in := upgradecheck.Input{
+		TargetVersion: version.Version(),
+		Config:        cfg,
+		ConfigErr:     configErr,
+	}
+	if in.Migrations, err = coord.List(ctx); err != nil {
+		return err
+	}
+	if in.Plan, err = coord.Plan(ctx); err != nil {
+		return err
+	}
+	if tierCoord != nil {
+		if in.LogTierPlan, err = tierCoord.Plan(ctx); err != nil {
+			return fmt.Errorf("log tier: %w", err)
+		}
+	}
+	report := upgradecheck.Check(in)
+
+	if c.Bool("json") {
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+	printUpgradeReport(os.Stdout, report)
+	return nil
+}
+
+// printUpgradeReport renders the output for `outpost upgrade check`.
+func printUpgradeReport(w io.Writer, report *upgradecheck.Report) {
+	fmt.Fprintf(w, "Upgrade check for Outpost %s\n\n", report.TargetVersion)
+	printSchema(w, "Redis schema:    ", report.RedisSchema)
+	if report.LogStoreSchema != (upgradecheck.Schema{}) {
+		printSchema(w, "Log store schema:", report.LogStoreSchema)
+	}
+	fmt.Fprintln(w)
+
+	if report.Ready() {
+		fmt.Fprintf(w, "No actions required: Outpost %s can be deployed.\n", report.TargetVersion)
+		return
+	}
+
+	fmt.Fprintln(w, "Action plan:")
+	for i, step := range report.Steps {
+		fmt.Fprintf(w, "  %d. %s\n", i+1, step.Title)
+		for _, detail := range step.Details {
+			fmt.Fprintf(w, "       - %s\n", detail)
+		}
+		if step.Command != "" {
+			fmt.Fprintf(w, "       $ %s\n", step.Command)
+		}
+	}
+	fmt.Fprintf(w, "  %d. Deploy Outpost %s\n", len(report.Steps)+1, report.TargetVersion)
+}
+
+func printSchema(w io.Writer, label string, schema upgradecheck.Schema) {
+	current := schema.Current
+	if current == "" {
+		current = "none"
+	}
+	status := "up to date"
+	if !schema.UpToDate() {
+		status = "behind, latest " + schema.Latest
+	}
+	fmt.Fprintf(w, "%s %s (%s)\n", label, current, status)
+}
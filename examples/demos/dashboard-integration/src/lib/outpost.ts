@@ -59,14 +59,12 @@ export async function getTenantOverview(tenantId: string) {
     const tenant = await outpost.tenants.get(tenantId);
     logger.debug(`Tenant found`, { tenantId, tenant });
 
-    // Get destinations for this tenant (SDK returns array)
-    const destinationsList = await outpost.destinations.list(tenantId);
-    const destinations = Array.isArray(destinationsList)
-      ? destinationsList.map((dest: any) => ({
-          ...dest,
-          enabled: dest.disabledAt === null,
-        }))
-      : [];
+    // Get destinations for this tenant (destinations.list() returns DestinationPaginatedResult; items live under .models)
+    const destinationsPage = await outpost.destinations.list(tenantId);
+    const destinations = (destinationsPage.models ?? []).map((dest: any) => ({
+      ...dest,
+      enabled: dest.disabledAt === null,
+    }));
     logger.debug(`Destinations found`, { tenantId, count: destinations.length });
 
     // Get recent events (events.list() returns EventPaginatedResult; items live under .models)
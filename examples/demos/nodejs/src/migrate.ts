@@ -18,7 +18,7 @@ const cleanup = async () => {
   const organizations = db.getOrganizations();
   for (const organization of organizations) {
     const destinations = await outpost.destinations.list(organization.id);
-    for (const destination of destinations) {
+    for (const destination of destinations.models ?? []) {
       console.log(`Deleting destination:`, destination);
       await outpost.destinations.delete(organization.id, destination.id);
     }
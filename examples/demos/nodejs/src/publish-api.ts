@@ -8,7 +8,7 @@ const main = async () => {
 
   for (const organization of organizations) {
     const destinations = await outpost.destinations.list(organization.id);
-    for (const destination of destinations) {
+    for (const destination of destinations.models ?? []) {
       const topics = destination.topics;
       const topic =
         Array.isArray(topics) && topics.length > 0
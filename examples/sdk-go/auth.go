@@ -29,8 +29,8 @@ func withTenantApiKey(ctx context.Context, tenantApiKey string, apiServerURL str
 		log.Fatalf("Failed to list destinations with tenant API key: %v", err)
 	}
 
-	if destRes != nil && destRes.Destinations != nil {
-		log.Printf("Successfully listed %d destinations using tenant API key.", len(destRes.Destinations))
+	if destRes != nil && destRes.DestinationPaginatedResult != nil {
+		log.Printf("Successfully listed %d destinations using tenant API key.", len(destRes.DestinationPaginatedResult.Models))
 	} else {
 		log.Println("List destinations with tenant API key returned no data or an unexpected response structure.")
 	}
@@ -63,8 +63,8 @@ func withAdminApiKey(ctx context.Context, apiServerURL string, adminAPIKey strin
 		log.Fatalf("Failed to list destinations with Admin Key: %v", err)
 	}
 
-	if destRes != nil && destRes.Destinations != nil {
-		log.Printf("Successfully listed %d destinations using Admin Key for tenant %s.", len(destRes.Destinations), tenantID)
+	if destRes != nil && destRes.DestinationPaginatedResult != nil {
+		log.Printf("Successfully listed %d destinations using Admin Key for tenant %s.", len(destRes.DestinationPaginatedResult.Models), tenantID)
 	} else {
 		log.Println("List destinations with Admin Key returned no data or an unexpected response structure.")
 	}
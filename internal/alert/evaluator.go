@@ -8,6 +8,7 @@ package alert
 import (
 	"context"
 	"fmt"
+	"time"
 )
 
 // Attempt is the tracker's input: the identity and outcome of one delivery
@@ -19,6 +20,14 @@ type Attempt struct {
 	Number           int // 1-indexed attempt number
 	Success          bool
 	EligibleForRetry bool
+	// RetryLimit overrides the evaluator's retry limit for a destination
+	// with its own retry policy.
+	RetryLimit *int
+	// FailureCount and FailureWindow override the evaluator's auto-disable
+	// count and failure window for a destination whose tenant has its own
+	// auto-disable policy.
+	FailureCount  *int
+	FailureWindow *time.Duration
 }
 
 // Evaluation is the tracker's verdict on one attempt: one field per signal
@@ -52,6 +61,14 @@ func WithAutoDisableFailureCount(count int) Option {
 	}
 }
 
+// WithFailureWindow only counts the failures of a streak within the window.
+// Defaults to 0, which counts the whole streak.
+func WithFailureWindow(window time.Duration) Option {
+	return func(e *Evaluator) {
+		e.failureWindow = window
+	}
+}
+
 // WithAlertThresholds sets the percentage thresholds at which alerts fire.
 func WithAlertThresholds(thresholds []int) Option {
 	return func(e *Evaluator) {
@@ -83,6 +100,7 @@ type Evaluator struct {
 	thresholds thresholdEvaluator
 
 	autoDisableFailureCount int
+	failureWindow           time.Duration
 	alertThresholds         []int
 	retryMaxLimit           int
 
@@ -132,24 +150,44 @@ func (e *Evaluator) Evaluate(ctx context.Context, attempt Attempt) (Evaluation,
 	var eval Evaluation
 
 	if e.consecutiveFailureEnabled {
-		count, err := e.store.IncrementConsecutiveFailureCount(ctx, attempt.TenantID, attempt.DestinationID, attempt.AttemptID)
+		window := e.failureWindow
+		if attempt.FailureWindow != nil {
+			window = *attempt.FailureWindow
+		}
+		var count int
+		var err error
+		if window > 0 {
+			count, err = e.store.IncrementConsecutiveFailureCountWithin(ctx, attempt.TenantID, attempt.DestinationID, attempt.AttemptID, window)
+		} else {
+			count, err = e.store.IncrementConsecutiveFailureCount(ctx, attempt.TenantID, attempt.DestinationID, attempt.AttemptID)
+		}
 		if err != nil {
 			return Evaluation{}, fmt.Errorf("failed to track consecutive failures: %w", err)
 		}
-		if level, crossed := e.thresholds.shouldAlert(count); crossed {
+
+		thresholds, failureCount := e.thresholds, e.autoDisableFailureCount
+		if attempt.FailureCount != nil && *attempt.FailureCount != failureCount {
+			failureCount = *attempt.FailureCount
+			thresholds = newThresholdEvaluator(e.alertThresholds, failureCount)
+		}
+		if level, crossed := thresholds.shouldAlert(count); crossed {
 			eval.ConsecutiveFailure = &ConsecutiveFailureSignal{
 				Failures: count,
-				Max:      e.autoDisableFailureCount,
+				Max:      failureCount,
 				Level:    level,
 			}
 		}
 	}
 
 	// Exhausted retries check (independent of consecutive failure thresholds).
-	// Attempt is 1-indexed: with retryMaxLimit=10, attempt 11 is the final one.
-	// Skip if retryMaxLimit=0 (retries disabled — no exhausted state to report)
+	// Attempt is 1-indexed: with a retry limit of 10, attempt 11 is the final one.
+	// Skip if the limit is 0 (retries disabled — no exhausted state to report)
 	// or if the exhausted-retries signal is disabled.
-	if e.exhaustedRetriesEnabled && e.retryMaxLimit > 0 && attempt.EligibleForRetry && attempt.Number > e.retryMaxLimit {
+	retryLimit := e.retryMaxLimit
+	if attempt.RetryLimit != nil {
+		retryLimit = *attempt.RetryLimit
+	}
+	if e.exhaustedRetriesEnabled && retryLimit > 0 && attempt.EligibleForRetry && attempt.Number > retryLimit {
 		eval.RetriesExhausted = true
 	}
 
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -222,6 +223,28 @@ func TestEvaluator_RetriesExhausted_RetriesDisabled(t *testing.T) {
 	assert.False(t, eval.RetriesExhausted)
 }
 
+func TestEvaluator_RetriesExhausted_DestinationRetryLimit(t *testing.T) {
+	// A destination's retry policy replaces the deployment's retry limit.
+	t.Parallel()
+	ctx := context.Background()
+	redisClient := testutil.CreateTestRedisClient(t)
+
+	e := alert.NewEvaluator(
+		alert.NewRedisAlertStore(redisClient, ""),
+		10,
+		alert.WithAutoDisableFailureCount(100),
+	)
+
+	retryLimit := 1
+	a := failedAttempt("dest_rl", "tenant_rl", "att_2")
+	a.Number = 2
+	a.EligibleForRetry = true
+	a.RetryLimit = &retryLimit
+	eval, err := e.Evaluate(ctx, a)
+	require.NoError(t, err)
+	assert.True(t, eval.RetriesExhausted)
+}
+
 func TestEvaluator_ConsecutiveFailure_Disabled(t *testing.T) {
 	// With consecutive-failure tracking disabled, failures never count and
 	// never cross thresholds.
@@ -268,6 +291,68 @@ func TestEvaluator_ExhaustedRetries_Disabled(t *testing.T) {
 	assert.False(t, eval.RetriesExhausted, "no exhaustion signal when disabled")
 }
 
+func TestEvaluator_FailureCountOverride(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	redisClient := testutil.CreateTestRedisClient(t)
+
+	e := alert.NewEvaluator(
+		alert.NewRedisAlertStore(redisClient, ""),
+		10,
+		alert.WithAutoDisableFailureCount(20),
+		alert.WithAlertThresholds([]int{50, 100}),
+	)
+
+	failureCount := 4
+	var levels []int
+	for i := 1; i <= 4; i++ {
+		a := failedAttempt("dest_override", "tenant_override", fmt.Sprintf("att_%d", i))
+		a.FailureCount = &failureCount
+		eval, err := e.Evaluate(ctx, a)
+		require.NoError(t, err)
+		if sig := eval.ConsecutiveFailure; sig != nil {
+			assert.Equal(t, 4, sig.Max)
+			levels = append(levels, sig.Level)
+		}
+	}
+	assert.Equal(t, []int{50, 100}, levels, "thresholds follow the attempt's failure count")
+}
+
+func TestEvaluator_FailureWindow(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	redisClient := testutil.CreateTestRedisClient(t)
+
+	e := alert.NewEvaluator(
+		alert.NewRedisAlertStore(redisClient, ""),
+		10,
+		alert.WithAutoDisableFailureCount(2),
+		alert.WithAlertThresholds([]int{100}),
+		alert.WithFailureWindow(50*time.Millisecond),
+	)
+
+	eval, err := e.Evaluate(ctx, failedAttempt("dest_window", "tenant_window", "att_1"))
+	require.NoError(t, err)
+	assert.Nil(t, eval.ConsecutiveFailure)
+
+	time.Sleep(100 * time.Millisecond)
+
+	eval, err = e.Evaluate(ctx, failedAttempt("dest_window", "tenant_window", "att_2"))
+	require.NoError(t, err)
+	assert.Nil(t, eval.ConsecutiveFailure, "failures outside the window don't count")
+
+	eval, err = e.Evaluate(ctx, failedAttempt("dest_window", "tenant_window", "att_3"))
+	require.NoError(t, err)
+	require.NotNil(t, eval.ConsecutiveFailure)
+	assert.Equal(t, 2, eval.ConsecutiveFailure.Failures)
+
+	_, err = e.Evaluate(ctx, successAttempt("dest_window", "tenant_window"))
+	require.NoError(t, err)
+	eval, err = e.Evaluate(ctx, failedAttempt("dest_window", "tenant_window", "att_4"))
+	require.NoError(t, err)
+	assert.Nil(t, eval.ConsecutiveFailure, "a success resets a windowed streak")
+}
+
 func TestEvaluator_Gates_Independent(t *testing.T) {
 	// Consecutive-failure tracking off, exhausted-retries on: exhaustion still
 	// fires while the count stays silent.
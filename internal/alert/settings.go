@@ -1,5 +1,11 @@
 package alert
 
+import (
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
 // Default alert values, applied when the corresponding config value is unset.
 const (
 	DefaultConsecutiveFailureCount       = 100
@@ -14,14 +20,21 @@ type Settings struct {
 	ConsecutiveFailure     ConsecutiveFailureSetting
 	ExhaustedRetries       ExhaustedRetriesSetting
 	AutoDisableDestination bool
+	// AutoReenableProbationSeconds is how long an auto-disabled destination
+	// stays disabled before a successful test delivery re-enables it; 0 never
+	// re-enables it.
+	AutoReenableProbationSeconds int
 }
 
 // ConsecutiveFailureSetting controls consecutive-failure alerting. When Enabled
 // is false the monitor never tracks or alerts on consecutive failures, and
 // therefore never auto-disables a destination regardless of AutoDisableDestination.
+// A positive WindowSeconds only counts the failures of a streak within that
+// many seconds.
 type ConsecutiveFailureSetting struct {
-	Enabled bool
-	Count   int
+	Enabled       bool
+	Count         int
+	WindowSeconds int
 }
 
 // ExhaustedRetriesSetting controls exhausted-retries alerting. When Enabled is
@@ -32,3 +45,49 @@ type ExhaustedRetriesSetting struct {
 	Enabled       bool
 	WindowSeconds int
 }
+
+// Policy is the consecutive-failure policy a destination follows: the
+// deployment's, or its tenant's override of it.
+type Policy struct {
+	// FailureCount is the consecutive-failure count that means 100%.
+	FailureCount int
+	// Window only counts the failures of a streak within it; 0 counts the
+	// whole streak.
+	Window time.Duration
+	// AutoDisable disables a destination at 100%.
+	AutoDisable bool
+	// Probation is how long an auto-disabled destination waits before a test
+	// delivery may re-enable it; 0 never re-enables it.
+	Probation time.Duration
+}
+
+// Policy returns the deployment's consecutive-failure policy.
+func (s Settings) Policy() Policy {
+	return Policy{
+		FailureCount: s.ConsecutiveFailure.Count,
+		Window:       time.Duration(s.ConsecutiveFailure.WindowSeconds) * time.Second,
+		AutoDisable:  s.AutoDisableDestination,
+		Probation:    time.Duration(s.AutoReenableProbationSeconds) * time.Second,
+	}
+}
+
+// Override returns the policy with a tenant's overrides applied. A nil
+// override returns the policy unchanged.
+func (p Policy) Override(o *models.AutoDisablePolicy) Policy {
+	if o == nil {
+		return p
+	}
+	if o.FailureCount != nil {
+		p.FailureCount = *o.FailureCount
+	}
+	if o.WindowSeconds != nil {
+		p.Window = time.Duration(*o.WindowSeconds) * time.Second
+	}
+	if o.AutoDisable != nil {
+		p.AutoDisable = *o.AutoDisable
+	}
+	if o.ProbationSeconds != nil {
+		p.Probation = time.Duration(*o.ProbationSeconds) * time.Second
+	}
+	return p
+}
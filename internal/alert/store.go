@@ -3,6 +3,7 @@ package alert
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -11,6 +12,7 @@ import (
 const (
 	keyPrefixAlert = "alert" // Base prefix for all alert keys
 	keyFailures    = "cf"    // Set for consecutive failure attempt IDs
+	keyWindowed    = "cfw"   // Sorted set of consecutive failure attempt IDs by time
 	alertKeyTTL    = 24 * time.Hour
 )
 
@@ -21,6 +23,9 @@ type AlertStore interface {
 	// destination's current consecutive-failure count. Recording is idempotent
 	// per attempt ID, so replays never double-count.
 	IncrementConsecutiveFailureCount(ctx context.Context, tenantID, destinationID, attemptID string) (int, error)
+	// IncrementConsecutiveFailureCountWithin is IncrementConsecutiveFailureCount
+	// counting only the failures recorded within the window.
+	IncrementConsecutiveFailureCountWithin(ctx context.Context, tenantID, destinationID, attemptID string, window time.Duration) (int, error)
 	ResetConsecutiveFailureCount(ctx context.Context, tenantID, destinationID string) error
 }
 
@@ -61,8 +66,41 @@ func (s *redisAlertStore) IncrementConsecutiveFailureCount(ctx context.Context,
 	return int(count), nil
 }
 
+func (s *redisAlertStore) IncrementConsecutiveFailureCountWithin(ctx context.Context, tenantID, destinationID, attemptID string, window time.Duration) (int, error) {
+	key := s.getWindowedKey(tenantID, destinationID)
+	now := time.Now()
+
+	// Failures are scored by the time they were recorded; those older than
+	// the window are dropped before counting. ZADD NX keeps a replayed
+	// attempt's original time.
+	pipe := s.client.TxPipeline()
+	pipe.ZAddNX(ctx, key, redis.Z{Score: float64(now.UnixMilli()), Member: attemptID})
+	pipe.ZRemRangeByScore(ctx, key, "-inf", "("+strconv.FormatInt(now.Add(-window).UnixMilli(), 10))
+	zcardCmd := pipe.ZCard(ctx, key)
+	pipe.PExpire(ctx, key, window)
+
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to execute consecutive failure count transaction: %w", err)
+	}
+
+	count, err := zcardCmd.Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get consecutive failure count: %w", err)
+	}
+
+	return int(count), nil
+}
+
 func (s *redisAlertStore) ResetConsecutiveFailureCount(ctx context.Context, tenantID, destinationID string) error {
-	return s.client.Del(ctx, s.getFailuresKey(tenantID, destinationID)).Err()
+	// The keys may live in different cluster slots, so they're deleted one by
+	// one rather than in a single DEL.
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, s.getFailuresKey(tenantID, destinationID))
+		pipe.Del(ctx, s.getWindowedKey(tenantID, destinationID))
+		return nil
+	})
+	return err
 }
 
 func (s *redisAlertStore) deploymentPrefix() string {
@@ -75,3 +113,7 @@ func (s *redisAlertStore) deploymentPrefix() string {
 func (s *redisAlertStore) getFailuresKey(tenantID, destinationID string) string {
 	return fmt.Sprintf("%s%s:%s:%s:%s", s.deploymentPrefix(), keyPrefixAlert, tenantID, destinationID, keyFailures)
 }
+
+func (s *redisAlertStore) getWindowedKey(tenantID, destinationID string) string {
+	return fmt.Sprintf("%s%s:%s:%s:%s", s.deploymentPrefix(), keyPrefixAlert, tenantID, destinationID, keyWindowed)
+}
@@ -0,0 +1,154 @@
+// Package alertchannel sends delivery alerts — consecutive failures,
+// auto-disabled destinations and exhausted retries — to the channels people
+// watch: Slack, PagerDuty or a generic webhook.
+//
+// Channels are configured for the deployment, receiving every alert, and per
+// tenant, receiving that tenant's alerts. They are fed by Emitter, which wraps
+// the operator events emitter, so alerts reach the channels alongside the
+// operator events sink rather than instead of it.
+//
+// Delivery to a channel is best-effort: a failed send is logged and not
+// retried, and a replayed alert may be sent again. PagerDuty deduplicates
+// alerts of the same destination and topic into one incident.
+package alertchannel
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/opevents"
+)
+
+const (
+	TypeSlack     = "slack"
+	TypePagerDuty = "pagerduty"
+	TypeWebhook   = "webhook"
+)
+
+// Alert severities, as PagerDuty defines them.
+const (
+	SeverityCritical = "critical"
+	SeverityError    = "error"
+	SeverityWarning  = "warning"
+)
+
+// DefaultPagerDutyURL is the PagerDuty Events API v2 endpoint.
+const DefaultPagerDutyURL = "https://events.pagerduty.com/v2/enqueue"
+
+// sendTimeout caps a single send to a channel.
+const sendTimeout = 10 * time.Second
+
+// Alert is an alert operator event, summarized for the people it notifies.
+// Data is the operator event's data, as sent to the operator events sink.
+type Alert struct {
+	ID           string                     `json:"id"`
+	Topic        string                     `json:"topic"`
+	Time         time.Time                  `json:"time"`
+	DeploymentID string                     `json:"deployment_id,omitempty"`
+	TenantID     string                     `json:"tenant_id"`
+	Severity     string                     `json:"severity"`
+	Summary      string                     `json:"summary"`
+	Destination  *opevents.AlertDestination `json:"destination"`
+	Data         any                        `json:"data"`
+}
+
+// NewAlert summarizes an alert operator event. It returns false for events
+// that aren't alerts.
+func NewAlert(ev opevents.Event) (Alert, bool) {
+	alert := Alert{
+		Topic:    ev.Topic,
+		TenantID: ev.TenantID,
+		Data:     ev.Data,
+	}
+	switch data := ev.Data.(type) {
+	case opevents.ConsecutiveFailureData:
+		alert.Destination = data.Destination
+		alert.Severity = SeverityWarning
+		if data.ConsecutiveFailures.Threshold >= 100 {
+			alert.Severity = SeverityError
+		}
+		alert.Summary = fmt.Sprintf("Destination %s failed %d consecutive deliveries (%d%% of %d)",
+			describe(data.Destination), data.ConsecutiveFailures.Current,
+			data.ConsecutiveFailures.Threshold, data.ConsecutiveFailures.Max)
+	case opevents.DestinationDisabledData:
+		alert.Destination = data.Destination
+		alert.Severity = SeverityCritical
+		alert.Summary = fmt.Sprintf("Destination %s was disabled after consecutive delivery failures",
+			describe(data.Destination))
+	case opevents.ExhaustedRetriesData:
+		alert.Destination = data.Destination
+		alert.Severity = SeverityError
+		alert.Summary = fmt.Sprintf("Event %s exhausted its retries to destination %s",
+			data.Event.ID, describe(data.Destination))
+	default:
+		return Alert{}, false
+	}
+	return alert, true
+}
+
+func describe(dest *opevents.AlertDestination) string {
+	return fmt.Sprintf("%s (%s) of tenant %s", dest.ID, dest.Type, dest.TenantID)
+}
+
+// Channel sends alerts to one place.
+type Channel interface {
+	// Type is the channel's type: slack, pagerduty or webhook.
+	Type() string
+	Send(ctx context.Context, alert Alert) error
+}
+
+// Config is the deployment's alert channels. Each channel is used when set.
+type Config struct {
+	SlackWebhookURL     string
+	PagerDutyRoutingKey string
+	WebhookURL          string
+	// WebhookSigningSecret signs the requests of the deployment's and the
+	// tenants' webhook channels.
+	WebhookSigningSecret string
+}
+
+// channels builds the channels of a set of channel settings.
+func (e *Emitter) channels(settings models.AlertChannels) []Channel {
+	var channels []Channel
+	if settings.SlackWebhookURL != "" {
+		channels = append(channels, &slackChannel{client: e.client, url: settings.SlackWebhookURL})
+	}
+	if settings.PagerDutyRoutingKey != "" {
+		channels = append(channels, &pagerDutyChannel{client: e.client, url: e.pagerDutyURL, routingKey: settings.PagerDutyRoutingKey})
+	}
+	if settings.WebhookURL != "" {
+		channels = append(channels, &webhookChannel{client: e.client, url: settings.WebhookURL, signingSecret: e.signingSecret})
+	}
+	return channels
+}
+
+// post sends a JSON body to url. A response status of 400 or above is an
+// error.
+func post(ctx context.Context, client *http.Client, url string, body []byte, header http.Header) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("channel returned status %d: %s", resp.StatusCode, string(snippet))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,110 @@
+package alertchannel
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+const signatureHeader = "X-Outpost-Signature"
+
+// slackChannel posts alerts to a Slack incoming webhook.
+type slackChannel struct {
+	client *http.Client
+	url    string
+}
+
+func (c *slackChannel) Type() string { return TypeSlack }
+
+func (c *slackChannel) Send(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(map[string]string{
+		"text": fmt.Sprintf("*[%s]* %s", alert.Severity, alert.Summary),
+	})
+	if err != nil {
+		return err
+	}
+	return post(ctx, c.client, c.url, body, nil)
+}
+
+// pagerDutyChannel triggers PagerDuty incidents through the Events API v2.
+type pagerDutyChannel struct {
+	client     *http.Client
+	url        string
+	routingKey string
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string           `json:"routing_key"`
+	EventAction string           `json:"event_action"`
+	DedupKey    string           `json:"dedup_key"`
+	Payload     pagerDutyPayload `json:"payload"`
+}
+
+type pagerDutyPayload struct {
+	Summary       string    `json:"summary"`
+	Source        string    `json:"source"`
+	Severity      string    `json:"severity"`
+	Timestamp     time.Time `json:"timestamp"`
+	Component     string    `json:"component"`
+	Group         string    `json:"group"`
+	Class         string    `json:"class"`
+	CustomDetails any       `json:"custom_details"`
+}
+
+func (c *pagerDutyChannel) Type() string { return TypePagerDuty }
+
+func (c *pagerDutyChannel) Send(ctx context.Context, alert Alert) error {
+	source := "outpost"
+	if alert.DeploymentID != "" {
+		source = "outpost/" + alert.DeploymentID
+	}
+	body, err := json.Marshal(pagerDutyEvent{
+		RoutingKey:  c.routingKey,
+		EventAction: "trigger",
+		// Repeated alerts of a destination update its open incident
+		DedupKey: fmt.Sprintf("%s/%s/%s/%s", source, alert.TenantID, alert.Destination.ID, alert.Topic),
+		Payload: pagerDutyPayload{
+			Summary:       alert.Summary,
+			Source:        source,
+			Severity:      alert.Severity,
+			Timestamp:     alert.Time,
+			Component:     alert.Destination.ID,
+			Group:         alert.TenantID,
+			Class:         alert.Topic,
+			CustomDetails: alert.Data,
+		},
+	})
+	if err != nil {
+		return err
+	}
+	return post(ctx, c.client, c.url, body, nil)
+}
+
+// webhookChannel POSTs alerts as JSON, signed the same way as the HTTP sink
+// for operator events.
+type webhookChannel struct {
+	client        *http.Client
+	url           string
+	signingSecret string
+}
+
+func (c *webhookChannel) Type() string { return TypeWebhook }
+
+func (c *webhookChannel) Send(ctx context.Context, alert Alert) error {
+	body, err := json.Marshal(alert)
+	if err != nil {
+		return err
+	}
+	header := http.Header{}
+	if c.signingSecret != "" {
+		mac := hmac.New(sha256.New, []byte(c.signingSecret))
+		mac.Write(body)
+		header.Set(signatureHeader, "v0="+hex.EncodeToString(mac.Sum(nil)))
+	}
+	return post(ctx, c.client, c.url, body, header)
+}
@@ -0,0 +1,150 @@
+package alertchannel
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/opevents"
+	"go.uber.org/zap"
+)
+
+// TenantGetter looks up a tenant's alert channels.
+type TenantGetter interface {
+	RetrieveTenant(ctx context.Context, tenantID string) (*models.Tenant, error)
+}
+
+// Emitter wraps an operator events emitter, also sending its alerts to the
+// deployment's and the alert's tenant's channels.
+type Emitter struct {
+	next          opevents.Emitter
+	deployment    models.AlertChannels
+	signingSecret string
+	deploymentID  string
+	tenants       TenantGetter
+	client        *http.Client
+	pagerDutyURL  string
+	logger        *logging.Logger
+}
+
+var _ opevents.Emitter = (*Emitter)(nil)
+
+// Option configures an Emitter.
+type Option func(*Emitter)
+
+// WithHTTPClient sets the client channels are sent with.
+func WithHTTPClient(client *http.Client) Option {
+	return func(e *Emitter) {
+		e.client = client
+	}
+}
+
+// WithPagerDutyURL overrides the PagerDuty Events API endpoint.
+func WithPagerDutyURL(url string) Option {
+	return func(e *Emitter) {
+		e.pagerDutyURL = url
+	}
+}
+
+// NewEmitter wraps next. Tenants may be nil, in which case only the
+// deployment's channels receive alerts.
+func NewEmitter(next opevents.Emitter, cfg Config, deploymentID string, tenants TenantGetter, logger *logging.Logger, opts ...Option) *Emitter {
+	e := &Emitter{
+		next: next,
+		deployment: models.AlertChannels{
+			SlackWebhookURL:     cfg.SlackWebhookURL,
+			PagerDutyRoutingKey: cfg.PagerDutyRoutingKey,
+			WebhookURL:          cfg.WebhookURL,
+		},
+		signingSecret: cfg.WebhookSigningSecret,
+		deploymentID:  deploymentID,
+		tenants:       tenants,
+		client:        &http.Client{Timeout: sendTimeout},
+		pagerDutyURL:  DefaultPagerDutyURL,
+		logger:        logger,
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// Enabled reports whether events on topic are sent anywhere. Alerts always
+// are, since a tenant may have channels of its own.
+func (e *Emitter) Enabled(topic string) bool {
+	return e.next.Enabled(topic) || isAlertTopic(topic)
+}
+
+// Emit emits the event to the wrapped emitter, then sends it to the channels
+// when it's an alert. Channel failures are logged, not returned: the wrapped
+// emitter's error alone decides whether the event is emitted again.
+func (e *Emitter) Emit(ctx context.Context, ev opevents.Event) error {
+	err := e.next.Emit(ctx, ev)
+
+	alert, ok := NewAlert(ev)
+	if !ok {
+		return err
+	}
+	alert.ID = idgen.String()
+	alert.Time = time.Now()
+	alert.DeploymentID = e.deploymentID
+
+	for _, channel := range e.channelsFor(ctx, alert.TenantID) {
+		e.send(ctx, channel, alert)
+	}
+	return err
+}
+
+// channelsFor returns the deployment's channels followed by the tenant's. A
+// tenant lookup failure is logged and the deployment's channels still send.
+func (e *Emitter) channelsFor(ctx context.Context, tenantID string) []Channel {
+	channels := e.channels(e.deployment)
+	if e.tenants == nil || tenantID == "" {
+		return channels
+	}
+	tenant, err := e.tenants.RetrieveTenant(ctx, tenantID)
+	if err != nil {
+		e.logger.Ctx(ctx).Warn("failed to retrieve tenant alert channels",
+			zap.Error(err),
+			zap.String("tenant_id", tenantID))
+		return channels
+	}
+	if tenant == nil || tenant.AlertChannels == nil {
+		return channels
+	}
+	return append(channels, e.channels(*tenant.AlertChannels)...)
+}
+
+func (e *Emitter) send(ctx context.Context, channel Channel, alert Alert) {
+	ctx, cancel := context.WithTimeout(ctx, sendTimeout)
+	defer cancel()
+
+	if err := channel.Send(ctx, alert); err != nil {
+		e.logger.Ctx(ctx).Error("alert channel delivery failed",
+			zap.Error(err),
+			zap.String("alert_id", alert.ID),
+			zap.String("topic", alert.Topic),
+			zap.String("tenant_id", alert.TenantID),
+			zap.String("channel", channel.Type()))
+		return
+	}
+	e.logger.Ctx(ctx).Audit("alert sent",
+		zap.String("alert_id", alert.ID),
+		zap.String("topic", alert.Topic),
+		zap.String("tenant_id", alert.TenantID),
+		zap.String("destination_id", alert.Destination.ID),
+		zap.String("channel", channel.Type()))
+}
+
+func isAlertTopic(topic string) bool {
+	switch topic {
+	case opevents.TopicAlertConsecutiveFailure,
+		opevents.TopicAlertDestinationDisabled,
+		opevents.TopicAlertExhaustedRetries:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,301 @@
+package alertchannel_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/alertchannel"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/opevents"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder is a channel endpoint that records the requests it receives.
+type recorder struct {
+	mu      sync.Mutex
+	bodies  [][]byte
+	headers []http.Header
+	status  int
+	server  *httptest.Server
+}
+
+func newRecorder(t *testing.T) *recorder {
+	r := &recorder{status: http.StatusOK}
+	r.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		r.bodies = append(r.bodies, body)
+		r.headers = append(r.headers, req.Header.Clone())
+		status := r.status
+		r.mu.Unlock()
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(r.server.Close)
+	return r
+}
+
+func (r *recorder) requests() ([][]byte, []http.Header) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([][]byte(nil), r.bodies...), append([]http.Header(nil), r.headers...)
+}
+
+// stubEmitter is the wrapped operator events emitter.
+type stubEmitter struct {
+	mu      sync.Mutex
+	emitted []opevents.Event
+	err     error
+	topics  map[string]bool
+}
+
+func (s *stubEmitter) Emit(ctx context.Context, ev opevents.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.emitted = append(s.emitted, ev)
+	return s.err
+}
+
+func (s *stubEmitter) Enabled(topic string) bool { return s.topics[topic] }
+
+type stubTenants struct {
+	tenant *models.Tenant
+	err    error
+}
+
+func (s *stubTenants) RetrieveTenant(ctx context.Context, tenantID string) (*models.Tenant, error) {
+	return s.tenant, s.err
+}
+
+func disabledEvent() opevents.Event {
+	return opevents.Event{
+		Topic:    opevents.TopicAlertDestinationDisabled,
+		TenantID: "tenant-1",
+		Data: opevents.DestinationDisabledData{
+			TenantID: "tenant-1",
+			Destination: &opevents.AlertDestination{
+				ID:       "des_1",
+				TenantID: "tenant-1",
+				Type:     "webhook",
+			},
+			Reason: "consecutive_failure",
+		},
+	}
+}
+
+func TestEmitter_Channels(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sends alerts to deployment channels", func(t *testing.T) {
+		t.Parallel()
+		slack := newRecorder(t)
+		pagerDuty := newRecorder(t)
+		webhook := newRecorder(t)
+		next := &stubEmitter{}
+		em := alertchannel.NewEmitter(next, alertchannel.Config{
+			SlackWebhookURL:      slack.server.URL,
+			PagerDutyRoutingKey:  "routing-key",
+			WebhookURL:           webhook.server.URL,
+			WebhookSigningSecret: "secret",
+		}, "deploy-1", nil, testutil.CreateTestLogger(t),
+			alertchannel.WithPagerDutyURL(pagerDuty.server.URL))
+
+		require.NoError(t, em.Emit(context.Background(), disabledEvent()))
+		assert.Len(t, next.emitted, 1, "wrapped emitter still receives the event")
+
+		bodies, _ := slack.requests()
+		require.Len(t, bodies, 1)
+		var slackMsg map[string]string
+		require.NoError(t, json.Unmarshal(bodies[0], &slackMsg))
+		assert.Contains(t, slackMsg["text"], "des_1")
+		assert.Contains(t, slackMsg["text"], "critical")
+
+		bodies, _ = pagerDuty.requests()
+		require.Len(t, bodies, 1)
+		var pdEvent struct {
+			RoutingKey  string `json:"routing_key"`
+			EventAction string `json:"event_action"`
+			DedupKey    string `json:"dedup_key"`
+			Payload     struct {
+				Severity  string `json:"severity"`
+				Source    string `json:"source"`
+				Component string `json:"component"`
+				Group     string `json:"group"`
+				Class     string `json:"class"`
+			} `json:"payload"`
+		}
+		require.NoError(t, json.Unmarshal(bodies[0], &pdEvent))
+		assert.Equal(t, "routing-key", pdEvent.RoutingKey)
+		assert.Equal(t, "trigger", pdEvent.EventAction)
+		assert.Equal(t, "outpost/deploy-1/tenant-1/des_1/"+opevents.TopicAlertDestinationDisabled, pdEvent.DedupKey)
+		assert.Equal(t, "critical", pdEvent.Payload.Severity)
+		assert.Equal(t, "des_1", pdEvent.Payload.Component)
+		assert.Equal(t, "tenant-1", pdEvent.Payload.Group)
+		assert.Equal(t, opevents.TopicAlertDestinationDisabled, pdEvent.Payload.Class)
+
+		bodies, headers := webhook.requests()
+		require.Len(t, bodies, 1)
+		var alert alertchannel.Alert
+		require.NoError(t, json.Unmarshal(bodies[0], &alert))
+		assert.NotEmpty(t, alert.ID)
+		assert.Equal(t, opevents.TopicAlertDestinationDisabled, alert.Topic)
+		assert.Equal(t, "deploy-1", alert.DeploymentID)
+		assert.Equal(t, "des_1", alert.Destination.ID)
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(bodies[0])
+		assert.Equal(t, "v0="+hex.EncodeToString(mac.Sum(nil)), headers[0].Get("X-Outpost-Signature"))
+	})
+
+	t.Run("sends alerts to the tenant's channels", func(t *testing.T) {
+		t.Parallel()
+		deployment := newRecorder(t)
+		tenant := newRecorder(t)
+		tenants := &stubTenants{tenant: &models.Tenant{
+			ID:            "tenant-1",
+			AlertChannels: &models.AlertChannels{WebhookURL: tenant.server.URL},
+		}}
+		em := alertchannel.NewEmitter(&stubEmitter{}, alertchannel.Config{
+			WebhookURL: deployment.server.URL,
+		}, "", tenants, testutil.CreateTestLogger(t))
+
+		require.NoError(t, em.Emit(context.Background(), disabledEvent()))
+
+		bodies, headers := deployment.requests()
+		assert.Len(t, bodies, 1)
+		bodies, _ = tenant.requests()
+		assert.Len(t, bodies, 1)
+		assert.Empty(t, headers[0].Get("X-Outpost-Signature"), "unsigned without a signing secret")
+	})
+
+	t.Run("tenant lookup failure still sends to deployment channels", func(t *testing.T) {
+		t.Parallel()
+		deployment := newRecorder(t)
+		em := alertchannel.NewEmitter(&stubEmitter{}, alertchannel.Config{
+			SlackWebhookURL: deployment.server.URL,
+		}, "", &stubTenants{err: errors.New("redis down")}, testutil.CreateTestLogger(t))
+
+		require.NoError(t, em.Emit(context.Background(), disabledEvent()))
+		bodies, _ := deployment.requests()
+		assert.Len(t, bodies, 1)
+	})
+
+	t.Run("channel failure is not returned", func(t *testing.T) {
+		t.Parallel()
+		slack := newRecorder(t)
+		slack.status = http.StatusInternalServerError
+		em := alertchannel.NewEmitter(&stubEmitter{}, alertchannel.Config{
+			SlackWebhookURL: slack.server.URL,
+		}, "", nil, testutil.CreateTestLogger(t))
+
+		require.NoError(t, em.Emit(context.Background(), disabledEvent()))
+		bodies, _ := slack.requests()
+		assert.Len(t, bodies, 1)
+	})
+
+	t.Run("wrapped emitter error is returned after channels send", func(t *testing.T) {
+		t.Parallel()
+		slack := newRecorder(t)
+		sinkErr := errors.New("sink down")
+		em := alertchannel.NewEmitter(&stubEmitter{err: sinkErr}, alertchannel.Config{
+			SlackWebhookURL: slack.server.URL,
+		}, "", nil, testutil.CreateTestLogger(t))
+
+		assert.ErrorIs(t, em.Emit(context.Background(), disabledEvent()), sinkErr)
+		bodies, _ := slack.requests()
+		assert.Len(t, bodies, 1)
+	})
+
+	t.Run("non-alert events skip channels", func(t *testing.T) {
+		t.Parallel()
+		slack := newRecorder(t)
+		next := &stubEmitter{}
+		em := alertchannel.NewEmitter(next, alertchannel.Config{
+			SlackWebhookURL: slack.server.URL,
+		}, "", nil, testutil.CreateTestLogger(t))
+
+		require.NoError(t, em.Emit(context.Background(), opevents.Event{
+			Topic:    opevents.TopicTenantSubscriptionUpdated,
+			TenantID: "tenant-1",
+			Data:     map[string]string{"key": "val"},
+		}))
+		assert.Len(t, next.emitted, 1)
+		bodies, _ := slack.requests()
+		assert.Empty(t, bodies)
+	})
+}
+
+func TestEmitter_Enabled(t *testing.T) {
+	t.Parallel()
+
+	em := alertchannel.NewEmitter(&stubEmitter{topics: map[string]bool{
+		opevents.TopicTenantSubscriptionUpdated: true,
+	}}, alertchannel.Config{}, "", nil, testutil.CreateTestLogger(t))
+
+	assert.True(t, em.Enabled(opevents.TopicAlertExhaustedRetries))
+	assert.True(t, em.Enabled(opevents.TopicTenantSubscriptionUpdated))
+	assert.False(t, em.Enabled(opevents.TopicAttemptSuccess))
+}
+
+func TestNewAlert(t *testing.T) {
+	t.Parallel()
+
+	dest := &opevents.AlertDestination{ID: "des_1", TenantID: "tenant-1", Type: "webhook"}
+
+	tests := []struct {
+		name     string
+		ev       opevents.Event
+		severity string
+	}{
+		{
+			name: "consecutive failure below threshold is a warning",
+			ev: opevents.Event{Topic: opevents.TopicAlertConsecutiveFailure, Data: opevents.ConsecutiveFailureData{
+				Destination:         dest,
+				ConsecutiveFailures: opevents.ConsecutiveFailures{Current: 10, Max: 20, Threshold: 50},
+			}},
+			severity: alertchannel.SeverityWarning,
+		},
+		{
+			name: "consecutive failure at threshold is an error",
+			ev: opevents.Event{Topic: opevents.TopicAlertConsecutiveFailure, Data: opevents.ConsecutiveFailureData{
+				Destination:         dest,
+				ConsecutiveFailures: opevents.ConsecutiveFailures{Current: 20, Max: 20, Threshold: 100},
+			}},
+			severity: alertchannel.SeverityError,
+		},
+		{
+			name: "exhausted retries is an error",
+			ev: opevents.Event{Topic: opevents.TopicAlertExhaustedRetries, Data: opevents.ExhaustedRetriesData{
+				Destination: dest,
+				Event:       &models.Event{ID: "evt_1"},
+			}},
+			severity: alertchannel.SeverityError,
+		},
+		{
+			name:     "disabled destination is critical",
+			ev:       disabledEvent(),
+			severity: alertchannel.SeverityCritical,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			alert, ok := alertchannel.NewAlert(tt.ev)
+			require.True(t, ok)
+			assert.Equal(t, tt.severity, alert.Severity)
+			assert.Contains(t, alert.Summary, "des_1")
+		})
+	}
+
+	_, ok := alertchannel.NewAlert(opevents.Event{Topic: opevents.TopicAttemptSuccess, Data: "data"})
+	assert.False(t, ok)
+}
@@ -0,0 +1,104 @@
+package apirouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/deliveryack"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/models"
+	"go.uber.org/zap"
+)
+
+type deliveryAckStore interface {
+	Retrieve(ctx context.Context, token string) (*deliveryack.Pending, error)
+	Delete(ctx context.Context, token string) error
+}
+
+type retryCanceler interface {
+	Cancel(ctx context.Context, taskID string) error
+}
+
+// ackNotifier completes an acknowledged delivery in the event lifecycle.
+type ackNotifier interface {
+	DeliveryAcknowledged(pending *deliveryack.Pending)
+}
+
+type AckHandlers struct {
+	logger        *logging.Logger
+	acks          deliveryAckStore
+	retryCanceler retryCanceler
+	lifecycle     ackNotifier
+}
+
+func NewAckHandlers(logger *logging.Logger, acks deliveryAckStore, retryCanceler retryCanceler, lifecycle ackNotifier) *AckHandlers {
+	return &AckHandlers{
+		logger:        logger,
+		acks:          acks,
+		retryCanceler: retryCanceler,
+		lifecycle:     lifecycle,
+	}
+}
+
+// AckResponse identifies the delivery confirmed by an acknowledgment.
+type AckResponse struct {
+	EventID       string `json:"event_id"`
+	DestinationID string `json:"destination_id"`
+	AttemptID     string `json:"attempt_id"`
+}
+
+// Ack handles POST /ack/:token. The consumer confirms it has processed a
+// delivery it accepted with 202, which cancels the retry scheduled for the
+// ack timeout. The token authenticates the request, so the route is public.
+func (h *AckHandlers) Ack(c *gin.Context) {
+	if h.acks == nil || h.retryCanceler == nil {
+		AbortWithError(c, http.StatusNotImplemented, ErrorResponse{
+			Code:    http.StatusNotImplemented,
+			Message: "delivery acknowledgments are not enabled",
+		})
+		return
+	}
+	ctx := c.Request.Context()
+	token := c.Param("token")
+
+	pending, err := h.acks.Retrieve(ctx, token)
+	if err != nil {
+		if errors.Is(err, deliveryack.ErrNotFound) {
+			AbortWithError(c, http.StatusNotFound, NewErrNotFound("delivery ack"))
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+
+	// Cancel before deleting the token so a failed cancel can be retried by
+	// the consumer.
+	if err := h.retryCanceler.Cancel(ctx, models.RetryID(pending.EventID, pending.DestinationID)); err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	if err := h.acks.Delete(ctx, token); err != nil {
+		h.logger.Ctx(ctx).Error("failed to delete delivery ack",
+			zap.Error(err),
+			zap.String("tenant_id", pending.TenantID),
+			zap.String("event_id", pending.EventID),
+			zap.String("destination_id", pending.DestinationID))
+	}
+
+	h.logger.Ctx(ctx).Audit("delivery acknowledged",
+		zap.String("tenant_id", pending.TenantID),
+		zap.String("event_id", pending.EventID),
+		zap.String("destination_id", pending.DestinationID),
+		zap.String("attempt_id", pending.AttemptID),
+	)
+	if h.lifecycle != nil {
+		h.lifecycle.DeliveryAcknowledged(pending)
+	}
+	c.JSON(http.StatusOK, AckResponse{
+		EventID:       pending.EventID,
+		DestinationID: pending.DestinationID,
+		AttemptID:     pending.AttemptID,
+	})
+}
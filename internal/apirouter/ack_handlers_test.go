@@ -0,0 +1,92 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/deliveryack"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_Ack(t *testing.T) {
+	pending := deliveryack.Pending{
+		TenantID:      "t1",
+		EventID:       "e1",
+		DestinationID: "d1",
+		Topic:         "user.created",
+		AttemptID:     "a1",
+		AttemptNumber: 1,
+	}
+
+	setup := func(t *testing.T) (*apiTest, deliveryack.Store, *mockRetryCanceler) {
+		acks := deliveryack.New(testutil.CreateTestRedisClient(t))
+		require.NoError(t, acks.Register(t.Context(), "token", pending, time.Minute))
+		canceler := &mockRetryCanceler{}
+		return newAPITest(t, withDeliveryAcks(acks, canceler)), acks, canceler
+	}
+
+	t.Run("acknowledges without credentials and cancels the retry", func(t *testing.T) {
+		h, acks, canceler := setup(t)
+
+		resp := h.do(httptest.NewRequest(http.MethodPost, "/api/v1/ack/token", nil))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var result apirouter.AckResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		assert.Equal(t, "e1", result.EventID)
+		assert.Equal(t, "d1", result.DestinationID)
+		assert.Equal(t, "a1", result.AttemptID)
+		assert.Equal(t, []string{models.RetryID("e1", "d1")}, canceler.canceled)
+
+		_, err := acks.Retrieve(t.Context(), "token")
+		assert.ErrorIs(t, err, deliveryack.ErrNotFound, "token should be consumed")
+	})
+
+	t.Run("completes the delivery in the event lifecycle", func(t *testing.T) {
+		acks := deliveryack.New(testutil.CreateTestRedisClient(t))
+		require.NoError(t, acks.Register(t.Context(), "token", pending, time.Minute))
+		notifier := &mockAckNotifier{}
+		h := newAPITest(t, withDeliveryAcks(acks, &mockRetryCanceler{}), withAckNotifier(notifier))
+
+		resp := h.do(httptest.NewRequest(http.MethodPost, "/api/v1/ack/token", nil))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, []deliveryack.Pending{pending}, notifier.acknowledged)
+	})
+
+	t.Run("unknown token returns 404", func(t *testing.T) {
+		h, _, canceler := setup(t)
+
+		resp := h.do(httptest.NewRequest(http.MethodPost, "/api/v1/ack/unknown", nil))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+		assert.Empty(t, canceler.canceled)
+	})
+
+	t.Run("failed cancel keeps the token", func(t *testing.T) {
+		h, acks, canceler := setup(t)
+		canceler.err = errors.New("scheduler unavailable")
+
+		resp := h.do(httptest.NewRequest(http.MethodPost, "/api/v1/ack/token", nil))
+
+		assert.Equal(t, http.StatusInternalServerError, resp.Code)
+		_, err := acks.Retrieve(t.Context(), "token")
+		assert.NoError(t, err, "token should remain so the ack can be retried")
+	})
+
+	t.Run("without an ack store returns 501", func(t *testing.T) {
+		h := newAPITest(t)
+
+		resp := h.do(httptest.NewRequest(http.MethodPost, "/api/v1/ack/token", nil))
+
+		assert.Equal(t, http.StatusNotImplemented, resp.Code)
+	})
+}
@@ -0,0 +1,101 @@
+package apirouter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/models"
+	"go.uber.org/zap"
+)
+
+// UpdateAlertChannelsRequest sets the channels that receive a tenant's
+// alerts, alongside the deployment's.
+type UpdateAlertChannelsRequest struct {
+	SlackWebhookURL     string `json:"slack_webhook_url"`
+	PagerDutyRoutingKey string `json:"pagerduty_routing_key"`
+	WebhookURL          string `json:"webhook_url"`
+}
+
+func (r *UpdateAlertChannelsRequest) toAlertChannels() (*models.AlertChannels, error) {
+	if r.SlackWebhookURL == "" && r.PagerDutyRoutingKey == "" && r.WebhookURL == "" {
+		return nil, errors.New("at least one alert channel is required")
+	}
+	for _, field := range []struct{ name, value string }{
+		{"slack_webhook_url", r.SlackWebhookURL},
+		{"webhook_url", r.WebhookURL},
+	} {
+		if field.value == "" {
+			continue
+		}
+		u, err := url.Parse(field.value)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return nil, fmt.Errorf("%s must be an absolute https URL", field.name)
+		}
+	}
+	return &models.AlertChannels{
+		SlackWebhookURL:     r.SlackWebhookURL,
+		PagerDutyRoutingKey: r.PagerDutyRoutingKey,
+		WebhookURL:          r.WebhookURL,
+	}, nil
+}
+
+// RetrieveAlertChannels returns the tenant's alert channels.
+func (h *TenantHandlers) RetrieveAlertChannels(c *gin.Context) {
+	tenant := mustTenantFromContext(c)
+	if tenant.AlertChannels == nil {
+		AbortWithError(c, http.StatusNotFound, NewErrNotFound("alert channels"))
+		return
+	}
+	c.JSON(http.StatusOK, tenant.AlertChannels)
+}
+
+// UpdateAlertChannels replaces the tenant's alert channels.
+func (h *TenantHandlers) UpdateAlertChannels(c *gin.Context) {
+	var input UpdateAlertChannelsRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	channels, err := input.toAlertChannels()
+	if err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+
+	tenant := *mustTenantFromContext(c)
+	tenant.AlertChannels = channels
+	tenant.UpdatedAt = time.Now()
+	if err := h.tenantStore.UpsertTenant(c.Request.Context(), tenant); err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	h.logger.Ctx(c.Request.Context()).Audit("tenant alert channels updated",
+		zap.String("tenant_id", tenant.ID),
+		zap.Bool("slack", channels.SlackWebhookURL != ""),
+		zap.Bool("pagerduty", channels.PagerDutyRoutingKey != ""),
+		zap.Bool("webhook", channels.WebhookURL != ""),
+	)
+	c.JSON(http.StatusOK, channels)
+}
+
+// DeleteAlertChannels clears the tenant's alert channels, so its alerts only
+// go to the deployment's.
+func (h *TenantHandlers) DeleteAlertChannels(c *gin.Context) {
+	tenant := *mustTenantFromContext(c)
+	if tenant.AlertChannels != nil {
+		tenant.AlertChannels = nil
+		tenant.UpdatedAt = time.Now()
+		if err := h.tenantStore.UpsertTenant(c.Request.Context(), tenant); err != nil {
+			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+			return
+		}
+		h.logger.Ctx(c.Request.Context()).Audit("tenant alert channels cleared",
+			zap.String("tenant_id", tenant.ID),
+		)
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
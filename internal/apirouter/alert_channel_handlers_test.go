@@ -0,0 +1,92 @@
+package apirouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_TenantAlertChannels(t *testing.T) {
+	t.Run("admin sets channels", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/alert-channels", map[string]any{
+			"slack_webhook_url":     "https://hooks.slack.com/services/T0/B0/x",
+			"pagerduty_routing_key": "routing-key",
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"slack_webhook_url":"https://hooks.slack.com/services/T0/B0/x","pagerduty_routing_key":"routing-key"}`, resp.Body.String())
+
+		tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+		require.NoError(t, err)
+		require.NotNil(t, tenant.AlertChannels)
+		assert.Equal(t, "routing-key", tenant.AlertChannels.PagerDutyRoutingKey)
+		assert.Empty(t, tenant.AlertChannels.WebhookURL)
+	})
+
+	t.Run("get without channels returns 404", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/alert-channels", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("delete clears channels", func(t *testing.T) {
+		h := newAPITest(t)
+		existing := tf.Any(tf.WithID("t1"))
+		existing.AlertChannels = &models.AlertChannels{WebhookURL: "https://producer.example.com/alerts"}
+		h.tenantStore.UpsertTenant(t.Context(), existing)
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/tenants/t1/alert-channels", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+		require.NoError(t, err)
+		assert.Nil(t, tenant.AlertChannels)
+	})
+
+	t.Run("no channels returns 422", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/alert-channels", map[string]any{})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	t.Run("plain http url returns 422", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/alert-channels", map[string]any{
+			"webhook_url": "http://producer.example.com/alerts",
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	t.Run("jwt returns 403", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/alert-channels", map[string]any{
+			"webhook_url": "https://producer.example.com/alerts",
+		})
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
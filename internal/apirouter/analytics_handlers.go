@@ -0,0 +1,84 @@
+package apirouter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/topicstats"
+)
+
+const (
+	defaultTopicAnalyticsDays  = 30
+	defaultTopicAnalyticsLimit = 20
+	maxTopicAnalyticsLimit     = 100
+)
+
+// topicStatsReporter reports topic volume and payload sizes. Satisfied by
+// *topicstats.Store.
+type topicStatsReporter interface {
+	Report(ctx context.Context, tenantID string, days, limit int) (*topicstats.Report, error)
+	RetentionDays() int
+}
+
+type AnalyticsHandlers struct {
+	logger *logging.Logger
+	stats  topicStatsReporter
+}
+
+func NewAnalyticsHandlers(logger *logging.Logger, stats topicStatsReporter) *AnalyticsHandlers {
+	return &AnalyticsHandlers{
+		logger: logger,
+		stats:  stats,
+	}
+}
+
+// Topics handles GET /analytics/topics and
+// GET /tenants/:tenant_id/analytics/topics. It reports the busiest topics of
+// the deployment, or of the tenant, over the last days days: their volume,
+// payload sizes and growth.
+func (h *AnalyticsHandlers) Topics(c *gin.Context) {
+	if h.stats == nil {
+		AbortWithError(c, http.StatusNotImplemented, ErrorResponse{
+			Code:    http.StatusNotImplemented,
+			Message: "topic analytics are not enabled",
+		})
+		return
+	}
+
+	days := defaultTopicAnalyticsDays
+	if value := c.Query("days"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > h.stats.RetentionDays() {
+			AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(fmt.Errorf("invalid days: must be between 1 and %d", h.stats.RetentionDays())))
+			return
+		}
+		days = n
+	}
+	days = min(days, h.stats.RetentionDays())
+
+	limit := defaultTopicAnalyticsLimit
+	if value := c.Query("limit"); value != "" {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 || n > maxTopicAnalyticsLimit {
+			AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(fmt.Errorf("invalid limit: must be between 1 and %d", maxTopicAnalyticsLimit)))
+			return
+		}
+		limit = n
+	}
+
+	report, err := h.stats.Report(c.Request.Context(), c.Param("tenant_id"), days, limit)
+	if err != nil {
+		if errors.Is(err, topicstats.ErrInvalidRange) {
+			AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(err))
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
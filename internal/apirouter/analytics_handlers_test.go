@@ -0,0 +1,82 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/topicstats"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_TopicAnalytics(t *testing.T) {
+	t.Run("reports deployment topics", func(t *testing.T) {
+		store := topicstats.NewStore(testutil.CreateTestRedisClient(t))
+		require.NoError(t, store.Record(t.Context(), "t1", "user.created", 100))
+		require.NoError(t, store.Record(t.Context(), "t2", "user.created", 300))
+		require.NoError(t, store.Record(t.Context(), "t2", "order.paid", 50))
+		h := newAPITest(t, withTopicStats(store))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/analytics/topics?days=7", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var report topicstats.Report
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &report))
+		assert.Equal(t, int64(3), report.Count)
+		require.Len(t, report.Topics, 2)
+		assert.Equal(t, "user.created", report.Topics[0].Topic)
+		assert.Equal(t, 200.0, report.Topics[0].AvgSize)
+		assert.Len(t, report.Topics[0].Daily, 7)
+	})
+
+	t.Run("reports tenant topics", func(t *testing.T) {
+		store := topicstats.NewStore(testutil.CreateTestRedisClient(t))
+		require.NoError(t, store.Record(t.Context(), "t1", "user.created", 100))
+		require.NoError(t, store.Record(t.Context(), "t2", "order.paid", 50))
+		h := newAPITest(t, withTopicStats(store))
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/analytics/topics", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var report topicstats.Report
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &report))
+		assert.Equal(t, "t1", report.TenantID)
+		require.Len(t, report.Topics, 1)
+		assert.Equal(t, "user.created", report.Topics[0].Topic)
+	})
+
+	t.Run("invalid params return 400", func(t *testing.T) {
+		h := newAPITest(t, withTopicStats(topicstats.NewStore(testutil.CreateTestRedisClient(t), topicstats.WithRetentionDays(7))))
+
+		for _, query := range []string{"days=0", "days=8", "days=x", "limit=0", "limit=101"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/analytics/topics?"+query, nil)
+			resp := h.do(h.withAPIKey(req))
+			assert.Equal(t, http.StatusBadRequest, resp.Code, query)
+		}
+	})
+
+	t.Run("not enabled returns 501", func(t *testing.T) {
+		h := newAPITest(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/analytics/topics", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusNotImplemented, resp.Code)
+	})
+
+	t.Run("jwt returns 403", func(t *testing.T) {
+		h := newAPITest(t, withTopicStats(topicstats.NewStore(testutil.CreateTestRedisClient(t))))
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/analytics/topics", nil)
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
@@ -83,6 +83,23 @@ func TestAuditLog_Tenant(t *testing.T) {
 	})
 }
 
+func TestAuditLog_PortalTokenRejected(t *testing.T) {
+	h, logs := newAuditTest(t)
+	tenant := tf.Any(tf.WithID("t1"))
+	tenant.PortalAllowedIPs = []string{"203.0.113.0/24"}
+	h.tenantStore.UpsertTenant(t.Context(), tenant)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1", nil)
+	req.RemoteAddr = "198.51.100.7:1234"
+	resp := h.do(h.withJWT(req, "t1"))
+
+	require.Equal(t, http.StatusForbidden, resp.Code)
+	entry := findAuditLog(logs, "portal token rejected")
+	require.NotNil(t, entry, "expected 'portal token rejected' audit log")
+	assertAuditField(t, entry, "tenant_id", "t1")
+	assertAuditField(t, entry, "client_ip", "198.51.100.7")
+}
+
 func TestAuditLog_Destination(t *testing.T) {
 	t.Run("destination created", func(t *testing.T) {
 		h, logs := newAuditTest(t)
@@ -175,4 +192,50 @@ func TestAuditLog_Destination(t *testing.T) {
 		entry := findAuditLog(logs, "destination disabled")
 		assert.Nil(t, entry, "should not emit audit log for no-op disable")
 	})
+	t.Run("destination TLS verification disabled", func(t *testing.T) {
+		h, logs := newAuditTest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		body := validDestination()
+		body["config"] = map[string]string{"url": "https://example.com/hook", "tls_skip_verify": "true"}
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", body)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusCreated, resp.Code)
+		entry := findAuditLog(logs, "destination TLS verification disabled")
+		require.NotNil(t, entry, "expected 'destination TLS verification disabled' audit log")
+		assertAuditField(t, entry, "tenant_id", "t1")
+		assertAuditField(t, entry, "destination_type", "webhook")
+	})
+
+	t.Run("destination TLS verification enabled", func(t *testing.T) {
+		h, logs := newAuditTest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithTenantID("t1"), df.WithID("d1"),
+			df.WithConfig(map[string]string{"url": "https://example.com/hook", "tls_skip_verify": "true"}),
+		))
+
+		req := h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+			"config": map[string]string{"tls_skip_verify": "false"},
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		entry := findAuditLog(logs, "destination TLS verification enabled")
+		require.NotNil(t, entry, "expected 'destination TLS verification enabled' audit log")
+		assertAuditField(t, entry, "destination_id", "d1")
+		assert.Nil(t, findAuditLog(logs, "destination TLS verification disabled"))
+	})
+
+	t.Run("no TLS verification audit log when unchanged", func(t *testing.T) {
+		h, logs := newAuditTest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", validDestination())
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusCreated, resp.Code)
+		assert.Nil(t, findAuditLog(logs, "destination TLS verification disabled"))
+		assert.Nil(t, findAuditLog(logs, "destination TLS verification enabled"))
+	})
 }
@@ -7,8 +7,10 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/models"
 	"github.com/hookdeck/outpost/internal/tenantstore"
+	"go.uber.org/zap"
 )
 
 var (
@@ -18,7 +20,8 @@ var (
 
 const (
 	// Context keys
-	authRoleKey = "authRole"
+	authRoleKey   = "authRole"
+	portalRoleKey = "portalRole"
 
 	// Role values
 	RoleAdmin  = "admin"
@@ -35,6 +38,9 @@ type TenantRetriever interface {
 type AuthOptions struct {
 	AdminOnly     bool
 	RequireTenant bool
+	// Logger records portal tokens rejected by the tenant's IP allowlist.
+	// optional
+	Logger *logging.Logger
 }
 
 // AuthMiddleware returns a single gin.HandlerFunc that handles authentication,
@@ -47,7 +53,8 @@ type AuthOptions struct {
 //  4. JWT.Extract(token) → 401 if invalid.
 //  5. AdminOnly? → 403.
 //  6. :tenant_id param mismatch? → 403.
-//  7. Set tenantID + RoleTenant, always resolve tenant for JWT → 401 if missing/deleted.
+//  7. Set tenantID + RoleTenant + portal role, always resolve tenant for JWT → 401 if missing/deleted.
+//  8. Client IP outside the tenant's portal_allowed_ips? → 403.
 func AuthMiddleware(apiKey, jwtSecret string, tenantRetriever TenantRetriever, opts AuthOptions) gin.HandlerFunc {
 	// VPC mode — no API key configured, everything is admin.
 	if apiKey == "" {
@@ -106,11 +113,28 @@ func AuthMiddleware(apiKey, jwtSecret string, tenantRetriever TenantRetriever, o
 		// 7. Set tenant context and always resolve for JWT
 		c.Set("tenantID", claims.TenantID)
 		c.Set(authRoleKey, RoleTenant)
+		c.Set(portalRoleKey, claims.PortalRole)
 		resolveTenantOrAbort(c, tenantRetriever, claims.TenantID, true)
 		if c.IsAborted() {
 			return
 		}
 
+		// 8. Tenant IP allowlist
+		if tenant := mustTenantFromContext(c); !tenant.AllowsPortalIP(c.ClientIP()) {
+			if opts.Logger != nil {
+				opts.Logger.Ctx(c.Request.Context()).Audit("portal token rejected",
+					zap.String("tenant_id", tenant.ID),
+					zap.String("client_ip", c.ClientIP()),
+					zap.String("reason", "ip_not_allowed"),
+				)
+			}
+			AbortWithError(c, http.StatusForbidden, ErrorResponse{
+				Code:    http.StatusForbidden,
+				Message: "portal token not allowed from this IP address",
+			})
+			return
+		}
+
 		c.Next()
 	}
 }
@@ -199,6 +223,12 @@ func resolveTenantIDsFilter(c *gin.Context) ([]string, bool) {
 	return ParseArrayQueryParam(c, "tenant_id"), true
 }
 
+// canReadPayloads reports whether the caller reads event payloads in full.
+// Only viewer portal tokens read them redacted.
+func canReadPayloads(c *gin.Context) bool {
+	return c.GetString(portalRoleKey) != PortalRoleViewer
+}
+
 // tenantFromContext returns the resolved tenant from context, if present.
 // Returns nil when the request is not JWT-authenticated or the route doesn't require a tenant.
 func tenantFromContext(c *gin.Context) *models.Tenant {
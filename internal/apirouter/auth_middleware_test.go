@@ -206,6 +206,55 @@ func TestAuthMiddleware(t *testing.T) {
 		assert.Equal(t, http.StatusUnauthorized, w.Code)
 	})
 
+	t.Run("JWT from IP outside tenant allowlist returns 403", func(t *testing.T) {
+		restrictedStore := &mockTenantRetriever{tenant: &models.Tenant{ID: "t1", PortalAllowedIPs: []string{"203.0.113.0/24"}}}
+		r := gin.New()
+		r.Use(apirouter.ErrorHandlerMiddleware())
+		r.GET("/test", apirouter.AuthMiddleware(testAPIKey, testJWTSecret, restrictedStore, apirouter.AuthOptions{}), okHandler)
+
+		token, err := apirouter.JWT.New(testJWTSecret, apirouter.JWTClaims{TenantID: "t1"})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "198.51.100.7:1234"
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusForbidden, w.Code)
+	})
+
+	t.Run("JWT from IP inside tenant allowlist returns 200", func(t *testing.T) {
+		restrictedStore := &mockTenantRetriever{tenant: &models.Tenant{ID: "t1", PortalAllowedIPs: []string{"203.0.113.0/24"}}}
+		r := gin.New()
+		r.GET("/test", apirouter.AuthMiddleware(testAPIKey, testJWTSecret, restrictedStore, apirouter.AuthOptions{}), okHandler)
+
+		token, err := apirouter.JWT.New(testJWTSecret, apirouter.JWTClaims{TenantID: "t1"})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/test", nil)
+		req.RemoteAddr = "203.0.113.9:1234"
+		req.Header.Set("Authorization", "Bearer "+token)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
+	t.Run("API key ignores tenant allowlist", func(t *testing.T) {
+		restrictedStore := &mockTenantRetriever{tenant: &models.Tenant{ID: "t1", PortalAllowedIPs: []string{"203.0.113.0/24"}}}
+		r := gin.New()
+		r.GET("/test/:tenant_id", apirouter.AuthMiddleware(testAPIKey, testJWTSecret, restrictedStore, apirouter.AuthOptions{RequireTenant: true}), okHandler)
+
+		req := httptest.NewRequest(http.MethodGet, "/test/t1", nil)
+		req.RemoteAddr = "198.51.100.7:1234"
+		req.Header.Set("Authorization", "Bearer "+testAPIKey)
+		w := httptest.NewRecorder()
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+
 	t.Run("RequireTenant admin missing tenant returns 404", func(t *testing.T) {
 		nilStore := &mockTenantRetriever{tenant: nil}
 		r := gin.New()
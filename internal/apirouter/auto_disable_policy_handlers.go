@@ -0,0 +1,95 @@
+package apirouter
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/models"
+	"go.uber.org/zap"
+)
+
+// UpdateAutoDisablePolicyRequest overrides the deployment's auto-disable
+// policy for a tenant's destinations. Omitted fields follow the deployment's.
+type UpdateAutoDisablePolicyRequest struct {
+	FailureCount     *int  `json:"failure_count"`
+	WindowSeconds    *int  `json:"window_seconds"`
+	AutoDisable      *bool `json:"auto_disable"`
+	ProbationSeconds *int  `json:"probation_seconds"`
+}
+
+func (r *UpdateAutoDisablePolicyRequest) toAutoDisablePolicy() (*models.AutoDisablePolicy, error) {
+	if r.FailureCount == nil && r.WindowSeconds == nil && r.AutoDisable == nil && r.ProbationSeconds == nil {
+		return nil, errors.New("at least one policy field is required")
+	}
+	if r.FailureCount != nil && *r.FailureCount < 1 {
+		return nil, errors.New("failure_count must be at least 1")
+	}
+	if r.WindowSeconds != nil && *r.WindowSeconds < 0 {
+		return nil, errors.New("window_seconds must not be negative")
+	}
+	if r.ProbationSeconds != nil && *r.ProbationSeconds < 0 {
+		return nil, errors.New("probation_seconds must not be negative")
+	}
+	return &models.AutoDisablePolicy{
+		FailureCount:     r.FailureCount,
+		WindowSeconds:    r.WindowSeconds,
+		AutoDisable:      r.AutoDisable,
+		ProbationSeconds: r.ProbationSeconds,
+	}, nil
+}
+
+// RetrieveAutoDisablePolicy returns the tenant's auto-disable policy.
+func (h *TenantHandlers) RetrieveAutoDisablePolicy(c *gin.Context) {
+	tenant := mustTenantFromContext(c)
+	if tenant.AutoDisablePolicy == nil {
+		AbortWithError(c, http.StatusNotFound, NewErrNotFound("auto-disable policy"))
+		return
+	}
+	c.JSON(http.StatusOK, tenant.AutoDisablePolicy)
+}
+
+// UpdateAutoDisablePolicy replaces the tenant's auto-disable policy.
+func (h *TenantHandlers) UpdateAutoDisablePolicy(c *gin.Context) {
+	var input UpdateAutoDisablePolicyRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	policy, err := input.toAutoDisablePolicy()
+	if err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+
+	tenant := *mustTenantFromContext(c)
+	tenant.AutoDisablePolicy = policy
+	tenant.UpdatedAt = time.Now()
+	if err := h.tenantStore.UpsertTenant(c.Request.Context(), tenant); err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	h.logger.Ctx(c.Request.Context()).Audit("tenant auto-disable policy updated",
+		zap.String("tenant_id", tenant.ID),
+	)
+	c.JSON(http.StatusOK, policy)
+}
+
+// DeleteAutoDisablePolicy clears the tenant's auto-disable policy, so its
+// destinations follow the deployment's.
+func (h *TenantHandlers) DeleteAutoDisablePolicy(c *gin.Context) {
+	tenant := *mustTenantFromContext(c)
+	if tenant.AutoDisablePolicy != nil {
+		tenant.AutoDisablePolicy = nil
+		tenant.UpdatedAt = time.Now()
+		if err := h.tenantStore.UpsertTenant(c.Request.Context(), tenant); err != nil {
+			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+			return
+		}
+		h.logger.Ctx(c.Request.Context()).Audit("tenant auto-disable policy cleared",
+			zap.String("tenant_id", tenant.ID),
+		)
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
@@ -0,0 +1,92 @@
+package apirouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_TenantAutoDisablePolicy(t *testing.T) {
+	t.Run("admin sets policy", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/auto-disable-policy", map[string]any{
+			"failure_count":     5,
+			"auto_disable":      true,
+			"probation_seconds": 3600,
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"failure_count":5,"auto_disable":true,"probation_seconds":3600}`, resp.Body.String())
+
+		tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+		require.NoError(t, err)
+		require.NotNil(t, tenant.AutoDisablePolicy)
+		require.NotNil(t, tenant.AutoDisablePolicy.FailureCount)
+		assert.Equal(t, 5, *tenant.AutoDisablePolicy.FailureCount)
+		assert.Nil(t, tenant.AutoDisablePolicy.WindowSeconds, "omitted fields follow the deployment")
+	})
+
+	t.Run("get without policy returns 404", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/auto-disable-policy", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("delete clears policy", func(t *testing.T) {
+		h := newAPITest(t)
+		autoDisable := false
+		existing := tf.Any(tf.WithID("t1"))
+		existing.AutoDisablePolicy = &models.AutoDisablePolicy{AutoDisable: &autoDisable}
+		h.tenantStore.UpsertTenant(t.Context(), existing)
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/tenants/t1/auto-disable-policy", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+		require.NoError(t, err)
+		assert.Nil(t, tenant.AutoDisablePolicy)
+	})
+
+	t.Run("invalid policy returns 422", func(t *testing.T) {
+		for name, body := range map[string]map[string]any{
+			"empty":              {},
+			"zero failure count": {"failure_count": 0},
+			"negative window":    {"window_seconds": -1},
+			"negative probation": {"probation_seconds": -1},
+		} {
+			t.Run(name, func(t *testing.T) {
+				h := newAPITest(t)
+				h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/auto-disable-policy", body)
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+			})
+		}
+	})
+
+	t.Run("jwt returns 403", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/auto-disable-policy", map[string]any{
+			"failure_count": 5,
+		})
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
@@ -0,0 +1,104 @@
+package apirouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/bulkretry"
+	"github.com/hookdeck/outpost/internal/logging"
+	"go.uber.org/zap"
+)
+
+type bulkRetryJobs interface {
+	Start(ctx context.Context, tenantID string, filter bulkretry.Filter) (*bulkretry.Job, error)
+	Retrieve(ctx context.Context, tenantID, jobID string) (*bulkretry.Job, error)
+}
+
+type BulkRetryHandlers struct {
+	logger *logging.Logger
+	jobs   bulkRetryJobs
+}
+
+func NewBulkRetryHandlers(logger *logging.Logger, jobs bulkRetryJobs) *BulkRetryHandlers {
+	return &BulkRetryHandlers{
+		logger: logger,
+		jobs:   jobs,
+	}
+}
+
+// Start handles POST /tenants/:tenant_id/events/retry. It starts a job that
+// retries every delivery of the tenant matching the filter in the body and
+// returns the job without waiting for it. Admin only: a job can enqueue up to
+// bulkretry.MaxDeliveries retries.
+func (h *BulkRetryHandlers) Start(c *gin.Context) {
+	if !h.mustBeEnabled(c) {
+		return
+	}
+	var filter bulkretry.Filter
+	if err := c.ShouldBindJSON(&filter); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	tenant := mustTenantFromContext(c)
+
+	job, err := h.jobs.Start(c.Request.Context(), tenant.ID, filter)
+	if err != nil {
+		if errors.Is(err, bulkretry.ErrInvalidFilter) {
+			AbortWithValidationError(c, err)
+			return
+		}
+		if errors.Is(err, bulkretry.ErrTooManyJobs) {
+			AbortWithError(c, http.StatusTooManyRequests, ErrorResponse{
+				Code:    http.StatusTooManyRequests,
+				Message: err.Error(),
+			})
+			return
+		}
+		if errors.Is(err, bulkretry.ErrStopped) {
+			AbortWithError(c, http.StatusServiceUnavailable, ErrorResponse{
+				Code:    http.StatusServiceUnavailable,
+				Message: err.Error(),
+			})
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+
+	h.logger.Ctx(c.Request.Context()).Audit("bulk retry started",
+		zap.String("job_id", job.ID),
+		zap.String("tenant_id", tenant.ID))
+	c.JSON(http.StatusAccepted, job)
+}
+
+// Retrieve handles GET /tenants/:tenant_id/events/retry/:job_id.
+func (h *BulkRetryHandlers) Retrieve(c *gin.Context) {
+	if !h.mustBeEnabled(c) {
+		return
+	}
+	tenant := mustTenantFromContext(c)
+
+	job, err := h.jobs.Retrieve(c.Request.Context(), tenant.ID, c.Param("job_id"))
+	if err != nil {
+		if errors.Is(err, bulkretry.ErrNotFound) {
+			AbortWithError(c, http.StatusNotFound, NewErrNotFound("job"))
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	c.JSON(http.StatusOK, job)
+}
+
+func (h *BulkRetryHandlers) mustBeEnabled(c *gin.Context) bool {
+	if h.jobs == nil {
+		AbortWithError(c, http.StatusNotImplemented, ErrorResponse{
+			Code:    http.StatusNotImplemented,
+			Message: "bulk retries are not enabled",
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/bulkretry"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_BulkRetry(t *testing.T) {
+	setup := func(t *testing.T, opts ...apiTestOption) *apiTest {
+		t.Helper()
+		h := newAPITest(t, opts...)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.UpsertDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1"), df.WithTopics([]string{"*"})))
+		e1 := ef.AnyPointer(ef.WithID("e1"), ef.WithTenantID("t1"), ef.WithTopic("user.created"))
+		e2 := ef.AnyPointer(ef.WithID("e2"), ef.WithTenantID("t1"), ef.WithTopic("user.deleted"))
+		require.NoError(t, h.logStore.InsertMany(t.Context(), []*models.LogEntry{
+			{Event: e1, Attempt: attemptForEvent(e1, af.WithDestinationID("d1"), af.WithStatus(models.AttemptStatusFailed))},
+			{Event: e2, Attempt: attemptForEvent(e2, af.WithDestinationID("d1"), af.WithStatus(models.AttemptStatusFailed))},
+		}))
+		return h
+	}
+
+	end := time.Now().Add(time.Minute)
+	start := end.Add(-time.Hour)
+	filter := func(fields map[string]any) map[string]any {
+		body := map[string]any{"start": start, "end": end}
+		for k, v := range fields {
+			body[k] = v
+		}
+		return body
+	}
+
+	awaitJob := func(t *testing.T, h *apiTest, jobID string) bulkretry.Job {
+		t.Helper()
+		var job bulkretry.Job
+		require.Eventually(t, func() bool {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/events/retry/"+jobID, nil)
+			resp := h.do(h.withAPIKey(req))
+			if resp.Code != http.StatusOK {
+				return false
+			}
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &job))
+			return job.Status != bulkretry.StatusRunning
+		}, 5*time.Second, 10*time.Millisecond)
+		return job
+	}
+
+	t.Run("starts a job that retries matching deliveries", func(t *testing.T) {
+		h := setup(t, withBulkRetries())
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/events/retry", filter(map[string]any{
+			"topics": []string{"user.created"},
+		}))
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusAccepted, resp.Code)
+		var started bulkretry.Job
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &started))
+		assert.NotEmpty(t, started.ID)
+		assert.Equal(t, "t1", started.TenantID)
+		assert.Equal(t, models.AttemptStatusFailed, started.Filter.Status)
+
+		job := awaitJob(t, h, started.ID)
+		assert.Equal(t, bulkretry.StatusCompleted, job.Status)
+		assert.Equal(t, 1, job.Matched)
+		assert.Equal(t, 1, job.Enqueued)
+	})
+
+	t.Run("jwt returns 403", func(t *testing.T) {
+		h := setup(t, withBulkRetries())
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/events/retry", filter(nil))
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+
+	t.Run("missing time range returns 422", func(t *testing.T) {
+		h := setup(t, withBulkRetries())
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/events/retry", map[string]any{})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	t.Run("invalid status returns 422", func(t *testing.T) {
+		h := setup(t, withBulkRetries())
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/events/retry", filter(map[string]any{
+			"status": "pending",
+		}))
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	t.Run("unknown job returns 404", func(t *testing.T) {
+		h := setup(t, withBulkRetries())
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/events/retry/job_missing", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("not enabled returns 501", func(t *testing.T) {
+		h := setup(t)
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/events/retry", filter(nil))
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusNotImplemented, resp.Code)
+	})
+}
@@ -17,6 +17,12 @@ func (d *destinationDisplayer) Display(dest *models.Destination) (*destregistry.
 	return d.registry.DisplayDestination(dest)
 }
 
+// DisplayRevealed displays a destination whose credentials were just set by the
+// caller, bypassing any redaction the provider applies to stored secrets.
+func (d *destinationDisplayer) DisplayRevealed(dest *models.Destination) (*destregistry.DestinationDisplay, error) {
+	return destregistry.DisplayRevealed(d.registry, dest)
+}
+
 func (d *destinationDisplayer) DisplayList(destinations []models.Destination) ([]*destregistry.DestinationDisplay, error) {
 	result := make([]*destregistry.DestinationDisplay, len(destinations))
 	for i := range destinations {
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"net/http"
 	"slices"
 	"strings"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/metadata"
 	"github.com/hookdeck/outpost/internal/idgen"
 	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/models"
@@ -33,13 +35,16 @@ type DestinationHandlers struct {
 	telemetry            telemetry.Telemetry
 	tenantStore          tenantstore.TenantStore
 	emitter              SubscriptionEmitter
-	topics               []string
+	topics               topicLister
 	topicsAllowWildcards bool
+	topicLifecycle       models.TopicLifecycleSource
 	registry             destregistry.Registry
 	displayer            *destinationDisplayer
+	quota                tenantQuota
+	health               destHealthStore
 }
 
-func NewDestinationHandlers(logger *logging.Logger, telemetry telemetry.Telemetry, tenantStore tenantstore.TenantStore, emitter SubscriptionEmitter, topics []string, topicsAllowWildcards bool, registry destregistry.Registry, displayer *destinationDisplayer) *DestinationHandlers {
+func NewDestinationHandlers(logger *logging.Logger, telemetry telemetry.Telemetry, tenantStore tenantstore.TenantStore, emitter SubscriptionEmitter, topics topicLister, topicsAllowWildcards bool, topicLifecycle models.TopicLifecycleSource, registry destregistry.Registry, displayer *destinationDisplayer, quota tenantQuota, health destHealthStore) *DestinationHandlers {
 	return &DestinationHandlers{
 		logger:               logger,
 		telemetry:            telemetry,
@@ -47,14 +52,23 @@ func NewDestinationHandlers(logger *logging.Logger, telemetry telemetry.Telemetr
 		emitter:              emitter,
 		topics:               topics,
 		topicsAllowWildcards: topicsAllowWildcards,
+		topicLifecycle:       topicLifecycle,
 		registry:             registry,
 		displayer:            displayer,
+		quota:                quota,
+		health:               health,
 	}
 }
 
 func (h *DestinationHandlers) List(c *gin.Context) {
 	tenant := mustTenantFromContext(c)
 
+	status, errResp := parseHealthStatus(c)
+	if errResp != nil {
+		AbortWithError(c, errResp.Code, *errResp)
+		return
+	}
+
 	destinations, err := h.tenantStore.ListDestination(c.Request.Context(), tenantstore.ListDestinationRequest{
 		TenantID: tenant.ID,
 		Type:     ParseArrayQueryParam(c, "type"),
@@ -70,8 +84,33 @@ func (h *DestinationHandlers) List(c *gin.Context) {
 		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
 		return
 	}
+	if err := h.attachHealth(c.Request.Context(), tenant.ID, displayDestinations); err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	if status != "" {
+		displayDestinations = slices.DeleteFunc(displayDestinations, func(display *destregistry.DestinationDisplay) bool {
+			return healthStatus(display) != status
+		})
+	}
 
-	c.JSON(http.StatusOK, displayDestinations)
+	// All of a tenant's destinations are returned in one page, so the applied
+	// page size is the tenant's destination limit.
+	limit := h.quota.limit
+	if limit <= 0 {
+		limit = len(displayDestinations)
+	}
+
+	h.quota.setHeaders(c, tenant.DestinationsCount)
+	c.JSON(http.StatusOK, DestinationPaginatedResult{
+		Models: displayDestinations,
+		Pagination: SeekPagination{
+			OrderBy: "created_at",
+			Dir:     "asc",
+			Limit:   limit,
+		},
+		Count: len(displayDestinations),
+	})
 }
 
 func (h *DestinationHandlers) Create(c *gin.Context) {
@@ -105,15 +144,25 @@ func (h *DestinationHandlers) Create(c *gin.Context) {
 	prev := h.snapshotTenant(tenant)
 
 	destination := input.ToDestination(tenant.ID)
-	if err := destination.Validate(h.topics, h.topicsAllowWildcards); err != nil {
+	if !tenant.AllowsDestinationType(destination.Type) {
+		AbortWithError(c, http.StatusForbidden, errDestinationTypeNotEnabled(destination.Type))
+		return
+	}
+	if err := destination.Validate(h.topics.Topics(c.Request.Context()), h.topicsAllowWildcards); err != nil {
 		AbortWithValidationError(c, err)
 		return
 	}
 	destination.Topics = destination.Topics.Normalize()
+	if !h.mustCheckTopicLifecycle(c, destination.Topics, nil) {
+		return
+	}
 	if err := h.registry.ValidateDestination(c.Request.Context(), &destination); err != nil {
 		AbortWithValidationError(c, err)
 		return
 	}
+	if !h.mustValidateShadowDestination(c, &destination) {
+		return
+	}
 	if err := h.registry.PreprocessDestination(&destination, nil, &destregistry.PreprocessDestinationOpts{
 		Role: mustRoleFromContext(c),
 		Request: destregistry.PreprocessRequest{
@@ -124,19 +173,38 @@ func (h *DestinationHandlers) Create(c *gin.Context) {
 		AbortWithValidationError(c, err)
 		return
 	}
+	// A destination whose endpoint fails verification is created disabled,
+	// and is verified again when enabled.
+	if destination.DisabledAt == nil {
+		if err := h.verifyEndpoint(c, tenant, &destination); err != nil {
+			if !errors.Is(err, destregistry.ErrEndpointNotVerified) {
+				AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+				return
+			}
+			destination.DisabledAt = &now
+		}
+	}
 	if err := h.tenantStore.CreateDestination(c.Request.Context(), destination); err != nil {
+		if errors.Is(err, tenantstore.ErrMaxDestinationsPerTenantReached) {
+			h.quota.setHeaders(c, prev.destinationsCount)
+		}
 		h.handleUpsertDestinationError(c, err)
 		return
 	}
 	h.telemetry.DestinationCreated(c.Request.Context(), destination.Type)
 	h.emitSubscriptionUpdateIfChanged(c.Request.Context(), tenant.ID, prev)
+	h.quota.report(c, h.logger, h.emitter, tenant.ID, prev.destinationsCount, prev.destinationsCount+1)
 	h.logger.Ctx(c.Request.Context()).Audit("destination created",
 		zap.String("tenant_id", tenant.ID),
 		zap.String("destination_id", destination.ID),
 		zap.String("destination_type", destination.Type),
 	)
+	h.auditTLSVerification(c, nil, &destination)
+	h.recordVersion(c, nil, &destination)
 
-	display, err := h.displayer.Display(&destination)
+	// The response to the create request is the one place a generated secret
+	// is returned regardless of the secret retrieval policy.
+	display, err := h.displayer.DisplayRevealed(&destination)
 	if err != nil {
 		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
 		return
@@ -156,6 +224,10 @@ func (h *DestinationHandlers) Retrieve(c *gin.Context) {
 		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
 		return
 	}
+	if err := h.attachHealth(c.Request.Context(), tenant.ID, []*destregistry.DestinationDisplay{display}); err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
 	c.JSON(http.StatusOK, display)
 }
 
@@ -180,17 +252,28 @@ func (h *DestinationHandlers) Update(c *gin.Context) {
 	// Validate.
 	if input.Topics != nil {
 		updatedDestination.Topics = input.Topics
-		if err := updatedDestination.Validate(h.topics, h.topicsAllowWildcards); err != nil {
+		if err := updatedDestination.Topics.Validate(h.topics.Topics(c.Request.Context()), h.topicsAllowWildcards); err != nil {
 			AbortWithValidationError(c, err)
 			return
 		}
 		updatedDestination.Topics = updatedDestination.Topics.Normalize()
+		if !h.mustCheckTopicLifecycle(c, updatedDestination.Topics, originalDestination.Topics) {
+			return
+		}
 	}
 	shouldRevalidate := false
 	if input.Type != "" && input.Type != originalDestination.Type {
 		AbortWithValidationError(c, errors.New("type cannot be updated"))
 		return
 	}
+	queuedMessages := ""
+	if input.QueuedMessages != nil {
+		queuedMessages = *input.QueuedMessages
+		if err := models.ValidateQueuedMessages(queuedMessages); err != nil {
+			AbortWithValidationError(c, err)
+			return
+		}
+	}
 
 	// Config (merge-patch)
 	configResult, configRequest, configChanged, err := applyMergePatchStringMap(originalDestination.Config, input.Config)
@@ -224,6 +307,10 @@ func (h *DestinationHandlers) Update(c *gin.Context) {
 				AbortWithValidationError(c, fmt.Errorf("invalid filter: %w", err))
 				return
 			}
+			if err := filter.Validate(); err != nil {
+				AbortWithValidationError(c, err)
+				return
+			}
 			updatedDestination.Filter = filter
 		}
 	}
@@ -248,6 +335,72 @@ func (h *DestinationHandlers) Update(c *gin.Context) {
 		updatedDestination.Metadata = metaResult
 	}
 
+	// RetryPolicy (full replacement)
+	if input.RetryPolicy != nil {
+		if isJSONNull(input.RetryPolicy) {
+			updatedDestination.RetryPolicy = nil
+		} else {
+			var policy models.RetryPolicy
+			if err := json.Unmarshal(input.RetryPolicy, &policy); err != nil {
+				AbortWithValidationError(c, fmt.Errorf("invalid retry_policy: %w", err))
+				return
+			}
+			if err := policy.Validate(); err != nil {
+				AbortWithValidationError(c, err)
+				return
+			}
+			updatedDestination.RetryPolicy = &policy
+		}
+	}
+
+	// Transformation (full replacement)
+	if input.Transformation != nil {
+		if isJSONNull(input.Transformation) {
+			updatedDestination.Transformation = nil
+		} else {
+			var transformation models.Transformation
+			if err := json.Unmarshal(input.Transformation, &transformation); err != nil {
+				AbortWithValidationError(c, fmt.Errorf("invalid transformation: %w", err))
+				return
+			}
+			if err := transformation.Validate(); err != nil {
+				AbortWithValidationError(c, err)
+				return
+			}
+			updatedDestination.Transformation = &transformation
+		}
+	}
+
+	// MaxPayloadBytes
+	if input.MaxPayloadBytes != nil {
+		updatedDestination.MaxPayloadBytes = *input.MaxPayloadBytes
+	}
+
+	// MaxConcurrency and MaxDeliveriesPerSecond
+	if input.MaxConcurrency != nil {
+		updatedDestination.MaxConcurrency = *input.MaxConcurrency
+	}
+	if input.MaxDeliveriesPerSecond != nil {
+		updatedDestination.MaxDeliveriesPerSecond = *input.MaxDeliveriesPerSecond
+	}
+	if updatedDestination.MaxConcurrency < 0 || updatedDestination.MaxDeliveriesPerSecond < 0 {
+		AbortWithValidationError(c, models.ErrInvalidDeliveryCaps)
+		return
+	}
+
+	// SandboxSafe
+	if input.SandboxSafe != nil {
+		updatedDestination.SandboxSafe = *input.SandboxSafe
+	}
+
+	// ShadowDestinationID ("" clears)
+	if input.ShadowID != nil {
+		updatedDestination.ShadowDestinationID = *input.ShadowID
+		if !h.mustValidateShadowDestination(c, &updatedDestination) {
+			return
+		}
+	}
+
 	// DisabledAt
 	//   omitted: leave alone
 	//   null:    enable (clear)
@@ -295,8 +448,18 @@ func (h *DestinationHandlers) Update(c *gin.Context) {
 		}
 	}
 
+	// Enabling the destination, or changing the config of an enabled one,
+	// verifies its endpoint again.
+	if updatedDestination.DisabledAt == nil && (originalDestination.DisabledAt != nil || configChanged) {
+		if !h.mustVerifyEndpoint(c, tenant, &updatedDestination) {
+			return
+		}
+	}
+
 	// Update destination.
-	updatedDestination.UpdatedAt = time.Now()
+	now := time.Now()
+	destregistry.RecordTargetChange(h.registry, originalDestination, &updatedDestination, queuedMessages, now)
+	updatedDestination.UpdatedAt = now
 	if err := h.tenantStore.UpsertDestination(c.Request.Context(), updatedDestination); err != nil {
 		h.handleUpsertDestinationError(c, err)
 		return
@@ -318,13 +481,20 @@ func (h *DestinationHandlers) Update(c *gin.Context) {
 			zap.String("destination_type", updatedDestination.Type),
 		)
 	}
+	h.auditTLSVerification(c, originalDestination, &updatedDestination)
+	h.recordVersion(c, originalDestination, &updatedDestination)
 
-	display, err := h.displayer.Display(&updatedDestination)
+	display := h.displayer.Display
+	if !maps.Equal(originalDestination.Credentials, updatedDestination.Credentials) {
+		// Credentials were set or rotated by this request: return them once.
+		display = h.displayer.DisplayRevealed
+	}
+	result, err := display(&updatedDestination)
 	if err != nil {
 		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
 		return
 	}
-	c.JSON(http.StatusOK, display)
+	c.JSON(http.StatusOK, result)
 }
 
 func (h *DestinationHandlers) Delete(c *gin.Context) {
@@ -356,19 +526,60 @@ func (h *DestinationHandlers) Enable(c *gin.Context) {
 	h.setDisabilityHandler(c, false)
 }
 
+// ListProviderMetadata lists the destination types. For a tenant, it only
+// lists the types the tenant may create.
 func (h *DestinationHandlers) ListProviderMetadata(c *gin.Context) {
-	metadata := h.registry.ListProviderMetadata()
-	c.JSON(http.StatusOK, metadata)
+	providers := h.registry.ListProviderMetadata()
+	if tenant := tenantFromContext(c); tenant != nil {
+		providers = slices.DeleteFunc(slices.Clone(providers), func(provider *metadata.ProviderMetadata) bool {
+			return !tenant.AllowsDestinationType(provider.Type)
+		})
+	}
+	c.JSON(http.StatusOK, providers)
 }
 
 func (h *DestinationHandlers) RetrieveProviderMetadata(c *gin.Context) {
+	provider := h.mustRetrieveProviderMetadata(c)
+	if provider == nil {
+		return
+	}
+	c.JSON(http.StatusOK, provider)
+}
+
+// RetrieveProviderSchema returns the JSON Schema of a destination type's
+// create payload, for clients to validate config and credentials locally.
+func (h *DestinationHandlers) RetrieveProviderSchema(c *gin.Context) {
+	provider := h.mustRetrieveProviderMetadata(c)
+	if provider == nil {
+		return
+	}
+	c.JSON(http.StatusOK, provider.JSONSchema())
+}
+
+// mustRetrieveProviderMetadata returns the metadata of the :type destination
+// type, or responds 404 when the type doesn't exist or isn't enabled for the
+// tenant.
+func (h *DestinationHandlers) mustRetrieveProviderMetadata(c *gin.Context) *metadata.ProviderMetadata {
 	providerType := c.Param("type")
-	metadata, err := h.registry.RetrieveProviderMetadata(providerType)
+	if tenant := tenantFromContext(c); tenant != nil && !tenant.AllowsDestinationType(providerType) {
+		c.Status(http.StatusNotFound)
+		return nil
+	}
+	provider, err := h.registry.RetrieveProviderMetadata(providerType)
 	if err != nil {
 		c.Status(http.StatusNotFound)
-		return
+		return nil
+	}
+	return provider
+}
+
+// errDestinationTypeNotEnabled is the error for creating a destination of a
+// type the tenant's destination types don't include.
+func errDestinationTypeNotEnabled(destinationType string) ErrorResponse {
+	return ErrorResponse{
+		Code:    http.StatusForbidden,
+		Message: fmt.Sprintf("destination type %q is not enabled for this tenant", destinationType),
 	}
-	c.JSON(http.StatusOK, metadata)
 }
 
 func (h *DestinationHandlers) setDisabilityHandler(c *gin.Context, disabled bool) {
@@ -385,6 +596,9 @@ func (h *DestinationHandlers) setDisabilityHandler(c *gin.Context, disabled bool
 		destination.DisabledAt = &now
 	}
 	if !disabled && destination.DisabledAt != nil {
+		if !h.mustVerifyEndpoint(c, tenant, destination) {
+			return
+		}
 		shouldUpdate = true
 		destination.DisabledAt = nil
 	}
@@ -430,6 +644,101 @@ func (h *DestinationHandlers) mustRetrieveDestination(c *gin.Context, tenantID,
 	return destination
 }
 
+// deprecatedTopicsHeader lists the deprecated topics a destination is
+// subscribed to in the response to a create or update.
+const deprecatedTopicsHeader = "X-Outpost-Deprecated-Topics"
+
+// mustCheckTopicLifecycle rejects subscriptions to retired topics the
+// destination was not already subscribed to and flags deprecated topics with
+// a response header. It aborts the request and returns false when a retired
+// topic is added.
+func (h *DestinationHandlers) mustCheckTopicLifecycle(c *gin.Context, topics, previousTopics models.Topics) bool {
+	lifecycle := h.topicLifecycle.Lifecycle(c.Request.Context())
+	if messages := retiredTopicErrors(lifecycle, topics, previousTopics); len(messages) > 0 {
+		AbortWithValidationError(c, ErrorResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "validation error",
+			Data:    messages,
+		})
+		return false
+	}
+	if deprecated := lifecycle.DeprecatedIn(topics); len(deprecated) > 0 {
+		c.Header(deprecatedTopicsHeader, strings.Join(deprecated, ","))
+	}
+	return true
+}
+
+// retiredTopicErrors returns a message for each retired topic in topics that
+// is not in previousTopics.
+func retiredTopicErrors(lifecycle models.TopicLifecycle, topics, previousTopics models.Topics) []string {
+	var messages []string
+	for _, topic := range lifecycle.RetiredIn(topics) {
+		if !slices.Contains(previousTopics, topic) {
+			messages = append(messages, fmt.Sprintf("topic %s is retired", topic))
+		}
+	}
+	return messages
+}
+
+// mustValidateShadowDestination ensures a configured shadow destination exists
+// in the same tenant and is not the destination itself. It aborts the request
+// and returns false when validation fails.
+func (h *DestinationHandlers) mustValidateShadowDestination(c *gin.Context, destination *models.Destination) bool {
+	if err := h.validateShadowDestination(c.Request.Context(), destination); err != nil {
+		if errors.Is(err, errShadowDestinationSelf) || errors.Is(err, errShadowDestinationMissing) {
+			AbortWithValidationError(c, err)
+			return false
+		}
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return false
+	}
+	return true
+}
+
+var (
+	errShadowDestinationSelf    = errors.New("shadow_destination_id cannot reference the destination itself")
+	errShadowDestinationMissing = errors.New("shadow_destination_id must reference an existing destination of the same tenant")
+)
+
+// validateShadowDestination returns errShadowDestinationSelf or
+// errShadowDestinationMissing when the shadow destination is invalid, or the
+// store error when it could not be checked.
+func (h *DestinationHandlers) validateShadowDestination(ctx context.Context, destination *models.Destination) error {
+	if destination.ShadowDestinationID == "" {
+		return nil
+	}
+	if destination.ShadowDestinationID == destination.ID {
+		return errShadowDestinationSelf
+	}
+	shadow, err := h.tenantStore.RetrieveDestination(ctx, destination.TenantID, destination.ShadowDestinationID)
+	if err != nil && !errors.Is(err, tenantstore.ErrDestinationDeleted) {
+		return err
+	}
+	if shadow == nil {
+		return errShadowDestinationMissing
+	}
+	return nil
+}
+
+// auditTLSVerification records turning off, or back on, the verification of a
+// destination server's TLS certificate, as it exposes deliveries to
+// interception.
+func (h *DestinationHandlers) auditTLSVerification(c *gin.Context, previous, destination *models.Destination) {
+	disabled := destregistry.TLSVerificationDisabled(destination)
+	if disabled == destregistry.TLSVerificationDisabled(previous) {
+		return
+	}
+	action := "destination TLS verification enabled"
+	if disabled {
+		action = "destination TLS verification disabled"
+	}
+	h.logger.Ctx(c.Request.Context()).Audit(action,
+		zap.String("tenant_id", destination.TenantID),
+		zap.String("destination_id", destination.ID),
+		zap.String("destination_type", destination.Type),
+	)
+}
+
 func (h *DestinationHandlers) handleUpsertDestinationError(c *gin.Context, err error) {
 	if strings.Contains(err.Error(), "validation failed") {
 		AbortWithValidationError(c, err)
@@ -439,23 +748,49 @@ func (h *DestinationHandlers) handleUpsertDestinationError(c *gin.Context, err e
 		AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(err))
 		return
 	}
+	if errors.Is(err, tenantstore.ErrMaxDestinationsPerTenantReached) {
+		AbortWithError(c, http.StatusTooManyRequests, ErrorResponse{
+			Code:    http.StatusTooManyRequests,
+			Message: err.Error(),
+		})
+		return
+	}
 	AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
 }
 
+// ===== Responses =====
+
+// DestinationPaginatedResult is the response for listing destinations. It uses
+// the same envelope as the other list endpoints; since a tenant's destinations
+// are always returned in a single page, both cursors are null, and the count,
+// as in every list, is the total number of matching destinations.
+type DestinationPaginatedResult struct {
+	Models     []*destregistry.DestinationDisplay `json:"models"`
+	Pagination SeekPagination                     `json:"pagination"`
+	Count      int                                `json:"count"`
+}
+
 // ===== Requests =====
 
 type CreateDestinationRequest struct {
-	ID               string                  `json:"id" binding:"-"`
-	Type             string                  `json:"type" binding:"required"`
-	Topics           models.Topics           `json:"topics" binding:"required"`
-	Filter           models.Filter           `json:"filter,omitempty" binding:"-"`
-	Config           models.Config           `json:"config" binding:"-"`
-	Credentials      models.Credentials      `json:"credentials" binding:"-"`
-	DeliveryMetadata models.DeliveryMetadata `json:"delivery_metadata,omitempty" binding:"-"`
-	Metadata         models.Metadata         `json:"metadata,omitempty" binding:"-"`
-	CreatedAt        *time.Time              `json:"created_at,omitempty" binding:"-"`
-	UpdatedAt        *time.Time              `json:"updated_at,omitempty" binding:"-"`
-	DisabledAt       *time.Time              `json:"disabled_at,omitempty" binding:"-"`
+	ID                     string                  `json:"id" binding:"-"`
+	Type                   string                  `json:"type" binding:"required"`
+	Topics                 models.Topics           `json:"topics" binding:"required"`
+	Filter                 models.Filter           `json:"filter,omitempty" binding:"-"`
+	Config                 models.Config           `json:"config" binding:"-"`
+	Credentials            models.Credentials      `json:"credentials" binding:"-"`
+	DeliveryMetadata       models.DeliveryMetadata `json:"delivery_metadata,omitempty" binding:"-"`
+	Metadata               models.Metadata         `json:"metadata,omitempty" binding:"-"`
+	RetryPolicy            *models.RetryPolicy     `json:"retry_policy,omitempty" binding:"-"`
+	Transformation         *models.Transformation  `json:"transformation,omitempty" binding:"-"`
+	MaxPayloadBytes        int                     `json:"max_payload_bytes,omitempty" binding:"-"`
+	MaxConcurrency         int                     `json:"max_concurrency,omitempty" binding:"-"`
+	MaxDeliveriesPerSecond int                     `json:"max_deliveries_per_second,omitempty" binding:"-"`
+	SandboxSafe            bool                    `json:"sandbox_safe,omitempty" binding:"-"`
+	ShadowID               string                  `json:"shadow_destination_id,omitempty" binding:"-"`
+	CreatedAt              *time.Time              `json:"created_at,omitempty" binding:"-"`
+	UpdatedAt              *time.Time              `json:"updated_at,omitempty" binding:"-"`
+	DisabledAt             *time.Time              `json:"disabled_at,omitempty" binding:"-"`
 }
 
 func (r *CreateDestinationRequest) ToDestination(tenantID string) models.Destination {
@@ -479,30 +814,45 @@ func (r *CreateDestinationRequest) ToDestination(tenantID string) models.Destina
 		updatedAt = *r.UpdatedAt
 	}
 	return models.Destination{
-		ID:               r.ID,
-		Type:             r.Type,
-		Topics:           r.Topics,
-		Filter:           r.Filter,
-		Config:           r.Config,
-		Credentials:      r.Credentials,
-		DeliveryMetadata: r.DeliveryMetadata,
-		Metadata:         r.Metadata,
-		CreatedAt:        createdAt,
-		UpdatedAt:        updatedAt,
-		DisabledAt:       r.DisabledAt,
-		TenantID:         tenantID,
+		ID:                     r.ID,
+		Type:                   r.Type,
+		Topics:                 r.Topics,
+		Filter:                 r.Filter,
+		Config:                 r.Config,
+		Credentials:            r.Credentials,
+		DeliveryMetadata:       r.DeliveryMetadata,
+		Metadata:               r.Metadata,
+		RetryPolicy:            r.RetryPolicy,
+		Transformation:         r.Transformation,
+		MaxPayloadBytes:        r.MaxPayloadBytes,
+		MaxConcurrency:         r.MaxConcurrency,
+		MaxDeliveriesPerSecond: r.MaxDeliveriesPerSecond,
+		SandboxSafe:            r.SandboxSafe,
+		ShadowDestinationID:    r.ShadowID,
+		CreatedAt:              createdAt,
+		UpdatedAt:              updatedAt,
+		DisabledAt:             r.DisabledAt,
+		TenantID:               tenantID,
 	}
 }
 
 type UpdateDestinationRequest struct {
-	Type             string          `json:"type" binding:"-"`
-	Topics           models.Topics   `json:"topics" binding:"-"`
-	Filter           json.RawMessage `json:"filter" binding:"-"`
-	Config           json.RawMessage `json:"config" binding:"-"`
-	Credentials      json.RawMessage `json:"credentials" binding:"-"`
-	DeliveryMetadata json.RawMessage `json:"delivery_metadata" binding:"-"`
-	Metadata         json.RawMessage `json:"metadata" binding:"-"`
-	DisabledAt       json.RawMessage `json:"disabled_at" binding:"-"`
+	Type                   string          `json:"type" binding:"-"`
+	Topics                 models.Topics   `json:"topics" binding:"-"`
+	Filter                 json.RawMessage `json:"filter" binding:"-"`
+	Config                 json.RawMessage `json:"config" binding:"-"`
+	Credentials            json.RawMessage `json:"credentials" binding:"-"`
+	DeliveryMetadata       json.RawMessage `json:"delivery_metadata" binding:"-"`
+	Metadata               json.RawMessage `json:"metadata" binding:"-"`
+	RetryPolicy            json.RawMessage `json:"retry_policy" binding:"-"`
+	Transformation         json.RawMessage `json:"transformation" binding:"-"`
+	MaxPayloadBytes        *int            `json:"max_payload_bytes" binding:"-"`
+	MaxConcurrency         *int            `json:"max_concurrency" binding:"-"`
+	MaxDeliveriesPerSecond *int            `json:"max_deliveries_per_second" binding:"-"`
+	SandboxSafe            *bool           `json:"sandbox_safe" binding:"-"`
+	ShadowID               *string         `json:"shadow_destination_id" binding:"-"`
+	DisabledAt             json.RawMessage `json:"disabled_at" binding:"-"`
+	QueuedMessages         *string         `json:"queued_messages" binding:"-"`
 }
 
 // isJSONNull checks if raw JSON bytes represent a JSON null literal.
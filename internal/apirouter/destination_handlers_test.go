@@ -2,15 +2,19 @@ package apirouter_test
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
 	"time"
 
+	"github.com/hookdeck/outpost/internal/apirouter"
 	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/metadata"
 	"github.com/hookdeck/outpost/internal/models"
 	"github.com/hookdeck/outpost/internal/opevents"
 	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/tenantstore/memtenantstore"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -57,6 +61,21 @@ func TestAPI_Destinations(t *testing.T) {
 			require.Equal(t, http.StatusCreated, resp.Code)
 		})
 
+		t.Run("type not enabled for tenant returns 403", func(t *testing.T) {
+			h := newAPITest(t)
+			tenant := tf.Any(tf.WithID("t1"))
+			tenant.DestinationTypes = []string{"aws_sqs"}
+			h.tenantStore.UpsertTenant(t.Context(), tenant)
+
+			req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", validDestination())
+			resp := h.do(h.withJWT(req, "t1"))
+
+			require.Equal(t, http.StatusForbidden, resp.Code)
+			dests, err := h.tenantStore.ListDestination(t.Context(), tenantstore.ListDestinationRequest{TenantID: "t1"})
+			require.NoError(t, err)
+			assert.Empty(t, dests)
+		})
+
 		t.Run("missing type returns 422", func(t *testing.T) {
 			h := newAPITest(t)
 			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
@@ -182,6 +201,130 @@ func TestAPI_Destinations(t *testing.T) {
 			assert.Equal(t, models.Topics{"user.*"}, stored.Topics)
 		})
 
+		t.Run("retry_policy is saved", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			body := validDestination()
+			body["retry_policy"] = map[string]any{"max_attempts": 3, "schedule": []int{5, 60}}
+			req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", body)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusCreated, resp.Code)
+			var dest destregistry.DestinationDisplay
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dest))
+			expected := &models.RetryPolicy{MaxAttempts: 3, Schedule: []int{5, 60}}
+			assert.Equal(t, expected, dest.RetryPolicy)
+
+			stored, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", dest.ID)
+			require.NoError(t, err)
+			assert.Equal(t, expected, stored.RetryPolicy)
+		})
+
+		t.Run("invalid retry_policy returns 422", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			body := validDestination()
+			body["retry_policy"] = map[string]any{"backoff": "scheduled"}
+			req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", body)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+		})
+
+		t.Run("transformation is saved", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			body := validDestination()
+			body["transformation"] = map[string]any{"type": "jq", "expression": "{id: $id, user: .user.id}"}
+			req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", body)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusCreated, resp.Code)
+			var dest destregistry.DestinationDisplay
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dest))
+			expected := &models.Transformation{Type: "jq", Expression: "{id: $id, user: .user.id}"}
+			assert.Equal(t, expected, dest.Transformation)
+
+			stored, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", dest.ID)
+			require.NoError(t, err)
+			assert.Equal(t, expected, stored.Transformation)
+		})
+
+		t.Run("invalid transformation returns 422", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			body := validDestination()
+			body["transformation"] = map[string]any{"type": "jq", "expression": "{id:"}
+			req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", body)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+		})
+
+		t.Run("max_payload_bytes is saved", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			body := validDestination()
+			body["max_payload_bytes"] = 262144
+			req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", body)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusCreated, resp.Code)
+			var dest destregistry.DestinationDisplay
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dest))
+			assert.Equal(t, 262144, dest.MaxPayloadBytes)
+
+			stored, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", dest.ID)
+			require.NoError(t, err)
+			assert.Equal(t, 262144, stored.MaxPayloadBytes)
+		})
+
+		t.Run("negative max_payload_bytes returns 422", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			body := validDestination()
+			body["max_payload_bytes"] = -1
+			req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", body)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+		})
+
+		t.Run("delivery caps are saved", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			body := validDestination()
+			body["max_concurrency"] = 4
+			body["max_deliveries_per_second"] = 20
+			req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", body)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusCreated, resp.Code)
+			var dest destregistry.DestinationDisplay
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dest))
+			assert.Equal(t, 4, dest.MaxConcurrency)
+			assert.Equal(t, 20, dest.MaxDeliveriesPerSecond)
+		})
+
+		t.Run("negative max_concurrency returns 422", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			body := validDestination()
+			body["max_concurrency"] = -1
+			req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", body)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+		})
+
 		t.Run("import timestamps", func(t *testing.T) {
 			t.Run("disabled_at preserved on create", func(t *testing.T) {
 				h := newAPITest(t)
@@ -407,9 +550,28 @@ func TestAPI_Destinations(t *testing.T) {
 
 			require.Equal(t, http.StatusOK, resp.Code)
 
-			var dests []destregistry.DestinationDisplay
-			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dests))
-			assert.Len(t, dests, 2)
+			var result apirouter.DestinationPaginatedResult
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+			assert.Len(t, result.Models, 2)
+			assert.Equal(t, 2, result.Count)
+			assert.Nil(t, result.Pagination.Next)
+			assert.Nil(t, result.Pagination.Prev)
+		})
+
+		t.Run("empty list reports the destination limit as page size", func(t *testing.T) {
+			h := newAPITest(t, withDestinationQuota(20, 0))
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations", nil)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+
+			var result apirouter.DestinationPaginatedResult
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+			assert.Empty(t, result.Models)
+			assert.Equal(t, 0, result.Count)
+			assert.Equal(t, 20, result.Pagination.Limit)
 		})
 
 		t.Run("jwt returns destinations on own tenant", func(t *testing.T) {
@@ -422,9 +584,9 @@ func TestAPI_Destinations(t *testing.T) {
 
 			require.Equal(t, http.StatusOK, resp.Code)
 
-			var dests []destregistry.DestinationDisplay
-			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dests))
-			assert.Len(t, dests, 1)
+			var result apirouter.DestinationPaginatedResult
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+			assert.Len(t, result.Models, 1)
 		})
 
 		t.Run("Filtering", func(t *testing.T) {
@@ -445,10 +607,10 @@ func TestAPI_Destinations(t *testing.T) {
 
 				require.Equal(t, http.StatusOK, resp.Code)
 
-				var dests []destregistry.DestinationDisplay
-				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dests))
-				require.Len(t, dests, 1)
-				assert.Equal(t, "d1", dests[0].ID)
+				var result apirouter.DestinationPaginatedResult
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+				require.Len(t, result.Models, 1)
+				assert.Equal(t, "d1", result.Models[0].ID)
 			})
 
 			t.Run("topics filter", func(t *testing.T) {
@@ -457,10 +619,10 @@ func TestAPI_Destinations(t *testing.T) {
 
 				require.Equal(t, http.StatusOK, resp.Code)
 
-				var dests []destregistry.DestinationDisplay
-				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dests))
-				require.Len(t, dests, 1)
-				assert.Equal(t, "d1", dests[0].ID)
+				var result apirouter.DestinationPaginatedResult
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+				require.Len(t, result.Models, 1)
+				assert.Equal(t, "d1", result.Models[0].ID)
 			})
 		})
 	})
@@ -549,6 +711,66 @@ func TestAPI_Destinations(t *testing.T) {
 			assert.Equal(t, "https://new.example.com", dest.Config["url"])
 		})
 
+		t.Run("target change is recorded", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+			h.tenantStore.CreateDestination(t.Context(), df.Any(
+				df.WithID("d1"), df.WithTenantID("t1"),
+				df.WithConfig(map[string]string{"url": "https://old.example.com"}),
+			))
+
+			req := h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+				"config": map[string]string{"url": "https://new.example.com"},
+			})
+			resp := h.do(h.withAPIKey(req))
+			require.Equal(t, http.StatusOK, resp.Code)
+
+			dest, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+			require.NoError(t, err)
+			require.NotNil(t, dest.TargetChange)
+			assert.Equal(t, models.QueuedMessagesNewTarget, dest.TargetChange.QueuedMessages)
+			assert.True(t, dest.UpdatedAt.Equal(dest.TargetChange.ChangedAt))
+			changedAt := dest.TargetChange.ChangedAt
+
+			// Choosing for the queued messages without a new target keeps the
+			// time of the change.
+			req = h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+				"queued_messages": models.QueuedMessagesHold,
+			})
+			resp = h.do(h.withAPIKey(req))
+			require.Equal(t, http.StatusOK, resp.Code)
+
+			dest, err = h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+			require.NoError(t, err)
+			assert.Equal(t, models.QueuedMessagesHold, dest.TargetChange.QueuedMessages)
+			assert.True(t, changedAt.Equal(dest.TargetChange.ChangedAt))
+
+			// The choice stands for the next change of target.
+			req = h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+				"config": map[string]string{"url": "https://newer.example.com"},
+			})
+			resp = h.do(h.withAPIKey(req))
+			require.Equal(t, http.StatusOK, resp.Code)
+
+			dest, err = h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+			require.NoError(t, err)
+			assert.Equal(t, models.QueuedMessagesHold, dest.TargetChange.QueuedMessages)
+			assert.True(t, dest.TargetChange.ChangedAt.After(changedAt))
+		})
+
+		t.Run("invalid queued_messages returns 422", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+			h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+
+			req := h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+				"queued_messages": "somewhere",
+			})
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+		})
+
 		t.Run("jwt updates destination on own tenant", func(t *testing.T) {
 			h := newAPITest(t)
 			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
@@ -625,6 +847,143 @@ func TestAPI_Destinations(t *testing.T) {
 
 		// ── Merge-patch semantics (RFC 7396) for map fields ──
 
+		t.Run("retry_policy is replaced and cleared via null", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+			destination := df.Any(df.WithID("d1"), df.WithTenantID("t1"))
+			destination.RetryPolicy = &models.RetryPolicy{MaxAttempts: 2}
+			h.tenantStore.CreateDestination(t.Context(), destination)
+
+			req := h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+				"retry_policy": map[string]any{"backoff": "constant", "interval_seconds": 30},
+			})
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			var dest destregistry.DestinationDisplay
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dest))
+			assert.Equal(t, &models.RetryPolicy{Backoff: "constant", IntervalSeconds: 30}, dest.RetryPolicy)
+
+			req = h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+				"retry_policy": nil,
+			})
+			resp = h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			stored, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+			require.NoError(t, err)
+			assert.Nil(t, stored.RetryPolicy)
+		})
+
+		t.Run("invalid retry_policy update returns 422", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+			h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+
+			req := h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+				"retry_policy": map[string]any{"max_attempts": 0, "backoff": "linear"},
+			})
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+		})
+
+		t.Run("transformation is replaced and cleared via null", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+			destination := df.Any(df.WithID("d1"), df.WithTenantID("t1"))
+			destination.Transformation = &models.Transformation{Type: "jq", Expression: "."}
+			h.tenantStore.CreateDestination(t.Context(), destination)
+
+			req := h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+				"transformation": map[string]any{"type": "jq", "expression": "{topic: $topic}"},
+			})
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			var dest destregistry.DestinationDisplay
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dest))
+			assert.Equal(t, &models.Transformation{Type: "jq", Expression: "{topic: $topic}"}, dest.Transformation)
+
+			req = h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+				"transformation": nil,
+			})
+			resp = h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			stored, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+			require.NoError(t, err)
+			assert.Nil(t, stored.Transformation)
+		})
+
+		t.Run("invalid transformation update returns 422", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+			h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+
+			req := h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+				"transformation": map[string]any{"type": "cel", "expression": "."},
+			})
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+		})
+
+		t.Run("max_payload_bytes is updated and cleared with 0", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+			h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+
+			req := h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+				"max_payload_bytes": 1048576,
+			})
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			stored, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+			require.NoError(t, err)
+			assert.Equal(t, 1048576, stored.MaxPayloadBytes)
+
+			req = h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+				"max_payload_bytes": 0,
+			})
+			resp = h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			stored, err = h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+			require.NoError(t, err)
+			assert.Zero(t, stored.MaxPayloadBytes)
+		})
+
+		t.Run("delivery caps are updated and cleared with 0", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+			h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+
+			req := h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+				"max_concurrency":           2,
+				"max_deliveries_per_second": 10,
+			})
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			stored, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+			require.NoError(t, err)
+			assert.Equal(t, 2, stored.MaxConcurrency)
+			assert.Equal(t, 10, stored.MaxDeliveriesPerSecond)
+
+			req = h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+				"max_concurrency": 0,
+			})
+			resp = h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			stored, err = h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+			require.NoError(t, err)
+			assert.Zero(t, stored.MaxConcurrency)
+			assert.Equal(t, 10, stored.MaxDeliveriesPerSecond)
+		})
+
 		t.Run("metadata merge adds key preserving existing", func(t *testing.T) {
 			h := newAPITest(t)
 			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
@@ -1348,6 +1707,143 @@ func TestAPI_Destinations(t *testing.T) {
 	})
 }
 
+func TestAPI_DestinationTopicLifecycle(t *testing.T) {
+	lifecycle := withTopicLifecycle(models.TopicLifecycle{
+		Deprecated: []string{"user.updated"},
+		Retired:    []string{"user.deleted"},
+	})
+
+	t.Run("create with retired topic returns 422", func(t *testing.T) {
+		h := newAPITest(t, lifecycle)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", map[string]any{
+			"type":   "webhook",
+			"topics": []string{"user.created", "user.deleted"},
+			"config": map[string]string{"url": "https://example.com/hook"},
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+		assert.Contains(t, resp.Body.String(), "topic user.deleted is retired")
+	})
+
+	t.Run("create with deprecated topic sets warning header", func(t *testing.T) {
+		h := newAPITest(t, lifecycle)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", map[string]any{
+			"type":   "webhook",
+			"topics": []string{"user.created", "user.updated"},
+			"config": map[string]string{"url": "https://example.com/hook"},
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusCreated, resp.Code)
+		assert.Equal(t, "user.updated", resp.Header().Get("X-Outpost-Deprecated-Topics"))
+	})
+
+	t.Run("update keeps existing retired subscription", func(t *testing.T) {
+		h := newAPITest(t, lifecycle)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(
+			df.WithID("d1"), df.WithTenantID("t1"), df.WithTopics([]string{"user.deleted"}),
+		))
+
+		req := h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+			"topics": []string{"user.deleted", "user.created"},
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("update adding retired topic returns 422", func(t *testing.T) {
+		h := newAPITest(t, lifecycle)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(
+			df.WithID("d1"), df.WithTenantID("t1"), df.WithTopics([]string{"user.created"}),
+		))
+
+		req := h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+			"topics": []string{"user.created", "user.deleted"},
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+}
+
+func TestAPI_DestinationQuota(t *testing.T) {
+	newQuotaTest := func(t *testing.T) *apiTest {
+		h := newAPITest(t,
+			withTenantStore(memtenantstore.New(memtenantstore.WithMaxDestinationsPerTenant(4))),
+			withDestinationQuota(4, 50),
+		)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		return h
+	}
+	quotaWarnings := func(h *apiTest) []opevents.TenantQuotaWarningData {
+		var warnings []opevents.TenantQuotaWarningData
+		for _, call := range h.subscriptionEmitter.calls {
+			if call.topic == opevents.TopicTenantQuotaWarning {
+				warnings = append(warnings, call.data.(opevents.TenantQuotaWarningData))
+			}
+		}
+		return warnings
+	}
+
+	t.Run("create below threshold sets advisory headers without warning", func(t *testing.T) {
+		h := newQuotaTest(t)
+
+		resp := h.do(h.withAPIKey(h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", validDestination())))
+
+		require.Equal(t, http.StatusCreated, resp.Code)
+		assert.Equal(t, "4", resp.Header().Get("X-Outpost-Quota-Destinations-Limit"))
+		assert.Equal(t, "3", resp.Header().Get("X-Outpost-Quota-Destinations-Remaining"))
+		assert.Empty(t, resp.Header().Get("X-Outpost-Quota-Warning"))
+		assert.Empty(t, quotaWarnings(h))
+	})
+
+	t.Run("create crossing threshold warns once", func(t *testing.T) {
+		h := newQuotaTest(t)
+
+		for range 3 {
+			resp := h.do(h.withAPIKey(h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", validDestination())))
+			require.Equal(t, http.StatusCreated, resp.Code)
+		}
+
+		warnings := quotaWarnings(h)
+		require.Len(t, warnings, 1)
+		assert.Equal(t, opevents.TenantQuotaWarningData{
+			TenantID:         "t1",
+			Quota:            "destinations",
+			Used:             2,
+			Limit:            4,
+			ThresholdPercent: 50,
+		}, warnings[0])
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations", nil)
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "1", resp.Header().Get("X-Outpost-Quota-Destinations-Remaining"))
+		assert.Equal(t, "destinations", resp.Header().Get("X-Outpost-Quota-Warning"))
+	})
+
+	t.Run("create past limit returns 429 with headers", func(t *testing.T) {
+		h := newQuotaTest(t)
+		for i := range 4 {
+			h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID(fmt.Sprintf("d%d", i)), df.WithTenantID("t1")))
+		}
+
+		resp := h.do(h.withAPIKey(h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", validDestination())))
+
+		require.Equal(t, http.StatusTooManyRequests, resp.Code)
+		assert.Equal(t, "0", resp.Header().Get("X-Outpost-Quota-Destinations-Remaining"))
+		assert.Equal(t, "destinations", resp.Header().Get("X-Outpost-Quota-Warning"))
+	})
+}
+
 func TestAPI_SubscriptionUpdated(t *testing.T) {
 	t.Run("create destination emits subscription update", func(t *testing.T) {
 		h := newAPITest(t)
@@ -1454,8 +1950,8 @@ func TestAPI_SubscriptionUpdated(t *testing.T) {
 }
 
 // TestAPI_DestinationTypes tests the /destination-types endpoints.
-// Note: response body is a passthrough from the registry stub (returns nil);
-// not validated here. 404 path not testable without enhancing the stub.
+// Note: response body is a passthrough from the registry stub, which only
+// knows the "webhook" type; metadata content is not validated here.
 func TestAPI_DestinationTypes(t *testing.T) {
 	t.Run("List", func(t *testing.T) {
 		t.Run("api key returns 200", func(t *testing.T) {
@@ -1485,6 +1981,37 @@ func TestAPI_DestinationTypes(t *testing.T) {
 
 			require.Equal(t, http.StatusUnauthorized, resp.Code)
 		})
+
+		t.Run("jwt lists only types enabled for tenant", func(t *testing.T) {
+			h := newAPITest(t)
+			tenant := tf.Any(tf.WithID("t1"))
+			tenant.DestinationTypes = []string{"aws_sqs"}
+			h.tenantStore.UpsertTenant(t.Context(), tenant)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/destination-types", nil)
+			resp := h.do(h.withJWT(req, "t1"))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			var types []metadata.ProviderMetadata
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &types))
+			assert.Empty(t, types)
+		})
+
+		t.Run("api key lists types enabled for tenant", func(t *testing.T) {
+			h := newAPITest(t)
+			tenant := tf.Any(tf.WithID("t1"))
+			tenant.DestinationTypes = []string{"webhook"}
+			h.tenantStore.UpsertTenant(t.Context(), tenant)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destination-types", nil)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			var types []metadata.ProviderMetadata
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &types))
+			require.Len(t, types, 1)
+			assert.Equal(t, "webhook", types[0].Type)
+		})
 	})
 
 	t.Run("Retrieve", func(t *testing.T) {
@@ -1494,8 +2021,6 @@ func TestAPI_DestinationTypes(t *testing.T) {
 			req := httptest.NewRequest(http.MethodGet, "/api/v1/destination-types/webhook", nil)
 			resp := h.do(h.withAPIKey(req))
 
-			// The stub returns (nil, nil) for RetrieveProviderMetadata,
-			// so the handler returns 200 with null body.
 			require.Equal(t, http.StatusOK, resp.Code)
 		})
 
@@ -1517,5 +2042,51 @@ func TestAPI_DestinationTypes(t *testing.T) {
 
 			require.Equal(t, http.StatusUnauthorized, resp.Code)
 		})
+
+		t.Run("jwt type not enabled for tenant returns 404", func(t *testing.T) {
+			h := newAPITest(t)
+			tenant := tf.Any(tf.WithID("t1"))
+			tenant.DestinationTypes = []string{"aws_sqs"}
+			h.tenantStore.UpsertTenant(t.Context(), tenant)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/destination-types/webhook", nil)
+			resp := h.do(h.withJWT(req, "t1"))
+
+			require.Equal(t, http.StatusNotFound, resp.Code)
+		})
+	})
+	t.Run("Schema", func(t *testing.T) {
+		t.Run("jwt returns json schema", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/destination-types/webhook/schema", nil)
+			resp := h.do(h.withJWT(req, "t1"))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			var schema metadata.JSONSchema
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &schema))
+			assert.Equal(t, metadata.JSONSchemaDialect, schema.Schema)
+			assert.Equal(t, "webhook", schema.Properties["type"].Const)
+			assert.Equal(t, []string{"url"}, schema.Properties["config"].Required)
+		})
+
+		t.Run("unknown type returns 404", func(t *testing.T) {
+			h := newAPITest(t)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/destination-types/unknown/schema", nil)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusNotFound, resp.Code)
+		})
+
+		t.Run("no auth returns 401", func(t *testing.T) {
+			h := newAPITest(t)
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/destination-types/webhook/schema", nil)
+			resp := h.do(req)
+
+			require.Equal(t, http.StatusUnauthorized, resp.Code)
+		})
 	})
 }
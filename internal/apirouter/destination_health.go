@@ -0,0 +1,67 @@
+package apirouter
+
+import (
+	"context"
+	"net/http"
+	"slices"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// destHealthStore reads the delivery health recorded per destination.
+type destHealthStore interface {
+	List(ctx context.Context, tenantID string, destinationIDs []string) (map[string]*models.DestinationHealth, error)
+}
+
+var healthStatuses = []string{
+	models.DestinationHealthHealthy,
+	models.DestinationHealthFailing,
+	models.DestinationHealthUnknown,
+}
+
+// parseHealthStatus parses the "status" query parameter of the destination
+// list. Returns an empty string when it's not provided.
+func parseHealthStatus(c *gin.Context) (string, *ErrorResponse) {
+	status := c.Query("status")
+	if status == "" || slices.Contains(healthStatuses, status) {
+		return status, nil
+	}
+	return "", &ErrorResponse{
+		Code:    http.StatusUnprocessableEntity,
+		Message: "validation error",
+		Data: map[string]string{
+			"query.status": "must be 'healthy', 'failing' or 'unknown'",
+		},
+	}
+}
+
+// attachHealth sets the health of the displayed destinations of a tenant. It
+// does nothing when delivery health isn't tracked.
+func (h *DestinationHandlers) attachHealth(ctx context.Context, tenantID string, displays []*destregistry.DestinationDisplay) error {
+	if h.health == nil || len(displays) == 0 {
+		return nil
+	}
+	ids := make([]string, len(displays))
+	for i, display := range displays {
+		ids[i] = display.ID
+	}
+	health, err := h.health.List(ctx, tenantID, ids)
+	if err != nil {
+		return err
+	}
+	for _, display := range displays {
+		display.Health = health[display.ID]
+	}
+	return nil
+}
+
+// healthStatus returns the health status of a displayed destination. Without
+// tracked health, every destination is unknown.
+func healthStatus(display *destregistry.DestinationDisplay) string {
+	if display.Health == nil {
+		return models.DestinationHealthUnknown
+	}
+	return display.Health.Status
+}
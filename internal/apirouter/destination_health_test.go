@@ -0,0 +1,117 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/desthealth"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_DestinationHealth(t *testing.T) {
+	// d1 is failing, d2 is healthy and d3 has no recorded attempt.
+	setup := func(t *testing.T) *apiTest {
+		t.Helper()
+		store := desthealth.NewStore(testutil.CreateTestRedisClient(t))
+		h := newAPITest(t, withDestinationHealth(store))
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		for _, id := range []string{"d1", "d2", "d3"} {
+			h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID(id), df.WithTenantID("t1")))
+		}
+		require.NoError(t, store.Record(t.Context(), "t1", "d1", &models.Attempt{
+			ID:           "atm_1",
+			Status:       models.AttemptStatusFailed,
+			ErrorCode:    destregistry.ErrorCodeHTTPStatus,
+			ErrorMessage: "The destination responded with HTTP status 500.",
+		}))
+		require.NoError(t, store.Record(t.Context(), "t1", "d2", &models.Attempt{
+			ID:     "atm_2",
+			Status: models.AttemptStatusSuccess,
+		}))
+		return h
+	}
+
+	list := func(t *testing.T, h *apiTest, query string) apirouter.DestinationPaginatedResult {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations"+query, nil)
+		resp := h.do(h.withJWT(req, "t1"))
+		require.Equal(t, http.StatusOK, resp.Code)
+		var result apirouter.DestinationPaginatedResult
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		return result
+	}
+
+	t.Run("retrieve returns the destination's health", func(t *testing.T) {
+		h := setup(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations/d1", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var dest destregistry.DestinationDisplay
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dest))
+		require.NotNil(t, dest.Health)
+		assert.Equal(t, models.DestinationHealthFailing, dest.Health.Status)
+		assert.Equal(t, 1, dest.Health.ConsecutiveFailures)
+		require.NotNil(t, dest.Health.LastError)
+		assert.Equal(t, "atm_1", dest.Health.LastError.AttemptID)
+		assert.Equal(t, "The destination responded with HTTP status 500.", dest.Health.LastError.Message)
+	})
+
+	t.Run("list returns the health of every destination", func(t *testing.T) {
+		h := setup(t)
+
+		result := list(t, h, "")
+
+		require.Len(t, result.Models, 3)
+		statuses := map[string]string{}
+		for _, dest := range result.Models {
+			require.NotNil(t, dest.Health)
+			statuses[dest.ID] = dest.Health.Status
+		}
+		assert.Equal(t, map[string]string{
+			"d1": models.DestinationHealthFailing,
+			"d2": models.DestinationHealthHealthy,
+			"d3": models.DestinationHealthUnknown,
+		}, statuses)
+	})
+
+	t.Run("list filters by status", func(t *testing.T) {
+		h := setup(t)
+
+		result := list(t, h, "?status=failing")
+
+		require.Len(t, result.Models, 1)
+		assert.Equal(t, "d1", result.Models[0].ID)
+		assert.Equal(t, 1, result.Count)
+	})
+
+	t.Run("invalid status returns 422", func(t *testing.T) {
+		h := setup(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations?status=broken", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	t.Run("without tracked health destinations are unknown", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+
+		result := list(t, h, "")
+		require.Len(t, result.Models, 1)
+		assert.Nil(t, result.Models[0].Health)
+
+		assert.Empty(t, list(t, h, "?status=failing").Models)
+		assert.Len(t, list(t, h, "?status=unknown").Models, 1)
+	})
+}
@@ -0,0 +1,491 @@
+package apirouter
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"go.uber.org/zap"
+)
+
+const (
+	// maxImportRows caps the number of destinations in a single import.
+	maxImportRows = 1000
+	// maxImportBytes caps the size of an import file.
+	maxImportBytes = 10 << 20
+)
+
+const (
+	DestinationImportStatusCreated = "created"
+	DestinationImportStatusValid   = "valid"
+	DestinationImportStatusFailed  = "failed"
+)
+
+var (
+	errImportFormat      = errors.New("import file must be JSON or CSV")
+	errImportEmpty       = errors.New("import file contains no destinations")
+	errImportTooManyRows = fmt.Errorf("import file cannot contain more than %d destinations", maxImportRows)
+	errImportDuplicateID = errors.New("id is duplicated in the import file")
+	errImportExists      = errors.New("a destination with this id already exists")
+)
+
+// Import creates destinations in bulk from a JSON or CSV file. Each row goes
+// through the same validation as Create and rows are imported independently:
+// an invalid row is reported and skipped without failing the others. With
+// dry_run=true every row is validated and nothing is created.
+func (h *DestinationHandlers) Import(c *gin.Context) {
+	dryRun, ok := mustDryRunFromQuery(c)
+	if !ok {
+		return
+	}
+	rows, err := readImportRows(c)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(err))
+		return
+	}
+	c.JSON(http.StatusOK, h.importRows(c, mustTenantFromContext(c), rows, dryRun))
+}
+
+// importRows validates and, unless dryRun is set, creates each row as a
+// destination of tenant.
+func (h *DestinationHandlers) importRows(c *gin.Context, tenant *models.Tenant, rows []importRow, dryRun bool) DestinationImportResponse {
+	ctx := c.Request.Context()
+	prev := h.snapshotTenant(tenant)
+
+	response := DestinationImportResponse{
+		DryRun:  dryRun,
+		Total:   len(rows),
+		Results: make([]DestinationImportResult, 0, len(rows)),
+	}
+	// accepted holds the IDs of earlier valid rows so duplicates within the
+	// file are caught and shadow destinations may reference them.
+	accepted := make(map[string]bool)
+	for i, row := range rows {
+		result := DestinationImportResult{Row: i + 1, ID: row.input.ID, Type: row.input.Type}
+		destination, err := row.destination(tenant.ID)
+		if err == nil {
+			err = h.prepareImportedDestination(c, tenant, &row.input, &destination, accepted)
+		}
+		if err == nil && !dryRun {
+			err = h.createImportedDestination(c, &destination)
+		}
+		if err != nil {
+			result.Status = DestinationImportStatusFailed
+			result.Errors = h.importErrorMessages(ctx, err)
+			response.Failed++
+		} else {
+			accepted[destination.ID] = true
+			result.Status = DestinationImportStatusValid
+			if !dryRun {
+				result.ID = destination.ID
+				result.Status = DestinationImportStatusCreated
+			}
+			response.Succeeded++
+		}
+		response.Results = append(response.Results, result)
+	}
+
+	if !dryRun && response.Succeeded > 0 {
+		h.emitSubscriptionUpdateIfChanged(ctx, tenant.ID, prev)
+	}
+	h.logger.Ctx(ctx).Audit("destinations imported",
+		zap.String("tenant_id", tenant.ID),
+		zap.Bool("dry_run", dryRun),
+		zap.Int("succeeded", response.Succeeded),
+		zap.Int("failed", response.Failed),
+	)
+	return response
+}
+
+// mustDryRunFromQuery parses the dry_run query parameter. It aborts the
+// request and returns false when the value is not a boolean.
+func mustDryRunFromQuery(c *gin.Context) (bool, bool) {
+	v := c.Query("dry_run")
+	if v == "" {
+		return false, true
+	}
+	dryRun, err := strconv.ParseBool(v)
+	if err != nil {
+		AbortWithValidationError(c, errors.New("dry_run must be a boolean"))
+		return false, false
+	}
+	return dryRun, true
+}
+
+// prepareImportedDestination runs the Create validations on an imported row
+// and preprocesses it, returning the first error instead of aborting.
+func (h *DestinationHandlers) prepareImportedDestination(c *gin.Context, tenant *models.Tenant, input *CreateDestinationRequest, destination *models.Destination, accepted map[string]bool) error {
+	ctx := c.Request.Context()
+	if err := binding.Validator.ValidateStruct(input); err != nil {
+		return err
+	}
+	if mustRoleFromContext(c) != RoleAdmin && (input.CreatedAt != nil || input.UpdatedAt != nil) {
+		return errors.New("created_at and updated_at can only be set with API key authentication")
+	}
+	now := time.Now()
+	if input.CreatedAt != nil && input.CreatedAt.After(now) {
+		return errors.New("created_at cannot be in the future")
+	}
+	if input.UpdatedAt != nil && input.UpdatedAt.After(now) {
+		return errors.New("updated_at cannot be in the future")
+	}
+	if input.DisabledAt != nil && input.DisabledAt.After(now) {
+		return errors.New("disabled_at cannot be in the future")
+	}
+	if accepted[destination.ID] {
+		return errImportDuplicateID
+	}
+	if !tenant.AllowsDestinationType(destination.Type) {
+		return errDestinationTypeNotEnabled(destination.Type)
+	}
+
+	if err := destination.Validate(h.topics.Topics(ctx), h.topicsAllowWildcards); err != nil {
+		return err
+	}
+	destination.Topics = destination.Topics.Normalize()
+	if messages := retiredTopicErrors(h.topicLifecycle.Lifecycle(ctx), destination.Topics, nil); len(messages) > 0 {
+		return ErrorResponse{Code: http.StatusUnprocessableEntity, Message: "validation error", Data: messages}
+	}
+	if err := h.registry.ValidateDestination(ctx, destination); err != nil {
+		return err
+	}
+	if !accepted[destination.ShadowDestinationID] {
+		if err := h.validateShadowDestination(ctx, destination); err != nil {
+			if errors.Is(err, errShadowDestinationSelf) || errors.Is(err, errShadowDestinationMissing) {
+				return err
+			}
+			return NewErrInternalServer(err)
+		}
+	}
+	if input.ID != "" {
+		existing, err := h.tenantStore.RetrieveDestination(ctx, destination.TenantID, destination.ID)
+		if err != nil && !errors.Is(err, tenantstore.ErrDestinationDeleted) {
+			return NewErrInternalServer(err)
+		}
+		if existing != nil {
+			return errImportExists
+		}
+	}
+	return h.registry.PreprocessDestination(destination, nil, &destregistry.PreprocessDestinationOpts{
+		Role: mustRoleFromContext(c),
+		Request: destregistry.PreprocessRequest{
+			Config:      destination.Config,
+			Credentials: destination.Credentials,
+		},
+	})
+}
+
+func (h *DestinationHandlers) createImportedDestination(c *gin.Context, destination *models.Destination) error {
+	ctx := c.Request.Context()
+	// Imports don't run the endpoint verification handshake, so destinations
+	// that require it are imported disabled and verified when enabled.
+	if destination.DisabledAt == nil {
+		verifier, err := destregistry.TenantEndpointVerifier(h.registry, mustTenantFromContext(c), destination)
+		if err != nil {
+			return NewErrInternalServer(err)
+		}
+		if verifier != nil {
+			now := time.Now()
+			destination.DisabledAt = &now
+		}
+	}
+	if err := h.tenantStore.CreateDestination(ctx, *destination); err != nil {
+		if strings.Contains(err.Error(), "validation failed") ||
+			errors.Is(err, tenantstore.ErrDuplicateDestination) ||
+			errors.Is(err, tenantstore.ErrMaxDestinationsPerTenantReached) {
+			return err
+		}
+		return NewErrInternalServer(err)
+	}
+	h.telemetry.DestinationCreated(ctx, destination.Type)
+	h.logger.Ctx(ctx).Audit("destination created",
+		zap.String("tenant_id", destination.TenantID),
+		zap.String("destination_id", destination.ID),
+		zap.String("destination_type", destination.Type),
+	)
+	h.auditTLSVerification(c, nil, destination)
+	h.recordVersion(c, nil, destination)
+	return nil
+}
+
+// importErrorMessages turns a row error into the messages reported for it.
+// Internal errors are logged and reported without detail.
+func (h *DestinationHandlers) importErrorMessages(ctx context.Context, err error) []string {
+	var response ErrorResponse
+	response.Parse(err)
+	if response.Code == http.StatusInternalServerError {
+		h.logger.Ctx(ctx).Error("failed to import destination", zap.Error(err))
+		return []string{response.Message}
+	}
+	if messages, ok := response.Data.([]string); ok && len(messages) > 0 {
+		return messages
+	}
+	return []string{response.Message}
+}
+
+// ===== Import file parsing =====
+
+// errImportRow wraps problems decoding a single row.
+var errImportRow = errors.New("invalid row")
+
+type importRow struct {
+	input CreateDestinationRequest
+	err   error
+}
+
+func (r *importRow) destination(tenantID string) (models.Destination, error) {
+	if r.err != nil {
+		return models.Destination{}, r.err
+	}
+	return r.input.ToDestination(tenantID), nil
+}
+
+// readImportRows reads the import file from the request body, or from the
+// "file" field of a multipart form, and decodes it according to its content
+// type or file extension.
+func readImportRows(c *gin.Context) ([]importRow, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+
+	var (
+		data        []byte
+		contentType = c.ContentType()
+		filename    string
+	)
+	if contentType == "multipart/form-data" {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return nil, fmt.Errorf("file is required: %w", err)
+		}
+		file, err := header.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		if data, err = io.ReadAll(file); err != nil {
+			return nil, err
+		}
+		contentType, _, _ = mime.ParseMediaType(header.Header.Get("Content-Type"))
+		filename = header.Filename
+	} else {
+		var err error
+		if data, err = io.ReadAll(c.Request.Body); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		rows []importRow
+		err  error
+	)
+	switch {
+	case contentType == "text/csv" || strings.EqualFold(filepath.Ext(filename), ".csv"):
+		rows, err = parseImportCSV(data)
+	case contentType == "application/json" || strings.EqualFold(filepath.Ext(filename), ".json"):
+		rows, err = parseImportJSON(data)
+	default:
+		return nil, errImportFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errImportEmpty
+	}
+	if len(rows) > maxImportRows {
+		return nil, errImportTooManyRows
+	}
+	return rows, nil
+}
+
+// parseImportJSON decodes an array of create destination requests. Rows are
+// decoded one at a time so a malformed row fails on its own.
+func parseImportJSON(data []byte) ([]importRow, error) {
+	var raws []json.RawMessage
+	if err := json.Unmarshal(data, &raws); err != nil {
+		return nil, fmt.Errorf("invalid JSON: import file must be an array of destinations: %w", err)
+	}
+	rows := make([]importRow, len(raws))
+	for i, raw := range raws {
+		if err := json.Unmarshal(raw, &rows[i].input); err != nil {
+			rows[i].err = fmt.Errorf("%w: %v", errImportRow, err)
+		}
+	}
+	return rows, nil
+}
+
+// parseImportCSV decodes a CSV file with a header row. Columns are named
+// after the create destination request fields; map fields are spread across
+// one column per key, e.g. config.url or metadata.team. topics is a comma
+// separated list and filter is a JSON object. Empty cells are ignored.
+func parseImportCSV(data []byte) ([]importRow, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, errImportEmpty
+		}
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	for i, column := range header {
+		header[i] = strings.TrimSpace(column)
+		if !isImportColumn(header[i]) {
+			return nil, fmt.Errorf("invalid CSV: unknown column %q", header[i])
+		}
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid CSV: %w", err)
+		}
+		var row importRow
+		for i, value := range record {
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+			if err := setImportColumn(&row.input, header[i], value); err != nil {
+				row.err = fmt.Errorf("%w: %s: %v", errImportRow, header[i], err)
+				break
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+var importMapColumns = []string{"config.", "credentials.", "metadata.", "delivery_metadata."}
+
+func isImportColumn(column string) bool {
+	switch column {
+	case "id", "type", "topics", "filter", "retry_policy", "transformation", "max_payload_bytes", "max_concurrency", "max_deliveries_per_second", "sandbox_safe", "shadow_destination_id", "created_at", "updated_at", "disabled_at":
+		return true
+	}
+	for _, prefix := range importMapColumns {
+		if strings.HasPrefix(column, prefix) && len(column) > len(prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func setImportColumn(input *CreateDestinationRequest, column, value string) error {
+	switch column {
+	case "id":
+		input.ID = value
+	case "type":
+		input.Type = value
+	case "topics":
+		for _, topic := range strings.Split(value, ",") {
+			if topic = strings.TrimSpace(topic); topic != "" {
+				input.Topics = append(input.Topics, topic)
+			}
+		}
+	case "filter":
+		return json.Unmarshal([]byte(value), &input.Filter)
+	case "retry_policy":
+		return json.Unmarshal([]byte(value), &input.RetryPolicy)
+	case "transformation":
+		return json.Unmarshal([]byte(value), &input.Transformation)
+	case "max_payload_bytes":
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		input.MaxPayloadBytes = parsed
+	case "max_concurrency":
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		input.MaxConcurrency = parsed
+	case "max_deliveries_per_second":
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		input.MaxDeliveriesPerSecond = parsed
+	case "sandbox_safe":
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			return errors.New("must be a boolean")
+		}
+		input.SandboxSafe = parsed
+	case "shadow_destination_id":
+		input.ShadowID = value
+	case "created_at", "updated_at", "disabled_at":
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return errors.New("must be an RFC 3339 timestamp")
+		}
+		switch column {
+		case "created_at":
+			input.CreatedAt = &parsed
+		case "updated_at":
+			input.UpdatedAt = &parsed
+		default:
+			input.DisabledAt = &parsed
+		}
+	default:
+		key := column[strings.Index(column, ".")+1:]
+		switch {
+		case strings.HasPrefix(column, "config."):
+			input.Config = setImportMapValue(input.Config, key, value)
+		case strings.HasPrefix(column, "credentials."):
+			input.Credentials = setImportMapValue(input.Credentials, key, value)
+		case strings.HasPrefix(column, "metadata."):
+			input.Metadata = setImportMapValue(input.Metadata, key, value)
+		case strings.HasPrefix(column, "delivery_metadata."):
+			input.DeliveryMetadata = setImportMapValue(input.DeliveryMetadata, key, value)
+		}
+	}
+	return nil
+}
+
+func setImportMapValue[M ~map[string]string](m M, key, value string) M {
+	if m == nil {
+		m = make(M)
+	}
+	m[key] = value
+	return m
+}
+
+// ===== Responses =====
+
+// DestinationImportResponse reports the outcome of each row of an import.
+type DestinationImportResponse struct {
+	DryRun    bool                      `json:"dry_run"`
+	Total     int                       `json:"total"`
+	Succeeded int                       `json:"succeeded"`
+	Failed    int                       `json:"failed"`
+	Results   []DestinationImportResult `json:"results"`
+}
+
+// DestinationImportResult is the outcome of a single row. Row is 1-based and
+// does not count the CSV header.
+type DestinationImportResult struct {
+	Row    int      `json:"row"`
+	ID     string   `json:"id,omitempty"`
+	Type   string   `json:"type,omitempty"`
+	Status string   `json:"status"`
+	Errors []string `json:"errors,omitempty"`
+}
@@ -0,0 +1,209 @@
+package apirouter_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_DestinationImport(t *testing.T) {
+	const path = "/api/v1/tenants/t1/destinations/import"
+
+	setup := func(t *testing.T) *apiTest {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		return h
+	}
+
+	csvReq := func(path, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "text/csv")
+		return req
+	}
+
+	decode := func(t *testing.T, resp *httptest.ResponseRecorder) apirouter.DestinationImportResponse {
+		t.Helper()
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var result apirouter.DestinationImportResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		return result
+	}
+
+	listDestinations := func(t *testing.T, h *apiTest) []models.Destination {
+		t.Helper()
+		dests, err := h.tenantStore.ListDestination(t.Context(), tenantstore.ListDestinationRequest{TenantID: "t1"})
+		require.NoError(t, err)
+		return dests
+	}
+
+	t.Run("imports JSON rows independently", func(t *testing.T) {
+		h := setup(t)
+
+		invalid := validDestination()
+		invalid["topics"] = []string{"unknown.topic"}
+		req := h.jsonReq(http.MethodPost, path, []any{
+			validDestination(),
+			invalid,
+			map[string]any{"topics": []string{"user.created"}},
+		})
+		result := decode(t, h.do(h.withAPIKey(req)))
+
+		assert.False(t, result.DryRun)
+		assert.Equal(t, 3, result.Total)
+		assert.Equal(t, 1, result.Succeeded)
+		assert.Equal(t, 2, result.Failed)
+		require.Len(t, result.Results, 3)
+		assert.Equal(t, apirouter.DestinationImportStatusCreated, result.Results[0].Status)
+		assert.NotEmpty(t, result.Results[0].ID)
+		assert.Equal(t, 2, result.Results[1].Row)
+		assert.Equal(t, apirouter.DestinationImportStatusFailed, result.Results[1].Status)
+		assert.NotEmpty(t, result.Results[1].Errors)
+		assert.Equal(t, []string{"type is required"}, result.Results[2].Errors)
+
+		dests := listDestinations(t, h)
+		require.Len(t, dests, 1)
+		assert.Equal(t, result.Results[0].ID, dests[0].ID)
+	})
+
+	t.Run("imports CSV with map columns", func(t *testing.T) {
+		h := setup(t)
+
+		body := "id,type,topics,config.url,metadata.team\n" +
+			`d1,webhook,"user.created,user.updated",https://example.com/a,billing` + "\n" +
+			"d2,webhook,*,https://example.com/b,\n"
+		result := decode(t, h.do(h.withAPIKey(csvReq(path, body))))
+
+		assert.Equal(t, 2, result.Succeeded)
+		assert.Equal(t, 0, result.Failed)
+
+		d1, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		assert.Equal(t, models.Topics{"user.created", "user.updated"}, d1.Topics)
+		assert.Equal(t, "https://example.com/a", d1.Config["url"])
+		assert.Equal(t, "billing", d1.Metadata["team"])
+
+		d2, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", "d2")
+		require.NoError(t, err)
+		assert.Equal(t, models.Topics{"*"}, d2.Topics)
+		assert.Empty(t, d2.Metadata)
+	})
+
+	t.Run("accepts a multipart file upload", func(t *testing.T) {
+		h := setup(t)
+
+		var body bytes.Buffer
+		w := multipart.NewWriter(&body)
+		part, err := w.CreateFormFile("file", "destinations.csv")
+		require.NoError(t, err)
+		part.Write([]byte("type,topics,config.url\nwebhook,user.created,https://example.com\n"))
+		require.NoError(t, w.Close())
+
+		req := httptest.NewRequest(http.MethodPost, path, &body)
+		req.Header.Set("Content-Type", w.FormDataContentType())
+		result := decode(t, h.do(h.withJWT(req, "t1")))
+
+		assert.Equal(t, 1, result.Succeeded)
+		assert.Len(t, listDestinations(t, h), 1)
+	})
+
+	t.Run("dry run validates without creating", func(t *testing.T) {
+		h := setup(t)
+
+		body := "id,type,topics,config.url\n" +
+			"d1,webhook,user.created,https://example.com\n" +
+			"d1,webhook,user.created,https://example.com\n"
+		result := decode(t, h.do(h.withAPIKey(csvReq(path+"?dry_run=true", body))))
+
+		assert.True(t, result.DryRun)
+		assert.Equal(t, 1, result.Succeeded)
+		assert.Equal(t, apirouter.DestinationImportStatusValid, result.Results[0].Status)
+		assert.Equal(t, []string{"id is duplicated in the import file"}, result.Results[1].Errors)
+		assert.Empty(t, listDestinations(t, h))
+	})
+
+	t.Run("rejects ids that already exist", func(t *testing.T) {
+		h := setup(t)
+		h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+
+		body := "id,type,topics,config.url\nd1,webhook,user.created,https://example.com\n"
+		result := decode(t, h.do(h.withAPIKey(csvReq(path+"?dry_run=true", body))))
+
+		assert.Equal(t, 1, result.Failed)
+		assert.Equal(t, []string{"a destination with this id already exists"}, result.Results[0].Errors)
+	})
+
+	t.Run("shadow destination may reference an earlier row", func(t *testing.T) {
+		h := setup(t)
+
+		body := "id,type,topics,config.url,shadow_destination_id\n" +
+			"primary,webhook,user.created,https://example.com/a,\n" +
+			"shadow,webhook,user.created,https://example.com/b,primary\n" +
+			"orphan,webhook,user.created,https://example.com/c,missing\n"
+		result := decode(t, h.do(h.withAPIKey(csvReq(path, body))))
+
+		assert.Equal(t, 2, result.Succeeded)
+		assert.Equal(t, apirouter.DestinationImportStatusCreated, result.Results[1].Status)
+		assert.Equal(t, apirouter.DestinationImportStatusFailed, result.Results[2].Status)
+	})
+
+	t.Run("malformed row fails on its own", func(t *testing.T) {
+		h := setup(t)
+
+		body := "type,topics,config.url,sandbox_safe\n" +
+			"webhook,user.created,https://example.com,maybe\n" +
+			"webhook,user.created,https://example.com,true\n"
+		result := decode(t, h.do(h.withAPIKey(csvReq(path, body))))
+
+		assert.Equal(t, 1, result.Succeeded)
+		assert.Equal(t, []string{"invalid row: sandbox_safe: must be a boolean"}, result.Results[0].Errors)
+	})
+
+	t.Run("jwt cannot import timestamps", func(t *testing.T) {
+		h := setup(t)
+
+		body := "type,topics,config.url,created_at\nwebhook,user.created,https://example.com,2024-01-01T00:00:00Z\n"
+		result := decode(t, h.do(h.withJWT(csvReq(path, body), "t1")))
+
+		assert.Equal(t, 1, result.Failed)
+		assert.Empty(t, listDestinations(t, h))
+	})
+
+	t.Run("invalid files return 400", func(t *testing.T) {
+		h := setup(t)
+
+		tests := map[string]*http.Request{
+			"unknown column":      csvReq(path, "type,topics,color\nwebhook,user.created,red\n"),
+			"header only":         csvReq(path, "type,topics\n"),
+			"JSON object":         h.jsonReq(http.MethodPost, path, validDestination()),
+			"unsupported content": httptest.NewRequest(http.MethodPost, path, strings.NewReader("<xml/>")),
+		}
+		for name, req := range tests {
+			t.Run(name, func(t *testing.T) {
+				assert.Equal(t, http.StatusBadRequest, h.do(h.withAPIKey(req)).Code)
+			})
+		}
+	})
+
+	t.Run("too many rows returns 400", func(t *testing.T) {
+		h := setup(t)
+
+		rows := make([]any, 1001)
+		for i := range rows {
+			rows[i] = validDestination()
+		}
+		resp := h.do(h.withAPIKey(h.jsonReq(http.MethodPost, path, rows)))
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+		assert.Empty(t, listDestinations(t, h))
+	})
+}
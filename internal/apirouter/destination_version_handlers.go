@@ -0,0 +1,394 @@
+package apirouter
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"go.uber.org/zap"
+)
+
+// maskedValue replaces credentials in versions, and values the provider does
+// not obfuscate itself.
+const maskedValue = "****"
+
+// DestinationVersion is a saved version of a destination, with secrets
+// obfuscated and credentials masked, and the changes it made to the version
+// before it.
+type DestinationVersion struct {
+	Version     int                              `json:"version"`
+	Actor       string                           `json:"actor,omitempty"`
+	CreatedAt   time.Time                        `json:"created_at"`
+	Destination *destregistry.DestinationDisplay `json:"destination"`
+	// Changes is nil when the previous version is no longer retained.
+	Changes []DestinationChange `json:"changes"`
+}
+
+// DestinationChange is a field changed by a version. From is nil for added
+// fields and To for removed ones.
+type DestinationChange struct {
+	Field string  `json:"field"`
+	From  *string `json:"from"`
+	To    *string `json:"to"`
+}
+
+// DestinationVersionPaginatedResult is the response for listing destination
+// versions. All retained versions are returned in a single page, so the count
+// is their total.
+type DestinationVersionPaginatedResult struct {
+	Models     []DestinationVersion `json:"models"`
+	Pagination SeekPagination       `json:"pagination"`
+	Count      int                  `json:"count"`
+}
+
+// ListVersions returns the retained versions of a destination, newest first.
+func (h *DestinationHandlers) ListVersions(c *gin.Context) {
+	versioner := h.mustVersioner(c)
+	if versioner == nil {
+		return
+	}
+	tenant := mustTenantFromContext(c)
+	destination := h.mustRetrieveDestination(c, tenant.ID, c.Param("destination_id"))
+	if destination == nil {
+		return
+	}
+
+	versions, err := versioner.ListDestinationVersion(c.Request.Context(), tenant.ID, destination.ID)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	result := make([]DestinationVersion, len(versions))
+	for i := range versions {
+		var previous *tenantstore.DestinationVersion
+		if i+1 < len(versions) {
+			previous = &versions[i+1]
+		}
+		version, err := h.toAPIDestinationVersion(&versions[i], previous)
+		if err != nil {
+			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+			return
+		}
+		result[i] = version
+	}
+
+	c.JSON(http.StatusOK, DestinationVersionPaginatedResult{
+		Models: result,
+		Pagination: SeekPagination{
+			OrderBy: "version",
+			Dir:     "desc",
+			Limit:   len(result),
+		},
+		Count: len(result),
+	})
+}
+
+// RetrieveVersion returns one version of a destination.
+func (h *DestinationHandlers) RetrieveVersion(c *gin.Context) {
+	versioner := h.mustVersioner(c)
+	if versioner == nil {
+		return
+	}
+	tenant := mustTenantFromContext(c)
+	destination := h.mustRetrieveDestination(c, tenant.ID, c.Param("destination_id"))
+	if destination == nil {
+		return
+	}
+	version := h.mustRetrieveVersion(c, versioner, destination)
+	if version == nil {
+		return
+	}
+
+	previous, err := versioner.RetrieveDestinationVersion(c.Request.Context(), tenant.ID, destination.ID, version.Version-1)
+	if err != nil && !errors.Is(err, tenantstore.ErrDestinationVersionNotFound) {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	result, err := h.toAPIDestinationVersion(version, previous)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// RollbackVersion restores the configuration, credentials, topics, filter and
+// metadata of a destination from one of its versions, recording the result
+// as a new version. Whether the destination is disabled, and any debug
+// recording, are left as they are. A change of target is recorded as on
+// update.
+func (h *DestinationHandlers) RollbackVersion(c *gin.Context) {
+	versioner := h.mustVersioner(c)
+	if versioner == nil {
+		return
+	}
+	tenant := mustTenantFromContext(c)
+	prev := h.snapshotTenant(tenant)
+	destination := h.mustRetrieveDestination(c, tenant.ID, c.Param("destination_id"))
+	if destination == nil {
+		return
+	}
+	version := h.mustRetrieveVersion(c, versioner, destination)
+	if version == nil {
+		return
+	}
+
+	restored := *destination
+	restored.Topics = version.Destination.Topics
+	restored.Filter = version.Destination.Filter
+	restored.Config = version.Destination.Config
+	restored.Credentials = version.Destination.Credentials
+	restored.DeliveryMetadata = version.Destination.DeliveryMetadata
+	restored.Metadata = version.Destination.Metadata
+	restored.RetryPolicy = version.Destination.RetryPolicy
+	restored.Transformation = version.Destination.Transformation
+	restored.MaxPayloadBytes = version.Destination.MaxPayloadBytes
+	restored.MaxConcurrency = version.Destination.MaxConcurrency
+	restored.MaxDeliveriesPerSecond = version.Destination.MaxDeliveriesPerSecond
+	restored.SandboxSafe = version.Destination.SandboxSafe
+	restored.ShadowDestinationID = version.Destination.ShadowDestinationID
+
+	// Only an admin may name one of the deployment's client certificates, so
+	// a tenant can't roll back to a name an admin has since removed.
+	if err := destwebhook.CheckClientCertificateName(&restored, destination, &destregistry.PreprocessDestinationOpts{
+		Role: mustRoleFromContext(c),
+	}); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+
+	// The version was valid when saved, but topics and the shadow destination
+	// may have been retired or deleted since.
+	if err := restored.Validate(h.topics.Topics(c.Request.Context()), h.topicsAllowWildcards); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	if !h.mustCheckTopicLifecycle(c, restored.Topics, destination.Topics) {
+		return
+	}
+	if !h.mustValidateShadowDestination(c, &restored) {
+		return
+	}
+	if err := h.registry.ValidateDestination(c.Request.Context(), &restored); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+
+	now := time.Now()
+	destregistry.RecordTargetChange(h.registry, destination, &restored, "", now)
+	restored.UpdatedAt = now
+	if err := h.tenantStore.UpsertDestination(c.Request.Context(), restored); err != nil {
+		h.handleUpsertDestinationError(c, err)
+		return
+	}
+	h.emitSubscriptionUpdateIfChanged(c.Request.Context(), tenant.ID, prev)
+	h.logger.Ctx(c.Request.Context()).Audit("destination rolled back",
+		zap.String("tenant_id", tenant.ID),
+		zap.String("destination_id", restored.ID),
+		zap.String("destination_type", restored.Type),
+		zap.Int("version", version.Version),
+	)
+	h.auditTLSVerification(c, destination, &restored)
+	h.recordVersion(c, destination, &restored)
+
+	display, err := h.displayer.Display(&restored)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	c.JSON(http.StatusOK, display)
+}
+
+// recordVersion saves destination as a new version when the store keeps
+// versions. The change itself has already been applied, so failures are
+// logged rather than returned.
+func (h *DestinationHandlers) recordVersion(c *gin.Context, previous, destination *models.Destination) {
+	if err := tenantstore.RecordDestinationVersion(c.Request.Context(), h.tenantStore, previous, destination, mustRoleFromContext(c)); err != nil {
+		h.logVersionError(c.Request.Context(), destination, err)
+	}
+}
+
+func (h *DestinationHandlers) logVersionError(ctx context.Context, destination *models.Destination, err error) {
+	h.logger.Ctx(ctx).Error("failed to record destination version",
+		zap.Error(err),
+		zap.String("tenant_id", destination.TenantID),
+		zap.String("destination_id", destination.ID),
+	)
+}
+
+// mustVersioner returns the tenant store as a DestinationVersioner. It aborts
+// the request and returns nil when the store does not keep versions.
+func (h *DestinationHandlers) mustVersioner(c *gin.Context) tenantstore.DestinationVersioner {
+	versioner, ok := h.tenantStore.(tenantstore.DestinationVersioner)
+	if !ok {
+		AbortWithError(c, http.StatusNotImplemented, ErrorResponse{
+			Code:    http.StatusNotImplemented,
+			Message: "destination versioning is not supported by the tenant store",
+		})
+		return nil
+	}
+	return versioner
+}
+
+func (h *DestinationHandlers) mustRetrieveVersion(c *gin.Context, versioner tenantstore.DestinationVersioner, destination *models.Destination) *tenantstore.DestinationVersion {
+	number, err := strconv.Atoi(c.Param("version"))
+	if err != nil || number < 1 {
+		AbortWithError(c, http.StatusNotFound, NewErrNotFound("destination version"))
+		return nil
+	}
+	version, err := versioner.RetrieveDestinationVersion(c.Request.Context(), destination.TenantID, destination.ID, number)
+	if err != nil {
+		if errors.Is(err, tenantstore.ErrDestinationVersionNotFound) {
+			AbortWithError(c, http.StatusNotFound, NewErrNotFound("destination version"))
+			return nil
+		}
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return nil
+	}
+	return version
+}
+
+func (h *DestinationHandlers) toAPIDestinationVersion(version, previous *tenantstore.DestinationVersion) (DestinationVersion, error) {
+	display, err := h.versionDisplay(&version.Destination)
+	if err != nil {
+		return DestinationVersion{}, err
+	}
+	result := DestinationVersion{
+		Version:     version.Version,
+		Actor:       version.Actor,
+		CreatedAt:   version.CreatedAt,
+		Destination: display,
+	}
+	switch {
+	case previous != nil:
+		previousDisplay, err := h.versionDisplay(&previous.Destination)
+		if err != nil {
+			return DestinationVersion{}, err
+		}
+		result.Changes = diffDestinations(&previous.Destination, previousDisplay.Destination, &version.Destination, display.Destination)
+	case version.Version == 1:
+		result.Changes = diffDestinations(nil, nil, &version.Destination, display.Destination)
+	}
+	return result, nil
+}
+
+// versionDisplay displays a versioned destination with every credential
+// masked. Versions keep secrets that were rotated out, so unlike the current
+// destination they are never returned, whatever the secret retrieval policy.
+func (h *DestinationHandlers) versionDisplay(destination *models.Destination) (*destregistry.DestinationDisplay, error) {
+	display, err := h.displayer.Display(destination)
+	if err != nil {
+		return nil, err
+	}
+	masked := *display.Destination
+	masked.Credentials = make(map[string]string, len(display.Destination.Credentials))
+	for key := range display.Destination.Credentials {
+		masked.Credentials[key] = maskedValue
+	}
+	display.Destination = &masked
+	return display, nil
+}
+
+// diffDestinations lists the fields that differ between two destinations.
+// Fields are compared on their raw values, so rotated secrets show up even
+// when their obfuscated forms match, and reported with obfuscated values.
+func diffDestinations(from, fromDisplay, to, toDisplay *models.Destination) []DestinationChange {
+	fromFields, fromShown := destinationFields(from), destinationFields(fromDisplay)
+	toFields, toShown := destinationFields(to), destinationFields(toDisplay)
+
+	names := make([]string, 0, len(fromFields)+len(toFields))
+	for name := range fromFields {
+		names = append(names, name)
+	}
+	for name := range toFields {
+		if _, ok := fromFields[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	changes := []DestinationChange{}
+	for _, name := range names {
+		fromValue, inFrom := fromFields[name]
+		toValue, inTo := toFields[name]
+		if inFrom && inTo && fromValue == toValue {
+			continue
+		}
+		change := DestinationChange{Field: name}
+		if inFrom {
+			change.From = shownValue(fromShown, name)
+		}
+		if inTo {
+			change.To = shownValue(toShown, name)
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+func shownValue(shown map[string]string, name string) *string {
+	value, ok := shown[name]
+	if !ok {
+		value = maskedValue
+	}
+	return &value
+}
+
+// destinationFields flattens the versioned fields of a destination, keyed by
+// their path, e.g. "config.url".
+func destinationFields(destination *models.Destination) map[string]string {
+	fields := map[string]string{}
+	if destination == nil {
+		return fields
+	}
+	fields["type"] = destination.Type
+	fields["topics"] = strings.Join(destination.Topics, ",")
+	if len(destination.Filter) > 0 {
+		filter, _ := json.Marshal(destination.Filter)
+		fields["filter"] = string(filter)
+	}
+	for prefix, values := range map[string]map[string]string{
+		"config":            destination.Config,
+		"credentials":       destination.Credentials,
+		"delivery_metadata": destination.DeliveryMetadata,
+		"metadata":          destination.Metadata,
+	} {
+		for key, value := range values {
+			fields[prefix+"."+key] = value
+		}
+	}
+	if destination.RetryPolicy != nil {
+		retryPolicy, _ := json.Marshal(destination.RetryPolicy)
+		fields["retry_policy"] = string(retryPolicy)
+	}
+	if destination.Transformation != nil {
+		transformation, _ := json.Marshal(destination.Transformation)
+		fields["transformation"] = string(transformation)
+	}
+	if destination.MaxPayloadBytes > 0 {
+		fields["max_payload_bytes"] = strconv.Itoa(destination.MaxPayloadBytes)
+	}
+	if destination.MaxConcurrency > 0 {
+		fields["max_concurrency"] = strconv.Itoa(destination.MaxConcurrency)
+	}
+	if destination.MaxDeliveriesPerSecond > 0 {
+		fields["max_deliveries_per_second"] = strconv.Itoa(destination.MaxDeliveriesPerSecond)
+	}
+	if destination.SandboxSafe {
+		fields["sandbox_safe"] = "true"
+	}
+	if destination.ShadowDestinationID != "" {
+		fields["shadow_destination_id"] = destination.ShadowDestinationID
+	}
+	return fields
+}
@@ -0,0 +1,182 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_DestinationVersions(t *testing.T) {
+	// setup creates d1 outside the API, i.e. before versioning, and updates
+	// its url twice.
+	setup := func(t *testing.T) *apiTest {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(
+			df.WithID("d1"), df.WithTenantID("t1"),
+			df.WithConfig(map[string]string{"url": "https://example.com/v1"}),
+		))
+		for _, url := range []string{"https://example.com/v2", "https://example.com/v3"} {
+			req := h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+				"config": map[string]string{"url": url},
+			})
+			require.Equal(t, http.StatusOK, h.do(h.withAPIKey(req)).Code)
+		}
+		return h
+	}
+
+	t.Run("lists versions newest first with changes", func(t *testing.T) {
+		h := setup(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations/d1/versions", nil)
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var result apirouter.DestinationVersionPaginatedResult
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		require.Len(t, result.Models, 3)
+		assert.Equal(t, 3, result.Count)
+
+		latest := result.Models[0]
+		assert.Equal(t, 3, latest.Version)
+		assert.Equal(t, "admin", latest.Actor)
+		assert.Equal(t, "https://example.com/v3", latest.Destination.Config["url"])
+		require.Len(t, latest.Changes, 1)
+		assert.Equal(t, "config.url", latest.Changes[0].Field)
+		assert.Equal(t, "https://example.com/v2", *latest.Changes[0].From)
+		assert.Equal(t, "https://example.com/v3", *latest.Changes[0].To)
+
+		// The state before the first update is saved as the baseline.
+		baseline := result.Models[2]
+		assert.Equal(t, 1, baseline.Version)
+		assert.Empty(t, baseline.Actor)
+		assert.Equal(t, "https://example.com/v1", baseline.Destination.Config["url"])
+	})
+
+	t.Run("retrieves a version", func(t *testing.T) {
+		h := setup(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations/d1/versions/2", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var version apirouter.DestinationVersion
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &version))
+		assert.Equal(t, 2, version.Version)
+		assert.Equal(t, "https://example.com/v2", version.Destination.Config["url"])
+		require.Len(t, version.Changes, 1)
+		assert.Equal(t, "https://example.com/v1", *version.Changes[0].From)
+	})
+
+	t.Run("unknown version returns 404", func(t *testing.T) {
+		h := setup(t)
+
+		for _, version := range []string{"9", "latest"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations/d1/versions/"+version, nil)
+			resp := h.do(h.withAPIKey(req))
+			assert.Equal(t, http.StatusNotFound, resp.Code, version)
+			assert.Contains(t, resp.Body.String(), "destination version not found", version)
+		}
+	})
+
+	t.Run("rolls back to a version", func(t *testing.T) {
+		h := setup(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tenants/t1/destinations/d1/versions/1/rollback", nil)
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var dest destregistry.DestinationDisplay
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dest))
+		assert.Equal(t, "https://example.com/v1", dest.Config["url"])
+
+		stored, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/v1", stored.Config["url"])
+
+		// The rollback is recorded as a new version.
+		versions, err := h.tenantStore.(tenantstore.DestinationVersioner).ListDestinationVersion(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		require.Len(t, versions, 4)
+		assert.Equal(t, "tenant", versions[0].Actor)
+		assert.Equal(t, "https://example.com/v1", versions[0].Destination.Config["url"])
+	})
+
+	t.Run("masks credentials under the retrievable secret policy", func(t *testing.T) {
+		h := newAPITest(t, withDestRegistry(webhookStandardRegistry(t)))
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", map[string]any{
+			"id":     "d1",
+			"type":   "webhook",
+			"topics": []string{"user.created"},
+			"config": map[string]string{"url": "https://example.com/hook"},
+		})
+		require.Equal(t, http.StatusCreated, h.do(h.withAPIKey(req)).Code)
+		req = h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+			"credentials": map[string]any{"rotate_secret": true},
+		})
+		require.Equal(t, http.StatusOK, h.do(h.withAPIKey(req)).Code)
+
+		stored, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		secret, previousSecret := stored.Credentials["secret"], stored.Credentials["previous_secret"]
+		require.NotEmpty(t, secret)
+		require.NotEmpty(t, previousSecret)
+
+		// The current destination is still returned in full.
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations/d1", nil)
+		resp := h.do(h.withJWT(req, "t1"))
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Contains(t, resp.Body.String(), secret)
+
+		for _, path := range []string{"/versions", "/versions/1", "/versions/2"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations/d1"+path, nil)
+			resp := h.do(h.withJWT(req, "t1"))
+			require.Equal(t, http.StatusOK, resp.Code, path)
+			assert.NotContains(t, resp.Body.String(), secret, path)
+			assert.NotContains(t, resp.Body.String(), previousSecret, path)
+		}
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations/d1/versions/2", nil)
+		resp = h.do(h.withJWT(req, "t1"))
+		var version apirouter.DestinationVersion
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &version))
+		assert.Equal(t, "****", version.Destination.Credentials["secret"])
+		assert.Equal(t, "****", version.Destination.Credentials["previous_secret"])
+		// The rotation is still reported as a change.
+		var fields []string
+		for _, change := range version.Changes {
+			fields = append(fields, change.Field)
+		}
+		assert.Contains(t, fields, "credentials.secret")
+	})
+
+	t.Run("other tenant cannot list versions", func(t *testing.T) {
+		h := setup(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t2")))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations/d1/versions", nil)
+		resp := h.do(h.withJWT(req, "t2"))
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+
+	t.Run("store without versioning returns 501", func(t *testing.T) {
+		h := newAPITest(t, withTenantStore(struct{ tenantstore.TenantStore }{tenantstore.NewMemTenantStore()}))
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations/d1/versions", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		assert.Equal(t, http.StatusNotImplemented, resp.Code)
+	})
+}
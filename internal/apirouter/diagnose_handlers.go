@@ -0,0 +1,40 @@
+package apirouter
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/destregistry"
+)
+
+// Diagnose handles POST /tenants/:tenant_id/destinations/:destination_id/diagnose.
+// It checks the destination's host can be reached (DNS resolution, TCP
+// connect, TLS handshake and an OPTIONS probe) with the network settings
+// deliveries use, and reports each step. A failed step is part of the report,
+// not an error response.
+func (h *DestinationHandlers) Diagnose(c *gin.Context) {
+	tenant := mustTenantFromContext(c)
+	destination := h.mustRetrieveDestination(c, tenant.ID, c.Param("destination_id"))
+	if destination == nil {
+		return
+	}
+
+	provider, err := h.registry.ResolveProvider(destination)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	diagnoser, ok := provider.(destregistry.ConnectivityDiagnoser)
+	if !ok {
+		AbortWithValidationError(c, errors.New("destination type does not support connectivity diagnostics"))
+		return
+	}
+
+	report, err := diagnoser.Diagnose(c.Request.Context(), destination)
+	if err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,63 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_DiagnoseDestination(t *testing.T) {
+	t.Run("reports each step", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}))
+		defer server.Close()
+
+		h := newAPITest(t, withDestRegistry(webhookStandardRegistry(t)))
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(
+			df.WithID("d1"),
+			df.WithTenantID("t1"),
+			df.WithType("webhook"),
+			df.WithConfig(map[string]string{"url": server.URL + "/webhook"}),
+			df.WithCredentials(map[string]string{"secret": "whsec_dGVzdHNlY3JldDEyMzQ1Njc4OTBhYmNkZWY="}),
+		))
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations/d1/diagnose", nil)
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var report destregistry.ConnectivityReport
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &report))
+		assert.True(t, report.OK)
+		require.Len(t, report.Steps, 4)
+		assert.Equal(t, destregistry.DiagnosticStepHTTP, report.Steps[3].Name)
+		assert.EqualValues(t, http.StatusMethodNotAllowed, report.Steps[3].Details["status_code"])
+	})
+
+	t.Run("unknown destination returns 404", func(t *testing.T) {
+		h := newAPITest(t, withDestRegistry(webhookStandardRegistry(t)))
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations/missing/diagnose", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("unsupported destination type returns 422", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations/d1/diagnose", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+}
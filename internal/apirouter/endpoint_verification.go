@@ -0,0 +1,56 @@
+package apirouter
+
+import (
+	"crypto/rand"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+	"go.uber.org/zap"
+)
+
+// endpointVerificationHeader reports the outcome of the endpoint verification
+// handshake on the responses of requests that ran it: "verified" or "failed".
+const endpointVerificationHeader = "X-Outpost-Endpoint-Verification"
+
+// verifyEndpoint sends the destination's endpoint a challenge it must echo,
+// when the tenant requires endpoint verification and the destination type
+// supports it. A failed handshake returns an error wrapping
+// destregistry.ErrEndpointNotVerified; any other error means the handshake
+// could not run.
+func (h *DestinationHandlers) verifyEndpoint(c *gin.Context, tenant *models.Tenant, destination *models.Destination) error {
+	verifier, err := destregistry.TenantEndpointVerifier(h.registry, tenant, destination)
+	if err != nil || verifier == nil {
+		return err
+	}
+
+	if err := verifier.VerifyEndpoint(c.Request.Context(), destination, rand.Text()); err != nil {
+		c.Header(endpointVerificationHeader, "failed")
+		h.logger.Ctx(c.Request.Context()).Info("destination endpoint verification failed",
+			zap.Error(err),
+			zap.String("tenant_id", tenant.ID),
+			zap.String("destination_id", destination.ID),
+			zap.String("destination_type", destination.Type))
+		return err
+	}
+	c.Header(endpointVerificationHeader, "verified")
+	return nil
+}
+
+// mustVerifyEndpoint runs the endpoint verification handshake before a
+// destination is enabled. It aborts the request and returns false when the
+// handshake fails or could not run.
+func (h *DestinationHandlers) mustVerifyEndpoint(c *gin.Context, tenant *models.Tenant, destination *models.Destination) bool {
+	err := h.verifyEndpoint(c, tenant, destination)
+	if err == nil {
+		return true
+	}
+	if errors.Is(err, destregistry.ErrEndpointNotVerified) {
+		AbortWithValidationError(c, err)
+		return false
+	}
+	AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+	return false
+}
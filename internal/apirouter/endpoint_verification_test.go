@@ -0,0 +1,151 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_EndpointVerification(t *testing.T) {
+	// endpoint echoes the challenge while echo is set.
+	type endpoint struct {
+		url        string
+		echo       atomic.Bool
+		challenges atomic.Int32
+	}
+	newEndpoint := func(t *testing.T, echo bool) *endpoint {
+		t.Helper()
+		e := &endpoint{}
+		e.echo.Store(echo)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var req destwebhook.VerificationRequest
+			json.NewDecoder(r.Body).Decode(&req)
+			e.challenges.Add(1)
+			if e.echo.Load() {
+				w.Write([]byte(req.Challenge))
+			}
+		}))
+		t.Cleanup(server.Close)
+		e.url = server.URL + "/webhook"
+		return e
+	}
+
+	setup := func(t *testing.T, verify bool) *apiTest {
+		t.Helper()
+		h := newAPITest(t, withDestRegistry(webhookStandardRegistry(t)))
+		tenant := tf.Any(tf.WithID("t1"))
+		tenant.VerifyWebhookEndpoints = verify
+		h.tenantStore.UpsertTenant(t.Context(), tenant)
+		return h
+	}
+
+	create := func(t *testing.T, h *apiTest, url string) (*httptest.ResponseRecorder, destregistry.DestinationDisplay) {
+		t.Helper()
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", map[string]any{
+			"id":     "d1",
+			"type":   "webhook",
+			"topics": []string{"user.created"},
+			"config": map[string]string{"url": url},
+		})
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusCreated, resp.Code)
+		var dest destregistry.DestinationDisplay
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dest))
+		return resp, dest
+	}
+
+	t.Run("creates an enabled destination when the endpoint echoes the challenge", func(t *testing.T) {
+		h := setup(t, true)
+		e := newEndpoint(t, true)
+
+		resp, dest := create(t, h, e.url)
+
+		assert.Equal(t, "verified", resp.Header().Get("X-Outpost-Endpoint-Verification"))
+		assert.Nil(t, dest.DisabledAt)
+		assert.EqualValues(t, 1, e.challenges.Load())
+	})
+
+	t.Run("creates a disabled destination when the endpoint does not echo the challenge", func(t *testing.T) {
+		h := setup(t, true)
+		e := newEndpoint(t, false)
+
+		resp, dest := create(t, h, e.url)
+
+		assert.Equal(t, "failed", resp.Header().Get("X-Outpost-Endpoint-Verification"))
+		assert.NotNil(t, dest.DisabledAt)
+		stored, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		assert.NotNil(t, stored.DisabledAt)
+	})
+
+	t.Run("enable verifies the endpoint again", func(t *testing.T) {
+		h := setup(t, true)
+		e := newEndpoint(t, false)
+		create(t, h, e.url)
+
+		req := httptest.NewRequest(http.MethodPut, "/api/v1/tenants/t1/destinations/d1/enable", nil)
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+		stored, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		assert.NotNil(t, stored.DisabledAt, "a failed verification keeps the destination disabled")
+
+		e.echo.Store(true)
+		req = httptest.NewRequest(http.MethodPut, "/api/v1/tenants/t1/destinations/d1/enable", nil)
+		resp = h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "verified", resp.Header().Get("X-Outpost-Endpoint-Verification"))
+		stored, err = h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		assert.Nil(t, stored.DisabledAt)
+	})
+
+	t.Run("changing the url of an enabled destination verifies the new endpoint", func(t *testing.T) {
+		h := setup(t, true)
+		create(t, h, newEndpoint(t, true).url)
+		other := newEndpoint(t, false)
+
+		req := h.jsonReq(http.MethodPatch, "/api/v1/tenants/t1/destinations/d1", map[string]any{
+			"config": map[string]string{"url": other.url},
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+		assert.EqualValues(t, 1, other.challenges.Load())
+	})
+
+	t.Run("not required by the tenant", func(t *testing.T) {
+		h := setup(t, false)
+		e := newEndpoint(t, false)
+
+		resp, dest := create(t, h, e.url)
+
+		assert.Empty(t, resp.Header().Get("X-Outpost-Endpoint-Verification"))
+		assert.Nil(t, dest.DisabledAt)
+		assert.Zero(t, e.challenges.Load())
+	})
+
+	t.Run("destinations created disabled are not verified", func(t *testing.T) {
+		h := setup(t, true)
+		e := newEndpoint(t, false)
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", map[string]any{
+			"type":        "webhook",
+			"topics":      []string{"user.created"},
+			"config":      map[string]string{"url": e.url},
+			"disabled_at": time.Now().Add(-time.Minute),
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusCreated, resp.Code)
+		assert.Zero(t, e.challenges.Load())
+	})
+}
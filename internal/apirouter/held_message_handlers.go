@@ -0,0 +1,179 @@
+package apirouter
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/deliveryhold"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/models"
+	"go.uber.org/zap"
+)
+
+type heldMessageStore interface {
+	Hold(ctx context.Context, task models.DeliveryTask) error
+	List(ctx context.Context, tenantID, destinationID string) ([]deliveryhold.Held, error)
+	Take(ctx context.Context, tenantID, destinationID string, eventIDs []string) ([]deliveryhold.Held, error)
+}
+
+// HeldMessageHandlers manage the messages queued for a destination before its
+// target changed, held until the change is confirmed.
+type HeldMessageHandlers struct {
+	logger            *logging.Logger
+	destinations      *DestinationHandlers
+	held              heldMessageStore
+	deliveryPublisher deliveryPublisher
+}
+
+func NewHeldMessageHandlers(logger *logging.Logger, destinations *DestinationHandlers, held heldMessageStore, deliveryPublisher deliveryPublisher) *HeldMessageHandlers {
+	return &HeldMessageHandlers{
+		logger:            logger,
+		destinations:      destinations,
+		held:              held,
+		deliveryPublisher: deliveryPublisher,
+	}
+}
+
+// HeldMessage is a message held for a destination.
+type HeldMessage struct {
+	EventID  string    `json:"event_id"`
+	Topic    string    `json:"topic"`
+	Attempt  int       `json:"attempt"`
+	QueuedAt time.Time `json:"queued_at"`
+	HeldAt   time.Time `json:"held_at"`
+}
+
+// HeldMessagesRequest selects held messages by event ID. No event IDs selects
+// all of them.
+type HeldMessagesRequest struct {
+	EventIDs []string `json:"event_ids"`
+}
+
+// List handles GET /tenants/:tenant_id/destinations/:destination_id/held-messages.
+func (h *HeldMessageHandlers) List(c *gin.Context) {
+	if !h.mustBeEnabled(c) {
+		return
+	}
+	tenant := mustTenantFromContext(c)
+	destination := h.destinations.mustRetrieveDestination(c, tenant.ID, c.Param("destination_id"))
+	if destination == nil {
+		return
+	}
+
+	held, err := h.held.List(c.Request.Context(), tenant.ID, destination.ID)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	messages := make([]HeldMessage, len(held))
+	for i, m := range held {
+		messages[i] = HeldMessage{
+			EventID:  m.Task.Event.ID,
+			Topic:    m.Task.Event.Topic,
+			Attempt:  m.Task.Attempt,
+			QueuedAt: m.Task.QueuedAt,
+			HeldAt:   m.HeldAt,
+		}
+	}
+	c.JSON(http.StatusOK, messages)
+}
+
+// Release handles POST
+// /tenants/:tenant_id/destinations/:destination_id/held-messages/release. It
+// confirms the target change for the selected messages, which are queued
+// again for the destination's current target.
+func (h *HeldMessageHandlers) Release(c *gin.Context) {
+	if !h.mustBeEnabled(c) {
+		return
+	}
+	tenant, destinationID, taken := h.mustTake(c)
+	if taken == nil {
+		return
+	}
+
+	ctx := c.Request.Context()
+	now := time.Now()
+	for i, m := range taken {
+		task := m.Task
+		task.QueuedAt = now
+		if err := h.deliveryPublisher.Publish(ctx, task); err != nil {
+			// The rest stay held, to be released again.
+			for _, rest := range taken[i:] {
+				if err := h.held.Hold(context.WithoutCancel(ctx), rest.Task); err != nil {
+					h.logger.Ctx(ctx).Error("failed to hold message again",
+						zap.Error(err),
+						zap.String("tenant_id", tenant.ID),
+						zap.String("destination_id", destinationID),
+						zap.String("event_id", rest.Task.Event.ID))
+				}
+			}
+			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+			return
+		}
+	}
+
+	h.logger.Ctx(ctx).Audit("held messages released",
+		zap.String("tenant_id", tenant.ID),
+		zap.String("destination_id", destinationID),
+		zap.Int("count", len(taken)))
+	c.JSON(http.StatusOK, gin.H{"released": len(taken)})
+}
+
+// Discard handles POST
+// /tenants/:tenant_id/destinations/:destination_id/held-messages/discard. The
+// selected messages are dropped without being delivered.
+func (h *HeldMessageHandlers) Discard(c *gin.Context) {
+	if !h.mustBeEnabled(c) {
+		return
+	}
+	tenant, destinationID, taken := h.mustTake(c)
+	if taken == nil {
+		return
+	}
+
+	h.logger.Ctx(c.Request.Context()).Audit("held messages discarded",
+		zap.String("tenant_id", tenant.ID),
+		zap.String("destination_id", destinationID),
+		zap.Int("count", len(taken)))
+	c.JSON(http.StatusOK, gin.H{"discarded": len(taken)})
+}
+
+// mustTake takes the held messages selected by the request. It aborts the
+// request and returns nil messages on failure.
+func (h *HeldMessageHandlers) mustTake(c *gin.Context) (*models.Tenant, string, []deliveryhold.Held) {
+	var req HeldMessagesRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		AbortWithValidationError(c, err)
+		return nil, "", nil
+	}
+	tenant := mustTenantFromContext(c)
+	destination := h.destinations.mustRetrieveDestination(c, tenant.ID, c.Param("destination_id"))
+	if destination == nil {
+		return nil, "", nil
+	}
+
+	taken, err := h.held.Take(c.Request.Context(), tenant.ID, destination.ID, req.EventIDs)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return nil, "", nil
+	}
+	if taken == nil {
+		taken = []deliveryhold.Held{}
+	}
+	return tenant, destination.ID, taken
+}
+
+func (h *HeldMessageHandlers) mustBeEnabled(c *gin.Context) bool {
+	if h.held == nil {
+		AbortWithError(c, http.StatusNotImplemented, ErrorResponse{
+			Code:    http.StatusNotImplemented,
+			Message: "held messages are not enabled",
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,120 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/deliveryhold"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_HeldMessages(t *testing.T) {
+	setup := func(t *testing.T) (*apiTest, *deliveryhold.Store) {
+		t.Helper()
+		store := deliveryhold.New(testutil.CreateTestRedisClient(t))
+		h := newAPITest(t, withHeldMessages(store))
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+		for _, id := range []string{"e1", "e2"} {
+			event := testutil.EventFactory.Any(
+				testutil.EventFactory.WithID(id),
+				testutil.EventFactory.WithTenantID("t1"),
+			)
+			task := models.NewDeliveryTask(event, "d1")
+			task.QueuedAt = time.Now().Add(-time.Hour)
+			require.NoError(t, store.Hold(t.Context(), task))
+		}
+		return h, store
+	}
+
+	list := func(t *testing.T, h *apiTest) []apirouter.HeldMessage {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations/d1/held-messages", nil)
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code)
+		var messages []apirouter.HeldMessage
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &messages))
+		return messages
+	}
+
+	t.Run("lists held messages", func(t *testing.T) {
+		h, _ := setup(t)
+
+		messages := list(t, h)
+		require.Len(t, messages, 2)
+		assert.Equal(t, "e1", messages[0].EventID)
+		assert.Equal(t, "e2", messages[1].EventID)
+	})
+
+	t.Run("releases the given messages to the current target", func(t *testing.T) {
+		h, _ := setup(t)
+		before := time.Now()
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations/d1/held-messages/release", map[string]any{
+			"event_ids": []string{"e2"},
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"released":1}`, resp.Body.String())
+		require.Len(t, h.deliveryPub.calls, 1)
+		assert.Equal(t, "e2", h.deliveryPub.calls[0].Event.ID)
+		assert.False(t, h.deliveryPub.calls[0].QueuedAt.Before(before))
+
+		messages := list(t, h)
+		require.Len(t, messages, 1)
+		assert.Equal(t, "e1", messages[0].EventID)
+	})
+
+	t.Run("discards all messages", func(t *testing.T) {
+		h, _ := setup(t)
+
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tenants/t1/destinations/d1/held-messages/discard", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"discarded":2}`, resp.Body.String())
+		assert.Empty(t, h.deliveryPub.calls)
+		assert.Empty(t, list(t, h))
+	})
+
+	t.Run("messages stay held when release fails", func(t *testing.T) {
+		h, store := setup(t)
+		h.deliveryPub.err = assert.AnError
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations/d1/held-messages/release", map[string]any{})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusInternalServerError, resp.Code)
+		held, err := store.List(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		assert.Len(t, held, 2)
+	})
+
+	t.Run("unknown destination returns 404", func(t *testing.T) {
+		h, _ := setup(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations/nope/held-messages", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("disabled returns 501", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations/d1/held-messages", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusNotImplemented, resp.Code)
+	})
+}
@@ -0,0 +1,167 @@
+package apirouter
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/importer"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/telemetry"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"go.uber.org/zap"
+)
+
+const (
+	TenantImportStatusCreated = "created"
+	TenantImportStatusExists  = "exists"
+	TenantImportStatusValid   = "valid"
+	TenantImportStatusFailed  = "failed"
+)
+
+// ImportHandlers migrate tenants and destinations from other webhook
+// services.
+type ImportHandlers struct {
+	logger       *logging.Logger
+	telemetry    telemetry.Telemetry
+	tenantStore  tenantstore.TenantStore
+	destinations *DestinationHandlers
+}
+
+func NewImportHandlers(logger *logging.Logger, telemetry telemetry.Telemetry, tenantStore tenantstore.TenantStore, destinations *DestinationHandlers) *ImportHandlers {
+	return &ImportHandlers{
+		logger:       logger,
+		telemetry:    telemetry,
+		tenantStore:  tenantStore,
+		destinations: destinations,
+	}
+}
+
+// Import converts an export from the service named by the source query
+// parameter and creates its tenants and destinations. Tenants that already
+// exist are kept as they are and receive the new destinations. Destinations
+// are imported like rows of a destination import, so one invalid destination
+// does not fail the others.
+func (h *ImportHandlers) Import(c *gin.Context) {
+	dryRun, ok := mustDryRunFromQuery(c)
+	if !ok {
+		return
+	}
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxImportBytes)
+	data, err := io.ReadAll(c.Request.Body)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(err))
+		return
+	}
+	source := importer.Source(c.Query("source"))
+	result, err := importer.Parse(source, data)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(err))
+		return
+	}
+	count := 0
+	for _, tenant := range result.Tenants {
+		count += len(tenant.Destinations)
+	}
+	if count > maxImportRows {
+		AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(errImportTooManyRows))
+		return
+	}
+
+	response := TenantImportResponse{
+		DryRun:   dryRun,
+		Source:   string(source),
+		Tenants:  make([]TenantImportResult, 0, len(result.Tenants)),
+		Warnings: result.Warnings,
+	}
+	for _, input := range result.Tenants {
+		tenantResult := TenantImportResult{ID: input.ID}
+		tenant, status, err := h.importTenant(c, input, dryRun)
+		if err != nil {
+			h.logger.Ctx(c.Request.Context()).Error("failed to import tenant", zap.String("tenant_id", input.ID), zap.Error(err))
+			tenantResult.Status = TenantImportStatusFailed
+			tenantResult.Errors = []string{"internal server error"}
+			response.Tenants = append(response.Tenants, tenantResult)
+			continue
+		}
+		tenantResult.Status = status
+
+		rows := make([]importRow, len(input.Destinations))
+		for i, destination := range input.Destinations {
+			rows[i].input = toCreateDestinationRequest(destination)
+		}
+		destinations := h.destinations.importRows(c, tenant, rows, dryRun)
+		tenantResult.Destinations = &destinations
+		response.Tenants = append(response.Tenants, tenantResult)
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+// importTenant returns the existing tenant with the given ID or creates it.
+// In a dry run a missing tenant is returned without being created.
+func (h *ImportHandlers) importTenant(c *gin.Context, input importer.Tenant, dryRun bool) (*models.Tenant, string, error) {
+	ctx := c.Request.Context()
+	existing, err := h.tenantStore.RetrieveTenant(ctx, input.ID)
+	if err != nil && !errors.Is(err, tenantstore.ErrTenantDeleted) {
+		return nil, "", err
+	}
+	if existing != nil {
+		return existing, TenantImportStatusExists, nil
+	}
+
+	now := time.Now()
+	tenant := &models.Tenant{
+		ID:        input.ID,
+		Topics:    []string{},
+		Metadata:  input.Metadata,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if dryRun {
+		return tenant, TenantImportStatusValid, nil
+	}
+	if err := h.tenantStore.UpsertTenant(ctx, *tenant); err != nil {
+		return nil, "", err
+	}
+	h.telemetry.TenantCreated(ctx)
+	h.logger.Ctx(ctx).Audit("tenant created",
+		zap.String("tenant_id", tenant.ID),
+		zap.Bool("sandbox", tenant.Sandbox),
+	)
+	return tenant, TenantImportStatusCreated, nil
+}
+
+func toCreateDestinationRequest(d importer.Destination) CreateDestinationRequest {
+	return CreateDestinationRequest{
+		ID:          d.ID,
+		Type:        d.Type,
+		Topics:      d.Topics,
+		Filter:      d.Filter,
+		Config:      d.Config,
+		Credentials: d.Credentials,
+		Metadata:    d.Metadata,
+		DisabledAt:  d.DisabledAt,
+	}
+}
+
+// ===== Responses =====
+
+// TenantImportResponse reports the outcome of importing an export.
+type TenantImportResponse struct {
+	DryRun  bool                 `json:"dry_run"`
+	Source  string               `json:"source"`
+	Tenants []TenantImportResult `json:"tenants"`
+	// Warnings describe settings in the export that were not imported.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// TenantImportResult is the outcome for one tenant and its destinations.
+type TenantImportResult struct {
+	ID           string                     `json:"id"`
+	Status       string                     `json:"status"`
+	Errors       []string                   `json:"errors,omitempty"`
+	Destinations *DestinationImportResponse `json:"destinations,omitempty"`
+}
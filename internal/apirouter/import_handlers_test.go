@@ -0,0 +1,107 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const svixExport = `{
+	"applications": [
+		{
+			"uid": "acme",
+			"name": "Acme",
+			"endpoints": [
+				{"uid": "acme-orders", "url": "https://acme.example.com", "filterTypes": ["user.created"], "secret": "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw", "channels": ["eu"]},
+				{"uid": "acme-bad", "url": "https://acme.example.com", "filterTypes": ["unknown.topic"]}
+			]
+		},
+		{
+			"uid": "globex",
+			"endpoints": [{"uid": "globex-all", "url": "https://globex.example.com"}]
+		}
+	]
+}`
+
+func TestAPI_TenantImport(t *testing.T) {
+	importReq := func(query, body string) *http.Request {
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/tenants/import?"+query, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		return req
+	}
+
+	decode := func(t *testing.T, resp *httptest.ResponseRecorder) apirouter.TenantImportResponse {
+		t.Helper()
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var result apirouter.TenantImportResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		return result
+	}
+
+	t.Run("imports tenants and destinations", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("globex")))
+
+		result := decode(t, h.do(h.withAPIKey(importReq("source=svix", svixExport))))
+
+		assert.Equal(t, "svix", result.Source)
+		assert.Len(t, result.Warnings, 3)
+		require.Len(t, result.Tenants, 2)
+
+		acme := result.Tenants[0]
+		assert.Equal(t, "acme", acme.ID)
+		assert.Equal(t, apirouter.TenantImportStatusCreated, acme.Status)
+		require.NotNil(t, acme.Destinations)
+		assert.Equal(t, 1, acme.Destinations.Succeeded)
+		assert.Equal(t, 1, acme.Destinations.Failed)
+
+		globex := result.Tenants[1]
+		assert.Equal(t, apirouter.TenantImportStatusExists, globex.Status)
+		assert.Equal(t, 1, globex.Destinations.Succeeded)
+
+		tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "acme")
+		require.NoError(t, err)
+		assert.Equal(t, "Acme", tenant.Metadata["name"])
+
+		dest, err := h.tenantStore.RetrieveDestination(t.Context(), "acme", "acme-orders")
+		require.NoError(t, err)
+		assert.Equal(t, "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw", dest.Credentials["secret"])
+	})
+
+	t.Run("dry run creates nothing", func(t *testing.T) {
+		h := newAPITest(t)
+
+		result := decode(t, h.do(h.withAPIKey(importReq("source=svix&dry_run=true", svixExport))))
+
+		assert.True(t, result.DryRun)
+		assert.Equal(t, apirouter.TenantImportStatusValid, result.Tenants[0].Status)
+		assert.Equal(t, apirouter.DestinationImportStatusValid, result.Tenants[0].Destinations.Results[0].Status)
+
+		tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "acme")
+		require.NoError(t, err)
+		assert.Nil(t, tenant)
+	})
+
+	t.Run("unknown source returns 400", func(t *testing.T) {
+		h := newAPITest(t)
+
+		resp := h.do(h.withAPIKey(importReq("source=stripe", svixExport)))
+
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("jwt cannot import", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("acme")))
+
+		resp := h.do(h.withJWT(importReq("source=svix", svixExport), "acme"))
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
@@ -19,10 +19,20 @@ var (
 	ErrInvalidToken = errors.New("invalid token")
 )
 
+// Portal roles scope what a tenant token can read. Tokens without a role are
+// portal admins.
+const (
+	PortalRoleAdmin  = "admin"
+	PortalRoleViewer = "viewer"
+)
+
 // JWTClaims contains the custom claims for JWT tokens
 type JWTClaims struct {
 	TenantID     string
 	DeploymentID string
+	// PortalRole is PortalRoleAdmin or PortalRoleViewer. Viewers read event
+	// payloads redacted.
+	PortalRole string
 }
 
 func (_ jsonwebtoken) New(jwtSecret string, claims JWTClaims) (string, error) {
@@ -36,6 +46,9 @@ func (_ jsonwebtoken) New(jwtSecret string, claims JWTClaims) (string, error) {
 	if claims.DeploymentID != "" {
 		mapClaims["deployment_id"] = claims.DeploymentID
 	}
+	if claims.PortalRole != "" {
+		mapClaims["role"] = claims.PortalRole
+	}
 	token := jwt.NewWithClaims(signingMethod, mapClaims)
 	return token.SignedString([]byte(jwtSecret))
 }
@@ -67,9 +80,15 @@ func (_ jsonwebtoken) Extract(jwtSecret string, tokenString string) (JWTClaims,
 		deploymentID = did
 	}
 
+	var portalRole string
+	if role, ok := claims["role"].(string); ok {
+		portalRole = role
+	}
+
 	return JWTClaims{
 		TenantID:     tenantID,
 		DeploymentID: deploymentID,
+		PortalRole:   portalRole,
 	}, nil
 }
 
@@ -96,6 +96,20 @@ func TestJWT(t *testing.T) {
 		assert.Equal(t, "", claims.DeploymentID)
 	})
 
+	t.Run("should extract the portal role", func(t *testing.T) {
+		t.Parallel()
+		token, err := apirouter.JWT.New(jwtKey, apirouter.JWTClaims{
+			TenantID:   tenantID,
+			PortalRole: apirouter.PortalRoleViewer,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		claims, err := apirouter.JWT.Extract(jwtKey, token)
+		assert.Nil(t, err)
+		assert.Equal(t, apirouter.PortalRoleViewer, claims.PortalRole)
+	})
+
 	t.Run("should fail to extract claims from token with invalid issuer", func(t *testing.T) {
 		t.Parallel()
 		now := time.Now()
@@ -0,0 +1,127 @@
+package apirouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/legalhold"
+	"github.com/hookdeck/outpost/internal/logging"
+	"go.uber.org/zap"
+)
+
+type legalHoldStore interface {
+	Place(ctx context.Context, tenantID, eventID, reason string, expiresAt *time.Time) (*legalhold.Hold, error)
+	List(ctx context.Context, tenantID string) ([]legalhold.Hold, error)
+	Release(ctx context.Context, tenantID, holdID string) (*legalhold.Hold, error)
+}
+
+type LegalHoldHandlers struct {
+	logger *logging.Logger
+	holds  legalHoldStore
+}
+
+func NewLegalHoldHandlers(logger *logging.Logger, holds legalHoldStore) *LegalHoldHandlers {
+	return &LegalHoldHandlers{
+		logger: logger,
+		holds:  holds,
+	}
+}
+
+type PlaceLegalHoldRequest struct {
+	EventID   string     `json:"event_id"`
+	Reason    string     `json:"reason"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// Place handles POST /tenants/:tenant_id/legal-holds. It exempts the tenant's
+// delivery logs, or an event's when event_id is set, from retention pruning
+// until the hold is released or expires.
+func (h *LegalHoldHandlers) Place(c *gin.Context) {
+	if !h.mustBeEnabled(c) {
+		return
+	}
+	var req PlaceLegalHoldRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	tenant := mustTenantFromContext(c)
+
+	hold, err := h.holds.Place(c.Request.Context(), tenant.ID, req.EventID, req.Reason, req.ExpiresAt)
+	if err != nil {
+		if errors.Is(err, legalhold.ErrInvalidHold) {
+			AbortWithValidationError(c, err)
+			return
+		}
+		if errors.Is(err, legalhold.ErrHoldUnenforceable) {
+			AbortWithError(c, http.StatusNotImplemented, ErrorResponse{
+				Code:    http.StatusNotImplemented,
+				Message: err.Error(),
+			})
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+
+	h.logger.Ctx(c.Request.Context()).Audit("legal hold placed",
+		zap.String("hold_id", hold.ID),
+		zap.String("tenant_id", tenant.ID),
+		zap.String("event_id", hold.EventID),
+		zap.String("reason", hold.Reason))
+	c.JSON(http.StatusCreated, hold)
+}
+
+// List handles GET /tenants/:tenant_id/legal-holds.
+func (h *LegalHoldHandlers) List(c *gin.Context) {
+	if !h.mustBeEnabled(c) {
+		return
+	}
+	tenant := mustTenantFromContext(c)
+
+	holds, err := h.holds.List(c.Request.Context(), tenant.ID)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	c.JSON(http.StatusOK, holds)
+}
+
+// Release handles DELETE /tenants/:tenant_id/legal-holds/:hold_id. Released
+// logs past retention are pruned on the next run.
+func (h *LegalHoldHandlers) Release(c *gin.Context) {
+	if !h.mustBeEnabled(c) {
+		return
+	}
+	tenant := mustTenantFromContext(c)
+
+	hold, err := h.holds.Release(c.Request.Context(), tenant.ID, c.Param("hold_id"))
+	if err != nil {
+		if errors.Is(err, legalhold.ErrHoldNotFound) {
+			AbortWithError(c, http.StatusNotFound, NewErrNotFound("legal hold"))
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+
+	h.logger.Ctx(c.Request.Context()).Audit("legal hold released",
+		zap.String("hold_id", hold.ID),
+		zap.String("tenant_id", tenant.ID),
+		zap.String("event_id", hold.EventID))
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+func (h *LegalHoldHandlers) mustBeEnabled(c *gin.Context) bool {
+	if h.holds == nil {
+		AbortWithError(c, http.StatusNotImplemented, ErrorResponse{
+			Code:    http.StatusNotImplemented,
+			Message: "legal holds are not enabled",
+		})
+		return false
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/legalhold"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_LegalHolds(t *testing.T) {
+	setup := func(t *testing.T, opts ...apiTestOption) *apiTest {
+		t.Helper()
+		h := newAPITest(t, opts...)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t2")))
+		return h
+	}
+
+	place := func(t *testing.T, h *apiTest, tenantID string, body map[string]any) legalhold.Hold {
+		t.Helper()
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/"+tenantID+"/legal-holds", body)
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusCreated, resp.Code)
+		var hold legalhold.Hold
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &hold))
+		return hold
+	}
+
+	list := func(t *testing.T, h *apiTest, tenantID string) []legalhold.Hold {
+		t.Helper()
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/"+tenantID+"/legal-holds", nil)
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code)
+		var holds []legalhold.Hold
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &holds))
+		return holds
+	}
+
+	t.Run("places, lists and releases holds", func(t *testing.T) {
+		h := setup(t, withLegalHolds())
+
+		hold := place(t, h, "t1", map[string]any{"event_id": "e1", "reason": "case 42"})
+		assert.NotEmpty(t, hold.ID)
+		assert.Equal(t, "t1", hold.TenantID)
+		assert.Equal(t, "e1", hold.EventID)
+		assert.Equal(t, "case 42", hold.Reason)
+
+		holds := list(t, h, "t1")
+		require.Len(t, holds, 1)
+		assert.Equal(t, hold.ID, holds[0].ID)
+		assert.Empty(t, list(t, h, "t2"))
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/tenants/t1/legal-holds/"+hold.ID, nil)
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Empty(t, list(t, h, "t1"))
+	})
+
+	t.Run("release of another tenant's hold returns 404", func(t *testing.T) {
+		h := setup(t, withLegalHolds())
+		hold := place(t, h, "t1", map[string]any{})
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/tenants/t2/legal-holds/"+hold.ID, nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusNotFound, resp.Code)
+		assert.Len(t, list(t, h, "t1"), 1)
+	})
+
+	t.Run("past expiry returns 422", func(t *testing.T) {
+		h := setup(t, withLegalHolds())
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/legal-holds", map[string]any{
+			"expires_at": time.Now().Add(-time.Hour),
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	t.Run("log store that cannot enforce holds returns 501", func(t *testing.T) {
+		h := setup(t, withLegalHolds(legalhold.WithUnenforceable("ClickHouse deletes logs with table TTLs")))
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/legal-holds", map[string]any{})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusNotImplemented, resp.Code)
+		assert.Contains(t, resp.Body.String(), "ClickHouse deletes logs with table TTLs")
+		assert.Empty(t, list(t, h, "t1"))
+	})
+
+	t.Run("jwt returns 403", func(t *testing.T) {
+		h := setup(t, withLegalHolds())
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/legal-holds", map[string]any{})
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+
+	t.Run("not enabled returns 501", func(t *testing.T) {
+		h := setup(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/legal-holds", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusNotImplemented, resp.Code)
+	})
+}
@@ -0,0 +1,80 @@
+package apirouter
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/models"
+	"go.uber.org/zap"
+)
+
+// UpdateLifecycleCallbackRequest registers the callback that receives the
+// lifecycle notifications for a tenant's events.
+type UpdateLifecycleCallbackRequest struct {
+	URL string `json:"url" binding:"required"`
+}
+
+func (r *UpdateLifecycleCallbackRequest) toCallback() (*models.LifecycleCallback, error) {
+	u, err := url.Parse(r.URL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return nil, errors.New("url must be an absolute https URL")
+	}
+	return &models.LifecycleCallback{URL: r.URL}, nil
+}
+
+// RetrieveLifecycleCallback returns the tenant's lifecycle callback.
+func (h *TenantHandlers) RetrieveLifecycleCallback(c *gin.Context) {
+	tenant := mustTenantFromContext(c)
+	if tenant.LifecycleCallback == nil {
+		AbortWithError(c, http.StatusNotFound, NewErrNotFound("lifecycle callback"))
+		return
+	}
+	c.JSON(http.StatusOK, tenant.LifecycleCallback)
+}
+
+// UpdateLifecycleCallback replaces the tenant's lifecycle callback.
+func (h *TenantHandlers) UpdateLifecycleCallback(c *gin.Context) {
+	var input UpdateLifecycleCallbackRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	callback, err := input.toCallback()
+	if err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+
+	tenant := *mustTenantFromContext(c)
+	tenant.LifecycleCallback = callback
+	tenant.UpdatedAt = time.Now()
+	if err := h.tenantStore.UpsertTenant(c.Request.Context(), tenant); err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	h.logger.Ctx(c.Request.Context()).Audit("tenant lifecycle callback updated",
+		zap.String("tenant_id", tenant.ID),
+	)
+	c.JSON(http.StatusOK, callback)
+}
+
+// DeleteLifecycleCallback clears the tenant's lifecycle callback, so its
+// notifications go to the deployment's callback, if any.
+func (h *TenantHandlers) DeleteLifecycleCallback(c *gin.Context) {
+	tenant := *mustTenantFromContext(c)
+	if tenant.LifecycleCallback != nil {
+		tenant.LifecycleCallback = nil
+		tenant.UpdatedAt = time.Now()
+		if err := h.tenantStore.UpsertTenant(c.Request.Context(), tenant); err != nil {
+			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+			return
+		}
+		h.logger.Ctx(c.Request.Context()).Audit("tenant lifecycle callback cleared",
+			zap.String("tenant_id", tenant.ID),
+		)
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
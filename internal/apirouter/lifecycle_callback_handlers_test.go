@@ -0,0 +1,80 @@
+package apirouter_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_TenantLifecycleCallback(t *testing.T) {
+	t.Run("admin sets callback", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/lifecycle-callback", map[string]any{
+			"url": "https://producer.example.com/lifecycle",
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"url":"https://producer.example.com/lifecycle"}`, resp.Body.String())
+
+		tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+		require.NoError(t, err)
+		require.NotNil(t, tenant.LifecycleCallback)
+		assert.Equal(t, "https://producer.example.com/lifecycle", tenant.LifecycleCallback.URL)
+	})
+
+	t.Run("get without callback returns 404", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/lifecycle-callback", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("delete clears callback", func(t *testing.T) {
+		h := newAPITest(t)
+		existing := tf.Any(tf.WithID("t1"))
+		existing.LifecycleCallback = &models.LifecycleCallback{URL: "https://producer.example.com/lifecycle"}
+		h.tenantStore.UpsertTenant(t.Context(), existing)
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/tenants/t1/lifecycle-callback", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+		require.NoError(t, err)
+		assert.Nil(t, tenant.LifecycleCallback)
+	})
+
+	t.Run("plain http url returns 422", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/lifecycle-callback", map[string]any{
+			"url": "http://producer.example.com/lifecycle",
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	t.Run("jwt returns 403", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/lifecycle-callback", map[string]any{
+			"url": "https://producer.example.com/lifecycle",
+		})
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
@@ -12,14 +12,16 @@ import (
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/models"
 	"github.com/hookdeck/outpost/internal/tenantstore"
 )
 
 type LogHandlers struct {
-	logger      *logging.Logger
-	logStore    logstore.LogStore
-	tenantStore tenantstore.TenantStore
-	displayer   *destinationDisplayer
+	logger         *logging.Logger
+	logStore       logstore.LogStore
+	tenantStore    tenantstore.TenantStore
+	displayer      *destinationDisplayer
+	topicNamespace models.TopicNamespace
 }
 
 func NewLogHandlers(
@@ -27,12 +29,14 @@ func NewLogHandlers(
 	logStore logstore.LogStore,
 	tenantStore tenantstore.TenantStore,
 	displayer *destinationDisplayer,
+	topicNamespace models.TopicNamespace,
 ) *LogHandlers {
 	return &LogHandlers{
-		logger:      logger,
-		logStore:    logStore,
-		tenantStore: tenantStore,
-		displayer:   displayer,
+		logger:         logger,
+		logStore:       logStore,
+		tenantStore:    tenantStore,
+		displayer:      displayer,
+		topicNamespace: topicNamespace,
 	}
 }
 
@@ -57,6 +61,11 @@ type IncludeOptions struct {
 	EventData    bool
 	ResponseData bool
 	Destination  bool
+	// RawErrors keeps the provider's raw error in response_data. Only admin
+	// callers see it; tenants get the normalized error_code and error_message.
+	RawErrors bool
+	// RedactData masks event.data for viewer portal tokens.
+	RedactData bool
 }
 
 func parseIncludeOptions(c *gin.Context) IncludeOptions {
@@ -74,6 +83,8 @@ func parseIncludeOptions(c *gin.Context) IncludeOptions {
 			opts.Destination = true
 		}
 	}
+	opts.RawErrors = !isJWTCaller(c)
+	opts.RedactData = !canReadPayloads(c)
 	return opts
 }
 
@@ -86,15 +97,19 @@ type APIAttempt struct {
 	Status          string                 `json:"status"`
 	Time            time.Time              `json:"time"`
 	Code            string                 `json:"code,omitempty"`
+	ErrorCode       string                 `json:"error_code,omitempty"`
+	ErrorMessage    string                 `json:"error_message,omitempty"`
+	LatencyMs       *int64                 `json:"latency_ms,omitempty"`
 	ResponseData    map[string]interface{} `json:"response_data,omitempty"`
 	AttemptNumber   int                    `json:"attempt_number"`
 	Manual          bool                   `json:"manual"`
 	DestinationType string                 `json:"destination_type"`
 
-	EventID       string      `json:"event_id"`
-	DestinationID string      `json:"destination_id"`
-	Event         interface{} `json:"event,omitempty"`
-	Destination   interface{} `json:"destination,omitempty"`
+	EventID             string                      `json:"event_id"`
+	DestinationID       string                      `json:"destination_id"`
+	DestinationSnapshot *models.DestinationSnapshot `json:"destination_snapshot,omitempty"`
+	Event               interface{}                 `json:"event,omitempty"`
+	Destination         interface{}                 `json:"destination,omitempty"`
 }
 
 // APIEventSummary is the event object when expand=event (without data)
@@ -103,9 +118,11 @@ type APIEventSummary struct {
 	TenantID              string            `json:"tenant_id"`
 	MatchedDestinationIDs []string          `json:"matched_destination_ids,omitempty"`
 	Topic                 string            `json:"topic"`
+	Source                string            `json:"source,omitempty"`
 	Time                  time.Time         `json:"time"`
 	EligibleForRetry      bool              `json:"eligible_for_retry"`
 	Metadata              map[string]string `json:"metadata,omitempty"`
+	Checksum              string            `json:"checksum,omitempty"`
 }
 
 // APIEventFull is the event object when expand=event.data
@@ -114,10 +131,15 @@ type APIEventFull struct {
 	TenantID              string            `json:"tenant_id"`
 	MatchedDestinationIDs []string          `json:"matched_destination_ids,omitempty"`
 	Topic                 string            `json:"topic"`
+	Source                string            `json:"source,omitempty"`
 	Time                  time.Time         `json:"time"`
 	EligibleForRetry      bool              `json:"eligible_for_retry"`
 	Metadata              map[string]string `json:"metadata,omitempty"`
+	Checksum              string            `json:"checksum,omitempty"`
 	Data                  json.RawMessage   `json:"data,omitempty"`
+	// DataRedacted is set when data only keeps the payload's shape, for
+	// viewer portal tokens.
+	DataRedacted bool `json:"data_redacted,omitempty"`
 }
 
 // APIEvent is the API response for retrieving a single event
@@ -126,19 +148,28 @@ type APIEvent struct {
 	TenantID              string            `json:"tenant_id"`
 	MatchedDestinationIDs []string          `json:"matched_destination_ids"`
 	Topic                 string            `json:"topic"`
+	Source                string            `json:"source,omitempty"`
+	GroupID               string            `json:"group_id,omitempty"`
 	Time                  time.Time         `json:"time"`
 	EligibleForRetry      bool              `json:"eligible_for_retry"`
 	Metadata              map[string]string `json:"metadata,omitempty"`
+	Checksum              string            `json:"checksum,omitempty"`
 	Data                  json.RawMessage   `json:"data,omitempty"`
+	// DataRedacted is set when data only keeps the payload's shape, for
+	// viewer portal tokens.
+	DataRedacted bool `json:"data_redacted,omitempty"`
 }
 
 // AttemptPaginatedResult is the paginated response for listing attempts.
+// Lists paged with cursors over the log store aren't counted, so unlike the
+// lists whose count is their total, it has no count.
 type AttemptPaginatedResult struct {
 	Models     []APIAttempt   `json:"models"`
 	Pagination SeekPagination `json:"pagination"`
 }
 
-// EventPaginatedResult is the paginated response for listing events.
+// EventPaginatedResult is the paginated response for listing events. Like
+// AttemptPaginatedResult, it has no count.
 type EventPaginatedResult struct {
 	Models     []APIEvent     `json:"models"`
 	Pagination SeekPagination `json:"pagination"`
@@ -147,6 +178,73 @@ type EventPaginatedResult struct {
 // toAPIAttempt converts an AttemptRecord to APIAttempt with expand options.
 // destDisplay is optional; when non-nil and opts.Destination is true, the
 // destination field is populated.
+// withoutRawErrors returns responseData without the provider's raw error,
+// which can expose internal hostnames and addresses.
+func withoutRawErrors(responseData map[string]interface{}) map[string]interface{} {
+	if _, ok := responseData["error"]; !ok {
+		if _, ok := responseData["message"]; !ok {
+			return responseData
+		}
+	}
+	stripped := make(map[string]interface{}, len(responseData))
+	for k, v := range responseData {
+		if k == "error" || k == "message" {
+			continue
+		}
+		stripped[k] = v
+	}
+	if len(stripped) == 0 {
+		return nil
+	}
+	return stripped
+}
+
+// redactPayload keeps the shape of an event payload, its keys and array
+// lengths, and masks every value, so viewer portal tokens can preview events
+// without reading them.
+func redactPayload(data json.RawMessage) json.RawMessage {
+	if len(data) == 0 {
+		return data
+	}
+	var payload interface{}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return json.RawMessage(strconv.Quote(SensitiveFieldMask))
+	}
+	redacted, err := json.Marshal(maskValues(payload))
+	if err != nil {
+		return json.RawMessage(strconv.Quote(SensitiveFieldMask))
+	}
+	return redacted
+}
+
+func maskValues(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for k, value := range v {
+			v[k] = maskValues(value)
+		}
+		return v
+	case []interface{}:
+		for i, value := range v {
+			v[i] = maskValues(value)
+		}
+		return v
+	case nil:
+		return nil
+	default:
+		return SensitiveFieldMask
+	}
+}
+
+// eventData returns the event payload as the caller may read it, and whether
+// it was redacted.
+func eventData(data json.RawMessage, redact bool) (json.RawMessage, bool) {
+	if !redact || len(data) == 0 {
+		return data, false
+	}
+	return redactPayload(data), true
+}
+
 func toAPIAttempt(ar *logstore.AttemptRecord, opts IncludeOptions, destDisplay *destregistry.DestinationDisplay) APIAttempt {
 	api := APIAttempt{
 		ID:              ar.Attempt.ID,
@@ -154,28 +252,45 @@ func toAPIAttempt(ar *logstore.AttemptRecord, opts IncludeOptions, destDisplay *
 		Status:          ar.Attempt.Status,
 		Time:            ar.Attempt.Time,
 		Code:            ar.Attempt.Code,
+		ErrorCode:       ar.Attempt.ErrorCode,
+		ErrorMessage:    ar.Attempt.ErrorMessage,
+		LatencyMs:       ar.Attempt.LatencyMs,
 		AttemptNumber:   ar.Attempt.AttemptNumber,
 		Manual:          ar.Attempt.Manual,
 		DestinationType: ar.Attempt.DestinationType,
 		EventID:         ar.Attempt.EventID,
 		DestinationID:   ar.Attempt.DestinationID,
+
+		DestinationSnapshot: ar.Attempt.DestinationSnapshot,
+	}
+
+	// Attempts stored before errors were normalized are normalized on read.
+	if api.ErrorCode == "" {
+		api.ErrorCode, api.ErrorMessage = destregistry.NormalizeAttemptError(ar.Attempt, nil)
 	}
 
 	if opts.ResponseData {
 		api.ResponseData = ar.Attempt.ResponseData
+		if !opts.RawErrors {
+			api.ResponseData = withoutRawErrors(api.ResponseData)
+		}
 	}
 
 	if ar.Event != nil {
 		if opts.EventData {
+			data, redacted := eventData(ar.Event.Data, opts.RedactData)
 			api.Event = APIEventFull{
 				ID:                    ar.Event.ID,
 				TenantID:              ar.Event.TenantID,
 				MatchedDestinationIDs: ar.Event.MatchedDestinationIDs,
 				Topic:                 ar.Event.Topic,
+				Source:                ar.Event.Source,
 				Time:                  ar.Event.Time,
 				EligibleForRetry:      ar.Event.EligibleForRetry,
 				Metadata:              ar.Event.Metadata,
-				Data:                  ar.Event.Data,
+				Checksum:              ar.Event.Checksum,
+				Data:                  data,
+				DataRedacted:          redacted,
 			}
 		} else if opts.Event {
 			api.Event = APIEventSummary{
@@ -183,9 +298,11 @@ func toAPIAttempt(ar *logstore.AttemptRecord, opts IncludeOptions, destDisplay *
 				TenantID:              ar.Event.TenantID,
 				MatchedDestinationIDs: ar.Event.MatchedDestinationIDs,
 				Topic:                 ar.Event.Topic,
+				Source:                ar.Event.Source,
 				Time:                  ar.Event.Time,
 				EligibleForRetry:      ar.Event.EligibleForRetry,
 				Metadata:              ar.Event.Metadata,
+				Checksum:              ar.Event.Checksum,
 			}
 		}
 	}
@@ -198,7 +315,7 @@ func toAPIAttempt(ar *logstore.AttemptRecord, opts IncludeOptions, destDisplay *
 }
 
 // ListAttempts handles GET /attempts
-// Query params: tenant_id[], event_id[], destination_id[], status, topic[], time[gte], time[lte], time[gt], time[lt], limit, next, prev, include, order_by, dir
+// Query params: tenant_id[], event_id[], destination_id[], status, topic[], source[], time[gte], time[lte], time[gt], time[lt], limit, next, prev, include, order_by, dir
 func (h *LogHandlers) ListAttempts(c *gin.Context) {
 	// Authz: JWT users can only query their own tenant's attempts
 	tenantIDs, ok := resolveTenantIDsFilter(c)
@@ -268,7 +385,8 @@ func (h *LogHandlers) listAttemptsInternal(c *gin.Context, tenantIDs []string, d
 		DestinationIDs:   destinationIDs,
 		DestinationTypes: ParseArrayQueryParam(c, "destination_type"),
 		Status:           c.Query("status"),
-		Topics:           ParseArrayQueryParam(c, "topic"),
+		Topics:           h.topicNamespace.ApplyAll(ParseArrayQueryParam(c, "topic")),
+		Sources:          ParseArrayQueryParam(c, "source"),
 		TimeFilter: logstore.TimeFilter{
 			GTE: attemptTimeFilter.GTE,
 			LTE: attemptTimeFilter.LTE,
@@ -366,15 +484,20 @@ func (h *LogHandlers) RetrieveEvent(c *gin.Context) {
 		AbortWithError(c, http.StatusNotFound, NewErrNotFound("event"))
 		return
 	}
+	data, redacted := eventData(event.Data, !canReadPayloads(c))
 	c.JSON(http.StatusOK, APIEvent{
 		ID:                    event.ID,
 		TenantID:              event.TenantID,
 		MatchedDestinationIDs: event.MatchedDestinationIDs,
 		Topic:                 event.Topic,
+		Source:                event.Source,
+		GroupID:               event.GroupID,
 		Time:                  event.Time,
 		EligibleForRetry:      event.EligibleForRetry,
 		Metadata:              event.Metadata,
-		Data:                  event.Data,
+		Checksum:              event.Checksum,
+		Data:                  data,
+		DataRedacted:          redacted,
 	})
 }
 
@@ -399,14 +522,20 @@ func (h *LogHandlers) RetrieveAttempt(c *gin.Context) {
 		return
 	}
 
-	// Authz: when accessed via a destination-scoped route, verify the attempt
-	// belongs to the destination in the path.
+	// Authz: when accessed via a destination- or event-scoped route, verify
+	// the attempt belongs to the destination or event in the path.
 	if destinationID := c.Param("destination_id"); destinationID != "" {
 		if attemptRecord.Attempt.DestinationID != destinationID {
 			AbortWithError(c, http.StatusNotFound, NewErrNotFound("attempt"))
 			return
 		}
 	}
+	if eventID := c.Param("event_id"); eventID != "" {
+		if attemptRecord.Attempt.EventID != eventID {
+			AbortWithError(c, http.StatusNotFound, NewErrNotFound("attempt"))
+			return
+		}
+	}
 
 	includeOpts := parseIncludeOptions(c)
 
@@ -431,7 +560,7 @@ func (h *LogHandlers) RetrieveAttempt(c *gin.Context) {
 }
 
 // ListEvents handles GET /events
-// Query params: tenant_id[], id[], destination_id, topic[], time[gte], time[lte], time[gt], time[lt], limit, next, prev, order_by, dir
+// Query params: tenant_id[], id[], destination_id, topic[], source[], group_id, metadata[key], search, time[gte], time[lte], time[gt], time[lt], limit, next, prev, order_by, dir
 func (h *LogHandlers) ListEvents(c *gin.Context) {
 	// Authz: JWT users can only query their own tenant's events
 	tenantIDs, ok := resolveTenantIDsFilter(c)
@@ -482,11 +611,30 @@ func (h *LogHandlers) listEventsInternal(c *gin.Context, tenantIDs []string) {
 
 	destinationIDs := ParseArrayQueryParam(c, "destination_id")
 
+	metadata := c.QueryMap("metadata")
+	if len(metadata) == 0 {
+		metadata = nil
+	}
+	// Matching searches would reveal the data of events to viewer portal
+	// tokens, which read it redacted.
+	search := c.Query("search")
+	if search != "" && !canReadPayloads(c) {
+		AbortWithError(c, http.StatusForbidden, ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "viewer tokens can't search event data",
+		})
+		return
+	}
+
 	req := logstore.ListEventRequest{
 		TenantIDs:      tenantIDs,
 		EventIDs:       ParseArrayQueryParam(c, "id"),
 		DestinationIDs: destinationIDs,
-		Topics:         ParseArrayQueryParam(c, "topic"),
+		Topics:         h.topicNamespace.ApplyAll(ParseArrayQueryParam(c, "topic")),
+		Sources:        ParseArrayQueryParam(c, "source"),
+		GroupID:        c.Query("group_id"),
+		Metadata:       metadata,
+		Search:         search,
 		TimeFilter: logstore.TimeFilter{
 			GTE: eventTimeFilter.GTE,
 			LTE: eventTimeFilter.LTE,
@@ -505,21 +653,34 @@ func (h *LogHandlers) listEventsInternal(c *gin.Context, tenantIDs []string) {
 			AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(err))
 			return
 		}
+		if errors.Is(err, logstore.ErrSearchNotSupported) {
+			AbortWithError(c, http.StatusNotImplemented, ErrorResponse{
+				Code:    http.StatusNotImplemented,
+				Message: err.Error(),
+			})
+			return
+		}
 		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
 		return
 	}
 
+	redact := !canReadPayloads(c)
 	apiEvents := make([]APIEvent, len(response.Data))
 	for i, e := range response.Data {
+		data, redacted := eventData(e.Data, redact)
 		apiEvents[i] = APIEvent{
 			ID:                    e.ID,
 			TenantID:              e.TenantID,
 			MatchedDestinationIDs: e.MatchedDestinationIDs,
 			Topic:                 e.Topic,
+			Source:                e.Source,
+			GroupID:               e.GroupID,
 			Time:                  e.Time,
 			EligibleForRetry:      e.EligibleForRetry,
 			Metadata:              e.Metadata,
-			Data:                  e.Data,
+			Checksum:              e.Checksum,
+			Data:                  data,
+			DataRedacted:          redacted,
 		}
 	}
 
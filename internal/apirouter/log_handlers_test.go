@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/models"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -44,6 +45,7 @@ func TestAPI_Events(t *testing.T) {
 			var result apirouter.EventPaginatedResult
 			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
 			assert.Len(t, result.Models, 2)
+			assert.NotContains(t, resp.Body.String(), `"count"`, "event and attempt lists aren't counted")
 		})
 
 		t.Run("api key with tenant_id filter", func(t *testing.T) {
@@ -174,6 +176,35 @@ func TestAPI_Events(t *testing.T) {
 			assert.Equal(t, e1.ID, result.Models[0].ID)
 		})
 
+		t.Run("viewer jwt reads redacted data", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			e := ef.AnyPointer(ef.WithTenantID("t1"), ef.WithDataMap(map[string]any{"email": "jane@example.com"}))
+			require.NoError(t, h.logStore.InsertMany(t.Context(), []*models.LogEntry{
+				{Event: e, Attempt: attemptForEvent(e)},
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/events", nil)
+			resp := h.do(h.withViewerJWT(req, "t1"))
+			require.Equal(t, http.StatusOK, resp.Code)
+			var result apirouter.EventPaginatedResult
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+			require.Len(t, result.Models, 1)
+			assert.True(t, result.Models[0].DataRedacted)
+			assert.JSONEq(t, `{"email":"[REDACTED]"}`, string(result.Models[0].Data))
+
+			// Portal admins read the full payload
+			req = httptest.NewRequest(http.MethodGet, "/api/v1/events", nil)
+			resp = h.do(h.withJWT(req, "t1"))
+			require.Equal(t, http.StatusOK, resp.Code)
+			result = apirouter.EventPaginatedResult{}
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+			require.Len(t, result.Models, 1)
+			assert.False(t, result.Models[0].DataRedacted)
+			assert.JSONEq(t, `{"email":"jane@example.com"}`, string(result.Models[0].Data))
+		})
+
 		t.Run("jwt with matching tenant_id returns 200", func(t *testing.T) {
 			h := newAPITest(t)
 			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
@@ -465,6 +496,68 @@ func TestAPI_Events(t *testing.T) {
 			})
 		})
 
+		t.Run("Metadata and search", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			e1 := ef.AnyPointer(
+				ef.WithID("e1"), ef.WithTenantID("t1"),
+				ef.WithMetadata(map[string]string{"region": "eu", "plan": "pro"}),
+				ef.WithDataMap(map[string]any{"order_id": "12345", "customer": "Jane"}),
+			)
+			e2 := ef.AnyPointer(
+				ef.WithID("e2"), ef.WithTenantID("t1"),
+				ef.WithMetadata(map[string]string{"region": "us", "plan": "pro"}),
+				ef.WithDataMap(map[string]any{"order_id": "67890", "customer": "John"}),
+			)
+			require.NoError(t, h.logStore.InsertMany(t.Context(), []*models.LogEntry{
+				{Event: e1, Attempt: attemptForEvent(e1)},
+				{Event: e2, Attempt: attemptForEvent(e2)},
+			}))
+
+			list := func(t *testing.T, query string, req func(*http.Request) *http.Request) []string {
+				t.Helper()
+				resp := h.do(req(httptest.NewRequest(http.MethodGet, "/api/v1/events?"+query, nil)))
+				require.Equal(t, http.StatusOK, resp.Code)
+				var result apirouter.EventPaginatedResult
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+				ids := make([]string, len(result.Models))
+				for i, event := range result.Models {
+					ids[i] = event.ID
+				}
+				return ids
+			}
+
+			t.Run("metadata filter", func(t *testing.T) {
+				assert.ElementsMatch(t, []string{"e1", "e2"}, list(t, "metadata[plan]=pro", h.withAPIKey))
+				assert.Equal(t, []string{"e1"}, list(t, "metadata[plan]=pro&metadata[region]=eu", h.withAPIKey))
+				assert.Empty(t, list(t, "metadata[region]=ap", h.withAPIKey))
+			})
+
+			t.Run("search", func(t *testing.T) {
+				assert.Equal(t, []string{"e1"}, list(t, "search=12345", h.withAPIKey))
+				assert.Equal(t, []string{"e2"}, list(t, "search=john+67890", h.withAPIKey))
+				assert.Empty(t, list(t, "search=jane+67890", h.withAPIKey))
+			})
+
+			t.Run("jwt search", func(t *testing.T) {
+				assert.Equal(t, []string{"e1"}, list(t, "search=12345", func(req *http.Request) *http.Request {
+					return h.withJWT(req, "t1")
+				}))
+			})
+
+			t.Run("viewer jwt search returns 403", func(t *testing.T) {
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/events?search=12345", nil)
+				resp := h.do(h.withViewerJWT(req, "t1"))
+				require.Equal(t, http.StatusForbidden, resp.Code)
+
+				// Viewers still filter by metadata
+				assert.Equal(t, []string{"e1"}, list(t, "metadata[region]=eu", func(req *http.Request) *http.Request {
+					return h.withViewerJWT(req, "t1")
+				}))
+			})
+		})
+
 		t.Run("Validation", func(t *testing.T) {
 			t.Run("invalid dir returns 422", func(t *testing.T) {
 				h := newAPITest(t)
@@ -551,6 +644,34 @@ func TestAPI_Events(t *testing.T) {
 			assert.Equal(t, "e1", event.ID)
 		})
 
+		t.Run("viewer jwt reads redacted data", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			e := ef.AnyPointer(ef.WithID("e1"), ef.WithTenantID("t1"), ef.WithDataMap(map[string]any{
+				"email": "jane@example.com",
+				"items": []any{map[string]any{"sku": "abc", "qty": 2}},
+				"note":  nil,
+			}))
+			require.NoError(t, h.logStore.InsertMany(t.Context(), []*models.LogEntry{
+				{Event: e, Attempt: attemptForEvent(e)},
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/events/e1", nil)
+			resp := h.do(h.withViewerJWT(req, "t1"))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+
+			var event apirouter.APIEvent
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &event))
+			assert.True(t, event.DataRedacted)
+			assert.JSONEq(t, `{
+				"email": "[REDACTED]",
+				"items": [{"sku": "[REDACTED]", "qty": "[REDACTED]"}],
+				"note": null
+			}`, string(event.Data))
+		})
+
 		t.Run("jwt other tenant event returns 404", func(t *testing.T) {
 			h := newAPITest(t)
 			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
@@ -666,6 +787,7 @@ func TestAPI_Attempts(t *testing.T) {
 			var result apirouter.AttemptPaginatedResult
 			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
 			assert.Len(t, result.Models, 2)
+			assert.NotContains(t, resp.Body.String(), `"count"`, "event and attempt lists aren't counted")
 		})
 
 		t.Run("api key with tenant_id filter", func(t *testing.T) {
@@ -1239,6 +1361,32 @@ func TestAPI_Attempts(t *testing.T) {
 			assert.Equal(t, "val", dataMap["key"])
 		})
 
+		t.Run("include event.data redacts data for viewer jwt", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			e := ef.AnyPointer(
+				ef.WithID("e1"), ef.WithTenantID("t1"),
+				ef.WithDataMap(map[string]any{"key": "val"}),
+			)
+			a := attemptForEvent(e, af.WithID("a1"))
+			require.NoError(t, h.logStore.InsertMany(t.Context(), []*models.LogEntry{
+				{Event: e, Attempt: a},
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/attempts/a1?include=event.data", nil)
+			resp := h.do(h.withViewerJWT(req, "t1"))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+
+			var raw map[string]any
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &raw))
+			eventMap, ok := raw["event"].(map[string]any)
+			require.True(t, ok)
+			assert.Equal(t, map[string]any{"key": "[REDACTED]"}, eventMap["data"])
+			assert.Equal(t, true, eventMap["data_redacted"])
+		})
+
 		t.Run("include response data", func(t *testing.T) {
 			h := newAPITest(t)
 
@@ -1267,6 +1415,45 @@ func TestAPI_Attempts(t *testing.T) {
 			assert.Equal(t, "response-body", respData["body"])
 		})
 
+		t.Run("raw errors are admin only", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			e := ef.AnyPointer(ef.WithID("e1"), ef.WithTenantID("t1"))
+			a := attemptForEvent(e, af.WithID("a1"), func(att *models.Attempt) {
+				att.Status = models.AttemptStatusFailed
+				att.Code = "connection_refused"
+				att.ResponseData = map[string]interface{}{
+					"error":   "dial tcp 10.0.0.4:443: connect: connection refused",
+					"message": "connection refused",
+				}
+			})
+			require.NoError(t, h.logStore.InsertMany(t.Context(), []*models.LogEntry{
+				{Event: e, Attempt: a},
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/attempts/a1?include=response_data", nil)
+			resp := h.do(h.withJWT(req, "t1"))
+			require.Equal(t, http.StatusOK, resp.Code)
+
+			var tenantAttempt map[string]any
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &tenantAttempt))
+			assert.Equal(t, destregistry.ErrorCodeConnection, tenantAttempt["error_code"])
+			assert.NotEmpty(t, tenantAttempt["error_message"])
+			assert.NotContains(t, resp.Body.String(), "10.0.0.4")
+
+			req = httptest.NewRequest(http.MethodGet, "/api/v1/attempts/a1?include=response_data", nil)
+			resp = h.do(h.withAPIKey(req))
+			require.Equal(t, http.StatusOK, resp.Code)
+
+			var adminAttempt map[string]any
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &adminAttempt))
+			assert.Equal(t, destregistry.ErrorCodeConnection, adminAttempt["error_code"])
+			respData, ok := adminAttempt["response_data"].(map[string]any)
+			require.True(t, ok, "admins see the raw response_data")
+			assert.Equal(t, "dial tcp 10.0.0.4:443: connect: connection refused", respData["error"])
+		})
+
 		t.Run("include destination expands destination on retrieve", func(t *testing.T) {
 			h := newAPITest(t)
 
@@ -1691,6 +1878,76 @@ func TestAPI_Attempts(t *testing.T) {
 		})
 	})
 
+	t.Run("EventAttempts", func(t *testing.T) {
+		t.Run("Retrieve", func(t *testing.T) {
+			t.Run("returns the attempt with its response", func(t *testing.T) {
+				h := newAPITest(t)
+				h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+				e := ef.AnyPointer(ef.WithID("e1"), ef.WithTenantID("t1"))
+				a := attemptForEvent(e, af.WithID("a1"), func(att *models.Attempt) {
+					att.ResponseData = map[string]interface{}{
+						"status":  500,
+						"headers": map[string]string{"x-request-id": "req_123"},
+						"body":    "upstream unavailable",
+					}
+				})
+				require.NoError(t, h.logStore.InsertMany(t.Context(), []*models.LogEntry{
+					{Event: e, Attempt: a},
+				}))
+
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/events/e1/attempts/a1?include=response_data", nil)
+				resp := h.do(h.withJWT(req, "t1"))
+
+				require.Equal(t, http.StatusOK, resp.Code)
+
+				var raw map[string]any
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &raw))
+				assert.Equal(t, "a1", raw["id"])
+				assert.Equal(t, "e1", raw["event_id"])
+				respData, ok := raw["response_data"].(map[string]any)
+				require.True(t, ok, "response_data should be an object when include=response_data")
+				assert.Equal(t, float64(500), respData["status"])
+				assert.Equal(t, map[string]any{"x-request-id": "req_123"}, respData["headers"])
+				assert.Equal(t, "upstream unavailable", respData["body"])
+			})
+
+			t.Run("attempt belonging to different event returns 404", func(t *testing.T) {
+				h := newAPITest(t)
+				h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+				e := ef.AnyPointer(ef.WithID("e2"), ef.WithTenantID("t1"))
+				a := attemptForEvent(e, af.WithID("a1"))
+				require.NoError(t, h.logStore.InsertMany(t.Context(), []*models.LogEntry{
+					{Event: e, Attempt: a},
+				}))
+
+				// Request via e1's path, but attempt belongs to e2
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/events/e1/attempts/a1", nil)
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusNotFound, resp.Code)
+			})
+
+			t.Run("attempt belonging to other tenant returns 404", func(t *testing.T) {
+				h := newAPITest(t)
+				h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+				h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t2")))
+
+				e := ef.AnyPointer(ef.WithID("e1"), ef.WithTenantID("t2"))
+				a := attemptForEvent(e, af.WithID("a1"))
+				require.NoError(t, h.logStore.InsertMany(t.Context(), []*models.LogEntry{
+					{Event: e, Attempt: a},
+				}))
+
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/events/e1/attempts/a1", nil)
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusNotFound, resp.Code)
+			})
+		})
+	})
+
 	t.Run("no auth returns 401", func(t *testing.T) {
 		h := newAPITest(t)
 
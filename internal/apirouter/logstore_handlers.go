@@ -0,0 +1,91 @@
+package apirouter
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"go.uber.org/zap"
+)
+
+type LogStoreHandlers struct {
+	logger   *logging.Logger
+	logStore logstore.LogStore
+}
+
+func NewLogStoreHandlers(logger *logging.Logger, logStore logstore.LogStore) *LogStoreHandlers {
+	return &LogStoreHandlers{
+		logger:   logger,
+		logStore: logStore,
+	}
+}
+
+// LogStoreStats is the merge state of the log store. Stores that read their
+// writes immediately are not eventually consistent and report no tables.
+type LogStoreStats struct {
+	EventuallyConsistent bool                 `json:"eventually_consistent"`
+	Tables               []LogStoreTableStats `json:"tables"`
+}
+
+type LogStoreTableStats struct {
+	Table            string `json:"table"`
+	ActiveParts      uint64 `json:"active_parts"`
+	Rows             uint64 `json:"rows"`
+	BytesOnDisk      uint64 `json:"bytes_on_disk"`
+	MergesInProgress uint64 `json:"merges_in_progress"`
+	PendingMutations uint64 `json:"pending_mutations"`
+}
+
+// Stats returns the merge state of the log store.
+func (h *LogStoreHandlers) Stats(c *gin.Context) {
+	stats, err := h.stats(c)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	c.JSON(http.StatusOK, stats)
+}
+
+// Flush forces pending merges to complete, then returns the resulting merge
+// state. It is a no-op on stores that are not eventually consistent.
+func (h *LogStoreHandlers) Flush(c *gin.Context) {
+	if compactor, ok := h.logStore.(logstore.Compactor); ok {
+		start := time.Now()
+		if err := compactor.Flush(c.Request.Context()); err != nil {
+			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+			return
+		}
+		h.logger.Ctx(c.Request.Context()).Audit("log store flushed",
+			zap.Duration("duration", time.Since(start)),
+		)
+	}
+	h.Stats(c)
+}
+
+func (h *LogStoreHandlers) stats(c *gin.Context) (*LogStoreStats, error) {
+	compactor, ok := h.logStore.(logstore.Compactor)
+	if !ok {
+		return &LogStoreStats{Tables: []LogStoreTableStats{}}, nil
+	}
+	stats, err := compactor.Stats(c.Request.Context())
+	if err != nil {
+		return nil, err
+	}
+	resp := &LogStoreStats{
+		EventuallyConsistent: true,
+		Tables:               make([]LogStoreTableStats, 0, len(stats.Tables)),
+	}
+	for _, t := range stats.Tables {
+		resp.Tables = append(resp.Tables, LogStoreTableStats{
+			Table:            t.Table,
+			ActiveParts:      t.ActiveParts,
+			Rows:             t.Rows,
+			BytesOnDisk:      t.BytesOnDisk,
+			MergesInProgress: t.MergesInProgress,
+			PendingMutations: t.PendingMutations,
+		})
+	}
+	return resp, nil
+}
@@ -0,0 +1,132 @@
+package apirouter_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// compactingLogStore is a memory log store that reports itself as eventually
+// consistent.
+type compactingLogStore struct {
+	logstore.LogStore
+	flushes  int
+	flushErr error
+}
+
+func (s *compactingLogStore) Stats(ctx context.Context) (*logstore.CompactionStats, error) {
+	parts := uint64(3)
+	if s.flushes > 0 {
+		parts = 1
+	}
+	return &logstore.CompactionStats{Tables: []logstore.TableCompactionStats{
+		{Table: "events", ActiveParts: parts, Rows: 10, BytesOnDisk: 2048},
+		{Table: "attempts", ActiveParts: parts, Rows: 12, BytesOnDisk: 4096, MergesInProgress: 1},
+	}}, nil
+}
+
+func (s *compactingLogStore) Flush(ctx context.Context) error {
+	if s.flushErr != nil {
+		return s.flushErr
+	}
+	s.flushes++
+	return nil
+}
+
+func TestAPI_LogStoreStats(t *testing.T) {
+	t.Run("store without compaction", func(t *testing.T) {
+		h := newAPITest(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/logstore/stats", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var stats apirouter.LogStoreStats
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &stats))
+		assert.False(t, stats.EventuallyConsistent)
+		assert.Empty(t, stats.Tables)
+	})
+
+	t.Run("eventually consistent store", func(t *testing.T) {
+		h := newAPITest(t, withLogStore(&compactingLogStore{LogStore: logstore.NewMemLogStore()}))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/logstore/stats", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var stats apirouter.LogStoreStats
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &stats))
+		assert.True(t, stats.EventuallyConsistent)
+		require.Len(t, stats.Tables, 2)
+		assert.Equal(t, apirouter.LogStoreTableStats{
+			Table:            "attempts",
+			ActiveParts:      3,
+			Rows:             12,
+			BytesOnDisk:      4096,
+			MergesInProgress: 1,
+		}, stats.Tables[1])
+	})
+
+	t.Run("jwt returns 403", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/logstore/stats", nil)
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
+
+func TestAPI_LogStoreFlush(t *testing.T) {
+	t.Run("flushes and returns stats", func(t *testing.T) {
+		ls := &compactingLogStore{LogStore: logstore.NewMemLogStore()}
+		h := newAPITest(t, withLogStore(ls))
+
+		resp := h.do(h.withAPIKey(h.jsonReq(http.MethodPost, "/api/v1/logstore/flush", nil)))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, 1, ls.flushes)
+		var stats apirouter.LogStoreStats
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &stats))
+		require.Len(t, stats.Tables, 2)
+		assert.Equal(t, uint64(1), stats.Tables[0].ActiveParts)
+	})
+
+	t.Run("store without compaction is a no-op", func(t *testing.T) {
+		h := newAPITest(t)
+
+		resp := h.do(h.withAPIKey(h.jsonReq(http.MethodPost, "/api/v1/logstore/flush", nil)))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var stats apirouter.LogStoreStats
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &stats))
+		assert.False(t, stats.EventuallyConsistent)
+	})
+
+	t.Run("flush failure returns 500", func(t *testing.T) {
+		ls := &compactingLogStore{LogStore: logstore.NewMemLogStore(), flushErr: errors.New("timeout")}
+		h := newAPITest(t, withLogStore(ls))
+
+		resp := h.do(h.withAPIKey(h.jsonReq(http.MethodPost, "/api/v1/logstore/flush", nil)))
+
+		require.Equal(t, http.StatusInternalServerError, resp.Code)
+	})
+
+	t.Run("jwt returns 403", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		resp := h.do(h.withJWT(h.jsonReq(http.MethodPost, "/api/v1/logstore/flush", nil), "t1"))
+
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"slices"
 	"strconv"
 	"time"
 
@@ -52,12 +53,23 @@ func (s stringSet) contains(v string) bool {
 
 var (
 	eventMeasures   = newStringSet("count", "rate")
-	eventDimensions = newStringSet("tenant_id", "topic", "destination_id")
-	eventFilters    = newStringSet("tenant_id", "topic", "destination_id")
+	eventDimensions = newStringSet("tenant_id", "topic", "source", "destination_id")
+	eventFilters    = newStringSet("tenant_id", "topic", "source", "destination_id")
+
+	attemptMeasures   = newStringSet("count", "successful_count", "failed_count", "error_rate", "first_attempt_count", "retry_count", "manual_retry_count", "avg_attempt_number", "rate", "successful_rate", "failed_rate", "p50_latency_ms", "p95_latency_ms")
+	attemptDimensions = newStringSet("tenant_id", "destination_id", "destination_type", "topic", "source", "status", "code", "manual", "attempt_number")
+	attemptFilters    = newStringSet("tenant_id", "destination_id", "destination_type", "topic", "source", "status", "code", "manual", "attempt_number")
+
+	// deliveryMeasures are the measures of each delivery metrics data point.
+	deliveryMeasures = []string{"successful_count", "failed_count", "p50_latency_ms", "p95_latency_ms"}
+	// deliveryGroups maps the group_by values of the delivery metrics to
+	// attempt dimensions.
+	deliveryGroups = map[string]string{"destination": "destination_id", "topic": "topic"}
+)
 
-	attemptMeasures   = newStringSet("count", "successful_count", "failed_count", "error_rate", "first_attempt_count", "retry_count", "manual_retry_count", "avg_attempt_number", "rate", "successful_rate", "failed_rate")
-	attemptDimensions = newStringSet("tenant_id", "destination_id", "destination_type", "topic", "status", "code", "manual", "attempt_number")
-	attemptFilters    = newStringSet("tenant_id", "destination_id", "destination_type", "topic", "status", "code", "manual", "attempt_number")
+const (
+	defaultDeliveryMetricsWindow      = 24 * time.Hour
+	defaultDeliveryMetricsGranularity = "1h"
 )
 
 // --- API response types ---
@@ -178,6 +190,56 @@ func parseMetricsRequest(c *gin.Context, allowedMeasures, allowedDimensions, all
 	}, nil
 }
 
+// parseDeliveryMetricsRequest reads the query of the delivery metrics. The
+// time range defaults to the last 24 hours, bucketed by hour.
+func parseDeliveryMetricsRequest(c *gin.Context) (*logstore.MetricsRequest, error) {
+	end := time.Now().UTC()
+	if endStr := c.Query("time[end]"); endStr != "" {
+		t, err := time.Parse(time.RFC3339, endStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time[end]: %w", err)
+		}
+		end = t
+	}
+	start := end.Add(-defaultDeliveryMetricsWindow)
+	if startStr := c.Query("time[start]"); startStr != "" {
+		t, err := time.Parse(time.RFC3339, startStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid time[start]: %w", err)
+		}
+		start = t
+	}
+
+	gran, err := parseGranularity(c.DefaultQuery("granularity", defaultDeliveryMetricsGranularity))
+	if err != nil {
+		return nil, err
+	}
+
+	var dimensions []string
+	if groupBy := c.Query("group_by"); groupBy != "" {
+		dimension, ok := deliveryGroups[groupBy]
+		if !ok {
+			return nil, fmt.Errorf("invalid group_by %q: must be destination or topic", groupBy)
+		}
+		dimensions = []string{dimension}
+	}
+
+	filters := make(map[string][]string)
+	for _, key := range []string{"destination_id", "topic"} {
+		if vals := ParseArrayQueryParam(c, key); len(vals) > 0 {
+			filters[key] = vals
+		}
+	}
+
+	return &logstore.MetricsRequest{
+		TimeRange:   logstore.TimeRange{Start: start, End: end},
+		Granularity: gran,
+		Measures:    slices.Clone(deliveryMeasures),
+		Dimensions:  dimensions,
+		Filters:     filters,
+	}, nil
+}
+
 // isJWTCaller returns true when the request was authenticated via JWT (tenant role).
 func isJWTCaller(c *gin.Context) bool {
 	return mustRoleFromContext(c) == RoleTenant
@@ -257,6 +319,34 @@ func (h *MetricsHandlers) MetricsAttempts(c *gin.Context) {
 	c.JSON(http.StatusOK, buildAPIMetricsResponse(apiData, resp.Metadata, req.Granularity))
 }
 
+// MetricsDeliveries handles GET /tenants/:tenant_id/metrics/deliveries. It
+// returns the tenant's successful and failed deliveries and their p50 and p95
+// latency per time bucket, optionally grouped by destination or topic, for
+// the portal's charts.
+func (h *MetricsHandlers) MetricsDeliveries(c *gin.Context) {
+	tenant := mustTenantFromContext(c)
+
+	req, err := parseDeliveryMetricsRequest(c)
+	if err != nil {
+		AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(err))
+		return
+	}
+	req.Filters["tenant_id"] = []string{tenant.ID}
+
+	resp, err := h.metricsStore.QueryAttemptMetrics(c.Request.Context(), *req)
+	if err != nil {
+		abortWithMetricsError(c, err)
+		return
+	}
+
+	apiData := make([]APIMetricsDataPoint, len(resp.Data))
+	for i, dp := range resp.Data {
+		apiData[i] = attemptDataPointToAPI(dp, req.Measures, req.Dimensions)
+	}
+
+	c.JSON(http.StatusOK, buildAPIMetricsResponse(apiData, resp.Metadata, req.Granularity))
+}
+
 // rejectTenantIDDimension aborts with 403 if the request includes tenant_id as a dimension.
 // Returns true if the request was aborted.
 func rejectTenantIDDimension(c *gin.Context) bool {
@@ -348,6 +438,8 @@ func eventDataPointToAPI(dp logstore.EventMetricsDataPoint, measures, dimensions
 			dims["tenant_id"] = derefString(dp.TenantID)
 		case "topic":
 			dims["topic"] = derefString(dp.Topic)
+		case "source":
+			dims["source"] = derefString(dp.Source)
 		case "destination_id":
 			dims["destination_id"] = derefString(dp.DestinationID)
 		}
@@ -386,6 +478,10 @@ func attemptDataPointToAPI(dp logstore.AttemptMetricsDataPoint, measures, dimens
 			metrics["successful_rate"] = derefFloat64(dp.SuccessfulRate)
 		case "failed_rate":
 			metrics["failed_rate"] = derefFloat64(dp.FailedRate)
+		case "p50_latency_ms":
+			metrics["p50_latency_ms"] = derefFloat64(dp.P50LatencyMs)
+		case "p95_latency_ms":
+			metrics["p95_latency_ms"] = derefFloat64(dp.P95LatencyMs)
 		}
 	}
 
@@ -400,6 +496,8 @@ func attemptDataPointToAPI(dp logstore.AttemptMetricsDataPoint, measures, dimens
 			dims["destination_type"] = derefString(dp.DestinationType)
 		case "topic":
 			dims["topic"] = derefString(dp.Topic)
+		case "source":
+			dims["source"] = derefString(dp.Source)
 		case "status":
 			dims["status"] = derefString(dp.Status)
 		case "code":
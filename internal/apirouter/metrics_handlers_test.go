@@ -633,6 +633,29 @@ func TestAPI_MetricsAttempts(t *testing.T) {
 		}
 	})
 
+	t.Run("latency percentiles", func(t *testing.T) {
+		h := newAPITest(t)
+
+		e1 := ef.AnyPointer(ef.WithTenantID("t1"))
+		e2 := ef.AnyPointer(ef.WithTenantID("t1"))
+		require.NoError(t, h.logStore.InsertMany(t.Context(), []*models.LogEntry{
+			{Event: e1, Attempt: attemptForEvent(e1, af.WithLatencyMs(100))},
+			{Event: e2, Attempt: attemptForEvent(e2, af.WithLatencyMs(300))},
+		}))
+
+		req := httptest.NewRequest(http.MethodGet,
+			"/api/v1/metrics/attempts?"+baseQS+"&measures[0]=p50_latency_ms&measures[1]=p95_latency_ms", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var result apirouter.APIMetricsResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		require.Len(t, result.Data, 1)
+		assert.InDelta(t, 200, result.Data[0].Metrics["p50_latency_ms"], 0.001)
+		assert.InDelta(t, 290, result.Data[0].Metrics["p95_latency_ms"], 0.001)
+	})
+
 	t.Run("rate with granularity", func(t *testing.T) {
 		h := newAPITest(t)
 
@@ -655,3 +678,116 @@ func TestAPI_MetricsAttempts(t *testing.T) {
 		}
 	})
 }
+
+func TestAPI_MetricsDeliveries(t *testing.T) {
+	setup := func(t *testing.T) *apiTest {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t2")))
+
+		e1 := ef.AnyPointer(ef.WithTenantID("t1"), ef.WithDestinationID("d1"), ef.WithTopic("user.created"))
+		e2 := ef.AnyPointer(ef.WithTenantID("t1"), ef.WithDestinationID("d1"), ef.WithTopic("user.created"))
+		e3 := ef.AnyPointer(ef.WithTenantID("t1"), ef.WithDestinationID("d1"), ef.WithTopic("user.updated"))
+		e4 := ef.AnyPointer(ef.WithTenantID("t1"), ef.WithDestinationID("d2"), ef.WithTopic("user.updated"))
+		e5 := ef.AnyPointer(ef.WithTenantID("t2"), ef.WithDestinationID("d3"), ef.WithTopic("user.created"))
+		require.NoError(t, h.logStore.InsertMany(t.Context(), []*models.LogEntry{
+			{Event: e1, Attempt: attemptForEvent(e1, af.WithStatus("success"), af.WithLatencyMs(100))},
+			{Event: e2, Attempt: attemptForEvent(e2, af.WithStatus("success"), af.WithLatencyMs(300))},
+			{Event: e3, Attempt: attemptForEvent(e3, af.WithStatus("failed"), af.WithLatencyMs(200))},
+			{Event: e4, Attempt: attemptForEvent(e4, af.WithStatus("success"), af.WithLatencyMs(50))},
+			{Event: e5, Attempt: attemptForEvent(e5, af.WithStatus("success"), af.WithLatencyMs(1000))},
+		}))
+		return h
+	}
+
+	// totals sums the counts of each group across time buckets.
+	totals := func(t *testing.T, result apirouter.APIMetricsResponse, dimension string) map[string][2]float64 {
+		t.Helper()
+		sums := map[string][2]float64{}
+		for _, dp := range result.Data {
+			key, _ := dp.Dimensions[dimension].(string)
+			sum := sums[key]
+			sum[0] += dp.Metrics["successful_count"].(float64)
+			sum[1] += dp.Metrics["failed_count"].(float64)
+			sums[key] = sum
+		}
+		return sums
+	}
+
+	t.Run("groups by destination", func(t *testing.T) {
+		h := setup(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/metrics/deliveries?group_by=destination", nil)
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var result apirouter.APIMetricsResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		require.NotNil(t, result.Metadata.Granularity)
+		assert.Equal(t, "1h", *result.Metadata.Granularity, "buckets are hourly by default")
+		assert.Equal(t, map[string][2]float64{"d1": {2, 1}, "d2": {1, 0}}, totals(t, result, "destination_id"))
+
+		for _, dp := range result.Data {
+			require.NotNil(t, dp.TimeBucket)
+			if dp.Dimensions["destination_id"] == "d1" && dp.Metrics["successful_count"].(float64) > 0 {
+				assert.InDelta(t, 200, dp.Metrics["p50_latency_ms"], 0.001)
+				assert.InDelta(t, 290, dp.Metrics["p95_latency_ms"], 0.001)
+			}
+		}
+	})
+
+	t.Run("groups by topic", func(t *testing.T) {
+		h := setup(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/metrics/deliveries?group_by=topic&granularity=1d", nil)
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var result apirouter.APIMetricsResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		assert.Equal(t, map[string][2]float64{"user.created": {2, 0}, "user.updated": {1, 1}}, totals(t, result, "topic"))
+	})
+
+	t.Run("filters by destination", func(t *testing.T) {
+		h := setup(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/metrics/deliveries?destination_id=d2", nil)
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var result apirouter.APIMetricsResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		assert.Equal(t, map[string][2]float64{"": {1, 0}}, totals(t, result, "destination_id"))
+	})
+
+	t.Run("jwt reads its own tenant", func(t *testing.T) {
+		h := setup(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/metrics/deliveries?group_by=destination", nil)
+		resp := h.do(h.withJWT(req, "t1"))
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var result apirouter.APIMetricsResponse
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		sums := totals(t, result, "destination_id")
+		assert.NotContains(t, sums, "d3", "other tenants' deliveries are excluded")
+	})
+
+	t.Run("invalid group_by returns 400", func(t *testing.T) {
+		h := setup(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/metrics/deliveries?group_by=status", nil)
+		resp := h.do(h.withAPIKey(req))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+
+	t.Run("start after end returns 400", func(t *testing.T) {
+		h := setup(t)
+
+		end := time.Now().Add(-2 * time.Hour).UTC().Format(time.RFC3339)
+		start := time.Now().Add(-1 * time.Hour).UTC().Format(time.RFC3339)
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/metrics/deliveries?time[start]="+start+"&time[end]="+end, nil)
+		resp := h.do(h.withAPIKey(req))
+		assert.Equal(t, http.StatusBadRequest, resp.Code)
+	})
+}
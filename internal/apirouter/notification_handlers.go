@@ -0,0 +1,109 @@
+package apirouter
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/models"
+	"go.uber.org/zap"
+)
+
+// UpdateNotificationsRequest replaces a tenant's notification preferences. At
+// least one of Email and WebhookURL is required.
+type UpdateNotificationsRequest struct {
+	Email      string   `json:"email" binding:"-"`
+	WebhookURL string   `json:"webhook_url" binding:"-"`
+	Categories []string `json:"categories" binding:"-"`
+}
+
+func (r *UpdateNotificationsRequest) toPreferences() (*models.NotificationPreferences, error) {
+	if r.Email == "" && r.WebhookURL == "" {
+		return nil, errors.New("email or webhook_url is required")
+	}
+	if r.Email != "" {
+		if addr, err := mail.ParseAddress(r.Email); err != nil || addr.Address != r.Email {
+			return nil, errors.New("email must be a valid email address")
+		}
+	}
+	if r.WebhookURL != "" {
+		u, err := url.Parse(r.WebhookURL)
+		if err != nil || u.Scheme != "https" || u.Host == "" {
+			return nil, errors.New("webhook_url must be an absolute https URL")
+		}
+	}
+	categories := []string{}
+	for _, category := range r.Categories {
+		if !slices.Contains(models.NotificationCategories, category) {
+			return nil, fmt.Errorf("unknown notification category %q", category)
+		}
+		if !slices.Contains(categories, category) {
+			categories = append(categories, category)
+		}
+	}
+	return &models.NotificationPreferences{
+		Email:      r.Email,
+		WebhookURL: r.WebhookURL,
+		Categories: categories,
+	}, nil
+}
+
+// RetrieveNotifications returns the tenant's notification preferences. A
+// tenant without preferences gets an empty set of categories.
+func (h *TenantHandlers) RetrieveNotifications(c *gin.Context) {
+	tenant := mustTenantFromContext(c)
+	if tenant.Notifications == nil {
+		c.JSON(http.StatusOK, models.NotificationPreferences{Categories: []string{}})
+		return
+	}
+	c.JSON(http.StatusOK, tenant.Notifications)
+}
+
+// UpdateNotifications replaces the tenant's notification preferences.
+func (h *TenantHandlers) UpdateNotifications(c *gin.Context) {
+	var input UpdateNotificationsRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	preferences, err := input.toPreferences()
+	if err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+
+	tenant := *mustTenantFromContext(c)
+	tenant.Notifications = preferences
+	tenant.UpdatedAt = time.Now()
+	if err := h.tenantStore.UpsertTenant(c.Request.Context(), tenant); err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	h.logger.Ctx(c.Request.Context()).Audit("tenant notifications updated",
+		zap.String("tenant_id", tenant.ID),
+		zap.Strings("categories", preferences.Categories),
+	)
+	c.JSON(http.StatusOK, preferences)
+}
+
+// DeleteNotifications clears the tenant's notification preferences.
+func (h *TenantHandlers) DeleteNotifications(c *gin.Context) {
+	tenant := *mustTenantFromContext(c)
+	if tenant.Notifications != nil {
+		tenant.Notifications = nil
+		tenant.UpdatedAt = time.Now()
+		if err := h.tenantStore.UpsertTenant(c.Request.Context(), tenant); err != nil {
+			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+			return
+		}
+		h.logger.Ctx(c.Request.Context()).Audit("tenant notifications cleared",
+			zap.String("tenant_id", tenant.ID),
+		)
+	}
+	c.JSON(http.StatusOK, models.NotificationPreferences{Categories: []string{}})
+}
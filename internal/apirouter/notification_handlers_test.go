@@ -0,0 +1,131 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_TenantNotifications(t *testing.T) {
+	t.Run("jwt sets preferences", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/notifications", map[string]any{
+			"email":      "ops@example.com",
+			"categories": []string{"failures", "disables", "failures"},
+		})
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var prefs models.NotificationPreferences
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &prefs))
+		assert.Equal(t, []string{"failures", "disables"}, prefs.Categories)
+
+		tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+		require.NoError(t, err)
+		require.NotNil(t, tenant.Notifications)
+		assert.Equal(t, "ops@example.com", tenant.Notifications.Email)
+		assert.True(t, tenant.Notifications.Wants(models.NotificationCategoryDisables))
+		assert.False(t, tenant.Notifications.Wants(models.NotificationCategoryMaintenance))
+	})
+
+	t.Run("get without preferences returns empty categories", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/notifications", nil)
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"categories":[]}`, resp.Body.String())
+	})
+
+	t.Run("get returns stored preferences", func(t *testing.T) {
+		h := newAPITest(t)
+		existing := tf.Any(tf.WithID("t1"))
+		existing.Notifications = &models.NotificationPreferences{
+			WebhookURL: "https://hooks.example.com/outpost",
+			Categories: []string{"maintenance"},
+		}
+		h.tenantStore.UpsertTenant(t.Context(), existing)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/notifications", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"webhook_url":"https://hooks.example.com/outpost","categories":["maintenance"]}`, resp.Body.String())
+	})
+
+	t.Run("delete clears preferences", func(t *testing.T) {
+		h := newAPITest(t)
+		existing := tf.Any(tf.WithID("t1"))
+		existing.Notifications = &models.NotificationPreferences{Email: "ops@example.com", Categories: []string{"failures"}}
+		h.tenantStore.UpsertTenant(t.Context(), existing)
+
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/tenants/t1/notifications", nil)
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+		require.NoError(t, err)
+		assert.Nil(t, tenant.Notifications)
+	})
+
+	t.Run("tenant upsert keeps preferences", func(t *testing.T) {
+		h := newAPITest(t)
+		existing := tf.Any(tf.WithID("t1"))
+		existing.Notifications = &models.NotificationPreferences{Email: "ops@example.com", Categories: []string{"failures"}}
+		h.tenantStore.UpsertTenant(t.Context(), existing)
+
+		req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+			"metadata": map[string]string{"env": "prod"},
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+		require.NoError(t, err)
+		require.NotNil(t, tenant.Notifications)
+		assert.Equal(t, "ops@example.com", tenant.Notifications.Email)
+	})
+
+	t.Run("Validation", func(t *testing.T) {
+		tests := []struct {
+			name string
+			body map[string]any
+		}{
+			{"missing contact", map[string]any{"categories": []string{"failures"}}},
+			{"invalid email", map[string]any{"email": "not-an-email", "categories": []string{"failures"}}},
+			{"plain http webhook", map[string]any{"webhook_url": "http://hooks.example.com", "categories": []string{"failures"}}},
+			{"unknown category", map[string]any{"email": "ops@example.com", "categories": []string{"billing"}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name+" returns 422", func(t *testing.T) {
+				h := newAPITest(t)
+				h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/notifications", tt.body)
+				resp := h.do(h.withJWT(req, "t1"))
+
+				require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+			})
+		}
+	})
+
+	t.Run("jwt other tenant returns 403", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t2")))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t2/notifications", nil)
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
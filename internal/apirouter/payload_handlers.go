@@ -0,0 +1,51 @@
+package apirouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/payloadoffload"
+)
+
+type payloadStore interface {
+	Retrieve(ctx context.Context, token string) (*payloadoffload.Payload, error)
+}
+
+type PayloadHandlers struct {
+	logger   *logging.Logger
+	payloads payloadStore
+}
+
+func NewPayloadHandlers(logger *logging.Logger, payloads payloadStore) *PayloadHandlers {
+	return &PayloadHandlers{
+		logger:   logger,
+		payloads: payloads,
+	}
+}
+
+// Retrieve handles GET /payloads/:token. A destination that received an
+// offloaded delivery stub fetches the event data from the stub's fetch URL.
+// The token authenticates the request, so the route is public.
+func (h *PayloadHandlers) Retrieve(c *gin.Context) {
+	if h.payloads == nil {
+		AbortWithError(c, http.StatusNotImplemented, ErrorResponse{
+			Code:    http.StatusNotImplemented,
+			Message: "payload offloading is not enabled",
+		})
+		return
+	}
+
+	payload, err := h.payloads.Retrieve(c.Request.Context(), c.Param("token"))
+	if err != nil {
+		if errors.Is(err, payloadoffload.ErrNotFound) {
+			AbortWithError(c, http.StatusNotFound, NewErrNotFound("payload"))
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	c.Data(http.StatusOK, "application/json", payload.Data)
+}
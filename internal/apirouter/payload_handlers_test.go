@@ -0,0 +1,51 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/payloadoffload"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_Payloads(t *testing.T) {
+	payload := payloadoffload.Payload{
+		TenantID:      "t1",
+		EventID:       "e1",
+		DestinationID: "d1",
+		Data:          json.RawMessage(`{"user":{"id":1}}`),
+	}
+
+	t.Run("returns the payload without credentials", func(t *testing.T) {
+		payloads := payloadoffload.New(testutil.CreateTestRedisClient(t))
+		require.NoError(t, payloads.Save(t.Context(), "token", payload, time.Minute))
+		h := newAPITest(t, withPayloads(payloads))
+
+		resp := h.do(httptest.NewRequest(http.MethodGet, "/api/v1/payloads/token", nil))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.Equal(t, "application/json", resp.Header().Get("Content-Type"))
+		assert.JSONEq(t, `{"user":{"id":1}}`, resp.Body.String())
+	})
+
+	t.Run("unknown token returns 404", func(t *testing.T) {
+		h := newAPITest(t, withPayloads(payloadoffload.New(testutil.CreateTestRedisClient(t))))
+
+		resp := h.do(httptest.NewRequest(http.MethodGet, "/api/v1/payloads/unknown", nil))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("without a payload store returns 501", func(t *testing.T) {
+		h := newAPITest(t)
+
+		resp := h.do(httptest.NewRequest(http.MethodGet, "/api/v1/payloads/token", nil))
+
+		assert.Equal(t, http.StatusNotImplemented, resp.Code)
+	})
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -12,25 +14,67 @@ import (
 	"github.com/hookdeck/outpost/internal/idgen"
 	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/publishkey"
 	"github.com/hookdeck/outpost/internal/publishmq"
+	"github.com/hookdeck/outpost/internal/publishrate"
+	"go.uber.org/zap"
 )
 
 type eventHandler interface {
 	Handle(ctx context.Context, event *models.Event) (*publishmq.HandleResult, error)
+	HandleGroup(ctx context.Context, groupID string, events []*models.Event) ([]*publishmq.HandleResult, error)
+	DryRun(ctx context.Context, event *models.Event) (*publishmq.DryRunResult, error)
 }
 
+// eventRateCounter counts the events a tenant published in the current
+// minute. Satisfied by eventrate.Counter.
+type eventRateCounter interface {
+	Incr(ctx context.Context, tenantID string) (int, error)
+}
+
+// publishRateLimiter takes a token from a tenant's publish rate limit.
+// Satisfied by *publishrate.Limiter.
+type publishRateLimiter interface {
+	Allow(ctx context.Context, tenantID string) (publishrate.Result, error)
+}
+
+// publishKeys records the events published with idempotency keys. Satisfied
+// by publishkey.Keys.
+type publishKeys interface {
+	Reserve(ctx context.Context, tenantID, key, eventID string) (publishkey.Entry, error)
+	Complete(ctx context.Context, tenantID, key, eventID string, destinationIDs []string) error
+}
+
+// maxIdempotencyKeyLength caps the length of a publish idempotency key.
+const maxIdempotencyKeyLength = 255
+
 type PublishHandlers struct {
 	logger       *logging.Logger
 	eventHandler eventHandler
+	eventRates   eventRateCounter
+	rateLimiter  publishRateLimiter
+	keys         publishKeys
+	emitter      SubscriptionEmitter
+	quota        tenantQuota
 }
 
 func NewPublishHandlers(
 	logger *logging.Logger,
 	eventHandler eventHandler,
+	eventRates eventRateCounter,
+	rateLimiter publishRateLimiter,
+	keys publishKeys,
+	emitter SubscriptionEmitter,
+	quota tenantQuota,
 ) *PublishHandlers {
 	return &PublishHandlers{
 		logger:       logger,
 		eventHandler: eventHandler,
+		eventRates:   eventRates,
+		rateLimiter:  rateLimiter,
+		keys:         keys,
+		emitter:      emitter,
+		quota:        quota,
 	}
 }
 
@@ -40,42 +84,373 @@ func (h *PublishHandlers) Ingest(c *gin.Context) {
 		AbortWithValidationError(c, err)
 		return
 	}
-	// json.RawMessage is []byte, so binding:"required" only checks non-nil.
-	// We must reject null, invalid JSON, and non-object types (strings,
-	// numbers, arrays) since the system expects data to be a JSON object.
-	if !json.Valid(publishedEvent.Data) || publishedEvent.Data[0] != '{' {
+	if !mustBeDataObject(c, publishedEvent.Data) {
+		return
+	}
+	dryRun := false
+	if raw := c.Query("dry_run"); raw != "" {
+		var err error
+		if dryRun, err = strconv.ParseBool(raw); err != nil {
+			AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(errors.New("invalid dry_run: must be true or false")))
+			return
+		}
+	}
+	if dryRun {
+		// A dry run enqueues nothing, so it neither takes a rate limit token
+		// nor counts against the event quota.
+		event := publishedEvent.toEvent()
+		result, err := h.eventHandler.DryRun(c.Request.Context(), &event)
+		if err != nil {
+			abortWithPublishError(c, err)
+			return
+		}
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	event := publishedEvent.toEvent()
+	idempotencyKey, ok := h.reserveIdempotencyKey(c, &publishedEvent, &event)
+	if !ok {
+		return
+	}
+	if !h.checkRateLimit(c, publishedEvent.TenantID) {
+		return
+	}
+	if !h.checkEventQuota(c, publishedEvent.TenantID) {
+		return
+	}
+	result, err := h.eventHandler.Handle(c.Request.Context(), &event)
+	if err != nil {
+		abortWithPublishError(c, err)
+		return
+	}
+	if idempotencyKey != "" {
+		// The event ID stays reserved for the key either way, so a failure
+		// here only lets a retry past the publish queue's own deduplication
+		// window publish again.
+		if err := h.keys.Complete(c.Request.Context(), event.TenantID, idempotencyKey, event.ID, result.DestinationIDs); err != nil {
+			h.logger.Ctx(c.Request.Context()).Error("failed to complete idempotency key",
+				zap.Error(err),
+				zap.String("tenant_id", event.TenantID),
+				zap.String("event_id", event.ID))
+		}
+	}
+	c.JSON(http.StatusAccepted, result)
+}
+
+// IngestGroup handles POST /publish/group. The events of the group are
+// validated together and accepted or rejected together, then delivered
+// independently, each tagged with the group's ID. They must all belong to
+// the same tenant.
+func (h *PublishHandlers) IngestGroup(c *gin.Context) {
+	var group PublishedGroup
+	if err := c.ShouldBindJSON(&group); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	if !mustBeValidGroup(c, &group) {
+		return
+	}
+	tenantID := group.Events[0].TenantID
+
+	events := make([]*models.Event, len(group.Events))
+	for i := range group.Events {
+		event := group.Events[i].toEvent()
+		events[i] = &event
+	}
+	for range events {
+		if !h.checkRateLimit(c, tenantID) {
+			return
+		}
+		if !h.checkEventQuota(c, tenantID) {
+			return
+		}
+	}
+
+	groupID := group.ID
+	if groupID == "" {
+		groupID = idgen.String()
+	}
+	results, err := h.eventHandler.HandleGroup(c.Request.Context(), groupID, events)
+	if err != nil {
+		var groupErr *publishmq.GroupError
+		if errors.As(err, &groupErr) {
+			abortWithGroupError(c, groupErr)
+			return
+		}
+		abortWithPublishError(c, err)
+		return
+	}
+	c.JSON(http.StatusAccepted, PublishedGroupResult{
+		GroupID: groupID,
+		Events:  results,
+	})
+}
+
+// mustBeValidGroup rejects groups that are empty, too large, span tenants,
+// repeat an event ID or have events that can't be published on their own.
+// The group ID is capped like an idempotency key.
+func mustBeValidGroup(c *gin.Context, group *PublishedGroup) bool {
+	var errs []string
+	if len(group.ID) > maxIdempotencyKeyLength {
+		errs = append(errs, fmt.Sprintf("id must be at most %d characters", maxIdempotencyKeyLength))
+	}
+	if len(group.Events) == 0 {
+		errs = append(errs, "events must not be empty")
+	}
+	if len(group.Events) > publishmq.MaxGroupSize {
+		errs = append(errs, fmt.Sprintf("events must have at most %d events", publishmq.MaxGroupSize))
+	}
+	seen := make(map[string]bool, len(group.Events))
+	for i, event := range group.Events {
+		if event.TenantID == "" {
+			errs = append(errs, fmt.Sprintf("events[%d].tenant_id is required", i))
+		} else if event.TenantID != group.Events[0].TenantID {
+			errs = append(errs, fmt.Sprintf("events[%d].tenant_id must match the other events of the group", i))
+		}
+		if !json.Valid(event.Data) || len(event.Data) == 0 || event.Data[0] != '{' {
+			errs = append(errs, fmt.Sprintf("events[%d].data must be a valid JSON object", i))
+		}
+		if event.IdempotencyKey != "" {
+			errs = append(errs, fmt.Sprintf("events[%d].idempotency_key is not supported in groups; set event IDs instead", i))
+		}
+		if event.ID != "" {
+			if seen[event.ID] {
+				errs = append(errs, fmt.Sprintf("events[%d].id is repeated in the group", i))
+			}
+			seen[event.ID] = true
+		}
+	}
+	if len(errs) > 0 {
 		AbortWithValidationError(c, ErrorResponse{
 			Code:    http.StatusUnprocessableEntity,
 			Message: "validation error",
-			Data:    []string{"data must be a valid JSON object"},
+			Data:    errs,
 		})
+		return false
+	}
+	return true
+}
+
+// abortWithGroupError aborts with the response for the event that rejected a
+// group, naming its position in the group.
+func abortWithGroupError(c *gin.Context, groupErr *publishmq.GroupError) {
+	if errors.Is(groupErr, publishmq.ErrValidationFailed) {
+		abortWithPublishError(c, groupErr)
+		return
+	}
+	AbortWithValidationError(c, ErrorResponse{
+		Code:    http.StatusUnprocessableEntity,
+		Message: "validation error",
+		Err:     groupErr,
+		Data:    []string{fmt.Sprintf("events[%d]: %s", groupErr.Index, groupErr.Err)},
+	})
+}
+
+// Preview handles POST /tenants/:tenant_id/events/preview. It matches a
+// hypothetical event of the tenant against each of its destinations, like a
+// dry run publish, and reports why each would or wouldn't receive it. Unlike
+// a publish, tenants may preview their own events.
+func (h *PublishHandlers) Preview(c *gin.Context) {
+	var preview EventPreview
+	if err := c.ShouldBindJSON(&preview); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	if !mustBeDataObject(c, preview.Data) {
 		return
 	}
+	tenant := mustTenantFromContext(c)
+
+	publishedEvent := PublishedEvent{
+		ID:            preview.ID,
+		TenantID:      tenant.ID,
+		DestinationID: preview.DestinationID,
+		Topic:         preview.Topic,
+		Time:          preview.Time,
+		Metadata:      preview.Metadata,
+		Data:          preview.Data,
+	}
 	event := publishedEvent.toEvent()
-	result, err := h.eventHandler.Handle(c.Request.Context(), &event)
+	result, err := h.eventHandler.DryRun(c.Request.Context(), &event)
 	if err != nil {
-		if errors.Is(err, idempotence.ErrConflict) {
-			c.Status(http.StatusConflict)
-		} else if errors.Is(err, publishmq.ErrRequiredTopic) {
-			AbortWithValidationError(c, ErrorResponse{
-				Code:    http.StatusUnprocessableEntity,
-				Message: "validation error",
-				Err:     err,
-				Data:    []string{"topic is required"},
-			})
-		} else if errors.Is(err, publishmq.ErrInvalidTopic) {
-			AbortWithValidationError(c, ErrorResponse{
-				Code:    http.StatusUnprocessableEntity,
-				Message: "validation error",
-				Err:     err,
-				Data:    []string{"topic is invalid"},
-			})
-		} else {
-			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
-		}
+		abortWithPublishError(c, err)
 		return
 	}
-	c.JSON(http.StatusAccepted, result)
+	c.JSON(http.StatusOK, result)
+}
+
+// mustBeDataObject rejects event data that isn't a JSON object.
+// json.RawMessage is []byte, so binding:"required" only checks non-nil. We
+// must reject null, invalid JSON, and non-object types (strings, numbers,
+// arrays) since the system expects data to be a JSON object.
+func mustBeDataObject(c *gin.Context, data json.RawMessage) bool {
+	if !json.Valid(data) || data[0] != '{' {
+		AbortWithValidationError(c, ErrorResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "validation error",
+			Data:    []string{"data must be a valid JSON object"},
+		})
+		return false
+	}
+	return true
+}
+
+// reserveIdempotencyKey resolves the request's idempotency key, from the
+// Idempotency-Key header or the idempotency_key field, and publishes the
+// event under the ID reserved for it. When the key's event was already
+// accepted, it responds with the original result and returns false. It
+// returns the key, or "" when the request has none or keys aren't enabled.
+func (h *PublishHandlers) reserveIdempotencyKey(c *gin.Context, publishedEvent *PublishedEvent, event *models.Event) (string, bool) {
+	key := c.GetHeader("Idempotency-Key")
+	if key != "" && publishedEvent.IdempotencyKey != "" && key != publishedEvent.IdempotencyKey {
+		AbortWithValidationError(c, ErrorResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "validation error",
+			Data:    []string{"Idempotency-Key header and idempotency_key must match"},
+		})
+		return "", false
+	}
+	if key == "" {
+		key = publishedEvent.IdempotencyKey
+	}
+	if key == "" || h.keys == nil {
+		return "", true
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		AbortWithValidationError(c, ErrorResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "validation error",
+			Data:    []string{"idempotency_key must be at most 255 characters"},
+		})
+		return "", false
+	}
+
+	entry, err := h.keys.Reserve(c.Request.Context(), event.TenantID, key, event.ID)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return "", false
+	}
+	if publishedEvent.ID != "" && entry.EventID != publishedEvent.ID {
+		AbortWithError(c, http.StatusConflict, ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: "idempotency key was already used to publish another event",
+		})
+		return "", false
+	}
+	if entry.Published {
+		destinationIDs := entry.DestinationIDs
+		if destinationIDs == nil {
+			destinationIDs = []string{}
+		}
+		c.JSON(http.StatusAccepted, publishmq.HandleResult{
+			EventID:        entry.EventID,
+			Duplicate:      true,
+			DestinationIDs: destinationIDs,
+		})
+		return "", false
+	}
+	event.ID = entry.EventID
+	return key, true
+}
+
+// abortWithPublishError aborts with the response for an error publishing an
+// event.
+func abortWithPublishError(c *gin.Context, err error) {
+	if errors.Is(err, idempotence.ErrConflict) {
+		c.Status(http.StatusConflict)
+	} else if errors.Is(err, publishmq.ErrRequiredTopic) {
+		AbortWithValidationError(c, ErrorResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "validation error",
+			Err:     err,
+			Data:    []string{"topic is required"},
+		})
+	} else if errors.Is(err, publishmq.ErrInvalidTopic) {
+		AbortWithValidationError(c, ErrorResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "validation error",
+			Err:     err,
+			Data:    []string{"topic is invalid"},
+		})
+	} else if errors.Is(err, publishmq.ErrRetiredTopic) {
+		AbortWithValidationError(c, ErrorResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "validation error",
+			Err:     err,
+			Data:    []string{"topic is retired"},
+		})
+	} else if errors.Is(err, publishmq.ErrEventRejected) {
+		AbortWithValidationError(c, ErrorResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "validation error",
+			Err:     err,
+			Data:    []string{err.Error()},
+		})
+	} else if errors.Is(err, publishmq.ErrValidationFailed) {
+		AbortWithError(c, http.StatusServiceUnavailable, ErrorResponse{
+			Code:    http.StatusServiceUnavailable,
+			Message: "event validation unavailable",
+			Err:     err,
+		})
+	} else if errors.Is(err, publishmq.ErrInvalidSource) {
+		AbortWithValidationError(c, ErrorResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "validation error",
+			Err:     err,
+			Data:    []string{"source is invalid"},
+		})
+	} else {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+	}
+}
+
+// checkRateLimit takes a token from the tenant's publish rate limit and
+// aborts with 429 and a Retry-After header when none is available. It fails
+// open: when the limit can't be checked the event is published.
+func (h *PublishHandlers) checkRateLimit(c *gin.Context, tenantID string) bool {
+	if h.rateLimiter == nil {
+		return true
+	}
+	ctx := c.Request.Context()
+	result, err := h.rateLimiter.Allow(ctx, tenantID)
+	if err != nil {
+		h.logger.Ctx(ctx).Error("failed to check publish rate limit", zap.Error(err), zap.String("tenant_id", tenantID))
+		return true
+	}
+	if result.Allowed {
+		return true
+	}
+	c.Header("Retry-After", publishrate.RetryAfterSeconds(result.RetryAfter))
+	AbortWithError(c, http.StatusTooManyRequests, ErrorResponse{
+		Code:    http.StatusTooManyRequests,
+		Message: "publish rate limit exceeded",
+	})
+	return false
+}
+
+// checkEventQuota counts the event against the tenant's per-minute event
+// quota, sets the advisory quota headers, and aborts with 429 once the quota
+// is exceeded. It fails open: when the count can't be read the event is
+// published without quota headers.
+func (h *PublishHandlers) checkEventQuota(c *gin.Context, tenantID string) bool {
+	if h.eventRates == nil || h.quota.limit <= 0 {
+		return true
+	}
+	ctx := c.Request.Context()
+	used, err := h.eventRates.Incr(ctx, tenantID)
+	if err != nil {
+		h.logger.Ctx(ctx).Error("failed to count event against quota", zap.Error(err), zap.String("tenant_id", tenantID))
+		return true
+	}
+	h.quota.report(c, h.logger, h.emitter, tenantID, used-1, used)
+	if used > h.quota.limit {
+		AbortWithError(c, http.StatusTooManyRequests, ErrorResponse{
+			Code:    http.StatusTooManyRequests,
+			Message: "event quota exceeded",
+		})
+		return false
+	}
+	return true
 }
 
 type PublishedEvent struct {
@@ -83,10 +458,39 @@ type PublishedEvent struct {
 	TenantID         string            `json:"tenant_id" binding:"required"`
 	DestinationID    string            `json:"destination_id"`
 	Topic            string            `json:"topic"`
+	Source           string            `json:"source"`
 	EligibleForRetry *bool             `json:"eligible_for_retry"`
 	Time             time.Time         `json:"time"`
 	Metadata         map[string]string `json:"metadata"`
 	Data             json.RawMessage   `json:"data" binding:"required"`
+	// IdempotencyKey deduplicates retried publishes, like the
+	// Idempotency-Key header.
+	IdempotencyKey string `json:"idempotency_key"`
+}
+
+// PublishedGroup is a group of events published together.
+type PublishedGroup struct {
+	// ID identifies the group on its events. Generated when empty.
+	ID     string           `json:"id"`
+	Events []PublishedEvent `json:"events"`
+}
+
+// PublishedGroupResult is the result of publishing a group: the result of
+// each event, in the order of the group.
+type PublishedGroupResult struct {
+	GroupID string                    `json:"group_id"`
+	Events  []*publishmq.HandleResult `json:"events"`
+}
+
+// EventPreview is the hypothetical event of a preview. The tenant is the one
+// in the path.
+type EventPreview struct {
+	ID            string            `json:"id"`
+	DestinationID string            `json:"destination_id"`
+	Topic         string            `json:"topic"`
+	Time          time.Time         `json:"time"`
+	Metadata      map[string]string `json:"metadata"`
+	Data          json.RawMessage   `json:"data" binding:"required"`
 }
 
 func (p *PublishedEvent) toEvent() models.Event {
@@ -113,6 +517,7 @@ func (p *PublishedEvent) toEvent() models.Event {
 		TenantID:         p.TenantID,
 		DestinationID:    p.DestinationID,
 		Topic:            p.Topic,
+		Source:           p.Source,
 		EligibleForRetry: eligibleForRetry,
 		Time:             eventTime,
 		Metadata:         metadata,
@@ -8,8 +8,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hookdeck/outpost/internal/apirouter"
 	"github.com/hookdeck/outpost/internal/idempotence"
 	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/opevents"
 	"github.com/hookdeck/outpost/internal/publishmq"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -199,6 +201,46 @@ func TestAPI_Publish(t *testing.T) {
 			assert.Contains(t, data, "topic is invalid")
 		})
 
+		t.Run("retired topic returns 422 with detail", func(t *testing.T) {
+			h := newAPITest(t)
+			h.eventHandler.err = publishmq.ErrRetiredTopic
+
+			req := h.jsonReq(http.MethodPost, "/api/v1/publish", map[string]any{
+				"tenant_id": "t1",
+				"topic":     "user.deleted",
+				"data":      map[string]any{"key": "value"},
+			})
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+			data, ok := body["data"].([]any)
+			require.True(t, ok)
+			assert.Contains(t, data, "topic is retired")
+		})
+
+		t.Run("invalid source returns 422 with detail", func(t *testing.T) {
+			h := newAPITest(t)
+			h.eventHandler.err = publishmq.ErrInvalidSource
+
+			req := h.jsonReq(http.MethodPost, "/api/v1/publish", map[string]any{
+				"tenant_id": "t1",
+				"source":    "billing service",
+				"data":      map[string]any{"key": "value"},
+			})
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+
+			var body map[string]any
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+			data, ok := body["data"].([]any)
+			require.True(t, ok)
+			assert.Contains(t, data, "source is invalid")
+		})
+
 		t.Run("internal error returns 500", func(t *testing.T) {
 			h := newAPITest(t)
 			h.eventHandler.err = errors.New("database error")
@@ -252,6 +294,309 @@ func TestAPI_Publish(t *testing.T) {
 		})
 	})
 
+	t.Run("Dry run", func(t *testing.T) {
+		dryRun := func(h *apiTest, query string) *httptest.ResponseRecorder {
+			req := h.jsonReq(http.MethodPost, "/api/v1/publish?dry_run="+query, map[string]any{
+				"tenant_id": "t1",
+				"topic":     "user.created",
+				"data":      map[string]any{"key": "value"},
+			})
+			return h.do(h.withAPIKey(req))
+		}
+
+		t.Run("returns the fan-out without publishing", func(t *testing.T) {
+			h := newAPITest(t, withEventQuota(1, 0))
+			h.eventHandler.dryRunResult = &publishmq.DryRunResult{
+				EventID:        "evt-123",
+				DryRun:         true,
+				DestinationIDs: []string{"d1"},
+				Destinations: []publishmq.DryRunDestination{
+					{ID: "d1", Type: "webhook", Matched: true},
+					{ID: "d2", Type: "webhook", SuppressionReason: publishmq.SuppressedFilter},
+				},
+			}
+
+			for range 2 {
+				resp := dryRun(h, "true")
+				require.Equal(t, http.StatusOK, resp.Code)
+
+				var result publishmq.DryRunResult
+				require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+				assert.True(t, result.DryRun)
+				assert.Equal(t, []string{"d1"}, result.DestinationIDs)
+				require.Len(t, result.Destinations, 2)
+				assert.Equal(t, publishmq.SuppressedFilter, result.Destinations[1].SuppressionReason)
+				assert.Empty(t, resp.Header().Get("X-Outpost-Quota-Events-Limit"), "dry runs should not count against the quota")
+			}
+			assert.Empty(t, h.eventHandler.calls)
+			assert.Len(t, h.eventHandler.dryRunCalls, 2)
+		})
+
+		t.Run("dry_run=false publishes", func(t *testing.T) {
+			h := newAPITest(t)
+
+			resp := dryRun(h, "false")
+
+			require.Equal(t, http.StatusAccepted, resp.Code)
+			assert.Len(t, h.eventHandler.calls, 1)
+			assert.Empty(t, h.eventHandler.dryRunCalls)
+		})
+
+		t.Run("invalid dry_run returns 400", func(t *testing.T) {
+			h := newAPITest(t)
+
+			resp := dryRun(h, "maybe")
+
+			require.Equal(t, http.StatusBadRequest, resp.Code)
+			assert.Empty(t, h.eventHandler.dryRunCalls)
+		})
+
+		t.Run("invalid topic returns 422", func(t *testing.T) {
+			h := newAPITest(t)
+			h.eventHandler.err = publishmq.ErrInvalidTopic
+
+			resp := dryRun(h, "true")
+
+			require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+		})
+	})
+
+	t.Run("Preview", func(t *testing.T) {
+		body := map[string]any{
+			"topic":    "user.created",
+			"metadata": map[string]string{"source": "api"},
+			"data":     map[string]any{"key": "value"},
+		}
+
+		t.Run("matches the tenant's event without publishing", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/events/preview", body)
+			resp := h.do(h.withJWT(req, "t1"))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			assert.Empty(t, h.eventHandler.calls)
+			require.Len(t, h.eventHandler.dryRunCalls, 1)
+			event := h.eventHandler.dryRunCalls[0]
+			assert.Equal(t, "t1", event.TenantID)
+			assert.Equal(t, "user.created", event.Topic)
+			assert.Equal(t, "api", event.Metadata["source"])
+			assert.NotEmpty(t, event.ID)
+		})
+
+		t.Run("invalid data returns 422", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/events/preview", map[string]any{
+				"topic": "user.created",
+				"data":  []string{"value"},
+			})
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+			assert.Empty(t, h.eventHandler.dryRunCalls)
+		})
+
+		t.Run("unknown tenant returns 404", func(t *testing.T) {
+			h := newAPITest(t)
+
+			req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/events/preview", body)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusNotFound, resp.Code)
+		})
+	})
+
+	t.Run("Event quota", func(t *testing.T) {
+		publish := func(h *apiTest) *httptest.ResponseRecorder {
+			req := h.jsonReq(http.MethodPost, "/api/v1/publish", map[string]any{
+				"tenant_id": "t1",
+				"data":      map[string]any{"key": "value"},
+			})
+			return h.do(h.withAPIKey(req))
+		}
+		quotaWarnings := func(h *apiTest) []opevents.TenantQuotaWarningData {
+			var warnings []opevents.TenantQuotaWarningData
+			for _, call := range h.subscriptionEmitter.calls {
+				if call.topic == opevents.TopicTenantQuotaWarning {
+					warnings = append(warnings, call.data.(opevents.TenantQuotaWarningData))
+				}
+			}
+			return warnings
+		}
+
+		t.Run("publish below threshold sets advisory headers without warning", func(t *testing.T) {
+			h := newAPITest(t, withEventQuota(4, 50))
+
+			resp := publish(h)
+
+			require.Equal(t, http.StatusAccepted, resp.Code)
+			assert.Equal(t, "4", resp.Header().Get("X-Outpost-Quota-Events-Limit"))
+			assert.Equal(t, "3", resp.Header().Get("X-Outpost-Quota-Events-Remaining"))
+			assert.Empty(t, resp.Header().Get("X-Outpost-Quota-Warning"))
+			assert.Empty(t, quotaWarnings(h))
+		})
+
+		t.Run("publish crossing threshold warns once", func(t *testing.T) {
+			h := newAPITest(t, withEventQuota(4, 50))
+
+			var resp *httptest.ResponseRecorder
+			for range 3 {
+				resp = publish(h)
+				require.Equal(t, http.StatusAccepted, resp.Code)
+			}
+
+			assert.Equal(t, "events", resp.Header().Get("X-Outpost-Quota-Warning"))
+			warnings := quotaWarnings(h)
+			require.Len(t, warnings, 1)
+			assert.Equal(t, opevents.TenantQuotaWarningData{
+				TenantID:         "t1",
+				Quota:            "events",
+				Used:             2,
+				Limit:            4,
+				ThresholdPercent: 50,
+			}, warnings[0])
+		})
+
+		t.Run("publish past limit returns 429 without publishing", func(t *testing.T) {
+			h := newAPITest(t, withEventQuota(2, 50))
+			for range 2 {
+				require.Equal(t, http.StatusAccepted, publish(h).Code)
+			}
+
+			resp := publish(h)
+
+			require.Equal(t, http.StatusTooManyRequests, resp.Code)
+			assert.Equal(t, "0", resp.Header().Get("X-Outpost-Quota-Events-Remaining"))
+			assert.Equal(t, "events", resp.Header().Get("X-Outpost-Quota-Warning"))
+			assert.Len(t, h.eventHandler.calls, 2)
+		})
+
+		t.Run("no quota sets no headers", func(t *testing.T) {
+			h := newAPITest(t)
+
+			resp := publish(h)
+
+			require.Equal(t, http.StatusAccepted, resp.Code)
+			assert.Empty(t, resp.Header().Get("X-Outpost-Quota-Events-Limit"))
+		})
+	})
+
+	t.Run("Publish rate limit", func(t *testing.T) {
+		publish := func(h *apiTest, tenantID string) *httptest.ResponseRecorder {
+			req := h.jsonReq(http.MethodPost, "/api/v1/publish", map[string]any{
+				"tenant_id": tenantID,
+				"data":      map[string]any{"key": "value"},
+			})
+			return h.do(h.withAPIKey(req))
+		}
+
+		t.Run("publish past the burst returns 429 with retry-after", func(t *testing.T) {
+			h := newAPITest(t, withPublishRateLimit(models.PublishRateLimit{PerSecond: 1, Burst: 2}))
+			for range 2 {
+				require.Equal(t, http.StatusAccepted, publish(h, "t1").Code)
+			}
+
+			resp := publish(h, "t1")
+
+			require.Equal(t, http.StatusTooManyRequests, resp.Code)
+			assert.Equal(t, "1", resp.Header().Get("Retry-After"))
+			assert.Len(t, h.eventHandler.calls, 2)
+		})
+
+		t.Run("tenant override replaces the default", func(t *testing.T) {
+			h := newAPITest(t, withPublishRateLimit(models.PublishRateLimit{}))
+			tenant := tf.Any(tf.WithID("t1"))
+			tenant.PublishRateLimit = &models.PublishRateLimit{PerSecond: 1}
+			require.NoError(t, h.tenantStore.UpsertTenant(t.Context(), tenant))
+
+			require.Equal(t, http.StatusAccepted, publish(h, "t1").Code)
+			assert.Equal(t, http.StatusTooManyRequests, publish(h, "t1").Code)
+			assert.Equal(t, http.StatusAccepted, publish(h, "t2").Code)
+		})
+	})
+
+	t.Run("Idempotency key", func(t *testing.T) {
+		publish := func(h *apiTest, body map[string]any, key string) *httptest.ResponseRecorder {
+			body["tenant_id"] = "t1"
+			body["data"] = map[string]any{"key": "value"}
+			req := h.jsonReq(http.MethodPost, "/api/v1/publish", body)
+			if key != "" {
+				req.Header.Set("Idempotency-Key", key)
+			}
+			return h.do(h.withAPIKey(req))
+		}
+
+		t.Run("duplicate returns the original event", func(t *testing.T) {
+			h := newAPITest(t, withPublishKeys())
+			h.eventHandler.result = &publishmq.HandleResult{DestinationIDs: []string{"d1"}}
+
+			first := publish(h, map[string]any{}, "key-1")
+			require.Equal(t, http.StatusAccepted, first.Code)
+			require.Len(t, h.eventHandler.calls, 1)
+			eventID := h.eventHandler.calls[0].ID
+
+			resp := publish(h, map[string]any{}, "key-1")
+
+			require.Equal(t, http.StatusAccepted, resp.Code)
+			assert.Len(t, h.eventHandler.calls, 1, "duplicate should not be published")
+			var result publishmq.HandleResult
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+			assert.Equal(t, publishmq.HandleResult{EventID: eventID, Duplicate: true, DestinationIDs: []string{"d1"}}, result)
+		})
+
+		t.Run("body field", func(t *testing.T) {
+			h := newAPITest(t, withPublishKeys())
+
+			require.Equal(t, http.StatusAccepted, publish(h, map[string]any{"idempotency_key": "key-1"}, "").Code)
+			require.Equal(t, http.StatusAccepted, publish(h, map[string]any{"idempotency_key": "key-1"}, "key-1").Code)
+
+			assert.Len(t, h.eventHandler.calls, 1)
+		})
+
+		t.Run("header and body field must match", func(t *testing.T) {
+			h := newAPITest(t, withPublishKeys())
+
+			resp := publish(h, map[string]any{"idempotency_key": "key-1"}, "key-2")
+
+			require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+			assert.Empty(t, h.eventHandler.calls)
+		})
+
+		t.Run("key used with another event id returns 409", func(t *testing.T) {
+			h := newAPITest(t, withPublishKeys())
+			require.Equal(t, http.StatusAccepted, publish(h, map[string]any{"id": "evt_1"}, "key-1").Code)
+
+			resp := publish(h, map[string]any{"id": "evt_2"}, "key-1")
+
+			require.Equal(t, http.StatusConflict, resp.Code)
+			assert.Len(t, h.eventHandler.calls, 1)
+		})
+
+		t.Run("retry after a failed publish reuses the event id", func(t *testing.T) {
+			h := newAPITest(t, withPublishKeys())
+			h.eventHandler.err = errors.New("publish failed")
+			require.Equal(t, http.StatusInternalServerError, publish(h, map[string]any{}, "key-1").Code)
+
+			h.eventHandler.err = nil
+			require.Equal(t, http.StatusAccepted, publish(h, map[string]any{}, "key-1").Code)
+
+			require.Len(t, h.eventHandler.calls, 2)
+			assert.Equal(t, h.eventHandler.calls[0].ID, h.eventHandler.calls[1].ID)
+		})
+
+		t.Run("ignored when keys are disabled", func(t *testing.T) {
+			h := newAPITest(t)
+
+			publish(h, map[string]any{}, "key-1")
+			publish(h, map[string]any{}, "key-1")
+
+			assert.Len(t, h.eventHandler.calls, 2)
+		})
+	})
+
 	t.Run("Input defaults", func(t *testing.T) {
 		t.Run("auto-generates ID when omitted", func(t *testing.T) {
 			h := newAPITest(t)
@@ -374,6 +719,21 @@ func TestAPI_Publish(t *testing.T) {
 			assert.Equal(t, "user.created", h.eventHandler.calls[0].Topic)
 		})
 
+		t.Run("preserves source", func(t *testing.T) {
+			h := newAPITest(t)
+
+			req := h.jsonReq(http.MethodPost, "/api/v1/publish", map[string]any{
+				"tenant_id": "t1",
+				"source":    "billing-service",
+				"data":      map[string]any{"key": "value"},
+			})
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusAccepted, resp.Code)
+			require.Len(t, h.eventHandler.calls, 1)
+			assert.Equal(t, "billing-service", h.eventHandler.calls[0].Source)
+		})
+
 		t.Run("preserves destination_id", func(t *testing.T) {
 			h := newAPITest(t)
 
@@ -451,3 +811,95 @@ func TestAPI_Publish(t *testing.T) {
 		})
 	})
 }
+
+func TestAPI_PublishGroup(t *testing.T) {
+	event := func(id, tenantID string) map[string]any {
+		return map[string]any{
+			"id":        id,
+			"tenant_id": tenantID,
+			"topic":     "user.created",
+			"data":      map[string]any{"key": "value"},
+		}
+	}
+
+	t.Run("accepts the events with the group ID", func(t *testing.T) {
+		h := newAPITest(t)
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/publish/group", map[string]any{
+			"id":     "grp_1",
+			"events": []any{event("e1", "t1"), event("e2", "t1")},
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusAccepted, resp.Code)
+		var result apirouter.PublishedGroupResult
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		assert.Equal(t, "grp_1", result.GroupID)
+		require.Len(t, result.Events, 2)
+		assert.Equal(t, "e1", result.Events[0].EventID)
+		assert.Equal(t, "e2", result.Events[1].EventID)
+		require.Len(t, h.eventHandler.calls, 2)
+		assert.Equal(t, "grp_1", h.eventHandler.calls[0].GroupID)
+	})
+
+	t.Run("generates a group ID", func(t *testing.T) {
+		h := newAPITest(t)
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/publish/group", map[string]any{
+			"events": []any{event("e1", "t1")},
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusAccepted, resp.Code)
+		require.Len(t, h.eventHandler.groupIDs, 1)
+		assert.NotEmpty(t, h.eventHandler.groupIDs[0])
+	})
+
+	t.Run("rejects invalid groups before handling them", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			events []any
+		}{
+			{"no events", []any{}},
+			{"several tenants", []any{event("e1", "t1"), event("e2", "t2")}},
+			{"repeated event ID", []any{event("e1", "t1"), event("e1", "t1")}},
+			{"invalid data", []any{event("e1", "t1"), map[string]any{"tenant_id": "t1", "data": "text"}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				h := newAPITest(t)
+
+				req := h.jsonReq(http.MethodPost, "/api/v1/publish/group", map[string]any{"events": tt.events})
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+				assert.Empty(t, h.eventHandler.groupIDs)
+			})
+		}
+	})
+
+	t.Run("rejected event returns 422 with its position", func(t *testing.T) {
+		h := newAPITest(t)
+		h.eventHandler.err = &publishmq.GroupError{Index: 1, Err: publishmq.ErrInvalidTopic}
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/publish/group", map[string]any{
+			"events": []any{event("e1", "t1"), event("e2", "t1")},
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+		assert.Contains(t, resp.Body.String(), "events[1]: invalid topic")
+	})
+
+	t.Run("jwt returns 403", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/publish/group", map[string]any{
+			"events": []any{event("e1", "t1")},
+		})
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
@@ -0,0 +1,118 @@
+package apirouter
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/opevents"
+	"go.uber.org/zap"
+)
+
+// Advisory quota headers let clients see a tenant approaching a quota before
+// requests start failing.
+const (
+	quotaDestinationsLimitHeader     = "X-Outpost-Quota-Destinations-Limit"
+	quotaDestinationsRemainingHeader = "X-Outpost-Quota-Destinations-Remaining"
+	quotaEventsLimitHeader           = "X-Outpost-Quota-Events-Limit"
+	quotaEventsRemainingHeader       = "X-Outpost-Quota-Events-Remaining"
+	quotaWarningHeader               = "X-Outpost-Quota-Warning"
+)
+
+// Quota names, used in the headers and the tenant.quota.warning operator
+// event.
+const (
+	quotaDestinations = "destinations"
+	quotaEvents       = "events"
+)
+
+// tenantQuota is a per-tenant limit and the percentage of it at which
+// warnings start.
+type tenantQuota struct {
+	name            string
+	limitHeader     string
+	remainingHeader string
+	limit           int
+	warningPercent  int
+}
+
+// destinationQuota is the per-tenant destination limit. The limit itself is
+// enforced by the tenant store; the API only reports on it.
+func destinationQuota(limit, warningPercent int) tenantQuota {
+	return tenantQuota{
+		name:            quotaDestinations,
+		limitHeader:     quotaDestinationsLimitHeader,
+		remainingHeader: quotaDestinationsRemainingHeader,
+		limit:           limit,
+		warningPercent:  warningPercent,
+	}
+}
+
+// eventQuota is the per-tenant limit on events published per minute.
+func eventQuota(limit, warningPercent int) tenantQuota {
+	return tenantQuota{
+		name:            quotaEvents,
+		limitHeader:     quotaEventsLimitHeader,
+		remainingHeader: quotaEventsRemainingHeader,
+		limit:           limit,
+		warningPercent:  warningPercent,
+	}
+}
+
+// threshold returns the usage at which warnings start, or 0 when warnings
+// are disabled.
+func (q tenantQuota) threshold() int {
+	if q.limit <= 0 || q.warningPercent <= 0 {
+		return 0
+	}
+	// Round up so a warning never fires below the configured percentage.
+	return (q.limit*q.warningPercent + 99) / 100
+}
+
+// warns reports whether a tenant with used units is past the warning
+// threshold.
+func (q tenantQuota) warns(used int) bool {
+	threshold := q.threshold()
+	return threshold > 0 && used >= threshold
+}
+
+// crossed reports whether going from prev to used units crossed the warning
+// threshold, so the operator event fires once per crossing rather than on
+// every request.
+func (q tenantQuota) crossed(prev, used int) bool {
+	return q.warns(used) && !q.warns(prev)
+}
+
+// setHeaders writes the advisory quota headers for a tenant with used units.
+func (q tenantQuota) setHeaders(c *gin.Context, used int) {
+	if q.limit <= 0 {
+		return
+	}
+	remaining := max(q.limit-used, 0)
+	c.Header(q.limitHeader, strconv.Itoa(q.limit))
+	c.Header(q.remainingHeader, strconv.Itoa(remaining))
+	if q.warns(used) {
+		c.Header(quotaWarningHeader, q.name)
+	}
+}
+
+// report sets the advisory quota headers for a tenant with used units and
+// emits tenant.quota.warning when going from prev to used crossed the warning
+// threshold. Best-effort: emit errors are logged but do not affect the API
+// response.
+func (q tenantQuota) report(c *gin.Context, logger *logging.Logger, emitter SubscriptionEmitter, tenantID string, prev, used int) {
+	q.setHeaders(c, used)
+	if emitter == nil || !q.crossed(prev, used) {
+		return
+	}
+	ctx := c.Request.Context()
+	if err := emitter.Emit(ctx, opevents.TenantQuotaWarningEvent(opevents.TenantQuotaWarningData{
+		TenantID:         tenantID,
+		Quota:            q.name,
+		Used:             used,
+		Limit:            q.limit,
+		ThresholdPercent: q.warningPercent,
+	})); err != nil {
+		logger.Ctx(ctx).Error("failed to emit quota warning", zap.Error(err))
+	}
+}
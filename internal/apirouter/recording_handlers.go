@@ -0,0 +1,121 @@
+package apirouter
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/models"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultRecordingDuration = time.Hour
+	maxRecordingDuration     = 24 * time.Hour
+)
+
+// StartRecordingRequest turns on a time-boxed debug recording for a
+// destination. SampleRate defaults to 1 (every delivery) and DurationSeconds
+// defaults to one hour, capped at 24 hours.
+type StartRecordingRequest struct {
+	SinkURL         string   `json:"sink_url" binding:"required"`
+	SampleRate      *float64 `json:"sample_rate" binding:"-"`
+	DurationSeconds int      `json:"duration_seconds" binding:"-"`
+}
+
+func (r *StartRecordingRequest) toRecording(now time.Time) (*models.Recording, error) {
+	u, err := url.Parse(r.SinkURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, errors.New("sink_url must be an absolute http(s) URL")
+	}
+	sampleRate := 1.0
+	if r.SampleRate != nil {
+		sampleRate = *r.SampleRate
+	}
+	if sampleRate <= 0 || sampleRate > 1 {
+		return nil, errors.New("sample_rate must be greater than 0 and at most 1")
+	}
+	duration := defaultRecordingDuration
+	if r.DurationSeconds != 0 {
+		duration = time.Duration(r.DurationSeconds) * time.Second
+	}
+	if duration <= 0 || duration > maxRecordingDuration {
+		return nil, errors.New("duration_seconds must be between 1 and 86400")
+	}
+	return &models.Recording{
+		SinkURL:    r.SinkURL,
+		SampleRate: sampleRate,
+		ExpiresAt:  now.Add(duration),
+	}, nil
+}
+
+// StartRecording enables (or replaces) the debug recording of a destination.
+func (h *DestinationHandlers) StartRecording(c *gin.Context) {
+	var input StartRecordingRequest
+	if err := c.ShouldBindJSON(&input); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	recording, err := input.toRecording(time.Now())
+	if err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+
+	tenant := mustTenantFromContext(c)
+	destination := h.mustRetrieveDestination(c, tenant.ID, c.Param("destination_id"))
+	if destination == nil {
+		return
+	}
+
+	destination.Recording = recording
+	if err := h.tenantStore.UpsertDestination(c.Request.Context(), *destination); err != nil {
+		h.handleUpsertDestinationError(c, err)
+		return
+	}
+	h.logger.Ctx(c.Request.Context()).Audit("destination recording started",
+		zap.String("tenant_id", tenant.ID),
+		zap.String("destination_id", destination.ID),
+		zap.String("destination_type", destination.Type),
+		zap.Float64("sample_rate", recording.SampleRate),
+		zap.Time("expires_at", recording.ExpiresAt),
+	)
+
+	display, err := h.displayer.Display(destination)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	c.JSON(http.StatusOK, display)
+}
+
+// StopRecording clears the debug recording of a destination.
+func (h *DestinationHandlers) StopRecording(c *gin.Context) {
+	tenant := mustTenantFromContext(c)
+	destination := h.mustRetrieveDestination(c, tenant.ID, c.Param("destination_id"))
+	if destination == nil {
+		return
+	}
+
+	if destination.Recording != nil {
+		destination.Recording = nil
+		if err := h.tenantStore.UpsertDestination(c.Request.Context(), *destination); err != nil {
+			h.handleUpsertDestinationError(c, err)
+			return
+		}
+		h.logger.Ctx(c.Request.Context()).Audit("destination recording stopped",
+			zap.String("tenant_id", tenant.ID),
+			zap.String("destination_id", destination.ID),
+			zap.String("destination_type", destination.Type),
+		)
+	}
+
+	display, err := h.displayer.Display(destination)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	c.JSON(http.StatusOK, display)
+}
@@ -0,0 +1,86 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_DestinationRecording(t *testing.T) {
+	t.Run("api key starts recording", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+
+		req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/destinations/d1/recording", map[string]any{
+			"sink_url":         "https://debug.example.com/sink",
+			"sample_rate":      0.5,
+			"duration_seconds": 600,
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var dest destregistry.DestinationDisplay
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &dest))
+		require.NotNil(t, dest.Recording)
+		assert.Equal(t, "https://debug.example.com/sink", dest.Recording.SinkURL)
+		assert.Equal(t, 0.5, dest.Recording.SampleRate)
+		assert.WithinDuration(t, time.Now().Add(10*time.Minute), dest.Recording.ExpiresAt, time.Minute)
+
+		stored, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		require.NotNil(t, stored.Recording)
+	})
+
+	t.Run("jwt cannot start recording", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+
+		req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/destinations/d1/recording", map[string]any{
+			"sink_url": "https://debug.example.com/sink",
+		})
+		resp := h.do(h.withJWT(req, "t1"))
+
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+
+	t.Run("invalid sample rate returns 422", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+
+		req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/destinations/d1/recording", map[string]any{
+			"sink_url":    "https://debug.example.com/sink",
+			"sample_rate": 2,
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	t.Run("api key stops recording", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+
+		req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1/destinations/d1/recording", map[string]any{
+			"sink_url": "https://debug.example.com/sink",
+		})
+		require.Equal(t, http.StatusOK, h.do(h.withAPIKey(req)).Code)
+
+		req = httptest.NewRequest(http.MethodDelete, "/api/v1/tenants/t1/destinations/d1/recording", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		stored, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		assert.Nil(t, stored.Recording)
+	})
+}
@@ -0,0 +1,81 @@
+package apirouter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/redismemory"
+)
+
+const (
+	// defaultRedisMemoryMaxKeys bounds the scan of a memory report so a
+	// request returns in reasonable time on a large keyspace. The CLI has no
+	// bound by default.
+	defaultRedisMemoryMaxKeys = 100_000
+	maxRedisMemoryMaxKeys     = 10_000_000
+)
+
+type redisMemoryAnalyzer interface {
+	Analyze(ctx context.Context, opts redismemory.Options) (*redismemory.Report, error)
+}
+
+type RedisHandlers struct {
+	logger   *logging.Logger
+	analyzer redisMemoryAnalyzer
+}
+
+func NewRedisHandlers(logger *logging.Logger, analyzer redisMemoryAnalyzer) *RedisHandlers {
+	return &RedisHandlers{
+		logger:   logger,
+		analyzer: analyzer,
+	}
+}
+
+// Memory handles GET /redis/memory. It reports the Redis memory used by each
+// family of keys, measuring one in every sample_every keys with MEMORY USAGE
+// and scanning at most max_keys keys.
+func (h *RedisHandlers) Memory(c *gin.Context) {
+	if h.analyzer == nil {
+		AbortWithError(c, http.StatusNotImplemented, ErrorResponse{
+			Code:    http.StatusNotImplemented,
+			Message: "redis memory reports are not enabled",
+		})
+		return
+	}
+
+	opts := redismemory.Options{
+		SampleEvery: redismemory.DefaultSampleEvery,
+		MaxKeys:     defaultRedisMemoryMaxKeys,
+	}
+	if value := c.Query("sample_every"); value != "" {
+		sampleEvery, err := strconv.Atoi(value)
+		if err != nil || sampleEvery < 1 {
+			AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(errors.New("invalid sample_every: must be a positive integer")))
+			return
+		}
+		opts.SampleEvery = sampleEvery
+	}
+	if value := c.Query("max_keys"); value != "" {
+		maxKeys, err := strconv.Atoi(value)
+		if err != nil {
+			AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(errors.New("invalid max_keys: must be an integer")))
+			return
+		}
+		if maxKeys < 1 || maxKeys > maxRedisMemoryMaxKeys {
+			AbortWithError(c, http.StatusBadRequest, NewErrBadRequest(errors.New("invalid max_keys: must be between 1 and "+strconv.Itoa(maxRedisMemoryMaxKeys))))
+			return
+		}
+		opts.MaxKeys = maxKeys
+	}
+
+	report, err := h.analyzer.Analyze(c.Request.Context(), opts)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	c.JSON(http.StatusOK, report)
+}
@@ -0,0 +1,79 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/redismemory"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_RedisMemory(t *testing.T) {
+	t.Run("reports memory by key family", func(t *testing.T) {
+		redisClient := testutil.CreateTestRedisClient(t)
+		require.NoError(t, redisClient.HSet(t.Context(), "tenant:{t1}:tenant", "id", "t1").Err())
+		require.NoError(t, redisClient.HSet(t.Context(), "tenant:{t1}:destination:d1", "id", "d1").Err())
+		h := newAPITest(t, withRedisMemory(redisClient))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/redis/memory?sample_every=1", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var report redismemory.Report
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &report))
+		assert.Equal(t, int64(2), report.ScannedKeys)
+		assert.Equal(t, int64(2), report.SampledKeys)
+		require.Len(t, report.Families, 2)
+		assert.Positive(t, report.EstimatedBytes)
+	})
+
+	t.Run("max_keys truncates the report", func(t *testing.T) {
+		redisClient := testutil.CreateTestRedisClient(t)
+		for _, key := range []string{"publishrate:{t1}", "publishrate:{t2}", "publishrate:{t3}"} {
+			require.NoError(t, redisClient.Set(t.Context(), key, "1", 0).Err())
+		}
+		h := newAPITest(t, withRedisMemory(redisClient))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/redis/memory?max_keys=2", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+		var report redismemory.Report
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &report))
+		assert.Equal(t, int64(2), report.ScannedKeys)
+		assert.True(t, report.Truncated)
+	})
+
+	t.Run("invalid params return 400", func(t *testing.T) {
+		h := newAPITest(t, withRedisMemory(testutil.CreateTestRedisClient(t)))
+
+		for _, query := range []string{"sample_every=0", "sample_every=x", "max_keys=0", "max_keys=x"} {
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/redis/memory?"+query, nil)
+			resp := h.do(h.withAPIKey(req))
+			assert.Equal(t, http.StatusBadRequest, resp.Code, query)
+		}
+	})
+
+	t.Run("not enabled returns 501", func(t *testing.T) {
+		h := newAPITest(t)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/redis/memory", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusNotImplemented, resp.Code)
+	})
+
+	t.Run("jwt returns 403", func(t *testing.T) {
+		h := newAPITest(t, withRedisMemory(testutil.CreateTestRedisClient(t)))
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/redis/memory", nil)
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
@@ -0,0 +1,308 @@
+package apirouter
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultReplayLimit    = 100
+	maxReplayLimit        = 500
+	maxReplayDestinations = 10
+	defaultReplayWindow   = 24 * time.Hour
+)
+
+var errNoReplayAttempt = errors.New("replay delivery made no attempt")
+
+// Changes a replay reports for an event and a staging destination.
+const (
+	ReplayNewlyMatched         = "newly_matched"
+	ReplayNoLongerMatched      = "no_longer_matched"
+	ReplayTransformationFailed = "transformation_failed"
+	ReplayDeliveryFailed       = "delivery_failed"
+)
+
+// ReplayRequest selects the production events of a tenant replayed against
+// staging destinations of the same tenant.
+type ReplayRequest struct {
+	TenantID     string              `json:"tenant_id" binding:"required"`
+	Destinations []ReplayDestination `json:"destinations" binding:"required"`
+	Topics       []string            `json:"topics"`
+	// Start and End bound the time of the sampled events, the last 24 hours
+	// by default.
+	Start *time.Time `json:"start"`
+	End   *time.Time `json:"end"`
+	// Limit is how many of the most recent events are sampled.
+	Limit int `json:"limit" binding:"min=0"`
+	// Deliver delivers the events each staging destination matches to it,
+	// redacted. Without it, nothing is sent.
+	Deliver bool `json:"deliver"`
+}
+
+// ReplayDestination is a staging destination, compared with the production
+// destination it would replace. CompareTo defaults to the production
+// destination the staging destination is the shadow of, if any.
+type ReplayDestination struct {
+	ID        string `json:"id" binding:"required"`
+	CompareTo string `json:"compare_to"`
+}
+
+// ReplayReport is how the staging destinations handled the sampled events,
+// and where they differ from production.
+type ReplayReport struct {
+	Events       int                        `json:"events"`
+	Destinations []ReplayDestinationSummary `json:"destinations"`
+	Differences  []ReplayDifference         `json:"differences"`
+}
+
+// ReplayDestinationSummary counts how a staging destination handled the
+// sampled events. The production counts are set when it is compared with a
+// production destination.
+type ReplayDestinationSummary struct {
+	ID                   string `json:"id"`
+	Type                 string `json:"type"`
+	CompareTo            string `json:"compare_to,omitempty"`
+	Matched              int    `json:"matched"`
+	ProductionMatched    int    `json:"production_matched"`
+	NewlyMatched         int    `json:"newly_matched"`
+	NoLongerMatched      int    `json:"no_longer_matched"`
+	TransformationFailed int    `json:"transformation_failed"`
+	Delivered            int    `json:"delivered"`
+	DeliveryFailed       int    `json:"delivery_failed"`
+}
+
+// ReplayDifference is an event a staging destination handles differently
+// from production, or failed to handle.
+type ReplayDifference struct {
+	EventID       string `json:"event_id"`
+	Topic         string `json:"topic"`
+	DestinationID string `json:"destination_id"`
+	Change        string `json:"change"`
+	Error         string `json:"error,omitempty"`
+}
+
+// Replay handles POST /tools/replay. It replays a sample of a tenant's
+// production events against staging destinations, to validate their topics,
+// filters and transformations before they are applied in production. Each
+// staging destination is matched against every event regardless of its
+// enabled state, its transformation is run on the matched events, and the
+// outcome is compared with the production destination it would replace.
+//
+// Matching and transformations run on the events as published; only
+// delivered events are redacted, keeping the shape of their data and the keys
+// of their metadata. Replays are not logged, retried or counted towards
+// disabling destinations.
+func (h *ToolHandlers) Replay(c *gin.Context) {
+	var req ReplayRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	if !mustBeValidReplay(c, &req) {
+		return
+	}
+
+	ctx := c.Request.Context()
+	destinations, err := h.tenantStore.ListDestination(ctx, tenantstore.ListDestinationRequest{TenantID: req.TenantID})
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	byID := make(map[string]*models.Destination, len(destinations))
+	for i := range destinations {
+		byID[destinations[i].ID] = &destinations[i]
+	}
+	type replayPair struct {
+		staging    *models.Destination
+		production *models.Destination
+	}
+	pairs := make([]replayPair, len(req.Destinations))
+	for i, d := range req.Destinations {
+		staging := byID[d.ID]
+		if staging == nil {
+			AbortWithValidationError(c, fmt.Errorf("destinations[%d].id must reference a destination of the tenant", i))
+			return
+		}
+		compareTo := d.CompareTo
+		if compareTo == "" {
+			for _, destination := range destinations {
+				if destination.ShadowDestinationID == staging.ID {
+					compareTo = destination.ID
+					break
+				}
+			}
+		}
+		pair := replayPair{staging: staging}
+		if compareTo != "" {
+			pair.production = byID[compareTo]
+			if pair.production == nil {
+				AbortWithValidationError(c, fmt.Errorf("destinations[%d].compare_to must reference a destination of the tenant", i))
+				return
+			}
+		}
+		pairs[i] = pair
+	}
+
+	end := time.Now()
+	if req.End != nil {
+		end = *req.End
+	}
+	start := end.Add(-defaultReplayWindow)
+	if req.Start != nil {
+		start = *req.Start
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = defaultReplayLimit
+	}
+	events, err := h.logStore.ListEvent(ctx, logstore.ListEventRequest{
+		Limit:      limit,
+		TimeFilter: logstore.TimeFilter{GTE: &start, LTE: &end},
+		TenantIDs:  []string{req.TenantID},
+		Topics:     h.topicNamespace.ApplyAll(req.Topics),
+	})
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+
+	report := ReplayReport{
+		Events:       len(events.Data),
+		Destinations: make([]ReplayDestinationSummary, len(pairs)),
+		Differences:  []ReplayDifference{},
+	}
+	for i, pair := range pairs {
+		summary := &report.Destinations[i]
+		summary.ID = pair.staging.ID
+		summary.Type = pair.staging.Type
+		if pair.production != nil {
+			summary.CompareTo = pair.production.ID
+		}
+		for _, event := range events.Data {
+			matchEvent := h.topicNamespace.StripEvent(*event)
+			matched := matchesRouting(pair.staging, &matchEvent)
+			difference := ReplayDifference{
+				EventID:       event.ID,
+				Topic:         matchEvent.Topic,
+				DestinationID: pair.staging.ID,
+			}
+			if pair.production != nil {
+				productionMatched := matchesRouting(pair.production, &matchEvent)
+				if productionMatched {
+					summary.ProductionMatched++
+				}
+				if matched && !productionMatched {
+					summary.NewlyMatched++
+					difference.Change = ReplayNewlyMatched
+					report.Differences = append(report.Differences, difference)
+				} else if !matched && productionMatched {
+					summary.NoLongerMatched++
+					difference.Change = ReplayNoLongerMatched
+					report.Differences = append(report.Differences, difference)
+				}
+			}
+			if !matched {
+				continue
+			}
+			summary.Matched++
+
+			transformed, err := pair.staging.Transformation.Apply(ctx, &matchEvent)
+			if err != nil {
+				summary.TransformationFailed++
+				difference.Change = ReplayTransformationFailed
+				difference.Error = err.Error()
+				report.Differences = append(report.Differences, difference)
+				continue
+			}
+			if !req.Deliver {
+				continue
+			}
+			if message, ok := h.deliverReplay(ctx, pair.staging, transformed); ok {
+				summary.Delivered++
+			} else {
+				summary.DeliveryFailed++
+				difference.Change = ReplayDeliveryFailed
+				difference.Error = message
+				report.Differences = append(report.Differences, difference)
+			}
+		}
+	}
+
+	h.logger.Ctx(ctx).Audit("events replayed against staging destinations",
+		zap.String("tenant_id", req.TenantID),
+		zap.Int("events", report.Events),
+		zap.Int("destinations", len(pairs)),
+		zap.Bool("deliver", req.Deliver))
+	c.JSON(http.StatusOK, report)
+}
+
+// deliverReplay delivers a transformed event, redacted, to a staging
+// destination. It returns why the delivery failed, if it did.
+func (h *ToolHandlers) deliverReplay(ctx context.Context, destination *models.Destination, event *models.Event) (string, bool) {
+	redacted := *event
+	redacted.Data = redactPayload(event.Data)
+	redacted.Metadata = make(map[string]string, len(event.Metadata))
+	for key := range event.Metadata {
+		redacted.Metadata[key] = SensitiveFieldMask
+	}
+	// The event is already transformed.
+	staging := *destination
+	staging.Transformation = nil
+
+	attempt, err := h.registry.PublishEvent(ctx, &staging, &redacted)
+	if attempt == nil {
+		if err == nil {
+			err = errNoReplayAttempt
+		}
+		return err.Error(), false
+	}
+	if attempt.Status != models.AttemptStatusSuccess {
+		return attempt.ErrorMessage, false
+	}
+	return "", true
+}
+
+// matchesRouting reports whether the destination's topics and filter match
+// the event, whatever its enabled state.
+func matchesRouting(destination *models.Destination, event *models.Event) bool {
+	if event.Topic != "" && !destination.Topics.MatchTopic(event.Topic) {
+		return false
+	}
+	return models.MatchFilter(destination.Filter, *event)
+}
+
+// mustBeValidReplay rejects replays of too many events or destinations, or
+// with an empty time range.
+func mustBeValidReplay(c *gin.Context, req *ReplayRequest) bool {
+	var errs []string
+	if len(req.Destinations) == 0 {
+		errs = append(errs, "destinations must not be empty")
+	}
+	if len(req.Destinations) > maxReplayDestinations {
+		errs = append(errs, fmt.Sprintf("destinations must have at most %d destinations", maxReplayDestinations))
+	}
+	if req.Limit > maxReplayLimit {
+		errs = append(errs, fmt.Sprintf("limit must be at most %d", maxReplayLimit))
+	}
+	if req.Start != nil && req.End != nil && !req.Start.Before(*req.End) {
+		errs = append(errs, "start must be before end")
+	}
+	if len(errs) > 0 {
+		AbortWithValidationError(c, ErrorResponse{
+			Code:    http.StatusUnprocessableEntity,
+			Message: "validation error",
+			Data:    errs,
+		})
+		return false
+	}
+	return true
+}
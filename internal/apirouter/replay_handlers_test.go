@@ -0,0 +1,173 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_Replay(t *testing.T) {
+	type received struct {
+		mu     sync.Mutex
+		bodies []string
+	}
+	setup := func(t *testing.T) (*apiTest, *received) {
+		t.Helper()
+		r := &received{}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			body, _ := io.ReadAll(req.Body)
+			r.mu.Lock()
+			r.bodies = append(r.bodies, string(body))
+			r.mu.Unlock()
+		}))
+		t.Cleanup(server.Close)
+
+		h := newAPITest(t, withDestRegistry(webhookStandardRegistry(t)))
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(
+			df.WithID("prod"),
+			df.WithTenantID("t1"),
+			df.WithTopics([]string{"order.created"}),
+		))
+		staging := df.Any(
+			df.WithID("staging"),
+			df.WithTenantID("t1"),
+			df.WithType("webhook"),
+			df.WithTopics([]string{"order.created"}),
+			df.WithFilter(models.Filter{"data": map[string]any{"tier": "gold"}}),
+			df.WithConfig(map[string]string{"url": server.URL + "/webhook"}),
+			df.WithCredentials(map[string]string{"secret": "whsec_dGVzdHNlY3JldDEyMzQ1Njc4OTBhYmNkZWY="}),
+			df.WithDisabledAt(time.Now()),
+		)
+		staging.Transformation = &models.Transformation{Type: models.TransformationJQ, Expression: `{order: .id}`}
+		h.tenantStore.CreateDestination(t.Context(), staging)
+
+		now := time.Now()
+		for i, tier := range []string{"gold", "silver"} {
+			event := ef.AnyPointer(
+				ef.WithID([]string{"gold_order", "silver_order"}[i]),
+				ef.WithTenantID("t1"),
+				ef.WithTopic("order.created"),
+				ef.WithTime(now.Add(-time.Duration(i+1)*time.Minute)),
+				ef.WithDataMap(map[string]any{"id": "ord_" + tier, "tier": tier}),
+			)
+			require.NoError(t, h.logStore.InsertMany(t.Context(), []*models.LogEntry{
+				{Event: event, Attempt: attemptForEvent(event)},
+			}))
+		}
+		return h, r
+	}
+
+	replay := func(t *testing.T, h *apiTest, body map[string]any) apirouter.ReplayReport {
+		t.Helper()
+		req := h.jsonReq(http.MethodPost, "/api/v1/tools/replay", body)
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code, resp.Body.String())
+		var report apirouter.ReplayReport
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &report))
+		return report
+	}
+
+	t.Run("reports matching differences without delivering", func(t *testing.T) {
+		h, r := setup(t)
+
+		report := replay(t, h, map[string]any{
+			"tenant_id":    "t1",
+			"destinations": []any{map[string]any{"id": "staging", "compare_to": "prod"}},
+		})
+
+		assert.Equal(t, 2, report.Events)
+		require.Len(t, report.Destinations, 1)
+		summary := report.Destinations[0]
+		assert.Equal(t, "prod", summary.CompareTo)
+		assert.Equal(t, 1, summary.Matched)
+		assert.Equal(t, 2, summary.ProductionMatched)
+		assert.Equal(t, 1, summary.NoLongerMatched)
+		assert.Zero(t, summary.Delivered)
+		require.Len(t, report.Differences, 1)
+		assert.Equal(t, "silver_order", report.Differences[0].EventID)
+		assert.Equal(t, apirouter.ReplayNoLongerMatched, report.Differences[0].Change)
+		assert.Empty(t, r.bodies)
+	})
+
+	t.Run("compares with the destination it shadows", func(t *testing.T) {
+		h, _ := setup(t)
+		prod, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", "prod")
+		require.NoError(t, err)
+		prod.ShadowDestinationID = "staging"
+		require.NoError(t, h.tenantStore.UpsertDestination(t.Context(), *prod))
+
+		report := replay(t, h, map[string]any{
+			"tenant_id":    "t1",
+			"destinations": []any{map[string]any{"id": "staging"}},
+		})
+
+		assert.Equal(t, "prod", report.Destinations[0].CompareTo)
+	})
+
+	t.Run("delivers matched events transformed and redacted", func(t *testing.T) {
+		h, r := setup(t)
+
+		report := replay(t, h, map[string]any{
+			"tenant_id":    "t1",
+			"destinations": []any{map[string]any{"id": "staging"}},
+			"deliver":      true,
+		})
+
+		assert.Equal(t, 1, report.Destinations[0].Delivered)
+		assert.Empty(t, report.Differences)
+		require.Len(t, r.bodies, 1)
+		assert.JSONEq(t, `{"order":"[REDACTED]"}`, r.bodies[0])
+	})
+
+	t.Run("reports transformation failures", func(t *testing.T) {
+		h, _ := setup(t)
+		staging, err := h.tenantStore.RetrieveDestination(t.Context(), "t1", "staging")
+		require.NoError(t, err)
+		staging.Transformation = &models.Transformation{Type: models.TransformationJQ, Expression: `error("boom")`}
+		require.NoError(t, h.tenantStore.UpsertDestination(t.Context(), *staging))
+
+		report := replay(t, h, map[string]any{
+			"tenant_id":    "t1",
+			"destinations": []any{map[string]any{"id": "staging"}},
+		})
+
+		assert.Equal(t, 1, report.Destinations[0].TransformationFailed)
+		require.Len(t, report.Differences, 1)
+		assert.Equal(t, apirouter.ReplayTransformationFailed, report.Differences[0].Change)
+		assert.NotEmpty(t, report.Differences[0].Error)
+	})
+
+	t.Run("unknown destination returns 422", func(t *testing.T) {
+		h, _ := setup(t)
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tools/replay", map[string]any{
+			"tenant_id":    "t1",
+			"destinations": []any{map[string]any{"id": "missing"}},
+		})
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	t.Run("jwt returns 403", func(t *testing.T) {
+		h, _ := setup(t)
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tools/replay", map[string]any{
+			"tenant_id":    "t1",
+			"destinations": []any{map[string]any{"id": "staging"}},
+		})
+		resp := h.do(h.withJWT(req, "t1"))
+
+		require.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
@@ -21,6 +21,7 @@ type RetryHandlers struct {
 	tenantStore       tenantstore.TenantStore
 	logStore          logstore.LogStore
 	deliveryPublisher deliveryPublisher
+	topicNamespace    models.TopicNamespace
 }
 
 func NewRetryHandlers(
@@ -28,12 +29,14 @@ func NewRetryHandlers(
 	tenantStore tenantstore.TenantStore,
 	logStore logstore.LogStore,
 	deliveryPublisher deliveryPublisher,
+	topicNamespace models.TopicNamespace,
 ) *RetryHandlers {
 	return &RetryHandlers{
 		logger:            logger,
 		tenantStore:       tenantStore,
 		logStore:          logStore,
 		deliveryPublisher: deliveryPublisher,
+		topicNamespace:    topicNamespace,
 	}
 }
 
@@ -86,6 +89,22 @@ func (h *RetryHandlers) Retry(c *gin.Context) {
 		}
 	}
 
+	// Replaying data that no longer matches its checksum would deliver
+	// something the producer never published.
+	if err := event.VerifyChecksum(); err != nil {
+		h.logger.Ctx(c.Request.Context()).Error("event data does not match its checksum",
+			zap.String("event_id", event.ID),
+			zap.String("tenant_id", event.TenantID))
+		AbortWithError(c, http.StatusConflict, ErrorResponse{
+			Code:    http.StatusConflict,
+			Message: "event data does not match its checksum",
+			Data: map[string]string{
+				"error": "checksum_mismatch",
+			},
+		})
+		return
+	}
+
 	// 2. Check destination exists and is enabled
 	destination, err := h.tenantStore.RetrieveDestination(c.Request.Context(), event.TenantID, req.DestinationID)
 	if err != nil {
@@ -107,7 +126,7 @@ func (h *RetryHandlers) Retry(c *gin.Context) {
 		return
 	}
 
-	if !destination.MatchEvent(*event) {
+	if !destination.MatchEvent(h.topicNamespace.StripEvent(*event)) {
 		AbortWithError(c, http.StatusBadRequest, ErrorResponse{
 			Code:    http.StatusBadRequest,
 			Message: "destination does not match event",
@@ -14,6 +14,7 @@ import (
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/models"
 	"github.com/hookdeck/outpost/internal/portal"
 	"github.com/hookdeck/outpost/internal/telemetry"
 	"github.com/hookdeck/outpost/internal/tenantstore"
@@ -26,7 +27,10 @@ type RouteDefinition struct {
 	Handler       gin.HandlerFunc
 	AdminOnly     bool
 	RequireTenant bool
-	Middlewares   []gin.HandlerFunc
+	// Public routes skip authentication; the handler authenticates the
+	// request itself.
+	Public      bool
+	Middlewares []gin.HandlerFunc
 }
 
 type RouterConfig struct {
@@ -36,9 +40,31 @@ type RouterConfig struct {
 	DeploymentID         string
 	Topics               []string
 	TopicsAllowWildcards bool
+	TopicLifecycle       models.TopicLifecycle
 	Registry             destregistry.Registry
 	PortalConfig         portal.PortalConfig
-	GinMode              string
+	// TopicNamespace is applied to the topics of stored events; topic filters
+	// and subscriptions use topics without it.
+	TopicNamespace models.TopicNamespace
+	// MaxDestinationsPerTenant and QuotaWarningPercent drive the advisory
+	// quota headers and tenant.quota.warning events; the limit itself is
+	// enforced by the tenant store.
+	MaxDestinationsPerTenant int
+	QuotaWarningPercent      int
+	// MaxEventsPerMinutePerTenant limits the events a tenant can publish per
+	// minute, counted by RouterDeps.EventRates.
+	MaxEventsPerMinutePerTenant int
+	GinMode                     string
+	// TrustedProxies are the proxies whose X-Forwarded-For header is trusted
+	// for the client IP. When empty, the header is not trusted from any
+	// address.
+	TrustedProxies []string
+	// Middlewares run on every /api/v1 request before authentication, for
+	// checks and logging added by programs embedding Outpost.
+	Middlewares []gin.HandlerFunc
+	// Routes are extra endpoints registered under /api/v1 alongside the
+	// built-in ones, with the same authentication.
+	Routes []RouteDefinition
 }
 
 type RouterDeps struct {
@@ -49,6 +75,20 @@ type RouterDeps struct {
 	EventHandler        eventHandler
 	Telemetry           telemetry.Telemetry
 	SubscriptionEmitter SubscriptionEmitter // optional — emits tenant.subscription.updated on destination mutations
+	DeliveryAcks        deliveryAckStore    // optional — with RetryCanceler, enables delivery acknowledgments
+	RetryCanceler       retryCanceler       // optional — cancels the retry an acknowledgment confirms
+	Lifecycle           ackNotifier         // optional — notifies producers of acknowledged deliveries
+	BulkRetries         bulkRetryJobs       // optional — enables bulk retry jobs
+	Payloads            payloadStore        // optional — serves payloads offloaded for exceeding a destination's size limit
+	EventRates          eventRateCounter    // optional — with MaxEventsPerMinutePerTenant, enforces the event quota
+	PublishRateLimiter  publishRateLimiter  // optional — enforces the tenant publish rate limit
+	PublishKeys         publishKeys         // optional — deduplicates publishes by idempotency key
+	RedisMemory         redisMemoryAnalyzer // optional — reports Redis memory by key family
+	TopicStore          topicStore          // optional — manages topics and their lifecycle at runtime alongside RouterConfig.Topics and RouterConfig.TopicLifecycle
+	LegalHolds          legalHoldStore      // optional — exempts delivery logs from retention pruning
+	DestinationHealth   destHealthStore     // optional — reports delivery health on destinations
+	TopicStats          topicStatsReporter  // optional — reports topic volume and payload sizes
+	HeldMessages        heldMessageStore    // optional — manages messages held across destination target changes
 }
 
 func (d RouterDeps) validate() error {
@@ -74,20 +114,23 @@ func (d RouterDeps) validate() error {
 }
 
 // registerRoutes registers routes to the given router based on route definitions and config
-func registerRoutes(router *gin.RouterGroup, cfg RouterConfig, tenantRetriever TenantRetriever, routes []RouteDefinition) {
+func registerRoutes(router *gin.RouterGroup, cfg RouterConfig, tenantRetriever TenantRetriever, logger *logging.Logger, routes []RouteDefinition) {
 	for _, route := range routes {
-		handlers := buildMiddlewareChain(cfg, tenantRetriever, route)
+		handlers := buildMiddlewareChain(cfg, tenantRetriever, logger, route)
 		router.Handle(route.Method, route.Path, handlers...)
 	}
 }
 
-func buildMiddlewareChain(cfg RouterConfig, tenantRetriever TenantRetriever, def RouteDefinition) []gin.HandlerFunc {
+func buildMiddlewareChain(cfg RouterConfig, tenantRetriever TenantRetriever, logger *logging.Logger, def RouteDefinition) []gin.HandlerFunc {
 	chain := make([]gin.HandlerFunc, 0)
 
-	chain = append(chain, AuthMiddleware(cfg.APIKey, cfg.JWTSecret, tenantRetriever, AuthOptions{
-		AdminOnly:     def.AdminOnly,
-		RequireTenant: def.RequireTenant,
-	}))
+	if !def.Public {
+		chain = append(chain, AuthMiddleware(cfg.APIKey, cfg.JWTSecret, tenantRetriever, AuthOptions{
+			AdminOnly:     def.AdminOnly,
+			RequireTenant: def.RequireTenant,
+			Logger:        logger,
+		}))
+	}
 
 	// Add custom middlewares
 	chain = append(chain, def.Middlewares...)
@@ -109,6 +152,11 @@ func NewRouter(cfg RouterConfig, deps RouterDeps) http.Handler {
 	}
 
 	r := gin.New()
+	// With no trusted proxies, the client IP is the connection's remote
+	// address and X-Forwarded-For is ignored.
+	if err := r.SetTrustedProxies(cfg.TrustedProxies); err != nil {
+		panic(err)
+	}
 	// Core middlewares
 	r.Use(gin.Recovery())
 	r.Use(deps.Telemetry.MakeSentryHandler())
@@ -136,48 +184,106 @@ func NewRouter(cfg RouterConfig, deps RouterDeps) http.Handler {
 
 	portal.AddRoutes(r, cfg.PortalConfig)
 
-	apiRouter := r.Group("/api/v1")
+	apiRouter := r.Group("/api/v1", cfg.Middlewares...)
 
 	displayer := newDestinationDisplayer(cfg.Registry)
 
-	tenantHandlers := NewTenantHandlers(deps.Logger, deps.Telemetry, cfg.JWTSecret, cfg.DeploymentID, deps.TenantStore)
-	destinationHandlers := NewDestinationHandlers(deps.Logger, deps.Telemetry, deps.TenantStore, deps.SubscriptionEmitter, cfg.Topics, cfg.TopicsAllowWildcards, cfg.Registry, displayer)
-	publishHandlers := NewPublishHandlers(deps.Logger, deps.EventHandler)
-	logHandlers := NewLogHandlers(deps.Logger, deps.LogStore, deps.TenantStore, displayer)
-	retryHandlers := NewRetryHandlers(deps.Logger, deps.TenantStore, deps.LogStore, deps.DeliveryPublisher)
-	topicHandlers := NewTopicHandlers(deps.Logger, cfg.Topics)
+	var topics topicLister = staticTopics(cfg.Topics)
+	var topicLifecycle models.TopicLifecycleSource = cfg.TopicLifecycle
+	if deps.TopicStore != nil {
+		topics = deps.TopicStore
+		topicLifecycle = deps.TopicStore
+	}
+
+	tenantHandlers := NewTenantHandlers(deps.Logger, deps.Telemetry, cfg.JWTSecret, cfg.DeploymentID, deps.TenantStore, cfg.Registry)
+	destinationHandlers := NewDestinationHandlers(deps.Logger, deps.Telemetry, deps.TenantStore, deps.SubscriptionEmitter, topics, cfg.TopicsAllowWildcards, topicLifecycle, cfg.Registry, displayer, destinationQuota(cfg.MaxDestinationsPerTenant, cfg.QuotaWarningPercent), deps.DestinationHealth)
+	publishHandlers := NewPublishHandlers(deps.Logger, deps.EventHandler, deps.EventRates, deps.PublishRateLimiter, deps.PublishKeys, deps.SubscriptionEmitter, eventQuota(cfg.MaxEventsPerMinutePerTenant, cfg.QuotaWarningPercent))
+	logHandlers := NewLogHandlers(deps.Logger, deps.LogStore, deps.TenantStore, displayer, cfg.TopicNamespace)
+	retryHandlers := NewRetryHandlers(deps.Logger, deps.TenantStore, deps.LogStore, deps.DeliveryPublisher, cfg.TopicNamespace)
+	topicHandlers := NewTopicHandlers(deps.Logger, topics, deps.TopicStore, topicLifecycle)
 	metricsHandlers := NewMetricsHandlers(deps.Logger, deps.LogStore)
+	logStoreHandlers := NewLogStoreHandlers(deps.Logger, deps.LogStore)
+	redisHandlers := NewRedisHandlers(deps.Logger, deps.RedisMemory)
+	analyticsHandlers := NewAnalyticsHandlers(deps.Logger, deps.TopicStats)
+	toolHandlers := NewToolHandlers(deps.Logger, deps.TenantStore, deps.LogStore, cfg.Registry, cfg.TopicNamespace)
+	ackHandlers := NewAckHandlers(deps.Logger, deps.DeliveryAcks, deps.RetryCanceler, deps.Lifecycle)
+	payloadHandlers := NewPayloadHandlers(deps.Logger, deps.Payloads)
+	bulkRetryHandlers := NewBulkRetryHandlers(deps.Logger, deps.BulkRetries)
+	legalHoldHandlers := NewLegalHoldHandlers(deps.Logger, deps.LegalHolds)
+	heldMessageHandlers := NewHeldMessageHandlers(deps.Logger, destinationHandlers, deps.HeldMessages, deps.DeliveryPublisher)
+	importHandlers := NewImportHandlers(deps.Logger, deps.Telemetry, deps.TenantStore, destinationHandlers)
 
 	routes := []RouteDefinition{
 		// Schemas & Topics
 		{Method: http.MethodGet, Path: "/destination-types", Handler: destinationHandlers.ListProviderMetadata},
 		{Method: http.MethodGet, Path: "/destination-types/:type", Handler: destinationHandlers.RetrieveProviderMetadata},
+		{Method: http.MethodGet, Path: "/destination-types/:type/schema", Handler: destinationHandlers.RetrieveProviderSchema},
 		{Method: http.MethodGet, Path: "/topics", Handler: topicHandlers.List},
+		{Method: http.MethodGet, Path: "/topics/status", Handler: topicHandlers.ListStatus},
+		{Method: http.MethodPost, Path: "/topics", Handler: topicHandlers.Create, AdminOnly: true},
+		{Method: http.MethodDelete, Path: "/topics/:topic", Handler: topicHandlers.Delete, AdminOnly: true},
+		{Method: http.MethodPut, Path: "/topics/:topic/status", Handler: topicHandlers.UpdateStatus, AdminOnly: true},
 
 		// Publish / Retry
 		{Method: http.MethodPost, Path: "/publish", Handler: publishHandlers.Ingest, AdminOnly: true},
+		{Method: http.MethodPost, Path: "/publish/group", Handler: publishHandlers.IngestGroup, AdminOnly: true},
 		{Method: http.MethodPost, Path: "/retry", Handler: retryHandlers.Retry},
+		{Method: http.MethodPost, Path: "/ack/:token", Handler: ackHandlers.Ack, Public: true},
+		{Method: http.MethodGet, Path: "/payloads/:token", Handler: payloadHandlers.Retrieve, Public: true},
 
 		// Tenants
 		{Method: http.MethodGet, Path: "/tenants", Handler: tenantHandlers.List},
+		{Method: http.MethodPost, Path: "/tenants/import", Handler: importHandlers.Import, AdminOnly: true},
 		{Method: http.MethodPut, Path: "/tenants/:tenant_id", Handler: tenantHandlers.Upsert},
 		{Method: http.MethodGet, Path: "/tenants/:tenant_id", Handler: tenantHandlers.Retrieve, RequireTenant: true},
 		{Method: http.MethodDelete, Path: "/tenants/:tenant_id", Handler: tenantHandlers.Delete, RequireTenant: true},
+		{Method: http.MethodPost, Path: "/tenants/:tenant_id/suspend", Handler: tenantHandlers.Suspend, AdminOnly: true, RequireTenant: true},
 		{Method: http.MethodGet, Path: "/tenants/:tenant_id/token", Handler: tenantHandlers.RetrieveToken, AdminOnly: true, RequireTenant: true},
 		{Method: http.MethodGet, Path: "/tenants/:tenant_id/portal", Handler: tenantHandlers.RetrievePortal, AdminOnly: true, RequireTenant: true},
+		{Method: http.MethodGet, Path: "/tenants/:tenant_id/notifications", Handler: tenantHandlers.RetrieveNotifications, RequireTenant: true},
+		{Method: http.MethodPut, Path: "/tenants/:tenant_id/notifications", Handler: tenantHandlers.UpdateNotifications, RequireTenant: true},
+		{Method: http.MethodDelete, Path: "/tenants/:tenant_id/notifications", Handler: tenantHandlers.DeleteNotifications, RequireTenant: true},
+		{Method: http.MethodGet, Path: "/tenants/:tenant_id/destination-types", Handler: destinationHandlers.ListProviderMetadata, RequireTenant: true},
+		{Method: http.MethodGet, Path: "/tenants/:tenant_id/lifecycle-callback", Handler: tenantHandlers.RetrieveLifecycleCallback, AdminOnly: true, RequireTenant: true},
+		{Method: http.MethodPut, Path: "/tenants/:tenant_id/lifecycle-callback", Handler: tenantHandlers.UpdateLifecycleCallback, AdminOnly: true, RequireTenant: true},
+		{Method: http.MethodDelete, Path: "/tenants/:tenant_id/lifecycle-callback", Handler: tenantHandlers.DeleteLifecycleCallback, AdminOnly: true, RequireTenant: true},
+		{Method: http.MethodGet, Path: "/tenants/:tenant_id/alert-channels", Handler: tenantHandlers.RetrieveAlertChannels, AdminOnly: true, RequireTenant: true},
+		{Method: http.MethodPut, Path: "/tenants/:tenant_id/alert-channels", Handler: tenantHandlers.UpdateAlertChannels, AdminOnly: true, RequireTenant: true},
+		{Method: http.MethodDelete, Path: "/tenants/:tenant_id/alert-channels", Handler: tenantHandlers.DeleteAlertChannels, AdminOnly: true, RequireTenant: true},
+		{Method: http.MethodGet, Path: "/tenants/:tenant_id/auto-disable-policy", Handler: tenantHandlers.RetrieveAutoDisablePolicy, AdminOnly: true, RequireTenant: true},
+		{Method: http.MethodPut, Path: "/tenants/:tenant_id/auto-disable-policy", Handler: tenantHandlers.UpdateAutoDisablePolicy, AdminOnly: true, RequireTenant: true},
+		{Method: http.MethodDelete, Path: "/tenants/:tenant_id/auto-disable-policy", Handler: tenantHandlers.DeleteAutoDisablePolicy, AdminOnly: true, RequireTenant: true},
+		{Method: http.MethodGet, Path: "/tenants/:tenant_id/legal-holds", Handler: legalHoldHandlers.List, AdminOnly: true, RequireTenant: true},
+		{Method: http.MethodPost, Path: "/tenants/:tenant_id/legal-holds", Handler: legalHoldHandlers.Place, AdminOnly: true, RequireTenant: true},
+		{Method: http.MethodDelete, Path: "/tenants/:tenant_id/legal-holds/:hold_id", Handler: legalHoldHandlers.Release, AdminOnly: true, RequireTenant: true},
 
 		// Destinations
 		{Method: http.MethodGet, Path: "/tenants/:tenant_id/destinations", Handler: destinationHandlers.List, RequireTenant: true},
 		{Method: http.MethodPost, Path: "/tenants/:tenant_id/destinations", Handler: destinationHandlers.Create, RequireTenant: true},
+		{Method: http.MethodPost, Path: "/tenants/:tenant_id/destinations/import", Handler: destinationHandlers.Import, RequireTenant: true},
 		{Method: http.MethodGet, Path: "/tenants/:tenant_id/destinations/:destination_id", Handler: destinationHandlers.Retrieve, RequireTenant: true},
 		{Method: http.MethodPatch, Path: "/tenants/:tenant_id/destinations/:destination_id", Handler: destinationHandlers.Update, RequireTenant: true},
 		{Method: http.MethodDelete, Path: "/tenants/:tenant_id/destinations/:destination_id", Handler: destinationHandlers.Delete, RequireTenant: true},
 		{Method: http.MethodPut, Path: "/tenants/:tenant_id/destinations/:destination_id/enable", Handler: destinationHandlers.Enable, RequireTenant: true},
 		{Method: http.MethodPut, Path: "/tenants/:tenant_id/destinations/:destination_id/disable", Handler: destinationHandlers.Disable, RequireTenant: true},
+		{Method: http.MethodGet, Path: "/tenants/:tenant_id/destinations/:destination_id/versions", Handler: destinationHandlers.ListVersions, RequireTenant: true},
+		{Method: http.MethodGet, Path: "/tenants/:tenant_id/destinations/:destination_id/versions/:version", Handler: destinationHandlers.RetrieveVersion, RequireTenant: true},
+		{Method: http.MethodPost, Path: "/tenants/:tenant_id/destinations/:destination_id/versions/:version/rollback", Handler: destinationHandlers.RollbackVersion, RequireTenant: true},
+		{Method: http.MethodPut, Path: "/tenants/:tenant_id/destinations/:destination_id/recording", Handler: destinationHandlers.StartRecording, AdminOnly: true, RequireTenant: true},
+		{Method: http.MethodDelete, Path: "/tenants/:tenant_id/destinations/:destination_id/recording", Handler: destinationHandlers.StopRecording, AdminOnly: true, RequireTenant: true},
+		{Method: http.MethodPost, Path: "/tenants/:tenant_id/destinations/:destination_id/diagnose", Handler: destinationHandlers.Diagnose, RequireTenant: true},
+		{Method: http.MethodPost, Path: "/tenants/:tenant_id/destinations/:destination_id/test", Handler: destinationHandlers.SendTestEvent, RequireTenant: true},
+		{Method: http.MethodGet, Path: "/tenants/:tenant_id/destinations/:destination_id/held-messages", Handler: heldMessageHandlers.List, RequireTenant: true},
+		{Method: http.MethodPost, Path: "/tenants/:tenant_id/destinations/:destination_id/held-messages/release", Handler: heldMessageHandlers.Release, RequireTenant: true},
+		{Method: http.MethodPost, Path: "/tenants/:tenant_id/destinations/:destination_id/held-messages/discard", Handler: heldMessageHandlers.Discard, RequireTenant: true},
 		{Method: http.MethodGet, Path: "/tenants/:tenant_id/destinations/:destination_id/attempts", Handler: logHandlers.ListDestinationAttempts, RequireTenant: true},
 		{Method: http.MethodGet, Path: "/tenants/:tenant_id/destinations/:destination_id/attempts/:attempt_id", Handler: logHandlers.RetrieveAttempt, RequireTenant: true},
 
 		// Events
+		{Method: http.MethodPost, Path: "/tenants/:tenant_id/events/preview", Handler: publishHandlers.Preview, RequireTenant: true},
+		{Method: http.MethodPost, Path: "/tenants/:tenant_id/events/retry", Handler: bulkRetryHandlers.Start, AdminOnly: true, RequireTenant: true},
+		{Method: http.MethodGet, Path: "/tenants/:tenant_id/events/retry/:job_id", Handler: bulkRetryHandlers.Retrieve, RequireTenant: true},
+		{Method: http.MethodGet, Path: "/tenants/:tenant_id/events/:event_id/attempts/:attempt_id", Handler: logHandlers.RetrieveAttempt, RequireTenant: true},
 		{Method: http.MethodGet, Path: "/events", Handler: logHandlers.ListEvents},
 		{Method: http.MethodGet, Path: "/events/:event_id", Handler: logHandlers.RetrieveEvent},
 
@@ -188,9 +294,26 @@ func NewRouter(cfg RouterConfig, deps RouterDeps) http.Handler {
 		// Metrics
 		{Method: http.MethodGet, Path: "/metrics/events", Handler: metricsHandlers.MetricsEvents},
 		{Method: http.MethodGet, Path: "/metrics/attempts", Handler: metricsHandlers.MetricsAttempts},
+		{Method: http.MethodGet, Path: "/tenants/:tenant_id/metrics/deliveries", Handler: metricsHandlers.MetricsDeliveries, RequireTenant: true},
+
+		// Log Store
+		{Method: http.MethodGet, Path: "/logstore/stats", Handler: logStoreHandlers.Stats, AdminOnly: true},
+		{Method: http.MethodPost, Path: "/logstore/flush", Handler: logStoreHandlers.Flush, AdminOnly: true},
+
+		// Redis
+		{Method: http.MethodGet, Path: "/redis/memory", Handler: redisHandlers.Memory, AdminOnly: true},
+
+		// Analytics
+		{Method: http.MethodGet, Path: "/analytics/topics", Handler: analyticsHandlers.Topics, AdminOnly: true},
+		{Method: http.MethodGet, Path: "/tenants/:tenant_id/analytics/topics", Handler: analyticsHandlers.Topics, AdminOnly: true, RequireTenant: true},
+
+		// Tools
+		{Method: http.MethodPost, Path: "/tools/verify-signature", Handler: toolHandlers.VerifySignature},
+		{Method: http.MethodPost, Path: "/tools/replay", Handler: toolHandlers.Replay, AdminOnly: true},
 	}
+	routes = append(routes, cfg.Routes...)
 
-	registerRoutes(apiRouter, cfg, deps.TenantStore, routes)
+	registerRoutes(apiRouter, cfg, deps.TenantStore, deps.Logger, routes)
 
 	// Register dev routes
 	if gin.Mode() == gin.DebugMode {
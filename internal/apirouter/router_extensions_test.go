@@ -0,0 +1,63 @@
+package apirouter_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_Extensions(t *testing.T) {
+	requireHeader := func(c *gin.Context) {
+		if c.GetHeader("X-Company-Check") != "ok" {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+		c.Next()
+	}
+	routes := []apirouter.RouteDefinition{
+		{Method: http.MethodGet, Path: "/custom/ping", Handler: func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{"pong": true})
+		}, AdminOnly: true},
+	}
+	setup := func(t *testing.T) *apiTest {
+		return newAPITest(t, withExtensions([]gin.HandlerFunc{requireHeader}, routes))
+	}
+
+	t.Run("middleware runs on built-in routes", func(t *testing.T) {
+		h := setup(t)
+
+		resp := h.do(h.withAPIKey(h.jsonReq(http.MethodGet, "/api/v1/topics", nil)))
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+
+		req := h.withAPIKey(h.jsonReq(http.MethodGet, "/api/v1/topics", nil))
+		req.Header.Set("X-Company-Check", "ok")
+		resp = h.do(req)
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("custom route is served with authentication", func(t *testing.T) {
+		h := setup(t)
+
+		req := h.jsonReq(http.MethodGet, "/api/v1/custom/ping", nil)
+		req.Header.Set("X-Company-Check", "ok")
+		resp := h.do(req)
+		assert.Equal(t, http.StatusUnauthorized, resp.Code)
+
+		req = h.withAPIKey(h.jsonReq(http.MethodGet, "/api/v1/custom/ping", nil))
+		req.Header.Set("X-Company-Check", "ok")
+		resp = h.do(req)
+		require.Equal(t, http.StatusOK, resp.Code)
+		assert.JSONEq(t, `{"pong":true}`, resp.Body.String())
+	})
+
+	t.Run("middleware runs on custom routes", func(t *testing.T) {
+		h := setup(t)
+
+		resp := h.do(h.withAPIKey(h.jsonReq(http.MethodGet, "/api/v1/custom/ping", nil)))
+		assert.Equal(t, http.StatusForbidden, resp.Code)
+	})
+}
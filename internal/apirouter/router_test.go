@@ -3,24 +3,40 @@ package apirouter_test
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/bulkretry"
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/deliveryack"
+	"github.com/hookdeck/outpost/internal/deliveryhold"
+	"github.com/hookdeck/outpost/internal/desthealth"
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/destregistry/metadata"
+	"github.com/hookdeck/outpost/internal/eventrate"
+	"github.com/hookdeck/outpost/internal/legalhold"
 	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/logstore"
 	"github.com/hookdeck/outpost/internal/models"
 	"github.com/hookdeck/outpost/internal/opevents"
+	"github.com/hookdeck/outpost/internal/payloadoffload"
 	"github.com/hookdeck/outpost/internal/portal"
+	"github.com/hookdeck/outpost/internal/publishkey"
 	"github.com/hookdeck/outpost/internal/publishmq"
+	"github.com/hookdeck/outpost/internal/publishrate"
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/redismemory"
 	"github.com/hookdeck/outpost/internal/telemetry"
 	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/topicstats"
+	"github.com/hookdeck/outpost/internal/topicstore"
 	"github.com/hookdeck/outpost/internal/util/testutil"
 	"go.uber.org/zap"
 )
@@ -59,10 +75,33 @@ type apiTestOption func(*apiTestConfig)
 
 type apiTestConfig struct {
 	tenantStore          tenantstore.TenantStore
+	logStore             logstore.LogStore
 	destRegistry         destregistry.Registry
 	subscriptionEmitter  apirouter.SubscriptionEmitter
 	logger               *logging.Logger
 	topicsAllowWildcards bool
+	topicLifecycle       models.TopicLifecycle
+	maxDestinations      int
+	maxEventsPerMinute   int
+	publishRateLimit     *models.PublishRateLimit
+	publishKeys          bool
+	bulkRetries          bool
+	redisMemory          redis.Cmdable
+	topicStore           bool
+	legalHolds           []legalhold.Option
+	destinationHealth    *desthealth.Store
+	topicStats           *topicstats.Store
+	heldMessages         *deliveryhold.Store
+	quotaWarningPercent  int
+	deliveryAcks         deliveryack.Store
+	ackNotifier          *mockAckNotifier
+	payloads             payloadoffload.Store
+	trustedProxies       []string
+	middlewares          []gin.HandlerFunc
+	routes               []apirouter.RouteDefinition
+	retryCanceler        interface {
+		Cancel(ctx context.Context, taskID string) error
+	}
 }
 
 func withTenantStore(ts tenantstore.TenantStore) apiTestOption {
@@ -71,6 +110,12 @@ func withTenantStore(ts tenantstore.TenantStore) apiTestOption {
 	}
 }
 
+func withLogStore(ls logstore.LogStore) apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.logStore = ls
+	}
+}
+
 func withDestRegistry(r destregistry.Registry) apiTestOption {
 	return func(cfg *apiTestConfig) {
 		cfg.destRegistry = r
@@ -89,12 +134,129 @@ func withLogger(l *logging.Logger) apiTestOption {
 	}
 }
 
+func withExtensions(middlewares []gin.HandlerFunc, routes []apirouter.RouteDefinition) apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.middlewares = middlewares
+		cfg.routes = routes
+	}
+}
+
+func withDeliveryAcks(acks deliveryack.Store, canceler *mockRetryCanceler) apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.deliveryAcks = acks
+		cfg.retryCanceler = canceler
+	}
+}
+
+func withAckNotifier(notifier *mockAckNotifier) apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.ackNotifier = notifier
+	}
+}
+
+func withPayloads(payloads payloadoffload.Store) apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.payloads = payloads
+	}
+}
+
+func withTrustedProxies(proxies ...string) apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.trustedProxies = proxies
+	}
+}
+
 func withTopicsAllowWildcards(allow bool) apiTestOption {
 	return func(cfg *apiTestConfig) {
 		cfg.topicsAllowWildcards = allow
 	}
 }
 
+func withTopicLifecycle(lifecycle models.TopicLifecycle) apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.topicLifecycle = lifecycle
+	}
+}
+
+func withDestinationQuota(limit, warningPercent int) apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.maxDestinations = limit
+		cfg.quotaWarningPercent = warningPercent
+	}
+}
+
+// withEventQuota enables the per-tenant event quota, counted in Redis.
+func withEventQuota(limit, warningPercent int) apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.maxEventsPerMinute = limit
+		cfg.quotaWarningPercent = warningPercent
+	}
+}
+
+// withPublishRateLimit enables the tenant publish rate limit with the given
+// default, on a clock that doesn't move so buckets don't refill mid-test.
+func withPublishRateLimit(limit models.PublishRateLimit) apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.publishRateLimit = &limit
+	}
+}
+
+// withPublishKeys enables publish deduplication by idempotency key.
+func withPublishKeys() apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.publishKeys = true
+	}
+}
+
+// withBulkRetries enables bulk retry jobs, run against the test's log store,
+// tenant store and delivery publisher.
+func withBulkRetries() apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.bulkRetries = true
+	}
+}
+
+// withRedisMemory enables Redis memory reports of redisClient's keys.
+func withTopicStore() apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.topicStore = true
+	}
+}
+
+// withLegalHolds enables legal holds, stored in Redis.
+func withLegalHolds(opts ...legalhold.Option) apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.legalHolds = append([]legalhold.Option{}, opts...)
+	}
+}
+
+// withDestinationHealth reports the destination health recorded in store.
+func withDestinationHealth(store *desthealth.Store) apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.destinationHealth = store
+	}
+}
+
+// withTopicStats reports the topic stats recorded in store.
+func withTopicStats(store *topicstats.Store) apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.topicStats = store
+	}
+}
+
+// withHeldMessages manages the messages held in store.
+func withHeldMessages(store *deliveryhold.Store) apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.heldMessages = store
+	}
+}
+
+func withRedisMemory(redisClient redis.Cmdable) apiTestOption {
+	return func(cfg *apiTestConfig) {
+		cfg.redisMemory = redisClient
+	}
+}
+
 func newAPITest(t *testing.T, opts ...apiTestOption) *apiTest {
 	t.Helper()
 
@@ -110,7 +272,10 @@ func newAPITest(t *testing.T, opts ...apiTestOption) *apiTest {
 		logger = logging.NewTestLogger(zap.NewNop())
 	}
 	ts := cfg.tenantStore
-	ls := logstore.NewMemLogStore()
+	ls := cfg.logStore
+	if ls == nil {
+		ls = logstore.NewMemLogStore()
+	}
 	dp := &mockDeliveryPublisher{}
 	eh := &mockEventHandler{}
 	var se *mockSubscriptionEmitter
@@ -127,25 +292,74 @@ func newAPITest(t *testing.T, opts ...apiTestOption) *apiTest {
 		registry = cfg.destRegistry
 	}
 
+	deps := apirouter.RouterDeps{
+		TenantStore:         ts,
+		LogStore:            ls,
+		Logger:              logger,
+		DeliveryPublisher:   dp,
+		EventHandler:        eh,
+		Telemetry:           &telemetry.NoopTelemetry{},
+		SubscriptionEmitter: subEmitter,
+		DeliveryAcks:        cfg.deliveryAcks,
+		RetryCanceler:       cfg.retryCanceler,
+	}
+	if cfg.ackNotifier != nil {
+		deps.Lifecycle = cfg.ackNotifier
+	}
+	if cfg.payloads != nil {
+		deps.Payloads = cfg.payloads
+	}
+	if cfg.maxEventsPerMinute > 0 {
+		deps.EventRates = eventrate.New(testutil.CreateTestRedisClient(t))
+	}
+	if cfg.publishRateLimit != nil {
+		deps.PublishRateLimiter = publishrate.New(testutil.CreateTestRedisClient(t), ts, *cfg.publishRateLimit,
+			publishrate.WithClock(clock.NewFake(time.Now())))
+	}
+	if cfg.publishKeys {
+		deps.PublishKeys = publishkey.New(testutil.CreateTestRedisClient(t))
+	}
+	if cfg.bulkRetries {
+		store := bulkretry.NewStore(testutil.CreateTestRedisClient(t))
+		deps.BulkRetries = bulkretry.NewRunner(logger, store, ls, ts, dp)
+	}
+	if cfg.redisMemory != nil {
+		deps.RedisMemory = redismemory.New(cfg.redisMemory, "")
+	}
+	if cfg.topicStore {
+		deps.TopicStore = topicstore.New(testutil.CreateTestRedisClient(t), testutil.TestTopics, topicstore.WithLifecycle(cfg.topicLifecycle))
+	}
+	if cfg.legalHolds != nil {
+		deps.LegalHolds = legalhold.NewStore(testutil.CreateTestRedisClient(t), cfg.legalHolds...)
+	}
+	if cfg.destinationHealth != nil {
+		deps.DestinationHealth = cfg.destinationHealth
+	}
+	if cfg.topicStats != nil {
+		deps.TopicStats = cfg.topicStats
+	}
+	if cfg.heldMessages != nil {
+		deps.HeldMessages = cfg.heldMessages
+	}
+
 	router := apirouter.NewRouter(
 		apirouter.RouterConfig{
-			ServiceName:          "test",
-			APIKey:               testAPIKey,
-			JWTSecret:            testJWTSecret,
-			Topics:               testutil.TestTopics,
-			TopicsAllowWildcards: cfg.topicsAllowWildcards,
-			Registry:             registry,
-			PortalConfig:         portal.PortalConfig{},
-		},
-		apirouter.RouterDeps{
-			TenantStore:         ts,
-			LogStore:            ls,
-			Logger:              logger,
-			DeliveryPublisher:   dp,
-			EventHandler:        eh,
-			Telemetry:           &telemetry.NoopTelemetry{},
-			SubscriptionEmitter: subEmitter,
+			ServiceName:                 "test",
+			APIKey:                      testAPIKey,
+			JWTSecret:                   testJWTSecret,
+			Topics:                      testutil.TestTopics,
+			TopicsAllowWildcards:        cfg.topicsAllowWildcards,
+			TopicLifecycle:              cfg.topicLifecycle,
+			Registry:                    registry,
+			PortalConfig:                portal.PortalConfig{},
+			MaxDestinationsPerTenant:    cfg.maxDestinations,
+			QuotaWarningPercent:         cfg.quotaWarningPercent,
+			MaxEventsPerMinutePerTenant: cfg.maxEventsPerMinute,
+			TrustedProxies:              cfg.trustedProxies,
+			Middlewares:                 cfg.middlewares,
+			Routes:                      cfg.routes,
 		},
+		deps,
 	)
 
 	return &apiTest{
@@ -201,6 +415,17 @@ func (a *apiTest) withJWT(req *http.Request, tenantID string) *http.Request {
 	return req
 }
 
+// withViewerJWT adds a JWT auth header for a viewer of the given tenant.
+func (a *apiTest) withViewerJWT(req *http.Request, tenantID string) *http.Request {
+	a.t.Helper()
+	token, err := apirouter.JWT.New(testJWTSecret, apirouter.JWTClaims{TenantID: tenantID, PortalRole: apirouter.PortalRoleViewer})
+	if err != nil {
+		a.t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	return req
+}
+
 // ---------------------------------------------------------------------------
 // Mocks
 // ---------------------------------------------------------------------------
@@ -216,11 +441,34 @@ func (m *mockDeliveryPublisher) Publish(_ context.Context, task models.DeliveryT
 	return m.err
 }
 
-// mockEventHandler records Handle calls with configurable return values.
+// mockRetryCanceler records Cancel calls.
+type mockRetryCanceler struct {
+	canceled []string
+	err      error
+}
+
+func (m *mockRetryCanceler) Cancel(_ context.Context, taskID string) error {
+	m.canceled = append(m.canceled, taskID)
+	return m.err
+}
+
+type mockAckNotifier struct {
+	acknowledged []deliveryack.Pending
+}
+
+func (m *mockAckNotifier) DeliveryAcknowledged(pending *deliveryack.Pending) {
+	m.acknowledged = append(m.acknowledged, *pending)
+}
+
+// mockEventHandler records Handle, HandleGroup and DryRun calls with
+// configurable return values.
 type mockEventHandler struct {
-	calls  []*models.Event
-	result *publishmq.HandleResult
-	err    error
+	calls        []*models.Event
+	groupIDs     []string
+	result       *publishmq.HandleResult
+	dryRunCalls  []*models.Event
+	dryRunResult *publishmq.DryRunResult
+	err          error
 }
 
 func (m *mockEventHandler) Handle(_ context.Context, event *models.Event) (*publishmq.HandleResult, error) {
@@ -234,6 +482,30 @@ func (m *mockEventHandler) Handle(_ context.Context, event *models.Event) (*publ
 	return &publishmq.HandleResult{EventID: event.ID, DestinationIDs: []string{}}, nil
 }
 
+func (m *mockEventHandler) HandleGroup(ctx context.Context, groupID string, events []*models.Event) ([]*publishmq.HandleResult, error) {
+	m.groupIDs = append(m.groupIDs, groupID)
+	if m.err != nil {
+		return nil, m.err
+	}
+	results := make([]*publishmq.HandleResult, len(events))
+	for i, event := range events {
+		event.GroupID = groupID
+		results[i], _ = m.Handle(ctx, event)
+	}
+	return results, nil
+}
+
+func (m *mockEventHandler) DryRun(_ context.Context, event *models.Event) (*publishmq.DryRunResult, error) {
+	m.dryRunCalls = append(m.dryRunCalls, event)
+	if m.err != nil {
+		return nil, m.err
+	}
+	if m.dryRunResult != nil {
+		return m.dryRunResult, nil
+	}
+	return &publishmq.DryRunResult{EventID: event.ID, DryRun: true, DestinationIDs: []string{}, Destinations: []publishmq.DryRunDestination{}}, nil
+}
+
 // mockSubscriptionEmitter records Emit calls.
 type mockSubscriptionEmitter struct {
 	calls []emitCall
@@ -274,7 +546,16 @@ func (r *stubRegistry) ResolvePublisher(context.Context, *models.Destination) (d
 	return nil, nil
 }
 func (r *stubRegistry) MetadataLoader() metadata.MetadataLoader { return nil }
-func (r *stubRegistry) RetrieveProviderMetadata(string) (*metadata.ProviderMetadata, error) {
-	return nil, nil
+func (r *stubRegistry) RetrieveProviderMetadata(providerType string) (*metadata.ProviderMetadata, error) {
+	if providerType != "webhook" {
+		return nil, fmt.Errorf("provider metadata not found: %s", providerType)
+	}
+	return &metadata.ProviderMetadata{
+		Type:         "webhook",
+		ConfigFields: []metadata.FieldSchema{{Key: "url", Type: "text", Required: true}},
+	}, nil
+}
+func (r *stubRegistry) ListProviderMetadata() []*metadata.ProviderMetadata {
+	webhook, _ := r.RetrieveProviderMetadata("webhook")
+	return []*metadata.ProviderMetadata{webhook}
 }
-func (r *stubRegistry) ListProviderMetadata() []*metadata.ProviderMetadata { return nil }
@@ -1,12 +1,17 @@
 package apirouter
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/models"
 	"github.com/hookdeck/outpost/internal/telemetry"
@@ -20,6 +25,7 @@ type TenantHandlers struct {
 	jwtSecret    string
 	deploymentID string
 	tenantStore  tenantstore.TenantStore
+	registry     destregistry.Registry
 }
 
 func NewTenantHandlers(
@@ -28,6 +34,7 @@ func NewTenantHandlers(
 	jwtSecret string,
 	deploymentID string,
 	tenantStore tenantstore.TenantStore,
+	registry destregistry.Registry,
 ) *TenantHandlers {
 	return &TenantHandlers{
 		logger:       logger,
@@ -35,15 +42,24 @@ func NewTenantHandlers(
 		jwtSecret:    jwtSecret,
 		deploymentID: deploymentID,
 		tenantStore:  tenantStore,
+		registry:     registry,
 	}
 }
 
 func (h *TenantHandlers) Upsert(c *gin.Context) {
 	tenantID := c.Param("tenant_id")
 
-	// Parse request body for metadata
+	// Parse request body for metadata, sandbox flag, receipt storage,
+	// destination types, publish rate limit, portal IP allowlist and webhook
+	// endpoint verification
 	var input struct {
-		Metadata models.Metadata `json:"metadata,omitempty"`
+		Metadata               models.Metadata `json:"metadata,omitempty"`
+		Sandbox                *bool           `json:"sandbox,omitempty"`
+		ReceiptStorage         json.RawMessage `json:"receipt_storage,omitempty"`
+		DestinationTypes       json.RawMessage `json:"destination_types,omitempty"`
+		PublishRateLimit       json.RawMessage `json:"publish_rate_limit,omitempty"`
+		PortalAllowedIPs       json.RawMessage `json:"portal_allowed_ips,omitempty"`
+		VerifyWebhookEndpoints *bool           `json:"verify_webhook_endpoints,omitempty"`
 	}
 	// Only attempt to parse JSON if there's a request body
 	if c.Request.ContentLength > 0 {
@@ -52,6 +68,64 @@ func (h *TenantHandlers) Upsert(c *gin.Context) {
 			return
 		}
 	}
+	receiptStorage, receiptStorageSet, err := parseReceiptStorage(input.ReceiptStorage)
+	if err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	// Receipts are written with the deployment's AWS identity, so only the
+	// operator may choose where they go.
+	if receiptStorageSet && mustRoleFromContext(c) != RoleAdmin {
+		AbortWithError(c, http.StatusForbidden, ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "receipt_storage can only be set with API key authentication",
+		})
+		return
+	}
+	destinationTypes, destinationTypesSet, err := h.parseDestinationTypes(input.DestinationTypes)
+	if err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	// The destination types a tenant may use are the operator's policy, for
+	// instance per plan, so a tenant can't change its own.
+	if destinationTypesSet && mustRoleFromContext(c) != RoleAdmin {
+		AbortWithError(c, http.StatusForbidden, ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "destination_types can only be set with API key authentication",
+		})
+		return
+	}
+	publishRateLimit, publishRateLimitSet, err := parsePublishRateLimit(input.PublishRateLimit)
+	if err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	// Like destination types, the rate limit is the operator's policy.
+	if publishRateLimitSet && mustRoleFromContext(c) != RoleAdmin {
+		AbortWithError(c, http.StatusForbidden, ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "publish_rate_limit can only be set with API key authentication",
+		})
+		return
+	}
+	portalAllowedIPs, portalAllowedIPsSet, err := parsePortalAllowedIPs(input.PortalAllowedIPs)
+	if err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	// Tenants may restrict their own portal tokens, but not in a way that
+	// locks out the token making the change.
+	if portalAllowedIPsSet && mustRoleFromContext(c) != RoleAdmin {
+		allowed := models.Tenant{PortalAllowedIPs: portalAllowedIPs}
+		if !allowed.AllowsPortalIP(c.ClientIP()) {
+			AbortWithError(c, http.StatusBadRequest, ErrorResponse{
+				Code:    http.StatusBadRequest,
+				Message: "portal_allowed_ips must include the address of this request",
+			})
+			return
+		}
+	}
 
 	// Check existing tenant.
 	existingTenant, err := h.tenantStore.RetrieveTenant(c.Request.Context(), tenantID)
@@ -59,10 +133,41 @@ func (h *TenantHandlers) Upsert(c *gin.Context) {
 		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
 		return
 	}
+	// Tenants may require verification of their own endpoints, but only the
+	// operator may lift the requirement.
+	if input.VerifyWebhookEndpoints != nil && !*input.VerifyWebhookEndpoints &&
+		existingTenant != nil && existingTenant.VerifyWebhookEndpoints && mustRoleFromContext(c) != RoleAdmin {
+		AbortWithError(c, http.StatusForbidden, ErrorResponse{
+			Code:    http.StatusForbidden,
+			Message: "verify_webhook_endpoints can only be turned off with API key authentication",
+		})
+		return
+	}
 
-	// If tenant already exists, update it (PUT replaces metadata)
+	// If tenant already exists, update it (PUT replaces metadata; sandbox,
+	// receipt storage, destination types, publish rate limit, portal IP
+	// allowlist and webhook endpoint verification are only changed when
+	// provided)
 	if existingTenant != nil {
 		existingTenant.Metadata = input.Metadata
+		if input.Sandbox != nil {
+			existingTenant.Sandbox = *input.Sandbox
+		}
+		if receiptStorageSet {
+			existingTenant.ReceiptStorage = receiptStorage
+		}
+		if destinationTypesSet {
+			existingTenant.DestinationTypes = destinationTypes
+		}
+		if publishRateLimitSet {
+			existingTenant.PublishRateLimit = publishRateLimit
+		}
+		if portalAllowedIPsSet {
+			existingTenant.PortalAllowedIPs = portalAllowedIPs
+		}
+		if input.VerifyWebhookEndpoints != nil {
+			existingTenant.VerifyWebhookEndpoints = *input.VerifyWebhookEndpoints
+		}
 		existingTenant.UpdatedAt = time.Now()
 		if err := h.tenantStore.UpsertTenant(c.Request.Context(), *existingTenant); err != nil {
 			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
@@ -70,6 +175,11 @@ func (h *TenantHandlers) Upsert(c *gin.Context) {
 		}
 		h.logger.Ctx(c.Request.Context()).Audit("tenant updated",
 			zap.String("tenant_id", tenantID),
+			zap.Bool("sandbox", existingTenant.Sandbox),
+			zap.Strings("destination_types", existingTenant.DestinationTypes),
+			zap.Any("publish_rate_limit", existingTenant.PublishRateLimit),
+			zap.Strings("portal_allowed_ips", existingTenant.PortalAllowedIPs),
+			zap.Bool("verify_webhook_endpoints", existingTenant.VerifyWebhookEndpoints),
 		)
 		c.JSON(http.StatusOK, existingTenant)
 		return
@@ -81,8 +191,16 @@ func (h *TenantHandlers) Upsert(c *gin.Context) {
 		ID:        tenantID,
 		Topics:    []string{},
 		Metadata:  input.Metadata,
+		Sandbox:   input.Sandbox != nil && *input.Sandbox,
 		CreatedAt: now,
 		UpdatedAt: now,
+
+		ReceiptStorage:   receiptStorage,
+		DestinationTypes: destinationTypes,
+		PublishRateLimit: publishRateLimit,
+		PortalAllowedIPs: portalAllowedIPs,
+
+		VerifyWebhookEndpoints: input.VerifyWebhookEndpoints != nil && *input.VerifyWebhookEndpoints,
 	}
 	if err := h.tenantStore.UpsertTenant(c.Request.Context(), *tenant); err != nil {
 		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
@@ -91,10 +209,112 @@ func (h *TenantHandlers) Upsert(c *gin.Context) {
 	h.telemetry.TenantCreated(c.Request.Context())
 	h.logger.Ctx(c.Request.Context()).Audit("tenant created",
 		zap.String("tenant_id", tenantID),
+		zap.Bool("sandbox", tenant.Sandbox),
+		zap.Strings("destination_types", tenant.DestinationTypes),
+		zap.Any("publish_rate_limit", tenant.PublishRateLimit),
+		zap.Strings("portal_allowed_ips", tenant.PortalAllowedIPs),
+		zap.Bool("verify_webhook_endpoints", tenant.VerifyWebhookEndpoints),
 	)
 	c.JSON(http.StatusCreated, tenant)
 }
 
+// parseReceiptStorage parses the receipt_storage field of a tenant upsert. It
+// reports whether the field was provided; an explicit null clears the setting.
+func parseReceiptStorage(raw json.RawMessage) (*models.ReceiptStorage, bool, error) {
+	if len(raw) == 0 {
+		return nil, false, nil
+	}
+	if isJSONNull(raw) {
+		return nil, true, nil
+	}
+	var storage models.ReceiptStorage
+	if err := json.Unmarshal(raw, &storage); err != nil {
+		return nil, true, fmt.Errorf("invalid receipt_storage: %w", err)
+	}
+	if storage.Bucket == "" || storage.Region == "" {
+		return nil, true, errors.New("receipt_storage requires bucket and region")
+	}
+	return &storage, true, nil
+}
+
+// parsePublishRateLimit parses the publish_rate_limit field of a tenant
+// upsert. It reports whether the field was provided; an explicit null clears
+// the override so the deployment default applies.
+func parsePublishRateLimit(raw json.RawMessage) (*models.PublishRateLimit, bool, error) {
+	if len(raw) == 0 {
+		return nil, false, nil
+	}
+	if isJSONNull(raw) {
+		return nil, true, nil
+	}
+	var limit models.PublishRateLimit
+	if err := json.Unmarshal(raw, &limit); err != nil {
+		return nil, true, fmt.Errorf("invalid publish_rate_limit: %w", err)
+	}
+	if limit.PerSecond < 0 || limit.Burst < 0 {
+		return nil, true, errors.New("publish_rate_limit per_second and burst must not be negative")
+	}
+	return &limit, true, nil
+}
+
+// parseDestinationTypes parses the destination_types field of a tenant
+// upsert. It reports whether the field was provided; an explicit null or an
+// empty list enables every destination type.
+func (h *TenantHandlers) parseDestinationTypes(raw json.RawMessage) ([]string, bool, error) {
+	if len(raw) == 0 {
+		return nil, false, nil
+	}
+	if isJSONNull(raw) {
+		return nil, true, nil
+	}
+	var types []string
+	if err := json.Unmarshal(raw, &types); err != nil {
+		return nil, true, fmt.Errorf("invalid destination_types: %w", err)
+	}
+	if len(types) == 0 {
+		return nil, true, nil
+	}
+	for _, destinationType := range types {
+		if _, err := h.registry.RetrieveProviderMetadata(destinationType); err != nil {
+			return nil, true, fmt.Errorf("invalid destination_types: unknown destination type %q", destinationType)
+		}
+	}
+	slices.Sort(types)
+	return slices.Compact(types), true, nil
+}
+
+// parsePortalAllowedIPs parses the portal_allowed_ips field of a tenant
+// upsert. It reports whether the field was provided; an explicit null or an
+// empty list allows portal tokens from any address.
+func parsePortalAllowedIPs(raw json.RawMessage) ([]string, bool, error) {
+	if len(raw) == 0 {
+		return nil, false, nil
+	}
+	if isJSONNull(raw) {
+		return nil, true, nil
+	}
+	var entries []string
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, true, fmt.Errorf("invalid portal_allowed_ips: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, true, nil
+	}
+	for i, entry := range entries {
+		prefix, err := models.ParseIPRange(strings.TrimSpace(entry))
+		if err != nil {
+			return nil, true, fmt.Errorf("invalid portal_allowed_ips: %q is not an IP address or CIDR range", entry)
+		}
+		if prefix.IsSingleIP() {
+			entries[i] = prefix.Addr().String()
+		} else {
+			entries[i] = prefix.String()
+		}
+	}
+	slices.Sort(entries)
+	return slices.Compact(entries), true, nil
+}
+
 func (h *TenantHandlers) Retrieve(c *gin.Context) {
 	tenant := mustTenantFromContext(c)
 	c.JSON(http.StatusOK, tenant)
@@ -198,11 +418,71 @@ func (h *TenantHandlers) Delete(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"success": true})
 }
 
+// Suspend moves the tenant's data out of Redis into cold storage. The tenant
+// is resumed on its next access.
+func (h *TenantHandlers) Suspend(c *gin.Context) {
+	tenant := mustTenantFromContext(c)
+
+	suspender, ok := h.tenantStore.(tenantstore.TenantSuspender)
+	if !ok {
+		AbortWithError(c, http.StatusNotImplemented, ErrorResponse{
+			Code:    http.StatusNotImplemented,
+			Message: "tenant suspension is not supported by the tenant store",
+		})
+		return
+	}
+	if err := suspender.SuspendTenant(c.Request.Context(), tenant.ID); err != nil {
+		switch {
+		case errors.Is(err, tenantstore.ErrColdStorageNotConfigured):
+			AbortWithError(c, http.StatusNotImplemented, ErrorResponse{
+				Err:     err,
+				Code:    http.StatusNotImplemented,
+				Message: err.Error(),
+			})
+		case errors.Is(err, tenantstore.ErrTenantModified):
+			AbortWithError(c, http.StatusConflict, ErrorResponse{
+				Err:     err,
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+		case errors.Is(err, tenantstore.ErrTenantNotFound), errors.Is(err, tenantstore.ErrTenantDeleted):
+			AbortWithError(c, http.StatusNotFound, NewErrNotFound("tenant"))
+		default:
+			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		}
+		return
+	}
+	h.logger.Ctx(c.Request.Context()).Audit("tenant suspended",
+		zap.String("tenant_id", tenant.ID),
+	)
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// portalRoleFromQuery returns the role of the portal token requested with the
+// role query parameter, and aborts with 422 when it isn't a portal role.
+func portalRoleFromQuery(c *gin.Context) (string, bool) {
+	switch role := c.Query("role"); role {
+	case "", PortalRoleAdmin, PortalRoleViewer:
+		return role, true
+	}
+	AbortWithValidationError(c, ErrorResponse{
+		Code:    http.StatusUnprocessableEntity,
+		Message: "validation error",
+		Data:    []string{"role must be admin or viewer"},
+	})
+	return "", false
+}
+
 func (h *TenantHandlers) RetrieveToken(c *gin.Context) {
 	tenant := mustTenantFromContext(c)
+	role, ok := portalRoleFromQuery(c)
+	if !ok {
+		return
+	}
 	jwtToken, err := JWT.New(h.jwtSecret, JWTClaims{
 		TenantID:     tenant.ID,
 		DeploymentID: h.deploymentID,
+		PortalRole:   role,
 	})
 	if err != nil {
 		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
@@ -213,9 +493,14 @@ func (h *TenantHandlers) RetrieveToken(c *gin.Context) {
 
 func (h *TenantHandlers) RetrievePortal(c *gin.Context) {
 	tenant := mustTenantFromContext(c)
+	role, ok := portalRoleFromQuery(c)
+	if !ok {
+		return
+	}
 	jwtToken, err := JWT.New(h.jwtSecret, JWTClaims{
 		TenantID:     tenant.ID,
 		DeploymentID: h.deploymentID,
+		PortalRole:   role,
 	})
 	if err != nil {
 		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
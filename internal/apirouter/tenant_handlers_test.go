@@ -29,6 +29,22 @@ func (s *listUnsupportedStore) ListTenant(_ context.Context, _ tenantstore.ListT
 	return nil, tenantstore.ErrListTenantNotSupported
 }
 
+// suspendingStore wraps a TenantStore and records the tenants it suspends,
+// or fails with err.
+type suspendingStore struct {
+	tenantstore.TenantStore
+	suspended []string
+	err       error
+}
+
+func (s *suspendingStore) SuspendTenant(_ context.Context, tenantID string) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.suspended = append(s.suspended, tenantID)
+	return nil
+}
+
 func TestAPI_Tenants(t *testing.T) {
 	t.Run("Upsert", func(t *testing.T) {
 		t.Run("api key creates tenant", func(t *testing.T) {
@@ -133,6 +149,376 @@ func TestAPI_Tenants(t *testing.T) {
 			require.NoError(t, err)
 			assert.Equal(t, "t1", tenant.ID)
 		})
+
+		t.Run("ReceiptStorage", func(t *testing.T) {
+			storage := map[string]any{"bucket": "receipts", "region": "us-east-1", "prefix": "outpost/"}
+
+			t.Run("api key sets receipt storage", func(t *testing.T) {
+				h := newAPITest(t)
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"receipt_storage": storage,
+				})
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusCreated, resp.Code)
+
+				tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+				require.NoError(t, err)
+				require.NotNil(t, tenant.ReceiptStorage)
+				assert.Equal(t, models.ReceiptStorage{Bucket: "receipts", Region: "us-east-1", Prefix: "outpost/"}, *tenant.ReceiptStorage)
+			})
+
+			t.Run("update without field keeps receipt storage", func(t *testing.T) {
+				h := newAPITest(t)
+				existing := tf.Any(tf.WithID("t1"))
+				existing.ReceiptStorage = &models.ReceiptStorage{Bucket: "receipts", Region: "us-east-1"}
+				h.tenantStore.UpsertTenant(t.Context(), existing)
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"metadata": map[string]string{"env": "prod"},
+				})
+				resp := h.do(h.withJWT(req, "t1"))
+
+				require.Equal(t, http.StatusOK, resp.Code)
+
+				tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+				require.NoError(t, err)
+				require.NotNil(t, tenant.ReceiptStorage)
+				assert.Equal(t, "receipts", tenant.ReceiptStorage.Bucket)
+			})
+
+			t.Run("null clears receipt storage", func(t *testing.T) {
+				h := newAPITest(t)
+				existing := tf.Any(tf.WithID("t1"))
+				existing.ReceiptStorage = &models.ReceiptStorage{Bucket: "receipts", Region: "us-east-1"}
+				h.tenantStore.UpsertTenant(t.Context(), existing)
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"receipt_storage": nil,
+				})
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusOK, resp.Code)
+
+				tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+				require.NoError(t, err)
+				assert.Nil(t, tenant.ReceiptStorage)
+			})
+
+			t.Run("missing region returns 422", func(t *testing.T) {
+				h := newAPITest(t)
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"receipt_storage": map[string]any{"bucket": "receipts"},
+				})
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+			})
+
+			t.Run("jwt returns 403", func(t *testing.T) {
+				h := newAPITest(t)
+				h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"receipt_storage": storage,
+				})
+				resp := h.do(h.withJWT(req, "t1"))
+
+				require.Equal(t, http.StatusForbidden, resp.Code)
+
+				tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+				require.NoError(t, err)
+				assert.Nil(t, tenant.ReceiptStorage)
+			})
+		})
+
+		t.Run("DestinationTypes", func(t *testing.T) {
+			t.Run("api key sets destination types", func(t *testing.T) {
+				h := newAPITest(t)
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"destination_types": []string{"webhook", "webhook"},
+				})
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusCreated, resp.Code)
+
+				tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+				require.NoError(t, err)
+				assert.Equal(t, []string{"webhook"}, tenant.DestinationTypes)
+			})
+
+			t.Run("null enables every type", func(t *testing.T) {
+				h := newAPITest(t)
+				existing := tf.Any(tf.WithID("t1"))
+				existing.DestinationTypes = []string{"webhook"}
+				h.tenantStore.UpsertTenant(t.Context(), existing)
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"destination_types": nil,
+				})
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusOK, resp.Code)
+
+				tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+				require.NoError(t, err)
+				assert.Empty(t, tenant.DestinationTypes)
+			})
+
+			t.Run("unknown type returns 422", func(t *testing.T) {
+				h := newAPITest(t)
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"destination_types": []string{"carrier_pigeon"},
+				})
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+			})
+
+			t.Run("jwt returns 403", func(t *testing.T) {
+				h := newAPITest(t)
+				existing := tf.Any(tf.WithID("t1"))
+				existing.DestinationTypes = []string{"webhook"}
+				h.tenantStore.UpsertTenant(t.Context(), existing)
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"destination_types": nil,
+				})
+				resp := h.do(h.withJWT(req, "t1"))
+
+				require.Equal(t, http.StatusForbidden, resp.Code)
+
+				tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+				require.NoError(t, err)
+				assert.Equal(t, []string{"webhook"}, tenant.DestinationTypes)
+			})
+		})
+
+		t.Run("PublishRateLimit", func(t *testing.T) {
+			t.Run("api key sets publish rate limit", func(t *testing.T) {
+				h := newAPITest(t)
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"publish_rate_limit": map[string]any{"per_second": 10, "burst": 50},
+				})
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusCreated, resp.Code)
+
+				tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+				require.NoError(t, err)
+				assert.Equal(t, &models.PublishRateLimit{PerSecond: 10, Burst: 50}, tenant.PublishRateLimit)
+			})
+
+			t.Run("null clears the override", func(t *testing.T) {
+				h := newAPITest(t)
+				existing := tf.Any(tf.WithID("t1"))
+				existing.PublishRateLimit = &models.PublishRateLimit{PerSecond: 10}
+				h.tenantStore.UpsertTenant(t.Context(), existing)
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"publish_rate_limit": nil,
+				})
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusOK, resp.Code)
+
+				tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+				require.NoError(t, err)
+				assert.Nil(t, tenant.PublishRateLimit)
+			})
+
+			t.Run("negative rate returns 422", func(t *testing.T) {
+				h := newAPITest(t)
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"publish_rate_limit": map[string]any{"per_second": -1},
+				})
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+			})
+
+			t.Run("jwt returns 403", func(t *testing.T) {
+				h := newAPITest(t)
+				h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"publish_rate_limit": map[string]any{"per_second": 1000},
+				})
+				resp := h.do(h.withJWT(req, "t1"))
+
+				require.Equal(t, http.StatusForbidden, resp.Code)
+
+				tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+				require.NoError(t, err)
+				assert.Nil(t, tenant.PublishRateLimit)
+			})
+		})
+
+		t.Run("PortalAllowedIPs", func(t *testing.T) {
+			t.Run("api key sets allowed IPs", func(t *testing.T) {
+				h := newAPITest(t)
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"portal_allowed_ips": []string{"203.0.113.7", "10.1.2.3/8", "203.0.113.7"},
+				})
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusCreated, resp.Code)
+
+				tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+				require.NoError(t, err)
+				assert.Equal(t, []string{"10.0.0.0/8", "203.0.113.7"}, tenant.PortalAllowedIPs)
+			})
+
+			t.Run("null allows every address", func(t *testing.T) {
+				h := newAPITest(t)
+				existing := tf.Any(tf.WithID("t1"))
+				existing.PortalAllowedIPs = []string{"203.0.113.0/24"}
+				h.tenantStore.UpsertTenant(t.Context(), existing)
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"portal_allowed_ips": nil,
+				})
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusOK, resp.Code)
+
+				tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+				require.NoError(t, err)
+				assert.Empty(t, tenant.PortalAllowedIPs)
+			})
+
+			t.Run("invalid entry returns 422", func(t *testing.T) {
+				h := newAPITest(t)
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"portal_allowed_ips": []string{"not-an-ip"},
+				})
+				resp := h.do(h.withAPIKey(req))
+
+				require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+			})
+
+			t.Run("jwt sets allowed IPs including its own address", func(t *testing.T) {
+				h := newAPITest(t)
+				h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"portal_allowed_ips": []string{"198.51.100.0/24"},
+				})
+				req.RemoteAddr = "198.51.100.7:1234"
+				resp := h.do(h.withJWT(req, "t1"))
+
+				require.Equal(t, http.StatusOK, resp.Code)
+
+				tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+				require.NoError(t, err)
+				assert.Equal(t, []string{"198.51.100.0/24"}, tenant.PortalAllowedIPs)
+			})
+
+			t.Run("forged X-Forwarded-For is ignored without trusted proxies", func(t *testing.T) {
+				h := newAPITest(t)
+				existing := tf.Any(tf.WithID("t1"))
+				existing.PortalAllowedIPs = []string{"203.0.113.0/24"}
+				h.tenantStore.UpsertTenant(t.Context(), existing)
+
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1", nil)
+				req.RemoteAddr = "198.51.100.7:1234"
+				req.Header.Set("X-Forwarded-For", "203.0.113.9")
+				resp := h.do(h.withJWT(req, "t1"))
+
+				assert.Equal(t, http.StatusForbidden, resp.Code)
+			})
+
+			t.Run("X-Forwarded-For is read from a trusted proxy", func(t *testing.T) {
+				h := newAPITest(t, withTrustedProxies("198.51.100.0/24"))
+				existing := tf.Any(tf.WithID("t1"))
+				existing.PortalAllowedIPs = []string{"203.0.113.0/24"}
+				h.tenantStore.UpsertTenant(t.Context(), existing)
+
+				req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1", nil)
+				req.RemoteAddr = "198.51.100.7:1234"
+				req.Header.Set("X-Forwarded-For", "203.0.113.9")
+				resp := h.do(h.withJWT(req, "t1"))
+
+				assert.Equal(t, http.StatusOK, resp.Code)
+			})
+
+			t.Run("jwt excluding its own address returns 400", func(t *testing.T) {
+				h := newAPITest(t)
+				h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+				req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+					"portal_allowed_ips": []string{"203.0.113.0/24"},
+				})
+				req.RemoteAddr = "198.51.100.7:1234"
+				resp := h.do(h.withJWT(req, "t1"))
+
+				require.Equal(t, http.StatusBadRequest, resp.Code)
+
+				tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+				require.NoError(t, err)
+				assert.Empty(t, tenant.PortalAllowedIPs)
+			})
+		})
+	})
+
+	t.Run("VerifyWebhookEndpoints", func(t *testing.T) {
+		t.Run("jwt turns on verification", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+				"verify_webhook_endpoints": true,
+			})
+			resp := h.do(h.withJWT(req, "t1"))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+
+			tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+			require.NoError(t, err)
+			assert.True(t, tenant.VerifyWebhookEndpoints)
+		})
+
+		t.Run("update without field keeps verification", func(t *testing.T) {
+			h := newAPITest(t)
+			existing := tf.Any(tf.WithID("t1"))
+			existing.VerifyWebhookEndpoints = true
+			h.tenantStore.UpsertTenant(t.Context(), existing)
+
+			req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{})
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+
+			tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+			require.NoError(t, err)
+			assert.True(t, tenant.VerifyWebhookEndpoints)
+		})
+
+		t.Run("jwt turning off verification returns 403", func(t *testing.T) {
+			h := newAPITest(t)
+			existing := tf.Any(tf.WithID("t1"))
+			existing.VerifyWebhookEndpoints = true
+			h.tenantStore.UpsertTenant(t.Context(), existing)
+
+			req := h.jsonReq(http.MethodPut, "/api/v1/tenants/t1", map[string]any{
+				"verify_webhook_endpoints": false,
+			})
+			resp := h.do(h.withJWT(req, "t1"))
+
+			require.Equal(t, http.StatusForbidden, resp.Code)
+
+			tenant, err := h.tenantStore.RetrieveTenant(t.Context(), "t1")
+			require.NoError(t, err)
+			assert.True(t, tenant.VerifyWebhookEndpoints)
+		})
 	})
 
 	t.Run("Retrieve", func(t *testing.T) {
@@ -436,6 +822,64 @@ func TestAPI_Tenants(t *testing.T) {
 		})
 	})
 
+	t.Run("Suspend", func(t *testing.T) {
+		t.Run("api key suspends tenant", func(t *testing.T) {
+			store := &suspendingStore{TenantStore: tenantstore.NewMemTenantStore()}
+			h := newAPITest(t, withTenantStore(store))
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tenants/t1/suspend", nil)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+			assert.Equal(t, []string{"t1"}, store.suspended)
+		})
+
+		t.Run("jwt returns 403", func(t *testing.T) {
+			store := &suspendingStore{TenantStore: tenantstore.NewMemTenantStore()}
+			h := newAPITest(t, withTenantStore(store))
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tenants/t1/suspend", nil)
+			resp := h.do(h.withJWT(req, "t1"))
+
+			require.Equal(t, http.StatusForbidden, resp.Code)
+			assert.Empty(t, store.suspended)
+		})
+
+		t.Run("tenant modified returns 409", func(t *testing.T) {
+			store := &suspendingStore{TenantStore: tenantstore.NewMemTenantStore(), err: tenantstore.ErrTenantModified}
+			h := newAPITest(t, withTenantStore(store))
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tenants/t1/suspend", nil)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusConflict, resp.Code)
+		})
+
+		t.Run("cold storage not configured returns 501", func(t *testing.T) {
+			store := &suspendingStore{TenantStore: tenantstore.NewMemTenantStore(), err: tenantstore.ErrColdStorageNotConfigured}
+			h := newAPITest(t, withTenantStore(store))
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tenants/t1/suspend", nil)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusNotImplemented, resp.Code)
+		})
+
+		t.Run("store without suspension returns 501", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			req := httptest.NewRequest(http.MethodPost, "/api/v1/tenants/t1/suspend", nil)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusNotImplemented, resp.Code)
+		})
+	})
+
 	t.Run("jwt other tenant returns 403", func(t *testing.T) {
 		h := newAPITest(t)
 		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
@@ -486,6 +930,33 @@ func TestAPI_Tenants(t *testing.T) {
 			claims, err := apirouter.JWT.Extract(testJWTSecret, body["token"])
 			require.NoError(t, err)
 			assert.Equal(t, "t1", claims.TenantID)
+			assert.Empty(t, claims.PortalRole)
+		})
+
+		t.Run("role scopes the token", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/token?role=viewer", nil)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusOK, resp.Code)
+
+			var body map[string]string
+			require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &body))
+			claims, err := apirouter.JWT.Extract(testJWTSecret, body["token"])
+			require.NoError(t, err)
+			assert.Equal(t, apirouter.PortalRoleViewer, claims.PortalRole)
+		})
+
+		t.Run("unknown role returns 422", func(t *testing.T) {
+			h := newAPITest(t)
+			h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/token?role=owner", nil)
+			resp := h.do(h.withAPIKey(req))
+
+			require.Equal(t, http.StatusUnprocessableEntity, resp.Code)
 		})
 
 		t.Run("nonexistent tenant returns 404", func(t *testing.T) {
@@ -0,0 +1,67 @@
+package apirouter
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/models"
+	"go.uber.org/zap"
+)
+
+// TestEventTopic is the topic of the test events sent to destinations on
+// request.
+const TestEventTopic = "outpost.test"
+
+var errNoTestAttempt = errors.New("test event delivery made no attempt")
+
+// SendTestEvent handles POST /tenants/:tenant_id/destinations/:destination_id/test.
+// It delivers a test event to the destination the way the delivery worker
+// delivers events, with the destination's transformation, signature and
+// headers, and responds with the attempt. The destination's topics, filter
+// and enabled state are ignored. Test events are not logged or retried, and
+// a failed test doesn't count towards disabling the destination.
+func (h *DestinationHandlers) SendTestEvent(c *gin.Context) {
+	tenant := mustTenantFromContext(c)
+	destination := h.mustRetrieveDestination(c, tenant.ID, c.Param("destination_id"))
+	if destination == nil {
+		return
+	}
+
+	ctx := c.Request.Context()
+	event := &models.Event{
+		ID:            idgen.Event(),
+		TenantID:      tenant.ID,
+		DestinationID: destination.ID,
+		Topic:         TestEventTopic,
+		Time:          time.Now(),
+		Metadata:      map[string]string{"test": "true"},
+		Data:          json.RawMessage(`{"type":"test","message":"This is a test event sent from Outpost."}`),
+	}
+	attempt, err := h.registry.PublishEvent(ctx, destination, event)
+	if attempt == nil {
+		if err == nil {
+			err = errNoTestAttempt
+		}
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	attempt.TenantID = tenant.ID
+
+	h.logger.Ctx(ctx).Info("destination test event sent",
+		zap.Error(err),
+		zap.String("tenant_id", tenant.ID),
+		zap.String("destination_id", destination.ID),
+		zap.String("destination_type", destination.Type),
+		zap.String("event_id", event.ID),
+		zap.String("status", attempt.Status))
+	c.JSON(http.StatusOK, toAPIAttempt(&logstore.AttemptRecord{Attempt: attempt, Event: event}, IncludeOptions{
+		EventData:    true,
+		ResponseData: true,
+		RawErrors:    !isJWTCaller(c),
+	}, nil))
+}
@@ -0,0 +1,103 @@
+package apirouter_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPI_SendTestEvent(t *testing.T) {
+	setup := func(t *testing.T, handler http.HandlerFunc) *apiTest {
+		t.Helper()
+		server := httptest.NewServer(handler)
+		t.Cleanup(server.Close)
+
+		h := newAPITest(t, withDestRegistry(webhookStandardRegistry(t)))
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(
+			df.WithID("d1"),
+			df.WithTenantID("t1"),
+			df.WithType("webhook"),
+			df.WithTopics([]string{"user.created"}),
+			df.WithConfig(map[string]string{"url": server.URL + "/webhook"}),
+			df.WithCredentials(map[string]string{"secret": "whsec_dGVzdHNlY3JldDEyMzQ1Njc4OTBhYmNkZWY="}),
+		))
+		return h
+	}
+
+	t.Run("delivers a signed test event", func(t *testing.T) {
+		var received *http.Request
+		var body []byte
+		h := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			received = r
+			body, _ = io.ReadAll(r.Body)
+			w.WriteHeader(http.StatusOK)
+		})
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations/d1/test", nil)
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var attempt apirouter.APIAttempt
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &attempt))
+		assert.Equal(t, models.AttemptStatusSuccess, attempt.Status)
+		assert.Equal(t, "t1", attempt.TenantID)
+		assert.Equal(t, "d1", attempt.DestinationID)
+		event, ok := attempt.Event.(map[string]any)
+		require.True(t, ok)
+		assert.Equal(t, apirouter.TestEventTopic, event["topic"])
+		assert.Equal(t, attempt.EventID, event["id"])
+
+		require.NotNil(t, received)
+		assert.NotEmpty(t, received.Header.Get("webhook-signature"))
+		assert.Equal(t, attempt.EventID, received.Header.Get("webhook-id"))
+		assert.JSONEq(t, `{"type":"test","message":"This is a test event sent from Outpost."}`, string(body))
+	})
+
+	t.Run("failed delivery returns the failed attempt", func(t *testing.T) {
+		h := setup(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		})
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations/d1/test", nil)
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var attempt apirouter.APIAttempt
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &attempt))
+		assert.Equal(t, models.AttemptStatusFailed, attempt.Status)
+		assert.Equal(t, destregistry.ErrorCodeHTTPStatus, attempt.ErrorCode)
+	})
+
+	t.Run("test events are not logged", func(t *testing.T) {
+		h := setup(t, func(w http.ResponseWriter, r *http.Request) {})
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations/d1/test", nil)
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/tenants/t1/destinations/d1/attempts", nil)
+		resp = h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code)
+		var result apirouter.AttemptPaginatedResult
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &result))
+		assert.Empty(t, result.Models)
+	})
+
+	t.Run("unknown destination returns 404", func(t *testing.T) {
+		h := setup(t, func(w http.ResponseWriter, r *http.Request) {})
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations/missing/test", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+}
@@ -0,0 +1,95 @@
+package apirouter
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+)
+
+type ToolHandlers struct {
+	logger         *logging.Logger
+	tenantStore    tenantstore.TenantStore
+	logStore       logstore.LogStore
+	registry       destregistry.Registry
+	topicNamespace models.TopicNamespace
+}
+
+func NewToolHandlers(logger *logging.Logger, tenantStore tenantstore.TenantStore, logStore logstore.LogStore, registry destregistry.Registry, topicNamespace models.TopicNamespace) *ToolHandlers {
+	return &ToolHandlers{
+		logger:         logger,
+		tenantStore:    tenantStore,
+		logStore:       logStore,
+		registry:       registry,
+		topicNamespace: topicNamespace,
+	}
+}
+
+// VerifySignatureRequest is a request as received by a consumer's endpoint.
+// Payload is the raw request body and Headers the received headers. TenantID
+// is required with API key auth and ignored with JWT auth.
+type VerifySignatureRequest struct {
+	TenantID         string            `json:"tenant_id"`
+	DestinationID    string            `json:"destination_id" binding:"required"`
+	Payload          string            `json:"payload"`
+	Headers          map[string]string `json:"headers" binding:"required"`
+	ToleranceSeconds int               `json:"tolerance_seconds" binding:"min=0"`
+}
+
+// VerifySignature handles POST /tools/verify-signature. It reports whether
+// the signature of a received request verifies against the destination's
+// signing secrets and, when it does not, why.
+func (h *ToolHandlers) VerifySignature(c *gin.Context) {
+	var req VerifySignatureRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+
+	tenantID := tenantIDFromContext(c)
+	if tenantID == "" {
+		tenantID = req.TenantID
+	}
+	if tenantID == "" {
+		AbortWithValidationError(c, errors.New("tenant_id is required"))
+		return
+	}
+
+	destination, err := h.tenantStore.RetrieveDestination(c.Request.Context(), tenantID, req.DestinationID)
+	if err != nil && !errors.Is(err, tenantstore.ErrDestinationDeleted) {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	if destination == nil {
+		AbortWithError(c, http.StatusNotFound, NewErrNotFound("destination"))
+		return
+	}
+
+	provider, err := h.registry.ResolveProvider(destination)
+	if err != nil {
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	verifier, ok := provider.(destregistry.SignatureVerifier)
+	if !ok {
+		AbortWithValidationError(c, errors.New("destination type does not sign requests"))
+		return
+	}
+
+	result, err := verifier.VerifySignature(c.Request.Context(), destination, destregistry.SignatureVerificationRequest{
+		Body:      req.Payload,
+		Headers:   req.Headers,
+		Tolerance: time.Duration(req.ToleranceSeconds) * time.Second,
+	})
+	if err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,61 @@
+package apirouter_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPI_VerifySignature(t *testing.T) {
+	body := func(tenantID, destinationID string) map[string]any {
+		return map[string]any{
+			"tenant_id":      tenantID,
+			"destination_id": destinationID,
+			"payload":        `{"key":"value"}`,
+			"headers":        map[string]string{"x-outpost-signature": "v0=abc"},
+		}
+	}
+
+	t.Run("api key requires tenant_id", func(t *testing.T) {
+		h := newAPITest(t)
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tools/verify-signature", body("", "d1"))
+		resp := h.do(h.withAPIKey(req))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	t.Run("unknown destination returns 404", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tools/verify-signature", body("t1", "missing"))
+		resp := h.do(h.withAPIKey(req))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("jwt is scoped to its own tenant", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t2")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d2"), df.WithTenantID("t2")))
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tools/verify-signature", body("t2", "d2"))
+		resp := h.do(h.withJWT(req, "t1"))
+
+		assert.Equal(t, http.StatusNotFound, resp.Code)
+	})
+
+	t.Run("unsigned destination type returns 422", func(t *testing.T) {
+		h := newAPITest(t)
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		h.tenantStore.CreateDestination(t.Context(), df.Any(df.WithID("d1"), df.WithTenantID("t1")))
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/tools/verify-signature", body("t1", "d1"))
+		resp := h.do(h.withAPIKey(req))
+
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+}
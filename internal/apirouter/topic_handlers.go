@@ -1,24 +1,192 @@
 package apirouter
 
 import (
+	"context"
+	"errors"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/topicstore"
+	"go.uber.org/zap"
 )
 
+// topicLister lists the topics destinations can subscribe to.
+type topicLister interface {
+	Topics(ctx context.Context) []string
+}
+
+// topicStore manages the topics created at runtime and the lifecycle state
+// of topics. Satisfied by *topicstore.Store.
+type topicStore interface {
+	topicLister
+	models.TopicLifecycleSource
+	Create(ctx context.Context, topic string) (bool, error)
+	Delete(ctx context.Context, topic string) error
+	SetStatus(ctx context.Context, topic, status string) error
+}
+
+// staticTopics are the configured topics, when topics aren't managed at
+// runtime.
+type staticTopics []string
+
+func (t staticTopics) Topics(context.Context) []string {
+	return t
+}
+
 type TopicHandlers struct {
-	logger *logging.Logger
-	topics []string
+	logger    *logging.Logger
+	topics    topicLister
+	store     topicStore
+	lifecycle models.TopicLifecycleSource
 }
 
-func NewTopicHandlers(logger *logging.Logger, topics []string) *TopicHandlers {
+func NewTopicHandlers(logger *logging.Logger, topics topicLister, store topicStore, lifecycle models.TopicLifecycleSource) *TopicHandlers {
 	return &TopicHandlers{
-		logger: logger,
-		topics: topics,
+		logger:    logger,
+		topics:    topics,
+		store:     store,
+		lifecycle: lifecycle,
 	}
 }
 
+// List returns the topics destinations can subscribe to. Retired topics are
+// left out.
 func (h *TopicHandlers) List(c *gin.Context) {
-	c.JSON(http.StatusOK, h.topics)
+	available := h.topics.Topics(c.Request.Context())
+	lifecycle := h.lifecycle.Lifecycle(c.Request.Context())
+	topics := make([]string, 0, len(available))
+	for _, topic := range available {
+		if lifecycle.Status(topic) != models.TopicStatusRetired {
+			topics = append(topics, topic)
+		}
+	}
+	c.JSON(http.StatusOK, topics)
+}
+
+// TopicStatus is a topic and its lifecycle state.
+type TopicStatus struct {
+	Topic  string `json:"topic"`
+	Status string `json:"status"`
+}
+
+// ListStatus returns every topic, including retired ones, with its lifecycle
+// state.
+func (h *TopicHandlers) ListStatus(c *gin.Context) {
+	available := h.topics.Topics(c.Request.Context())
+	lifecycle := h.lifecycle.Lifecycle(c.Request.Context())
+	statuses := make([]TopicStatus, 0, len(available))
+	for _, topic := range available {
+		statuses = append(statuses, TopicStatus{Topic: topic, Status: lifecycle.Status(topic)})
+	}
+	c.JSON(http.StatusOK, statuses)
+}
+
+// Create adds a topic at runtime. It responds with 201 when the topic is
+// created, and 200 when it already exists.
+func (h *TopicHandlers) Create(c *gin.Context) {
+	if !h.mustHaveStore(c) {
+		return
+	}
+	var input struct {
+		Topic string `json:"topic" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	created, err := h.store.Create(c.Request.Context(), input.Topic)
+	if err != nil {
+		if errors.Is(err, topicstore.ErrInvalidTopic) {
+			AbortWithValidationError(c, ErrorResponse{
+				Code:    http.StatusUnprocessableEntity,
+				Message: "validation error",
+				Err:     err,
+				Data:    []string{err.Error()},
+			})
+			return
+		}
+		AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		return
+	}
+	result := TopicStatus{Topic: input.Topic, Status: h.lifecycle.Lifecycle(c.Request.Context()).Status(input.Topic)}
+	if !created {
+		c.JSON(http.StatusOK, result)
+		return
+	}
+	h.logger.Ctx(c.Request.Context()).Audit("topic created", zap.String("topic", input.Topic))
+	c.JSON(http.StatusCreated, result)
+}
+
+// Delete removes a topic created at runtime. Destinations subscribed to it
+// keep their subscription, but events can no longer be published to it.
+func (h *TopicHandlers) Delete(c *gin.Context) {
+	if !h.mustHaveStore(c) {
+		return
+	}
+	topic := c.Param("topic")
+	if err := h.store.Delete(c.Request.Context(), topic); err != nil {
+		switch {
+		case errors.Is(err, topicstore.ErrTopicNotFound):
+			AbortWithError(c, http.StatusNotFound, NewErrNotFound("topic"))
+		case errors.Is(err, topicstore.ErrConfiguredTopic):
+			AbortWithError(c, http.StatusConflict, ErrorResponse{
+				Code:    http.StatusConflict,
+				Message: err.Error(),
+			})
+		default:
+			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		}
+		return
+	}
+	h.logger.Ctx(c.Request.Context()).Audit("topic deleted", zap.String("topic", topic))
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// UpdateStatus sets the lifecycle state of a topic: active, deprecated or
+// retired. It overrides TOPICS_DEPRECATED and TOPICS_RETIRED for the topic.
+func (h *TopicHandlers) UpdateStatus(c *gin.Context) {
+	if !h.mustHaveStore(c) {
+		return
+	}
+	var input struct {
+		Status string `json:"status" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		AbortWithValidationError(c, err)
+		return
+	}
+	topic := c.Param("topic")
+	if err := h.store.SetStatus(c.Request.Context(), topic, input.Status); err != nil {
+		switch {
+		case errors.Is(err, topicstore.ErrTopicNotFound):
+			AbortWithError(c, http.StatusNotFound, NewErrNotFound("topic"))
+		case errors.Is(err, topicstore.ErrInvalidStatus):
+			AbortWithValidationError(c, ErrorResponse{
+				Code:    http.StatusUnprocessableEntity,
+				Message: "validation error",
+				Err:     err,
+				Data:    []string{err.Error()},
+			})
+		default:
+			AbortWithError(c, http.StatusInternalServerError, NewErrInternalServer(err))
+		}
+		return
+	}
+	h.logger.Ctx(c.Request.Context()).Audit("topic status updated",
+		zap.String("topic", topic),
+		zap.String("status", input.Status))
+	c.JSON(http.StatusOK, TopicStatus{Topic: topic, Status: input.Status})
+}
+
+func (h *TopicHandlers) mustHaveStore(c *gin.Context) bool {
+	if h.store != nil {
+		return true
+	}
+	AbortWithError(c, http.StatusNotImplemented, ErrorResponse{
+		Code:    http.StatusNotImplemented,
+		Message: "topics can't be managed at runtime",
+	})
+	return false
 }
@@ -4,8 +4,11 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"slices"
 	"testing"
 
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/models"
 	"github.com/hookdeck/outpost/internal/util/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -40,6 +43,41 @@ func TestAPI_Topics(t *testing.T) {
 		assert.Equal(t, testutil.TestTopics, topics)
 	})
 
+	t.Run("omits retired topics", func(t *testing.T) {
+		h := newAPITest(t, withTopicLifecycle(models.TopicLifecycle{Retired: []string{"user.deleted"}}))
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/topics", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var topics []string
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &topics))
+		assert.NotContains(t, topics, "user.deleted")
+		assert.Contains(t, topics, "user.created")
+	})
+
+	t.Run("status lists lifecycle of every topic", func(t *testing.T) {
+		h := newAPITest(t, withTopicLifecycle(models.TopicLifecycle{
+			Deprecated: []string{"user.updated"},
+			Retired:    []string{"user.deleted"},
+		}))
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/topics/status", nil)
+		resp := h.do(h.withAPIKey(req))
+
+		require.Equal(t, http.StatusOK, resp.Code)
+
+		var statuses []apirouter.TopicStatus
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &statuses))
+		require.Len(t, statuses, len(testutil.TestTopics))
+		byTopic := map[string]string{}
+		for _, s := range statuses {
+			byTopic[s.Topic] = s.Status
+		}
+		assert.Equal(t, models.TopicStatusActive, byTopic["user.created"])
+		assert.Equal(t, models.TopicStatusDeprecated, byTopic["user.updated"])
+		assert.Equal(t, models.TopicStatusRetired, byTopic["user.deleted"])
+	})
+
 	t.Run("without auth returns 401", func(t *testing.T) {
 		h := newAPITest(t)
 		req := httptest.NewRequest(http.MethodGet, "/api/v1/topics", nil)
@@ -48,3 +86,135 @@ func TestAPI_Topics(t *testing.T) {
 		require.Equal(t, http.StatusUnauthorized, resp.Code)
 	})
 }
+
+func TestAPI_RuntimeTopics(t *testing.T) {
+	t.Run("created topics are listed and accepted by destinations", func(t *testing.T) {
+		h := newAPITest(t, withTopicStore())
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		req := h.jsonReq(http.MethodPost, "/api/v1/topics", map[string]any{"topic": "order.created"})
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusCreated, resp.Code)
+
+		req = httptest.NewRequest(http.MethodGet, "/api/v1/topics", nil)
+		resp = h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code)
+		var topics []string
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &topics))
+		assert.Equal(t, append(slices.Clone(testutil.TestTopics), "order.created"), topics)
+
+		destination := validDestination()
+		destination["topics"] = []string{"order.created"}
+		req = h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", destination)
+		resp = h.do(h.withAPIKey(req))
+		assert.Equal(t, http.StatusCreated, resp.Code)
+	})
+
+	t.Run("creating an existing topic returns 200", func(t *testing.T) {
+		h := newAPITest(t, withTopicStore())
+		req := h.jsonReq(http.MethodPost, "/api/v1/topics", map[string]any{"topic": testutil.TestTopics[0]})
+		resp := h.do(h.withAPIKey(req))
+		assert.Equal(t, http.StatusOK, resp.Code)
+	})
+
+	t.Run("invalid topic returns 422", func(t *testing.T) {
+		h := newAPITest(t, withTopicStore())
+		req := h.jsonReq(http.MethodPost, "/api/v1/topics", map[string]any{"topic": "order created"})
+		resp := h.do(h.withAPIKey(req))
+		assert.Equal(t, http.StatusUnprocessableEntity, resp.Code)
+	})
+
+	t.Run("deletes runtime topics", func(t *testing.T) {
+		h := newAPITest(t, withTopicStore())
+		req := h.jsonReq(http.MethodPost, "/api/v1/topics", map[string]any{"topic": "order.created"})
+		require.Equal(t, http.StatusCreated, h.do(h.withAPIKey(req)).Code)
+
+		req = httptest.NewRequest(http.MethodDelete, "/api/v1/topics/order.created", nil)
+		assert.Equal(t, http.StatusOK, h.do(h.withAPIKey(req)).Code)
+
+		req = httptest.NewRequest(http.MethodDelete, "/api/v1/topics/order.created", nil)
+		assert.Equal(t, http.StatusNotFound, h.do(h.withAPIKey(req)).Code)
+	})
+
+	t.Run("configured topics can't be deleted", func(t *testing.T) {
+		h := newAPITest(t, withTopicStore())
+		req := httptest.NewRequest(http.MethodDelete, "/api/v1/topics/"+testutil.TestTopics[0], nil)
+		assert.Equal(t, http.StatusConflict, h.do(h.withAPIKey(req)).Code)
+	})
+
+	t.Run("requires API key", func(t *testing.T) {
+		h := newAPITest(t, withTopicStore())
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		req := h.jsonReq(http.MethodPost, "/api/v1/topics", map[string]any{"topic": "order.created"})
+		assert.Equal(t, http.StatusForbidden, h.do(h.withJWT(req, "t1")).Code)
+	})
+
+	t.Run("without a topic store returns 501", func(t *testing.T) {
+		h := newAPITest(t)
+		req := h.jsonReq(http.MethodPost, "/api/v1/topics", map[string]any{"topic": "order.created"})
+		assert.Equal(t, http.StatusNotImplemented, h.do(h.withAPIKey(req)).Code)
+	})
+}
+
+func TestAPI_TopicStatus(t *testing.T) {
+	setStatus := func(h *apiTest, topic, status string) *httptest.ResponseRecorder {
+		req := h.jsonReq(http.MethodPut, "/api/v1/topics/"+topic+"/status", map[string]any{"status": status})
+		return h.do(h.withAPIKey(req))
+	}
+
+	t.Run("retiring a topic rejects new subscriptions until reactivated", func(t *testing.T) {
+		h := newAPITest(t, withTopicStore())
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+
+		resp := setStatus(h, "user.deleted", models.TopicStatusRetired)
+		require.Equal(t, http.StatusOK, resp.Code)
+		var status apirouter.TopicStatus
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &status))
+		assert.Equal(t, apirouter.TopicStatus{Topic: "user.deleted", Status: models.TopicStatusRetired}, status)
+
+		destination := validDestination()
+		destination["topics"] = []string{"user.deleted"}
+		req := h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", destination)
+		assert.Equal(t, http.StatusUnprocessableEntity, h.do(h.withAPIKey(req)).Code)
+
+		require.Equal(t, http.StatusOK, setStatus(h, "user.deleted", models.TopicStatusActive).Code)
+		req = h.jsonReq(http.MethodPost, "/api/v1/tenants/t1/destinations", destination)
+		assert.Equal(t, http.StatusCreated, h.do(h.withAPIKey(req)).Code)
+	})
+
+	t.Run("overrides the configured lifecycle", func(t *testing.T) {
+		h := newAPITest(t, withTopicStore(), withTopicLifecycle(models.TopicLifecycle{
+			Deprecated: []string{"user.updated"},
+		}))
+		require.Equal(t, http.StatusOK, setStatus(h, "user.updated", models.TopicStatusActive).Code)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/topics/status", nil)
+		resp := h.do(h.withAPIKey(req))
+		require.Equal(t, http.StatusOK, resp.Code)
+		var statuses []apirouter.TopicStatus
+		require.NoError(t, json.Unmarshal(resp.Body.Bytes(), &statuses))
+		assert.Contains(t, statuses, apirouter.TopicStatus{Topic: "user.updated", Status: models.TopicStatusActive})
+	})
+
+	t.Run("invalid status returns 422", func(t *testing.T) {
+		h := newAPITest(t, withTopicStore())
+		assert.Equal(t, http.StatusUnprocessableEntity, setStatus(h, "user.created", "sunset").Code)
+	})
+
+	t.Run("unknown topic returns 404", func(t *testing.T) {
+		h := newAPITest(t, withTopicStore())
+		assert.Equal(t, http.StatusNotFound, setStatus(h, "order.created", models.TopicStatusRetired).Code)
+	})
+
+	t.Run("requires API key", func(t *testing.T) {
+		h := newAPITest(t, withTopicStore())
+		h.tenantStore.UpsertTenant(t.Context(), tf.Any(tf.WithID("t1")))
+		req := h.jsonReq(http.MethodPut, "/api/v1/topics/user.created/status", map[string]any{"status": models.TopicStatusRetired})
+		assert.Equal(t, http.StatusForbidden, h.do(h.withJWT(req, "t1")).Code)
+	})
+
+	t.Run("without a topic store returns 501", func(t *testing.T) {
+		h := newAPITest(t)
+		assert.Equal(t, http.StatusNotImplemented, setStatus(h, "user.created", models.TopicStatusRetired).Code)
+	})
+}
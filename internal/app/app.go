@@ -4,12 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/hookdeck/outpost/internal/clickhouse"
+	"github.com/hookdeck/outpost/internal/clock"
 	"github.com/hookdeck/outpost/internal/config"
 	"github.com/hookdeck/outpost/internal/idgen"
 	"github.com/hookdeck/outpost/internal/infra"
@@ -26,6 +28,7 @@ import (
 type App struct {
 	config *config.Config
 	logger *logging.Logger
+	clock  clock.Clock
 
 	// Runtime dependencies
 	redisClient    redis.Cmdable
@@ -34,12 +37,53 @@ type App struct {
 	supervisor     *worker.WorkerSupervisor
 	otelShutdown   func(context.Context) error
 	installationID string
+
+	// builderOpts are passed through to the service builder.
+	builderOpts []services.ServiceBuilderOption
+}
+
+type Option func(*App)
+
+// WithClock overrides the time source of retry scheduling and log retention.
+// It is meant for tests; every replica of a deployment must share the clock.
+func WithClock(c clock.Clock) Option {
+	return func(a *App) {
+		a.clock = c
+	}
+}
+
+// WithLogger replaces the logger built from the log level config.
+func WithLogger(logger *logging.Logger) Option {
+	return func(a *App) {
+		a.logger = logger
+	}
+}
+
+// WithRedisClient shares the given Redis client with the application and its
+// services instead of connecting with the Redis config.
+func WithRedisClient(client redis.Client) Option {
+	return func(a *App) {
+		a.redisClient = client
+		a.builderOpts = append(a.builderOpts, services.WithRedisClient(client))
+	}
+}
+
+// WithServiceBuilderOptions passes options through to the service builder,
+// such as injected stores.
+func WithServiceBuilderOptions(opts ...services.ServiceBuilderOption) Option {
+	return func(a *App) {
+		a.builderOpts = append(a.builderOpts, opts...)
+	}
 }
 
-func New(cfg *config.Config) *App {
-	return &App{
+func New(cfg *config.Config, opts ...Option) *App {
+	a := &App{
 		config: cfg,
 	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
 }
 
 func (a *App) Run(ctx context.Context) error {
@@ -51,6 +95,21 @@ func (a *App) Run(ctx context.Context) error {
 	return a.run(ctx)
 }
 
+// RunWorkers runs the workers built by PreRun until ctx is canceled or a
+// worker fails. Unlike Run it leaves signal handling and cleanup to the
+// caller, for programs that embed Outpost.
+func (a *App) RunWorkers(ctx context.Context) error {
+	return a.supervisor.Run(ctx)
+}
+
+// Handler returns the HTTP handler of the services built by PreRun.
+func (a *App) Handler() http.Handler {
+	if a.builder == nil {
+		return nil
+	}
+	return a.builder.Handler()
+}
+
 // PreRun initializes all dependencies before starting the application
 func (a *App) PreRun(ctx context.Context) (err error) {
 	if err := a.setupLogger(); err != nil {
@@ -165,6 +224,9 @@ func (a *App) run(ctx context.Context) error {
 }
 
 func (a *App) setupLogger() error {
+	if a.logger != nil {
+		return nil
+	}
 	logger, err := logging.NewLogger(
 		logging.WithLogLevel(a.config.LogLevel),
 	)
@@ -195,6 +257,9 @@ func (a *App) configureIDGenerators() error {
 }
 
 func (a *App) initializeRedis(ctx context.Context) error {
+	if a.redisClient != nil {
+		return nil
+	}
 	a.logger.Debug("initializing Redis client for infrastructure")
 	redisClient, err := redis.New(ctx, a.config.Redis.ToConfig())
 	if err != nil {
@@ -253,7 +318,11 @@ func (a *App) setupOpenTelemetry(ctx context.Context) error {
 
 func (a *App) buildServices(ctx context.Context) error {
 	a.logger.Debug("building services")
-	builder := services.NewServiceBuilder(ctx, a.config, a.logger, a.telemetry)
+	builderOpts := a.builderOpts
+	if a.clock != nil {
+		builderOpts = append(builderOpts, services.WithClock(a.clock))
+	}
+	builder := services.NewServiceBuilder(ctx, a.config, a.logger, a.telemetry, builderOpts...)
 
 	supervisor, err := builder.BuildWorkers()
 	if err != nil {
@@ -281,5 +350,5 @@ func (a *App) applyLogRetentionTTL(ctx context.Context) error {
 	}
 	defer chConn.Close()
 
-	return logretention.Apply(ctx, a.redisClient, chConn, a.config.DeploymentID, a.config.ClickHouseLogRetentionTTLDays, a.logger)
+	return logretention.Apply(ctx, a.redisClient, chConn, a.config.DeploymentID, a.config.LogRetentionPolicy(), a.logger)
 }
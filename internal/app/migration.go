@@ -64,6 +64,25 @@ func checkPendingMigrations(
 		return fmt.Errorf("check pending migrations: %w", err)
 	}
 
+	if tierOpts, ok := cfg.ToLogTierMigratorOpts(); ok {
+		m, err := migrator.New(tierOpts)
+		if err != nil {
+			return fmt.Errorf("create log tier sql migrator for pending check: %w", err)
+		}
+		defer func() {
+			if sourceErr, dbErr := m.Close(ctx); sourceErr != nil || dbErr != nil {
+				logger.Warn("failed to close log tier sql migrator during pending check",
+					zap.NamedError("source_err", sourceErr),
+					zap.NamedError("db_err", dbErr))
+			}
+		}()
+		tierSummary, err := coordinator.New(coordinator.Config{SQLMigrator: m, Logger: logger}).PendingSummary(ctx)
+		if err != nil {
+			return fmt.Errorf("check pending log tier migrations: %w", err)
+		}
+		summary.SQLPending += tierSummary.SQLPending
+	}
+
 	if !summary.HasPending() {
 		logger.Info("no pending migrations")
 		return nil
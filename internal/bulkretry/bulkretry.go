@@ -0,0 +1,626 @@
+// Package bulkretry retries every delivery matching a filter as a background
+// job.
+//
+// A job scans a tenant's attempts with the filter's status in the log store,
+// keeps the latest attempt of each event and destination pair, and publishes
+// a bulk delivery task for the pairs that did not reach the other outcome
+// later. Bulk tasks count against the delivery worker's retry limiter like
+// automatic retries do, so a large job cannot starve first attempts.
+//
+// Job progress is stored in Redis so any API instance can report on it; the
+// scan itself runs in the process that accepted the request. Jobs are not
+// resumed: a job interrupted by shutdown is marked failed, and a job whose
+// process died is marked failed once its progress is staleAfter old.
+package bulkretry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrNotFound      = errors.New("bulk retry job not found")
+	ErrInvalidFilter = errors.New("validation failed: invalid bulk retry filter")
+	ErrTooManyJobs   = errors.New("tenant has too many running bulk retry jobs")
+	ErrStopped       = errors.New("bulk retry runner is stopped")
+)
+
+const (
+	// jobTTL is how long a job's progress stays retrievable after it was last
+	// updated.
+	jobTTL = 24 * time.Hour
+	// pageSize is the number of attempts read from the log store per query.
+	pageSize = 1000
+	// MaxTimeRange is the longest time range a job may scan.
+	MaxTimeRange = 7 * 24 * time.Hour
+	// MaxDeliveries is the most deliveries a job may match. It bounds the
+	// memory a job uses to keep only the latest attempt of each delivery.
+	MaxDeliveries = 100_000
+	// MaxRunningPerTenant is the most jobs a tenant may run at once.
+	MaxRunningPerTenant = 1
+	// staleAfter is how long a running job may go without saving progress
+	// before it is considered abandoned by a process that died. Progress is
+	// saved after every page, which takes far less.
+	staleAfter = 10 * time.Minute
+)
+
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+// Filter selects the deliveries a job retries. Deliveries are matched on
+// their latest failed or successful attempt within the time range, which is
+// required.
+type Filter struct {
+	DestinationIDs []string `json:"destination_ids,omitempty"`
+	Topics         []string `json:"topics,omitempty"`
+	// Status is the status of the latest attempt: "failed" (default) or
+	// "success".
+	Status string     `json:"status"`
+	Start  *time.Time `json:"start,omitempty"`
+	End    *time.Time `json:"end,omitempty"`
+}
+
+// Validate fills in the default status and rejects unknown statuses and
+// missing, inverted or too long time ranges.
+func (f *Filter) Validate() error {
+	switch f.Status {
+	case "":
+		f.Status = models.AttemptStatusFailed
+	case models.AttemptStatusFailed, models.AttemptStatusSuccess:
+	default:
+		return fmt.Errorf("%w: status must be %s or %s", ErrInvalidFilter, models.AttemptStatusFailed, models.AttemptStatusSuccess)
+	}
+	if f.Start == nil || f.End == nil {
+		return fmt.Errorf("%w: start and end are required", ErrInvalidFilter)
+	}
+	if f.End.Before(*f.Start) {
+		return fmt.Errorf("%w: end must not be before start", ErrInvalidFilter)
+	}
+	if f.End.Sub(*f.Start) > MaxTimeRange {
+		return fmt.Errorf("%w: time range must not exceed %s", ErrInvalidFilter, MaxTimeRange)
+	}
+	return nil
+}
+
+// otherStatus returns the outcome that supersedes an attempt with the
+// filter's status.
+func (f *Filter) otherStatus() string {
+	if f.Status == models.AttemptStatusSuccess {
+		return models.AttemptStatusFailed
+	}
+	return models.AttemptStatusSuccess
+}
+
+// Job is the progress of a bulk retry.
+type Job struct {
+	ID       string `json:"id"`
+	TenantID string `json:"tenant_id"`
+	Status   Status `json:"status"`
+	Filter   Filter `json:"filter"`
+	// Matched counts the deliveries whose latest attempt matched the filter.
+	// Each is either enqueued or skipped because its destination was deleted,
+	// disabled or no longer matches the event, or because the stored event
+	// data no longer matches its checksum.
+	Matched  int `json:"matched"`
+	Enqueued int `json:"enqueued"`
+	Skipped  int `json:"skipped"`
+	// ChecksumMismatches counts the skipped deliveries whose event data no
+	// longer matches its checksum.
+	ChecksumMismatches int        `json:"checksum_mismatches"`
+	Error              string     `json:"error,omitempty"`
+	CreatedAt          time.Time  `json:"created_at"`
+	UpdatedAt          time.Time  `json:"updated_at"`
+	CompletedAt        *time.Time `json:"completed_at"`
+}
+
+type Store interface {
+	Save(ctx context.Context, job *Job) error
+	// Retrieve returns the tenant's job, or ErrNotFound once it expired.
+	Retrieve(ctx context.Context, tenantID, jobID string) (*Job, error)
+	// Claim adds the job to the tenant's running jobs, or returns
+	// ErrTooManyJobs when the tenant already runs limit jobs.
+	Claim(ctx context.Context, tenantID, jobID string, limit int) error
+	// Release removes the job from the tenant's running jobs.
+	Release(ctx context.Context, tenantID, jobID string) error
+	// Running returns the IDs of the tenant's running jobs.
+	Running(ctx context.Context, tenantID string) ([]string, error)
+}
+
+type redisStore struct {
+	redisClient  redis.Cmdable
+	deploymentID string
+}
+
+type Option func(*redisStore)
+
+func WithDeploymentID(deploymentID string) Option {
+	return func(s *redisStore) {
+		s.deploymentID = deploymentID
+	}
+}
+
+// NewStore returns a Store backed by Redis. Each job is a key that expires
+// jobTTL after its last update.
+func NewStore(redisClient redis.Cmdable, opts ...Option) Store {
+	s := &redisStore{redisClient: redisClient}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *redisStore) key(tenantID, jobID string) string {
+	if s.deploymentID == "" {
+		return "bulkretry:" + tenantID + ":" + jobID
+	}
+	return s.deploymentID + ":bulkretry:" + tenantID + ":" + jobID
+}
+
+// runningKey is the set of the tenant's running job IDs.
+func (s *redisStore) runningKey(tenantID string) string {
+	if s.deploymentID == "" {
+		return "bulkretry-running:" + tenantID
+	}
+	return s.deploymentID + ":bulkretry-running:" + tenantID
+}
+
+func (s *redisStore) Save(ctx context.Context, job *Job) error {
+	value, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+	return s.redisClient.Set(ctx, s.key(job.TenantID, job.ID), value, jobTTL).Err()
+}
+
+func (s *redisStore) Retrieve(ctx context.Context, tenantID, jobID string) (*Job, error) {
+	value, err := s.redisClient.Get(ctx, s.key(tenantID, jobID)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	var job Job
+	if err := json.Unmarshal(value, &job); err != nil {
+		return nil, fmt.Errorf("invalid bulk retry job: %w", err)
+	}
+	return &job, nil
+}
+
+func (s *redisStore) Claim(ctx context.Context, tenantID, jobID string, limit int) error {
+	key := s.runningKey(tenantID)
+	var count *redis.IntCmd
+	_, err := s.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.SAdd(ctx, key, jobID)
+		count = pipe.SCard(ctx, key)
+		pipe.Expire(ctx, key, jobTTL)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if int(count.Val()) > limit {
+		if err := s.redisClient.SRem(ctx, key, jobID).Err(); err != nil {
+			return err
+		}
+		return ErrTooManyJobs
+	}
+	return nil
+}
+
+func (s *redisStore) Release(ctx context.Context, tenantID, jobID string) error {
+	return s.redisClient.SRem(ctx, s.runningKey(tenantID), jobID).Err()
+}
+
+func (s *redisStore) Running(ctx context.Context, tenantID string) ([]string, error) {
+	return s.redisClient.SMembers(ctx, s.runningKey(tenantID)).Result()
+}
+
+type attemptLister interface {
+	ListAttempt(ctx context.Context, req logstore.ListAttemptRequest) (logstore.ListAttemptResponse, error)
+}
+
+type destinationRetriever interface {
+	RetrieveDestination(ctx context.Context, tenantID, destinationID string) (*models.Destination, error)
+}
+
+type deliveryPublisher interface {
+	Publish(ctx context.Context, task models.DeliveryTask) error
+}
+
+// Runner starts bulk retry jobs and runs them to completion. Jobs run until
+// Stop, which interrupts them and waits for them to record the interruption.
+type Runner struct {
+	logger    *logging.Logger
+	store     Store
+	logStore  attemptLister
+	tenants   destinationRetriever
+	publisher deliveryPublisher
+	clock     clock.Clock
+	namespace models.TopicNamespace
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu      sync.Mutex
+	stopped bool
+	// active holds the IDs of the jobs running in this process, which are
+	// never considered stale.
+	active map[string]struct{}
+}
+
+type RunnerOption func(*Runner)
+
+// WithClock sets the clock for job timestamps and staleness.
+func WithClock(c clock.Clock) RunnerOption {
+	return func(r *Runner) {
+		r.clock = c
+	}
+}
+
+// WithTopicNamespace sets the namespace of the topics of stored events. Job
+// topic filters and destination subscriptions use topics without it.
+func WithTopicNamespace(namespace models.TopicNamespace) RunnerOption {
+	return func(r *Runner) {
+		r.namespace = namespace
+	}
+}
+
+func NewRunner(logger *logging.Logger, store Store, logStore attemptLister, tenants destinationRetriever, publisher deliveryPublisher, opts ...RunnerOption) *Runner {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &Runner{
+		logger:    logger,
+		store:     store,
+		logStore:  logStore,
+		tenants:   tenants,
+		publisher: publisher,
+		clock:     clock.New(),
+		ctx:       ctx,
+		cancel:    cancel,
+		active:    make(map[string]struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// Start records a new job for the tenant and runs it in the background. It
+// returns ErrTooManyJobs when the tenant already runs MaxRunningPerTenant
+// jobs.
+func (r *Runner) Start(ctx context.Context, tenantID string, filter Filter) (*Job, error) {
+	if err := filter.Validate(); err != nil {
+		return nil, err
+	}
+	if err := r.failStale(ctx, tenantID); err != nil {
+		return nil, err
+	}
+	now := r.clock.Now()
+	job := &Job{
+		ID:        idgen.String(),
+		TenantID:  tenantID,
+		Status:    StatusRunning,
+		Filter:    filter,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	r.mu.Lock()
+	if r.stopped {
+		r.mu.Unlock()
+		return nil, ErrStopped
+	}
+	r.active[job.ID] = struct{}{}
+	r.wg.Add(1)
+	r.mu.Unlock()
+
+	started, err := r.claim(ctx, job)
+	if err != nil {
+		r.done(job)
+		return nil, err
+	}
+	go func() {
+		defer r.done(job)
+		r.Run(r.ctx, job)
+	}()
+	return started, nil
+}
+
+// claim adds the job to the tenant's running jobs and saves it, returning a
+// copy of the saved job.
+func (r *Runner) claim(ctx context.Context, job *Job) (*Job, error) {
+	if err := r.store.Claim(ctx, job.TenantID, job.ID, MaxRunningPerTenant); err != nil {
+		return nil, err
+	}
+	if err := r.store.Save(ctx, job); err != nil {
+		if releaseErr := r.store.Release(ctx, job.TenantID, job.ID); releaseErr != nil {
+			err = errors.Join(err, releaseErr)
+		}
+		return nil, err
+	}
+	started := *job
+	return &started, nil
+}
+
+func (r *Runner) done(job *Job) {
+	r.mu.Lock()
+	delete(r.active, job.ID)
+	r.mu.Unlock()
+	r.wg.Done()
+}
+
+// Stop interrupts the running jobs and waits for them to be saved as failed.
+// Jobs started after Stop are rejected with ErrStopped.
+func (r *Runner) Stop() {
+	r.mu.Lock()
+	r.stopped = true
+	r.mu.Unlock()
+	r.cancel()
+	r.wg.Wait()
+}
+
+// Run scans the job's attempts, publishes the retries and saves the final
+// job state. A job interrupted by ctx is saved as failed.
+func (r *Runner) Run(ctx context.Context, job *Job) {
+	err := r.run(ctx, job)
+	if err != nil && ctx.Err() != nil {
+		err = fmt.Errorf("interrupted: %w", ctx.Err())
+	}
+	// The final state is saved even when the job was interrupted.
+	r.finish(context.WithoutCancel(ctx), job, err)
+}
+
+// finish saves the job as completed, or failed when err is set, and removes
+// it from the tenant's running jobs.
+func (r *Runner) finish(ctx context.Context, job *Job, err error) {
+	now := r.clock.Now()
+	job.UpdatedAt = now
+	job.CompletedAt = &now
+	job.Status = StatusCompleted
+	if err != nil {
+		job.Status = StatusFailed
+		job.Error = err.Error()
+		r.logger.Ctx(ctx).Error("bulk retry failed",
+			zap.String("job_id", job.ID),
+			zap.String("tenant_id", job.TenantID),
+			zap.Error(err))
+	}
+	if err := r.store.Save(ctx, job); err != nil {
+		r.logger.Ctx(ctx).Error("failed to save bulk retry job",
+			zap.String("job_id", job.ID),
+			zap.String("tenant_id", job.TenantID),
+			zap.Error(err))
+		return
+	}
+	if err := r.store.Release(ctx, job.TenantID, job.ID); err != nil {
+		r.logger.Ctx(ctx).Error("failed to release bulk retry job",
+			zap.String("job_id", job.ID),
+			zap.String("tenant_id", job.TenantID),
+			zap.Error(err))
+	}
+	r.logger.Ctx(ctx).Audit("bulk retry finished",
+		zap.String("job_id", job.ID),
+		zap.String("tenant_id", job.TenantID),
+		zap.String("status", string(job.Status)),
+		zap.Int("matched", job.Matched),
+		zap.Int("enqueued", job.Enqueued),
+		zap.Int("skipped", job.Skipped))
+}
+
+func (r *Runner) run(ctx context.Context, job *Job) error {
+	// Attempts are read newest first, so the first attempt seen for a pair is
+	// its latest one with the filter's status and older ones are ignored.
+	// Only pairs with that status are kept, so MaxDeliveries bounds seen.
+	seen := make(map[string]struct{})
+	destinations := make(map[string]*models.Destination)
+	req := logstore.ListAttemptRequest{
+		TenantIDs:      []string{job.TenantID},
+		DestinationIDs: job.Filter.DestinationIDs,
+		Topics:         r.namespace.ApplyAll(job.Filter.Topics),
+		Status:         job.Filter.Status,
+		TimeFilter: logstore.TimeFilter{
+			GTE: job.Filter.Start,
+			LTE: job.Filter.End,
+		},
+		Limit:     pageSize,
+		SortOrder: "desc",
+	}
+	for {
+		resp, err := r.logStore.ListAttempt(ctx, req)
+		if err != nil {
+			return fmt.Errorf("failed to list attempts: %w", err)
+		}
+		var latest []*logstore.AttemptRecord
+		for _, record := range resp.Data {
+			if record.Event == nil {
+				continue
+			}
+			pair := deliveryPair(record.Event.ID, record.Attempt.DestinationID)
+			if _, ok := seen[pair]; ok {
+				continue
+			}
+			if len(seen) >= MaxDeliveries {
+				return fmt.Errorf("filter matches more than %d deliveries; narrow the time range", MaxDeliveries)
+			}
+			seen[pair] = struct{}{}
+			latest = append(latest, record)
+		}
+		superseded, err := r.superseded(ctx, job, latest)
+		if err != nil {
+			return err
+		}
+		for _, record := range latest {
+			if _, ok := superseded[deliveryPair(record.Event.ID, record.Attempt.DestinationID)]; ok {
+				continue
+			}
+			job.Matched++
+
+			destination, err := r.destination(ctx, destinations, job.TenantID, record.Attempt.DestinationID)
+			if err != nil {
+				return err
+			}
+			if destination == nil || destination.DisabledAt != nil || !destination.MatchEvent(r.namespace.StripEvent(*record.Event)) {
+				job.Skipped++
+				continue
+			}
+			if err := record.Event.VerifyChecksum(); err != nil {
+				job.Skipped++
+				job.ChecksumMismatches++
+				continue
+			}
+			task := models.NewBulkDeliveryTask(*record.Event, destination.ID, record.Attempt.AttemptNumber+1)
+			if err := r.publisher.Publish(ctx, task); err != nil {
+				return fmt.Errorf("failed to publish retry: %w", err)
+			}
+			job.Enqueued++
+		}
+		if resp.Next == "" {
+			return nil
+		}
+		req.Next = resp.Next
+		// Save progress between pages so the job can be followed while it
+		// runs and is not mistaken for an abandoned one.
+		job.UpdatedAt = r.clock.Now()
+		if err := r.store.Save(ctx, job); err != nil {
+			return fmt.Errorf("failed to save progress: %w", err)
+		}
+	}
+}
+
+// superseded returns the pairs among records that have a later attempt with
+// the other outcome within the job's time range. Those deliveries already
+// reached that outcome and are not retried.
+func (r *Runner) superseded(ctx context.Context, job *Job, records []*logstore.AttemptRecord) (map[string]struct{}, error) {
+	superseded := make(map[string]struct{})
+	if len(records) == 0 {
+		return superseded, nil
+	}
+	times := make(map[string]time.Time, len(records))
+	eventIDs := make([]string, 0, len(records))
+	for _, record := range records {
+		pair := deliveryPair(record.Event.ID, record.Attempt.DestinationID)
+		if _, ok := times[pair]; !ok {
+			eventIDs = append(eventIDs, record.Event.ID)
+		}
+		times[pair] = record.Attempt.Time
+	}
+	req := logstore.ListAttemptRequest{
+		TenantIDs:      []string{job.TenantID},
+		EventIDs:       eventIDs,
+		DestinationIDs: job.Filter.DestinationIDs,
+		Status:         job.Filter.otherStatus(),
+		TimeFilter: logstore.TimeFilter{
+			GTE: job.Filter.Start,
+			LTE: job.Filter.End,
+		},
+		Limit: pageSize,
+	}
+	for {
+		resp, err := r.logStore.ListAttempt(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list attempts: %w", err)
+		}
+		for _, record := range resp.Data {
+			pair := deliveryPair(record.Attempt.EventID, record.Attempt.DestinationID)
+			if at, ok := times[pair]; ok && record.Attempt.Time.After(at) {
+				superseded[pair] = struct{}{}
+			}
+		}
+		if resp.Next == "" {
+			return superseded, nil
+		}
+		req.Next = resp.Next
+	}
+}
+
+func deliveryPair(eventID, destinationID string) string {
+	return eventID + ":" + destinationID
+}
+
+// destination returns the destination from the job's cache, retrieving it on
+// first use. A deleted destination is cached as nil.
+func (r *Runner) destination(ctx context.Context, cache map[string]*models.Destination, tenantID, destinationID string) (*models.Destination, error) {
+	if destination, ok := cache[destinationID]; ok {
+		return destination, nil
+	}
+	destination, err := r.tenants.RetrieveDestination(ctx, tenantID, destinationID)
+	if errors.Is(err, tenantstore.ErrDestinationDeleted) {
+		destination, err = nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve destination: %w", err)
+	}
+	cache[destinationID] = destination
+	return destination, nil
+}
+
+// Retrieve returns the tenant's job from the runner's store. A running job
+// abandoned by a process that died is reported, and saved, as failed.
+func (r *Runner) Retrieve(ctx context.Context, tenantID, jobID string) (*Job, error) {
+	job, err := r.store.Retrieve(ctx, tenantID, jobID)
+	if err != nil {
+		return nil, err
+	}
+	if r.stale(job) {
+		r.finish(ctx, job, errAbandoned)
+	}
+	return job, nil
+}
+
+var errAbandoned = errors.New("interrupted: the process running the job stopped")
+
+// stale reports whether job is running without progress for staleAfter and
+// not in this process.
+func (r *Runner) stale(job *Job) bool {
+	if job.Status != StatusRunning || r.clock.Now().Sub(job.UpdatedAt) < staleAfter {
+		return false
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	_, ok := r.active[job.ID]
+	return !ok
+}
+
+// failStale fails the tenant's abandoned jobs and releases expired ones, so
+// they stop counting against MaxRunningPerTenant.
+func (r *Runner) failStale(ctx context.Context, tenantID string) error {
+	jobIDs, err := r.store.Running(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	for _, jobID := range jobIDs {
+		job, err := r.store.Retrieve(ctx, tenantID, jobID)
+		if errors.Is(err, ErrNotFound) {
+			if err := r.store.Release(ctx, tenantID, jobID); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		if r.stale(job) {
+			r.finish(ctx, job, errAbandoned)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,228 @@
+package bulkretry_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/bulkretry"
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+type mockPublisher struct {
+	tasks []models.DeliveryTask
+}
+
+func (m *mockPublisher) Publish(_ context.Context, task models.DeliveryTask) error {
+	m.tasks = append(m.tasks, task)
+	return nil
+}
+
+func TestFilter_Validate(t *testing.T) {
+	t.Parallel()
+
+	end := time.Now()
+	start := end.Add(-time.Hour)
+
+	t.Run("defaults status to failed", func(t *testing.T) {
+		t.Parallel()
+		filter := bulkretry.Filter{Start: &start, End: &end}
+		require.NoError(t, filter.Validate())
+		assert.Equal(t, models.AttemptStatusFailed, filter.Status)
+	})
+
+	t.Run("requires a time range", func(t *testing.T) {
+		t.Parallel()
+		filter := bulkretry.Filter{Start: &start}
+		assert.ErrorIs(t, filter.Validate(), bulkretry.ErrInvalidFilter)
+	})
+
+	t.Run("rejects time range over the maximum", func(t *testing.T) {
+		t.Parallel()
+		early := end.Add(-bulkretry.MaxTimeRange - time.Second)
+		filter := bulkretry.Filter{Start: &early, End: &end}
+		assert.ErrorIs(t, filter.Validate(), bulkretry.ErrInvalidFilter)
+	})
+
+	t.Run("rejects unknown status", func(t *testing.T) {
+		t.Parallel()
+		filter := bulkretry.Filter{Status: "pending", Start: &start, End: &end}
+		assert.ErrorIs(t, filter.Validate(), bulkretry.ErrInvalidFilter)
+	})
+
+	t.Run("rejects inverted time range", func(t *testing.T) {
+		t.Parallel()
+		filter := bulkretry.Filter{Start: &end, End: &start}
+		assert.ErrorIs(t, filter.Validate(), bulkretry.ErrInvalidFilter)
+	})
+}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	store := bulkretry.NewStore(testutil.CreateTestRedisClient(t), bulkretry.WithDeploymentID("dp_test"))
+	job := &bulkretry.Job{
+		ID:        "job_1",
+		TenantID:  "t1",
+		Status:    bulkretry.StatusRunning,
+		Filter:    bulkretry.Filter{Status: models.AttemptStatusFailed},
+		CreatedAt: time.Now().UTC().Truncate(time.Second),
+	}
+	require.NoError(t, store.Save(t.Context(), job))
+
+	got, err := store.Retrieve(t.Context(), "t1", "job_1")
+	require.NoError(t, err)
+	assert.Equal(t, job, got)
+
+	_, err = store.Retrieve(t.Context(), "t2", "job_1")
+	assert.ErrorIs(t, err, bulkretry.ErrNotFound)
+
+	t.Run("claim limits running jobs per tenant", func(t *testing.T) {
+		require.NoError(t, store.Claim(t.Context(), "t3", "job_1", 1))
+		assert.ErrorIs(t, store.Claim(t.Context(), "t3", "job_2", 1), bulkretry.ErrTooManyJobs)
+		require.NoError(t, store.Claim(t.Context(), "t4", "job_2", 1))
+
+		running, err := store.Running(t.Context(), "t3")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"job_1"}, running)
+
+		require.NoError(t, store.Release(t.Context(), "t3", "job_1"))
+		require.NoError(t, store.Claim(t.Context(), "t3", "job_2", 1))
+	})
+}
+
+func TestRunner_Run(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	logStore := logstore.NewMemLogStore()
+	tenantStore := tenantstore.NewMemTenantStore()
+	publisher := &mockPublisher{}
+	store := bulkretry.NewStore(testutil.CreateTestRedisClient(t))
+	runner := bulkretry.NewRunner(logging.NewTestLogger(zap.NewNop()), store, logStore, tenantStore, publisher)
+
+	df := testutil.DestinationFactory
+	ef := testutil.EventFactory
+	af := testutil.AttemptFactory
+
+	require.NoError(t, tenantStore.UpsertTenant(ctx, models.Tenant{ID: "t1"}))
+	require.NoError(t, tenantStore.CreateDestination(ctx, df.Any(df.WithID("d1"), df.WithTenantID("t1"), df.WithTopics([]string{"*"}))))
+	disabledAt := time.Now()
+	disabled := df.Any(df.WithID("d2"), df.WithTenantID("t1"), df.WithTopics([]string{"*"}))
+	disabled.DisabledAt = &disabledAt
+	require.NoError(t, tenantStore.CreateDestination(ctx, disabled))
+
+	now := time.Now()
+	entry := func(event *models.Event, destinationID, status string, number int, at time.Time) *models.LogEntry {
+		return &models.LogEntry{
+			Event: event,
+			Attempt: af.AnyPointer(
+				af.WithTenantID("t1"),
+				af.WithEventID(event.ID),
+				af.WithDestinationID(destinationID),
+				af.WithStatus(status),
+				af.WithAttemptNumber(number),
+				af.WithTime(at),
+			),
+		}
+	}
+	failed := ef.AnyPointer(ef.WithID("e_failed"), ef.WithTenantID("t1"))
+	recovered := ef.AnyPointer(ef.WithID("e_recovered"), ef.WithTenantID("t1"))
+	toDisabled := ef.AnyPointer(ef.WithID("e_disabled"), ef.WithTenantID("t1"))
+	otherTenant := ef.AnyPointer(ef.WithID("e_other"), ef.WithTenantID("t2"))
+	tampered := ef.AnyPointer(ef.WithID("e_tampered"), ef.WithTenantID("t1"))
+	tampered.Checksum = models.DataChecksum([]byte(`{"original":true}`))
+	require.NoError(t, logStore.InsertMany(ctx, []*models.LogEntry{
+		entry(failed, "d1", models.AttemptStatusFailed, 1, now.Add(-3*time.Minute)),
+		entry(failed, "d1", models.AttemptStatusFailed, 2, now.Add(-2*time.Minute)),
+		entry(recovered, "d1", models.AttemptStatusFailed, 1, now.Add(-3*time.Minute)),
+		entry(recovered, "d1", models.AttemptStatusSuccess, 2, now.Add(-2*time.Minute)),
+		entry(toDisabled, "d2", models.AttemptStatusFailed, 1, now.Add(-time.Minute)),
+		entry(tampered, "d1", models.AttemptStatusFailed, 1, now.Add(-time.Minute)),
+		{
+			Event:   otherTenant,
+			Attempt: af.AnyPointer(af.WithTenantID("t2"), af.WithEventID(otherTenant.ID), af.WithDestinationID("d1"), af.WithStatus(models.AttemptStatusFailed)),
+		},
+	}))
+
+	start := now.Add(-time.Hour)
+	job := &bulkretry.Job{ID: "job_1", TenantID: "t1", Filter: bulkretry.Filter{Status: models.AttemptStatusFailed, Start: &start, End: &now}}
+	runner.Run(ctx, job)
+
+	assert.Equal(t, bulkretry.StatusCompleted, job.Status)
+	assert.Equal(t, 3, job.Matched)
+	assert.Equal(t, 1, job.Enqueued)
+	assert.Equal(t, 2, job.Skipped)
+	assert.Equal(t, 1, job.ChecksumMismatches)
+	assert.NotNil(t, job.CompletedAt)
+
+	require.Len(t, publisher.tasks, 1)
+	assert.Equal(t, "e_failed", publisher.tasks[0].Event.ID)
+	assert.Equal(t, "d1", publisher.tasks[0].DestinationID)
+	assert.Equal(t, 3, publisher.tasks[0].Attempt)
+	assert.True(t, publisher.tasks[0].Manual)
+	assert.True(t, publisher.tasks[0].Bulk)
+
+	saved, err := runner.Retrieve(ctx, "t1", "job_1")
+	require.NoError(t, err)
+	assert.Equal(t, bulkretry.StatusCompleted, saved.Status)
+	assert.Equal(t, 1, saved.Enqueued)
+}
+
+func TestRunner_Start(t *testing.T) {
+	t.Parallel()
+
+	newRunner := func(t *testing.T, clk clock.Clock) (*bulkretry.Runner, bulkretry.Store) {
+		store := bulkretry.NewStore(testutil.CreateTestRedisClient(t))
+		runner := bulkretry.NewRunner(logging.NewTestLogger(zap.NewNop()), store, logstore.NewMemLogStore(), tenantstore.NewMemTenantStore(), &mockPublisher{}, bulkretry.WithClock(clk))
+		return runner, store
+	}
+	end := time.Now()
+	start := end.Add(-time.Hour)
+	filter := bulkretry.Filter{Start: &start, End: &end}
+
+	t.Run("rejects a job while the tenant runs one", func(t *testing.T) {
+		t.Parallel()
+		runner, store := newRunner(t, clock.New())
+		require.NoError(t, store.Claim(t.Context(), "t1", "job_running", bulkretry.MaxRunningPerTenant))
+		require.NoError(t, store.Save(t.Context(), &bulkretry.Job{ID: "job_running", TenantID: "t1", Status: bulkretry.StatusRunning, UpdatedAt: time.Now()}))
+
+		_, err := runner.Start(t.Context(), "t1", filter)
+		assert.ErrorIs(t, err, bulkretry.ErrTooManyJobs)
+	})
+
+	t.Run("fails an abandoned job and starts a new one", func(t *testing.T) {
+		t.Parallel()
+		clk := clock.NewFake(time.Now())
+		runner, store := newRunner(t, clk)
+		require.NoError(t, store.Claim(t.Context(), "t1", "job_abandoned", bulkretry.MaxRunningPerTenant))
+		require.NoError(t, store.Save(t.Context(), &bulkretry.Job{ID: "job_abandoned", TenantID: "t1", Status: bulkretry.StatusRunning, UpdatedAt: clk.Now()}))
+		clk.Advance(time.Hour)
+
+		job, err := runner.Start(t.Context(), "t1", filter)
+		require.NoError(t, err)
+		assert.Equal(t, bulkretry.StatusRunning, job.Status)
+
+		abandoned, err := runner.Retrieve(t.Context(), "t1", "job_abandoned")
+		require.NoError(t, err)
+		assert.Equal(t, bulkretry.StatusFailed, abandoned.Status)
+		assert.NotEmpty(t, abandoned.Error)
+	})
+
+	t.Run("rejects jobs after stop", func(t *testing.T) {
+		t.Parallel()
+		runner, _ := newRunner(t, clock.New())
+		runner.Stop()
+
+		_, err := runner.Start(t.Context(), "t1", filter)
+		assert.ErrorIs(t, err, bulkretry.ErrStopped)
+	})
+}
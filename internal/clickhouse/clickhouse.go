@@ -20,6 +20,9 @@ type ClickHouseConfig struct {
 	Password   string
 	Database   string
 	TLSEnabled bool
+	// AsyncInsert sends inserts as asynchronous inserts. They still wait
+	// until the server flushes its buffer, so a successful insert is stored.
+	AsyncInsert bool
 }
 
 func New(config *ClickHouseConfig) (DB, error) {
@@ -46,6 +49,13 @@ func New(config *ClickHouseConfig) (DB, error) {
 		opts.TLS = &tls.Config{}
 	}
 
+	if config.AsyncInsert {
+		opts.Settings = clickhouse.Settings{
+			"async_insert":          1,
+			"wait_for_async_insert": 1,
+		}
+	}
+
 	conn, err := clickhouse.Open(opts)
 	return conn, err
 }
@@ -0,0 +1,230 @@
+// Package clock abstracts the passage of time so that time-dependent code
+// (retry scheduling, retention, periodic workers) can be driven by tests
+// without sleeping.
+//
+// Production code takes a Clock and defaults to New(). Unit tests inject a
+// Fake and move it forward with Advance; end-to-end tests, which need time to
+// keep flowing, inject an Offset and jump ahead with Travel.
+package clock
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock tells the time and waits for it to pass.
+type Clock interface {
+	Now() time.Time
+	// After waits for d to elapse and then sends the current time on the
+	// returned channel.
+	After(d time.Duration) <-chan time.Time
+	// NewTicker returns a Ticker delivering the time every d.
+	NewTicker(d time.Duration) Ticker
+}
+
+// Ticker delivers ticks at intervals, like time.Ticker.
+type Ticker interface {
+	C() <-chan time.Time
+	Stop()
+}
+
+// New returns the wall clock.
+func New() Clock {
+	return realClock{}
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (realClock) NewTicker(d time.Duration) Ticker       { return &realTicker{time.NewTicker(d)} }
+
+type realTicker struct {
+	ticker *time.Ticker
+}
+
+func (t *realTicker) C() <-chan time.Time { return t.ticker.C }
+func (t *realTicker) Stop()               { t.ticker.Stop() }
+
+// Offset is the wall clock shifted by a duration that only grows. Waits take
+// real time, so services keep running normally, while Travel makes anything
+// due within the jump due immediately.
+type Offset struct {
+	mu     sync.RWMutex
+	offset time.Duration
+}
+
+var _ Clock = (*Offset)(nil)
+
+// NewOffset returns an Offset that starts at the wall clock.
+func NewOffset() *Offset {
+	return &Offset{}
+}
+
+func (o *Offset) Now() time.Time {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return time.Now().Add(o.offset)
+}
+
+func (o *Offset) After(d time.Duration) <-chan time.Time { return time.After(d) }
+func (o *Offset) NewTicker(d time.Duration) Ticker       { return &realTicker{time.NewTicker(d)} }
+
+// Travel moves the clock forward by d. Negative durations are ignored.
+func (o *Offset) Travel(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.offset += d
+}
+
+// Fake is a clock that only moves when told to. Waiters fire, in deadline
+// order, as Advance or Set reaches their deadline.
+type Fake struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []*fakeWaiter
+	changed chan struct{}
+}
+
+var _ Clock = (*Fake)(nil)
+
+type fakeWaiter struct {
+	deadline time.Time
+	// interval is set for tickers, which re-arm after each tick.
+	interval time.Duration
+	ch       chan time.Time
+}
+
+// NewFake returns a Fake set to now.
+func NewFake(now time.Time) *Fake {
+	return &Fake{now: now, changed: make(chan struct{})}
+}
+
+func (f *Fake) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *Fake) After(d time.Duration) <-chan time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &fakeWaiter{deadline: f.now.Add(d), ch: make(chan time.Time, 1)}
+	if d <= 0 {
+		w.ch <- f.now
+		return w.ch
+	}
+	f.addWaiter(w)
+	return w.ch
+}
+
+func (f *Fake) NewTicker(d time.Duration) Ticker {
+	if d <= 0 {
+		panic("clock: non-positive interval for NewTicker")
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &fakeWaiter{deadline: f.now.Add(d), interval: d, ch: make(chan time.Time, 1)}
+	f.addWaiter(w)
+	return &fakeTicker{clock: f, waiter: w}
+}
+
+// Advance moves the clock forward by d, firing every waiter it passes.
+func (f *Fake) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.setLocked(f.now.Add(d))
+}
+
+// Set moves the clock to t, firing every waiter it passes. Moving it
+// backwards fires nothing.
+func (f *Fake) Set(t time.Time) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.setLocked(t)
+}
+
+// Waiters returns the number of pending After calls and running tickers.
+func (f *Fake) Waiters() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.waiters)
+}
+
+// BlockUntil waits until at least n waiters are pending. Tests call it before
+// Advance so the code under test is known to be waiting on the clock.
+func (f *Fake) BlockUntil(n int) {
+	for {
+		f.mu.Lock()
+		if len(f.waiters) >= n {
+			f.mu.Unlock()
+			return
+		}
+		changed := f.changed
+		f.mu.Unlock()
+		<-changed
+	}
+}
+
+func (f *Fake) setLocked(t time.Time) {
+	f.now = t
+	for {
+		next := -1
+		for i, w := range f.waiters {
+			if !w.deadline.After(t) && (next < 0 || w.deadline.Before(f.waiters[next].deadline)) {
+				next = i
+			}
+		}
+		if next < 0 {
+			return
+		}
+		w := f.waiters[next]
+		// Like time.Ticker, a tick is dropped rather than blocking when the
+		// previous one has not been received.
+		select {
+		case w.ch <- w.deadline:
+		default:
+		}
+		if w.interval > 0 {
+			w.deadline = w.deadline.Add(w.interval)
+		} else {
+			f.removeWaiter(w)
+		}
+	}
+}
+
+func (f *Fake) addWaiter(w *fakeWaiter) {
+	f.waiters = append(f.waiters, w)
+	f.notify()
+}
+
+func (f *Fake) removeWaiter(w *fakeWaiter) {
+	for i, other := range f.waiters {
+		if other == w {
+			f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+			f.notify()
+			return
+		}
+	}
+}
+
+func (f *Fake) notify() {
+	close(f.changed)
+	f.changed = make(chan struct{})
+}
+
+type fakeTicker struct {
+	clock  *Fake
+	waiter *fakeWaiter
+}
+
+func (t *fakeTicker) C() <-chan time.Time { return t.waiter.ch }
+
+func (t *fakeTicker) Stop() {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	t.clock.removeWaiter(t.waiter)
+}
@@ -0,0 +1,90 @@
+package clock_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var epoch = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+func received(ch <-chan time.Time) (time.Time, bool) {
+	select {
+	case t := <-ch:
+		return t, true
+	default:
+		return time.Time{}, false
+	}
+}
+
+func TestFake_After(t *testing.T) {
+	t.Parallel()
+
+	c := clock.NewFake(epoch)
+	ch := c.After(time.Minute)
+
+	c.Advance(59 * time.Second)
+	_, ok := received(ch)
+	assert.False(t, ok, "should not fire before the deadline")
+
+	c.Advance(time.Second)
+	got, ok := received(ch)
+	require.True(t, ok, "should fire at the deadline")
+	assert.Equal(t, epoch.Add(time.Minute), got)
+	assert.Equal(t, 0, c.Waiters())
+}
+
+func TestFake_Ticker(t *testing.T) {
+	t.Parallel()
+
+	c := clock.NewFake(epoch)
+	ticker := c.NewTicker(time.Hour)
+
+	c.Advance(time.Hour)
+	got, ok := received(ticker.C())
+	require.True(t, ok)
+	assert.Equal(t, epoch.Add(time.Hour), got)
+
+	// Unreceived ticks are dropped, not queued.
+	c.Advance(3 * time.Hour)
+	_, ok = received(ticker.C())
+	assert.True(t, ok)
+	_, ok = received(ticker.C())
+	assert.False(t, ok)
+
+	ticker.Stop()
+	assert.Equal(t, 0, c.Waiters())
+	c.Advance(time.Hour)
+	_, ok = received(ticker.C())
+	assert.False(t, ok, "stopped ticker should not fire")
+}
+
+func TestFake_BlockUntil(t *testing.T) {
+	t.Parallel()
+
+	c := clock.NewFake(epoch)
+	done := make(chan time.Time)
+	go func() {
+		done <- <-c.After(time.Second)
+	}()
+
+	c.BlockUntil(1)
+	c.Advance(time.Second)
+	assert.Equal(t, epoch.Add(time.Second), <-done)
+}
+
+func TestOffset_Travel(t *testing.T) {
+	t.Parallel()
+
+	c := clock.NewOffset()
+	before := time.Now()
+	c.Travel(24 * time.Hour)
+	c.Travel(-time.Hour)
+
+	now := c.Now()
+	assert.False(t, now.Before(before.Add(24*time.Hour)))
+	assert.True(t, now.Before(time.Now().Add(25*time.Hour)))
+}
@@ -173,6 +173,30 @@ func TestAlertConfig_ToConfig(t *testing.T) {
 				AutoDisableDestination: true,
 			},
 		},
+		{
+			name: "window and probation are carried through",
+			cfg: config.AlertConfig{
+				ConsecutiveFailureWindowSeconds: 600,
+				AutoDisableDestination:          true,
+				AutoReenableProbationSeconds:    3600,
+			},
+			want: alert.Settings{
+				ConsecutiveFailure:           alert.ConsecutiveFailureSetting{Enabled: true, Count: 100, WindowSeconds: 600},
+				ExhaustedRetries:             alert.ExhaustedRetriesSetting{Enabled: true, WindowSeconds: 3600},
+				AutoDisableDestination:       true,
+				AutoReenableProbationSeconds: 3600,
+			},
+		},
+		{
+			name:    "negative window is invalid",
+			cfg:     config.AlertConfig{ConsecutiveFailureWindowSeconds: -1},
+			wantErr: true,
+		},
+		{
+			name:    "negative probation is invalid",
+			cfg:     config.AlertConfig{AutoReenableProbationSeconds: -1},
+			wantErr: true,
+		},
 		{
 			name:    "consecutive zero is invalid (min 1)",
 			cfg:     config.AlertConfig{ConsecutiveFailureCount: config.NewOptionalString("0")},
@@ -0,0 +1,207 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Connection URLs are an alternative to the discrete connection fields of a
+// backend, for secret managers that emit a single URL. A URL sets every field
+// it covers, overriding the discrete ones. Errors never include the URL, as
+// it usually holds a password.
+
+// expandConnectionURLs sets the connection fields of the backends configured
+// with a URL.
+func (c *Config) expandConnectionURLs() error {
+	if c.Redis.URL != "" {
+		if err := c.Redis.expandURL(); err != nil {
+			return fmt.Errorf("%w: redis.url: %w", ErrInvalidConnectionURL, err)
+		}
+	}
+	if c.ClickHouse.URL != "" {
+		if err := c.ClickHouse.expandURL(); err != nil {
+			return fmt.Errorf("%w: clickhouse.url: %w", ErrInvalidConnectionURL, err)
+		}
+	}
+	if c.MQs != nil && c.MQs.AWSSQS.URL != "" {
+		if err := c.MQs.AWSSQS.expandURL(); err != nil {
+			return fmt.Errorf("%w: mqs.aws_sqs.url: %w", ErrInvalidConnectionURL, err)
+		}
+	}
+	return nil
+}
+
+// expandURL parses redis://[username[:password]@]host[:port][/database], or
+// rediss:// for TLS. The cluster=true query parameter enables cluster mode.
+func (c *RedisConfig) expandURL() error {
+	u, err := parseHostURL(c.URL, "redis", "rediss")
+	if err != nil {
+		return err
+	}
+	query, err := parseConnectionQuery(u, "cluster")
+	if err != nil {
+		return err
+	}
+	port, err := connectionPort(u, 6379)
+	if err != nil {
+		return err
+	}
+	database := 0
+	if path := strings.Trim(u.Path, "/"); path != "" {
+		if database, err = strconv.Atoi(path); err != nil || database < 0 {
+			return errors.New("the path must be a database number")
+		}
+	}
+	cluster := false
+	if v := query["cluster"]; v != "" {
+		if cluster, err = strconv.ParseBool(v); err != nil {
+			return errors.New("cluster must be true or false")
+		}
+	}
+
+	c.Host = u.Hostname()
+	c.Port = port
+	c.Username, c.Password = connectionUserinfo(u)
+	c.Database = database
+	c.TLSEnabled = u.Scheme == "rediss"
+	c.ClusterEnabled = cluster
+	return nil
+}
+
+// expandURL parses clickhouse://[username[:password]@]host[:port][/database].
+// The secure=true query parameter enables TLS.
+func (c *ClickHouseConfig) expandURL() error {
+	u, err := parseHostURL(c.URL, "clickhouse")
+	if err != nil {
+		return err
+	}
+	query, err := parseConnectionQuery(u, "secure")
+	if err != nil {
+		return err
+	}
+	secure := false
+	if v := query["secure"]; v != "" {
+		if secure, err = strconv.ParseBool(v); err != nil {
+			return errors.New("secure must be true or false")
+		}
+	}
+	defaultPort := 9000
+	if secure {
+		defaultPort = 9440
+	}
+	port, err := connectionPort(u, defaultPort)
+	if err != nil {
+		return err
+	}
+
+	c.Addr = net.JoinHostPort(u.Hostname(), strconv.Itoa(port))
+	c.Username, c.Password = connectionUserinfo(u)
+	if database := strings.Trim(u.Path, "/"); database != "" {
+		c.Database = database
+	}
+	c.TLSEnabled = secure
+	return nil
+}
+
+// expandURL parses sqs://[access_key_id:secret_access_key@]region. The
+// endpoint query parameter sets a custom endpoint, such as LocalStack's.
+func (c *AWSSQSConfig) expandURL() error {
+	u, err := parseHostURL(c.URL, "sqs")
+	if err != nil {
+		return err
+	}
+	query, err := parseConnectionQuery(u, "endpoint")
+	if err != nil {
+		return err
+	}
+	if u.Port() != "" || strings.Trim(u.Path, "/") != "" {
+		return errors.New("the host must be an AWS region")
+	}
+
+	c.Region = u.Hostname()
+	c.AccessKeyID, c.SecretAccessKey = connectionUserinfo(u)
+	c.Endpoint = query["endpoint"]
+	return nil
+}
+
+// parseConnectionURL parses a URL with one of schemes.
+func parseConnectionURL(raw string, schemes ...string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		// The parse error quotes the URL, or parts of it.
+		return nil, errors.New("malformed URL")
+	}
+	if !slices.Contains(schemes, u.Scheme) {
+		return nil, fmt.Errorf("the scheme must be %s", strings.Join(schemes, " or "))
+	}
+	return u, nil
+}
+
+// parseHostURL parses a URL with one of schemes that names a host.
+func parseHostURL(raw string, schemes ...string) (*url.URL, error) {
+	u, err := parseConnectionURL(raw, schemes...)
+	if err != nil {
+		return nil, err
+	}
+	if u.Hostname() == "" {
+		return nil, errors.New("missing host")
+	}
+	return u, nil
+}
+
+// parseConnectionQuery returns the query parameters of u, rejecting any not
+// in allowed so that typos aren't silently ignored.
+func parseConnectionQuery(u *url.URL, allowed ...string) (map[string]string, error) {
+	values, err := url.ParseQuery(u.RawQuery)
+	if err != nil {
+		return nil, errors.New("malformed query")
+	}
+	query := make(map[string]string, len(values))
+	for key, v := range values {
+		if !slices.Contains(allowed, key) {
+			return nil, fmt.Errorf("unsupported parameter %q", key)
+		}
+		query[key] = v[len(v)-1]
+	}
+	return query, nil
+}
+
+func connectionPort(u *url.URL, defaultPort int) (int, error) {
+	if u.Port() == "" {
+		return defaultPort, nil
+	}
+	port, err := strconv.Atoi(u.Port())
+	if err != nil || port < 1 || port > 65535 {
+		return 0, errors.New("invalid port")
+	}
+	return port, nil
+}
+
+func connectionUserinfo(u *url.URL) (string, string) {
+	if u.User == nil {
+		return "", ""
+	}
+	password, _ := u.User.Password()
+	return u.User.Username(), password
+}
+
+// validateConnectionURLs checks the schemes of the backends only configured
+// with a URL, so a misplaced URL fails at startup rather than on connect.
+func (c *Config) validateConnectionURLs() error {
+	if c.PostgresURL != "" {
+		if _, err := parseConnectionURL(c.PostgresURL, "postgres", "postgresql"); err != nil {
+			return fmt.Errorf("%w: postgres: %w", ErrInvalidConnectionURL, err)
+		}
+	}
+	if c.MQs != nil && c.MQs.RabbitMQ.ServerURL != "" {
+		if _, err := parseConnectionURL(c.MQs.RabbitMQ.ServerURL, "amqp", "amqps"); err != nil {
+			return fmt.Errorf("%w: mqs.rabbitmq.server_url: %w", ErrInvalidConnectionURL, err)
+		}
+	}
+	return nil
+}
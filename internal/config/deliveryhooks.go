@@ -0,0 +1,37 @@
+package config
+
+import (
+	"time"
+
+	"github.com/hookdeck/outpost/internal/deliveryhook"
+)
+
+// DeliveryHooksConfig is the configuration for the HTTP delivery hooks
+type DeliveryHooksConfig struct {
+	PreDeliveryURL  string `yaml:"pre_delivery_url" env:"DELIVERY_HOOKS_PRE_DELIVERY_URL" desc:"URL called before each delivery attempt. It can respond with headers to add to the delivered event or veto the delivery. If empty, no pre-delivery hook is called." required:"N"`
+	PostDeliveryURL string `yaml:"post_delivery_url" env:"DELIVERY_HOOKS_POST_DELIVERY_URL" desc:"URL called after each delivery attempt with its outcome. If empty, no post-delivery hook is called." required:"N"`
+	SigningSecret   string `yaml:"signing_secret" env:"DELIVERY_HOOKS_SIGNING_SECRET" desc:"Secret used to sign each hook request with HMAC-SHA256. The signature is sent in the X-Outpost-Signature header. If empty, requests are not signed." required:"N"`
+	IncludeData     bool   `yaml:"include_data" env:"DELIVERY_HOOKS_INCLUDE_DATA" desc:"Include the event payload in hook requests. Default: false" required:"N"`
+	TimeoutMs       int    `yaml:"timeout_ms" env:"DELIVERY_HOOKS_TIMEOUT_MS" desc:"Time budget in milliseconds for the hooks of each stage of a delivery attempt. Default: 1000" required:"N"`
+	FailClosed      bool   `yaml:"fail_closed" env:"DELIVERY_HOOKS_FAIL_CLOSED" desc:"If true, a failed or timed out pre-delivery hook fails the delivery, which is retried. If false, the delivery proceeds. Default: false" required:"N"`
+}
+
+// ToConfig returns the configured HTTP hooks. The result has no hooks when
+// neither URL is set.
+func (c *DeliveryHooksConfig) ToConfig() deliveryhook.Hooks {
+	hooks := deliveryhook.Hooks{
+		Budget:     time.Duration(c.TimeoutMs) * time.Millisecond,
+		FailClosed: c.FailClosed,
+	}
+	opts := []deliveryhook.HTTPHookOption{
+		deliveryhook.WithSigningSecret(c.SigningSecret),
+		deliveryhook.WithEventData(c.IncludeData),
+	}
+	if c.PreDeliveryURL != "" {
+		hooks.Pre = append(hooks.Pre, deliveryhook.NewHTTPHook(c.PreDeliveryURL, opts...))
+	}
+	if c.PostDeliveryURL != "" {
+		hooks.Post = append(hooks.Post, deliveryhook.NewHTTPHook(c.PostDeliveryURL, opts...))
+	}
+	return hooks
+}
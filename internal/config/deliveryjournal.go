@@ -0,0 +1,22 @@
+package config
+
+import "time"
+
+// DeliveryJournalConfig is the configuration for backfilling delivery history
+// lost to log store outages
+type DeliveryJournalConfig struct {
+	Enabled            bool `yaml:"enabled" env:"DELIVERY_JOURNAL_ENABLED" desc:"If true, delivery workers journal each attempt in Redis until it is persisted, and log workers backfill the log store with attempts that never reached it, such as during a log store outage." required:"N"`
+	RetentionHours     int  `yaml:"retention_hours" env:"DELIVERY_JOURNAL_RETENTION_HOURS" desc:"Hours an attempt is kept in the journal for backfilling. Attempts of a log store outage longer than this are lost. Default: 72" required:"N"`
+	GracePeriodSeconds int  `yaml:"grace_period_seconds" env:"DELIVERY_JOURNAL_GRACE_PERIOD_SECONDS" desc:"Seconds an attempt is given to be persisted through the log queue before it is backfilled. Default: 900" required:"N"`
+}
+
+// Retention returns how long an attempt is kept in the journal.
+func (c *DeliveryJournalConfig) Retention() time.Duration {
+	return time.Duration(c.RetentionHours) * time.Hour
+}
+
+// GracePeriod returns how long an attempt is given to be persisted before it
+// is backfilled.
+func (c *DeliveryJournalConfig) GracePeriod() time.Duration {
+	return time.Duration(c.GracePeriodSeconds) * time.Second
+}
@@ -3,17 +3,22 @@ package config
 import (
 	"fmt"
 	"strings"
+	"time"
 
+	"github.com/hookdeck/outpost/internal/destregistry"
 	destregistrydefault "github.com/hookdeck/outpost/internal/destregistry/providers"
 	"github.com/hookdeck/outpost/internal/version"
 )
 
 // DestinationsConfig is the main configuration for all destination types
 type DestinationsConfig struct {
-	MetadataPath                string                      `yaml:"metadata_path" env:"DESTINATIONS_METADATA_PATH" desc:"Path to the directory containing custom destination type definitions." required:"N"`
-	IncludeMillisecondTimestamp bool                        `yaml:"include_millisecond_timestamp" env:"DESTINATIONS_INCLUDE_MILLISECOND_TIMESTAMP" desc:"If true, includes a 'timestamp-ms' field with millisecond precision in destination metadata. Useful for load testing and debugging." required:"N"`
-	Webhook                     DestinationWebhookConfig    `yaml:"webhook" desc:"Configuration specific to webhook destinations."`
-	AWSKinesis                  DestinationAWSKinesisConfig `yaml:"aws_kinesis" desc:"Configuration specific to AWS Kinesis destinations."`
+	MetadataPath                  string                      `yaml:"metadata_path" env:"DESTINATIONS_METADATA_PATH" desc:"Path to the directory containing custom destination type definitions." required:"N"`
+	IncludeMillisecondTimestamp   bool                        `yaml:"include_millisecond_timestamp" env:"DESTINATIONS_INCLUDE_MILLISECOND_TIMESTAMP" desc:"If true, includes a 'timestamp-ms' field with millisecond precision in destination metadata. Useful for load testing and debugging." required:"N"`
+	MaxHeaders                    int                         `yaml:"max_headers" env:"DESTINATIONS_MAX_HEADERS" desc:"Maximum number of delivery_metadata entries and webhook custom headers a destination can set. Enforced when a destination is saved. 0 disables the limit." required:"N" default:"50"`
+	MaxHeaderBytes                int                         `yaml:"max_header_bytes" env:"DESTINATIONS_MAX_HEADER_BYTES" desc:"Maximum total bytes of delivery_metadata and webhook custom header names and values per destination. Enforced when a destination is saved. 0 disables the limit." required:"N" default:"8192"`
+	EnforceHeaderLimitsAtDelivery bool                        `yaml:"enforce_header_limits_at_delivery" env:"DESTINATIONS_ENFORCE_HEADER_LIMITS_AT_DELIVERY" desc:"If true, max_headers and max_header_bytes are also checked on every delivery, failing the attempts of destinations saved before the limits were lowered." required:"N"`
+	Webhook                       DestinationWebhookConfig    `yaml:"webhook" desc:"Configuration specific to webhook destinations."`
+	AWSKinesis                    DestinationAWSKinesisConfig `yaml:"aws_kinesis" desc:"Configuration specific to AWS Kinesis destinations."`
 }
 
 func (c *DestinationsConfig) ToConfig(cfg *Config) destregistrydefault.RegisterDefaultDestinationOptions {
@@ -25,8 +30,21 @@ func (c *DestinationsConfig) ToConfig(cfg *Config) destregistrydefault.RegisterD
 	return destregistrydefault.RegisterDefaultDestinationOptions{
 		UserAgent:                   userAgent,
 		IncludeMillisecondTimestamp: c.IncludeMillisecondTimestamp,
+		TopicLifecycle:              cfg.TopicLifecycle(),
 		Webhook:                     c.Webhook.toConfig(),
 		AWSKinesis:                  c.AWSKinesis.toConfig(),
+		DNSCacheTTL:                 time.Duration(cfg.DeliveryDNSCacheTTLSeconds) * time.Second,
+		DNSCacheStale:               time.Duration(cfg.DeliveryDNSCacheStaleSeconds) * time.Second,
+	}
+}
+
+// HeaderLimits returns the caps on the headers a destination adds to outbound
+// requests.
+func (c *DestinationsConfig) HeaderLimits() destregistry.HeaderLimits {
+	return destregistry.HeaderLimits{
+		MaxCount:          c.MaxHeaders,
+		MaxBytes:          c.MaxHeaderBytes,
+		EnforceAtDelivery: c.EnforceHeaderLimitsAtDelivery,
 	}
 }
 
@@ -63,6 +81,11 @@ type DestinationWebhookConfig struct {
 	TimestampHeaderName OptionalString `yaml:"timestamp_header_name" env:"DESTINATIONS_WEBHOOK_TIMESTAMP_HEADER_NAME" desc:"Complete name of the timestamp header. Unset uses the default '<prefix>timestamp'; an explicit value pins that exact name; an empty string disables the header. Only applies to 'default' mode." required:"N"`
 	TopicHeaderName     OptionalString `yaml:"topic_header_name" env:"DESTINATIONS_WEBHOOK_TOPIC_HEADER_NAME" desc:"Complete name of the topic header. Unset uses the default '<prefix>topic'; an explicit value pins that exact name; an empty string disables the header. Only applies to 'default' mode." required:"N"`
 
+	// Consumer-requested redelivery. The header is read from successful
+	// responses rather than sent, but follows the same three-state rule.
+	RetryAfterHeaderName OptionalString `yaml:"retry_after_header_name" env:"DESTINATIONS_WEBHOOK_RETRY_AFTER_HEADER_NAME" desc:"Complete name of the response header through which an endpoint asks for an event to be redelivered later. Unset uses the default '<prefix>retry-after'; an explicit value pins that exact name; an empty string disables consumer-requested redelivery. Only applies to 'default' mode." required:"N"`
+	RetryAfterMaxSeconds int            `yaml:"retry_after_max_seconds" env:"DESTINATIONS_WEBHOOK_RETRY_AFTER_MAX_SECONDS" desc:"Maximum redelivery delay, in seconds, an endpoint can request through the retry-after header. Longer requests are capped. Default: 86400 (24 hours). Set to 0 to disable the cap." required:"N"`
+
 	// Deprecated: replaced by the *_HEADER_NAME configs above. Setting one of
 	// these to true still disables the corresponding header (an empty
 	// *_HEADER_NAME is the replacement) but logs a deprecation warning. A new
@@ -78,6 +101,8 @@ type DestinationWebhookConfig struct {
 	SignatureAlgorithm       string `yaml:"signature_algorithm" env:"DESTINATIONS_WEBHOOK_SIGNATURE_ALGORITHM" desc:"Algorithm used for signing webhook requests (e.g., 'hmac-sha256'). Only applies to 'default' mode." required:"N"`
 	SigningSecretTemplate    string `yaml:"signing_secret_template" env:"DESTINATIONS_WEBHOOK_SIGNING_SECRET_TEMPLATE" desc:"Go template for generating webhook signing secrets. Available variables: {{.RandomHex}} (64-char hex), {{.RandomBase64}} (base64-encoded), {{.RandomAlphanumeric}} (32-char alphanumeric). Defaults to 'whsec_{{.RandomHex}}'. Only applies to 'default' mode." required:"N"`
 	MaxResponseBodyBytes     int    `yaml:"max_response_body_bytes" env:"DESTINATIONS_WEBHOOK_MAX_RESPONSE_BODY_BYTES" desc:"Maximum size in bytes of a destination's response body stored on the delivery attempt. Responses larger than this are replaced with a placeholder so the attempt log stays under the event queue's per-message size limit (oversized log messages fail to publish and retry indefinitely). Default: 131072 (128 KiB). Set to 0 to disable the cap." required:"N"`
+	ClientCertificatesPath   string `yaml:"client_certificates_path" env:"DESTINATIONS_WEBHOOK_CLIENT_CERTIFICATES_PATH" desc:"Directory of client certificates webhook destinations can present for mutual TLS by naming one in config.client_certificate_name. Each '<name>.crt' PEM certificate is paired with the '<name>.key' private key. Loaded on startup." required:"N"`
+	SecretRetrievalPolicy    string `yaml:"secret_retrieval_policy" env:"DESTINATIONS_WEBHOOK_SECRET_RETRIEVAL_POLICY" desc:"Controls how webhook signing secrets are returned by the API after they are stored: 'retrievable' returns them in full, 'write_only' omits them, 'masked' returns only their last 4 characters. Secrets are always returned in the response to the request that created or rotated them. Default: 'retrievable'." required:"N"`
 }
 
 // toConfig converts WebhookConfig to the provider config - private since it's only used internally
@@ -105,13 +130,50 @@ func (c *DestinationWebhookConfig) toConfig() *destregistrydefault.DestWebhookCo
 		SignatureHeader:          resolveWebhookHeaderName(c.SignatureHeaderName, c.DisableDefaultSignatureHeader),
 		TimestampHeader:          resolveWebhookHeaderName(c.TimestampHeaderName, c.DisableDefaultTimestampHeader),
 		TopicHeader:              resolveWebhookHeaderName(c.TopicHeaderName, c.DisableDefaultTopicHeader),
+		RetryAfterHeader:         resolveWebhookHeaderName(c.RetryAfterHeaderName, false),
+		MaxRetryAfterSeconds:     c.RetryAfterMaxSeconds,
 		SignatureContentTemplate: c.SignatureContentTemplate,
 		SignatureHeaderTemplate:  c.SignatureHeaderTemplate,
 		SignatureEncoding:        c.SignatureEncoding,
 		SignatureAlgorithm:       c.SignatureAlgorithm,
 		SigningSecretTemplate:    c.SigningSecretTemplate,
 		MaxResponseBodyBytes:     c.MaxResponseBodyBytes,
+		SecretRetrievalPolicy:    c.SecretRetrievalPolicy,
+		ClientCertificatesPath:   c.ClientCertificatesPath,
+	}
+}
+
+// HeaderName returns the name of a system header of webhook requests:
+// "event-id", "signature", "timestamp" or "topic". It returns "" when the
+// header is disabled. In 'standard' mode, headers are always the prefix
+// followed by the key, with "id" for the event ID.
+func (c *DestinationWebhookConfig) HeaderName(key string) string {
+	cfg := c.toConfig()
+	prefix := strings.TrimSpace(cfg.HeaderPrefix)
+	if c.Mode == "standard" {
+		if key == "event-id" {
+			return prefix + "id"
+		}
+		return prefix + key
+	}
+	var header destregistrydefault.WebhookHeaderConfig
+	switch key {
+	case "event-id":
+		header = cfg.EventIDHeader
+	case "signature":
+		header = cfg.SignatureHeader
+	case "timestamp":
+		header = cfg.TimestampHeader
+	case "topic":
+		header = cfg.TopicHeader
+	}
+	if header.Disabled {
+		return ""
+	}
+	if header.Name != "" {
+		return header.Name
 	}
+	return prefix + key
 }
 
 // resolveWebhookHeaderName applies the three-state rule for a webhook header
@@ -135,12 +197,12 @@ func resolveWebhookHeaderName(name OptionalString, deprecatedDisable bool) destr
 	return destregistrydefault.WebhookHeaderConfig{}
 }
 
-// deprecationWarnings returns a message for each deprecated DISABLE_* flag that
-// is actively set to true (the case that changes behavior). A set *_HEADER_NAME
-// config still takes precedence over the flag, but the warning is emitted
-// regardless so operators migrate off the deprecated var.
-func (c *DestinationWebhookConfig) deprecationWarnings() []string {
-	var warnings []string
+// deprecations returns each deprecated DISABLE_* flag that is actively set to
+// true (the case that changes behavior). A set *_HEADER_NAME config still takes
+// precedence over the flag, but it is reported regardless so operators migrate
+// off the deprecated var.
+func (c *DestinationWebhookConfig) deprecations() []Deprecation {
+	var deprecations []Deprecation
 	for _, d := range []struct {
 		enabled bool
 		oldEnv  string
@@ -152,13 +214,17 @@ func (c *DestinationWebhookConfig) deprecationWarnings() []string {
 		{c.DisableDefaultTopicHeader, "DESTINATIONS_WEBHOOK_DISABLE_DEFAULT_TOPIC_HEADER", "DESTINATIONS_WEBHOOK_TOPIC_HEADER_NAME"},
 	} {
 		if d.enabled {
-			warnings = append(warnings, fmt.Sprintf(
-				"%s is deprecated and will be removed in a future version. Set %s to an empty string to disable the header instead.",
-				d.oldEnv, d.newEnv,
-			))
+			deprecations = append(deprecations, Deprecation{
+				Key:         d.oldEnv,
+				Replacement: d.newEnv,
+				Message: fmt.Sprintf(
+					"%s is deprecated and will be removed in a future version. Set %s to an empty string to disable the header instead.",
+					d.oldEnv, d.newEnv,
+				),
+			})
 		}
 	}
-	return warnings
+	return deprecations
 }
 
 // AWS Kinesis configuration
@@ -9,7 +9,7 @@ var (
 )
 
 func init() {
-	flag.StringVar(&service, "service", "", "service (e.g. api, delivery, log). If empty, all services will run.")
+	flag.StringVar(&service, "service", "", "service (e.g. api, delivery, log, archiver). If empty, all services will run.")
 	flag.StringVar(&config, "config", "", "config file (e.g. .env, config.yaml)")
 	flag.BoolVar(&printVersion, "version", false, "print version information")
 }
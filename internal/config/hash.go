@@ -0,0 +1,19 @@
+package config
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+)
+
+// Hash returns a fingerprint of the effective configuration, so operators
+// can tell whether two processes run with the same settings. Secrets are
+// part of the hash but cannot be recovered from it.
+func (c *Config) Hash() string {
+	b, err := json.Marshal(c)
+	if err != nil {
+		return ""
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,26 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigHash(t *testing.T) {
+	parse := func(envVars map[string]string) *config.Config {
+		cfg, err := config.ParseWithoutValidation(config.Flags{}, &mockOS{
+			files:   map[string][]byte{},
+			envVars: envVars,
+		})
+		require.NoError(t, err)
+		return cfg
+	}
+
+	hash := parse(map[string]string{"REDIS_HOST": "redis"}).Hash()
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, parse(map[string]string{"REDIS_HOST": "redis"}).Hash())
+	assert.NotEqual(t, hash, parse(map[string]string{"REDIS_HOST": "other"}).Hash())
+	assert.NotEqual(t, hash, parse(map[string]string{"REDIS_HOST": "redis", "API_KEY": "secret"}).Hash())
+}
@@ -0,0 +1,29 @@
+package config
+
+import (
+	"time"
+
+	"github.com/hookdeck/outpost/internal/lifecycle"
+)
+
+// EventLifecycleConfig is the configuration for event lifecycle callbacks
+type EventLifecycleConfig struct {
+	CallbackURL     string `yaml:"callback_url" env:"EVENT_LIFECYCLE_CALLBACK_URL" desc:"URL that receives batched lifecycle notifications for published events: when each event is accepted and fanned out, and when each of its deliveries reaches a terminal status. With tenant callbacks enabled, it only receives the notifications of tenants without a callback of their own. If empty and tenant callbacks are disabled, no notifications are sent." required:"N"`
+	TenantCallbacks bool   `yaml:"tenant_callbacks" env:"EVENT_LIFECYCLE_TENANT_CALLBACKS" desc:"Send each tenant's lifecycle notifications to the callback URL registered on the tenant through the API, falling back to the callback URL. Default: false" required:"N"`
+	SigningSecret   string `yaml:"signing_secret" env:"EVENT_LIFECYCLE_SIGNING_SECRET" desc:"Secret used to sign each callback request with HMAC-SHA256. The signature is sent in the X-Outpost-Signature header. If empty, requests are not signed." required:"N"`
+	BatchSize       int    `yaml:"batch_size" env:"EVENT_LIFECYCLE_BATCH_SIZE" desc:"Maximum number of notifications sent in one callback request. Default: 100" required:"N"`
+	BatchInterval   int    `yaml:"batch_interval" env:"EVENT_LIFECYCLE_BATCH_INTERVAL" desc:"Maximum time in seconds to wait before sending a batch of notifications if batch size is not reached. Default: 5" required:"N"`
+}
+
+// ToConfig returns the notifier configuration. retryMaxLimit is the
+// deployment's retry limit, used to recognize a delivery's final attempt.
+func (c *EventLifecycleConfig) ToConfig(retryMaxLimit int) lifecycle.Config {
+	return lifecycle.Config{
+		CallbackURL:     c.CallbackURL,
+		TenantCallbacks: c.TenantCallbacks,
+		SigningSecret:   c.SigningSecret,
+		BatchSize:       c.BatchSize,
+		BatchInterval:   time.Duration(c.BatchInterval) * time.Second,
+		RetryMaxLimit:   retryMaxLimit,
+	}
+}
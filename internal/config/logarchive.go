@@ -0,0 +1,50 @@
+package config
+
+import (
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logarchive"
+)
+
+// LogArchiveConfig is the configuration for archiving delivery logs to object
+// storage before log retention deletes them
+type LogArchiveConfig struct {
+	Enabled         bool   `yaml:"enabled" env:"LOG_ARCHIVE_ENABLED" desc:"If true, delivery logs are exported to the configured bucket once they are log_archive.after_days old, and log retention never deletes logs that are not archived yet. The archiver runs in the 'archiver' service, or in singular mode." required:"N"`
+	AfterDays       int    `yaml:"after_days" env:"LOG_ARCHIVE_AFTER_DAYS" desc:"Days after which delivery logs are archived. Must be lower than the log retention days." required:"N"`
+	Bucket          string `yaml:"bucket" env:"LOG_ARCHIVE_BUCKET" desc:"S3 bucket the archive is written to. Required if log_archive.enabled is true." required:"C"`
+	Prefix          string `yaml:"prefix" env:"LOG_ARCHIVE_PREFIX" desc:"Key prefix of the archive in the bucket." required:"N"`
+	Region          string `yaml:"region" env:"LOG_ARCHIVE_REGION" desc:"Region of the bucket." required:"N"`
+	AccessKeyID     string `yaml:"access_key_id" env:"LOG_ARCHIVE_ACCESS_KEY_ID" desc:"Access key ID used to write the archive. If empty, the default AWS credential chain (environment, instance or task role) is used." required:"N"`
+	SecretAccessKey string `yaml:"secret_access_key" env:"LOG_ARCHIVE_SECRET_ACCESS_KEY" desc:"Secret access key used to write the archive." required:"N"`
+	Endpoint        string `yaml:"endpoint" env:"LOG_ARCHIVE_ENDPOINT" desc:"Custom S3 endpoint. Set to 'https://storage.googleapis.com' with HMAC keys to archive to Google Cloud Storage, or to a local endpoint for development." required:"N"`
+	ServeReads      bool   `yaml:"serve_reads" env:"LOG_ARCHIVE_SERVE_READS" desc:"If true, the API lists delivery logs older than the shortest log retention from the archive, after those of the log store. Archived logs are listed but not retrieved by ID or counted in metrics. Requires log_archive.enabled." required:"N"`
+}
+
+// ArchiveTierMaxAge returns the age up to which the log store serves delivery
+// logs before the archive does, or 0 when the archive doesn't serve reads.
+// It is the shortest log retention, past which the log store may no longer
+// hold every log.
+func (c *Config) ArchiveTierMaxAge() time.Duration {
+	if !c.LogArchive.Enabled || !c.LogArchive.ServeReads {
+		return 0
+	}
+	policy := c.LogRetentionPolicy()
+	days := 0
+	for _, d := range []int{policy.SuccessDays, policy.FailedDays} {
+		if d > 0 && (days == 0 || d < days) {
+			days = d
+		}
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+func (c *LogArchiveConfig) ToConfig() logarchive.S3Config {
+	return logarchive.S3Config{
+		Bucket:          c.Bucket,
+		Prefix:          c.Prefix,
+		Region:          c.Region,
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		Endpoint:        c.Endpoint,
+	}
+}
@@ -0,0 +1,29 @@
+package config
+
+import "time"
+
+// LogBatchAdaptiveConfig is the configuration for sizing log batches by load
+// and insert latency
+type LogBatchAdaptiveConfig struct {
+	Enabled        bool `yaml:"enabled" env:"LOG_BATCH_ADAPTIVE_ENABLED" desc:"If true, the log service adapts the batch size and threshold to the load: they grow while batches fill up, and shrink when an insert takes longer than the latency SLO. log_batch_size and log_batch_threshold_seconds are the starting point." required:"N"`
+	MinSize        int  `yaml:"min_size" env:"LOG_BATCH_ADAPTIVE_MIN_SIZE" desc:"Smallest batch size the log service shrinks to. Default: 100" required:"N"`
+	MaxSize        int  `yaml:"max_size" env:"LOG_BATCH_ADAPTIVE_MAX_SIZE" desc:"Largest batch size the log service grows to. Default: 10000" required:"N"`
+	MinThresholdMs int  `yaml:"min_threshold_ms" env:"LOG_BATCH_ADAPTIVE_MIN_THRESHOLD_MS" desc:"Shortest time in milliseconds the log service buffers logs for. Default: 100" required:"N"`
+	MaxThresholdMs int  `yaml:"max_threshold_ms" env:"LOG_BATCH_ADAPTIVE_MAX_THRESHOLD_MS" desc:"Longest time in milliseconds the log service buffers logs for. Default: 30000" required:"N"`
+	LatencySLOMs   int  `yaml:"latency_slo_ms" env:"LOG_BATCH_ADAPTIVE_LATENCY_SLO_MS" desc:"Longest time in milliseconds an insert may take before the batch size and threshold shrink. Default: 2000" required:"N"`
+}
+
+// MinThreshold returns the shortest time logs are buffered for.
+func (c *LogBatchAdaptiveConfig) MinThreshold() time.Duration {
+	return time.Duration(c.MinThresholdMs) * time.Millisecond
+}
+
+// MaxThreshold returns the longest time logs are buffered for.
+func (c *LogBatchAdaptiveConfig) MaxThreshold() time.Duration {
+	return time.Duration(c.MaxThresholdMs) * time.Millisecond
+}
+
+// LatencySLO returns the longest time an insert may take.
+func (c *LogBatchAdaptiveConfig) LatencySLO() time.Duration {
+	return time.Duration(c.LatencySLOMs) * time.Millisecond
+}
@@ -56,16 +56,26 @@ func (c *Config) LogConfigurationSummary() []zap.Field {
 		zap.String("log_level", c.LogLevel),
 		zap.String("deployment_id", c.DeploymentID),
 		zap.Strings("topics", c.Topics),
+		zap.Strings("topics_deprecated", c.TopicsDeprecated),
+		zap.Strings("topics_retired", c.TopicsRetired),
+		zap.String("topic_namespace", c.TopicNamespace),
+		zap.Bool("topic_namespace_strip_on_delivery", c.TopicNamespaceStripOnDelivery),
 		zap.String("http_user_agent", c.HTTPUserAgent),
 
 		// API
 		zap.Int("api_port", c.APIPort),
+		zap.Int("grpc_port", c.GRPCPort),
 		zap.Bool("api_key_configured", c.APIKey != ""),
 		zap.Bool("api_jwt_secret_configured", c.APIJWTSecret != ""),
 		zap.String("gin_mode", c.GinMode),
+		zap.Strings("api_trusted_proxies", c.APITrustedProxies),
 
 		// Application
 		zap.Bool("aes_encryption_secret_configured", c.AESEncryptionSecret != ""),
+		zap.Int("aes_encryption_previous_secrets", len(c.AESEncryptionPreviousSecrets)),
+		zap.Int("aes_encryption_keys", len(c.AESEncryptionKeys)),
+		zap.String("aes_encryption_primary_key_id", c.AESEncryptionPrimaryKeyID),
+		zap.Bool("aes_encryption_reencrypt_on_startup", c.AESEncryptionReEncryptOnStartup),
 
 		// Redis
 		zap.String("redis_host", c.Redis.Host),
@@ -74,6 +84,15 @@ func (c *Config) LogConfigurationSummary() []zap.Field {
 		zap.Int("redis_database", c.Redis.Database),
 		zap.Bool("redis_tls_enabled", c.Redis.TLSEnabled),
 		zap.Bool("redis_cluster_enabled", c.Redis.ClusterEnabled),
+		zap.Bool("redis_standby_check_enabled", c.RedisStandby.Enabled),
+		zap.String("redis_standby_host", c.RedisStandby.Host),
+		zap.Int("redis_standby_check_interval_seconds", c.RedisStandby.IntervalSeconds),
+
+		// Replication
+		zap.Bool("replication_changefeed_enabled", c.Replication.ChangefeedEnabled),
+		zap.Bool("replication_enabled", c.Replication.Enabled),
+		zap.String("replication_source_host", c.Replication.SourceHost),
+		zap.String("replication_source_deployment_id", c.Replication.SourceDeploymentID),
 
 		// PostgreSQL
 		zap.Bool("postgres_configured", c.PostgresURL != ""),
@@ -85,13 +104,16 @@ func (c *Config) LogConfigurationSummary() []zap.Field {
 		zap.String("clickhouse_database", c.ClickHouse.Database),
 		zap.Bool("clickhouse_password_configured", c.ClickHouse.Password != ""),
 		zap.Bool("clickhouse_tls_enabled", c.ClickHouse.TLSEnabled),
+		zap.Bool("clickhouse_async_insert", c.ClickHouse.AsyncInsert),
 
 		// Message Queue
 		zap.String("mq_type", c.MQs.GetInfraType()),
+		zap.Int("mqs_max_visibility_extension_seconds", c.MQs.MaxVisibilityExtensionSeconds),
 
 		// Consumers
 		zap.Int("publish_max_concurrency", c.PublishMaxConcurrency),
 		zap.Int("delivery_max_concurrency", c.DeliveryMaxConcurrency),
+		zap.Int("delivery_warmup_tenants", c.DeliveryWarmupTenants),
 		zap.Int("log_max_concurrency", c.LogMaxConcurrency),
 
 		// Delivery Retry
@@ -101,15 +123,39 @@ func (c *Config) LogConfigurationSummary() []zap.Field {
 
 		// Event Delivery
 		zap.Int("max_destinations_per_tenant", c.MaxDestinationsPerTenant),
+		zap.Int("max_events_per_minute_per_tenant", c.MaxEventsPerMinutePerTenant),
+		zap.Int("publish_rate_limit_per_second", c.PublishRateLimitPerSecond),
+		zap.Int("publish_rate_limit_burst", c.PublishRateLimitBurst),
+		zap.Int("quota_warning_percent", c.QuotaWarningPercent),
 		zap.Int("delivery_timeout_seconds", c.DeliveryTimeoutSeconds),
+		zap.Int("delivery_dns_cache_ttl_seconds", c.DeliveryDNSCacheTTLSeconds),
+		zap.Int("delivery_dns_cache_stale_seconds", c.DeliveryDNSCacheStaleSeconds),
+		zap.Bool("payload_offload_enabled", c.PayloadOffloadBaseURL != ""),
+		zap.Int("payload_offload_ttl_seconds", c.PayloadOffloadTTLSeconds),
 
 		// Idempotency
 		zap.Int("publish_idempotency_key_ttl", c.PublishIdempotencyKeyTTL),
+		zap.Int("publish_idempotency_key_window", c.PublishIdempotencyKeyWindow),
 		zap.Int("delivery_idempotency_key_ttl", c.DeliveryIdempotencyKeyTTL),
 
 		// Log batcher
 		zap.Int("log_batch_threshold_seconds", c.LogBatchThresholdSeconds),
 		zap.Int("log_batch_size", c.LogBatchSize),
+		zap.Bool("log_batch_adaptive_enabled", c.LogBatchAdaptive.Enabled),
+		zap.Int("log_batch_adaptive_min_size", c.LogBatchAdaptive.MinSize),
+		zap.Int("log_batch_adaptive_max_size", c.LogBatchAdaptive.MaxSize),
+		zap.Int("log_batch_adaptive_min_threshold_ms", c.LogBatchAdaptive.MinThresholdMs),
+		zap.Int("log_batch_adaptive_max_threshold_ms", c.LogBatchAdaptive.MaxThresholdMs),
+		zap.Int("log_batch_adaptive_latency_slo_ms", c.LogBatchAdaptive.LatencySLOMs),
+
+		// Delivery journal
+		zap.Bool("delivery_journal_enabled", c.DeliveryJournal.Enabled),
+		zap.Int("delivery_journal_retention_hours", c.DeliveryJournal.RetentionHours),
+		zap.Int("delivery_journal_grace_period_seconds", c.DeliveryJournal.GracePeriodSeconds),
+
+		// Topic analytics
+		zap.Bool("topic_analytics_enabled", c.TopicAnalytics.Enabled),
+		zap.Int("topic_analytics_retention_days", c.TopicAnalytics.RetentionDays),
 
 		// Telemetry
 		zap.Bool("telemetry_disabled", c.Telemetry.Disabled || c.DisableTelemetry),
@@ -117,16 +163,80 @@ func (c *Config) LogConfigurationSummary() []zap.Field {
 		// Alert
 		zap.Bool("alert_consecutive_failure_enabled", alertSettings.ConsecutiveFailure.Enabled),
 		zap.Int("alert_consecutive_failure_count", alertSettings.ConsecutiveFailure.Count),
+		zap.Int("alert_consecutive_failure_window_seconds", alertSettings.ConsecutiveFailure.WindowSeconds),
 		zap.Bool("alert_auto_disable_destination", c.Alert.AutoDisableDestination),
+		zap.Int("alert_auto_reenable_probation_seconds", alertSettings.AutoReenableProbationSeconds),
 		zap.Bool("alert_exhausted_retries_enabled", alertSettings.ExhaustedRetries.Enabled),
 		zap.Int("alert_exhausted_retries_window_seconds", alertSettings.ExhaustedRetries.WindowSeconds),
+		zap.Bool("alert_slack_enabled", c.Alert.Channels.SlackWebhookURL != ""),
+		zap.Bool("alert_pagerduty_enabled", c.Alert.Channels.PagerDutyRoutingKey != ""),
+		zap.String("alert_webhook_url", maskURL(c.Alert.Channels.WebhookURL)),
+		zap.Bool("alert_webhook_signing_enabled", c.Alert.Channels.WebhookSigningSecret != ""),
 
 		// ID Generation
 		zap.String("idgen_type", c.IDGen.Type),
 		zap.String("idgen_event_prefix", c.IDGen.EventPrefix),
 
+		// Delivery Receipts
+		zap.Bool("receipts_enabled", c.Receipts.Enabled),
+		zap.Bool("receipts_static_credentials", c.Receipts.AccessKeyID != ""),
+		zap.String("receipts_aws_s3_endpoint", c.Receipts.Endpoint),
+		zap.Bool("receipts_signing_enabled", c.Receipts.SigningKey != ""),
+
+		// Log Archive
+		zap.Bool("log_archive_enabled", c.LogArchive.Enabled),
+		zap.Int("log_archive_after_days", c.LogArchive.AfterDays),
+		zap.String("log_archive_bucket", c.LogArchive.Bucket),
+		zap.String("log_archive_prefix", c.LogArchive.Prefix),
+		zap.Bool("log_archive_static_credentials", c.LogArchive.AccessKeyID != ""),
+		zap.String("log_archive_endpoint", c.LogArchive.Endpoint),
+		zap.Bool("log_archive_serve_reads", c.LogArchive.ServeReads),
+
+		// Tenant Cold Storage
+		zap.Bool("tenant_cold_storage_enabled", c.TenantColdStorage.Enabled),
+		zap.String("tenant_cold_storage_bucket", c.TenantColdStorage.Bucket),
+		zap.String("tenant_cold_storage_prefix", c.TenantColdStorage.Prefix),
+		zap.Bool("tenant_cold_storage_static_credentials", c.TenantColdStorage.AccessKeyID != ""),
+		zap.String("tenant_cold_storage_endpoint", c.TenantColdStorage.Endpoint),
+
+		// Log Redaction
+		zap.Strings("log_redaction_headers", c.LogRedaction.Headers),
+		zap.Strings("log_redaction_json_paths", c.LogRedaction.JSONPaths),
+
+		// Event Lifecycle Callbacks
+		zap.String("event_lifecycle_callback_url", maskURL(c.EventLifecycle.CallbackURL)),
+		zap.Bool("event_lifecycle_signing_enabled", c.EventLifecycle.SigningSecret != ""),
+		zap.Bool("event_lifecycle_tenant_callbacks", c.EventLifecycle.TenantCallbacks),
+
+		// Delivery Hooks
+		zap.String("delivery_hooks_pre_delivery_url", maskURL(c.DeliveryHooks.PreDeliveryURL)),
+		zap.String("delivery_hooks_post_delivery_url", maskURL(c.DeliveryHooks.PostDeliveryURL)),
+		zap.Bool("delivery_hooks_signing_enabled", c.DeliveryHooks.SigningSecret != ""),
+		zap.Int("delivery_hooks_timeout_ms", c.DeliveryHooks.TimeoutMs),
+		zap.Bool("delivery_hooks_fail_closed", c.DeliveryHooks.FailClosed),
+		zap.String("publish_validation_url", maskURL(c.PublishValidation.URL)),
+		zap.Strings("publish_validation_topics", c.PublishValidation.Topics),
+		zap.Bool("publish_validation_signing_enabled", c.PublishValidation.SigningSecret != ""),
+		zap.Int("publish_validation_timeout_ms", c.PublishValidation.TimeoutMs),
+		zap.Bool("publish_validation_fail_closed", c.PublishValidation.FailClosed),
+
+		// Log Store Tuning
+		zap.String("logstore_partition_interval", c.LogStore.PartitionInterval),
+		zap.Int("logstore_index_granularity", c.LogStore.IndexGranularity),
+		zap.Strings("logstore_indexes", c.LogStore.Indexes),
+		zap.String("logstore_compression_codec", c.LogStore.CompressionCodec),
+		zap.Duration("logstore_hot_tier_max_age", c.HotTierMaxAge()),
+
 		// Retention
 		zap.Int("clickhouse_log_retention_ttl_days", c.ClickHouseLogRetentionTTLDays),
+		zap.Int("log_retention_days", c.LogRetentionDays),
+		zap.Int("log_retention_success_days", c.LogRetentionSuccessDays),
+		zap.Int("log_retention_failed_days", c.LogRetentionFailedDays),
+
+		// Destinations
+		zap.Int("destinations_max_headers", c.Destinations.MaxHeaders),
+		zap.Int("destinations_max_header_bytes", c.Destinations.MaxHeaderBytes),
+		zap.Bool("destinations_enforce_header_limits_at_delivery", c.Destinations.EnforceHeaderLimitsAtDelivery),
 
 		// Destinations - Webhook (effective header directives after resolving the
 		// three-state name configs and deprecated DISABLE_* flags)
@@ -134,6 +244,7 @@ func (c *Config) LogConfigurationSummary() []zap.Field {
 		zap.String("destinations_webhook_signature_header", webhookHeaderSummary(webhookCfg.SignatureHeader)),
 		zap.String("destinations_webhook_timestamp_header", webhookHeaderSummary(webhookCfg.TimestampHeader)),
 		zap.String("destinations_webhook_topic_header", webhookHeaderSummary(webhookCfg.TopicHeader)),
+		zap.String("destinations_webhook_secret_retrieval_policy", webhookCfg.SecretRetrievalPolicy),
 	}
 
 	// Add MQ-specific fields based on type
@@ -181,6 +292,13 @@ func (c *Config) getMQSpecificFields(mqType string) []zap.Field {
 			zap.String("azure_log_topic", c.MQs.AzureServiceBus.LogTopic),
 			zap.String("azure_log_subscription", c.MQs.AzureServiceBus.LogSubscription),
 		}
+	case "natsjetstream":
+		return []zap.Field{
+			zap.String("nats_url", maskURL(c.MQs.NATSJetStream.ServerURL)),
+			zap.Bool("nats_credentials_configured", c.MQs.NATSJetStream.CredentialsFile != ""),
+			zap.String("nats_delivery_stream", c.MQs.NATSJetStream.DeliveryStream),
+			zap.String("nats_log_stream", c.MQs.NATSJetStream.LogStream),
+		}
 	default:
 		return []zap.Field{}
 	}
@@ -0,0 +1,30 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/config"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestLogConfigurationSummary_KeyRing(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.InitDefaults()
+	cfg.AESEncryptionKeys = []string{"k1:secret-one", "k2:secret-two"}
+	cfg.AESEncryptionPrimaryKeyID = "k2"
+
+	enc := zapcore.NewMapObjectEncoder()
+	for _, field := range cfg.LogConfigurationSummary() {
+		field.AddTo(enc)
+	}
+
+	assert.Equal(t, int64(2), enc.Fields["aes_encryption_keys"])
+	assert.Equal(t, "k2", enc.Fields["aes_encryption_primary_key_id"])
+	for key, value := range enc.Fields {
+		if s, ok := value.(string); ok {
+			assert.NotContains(t, s, "secret-one", key)
+			assert.NotContains(t, s, "secret-two", key)
+		}
+	}
+}
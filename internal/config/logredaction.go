@@ -0,0 +1,14 @@
+package config
+
+import "github.com/hookdeck/outpost/internal/redact"
+
+// LogRedactionConfig is the configuration for redacting credentials from the
+// request and response data delivery attempts store in the log store
+type LogRedactionConfig struct {
+	Headers   []string `yaml:"headers" env:"LOG_REDACTION_HEADERS" envSeparator:"," desc:"Comma-separated list of header name patterns, such as 'x-*-key', whose values are redacted from stored attempt data. Matching is case-insensitive. Authorization, Proxy-Authorization, Cookie, Set-Cookie, X-Api-Key and headers containing 'token', 'secret' or 'password' are always redacted." required:"N"`
+	JSONPaths []string `yaml:"json_paths" env:"LOG_REDACTION_JSON_PATHS" envSeparator:"," desc:"Comma-separated list of JSONPaths, such as '$.body.access_token' or '$..password', whose values are redacted from stored attempt data. Paths descend into captured bodies that hold JSON." required:"N"`
+}
+
+func (c *LogRedactionConfig) ToRedactor() (*redact.Redactor, error) {
+	return redact.New(c.Headers, c.JSONPaths)
+}
@@ -0,0 +1,44 @@
+package config
+
+import (
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logstore/tuning"
+)
+
+// LogStoreConfig tunes the log store schema and tiering. Apply schema changes
+// to existing tables with 'outpost logstore migrate-schema'.
+type LogStoreConfig struct {
+	PartitionInterval  string   `yaml:"partition_interval" env:"LOGSTORE_PARTITION_INTERVAL" desc:"Time range of a log store partition: 'day', 'week' or 'month'. ClickHouse applies it when 'outpost logstore migrate-schema --rebuild-tables' recreates its tables (default 'month'). PostgreSQL creates upcoming partitions with 'outpost logstore migrate-schema'; if unset, it keeps a single partition." required:"N"`
+	IndexGranularity   int      `yaml:"index_granularity" env:"LOGSTORE_INDEX_GRANULARITY" desc:"ClickHouse index_granularity of the log tables, applied when 'outpost logstore migrate-schema --rebuild-tables' recreates them. Smaller values speed up selective queries on small deployments at the cost of memory. 0 keeps the server default (8192)." required:"N"`
+	Indexes            []string `yaml:"indexes" env:"LOGSTORE_INDEXES" envSeparator:"," desc:"Comma-separated list of optional secondary indexes to keep on the log tables: 'topic', 'status', 'metadata'. Indexes left out are dropped by 'outpost logstore migrate-schema'." required:"N" default:"topic,status"`
+	CompressionCodec   string   `yaml:"compression_codec" env:"LOGSTORE_COMPRESSION_CODEC" desc:"Compression codec of event and response payload columns. ClickHouse: 'LZ4', 'LZ4HC(level)', 'ZSTD(level)' or 'NONE'. PostgreSQL: 'pglz' or 'lz4'. If unset, columns are left as they are." required:"N"`
+	HotTierMaxAgeHours int      `yaml:"hot_tier_max_age_hours" env:"LOGSTORE_HOT_TIER_MAX_AGE_HOURS" desc:"Serve log records younger than this many hours from PostgreSQL and older ones from ClickHouse, which keeps every record. Requires both POSTGRES_URL and CLICKHOUSE_ADDR. 0 disables tiering." required:"N"`
+}
+
+func (c *LogStoreConfig) ToConfig() tuning.Config {
+	return tuning.Config{
+		PartitionInterval: c.PartitionInterval,
+		IndexGranularity:  c.IndexGranularity,
+		Indexes:           c.Indexes,
+		CompressionCodec:  c.CompressionCodec,
+	}
+}
+
+// LogStoreDriver returns the tuning driver of the configured log store,
+// matching the database the migrator targets.
+func (c *Config) LogStoreDriver() string {
+	if c.PostgresURL != "" {
+		return tuning.DriverPostgres
+	}
+	return tuning.DriverClickHouse
+}
+
+// HotTierMaxAge returns the window served by the PostgreSQL hot tier, or 0
+// when the log store is not tiered.
+func (c *Config) HotTierMaxAge() time.Duration {
+	if c.PostgresURL == "" || c.ClickHouse.Addr == "" {
+		return 0
+	}
+	return time.Duration(c.LogStore.HotTierMaxAgeHours) * time.Hour
+}
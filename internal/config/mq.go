@@ -2,6 +2,7 @@ package config
 
 import (
 	"context"
+	"time"
 
 	"github.com/hookdeck/outpost/internal/mqinfra"
 	"github.com/hookdeck/outpost/internal/mqs"
@@ -19,8 +20,12 @@ type MQsConfig struct {
 	AzureServiceBus AzureServiceBusConfig `yaml:"azure_servicebus" desc:"Configuration for using Azure Service Bus as the message queue. Only one MQ provider should be configured." required:"N"`
 	GCPPubSub       GCPPubSubConfig       `yaml:"gcp_pubsub" desc:"Configuration for using GCP Pub/Sub as the message queue. Only one MQ provider should be configured." required:"N"`
 	RabbitMQ        RabbitMQConfig        `yaml:"rabbitmq" desc:"Configuration for using RabbitMQ as the message queue. Only one MQ provider should be configured." required:"N"`
+	NATSJetStream   NATSJetStreamConfig   `yaml:"nats_jetstream" desc:"Configuration for using NATS JetStream as the message queue. Only one MQ provider should be configured." required:"N"`
 	AutoProvision   *bool                 `yaml:"auto_provision" env:"MQS_AUTO_PROVISION" desc:"Whether Outpost should create and manage message queue infrastructure. Set to false if you manage infrastructure externally (e.g., via Terraform). Defaults to true for backward compatibility." required:"N" default:"true"`
 
+	// Visibility extension, for AWS SQS, GCP Pub/Sub and NATS JetStream
+	MaxVisibilityExtensionSeconds int `yaml:"max_visibility_extension_seconds" env:"MQS_MAX_VISIBILITY_EXTENSION_SECONDS" desc:"Time in seconds AWS SQS, GCP Pub/Sub and NATS JetStream messages still being handled are kept from being redelivered past their visibility timeout, by extending it while the handler runs. A handler holding a message longer is considered stuck and the message is redelivered. 0 disables extension. Default: 300" required:"N"`
+
 	adapter MQConfigAdapter
 }
 
@@ -37,6 +42,8 @@ func (c *MQsConfig) init() {
 		c.adapter = &c.GCPPubSub
 	} else if c.RabbitMQ.IsConfigured() {
 		c.adapter = &c.RabbitMQ
+	} else if c.NATSJetStream.IsConfigured() {
+		c.adapter = &c.NATSJetStream
 	}
 }
 
@@ -61,5 +68,10 @@ func (c *MQsConfig) ToQueueConfig(ctx context.Context, queueType string) (*mqs.Q
 	if c.adapter == nil {
 		return nil, nil
 	}
-	return c.adapter.ToQueueConfig(ctx, queueType)
+	queueConfig, err := c.adapter.ToQueueConfig(ctx, queueType)
+	if err != nil || queueConfig == nil {
+		return queueConfig, err
+	}
+	queueConfig.MaxVisibilityExtension = time.Duration(c.MaxVisibilityExtensionSeconds) * time.Second
+	return queueConfig, nil
 }
@@ -14,6 +14,7 @@ import (
 var errPartialAWSSQSCredentials = errors.New("AWS SQS: both access_key_id and secret_access_key must be set together, or both omitted to use the default credential chain")
 
 type AWSSQSConfig struct {
+	URL             string `yaml:"url" env:"AWS_SQS_URL" desc:"Connection URL for AWS SQS, as an alternative to the access_key_id, secret_access_key, region and endpoint fields, which it overrides. Format: 'sqs://[access_key_id:secret_access_key@]region', with '?endpoint=<url>' for a custom endpoint." required:"N"`
 	AccessKeyID     string `yaml:"access_key_id" env:"AWS_SQS_ACCESS_KEY_ID" desc:"AWS Access Key ID for SQS. Optional: omit (with the secret access key) to use the AWS SDK default credential chain, e.g. an IAM role." required:"N"`
 	SecretAccessKey string `yaml:"secret_access_key" env:"AWS_SQS_SECRET_ACCESS_KEY" desc:"AWS Secret Access Key for SQS. Optional: omit (with the access key ID) to use the AWS SDK default credential chain, e.g. an IAM role." required:"N"`
 	Region          string `yaml:"region" env:"AWS_SQS_REGION" desc:"AWS Region for SQS. Required if AWS SQS is the chosen MQ provider." required:"C"`
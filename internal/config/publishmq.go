@@ -1,9 +1,12 @@
 package config
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 
 	"github.com/hookdeck/outpost/internal/mqs"
+	"github.com/hookdeck/outpost/internal/publishmq"
 )
 
 type PublishAWSSQSConfig struct {
@@ -33,11 +36,35 @@ type PublishRabbitMQConfig struct {
 	Queue     string `yaml:"queue" env:"PUBLISH_RABBITMQ_QUEUE" desc:"Name of the RabbitMQ queue for publishing events. Required if RabbitMQ is the chosen publish MQ provider." required:"C"`
 }
 
+type PublishNATSJetStreamConfig struct {
+	ServerURL       string `yaml:"server_url" env:"PUBLISH_NATS_SERVER_URL" desc:"NATS server URL for the publish stream. Required if NATS JetStream is the chosen publish MQ provider." required:"C"`
+	CredentialsFile string `yaml:"credentials_file" env:"PUBLISH_NATS_CREDENTIALS_FILE" desc:"Path to a NATS user credentials (.creds) file for the publish stream. Optional." required:"N"`
+	Stream          string `yaml:"stream" env:"PUBLISH_NATS_STREAM" desc:"Name of the JetStream stream published events are read from. Required if NATS JetStream is the chosen publish MQ provider." required:"C"`
+	Consumer        string `yaml:"consumer" env:"PUBLISH_NATS_CONSUMER" desc:"Name of the durable pull consumer of the stream to read published events from. Required if NATS JetStream is the chosen publish MQ provider." required:"C"`
+}
+
+type PublishKafkaConfig struct {
+	Brokers         []string `yaml:"brokers" env:"PUBLISH_KAFKA_BROKERS" envSeparator:"," desc:"Comma-separated list of Kafka broker addresses (host:port) to consume published events from. Required if Kafka is the chosen publish MQ provider." required:"C"`
+	Topics          []string `yaml:"topics" env:"PUBLISH_KAFKA_TOPICS" envSeparator:"," desc:"Comma-separated list of Kafka topics to consume published events from. Required if Kafka is the chosen publish MQ provider." required:"C"`
+	GroupID         string   `yaml:"group_id" env:"PUBLISH_KAFKA_GROUP_ID" desc:"Kafka consumer group ID shared by the Outpost instances consuming the topics. Required if Kafka is the chosen publish MQ provider." required:"C"`
+	SASLMechanism   string   `yaml:"sasl_mechanism" env:"PUBLISH_KAFKA_SASL_MECHANISM" desc:"SASL mechanism to authenticate with: 'plain', 'scram-sha-256' or 'scram-sha-512'. If unset, SASL is not used." required:"N"`
+	Username        string   `yaml:"username" env:"PUBLISH_KAFKA_USERNAME" desc:"SASL username for the Kafka brokers." required:"N"`
+	Password        string   `yaml:"password" env:"PUBLISH_KAFKA_PASSWORD" desc:"SASL password for the Kafka brokers." required:"N"`
+	TLS             bool     `yaml:"tls" env:"PUBLISH_KAFKA_TLS" desc:"Connect to the Kafka brokers over TLS." required:"N"`
+	DeadLetterTopic string   `yaml:"dead_letter_topic" env:"PUBLISH_KAFKA_DEAD_LETTER_TOPIC" desc:"Kafka topic that messages still failing to publish after max_redeliveries are written to, so their partition can move on. If unset, such messages are redelivered with backoff until they publish." required:"N"`
+	MaxRedeliveries int      `yaml:"max_redeliveries" env:"PUBLISH_KAFKA_MAX_REDELIVERIES" desc:"Times a message that fails to publish is redelivered before it is written to dead_letter_topic. If unset, defaults to 5." required:"N"`
+	TenantIDFrom    string   `yaml:"tenant_id_from" env:"PUBLISH_KAFKA_TENANT_ID_FROM" desc:"Where to read the tenant ID of a message that isn't a published event: 'header:<name>', 'field:<path>' (a dot-separated path in the JSON message), 'topic' or 'key'. If unset, messages must be published events." required:"N"`
+	TopicFrom       string   `yaml:"topic_from" env:"PUBLISH_KAFKA_TOPIC_FROM" desc:"Where to read the event topic of a mapped message, in the same form as tenant_id_from. Requires tenant_id_from." required:"N"`
+	EventIDFrom     string   `yaml:"event_id_from" env:"PUBLISH_KAFKA_EVENT_ID_FROM" desc:"Where to read the event ID of a mapped message, in the same form as tenant_id_from. Requires tenant_id_from. If unset, the ID is derived from the message's topic, partition and offset." required:"N"`
+}
+
 type PublishMQConfig struct {
 	AWSSQS          PublishAWSSQSConfig          `yaml:"aws_sqs" desc:"Configuration for using AWS SQS as the publish message queue. Only one publish MQ provider should be configured." required:"N"`
 	AzureServiceBus PublishAzureServiceBusConfig `yaml:"azure_servicebus" desc:"Configuration for using Azure Service Bus as the publish message queue. Only one publish MQ provider should be configured." required:"N"`
 	GCPPubSub       PublishGCPPubSubConfig       `yaml:"gcp_pubsub" desc:"Configuration for using GCP Pub/Sub as the publish message queue. Only one publish MQ provider should be configured." required:"N"`
 	RabbitMQ        PublishRabbitMQConfig        `yaml:"rabbitmq" desc:"Configuration for using RabbitMQ as the publish message queue. Only one publish MQ provider should be configured." required:"N"`
+	NATSJetStream   PublishNATSJetStreamConfig   `yaml:"nats_jetstream" desc:"Configuration for using NATS JetStream as the publish message queue. Only one publish MQ provider should be configured." required:"N"`
+	Kafka           PublishKafkaConfig           `yaml:"kafka" desc:"Configuration for consuming published events from Kafka topics. Only one publish MQ provider should be configured." required:"N"`
 }
 
 func (c PublishMQConfig) GetInfraType() string {
@@ -53,6 +80,12 @@ func (c PublishMQConfig) GetInfraType() string {
 	if hasPublishRabbitMQConfig(c.RabbitMQ) {
 		return "rabbitmq"
 	}
+	if hasPublishNATSJetStreamConfig(c.NATSJetStream) {
+		return "natsjetstream"
+	}
+	if hasPublishKafkaConfig(c.Kafka) {
+		return "kafka"
+	}
 	return ""
 }
 
@@ -94,17 +127,88 @@ func (c *PublishMQConfig) GetQueueConfig() *mqs.QueueConfig {
 				Queue:     c.RabbitMQ.Queue,
 			},
 		}
+	case "natsjetstream":
+		return &mqs.QueueConfig{
+			NATSJetStream: &mqs.NATSJetStreamConfig{
+				ServerURL:       c.NATSJetStream.ServerURL,
+				CredentialsFile: c.NATSJetStream.CredentialsFile,
+				Stream:          c.NATSJetStream.Stream,
+				Consumer:        c.NATSJetStream.Consumer,
+			},
+		}
+	case "kafka":
+		return &mqs.QueueConfig{
+			Kafka: &mqs.KafkaConfig{
+				Brokers:         c.Kafka.Brokers,
+				Topics:          c.Kafka.Topics,
+				GroupID:         c.Kafka.GroupID,
+				SASLMechanism:   c.Kafka.SASLMechanism,
+				Username:        c.Kafka.Username,
+				Password:        c.Kafka.Password,
+				TLS:             c.Kafka.TLS,
+				DeadLetterTopic: c.Kafka.DeadLetterTopic,
+				MaxRedeliveries: c.Kafka.MaxRedeliveries,
+			},
+		}
 	default:
 		return nil
 	}
 }
 
-// Validate enforces the AWS SQS partial-credential rule for the selected provider.
+// GetEventMapping returns the mapping to build events from the messages of
+// the selected provider, or nil when its messages are published events.
+func (c *PublishMQConfig) GetEventMapping() *publishmq.EventMapping {
+	if c.GetInfraType() != "kafka" || c.Kafka.TenantIDFrom == "" {
+		return nil
+	}
+	return &publishmq.EventMapping{
+		TenantID: c.Kafka.TenantIDFrom,
+		Topic:    c.Kafka.TopicFrom,
+		ID:       c.Kafka.EventIDFrom,
+	}
+}
+
+// Validate enforces the AWS SQS partial-credential rule and the NATS
+// JetStream and Kafka consumer settings for the selected provider.
 func (c *PublishMQConfig) Validate() error {
-	if c.GetInfraType() == "awssqs" {
+	switch c.GetInfraType() {
+	case "awssqs":
 		if (c.AWSSQS.AccessKeyID == "") != (c.AWSSQS.SecretAccessKey == "") {
 			return errPartialAWSSQSCredentials
 		}
+	case "natsjetstream":
+		if c.NATSJetStream.Stream == "" || c.NATSJetStream.Consumer == "" {
+			return errors.New("nats jetstream: stream and consumer are required")
+		}
+	case "kafka":
+		return c.validateKafka()
+	}
+	return nil
+}
+
+var kafkaSASLMechanisms = []string{"plain", "scram-sha-256", "scram-sha-512"}
+
+func (c *PublishMQConfig) validateKafka() error {
+	if len(c.Kafka.Topics) == 0 || c.Kafka.GroupID == "" {
+		return errors.New("kafka: topics and group_id are required")
+	}
+	if c.Kafka.SASLMechanism != "" && !slices.Contains(kafkaSASLMechanisms, c.Kafka.SASLMechanism) {
+		return fmt.Errorf("kafka: unsupported sasl_mechanism %q", c.Kafka.SASLMechanism)
+	}
+	if c.Kafka.MaxRedeliveries < 0 {
+		return errors.New("kafka: max_redeliveries must not be negative")
+	}
+	if c.Kafka.DeadLetterTopic != "" && slices.Contains(c.Kafka.Topics, c.Kafka.DeadLetterTopic) {
+		return errors.New("kafka: dead_letter_topic must not be one of the consumed topics")
+	}
+	if c.Kafka.TenantIDFrom == "" {
+		if c.Kafka.TopicFrom != "" || c.Kafka.EventIDFrom != "" {
+			return errors.New("kafka: topic_from and event_id_from require tenant_id_from")
+		}
+		return nil
+	}
+	if err := c.GetEventMapping().Validate(); err != nil {
+		return fmt.Errorf("kafka: %w", err)
 	}
 	return nil
 }
@@ -126,3 +230,11 @@ func hasPublishGCPPubSubConfig(config PublishGCPPubSubConfig) bool {
 func hasPublishRabbitMQConfig(config PublishRabbitMQConfig) bool {
 	return config.ServerURL != ""
 }
+
+func hasPublishNATSJetStreamConfig(config PublishNATSJetStreamConfig) bool {
+	return config.ServerURL != ""
+}
+
+func hasPublishKafkaConfig(config PublishKafkaConfig) bool {
+	return len(config.Brokers) > 0
+}
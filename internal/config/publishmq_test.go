@@ -73,6 +73,48 @@ func TestPublishMQConfig_Validate(t *testing.T) {
 			cfg:     config.PublishMQConfig{AWSSQS: config.PublishAWSSQSConfig{SecretAccessKey: "SECRET", Region: "us-east-1"}},
 			wantErr: true,
 		},
+		{
+			name: "nats jetstream",
+			cfg:  config.PublishMQConfig{NATSJetStream: config.PublishNATSJetStreamConfig{ServerURL: "nats://localhost:4222", Stream: "events", Consumer: "outpost"}},
+		},
+		{
+			name:    "nats jetstream without consumer",
+			cfg:     config.PublishMQConfig{NATSJetStream: config.PublishNATSJetStreamConfig{ServerURL: "nats://localhost:4222", Stream: "events"}},
+			wantErr: true,
+		},
+		{
+			name: "kafka",
+			cfg:  config.PublishMQConfig{Kafka: config.PublishKafkaConfig{Brokers: []string{"localhost:9092"}, Topics: []string{"orders"}, GroupID: "outpost"}},
+		},
+		{
+			name:    "kafka without group id",
+			cfg:     config.PublishMQConfig{Kafka: config.PublishKafkaConfig{Brokers: []string{"localhost:9092"}, Topics: []string{"orders"}}},
+			wantErr: true,
+		},
+		{
+			name:    "kafka unsupported sasl mechanism",
+			cfg:     config.PublishMQConfig{Kafka: config.PublishKafkaConfig{Brokers: []string{"localhost:9092"}, Topics: []string{"orders"}, GroupID: "outpost", SASLMechanism: "gssapi"}},
+			wantErr: true,
+		},
+		{
+			name:    "kafka dead-letter topic among consumed topics",
+			cfg:     config.PublishMQConfig{Kafka: config.PublishKafkaConfig{Brokers: []string{"localhost:9092"}, Topics: []string{"orders"}, GroupID: "outpost", DeadLetterTopic: "orders"}},
+			wantErr: true,
+		},
+		{
+			name: "kafka event mapping",
+			cfg:  config.PublishMQConfig{Kafka: config.PublishKafkaConfig{Brokers: []string{"localhost:9092"}, Topics: []string{"orders"}, GroupID: "outpost", TenantIDFrom: "header:tenant-id", TopicFrom: "topic"}},
+		},
+		{
+			name:    "kafka event mapping unknown source",
+			cfg:     config.PublishMQConfig{Kafka: config.PublishKafkaConfig{Brokers: []string{"localhost:9092"}, Topics: []string{"orders"}, GroupID: "outpost", TenantIDFrom: "body.tenant"}},
+			wantErr: true,
+		},
+		{
+			name:    "kafka topic mapping without tenant mapping",
+			cfg:     config.PublishMQConfig{Kafka: config.PublishKafkaConfig{Brokers: []string{"localhost:9092"}, Topics: []string{"orders"}, GroupID: "outpost", TopicFrom: "topic"}},
+			wantErr: true,
+		},
 		{
 			name: "no publish provider configured",
 			cfg:  config.PublishMQConfig{},
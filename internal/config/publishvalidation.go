@@ -0,0 +1,31 @@
+package config
+
+import (
+	"time"
+
+	"github.com/hookdeck/outpost/internal/publishhook"
+)
+
+// PublishValidationConfig is the configuration for the HTTP publish
+// validation hook
+type PublishValidationConfig struct {
+	URL           string   `yaml:"url" env:"PUBLISH_VALIDATION_URL" desc:"URL called synchronously on publish to validate each event of publish_validation.topics. It can reject the event or add metadata to it. If empty, events are not validated." required:"N"`
+	Topics        []string `yaml:"topics" env:"PUBLISH_VALIDATION_TOPICS" envSeparator:"," desc:"Comma-separated list of topics whose events are validated. Wildcards are allowed. If empty, events of every topic are validated." required:"N"`
+	SigningSecret string   `yaml:"signing_secret" env:"PUBLISH_VALIDATION_SIGNING_SECRET" desc:"Secret used to sign each validation request with HMAC-SHA256. The signature is sent in the X-Outpost-Signature header. If empty, requests are not signed." required:"N"`
+	TimeoutMs     int      `yaml:"timeout_ms" env:"PUBLISH_VALIDATION_TIMEOUT_MS" desc:"Time in milliseconds the validation endpoint may take for one event. Default: 500" required:"N"`
+	FailClosed    bool     `yaml:"fail_closed" env:"PUBLISH_VALIDATION_FAIL_CLOSED" desc:"If true, a failed or timed out validation fails the publish. If false, the event is published unvalidated. Default: false" required:"N"`
+}
+
+// ToConfig returns the configured validation hook, which is disabled when no
+// URL is set.
+func (c *PublishValidationConfig) ToConfig() publishhook.Hook {
+	hook := publishhook.Hook{
+		Topics:     c.Topics,
+		Timeout:    time.Duration(c.TimeoutMs) * time.Millisecond,
+		FailClosed: c.FailClosed,
+	}
+	if c.URL != "" {
+		hook.Validator = publishhook.NewHTTPValidator(c.URL, publishhook.WithSigningSecret(c.SigningSecret))
+	}
+	return hook
+}
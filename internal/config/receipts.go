@@ -0,0 +1,28 @@
+package config
+
+import "github.com/hookdeck/outpost/internal/receipts"
+
+// ReceiptsConfig is the configuration for daily delivery receipts
+type ReceiptsConfig struct {
+	Enabled         bool   `yaml:"enabled" env:"RECEIPTS_ENABLED" desc:"If true, the API service writes a daily receipt of successful deliveries to the S3 bucket configured on each tenant's receipt_storage." required:"N"`
+	AccessKeyID     string `yaml:"access_key_id" env:"RECEIPTS_AWS_ACCESS_KEY_ID" desc:"AWS access key ID used to write receipts. If empty, the default AWS credential chain (environment, instance or task role) is used." required:"N"`
+	SecretAccessKey string `yaml:"secret_access_key" env:"RECEIPTS_AWS_SECRET_ACCESS_KEY" desc:"AWS secret access key used to write receipts." required:"N"`
+	Endpoint        string `yaml:"endpoint" env:"RECEIPTS_AWS_S3_ENDPOINT" desc:"Custom S3 endpoint for receipts. Optional, for local development or S3-compatible storage." required:"N"`
+	SigningKey      string `yaml:"signing_key" env:"RECEIPTS_SIGNING_KEY" desc:"Base64-encoded Ed25519 private key (32-byte seed) used to sign receipts. Generate one with 'outpost receipts keygen'. If empty, receipts are not signed." required:"N"`
+}
+
+func (c *ReceiptsConfig) ToConfig() receipts.S3Config {
+	return receipts.S3Config{
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		Endpoint:        c.Endpoint,
+	}
+}
+
+// Signer returns the receipt signer, or nil when no signing key is set.
+func (c *ReceiptsConfig) Signer() (*receipts.Signer, error) {
+	if c.SigningKey == "" {
+		return nil, nil
+	}
+	return receipts.NewSigner(c.SigningKey)
+}
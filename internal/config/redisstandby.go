@@ -0,0 +1,53 @@
+package config
+
+import (
+	"time"
+
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/redisstandby"
+)
+
+// RedisStandbyConfig is the configuration of the Redis failover readiness check
+type RedisStandbyConfig struct {
+	Enabled               bool    `yaml:"enabled" env:"REDIS_STANDBY_CHECK_ENABLED" desc:"If true, every service periodically checks that Redis is ready to fail over and reports it in /healthz and metrics. Without redis_standby.host, checks that every shard of a cluster has a caught up replica." required:"N"`
+	Host                  string  `yaml:"host" env:"REDIS_STANDBY_HOST" desc:"Hostname or IP address of the standby replica of the Redis primary. Required when the check is enabled outside cluster mode." required:"N"`
+	Port                  int     `yaml:"port" env:"REDIS_STANDBY_PORT" desc:"Port number of the standby replica. If unset, redis.port is used." required:"N"`
+	Username              string  `yaml:"username" env:"REDIS_STANDBY_USERNAME" desc:"Username for the standby replica. If unset, redis.username is used." required:"N"`
+	Password              string  `yaml:"password" env:"REDIS_STANDBY_PASSWORD" desc:"Password for the standby replica. If unset, redis.password is used." required:"N"`
+	IntervalSeconds       int     `yaml:"interval_seconds" env:"REDIS_STANDBY_CHECK_INTERVAL_SECONDS" desc:"Seconds between failover readiness checks. Default: 60" required:"N"`
+	MaxLagBytes           int64   `yaml:"max_lag_bytes" env:"REDIS_STANDBY_MAX_LAG_BYTES" desc:"Bytes of the replication stream a replica may trail its primary by and still be ready. Default: 1048576" required:"N"`
+	MaxMissingKeysPercent float64 `yaml:"max_missing_keys_percent" env:"REDIS_STANDBY_MAX_MISSING_KEYS_PERCENT" desc:"Percentage of the primary's keys the standby may lack and still be ready, to allow for keys written or expired during a check. Not checked in cluster mode. Default: 1" required:"N"`
+}
+
+// Interval returns the time between failover readiness checks.
+func (c *RedisStandbyConfig) Interval() time.Duration {
+	return time.Duration(c.IntervalSeconds) * time.Second
+}
+
+// ToConfig returns the connection of the standby, inheriting what it leaves
+// unset from the primary's, or nil in cluster mode.
+func (c *RedisStandbyConfig) ToConfig(primary *RedisConfig) *redis.RedisConfig {
+	if primary.ClusterEnabled {
+		return nil
+	}
+	config := primary.ToConfig()
+	config.Host = c.Host
+	if c.Port != 0 {
+		config.Port = c.Port
+	}
+	if c.Username != "" {
+		config.Username = c.Username
+	}
+	if c.Password != "" {
+		config.Password = c.Password
+	}
+	return config
+}
+
+// Options returns the thresholds of the check.
+func (c *RedisStandbyConfig) Options() redisstandby.Options {
+	return redisstandby.Options{
+		MaxLagBytes:           c.MaxLagBytes,
+		MaxMissingKeysPercent: c.MaxMissingKeysPercent,
+	}
+}
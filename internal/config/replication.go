@@ -0,0 +1,43 @@
+package config
+
+import (
+	"time"
+
+	"github.com/hookdeck/outpost/internal/redis"
+)
+
+// ReplicationConfig is the configuration for replicating tenants and
+// destinations from a primary deployment to a secondary one
+type ReplicationConfig struct {
+	ChangefeedEnabled  bool   `yaml:"changefeed_enabled" env:"REPLICATION_CHANGEFEED_ENABLED" desc:"If true, tenant and destination changes are recorded in a Redis stream that secondary deployments replicate from. Enable it on the primary deployment." required:"N"`
+	ChangefeedMaxLen   int64  `yaml:"changefeed_max_len" env:"REPLICATION_CHANGEFEED_MAX_LEN" desc:"Approximate number of changes kept in the changefeed. A secondary that falls further behind runs a full sync. Default: 100000" required:"N"`
+	Enabled            bool   `yaml:"enabled" env:"REPLICATION_ENABLED" desc:"If true, the API service replicates tenants and destinations from the primary deployment's Redis into this deployment. Writes made to this deployment are overwritten by later changes on the primary." required:"N"`
+	SourceHost         string `yaml:"source_host" env:"REPLICATION_SOURCE_HOST" desc:"Hostname or IP address of the primary deployment's Redis. Required when replication is enabled." required:"N"`
+	SourcePort         int    `yaml:"source_port" env:"REPLICATION_SOURCE_PORT" desc:"Port number of the primary deployment's Redis. If unset, redis.port is used." required:"N"`
+	SourceUsername     string `yaml:"source_username" env:"REPLICATION_SOURCE_USERNAME" desc:"Username for the primary deployment's Redis. If unset, redis.username is used." required:"N"`
+	SourcePassword     string `yaml:"source_password" env:"REPLICATION_SOURCE_PASSWORD" desc:"Password for the primary deployment's Redis. If unset, redis.password is used." required:"N"`
+	SourceDeploymentID string `yaml:"source_deployment_id" env:"REPLICATION_SOURCE_DEPLOYMENT_ID" desc:"Deployment ID of the primary deployment, if it sets one." required:"N"`
+	PollIntervalMs     int    `yaml:"poll_interval_ms" env:"REPLICATION_POLL_INTERVAL_MS" desc:"Milliseconds between reads of the changefeed when the secondary is caught up. Default: 1000" required:"N"`
+}
+
+// PollInterval returns the time between reads of a caught up changefeed.
+func (c *ReplicationConfig) PollInterval() time.Duration {
+	return time.Duration(c.PollIntervalMs) * time.Millisecond
+}
+
+// ToConfig returns the connection of the primary deployment's Redis,
+// inheriting what it leaves unset from the local one's.
+func (c *ReplicationConfig) ToConfig(local *RedisConfig) *redis.RedisConfig {
+	config := local.ToConfig()
+	config.Host = c.SourceHost
+	if c.SourcePort != 0 {
+		config.Port = c.SourcePort
+	}
+	if c.SourceUsername != "" {
+		config.Username = c.SourceUsername
+	}
+	if c.SourcePassword != "" {
+		config.Password = c.SourcePassword
+	}
+	return config
+}
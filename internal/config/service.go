@@ -7,6 +7,7 @@ const (
 	ServiceTypeAPI
 	ServiceTypeLog
 	ServiceTypeDelivery
+	ServiceTypeArchiver
 )
 
 func (s ServiceType) String() string {
@@ -19,6 +20,8 @@ func (s ServiceType) String() string {
 		return "log"
 	case ServiceTypeDelivery:
 		return "delivery"
+	case ServiceTypeArchiver:
+		return "archiver"
 	}
 	return "unknown"
 }
@@ -35,6 +38,8 @@ func ServiceTypeFromString(s string) (ServiceType, error) {
 		return ServiceTypeLog, nil
 	case "delivery":
 		return ServiceTypeDelivery, nil
+	case "archiver":
+		return ServiceTypeArchiver, nil
 	}
 	return ServiceType(-1), ErrInvalidServiceType
 }
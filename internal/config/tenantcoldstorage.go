@@ -0,0 +1,26 @@
+package config
+
+import "github.com/hookdeck/outpost/internal/logarchive"
+
+// TenantColdStorageConfig is the configuration for the object storage
+// suspended tenants are moved to
+type TenantColdStorageConfig struct {
+	Enabled         bool   `yaml:"enabled" env:"TENANT_COLD_STORAGE_ENABLED" desc:"If true, tenants can be suspended with POST /tenants/{tenant_id}/suspend, which moves their data from Redis to the configured bucket until they are next accessed." required:"N"`
+	Bucket          string `yaml:"bucket" env:"TENANT_COLD_STORAGE_BUCKET" desc:"S3 bucket suspended tenants are written to. Required if tenant_cold_storage.enabled is true." required:"C"`
+	Prefix          string `yaml:"prefix" env:"TENANT_COLD_STORAGE_PREFIX" desc:"Key prefix of suspended tenants in the bucket." required:"N"`
+	Region          string `yaml:"region" env:"TENANT_COLD_STORAGE_REGION" desc:"Region of the bucket." required:"N"`
+	AccessKeyID     string `yaml:"access_key_id" env:"TENANT_COLD_STORAGE_ACCESS_KEY_ID" desc:"Access key ID used to read and write suspended tenants. If empty, the default AWS credential chain (environment, instance or task role) is used." required:"N"`
+	SecretAccessKey string `yaml:"secret_access_key" env:"TENANT_COLD_STORAGE_SECRET_ACCESS_KEY" desc:"Secret access key used to read and write suspended tenants." required:"N"`
+	Endpoint        string `yaml:"endpoint" env:"TENANT_COLD_STORAGE_ENDPOINT" desc:"Custom S3 endpoint, e.g. 'https://storage.googleapis.com' with HMAC keys for Google Cloud Storage, or a local endpoint for development." required:"N"`
+}
+
+func (c *TenantColdStorageConfig) ToConfig() logarchive.S3Config {
+	return logarchive.S3Config{
+		Bucket:          c.Bucket,
+		Prefix:          c.Prefix,
+		Region:          c.Region,
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		Endpoint:        c.Endpoint,
+	}
+}
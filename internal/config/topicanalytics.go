@@ -0,0 +1,8 @@
+package config
+
+// TopicAnalyticsConfig is the configuration for topic volume and payload size
+// analytics
+type TopicAnalyticsConfig struct {
+	Enabled       bool `yaml:"enabled" env:"TOPIC_ANALYTICS_ENABLED" desc:"If true, the API service records the topic and payload size of each accepted event in Redis, and reports them per deployment and tenant at GET /analytics/topics." required:"N"`
+	RetentionDays int  `yaml:"retention_days" env:"TOPIC_ANALYTICS_RETENTION_DAYS" desc:"Days of topic analytics kept, and the longest range a report can cover. Default: 90" required:"N"`
+}
@@ -5,6 +5,11 @@ import (
 	"fmt"
 	"net/url"
 	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/models"
 )
 
 // Validate checks if the configuration is valid
@@ -21,6 +26,10 @@ func (c *Config) Validate(flags Flags) error {
 		return err
 	}
 
+	if err := c.validateConnectionURLs(); err != nil {
+		return err
+	}
+
 	if err := c.validateLogStorage(); err != nil {
 		return err
 	}
@@ -41,6 +50,98 @@ func (c *Config) Validate(flags Flags) error {
 		return err
 	}
 
+	if err := c.validateTrustedProxies(); err != nil {
+		return err
+	}
+
+	if err := c.validateGRPCPort(); err != nil {
+		return err
+	}
+
+	if err := c.validateWebhookSecretRetrievalPolicy(); err != nil {
+		return err
+	}
+
+	if err := c.validateHeaderLimits(); err != nil {
+		return err
+	}
+
+	if err := c.validateQuotaWarning(); err != nil {
+		return err
+	}
+
+	if err := c.validateEventQuota(); err != nil {
+		return err
+	}
+
+	if err := c.validatePublishRate(); err != nil {
+		return err
+	}
+
+	if err := c.validateIdempotencyKeyWindow(); err != nil {
+		return err
+	}
+
+	if err := c.validateDNSCache(); err != nil {
+		return err
+	}
+
+	if err := c.validateFanoutLimits(); err != nil {
+		return err
+	}
+
+	if err := c.validateLogRetention(); err != nil {
+		return err
+	}
+
+	if err := c.validateLogStore(); err != nil {
+		return err
+	}
+
+	if err := c.validateTopicLifecycle(); err != nil {
+		return err
+	}
+
+	if err := c.validateTopicNamespace(); err != nil {
+		return err
+	}
+
+	if err := c.validateReceipts(); err != nil {
+		return err
+	}
+
+	if err := c.validateRedisStandby(); err != nil {
+		return err
+	}
+
+	if err := c.validateReplication(); err != nil {
+		return err
+	}
+
+	if err := c.validateDeliveryJournal(); err != nil {
+		return err
+	}
+
+	if err := c.validateLogBatch(); err != nil {
+		return err
+	}
+
+	if err := c.validateTopicAnalytics(); err != nil {
+		return err
+	}
+
+	if err := c.validateLogArchive(); err != nil {
+		return err
+	}
+
+	if err := c.validateTenantColdStorage(); err != nil {
+		return err
+	}
+
+	if err := c.validateLogRedaction(); err != nil {
+		return err
+	}
+
 	if err := c.validateDeploymentID(); err != nil {
 		return err
 	}
@@ -135,6 +236,10 @@ func (c *Config) validateMQs() error {
 		return ErrMissingMQs
 	}
 
+	if c.MQs.MaxVisibilityExtensionSeconds < 0 {
+		return ErrInvalidMQVisibility
+	}
+
 	return nil
 }
 
@@ -151,9 +256,39 @@ func (c *Config) validateAESEncryptionSecret() error {
 	if c.AESEncryptionSecret == "" {
 		return ErrMissingAESSecret
 	}
+	if _, err := c.AESEncryptionKeyRing(); err != nil {
+		return err
+	}
 	return nil
 }
 
+// AESEncryptionKeyRing parses aes_encryption_keys into a map of key ID to
+// secret. It returns nil when no key ring is configured.
+func (c *Config) AESEncryptionKeyRing() (map[string]string, error) {
+	if len(c.AESEncryptionKeys) == 0 {
+		if c.AESEncryptionPrimaryKeyID != "" {
+			return nil, ErrInvalidAESKeyRing
+		}
+		return nil, nil
+	}
+	keys := make(map[string]string, len(c.AESEncryptionKeys))
+	for _, entry := range c.AESEncryptionKeys {
+		id, secret, ok := strings.Cut(entry, ":")
+		id = strings.TrimSpace(id)
+		if !ok || id == "" || secret == "" {
+			return nil, ErrInvalidAESKeyRing
+		}
+		if _, exists := keys[id]; exists {
+			return nil, ErrInvalidAESKeyRing
+		}
+		keys[id] = secret
+	}
+	if _, ok := keys[c.AESEncryptionPrimaryKeyID]; !ok {
+		return nil, ErrInvalidAESKeyRing
+	}
+	return keys, nil
+}
+
 // validatePortalProxyURL validates the portal proxy URL if set
 func (c *Config) validatePortal() error {
 	if c.Portal.ProxyURL != "" {
@@ -164,6 +299,272 @@ func (c *Config) validatePortal() error {
 	return nil
 }
 
+// validateTrustedProxies rejects API trusted proxies that are neither an IP
+// address nor a CIDR range.
+func (c *Config) validateTrustedProxies() error {
+	for _, proxy := range c.APITrustedProxies {
+		if _, err := models.ParseIPRange(proxy); err != nil {
+			return ErrInvalidTrustedProxies
+		}
+	}
+	return nil
+}
+
+// validateGRPCPort rejects a gRPC port that can't be listened on alongside
+// the API port.
+func (c *Config) validateGRPCPort() error {
+	if c.GRPCPort < 0 || c.GRPCPort > 65535 || (c.GRPCPort != 0 && c.GRPCPort == c.APIPort) {
+		return ErrInvalidGRPCPort
+	}
+	return nil
+}
+
+// validateWebhookSecretRetrievalPolicy rejects unknown secret retrieval
+// policies so a typo cannot silently expose secrets that were meant to be
+// write-only.
+func (c *Config) validateWebhookSecretRetrievalPolicy() error {
+	if !destwebhook.IsValidSecretRetrievalPolicy(c.Destinations.Webhook.SecretRetrievalPolicy) {
+		return ErrInvalidSecretPolicy
+	}
+	return nil
+}
+
+// validateHeaderLimits rejects negative destination header limits; 0 is the
+// way to disable a limit.
+func (c *Config) validateHeaderLimits() error {
+	if c.Destinations.MaxHeaders < 0 || c.Destinations.MaxHeaderBytes < 0 {
+		return ErrInvalidHeaderLimits
+	}
+	return nil
+}
+
+// validateQuotaWarning rejects a quota warning percentage outside 0-100.
+func (c *Config) validateQuotaWarning() error {
+	if c.QuotaWarningPercent < 0 || c.QuotaWarningPercent > 100 {
+		return ErrInvalidQuotaWarning
+	}
+	return nil
+}
+
+// validateEventQuota rejects a negative event quota; 0 is the way to disable
+// it.
+func (c *Config) validateEventQuota() error {
+	if c.MaxEventsPerMinutePerTenant < 0 {
+		return ErrInvalidEventQuota
+	}
+	return nil
+}
+
+// validatePublishRate rejects a negative publish rate limit; 0 is the way to
+// disable it.
+func (c *Config) validatePublishRate() error {
+	if c.PublishRateLimitPerSecond < 0 || c.PublishRateLimitBurst < 0 {
+		return ErrInvalidPublishRate
+	}
+	return nil
+}
+
+// validateIdempotencyKeyWindow rejects a negative publish idempotency key
+// window; 0 is the way to disable idempotency keys.
+func (c *Config) validateIdempotencyKeyWindow() error {
+	if c.PublishIdempotencyKeyWindow < 0 {
+		return ErrInvalidIdempotencyKey
+	}
+	return nil
+}
+
+// validateDNSCache rejects negative DNS cache durations; a 0 TTL is the way to
+// disable the cache.
+func (c *Config) validateDNSCache() error {
+	if c.DeliveryDNSCacheTTLSeconds < 0 || c.DeliveryDNSCacheStaleSeconds < 0 {
+		return ErrInvalidDNSCache
+	}
+	return nil
+}
+
+// validateFanoutLimits rejects negative or malformed delivery fan-out caps.
+func (c *Config) validateFanoutLimits() error {
+	if c.DeliveryFanoutMaxConcurrencyPerEvent < 0 {
+		return ErrInvalidFanoutLimits
+	}
+	if _, err := parseFanoutTopicLimits(c.DeliveryFanoutTopicLimits); err != nil {
+		return ErrInvalidFanoutLimits
+	}
+	return nil
+}
+
+// validateLogRetention rejects negative retention.
+func (c *Config) validateLogRetention() error {
+	if c.LogRetentionDays < 0 || c.LogRetentionSuccessDays < 0 || c.LogRetentionFailedDays < 0 {
+		return ErrInvalidLogRetention
+	}
+	return nil
+}
+
+// validateLogStore checks the log store tuning against the configured driver
+// and that tiering has both of its stores.
+func (c *Config) validateLogStore() error {
+	if err := c.LogStore.ToConfig().Validate(c.LogStoreDriver()); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidLogStore, err)
+	}
+	if c.LogStore.HotTierMaxAgeHours < 0 || (c.LogStore.HotTierMaxAgeHours > 0 && c.HotTierMaxAge() == 0) {
+		return ErrInvalidLogStoreTiers
+	}
+	if archive := c.ArchiveTierMaxAge(); archive > 0 && c.HotTierMaxAge() >= archive {
+		return ErrInvalidLogStoreTiers
+	}
+	return nil
+}
+
+// validateTopicLifecycle ensures deprecated and retired topics are configured
+// topics, or covered by a configured pattern, (when a topic list is set) and
+// that no topic is in both states.
+func (c *Config) validateTopicLifecycle() error {
+	for _, topic := range c.TopicsRetired {
+		if slices.Contains(c.TopicsDeprecated, topic) {
+			return ErrInvalidTopicLifecycle
+		}
+	}
+	if len(c.Topics) == 0 {
+		return nil
+	}
+	for _, topic := range slices.Concat(c.TopicsDeprecated, c.TopicsRetired) {
+		if !models.TopicAvailable(c.Topics, topic) {
+			return ErrInvalidTopicLifecycle
+		}
+	}
+	return nil
+}
+
+// validateTopicNamespace ensures the topic namespace can prefix a topic: it
+// must not contain the "." separating it from the topic, or a wildcard.
+func (c *Config) validateTopicNamespace() error {
+	if c.TopicNamespace == "" {
+		return nil
+	}
+	if len(c.TopicNamespace) > 64 || !topicNamespacePattern.MatchString(c.TopicNamespace) {
+		return ErrInvalidTopicNamespace
+	}
+	return nil
+}
+
+var topicNamespacePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// validateReceipts rejects a malformed receipt signing key at startup rather
+// than on the first daily run.
+func (c *Config) validateReceipts() error {
+	if _, err := c.Receipts.Signer(); err != nil {
+		return ErrInvalidReceiptsKey
+	}
+	return nil
+}
+
+// validateRedisStandby validates the failover readiness check. Without a
+// cluster, there is no replica to check unless a standby is configured.
+func (c *Config) validateRedisStandby() error {
+	if !c.RedisStandby.Enabled {
+		return nil
+	}
+	if !c.Redis.ClusterEnabled && c.RedisStandby.Host == "" {
+		return ErrInvalidRedisStandby
+	}
+	if c.RedisStandby.IntervalSeconds <= 0 || c.RedisStandby.MaxLagBytes < 0 || c.RedisStandby.MaxMissingKeysPercent < 0 {
+		return ErrInvalidRedisStandby
+	}
+	return nil
+}
+
+// validateReplication validates the changefeed of a primary deployment and
+// the replication of a secondary one.
+func (c *Config) validateReplication() error {
+	if c.Replication.ChangefeedEnabled && c.Replication.ChangefeedMaxLen <= 0 {
+		return ErrInvalidReplication
+	}
+	if !c.Replication.Enabled {
+		return nil
+	}
+	if c.Replication.SourceHost == "" || c.Replication.PollIntervalMs <= 0 {
+		return ErrInvalidReplication
+	}
+	return nil
+}
+
+// validateDeliveryJournal checks that journaled attempts are kept past the
+// grace period, or they would expire before they could be backfilled.
+func (c *Config) validateDeliveryJournal() error {
+	if !c.DeliveryJournal.Enabled {
+		return nil
+	}
+	if c.DeliveryJournal.RetentionHours <= 0 || c.DeliveryJournal.GracePeriodSeconds <= 0 ||
+		c.DeliveryJournal.GracePeriod() >= c.DeliveryJournal.Retention() {
+		return ErrInvalidJournal
+	}
+	return nil
+}
+
+// validateTopicAnalytics checks that topic analytics keep at least a day.
+func (c *Config) validateTopicAnalytics() error {
+	if c.TopicAnalytics.Enabled && c.TopicAnalytics.RetentionDays <= 0 {
+		return ErrInvalidTopicAnalytics
+	}
+	return nil
+}
+
+// validateLogBatch checks that adaptive log batching has a range to adapt in.
+func (c *Config) validateLogBatch() error {
+	a := c.LogBatchAdaptive
+	if !a.Enabled {
+		return nil
+	}
+	if a.MinSize <= 0 || a.MinSize > a.MaxSize ||
+		a.MinThresholdMs <= 0 || a.MinThresholdMs > a.MaxThresholdMs ||
+		a.LatencySLOMs <= 0 {
+		return ErrInvalidLogBatch
+	}
+	return nil
+}
+
+// validateLogArchive checks that logs are archived well before retention
+// deletes them. Pruning waits for the archive, but ClickHouse TTLs don't, so
+// the archive must come first on every log store.
+func (c *Config) validateLogArchive() error {
+	if !c.LogArchive.Enabled {
+		if service, _ := c.GetService(); service == ServiceTypeArchiver {
+			return ErrArchiverDisabled
+		}
+		if c.LogArchive.ServeReads {
+			return ErrInvalidLogArchive
+		}
+		return nil
+	}
+	if c.LogArchive.Bucket == "" || c.LogArchive.AfterDays <= 0 {
+		return ErrInvalidLogArchive
+	}
+	policy := c.LogRetentionPolicy()
+	for _, days := range []int{policy.SuccessDays, policy.FailedDays} {
+		if days > 0 && c.LogArchive.AfterDays >= days {
+			return ErrInvalidLogArchive
+		}
+	}
+	return nil
+}
+
+// validateTenantColdStorage checks that tenants can be suspended somewhere.
+func (c *Config) validateTenantColdStorage() error {
+	if c.TenantColdStorage.Enabled && c.TenantColdStorage.Bucket == "" {
+		return ErrInvalidColdStorage
+	}
+	return nil
+}
+
+// validateLogRedaction checks the redacted header patterns and JSONPaths.
+func (c *Config) validateLogRedaction() error {
+	if _, err := c.LogRedaction.ToRedactor(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidLogRedaction, err)
+	}
+	return nil
+}
+
 // validateDeploymentID validates the deployment ID format
 // Empty string is allowed (optional field)
 // If provided, must contain only alphanumeric characters, hyphens, and underscores
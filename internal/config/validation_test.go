@@ -135,6 +135,75 @@ func TestRedis(t *testing.T) {
 			}(),
 			wantErr: config.ErrMissingRedis,
 		},
+		{
+			name: "standby check with a standby",
+			config: func() *config.Config {
+				c := validConfig()
+				c.RedisStandby.Enabled = true
+				c.RedisStandby.Host = "redis-replica"
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "standby check of a cluster",
+			config: func() *config.Config {
+				c := validConfig()
+				c.Redis.ClusterEnabled = true
+				c.RedisStandby.Enabled = true
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "standby check without a standby",
+			config: func() *config.Config {
+				c := validConfig()
+				c.RedisStandby.Enabled = true
+				return c
+			}(),
+			wantErr: config.ErrInvalidRedisStandby,
+		},
+		{
+			name: "standby check without an interval",
+			config: func() *config.Config {
+				c := validConfig()
+				c.RedisStandby.Enabled = true
+				c.RedisStandby.Host = "redis-replica"
+				c.RedisStandby.IntervalSeconds = 0
+				return c
+			}(),
+			wantErr: config.ErrInvalidRedisStandby,
+		},
+		{
+			name: "replication from a primary",
+			config: func() *config.Config {
+				c := validConfig()
+				c.Replication.Enabled = true
+				c.Replication.SourceHost = "redis-primary"
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "replication without a primary",
+			config: func() *config.Config {
+				c := validConfig()
+				c.Replication.Enabled = true
+				return c
+			}(),
+			wantErr: config.ErrInvalidReplication,
+		},
+		{
+			name: "changefeed without a max length",
+			config: func() *config.Config {
+				c := validConfig()
+				c.Replication.ChangefeedEnabled = true
+				c.Replication.ChangefeedMaxLen = 0
+				return c
+			}(),
+			wantErr: config.ErrInvalidReplication,
+		},
 	}
 
 	for _, tt := range tests {
@@ -204,6 +273,15 @@ func TestMQs(t *testing.T) {
 			}(),
 			wantErr: config.ErrMissingMQs,
 		},
+		{
+			name: "negative max visibility extension",
+			config: func() *config.Config {
+				c := validConfig()
+				c.MQs.MaxVisibilityExtensionSeconds = -1
+				return c
+			}(),
+			wantErr: config.ErrInvalidMQVisibility,
+		},
 	}
 
 	for _, tt := range tests {
@@ -238,6 +316,94 @@ func TestMisc(t *testing.T) {
 			}(),
 			wantErr: config.ErrMissingAESSecret,
 		},
+		{
+			name: "valid delivery journal",
+			config: func() *config.Config {
+				c := validConfig()
+				c.DeliveryJournal.Enabled = true
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "delivery journal grace period past its retention",
+			config: func() *config.Config {
+				c := validConfig()
+				c.DeliveryJournal.Enabled = true
+				c.DeliveryJournal.RetentionHours = 1
+				c.DeliveryJournal.GracePeriodSeconds = 3600
+				return c
+			}(),
+			wantErr: config.ErrInvalidJournal,
+		},
+		{
+			name: "topic analytics without retention",
+			config: func() *config.Config {
+				c := validConfig()
+				c.TopicAnalytics.Enabled = true
+				c.TopicAnalytics.RetentionDays = 0
+				return c
+			}(),
+			wantErr: config.ErrInvalidTopicAnalytics,
+		},
+		{
+			name: "valid adaptive log batching",
+			config: func() *config.Config {
+				c := validConfig()
+				c.LogBatchAdaptive.Enabled = true
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "adaptive log batching minimum above its maximum",
+			config: func() *config.Config {
+				c := validConfig()
+				c.LogBatchAdaptive.Enabled = true
+				c.LogBatchAdaptive.MinSize = 20000
+				return c
+			}(),
+			wantErr: config.ErrInvalidLogBatch,
+		},
+		{
+			name: "valid aes key ring",
+			config: func() *config.Config {
+				c := validConfig()
+				c.AESEncryptionKeys = []string{"k1:secret-one", "k2:secret-two"}
+				c.AESEncryptionPrimaryKeyID = "k2"
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "aes key ring without primary key id",
+			config: func() *config.Config {
+				c := validConfig()
+				c.AESEncryptionKeys = []string{"k1:secret-one"}
+				return c
+			}(),
+			wantErr: config.ErrInvalidAESKeyRing,
+		},
+		{
+			name: "aes key ring with malformed entry",
+			config: func() *config.Config {
+				c := validConfig()
+				c.AESEncryptionKeys = []string{"secret-without-id"}
+				c.AESEncryptionPrimaryKeyID = "secret-without-id"
+				return c
+			}(),
+			wantErr: config.ErrInvalidAESKeyRing,
+		},
+		{
+			name: "aes key ring with duplicate id",
+			config: func() *config.Config {
+				c := validConfig()
+				c.AESEncryptionKeys = []string{"k1:secret-one", "k1:secret-two"}
+				c.AESEncryptionPrimaryKeyID = "k1"
+				return c
+			}(),
+			wantErr: config.ErrInvalidAESKeyRing,
+		},
 		{
 			name:    "valid portal proxy url",
 			config:  validConfig(),
@@ -261,6 +427,386 @@ func TestMisc(t *testing.T) {
 			}(),
 			wantErr: config.ErrInvalidPortalProxyURL,
 		},
+		{
+			name: "masked webhook secret retrieval policy is valid",
+			config: func() *config.Config {
+				c := validConfig()
+				c.Destinations.Webhook.SecretRetrievalPolicy = "masked"
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "invalid webhook secret retrieval policy",
+			config: func() *config.Config {
+				c := validConfig()
+				c.Destinations.Webhook.SecretRetrievalPolicy = "hidden"
+				return c
+			}(),
+			wantErr: config.ErrInvalidSecretPolicy,
+		},
+		{
+			name: "zero header limits are valid",
+			config: func() *config.Config {
+				c := validConfig()
+				c.Destinations.MaxHeaders = 0
+				c.Destinations.MaxHeaderBytes = 0
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "negative header limit",
+			config: func() *config.Config {
+				c := validConfig()
+				c.Destinations.MaxHeaders = -1
+				return c
+			}(),
+			wantErr: config.ErrInvalidHeaderLimits,
+		},
+		{
+			name: "quota warning above 100 percent",
+			config: func() *config.Config {
+				c := validConfig()
+				c.QuotaWarningPercent = 120
+				return c
+			}(),
+			wantErr: config.ErrInvalidQuotaWarning,
+		},
+		{
+			name: "negative event quota",
+			config: func() *config.Config {
+				c := validConfig()
+				c.MaxEventsPerMinutePerTenant = -1
+				return c
+			}(),
+			wantErr: config.ErrInvalidEventQuota,
+		},
+		{
+			name: "negative publish rate burst",
+			config: func() *config.Config {
+				c := validConfig()
+				c.PublishRateLimitPerSecond = 10
+				c.PublishRateLimitBurst = -1
+				return c
+			}(),
+			wantErr: config.ErrInvalidPublishRate,
+		},
+		{
+			name: "negative publish idempotency key window",
+			config: func() *config.Config {
+				c := validConfig()
+				c.PublishIdempotencyKeyWindow = -1
+				return c
+			}(),
+			wantErr: config.ErrInvalidIdempotencyKey,
+		},
+		{
+			name: "disabled dns cache",
+			config: func() *config.Config {
+				c := validConfig()
+				c.DeliveryDNSCacheTTLSeconds = 0
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "negative dns cache stale window",
+			config: func() *config.Config {
+				c := validConfig()
+				c.DeliveryDNSCacheStaleSeconds = -1
+				return c
+			}(),
+			wantErr: config.ErrInvalidDNSCache,
+		},
+		{
+			name: "fan-out limits",
+			config: func() *config.Config {
+				c := validConfig()
+				c.DeliveryFanoutMaxConcurrencyPerEvent = 10
+				c.DeliveryFanoutTopicLimits = []string{"broadcast:5", "user.created:20:100"}
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "malformed fan-out topic limit",
+			config: func() *config.Config {
+				c := validConfig()
+				c.DeliveryFanoutTopicLimits = []string{"broadcast"}
+				return c
+			}(),
+			wantErr: config.ErrInvalidFanoutLimits,
+		},
+		{
+			name: "negative fan-out limit",
+			config: func() *config.Config {
+				c := validConfig()
+				c.DeliveryFanoutMaxConcurrencyPerEvent = -1
+				return c
+			}(),
+			wantErr: config.ErrInvalidFanoutLimits,
+		},
+		{
+			name: "trusted proxies",
+			config: func() *config.Config {
+				c := validConfig()
+				c.APITrustedProxies = []string{"10.0.0.0/8", "192.0.2.1"}
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "invalid trusted proxy",
+			config: func() *config.Config {
+				c := validConfig()
+				c.APITrustedProxies = []string{"load-balancer"}
+				return c
+			}(),
+			wantErr: config.ErrInvalidTrustedProxies,
+		},
+		{
+			name: "grpc port",
+			config: func() *config.Config {
+				c := validConfig()
+				c.GRPCPort = 3334
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "grpc port same as api port",
+			config: func() *config.Config {
+				c := validConfig()
+				c.APIPort = 3333
+				c.GRPCPort = 3333
+				return c
+			}(),
+			wantErr: config.ErrInvalidGRPCPort,
+		},
+		{
+			name: "per-status log retention",
+			config: func() *config.Config {
+				c := validConfig()
+				c.LogRetentionSuccessDays = 14
+				c.LogRetentionFailedDays = 90
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "logstore codec for the wrong driver",
+			config: func() *config.Config {
+				c := validConfig()
+				c.LogStore.CompressionCodec = "ZSTD(3)"
+				return c
+			}(),
+			wantErr: config.ErrInvalidLogStore,
+		},
+		{
+			name: "logstore hot tier without clickhouse",
+			config: func() *config.Config {
+				c := validConfig()
+				c.LogStore.HotTierMaxAgeHours = 24
+				return c
+			}(),
+			wantErr: config.ErrInvalidLogStoreTiers,
+		},
+		{
+			name: "logstore hot tier over clickhouse",
+			config: func() *config.Config {
+				c := validConfig()
+				c.ClickHouse.Addr = "localhost:9000"
+				c.LogStore.HotTierMaxAgeHours = 24
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "negative log retention",
+			config: func() *config.Config {
+				c := validConfig()
+				c.LogRetentionFailedDays = -1
+				return c
+			}(),
+			wantErr: config.ErrInvalidLogRetention,
+		},
+		{
+			name: "valid receipts signing key",
+			config: func() *config.Config {
+				c := validConfig()
+				c.Receipts.SigningKey = "AAECAwQFBgcICQoLDA0ODxAREhMUFRYXGBkaGxwdHh8="
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "invalid receipts signing key",
+			config: func() *config.Config {
+				c := validConfig()
+				c.Receipts.SigningKey = "not-a-key"
+				return c
+			}(),
+			wantErr: config.ErrInvalidReceiptsKey,
+		},
+		{
+			name: "valid log archive",
+			config: func() *config.Config {
+				c := validConfig()
+				c.LogArchive = config.LogArchiveConfig{Enabled: true, AfterDays: 7, Bucket: "archive"}
+				c.LogRetentionDays = 30
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "log archive without bucket",
+			config: func() *config.Config {
+				c := validConfig()
+				c.LogArchive = config.LogArchiveConfig{Enabled: true, AfterDays: 7}
+				return c
+			}(),
+			wantErr: config.ErrInvalidLogArchive,
+		},
+		{
+			name: "log archive after retention",
+			config: func() *config.Config {
+				c := validConfig()
+				c.LogArchive = config.LogArchiveConfig{Enabled: true, AfterDays: 14, Bucket: "archive"}
+				c.LogRetentionSuccessDays = 14
+				c.LogRetentionFailedDays = 90
+				return c
+			}(),
+			wantErr: config.ErrInvalidLogArchive,
+		},
+		{
+			name: "log archive serving reads while disabled",
+			config: func() *config.Config {
+				c := validConfig()
+				c.LogArchive = config.LogArchiveConfig{AfterDays: 7, Bucket: "archive", ServeReads: true}
+				return c
+			}(),
+			wantErr: config.ErrInvalidLogArchive,
+		},
+		{
+			name: "logstore hot tier past the archive tier",
+			config: func() *config.Config {
+				c := validConfig()
+				c.ClickHouse.Addr = "localhost:9000"
+				c.LogStore.HotTierMaxAgeHours = 10 * 24
+				c.LogArchive = config.LogArchiveConfig{Enabled: true, AfterDays: 3, Bucket: "archive", ServeReads: true}
+				c.LogRetentionDays = 7
+				return c
+			}(),
+			wantErr: config.ErrInvalidLogStoreTiers,
+		},
+		{
+			name: "tenant cold storage without bucket",
+			config: func() *config.Config {
+				c := validConfig()
+				c.TenantColdStorage = config.TenantColdStorageConfig{Enabled: true}
+				return c
+			}(),
+			wantErr: config.ErrInvalidColdStorage,
+		},
+		{
+			name: "log redaction",
+			config: func() *config.Config {
+				c := validConfig()
+				c.LogRedaction = config.LogRedactionConfig{
+					Headers:   []string{"x-*-key"},
+					JSONPaths: []string{"$.body.access_token", "$..password"},
+				}
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "log redaction with invalid json path",
+			config: func() *config.Config {
+				c := validConfig()
+				c.LogRedaction = config.LogRedactionConfig{JSONPaths: []string{"body.access_token"}}
+				return c
+			}(),
+			wantErr: config.ErrInvalidLogRedaction,
+		},
+		{
+			name: "archiver service without log archive",
+			config: func() *config.Config {
+				c := validConfig()
+				c.Service = "archiver"
+				return c
+			}(),
+			wantErr: config.ErrArchiverDisabled,
+		},
+		{
+			name: "deprecated and retired topics from topic list",
+			config: func() *config.Config {
+				c := validConfig()
+				c.Topics = []string{"user.created", "user.updated", "user.deleted"}
+				c.TopicsDeprecated = []string{"user.updated"}
+				c.TopicsRetired = []string{"user.deleted"}
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "retired topic not in topic list",
+			config: func() *config.Config {
+				c := validConfig()
+				c.Topics = []string{"user.created"}
+				c.TopicsRetired = []string{"user.deleted"}
+				return c
+			}(),
+			wantErr: config.ErrInvalidTopicLifecycle,
+		},
+		{
+			name: "retired topic covered by topic pattern",
+			config: func() *config.Config {
+				c := validConfig()
+				c.Topics = []string{"user.*"}
+				c.TopicsRetired = []string{"user.deleted"}
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "topic both deprecated and retired",
+			config: func() *config.Config {
+				c := validConfig()
+				c.TopicsDeprecated = []string{"user.deleted"}
+				c.TopicsRetired = []string{"user.deleted"}
+				return c
+			}(),
+			wantErr: config.ErrInvalidTopicLifecycle,
+		},
+		{
+			name: "valid topic namespace",
+			config: func() *config.Config {
+				c := validConfig()
+				c.TopicNamespace = "acme_billing-1"
+				return c
+			}(),
+			wantErr: nil,
+		},
+		{
+			name: "topic namespace with separator",
+			config: func() *config.Config {
+				c := validConfig()
+				c.TopicNamespace = "acme.billing"
+				return c
+			}(),
+			wantErr: config.ErrInvalidTopicNamespace,
+		},
+		{
+			name: "topic namespace with wildcard",
+			config: func() *config.Config {
+				c := validConfig()
+				c.TopicNamespace = "acme*"
+				return c
+			}(),
+			wantErr: config.ErrInvalidTopicNamespace,
+		},
 		{
 			name: "empty deployment id is valid",
 			config: func() *config.Config {
@@ -0,0 +1,65 @@
+// Package deliveryack tracks deliveries awaiting a consumer acknowledgment.
+//
+// A webhook destination with an ack timeout receives an ack token with each
+// delivery. When the consumer accepts the delivery with 202 instead of
+// processing it in-line, the delivery is registered here under its token and
+// a retry is scheduled for when the timeout elapses. The consumer confirms
+// processing by acknowledging the token, which cancels that retry.
+package deliveryack
+
+import (
+	"context"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/tokenstore"
+)
+
+var ErrNotFound = tokenstore.ErrNotFound
+
+// Pending is a delivery awaiting acknowledgment.
+type Pending struct {
+	TenantID      string `json:"tenant_id"`
+	EventID       string `json:"event_id"`
+	DestinationID string `json:"destination_id"`
+	Topic         string `json:"topic"`
+	AttemptID     string `json:"attempt_id"`
+	AttemptNumber int    `json:"attempt_number"`
+}
+
+type Store interface {
+	// Register records a pending delivery under token until ttl elapses.
+	Register(ctx context.Context, token string, pending Pending, ttl time.Duration) error
+	// Retrieve returns the pending delivery for token, or ErrNotFound once
+	// it was deleted or expired.
+	Retrieve(ctx context.Context, token string) (*Pending, error)
+	Delete(ctx context.Context, token string) error
+}
+
+type Option = tokenstore.Option
+
+func WithDeploymentID(deploymentID string) Option {
+	return tokenstore.WithDeploymentID(deploymentID)
+}
+
+// New returns a Store backed by Redis. Each pending delivery is a key that
+// expires with its ack timeout.
+func New(redisClient redis.Cmdable, opts ...Option) Store {
+	return &redisStore{tokens: tokenstore.New[Pending](redisClient, "deliveryack", opts...)}
+}
+
+type redisStore struct {
+	tokens *tokenstore.Store[Pending]
+}
+
+func (s *redisStore) Register(ctx context.Context, token string, pending Pending, ttl time.Duration) error {
+	return s.tokens.Set(ctx, token, pending, ttl)
+}
+
+func (s *redisStore) Retrieve(ctx context.Context, token string) (*Pending, error) {
+	return s.tokens.Get(ctx, token)
+}
+
+func (s *redisStore) Delete(ctx context.Context, token string) error {
+	return s.tokens.Delete(ctx, token)
+}
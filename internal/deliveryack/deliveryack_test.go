@@ -0,0 +1,57 @@
+package deliveryack_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/deliveryack"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	pending := deliveryack.Pending{
+		TenantID:      "t1",
+		EventID:       "e1",
+		DestinationID: "d1",
+		AttemptID:     "a1",
+	}
+
+	t.Run("registers and retrieves a pending delivery", func(t *testing.T) {
+		t.Parallel()
+		store := deliveryack.New(testutil.CreateTestRedisClient(t))
+
+		require.NoError(t, store.Register(t.Context(), "token", pending, time.Minute))
+
+		got, err := store.Retrieve(t.Context(), "token")
+		require.NoError(t, err)
+		assert.Equal(t, pending, *got)
+	})
+
+	t.Run("deleted token is not found", func(t *testing.T) {
+		t.Parallel()
+		store := deliveryack.New(testutil.CreateTestRedisClient(t))
+
+		require.NoError(t, store.Register(t.Context(), "token", pending, time.Minute))
+		require.NoError(t, store.Delete(t.Context(), "token"))
+
+		_, err := store.Retrieve(t.Context(), "token")
+		assert.ErrorIs(t, err, deliveryack.ErrNotFound)
+	})
+
+	t.Run("tokens are scoped to the deployment", func(t *testing.T) {
+		t.Parallel()
+		redisClient := testutil.CreateTestRedisClient(t)
+		store := deliveryack.New(redisClient, deliveryack.WithDeploymentID("dp1"))
+
+		require.NoError(t, store.Register(t.Context(), "token", pending, time.Minute))
+
+		_, err := deliveryack.New(redisClient).Retrieve(t.Context(), "token")
+		assert.ErrorIs(t, err, deliveryack.ErrNotFound)
+		_, err = store.Retrieve(t.Context(), "token")
+		assert.NoError(t, err)
+	})
+}
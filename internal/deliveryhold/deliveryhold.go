@@ -0,0 +1,132 @@
+// Package deliveryhold holds the deliveries queued for a destination before
+// its target changed, when the destination asked for them to be held.
+//
+// Each destination's held deliveries are a Redis hash keyed by event ID, so
+// a delivery held again, such as on redelivery, replaces itself. They stay
+// held until they are released to the new target or discarded, for at most
+// Retention after the last one was held.
+package deliveryhold
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/redis"
+)
+
+// Retention is how long a destination's held deliveries are kept after the
+// last one was held.
+const Retention = 7 * 24 * time.Hour
+
+// Held is a held delivery.
+type Held struct {
+	Task   models.DeliveryTask `json:"task"`
+	HeldAt time.Time           `json:"held_at"`
+}
+
+// Store holds deliveries per destination.
+type Store struct {
+	redisClient  redis.Cmdable
+	deploymentID string
+	clock        clock.Clock
+}
+
+type Option func(*Store)
+
+func WithDeploymentID(deploymentID string) Option {
+	return func(s *Store) {
+		s.deploymentID = deploymentID
+	}
+}
+
+func WithClock(c clock.Clock) Option {
+	return func(s *Store) {
+		s.clock = c
+	}
+}
+
+// New returns a store of held deliveries in Redis.
+func New(redisClient redis.Cmdable, opts ...Option) *Store {
+	s := &Store{
+		redisClient: redisClient,
+		clock:       clock.New(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Store) key(tenantID, destinationID string) string {
+	key := "deliveryhold:" + tenantID + ":" + destinationID
+	if s.deploymentID == "" {
+		return key
+	}
+	return s.deploymentID + ":" + key
+}
+
+// Hold holds a delivery task.
+func (s *Store) Hold(ctx context.Context, task models.DeliveryTask) error {
+	data, err := json.Marshal(Held{Task: task, HeldAt: s.clock.Now()})
+	if err != nil {
+		return err
+	}
+	key := s.key(task.Event.TenantID, task.DestinationID)
+	pipe := s.redisClient.TxPipeline()
+	pipe.HSet(ctx, key, task.Event.ID, string(data))
+	pipe.Expire(ctx, key, Retention)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to hold delivery: %w", err)
+	}
+	return nil
+}
+
+// List returns a destination's held deliveries, oldest first.
+func (s *Store) List(ctx context.Context, tenantID, destinationID string) ([]Held, error) {
+	fields, err := s.redisClient.HGetAll(ctx, s.key(tenantID, destinationID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list held deliveries: %w", err)
+	}
+	held := make([]Held, 0, len(fields))
+	for _, data := range fields {
+		var h Held
+		if err := json.Unmarshal([]byte(data), &h); err != nil {
+			continue
+		}
+		held = append(held, h)
+	}
+	slices.SortFunc(held, func(a, b Held) int {
+		return a.HeldAt.Compare(b.HeldAt)
+	})
+	return held, nil
+}
+
+// Take removes the held deliveries of the given events, or all of them when
+// eventIDs is empty, and returns those it removed, oldest first. A delivery
+// is taken by one caller only, so it is never released twice.
+func (s *Store) Take(ctx context.Context, tenantID, destinationID string, eventIDs []string) ([]Held, error) {
+	held, err := s.List(ctx, tenantID, destinationID)
+	if err != nil {
+		return nil, err
+	}
+	key := s.key(tenantID, destinationID)
+	var taken []Held
+	for _, h := range held {
+		if len(eventIDs) > 0 && !slices.Contains(eventIDs, h.Task.Event.ID) {
+			continue
+		}
+		removed, err := s.redisClient.HDel(ctx, key, h.Task.Event.ID).Result()
+		if err != nil {
+			return taken, fmt.Errorf("failed to take held delivery: %w", err)
+		}
+		if removed == 1 {
+			taken = append(taken, h)
+		}
+	}
+	return taken, nil
+}
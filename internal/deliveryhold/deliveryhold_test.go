@@ -0,0 +1,90 @@
+package deliveryhold_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/deliveryhold"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func heldEventIDs(held []deliveryhold.Held) []string {
+	ids := make([]string, len(held))
+	for i, h := range held {
+		ids[i] = h.Task.Event.ID
+	}
+	return ids
+}
+
+func newTask(eventID string) models.DeliveryTask {
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithID(eventID),
+		testutil.EventFactory.WithTenantID("t1"),
+	)
+	return models.NewDeliveryTask(event, "d1")
+}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("lists held deliveries oldest first", func(t *testing.T) {
+		t.Parallel()
+		clk := clock.NewFake(time.Now())
+		store := deliveryhold.New(testutil.CreateTestRedisClient(t), deliveryhold.WithClock(clk))
+
+		require.NoError(t, store.Hold(t.Context(), newTask("e2")))
+		clk.Advance(time.Second)
+		require.NoError(t, store.Hold(t.Context(), newTask("e1")))
+
+		held, err := store.List(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"e2", "e1"}, heldEventIDs(held))
+
+		held, err = store.List(t.Context(), "t1", "d2")
+		require.NoError(t, err)
+		assert.Empty(t, held)
+	})
+
+	t.Run("holding a delivery again replaces it", func(t *testing.T) {
+		t.Parallel()
+		store := deliveryhold.New(testutil.CreateTestRedisClient(t))
+
+		task := newTask("e1")
+		require.NoError(t, store.Hold(t.Context(), task))
+		task.Attempt = 2
+		require.NoError(t, store.Hold(t.Context(), task))
+
+		held, err := store.List(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		require.Len(t, held, 1)
+		assert.Equal(t, 2, held[0].Task.Attempt)
+	})
+
+	t.Run("takes the given deliveries once", func(t *testing.T) {
+		t.Parallel()
+		store := deliveryhold.New(testutil.CreateTestRedisClient(t))
+		for _, id := range []string{"e1", "e2", "e3"} {
+			require.NoError(t, store.Hold(t.Context(), newTask(id)))
+		}
+
+		taken, err := store.Take(t.Context(), "t1", "d1", []string{"e2", "unknown"})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"e2"}, heldEventIDs(taken))
+
+		taken, err = store.Take(t.Context(), "t1", "d1", []string{"e2"})
+		require.NoError(t, err)
+		assert.Empty(t, taken)
+
+		taken, err = store.Take(t.Context(), "t1", "d1", nil)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"e1", "e3"}, heldEventIDs(taken))
+
+		held, err := store.List(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		assert.Empty(t, held)
+	})
+}
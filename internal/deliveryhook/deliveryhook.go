@@ -0,0 +1,171 @@
+// Package deliveryhook lets code outside the delivery worker take part in
+// each delivery attempt.
+//
+// A pre-delivery hook runs before an event is published to a destination.
+// It can add headers, which are merged into the metadata of the event sent
+// for that attempt only (and so become headers, attributes or properties
+// depending on the destination type), or veto the delivery altogether. A
+// post-delivery hook runs once the attempt is known, for instance to record
+// custom metrics. Hooks are either in-process (PreDeliveryFunc,
+// PostDeliveryFunc) or an HTTP endpoint (HTTPHook).
+//
+// The hooks of a stage share a time budget per attempt, so a slow hook
+// delays deliveries by at most that much.
+package deliveryhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// DefaultBudget is the time the hooks of a stage may take for one attempt
+// when Hooks.Budget is unset.
+const DefaultBudget = time.Second
+
+// ErrBudgetExceeded is returned when the hooks of a stage run out of time.
+var ErrBudgetExceeded = errors.New("deliveryhook: time budget exceeded")
+
+// Delivery describes the attempt a hook runs for. Hooks must not modify the
+// event or destination.
+type Delivery struct {
+	Event         *models.Event
+	Destination   *models.Destination
+	AttemptNumber int
+	Manual        bool
+}
+
+// Decision is the outcome of the pre-delivery hooks.
+type Decision struct {
+	// Veto drops the delivery: nothing is published, no attempt is logged
+	// and no retry is scheduled.
+	Veto bool
+	// Reason explains a veto in the delivery worker's log.
+	Reason string
+	// Headers are added to the metadata of the delivered event. They
+	// override event metadata but not the data-checksum.
+	Headers map[string]string
+}
+
+// PreDeliveryHook runs before an event is published to a destination.
+type PreDeliveryHook interface {
+	BeforeDelivery(ctx context.Context, delivery *Delivery) (Decision, error)
+}
+
+// PostDeliveryHook runs after an attempt, successful or not.
+type PostDeliveryHook interface {
+	AfterDelivery(ctx context.Context, delivery *Delivery, attempt *models.Attempt) error
+}
+
+// PreDeliveryFunc adapts a function to a PreDeliveryHook.
+type PreDeliveryFunc func(ctx context.Context, delivery *Delivery) (Decision, error)
+
+func (f PreDeliveryFunc) BeforeDelivery(ctx context.Context, delivery *Delivery) (Decision, error) {
+	return f(ctx, delivery)
+}
+
+// PostDeliveryFunc adapts a function to a PostDeliveryHook.
+type PostDeliveryFunc func(ctx context.Context, delivery *Delivery, attempt *models.Attempt) error
+
+func (f PostDeliveryFunc) AfterDelivery(ctx context.Context, delivery *Delivery, attempt *models.Attempt) error {
+	return f(ctx, delivery, attempt)
+}
+
+// Hooks is the chain of hooks run around each delivery attempt.
+type Hooks struct {
+	Pre  []PreDeliveryHook
+	Post []PostDeliveryHook
+	// Budget caps the time the hooks of each stage take for one attempt.
+	// Default: DefaultBudget.
+	Budget time.Duration
+	// FailClosed fails the delivery when a pre-delivery hook returns an
+	// error or the budget runs out; the delivery is then redelivered by the
+	// queue. By default the failure is logged and the delivery proceeds.
+	FailClosed bool
+}
+
+// Enabled reports whether any hook is registered.
+func (h Hooks) Enabled() bool {
+	return len(h.Pre) > 0 || len(h.Post) > 0
+}
+
+func (h Hooks) budget() time.Duration {
+	if h.Budget > 0 {
+		return h.Budget
+	}
+	return DefaultBudget
+}
+
+// BeforeDelivery runs the pre-delivery hooks in order. Headers of later
+// hooks override earlier ones, and the first veto ends the chain. The
+// returned decision holds what the hooks decided even when some of them
+// failed; the failures are joined in the error.
+func (h Hooks) BeforeDelivery(ctx context.Context, delivery *Delivery) (Decision, error) {
+	var decision Decision
+	if len(h.Pre) == 0 {
+		return decision, nil
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, h.budget(), ErrBudgetExceeded)
+	defer cancel()
+
+	var errs []error
+	for i, hook := range h.Pre {
+		if err := context.Cause(ctx); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		d, err := hook.BeforeDelivery(ctx, delivery)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("pre-delivery hook %d: %w", i, budgetCause(ctx, err)))
+			continue
+		}
+		if len(d.Headers) > 0 {
+			if decision.Headers == nil {
+				decision.Headers = make(map[string]string, len(d.Headers))
+			}
+			maps.Copy(decision.Headers, d.Headers)
+		}
+		if d.Veto {
+			decision.Veto = true
+			decision.Reason = d.Reason
+			break
+		}
+	}
+	return decision, errors.Join(errs...)
+}
+
+// AfterDelivery runs every post-delivery hook and joins their errors.
+func (h Hooks) AfterDelivery(ctx context.Context, delivery *Delivery, attempt *models.Attempt) error {
+	if len(h.Post) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeoutCause(ctx, h.budget(), ErrBudgetExceeded)
+	defer cancel()
+
+	var errs []error
+	for i, hook := range h.Post {
+		if err := context.Cause(ctx); err != nil {
+			errs = append(errs, err)
+			break
+		}
+		if err := hook.AfterDelivery(ctx, delivery, attempt); err != nil {
+			errs = append(errs, fmt.Errorf("post-delivery hook %d: %w", i, budgetCause(ctx, err)))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// budgetCause reports a hook that failed because the budget ran out as
+// ErrBudgetExceeded rather than as a bare deadline error.
+func budgetCause(ctx context.Context, err error) error {
+	if errors.Is(context.Cause(ctx), ErrBudgetExceeded) && errors.Is(err, context.DeadlineExceeded) {
+		return fmt.Errorf("%w: %w", ErrBudgetExceeded, err)
+	}
+	return err
+}
@@ -0,0 +1,178 @@
+package deliveryhook_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/deliveryhook"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDelivery() *deliveryhook.Delivery {
+	event := testutil.EventFactory.Any()
+	destination := testutil.DestinationFactory.Any()
+	return &deliveryhook.Delivery{Event: &event, Destination: &destination, AttemptNumber: 1}
+}
+
+func headers(h map[string]string) deliveryhook.PreDeliveryFunc {
+	return func(ctx context.Context, d *deliveryhook.Delivery) (deliveryhook.Decision, error) {
+		return deliveryhook.Decision{Headers: h}, nil
+	}
+}
+
+func TestHooks_BeforeDelivery(t *testing.T) {
+	t.Parallel()
+
+	t.Run("merges headers in order", func(t *testing.T) {
+		hooks := deliveryhook.Hooks{Pre: []deliveryhook.PreDeliveryHook{
+			headers(map[string]string{"a": "1", "b": "1"}),
+			headers(map[string]string{"b": "2"}),
+		}}
+		decision, err := hooks.BeforeDelivery(context.Background(), newDelivery())
+		require.NoError(t, err)
+		assert.False(t, decision.Veto)
+		assert.Equal(t, map[string]string{"a": "1", "b": "2"}, decision.Headers)
+	})
+
+	t.Run("first veto ends the chain", func(t *testing.T) {
+		called := false
+		hooks := deliveryhook.Hooks{Pre: []deliveryhook.PreDeliveryHook{
+			deliveryhook.PreDeliveryFunc(func(ctx context.Context, d *deliveryhook.Delivery) (deliveryhook.Decision, error) {
+				return deliveryhook.Decision{Veto: true, Reason: "blocked"}, nil
+			}),
+			deliveryhook.PreDeliveryFunc(func(ctx context.Context, d *deliveryhook.Delivery) (deliveryhook.Decision, error) {
+				called = true
+				return deliveryhook.Decision{}, nil
+			}),
+		}}
+		decision, err := hooks.BeforeDelivery(context.Background(), newDelivery())
+		require.NoError(t, err)
+		assert.True(t, decision.Veto)
+		assert.Equal(t, "blocked", decision.Reason)
+		assert.False(t, called)
+	})
+
+	t.Run("keeps other hooks' headers when one fails", func(t *testing.T) {
+		hooks := deliveryhook.Hooks{Pre: []deliveryhook.PreDeliveryHook{
+			deliveryhook.PreDeliveryFunc(func(ctx context.Context, d *deliveryhook.Delivery) (deliveryhook.Decision, error) {
+				return deliveryhook.Decision{}, errors.New("boom")
+			}),
+			headers(map[string]string{"a": "1"}),
+		}}
+		decision, err := hooks.BeforeDelivery(context.Background(), newDelivery())
+		require.ErrorContains(t, err, "boom")
+		assert.Equal(t, map[string]string{"a": "1"}, decision.Headers)
+	})
+
+	t.Run("stops when the budget runs out", func(t *testing.T) {
+		hooks := deliveryhook.Hooks{
+			Budget: 20 * time.Millisecond,
+			Pre: []deliveryhook.PreDeliveryHook{
+				deliveryhook.PreDeliveryFunc(func(ctx context.Context, d *deliveryhook.Delivery) (deliveryhook.Decision, error) {
+					<-ctx.Done()
+					return deliveryhook.Decision{}, ctx.Err()
+				}),
+				headers(map[string]string{"a": "1"}),
+			},
+		}
+		decision, err := hooks.BeforeDelivery(context.Background(), newDelivery())
+		require.ErrorIs(t, err, deliveryhook.ErrBudgetExceeded)
+		assert.Empty(t, decision.Headers)
+	})
+}
+
+func TestHooks_AfterDelivery(t *testing.T) {
+	t.Parallel()
+
+	calls := 0
+	hook := deliveryhook.PostDeliveryFunc(func(ctx context.Context, d *deliveryhook.Delivery, attempt *models.Attempt) error {
+		calls++
+		return errors.New("boom")
+	})
+	hooks := deliveryhook.Hooks{Post: []deliveryhook.PostDeliveryHook{hook, hook}}
+
+	err := hooks.AfterDelivery(context.Background(), newDelivery(), &models.Attempt{ID: "atm_1"})
+	require.Error(t, err)
+	assert.Equal(t, 2, calls, "every post-delivery hook runs")
+}
+
+func TestHTTPHook(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sends a signed request and reads the decision", func(t *testing.T) {
+		var got deliveryhook.HTTPRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := io.ReadAll(r.Body)
+			mac := hmac.New(sha256.New, []byte("secret"))
+			mac.Write(body)
+			assert.Equal(t, "v0="+hex.EncodeToString(mac.Sum(nil)), r.Header.Get("X-Outpost-Signature"))
+			require.NoError(t, json.Unmarshal(body, &got))
+			w.Write([]byte(`{"veto": true, "reason": "blocked", "headers": {"a": "1"}}`))
+		}))
+		defer server.Close()
+
+		delivery := newDelivery()
+		hook := deliveryhook.NewHTTPHook(server.URL, deliveryhook.WithSigningSecret("secret"))
+		decision, err := hook.BeforeDelivery(context.Background(), delivery)
+		require.NoError(t, err)
+		assert.Equal(t, deliveryhook.Decision{Veto: true, Reason: "blocked", Headers: map[string]string{"a": "1"}}, decision)
+
+		assert.Equal(t, deliveryhook.StagePreDelivery, got.Stage)
+		assert.Equal(t, delivery.Event.ID, got.Event.ID)
+		assert.Equal(t, delivery.Destination.ID, got.Destination.ID)
+		assert.Empty(t, got.Event.Data, "data is only sent when enabled")
+		assert.Nil(t, got.Attempt)
+	})
+
+	t.Run("empty response continues", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		decision, err := deliveryhook.NewHTTPHook(server.URL).BeforeDelivery(context.Background(), newDelivery())
+		require.NoError(t, err)
+		assert.Equal(t, deliveryhook.Decision{}, decision)
+	})
+
+	t.Run("error status fails the hook", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		_, err := deliveryhook.NewHTTPHook(server.URL).BeforeDelivery(context.Background(), newDelivery())
+		require.ErrorContains(t, err, "status 500")
+	})
+
+	t.Run("post-delivery request carries the attempt", func(t *testing.T) {
+		var got deliveryhook.HTTPRequest
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+		}))
+		defer server.Close()
+
+		hook := deliveryhook.NewHTTPHook(server.URL, deliveryhook.WithEventData(true))
+		attempt := &models.Attempt{ID: "atm_1", Status: models.AttemptStatusFailed, Code: "500"}
+		require.NoError(t, hook.AfterDelivery(context.Background(), newDelivery(), attempt))
+
+		assert.Equal(t, deliveryhook.StagePostDelivery, got.Stage)
+		assert.NotEmpty(t, got.Event.Data)
+		require.NotNil(t, got.Attempt)
+		assert.Equal(t, "atm_1", got.Attempt.ID)
+		assert.Equal(t, models.AttemptStatusFailed, got.Attempt.Status)
+		assert.Equal(t, "500", got.Attempt.Code)
+	})
+}
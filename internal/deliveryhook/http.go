@@ -0,0 +1,206 @@
+package deliveryhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+const (
+	StagePreDelivery  = "pre_delivery"
+	StagePostDelivery = "post_delivery"
+)
+
+const signatureHeader = "X-Outpost-Signature"
+
+// maxResponseBytes caps the response body read from a hook endpoint.
+const maxResponseBytes = 64 << 10
+
+// HTTPRequest is the body POSTed to a hook endpoint. Attempt is only set for
+// the post-delivery stage.
+type HTTPRequest struct {
+	Stage         string           `json:"stage"`
+	TenantID      string           `json:"tenant_id"`
+	Event         HTTPEvent        `json:"event"`
+	Destination   HTTPDestination  `json:"destination"`
+	AttemptNumber int              `json:"attempt_number"`
+	Manual        bool             `json:"manual"`
+	Attempt       *HTTPAttemptInfo `json:"attempt,omitempty"`
+}
+
+type HTTPEvent struct {
+	ID       string            `json:"id"`
+	Topic    string            `json:"topic"`
+	Time     time.Time         `json:"time"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+	Data     json.RawMessage   `json:"data,omitempty"`
+}
+
+type HTTPDestination struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+type HTTPAttemptInfo struct {
+	ID     string `json:"id"`
+	Status string `json:"status"`
+	Code   string `json:"code,omitempty"`
+}
+
+// HTTPResponse is the optional JSON body a pre-delivery endpoint responds
+// with. An empty body lets the delivery proceed unchanged.
+type HTTPResponse struct {
+	Veto    bool              `json:"veto"`
+	Reason  string            `json:"reason,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// HTTPHook calls an HTTP endpoint as a pre- or post-delivery hook. Requests
+// are signed like event lifecycle callbacks when a signing secret is set.
+// A response status of 400 or above fails the hook. The request is bound by
+// the stage's time budget.
+type HTTPHook struct {
+	url           string
+	signingSecret string
+	includeData   bool
+	client        *http.Client
+}
+
+// HTTPHookOption configures an HTTPHook.
+type HTTPHookOption func(*HTTPHook)
+
+// WithSigningSecret signs requests with HMAC-SHA256 in the
+// X-Outpost-Signature header.
+func WithSigningSecret(secret string) HTTPHookOption {
+	return func(h *HTTPHook) {
+		h.signingSecret = secret
+	}
+}
+
+// WithEventData includes the event payload in requests. Without it, hooks
+// only receive the event's ID, topic, time and metadata.
+func WithEventData(include bool) HTTPHookOption {
+	return func(h *HTTPHook) {
+		h.includeData = include
+	}
+}
+
+// WithHTTPClient sets the client used to call the endpoint.
+func WithHTTPClient(client *http.Client) HTTPHookOption {
+	return func(h *HTTPHook) {
+		h.client = client
+	}
+}
+
+// NewHTTPHook creates a hook calling url.
+func NewHTTPHook(url string, opts ...HTTPHookOption) *HTTPHook {
+	h := &HTTPHook{
+		url:    url,
+		client: &http.Client{},
+	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
+}
+
+var (
+	_ PreDeliveryHook  = (*HTTPHook)(nil)
+	_ PostDeliveryHook = (*HTTPHook)(nil)
+)
+
+func (h *HTTPHook) BeforeDelivery(ctx context.Context, delivery *Delivery) (Decision, error) {
+	body, err := h.call(ctx, h.request(StagePreDelivery, delivery, nil))
+	if err != nil {
+		return Decision{}, err
+	}
+	if len(bytes.TrimSpace(body)) == 0 {
+		return Decision{}, nil
+	}
+	var resp HTTPResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return Decision{}, fmt.Errorf("deliveryhook: invalid response: %w", err)
+	}
+	return Decision{Veto: resp.Veto, Reason: resp.Reason, Headers: resp.Headers}, nil
+}
+
+func (h *HTTPHook) AfterDelivery(ctx context.Context, delivery *Delivery, attempt *models.Attempt) error {
+	_, err := h.call(ctx, h.request(StagePostDelivery, delivery, attempt))
+	return err
+}
+
+func (h *HTTPHook) request(stage string, delivery *Delivery, attempt *models.Attempt) HTTPRequest {
+	req := HTTPRequest{
+		Stage:    stage,
+		TenantID: delivery.Event.TenantID,
+		Event: HTTPEvent{
+			ID:       delivery.Event.ID,
+			Topic:    delivery.Event.Topic,
+			Time:     delivery.Event.Time,
+			Metadata: delivery.Event.Metadata,
+		},
+		Destination: HTTPDestination{
+			ID:   delivery.Destination.ID,
+			Type: delivery.Destination.Type,
+		},
+		AttemptNumber: delivery.AttemptNumber,
+		Manual:        delivery.Manual,
+	}
+	if h.includeData {
+		req.Event.Data = json.RawMessage(delivery.Event.Data)
+	}
+	if attempt != nil {
+		req.Attempt = &HTTPAttemptInfo{
+			ID:     attempt.ID,
+			Status: attempt.Status,
+			Code:   attempt.Code,
+		}
+	}
+	return req
+}
+
+func (h *HTTPHook) call(ctx context.Context, payload HTTPRequest) ([]byte, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("deliveryhook: failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, h.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("deliveryhook: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if h.signingSecret != "" {
+		mac := hmac.New(sha256.New, []byte(h.signingSecret))
+		mac.Write(body)
+		req.Header.Set(signatureHeader, "v0="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("deliveryhook: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("deliveryhook: failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		snippet := respBody
+		if len(snippet) > 512 {
+			snippet = snippet[:512]
+		}
+		return nil, fmt.Errorf("deliveryhook: endpoint returned status %d: %s", resp.StatusCode, string(snippet))
+	}
+	return respBody, nil
+}
@@ -0,0 +1,283 @@
+// Package deliveryjournal backfills the delivery history a log store outage
+// would otherwise lose.
+//
+// Delivery workers journal every attempt in Redis before publishing it to the
+// log queue, and log workers remove attempts from the journal once they are
+// persisted. An attempt still journaled after a grace period never reached
+// the log store, whether its log message was dropped after exhausting its
+// redeliveries or was never published, so it is written to the log store by
+// Reconcile. Writes to the log store are idempotent, so an attempt persisted
+// twice is stored once.
+//
+// The journal is bucketed by the minute of each attempt, and every bucket
+// expires after the retention period, so an outage longer than it still
+// loses history.
+package deliveryjournal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/redis"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// DefaultRetention is how long an attempt is kept for backfilling when
+	// no retention is set.
+	DefaultRetention = 72 * time.Hour
+
+	bucketSize = time.Minute
+	// claimTTL keeps a bucket claimed by one log worker while it is being
+	// backfilled. A worker that dies mid-bucket releases it when it expires.
+	claimTTL = 5 * time.Minute
+	// scanCount is how many attempts are read, and written to the log store,
+	// at a time.
+	scanCount = 100
+)
+
+// LogStore is the log store attempts are backfilled to.
+type LogStore interface {
+	InsertMany(ctx context.Context, entries []*models.LogEntry) error
+}
+
+// LogPublisher publishes log entries to the log queue.
+type LogPublisher interface {
+	Publish(ctx context.Context, entry models.LogEntry) error
+}
+
+// Journal records the attempts that are yet to be persisted to the log store.
+type Journal struct {
+	redisClient  redis.Cmdable
+	deploymentID string
+	retention    time.Duration
+	clock        clock.Clock
+	backfilled   metric.Int64Counter
+}
+
+type Option func(*Journal)
+
+func WithDeploymentID(deploymentID string) Option {
+	return func(j *Journal) {
+		j.deploymentID = deploymentID
+	}
+}
+
+// WithRetention sets how long an attempt is kept for backfilling.
+func WithRetention(retention time.Duration) Option {
+	return func(j *Journal) {
+		if retention > 0 {
+			j.retention = retention
+		}
+	}
+}
+
+func WithClock(c clock.Clock) Option {
+	return func(j *Journal) {
+		j.clock = c
+	}
+}
+
+// New returns a journal stored in Redis.
+func New(redisClient redis.Cmdable, opts ...Option) *Journal {
+	j := &Journal{
+		redisClient: redisClient,
+		retention:   DefaultRetention,
+		clock:       clock.New(),
+	}
+	for _, opt := range opts {
+		opt(j)
+	}
+	// A failing meter leaves backfills unrecorded rather than failing them.
+	j.backfilled, _ = otel.Meter("outpost").Int64Counter("outpost.delivery_journal.backfilled",
+		metric.WithDescription("Number of attempts written to the log store from the delivery journal"),
+	)
+	return j
+}
+
+// Record journals the attempt of entry. The destination isn't journaled, as
+// the log store doesn't need it and it holds the destination's credentials.
+func (j *Journal) Record(ctx context.Context, entry models.LogEntry) error {
+	if entry.Event == nil || entry.Attempt == nil {
+		return nil
+	}
+	data, err := json.Marshal(models.LogEntry{Event: entry.Event, Attempt: entry.Attempt})
+	if err != nil {
+		return fmt.Errorf("failed to encode journaled attempt: %w", err)
+	}
+	bucket := bucketOf(entry.Attempt.Time)
+	pipe := j.redisClient.Pipeline()
+	pipe.HSet(ctx, j.bucketKey(bucket), entry.Attempt.ID, data)
+	pipe.Expire(ctx, j.bucketKey(bucket), j.retention)
+	pipe.ZAdd(ctx, j.indexKey(), redis.Z{Score: float64(bucket), Member: bucket})
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to journal attempt: %w", err)
+	}
+	return nil
+}
+
+// Persisted removes attempts persisted to the log store from the journal.
+func (j *Journal) Persisted(ctx context.Context, entries []*models.LogEntry) error {
+	byBucket := make(map[int64][]string)
+	for _, entry := range entries {
+		if entry.Attempt == nil {
+			continue
+		}
+		bucket := bucketOf(entry.Attempt.Time)
+		byBucket[bucket] = append(byBucket[bucket], entry.Attempt.ID)
+	}
+	if len(byBucket) == 0 {
+		return nil
+	}
+	pipe := j.redisClient.Pipeline()
+	for bucket, attemptIDs := range byBucket {
+		pipe.HDel(ctx, j.bucketKey(bucket), attemptIDs...)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to remove persisted attempts from the journal: %w", err)
+	}
+	return nil
+}
+
+// Reconcile writes the attempts journaled more than grace ago to the log
+// store, and returns how many were backfilled. Each bucket is claimed by one
+// caller at a time, so log workers backfill different buckets concurrently.
+func (j *Journal) Reconcile(ctx context.Context, logStore LogStore, grace time.Duration) (int, error) {
+	now := j.clock.Now()
+	buckets, err := j.redisClient.ZRangeByScore(ctx, j.indexKey(), &redis.ZRangeBy{
+		Min: strconv.FormatInt(bucketOf(now.Add(-j.retention)), 10),
+		Max: "(" + strconv.FormatInt(bucketOf(now.Add(-grace)), 10),
+	}).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list journal buckets: %w", err)
+	}
+	if err := j.redisClient.ZRemRangeByScore(ctx, j.indexKey(), "-inf", "("+strconv.FormatInt(bucketOf(now.Add(-j.retention)), 10)).Err(); err != nil {
+		return 0, fmt.Errorf("failed to trim expired journal buckets: %w", err)
+	}
+
+	backfilled := 0
+	for _, member := range buckets {
+		bucket, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		n, err := j.reconcileBucket(ctx, logStore, bucket)
+		backfilled += n
+		if err != nil {
+			return backfilled, err
+		}
+	}
+	return backfilled, nil
+}
+
+func (j *Journal) reconcileBucket(ctx context.Context, logStore LogStore, bucket int64) (int, error) {
+	claimed, err := j.redisClient.SetNX(ctx, j.claimKey(bucket), j.clock.Now().UTC().Format(time.RFC3339), claimTTL).Result()
+	if err != nil {
+		return 0, fmt.Errorf("failed to claim journal bucket: %w", err)
+	}
+	if !claimed {
+		return 0, nil
+	}
+	defer j.redisClient.Del(context.WithoutCancel(ctx), j.claimKey(bucket))
+
+	key := j.bucketKey(bucket)
+	backfilled := 0
+	var cursor uint64
+	for {
+		fields, nextCursor, err := j.redisClient.HScan(ctx, key, cursor, "", scanCount).Result()
+		if err != nil {
+			return backfilled, fmt.Errorf("failed to read journal bucket: %w", err)
+		}
+		entries := make([]*models.LogEntry, 0, len(fields)/2)
+		for i := 0; i+1 < len(fields); i += 2 {
+			entry := &models.LogEntry{}
+			if err := json.Unmarshal([]byte(fields[i+1]), entry); err != nil || entry.Event == nil || entry.Attempt == nil {
+				// An unreadable attempt can never be backfilled.
+				j.redisClient.HDel(ctx, key, fields[i])
+				continue
+			}
+			// The event may have been lost with its first attempt.
+			entry.WriteEvent = true
+			entries = append(entries, entry)
+		}
+		if len(entries) > 0 {
+			if err := logStore.InsertMany(ctx, entries); err != nil {
+				return backfilled, fmt.Errorf("failed to backfill attempts: %w", err)
+			}
+			if err := j.Persisted(ctx, entries); err != nil {
+				return backfilled, err
+			}
+			backfilled += len(entries)
+			if j.backfilled != nil {
+				j.backfilled.Add(ctx, int64(len(entries)))
+			}
+		}
+		cursor = nextCursor
+		if cursor == 0 {
+			break
+		}
+	}
+
+	// Attempts journaled since the scan started keep the bucket indexed.
+	remaining, err := j.redisClient.HLen(ctx, key).Result()
+	if err != nil {
+		return backfilled, fmt.Errorf("failed to read journal bucket: %w", err)
+	}
+	if remaining == 0 {
+		j.redisClient.ZRem(ctx, j.indexKey(), strconv.FormatInt(bucket, 10))
+	}
+	return backfilled, nil
+}
+
+// Publisher journals each entry before publishing it, so an entry is
+// backfilled even when it can't be published.
+type Publisher struct {
+	next    LogPublisher
+	journal *Journal
+	onError func(ctx context.Context, entry models.LogEntry, err error)
+}
+
+var _ LogPublisher = (*Publisher)(nil)
+
+// NewPublisher returns a publisher that journals entries before publishing
+// them with next. A failure to journal doesn't fail the publish; it is
+// reported to onError, which may be nil.
+func NewPublisher(next LogPublisher, journal *Journal, onError func(ctx context.Context, entry models.LogEntry, err error)) *Publisher {
+	return &Publisher{next: next, journal: journal, onError: onError}
+}
+
+func (p *Publisher) Publish(ctx context.Context, entry models.LogEntry) error {
+	if err := p.journal.Record(ctx, entry); err != nil && p.onError != nil {
+		p.onError(ctx, entry, err)
+	}
+	return p.next.Publish(ctx, entry)
+}
+
+func bucketOf(t time.Time) int64 {
+	return t.Unix() / int64(bucketSize/time.Second)
+}
+
+func (j *Journal) prefix() string {
+	if j.deploymentID == "" {
+		return "deliveryjournal:"
+	}
+	return j.deploymentID + ":deliveryjournal:"
+}
+
+func (j *Journal) bucketKey(bucket int64) string {
+	return j.prefix() + "bucket:" + strconv.FormatInt(bucket, 10)
+}
+
+func (j *Journal) indexKey() string {
+	return j.prefix() + "buckets"
+}
+
+func (j *Journal) claimKey(bucket int64) string {
+	return j.prefix() + "claim:" + strconv.FormatInt(bucket, 10)
+}
@@ -0,0 +1,157 @@
+package deliveryjournal_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/deliveryjournal"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeLogStore struct {
+	mu      sync.Mutex
+	err     error
+	entries []*models.LogEntry
+}
+
+func (s *fakeLogStore) InsertMany(_ context.Context, entries []*models.LogEntry) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.err != nil {
+		return s.err
+	}
+	s.entries = append(s.entries, entries...)
+	return nil
+}
+
+func (s *fakeLogStore) attemptIDs() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.entries))
+	for _, entry := range s.entries {
+		ids = append(ids, entry.Attempt.ID)
+	}
+	return ids
+}
+
+type fakeLogPublisher struct {
+	err       error
+	published []models.LogEntry
+}
+
+func (p *fakeLogPublisher) Publish(_ context.Context, entry models.LogEntry) error {
+	p.published = append(p.published, entry)
+	return p.err
+}
+
+func logEntry(attemptID string, at time.Time) models.LogEntry {
+	return models.LogEntry{
+		Event:       &models.Event{ID: "evt_" + attemptID, TenantID: "t1", Topic: "order.created", Time: at},
+		Attempt:     &models.Attempt{ID: attemptID, TenantID: "t1", EventID: "evt_" + attemptID, Time: at},
+		Destination: &models.Destination{ID: "d1", Credentials: map[string]string{"secret": "s3cr3t"}},
+	}
+}
+
+func TestJournal(t *testing.T) {
+	t.Parallel()
+
+	const grace = 10 * time.Minute
+
+	setup := func(t *testing.T) (*deliveryjournal.Journal, *clock.Fake) {
+		t.Helper()
+		clk := clock.NewFake(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC))
+		return deliveryjournal.New(testutil.CreateTestRedisClient(t),
+			deliveryjournal.WithDeploymentID("dp1"),
+			deliveryjournal.WithClock(clk),
+		), clk
+	}
+
+	t.Run("backfills attempts that were never persisted", func(t *testing.T) {
+		t.Parallel()
+		journal, clk := setup(t)
+		ctx := t.Context()
+
+		persisted, lost := logEntry("att_1", clk.Now()), logEntry("att_2", clk.Now())
+		require.NoError(t, journal.Record(ctx, persisted))
+		require.NoError(t, journal.Record(ctx, lost))
+		require.NoError(t, journal.Persisted(ctx, []*models.LogEntry{&persisted}))
+
+		logStore := &fakeLogStore{}
+		backfilled, err := journal.Reconcile(ctx, logStore, grace)
+		require.NoError(t, err)
+		assert.Zero(t, backfilled, "attempts within the grace period may still be persisted")
+
+		clk.Advance(grace + time.Minute)
+		backfilled, err = journal.Reconcile(ctx, logStore, grace)
+		require.NoError(t, err)
+		assert.Equal(t, 1, backfilled)
+		assert.Equal(t, []string{"att_2"}, logStore.attemptIDs())
+		assert.True(t, logStore.entries[0].WriteEvent, "the event may have been lost with the attempt")
+		assert.Nil(t, logStore.entries[0].Destination, "destination credentials aren't journaled")
+
+		backfilled, err = journal.Reconcile(ctx, logStore, grace)
+		require.NoError(t, err)
+		assert.Zero(t, backfilled, "backfilled attempts leave the journal")
+	})
+
+	t.Run("keeps attempts while the log store is down", func(t *testing.T) {
+		t.Parallel()
+		journal, clk := setup(t)
+		ctx := t.Context()
+
+		require.NoError(t, journal.Record(ctx, logEntry("att_1", clk.Now())))
+		clk.Advance(grace + time.Minute)
+
+		logStore := &fakeLogStore{err: errors.New("log store unavailable")}
+		_, err := journal.Reconcile(ctx, logStore, grace)
+		require.Error(t, err)
+
+		logStore.err = nil
+		backfilled, err := journal.Reconcile(ctx, logStore, grace)
+		require.NoError(t, err)
+		assert.Equal(t, 1, backfilled)
+	})
+
+	t.Run("forgets attempts past the retention", func(t *testing.T) {
+		t.Parallel()
+		journal, clk := setup(t)
+		ctx := t.Context()
+
+		require.NoError(t, journal.Record(ctx, logEntry("att_1", clk.Now())))
+		clk.Advance(deliveryjournal.DefaultRetention + time.Minute)
+
+		logStore := &fakeLogStore{}
+		backfilled, err := journal.Reconcile(ctx, logStore, grace)
+		require.NoError(t, err)
+		assert.Zero(t, backfilled)
+	})
+}
+
+func TestPublisher(t *testing.T) {
+	t.Parallel()
+
+	t.Run("journals entries it fails to publish", func(t *testing.T) {
+		t.Parallel()
+		clk := clock.NewFake(time.Now())
+		journal := deliveryjournal.New(testutil.CreateTestRedisClient(t), deliveryjournal.WithClock(clk))
+		next := &fakeLogPublisher{err: errors.New("queue unavailable")}
+		publisher := deliveryjournal.NewPublisher(next, journal, nil)
+
+		err := publisher.Publish(t.Context(), logEntry("att_1", clk.Now()))
+		require.Error(t, err)
+		assert.Len(t, next.published, 1)
+
+		clk.Advance(time.Hour)
+		logStore := &fakeLogStore{}
+		backfilled, err := journal.Reconcile(t.Context(), logStore, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 1, backfilled)
+	})
+}
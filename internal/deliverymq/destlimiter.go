@@ -0,0 +1,107 @@
+package deliverymq
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// destinationLimiterSweepInterval is how often idle destinations are dropped
+// from the limiter.
+const destinationLimiterSweepInterval = time.Minute
+
+// DestinationLimiter enforces a destination's MaxConcurrency and
+// MaxDeliveriesPerSecond, so a slow or rate-limited endpoint only occupies
+// its share of the delivery workers.
+type DestinationLimiter interface {
+	// Acquire reserves a delivery slot for the destination. It returns a
+	// release func and true when the destination is under its caps, or false
+	// and how long to wait before trying again.
+	Acquire(destination *models.Destination) (release func(), retryAfter time.Duration, ok bool)
+}
+
+type destinationLimiter struct {
+	clock clock.Clock
+
+	mu        sync.Mutex
+	states    map[string]*destinationState
+	lastSweep time.Time
+}
+
+type destinationState struct {
+	inFlight int
+	// tokens and refilledAt form a token bucket holding up to one second of
+	// deliveries.
+	tokens     float64
+	refilledAt time.Time
+}
+
+// NewDestinationLimiter returns an in-process DestinationLimiter. Caps apply
+// to each delivery replica on its own.
+func NewDestinationLimiter(c clock.Clock) DestinationLimiter {
+	if c == nil {
+		c = clock.New()
+	}
+	return &destinationLimiter{
+		clock:  c,
+		states: make(map[string]*destinationState),
+	}
+}
+
+func (l *destinationLimiter) Acquire(destination *models.Destination) (func(), time.Duration, bool) {
+	if destination.MaxConcurrency <= 0 && destination.MaxDeliveriesPerSecond <= 0 {
+		return func() {}, 0, true
+	}
+
+	now := l.clock.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sweep(now)
+
+	state, ok := l.states[destination.ID]
+	if !ok {
+		state = &destinationState{tokens: float64(destination.MaxDeliveriesPerSecond), refilledAt: now}
+		l.states[destination.ID] = state
+	}
+
+	if destination.MaxConcurrency > 0 && state.inFlight >= destination.MaxConcurrency {
+		return nil, time.Second, false
+	}
+	if rate := float64(destination.MaxDeliveriesPerSecond); rate > 0 {
+		state.tokens = math.Min(rate, state.tokens+now.Sub(state.refilledAt).Seconds()*rate)
+		state.refilledAt = now
+		if state.tokens < 1 {
+			wait := time.Duration((1 - state.tokens) / rate * float64(time.Second))
+			return nil, wait, false
+		}
+		state.tokens--
+	}
+
+	state.inFlight++
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			state.inFlight--
+		})
+	}, 0, true
+}
+
+// sweep drops destinations with nothing in flight whose bucket has refilled,
+// at most once per destinationLimiterSweepInterval. Must be called with mu
+// held.
+func (l *destinationLimiter) sweep(now time.Time) {
+	if now.Sub(l.lastSweep) < destinationLimiterSweepInterval {
+		return
+	}
+	l.lastSweep = now
+	for id, state := range l.states {
+		if state.inFlight == 0 && now.Sub(state.refilledAt) >= time.Second {
+			delete(l.states, id)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package deliverymq_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/backoff"
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/deliverymq"
+	"github.com/hookdeck/outpost/internal/idempotence"
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDestinationLimiter_Uncapped(t *testing.T) {
+	limiter := deliverymq.NewDestinationLimiter(clock.NewFake(time.Now()))
+	destination := testutil.DestinationFactory.Any()
+
+	for range 100 {
+		_, _, ok := limiter.Acquire(&destination)
+		require.True(t, ok)
+	}
+}
+
+func TestDestinationLimiter_MaxConcurrency(t *testing.T) {
+	limiter := deliverymq.NewDestinationLimiter(clock.NewFake(time.Now()))
+	destination := testutil.DestinationFactory.Any()
+	destination.MaxConcurrency = 1
+	other := testutil.DestinationFactory.Any()
+	other.MaxConcurrency = 1
+
+	release, _, ok := limiter.Acquire(&destination)
+	require.True(t, ok)
+
+	_, retryAfter, ok := limiter.Acquire(&destination)
+	assert.False(t, ok, "second delivery to the destination should be rejected")
+	assert.Equal(t, time.Second, retryAfter)
+
+	releaseOther, _, ok := limiter.Acquire(&other)
+	assert.True(t, ok, "other destinations should not be affected")
+	releaseOther()
+
+	release()
+	release() // release is idempotent
+	_, _, ok = limiter.Acquire(&destination)
+	assert.True(t, ok, "slot should be available after release")
+}
+
+func TestDestinationLimiter_MaxDeliveriesPerSecond(t *testing.T) {
+	clk := clock.NewFake(time.Now())
+	limiter := deliverymq.NewDestinationLimiter(clk)
+	destination := testutil.DestinationFactory.Any()
+	destination.MaxDeliveriesPerSecond = 2
+
+	for range 2 {
+		release, _, ok := limiter.Acquire(&destination)
+		require.True(t, ok)
+		release()
+	}
+	_, retryAfter, ok := limiter.Acquire(&destination)
+	assert.False(t, ok, "third delivery within the second should be rejected")
+	assert.Equal(t, 500*time.Millisecond, retryAfter)
+
+	clk.Advance(500 * time.Millisecond)
+	_, _, ok = limiter.Acquire(&destination)
+	assert.True(t, ok, "a delivery should be allowed once a token refills")
+}
+
+func TestMessageHandler_DestinationLimiterDefersDelivery(t *testing.T) {
+	// Test scenario:
+	// - The destination is at its max_concurrency
+	// - A first attempt arrives for it
+	// - The whole task is rescheduled instead of attempted, and the message is acked
+
+	tenant := models.Tenant{ID: idgen.String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	destination.MaxConcurrency = 1
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID(tenant.ID),
+		testutil.EventFactory.WithDestinationID(destination.ID),
+	)
+
+	limiter := deliverymq.NewDestinationLimiter(clock.NewFake(time.Now()))
+	_, _, ok := limiter.Acquire(&destination)
+	require.True(t, ok)
+
+	destGetter := &mockDestinationGetter{dest: &destination}
+	retryScheduler := newMockRetryScheduler()
+	publisher := newMockPublisher(nil)
+	logPublisher := newMockLogPublisher(nil)
+
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		logPublisher,
+		destGetter,
+		publisher,
+		testutil.NewMockEventTracer(nil),
+		retryScheduler,
+		&backoff.ConstantBackoff{Interval: 1 * time.Second},
+		10,
+		idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+		deliverymq.WithDestinationLimiter(limiter),
+	)
+
+	task := models.NewDeliveryTask(event, destination.ID)
+	mockMsg, msg := newDeliveryMockMessage(task)
+
+	err := handler.Handle(context.Background(), msg)
+	require.NoError(t, err)
+
+	assert.True(t, mockMsg.acked, "deferred delivery should be acked")
+	assert.False(t, mockMsg.nacked)
+	assert.Equal(t, 0, publisher.Current(), "deferred delivery should not be attempted")
+	assert.Empty(t, logPublisher.entries, "deferred delivery should not be logged as an attempt")
+	require.Len(t, retryScheduler.schedules, 1)
+	assert.Equal(t, models.RetryID(event.ID, destination.ID), retryScheduler.taskIDs[0])
+
+	var retryTask deliverymq.RetryTask
+	require.NoError(t, retryTask.FromString(retryScheduler.schedules[0]))
+	require.NotNil(t, retryTask.Deferred, "the whole task should be scheduled")
+	assert.Equal(t, task.Attempt, retryTask.Deferred.Attempt)
+	assert.Equal(t, event.ID, retryTask.Deferred.Event.ID)
+	assert.Equal(t, time.Second, retryScheduler.entries[retryScheduler.taskIDs[0]].delay)
+}
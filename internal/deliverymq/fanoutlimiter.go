@@ -0,0 +1,99 @@
+package deliverymq
+
+import (
+	"sync"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// FanoutLimiter bounds how many deliveries of one event, or of one topic's
+// events, may be in flight at once. Publishing to a topic with thousands of
+// destinations fans out into as many deliveries; without a cap they occupy
+// every delivery slot until they drain and starve other topics.
+type FanoutLimiter interface {
+	// Acquire reserves a delivery slot for the event. It returns a release
+	// func and true when a slot is available, or false when the event's or
+	// its topic's cap is exhausted.
+	Acquire(event *models.Event) (release func(), ok bool)
+}
+
+// FanoutLimiterConfig configures the in-process fan-out limiter. A zero value
+// for any cap means that dimension is unlimited.
+type FanoutLimiterConfig struct {
+	// MaxConcurrencyPerEvent caps concurrent deliveries of a single event.
+	MaxConcurrencyPerEvent int
+	// Topics overrides the caps of the events of specific topics.
+	Topics map[string]TopicFanoutLimit
+}
+
+// TopicFanoutLimit caps the concurrent deliveries of a topic's events.
+type TopicFanoutLimit struct {
+	// MaxConcurrencyPerEvent caps concurrent deliveries of a single event of
+	// the topic, in place of FanoutLimiterConfig.MaxConcurrencyPerEvent.
+	MaxConcurrencyPerEvent int
+	// MaxConcurrency caps concurrent deliveries of all the topic's events
+	// together.
+	MaxConcurrency int
+}
+
+type fanoutLimiter struct {
+	cfg FanoutLimiterConfig
+
+	mu       sync.Mutex
+	perEvent map[string]int
+	perTopic map[string]int
+}
+
+// NewFanoutLimiter returns an in-process FanoutLimiter. It returns nil when no
+// cap is set so callers can skip limiting entirely.
+func NewFanoutLimiter(cfg FanoutLimiterConfig) FanoutLimiter {
+	limited := cfg.MaxConcurrencyPerEvent > 0
+	for _, limit := range cfg.Topics {
+		limited = limited || limit.MaxConcurrencyPerEvent > 0 || limit.MaxConcurrency > 0
+	}
+	if !limited {
+		return nil
+	}
+	return &fanoutLimiter{
+		cfg:      cfg,
+		perEvent: make(map[string]int),
+		perTopic: make(map[string]int),
+	}
+}
+
+func (l *fanoutLimiter) Acquire(event *models.Event) (func(), bool) {
+	maxPerEvent := l.cfg.MaxConcurrencyPerEvent
+	topicLimit, hasTopicLimit := l.cfg.Topics[event.Topic]
+	if hasTopicLimit {
+		maxPerEvent = topicLimit.MaxConcurrencyPerEvent
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if maxPerEvent > 0 && l.perEvent[event.ID] >= maxPerEvent {
+		return nil, false
+	}
+	if topicLimit.MaxConcurrency > 0 && l.perTopic[event.Topic] >= topicLimit.MaxConcurrency {
+		return nil, false
+	}
+
+	l.perEvent[event.ID]++
+	l.perTopic[event.Topic]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.perEvent[event.ID]--
+			if l.perEvent[event.ID] <= 0 {
+				delete(l.perEvent, event.ID)
+			}
+			l.perTopic[event.Topic]--
+			if l.perTopic[event.Topic] <= 0 {
+				delete(l.perTopic, event.Topic)
+			}
+		})
+	}, true
+}
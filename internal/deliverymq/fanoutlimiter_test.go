@@ -0,0 +1,122 @@
+package deliverymq_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/backoff"
+	"github.com/hookdeck/outpost/internal/deliverymq"
+	"github.com/hookdeck/outpost/internal/idempotence"
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFanoutLimiter_Disabled(t *testing.T) {
+	assert.Nil(t, deliverymq.NewFanoutLimiter(deliverymq.FanoutLimiterConfig{}))
+	assert.Nil(t, deliverymq.NewFanoutLimiter(deliverymq.FanoutLimiterConfig{
+		Topics: map[string]deliverymq.TopicFanoutLimit{"user.created": {}},
+	}))
+}
+
+func TestFanoutLimiter_PerEventCap(t *testing.T) {
+	limiter := deliverymq.NewFanoutLimiter(deliverymq.FanoutLimiterConfig{MaxConcurrencyPerEvent: 1})
+	event := testutil.EventFactory.Any(testutil.EventFactory.WithTopic("user.created"))
+	other := testutil.EventFactory.Any(testutil.EventFactory.WithTopic("user.created"))
+
+	release, ok := limiter.Acquire(&event)
+	require.True(t, ok)
+
+	_, ok = limiter.Acquire(&event)
+	assert.False(t, ok, "second delivery of the same event should be rejected")
+
+	releaseOther, ok := limiter.Acquire(&other)
+	assert.True(t, ok, "other events should not be affected")
+	releaseOther()
+
+	release()
+	release() // release is idempotent
+	_, ok = limiter.Acquire(&event)
+	assert.True(t, ok, "slot should be available after release")
+}
+
+func TestFanoutLimiter_TopicCaps(t *testing.T) {
+	limiter := deliverymq.NewFanoutLimiter(deliverymq.FanoutLimiterConfig{
+		MaxConcurrencyPerEvent: 1,
+		Topics: map[string]deliverymq.TopicFanoutLimit{
+			"broadcast": {MaxConcurrencyPerEvent: 2, MaxConcurrency: 3},
+		},
+	})
+	first := testutil.EventFactory.Any(testutil.EventFactory.WithTopic("broadcast"))
+	second := testutil.EventFactory.Any(testutil.EventFactory.WithTopic("broadcast"))
+
+	_, ok := limiter.Acquire(&first)
+	require.True(t, ok)
+	_, ok = limiter.Acquire(&first)
+	require.True(t, ok, "the topic's per-event cap should override the default")
+	_, ok = limiter.Acquire(&first)
+	assert.False(t, ok, "the topic's per-event cap should be enforced")
+
+	release, ok := limiter.Acquire(&second)
+	require.True(t, ok)
+	_, ok = limiter.Acquire(&second)
+	assert.False(t, ok, "the topic's cap should be exhausted")
+
+	release()
+	_, ok = limiter.Acquire(&second)
+	assert.True(t, ok)
+}
+
+func TestMessageHandler_FanoutLimiterDefersDelivery(t *testing.T) {
+	// Test scenario:
+	// - The event's per-event fan-out cap is already saturated
+	// - Another delivery of the event arrives
+	// - The delivery is rescheduled instead of attempted, and the message is acked
+
+	tenant := models.Tenant{ID: idgen.String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID(tenant.ID),
+		testutil.EventFactory.WithDestinationID(destination.ID),
+	)
+
+	limiter := deliverymq.NewFanoutLimiter(deliverymq.FanoutLimiterConfig{MaxConcurrencyPerEvent: 1})
+	_, ok := limiter.Acquire(&event)
+	require.True(t, ok)
+
+	destGetter := &mockDestinationGetter{dest: &destination}
+	retryScheduler := newMockRetryScheduler()
+	publisher := newMockPublisher(nil)
+	logPublisher := newMockLogPublisher(nil)
+
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		logPublisher,
+		destGetter,
+		publisher,
+		testutil.NewMockEventTracer(nil),
+		retryScheduler,
+		&backoff.ConstantBackoff{Interval: 1 * time.Second},
+		10,
+		idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+		deliverymq.WithFanoutLimiter(limiter),
+	)
+
+	task := models.NewDeliveryTask(event, destination.ID)
+	mockMsg, msg := newDeliveryMockMessage(task)
+
+	err := handler.Handle(context.Background(), msg)
+	require.NoError(t, err)
+
+	assert.True(t, mockMsg.acked, "deferred delivery should be acked")
+	assert.False(t, mockMsg.nacked)
+	assert.Equal(t, 0, publisher.Current(), "deferred delivery should not be attempted")
+	assert.Empty(t, logPublisher.entries, "deferred delivery should not be logged as an attempt")
+	require.Len(t, retryScheduler.taskIDs, 1)
+	assert.Equal(t, models.RetryID(event.ID, destination.ID), retryScheduler.taskIDs[0])
+}
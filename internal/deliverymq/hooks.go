@@ -0,0 +1,85 @@
+package deliverymq
+
+import (
+	"context"
+	"maps"
+
+	"github.com/hookdeck/outpost/internal/deliveryhook"
+	"github.com/hookdeck/outpost/internal/models"
+	"go.uber.org/zap"
+)
+
+// WithDeliveryHooks runs the hooks around every delivery attempt made to a
+// destination. Sandboxed deliveries don't run hooks.
+func WithDeliveryHooks(hooks deliveryhook.Hooks) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.hooks = hooks
+	}
+}
+
+func newHookDelivery(task *models.DeliveryTask, destination *models.Destination) *deliveryhook.Delivery {
+	return &deliveryhook.Delivery{
+		Event:         &task.Event,
+		Destination:   destination,
+		AttemptNumber: task.Attempt,
+		Manual:        task.Manual,
+	}
+}
+
+// runPreDeliveryHooks returns the event to publish, carrying the headers the
+// hooks added as metadata, and whether the hooks vetoed the delivery. The
+// task's event is left untouched, so logged events don't include hook
+// headers.
+func (h *messageHandler) runPreDeliveryHooks(ctx context.Context, task *models.DeliveryTask, destination *models.Destination) (*models.Event, bool, error) {
+	if len(h.hooks.Pre) == 0 {
+		return &task.Event, false, nil
+	}
+
+	logger := h.logger.Ctx(ctx)
+	fields := []zap.Field{
+		zap.String("event_id", task.Event.ID),
+		zap.String("tenant_id", task.Event.TenantID),
+		zap.String("destination_id", destination.ID),
+		zap.String("destination_type", destination.Type),
+		zap.Int("attempt_number", task.Attempt),
+	}
+
+	decision, err := h.hooks.BeforeDelivery(ctx, newHookDelivery(task, destination))
+	if err != nil {
+		if h.hooks.FailClosed {
+			logger.Error("pre-delivery hook failed", append(fields, zap.Error(err))...)
+			return nil, false, err
+		}
+		logger.Warn("pre-delivery hook failed, delivering anyway", append(fields, zap.Error(err))...)
+	}
+	if decision.Veto {
+		logger.Info("delivery.vetoed", append(fields, zap.String("reason", decision.Reason))...)
+		return nil, true, nil
+	}
+	if len(decision.Headers) == 0 {
+		return &task.Event, false, nil
+	}
+
+	event := task.Event
+	event.Metadata = make(map[string]string, len(task.Event.Metadata)+len(decision.Headers))
+	maps.Copy(event.Metadata, task.Event.Metadata)
+	maps.Copy(event.Metadata, decision.Headers)
+	return &event, false, nil
+}
+
+// runPostDeliveryHooks runs the post-delivery hooks for a completed attempt.
+// Their failures are logged and never affect the delivery.
+func (h *messageHandler) runPostDeliveryHooks(ctx context.Context, task *models.DeliveryTask, destination *models.Destination, attempt *models.Attempt) {
+	if len(h.hooks.Post) == 0 || attempt.Code == models.AttemptCodeSandbox {
+		return
+	}
+	if err := h.hooks.AfterDelivery(ctx, newHookDelivery(task, destination), attempt); err != nil {
+		h.logger.Ctx(ctx).Warn("post-delivery hook failed",
+			zap.Error(err),
+			zap.String("attempt_id", attempt.ID),
+			zap.String("event_id", task.Event.ID),
+			zap.String("tenant_id", task.Event.TenantID),
+			zap.String("destination_id", destination.ID),
+			zap.String("destination_type", destination.Type))
+	}
+}
@@ -0,0 +1,143 @@
+package deliverymq_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/backoff"
+	"github.com/hookdeck/outpost/internal/consumer"
+	"github.com/hookdeck/outpost/internal/deliveryhook"
+	"github.com/hookdeck/outpost/internal/deliverymq"
+	"github.com/hookdeck/outpost/internal/idempotence"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingPublisher records the events it is asked to publish.
+type recordingPublisher struct {
+	*mockPublisher
+	mu     sync.Mutex
+	events []models.Event
+}
+
+func (p *recordingPublisher) PublishEvent(ctx context.Context, destination *models.Destination, event *models.Event) (*models.Attempt, error) {
+	p.mu.Lock()
+	p.events = append(p.events, *event)
+	p.mu.Unlock()
+	return p.mockPublisher.PublishEvent(ctx, destination, event)
+}
+
+func TestMessageHandler_DeliveryHooks(t *testing.T) {
+	setup := func(t *testing.T, hooks deliveryhook.Hooks) (consumer.MessageHandler, *recordingPublisher, *mockLogPublisher, models.Event) {
+		t.Helper()
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithTenantID("tenant"),
+		)
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID("tenant"),
+			testutil.EventFactory.WithDestinationID(destination.ID),
+			testutil.EventFactory.WithMetadata(map[string]string{"source": "producer"}),
+		)
+		publisher := &recordingPublisher{mockPublisher: newMockPublisher(nil)}
+		logPublisher := newMockLogPublisher(nil)
+		handler := deliverymq.NewMessageHandler(
+			testutil.CreateTestLogger(t),
+			logPublisher,
+			&mockDestinationGetter{dest: &destination},
+			publisher,
+			testutil.NewMockEventTracer(nil),
+			newMockRetryScheduler(),
+			&backoff.ConstantBackoff{Interval: 1 * time.Second},
+			10,
+			idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+			deliverymq.WithDeliveryHooks(hooks),
+		)
+		return handler, publisher, logPublisher, event
+	}
+
+	t.Run("adds hook headers to the delivered event only", func(t *testing.T) {
+		handler, publisher, logPublisher, event := setup(t, deliveryhook.Hooks{
+			Pre: []deliveryhook.PreDeliveryHook{deliveryhook.PreDeliveryFunc(func(ctx context.Context, d *deliveryhook.Delivery) (deliveryhook.Decision, error) {
+				return deliveryhook.Decision{Headers: map[string]string{"region": "eu"}}, nil
+			})},
+		})
+
+		mockMsg, msg := newDeliveryMockMessage(models.NewDeliveryTask(event, event.DestinationID))
+		require.NoError(t, handler.Handle(context.Background(), msg))
+
+		assert.True(t, mockMsg.acked)
+		require.Len(t, publisher.events, 1)
+		assert.Equal(t, map[string]string{"source": "producer", "region": "eu"}, publisher.events[0].Metadata)
+		require.Len(t, logPublisher.entries, 1)
+		assert.Equal(t, map[string]string{"source": "producer"}, logPublisher.entries[0].Event.Metadata)
+	})
+
+	t.Run("veto acks without delivering", func(t *testing.T) {
+		handler, publisher, logPublisher, event := setup(t, deliveryhook.Hooks{
+			Pre: []deliveryhook.PreDeliveryHook{deliveryhook.PreDeliveryFunc(func(ctx context.Context, d *deliveryhook.Delivery) (deliveryhook.Decision, error) {
+				return deliveryhook.Decision{Veto: true, Reason: "blocked"}, nil
+			})},
+		})
+
+		mockMsg, msg := newDeliveryMockMessage(models.NewDeliveryTask(event, event.DestinationID))
+		require.NoError(t, handler.Handle(context.Background(), msg))
+
+		assert.True(t, mockMsg.acked)
+		assert.Empty(t, publisher.events)
+		assert.Empty(t, logPublisher.entries)
+	})
+
+	t.Run("delivers when a hook fails open", func(t *testing.T) {
+		handler, publisher, _, event := setup(t, deliveryhook.Hooks{
+			Pre: []deliveryhook.PreDeliveryHook{deliveryhook.PreDeliveryFunc(func(ctx context.Context, d *deliveryhook.Delivery) (deliveryhook.Decision, error) {
+				return deliveryhook.Decision{}, errors.New("hook down")
+			})},
+		})
+
+		mockMsg, msg := newDeliveryMockMessage(models.NewDeliveryTask(event, event.DestinationID))
+		require.NoError(t, handler.Handle(context.Background(), msg))
+
+		assert.True(t, mockMsg.acked)
+		assert.Len(t, publisher.events, 1)
+	})
+
+	t.Run("nacks when a hook fails closed", func(t *testing.T) {
+		handler, publisher, logPublisher, event := setup(t, deliveryhook.Hooks{
+			Pre: []deliveryhook.PreDeliveryHook{deliveryhook.PreDeliveryFunc(func(ctx context.Context, d *deliveryhook.Delivery) (deliveryhook.Decision, error) {
+				return deliveryhook.Decision{}, errors.New("hook down")
+			})},
+			FailClosed: true,
+		})
+
+		mockMsg, msg := newDeliveryMockMessage(models.NewDeliveryTask(event, event.DestinationID))
+		require.Error(t, handler.Handle(context.Background(), msg))
+
+		assert.True(t, mockMsg.nacked)
+		assert.Empty(t, publisher.events)
+		assert.Empty(t, logPublisher.entries)
+	})
+
+	t.Run("post-delivery hook sees the attempt", func(t *testing.T) {
+		var got *models.Attempt
+		handler, _, logPublisher, event := setup(t, deliveryhook.Hooks{
+			Post: []deliveryhook.PostDeliveryHook{deliveryhook.PostDeliveryFunc(func(ctx context.Context, d *deliveryhook.Delivery, attempt *models.Attempt) error {
+				got = attempt
+				return errors.New("metrics down")
+			})},
+		})
+
+		mockMsg, msg := newDeliveryMockMessage(models.NewDeliveryTask(event, event.DestinationID))
+		require.NoError(t, handler.Handle(context.Background(), msg))
+
+		assert.True(t, mockMsg.acked, "post-delivery hook failures don't affect the delivery")
+		require.NotNil(t, got)
+		require.Len(t, logPublisher.entries, 1)
+		assert.Equal(t, logPublisher.entries[0].Attempt.ID, got.ID)
+		assert.Equal(t, models.AttemptStatusSuccess, got.Status)
+	})
+}
@@ -4,10 +4,14 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/hookdeck/outpost/internal/backoff"
 	"github.com/hookdeck/outpost/internal/consumer"
+	"github.com/hookdeck/outpost/internal/deliveryack"
+	"github.com/hookdeck/outpost/internal/deliveryhook"
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/idempotence"
 	"github.com/hookdeck/outpost/internal/logging"
@@ -15,6 +19,9 @@ import (
 	"github.com/hookdeck/outpost/internal/mqs"
 	"github.com/hookdeck/outpost/internal/scheduler"
 	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/tokenstore"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
 	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 )
@@ -27,6 +34,15 @@ var (
 	errDestinationDisabled = errors.New("destination disabled")
 )
 
+// retryDeferDelay is how long an automatic retry is pushed back when the retry
+// limiter has no free slot for its target host. Deferring does not consume an
+// attempt: the retry scheduler derives the attempt number from the logstore.
+const retryDeferDelay = 5 * time.Second
+
+// minDeliveryDeferDelay is the shortest a delivery over its destination's caps
+// is pushed back; the retry scheduler works in whole seconds.
+const minDeliveryDeferDelay = time.Second
+
 // Error types to distinguish between different stages of delivery
 type PreDeliveryError struct {
 	err error
@@ -74,12 +90,118 @@ type messageHandler struct {
 	retryMaxLimit  int
 	idempotence    idempotence.Idempotence
 	publisher      Publisher
+	retryLimiter   RetryLimiter
+	destLimiter    DestinationLimiter
+	fanoutLimiter  FanoutLimiter
+	tenantGetter   TenantGetter
+	sandboxes      *sandboxCache
+	recorder       Recorder
+	redactor       Redactor
+	acks           AckRegistry
+	activity       ActivityTracker
+	health         HealthRecorder
+	hooks          deliveryhook.Hooks
+	held           HeldTaskStore
+	versions       DestinationVersionLister
+
+	shadowConcurrency int
+	shadowSlots       chan struct{}
+	shadowDropped     metric.Int64Counter
+
+	sandboxCacheTTL time.Duration
+}
+
+// MessageHandlerOption is a functional option for configuring the delivery
+// message handler.
+type MessageHandlerOption func(*messageHandler)
+
+// WithTenantGetter enables tenant-level delivery settings (sandbox mode), which
+// are cached for the sandbox cache TTL. When unset, every task is delivered as
+// if its tenant were not in sandbox mode.
+func WithTenantGetter(tenantGetter TenantGetter) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.tenantGetter = tenantGetter
+	}
+}
+
+// WithRecorder mirrors deliveries of destinations with an active recording.
+func WithRecorder(recorder Recorder) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.recorder = recorder
+	}
+}
+
+// WithRedactor redacts credentials from the attempt's response data before it
+// is logged or recorded.
+func WithRedactor(redactor Redactor) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.redactor = redactor
+	}
+}
+
+// WithAckRegistry enables two-phase delivery for webhook destinations with an
+// ack timeout: deliveries carry an ack token, and a 202 response defers
+// completion until the consumer acknowledges the token. Without it, ack
+// timeouts are ignored and every 2xx completes the delivery.
+func WithAckRegistry(acks AckRegistry) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.acks = acks
+	}
+}
+
+// WithRetryLimiter caps concurrent automatic retries. Retries that cannot
+// acquire a slot are rescheduled instead of attempted. A nil limiter disables
+// limiting.
+func WithRetryLimiter(limiter RetryLimiter) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.retryLimiter = limiter
+	}
+}
+
+// WithDestinationLimiter enforces the destinations' max_concurrency and
+// max_deliveries_per_second. Deliveries over the caps are rescheduled instead
+// of holding a worker.
+func WithDestinationLimiter(limiter DestinationLimiter) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.destLimiter = limiter
+	}
+}
+
+// WithFanoutLimiter caps concurrent deliveries per event and per topic.
+// Deliveries over the caps are rescheduled instead of holding a worker. A nil
+// limiter disables limiting.
+func WithFanoutLimiter(limiter FanoutLimiter) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.fanoutLimiter = limiter
+	}
+}
+
+// WithActivityTracker records the tenant of every delivery so the next worker
+// to start can warm the publishers of recently active tenants.
+func WithActivityTracker(activity ActivityTracker) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.activity = activity
+	}
+}
+
+// WithHealthRecorder records the outcome of every attempt so the API can
+// report which destinations are failing.
+func WithHealthRecorder(health HealthRecorder) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.health = health
+	}
 }
 
 type Publisher interface {
 	PublishEvent(ctx context.Context, destination *models.Destination, event *models.Event) (*models.Attempt, error)
 }
 
+// DestinationSnapshotter is implemented by publishers that can describe a
+// destination for delivery records, such as the destination registry.
+type DestinationSnapshotter interface {
+	SnapshotDestination(destination *models.Destination) *models.DestinationSnapshot
+}
+
 type LogPublisher interface {
 	Publish(ctx context.Context, entry models.LogEntry) error
 }
@@ -93,6 +215,39 @@ type DestinationGetter interface {
 	RetrieveDestination(ctx context.Context, tenantID, destID string) (*models.Destination, error)
 }
 
+// TenantGetter resolves the tenant of a delivery task. It is used to honor
+// tenant-level delivery settings such as sandbox mode.
+type TenantGetter interface {
+	RetrieveTenant(ctx context.Context, tenantID string) (*models.Tenant, error)
+}
+
+// Recorder mirrors a finished delivery to a debug sink when the destination
+// has an active recording. Implementations must not block.
+type Recorder interface {
+	Record(ctx context.Context, destination *models.Destination, event *models.Event, attempt *models.Attempt)
+}
+
+// Redactor removes credentials from attempt data before it is stored.
+type Redactor interface {
+	Redact(data map[string]interface{}) map[string]interface{}
+}
+
+// ActivityTracker records which tenants receive deliveries. Implementations
+// are expected to throttle writes per tenant.
+type ActivityTracker interface {
+	Touch(ctx context.Context, tenantID string) error
+}
+
+// HealthRecorder records the outcome of attempts per destination.
+type HealthRecorder interface {
+	Record(ctx context.Context, tenantID, destinationID string, attempt *models.Attempt) error
+}
+
+// AckRegistry records deliveries awaiting a consumer acknowledgment.
+type AckRegistry interface {
+	Register(ctx context.Context, token string, pending deliveryack.Pending, ttl time.Duration) error
+}
+
 type DeliveryTracer interface {
 	Deliver(ctx context.Context, task *models.DeliveryTask, destination *models.Destination) (context.Context, trace.Span)
 }
@@ -107,8 +262,9 @@ func NewMessageHandler(
 	retryBackoff backoff.Backoff,
 	retryMaxLimit int,
 	idempotence idempotence.Idempotence,
+	opts ...MessageHandlerOption,
 ) consumer.MessageHandler {
-	return &messageHandler{
+	h := &messageHandler{
 		eventTracer:    eventTracer,
 		logger:         logger,
 		logMQ:          logMQ,
@@ -118,7 +274,20 @@ func NewMessageHandler(
 		retryBackoff:   retryBackoff,
 		retryMaxLimit:  retryMaxLimit,
 		idempotence:    idempotence,
+
+		shadowConcurrency: DefaultShadowConcurrency,
+		sandboxCacheTTL:   DefaultSandboxCacheTTL,
+	}
+	for _, opt := range opts {
+		opt(h)
 	}
+	h.sandboxes = newSandboxCache(h.sandboxCacheTTL)
+	h.shadowSlots = make(chan struct{}, h.shadowConcurrency)
+	// A failing meter leaves drops uncounted rather than failing deliveries.
+	h.shadowDropped, _ = otel.Meter("outpost").Int64Counter("outpost.shadow.dropped",
+		metric.WithDescription("Number of shadow deliveries dropped because too many were in flight"),
+	)
+	return h
 }
 
 func (h *messageHandler) Handle(ctx context.Context, msg *mqs.Message) error {
@@ -138,12 +307,53 @@ func (h *messageHandler) Handle(ctx context.Context, msg *mqs.Message) error {
 	if err != nil {
 		return h.handleError(msg, &PreDeliveryError{err: err})
 	}
+	destination, err = h.routeQueuedTask(ctx, task, destination)
+	if err != nil {
+		return h.handleError(msg, &PreDeliveryError{err: err})
+	}
+
+	if h.activity != nil {
+		if err := h.activity.Touch(ctx, task.Event.TenantID); err != nil {
+			h.logger.Ctx(ctx).Warn("failed to record tenant activity",
+				zap.String("tenant_id", task.Event.TenantID),
+				zap.Error(err))
+		}
+	}
+
+	sandboxed, err := h.isSandboxed(ctx, task, destination)
+	if err != nil {
+		return h.handleError(msg, &PreDeliveryError{err: err})
+	}
+
+	if h.retryLimiter != nil && isLimitedRetry(task) {
+		release, ok := h.retryLimiter.Acquire(retryTargetHost(destination))
+		if !ok {
+			return h.handleError(msg, h.deferRetry(ctx, task, destination))
+		}
+		defer release()
+	}
+
+	if h.destLimiter != nil {
+		release, retryAfter, ok := h.destLimiter.Acquire(destination)
+		if !ok {
+			return h.handleError(msg, h.deferDelivery(ctx, task, destination, retryAfter))
+		}
+		defer release()
+	}
+
+	if h.fanoutLimiter != nil {
+		release, ok := h.fanoutLimiter.Acquire(&task.Event)
+		if !ok {
+			return h.handleError(msg, h.deferDelivery(ctx, task, destination, minDeliveryDeferDelay))
+		}
+		defer release()
+	}
 
 	executed := false
 	idempotencyKey := idempotencyKeyFromDeliveryTask(task)
 	err = h.idempotence.Exec(ctx, idempotencyKey, func(ctx context.Context) error {
 		executed = true
-		return h.doHandle(ctx, task, destination)
+		return h.doHandle(ctx, task, destination, sandboxed)
 	})
 	if err == nil && !executed {
 		h.logger.Ctx(ctx).Debug("delivery task skipped (idempotent)",
@@ -168,7 +378,7 @@ func (h *messageHandler) handleError(msg *mqs.Message, err error) error {
 	// Don't return error for expected cases
 	var preErr *PreDeliveryError
 	if errors.As(err, &preErr) {
-		if errors.Is(preErr.err, tenantstore.ErrDestinationDeleted) || errors.Is(preErr.err, errDestinationDisabled) {
+		if errors.Is(preErr.err, tenantstore.ErrDestinationDeleted) || errors.Is(preErr.err, errDestinationDisabled) || errors.Is(preErr.err, errDeliveryHeld) {
 			return nil
 		}
 	}
@@ -194,14 +404,47 @@ type retryOutcome struct {
 	scheduleFailed bool
 	canceled       bool
 	cancelFailed   bool
+	awaitingAck    bool
+	redelivery     bool
 }
 
-func (h *messageHandler) doHandle(ctx context.Context, task models.DeliveryTask, destination *models.Destination) error {
+func (h *messageHandler) doHandle(ctx context.Context, task models.DeliveryTask, destination *models.Destination, sandboxed bool) error {
 	_, span := h.eventTracer.Deliver(ctx, &task, destination)
 	defer span.End()
 
+	h.mirrorToShadow(ctx, task, destination, sandboxed)
+
+	// The ack token travels with the publish context rather than the event,
+	// so it neither overrides nor shows up in the event's metadata; the
+	// publisher adds it as a header.
+	publishCtx := ctx
+	var ackToken string
+	ackTimeout := h.ackTimeout(destination)
+	if ackTimeout > 0 && !sandboxed {
+		token, err := tokenstore.NewToken()
+		if err != nil {
+			return &PreDeliveryError{err: err}
+		}
+		ackToken = token
+		publishCtx = destregistry.WithAckToken(ctx, token)
+	}
+
 	attemptStart := time.Now()
-	attempt, err := h.publisher.PublishEvent(ctx, destination, &task.Event)
+	var attempt *models.Attempt
+	var err error
+	if sandboxed {
+		attempt = newSandboxAttempt(destination, &task.Event)
+	} else {
+		event, vetoed, hookErr := h.runPreDeliveryHooks(ctx, &task, destination)
+		if hookErr != nil {
+			return &PreDeliveryError{err: hookErr}
+		}
+		if vetoed {
+			return nil
+		}
+		attemptStart = time.Now()
+		attempt, err = h.publisher.PublishEvent(publishCtx, destination, event)
+	}
 	attemptDuration := time.Since(attemptStart)
 
 	var retry retryOutcome
@@ -213,6 +456,23 @@ func (h *messageHandler) doHandle(ctx context.Context, task models.DeliveryTask,
 			return &PreDeliveryError{err: err}
 		}
 
+		// The destination asked to receive the event again later. This is
+		// not a failure, so it is neither counted as one nor nacked.
+		var redeliveryErr *destregistry.ErrRedeliveryRequested
+		if errors.As(err, &redeliveryErr) {
+			retry.redelivery = true
+			if !h.canRetry(task, destination) {
+				return h.logDeliveryResult(ctx, &task, destination, attempt, attemptStart, attemptDuration, retry, nil)
+			}
+			retry.backoff = redeliveryErr.After
+			if retryErr := h.scheduleRetryAfter(ctx, task, redeliveryErr.After); retryErr != nil {
+				retry.scheduleFailed = true
+				return h.logDeliveryResult(ctx, &task, destination, attempt, attemptStart, attemptDuration, retry, retryErr)
+			}
+			retry.scheduled = true
+			return h.logDeliveryResult(ctx, &task, destination, attempt, attemptStart, attemptDuration, retry, nil)
+		}
+
 		// Record delivery failure for metrics
 		if recorder, ok := span.(interface{ RecordDeliveryResult(bool) }); ok {
 			recorder.RecordDeliveryResult(false)
@@ -220,14 +480,14 @@ func (h *messageHandler) doHandle(ctx context.Context, task models.DeliveryTask,
 
 		attemptErr := &AttemptError{err: err}
 
-		if h.shouldScheduleRetry(task, err) {
+		if h.shouldScheduleRetry(task, destination, err) {
 			// scheduleRetry uses RetryID (event_id:destination_id) as the scheduler
 			// task ID. The scheduler has upsert semantics: scheduling with the same ID
 			// atomically replaces the existing entry (both timing and payload). This
 			// means manual retries automatically override any pending automatic retry
 			// without needing an explicit cancel — the new tier's delay takes effect
 			// and the old scheduled retry is gone in a single operation.
-			backoff, retryErr := h.scheduleRetry(ctx, task)
+			backoff, retryErr := h.scheduleRetry(ctx, task, destination)
 			retry.backoff = backoff
 			if retryErr != nil {
 				retry.scheduleFailed = true
@@ -253,7 +513,18 @@ func (h *messageHandler) doHandle(ctx context.Context, task models.DeliveryTask,
 	}
 
 	// Handle successful delivery
-	if task.Manual {
+	if ackToken != "" && h.shouldAwaitAck(task, destination, attempt) {
+		// The consumer accepted the delivery for asynchronous processing. The
+		// retry scheduled for the ack timeout replaces any pending retry and
+		// is canceled when the consumer acknowledges the token.
+		retry.awaitingAck = true
+		retry.backoff = ackTimeout
+		if ackErr := h.awaitAck(ctx, task, attempt, ackToken, ackTimeout); ackErr != nil {
+			retry.scheduleFailed = true
+			return h.logDeliveryResult(ctx, &task, destination, attempt, attemptStart, attemptDuration, retry, ackErr)
+		}
+		retry.scheduled = true
+	} else if task.Manual {
 		if cancelErr := h.retryScheduler.Cancel(ctx, models.RetryID(task.Event.ID, task.DestinationID)); cancelErr != nil {
 			retry.cancelFailed = true
 			h.logger.Ctx(ctx).Error("failed to cancel scheduled retry",
@@ -277,6 +548,30 @@ func (h *messageHandler) logDeliveryResult(ctx context.Context, task *models.Del
 	attempt.TenantID = task.Event.TenantID
 	attempt.AttemptNumber = task.Attempt
 	attempt.Manual = task.Manual
+	if snapshotter, ok := h.publisher.(DestinationSnapshotter); ok {
+		attempt.DestinationSnapshot = snapshotter.SnapshotDestination(destination)
+	}
+
+	// Redact before anything outside the handler sees the attempt, hooks
+	// included.
+	if h.redactor != nil {
+		attempt.ResponseData = h.redactor.Redact(attempt.ResponseData)
+	}
+
+	h.runPostDeliveryHooks(ctx, task, destination, attempt)
+
+	if h.recorder != nil && destination.Recording != nil {
+		h.recorder.Record(ctx, destination, &task.Event, attempt)
+	}
+
+	if h.health != nil {
+		if err := h.health.Record(ctx, task.Event.TenantID, destination.ID, attempt); err != nil {
+			logger.Warn("failed to record destination health",
+				zap.Error(err),
+				zap.String("tenant_id", task.Event.TenantID),
+				zap.String("destination_id", destination.ID))
+		}
+	}
 
 	// Wide event: one audit per delivery attempt carrying the full outcome
 	// (attempt result, timing, retry decision). Replaces the separate
@@ -292,7 +587,7 @@ func (h *messageHandler) logDeliveryResult(ctx context.Context, task *models.Del
 		zap.String("attempt_status", attempt.Status),
 		zap.String("attempt_code", attempt.Code),
 		zap.Int("attempt_number", task.Attempt),
-		zap.Int("attempt_max", h.retryMaxLimit+1),
+		zap.Int("attempt_max", h.retryLimit(destination)+1),
 		zap.Bool("manual", task.Manual),
 		zap.Bool("eligible_for_retry", task.Event.EligibleForRetry),
 		zap.Time("attempt_started_at", attemptStart),
@@ -301,6 +596,9 @@ func (h *messageHandler) logDeliveryResult(ctx context.Context, task *models.Del
 		zap.Bool("retry_scheduled", retry.scheduled),
 		zap.Bool("retry_canceled", retry.canceled),
 	}
+	if attempt.Code == models.AttemptCodeSandbox {
+		fields = append(fields, zap.Bool("sandbox", true))
+	}
 	if retry.scheduled {
 		fields = append(fields, zap.Int64("retry_backoff_ms", retry.backoff.Milliseconds()))
 	}
@@ -310,12 +608,19 @@ func (h *messageHandler) logDeliveryResult(ctx context.Context, task *models.Del
 	if retry.cancelFailed {
 		fields = append(fields, zap.Bool("retry_cancel_failed", true))
 	}
+	if retry.awaitingAck {
+		fields = append(fields, zap.Bool("awaiting_ack", true))
+	}
+	if retry.redelivery {
+		fields = append(fields, zap.Bool("redelivery_requested", true))
+	}
 	logger.Info("delivery.attempted", fields...)
 
 	logEntry := models.LogEntry{
 		Event:       &task.Event,
 		Attempt:     attempt,
 		Destination: destination,
+		AwaitingAck: retry.awaitingAck,
 	}
 	if logErr := h.logMQ.Publish(ctx, logEntry); logErr != nil {
 		logger.Error("failed to publish attempt log",
@@ -351,16 +656,93 @@ func (h *messageHandler) logDeliveryResult(ctx context.Context, task *models.Del
 	return nil
 }
 
-func (h *messageHandler) shouldScheduleRetry(task models.DeliveryTask, err error) bool {
-	if !task.Event.EligibleForRetry {
-		return false
-	}
+func (h *messageHandler) shouldScheduleRetry(task models.DeliveryTask, destination *models.Destination, err error) bool {
 	var pubErr *destregistry.ErrDestinationPublishAttempt
 	if !errors.As(err, &pubErr) {
 		return false
 	}
-	// Attempt is 1-indexed: max attempts = 1 (initial) + retryMaxLimit (retries)
-	return task.Attempt <= h.retryMaxLimit
+	// A transformation fails the same way on every attempt.
+	if errors.Is(pubErr.Err, models.ErrTransformationFailed) {
+		return false
+	}
+	return h.canRetry(task, destination)
+}
+
+// canRetry reports whether the event may be delivered again after this
+// attempt.
+func (h *messageHandler) canRetry(task models.DeliveryTask, destination *models.Destination) bool {
+	// Attempt is 1-indexed: max attempts = 1 (initial) + retry limit (retries)
+	return task.Event.EligibleForRetry && task.Attempt <= h.retryLimit(destination)
+}
+
+// retryLimit returns the number of retries of a delivery to destination: its
+// retry policy's, or the deployment's.
+func (h *messageHandler) retryLimit(destination *models.Destination) int {
+	if limit, ok := destination.RetryPolicy.RetryLimit(); ok {
+		return limit
+	}
+	return h.retryMaxLimit
+}
+
+// retryBackoffFor returns the backoff between retries of a delivery to
+// destination: its retry policy's, or the deployment's.
+func (h *messageHandler) retryBackoffFor(destination *models.Destination) backoff.Backoff {
+	if retryBackoff, ok := destination.RetryPolicy.RetryBackoff(); ok {
+		return retryBackoff
+	}
+	return h.retryBackoff
+}
+
+// shouldAwaitAck reports whether a successful attempt waits for the consumer's
+// acknowledgment. Only a 202 defers completion, and only while the delivery
+// could still be retried; otherwise the attempt completes the delivery.
+func (h *messageHandler) shouldAwaitAck(task models.DeliveryTask, destination *models.Destination, attempt *models.Attempt) bool {
+	if attempt.Code != strconv.Itoa(http.StatusAccepted) {
+		return false
+	}
+	return h.canRetry(task, destination)
+}
+
+// awaitAck registers the delivery under its ack token and schedules a retry
+// for when the ack timeout elapses. The token expires with the timeout, so a
+// late acknowledgment cannot cancel a retry of a later attempt.
+func (h *messageHandler) awaitAck(ctx context.Context, task models.DeliveryTask, attempt *models.Attempt, token string, timeout time.Duration) error {
+	if err := h.scheduleRetryAfter(ctx, task, timeout); err != nil {
+		return err
+	}
+	pending := deliveryack.Pending{
+		TenantID:      task.Event.TenantID,
+		EventID:       task.Event.ID,
+		DestinationID: task.DestinationID,
+		Topic:         task.Event.Topic,
+		AttemptID:     attempt.ID,
+		AttemptNumber: attempt.AttemptNumber,
+	}
+	if err := h.acks.Register(ctx, token, pending, timeout); err != nil {
+		h.logger.Ctx(ctx).Error("failed to register delivery ack",
+			zap.Error(err),
+			zap.String("event_id", task.Event.ID),
+			zap.String("tenant_id", task.Event.TenantID),
+			zap.String("destination_id", task.DestinationID),
+			zap.Int("attempt", task.Attempt))
+		return err
+	}
+	return nil
+}
+
+// ackTimeout returns how long a webhook destination has to acknowledge a
+// delivery it accepted with 202, or zero when two-phase delivery is off. The
+// value was validated against the provider metadata when the destination was
+// saved.
+func (h *messageHandler) ackTimeout(destination *models.Destination) time.Duration {
+	if h.acks == nil || destination.Type != "webhook" {
+		return 0
+	}
+	seconds, err := strconv.Atoi(destination.Config["ack_timeout"])
+	if err != nil || seconds <= 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
 }
 
 func (h *messageHandler) shouldNackError(err error) bool {
@@ -372,7 +754,7 @@ func (h *messageHandler) shouldNackError(err error) bool {
 	var preErr *PreDeliveryError
 	if errors.As(err, &preErr) {
 		// Don't nack if it's a permanent error
-		if errors.Is(preErr.err, tenantstore.ErrDestinationDeleted) || errors.Is(preErr.err, errDestinationDisabled) {
+		if errors.Is(preErr.err, tenantstore.ErrDestinationDeleted) || errors.Is(preErr.err, errDestinationDisabled) || errors.Is(preErr.err, errDeliveryHeld) {
 			return false
 		}
 		return true // Nack other pre-delivery errors
@@ -408,29 +790,104 @@ func (h *messageHandler) shouldNackDeliveryError(err error) bool {
 	return true // Nack other delivery errors
 }
 
-func (h *messageHandler) scheduleRetry(ctx context.Context, task models.DeliveryTask) (time.Duration, error) {
+func (h *messageHandler) scheduleRetry(ctx context.Context, task models.DeliveryTask, destination *models.Destination) (time.Duration, error) {
 	// Attempt is 1-indexed; backoff schedule is 0-indexed.
 	// Clamp to 0 to safely handle any leftover Attempt=0 in-flight tasks.
-	backoffDuration := h.retryBackoff.Duration(max(task.Attempt-1, 0))
+	backoffDuration := h.retryBackoffFor(destination).Duration(max(task.Attempt-1, 0))
+	return backoffDuration, h.scheduleRetryAfter(ctx, task, backoffDuration)
+}
 
+// scheduleRetryAfter schedules the next attempt of the task after delay,
+// replacing any retry already pending for the event and destination.
+func (h *messageHandler) scheduleRetryAfter(ctx context.Context, task models.DeliveryTask, delay time.Duration) error {
 	retryTask := RetryTaskFromDeliveryTask(task)
 	retryTaskStr, err := retryTask.ToString()
 	if err != nil {
-		return backoffDuration, err
+		return err
 	}
 
-	if err := h.retryScheduler.Schedule(ctx, retryTaskStr, backoffDuration, scheduler.WithTaskID(models.RetryID(task.Event.ID, task.DestinationID))); err != nil {
+	if err := h.retryScheduler.Schedule(ctx, retryTaskStr, delay, scheduler.WithTaskID(models.RetryID(task.Event.ID, task.DestinationID))); err != nil {
 		h.logger.Ctx(ctx).Error("failed to schedule retry",
 			zap.Error(err),
 			zap.String("event_id", task.Event.ID),
 			zap.String("tenant_id", task.Event.TenantID),
 			zap.String("destination_id", task.DestinationID),
 			zap.Int("attempt", task.Attempt),
-			zap.Duration("backoff", backoffDuration))
-		return backoffDuration, err
+			zap.Duration("backoff", delay))
+		return err
 	}
 
-	return backoffDuration, nil
+	return nil
+}
+
+// isLimitedRetry reports whether the task counts against the retry limiter:
+// retries produced by the retry scheduler or by a bulk retry job, but not
+// first attempts or single manual retry requests.
+func isLimitedRetry(task models.DeliveryTask) bool {
+	return task.Attempt > 1 && (!task.Manual || task.Bulk)
+}
+
+// deferRetry pushes an automatic retry back by retryDeferDelay because the
+// retry limiter is saturated. The scheduler upserts by RetryID, so this never
+// duplicates a pending retry. A scheduling failure is returned as a
+// pre-delivery error so the message is nacked and redelivered.
+func (h *messageHandler) deferRetry(ctx context.Context, task models.DeliveryTask, destination *models.Destination) error {
+	retryTask := RetryTaskFromDeliveryTask(task)
+	retryTaskStr, err := retryTask.ToString()
+	if err != nil {
+		return &PreDeliveryError{err: err}
+	}
+	if err := h.retryScheduler.Schedule(ctx, retryTaskStr, retryDeferDelay, scheduler.WithTaskID(models.RetryID(task.Event.ID, task.DestinationID))); err != nil {
+		h.logger.Ctx(ctx).Error("failed to defer retry",
+			zap.Error(err),
+			zap.String("event_id", task.Event.ID),
+			zap.String("tenant_id", task.Event.TenantID),
+			zap.String("destination_id", task.DestinationID),
+			zap.Int("attempt", task.Attempt))
+		return &PreDeliveryError{err: err}
+	}
+	h.logger.Ctx(ctx).Info("delivery.retry_deferred",
+		zap.String("event_id", task.Event.ID),
+		zap.String("tenant_id", task.Event.TenantID),
+		zap.String("destination_id", destination.ID),
+		zap.String("destination_type", destination.Type),
+		zap.String("target_host", retryTargetHost(destination)),
+		zap.Int("attempt_number", task.Attempt),
+		zap.Int64("retry_backoff_ms", retryDeferDelay.Milliseconds()))
+	return nil
+}
+
+// deferDelivery pushes a delivery back because its destination is at its
+// max_concurrency or max_deliveries_per_second. The task is scheduled whole,
+// attempt included, since a first attempt has no prior attempt to rebuild it
+// from. A scheduling failure, such as an event too large for the retry queue,
+// is returned as a pre-delivery error so the message is nacked and
+// redelivered.
+func (h *messageHandler) deferDelivery(ctx context.Context, task models.DeliveryTask, destination *models.Destination, delay time.Duration) error {
+	delay = max(delay, minDeliveryDeferDelay)
+	retryTask := RetryTaskFromDeliveryTask(task)
+	retryTask.Deferred = &task
+	retryTaskStr, err := retryTask.ToString()
+	if err != nil {
+		return &PreDeliveryError{err: err}
+	}
+	if err := h.retryScheduler.Schedule(ctx, retryTaskStr, delay, scheduler.WithTaskID(models.RetryID(task.Event.ID, task.DestinationID))); err != nil {
+		h.logger.Ctx(ctx).Warn("failed to defer delivery",
+			zap.Error(err),
+			zap.String("event_id", task.Event.ID),
+			zap.String("tenant_id", task.Event.TenantID),
+			zap.String("destination_id", task.DestinationID),
+			zap.Int("attempt", task.Attempt))
+		return &PreDeliveryError{err: err}
+	}
+	h.logger.Ctx(ctx).Info("delivery.deferred",
+		zap.String("event_id", task.Event.ID),
+		zap.String("tenant_id", task.Event.TenantID),
+		zap.String("destination_id", destination.ID),
+		zap.String("destination_type", destination.Type),
+		zap.Int("attempt_number", task.Attempt),
+		zap.Int64("defer_ms", delay.Milliseconds()))
+	return nil
 }
 
 // ensurePublishableDestination ensures that the destination exists and is in a publishable state.
@@ -3,6 +3,7 @@ package deliverymq_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -246,6 +247,61 @@ func TestMessageHandler_PublishError_EligibleForRetry(t *testing.T) {
 	assert.Equal(t, models.AttemptStatusFailed, logPublisher.entries[0].Attempt.Status, "delivery status should be Failed")
 }
 
+func TestMessageHandler_PublishError_TransformationFailed(t *testing.T) {
+	// Test scenario:
+	// - The destination's transformation fails on the event
+	// - Event is eligible for retry and under max attempts
+	// - Should not schedule a retry, since the transformation fails the same way
+
+	tenant := models.Tenant{ID: idgen.String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID(tenant.ID),
+		testutil.EventFactory.WithDestinationID(destination.ID),
+		testutil.EventFactory.WithEligibleForRetry(true),
+	)
+
+	destGetter := &mockDestinationGetter{dest: &destination}
+	retryScheduler := newMockRetryScheduler()
+	publishErr := &destregistry.ErrDestinationPublishAttempt{
+		Err:      fmt.Errorf("%w: expression produced no value", models.ErrTransformationFailed),
+		Provider: "webhook",
+		Data:     map[string]interface{}{"error": "transformation_failed"},
+	}
+	publisher := newMockPublisher([]error{publishErr})
+	logPublisher := newMockLogPublisher(nil)
+
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		logPublisher,
+		destGetter,
+		publisher,
+		testutil.NewMockEventTracer(nil),
+		retryScheduler,
+		&backoff.ConstantBackoff{Interval: 1 * time.Second},
+		10,
+		idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+	)
+
+	task := models.DeliveryTask{
+		Event:         event,
+		DestinationID: destination.ID,
+	}
+	mockMsg, msg := newDeliveryMockMessage(task)
+
+	err := handler.Handle(context.Background(), msg)
+	require.NoError(t, err)
+
+	assert.False(t, mockMsg.nacked, "message should not be nacked")
+	assert.True(t, mockMsg.acked, "message should be acked")
+	assert.Empty(t, retryScheduler.schedules, "no retry should be scheduled")
+	require.Len(t, logPublisher.entries, 1, "should have one delivery")
+	assert.Equal(t, models.AttemptStatusFailed, logPublisher.entries[0].Attempt.Status, "delivery status should be Failed")
+}
+
 func TestMessageHandler_PublishError_NotEligible(t *testing.T) {
 	// Test scenario:
 	// - Publish returns ErrDestinationPublishAttempt
@@ -1199,3 +1255,381 @@ func TestMessageHandler_RetryID_MultipleDestinations(t *testing.T) {
 		"BUG: retry task IDs should be unique per destination, but both are: %s",
 		retryScheduler.taskIDs[0])
 }
+
+func TestMessageHandler_DestinationSnapshot(t *testing.T) {
+	// Test scenario:
+	// - Publisher can snapshot destinations
+	// - Logged attempt should carry the destination snapshot
+
+	// Setup test data
+	tenant := models.Tenant{ID: idgen.String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID(tenant.ID),
+		testutil.EventFactory.WithDestinationID(destination.ID),
+	)
+
+	// Setup mocks
+	publisher := &snapshottingPublisher{mockPublisher: newMockPublisher([]error{nil})}
+	logPublisher := newMockLogPublisher(nil)
+
+	// Setup message handler
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		logPublisher,
+		&mockDestinationGetter{dest: &destination},
+		publisher,
+		testutil.NewMockEventTracer(nil),
+		newMockRetryScheduler(),
+		&backoff.ConstantBackoff{Interval: 1 * time.Second},
+		10,
+		idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+	)
+
+	// Create and handle message
+	task := models.DeliveryTask{
+		Event:         event,
+		DestinationID: destination.ID,
+	}
+	mockMsg, msg := newDeliveryMockMessage(task)
+
+	// Handle message
+	err := handler.Handle(context.Background(), msg)
+	require.NoError(t, err)
+
+	// Assert behavior
+	assert.True(t, mockMsg.acked, "message should be acked on successful delivery")
+	require.Len(t, logPublisher.entries, 1, "should have one attempt logged")
+	snapshot := logPublisher.entries[0].Attempt.DestinationSnapshot
+	require.NotNil(t, snapshot, "attempt should carry a destination snapshot")
+	assert.Equal(t, "webhook", snapshot.Type)
+	assert.Equal(t, "snapshot:"+destination.ID, snapshot.Target)
+	assert.Equal(t, destination.Config, snapshot.Config)
+}
+
+func TestMessageHandler_AckTimeout(t *testing.T) {
+	// Test scenario:
+	// - Webhook destination has an ack timeout
+	// - Delivery carries an ack token alongside, not in, the event metadata
+	// - A 202 registers the token and schedules a retry for the timeout
+	// - Any other 2xx completes the delivery
+
+	setup := func(t *testing.T, code string, eventOpts ...func(*models.Event)) (*acceptingPublisher, *mockAckRegistry, *mockRetryScheduler, *mockLogPublisher, *mockMessage, error) {
+		tenant := models.Tenant{ID: idgen.String()}
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithTenantID(tenant.ID),
+			testutil.DestinationFactory.WithConfig(map[string]string{
+				"url":         "https://example.com",
+				"ack_timeout": "300",
+			}),
+		)
+		event := testutil.EventFactory.Any(append([]func(*models.Event){
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithDestinationID(destination.ID),
+		}, eventOpts...)...)
+
+		publisher := &acceptingPublisher{code: code}
+		acks := newMockAckRegistry()
+		retryScheduler := newMockRetryScheduler()
+		logPublisher := newMockLogPublisher(nil)
+
+		handler := deliverymq.NewMessageHandler(
+			testutil.CreateTestLogger(t),
+			logPublisher,
+			&mockDestinationGetter{dest: &destination},
+			publisher,
+			testutil.NewMockEventTracer(nil),
+			retryScheduler,
+			&backoff.ConstantBackoff{Interval: 1 * time.Second},
+			10,
+			idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+			deliverymq.WithAckRegistry(acks),
+		)
+
+		mockMsg, msg := newDeliveryMockMessage(models.DeliveryTask{
+			Event:         event,
+			DestinationID: destination.ID,
+		})
+		err := handler.Handle(context.Background(), msg)
+		return publisher, acks, retryScheduler, logPublisher, mockMsg, err
+	}
+
+	t.Run("202 awaits acknowledgment", func(t *testing.T) {
+		publisher, acks, retryScheduler, logPublisher, mockMsg, err := setup(t, "202")
+		require.NoError(t, err)
+		assert.True(t, mockMsg.acked, "message should be acked")
+
+		require.Len(t, publisher.events, 1)
+		token := publisher.ackTokens[0]
+		require.NotEmpty(t, token, "delivery should carry an ack token")
+		assert.NotContains(t, publisher.events[0].Metadata, "ack-token", "token should not be written to the event metadata")
+
+		require.Contains(t, acks.acks, token, "token should be registered")
+		ack := acks.acks[token]
+		assert.Equal(t, 300*time.Second, ack.ttl)
+		assert.Equal(t, publisher.events[0].ID, ack.pending.EventID)
+
+		require.Len(t, logPublisher.entries, 1)
+		assert.Equal(t, ack.pending.AttemptID, logPublisher.entries[0].Attempt.ID)
+		assert.Equal(t, publisher.events[0].Topic, ack.pending.Topic)
+		assert.True(t, logPublisher.entries[0].AwaitingAck, "the attempt should not complete the delivery before the ack")
+		assert.Empty(t, logPublisher.entries[0].Event.Metadata["ack-token"], "token should not be logged with the event")
+
+		retryID := models.RetryID(ack.pending.EventID, ack.pending.DestinationID)
+		require.Contains(t, retryScheduler.entries, retryID, "retry should be scheduled for the ack timeout")
+		assert.Equal(t, 300*time.Second, retryScheduler.entries[retryID].delay)
+	})
+
+	t.Run("200 completes the delivery", func(t *testing.T) {
+		publisher, acks, retryScheduler, _, mockMsg, err := setup(t, "200")
+		require.NoError(t, err)
+		assert.True(t, mockMsg.acked, "message should be acked")
+		assert.NotEmpty(t, publisher.ackTokens[0])
+		assert.Empty(t, acks.acks, "no token should be registered")
+		assert.Empty(t, retryScheduler.schedules, "no retry should be scheduled")
+	})
+
+	t.Run("202 for an event not eligible for retry completes the delivery", func(t *testing.T) {
+		_, acks, retryScheduler, _, _, err := setup(t, "202", testutil.EventFactory.WithEligibleForRetry(false))
+		require.NoError(t, err)
+		assert.Empty(t, acks.acks, "no token should be registered")
+		assert.Empty(t, retryScheduler.schedules, "no retry should be scheduled")
+	})
+}
+
+func TestMessageHandler_RedeliveryRequested(t *testing.T) {
+	// Test scenario:
+	// - Destination responds successfully but asks for redelivery in 90s
+	// - A retry is scheduled with the requested delay, not the backoff
+	// - The message is acked and the attempt is logged as deferred
+
+	setup := func(t *testing.T, scheduleErr error, eventOpts ...func(*models.Event)) (*mockRetryScheduler, *mockLogPublisher, *mockMessage, models.Event, models.Destination, error) {
+		tenant := models.Tenant{ID: idgen.String()}
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithTenantID(tenant.ID),
+		)
+		event := testutil.EventFactory.Any(append([]func(*models.Event){
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithDestinationID(destination.ID),
+			testutil.EventFactory.WithEligibleForRetry(true),
+		}, eventOpts...)...)
+
+		retryScheduler := newMockRetryScheduler()
+		if scheduleErr != nil {
+			retryScheduler.scheduleResp = []error{scheduleErr}
+		}
+		logPublisher := newMockLogPublisher(nil)
+
+		handler := deliverymq.NewMessageHandler(
+			testutil.CreateTestLogger(t),
+			logPublisher,
+			&mockDestinationGetter{dest: &destination},
+			&deferringPublisher{after: 90 * time.Second},
+			testutil.NewMockEventTracer(nil),
+			retryScheduler,
+			&backoff.ConstantBackoff{Interval: 1 * time.Second},
+			10,
+			idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+		)
+
+		mockMsg, msg := newDeliveryMockMessage(models.DeliveryTask{
+			Event:         event,
+			DestinationID: destination.ID,
+		})
+		err := handler.Handle(context.Background(), msg)
+		return retryScheduler, logPublisher, mockMsg, event, destination, err
+	}
+
+	t.Run("schedules a retry with the requested delay", func(t *testing.T) {
+		retryScheduler, logPublisher, mockMsg, event, destination, err := setup(t, nil)
+		require.NoError(t, err)
+		assert.True(t, mockMsg.acked, "message should be acked")
+		assert.False(t, mockMsg.nacked)
+
+		retryID := models.RetryID(event.ID, destination.ID)
+		require.Contains(t, retryScheduler.entries, retryID)
+		assert.Equal(t, 90*time.Second, retryScheduler.entries[retryID].delay)
+
+		require.Len(t, logPublisher.entries, 1)
+		assert.Equal(t, models.AttemptStatusDeferred, logPublisher.entries[0].Attempt.Status)
+	})
+
+	t.Run("not eligible for retry completes without scheduling", func(t *testing.T) {
+		retryScheduler, logPublisher, mockMsg, _, _, err := setup(t, nil, testutil.EventFactory.WithEligibleForRetry(false))
+		require.NoError(t, err)
+		assert.True(t, mockMsg.acked, "message should be acked")
+		assert.Empty(t, retryScheduler.schedules, "no retry should be scheduled")
+		require.Len(t, logPublisher.entries, 1)
+	})
+
+	t.Run("failed schedule nacks the message", func(t *testing.T) {
+		_, _, mockMsg, _, _, err := setup(t, errors.New("scheduler unavailable"))
+		require.Error(t, err)
+		assert.True(t, mockMsg.nacked, "message should be nacked so the request isn't lost")
+	})
+}
+
+func TestMessageHandler_DestinationRetryPolicy(t *testing.T) {
+	// Test scenario:
+	// - The destination's retry policy overrides the deployment's backoff
+	//   and retry limit
+	// - Retries follow the destination's schedule until its attempts run out
+
+	tenant := models.Tenant{ID: idgen.String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	destination.RetryPolicy = &models.RetryPolicy{MaxAttempts: 3, Schedule: []int{5, 60}}
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID(tenant.ID),
+		testutil.EventFactory.WithDestinationID(destination.ID),
+		testutil.EventFactory.WithEligibleForRetry(true),
+	)
+	publishErr := &destregistry.ErrDestinationPublishAttempt{
+		Err:      errors.New("webhook returned 503"),
+		Provider: "webhook",
+	}
+
+	tests := []struct {
+		attempt       int
+		expectedDelay time.Duration
+		expectRetry   bool
+	}{
+		{attempt: 1, expectedDelay: 5 * time.Second, expectRetry: true},
+		{attempt: 2, expectedDelay: 60 * time.Second, expectRetry: true},
+		{attempt: 3, expectRetry: false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("attempt %d", tt.attempt), func(t *testing.T) {
+			retryScheduler := newMockRetryScheduler()
+			handler := deliverymq.NewMessageHandler(
+				testutil.CreateTestLogger(t),
+				newMockLogPublisher(nil),
+				&mockDestinationGetter{dest: &destination},
+				newMockPublisher([]error{publishErr}),
+				testutil.NewMockEventTracer(nil),
+				retryScheduler,
+				&backoff.ConstantBackoff{Interval: time.Second},
+				10,
+				idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+			)
+
+			task := models.DeliveryTask{Event: event, DestinationID: destination.ID, Attempt: tt.attempt}
+			mockMsg, msg := newDeliveryMockMessage(task)
+			require.NoError(t, handler.Handle(context.Background(), msg))
+			assert.True(t, mockMsg.acked)
+
+			entry, scheduled := retryScheduler.entries[models.RetryID(event.ID, destination.ID)]
+			require.Equal(t, tt.expectRetry, scheduled)
+			if tt.expectRetry {
+				assert.Equal(t, tt.expectedDelay, entry.delay)
+			}
+		})
+	}
+}
+
+type mockActivityTracker struct {
+	touched []string
+}
+
+func (m *mockActivityTracker) Touch(_ context.Context, tenantID string) error {
+	m.touched = append(m.touched, tenantID)
+	return nil
+}
+
+func TestMessageHandler_ActivityTracker(t *testing.T) {
+	// Test scenario:
+	// - A delivery records its tenant's activity for publisher warm-up
+
+	tenant := models.Tenant{ID: idgen.String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID(tenant.ID),
+		testutil.EventFactory.WithDestinationID(destination.ID),
+	)
+
+	activity := &mockActivityTracker{}
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		newMockLogPublisher(nil),
+		&mockDestinationGetter{dest: &destination},
+		newMockPublisher(nil),
+		testutil.NewMockEventTracer(nil),
+		newMockRetryScheduler(),
+		&backoff.ConstantBackoff{Interval: time.Second},
+		10,
+		idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+		deliverymq.WithActivityTracker(activity),
+	)
+
+	task := models.DeliveryTask{Event: event, DestinationID: destination.ID}
+	mockMsg, msg := newDeliveryMockMessage(task)
+	require.NoError(t, handler.Handle(context.Background(), msg))
+	assert.True(t, mockMsg.acked)
+	assert.Equal(t, []string{tenant.ID}, activity.touched)
+}
+
+type mockHealthRecorder struct {
+	recorded []models.Attempt
+}
+
+func (m *mockHealthRecorder) Record(_ context.Context, tenantID, destinationID string, attempt *models.Attempt) error {
+	m.recorded = append(m.recorded, *attempt)
+	return nil
+}
+
+func TestMessageHandler_HealthRecorder(t *testing.T) {
+	// Test scenario:
+	// - A failed and then a successful delivery both record the destination's
+	//   health
+
+	tenant := models.Tenant{ID: idgen.String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID(tenant.ID),
+		testutil.EventFactory.WithDestinationID(destination.ID),
+		testutil.EventFactory.WithEligibleForRetry(false),
+	)
+	publishErr := &destregistry.ErrDestinationPublishAttempt{
+		Err:      errors.New("webhook returned 500"),
+		Provider: "webhook",
+		Data:     map[string]interface{}{"error": "publish_failed"},
+	}
+
+	health := &mockHealthRecorder{}
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		newMockLogPublisher(nil),
+		&mockDestinationGetter{dest: &destination},
+		newMockPublisher([]error{publishErr, nil}),
+		testutil.NewMockEventTracer(nil),
+		newMockRetryScheduler(),
+		&backoff.ConstantBackoff{Interval: time.Second},
+		10,
+		idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+		deliverymq.WithHealthRecorder(health),
+	)
+
+	for i := range 2 {
+		task := models.DeliveryTask{Event: event, DestinationID: destination.ID, Attempt: i + 1}
+		_, msg := newDeliveryMockMessage(task)
+		handler.Handle(context.Background(), msg)
+	}
+
+	require.Len(t, health.recorded, 2)
+	assert.Equal(t, models.AttemptStatusFailed, health.recorded[0].Status)
+	assert.Equal(t, tenant.ID, health.recorded[0].TenantID)
+	assert.Equal(t, models.AttemptStatusSuccess, health.recorded[1].Status)
+}
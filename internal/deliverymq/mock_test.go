@@ -6,6 +6,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/hookdeck/outpost/internal/deliveryack"
+	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/idgen"
 	"github.com/hookdeck/outpost/internal/logstore"
 	"github.com/hookdeck/outpost/internal/models"
@@ -192,6 +194,83 @@ func contains(slice []string, s string) bool {
 	return false
 }
 
+// snapshottingPublisher is a mockPublisher that also snapshots destinations,
+// like the destination registry.
+type snapshottingPublisher struct {
+	*mockPublisher
+}
+
+func (p *snapshottingPublisher) SnapshotDestination(destination *models.Destination) *models.DestinationSnapshot {
+	return &models.DestinationSnapshot{
+		Type:   destination.Type,
+		Target: "snapshot:" + destination.ID,
+		Config: destination.Config,
+	}
+}
+
+// acceptingPublisher accepts every delivery with the given response code and
+// records the events it was asked to publish and their ack tokens.
+type acceptingPublisher struct {
+	code      string
+	events    []*models.Event
+	ackTokens []string
+}
+
+func (p *acceptingPublisher) PublishEvent(ctx context.Context, destination *models.Destination, event *models.Event) (*models.Attempt, error) {
+	p.events = append(p.events, event)
+	p.ackTokens = append(p.ackTokens, destregistry.AckToken(ctx))
+	return &models.Attempt{
+		ID:            idgen.Attempt(),
+		EventID:       event.ID,
+		DestinationID: destination.ID,
+		Status:        models.AttemptStatusSuccess,
+		Code:          p.code,
+		ResponseData:  map[string]interface{}{},
+		Time:          time.Now(),
+	}, nil
+}
+
+// deferringPublisher answers every delivery with a redelivery request.
+type deferringPublisher struct {
+	after time.Duration
+	calls int
+}
+
+func (p *deferringPublisher) PublishEvent(ctx context.Context, destination *models.Destination, event *models.Event) (*models.Attempt, error) {
+	p.calls++
+	return &models.Attempt{
+		ID:            idgen.Attempt(),
+		EventID:       event.ID,
+		DestinationID: destination.ID,
+		Status:        models.AttemptStatusDeferred,
+		Code:          "200",
+		ResponseData:  map[string]interface{}{},
+		Time:          time.Now(),
+	}, &destregistry.ErrRedeliveryRequested{Provider: destination.Type, After: p.after}
+}
+
+type registeredAck struct {
+	pending deliveryack.Pending
+	ttl     time.Duration
+}
+
+type mockAckRegistry struct {
+	acks map[string]registeredAck
+	err  error
+}
+
+func newMockAckRegistry() *mockAckRegistry {
+	return &mockAckRegistry{acks: make(map[string]registeredAck)}
+}
+
+func (m *mockAckRegistry) Register(ctx context.Context, token string, pending deliveryack.Pending, ttl time.Duration) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.acks[token] = registeredAck{pending: pending, ttl: ttl}
+	return nil
+}
+
 type mockLogPublisher struct {
 	err         error
 	entries     []models.LogEntry
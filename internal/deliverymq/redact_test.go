@@ -0,0 +1,121 @@
+package deliverymq_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/backoff"
+	"github.com/hookdeck/outpost/internal/deliveryhook"
+	"github.com/hookdeck/outpost/internal/deliverymq"
+	"github.com/hookdeck/outpost/internal/idempotence"
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/redact"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// headerPublisher succeeds with the given response headers.
+type headerPublisher struct {
+	headers map[string]interface{}
+}
+
+func (p *headerPublisher) PublishEvent(ctx context.Context, destination *models.Destination, event *models.Event) (*models.Attempt, error) {
+	return &models.Attempt{
+		ID:            idgen.Attempt(),
+		EventID:       event.ID,
+		DestinationID: destination.ID,
+		Status:        models.AttemptStatusSuccess,
+		Code:          "200",
+		ResponseData: map[string]interface{}{
+			"status_code": 200,
+			"body":        `{"session":{"access_token":"abc","user":"u_1"}}`,
+			"headers":     p.headers,
+		},
+		Time: time.Now(),
+	}, nil
+}
+
+func TestMessageHandler_RedactsLoggedAttempt(t *testing.T) {
+	tenant := models.Tenant{ID: idgen.String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+	)
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID(tenant.ID),
+		testutil.EventFactory.WithDestinationID(destination.ID),
+	)
+
+	redactor, err := redact.New(nil, []string{"$.body..access_token"})
+	require.NoError(t, err)
+	logPublisher := newMockLogPublisher(nil)
+
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		logPublisher,
+		&mockDestinationGetter{dest: &destination},
+		&headerPublisher{headers: map[string]interface{}{
+			"Authorization": "Bearer abc",
+			"Content-Type":  "application/json",
+		}},
+		testutil.NewMockEventTracer(nil),
+		newMockRetryScheduler(),
+		&backoff.ConstantBackoff{Interval: 1 * time.Second},
+		10,
+		idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+		deliverymq.WithRedactor(redactor),
+	)
+
+	_, msg := newDeliveryMockMessage(models.NewDeliveryTask(event, destination.ID))
+	require.NoError(t, handler.Handle(context.Background(), msg))
+
+	require.Len(t, logPublisher.entries, 1)
+	responseData := logPublisher.entries[0].Attempt.ResponseData
+	headers := responseData["headers"].(map[string]interface{})
+	assert.Equal(t, redact.Replacement, headers["Authorization"])
+	assert.Equal(t, "application/json", headers["Content-Type"])
+	assert.JSONEq(t, `{"session":{"access_token":"[REDACTED]","user":"u_1"}}`, responseData["body"].(string))
+	assert.Equal(t, 200, responseData["status_code"])
+}
+
+func TestMessageHandler_RedactsBeforePostDeliveryHooks(t *testing.T) {
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithTenantID("tenant"),
+	)
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID("tenant"),
+		testutil.EventFactory.WithDestinationID(destination.ID),
+	)
+
+	redactor, err := redact.New(nil, nil)
+	require.NoError(t, err)
+	var hookHeaders map[string]interface{}
+	hooks := deliveryhook.Hooks{
+		Post: []deliveryhook.PostDeliveryHook{deliveryhook.PostDeliveryFunc(func(ctx context.Context, d *deliveryhook.Delivery, attempt *models.Attempt) error {
+			hookHeaders = attempt.ResponseData["headers"].(map[string]interface{})
+			return nil
+		})},
+	}
+
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		newMockLogPublisher(nil),
+		&mockDestinationGetter{dest: &destination},
+		&headerPublisher{headers: map[string]interface{}{"Authorization": "Bearer abc"}},
+		testutil.NewMockEventTracer(nil),
+		newMockRetryScheduler(),
+		&backoff.ConstantBackoff{Interval: 1 * time.Second},
+		10,
+		idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+		deliverymq.WithRedactor(redactor),
+		deliverymq.WithDeliveryHooks(hooks),
+	)
+
+	_, msg := newDeliveryMockMessage(models.NewDeliveryTask(event, destination.ID))
+	require.NoError(t, handler.Handle(context.Background(), msg))
+
+	require.NotNil(t, hookHeaders)
+	assert.Equal(t, redact.Replacement, hookHeaders["Authorization"])
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/hookdeck/outpost/internal/clock"
 	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/logstore"
 	"github.com/hookdeck/outpost/internal/models"
@@ -30,6 +31,7 @@ type RetrySchedulerOption func(*retrySchedulerConfig)
 
 type retrySchedulerConfig struct {
 	visibilityTimeout uint
+	clock             clock.Clock
 }
 
 // WithRetryVisibilityTimeout sets the visibility timeout for the retry scheduler queue.
@@ -41,6 +43,15 @@ func WithRetryVisibilityTimeout(vt uint) RetrySchedulerOption {
 	}
 }
 
+// WithRetryClock sets the clock that decides when scheduled retries are due,
+// in place of the Redis server time. Every replica must share it, so it is
+// meant for tests that move time forward instead of waiting out backoffs.
+func WithRetryClock(c clock.Clock) RetrySchedulerOption {
+	return func(cfg *retrySchedulerConfig) {
+		cfg.clock = c
+	}
+}
+
 func NewRetryScheduler(deliverymq *DeliveryMQ, redisConfig *redis.RedisConfig, deploymentID string, pollBackoff time.Duration, logger *logging.Logger, eventGetter RetryEventGetter, opts ...RetrySchedulerOption) (scheduler.Scheduler, error) {
 	cfg := &retrySchedulerConfig{}
 	for _, opt := range opts {
@@ -69,6 +80,9 @@ func NewRetryScheduler(deliverymq *DeliveryMQ, redisConfig *redis.RedisConfig, d
 	} else {
 		rsmqClient = rsmq.NewRedisSMQ(adapter, namespace)
 	}
+	if cfg.clock != nil {
+		rsmqClient.SetClock(cfg.clock)
+	}
 
 	exec := func(ctx context.Context, msg string) error {
 		retryTask := RetryTask{}
@@ -76,6 +90,10 @@ func NewRetryScheduler(deliverymq *DeliveryMQ, redisConfig *redis.RedisConfig, d
 			return err
 		}
 
+		if retryTask.Deferred != nil {
+			return deliverymq.Publish(ctx, *retryTask.Deferred)
+		}
+
 		// Fetch prior attempt from logstore (single source of truth for both
 		// event data and attempt number). A retry always has at least one prior
 		// attempt — the one that failed and triggered this retry.
@@ -124,6 +142,9 @@ func NewRetryScheduler(deliverymq *DeliveryMQ, redisConfig *redis.RedisConfig, d
 	if cfg.visibilityTimeout > 0 {
 		schedulerOpts = append(schedulerOpts, scheduler.WithVisibilityTimeout(cfg.visibilityTimeout))
 	}
+	if cfg.clock != nil {
+		schedulerOpts = append(schedulerOpts, scheduler.WithClock(cfg.clock))
+	}
 	return scheduler.New("deliverymq-retry", rsmqClient, exec, schedulerOpts...), nil
 }
 
@@ -134,6 +155,10 @@ type RetryTask struct {
 	TenantID      string
 	DestinationID string
 	Telemetry     *models.DeliveryTelemetry
+	QueuedAt      time.Time `json:",omitzero"`
+	// Deferred is the whole task of a delivery pushed back by its
+	// destination's caps, published again as is when due.
+	Deferred *models.DeliveryTask `json:",omitempty"`
 }
 
 func (m *RetryTask) ToString() (string, error) {
@@ -154,6 +179,7 @@ func (m *RetryTask) ToDeliveryTask(event models.Event, attemptNumber int) models
 		DestinationID: m.DestinationID,
 		Event:         event,
 		Telemetry:     m.Telemetry,
+		QueuedAt:      m.QueuedAt,
 	}
 }
 
@@ -163,5 +189,6 @@ func RetryTaskFromDeliveryTask(task models.DeliveryTask) RetryTask {
 		TenantID:      task.Event.TenantID,
 		DestinationID: task.DestinationID,
 		Telemetry:     task.Telemetry,
+		QueuedAt:      task.QueuedAt,
 	}
 }
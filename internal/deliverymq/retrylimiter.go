@@ -0,0 +1,94 @@
+package deliverymq
+
+import (
+	"net/url"
+	"sync"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// RetryLimiter bounds how many automatic retries may be in flight at once.
+// It protects delivery capacity during a large consumer outage: without it, a
+// single failing host can accumulate enough scheduled retries to occupy every
+// delivery slot and starve healthy destinations.
+type RetryLimiter interface {
+	// Acquire reserves a retry slot for the given target host. It returns a
+	// release func and true when a slot is available, or false when either the
+	// per-host cap or the global retry budget is exhausted.
+	Acquire(host string) (release func(), ok bool)
+}
+
+// RetryLimiterConfig configures the in-process retry limiter. A zero value for
+// either cap means that dimension is unlimited.
+type RetryLimiterConfig struct {
+	// MaxConcurrencyPerHost caps concurrent retries to a single target host.
+	MaxConcurrencyPerHost int
+	// MaxConcurrency caps concurrent retries across all hosts (the global
+	// retry budget).
+	MaxConcurrency int
+}
+
+type retryLimiter struct {
+	cfg RetryLimiterConfig
+
+	mu       sync.Mutex
+	inFlight int
+	perHost  map[string]int
+}
+
+// NewRetryLimiter returns an in-process RetryLimiter. It returns nil when both
+// caps are zero so callers can skip limiting entirely.
+func NewRetryLimiter(cfg RetryLimiterConfig) RetryLimiter {
+	if cfg.MaxConcurrencyPerHost <= 0 && cfg.MaxConcurrency <= 0 {
+		return nil
+	}
+	return &retryLimiter{
+		cfg:     cfg,
+		perHost: make(map[string]int),
+	}
+}
+
+func (l *retryLimiter) Acquire(host string) (func(), bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.cfg.MaxConcurrency > 0 && l.inFlight >= l.cfg.MaxConcurrency {
+		return nil, false
+	}
+	if l.cfg.MaxConcurrencyPerHost > 0 && l.perHost[host] >= l.cfg.MaxConcurrencyPerHost {
+		return nil, false
+	}
+
+	l.inFlight++
+	l.perHost[host]++
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			l.mu.Lock()
+			defer l.mu.Unlock()
+			l.inFlight--
+			l.perHost[host]--
+			if l.perHost[host] <= 0 {
+				delete(l.perHost, host)
+			}
+		})
+	}, true
+}
+
+// retryTargetHost returns the key used to group retries by target. URL-based
+// destinations (webhook, hookdeck) are grouped by host so that many
+// destinations pointing at the same failing service share one cap. Other
+// destination types fall back to the destination itself.
+func retryTargetHost(destination *models.Destination) string {
+	for _, key := range []string{"url", "endpoint", "queue_url", "server_url"} {
+		raw, ok := destination.Config[key]
+		if !ok || raw == "" {
+			continue
+		}
+		if u, err := url.Parse(raw); err == nil && u.Host != "" {
+			return u.Host
+		}
+	}
+	return destination.Type + ":" + destination.ID
+}
@@ -0,0 +1,126 @@
+package deliverymq_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/backoff"
+	"github.com/hookdeck/outpost/internal/deliverymq"
+	"github.com/hookdeck/outpost/internal/idempotence"
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetryLimiter_Disabled(t *testing.T) {
+	assert.Nil(t, deliverymq.NewRetryLimiter(deliverymq.RetryLimiterConfig{}))
+}
+
+func TestRetryLimiter_PerHostCap(t *testing.T) {
+	limiter := deliverymq.NewRetryLimiter(deliverymq.RetryLimiterConfig{MaxConcurrencyPerHost: 1})
+
+	release, ok := limiter.Acquire("a.example.com")
+	require.True(t, ok)
+
+	_, ok = limiter.Acquire("a.example.com")
+	assert.False(t, ok, "second retry to the same host should be rejected")
+
+	releaseB, ok := limiter.Acquire("b.example.com")
+	assert.True(t, ok, "other hosts should not be affected")
+	releaseB()
+
+	release()
+	release() // release is idempotent
+	_, ok = limiter.Acquire("a.example.com")
+	assert.True(t, ok, "slot should be available after release")
+}
+
+func TestRetryLimiter_GlobalBudget(t *testing.T) {
+	limiter := deliverymq.NewRetryLimiter(deliverymq.RetryLimiterConfig{MaxConcurrency: 2})
+
+	_, ok := limiter.Acquire("a.example.com")
+	require.True(t, ok)
+	release, ok := limiter.Acquire("b.example.com")
+	require.True(t, ok)
+
+	_, ok = limiter.Acquire("c.example.com")
+	assert.False(t, ok, "global budget should be exhausted")
+
+	release()
+	_, ok = limiter.Acquire("c.example.com")
+	assert.True(t, ok)
+}
+
+func TestMessageHandler_RetryLimiterDefersRetry(t *testing.T) {
+	// Test scenario:
+	// - Per-host retry cap is already saturated for the destination's host
+	// - An automatic retry arrives for that host
+	// - The retry is rescheduled instead of attempted, and the message is acked
+
+	tenant := models.Tenant{ID: idgen.String()}
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+		testutil.DestinationFactory.WithConfig(map[string]string{"url": "https://failing.example.com/webhook"}),
+	)
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTenantID(tenant.ID),
+		testutil.EventFactory.WithDestinationID(destination.ID),
+	)
+
+	limiter := deliverymq.NewRetryLimiter(deliverymq.RetryLimiterConfig{MaxConcurrencyPerHost: 1})
+	_, ok := limiter.Acquire("failing.example.com")
+	require.True(t, ok)
+
+	destGetter := &mockDestinationGetter{dest: &destination}
+	retryScheduler := newMockRetryScheduler()
+	publisher := newMockPublisher(nil)
+	logPublisher := newMockLogPublisher(nil)
+
+	handler := deliverymq.NewMessageHandler(
+		testutil.CreateTestLogger(t),
+		logPublisher,
+		destGetter,
+		publisher,
+		testutil.NewMockEventTracer(nil),
+		retryScheduler,
+		&backoff.ConstantBackoff{Interval: 1 * time.Second},
+		10,
+		idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+		deliverymq.WithRetryLimiter(limiter),
+	)
+
+	task := models.DeliveryTask{
+		Attempt:       2,
+		DestinationID: destination.ID,
+		Event:         event,
+	}
+	mockMsg, msg := newDeliveryMockMessage(task)
+
+	err := handler.Handle(context.Background(), msg)
+	require.NoError(t, err)
+
+	assert.True(t, mockMsg.acked, "deferred retry should be acked")
+	assert.False(t, mockMsg.nacked)
+	assert.Equal(t, 0, publisher.Current(), "deferred retry should not be attempted")
+	assert.Empty(t, logPublisher.entries, "deferred retry should not be logged as an attempt")
+	require.Len(t, retryScheduler.taskIDs, 1)
+	assert.Equal(t, models.RetryID(event.ID, destination.ID), retryScheduler.taskIDs[0])
+
+	// First attempts and manual retries bypass the limiter.
+	task = models.NewDeliveryTask(event, destination.ID)
+	mockMsg, msg = newDeliveryMockMessage(task)
+	require.NoError(t, handler.Handle(context.Background(), msg))
+	assert.True(t, mockMsg.acked)
+	assert.Equal(t, 1, publisher.Current())
+
+	// Retries enqueued by a bulk retry job are limited like automatic ones.
+	task = models.NewBulkDeliveryTask(event, destination.ID, 3)
+	mockMsg, msg = newDeliveryMockMessage(task)
+	require.NoError(t, handler.Handle(context.Background(), msg))
+	assert.True(t, mockMsg.acked)
+	assert.Equal(t, 1, publisher.Current(), "deferred bulk retry should not be attempted")
+	assert.Len(t, retryScheduler.taskIDs, 2)
+}
@@ -0,0 +1,127 @@
+package deliverymq
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/models"
+	"golang.org/x/sync/singleflight"
+)
+
+// DefaultSandboxCacheTTL is how long a worker caches a tenant's sandbox mode,
+// and so how long turning it on or off takes to apply to deliveries.
+const DefaultSandboxCacheTTL = 10 * time.Second
+
+// WithSandboxCacheTTL sets how long a tenant's sandbox mode is cached. 0
+// retrieves the tenant on every delivery.
+func WithSandboxCacheTTL(ttl time.Duration) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.sandboxCacheTTL = ttl
+	}
+}
+
+// isSandboxed reports whether the delivery must be recorded without contacting
+// the destination. A task is sandboxed when its tenant is in sandbox mode and
+// the destination has not been flagged as sandbox-safe.
+func (h *messageHandler) isSandboxed(ctx context.Context, task models.DeliveryTask, destination *models.Destination) (bool, error) {
+	if h.tenantGetter == nil || destination.SandboxSafe {
+		return false, nil
+	}
+	return h.sandboxes.get(ctx, task.Event.TenantID, func(ctx context.Context) (bool, error) {
+		tenant, err := h.tenantGetter.RetrieveTenant(ctx, task.Event.TenantID)
+		if err != nil {
+			return false, err
+		}
+		return tenant != nil && tenant.Sandbox, nil
+	})
+}
+
+// sandboxCache caches the sandbox mode of tenants, so that deliveries don't
+// retrieve their tenant each time. Concurrent misses for a tenant share one
+// retrieval.
+type sandboxCache struct {
+	ttl time.Duration
+	now func() time.Time
+
+	group     singleflight.Group
+	mu        sync.Mutex
+	entries   map[string]sandboxEntry
+	lastSweep time.Time
+}
+
+type sandboxEntry struct {
+	sandbox bool
+	expires time.Time
+}
+
+func newSandboxCache(ttl time.Duration) *sandboxCache {
+	return &sandboxCache{
+		ttl:     ttl,
+		now:     time.Now,
+		entries: make(map[string]sandboxEntry),
+	}
+}
+
+func (c *sandboxCache) get(ctx context.Context, tenantID string, retrieve func(context.Context) (bool, error)) (bool, error) {
+	if c.ttl <= 0 {
+		return retrieve(ctx)
+	}
+	c.mu.Lock()
+	cached, ok := c.entries[tenantID]
+	c.mu.Unlock()
+	if ok && c.now().Before(cached.expires) {
+		return cached.sandbox, nil
+	}
+
+	sandbox, err, _ := c.group.Do(tenantID, func() (any, error) {
+		sandbox, err := retrieve(ctx)
+		if err != nil {
+			return false, err
+		}
+		c.store(tenantID, sandbox)
+		return sandbox, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return sandbox.(bool), nil
+}
+
+func (c *sandboxCache) store(tenantID string, sandbox bool) {
+	now := c.now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[tenantID] = sandboxEntry{sandbox: sandbox, expires: now.Add(c.ttl)}
+	// Drop expired entries at most once per TTL, so tenants no longer
+	// delivered to don't accumulate.
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+	for id, entry := range c.entries {
+		if !now.Before(entry.expires) {
+			delete(c.entries, id)
+		}
+	}
+}
+
+// newSandboxAttempt builds the attempt recorded in place of a real delivery for
+// a sandbox tenant. It is marked with AttemptCodeSandbox so delivery records
+// make it clear that nothing left the system.
+func newSandboxAttempt(destination *models.Destination, event *models.Event) *models.Attempt {
+	return &models.Attempt{
+		ID:              idgen.Attempt(),
+		DestinationID:   destination.ID,
+		DestinationType: destination.Type,
+		EventID:         event.ID,
+		Time:            time.Now(),
+		Status:          models.AttemptStatusSuccess,
+		Code:            models.AttemptCodeSandbox,
+		ResponseData: map[string]interface{}{
+			"sandbox": true,
+			"message": "tenant is in sandbox mode; no external delivery was made",
+		},
+	}
+}
@@ -0,0 +1,136 @@
+package deliverymq_test
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/backoff"
+	"github.com/hookdeck/outpost/internal/consumer"
+	"github.com/hookdeck/outpost/internal/deliverymq"
+	"github.com/hookdeck/outpost/internal/idempotence"
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockTenantGetter struct {
+	tenant *models.Tenant
+	calls  atomic.Int32
+}
+
+func (m *mockTenantGetter) RetrieveTenant(ctx context.Context, tenantID string) (*models.Tenant, error) {
+	m.calls.Add(1)
+	return m.tenant, nil
+}
+
+func TestMessageHandler_Sandbox(t *testing.T) {
+	tenant := models.Tenant{ID: idgen.String(), Sandbox: true}
+
+	newHandler := func(t *testing.T, destination *models.Destination, publisher *mockPublisher, logPublisher *mockLogPublisher, opts ...deliverymq.MessageHandlerOption) consumer.MessageHandler {
+		t.Helper()
+		return deliverymq.NewMessageHandler(
+			testutil.CreateTestLogger(t),
+			logPublisher,
+			&mockDestinationGetter{dest: destination},
+			publisher,
+			testutil.NewMockEventTracer(nil),
+			newMockRetryScheduler(),
+			&backoff.ConstantBackoff{Interval: 1 * time.Second},
+			10,
+			idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+			append([]deliverymq.MessageHandlerOption{deliverymq.WithTenantGetter(&mockTenantGetter{tenant: &tenant})}, opts...)...,
+		)
+	}
+
+	t.Run("records sandbox attempt without delivering", func(t *testing.T) {
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithTenantID(tenant.ID),
+		)
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithDestinationID(destination.ID),
+		)
+		publisher := newMockPublisher(nil)
+		logPublisher := newMockLogPublisher(nil)
+		handler := newHandler(t, &destination, publisher, logPublisher)
+
+		mockMsg, msg := newDeliveryMockMessage(models.NewDeliveryTask(event, destination.ID))
+		require.NoError(t, handler.Handle(context.Background(), msg))
+
+		assert.True(t, mockMsg.acked)
+		assert.Equal(t, 0, publisher.Current(), "sandbox tenant should not publish externally")
+		require.Len(t, logPublisher.entries, 1)
+		attempt := logPublisher.entries[0].Attempt
+		assert.Equal(t, models.AttemptStatusSuccess, attempt.Status)
+		assert.Equal(t, models.AttemptCodeSandbox, attempt.Code)
+		assert.Equal(t, true, attempt.ResponseData["sandbox"])
+	})
+
+	t.Run("delivers to sandbox-safe destination", func(t *testing.T) {
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithTenantID(tenant.ID),
+		)
+		destination.SandboxSafe = true
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithDestinationID(destination.ID),
+		)
+		publisher := newMockPublisher(nil)
+		logPublisher := newMockLogPublisher(nil)
+		handler := newHandler(t, &destination, publisher, logPublisher)
+
+		mockMsg, msg := newDeliveryMockMessage(models.NewDeliveryTask(event, destination.ID))
+		require.NoError(t, handler.Handle(context.Background(), msg))
+
+		assert.True(t, mockMsg.acked)
+		assert.Equal(t, 1, publisher.Current())
+		require.Len(t, logPublisher.entries, 1)
+		assert.NotEqual(t, models.AttemptCodeSandbox, logPublisher.entries[0].Attempt.Code)
+	})
+	t.Run("caches the tenant's sandbox mode", func(t *testing.T) {
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithTenantID(tenant.ID),
+		)
+		deliver := func(t *testing.T, handler consumer.MessageHandler) {
+			t.Helper()
+			event := testutil.EventFactory.Any(
+				testutil.EventFactory.WithTenantID(tenant.ID),
+				testutil.EventFactory.WithDestinationID(destination.ID),
+			)
+			_, msg := newDeliveryMockMessage(models.NewDeliveryTask(event, destination.ID))
+			require.NoError(t, handler.Handle(context.Background(), msg))
+		}
+
+		for _, tc := range []struct {
+			name  string
+			ttl   time.Duration
+			calls int32
+		}{
+			{name: "within the ttl", ttl: deliverymq.DefaultSandboxCacheTTL, calls: 1},
+			{name: "disabled", ttl: 0, calls: 3},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				tenantGetter := &mockTenantGetter{tenant: &tenant}
+				logPublisher := newMockLogPublisher(nil)
+				handler := newHandler(t, &destination, newMockPublisher(nil), logPublisher,
+					deliverymq.WithTenantGetter(tenantGetter),
+					deliverymq.WithSandboxCacheTTL(tc.ttl),
+				)
+
+				for range 3 {
+					deliver(t, handler)
+				}
+
+				assert.Equal(t, tc.calls, tenantGetter.calls.Load())
+				require.Len(t, logPublisher.entries, 3)
+				for _, entry := range logPublisher.entries {
+					assert.Equal(t, models.AttemptCodeSandbox, entry.Attempt.Code)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package deliverymq
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/idempotence"
+	"github.com/hookdeck/outpost/internal/models"
+	"go.uber.org/zap"
+)
+
+// DefaultShadowConcurrency is how many shadow deliveries a handler runs at
+// once unless configured with WithShadowConcurrency.
+const DefaultShadowConcurrency = 16
+
+// WithShadowConcurrency caps how many shadow deliveries run at once. Mirrors
+// beyond the cap are dropped and counted in outpost.shadow.dropped rather
+// than queued, so a slow shadow can't build up goroutines or hold deliveries.
+func WithShadowConcurrency(n int) MessageHandlerOption {
+	return func(h *messageHandler) {
+		if n > 0 {
+			h.shadowConcurrency = n
+		}
+	}
+}
+
+// shadowIdempotencyKey identifies the mirror of an event to a shadow
+// destination, so it is sent at most once whatever happens to the primary
+// delivery task.
+func shadowIdempotencyKey(eventID, shadowID string) string {
+	return "idempotency:deliverymq:shadow:" + eventID + ":" + shadowID
+}
+
+// mirrorToShadow sends a fire-and-forget copy of the event to the
+// destination's shadow destination, if one is configured. Shadow deliveries
+// run in the background, are never retried, and are not written to the
+// logstore, so they cannot affect the primary destination's status, retries,
+// or alerts. The shadow receives traffic regardless of its own disabled state,
+// which lets a consumer keep it disabled for regular matching while it is
+// being validated.
+//
+// Only the first automatic attempt is mirrored, and each event is mirrored to
+// a shadow at most once, so a redelivered first attempt doesn't reach the
+// shadow again. sandboxed is the primary delivery's sandbox decision, which
+// the shadow shares unless the tenant can't be told from it.
+func (h *messageHandler) mirrorToShadow(ctx context.Context, task models.DeliveryTask, destination *models.Destination, sandboxed bool) {
+	if destination.ShadowDestinationID == "" || task.Attempt > 1 || task.Manual {
+		return
+	}
+
+	fields := []zap.Field{
+		zap.String("event_id", task.Event.ID),
+		zap.String("tenant_id", task.Event.TenantID),
+		zap.String("destination_id", destination.ID),
+		zap.String("shadow_destination_id", destination.ShadowDestinationID),
+	}
+	select {
+	case h.shadowSlots <- struct{}{}:
+	default:
+		h.shadowDropped.Add(ctx, 1)
+		h.logger.Ctx(ctx).Warn("shadow delivery dropped: too many in flight", fields...)
+		return
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	go func() {
+		defer func() { <-h.shadowSlots }()
+		logger := h.logger.Ctx(ctx)
+
+		key := shadowIdempotencyKey(task.Event.ID, destination.ShadowDestinationID)
+		executed := false
+		err := h.idempotence.Exec(ctx, key, func(ctx context.Context) error {
+			executed = true
+			h.sendShadow(ctx, task, destination, sandboxed, fields)
+			return nil
+		})
+		switch {
+		case errors.Is(err, idempotence.ErrConflict) || (err == nil && !executed):
+			logger.Debug("shadow delivery skipped (idempotent)", fields...)
+		case err != nil:
+			logger.Warn("failed to deduplicate shadow delivery", append(fields, zap.Error(err))...)
+		}
+	}()
+}
+
+// sendShadow delivers the event to the shadow destination and logs the
+// outcome. Failures are only logged: the mirror is never retried.
+func (h *messageHandler) sendShadow(ctx context.Context, task models.DeliveryTask, destination *models.Destination, primarySandboxed bool, fields []zap.Field) {
+	logger := h.logger.Ctx(ctx)
+
+	shadow, err := h.tenantStore.RetrieveDestination(ctx, task.Event.TenantID, destination.ShadowDestinationID)
+	if err != nil || shadow == nil {
+		logger.Warn("failed to resolve shadow destination", append(fields, zap.Error(err))...)
+		return
+	}
+	sandboxed, err := h.shadowSandboxed(ctx, task, destination, shadow, primarySandboxed)
+	if err != nil {
+		logger.Warn("failed to resolve tenant for shadow delivery", append(fields, zap.Error(err))...)
+		return
+	}
+	if sandboxed {
+		return
+	}
+
+	start := time.Now()
+	attempt, err := h.publisher.PublishEvent(ctx, shadow, &task.Event)
+	fields = append(fields,
+		zap.String("shadow_destination_type", shadow.Type),
+		zap.Int64("attempt_duration_ms", time.Since(start).Milliseconds()))
+	if attempt != nil {
+		fields = append(fields,
+			zap.String("attempt_status", attempt.Status),
+			zap.String("attempt_code", attempt.Code))
+	}
+	if err != nil {
+		fields = append(fields, zap.Error(err))
+	}
+	logger.Info("delivery.shadow_attempted", fields...)
+}
+
+// shadowSandboxed reports whether the shadow delivery must be skipped for a
+// sandbox tenant. The shadow belongs to the primary's tenant, so the primary's
+// decision answers it, except when the primary is sandbox-safe and its
+// decision says nothing about the tenant.
+func (h *messageHandler) shadowSandboxed(ctx context.Context, task models.DeliveryTask, primary, shadow *models.Destination, primarySandboxed bool) (bool, error) {
+	if shadow.SandboxSafe {
+		return false, nil
+	}
+	if !primary.SandboxSafe {
+		return primarySandboxed, nil
+	}
+	return h.isSandboxed(ctx, task, shadow)
+}
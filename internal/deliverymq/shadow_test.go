@@ -0,0 +1,110 @@
+package deliverymq_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/backoff"
+	"github.com/hookdeck/outpost/internal/consumer"
+	"github.com/hookdeck/outpost/internal/deliverymq"
+	"github.com/hookdeck/outpost/internal/idempotence"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageHandler_ShadowDestination(t *testing.T) {
+	setup := func(t *testing.T) (*mockMultiDestinationGetter, *mockPublisher, *mockLogPublisher, *models.Destination, *models.Destination) {
+		t.Helper()
+		shadow := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithTenantID("tenant"),
+		)
+		primary := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithTenantID("tenant"),
+		)
+		primary.ShadowDestinationID = shadow.ID
+
+		destGetter := newMockMultiDestinationGetter()
+		destGetter.registerDestination(&primary)
+		destGetter.registerDestination(&shadow)
+		return destGetter, newMockPublisher(nil), newMockLogPublisher(nil), &primary, &shadow
+	}
+
+	newHandlerWithRedis := func(t *testing.T, redisClient redis.Client, destGetter *mockMultiDestinationGetter, publisher *mockPublisher, logPublisher *mockLogPublisher) consumer.MessageHandler {
+		t.Helper()
+		return deliverymq.NewMessageHandler(
+			testutil.CreateTestLogger(t),
+			logPublisher,
+			destGetter,
+			publisher,
+			testutil.NewMockEventTracer(nil),
+			newMockRetryScheduler(),
+			&backoff.ConstantBackoff{Interval: 1 * time.Second},
+			10,
+			idempotence.New(redisClient, idempotence.WithSuccessfulTTL(24*time.Hour)),
+		)
+	}
+	newHandler := func(t *testing.T, destGetter *mockMultiDestinationGetter, publisher *mockPublisher, logPublisher *mockLogPublisher) consumer.MessageHandler {
+		t.Helper()
+		return newHandlerWithRedis(t, testutil.CreateTestRedisClient(t), destGetter, publisher, logPublisher)
+	}
+
+	t.Run("mirrors first attempt to shadow", func(t *testing.T) {
+		destGetter, publisher, logPublisher, primary, _ := setup(t)
+		handler := newHandler(t, destGetter, publisher, logPublisher)
+
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID("tenant"),
+			testutil.EventFactory.WithDestinationID(primary.ID),
+		)
+		mockMsg, msg := newDeliveryMockMessage(models.NewDeliveryTask(event, primary.ID))
+		require.NoError(t, handler.Handle(context.Background(), msg))
+
+		assert.True(t, mockMsg.acked)
+		assert.Eventually(t, func() bool { return publisher.Current() == 2 }, time.Second, 10*time.Millisecond)
+		require.Len(t, logPublisher.entries, 1, "shadow delivery should not be logged")
+		assert.Equal(t, primary.ID, logPublisher.entries[0].Attempt.DestinationID)
+	})
+
+	t.Run("does not mirror retries", func(t *testing.T) {
+		destGetter, publisher, logPublisher, primary, _ := setup(t)
+		handler := newHandler(t, destGetter, publisher, logPublisher)
+
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID("tenant"),
+			testutil.EventFactory.WithDestinationID(primary.ID),
+		)
+		task := models.NewDeliveryTask(event, primary.ID)
+		task.Attempt = 2
+		_, msg := newDeliveryMockMessage(task)
+		require.NoError(t, handler.Handle(context.Background(), msg))
+
+		assert.Never(t, func() bool { return publisher.Current() > 1 }, 100*time.Millisecond, 10*time.Millisecond)
+	})
+	t.Run("mirrors a redelivered first attempt once", func(t *testing.T) {
+		destGetter, publisher, logPublisher, primary, _ := setup(t)
+		redisClient := testutil.CreateTestRedisClient(t)
+		handler := newHandlerWithRedis(t, redisClient, destGetter, publisher, logPublisher)
+
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID("tenant"),
+			testutil.EventFactory.WithDestinationID(primary.ID),
+		)
+		task := models.NewDeliveryTask(event, primary.ID)
+		_, msg := newDeliveryMockMessage(task)
+		require.NoError(t, handler.Handle(context.Background(), msg))
+		assert.Eventually(t, func() bool { return publisher.Current() == 2 }, time.Second, 10*time.Millisecond)
+
+		// Release the primary's idempotency key, as a failed first attempt
+		// does, so the redelivered task is handled again.
+		require.NoError(t, redisClient.Del(context.Background(), "idempotency:deliverymq:"+task.IdempotencyKey()).Err())
+		_, msg = newDeliveryMockMessage(task)
+		require.NoError(t, handler.Handle(context.Background(), msg))
+
+		assert.Eventually(t, func() bool { return publisher.Current() == 3 }, time.Second, 10*time.Millisecond)
+		assert.Never(t, func() bool { return publisher.Current() > 3 }, 100*time.Millisecond, 10*time.Millisecond)
+	})
+}
@@ -0,0 +1,121 @@
+package deliverymq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"go.uber.org/zap"
+)
+
+// errDeliveryHeld reports a task held until its destination's new target is
+// confirmed. Like a disabled destination, it is acked without an attempt.
+var errDeliveryHeld = errors.New("delivery held")
+
+// HeldTaskStore holds the tasks queued before their destination's target
+// changed, until they are released or discarded.
+type HeldTaskStore interface {
+	Hold(ctx context.Context, task models.DeliveryTask) error
+}
+
+// DestinationVersionLister lists the saved versions of a destination, newest
+// first.
+type DestinationVersionLister interface {
+	ListDestinationVersion(ctx context.Context, tenantID, destinationID string) ([]tenantstore.DestinationVersion, error)
+}
+
+// WithHeldTasks holds the tasks queued before their destination's target
+// changed, when the destination asked for them to be held. Without it, they
+// are delivered to the new target.
+func WithHeldTasks(held HeldTaskStore) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.held = held
+	}
+}
+
+// WithDestinationVersions delivers the tasks queued before their
+// destination's target changed to the previous target, when the destination
+// asked for it, using the destination as saved when the task was queued.
+// Without it, or once that version is no longer retained, they are held.
+func WithDestinationVersions(versions DestinationVersionLister) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.versions = versions
+	}
+}
+
+// routeQueuedTask returns the destination a task is delivered to. A task
+// queued before its destination's target changed goes where the destination's
+// TargetChange says: to the new target, to the previous one, or nowhere for
+// now, in which case it is held and errDeliveryHeld is returned.
+func (h *messageHandler) routeQueuedTask(ctx context.Context, task models.DeliveryTask, destination *models.Destination) (*models.Destination, error) {
+	change := destination.TargetChange
+	if !change.Precedes(&task) {
+		return destination, nil
+	}
+	logger := h.logger.Ctx(ctx)
+	fields := []zap.Field{
+		zap.String("event_id", task.Event.ID),
+		zap.String("tenant_id", task.Event.TenantID),
+		zap.String("destination_id", destination.ID),
+		zap.Time("queued_at", task.QueuedAt),
+		zap.Time("target_changed_at", change.ChangedAt),
+	}
+
+	switch change.QueuedMessages {
+	case models.QueuedMessagesPreviousTarget:
+		previous, err := h.previousTarget(ctx, task, destination)
+		if err != nil {
+			return nil, err
+		}
+		if previous != nil {
+			logger.Info("delivering queued task to previous target", fields...)
+			return previous, nil
+		}
+		logger.Warn("previous target of queued task not retained, holding it", fields...)
+		return h.hold(ctx, task, destination, fields)
+	case models.QueuedMessagesHold:
+		return h.hold(ctx, task, destination, fields)
+	}
+	return destination, nil
+}
+
+// previousTarget returns the destination as it was when the task was queued,
+// or nil when that version is not retained.
+func (h *messageHandler) previousTarget(ctx context.Context, task models.DeliveryTask, destination *models.Destination) (*models.Destination, error) {
+	if h.versions == nil {
+		return nil, nil
+	}
+	versions, err := h.versions.ListDestinationVersion(ctx, destination.TenantID, destination.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list destination versions: %w", err)
+	}
+	// Newest first: the first version not updated after the task was queued
+	// is the one it was queued for.
+	for _, version := range versions {
+		if version.Destination.UpdatedAt.After(task.QueuedAt) {
+			continue
+		}
+		previous := *destination
+		previous.Config = version.Destination.Config
+		previous.Credentials = version.Destination.Credentials
+		return &previous, nil
+	}
+	return nil, nil
+}
+
+// hold holds a task queued before its destination's target changed. Without
+// a store to hold it in, it is delivered to the new target.
+func (h *messageHandler) hold(ctx context.Context, task models.DeliveryTask, destination *models.Destination, fields []zap.Field) (*models.Destination, error) {
+	logger := h.logger.Ctx(ctx)
+	if h.held == nil {
+		logger.Warn("queued task can't be held, delivering it to new target", fields...)
+		return destination, nil
+	}
+	if err := h.held.Hold(ctx, task); err != nil {
+		return nil, fmt.Errorf("failed to hold queued task: %w", err)
+	}
+	logger.Info("held queued task until target change is confirmed", fields...)
+	return nil, errDeliveryHeld
+}
@@ -0,0 +1,165 @@
+package deliverymq_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/backoff"
+	"github.com/hookdeck/outpost/internal/consumer"
+	"github.com/hookdeck/outpost/internal/deliverymq"
+	"github.com/hookdeck/outpost/internal/idempotence"
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// urlRecordingPublisher records the URL of every destination it publishes to.
+type urlRecordingPublisher struct {
+	mu   sync.Mutex
+	urls []string
+}
+
+func (p *urlRecordingPublisher) PublishEvent(ctx context.Context, destination *models.Destination, event *models.Event) (*models.Attempt, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.urls = append(p.urls, destination.Config["url"])
+	return &models.Attempt{
+		ID:            idgen.Attempt(),
+		EventID:       event.ID,
+		DestinationID: destination.ID,
+		Status:        models.AttemptStatusSuccess,
+		Time:          time.Now(),
+	}, nil
+}
+
+type mockHeldTasks struct {
+	held []models.DeliveryTask
+}
+
+func (m *mockHeldTasks) Hold(ctx context.Context, task models.DeliveryTask) error {
+	m.held = append(m.held, task)
+	return nil
+}
+
+type mockVersionLister struct {
+	versions []tenantstore.DestinationVersion
+}
+
+func (m *mockVersionLister) ListDestinationVersion(ctx context.Context, tenantID, destinationID string) ([]tenantstore.DestinationVersion, error) {
+	return m.versions, nil
+}
+
+func TestMessageHandler_TargetChange(t *testing.T) {
+	changedAt := time.Now()
+	queuedBefore := changedAt.Add(-time.Minute)
+
+	type fixture struct {
+		handler   consumer.MessageHandler
+		publisher *urlRecordingPublisher
+		held      *mockHeldTasks
+		task      models.DeliveryTask
+	}
+	newFixture := func(t *testing.T, queuedMessages string, versions []tenantstore.DestinationVersion) *fixture {
+		t.Helper()
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithConfig(map[string]string{"url": "https://new.example.com"}),
+		)
+		destination.UpdatedAt = changedAt
+		destination.TargetChange = &models.TargetChange{ChangedAt: changedAt, QueuedMessages: queuedMessages}
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithTenantID(destination.TenantID),
+			testutil.EventFactory.WithDestinationID(destination.ID),
+		)
+		f := &fixture{
+			publisher: &urlRecordingPublisher{},
+			held:      &mockHeldTasks{},
+			task:      models.NewDeliveryTask(event, destination.ID),
+		}
+		f.task.QueuedAt = queuedBefore
+		f.handler = deliverymq.NewMessageHandler(
+			testutil.CreateTestLogger(t),
+			newMockLogPublisher(nil),
+			&mockDestinationGetter{dest: &destination},
+			f.publisher,
+			testutil.NewMockEventTracer(nil),
+			newMockRetryScheduler(),
+			&backoff.ConstantBackoff{Interval: 1 * time.Second},
+			10,
+			idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+			deliverymq.WithHeldTasks(f.held),
+			deliverymq.WithDestinationVersions(&mockVersionLister{versions: versions}),
+		)
+		return f
+	}
+	previousVersions := func() []tenantstore.DestinationVersion {
+		newer := testutil.DestinationFactory.Any(testutil.DestinationFactory.WithConfig(map[string]string{"url": "https://new.example.com"}))
+		newer.UpdatedAt = changedAt
+		older := testutil.DestinationFactory.Any(testutil.DestinationFactory.WithConfig(map[string]string{"url": "https://old.example.com"}))
+		older.UpdatedAt = changedAt.Add(-time.Hour)
+		return []tenantstore.DestinationVersion{
+			{Version: 2, Destination: newer},
+			{Version: 1, Destination: older},
+		}
+	}
+
+	t.Run("new target", func(t *testing.T) {
+		f := newFixture(t, models.QueuedMessagesNewTarget, previousVersions())
+
+		mockMsg, msg := newDeliveryMockMessage(f.task)
+		require.NoError(t, f.handler.Handle(context.Background(), msg))
+
+		assert.True(t, mockMsg.acked)
+		assert.Equal(t, []string{"https://new.example.com"}, f.publisher.urls)
+	})
+
+	t.Run("previous target", func(t *testing.T) {
+		f := newFixture(t, models.QueuedMessagesPreviousTarget, previousVersions())
+
+		mockMsg, msg := newDeliveryMockMessage(f.task)
+		require.NoError(t, f.handler.Handle(context.Background(), msg))
+
+		assert.True(t, mockMsg.acked)
+		assert.Equal(t, []string{"https://old.example.com"}, f.publisher.urls)
+	})
+
+	t.Run("previous target no longer retained is held", func(t *testing.T) {
+		f := newFixture(t, models.QueuedMessagesPreviousTarget, previousVersions()[:1])
+
+		mockMsg, msg := newDeliveryMockMessage(f.task)
+		require.NoError(t, f.handler.Handle(context.Background(), msg))
+
+		assert.True(t, mockMsg.acked)
+		assert.Empty(t, f.publisher.urls)
+		require.Len(t, f.held.held, 1)
+	})
+
+	t.Run("hold", func(t *testing.T) {
+		f := newFixture(t, models.QueuedMessagesHold, nil)
+
+		mockMsg, msg := newDeliveryMockMessage(f.task)
+		require.NoError(t, f.handler.Handle(context.Background(), msg))
+
+		assert.True(t, mockMsg.acked)
+		assert.False(t, mockMsg.nacked)
+		assert.Empty(t, f.publisher.urls)
+		require.Len(t, f.held.held, 1)
+		assert.Equal(t, f.task.Event.ID, f.held.held[0].Event.ID)
+	})
+
+	t.Run("tasks queued after the change are delivered", func(t *testing.T) {
+		f := newFixture(t, models.QueuedMessagesHold, nil)
+		f.task.QueuedAt = changedAt.Add(time.Second)
+
+		mockMsg, msg := newDeliveryMockMessage(f.task)
+		require.NoError(t, f.handler.Handle(context.Background(), msg))
+
+		assert.True(t, mockMsg.acked)
+		assert.Equal(t, []string{"https://new.example.com"}, f.publisher.urls)
+		assert.Empty(t, f.held.held)
+	})
+}
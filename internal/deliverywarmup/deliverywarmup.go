@@ -0,0 +1,181 @@
+// Package deliverywarmup preloads the delivery publishers of recently active
+// tenants when a delivery worker starts.
+//
+// Creating a publisher can mean building an SDK client or opening a
+// connection, so a freshly deployed worker delivers its first events slower
+// than one with a warm publisher cache. Delivery workers record tenant
+// activity in Redis while they deliver; on startup, a worker resolves the
+// publishers of the most recently active tenants' destinations before traffic
+// reaches them.
+package deliverywarmup
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/lru"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	// touchInterval is how often a worker records the same tenant's activity.
+	// Deliveries in between are not recorded, keeping the Redis cost per
+	// tenant constant regardless of its volume.
+	touchInterval = time.Minute
+	// maxTracked is the number of tenants kept in the activity set. Older
+	// entries are trimmed as new ones are recorded.
+	maxTracked = 10000
+	// activityWindow is how recent activity must be to count towards warm-up.
+	activityWindow = 24 * time.Hour
+	// warmConcurrency is the number of tenants warmed in parallel.
+	warmConcurrency = 8
+)
+
+// Activity records which tenants received deliveries recently.
+type Activity interface {
+	// Touch records a delivery for the tenant.
+	Touch(ctx context.Context, tenantID string) error
+	// Recent returns up to limit tenants with activity within the window,
+	// most recently active first.
+	Recent(ctx context.Context, limit int) ([]string, error)
+}
+
+type redisActivity struct {
+	redisClient  redis.Cmdable
+	deploymentID string
+	touched      *lru.Cache[string, time.Time]
+	clock        clock.Clock
+}
+
+type Option func(*redisActivity)
+
+func WithDeploymentID(deploymentID string) Option {
+	return func(a *redisActivity) {
+		a.deploymentID = deploymentID
+	}
+}
+
+func WithClock(c clock.Clock) Option {
+	return func(a *redisActivity) {
+		a.clock = c
+	}
+}
+
+// NewActivity returns an Activity backed by a Redis sorted set scored by the
+// time of each tenant's last recorded delivery.
+func NewActivity(redisClient redis.Cmdable, opts ...Option) Activity {
+	a := &redisActivity{
+		redisClient: redisClient,
+		touched:     lru.New[string, time.Time](maxTracked, 0, nil),
+		clock:       clock.New(),
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+func (a *redisActivity) key() string {
+	if a.deploymentID == "" {
+		return "deliverywarmup:tenants"
+	}
+	return a.deploymentID + ":deliverywarmup:tenants"
+}
+
+func (a *redisActivity) Touch(ctx context.Context, tenantID string) error {
+	now := a.clock.Now()
+	if last, ok := a.touched.Get(tenantID); ok && now.Sub(last) < touchInterval {
+		return nil
+	}
+	a.touched.Add(tenantID, now)
+
+	pipe := a.redisClient.TxPipeline()
+	pipe.ZAdd(ctx, a.key(), redis.Z{Score: float64(now.Unix()), Member: tenantID})
+	pipe.ZRemRangeByRank(ctx, a.key(), 0, -maxTracked-1)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record tenant activity: %w", err)
+	}
+	return nil
+}
+
+func (a *redisActivity) Recent(ctx context.Context, limit int) ([]string, error) {
+	if limit <= 0 {
+		return nil, nil
+	}
+	since := a.clock.Now().Add(-activityWindow).Unix()
+	tenantIDs, err := a.redisClient.ZRevRangeByScore(ctx, a.key(), &redis.ZRangeBy{
+		Min:   strconv.FormatInt(since, 10),
+		Max:   "+inf",
+		Count: int64(limit),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tenant activity: %w", err)
+	}
+	return tenantIDs, nil
+}
+
+type destinationLister interface {
+	ListDestination(ctx context.Context, req tenantstore.ListDestinationRequest) ([]models.Destination, error)
+}
+
+type publisherResolver interface {
+	ResolvePublisher(ctx context.Context, destination *models.Destination) (destregistry.Publisher, error)
+}
+
+// Result summarizes a warm-up pass.
+type Result struct {
+	Tenants    int
+	Publishers int
+	Failed     int
+}
+
+// Warm resolves the publishers of the enabled destinations of up to limit
+// recently active tenants. A destination whose publisher cannot be created
+// counts as failed and does not stop the pass; the delivery path surfaces the
+// same error when it is next used.
+func Warm(ctx context.Context, activity Activity, destinations destinationLister, publishers publisherResolver, limit int) (Result, error) {
+	tenantIDs, err := activity.Recent(ctx, limit)
+	if err != nil {
+		return Result{}, err
+	}
+
+	results := make([]Result, len(tenantIDs))
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(warmConcurrency)
+	for i, tenantID := range tenantIDs {
+		g.Go(func() error {
+			dests, err := destinations.ListDestination(gctx, tenantstore.ListDestinationRequest{TenantID: tenantID})
+			if err != nil {
+				return fmt.Errorf("failed to list destinations of tenant %s: %w", tenantID, err)
+			}
+			results[i].Tenants = 1
+			for _, destination := range dests {
+				if destination.DisabledAt != nil {
+					continue
+				}
+				if _, err := publishers.ResolvePublisher(gctx, &destination); err != nil {
+					results[i].Failed++
+					continue
+				}
+				results[i].Publishers++
+			}
+			return nil
+		})
+	}
+	err = g.Wait()
+
+	var result Result
+	for _, r := range results {
+		result.Tenants += r.Tenants
+		result.Publishers += r.Publishers
+		result.Failed += r.Failed
+	}
+	return result, err
+}
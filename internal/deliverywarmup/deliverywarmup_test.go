@@ -0,0 +1,118 @@
+package deliverywarmup_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/deliverywarmup"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubResolver struct {
+	failing  map[string]bool
+	resolved chan string
+}
+
+func (r *stubResolver) ResolvePublisher(_ context.Context, destination *models.Destination) (destregistry.Publisher, error) {
+	if r.failing[destination.ID] {
+		return nil, errors.New("failed to create publisher")
+	}
+	r.resolved <- destination.ID
+	return nil, nil
+}
+
+func TestActivity(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns recent tenants most recent first", func(t *testing.T) {
+		t.Parallel()
+		clk := clock.NewFake(time.Now())
+		activity := deliverywarmup.NewActivity(testutil.CreateTestRedisClient(t), deliverywarmup.WithClock(clk))
+
+		require.NoError(t, activity.Touch(t.Context(), "t1"))
+		clk.Advance(time.Second)
+		require.NoError(t, activity.Touch(t.Context(), "t2"))
+		clk.Advance(time.Second)
+		require.NoError(t, activity.Touch(t.Context(), "t3"))
+
+		tenantIDs, err := activity.Recent(t.Context(), 2)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"t3", "t2"}, tenantIDs)
+	})
+
+	t.Run("throttles repeated touches of a tenant", func(t *testing.T) {
+		t.Parallel()
+		clk := clock.NewFake(time.Now())
+		activity := deliverywarmup.NewActivity(testutil.CreateTestRedisClient(t), deliverywarmup.WithClock(clk))
+
+		require.NoError(t, activity.Touch(t.Context(), "t1"))
+		clk.Advance(time.Second)
+		require.NoError(t, activity.Touch(t.Context(), "t2"))
+		clk.Advance(time.Second)
+		require.NoError(t, activity.Touch(t.Context(), "t1"))
+
+		tenantIDs, err := activity.Recent(t.Context(), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"t2", "t1"}, tenantIDs, "second touch of t1 within the interval is not recorded")
+
+		clk.Advance(time.Minute)
+		require.NoError(t, activity.Touch(t.Context(), "t1"))
+
+		tenantIDs, err = activity.Recent(t.Context(), 10)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"t1", "t2"}, tenantIDs)
+	})
+
+	t.Run("ignores activity older than a day", func(t *testing.T) {
+		t.Parallel()
+		clk := clock.NewFake(time.Now())
+		activity := deliverywarmup.NewActivity(testutil.CreateTestRedisClient(t), deliverywarmup.WithClock(clk))
+
+		require.NoError(t, activity.Touch(t.Context(), "t1"))
+		clk.Advance(25 * time.Hour)
+
+		tenantIDs, err := activity.Recent(t.Context(), 10)
+		require.NoError(t, err)
+		assert.Empty(t, tenantIDs)
+	})
+}
+
+func TestWarm(t *testing.T) {
+	t.Parallel()
+
+	ctx := t.Context()
+	df := testutil.DestinationFactory
+	store := tenantstore.NewMemTenantStore()
+	require.NoError(t, store.UpsertTenant(ctx, models.Tenant{ID: "t1"}))
+	require.NoError(t, store.UpsertTenant(ctx, models.Tenant{ID: "t2"}))
+	require.NoError(t, store.CreateDestination(ctx, df.Any(df.WithID("d1"), df.WithTenantID("t1"))))
+	require.NoError(t, store.CreateDestination(ctx, df.Any(df.WithID("d2"), df.WithTenantID("t1"))))
+	disabledAt := time.Now()
+	disabled := df.Any(df.WithID("d3"), df.WithTenantID("t1"))
+	disabled.DisabledAt = &disabledAt
+	require.NoError(t, store.CreateDestination(ctx, disabled))
+	require.NoError(t, store.CreateDestination(ctx, df.Any(df.WithID("d4"), df.WithTenantID("t2"))))
+
+	activity := deliverywarmup.NewActivity(testutil.CreateTestRedisClient(t))
+	require.NoError(t, activity.Touch(ctx, "t1"))
+
+	resolver := &stubResolver{failing: map[string]bool{"d2": true}, resolved: make(chan string, 10)}
+	result, err := deliverywarmup.Warm(ctx, activity, store, resolver, 10)
+	require.NoError(t, err)
+	close(resolver.resolved)
+
+	assert.Equal(t, deliverywarmup.Result{Tenants: 1, Publishers: 1, Failed: 1}, result)
+	var resolved []string
+	for id := range resolver.resolved {
+		resolved = append(resolved, id)
+	}
+	assert.Equal(t, []string{"d1"}, resolved)
+}
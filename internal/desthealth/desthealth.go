@@ -0,0 +1,190 @@
+// Package desthealth tracks the delivery health of each destination: its
+// consecutive failures, the error of its last failed attempt and the time of
+// its last successful one.
+//
+// Delivery workers record the outcome of every attempt in a Redis hash per
+// destination, and the API reads it back with the destination. Health is kept
+// for healthTTL after a destination's last recorded attempt, so destinations
+// that stop receiving events, or are deleted, age out to unknown.
+package desthealth
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/redis"
+)
+
+// healthTTL is how long a destination's health is kept after its last
+// recorded attempt.
+const healthTTL = 30 * 24 * time.Hour
+
+const (
+	fieldFailures         = "failures"
+	fieldLastErrorAttempt = "last_error_attempt_id"
+	fieldLastErrorCode    = "last_error_code"
+	fieldLastErrorMessage = "last_error_message"
+	fieldLastErrorAt      = "last_error_at"
+	fieldLastSuccessAt    = "last_success_at"
+)
+
+// Store records and reads destination health.
+type Store struct {
+	redisClient  redis.Cmdable
+	deploymentID string
+	clock        clock.Clock
+}
+
+type Option func(*Store)
+
+func WithDeploymentID(deploymentID string) Option {
+	return func(s *Store) {
+		s.deploymentID = deploymentID
+	}
+}
+
+func WithClock(c clock.Clock) Option {
+	return func(s *Store) {
+		s.clock = c
+	}
+}
+
+// NewStore returns a store of destination health in Redis.
+func NewStore(redisClient redis.Cmdable, opts ...Option) *Store {
+	s := &Store{
+		redisClient: redisClient,
+		clock:       clock.New(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Store) key(tenantID, destinationID string) string {
+	key := fmt.Sprintf("desthealth:%s:%s", tenantID, destinationID)
+	if s.deploymentID == "" {
+		return key
+	}
+	return s.deploymentID + ":" + key
+}
+
+// Record updates the destination's health with the outcome of an attempt. A
+// success resets the consecutive failures; a failure increments them and
+// becomes the last error. Deferred and sandboxed attempts are neither outcome
+// and are ignored.
+//
+// Redelivered attempts are recorded again, so the count is approximate under
+// redelivery, which is enough to tell a failing destination apart.
+func (s *Store) Record(ctx context.Context, tenantID, destinationID string, attempt *models.Attempt) error {
+	if attempt.Code == models.AttemptCodeSandbox {
+		return nil
+	}
+	at := attempt.Time
+	if at.IsZero() {
+		at = s.clock.Now()
+	}
+	key := s.key(tenantID, destinationID)
+
+	pipe := s.redisClient.TxPipeline()
+	switch attempt.Status {
+	case models.AttemptStatusSuccess:
+		pipe.HSet(ctx, key,
+			fieldFailures, 0,
+			fieldLastSuccessAt, at.UTC().Format(time.RFC3339Nano))
+	case models.AttemptStatusFailed:
+		pipe.HIncrBy(ctx, key, fieldFailures, 1)
+		pipe.HSet(ctx, key,
+			fieldLastErrorAttempt, attempt.ID,
+			fieldLastErrorCode, attempt.ErrorCode,
+			fieldLastErrorMessage, attempt.ErrorMessage,
+			fieldLastErrorAt, at.UTC().Format(time.RFC3339Nano))
+	default:
+		return nil
+	}
+	pipe.Expire(ctx, key, healthTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to record destination health: %w", err)
+	}
+	return nil
+}
+
+// Get returns the health of a destination. A destination without a recorded
+// attempt is unknown.
+func (s *Store) Get(ctx context.Context, tenantID, destinationID string) (*models.DestinationHealth, error) {
+	health, err := s.List(ctx, tenantID, []string{destinationID})
+	if err != nil {
+		return nil, err
+	}
+	return health[destinationID], nil
+}
+
+// List returns the health of the tenant's destinations by ID.
+func (s *Store) List(ctx context.Context, tenantID string, destinationIDs []string) (map[string]*models.DestinationHealth, error) {
+	if len(destinationIDs) == 0 {
+		return map[string]*models.DestinationHealth{}, nil
+	}
+	pipe := s.redisClient.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(destinationIDs))
+	for i, destinationID := range destinationIDs {
+		cmds[i] = pipe.HGetAll(ctx, s.key(tenantID, destinationID))
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to read destination health: %w", err)
+	}
+
+	result := make(map[string]*models.DestinationHealth, len(destinationIDs))
+	for i, destinationID := range destinationIDs {
+		health, err := parseHealth(cmds[i].Val())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse health of destination %s: %w", destinationID, err)
+		}
+		result[destinationID] = health
+	}
+	return result, nil
+}
+
+func parseHealth(fields map[string]string) (*models.DestinationHealth, error) {
+	health := &models.DestinationHealth{Status: models.DestinationHealthUnknown}
+	if len(fields) == 0 {
+		return health, nil
+	}
+
+	if v := fields[fieldFailures]; v != "" {
+		failures, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", fieldFailures, err)
+		}
+		health.ConsecutiveFailures = failures
+	}
+	if v := fields[fieldLastErrorAt]; v != "" {
+		at, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", fieldLastErrorAt, err)
+		}
+		health.LastError = &models.DestinationHealthError{
+			AttemptID: fields[fieldLastErrorAttempt],
+			Code:      fields[fieldLastErrorCode],
+			Message:   fields[fieldLastErrorMessage],
+			Time:      at,
+		}
+	}
+	if v := fields[fieldLastSuccessAt]; v != "" {
+		at, err := time.Parse(time.RFC3339Nano, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", fieldLastSuccessAt, err)
+		}
+		health.LastSuccessAt = &at
+	}
+
+	if health.ConsecutiveFailures > 0 {
+		health.Status = models.DestinationHealthFailing
+	} else {
+		health.Status = models.DestinationHealthHealthy
+	}
+	return health, nil
+}
@@ -0,0 +1,109 @@
+package desthealth_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/desthealth"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	newStore := func(t *testing.T) *desthealth.Store {
+		t.Helper()
+		return desthealth.NewStore(testutil.CreateTestRedisClient(t),
+			desthealth.WithDeploymentID("dp_test"),
+			desthealth.WithClock(clock.NewFake(start)),
+		)
+	}
+	failed := func(id string, at time.Time) *models.Attempt {
+		return &models.Attempt{
+			ID:           id,
+			Status:       models.AttemptStatusFailed,
+			Code:         "500",
+			ErrorCode:    "http_error",
+			ErrorMessage: "The destination responded with HTTP status 500.",
+			Time:         at,
+		}
+	}
+
+	t.Run("unknown without attempts", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		health, err := store.Get(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		assert.Equal(t, &models.DestinationHealth{Status: models.DestinationHealthUnknown}, health)
+	})
+
+	t.Run("counts consecutive failures until a success", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		require.NoError(t, store.Record(t.Context(), "t1", "d1", failed("atm_1", start)))
+		require.NoError(t, store.Record(t.Context(), "t1", "d1", failed("atm_2", start.Add(time.Minute))))
+
+		health, err := store.Get(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		assert.Equal(t, models.DestinationHealthFailing, health.Status)
+		assert.Equal(t, 2, health.ConsecutiveFailures)
+		require.NotNil(t, health.LastError)
+		assert.Equal(t, "atm_2", health.LastError.AttemptID)
+		assert.Equal(t, "http_error", health.LastError.Code)
+		assert.Equal(t, "The destination responded with HTTP status 500.", health.LastError.Message)
+		assert.True(t, start.Add(time.Minute).Equal(health.LastError.Time))
+		assert.Nil(t, health.LastSuccessAt)
+
+		successAt := start.Add(2 * time.Minute)
+		require.NoError(t, store.Record(t.Context(), "t1", "d1", &models.Attempt{
+			ID:     "atm_3",
+			Status: models.AttemptStatusSuccess,
+			Time:   successAt,
+		}))
+
+		health, err = store.Get(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		assert.Equal(t, models.DestinationHealthHealthy, health.Status)
+		assert.Zero(t, health.ConsecutiveFailures)
+		require.NotNil(t, health.LastSuccessAt)
+		assert.True(t, successAt.Equal(*health.LastSuccessAt))
+		require.NotNil(t, health.LastError, "the last error is kept after recovering")
+		assert.Equal(t, "atm_2", health.LastError.AttemptID)
+	})
+
+	t.Run("ignores deferred and sandboxed attempts", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		require.NoError(t, store.Record(t.Context(), "t1", "d1", &models.Attempt{ID: "atm_1", Status: models.AttemptStatusDeferred}))
+		require.NoError(t, store.Record(t.Context(), "t1", "d1", &models.Attempt{ID: "atm_2", Status: models.AttemptStatusSuccess, Code: models.AttemptCodeSandbox}))
+
+		health, err := store.Get(t.Context(), "t1", "d1")
+		require.NoError(t, err)
+		assert.Equal(t, models.DestinationHealthUnknown, health.Status)
+	})
+
+	t.Run("lists the health of destinations", func(t *testing.T) {
+		t.Parallel()
+		store := newStore(t)
+
+		require.NoError(t, store.Record(t.Context(), "t1", "d1", failed("atm_1", time.Time{})))
+		require.NoError(t, store.Record(t.Context(), "t1", "d2", &models.Attempt{ID: "atm_2", Status: models.AttemptStatusSuccess}))
+		require.NoError(t, store.Record(t.Context(), "t2", "d3", failed("atm_3", start)))
+
+		health, err := store.List(t.Context(), "t1", []string{"d1", "d2", "d3"})
+		require.NoError(t, err)
+		require.Len(t, health, 3)
+		assert.Equal(t, models.DestinationHealthFailing, health["d1"].Status)
+		assert.True(t, start.Equal(health["d1"].LastError.Time), "attempts without a time are recorded at the current time")
+		assert.Equal(t, models.DestinationHealthHealthy, health["d2"].Status)
+		assert.Equal(t, models.DestinationHealthUnknown, health["d3"].Status, "health is scoped to the tenant")
+	})
+}
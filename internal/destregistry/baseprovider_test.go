@@ -1,6 +1,7 @@
 package destregistry_test
 
 import (
+	"crypto/tls"
 	"io"
 	"net/http"
 	"net/http/httptest"
@@ -112,3 +113,18 @@ func TestMakeHTTPClient_Proxy(t *testing.T) {
 		assert.Contains(t, err.Error(), "invalid proxy URL")
 	})
 }
+
+func TestMakeHTTPClient_ClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	certificate := &tls.Certificate{Certificate: [][]byte{[]byte("cert")}}
+	client, err := destregistry.NewHTTPClient(destregistry.HTTPClientConfig{
+		ClientCertificate: certificate,
+	})
+	require.NoError(t, err)
+
+	transport, ok := client.Transport.(*http.Transport)
+	require.True(t, ok)
+	require.NotNil(t, transport.TLSClientConfig)
+	assert.Equal(t, []tls.Certificate{*certificate}, transport.TLSClientConfig.Certificates)
+}
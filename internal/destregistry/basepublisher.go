@@ -1,8 +1,11 @@
 package destregistry
 
 import (
+	"context"
+	"slices"
 	"sync"
 	"sync/atomic"
+	"text/template"
 	"time"
 
 	"github.com/hookdeck/outpost/internal/models"
@@ -14,6 +17,10 @@ type BasePublisher struct {
 	closed                      atomic.Bool
 	includeMillisecondTimestamp bool
 	deliveryMetadata            map[string]string
+	deliveryMetadataTemplates   map[string]*template.Template
+	deprecatedTopics            []string
+	topicLifecycle              models.TopicLifecycleSource
+	destinationID               string
 }
 
 // BasePublisherOption is a functional option for configuring BasePublisher
@@ -26,15 +33,49 @@ func WithMillisecondTimestamp(enabled bool) BasePublisherOption {
 	}
 }
 
-// WithDeliveryMetadata sets static metadata to be merged with every event delivery
+// WithDeliveryMetadata sets metadata to be merged with every event delivery.
+// Values containing "{{" are templates rendered against each event.
 func WithDeliveryMetadata(metadata map[string]string) BasePublisherOption {
 	return func(p *BasePublisher) {
 		if metadata != nil {
 			p.deliveryMetadata = metadata
+			p.deliveryMetadataTemplates = parseDeliveryMetadataTemplates(metadata)
 		}
 	}
 }
 
+// WithDeprecatedTopics flags events of the given topics with a
+// 'topic-deprecated' metadata entry so receivers can tell a topic is being
+// sunset.
+func WithDeprecatedTopics(topics []string) BasePublisherOption {
+	return func(p *BasePublisher) {
+		p.deprecatedTopics = topics
+	}
+}
+
+// WithTopicLifecycle flags events of the topics deprecated in lifecycle, such
+// as those deprecated at runtime, instead of a fixed list of topics.
+func WithTopicLifecycle(lifecycle models.TopicLifecycleSource) BasePublisherOption {
+	return func(p *BasePublisher) {
+		p.topicLifecycle = lifecycle
+	}
+}
+
+// WithDestinationID sets the destination the publisher delivers to, adding an
+// 'idempotency-key' metadata entry to every event delivery.
+func WithDestinationID(destinationID string) BasePublisherOption {
+	return func(p *BasePublisher) {
+		p.destinationID = destinationID
+	}
+}
+
+// IdempotencyKey returns the key identifying the delivery of an event to a
+// destination. It is the same across retries, manual ones included, so
+// receivers can use it to drop duplicates.
+func IdempotencyKey(eventID, destinationID string) string {
+	return eventID + ":" + destinationID
+}
+
 // NewBasePublisher creates a new BasePublisher with the given options
 func NewBasePublisher(opts ...BasePublisherOption) *BasePublisher {
 	p := &BasePublisher{}
@@ -64,6 +105,21 @@ func (p *BasePublisher) StartClose() {
 	p.active.Wait()
 }
 
+type ackTokenKey struct{}
+
+// WithAckToken returns a copy of ctx carrying the ack token of the delivery
+// being published. Publishers supporting two-phase delivery send it along
+// with the event, outside of its metadata.
+func WithAckToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, ackTokenKey{}, token)
+}
+
+// AckToken returns the ack token carried by ctx, or "" if there is none.
+func AckToken(ctx context.Context) string {
+	token, _ := ctx.Value(ackTokenKey{}).(string)
+	return token
+}
+
 func (p *BasePublisher) MakeMetadata(event *models.Event, timestamp time.Time) map[string]string {
 	systemMetadata := map[string]string{
 		"timestamp": timestamp.UTC().Format(time.RFC3339),
@@ -76,6 +132,20 @@ func (p *BasePublisher) MakeMetadata(event *models.Event, timestamp time.Time) m
 		systemMetadata["timestamp-ms"] = timestamp.UTC().Format(time.RFC3339Nano)
 	}
 
+	if p.destinationID != "" {
+		systemMetadata["idempotency-key"] = IdempotencyKey(event.ID, p.destinationID)
+	}
+
+	deprecated := p.deprecatedTopics
+	if p.topicLifecycle != nil {
+		// The lifecycle is cached in memory, so this doesn't wait on Redis
+		// outside of its refresh interval.
+		deprecated = p.topicLifecycle.Lifecycle(context.Background()).Deprecated
+	}
+	if slices.Contains(deprecated, event.Topic) {
+		systemMetadata["topic-deprecated"] = "true"
+	}
+
 	// Merge with priority: system < deliveryMetadata < event.Metadata
 	// Start with system metadata (lowest priority)
 	metadata := make(map[string]string)
@@ -86,9 +156,16 @@ func (p *BasePublisher) MakeMetadata(event *models.Event, timestamp time.Time) m
 	for k, v := range p.deliveryMetadata {
 		metadata[k] = v
 	}
+	if len(p.deliveryMetadataTemplates) > 0 {
+		renderDeliveryMetadata(metadata, p.deliveryMetadataTemplates, event)
+	}
 	// Merge event metadata (highest priority, can override both)
 	for k, v := range event.Metadata {
 		metadata[k] = v
 	}
+	// The checksum is set last so event metadata cannot spoof it.
+	if event.Checksum != "" {
+		metadata["data-checksum"] = event.Checksum
+	}
 	return metadata
 }
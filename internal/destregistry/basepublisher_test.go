@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
 	"github.com/hookdeck/outpost/internal/util/testutil"
 	"github.com/stretchr/testify/assert"
 )
@@ -143,6 +144,39 @@ func TestMakeMetadata_WithMillisecondTimestamp(t *testing.T) {
 	assert.Equal(t, "2021-01-01T00:00:00.123456789Z", metadata["timestamp-ms"])
 }
 
+func TestMakeMetadata_WithDestinationID(t *testing.T) {
+	t.Parallel()
+
+	event := testutil.EventFactory.Any(testutil.EventFactory.WithID("evt_123"))
+	timestamp := time.Unix(1609459200, 0)
+
+	publisher := destregistry.NewBasePublisher(destregistry.WithDestinationID("des_456"))
+	assert.Equal(t, "evt_123:des_456", publisher.MakeMetadata(&event, timestamp)["idempotency-key"])
+	assert.Equal(t, publisher.MakeMetadata(&event, timestamp.Add(time.Hour))["idempotency-key"],
+		publisher.MakeMetadata(&event, timestamp)["idempotency-key"], "key should be stable across attempts")
+
+	other := destregistry.NewBasePublisher(destregistry.WithDestinationID("des_789"))
+	assert.NotEqual(t, publisher.MakeMetadata(&event, timestamp)["idempotency-key"],
+		other.MakeMetadata(&event, timestamp)["idempotency-key"], "key should differ per destination")
+
+	assert.NotContains(t, destregistry.NewBasePublisher().MakeMetadata(&event, timestamp), "idempotency-key")
+}
+
+func TestMakeMetadata_WithDeprecatedTopics(t *testing.T) {
+	t.Parallel()
+
+	publisher := destregistry.NewBasePublisher(
+		destregistry.WithDeprecatedTopics([]string{"user.legacy"}),
+	)
+	timestamp := time.Unix(1609459200, 0)
+
+	deprecated := testutil.EventFactory.Any(testutil.EventFactory.WithTopic("user.legacy"))
+	assert.Equal(t, "true", publisher.MakeMetadata(&deprecated, timestamp)["topic-deprecated"])
+
+	active := testutil.EventFactory.Any(testutil.EventFactory.WithTopic("user.created"))
+	assert.NotContains(t, publisher.MakeMetadata(&active, timestamp), "topic-deprecated")
+}
+
 func TestMakeMetadata_WithMillisecondTimestampAndDeliveryMetadata(t *testing.T) {
 	t.Parallel()
 
@@ -217,3 +251,47 @@ func TestMakeMetadata_NilEventMetadata(t *testing.T) {
 	// Should have 4 keys (3 system + 1 delivery)
 	assert.Len(t, metadata, 4)
 }
+
+func TestMakeMetadata_WithChecksum(t *testing.T) {
+	t.Parallel()
+
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithMetadata(map[string]string{"data-checksum": "spoofed"}),
+	)
+	event.Checksum = models.DataChecksum(event.Data)
+	timestamp := time.Unix(1609459200, 0)
+
+	metadata := destregistry.NewBasePublisher().MakeMetadata(&event, timestamp)
+	assert.Equal(t, event.Checksum, metadata["data-checksum"], "event metadata should not override the checksum")
+}
+
+func TestMakeMetadata_WithDeliveryMetadataTemplates(t *testing.T) {
+	t.Parallel()
+
+	publisher := destregistry.NewBasePublisher(
+		destregistry.WithDeliveryMetadata(map[string]string{
+			"app-id":       "my-app",
+			"event-topic":  "{{.Topic}}",
+			"request-id":   "{{.Metadata.request_id}}",
+			"user-id":      "{{.Data.user.id}}",
+			"missing":      "{{.Metadata.missing}}",
+			"missing-safe": `{{index .Metadata "missing"}}`,
+		}),
+	)
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTopic("user.created"),
+		testutil.EventFactory.WithMetadata(map[string]string{"request_id": "req_123"}),
+		testutil.EventFactory.WithDataMap(map[string]interface{}{
+			"user": map[string]interface{}{"id": "usr_456"},
+		}),
+	)
+
+	metadata := publisher.MakeMetadata(&event, time.Unix(1609459200, 0))
+
+	assert.Equal(t, "my-app", metadata["app-id"])
+	assert.Equal(t, "user.created", metadata["event-topic"])
+	assert.Equal(t, "req_123", metadata["request-id"])
+	assert.Equal(t, "usr_456", metadata["user-id"])
+	assert.NotContains(t, metadata, "missing", "a template referencing a missing field should be left out")
+	assert.Equal(t, "", metadata["missing-safe"])
+}
@@ -0,0 +1,135 @@
+package destregistry
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// Normalized delivery error codes. Failed attempts carry one of these in
+// place of the provider's raw error, which may leak infrastructure details
+// and differs between providers, so tenants see a stable code and message.
+const (
+	ErrorCodeHTTPStatus         = "http_error"
+	ErrorCodeTimeout            = "timeout"
+	ErrorCodeDNS                = "dns_error"
+	ErrorCodeConnection         = "connection_failed"
+	ErrorCodeTLS                = "tls_error"
+	ErrorCodeRedirect           = "redirect_error"
+	ErrorCodeAuthentication     = "authentication_failed"
+	ErrorCodeTransformation     = "transformation_failed"
+	ErrorCodeFormat             = "format_failed"
+	ErrorCodeInvalidDestination = "invalid_destination"
+	ErrorCodeDeliveryFailed     = "delivery_failed"
+)
+
+var errorMessages = map[string]string{
+	ErrorCodeTimeout:            "The destination did not respond in time.",
+	ErrorCodeDNS:                "The destination's hostname could not be resolved.",
+	ErrorCodeConnection:         "A connection to the destination could not be established.",
+	ErrorCodeTLS:                "A secure connection to the destination could not be established.",
+	ErrorCodeRedirect:           "The destination responded with too many or invalid redirects.",
+	ErrorCodeAuthentication:     "The destination rejected the configured credentials.",
+	ErrorCodeTransformation:     "The destination's transformation failed on the event.",
+	ErrorCodeFormat:             "The event could not be formatted for the destination.",
+	ErrorCodeInvalidDestination: "The destination's configuration is invalid.",
+	ErrorCodeDeliveryFailed:     "The event could not be delivered to the destination.",
+}
+
+// attemptCodeErrors maps the network failure codes providers record as the
+// attempt code to normalized codes.
+var attemptCodeErrors = map[string]string{
+	"timeout":             ErrorCodeTimeout,
+	CodeDNSError:          ErrorCodeDNS,
+	CodeDNSNXDomain:       ErrorCodeDNS,
+	CodeDNSTimeout:        ErrorCodeDNS,
+	"connection_refused":  ErrorCodeConnection,
+	"connection_reset":    ErrorCodeConnection,
+	"network_unreachable": ErrorCodeConnection,
+	"network_error":       ErrorCodeConnection,
+	"tls_error":           ErrorCodeTLS,
+	"redirect_error":      ErrorCodeRedirect,
+	"auth_failed":         ErrorCodeAuthentication,
+	"access_denied":       ErrorCodeAuthentication,
+}
+
+// publishErrors maps the "error" the registry records on publish errors to
+// normalized codes.
+var publishErrors = map[string]string{
+	"timeout":               ErrorCodeTimeout,
+	"transformation_failed": ErrorCodeTransformation,
+	"format_failed":         ErrorCodeFormat,
+	"validation_failed":     ErrorCodeInvalidDestination,
+}
+
+// NormalizeAttemptError returns the normalized code and message of a failed
+// attempt, or empty strings when the attempt didn't fail. err is the error
+// the attempt was published with; with a nil err, as for attempts stored
+// before they carried a normalized error, only the attempt itself is used.
+func NormalizeAttemptError(attempt *models.Attempt, err error) (code, message string) {
+	if attempt == nil || attempt.Status != models.AttemptStatusFailed {
+		return "", ""
+	}
+	if status, convErr := strconv.Atoi(attempt.Code); convErr == nil && status >= 400 {
+		return ErrorCodeHTTPStatus, fmt.Sprintf("The destination responded with HTTP status %d.", status)
+	}
+	code = classifyAttemptError(attempt, err)
+	return code, errorMessages[code]
+}
+
+func classifyAttemptError(attempt *models.Attempt, err error) string {
+	var publishErr *ErrDestinationPublishAttempt
+	if errors.As(err, &publishErr) {
+		if reason, ok := publishErr.Data["error"].(string); ok {
+			if code, ok := publishErrors[reason]; ok {
+				return code
+			}
+		}
+	}
+	if reason, ok := attempt.ResponseData["error"].(string); ok {
+		if code, ok := publishErrors[reason]; ok {
+			return code
+		}
+	}
+	if code, ok := attemptCodeErrors[attempt.Code]; ok {
+		return code
+	}
+	if err != nil {
+		if code := classifyNetworkError(err); code != "" {
+			return code
+		}
+	}
+	return ErrorCodeDeliveryFailed
+}
+
+func classifyNetworkError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ErrorCodeTimeout
+	}
+	if ClassifyDNSError(err) != "" {
+		return ErrorCodeDNS
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return ErrorCodeTimeout
+	}
+	var certErr *tls.CertificateVerificationError
+	var unknownAuthorityErr x509.UnknownAuthorityError
+	var hostnameErr x509.HostnameError
+	if errors.As(err, &certErr) || errors.As(err, &unknownAuthorityErr) || errors.As(err, &hostnameErr) ||
+		strings.Contains(err.Error(), "tls:") {
+		return ErrorCodeTLS
+	}
+	var opErr *net.OpError
+	if errors.As(err, &opErr) {
+		return ErrorCodeConnection
+	}
+	return ""
+}
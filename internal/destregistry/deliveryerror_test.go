@@ -0,0 +1,92 @@
+package destregistry_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeAttemptError(t *testing.T) {
+	t.Parallel()
+
+	failed := func(code string, responseData map[string]interface{}) *models.Attempt {
+		return &models.Attempt{Status: models.AttemptStatusFailed, Code: code, ResponseData: responseData}
+	}
+
+	tests := []struct {
+		name    string
+		attempt *models.Attempt
+		err     error
+		want    string
+	}{
+		{
+			name:    "successful attempt",
+			attempt: &models.Attempt{Status: models.AttemptStatusSuccess, Code: "200"},
+			want:    "",
+		},
+		{
+			name:    "http status",
+			attempt: failed("503", map[string]interface{}{"body": "upstream 10.0.0.4 unavailable"}),
+			want:    destregistry.ErrorCodeHTTPStatus,
+		},
+		{
+			name:    "registry timeout",
+			attempt: failed("ERR", nil),
+			err:     destregistry.NewErrDestinationPublishAttempt(context.DeadlineExceeded, "webhook", map[string]interface{}{"error": "timeout"}),
+			want:    destregistry.ErrorCodeTimeout,
+		},
+		{
+			name:    "stored transformation failure",
+			attempt: failed("ERR", map[string]interface{}{"error": "transformation_failed", "message": "jq: error"}),
+			want:    destregistry.ErrorCodeTransformation,
+		},
+		{
+			name:    "provider network code",
+			attempt: failed("connection_refused", nil),
+			want:    destregistry.ErrorCodeConnection,
+		},
+		{
+			name:    "provider dns code",
+			attempt: failed(destregistry.CodeDNSNXDomain, nil),
+			want:    destregistry.ErrorCodeDNS,
+		},
+		{
+			name:    "dns error",
+			attempt: failed("ERR", nil),
+			err:     &net.DNSError{Err: "no such host", Name: "internal.example", IsNotFound: true},
+			want:    destregistry.ErrorCodeDNS,
+		},
+		{
+			name:    "connection error",
+			attempt: failed("ERR", nil),
+			err:     &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")},
+			want:    destregistry.ErrorCodeConnection,
+		},
+		{
+			name:    "unclassified",
+			attempt: failed("ERR", map[string]interface{}{"error": "broker said no"}),
+			err:     errors.New("broker said no"),
+			want:    destregistry.ErrorCodeDeliveryFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			code, message := destregistry.NormalizeAttemptError(tt.attempt, tt.err)
+			assert.Equal(t, tt.want, code)
+			if tt.want == "" {
+				assert.Empty(t, message)
+				return
+			}
+			assert.NotEmpty(t, message)
+			assert.NotContains(t, message, "10.0.0.4")
+			assert.NotContains(t, message, "internal.example")
+		})
+	}
+}
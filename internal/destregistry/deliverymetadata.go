@@ -0,0 +1,116 @@
+package destregistry
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// A delivery_metadata value containing "{{" is a text/template rendered
+// against each event, e.g. "{{.Topic}}" or "{{.Metadata.request_id}}". A
+// template referencing a field the event doesn't have fails to render, and
+// the entry is left out of that delivery.
+
+// deliveryMetadataTemplateData holds the event fields available to
+// delivery_metadata templates.
+type deliveryMetadataTemplateData struct {
+	ID       string
+	TenantID string
+	Topic    string
+	Time     time.Time
+	Source   string
+	Metadata map[string]string
+	Data     map[string]any
+}
+
+// isDeliveryMetadataTemplate reports whether a delivery_metadata value is a
+// template rather than a static value.
+func isDeliveryMetadataTemplate(value string) bool {
+	return strings.Contains(value, "{{")
+}
+
+func parseDeliveryMetadataTemplate(key, value string) (*template.Template, error) {
+	return template.New(key).Funcs(sprig.TxtFuncMap()).Option("missingkey=error").Parse(value)
+}
+
+// parseDeliveryMetadataTemplates parses the templated values of a
+// destination's delivery_metadata, keyed by entry. Values that fail to parse
+// are left out and delivered as-is; ValidateDeliveryMetadataTemplates rejects
+// them when the destination is saved.
+func parseDeliveryMetadataTemplates(metadata map[string]string) map[string]*template.Template {
+	var templates map[string]*template.Template
+	for key, value := range metadata {
+		if !isDeliveryMetadataTemplate(value) {
+			continue
+		}
+		tmpl, err := parseDeliveryMetadataTemplate(key, value)
+		if err != nil {
+			continue
+		}
+		if templates == nil {
+			templates = make(map[string]*template.Template)
+		}
+		templates[key] = tmpl
+	}
+	return templates
+}
+
+// ValidateDeliveryMetadataTemplates checks that the templated values of a
+// destination's delivery_metadata parse, returning one detail per invalid
+// entry.
+func ValidateDeliveryMetadataTemplates(destination *models.Destination) []ValidationErrorDetail {
+	var details []ValidationErrorDetail
+	keys := make([]string, 0, len(destination.DeliveryMetadata))
+	for key := range destination.DeliveryMetadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := destination.DeliveryMetadata[key]
+		if !isDeliveryMetadataTemplate(value) {
+			continue
+		}
+		if _, err := parseDeliveryMetadataTemplate(key, value); err != nil {
+			details = append(details, ValidationErrorDetail{
+				Field: fmt.Sprintf("delivery_metadata.%s", key),
+				Type:  "template",
+			})
+		}
+	}
+	return details
+}
+
+// renderDeliveryMetadata renders templated delivery_metadata entries against
+// an event into metadata. Entries that fail to render are left out.
+func renderDeliveryMetadata(metadata map[string]string, templates map[string]*template.Template, event *models.Event) {
+	data := deliveryMetadataTemplateData{
+		ID:       event.ID,
+		TenantID: event.TenantID,
+		Topic:    event.Topic,
+		Time:     event.Time,
+		Source:   event.Source,
+		Metadata: event.Metadata,
+	}
+	if data.Metadata == nil {
+		data.Metadata = map[string]string{}
+	}
+	// Data that isn't a JSON object leaves .Data empty
+	data.Data, _ = event.ParsedData()
+	if data.Data == nil {
+		data.Data = map[string]any{}
+	}
+
+	for key, tmpl := range templates {
+		var b strings.Builder
+		if err := tmpl.Execute(&b, data); err != nil {
+			delete(metadata, key)
+			continue
+		}
+		metadata[key] = b.String()
+	}
+}
@@ -0,0 +1,27 @@
+package destregistry_test
+
+import (
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateDeliveryMetadataTemplates(t *testing.T) {
+	t.Parallel()
+
+	destination := &models.Destination{
+		DeliveryMetadata: map[string]string{
+			"static":   "outpost",
+			"topic":    "{{.Topic}}",
+			"unclosed": "{{.Topic",
+			"unknown":  "{{nosuchfunc .Topic}}",
+		},
+	}
+
+	assert.Equal(t, []destregistry.ValidationErrorDetail{
+		{Field: "delivery_metadata.unclosed", Type: "template"},
+		{Field: "delivery_metadata.unknown", Type: "template"},
+	}, destregistry.ValidateDeliveryMetadataTemplates(destination))
+}
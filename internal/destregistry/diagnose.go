@@ -0,0 +1,58 @@
+package destregistry
+
+import (
+	"context"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// Connectivity diagnostic steps, run in this order.
+const (
+	DiagnosticStepDNS  = "dns"
+	DiagnosticStepTCP  = "tcp"
+	DiagnosticStepTLS  = "tls"
+	DiagnosticStepHTTP = "http"
+)
+
+// Connectivity diagnostic step statuses.
+const (
+	DiagnosticStatusOK      = "ok"
+	DiagnosticStatusFailed  = "failed"
+	DiagnosticStatusSkipped = "skipped"
+)
+
+// DiagnosticStep is the outcome of one connectivity check. Code classifies a
+// failure with the same codes delivery attempts are recorded with.
+type DiagnosticStep struct {
+	Name       string         `json:"name"`
+	Status     string         `json:"status"`
+	DurationMs int64          `json:"duration_ms"`
+	Code       string         `json:"code,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	Details    map[string]any `json:"details,omitempty"`
+}
+
+// ConnectivityReport is the outcome of checking that a destination's host can
+// be reached. When deliveries go through a proxy, the DNS and TCP steps check
+// the proxy and Proxied is set.
+type ConnectivityReport struct {
+	OK      bool             `json:"ok"`
+	Target  string           `json:"target"`
+	Proxied bool             `json:"proxied"`
+	Steps   []DiagnosticStep `json:"steps"`
+}
+
+// AddStep records a step, marking the report failed when the step failed.
+func (r *ConnectivityReport) AddStep(step DiagnosticStep) {
+	r.Steps = append(r.Steps, step)
+	if step.Status == DiagnosticStatusFailed {
+		r.OK = false
+	}
+}
+
+// ConnectivityDiagnoser is implemented by providers that can check a
+// destination's host is reachable with the same network settings deliveries
+// use.
+type ConnectivityDiagnoser interface {
+	Diagnose(ctx context.Context, destination *models.Destination) (*ConnectivityReport, error)
+}
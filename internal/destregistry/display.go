@@ -8,9 +8,36 @@ import (
 type DestinationDisplay struct {
 	*models.Destination
 	DestinationTarget
+	// Health is set on destinations read back from the API when delivery
+	// health is tracked.
+	Health *models.DestinationHealth `json:"health,omitempty"`
 }
 
 type DestinationTarget struct {
 	Target    string `json:"target"`
 	TargetURL string `json:"target_url,omitempty"`
 }
+
+// DestinationRevealer is implemented by providers whose display may redact
+// secrets that the caller must still see once: in the response to the request
+// that created or changed them.
+type DestinationRevealer interface {
+	RevealDestination(destination *models.Destination) *models.Destination
+}
+
+// DisplayRevealed displays a destination whose credentials were just set by
+// the caller, bypassing any redaction the provider applies to stored secrets.
+func DisplayRevealed(registry Registry, destination *models.Destination) (*DestinationDisplay, error) {
+	provider, err := registry.ResolveProvider(destination)
+	if err != nil {
+		return nil, err
+	}
+	revealer, ok := provider.(DestinationRevealer)
+	if !ok {
+		return registry.DisplayDestination(destination)
+	}
+	return &DestinationDisplay{
+		Destination:       revealer.RevealDestination(destination),
+		DestinationTarget: provider.ComputeTarget(destination),
+	}, nil
+}
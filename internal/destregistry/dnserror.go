@@ -0,0 +1,34 @@
+package destregistry
+
+import (
+	"errors"
+	"net"
+)
+
+// DNS failure codes reported in delivery errors.
+const (
+	// CodeDNSNXDomain means the domain doesn't exist.
+	CodeDNSNXDomain = "dns_nxdomain"
+	// CodeDNSTimeout means the resolver didn't answer in time.
+	CodeDNSTimeout = "dns_timeout"
+	// CodeDNSError is any other DNS lookup failure.
+	CodeDNSError = "dns_error"
+)
+
+// ClassifyDNSError returns the DNS failure code of err, or "" when err isn't a
+// DNS lookup failure. Telling a missing domain apart from a resolver blip lets
+// operators see which failures are worth retrying.
+func ClassifyDNSError(err error) string {
+	var dnsErr *net.DNSError
+	if !errors.As(err, &dnsErr) {
+		return ""
+	}
+	switch {
+	case dnsErr.IsNotFound:
+		return CodeDNSNXDomain
+	case dnsErr.IsTimeout:
+		return CodeDNSTimeout
+	default:
+		return CodeDNSError
+	}
+}
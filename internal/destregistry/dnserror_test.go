@@ -0,0 +1,58 @@
+package destregistry_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassifyDNSError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "nxdomain",
+			err:  &net.DNSError{Err: "no such host", Name: "example.invalid", IsNotFound: true},
+			want: destregistry.CodeDNSNXDomain,
+		},
+		{
+			name: "resolver timeout",
+			err:  &net.DNSError{Err: "i/o timeout", Name: "example.com", IsTimeout: true},
+			want: destregistry.CodeDNSTimeout,
+		},
+		{
+			name: "server failure",
+			err:  &net.DNSError{Err: "server misbehaving", Name: "example.com", IsTemporary: true},
+			want: destregistry.CodeDNSError,
+		},
+		{
+			name: "wrapped in a dial error",
+			err: fmt.Errorf("Post \"https://example.invalid\": %w", &net.OpError{
+				Op:  "dial",
+				Net: "tcp",
+				Err: &net.DNSError{Err: "no such host", Name: "example.invalid", IsNotFound: true},
+			}),
+			want: destregistry.CodeDNSNXDomain,
+		},
+		{
+			name: "not a dns error",
+			err:  errors.New("connection refused"),
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, destregistry.ClassifyDNSError(tt.err))
+		})
+	}
+}
@@ -0,0 +1,35 @@
+package destregistry
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// ErrEndpointNotVerified is returned when a destination's endpoint does not
+// echo the verification challenge.
+var ErrEndpointNotVerified = errors.New("endpoint verification failed")
+
+// EndpointVerifier is implemented by providers that can check a destination's
+// endpoint is controlled by its owner: the endpoint is sent a challenge and
+// must echo it back. A URL with a typo, or one that answers every request
+// with a success, fails the check instead of silently dropping events.
+type EndpointVerifier interface {
+	VerifyEndpoint(ctx context.Context, destination *models.Destination, challenge string) error
+}
+
+// TenantEndpointVerifier returns the verifier of the destination's endpoint,
+// or nil when the tenant doesn't require endpoint verification or the
+// destination type doesn't support it.
+func TenantEndpointVerifier(registry Registry, tenant *models.Tenant, destination *models.Destination) (EndpointVerifier, error) {
+	if !tenant.VerifyWebhookEndpoints {
+		return nil, nil
+	}
+	provider, err := registry.ResolveProvider(destination)
+	if err != nil {
+		return nil, err
+	}
+	verifier, _ := provider.(EndpointVerifier)
+	return verifier, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 )
 
 type ErrDestinationValidation struct {
@@ -39,6 +40,20 @@ func NewErrDestinationPublishAttempt(err error, provider string, data map[string
 	return &ErrDestinationPublishAttempt{Err: err, Provider: provider, Data: data}
 }
 
+// ErrRedeliveryRequested is returned with a deferred delivery when the
+// destination accepted the event but asked to receive it again after a delay.
+// It is not a publish failure: the event is rescheduled rather than retried.
+type ErrRedeliveryRequested struct {
+	Provider string
+	After    time.Duration
+}
+
+var _ error = &ErrRedeliveryRequested{}
+
+func (e *ErrRedeliveryRequested) Error() string {
+	return fmt.Sprintf("%s requested redelivery after %s", e.Provider, e.After)
+}
+
 // NewFormatError returns the (*Delivery, error) a publisher should return when
 // formatting an event fails before it can be sent (e.g. an invalid key/partition
 // template or an unparseable payload). It records a failed attempt so the failure
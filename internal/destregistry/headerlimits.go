@@ -0,0 +1,103 @@
+package destregistry
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// Default header limits, applied through config. Receivers commonly reject
+// requests with more than ~100 headers or 8-16 KiB of header data.
+const (
+	DefaultMaxHeaderCount = 50
+	DefaultMaxHeaderBytes = 8192
+)
+
+// headerKeyRegex is the accepted charset for header-bound keys (RFC 7230
+// token subset).
+var headerKeyRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_-]*$`)
+
+// HeaderLimits caps the headers a destination adds to every outbound request:
+// its delivery_metadata entries plus, for webhooks, its custom_headers. A zero
+// limit is not enforced.
+type HeaderLimits struct {
+	// MaxCount caps the number of entries.
+	MaxCount int
+	// MaxBytes caps the total size of entry names and values.
+	MaxBytes int
+	// EnforceAtDelivery also checks the count and size limits on every
+	// delivery, failing the attempts of destinations saved before the limits
+	// were introduced or lowered. Off by default, so such destinations keep
+	// delivering.
+	EnforceAtDelivery bool
+}
+
+// Validate checks a destination's header-bound entries against the limits
+// and returns one detail per violation. Key charset is checked for
+// delivery_metadata only; webhook providers validate their custom_headers.
+func (l HeaderLimits) Validate(destination *models.Destination) []ValidationErrorDetail {
+	var details []ValidationErrorDetail
+
+	keys := make([]string, 0, len(destination.DeliveryMetadata))
+	for key := range destination.DeliveryMetadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		if !headerKeyRegex.MatchString(key) {
+			details = append(details, ValidationErrorDetail{
+				Field: fmt.Sprintf("delivery_metadata.%s", key),
+				Type:  "pattern",
+			})
+		}
+	}
+
+	return append(details, l.validateSize(destination)...)
+}
+
+// ValidateDelivery checks a destination's header-bound entries at delivery.
+// It returns nothing unless EnforceAtDelivery is set, and then checks the
+// count and size only: key charset is enforced when the destination is saved.
+func (l HeaderLimits) ValidateDelivery(destination *models.Destination) []ValidationErrorDetail {
+	if !l.EnforceAtDelivery || (l.MaxCount <= 0 && l.MaxBytes <= 0) {
+		return nil
+	}
+	return l.validateSize(destination)
+}
+
+func (l HeaderLimits) validateSize(destination *models.Destination) []ValidationErrorDetail {
+	var details []ValidationErrorDetail
+	count, size := 0, 0
+	for _, headers := range []map[string]string{destination.DeliveryMetadata, customHeaders(destination)} {
+		for key, value := range headers {
+			count++
+			size += len(key) + len(value)
+		}
+	}
+	if l.MaxCount > 0 && count > l.MaxCount {
+		details = append(details, ValidationErrorDetail{Field: "headers", Type: "max_count"})
+	}
+	if l.MaxBytes > 0 && size > l.MaxBytes {
+		details = append(details, ValidationErrorDetail{Field: "headers", Type: "max_bytes"})
+	}
+	return details
+}
+
+// customHeaders returns the webhook custom_headers of a destination. Both
+// webhook providers store them as a JSON object in config; an unparseable
+// value is reported by the provider's own validation, so it counts as empty
+// here.
+func customHeaders(destination *models.Destination) map[string]string {
+	raw, ok := destination.Config["custom_headers"]
+	if !ok || raw == "" {
+		return nil
+	}
+	var headers map[string]string
+	if err := json.Unmarshal([]byte(raw), &headers); err != nil {
+		return nil
+	}
+	return headers
+}
@@ -0,0 +1,143 @@
+package destregistry_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeaderLimits_Validate(t *testing.T) {
+	t.Parallel()
+
+	limits := destregistry.HeaderLimits{MaxCount: 3, MaxBytes: 64}
+
+	tests := []struct {
+		name        string
+		destination *models.Destination
+		want        []destregistry.ValidationErrorDetail
+	}{
+		{
+			name: "within limits",
+			destination: &models.Destination{
+				DeliveryMetadata: map[string]string{"source": "outpost"},
+				Config:           map[string]string{"custom_headers": `{"x-team":"payments"}`},
+			},
+		},
+		{
+			name: "invalid key charset",
+			destination: &models.Destination{
+				DeliveryMetadata: map[string]string{"bad key": "v", "-lead": "v"},
+			},
+			want: []destregistry.ValidationErrorDetail{
+				{Field: "delivery_metadata.-lead", Type: "pattern"},
+				{Field: "delivery_metadata.bad key", Type: "pattern"},
+			},
+		},
+		{
+			name: "count includes custom headers",
+			destination: &models.Destination{
+				DeliveryMetadata: map[string]string{"a": "1", "b": "2"},
+				Config:           map[string]string{"custom_headers": `{"c":"3","d":"4"}`},
+			},
+			want: []destregistry.ValidationErrorDetail{{Field: "headers", Type: "max_count"}},
+		},
+		{
+			name: "total bytes",
+			destination: &models.Destination{
+				DeliveryMetadata: map[string]string{"a": strings.Repeat("x", 64)},
+			},
+			want: []destregistry.ValidationErrorDetail{{Field: "headers", Type: "max_bytes"}},
+		},
+		{
+			name: "unparseable custom headers are ignored",
+			destination: &models.Destination{
+				Config: map[string]string{"custom_headers": `not json`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.want, limits.Validate(tt.destination))
+		})
+	}
+
+	t.Run("zero limits are not enforced", func(t *testing.T) {
+		t.Parallel()
+		metadata := map[string]string{}
+		for i := range 100 {
+			metadata[strings.Repeat("k", i+1)] = strings.Repeat("v", 100)
+		}
+		assert.Empty(t, destregistry.HeaderLimits{}.Validate(&models.Destination{DeliveryMetadata: metadata}))
+	})
+}
+
+func TestRegistry_HeaderLimits(t *testing.T) {
+	t.Parallel()
+
+	// errCreatePublisher is returned once the limits let a delivery through.
+	errCreatePublisher := errors.New("create publisher")
+	newRegistry := func(t *testing.T, enforceAtDelivery bool) destregistry.Registry {
+		registry := destregistry.NewRegistry(&destregistry.Config{
+			HeaderLimits: destregistry.HeaderLimits{MaxCount: 1, EnforceAtDelivery: enforceAtDelivery},
+		}, testutil.CreateTestLogger(t))
+		require.NoError(t, registry.RegisterProvider("gcp_pubsub", &mockFailingProvider{createErr: errCreatePublisher}))
+		return registry
+	}
+	destination := &models.Destination{
+		ID:               "dest-1",
+		Type:             "gcp_pubsub",
+		DeliveryMetadata: map[string]string{"a": "1", "b": "2"},
+	}
+
+	t.Run("validate rejects destination over the limits", func(t *testing.T) {
+		t.Parallel()
+
+		err := newRegistry(t, false).ValidateDestination(context.Background(), destination)
+
+		var valErr *destregistry.ErrDestinationValidation
+		require.ErrorAs(t, err, &valErr)
+		assert.Equal(t, []destregistry.ValidationErrorDetail{{Field: "headers", Type: "max_count"}}, valErr.Errors)
+	})
+
+	t.Run("publish ignores the limits by default", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := newRegistry(t, false).PublishEvent(context.Background(), destination, &models.Event{ID: "evt-1"})
+
+		require.ErrorIs(t, err, errCreatePublisher)
+	})
+
+	t.Run("publish does not check key charset", func(t *testing.T) {
+		t.Parallel()
+
+		legacy := &models.Destination{
+			ID:               "dest-2",
+			Type:             "gcp_pubsub",
+			DeliveryMetadata: map[string]string{"x.y": "1"},
+		}
+		_, err := newRegistry(t, true).PublishEvent(context.Background(), legacy, &models.Event{ID: "evt-1"})
+
+		require.ErrorIs(t, err, errCreatePublisher)
+	})
+
+	t.Run("publish enforced at delivery fails the attempt without sending", func(t *testing.T) {
+		t.Parallel()
+
+		attempt, err := newRegistry(t, true).PublishEvent(context.Background(), destination, &models.Event{ID: "evt-1"})
+
+		var pubErr *destregistry.ErrDestinationPublishAttempt
+		require.ErrorAs(t, err, &pubErr)
+		assert.Equal(t, "validation_failed", pubErr.Data["error"])
+		require.NotNil(t, attempt)
+		assert.Equal(t, "failed", attempt.Status)
+		assert.Equal(t, []destregistry.ValidationErrorDetail{{Field: "headers", Type: "max_count"}}, attempt.ResponseData["errors"])
+	})
+}
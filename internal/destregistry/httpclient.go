@@ -1,10 +1,13 @@
 package destregistry
 
 import (
+	"crypto/tls"
 	"fmt"
 	"net/http"
 	"net/url"
 	"time"
+
+	"github.com/hookdeck/outpost/internal/dnscache"
 )
 
 type HTTPClientConfig struct {
@@ -18,6 +21,13 @@ type HTTPClientConfig struct {
 	// underlying transport plus the parsed proxy URL; returns the
 	// RoundTripper to use thereafter.
 	WrapTransport func(*http.Transport, *url.URL) http.RoundTripper
+	// DNSCache, if set, resolves the hosts the client connects to.
+	DNSCache *dnscache.Cache
+	// ClientCertificate, if set, is presented to servers that request one
+	// (mutual TLS).
+	ClientCertificate *tls.Certificate
+	// ServerTLS, if set, replaces how the server's certificate is verified.
+	ServerTLS *ServerTLS
 }
 
 // NewHTTPClient builds an *http.Client from config. Free function — no
@@ -29,11 +39,21 @@ func NewHTTPClient(config HTTPClientConfig) (*http.Client, error) {
 		client.Timeout = *config.Timeout
 	}
 
-	if config.ProxyURL == nil && config.UserAgent == nil {
+	if config.ProxyURL == nil && config.UserAgent == nil && config.DNSCache == nil && config.ClientCertificate == nil && config.ServerTLS == nil {
 		return client, nil
 	}
 
 	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.DNSCache != nil {
+		transport.DialContext = config.DNSCache.DialContext
+	}
+	if config.ClientCertificate != nil || config.ServerTLS != nil {
+		transport.TLSClientConfig = &tls.Config{}
+		if config.ClientCertificate != nil {
+			transport.TLSClientConfig.Certificates = []tls.Certificate{*config.ClientCertificate}
+		}
+		config.ServerTLS.Apply(transport.TLSClientConfig)
+	}
 
 	var rt http.RoundTripper = transport
 
@@ -0,0 +1,103 @@
+package metadata
+
+// JSONSchemaDialect is the JSON Schema draft the generated schemas follow.
+const JSONSchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+
+// numberPattern matches the integers accepted for "number" fields.
+const numberPattern = `^-?[0-9]+$`
+
+// JSONSchema is the subset of JSON Schema used to describe a destination
+// type's create payload.
+type JSONSchema struct {
+	Schema      string                 `json:"$schema,omitempty"`
+	Title       string                 `json:"title,omitempty"`
+	Description string                 `json:"description,omitempty"`
+	Type        any                    `json:"type,omitempty"`
+	Const       string                 `json:"const,omitempty"`
+	Properties  map[string]*JSONSchema `json:"properties,omitempty"`
+	Required    []string               `json:"required,omitempty"`
+	Enum        []string               `json:"enum,omitempty"`
+	Default     *string                `json:"default,omitempty"`
+	Minimum     *int                   `json:"minimum,omitempty"`
+	Maximum     *int                   `json:"maximum,omitempty"`
+	MinLength   *int                   `json:"minLength,omitempty"`
+	MaxLength   *int                   `json:"maxLength,omitempty"`
+	Pattern     *string                `json:"pattern,omitempty"`
+}
+
+// JSONSchema describes the type, config and credentials of a destination of
+// this type, mirroring the field-level validation the API applies. Config and
+// credential values travel as strings; number and checkbox fields also accept
+// their native JSON types, which the API converts. Patterns are copied from the
+// metadata as written, in Go regexp syntax.
+func (m *ProviderMetadata) JSONSchema() *JSONSchema {
+	config := fieldsSchema(m.ConfigFields)
+	credentials := fieldsSchema(m.CredentialFields)
+
+	schema := &JSONSchema{
+		Schema:      JSONSchemaDialect,
+		Title:       m.Label,
+		Description: m.Description,
+		Type:        "object",
+		Properties: map[string]*JSONSchema{
+			"type":        {Type: "string", Const: m.Type},
+			"config":      config,
+			"credentials": credentials,
+		},
+		Required: []string{"type"},
+	}
+	if len(config.Required) > 0 {
+		schema.Required = append(schema.Required, "config")
+	}
+	if len(credentials.Required) > 0 {
+		schema.Required = append(schema.Required, "credentials")
+	}
+	return schema
+}
+
+func fieldsSchema(fields []FieldSchema) *JSONSchema {
+	schema := &JSONSchema{
+		Type:       "object",
+		Properties: make(map[string]*JSONSchema, len(fields)),
+	}
+	for _, field := range fields {
+		property := field.jsonSchema()
+		if field.Required {
+			schema.Required = append(schema.Required, field.Key)
+			// The API treats an empty value as missing.
+			if property.Type == "string" && property.MinLength == nil {
+				minLength := 1
+				property.MinLength = &minLength
+			}
+		}
+		schema.Properties[field.Key] = property
+	}
+	return schema
+}
+
+func (f FieldSchema) jsonSchema() *JSONSchema {
+	schema := &JSONSchema{
+		Title:       f.Label,
+		Description: f.Description,
+		Default:     f.Default,
+	}
+	switch f.Type {
+	case "number":
+		pattern := numberPattern
+		schema.Type = []string{"integer", "string"}
+		schema.Pattern = &pattern
+		schema.Minimum = f.Min
+		schema.Maximum = f.Max
+	case "checkbox":
+		schema.Type = []string{"boolean", "string"}
+	default:
+		schema.Type = "string"
+		schema.MinLength = f.MinLength
+		schema.MaxLength = f.MaxLength
+		schema.Pattern = f.Pattern
+		for _, option := range f.Options {
+			schema.Enum = append(schema.Enum, option.Value)
+		}
+	}
+	return schema
+}
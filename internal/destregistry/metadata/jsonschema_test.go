@@ -0,0 +1,58 @@
+package metadata
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProviderMetadata_JSONSchema(t *testing.T) {
+	min, max, maxLength := 1, 10, 64
+	pattern := `^https?://`
+	defaultTLS := "true"
+	meta := &ProviderMetadata{
+		Type:  "example",
+		Label: "Example",
+		ConfigFields: []FieldSchema{
+			{Key: "url", Type: "text", Required: true, Pattern: &pattern, MaxLength: &maxLength},
+			{Key: "partitions", Type: "number", Min: &min, Max: &max},
+			{Key: "tls", Type: "checkbox", Default: &defaultTLS},
+			{Key: "mode", Type: "select", Options: []FieldOption{{Label: "A", Value: "a"}, {Label: "B", Value: "b"}}},
+		},
+	}
+
+	schema := meta.JSONSchema()
+
+	assert.Equal(t, JSONSchemaDialect, schema.Schema)
+	assert.Equal(t, []string{"type", "config"}, schema.Required, "credentials have no required fields")
+	assert.Equal(t, "example", schema.Properties["type"].Const)
+
+	config := schema.Properties["config"]
+	assert.Equal(t, []string{"url"}, config.Required)
+
+	url := config.Properties["url"]
+	assert.Equal(t, "string", url.Type)
+	assert.Equal(t, &pattern, url.Pattern)
+	assert.Equal(t, 1, *url.MinLength, "required strings reject empty values")
+	assert.Equal(t, 64, *url.MaxLength)
+
+	partitions := config.Properties["partitions"]
+	assert.Equal(t, []string{"integer", "string"}, partitions.Type)
+	assert.Equal(t, 1, *partitions.Minimum)
+	assert.Equal(t, 10, *partitions.Maximum)
+
+	assert.Equal(t, []string{"boolean", "string"}, config.Properties["tls"].Type)
+	assert.Equal(t, "true", *config.Properties["tls"].Default)
+	assert.Equal(t, []string{"a", "b"}, config.Properties["mode"].Enum)
+
+	t.Run("embedded metadata marshals", func(t *testing.T) {
+		loaded, err := NewMetadataLoader("").Load("webhook")
+		require.NoError(t, err)
+
+		raw, err := json.Marshal(loaded.JSONSchema())
+		require.NoError(t, err)
+		assert.Contains(t, string(raw), `"const":"webhook"`)
+	})
+}
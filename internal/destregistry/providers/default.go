@@ -1,6 +1,10 @@
 package destregistrydefault
 
 import (
+	"crypto/tls"
+	"fmt"
+	"time"
+
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destawskinesis"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destawss3"
@@ -9,9 +13,12 @@ import (
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destgcppubsub"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/desthookdeck"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destkafka"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destmqtt"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destrabbitmq"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhookstandard"
+	"github.com/hookdeck/outpost/internal/dnscache"
+	"github.com/hookdeck/outpost/internal/models"
 )
 
 // WebhookHeaderConfig is the resolved directive for a single webhook system
@@ -31,12 +38,19 @@ type DestWebhookConfig struct {
 	SignatureHeader          WebhookHeaderConfig
 	TimestampHeader          WebhookHeaderConfig
 	TopicHeader              WebhookHeaderConfig
+	RetryAfterHeader         WebhookHeaderConfig
+	MaxRetryAfterSeconds     int
 	SignatureContentTemplate string
 	SignatureHeaderTemplate  string
 	SignatureEncoding        string
 	SignatureAlgorithm       string
 	SigningSecretTemplate    string
 	MaxResponseBodyBytes     int
+	SecretRetrievalPolicy    string
+	// ClientCertificatesPath is a directory of client certificates
+	// destinations can name for mutual TLS. See
+	// destwebhook.LoadClientCertificates.
+	ClientCertificatesPath string
 }
 
 type DestAWSKinesisConfig struct {
@@ -46,8 +60,15 @@ type DestAWSKinesisConfig struct {
 type RegisterDefaultDestinationOptions struct {
 	UserAgent                   string
 	IncludeMillisecondTimestamp bool
+	TopicLifecycle              models.TopicLifecycleSource
 	Webhook                     *DestWebhookConfig
 	AWSKinesis                  *DestAWSKinesisConfig
+	// DNSCacheTTL caches the DNS lookups of HTTP destinations for this long.
+	// 0 disables the cache.
+	DNSCacheTTL time.Duration
+	// DNSCacheStale is how long cached addresses keep being used past the TTL
+	// while the resolver fails.
+	DNSCacheStale time.Duration
 }
 
 // RegisterDefault registers the default destination providers with the registry.
@@ -61,6 +82,23 @@ func RegisterDefault(registry destregistry.Registry, opts RegisterDefaultDestina
 	if opts.IncludeMillisecondTimestamp {
 		basePublisherOpts = append(basePublisherOpts, destregistry.WithMillisecondTimestamp(opts.IncludeMillisecondTimestamp))
 	}
+	if opts.TopicLifecycle != nil {
+		basePublisherOpts = append(basePublisherOpts, destregistry.WithTopicLifecycle(opts.TopicLifecycle))
+	}
+
+	var dnsCache *dnscache.Cache
+	if opts.DNSCacheTTL > 0 {
+		dnsCache = dnscache.New(opts.DNSCacheTTL, dnscache.WithStaleTTL(opts.DNSCacheStale))
+	}
+
+	var clientCertificates map[string]tls.Certificate
+	if opts.Webhook != nil {
+		var err error
+		clientCertificates, err = destwebhook.LoadClientCertificates(opts.Webhook.ClientCertificatesPath)
+		if err != nil {
+			return fmt.Errorf("failed to load webhook client certificates: %w", err)
+		}
+	}
 
 	// Register webhook provider based on mode
 	if opts.Webhook != nil && opts.Webhook.Mode == "standard" {
@@ -70,6 +108,9 @@ func RegisterDefault(registry destregistry.Registry, opts RegisterDefaultDestina
 			destwebhookstandard.WithProxyURL(opts.Webhook.ProxyURL),
 			destwebhookstandard.WithHeaderPrefix(opts.Webhook.HeaderPrefix),
 			destwebhookstandard.WithMaxResponseBodyBytes(opts.Webhook.MaxResponseBodyBytes),
+			destwebhookstandard.WithSecretRetrievalPolicy(opts.Webhook.SecretRetrievalPolicy),
+			destwebhookstandard.WithDNSCache(dnsCache),
+			destwebhookstandard.WithClientCertificates(clientCertificates),
 		}
 		webhookStandard, err := destwebhookstandard.New(loader, basePublisherOpts, webhookStandardOpts...)
 		if err != nil {
@@ -80,6 +121,7 @@ func RegisterDefault(registry destregistry.Registry, opts RegisterDefaultDestina
 		// Default mode - register customizable webhook as "webhook"
 		webhookOpts := []destwebhook.Option{
 			destwebhook.WithUserAgent(opts.UserAgent),
+			destwebhook.WithDNSCache(dnsCache),
 		}
 		if opts.Webhook != nil {
 			webhookOpts = append(webhookOpts,
@@ -89,12 +131,16 @@ func RegisterDefault(registry destregistry.Registry, opts RegisterDefaultDestina
 				destwebhook.WithSignatureHeader(opts.Webhook.SignatureHeader.Name, opts.Webhook.SignatureHeader.Disabled),
 				destwebhook.WithTimestampHeader(opts.Webhook.TimestampHeader.Name, opts.Webhook.TimestampHeader.Disabled),
 				destwebhook.WithTopicHeader(opts.Webhook.TopicHeader.Name, opts.Webhook.TopicHeader.Disabled),
+				destwebhook.WithRetryAfterHeader(opts.Webhook.RetryAfterHeader.Name, opts.Webhook.RetryAfterHeader.Disabled),
+				destwebhook.WithMaxRetryAfter(time.Duration(opts.Webhook.MaxRetryAfterSeconds)*time.Second),
 				destwebhook.WithSignatureContentTemplate(opts.Webhook.SignatureContentTemplate),
 				destwebhook.WithSignatureHeaderTemplate(opts.Webhook.SignatureHeaderTemplate),
 				destwebhook.WithSignatureEncoding(opts.Webhook.SignatureEncoding),
 				destwebhook.WithSignatureAlgorithm(opts.Webhook.SignatureAlgorithm),
 				destwebhook.WithSigningSecretTemplate(opts.Webhook.SigningSecretTemplate),
 				destwebhook.WithMaxResponseBodyBytes(opts.Webhook.MaxResponseBodyBytes),
+				destwebhook.WithSecretRetrievalPolicy(opts.Webhook.SecretRetrievalPolicy),
+				destwebhook.WithClientCertificates(clientCertificates),
 			)
 		}
 		webhook, err := destwebhook.New(loader, basePublisherOpts, webhookOpts...)
@@ -105,7 +151,8 @@ func RegisterDefault(registry destregistry.Registry, opts RegisterDefaultDestina
 	}
 
 	hookdeck, err := desthookdeck.New(loader, basePublisherOpts,
-		desthookdeck.WithUserAgent(opts.UserAgent))
+		desthookdeck.WithUserAgent(opts.UserAgent),
+		desthookdeck.WithDNSCache(dnsCache))
 	if err != nil {
 		return err
 	}
@@ -159,5 +206,11 @@ func RegisterDefault(registry destregistry.Registry, opts RegisterDefaultDestina
 	}
 	registry.RegisterProvider("kafka", kafkaDest)
 
+	mqttDest, err := destmqtt.New(loader, basePublisherOpts)
+	if err != nil {
+		return err
+	}
+	registry.RegisterProvider("mqtt", mqttDest)
+
 	return nil
 }
@@ -11,7 +11,9 @@ import (
 	awssdk "github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	awscreds "github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/kinesis"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/destregistry/metadata"
 	"github.com/hookdeck/outpost/internal/destregistry/partitionkey"
@@ -24,6 +26,8 @@ type AWKKinesisConfig struct {
 	Region               string
 	Endpoint             string
 	PartitionKeyTemplate string
+	RoleARN              string // optional
+	ExternalID           string // optional
 }
 
 type AWSKinesisCredentials struct {
@@ -32,6 +36,10 @@ type AWSKinesisCredentials struct {
 	Session string // optional
 }
 
+// roleSessionName identifies Outpost in the CloudTrail logs of the account
+// owning an assumed role.
+const roleSessionName = "outpost"
+
 // Provider implementation
 type AWSKinesisProvider struct {
 	*destregistry.BaseProvider
@@ -94,6 +102,20 @@ func (p *AWSKinesisProvider) CreatePublisher(ctx context.Context, destination *m
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
+	// With a role, the access key only identifies Outpost to STS and records
+	// are written with the role's temporary credentials.
+	if config.RoleARN != "" {
+		sdkConfig.Credentials = awssdk.NewCredentialsCache(stscreds.NewAssumeRoleProvider(
+			sts.NewFromConfig(sdkConfig), config.RoleARN,
+			func(o *stscreds.AssumeRoleOptions) {
+				o.RoleSessionName = roleSessionName
+				if config.ExternalID != "" {
+					o.ExternalID = awssdk.String(config.ExternalID)
+				}
+			},
+		))
+	}
+
 	// Create Kinesis client with custom endpoint if provided
 	kinesisClient := kinesis.NewFromConfig(sdkConfig, func(o *kinesis.Options) {
 		if config.Endpoint != "" {
@@ -102,7 +124,7 @@ func (p *AWSKinesisProvider) CreatePublisher(ctx context.Context, destination *m
 	})
 
 	return &AWSKinesisPublisher{
-		BasePublisher:        p.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata)),
+		BasePublisher:        p.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata), destregistry.WithDestinationID(destination.ID)),
 		client:               kinesisClient,
 		streamName:           config.StreamName,
 		partitionKeyTemplate: config.PartitionKeyTemplate,
@@ -135,6 +157,8 @@ func (p *AWSKinesisProvider) resolveConfig(ctx context.Context, destination *mod
 			Region:               destination.Config["region"],
 			Endpoint:             destination.Config["endpoint"],
 			PartitionKeyTemplate: destination.Config["partition_key_template"],
+			RoleARN:              destination.Config["role_arn"],
+			ExternalID:           destination.Config["external_id"],
 		}, &AWSKinesisCredentials{
 			Key:     destination.Credentials["key"],
 			Secret:  destination.Credentials["secret"],
@@ -106,6 +106,29 @@ func TestAWSKinesisDestination_Validate(t *testing.T) {
 		assert.Equal(t, "pattern", validationErr.Errors[0].Type)
 	})
 
+	t.Run("should validate role_arn", func(t *testing.T) {
+		t.Parallel()
+		withRole := func(roleARN string) error {
+			destination := validDestination
+			destination.Config = map[string]string{
+				"stream_name": "my-stream",
+				"region":      "us-east-1",
+				"role_arn":    roleARN,
+				"external_id": "outpost-tenant-1",
+			}
+			return awsKinesisDestination.Validate(context.Background(), &destination)
+		}
+
+		assert.NoError(t, withRole("arn:aws:iam::123456789012:role/outpost-writer"))
+		assert.NoError(t, withRole("arn:aws-cn:iam::123456789012:role/service/outpost-writer"))
+
+		err := withRole("arn:aws:iam::123456789012:user/outpost")
+		var validationErr *destregistry.ErrDestinationValidation
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "config.role_arn", validationErr.Errors[0].Field)
+		assert.Equal(t, "pattern", validationErr.Errors[0].Type)
+	})
+
 	t.Run("should validate missing credentials", func(t *testing.T) {
 		t.Parallel()
 		invalidDestination := validDestination
@@ -101,7 +101,7 @@ func (p *AWSS3Provider) CreatePublisher(ctx context.Context, destination *models
 	}
 
 	return NewAWSS3Publisher(
-		p.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata)),
+		p.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata), destregistry.WithDestinationID(destination.ID)),
 		client,
 		cfg.Bucket,
 		cfg.KeyTemplate,
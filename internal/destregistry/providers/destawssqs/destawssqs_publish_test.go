@@ -115,7 +115,7 @@ func TestAWSSQSSuite(t *testing.T) {
 
 func (s *AWSSQSSuite) SetupSuite() {
 	t := s.T()
-	t.Cleanup(testinfra.Start(t))
+	t.Cleanup(testinfra.StartSimulation(t))
 	mqConfig := testinfra.NewMQAWSConfig(t, nil)
 
 	// Setup AWS config and client
@@ -49,7 +49,7 @@ func (d *AzureServiceBusDestination) CreatePublisher(ctx context.Context, destin
 	}
 
 	return &AzureServiceBusPublisher{
-		BasePublisher:    d.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata)),
+		BasePublisher:    d.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata), destregistry.WithDestinationID(destination.ID)),
 		connectionString: creds.ConnectionString,
 		queueOrTopic:     cfg.Name,
 	}, nil
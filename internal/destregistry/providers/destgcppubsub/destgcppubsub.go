@@ -87,7 +87,7 @@ func (d *GCPPubSubDestination) CreatePublisher(ctx context.Context, destination
 	topic := client.Topic(cfg.Topic)
 
 	return &GCPPubSubPublisher{
-		BasePublisher: d.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata)),
+		BasePublisher: d.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata), destregistry.WithDestinationID(destination.ID)),
 		client:        client,
 		topic:         topic,
 		projectID:     cfg.ProjectID,
@@ -9,6 +9,7 @@ import (
 
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/destregistry/metadata"
+	"github.com/hookdeck/outpost/internal/dnscache"
 	"github.com/hookdeck/outpost/internal/models"
 )
 
@@ -36,6 +37,7 @@ type HookdeckProvider struct {
 	*destregistry.BaseProvider
 	userAgent  string
 	httpClient *http.Client
+	dnsCache   *dnscache.Cache
 }
 
 // Ensure our provider implements the Provider interface
@@ -48,6 +50,13 @@ func WithUserAgent(userAgent string) ProviderOption {
 	}
 }
 
+// WithDNSCache resolves the Hookdeck host through the given DNS cache
+func WithDNSCache(cache *dnscache.Cache) ProviderOption {
+	return func(p *HookdeckProvider) {
+		p.dnsCache = cache
+	}
+}
+
 // Constructor
 func New(loader metadata.MetadataLoader, basePublisherOpts []destregistry.BasePublisherOption, opts ...ProviderOption) (*HookdeckProvider, error) {
 	base, err := destregistry.NewBaseProvider(loader, "hookdeck", basePublisherOpts...)
@@ -152,6 +161,7 @@ func (p *HookdeckProvider) CreatePublisher(ctx context.Context, destination *mod
 		var err error
 		client, err = destregistry.NewHTTPClient(destregistry.HTTPClientConfig{
 			UserAgent: &p.userAgent,
+			DNSCache:  p.dnsCache,
 		})
 		if err != nil {
 			return nil, err
@@ -160,7 +170,7 @@ func (p *HookdeckProvider) CreatePublisher(ctx context.Context, destination *mod
 
 	// Create publisher with base publisher from provider
 	publisher := &HookdeckPublisher{
-		BasePublisher: p.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata)),
+		BasePublisher: p.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata), destregistry.WithDestinationID(destination.ID)),
 		tokenString:   tokenString,
 		parsedToken:   parsedToken,
 		client:        client,
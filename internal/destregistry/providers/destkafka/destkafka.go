@@ -117,7 +117,7 @@ func (d *KafkaDestination) CreatePublisher(ctx context.Context, destination *mod
 	}
 
 	return &KafkaPublisher{
-		BasePublisher:        d.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata)),
+		BasePublisher:        d.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata), destregistry.WithDestinationID(destination.ID)),
 		writer:               writer,
 		partitionKeyTemplate: config.PartitionKeyTemplate,
 	}, nil
@@ -273,10 +273,15 @@ func (p *KafkaPublisher) Publish(ctx context.Context, event *models.Event) (*des
 }
 
 // ClassifyKafkaError returns a descriptive error code based on the error type.
+//
+// DNS failures are reported as dns_nxdomain, dns_timeout or dns_error.
 func ClassifyKafkaError(err error) string {
 	if err == nil {
 		return "unknown"
 	}
+	if code := destregistry.ClassifyDNSError(err); code != "" {
+		return code
+	}
 
 	errStr := err.Error()
 
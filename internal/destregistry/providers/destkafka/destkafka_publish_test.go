@@ -131,7 +131,7 @@ type KafkaPublishSuite struct {
 
 func (s *KafkaPublishSuite) SetupSuite() {
 	t := s.T()
-	t.Cleanup(testinfra.Start(t))
+	t.Cleanup(testinfra.StartSimulation(t))
 
 	brokerAddr := testinfra.EnsureKafka()
 	topic := "test-topic-" + idgen.String()
@@ -174,7 +174,7 @@ func (s *KafkaPublishSuite) TearDownSuite() {
 }
 
 func TestKafkaPublishIntegration(t *testing.T) {
-	if testing.Short() {
+	if testing.Short() && !testinfra.Simulated {
 		t.Skip("Skipping integration test in short mode")
 	}
 	suite.Run(t, new(KafkaPublishSuite))
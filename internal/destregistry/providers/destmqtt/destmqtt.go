@@ -0,0 +1,475 @@
+package destmqtt
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/metadata"
+	"github.com/hookdeck/outpost/internal/destregistry/partitionkey"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/jmespath/go-jmespath"
+)
+
+const (
+	// connectTimeout bounds the MQTT handshake when the delivery context has
+	// no earlier deadline.
+	connectTimeout = 30 * time.Second
+	// disconnectQuiesce is how long Close waits for in-flight work, in
+	// milliseconds.
+	disconnectQuiesce = 250
+)
+
+// secureSchemes are the broker URL schemes that connect over TLS.
+var secureSchemes = map[string]bool{"ssl": true, "tls": true, "mqtts": true, "wss": true}
+
+// Configuration types
+
+type MQTTConfig struct {
+	ServerURL         string
+	Topic             string
+	TopicTemplate     string
+	QoS               byte
+	Retain            bool
+	MetadataInPayload bool
+	// ServerTLS verifies the broker's certificate, nil for the system CAs.
+	ServerTLS *destregistry.ServerTLS
+}
+
+type MQTTCredentials struct {
+	Username          string
+	Password          string
+	ClientCertificate string
+	ClientKey         string
+}
+
+// Provider implementation
+
+type MQTTDestination struct {
+	*destregistry.BaseProvider
+}
+
+var _ destregistry.Provider = (*MQTTDestination)(nil)
+
+func New(loader metadata.MetadataLoader, basePublisherOpts []destregistry.BasePublisherOption) (*MQTTDestination, error) {
+	base, err := destregistry.NewBaseProvider(loader, "mqtt", basePublisherOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &MQTTDestination{BaseProvider: base}, nil
+}
+
+func (d *MQTTDestination) Validate(ctx context.Context, destination *models.Destination) error {
+	if err := d.BaseProvider.Validate(ctx, destination); err != nil {
+		return err
+	}
+
+	serverURL, err := url.Parse(destination.Config["server_url"])
+	if err != nil || serverURL.Host == "" || !validScheme(serverURL.Scheme) {
+		return invalid("config.server_url")
+	}
+
+	if !validTopic(destination.Config["topic"]) {
+		return invalid("config.topic")
+	}
+	if template := destination.Config["topic_template"]; template != "" {
+		if _, err := jmespath.Compile(template); err != nil {
+			return invalid("config.topic_template")
+		}
+	}
+
+	if qos := destination.Config["qos"]; qos != "" && qos != "0" && qos != "1" && qos != "2" {
+		return invalid("config.qos")
+	}
+	for _, key := range []string{"retain", "metadata_in_payload"} {
+		if value := destination.Config[key]; value != "" && value != "on" && value != "true" && value != "false" {
+			return invalid("config." + key)
+		}
+	}
+
+	// A client certificate is only usable with its key, and both are sent
+	// over TLS only.
+	cert, key := destination.Credentials["client_certificate"], destination.Credentials["client_key"]
+	if cert != "" || key != "" {
+		if cert == "" {
+			return required("credentials.client_certificate")
+		}
+		if key == "" {
+			return required("credentials.client_key")
+		}
+		if _, err := tls.X509KeyPair([]byte(cert), []byte(key)); err != nil {
+			return invalid("credentials.client_certificate")
+		}
+		if !secureSchemes[serverURL.Scheme] {
+			return invalid("config.server_url")
+		}
+	}
+	if _, err := destregistry.ResolveServerTLS(destination); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (d *MQTTDestination) CreatePublisher(ctx context.Context, destination *models.Destination) (destregistry.Publisher, error) {
+	config, credentials, err := d.resolveConfig(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := mqtt.NewClientOptions().
+		AddBroker(config.ServerURL).
+		SetClientID(clientID()).
+		SetCleanSession(true).
+		SetAutoReconnect(false).
+		SetConnectTimeout(connectTimeout).
+		SetUsername(credentials.Username).
+		SetPassword(credentials.Password)
+
+	tlsConfig, err := buildTLSConfig(credentials, config.ServerTLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to configure TLS: %w", err)
+	}
+	opts.SetTLSConfig(tlsConfig)
+
+	return &MQTTPublisher{
+		BasePublisher:     d.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata), destregistry.WithDestinationID(destination.ID)),
+		opts:              opts,
+		topic:             config.Topic,
+		topicTemplate:     config.TopicTemplate,
+		qos:               config.QoS,
+		retain:            config.Retain,
+		metadataInPayload: config.MetadataInPayload,
+	}, nil
+}
+
+func (d *MQTTDestination) resolveConfig(ctx context.Context, destination *models.Destination) (*MQTTConfig, *MQTTCredentials, error) {
+	if err := d.Validate(ctx, destination); err != nil {
+		return nil, nil, err
+	}
+
+	serverTLS, err := destregistry.ResolveServerTLS(destination)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	qos := 1
+	if qosStr := destination.Config["qos"]; qosStr != "" {
+		qos, _ = strconv.Atoi(qosStr)
+	}
+
+	return &MQTTConfig{
+		ServerURL:         destination.Config["server_url"],
+		Topic:             destination.Config["topic"],
+		TopicTemplate:     destination.Config["topic_template"],
+		QoS:               byte(qos),
+		Retain:            isTrue(destination.Config["retain"]),
+		MetadataInPayload: isTrue(destination.Config["metadata_in_payload"]),
+		ServerTLS:         serverTLS,
+	}, &MQTTCredentials{
+		Username:          destination.Credentials["username"],
+		Password:          destination.Credentials["password"],
+		ClientCertificate: destination.Credentials["client_certificate"],
+		ClientKey:         destination.Credentials["client_key"],
+	}, nil
+}
+
+// Preprocess sets the default QoS and normalizes the checkbox values.
+func (d *MQTTDestination) Preprocess(newDestination *models.Destination, originalDestination *models.Destination, opts *destregistry.PreprocessDestinationOpts) error {
+	if newDestination.Config == nil {
+		return nil
+	}
+
+	if newDestination.Config["qos"] == "" {
+		newDestination.Config["qos"] = "1"
+	}
+	for _, key := range []string{"retain", "metadata_in_payload", "tls_skip_verify"} {
+		if newDestination.Config[key] == "on" {
+			newDestination.Config[key] = "true"
+		} else if newDestination.Config[key] == "" {
+			newDestination.Config[key] = "false"
+		}
+	}
+
+	if _, _, err := d.resolveConfig(context.Background(), newDestination); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (d *MQTTDestination) ComputeTarget(destination *models.Destination) destregistry.DestinationTarget {
+	target := destination.Config["topic"]
+	if serverURL, err := url.Parse(destination.Config["server_url"]); err == nil && serverURL.Host != "" {
+		target = fmt.Sprintf("%s / %s", serverURL.Host, target)
+	}
+	return destregistry.DestinationTarget{
+		Target:    target,
+		TargetURL: "",
+	}
+}
+
+// Publisher implementation
+
+type MQTTPublisher struct {
+	*destregistry.BasePublisher
+	opts              *mqtt.ClientOptions
+	topic             string
+	topicTemplate     string
+	qos               byte
+	retain            bool
+	metadataInPayload bool
+
+	mu     sync.Mutex
+	client mqtt.Client
+}
+
+func (p *MQTTPublisher) Close() error {
+	p.BasePublisher.StartClose()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil {
+		p.client.Disconnect(disconnectQuiesce)
+		p.client = nil
+	}
+	return nil
+}
+
+func (p *MQTTPublisher) Publish(ctx context.Context, event *models.Event) (*destregistry.Delivery, error) {
+	if err := p.BasePublisher.StartPublish(); err != nil {
+		return nil, err
+	}
+	defer p.BasePublisher.FinishPublish()
+
+	meta := p.BasePublisher.MakeMetadata(event, time.Now())
+	topic, payload, err := p.format(event, meta)
+	if err != nil {
+		return destregistry.NewFormatError("mqtt", "", err)
+	}
+
+	client, err := p.ensureConnection(ctx)
+	if err != nil {
+		return &destregistry.Delivery{
+			Status: "failed",
+			Code:   ClassifyMQTTError(err),
+			Response: map[string]interface{}{
+				"error": err.Error(),
+			},
+		}, destregistry.NewErrDestinationPublishAttempt(err, "mqtt", map[string]interface{}{
+			"error":   "connection_failed",
+			"message": err.Error(),
+		})
+	}
+
+	if err := wait(ctx, client.Publish(topic, p.qos, p.retain, payload)); err != nil {
+		return &destregistry.Delivery{
+			Status: "failed",
+			Code:   ClassifyMQTTError(err),
+			Response: map[string]interface{}{
+				"error": err.Error(),
+			},
+		}, destregistry.NewErrDestinationPublishAttempt(err, "mqtt", map[string]interface{}{
+			"error":   "publish_failed",
+			"message": err.Error(),
+		})
+	}
+
+	return &destregistry.Delivery{
+		Status: "success",
+		Code:   "OK",
+		Response: map[string]interface{}{
+			"topic": topic,
+		},
+	}, nil
+}
+
+// format returns the topic and payload of the event. MQTT 3.1.1 messages
+// have no headers, so the metadata is only delivered when it is wrapped into
+// the payload.
+func (p *MQTTPublisher) format(event *models.Event, meta map[string]string) (string, []byte, error) {
+	metadataMap := make(map[string]interface{}, len(meta))
+	for k, v := range meta {
+		metadataMap[k] = v
+	}
+
+	topic := p.topic
+	if p.topicTemplate != "" {
+		dataMap, err := event.ParsedData()
+		if err != nil {
+			return "", nil, err
+		}
+		if dataMap == nil {
+			dataMap = make(map[string]interface{})
+		}
+		topic, err = partitionkey.Evaluate(p.topicTemplate, map[string]interface{}{
+			"metadata": metadataMap,
+			"data":     dataMap,
+		}, p.topic)
+		if err != nil {
+			topic = p.topic
+		}
+		if !validTopic(topic) {
+			return "", nil, fmt.Errorf("topic template produced an invalid topic %q", topic)
+		}
+	}
+
+	if !p.metadataInPayload {
+		return topic, []byte(event.Data), nil
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"metadata": metadataMap,
+		"data":     json.RawMessage(event.Data),
+	})
+	if err != nil {
+		return "", nil, err
+	}
+	return topic, payload, nil
+}
+
+func (p *MQTTPublisher) ensureConnection(ctx context.Context) (mqtt.Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.client != nil && p.client.IsConnectionOpen() {
+		return p.client, nil
+	}
+
+	client := mqtt.NewClient(p.opts)
+	if err := wait(ctx, client.Connect()); err != nil {
+		client.Disconnect(0)
+		return nil, fmt.Errorf("failed to connect to MQTT broker: %w", err)
+	}
+
+	if p.client != nil {
+		p.client.Disconnect(0)
+	}
+	p.client = client
+	return client, nil
+}
+
+// wait blocks until the token completes or ctx is done.
+func wait(ctx context.Context, token mqtt.Token) error {
+	select {
+	case <-token.Done():
+		return token.Error()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// ClassifyMQTTError returns a descriptive error code based on the error type.
+// All errors classified here are destination-level failures (DeliveryError → ack + retry).
+//
+// Error codes and their meanings:
+//   - dns_nxdomain:        Domain doesn't exist
+//   - dns_timeout:         DNS resolver didn't answer in time
+//   - dns_error:           Other DNS lookup failures
+//   - connection_refused:  Server not running or rejecting connections
+//   - connection_reset:    Connection was dropped by the broker
+//   - auth_failed:         Bad credentials or not authorized
+//   - timeout:             Connection or operation timed out
+//   - tls_error:           TLS/SSL certificate or handshake failure
+//   - mqtt_error:          Other MQTT-related failures (catch-all)
+func ClassifyMQTTError(err error) string {
+	if err == nil {
+		return "unknown"
+	}
+	if code := destregistry.ClassifyDNSError(err); code != "" {
+		return code
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+
+	errStr := err.Error()
+
+	switch {
+	case strings.Contains(errStr, "no such host"):
+		return "dns_error"
+	case strings.Contains(errStr, "connection refused"):
+		return "connection_refused"
+	case strings.Contains(errStr, "connection reset") || strings.Contains(errStr, "connection lost") || strings.Contains(errStr, "not currently connected"):
+		return "connection_reset"
+	case strings.Contains(errStr, "bad user name or password") || strings.Contains(errStr, "not Authorized"):
+		return "auth_failed"
+	case strings.Contains(errStr, "i/o timeout") || strings.Contains(errStr, "timeout"):
+		return "timeout"
+	case strings.Contains(errStr, "tls:") || strings.Contains(errStr, "x509:"):
+		return "tls_error"
+	default:
+		return "mqtt_error"
+	}
+}
+
+// Helper functions
+
+func buildTLSConfig(credentials *MQTTCredentials, serverTLS *destregistry.ServerTLS) (*tls.Config, error) {
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	serverTLS.Apply(tlsConfig)
+	if credentials.ClientCertificate != "" {
+		cert, err := tls.X509KeyPair([]byte(credentials.ClientCertificate), []byte(credentials.ClientKey))
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// clientID returns a random client ID. Brokers disconnect an existing
+// session when another client connects with the same ID, so every publisher
+// needs its own.
+func clientID() string {
+	b := make([]byte, 6)
+	rand.Read(b)
+	return "outpost-" + hex.EncodeToString(b)
+}
+
+func validScheme(scheme string) bool {
+	switch scheme {
+	case "tcp", "mqtt", "ws":
+		return true
+	default:
+		return secureSchemes[scheme]
+	}
+}
+
+// validTopic reports whether topic can be published to. Wildcards are only
+// allowed in subscriptions.
+func validTopic(topic string) bool {
+	return topic != "" && !strings.ContainsAny(topic, "+#\x00")
+}
+
+func isTrue(value string) bool {
+	return value == "true" || value == "on"
+}
+
+func invalid(field string) error {
+	return destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+		{
+			Field: field,
+			Type:  "invalid",
+		},
+	})
+}
+
+func required(field string) error {
+	return destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+		{
+			Field: field,
+			Type:  "required",
+		},
+	})
+}
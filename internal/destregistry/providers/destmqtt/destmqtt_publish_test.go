@@ -0,0 +1,191 @@
+package destmqtt_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destmqtt"
+	testsuite "github.com/hookdeck/outpost/internal/destregistry/testing"
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testinfra"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+// MQTTConsumer implements testsuite.MessageConsumer
+type MQTTConsumer struct {
+	client  mqtt.Client
+	msgChan chan testsuite.Message
+}
+
+func NewMQTTConsumer(serverURL, topic string) (*MQTTConsumer, error) {
+	c := &MQTTConsumer{msgChan: make(chan testsuite.Message, 100)}
+	c.client = mqtt.NewClient(mqtt.NewClientOptions().AddBroker(serverURL).SetClientID("consumer-" + idgen.String()))
+	if token := c.client.Connect(); token.Wait() && token.Error() != nil {
+		return nil, token.Error()
+	}
+	token := c.client.Subscribe(topic, 1, func(_ mqtt.Client, msg mqtt.Message) {
+		// The destination wraps the data with its metadata, as MQTT
+		// messages have no headers.
+		var envelope struct {
+			Metadata map[string]string `json:"metadata"`
+			Data     json.RawMessage   `json:"data"`
+		}
+		if err := json.Unmarshal(msg.Payload(), &envelope); err != nil {
+			return
+		}
+		c.msgChan <- testsuite.Message{
+			Data:     envelope.Data,
+			Metadata: envelope.Metadata,
+			Raw:      msg,
+		}
+	})
+	if token.Wait() && token.Error() != nil {
+		return nil, token.Error()
+	}
+	return c, nil
+}
+
+func (c *MQTTConsumer) Consume() <-chan testsuite.Message {
+	return c.msgChan
+}
+
+func (c *MQTTConsumer) Close() error {
+	c.client.Disconnect(250)
+	return nil
+}
+
+// MQTTAsserter implements testsuite.MessageAsserter
+type MQTTAsserter struct {
+	topicPrefix string
+}
+
+func (a *MQTTAsserter) AssertMessage(t testsuite.TestingT, msg testsuite.Message, event models.Event) {
+	mqttMsg, ok := msg.Raw.(mqtt.Message)
+	assert.True(t, ok, "raw message should be mqtt.Message")
+
+	// The topic template routes each event to a topic named after it
+	assert.Equal(t, a.topicPrefix+event.Topic, mqttMsg.Topic())
+	assert.Equal(t, byte(1), mqttMsg.Qos())
+	testsuite.AssertTimestampIsISO8601(t, msg.Metadata["timestamp"])
+}
+
+// MQTTPublishSuite runs the shared publisher test suite for MQTT
+type MQTTPublishSuite struct {
+	testsuite.PublisherSuite
+	consumer *MQTTConsumer
+}
+
+func (s *MQTTPublishSuite) SetupSuite() {
+	t := s.T()
+	t.Cleanup(testinfra.Start(t))
+
+	serverURL := testinfra.EnsureMQTT()
+	topicPrefix := "outpost-test/" + idgen.String() + "/"
+
+	provider, err := destmqtt.New(testutil.Registry.MetadataLoader(), nil)
+	require.NoError(t, err)
+
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("mqtt"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"server_url":          serverURL,
+			"topic":               topicPrefix + "fallback",
+			"topic_template":      "join('', ['" + topicPrefix + "', metadata.topic])",
+			"qos":                 "1",
+			"metadata_in_payload": "true",
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{}),
+	)
+
+	consumer, err := NewMQTTConsumer(serverURL, topicPrefix+"#")
+	require.NoError(t, err)
+	s.consumer = consumer
+
+	s.InitSuite(testsuite.Config{
+		Provider: provider,
+		Dest:     &dest,
+		Consumer: consumer,
+		Asserter: &MQTTAsserter{topicPrefix: topicPrefix},
+	})
+}
+
+func (s *MQTTPublishSuite) TearDownSuite() {
+	if s.consumer != nil {
+		s.consumer.Close()
+	}
+}
+
+func TestMQTTPublishIntegration(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Skipping integration test in short mode")
+	}
+	suite.Run(t, new(MQTTPublishSuite))
+}
+
+// TestMQTTPublisher_ConnectionErrors tests that connection errors return a Delivery object alongside the error.
+func TestMQTTPublisher_ConnectionErrors(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name         string
+		serverURL    string
+		description  string
+		expectedCode string
+	}{
+		{
+			name:         "connection refused",
+			serverURL:    "tcp://127.0.0.1:1",
+			description:  "simulates a server that is not running",
+			expectedCode: "connection_refused",
+		},
+		{
+			name:         "DNS failure",
+			serverURL:    "tcp://this-domain-does-not-exist-abc123xyz.invalid:1883",
+			description:  "simulates an invalid/non-existent domain",
+			expectedCode: "dns_error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			provider, err := destmqtt.New(testutil.Registry.MetadataLoader(), nil)
+			require.NoError(t, err)
+
+			destination := testutil.DestinationFactory.Any(
+				testutil.DestinationFactory.WithType("mqtt"),
+				testutil.DestinationFactory.WithConfig(map[string]string{
+					"server_url": tt.serverURL,
+					"topic":      "devices/config",
+				}),
+				testutil.DestinationFactory.WithCredentials(map[string]string{}),
+			)
+
+			publisher, err := provider.CreatePublisher(context.Background(), &destination)
+			require.NoError(t, err)
+			defer publisher.Close()
+
+			event := testutil.EventFactory.Any(
+				testutil.EventFactory.WithDataMap(map[string]interface{}{"key": "value"}),
+			)
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			delivery, err := publisher.Publish(ctx, &event)
+
+			require.Error(t, err, "should return error for %s", tt.description)
+			require.NotNil(t, delivery, "delivery should NOT be nil for connection errors")
+			assert.Equal(t, "failed", delivery.Status, "delivery status should be 'failed'")
+			assert.Equal(t, tt.expectedCode, delivery.Code, "delivery code should indicate error type")
+		})
+	}
+}
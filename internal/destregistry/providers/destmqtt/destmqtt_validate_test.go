@@ -0,0 +1,266 @@
+package destmqtt_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"maps"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destmqtt"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMQTTDestination_Validate(t *testing.T) {
+	t.Parallel()
+
+	validDestination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("mqtt"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"server_url": "ssl://broker.example.com:8883",
+			"topic":      "devices/config",
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{}),
+	)
+
+	mqttDestination, err := destmqtt.New(testutil.Registry.MetadataLoader(), nil)
+	require.NoError(t, err)
+
+	assertValidationError := func(t *testing.T, err error, field, errType string) {
+		t.Helper()
+		var validationErr *destregistry.ErrDestinationValidation
+		if !assert.ErrorAs(t, err, &validationErr) {
+			return
+		}
+		assert.Equal(t, field, validationErr.Errors[0].Field)
+		assert.Equal(t, errType, validationErr.Errors[0].Type)
+	}
+
+	t.Run("should validate valid destination", func(t *testing.T) {
+		t.Parallel()
+		assert.NoError(t, mqttDestination.Validate(context.Background(), &validDestination))
+	})
+
+	t.Run("should validate missing server_url", func(t *testing.T) {
+		t.Parallel()
+		dest := validDestination
+		dest.Config = map[string]string{"topic": "devices/config"}
+		err := mqttDestination.Validate(context.Background(), &dest)
+		assertValidationError(t, err, "config.server_url", "required")
+	})
+
+	t.Run("should validate server_url scheme", func(t *testing.T) {
+		t.Parallel()
+		testCases := []struct {
+			url         string
+			shouldError bool
+		}{
+			{url: "tcp://localhost:1883", shouldError: false},
+			{url: "mqtt://localhost:1883", shouldError: false},
+			{url: "mqtts://broker.example.com:8883", shouldError: false},
+			{url: "wss://broker.example.com/mqtt", shouldError: false},
+			{url: "http://broker.example.com", shouldError: true},
+			{url: "localhost:1883", shouldError: true},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.url, func(t *testing.T) {
+				t.Parallel()
+				dest := validDestination
+				dest.Config = maps.Clone(validDestination.Config)
+				dest.Config["server_url"] = tc.url
+				err := mqttDestination.Validate(context.Background(), &dest)
+				if tc.shouldError {
+					assertValidationError(t, err, "config.server_url", "invalid")
+				} else {
+					assert.NoError(t, err)
+				}
+			})
+		}
+	})
+
+	t.Run("should not allow wildcards in topic", func(t *testing.T) {
+		t.Parallel()
+		for _, topic := range []string{"devices/+/config", "devices/#"} {
+			dest := validDestination
+			dest.Config = maps.Clone(validDestination.Config)
+			dest.Config["topic"] = topic
+			err := mqttDestination.Validate(context.Background(), &dest)
+			assertValidationError(t, err, "config.topic", "invalid")
+		}
+	})
+
+	t.Run("should validate topic_template", func(t *testing.T) {
+		t.Parallel()
+		dest := validDestination
+		dest.Config = maps.Clone(validDestination.Config)
+		dest.Config["topic_template"] = "join('/', ['devices', data.device_id"
+		err := mqttDestination.Validate(context.Background(), &dest)
+		assertValidationError(t, err, "config.topic_template", "invalid")
+	})
+
+	t.Run("should validate qos values", func(t *testing.T) {
+		t.Parallel()
+		testCases := []struct {
+			qos         string
+			shouldError bool
+		}{
+			{qos: "0", shouldError: false},
+			{qos: "1", shouldError: false},
+			{qos: "2", shouldError: false},
+			{qos: "", shouldError: false},
+			{qos: "3", shouldError: true},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.qos, func(t *testing.T) {
+				t.Parallel()
+				dest := validDestination
+				dest.Config = maps.Clone(validDestination.Config)
+				dest.Config["qos"] = tc.qos
+				err := mqttDestination.Validate(context.Background(), &dest)
+				if tc.shouldError {
+					assertValidationError(t, err, "config.qos", "invalid")
+				} else {
+					assert.NoError(t, err)
+				}
+			})
+		}
+	})
+
+	t.Run("should validate client certificate", func(t *testing.T) {
+		t.Parallel()
+		cert, key := selfSignedCertificate(t)
+
+		testCases := []struct {
+			name          string
+			serverURL     string
+			credentials   map[string]string
+			expectedField string
+			expectedType  string
+		}{
+			{
+				name:        "valid certificate and key",
+				serverURL:   "ssl://broker.example.com:8883",
+				credentials: map[string]string{"client_certificate": cert, "client_key": key, "ca_certificate": cert},
+			},
+			{
+				name:          "certificate without key",
+				serverURL:     "ssl://broker.example.com:8883",
+				credentials:   map[string]string{"client_certificate": cert},
+				expectedField: "credentials.client_key",
+				expectedType:  "required",
+			},
+			{
+				name:          "invalid certificate",
+				serverURL:     "ssl://broker.example.com:8883",
+				credentials:   map[string]string{"client_certificate": "not a certificate", "client_key": key},
+				expectedField: "credentials.client_certificate",
+				expectedType:  "invalid",
+			},
+			{
+				name:          "certificate without tls",
+				serverURL:     "tcp://broker.example.com:1883",
+				credentials:   map[string]string{"client_certificate": cert, "client_key": key},
+				expectedField: "config.server_url",
+				expectedType:  "invalid",
+			},
+			{
+				name:          "invalid ca certificate",
+				serverURL:     "ssl://broker.example.com:8883",
+				credentials:   map[string]string{"ca_certificate": "not a certificate"},
+				expectedField: "credentials.ca_certificate",
+				expectedType:  "invalid",
+			},
+		}
+
+		for _, tc := range testCases {
+			t.Run(tc.name, func(t *testing.T) {
+				t.Parallel()
+				dest := validDestination
+				dest.Config = maps.Clone(validDestination.Config)
+				dest.Config["server_url"] = tc.serverURL
+				dest.Credentials = tc.credentials
+				err := mqttDestination.Validate(context.Background(), &dest)
+				if tc.expectedField == "" {
+					assert.NoError(t, err)
+				} else {
+					assertValidationError(t, err, tc.expectedField, tc.expectedType)
+				}
+			})
+		}
+	})
+}
+
+func TestMQTTDestination_Preprocess(t *testing.T) {
+	t.Parallel()
+
+	mqttDestination, err := destmqtt.New(testutil.Registry.MetadataLoader(), nil)
+	require.NoError(t, err)
+
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("mqtt"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"server_url": "tcp://localhost:1883",
+			"topic":      "devices/config",
+			"retain":     "on",
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{}),
+	)
+	require.NoError(t, mqttDestination.Preprocess(&dest, nil, &destregistry.PreprocessDestinationOpts{}))
+	assert.Equal(t, "1", dest.Config["qos"])
+	assert.Equal(t, "true", dest.Config["retain"])
+	assert.Equal(t, "false", dest.Config["metadata_in_payload"])
+}
+
+func TestMQTTDestination_ComputeTarget(t *testing.T) {
+	t.Parallel()
+
+	mqttDestination, err := destmqtt.New(testutil.Registry.MetadataLoader(), nil)
+	require.NoError(t, err)
+
+	dest := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("mqtt"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"server_url": "ssl://broker.example.com:8883",
+			"topic":      "devices/config",
+		}),
+	)
+	target := mqttDestination.ComputeTarget(&dest)
+	assert.Equal(t, "broker.example.com:8883 / devices/config", target.Target)
+	assert.Empty(t, target.TargetURL)
+}
+
+// selfSignedCertificate returns a PEM-encoded certificate and private key.
+func selfSignedCertificate(t *testing.T) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "outpost"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+
+		BasicConstraintsValid: true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return string(cert), string(keyPEM)
+}
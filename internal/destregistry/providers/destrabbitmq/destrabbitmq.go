@@ -2,6 +2,7 @@ package destrabbitmq
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"strings"
@@ -22,6 +23,8 @@ type RabbitMQDestinationConfig struct {
 	ServerURL string // TODO: consider renaming
 	Exchange  string
 	UseTLS    bool
+	// ServerTLS verifies the server's certificate, nil for the system CAs.
+	ServerTLS *destregistry.ServerTLS
 }
 
 type RabbitMQDestinationCredentials struct {
@@ -56,6 +59,20 @@ func (d *RabbitMQDestination) Validate(ctx context.Context, destination *models.
 		}
 	}
 
+	// A CA bundle or skipped verification only applies to TLS connections
+	serverTLS, err := destregistry.ResolveServerTLS(destination)
+	if err != nil {
+		return err
+	}
+	if serverTLS != nil && !isTrue(destination.Config["tls"]) {
+		return destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
+			{
+				Field: "config.tls",
+				Type:  "invalid",
+			},
+		})
+	}
+
 	return nil
 }
 
@@ -64,10 +81,16 @@ func (d *RabbitMQDestination) CreatePublisher(ctx context.Context, destination *
 	if err != nil {
 		return nil, err
 	}
+	var tlsConfig *tls.Config
+	if config.ServerTLS != nil {
+		tlsConfig = &tls.Config{}
+		config.ServerTLS.Apply(tlsConfig)
+	}
 	return &RabbitMQPublisher{
-		BasePublisher: d.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata)),
+		BasePublisher: d.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata), destregistry.WithDestinationID(destination.ID)),
 		url:           rabbitURL(config, credentials),
 		exchange:      config.Exchange,
+		tlsConfig:     tlsConfig,
 	}, nil
 }
 
@@ -76,15 +99,18 @@ func (d *RabbitMQDestination) resolveMetadata(ctx context.Context, destination *
 		return nil, nil, err
 	}
 
-	useTLS := false // default to false if omitted
-	if tlsStr, ok := destination.Config["tls"]; ok {
-		useTLS = tlsStr == "true" || tlsStr == "on"
+	useTLS := isTrue(destination.Config["tls"]) // default to false if omitted
+
+	serverTLS, err := destregistry.ResolveServerTLS(destination)
+	if err != nil {
+		return nil, nil, err
 	}
 
 	return &RabbitMQDestinationConfig{
 			ServerURL: destination.Config["server_url"],
 			Exchange:  destination.Config["exchange"],
 			UseTLS:    useTLS,
+			ServerTLS: serverTLS,
 		}, &RabbitMQDestinationCredentials{
 			Username: destination.Credentials["username"],
 			Password: destination.Credentials["password"],
@@ -101,6 +127,9 @@ func (d *RabbitMQDestination) Preprocess(newDestination *models.Destination, ori
 	} else if newDestination.Config["tls"] == "" {
 		newDestination.Config["tls"] = "false" // default to false if omitted
 	}
+	if newDestination.Config["tls_skip_verify"] == "on" {
+		newDestination.Config["tls_skip_verify"] = "true"
+	}
 	if _, _, err := d.resolveMetadata(context.Background(), newDestination); err != nil {
 		return err
 	}
@@ -114,6 +143,9 @@ type RabbitMQPublisher struct {
 	conn     *amqp091.Connection
 	channel  *amqp091.Channel
 	mu       sync.Mutex
+
+	// tlsConfig, if set, replaces how amqps connections verify the server.
+	tlsConfig *tls.Config
 }
 
 func (p *RabbitMQPublisher) Close() error {
@@ -197,7 +229,13 @@ func (p *RabbitMQPublisher) ensureConnection(_ context.Context) error {
 	}
 
 	// Create new connection
-	conn, err := amqp091.Dial(p.url)
+	var conn *amqp091.Connection
+	var err error
+	if p.tlsConfig != nil {
+		conn, err = amqp091.DialTLS(p.url, p.tlsConfig)
+	} else {
+		conn, err = amqp091.Dial(p.url)
+	}
 	if err != nil {
 		return fmt.Errorf("failed to connect to RabbitMQ: %w", err)
 	}
@@ -222,6 +260,10 @@ func (p *RabbitMQPublisher) ensureConnection(_ context.Context) error {
 	return nil
 }
 
+func isTrue(value string) bool {
+	return value == "true" || value == "on"
+}
+
 func rabbitURL(config *RabbitMQDestinationConfig, credentials *RabbitMQDestinationCredentials) string {
 	scheme := "amqp"
 	if config.UseTLS {
@@ -234,7 +276,9 @@ func rabbitURL(config *RabbitMQDestinationConfig, credentials *RabbitMQDestinati
 // All errors classified here are destination-level failures (DeliveryError → ack + retry).
 //
 // Error codes and their meanings:
-//   - dns_error:           Domain doesn't exist or DNS lookup failed
+//   - dns_nxdomain:        Domain doesn't exist
+//   - dns_timeout:         DNS resolver didn't answer in time
+//   - dns_error:           Other DNS lookup failures
 //   - connection_refused:  Server not running or rejecting connections
 //   - connection_reset:    Connection was dropped by the server
 //   - auth_failed:         Authentication/authorization failure
@@ -268,6 +312,9 @@ func ClassifyRabbitMQError(err error) string {
 	}
 
 	// Fall back to string matching for network-level errors
+	if code := destregistry.ClassifyDNSError(err); code != "" {
+		return code
+	}
 	switch {
 	case strings.Contains(errStr, "no such host"):
 		return "dns_error"
@@ -215,6 +215,22 @@ func TestRabbitMQDestination_Validate(t *testing.T) {
 		delete(dest.Config, "tls")
 		assert.NoError(t, rabbitmqDestination.Validate(context.Background(), &dest))
 	})
+	t.Run("should validate server verification only with tls", func(t *testing.T) {
+		t.Parallel()
+		dest := validDestination
+		dest.Config = maps.Clone(validDestination.Config)
+		dest.Credentials = maps.Clone(validDestination.Credentials)
+		dest.Config["tls_skip_verify"] = "true"
+
+		dest.Config["tls"] = "false"
+		err := rabbitmqDestination.Validate(context.Background(), &dest)
+		var validationErr *destregistry.ErrDestinationValidation
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "config.tls", validationErr.Errors[0].Field)
+
+		dest.Config["tls"] = "true"
+		assert.NoError(t, rabbitmqDestination.Validate(context.Background(), &dest))
+	})
 }
 
 func TestRabbitMQDestination_ComputeTarget(t *testing.T) {
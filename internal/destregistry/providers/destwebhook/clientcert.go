@@ -0,0 +1,132 @@
+package destwebhook
+
+import (
+	"crypto/tls"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// Deliveries present a client certificate to endpoints that require mutual
+// TLS. A destination either uploads its own certificate in
+// credentials.client_certificate and credentials.client_key, or names one of
+// the deployment's certificates in config.client_certificate_name. The
+// deployment's certificates are the operator's, so only an admin can name
+// one: a tenant could otherwise present another tenant's identity.
+
+// tlsCredentialKeys are the credential fields holding a destination's own
+// client certificate and the CA bundle its endpoint is verified with.
+var tlsCredentialKeys = []string{"client_certificate", "client_key", "ca_certificate"}
+
+// LoadClientCertificates loads the client certificates of a directory, keyed
+// by name: each "<name>.crt" PEM certificate with its "<name>.key" private
+// key. An empty dir loads none.
+func LoadClientCertificates(dir string) (map[string]tls.Certificate, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if _, err := os.Stat(dir); err != nil {
+		return nil, err
+	}
+	certFiles, err := filepath.Glob(filepath.Join(dir, "*.crt"))
+	if err != nil {
+		return nil, err
+	}
+	certificates := make(map[string]tls.Certificate, len(certFiles))
+	for _, certFile := range certFiles {
+		name := strings.TrimSuffix(filepath.Base(certFile), ".crt")
+		cert, err := tls.LoadX509KeyPair(certFile, strings.TrimSuffix(certFile, ".crt")+".key")
+		if err != nil {
+			return nil, fmt.Errorf("client certificate %q: %w", name, err)
+		}
+		certificates[name] = cert
+	}
+	return certificates, nil
+}
+
+// ResolveClientCertificate returns the client certificate deliveries to the
+// destination present, or nil when it has none. certificates are the
+// deployment's certificates destinations can name.
+func ResolveClientCertificate(destination *models.Destination, certificates map[string]tls.Certificate) (*tls.Certificate, error) {
+	invalid := func(field, errType string) error {
+		return destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+			Field: field,
+			Type:  errType,
+		}})
+	}
+
+	cert, key := destination.Credentials["client_certificate"], destination.Credentials["client_key"]
+	if name := destination.Config["client_certificate_name"]; name != "" {
+		// A destination uses either its own certificate or a named one
+		if cert != "" || key != "" {
+			return nil, invalid("credentials.client_certificate", "forbidden")
+		}
+		certificate, ok := certificates[name]
+		if !ok {
+			return nil, invalid("config.client_certificate_name", "invalid")
+		}
+		return &certificate, nil
+	}
+
+	if cert == "" && key == "" {
+		return nil, nil
+	}
+	if cert == "" {
+		return nil, invalid("credentials.client_certificate", "required")
+	}
+	if key == "" {
+		return nil, invalid("credentials.client_key", "required")
+	}
+	certificate, err := tls.X509KeyPair([]byte(cert), []byte(key))
+	if err != nil {
+		return nil, invalid("credentials.client_certificate", "invalid")
+	}
+	return &certificate, nil
+}
+
+// CheckClientCertificateName forbids callers other than an admin from setting,
+// changing or removing a destination's config.client_certificate_name.
+func CheckClientCertificateName(newDestination, originalDestination *models.Destination, opts *destregistry.PreprocessDestinationOpts) error {
+	if opts.Role == "admin" {
+		return nil
+	}
+	var previous string
+	if originalDestination != nil {
+		previous = originalDestination.Config["client_certificate_name"]
+	}
+	if newDestination.Config["client_certificate_name"] == previous {
+		return nil
+	}
+	return destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+		Field: "config.client_certificate_name",
+		Type:  "forbidden",
+	}})
+}
+
+// KeepTLSCredentials copies the client certificate and CA bundle of a
+// destination's credentials into the cleaned credentials Preprocess stores.
+// An obfuscated key sent back by a client is replaced with the stored key it
+// stands for.
+func KeepTLSCredentials(clean, credentials, original map[string]string) {
+	for _, key := range tlsCredentialKeys {
+		if value := credentials[key]; value != "" {
+			clean[key] = value
+		}
+	}
+	if stored := original["client_key"]; stored != "" && clean["client_key"] == destregistry.ObfuscateValue(stored) {
+		clean["client_key"] = stored
+	}
+}
+
+// ObfuscateClientKey obfuscates the client certificate key of a credentials
+// map in place. Unlike signing secrets, the key is never generated by Outpost,
+// so it is never returned in full.
+func ObfuscateClientKey(credentials map[string]string) {
+	if key, ok := credentials["client_key"]; ok {
+		credentials["client_key"] = destregistry.ObfuscateValue(key)
+	}
+}
@@ -0,0 +1,323 @@
+package destwebhook_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newClientCertificatePEM returns a self-signed client certificate and its
+// key, PEM-encoded.
+func newClientCertificatePEM(t *testing.T, commonName string) (string, string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})),
+		string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}))
+}
+
+func assertValidationError(t *testing.T, err error, field, errType string) {
+	t.Helper()
+	var validationErr *destregistry.ErrDestinationValidation
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, field, validationErr.Errors[0].Field)
+	assert.Equal(t, errType, validationErr.Errors[0].Type)
+}
+
+func TestLoadClientCertificates(t *testing.T) {
+	t.Parallel()
+
+	t.Run("loads certificates by name", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		cert, key := newClientCertificatePEM(t, "partner")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "partner.crt"), []byte(cert), 0o600))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "partner.key"), []byte(key), 0o600))
+
+		certificates, err := destwebhook.LoadClientCertificates(dir)
+		require.NoError(t, err)
+		require.Contains(t, certificates, "partner")
+		leaf, err := x509.ParseCertificate(certificates["partner"].Certificate[0])
+		require.NoError(t, err)
+		assert.Equal(t, "partner", leaf.Subject.CommonName)
+	})
+
+	t.Run("empty path loads none", func(t *testing.T) {
+		t.Parallel()
+		certificates, err := destwebhook.LoadClientCertificates("")
+		require.NoError(t, err)
+		assert.Empty(t, certificates)
+	})
+
+	t.Run("missing key", func(t *testing.T) {
+		t.Parallel()
+		dir := t.TempDir()
+		cert, _ := newClientCertificatePEM(t, "partner")
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "partner.crt"), []byte(cert), 0o600))
+
+		_, err := destwebhook.LoadClientCertificates(dir)
+		assert.ErrorContains(t, err, `client certificate "partner"`)
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		t.Parallel()
+		_, err := destwebhook.LoadClientCertificates(filepath.Join(t.TempDir(), "missing"))
+		assert.Error(t, err)
+	})
+}
+
+func TestResolveClientCertificate(t *testing.T) {
+	t.Parallel()
+
+	cert, key := newClientCertificatePEM(t, "uploaded")
+	namedCert, namedKey := newClientCertificatePEM(t, "named")
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "partner.crt"), []byte(namedCert), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "partner.key"), []byte(namedKey), 0o600))
+	certificates, err := destwebhook.LoadClientCertificates(dir)
+	require.NoError(t, err)
+
+	newDestination := func(config, credentials map[string]string) *models.Destination {
+		config["url"] = "https://example.com"
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(config),
+			testutil.DestinationFactory.WithCredentials(credentials),
+		)
+		return &destination
+	}
+	commonName := func(t *testing.T, certificate []byte) string {
+		t.Helper()
+		leaf, err := x509.ParseCertificate(certificate)
+		require.NoError(t, err)
+		return leaf.Subject.CommonName
+	}
+
+	t.Run("none", func(t *testing.T) {
+		t.Parallel()
+		certificate, err := destwebhook.ResolveClientCertificate(newDestination(map[string]string{}, map[string]string{}), certificates)
+		require.NoError(t, err)
+		assert.Nil(t, certificate)
+	})
+
+	t.Run("uploaded", func(t *testing.T) {
+		t.Parallel()
+		certificate, err := destwebhook.ResolveClientCertificate(newDestination(map[string]string{}, map[string]string{
+			"client_certificate": cert,
+			"client_key":         key,
+		}), certificates)
+		require.NoError(t, err)
+		require.NotNil(t, certificate)
+		assert.Equal(t, "uploaded", commonName(t, certificate.Certificate[0]))
+	})
+
+	t.Run("named", func(t *testing.T) {
+		t.Parallel()
+		certificate, err := destwebhook.ResolveClientCertificate(newDestination(map[string]string{
+			"client_certificate_name": "partner",
+		}, map[string]string{}), certificates)
+		require.NoError(t, err)
+		require.NotNil(t, certificate)
+		assert.Equal(t, "named", commonName(t, certificate.Certificate[0]))
+	})
+
+	t.Run("unknown name", func(t *testing.T) {
+		t.Parallel()
+		_, err := destwebhook.ResolveClientCertificate(newDestination(map[string]string{
+			"client_certificate_name": "unknown",
+		}, map[string]string{}), certificates)
+		assertValidationError(t, err, "config.client_certificate_name", "invalid")
+	})
+
+	t.Run("named and uploaded", func(t *testing.T) {
+		t.Parallel()
+		_, err := destwebhook.ResolveClientCertificate(newDestination(map[string]string{
+			"client_certificate_name": "partner",
+		}, map[string]string{
+			"client_certificate": cert,
+			"client_key":         key,
+		}), certificates)
+		assertValidationError(t, err, "credentials.client_certificate", "forbidden")
+	})
+
+	t.Run("certificate without key", func(t *testing.T) {
+		t.Parallel()
+		_, err := destwebhook.ResolveClientCertificate(newDestination(map[string]string{}, map[string]string{
+			"client_certificate": cert,
+		}), certificates)
+		assertValidationError(t, err, "credentials.client_key", "required")
+	})
+
+	t.Run("key without certificate", func(t *testing.T) {
+		t.Parallel()
+		_, err := destwebhook.ResolveClientCertificate(newDestination(map[string]string{}, map[string]string{
+			"client_key": key,
+		}), certificates)
+		assertValidationError(t, err, "credentials.client_certificate", "required")
+	})
+
+	t.Run("mismatched key", func(t *testing.T) {
+		t.Parallel()
+		_, err := destwebhook.ResolveClientCertificate(newDestination(map[string]string{}, map[string]string{
+			"client_certificate": cert,
+			"client_key":         namedKey,
+		}), certificates)
+		assertValidationError(t, err, "credentials.client_certificate", "invalid")
+	})
+}
+
+func TestCheckClientCertificateName(t *testing.T) {
+	t.Parallel()
+
+	newDestination := func(name string) *models.Destination {
+		config := map[string]string{"url": "https://example.com"}
+		if name != "" {
+			config["client_certificate_name"] = name
+		}
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(config),
+		)
+		return &destination
+	}
+	admin := &destregistry.PreprocessDestinationOpts{Role: "admin"}
+	tenant := &destregistry.PreprocessDestinationOpts{Role: "tenant"}
+
+	t.Run("admin sets a name", func(t *testing.T) {
+		t.Parallel()
+		assert.NoError(t, destwebhook.CheckClientCertificateName(newDestination("partner"), nil, admin))
+		assert.NoError(t, destwebhook.CheckClientCertificateName(newDestination("other"), newDestination("partner"), admin))
+	})
+
+	t.Run("tenant keeps the name", func(t *testing.T) {
+		t.Parallel()
+		assert.NoError(t, destwebhook.CheckClientCertificateName(newDestination(""), nil, tenant))
+		assert.NoError(t, destwebhook.CheckClientCertificateName(newDestination("partner"), newDestination("partner"), tenant))
+	})
+
+	t.Run("tenant sets a name", func(t *testing.T) {
+		t.Parallel()
+		err := destwebhook.CheckClientCertificateName(newDestination("partner"), nil, tenant)
+		assertValidationError(t, err, "config.client_certificate_name", "forbidden")
+	})
+
+	t.Run("tenant changes the name", func(t *testing.T) {
+		t.Parallel()
+		err := destwebhook.CheckClientCertificateName(newDestination("other"), newDestination("partner"), tenant)
+		assertValidationError(t, err, "config.client_certificate_name", "forbidden")
+	})
+
+	t.Run("tenant removes the name", func(t *testing.T) {
+		t.Parallel()
+		err := destwebhook.CheckClientCertificateName(newDestination(""), newDestination("partner"), tenant)
+		assertValidationError(t, err, "config.client_certificate_name", "forbidden")
+	})
+
+	t.Run("preprocess rejects a tenant's name", func(t *testing.T) {
+		t.Parallel()
+		err := NewTestProvider(t).Preprocess(newDestination("partner"), nil, tenant)
+		assertValidationError(t, err, "config.client_certificate_name", "forbidden")
+	})
+}
+
+func TestWebhookDestination_ClientCertificateCredentials(t *testing.T) {
+	t.Parallel()
+
+	cert, key := newClientCertificatePEM(t, "uploaded")
+	provider := NewTestProvider(t)
+
+	t.Run("validates the certificate", func(t *testing.T) {
+		t.Parallel()
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{"url": "https://example.com"}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{
+				"secret":             "test-secret",
+				"client_certificate": cert,
+			}),
+		)
+		err := provider.Validate(context.Background(), &destination)
+		assertValidationError(t, err, "credentials.client_key", "required")
+	})
+
+	t.Run("preprocess keeps the certificate for tenants", func(t *testing.T) {
+		t.Parallel()
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{"url": "https://example.com"}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{
+				"client_certificate": cert,
+				"client_key":         key,
+			}),
+		)
+		err := provider.Preprocess(&destination, nil, &destregistry.PreprocessDestinationOpts{Role: "tenant"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, destination.Credentials["secret"])
+		assert.Equal(t, cert, destination.Credentials["client_certificate"])
+		assert.Equal(t, key, destination.Credentials["client_key"])
+	})
+
+	t.Run("obfuscates the key", func(t *testing.T) {
+		t.Parallel()
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{"url": "https://example.com"}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{
+				"secret":             "test-secret",
+				"client_certificate": cert,
+				"client_key":         key,
+			}),
+		)
+		for _, result := range []*models.Destination{
+			provider.ObfuscateDestination(&destination),
+			provider.RevealDestination(&destination),
+		} {
+			assert.Equal(t, cert, result.Credentials["client_certificate"])
+			assert.Equal(t, destregistry.ObfuscateValue(key), result.Credentials["client_key"])
+		}
+	})
+
+	t.Run("preprocess restores an obfuscated key", func(t *testing.T) {
+		t.Parallel()
+		original := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{"url": "https://example.com"}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{
+				"secret":             "test-secret",
+				"client_certificate": cert,
+				"client_key":         key,
+			}),
+		)
+		updated := *provider.ObfuscateDestination(&original)
+		err := provider.Preprocess(&updated, &original, &destregistry.PreprocessDestinationOpts{Role: "tenant"})
+		require.NoError(t, err)
+		assert.Equal(t, key, updated.Credentials["client_key"])
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"crypto/rand"
+	"crypto/tls"
 	"encoding/base64"
 	"encoding/hex"
 	"encoding/json"
@@ -11,6 +12,7 @@ import (
 	"math/big"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"text/template"
 	"time"
@@ -18,6 +20,7 @@ import (
 	"github.com/Masterminds/sprig/v3"
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/destregistry/metadata"
+	"github.com/hookdeck/outpost/internal/dnscache"
 	"github.com/hookdeck/outpost/internal/models"
 )
 
@@ -96,22 +99,32 @@ type WebhookDestination struct {
 	headerPrefix             string
 	userAgent                string
 	proxyURL                 string
+	dnsCache                 *dnscache.Cache
 	signatureContentTemplate string
 	signatureHeaderTemplate  string
 	eventIDHeader            headerConfig
 	signatureHeader          headerConfig
 	timestampHeader          headerConfig
 	topicHeader              headerConfig
+	retryAfterHeader         headerConfig
+	maxRetryAfter            time.Duration
 	encoding                 string
 	algorithm                string
 	rawSigningSecretTemplate string
 	signingSecretTemplate    *template.Template
 	maxResponseBodyBytes     int
+	secretRetrievalPolicy    string
+	clientCertificates       map[string]tls.Certificate
 }
 
 type WebhookDestinationConfig struct {
-	URL           string
-	CustomHeaders map[string]string
+	URL             string
+	CustomHeaders   map[string]string
+	SignatureScheme string
+	// ClientCertificate is presented for mutual TLS, nil without one.
+	ClientCertificate *tls.Certificate
+	// ServerTLS verifies the endpoint's certificate, nil for the system CAs.
+	ServerTLS *destregistry.ServerTLS
 }
 
 type WebhookSecret struct {
@@ -153,6 +166,13 @@ func WithProxyURL(proxyURL string) Option {
 	}
 }
 
+// WithDNSCache resolves webhook hosts through the given DNS cache.
+func WithDNSCache(cache *dnscache.Cache) Option {
+	return func(w *WebhookDestination) {
+		w.dnsCache = cache
+	}
+}
+
 // WithMaxResponseBodyBytes caps how much of the destination response body is
 // stored on the attempt. 0 (default) disables the cap.
 func WithMaxResponseBodyBytes(maxBytes int) Option {
@@ -161,6 +181,22 @@ func WithMaxResponseBodyBytes(maxBytes int) Option {
 	}
 }
 
+// WithSecretRetrievalPolicy sets how signing secrets are returned when the
+// destination is displayed. See SecretRetrievalRetrievable and friends.
+func WithSecretRetrievalPolicy(policy string) Option {
+	return func(w *WebhookDestination) {
+		w.secretRetrievalPolicy = policy
+	}
+}
+
+// WithClientCertificates sets the deployment's client certificates,
+// destinations name in config.client_certificate_name.
+func WithClientCertificates(certificates map[string]tls.Certificate) Option {
+	return func(w *WebhookDestination) {
+		w.clientCertificates = certificates
+	}
+}
+
 // WithEventIDHeader sets the event ID header directive. A non-empty name pins
 // the exact header name (bypassing "<prefix>event-id"); disabled omits the
 // header. The name is trimmed of whitespace.
@@ -197,6 +233,24 @@ func WithTopicHeader(name string, disabled bool) Option {
 	}
 }
 
+// WithRetryAfterHeader sets the directive for the response header through
+// which an endpoint requests redelivery. A non-empty name pins the exact header
+// name (bypassing "<prefix>retry-after"); disabled ignores the header. The name
+// is trimmed of whitespace.
+func WithRetryAfterHeader(name string, disabled bool) Option {
+	return func(w *WebhookDestination) {
+		w.retryAfterHeader = headerConfig{name: strings.TrimSpace(name), disabled: disabled}
+	}
+}
+
+// WithMaxRetryAfter caps the redelivery delay an endpoint can request. 0
+// (default) disables the cap.
+func WithMaxRetryAfter(max time.Duration) Option {
+	return func(w *WebhookDestination) {
+		w.maxRetryAfter = max
+	}
+}
+
 func WithSignatureContentTemplate(template string) Option {
 	return func(w *WebhookDestination) {
 		w.signatureContentTemplate = template
@@ -319,6 +373,16 @@ func (d *WebhookDestination) ComputeTarget(destination *models.Destination) dest
 
 // ObfuscateDestination overrides the base implementation to handle webhook secrets
 func (d *WebhookDestination) ObfuscateDestination(destination *models.Destination) *models.Destination {
+	result := d.RevealDestination(destination)
+	RedactSecretCredentials(result.Credentials, d.secretRetrievalPolicy)
+	return result
+}
+
+// RevealDestination returns a copy of the destination with its signing
+// secrets in full regardless of the retrieval policy. It is used to answer
+// the request that created or rotated the secret. The client certificate key
+// is still obfuscated.
+func (d *WebhookDestination) RevealDestination(destination *models.Destination) *models.Destination {
 	result := *destination // shallow copy
 	result.Config = make(map[string]string, len(destination.Config))
 	result.Credentials = make(map[string]string, len(destination.Credentials))
@@ -339,16 +403,13 @@ func (d *WebhookDestination) ObfuscateDestination(destination *models.Destinatio
 	}
 
 	// Copy credentials, omitting expired previous_secret fields
-	// NOTE: Webhook secrets are intentionally not obfuscated for now because:
-	// 1. They're needed for secret rotation logic
-	// 2. They're less security-critical than other provider credentials (e.g. AWS keys)
-	// TODO: Implement proper secret obfuscation later if needed
 	for key, value := range destination.Credentials {
 		if skipPreviousSecret && (key == "previous_secret" || key == "previous_secret_invalid_at") {
 			continue
 		}
 		result.Credentials[key] = value
 	}
+	ObfuscateClientKey(result.Credentials)
 
 	return &result
 }
@@ -391,30 +452,27 @@ func (d *WebhookDestination) CreatePublisher(ctx context.Context, destination *m
 		})
 	}
 
+	scheme := d.signingScheme(config.SignatureScheme)
+	for i := range secrets {
+		// Keys were checked by resolveConfig
+		secrets[i].Key, _ = scheme.signingKey(secrets[i].Key)
+	}
+
 	sm := NewSignatureManager(
 		secrets,
-		WithSignatureFormatter(NewSignatureFormatter(d.signatureContentTemplate)),
-		WithHeaderFormatter(NewHeaderFormatter(d.signatureHeaderTemplate)),
-		WithEncoder(GetEncoder(d.encoding)),
-		WithAlgorithm(GetAlgorithm(d.algorithm)),
+		WithSignatureFormatter(NewSignatureFormatter(scheme.contentTemplate)),
+		WithHeaderFormatter(NewHeaderFormatter(scheme.headerTemplate)),
+		WithEncoder(GetEncoder(scheme.encoding)),
+		WithAlgorithm(GetAlgorithm(scheme.algorithm)),
 	)
 
-	var proxyURL *string
-	if d.proxyURL != "" {
-		proxyURL = &d.proxyURL
-	}
-
-	httpClient, err := destregistry.NewHTTPClient(destregistry.HTTPClientConfig{
-		UserAgent:     &d.userAgent,
-		ProxyURL:      proxyURL,
-		WrapTransport: WrapTransport,
-	})
+	httpClient, err := d.newHTTPClient(config.ClientCertificate, config.ServerTLS)
 	if err != nil {
 		return nil, err
 	}
 
 	return &WebhookPublisher{
-		BasePublisher:        d.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata)),
+		BasePublisher:        d.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata), destregistry.WithDestinationID(destination.ID)),
 		httpClient:           httpClient,
 		url:                  config.URL,
 		headerPrefix:         d.headerPrefix,
@@ -422,20 +480,47 @@ func (d *WebhookDestination) CreatePublisher(ctx context.Context, destination *m
 		signatureHeader:      d.signatureHeader,
 		timestampHeader:      d.timestampHeader,
 		topicHeader:          d.topicHeader,
+		retryAfterHeader:     d.retryAfterHeader,
+		maxRetryAfter:        d.maxRetryAfter,
 		secrets:              secrets,
+		scheme:               scheme,
 		sm:                   sm,
 		customHeaders:        config.CustomHeaders,
 		maxResponseBodyBytes: d.maxResponseBodyBytes,
 	}, nil
 }
 
+// newHTTPClient builds the client deliveries are sent with, presenting
+// clientCertificate when set and verifying the endpoint with serverTLS.
+func (d *WebhookDestination) newHTTPClient(clientCertificate *tls.Certificate, serverTLS *destregistry.ServerTLS) (*http.Client, error) {
+	var proxyURL *string
+	if d.proxyURL != "" {
+		proxyURL = &d.proxyURL
+	}
+	return destregistry.NewHTTPClient(destregistry.HTTPClientConfig{
+		UserAgent:         &d.userAgent,
+		ProxyURL:          proxyURL,
+		WrapTransport:     WrapTransport,
+		DNSCache:          d.dnsCache,
+		ClientCertificate: clientCertificate,
+		ServerTLS:         serverTLS,
+	})
+}
+
 func (d *WebhookDestination) resolveConfig(ctx context.Context, destination *models.Destination) (*WebhookDestinationConfig, *WebhookDestinationCredentials, error) {
 	if err := d.BaseProvider.Validate(ctx, destination); err != nil {
 		return nil, nil, err
 	}
 
 	config := &WebhookDestinationConfig{
-		URL: destination.Config["url"],
+		URL:             destination.Config["url"],
+		SignatureScheme: destination.Config["signature_scheme"],
+	}
+	if !validSignatureScheme(config.SignatureScheme) {
+		return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+			Field: "config.signature_scheme",
+			Type:  "invalid",
+		}})
 	}
 
 	// Parse custom headers from config
@@ -453,6 +538,18 @@ func (d *WebhookDestination) resolveConfig(ctx context.Context, destination *mod
 		}
 	}
 
+	clientCertificate, err := ResolveClientCertificate(destination, d.clientCertificates)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.ClientCertificate = clientCertificate
+
+	serverTLS, err := destregistry.ResolveServerTLS(destination)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.ServerTLS = serverTLS
+
 	// Parse credentials directly from map
 	creds := &WebhookDestinationCredentials{
 		Secret:         destination.Credentials["secret"],
@@ -502,11 +599,31 @@ func (d *WebhookDestination) resolveConfig(ctx context.Context, destination *mod
 		}})
 	}
 
+	// The scheme may require a secret format, e.g. "whsec_<base64>" for Svix
+	scheme := d.signingScheme(config.SignatureScheme)
+	for _, secret := range []struct {
+		field string
+		value string
+	}{
+		{"credentials.secret", creds.Secret},
+		{"credentials.previous_secret", creds.PreviousSecret},
+	} {
+		if secret.value == "" {
+			continue
+		}
+		if _, err := scheme.signingKey(secret.value); err != nil {
+			return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+				Field: secret.field,
+				Type:  "pattern",
+			}})
+		}
+	}
+
 	return config, creds, nil
 }
 
 // rotateSecret handles secret rotation and returns clean credentials
-func (d *WebhookDestination) rotateSecret(origDest *models.Destination, opts *destregistry.PreprocessDestinationOpts) (map[string]string, error) {
+func (d *WebhookDestination) rotateSecret(origDest *models.Destination, scheme string, opts *destregistry.PreprocessDestinationOpts) (map[string]string, error) {
 	if origDest == nil {
 		return nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{
 			{
@@ -531,7 +648,7 @@ func (d *WebhookDestination) rotateSecret(origDest *models.Destination, opts *de
 	creds["previous_secret"] = origDest.Credentials["secret"]
 
 	// Generate a new secret
-	secret, err := d.generateSignatureSecret()
+	secret, err := d.generateSignatureSecret(scheme)
 	if err != nil {
 		return nil, err
 	}
@@ -627,14 +744,14 @@ func (d *WebhookDestination) updateSecret(newDest, origDest *models.Destination,
 }
 
 // ensureInitializedCredentials ensures credentials are initialized for new destinations
-func (d *WebhookDestination) ensureInitializedCredentials(creds map[string]string) (map[string]string, error) {
+func (d *WebhookDestination) ensureInitializedCredentials(creds map[string]string, scheme string) (map[string]string, error) {
 	// If there are any credentials already, return them as is
 	if creds["secret"] != "" || creds["previous_secret"] != "" || creds["previous_secret_invalid_at"] != "" {
 		return creds, nil
 	}
 
 	// Otherwise generate a new secret
-	secret, err := d.generateSignatureSecret()
+	secret, err := d.generateSignatureSecret(scheme)
 	if err != nil {
 		return nil, err
 	}
@@ -663,21 +780,28 @@ func (d *WebhookDestination) validateAndSanitizeCredentials(creds map[string]str
 
 // Preprocess sets a default secret if one isn't provided and handles secret rotation
 func (d *WebhookDestination) Preprocess(newDestination *models.Destination, originalDestination *models.Destination, opts *destregistry.PreprocessDestinationOpts) error {
+	if err := CheckClientCertificateName(newDestination, originalDestination, opts); err != nil {
+		return err
+	}
+
 	// Initialize credentials if nil
 	if newDestination.Credentials == nil {
 		newDestination.Credentials = make(map[string]string)
 	}
+	if originalDestination != nil {
+		RestoreMaskedSecretCredentials(newDestination.Credentials, originalDestination.Credentials, d.secretRetrievalPolicy)
+	}
 
 	// Get clean credentials based on operation type
 	var cleanCredentials map[string]string
 	var err error
 	if isTruthy(newDestination.Credentials["rotate_secret"]) {
-		cleanCredentials, err = d.rotateSecret(originalDestination, opts)
+		cleanCredentials, err = d.rotateSecret(originalDestination, newDestination.Config["signature_scheme"], opts)
 	} else {
 		cleanCredentials, err = d.updateSecret(newDestination, originalDestination, opts)
 		// For new destinations, ensure credentials are initialized if needed
 		if err == nil && originalDestination == nil {
-			cleanCredentials, err = d.ensureInitializedCredentials(cleanCredentials)
+			cleanCredentials, err = d.ensureInitializedCredentials(cleanCredentials, newDestination.Config["signature_scheme"])
 		}
 	}
 	if err != nil {
@@ -689,6 +813,11 @@ func (d *WebhookDestination) Preprocess(newDestination *models.Destination, orig
 	if err != nil {
 		return err
 	}
+	var originalCredentials map[string]string
+	if originalDestination != nil {
+		originalCredentials = originalDestination.Credentials
+	}
+	KeepTLSCredentials(cleanCredentials, newDestination.Credentials, originalCredentials)
 
 	newDestination.Credentials = cleanCredentials
 	return nil
@@ -703,7 +832,10 @@ type WebhookPublisher struct {
 	signatureHeader      headerConfig
 	timestampHeader      headerConfig
 	topicHeader          headerConfig
+	retryAfterHeader     headerConfig
+	maxRetryAfter        time.Duration
 	secrets              []WebhookSecret
+	scheme               signingScheme
 	sm                   *SignatureManager
 	customHeaders        map[string]string
 	maxResponseBodyBytes int
@@ -726,9 +858,42 @@ func (p *WebhookPublisher) Publish(ctx context.Context, event *models.Event) (*d
 	}
 
 	result := ExecuteHTTPRequest(ctx, p.httpClient, httpReq, "webhook", p.maxResponseBodyBytes)
+	if result.Error == nil && result.Response != nil {
+		if after, ok := p.requestedRedelivery(result.Response); ok {
+			result.Delivery.Status = models.AttemptStatusDeferred
+			return result.Delivery, &destregistry.ErrRedeliveryRequested{Provider: "webhook", After: after}
+		}
+	}
 	return result.Delivery, result.Error
 }
 
+// requestedRedelivery returns the delay after which a successful response asks
+// for the event to be delivered again. The header value is a number of seconds
+// or a Go duration such as "90s" or "15m"; values that don't parse to a
+// positive delay are ignored.
+func (p *WebhookPublisher) requestedRedelivery(resp *http.Response) (time.Duration, bool) {
+	if p.retryAfterHeader.disabled {
+		return 0, false
+	}
+	value := strings.TrimSpace(resp.Header.Get(resolveHeaderName(p.retryAfterHeader, p.headerPrefix, "retry-after")))
+	if value == "" {
+		return 0, false
+	}
+	var after time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		after = time.Duration(seconds) * time.Second
+	} else if duration, err := time.ParseDuration(value); err == nil {
+		after = duration
+	}
+	if after <= 0 {
+		return 0, false
+	}
+	if p.maxRetryAfter > 0 && after > p.maxRetryAfter {
+		after = p.maxRetryAfter
+	}
+	return after, true
+}
+
 // Format is a helper function to format the event data into an HTTP request.
 func (p *WebhookPublisher) Format(ctx context.Context, event *models.Event) (*http.Request, error) {
 	now := time.Now()
@@ -760,8 +925,19 @@ func (p *WebhookPublisher) Format(ctx context.Context, event *models.Event) (*ht
 		req.Header.Set(name, value)
 	}
 
-	// Add signature header unless disabled
-	if !p.signatureHeader.disabled {
+	if token := destregistry.AckToken(ctx); token != "" {
+		req.Header.Set(p.headerPrefix+"ack-token", token)
+	}
+
+	// Schemes that pin their headers sign every request; the default scheme
+	// follows the signature header directive.
+	if p.scheme.idHeader != "" {
+		req.Header.Set(p.scheme.idHeader, event.ID)
+	}
+	if p.scheme.timestampHeader != "" {
+		req.Header.Set(p.scheme.timestampHeader, strconv.FormatInt(now.Unix(), 10))
+	}
+	if p.scheme.signatureHeader != "" || !p.signatureHeader.disabled {
 		signatureHeader := p.sm.GenerateSignatureHeader(SignaturePayload{
 			EventID:   event.ID,
 			Topic:     event.Topic,
@@ -769,19 +945,34 @@ func (p *WebhookPublisher) Format(ctx context.Context, event *models.Event) (*ht
 			Body:      string(rawBody),
 		})
 		if signatureHeader != "" {
-			req.Header.Set(resolveHeaderName(p.signatureHeader, p.headerPrefix, "signature"), signatureHeader)
+			req.Header.Set(p.signatureHeaderName(), signatureHeader)
 		}
 	}
 
 	return req, nil
 }
 
+// signatureHeaderName returns the header the signature is sent in.
+func (p *WebhookPublisher) signatureHeaderName() string {
+	if p.scheme.signatureHeader != "" {
+		return p.scheme.signatureHeader
+	}
+	return resolveHeaderName(p.signatureHeader, p.headerPrefix, "signature")
+}
+
+// idempotencyKeyHeader carries the delivery's idempotency key under the name
+// receivers conventionally look for, regardless of the header prefix.
+const idempotencyKeyHeader = "Idempotency-Key"
+
 // resolveMetadataHeaderName returns the header name to use for a metadata key
 // and whether it should be emitted. Known system keys (event-id, timestamp,
-// topic) follow their configured directive; unknown keys always use the prefix.
+// topic) follow their configured directive; idempotency-key is always sent as
+// Idempotency-Key; unknown keys always use the prefix.
 func (p *WebhookPublisher) resolveMetadataHeaderName(key string) (string, bool) {
 	var cfg headerConfig
 	switch key {
+	case "idempotency-key":
+		return idempotencyKeyHeader, true
 	case "event-id":
 		cfg = p.eventIDHeader
 	case "timestamp":
@@ -807,8 +998,13 @@ func resolveHeaderName(cfg headerConfig, prefix, key string) string {
 }
 
 // generateSignatureSecret creates a cryptographically secure random secret using the configured template.
-// The default template produces a 64-character hex string (32 random bytes).
-func (d *WebhookDestination) generateSignatureSecret() (string, error) {
+// The default template produces a 64-character hex string (32 random bytes). Svix
+// destinations get a secret in the format Svix libraries expect instead.
+func (d *WebhookDestination) generateSignatureSecret(scheme string) (string, error) {
+	if scheme == SignatureSchemeSvix {
+		return generateSvixSecret()
+	}
+
 	randomBytes := make([]byte, 32)
 	if _, err := rand.Read(randomBytes); err != nil {
 		return "", fmt.Errorf("failed to generate random bytes: %w", err)
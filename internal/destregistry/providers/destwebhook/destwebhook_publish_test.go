@@ -12,6 +12,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
 	testsuite "github.com/hookdeck/outpost/internal/destregistry/testing"
 	"github.com/hookdeck/outpost/internal/models"
@@ -648,6 +649,42 @@ func TestWebhookPublisher_CustomHeaderNames(t *testing.T) {
 	assert.Empty(t, req.Header.Get("x-outpost-topic"))
 }
 
+func TestWebhookPublisher_AckToken(t *testing.T) {
+	t.Parallel()
+
+	provider := NewTestProvider(t, destwebhook.WithHeaderPrefix("x-outpost-"))
+
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"url": "http://example.com/webhook",
+		}),
+		testutil.DestinationFactory.WithCredentials(map[string]string{
+			"secret": "test-secret",
+		}),
+	)
+
+	publisher, err := provider.CreatePublisher(context.Background(), &destination)
+	require.NoError(t, err)
+
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithMetadata(map[string]string{"ack-token": "user-value"}),
+	)
+
+	t.Run("sends the token of the publish context", func(t *testing.T) {
+		ctx := destregistry.WithAckToken(context.Background(), "tok_123")
+		req, err := publisher.(*destwebhook.WebhookPublisher).Format(ctx, &event)
+		require.NoError(t, err)
+		assert.Equal(t, "tok_123", req.Header.Get("x-outpost-ack-token"))
+	})
+
+	t.Run("leaves event metadata alone without a token", func(t *testing.T) {
+		req, err := publisher.(*destwebhook.WebhookPublisher).Format(context.Background(), &event)
+		require.NoError(t, err)
+		assert.Equal(t, "user-value", req.Header.Get("x-outpost-ack-token"))
+	})
+}
+
 func TestWebhookPublisher_DeliveryMetadata(t *testing.T) {
 	t.Parallel()
 
@@ -707,6 +744,10 @@ func TestWebhookPublisher_DeliveryMetadata(t *testing.T) {
 		assert.Equal(t, "evt_123", req.Header.Get("x-outpost-event-id"))
 		assert.Equal(t, "user.created", req.Header.Get("x-outpost-topic"))
 
+		// Verify the idempotency key uses the unprefixed header
+		assert.Equal(t, destregistry.IdempotencyKey("evt_123", destination.ID), req.Header.Get("Idempotency-Key"))
+		assert.Empty(t, req.Header.Get("x-outpost-idempotency-key"))
+
 		// Verify event metadata present
 		assert.Equal(t, "usr_456", req.Header.Get("x-outpost-user-id"))
 
@@ -900,7 +941,7 @@ func TestWebhookPublisher_ConnectionErrors(t *testing.T) {
 			name:         "DNS failure",
 			url:          "http://this-domain-does-not-exist-abc123xyz.invalid/webhook",
 			description:  "simulates an invalid/non-existent domain",
-			expectedCode: "dns_error",
+			expectedCode: "dns_nxdomain",
 		},
 	}
 
@@ -1012,6 +1053,88 @@ func TestWebhookPublisher_HTTPErrors(t *testing.T) {
 	}
 }
 
+// TestWebhookPublisher_RetryAfter verifies that a successful response carrying
+// the retry-after header defers the delivery instead of completing it.
+func TestWebhookPublisher_RetryAfter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name      string
+		opts      []destwebhook.Option
+		header    string
+		value     string
+		wantAfter time.Duration
+	}{
+		{name: "seconds", header: "x-outpost-retry-after", value: "30", wantAfter: 30 * time.Second},
+		{name: "duration", header: "x-outpost-retry-after", value: "15m", wantAfter: 15 * time.Minute},
+		{
+			name:      "clamped to max",
+			opts:      []destwebhook.Option{destwebhook.WithMaxRetryAfter(time.Hour)},
+			header:    "x-outpost-retry-after",
+			value:     "48h",
+			wantAfter: time.Hour,
+		},
+		{
+			name:      "custom header name",
+			opts:      []destwebhook.Option{destwebhook.WithRetryAfterHeader("x-replay-after", false)},
+			header:    "x-replay-after",
+			value:     "10",
+			wantAfter: 10 * time.Second,
+		},
+		{name: "invalid value is ignored", header: "x-outpost-retry-after", value: "later"},
+		{name: "zero is ignored", header: "x-outpost-retry-after", value: "0"},
+		{
+			name:   "disabled",
+			opts:   []destwebhook.Option{destwebhook.WithRetryAfterHeader("", true)},
+			header: "x-outpost-retry-after",
+			value:  "30",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(tt.header, tt.value)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			provider := NewTestProvider(t, tt.opts...)
+			destination := testutil.DestinationFactory.Any(
+				testutil.DestinationFactory.WithType("webhook"),
+				testutil.DestinationFactory.WithConfig(map[string]string{
+					"url": server.URL + "/webhook",
+				}),
+				testutil.DestinationFactory.WithCredentials(map[string]string{
+					"secret": "test-secret",
+				}),
+			)
+
+			publisher, err := provider.CreatePublisher(context.Background(), &destination)
+			require.NoError(t, err)
+			defer publisher.Close()
+
+			event := testutil.EventFactory.Any()
+			delivery, err := publisher.Publish(context.Background(), &event)
+			require.NotNil(t, delivery)
+
+			if tt.wantAfter == 0 {
+				require.NoError(t, err)
+				assert.Equal(t, models.AttemptStatusSuccess, delivery.Status)
+				return
+			}
+
+			var redeliveryErr *destregistry.ErrRedeliveryRequested
+			require.ErrorAs(t, err, &redeliveryErr)
+			assert.Equal(t, tt.wantAfter, redeliveryErr.After)
+			assert.Equal(t, models.AttemptStatusDeferred, delivery.Status)
+			assert.Equal(t, "200", delivery.Code)
+		})
+	}
+}
+
 // TestWebhookPublisher_PreservesKeyOrder verifies that Format() sends
 // the original JSON key order in the HTTP request body.
 func TestWebhookPublisher_PreservesKeyOrder(t *testing.T) {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/models"
 	"github.com/hookdeck/outpost/internal/util/maputil"
 	"github.com/hookdeck/outpost/internal/util/testutil"
 	"github.com/stretchr/testify/assert"
@@ -1038,3 +1039,66 @@ func TestWebhookDestination_ObfuscateDestination(t *testing.T) {
 		assert.Empty(t, result.Credentials["previous_secret_invalid_at"])
 	})
 }
+
+func TestWebhookDestination_SecretRetrievalPolicy(t *testing.T) {
+	t.Parallel()
+
+	newDestination := func() models.Destination {
+		return testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{
+				"url": "https://example.com",
+			}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{
+				"secret":                     "whsec_current-secret-1234",
+				"previous_secret":            "whsec_old-secret-5678",
+				"previous_secret_invalid_at": time.Now().Add(24 * time.Hour).Format(time.RFC3339),
+			}),
+		)
+	}
+
+	t.Run("write_only omits secrets", func(t *testing.T) {
+		t.Parallel()
+		provider := NewTestProvider(t, destwebhook.WithSecretRetrievalPolicy(destwebhook.SecretRetrievalWriteOnly))
+		destination := newDestination()
+
+		result := provider.ObfuscateDestination(&destination)
+		assert.NotContains(t, result.Credentials, "secret")
+		assert.NotContains(t, result.Credentials, "previous_secret")
+		assert.NotEmpty(t, result.Credentials["previous_secret_invalid_at"])
+		assert.Equal(t, "whsec_current-secret-1234", destination.Credentials["secret"], "stored destination must not be modified")
+	})
+
+	t.Run("masked keeps last 4 characters", func(t *testing.T) {
+		t.Parallel()
+		provider := NewTestProvider(t, destwebhook.WithSecretRetrievalPolicy(destwebhook.SecretRetrievalMasked))
+		destination := newDestination()
+
+		result := provider.ObfuscateDestination(&destination)
+		assert.Equal(t, "********1234", result.Credentials["secret"])
+		assert.Equal(t, "********5678", result.Credentials["previous_secret"])
+	})
+
+	t.Run("reveal ignores the policy", func(t *testing.T) {
+		t.Parallel()
+		provider := NewTestProvider(t, destwebhook.WithSecretRetrievalPolicy(destwebhook.SecretRetrievalWriteOnly))
+		destination := newDestination()
+
+		result := provider.RevealDestination(&destination)
+		assert.Equal(t, "whsec_current-secret-1234", result.Credentials["secret"])
+		assert.Equal(t, "whsec_old-secret-5678", result.Credentials["previous_secret"])
+	})
+
+	t.Run("masked secrets sent back on update are kept", func(t *testing.T) {
+		t.Parallel()
+		provider := NewTestProvider(t, destwebhook.WithSecretRetrievalPolicy(destwebhook.SecretRetrievalMasked))
+		original := newDestination()
+		updated := newDestination()
+		updated.Credentials = provider.ObfuscateDestination(&original).Credentials
+
+		err := provider.Preprocess(&updated, &original, &destregistry.PreprocessDestinationOpts{Role: "tenant"})
+		require.NoError(t, err)
+		assert.Equal(t, "whsec_current-secret-1234", updated.Credentials["secret"])
+		assert.Equal(t, "whsec_old-secret-5678", updated.Credentials["previous_secret"])
+	})
+}
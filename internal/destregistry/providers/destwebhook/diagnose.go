@@ -0,0 +1,234 @@
+package destwebhook
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/dnscache"
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// diagnoseStepTimeout bounds each connectivity check.
+const diagnoseStepTimeout = 10 * time.Second
+
+var _ destregistry.ConnectivityDiagnoser = (*WebhookDestination)(nil)
+
+// Diagnose checks the destination URL can be reached with the proxy and DNS
+// settings deliveries use.
+func (d *WebhookDestination) Diagnose(ctx context.Context, destination *models.Destination) (*destregistry.ConnectivityReport, error) {
+	config, _, err := d.resolveConfig(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+	client, err := d.newHTTPClient(config.ClientCertificate, config.ServerTLS)
+	if err != nil {
+		return nil, err
+	}
+	return DiagnoseURL(ctx, config.URL, DiagnoseOptions{
+		Client:            client,
+		ProxyURL:          d.proxyURL,
+		DNSCache:          d.dnsCache,
+		ClientCertificate: config.ClientCertificate,
+		ServerTLS:         config.ServerTLS,
+	})
+}
+
+// DiagnoseOptions are the network settings deliveries to a URL use.
+type DiagnoseOptions struct {
+	// Client sends the HTTP probe. It should be built like the publisher's.
+	Client *http.Client
+	// ProxyURL, when set, is the proxy deliveries go through.
+	ProxyURL string
+	// DNSCache, when set, resolves hosts instead of the system resolver.
+	DNSCache *dnscache.Cache
+	// ClientCertificate, when set, is presented in the TLS handshake.
+	ClientCertificate *tls.Certificate
+	// ServerTLS, when set, replaces how the server's certificate is verified.
+	ServerTLS *destregistry.ServerTLS
+}
+
+// DiagnoseURL resolves the URL's host, opens a TCP connection to it, performs
+// the TLS handshake of https URLs and sends an OPTIONS request. A failed step
+// skips the ones after it. With a proxy, the DNS and TCP steps check the
+// proxy, and the TLS handshake happens within the probe through it.
+func DiagnoseURL(ctx context.Context, rawURL string, opts DiagnoseOptions) (*destregistry.ConnectivityReport, error) {
+	target, err := url.Parse(rawURL)
+	if err != nil || target.Host == "" {
+		return nil, fmt.Errorf("invalid url %q", rawURL)
+	}
+	dialHost, dialPort := target.Hostname(), portOf(target)
+	report := &destregistry.ConnectivityReport{
+		OK:     true,
+		Target: (&url.URL{Scheme: target.Scheme, Host: target.Host, Path: target.Path}).String(),
+		Steps:  []destregistry.DiagnosticStep{},
+	}
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy url: %w", err)
+		}
+		dialHost, dialPort = proxyURL.Hostname(), portOf(proxyURL)
+		report.Proxied = true
+	}
+
+	addrs, ok := diagnoseDNS(ctx, report, dialHost, opts.DNSCache)
+	if !ok {
+		skipSteps(report, destregistry.DiagnosticStepTCP, destregistry.DiagnosticStepTLS, destregistry.DiagnosticStepHTTP)
+		return report, nil
+	}
+
+	conn, ok := diagnoseTCP(ctx, report, addrs, dialPort)
+	if !ok {
+		skipSteps(report, destregistry.DiagnosticStepTLS, destregistry.DiagnosticStepHTTP)
+		return report, nil
+	}
+	if target.Scheme != "https" || report.Proxied {
+		conn.Close()
+		skipSteps(report, destregistry.DiagnosticStepTLS)
+	} else if !diagnoseTLS(ctx, report, conn, target.Hostname(), opts.ClientCertificate, opts.ServerTLS) {
+		skipSteps(report, destregistry.DiagnosticStepHTTP)
+		return report, nil
+	}
+
+	diagnoseHTTP(ctx, report, opts.Client, rawURL)
+	return report, nil
+}
+
+func diagnoseDNS(ctx context.Context, report *destregistry.ConnectivityReport, host string, cache *dnscache.Cache) ([]string, bool) {
+	ctx, cancel := context.WithTimeout(ctx, diagnoseStepTimeout)
+	defer cancel()
+
+	start := time.Now()
+	var addrs []string
+	var err error
+	if cache != nil {
+		addrs, err = cache.LookupHost(ctx, host)
+	} else {
+		addrs, err = net.DefaultResolver.LookupHost(ctx, host)
+	}
+	step := newStep(destregistry.DiagnosticStepDNS, start, err)
+	step.Details = map[string]any{"host": host}
+	if err == nil {
+		step.Details["addresses"] = addrs
+	}
+	report.AddStep(step)
+	return addrs, err == nil
+}
+
+func diagnoseTCP(ctx context.Context, report *destregistry.ConnectivityReport, addrs []string, port string) (net.Conn, bool) {
+	ctx, cancel := context.WithTimeout(ctx, diagnoseStepTimeout)
+	defer cancel()
+
+	start := time.Now()
+	var dialer net.Dialer
+	var conn net.Conn
+	var err error
+	var address string
+	for _, ip := range addrs {
+		address = net.JoinHostPort(ip, port)
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+	}
+	step := newStep(destregistry.DiagnosticStepTCP, start, err)
+	step.Details = map[string]any{"address": address}
+	report.AddStep(step)
+	return conn, err == nil
+}
+
+func diagnoseTLS(ctx context.Context, report *destregistry.ConnectivityReport, conn net.Conn, serverName string, clientCertificate *tls.Certificate, serverTLS *destregistry.ServerTLS) bool {
+	ctx, cancel := context.WithTimeout(ctx, diagnoseStepTimeout)
+	defer cancel()
+	defer conn.Close()
+
+	start := time.Now()
+	tlsConfig := &tls.Config{ServerName: serverName}
+	if clientCertificate != nil {
+		tlsConfig.Certificates = []tls.Certificate{*clientCertificate}
+	}
+	serverTLS.Apply(tlsConfig)
+	tlsConn := tls.Client(conn, tlsConfig)
+	err := tlsConn.HandshakeContext(ctx)
+	step := newStep(destregistry.DiagnosticStepTLS, start, err)
+	if err == nil {
+		state := tlsConn.ConnectionState()
+		step.Details = map[string]any{
+			"version":      tls.VersionName(state.Version),
+			"cipher_suite": tls.CipherSuiteName(state.CipherSuite),
+		}
+		if len(state.PeerCertificates) > 0 {
+			cert := state.PeerCertificates[0]
+			step.Details["certificate"] = map[string]any{
+				"subject":   cert.Subject.String(),
+				"issuer":    cert.Issuer.String(),
+				"dns_names": cert.DNSNames,
+				"not_after": cert.NotAfter.UTC(),
+			}
+		}
+	}
+	report.AddStep(step)
+	return err == nil
+}
+
+func diagnoseHTTP(ctx context.Context, report *destregistry.ConnectivityReport, client *http.Client, rawURL string) {
+	ctx, cancel := context.WithTimeout(ctx, diagnoseStepTimeout)
+	defer cancel()
+
+	start := time.Now()
+	req, err := http.NewRequestWithContext(ctx, http.MethodOptions, rawURL, nil)
+	if err != nil {
+		report.AddStep(newStep(destregistry.DiagnosticStepHTTP, start, err))
+		return
+	}
+	resp, err := client.Do(req)
+	step := newStep(destregistry.DiagnosticStepHTTP, start, err)
+	if err == nil {
+		// Any response means the endpoint is reachable; the status is reported
+		// since endpoints rarely implement OPTIONS.
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+		step.Details = map[string]any{"status_code": resp.StatusCode}
+		if allow := resp.Header.Get("Allow"); allow != "" {
+			step.Details["allow"] = allow
+		}
+	}
+	report.AddStep(step)
+}
+
+func newStep(name string, start time.Time, err error) destregistry.DiagnosticStep {
+	step := destregistry.DiagnosticStep{
+		Name:       name,
+		Status:     destregistry.DiagnosticStatusOK,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		step.Status = destregistry.DiagnosticStatusFailed
+		step.Code = ClassifyNetworkError(err)
+		step.Error = err.Error()
+	}
+	return step
+}
+
+func skipSteps(report *destregistry.ConnectivityReport, names ...string) {
+	for _, name := range names {
+		report.AddStep(destregistry.DiagnosticStep{Name: name, Status: destregistry.DiagnosticStatusSkipped})
+	}
+}
+
+func portOf(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	if u.Scheme == "https" {
+		return "443"
+	}
+	return "80"
+}
@@ -0,0 +1,145 @@
+package destwebhook_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/dnscache"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubResolver struct {
+	addrs []string
+	err   error
+}
+
+func (r stubResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return r.addrs, r.err
+}
+
+// diagnoseURL rewrites the server URL to a hostname resolved by the stub.
+func diagnoseURL(t *testing.T, serverURL string) string {
+	t.Helper()
+	u, err := url.Parse(serverURL)
+	require.NoError(t, err)
+	u.Host = net.JoinHostPort("webhook.example.test", u.Port())
+	u.Path = "/webhook"
+	return u.String()
+}
+
+func stepStatuses(report *destregistry.ConnectivityReport) map[string]string {
+	statuses := map[string]string{}
+	for _, step := range report.Steps {
+		statuses[step.Name] = step.Status
+	}
+	return statuses
+}
+
+func TestWebhookDestination_Diagnose(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodOptions, r.Method)
+		w.Header().Set("Allow", "POST, OPTIONS")
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	cache := dnscache.New(time.Minute, dnscache.WithResolver(stubResolver{addrs: []string{"127.0.0.1"}}))
+	provider := NewTestProvider(t, destwebhook.WithDNSCache(cache))
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithConfig(map[string]string{"url": diagnoseURL(t, server.URL) + "?token=secret"}),
+	)
+
+	report, err := provider.Diagnose(context.Background(), &destination)
+	require.NoError(t, err)
+
+	assert.True(t, report.OK)
+	assert.False(t, report.Proxied)
+	assert.NotContains(t, report.Target, "token", "the query string is not reported")
+	assert.Equal(t, map[string]string{
+		destregistry.DiagnosticStepDNS:  destregistry.DiagnosticStatusOK,
+		destregistry.DiagnosticStepTCP:  destregistry.DiagnosticStatusOK,
+		destregistry.DiagnosticStepTLS:  destregistry.DiagnosticStatusSkipped,
+		destregistry.DiagnosticStepHTTP: destregistry.DiagnosticStatusOK,
+	}, stepStatuses(report))
+	assert.Equal(t, []string{"127.0.0.1"}, report.Steps[0].Details["addresses"])
+	assert.Equal(t, http.StatusNoContent, report.Steps[3].Details["status_code"])
+	assert.Equal(t, "POST, OPTIONS", report.Steps[3].Details["allow"])
+}
+
+func TestDiagnoseURL_Failures(t *testing.T) {
+	t.Parallel()
+
+	t.Run("nxdomain skips the remaining steps", func(t *testing.T) {
+		t.Parallel()
+		nxdomain := &net.DNSError{Err: "no such host", Name: "webhook.example.test", IsNotFound: true}
+		cache := dnscache.New(time.Minute, dnscache.WithResolver(stubResolver{err: nxdomain}))
+
+		report, err := destwebhook.DiagnoseURL(context.Background(), "https://webhook.example.test/webhook", destwebhook.DiagnoseOptions{
+			Client:   http.DefaultClient,
+			DNSCache: cache,
+		})
+		require.NoError(t, err)
+
+		assert.False(t, report.OK)
+		require.Len(t, report.Steps, 4)
+		assert.Equal(t, destregistry.DiagnosticStatusFailed, report.Steps[0].Status)
+		assert.Equal(t, destregistry.CodeDNSNXDomain, report.Steps[0].Code)
+		for _, step := range report.Steps[1:] {
+			assert.Equal(t, destregistry.DiagnosticStatusSkipped, step.Status, step.Name)
+		}
+	})
+
+	t.Run("untrusted certificate fails the tls step", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+		cache := dnscache.New(time.Minute, dnscache.WithResolver(stubResolver{addrs: []string{"127.0.0.1"}}))
+
+		report, err := destwebhook.DiagnoseURL(context.Background(), diagnoseURL(t, server.URL), destwebhook.DiagnoseOptions{
+			Client:   http.DefaultClient,
+			DNSCache: cache,
+		})
+		require.NoError(t, err)
+
+		assert.False(t, report.OK)
+		assert.Equal(t, map[string]string{
+			destregistry.DiagnosticStepDNS:  destregistry.DiagnosticStatusOK,
+			destregistry.DiagnosticStepTCP:  destregistry.DiagnosticStatusOK,
+			destregistry.DiagnosticStepTLS:  destregistry.DiagnosticStatusFailed,
+			destregistry.DiagnosticStepHTTP: destregistry.DiagnosticStatusSkipped,
+		}, stepStatuses(report))
+		assert.Equal(t, "tls_error", report.Steps[2].Code)
+	})
+
+	t.Run("proxied deliveries check the proxy", func(t *testing.T) {
+		t.Parallel()
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer proxy.Close()
+		proxyURL, err := url.Parse(proxy.URL)
+		require.NoError(t, err)
+
+		report, err := destwebhook.DiagnoseURL(context.Background(), "http://webhook.example.test/webhook", destwebhook.DiagnoseOptions{
+			Client:   &http.Client{Transport: &http.Transport{Proxy: http.ProxyURL(proxyURL)}},
+			ProxyURL: proxy.URL,
+		})
+		require.NoError(t, err)
+
+		assert.True(t, report.OK)
+		assert.True(t, report.Proxied)
+		assert.Equal(t, "127.0.0.1", report.Steps[0].Details["host"])
+	})
+}
@@ -0,0 +1,101 @@
+package destwebhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// VerificationRequestType is the type of the challenge request sent to verify
+// an endpoint, so endpoints can tell it apart from events.
+const VerificationRequestType = "endpoint.verification"
+
+// verifyTimeout bounds the challenge request.
+const verifyTimeout = 10 * time.Second
+
+// maxVerifyResponseBytes caps the response body read from the endpoint.
+const maxVerifyResponseBytes = 64 << 10
+
+// VerificationRequest is the body of the challenge request. The endpoint must
+// respond with a 2xx status and the challenge, either as the whole body or as
+// the challenge field of a JSON object.
+type VerificationRequest struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+}
+
+var _ destregistry.EndpointVerifier = (*WebhookDestination)(nil)
+
+// VerifyEndpoint sends the challenge to the destination URL with the proxy,
+// DNS and custom header settings deliveries use.
+func (d *WebhookDestination) VerifyEndpoint(ctx context.Context, destination *models.Destination, challenge string) error {
+	config, _, err := d.resolveConfig(ctx, destination)
+	if err != nil {
+		return err
+	}
+	client, err := d.newHTTPClient(config.ClientCertificate, config.ServerTLS)
+	if err != nil {
+		return err
+	}
+	return VerifyURL(ctx, client, config.URL, config.CustomHeaders, challenge)
+}
+
+// VerifyURL POSTs a VerificationRequest with the challenge to rawURL and
+// checks the response echoes it. Failures wrap
+// destregistry.ErrEndpointNotVerified.
+func VerifyURL(ctx context.Context, client *http.Client, rawURL string, headers map[string]string, challenge string) error {
+	ctx, cancel := context.WithTimeout(ctx, verifyTimeout)
+	defer cancel()
+
+	body, err := json.Marshal(VerificationRequest{Type: VerificationRequestType, Challenge: challenge})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("%w: %w", destregistry.ErrEndpointNotVerified, err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%w: %w", destregistry.ErrEndpointNotVerified, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxVerifyResponseBytes))
+	if err != nil {
+		return fmt.Errorf("%w: failed to read response: %w", destregistry.ErrEndpointNotVerified, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("%w: endpoint returned status %d", destregistry.ErrEndpointNotVerified, resp.StatusCode)
+	}
+	if !echoesChallenge(respBody, challenge) {
+		return fmt.Errorf("%w: response did not echo the challenge", destregistry.ErrEndpointNotVerified)
+	}
+	return nil
+}
+
+func echoesChallenge(body []byte, challenge string) bool {
+	trimmed := bytes.TrimSpace(body)
+	if string(trimmed) == challenge {
+		return true
+	}
+	var echo struct {
+		Challenge string `json:"challenge"`
+	}
+	if err := json.Unmarshal(trimmed, &echo); err != nil {
+		return false
+	}
+	return challenge != "" && echo.Challenge == challenge
+}
@@ -0,0 +1,86 @@
+package destwebhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDestination_VerifyEndpoint(t *testing.T) {
+	t.Parallel()
+
+	var received destwebhook.VerificationRequest
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.Write([]byte(received.Challenge))
+	}))
+	defer server.Close()
+
+	provider := NewTestProvider(t)
+	destination := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithType("webhook"),
+		testutil.DestinationFactory.WithConfig(map[string]string{
+			"url":            server.URL + "/webhook",
+			"custom_headers": `{"Authorization":"Bearer token"}`,
+		}),
+	)
+
+	require.NoError(t, provider.VerifyEndpoint(context.Background(), &destination, "challenge-1"))
+	assert.Equal(t, destwebhook.VerificationRequestType, received.Type)
+	assert.Equal(t, "challenge-1", received.Challenge)
+	assert.Equal(t, "Bearer token", authorization, "custom headers are sent")
+}
+
+func TestVerifyURL(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		verified bool
+	}{
+		{name: "plain echo", status: http.StatusOK, body: "abc\n", verified: true},
+		{name: "json echo", status: http.StatusOK, body: `{"challenge":"abc"}`, verified: true},
+		{name: "other body", status: http.StatusOK, body: "ok", verified: false},
+		{name: "empty body", status: http.StatusNoContent, body: "", verified: false},
+		{name: "wrong json challenge", status: http.StatusOK, body: `{"challenge":"xyz"}`, verified: false},
+		{name: "error status", status: http.StatusNotFound, body: "abc", verified: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			err := destwebhook.VerifyURL(context.Background(), server.Client(), server.URL, nil, "abc")
+			if tt.verified {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, destregistry.ErrEndpointNotVerified)
+			}
+		})
+	}
+
+	t.Run("unreachable endpoint", func(t *testing.T) {
+		t.Parallel()
+		server := httptest.NewServer(http.NotFoundHandler())
+		server.Close()
+
+		err := destwebhook.VerifyURL(context.Background(), http.DefaultClient, server.URL, nil, "abc")
+		assert.ErrorIs(t, err, destregistry.ErrEndpointNotVerified)
+	})
+}
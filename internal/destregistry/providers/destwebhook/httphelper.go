@@ -133,7 +133,9 @@ func ExecuteHTTPRequest(ctx context.Context, client *http.Client, req *http.Requ
 // All errors classified here are destination-level failures (DeliveryError → ack + retry).
 //
 // Error codes and their meanings:
-//   - dns_error:          Domain doesn't exist or DNS lookup failed
+//   - dns_nxdomain:       Domain doesn't exist
+//   - dns_timeout:        DNS resolver didn't answer in time
+//   - dns_error:          Other DNS lookup failures
 //   - connection_refused: Server not running or rejecting connections
 //   - connection_reset:   Connection was dropped by the server
 //   - network_unreachable: Network path to destination is unavailable
@@ -145,6 +147,9 @@ func ClassifyNetworkError(err error) string {
 	if err == nil {
 		return "unknown"
 	}
+	if code := destregistry.ClassifyDNSError(err); code != "" {
+		return code
+	}
 
 	errStr := err.Error()
 
@@ -170,8 +175,9 @@ func ClassifyNetworkError(err error) string {
 	}
 }
 
-// ParseHTTPResponse reads the HTTP response body into the delivery as a raw string.
-// The body is stored verbatim regardless of content type to preserve data integrity.
+// ParseHTTPResponse reads the HTTP response status, headers and body into the
+// delivery. The body is stored verbatim as a raw string regardless of content
+// type to preserve data integrity.
 //
 // maxBytes caps the stored body so an oversized response can't push the attempt
 // log past the queue's per-message size limit (which would fail to publish and
@@ -195,7 +201,19 @@ func ParseHTTPResponse(delivery *destregistry.Delivery, resp *http.Response, max
 		body = string(bodyBytes)
 	}
 	delivery.Response = map[string]interface{}{
-		"status": resp.StatusCode,
-		"body":   body,
+		"status":  resp.StatusCode,
+		"headers": responseHeaders(resp.Header),
+		"body":    body,
+	}
+}
+
+// responseHeaders flattens the response headers into lowercase names, joining
+// repeated values with a comma. Credentials such as Set-Cookie are redacted
+// before the attempt is logged.
+func responseHeaders(header http.Header) map[string]string {
+	headers := make(map[string]string, len(header))
+	for name, values := range header {
+		headers[strings.ToLower(name)] = strings.Join(values, ", ")
 	}
+	return headers
 }
@@ -63,3 +63,28 @@ func TestParseHTTPResponse_MaxBytes(t *testing.T) {
 		})
 	}
 }
+
+func TestParseHTTPResponse_Headers(t *testing.T) {
+	t.Parallel()
+
+	resp := &http.Response{
+		StatusCode: http.StatusBadGateway,
+		Header: http.Header{
+			"Content-Type": []string{"application/json"},
+			"X-Request-Id": []string{"req_123"},
+			"Vary":         []string{"Accept", "Origin"},
+		},
+		Body: io.NopCloser(strings.NewReader(`{"error":"upstream"}`)),
+	}
+	delivery := &destregistry.Delivery{}
+
+	destwebhook.ParseHTTPResponse(delivery, resp, 1024)
+
+	assert.Equal(t, http.StatusBadGateway, delivery.Response["status"])
+	assert.Equal(t, map[string]string{
+		"content-type": "application/json",
+		"x-request-id": "req_123",
+		"vary":         "Accept, Origin",
+	}, delivery.Response["headers"])
+	assert.Equal(t, `{"error":"upstream"}`, delivery.Response["body"])
+}
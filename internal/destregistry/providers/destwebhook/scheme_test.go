@@ -0,0 +1,211 @@
+package destwebhook_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	standardwebhooks "github.com/standard-webhooks/standard-webhooks/libraries/go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSvixSecret = "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+
+func TestWebhookPublisher_SignatureScheme(t *testing.T) {
+	t.Parallel()
+
+	newDestination := func(scheme, secret string) *models.Destination {
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{
+				"url":              "http://example.com/webhook",
+				"signature_scheme": scheme,
+			}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{
+				"secret": secret,
+			}),
+		)
+		return &destination
+	}
+
+	format := func(t *testing.T, provider *destwebhook.WebhookDestination, destination *models.Destination) (*models.Event, *http.Request, string) {
+		t.Helper()
+		publisher, err := provider.CreatePublisher(context.Background(), destination)
+		require.NoError(t, err)
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithDataMap(map[string]interface{}{"key": "value"}),
+		)
+		req, err := publisher.(*destwebhook.WebhookPublisher).Format(context.Background(), &event)
+		require.NoError(t, err)
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		return &event, req, string(body)
+	}
+
+	t.Run("stripe", func(t *testing.T) {
+		t.Parallel()
+		provider := NewTestProvider(t)
+		_, req, body := format(t, provider, newDestination(destwebhook.SignatureSchemeStripe, "whsec_test"))
+
+		header := req.Header.Get("Stripe-Signature")
+		require.NotEmpty(t, header)
+		assert.Empty(t, req.Header.Get("x-outpost-signature"))
+
+		elements := strings.Split(header, ",")
+		require.Len(t, elements, 2)
+		timestamp, ok := strings.CutPrefix(elements[0], "t=")
+		require.True(t, ok, "header should start with t=")
+		signature, ok := strings.CutPrefix(elements[1], "v1=")
+		require.True(t, ok, "signature should be v1=")
+
+		mac := hmac.New(sha256.New, []byte("whsec_test"))
+		mac.Write([]byte(timestamp + "." + body))
+		assert.Equal(t, hex.EncodeToString(mac.Sum(nil)), signature)
+	})
+
+	t.Run("svix", func(t *testing.T) {
+		t.Parallel()
+		provider := NewTestProvider(t)
+		event, req, body := format(t, provider, newDestination(destwebhook.SignatureSchemeSvix, testSvixSecret))
+
+		assert.Equal(t, event.ID, req.Header.Get("svix-id"))
+		require.NotEmpty(t, req.Header.Get("svix-timestamp"))
+		require.True(t, strings.HasPrefix(req.Header.Get("svix-signature"), "v1,"))
+		assert.Empty(t, req.Header.Get("x-outpost-signature"))
+
+		// Svix signatures are Standard Webhooks signatures under svix- headers
+		wh, err := standardwebhooks.NewWebhook(testSvixSecret)
+		require.NoError(t, err)
+		headers := http.Header{}
+		headers.Set("webhook-id", req.Header.Get("svix-id"))
+		headers.Set("webhook-timestamp", req.Header.Get("svix-timestamp"))
+		headers.Set("webhook-signature", req.Header.Get("svix-signature"))
+		assert.NoError(t, wh.Verify([]byte(body), headers))
+	})
+
+	t.Run("stripe signs when the deployment disables the signature header", func(t *testing.T) {
+		t.Parallel()
+		provider := NewTestProvider(t, destwebhook.WithSignatureHeader("", true))
+		_, req, _ := format(t, provider, newDestination(destwebhook.SignatureSchemeStripe, "whsec_test"))
+
+		assert.NotEmpty(t, req.Header.Get("Stripe-Signature"))
+	})
+
+	t.Run("default follows the deployment format", func(t *testing.T) {
+		t.Parallel()
+		provider := NewTestProvider(t)
+		_, req, _ := format(t, provider, newDestination(destwebhook.SignatureSchemeDefault, "test-secret"))
+
+		assert.True(t, strings.HasPrefix(req.Header.Get("x-outpost-signature"), "v0="))
+		assert.Empty(t, req.Header.Get("Stripe-Signature"))
+		assert.Empty(t, req.Header.Get("svix-signature"))
+	})
+}
+
+func TestWebhookDestination_SignatureSchemeValidation(t *testing.T) {
+	t.Parallel()
+
+	validate := func(t *testing.T, config, credentials map[string]string) error {
+		t.Helper()
+		config["url"] = "http://example.com/webhook"
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(config),
+			testutil.DestinationFactory.WithCredentials(credentials),
+		)
+		return NewTestProvider(t).Validate(context.Background(), &destination)
+	}
+
+	t.Run("unknown scheme", func(t *testing.T) {
+		t.Parallel()
+		err := validate(t, map[string]string{"signature_scheme": "github"}, map[string]string{"secret": "test-secret"})
+
+		var validationErr *destregistry.ErrDestinationValidation
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "config.signature_scheme", validationErr.Errors[0].Field)
+		assert.Equal(t, "invalid", validationErr.Errors[0].Type)
+	})
+
+	t.Run("svix requires a base64 secret", func(t *testing.T) {
+		t.Parallel()
+		err := validate(t, map[string]string{"signature_scheme": "svix"}, map[string]string{"secret": "whsec_not-base64!"})
+
+		var validationErr *destregistry.ErrDestinationValidation
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "credentials.secret", validationErr.Errors[0].Field)
+		assert.Equal(t, "pattern", validationErr.Errors[0].Type)
+	})
+
+	t.Run("svix destinations get a svix secret", func(t *testing.T) {
+		t.Parallel()
+		provider := NewTestProvider(t, destwebhook.WithSigningSecretTemplate("{{.RandomAlphanumeric}}-!"))
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{
+				"url":              "http://example.com/webhook",
+				"signature_scheme": "svix",
+			}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{}),
+		)
+
+		require.NoError(t, provider.Preprocess(&destination, nil, &destregistry.PreprocessDestinationOpts{Role: "tenant"}))
+		require.NoError(t, provider.Validate(context.Background(), &destination))
+		_, err := standardwebhooks.NewWebhook(destination.Credentials["secret"])
+		assert.NoError(t, err)
+	})
+}
+
+func TestWebhookDestination_VerifySignatureScheme(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		scheme string
+		secret string
+	}{
+		{destwebhook.SignatureSchemeStripe, "whsec_test"},
+		{destwebhook.SignatureSchemeSvix, testSvixSecret},
+	} {
+		t.Run(tc.scheme, func(t *testing.T) {
+			t.Parallel()
+			provider := NewTestProvider(t)
+			destination := testutil.DestinationFactory.Any(
+				testutil.DestinationFactory.WithType("webhook"),
+				testutil.DestinationFactory.WithConfig(map[string]string{
+					"url":              "http://example.com/webhook",
+					"signature_scheme": tc.scheme,
+				}),
+				testutil.DestinationFactory.WithCredentials(map[string]string{"secret": tc.secret}),
+			)
+			publisher, err := provider.CreatePublisher(context.Background(), &destination)
+			require.NoError(t, err)
+			event := testutil.EventFactory.Any()
+			req, err := publisher.(*destwebhook.WebhookPublisher).Format(context.Background(), &event)
+			require.NoError(t, err)
+			body, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+			headers := map[string]string{}
+			for key := range req.Header {
+				headers[key] = req.Header.Get(key)
+			}
+
+			result, err := provider.VerifySignature(context.Background(), &destination, destregistry.SignatureVerificationRequest{
+				Body:    string(body),
+				Headers: headers,
+			})
+			require.NoError(t, err)
+			assert.True(t, result.Valid, "issues: %v", result.Issues)
+			assert.Equal(t, tc.scheme, result.Scheme)
+			assert.NotNil(t, result.Timestamp)
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package destwebhook
+
+import "strings"
+
+// Secret retrieval policies control how signing secrets are returned by the
+// API once they are stored. Whatever the policy, a secret is always returned
+// in the response to the request that created or rotated it.
+const (
+	// SecretRetrievalRetrievable returns secrets in full (default).
+	SecretRetrievalRetrievable = "retrievable"
+	// SecretRetrievalWriteOnly never returns stored secrets.
+	SecretRetrievalWriteOnly = "write_only"
+	// SecretRetrievalMasked returns only the last 4 characters of secrets.
+	SecretRetrievalMasked = "masked"
+)
+
+const maskedSecretPrefix = "********"
+
+// secretCredentialKeys are the credential fields holding signing secrets.
+var secretCredentialKeys = []string{"secret", "previous_secret"}
+
+// IsValidSecretRetrievalPolicy reports whether policy is a known policy. The
+// empty string is accepted and means SecretRetrievalRetrievable.
+func IsValidSecretRetrievalPolicy(policy string) bool {
+	switch policy {
+	case "", SecretRetrievalRetrievable, SecretRetrievalWriteOnly, SecretRetrievalMasked:
+		return true
+	}
+	return false
+}
+
+// MaskSecret returns the masked form of a secret, keeping its last 4
+// characters. Secrets too short to keep anything are masked entirely.
+func MaskSecret(secret string) string {
+	if len(secret) <= 8 {
+		return maskedSecretPrefix
+	}
+	return maskedSecretPrefix + secret[len(secret)-4:]
+}
+
+// RedactSecretCredentials applies the retrieval policy to the signing secrets
+// of a credentials map in place.
+func RedactSecretCredentials(credentials map[string]string, policy string) {
+	for _, key := range secretCredentialKeys {
+		value, ok := credentials[key]
+		if !ok {
+			continue
+		}
+		switch policy {
+		case SecretRetrievalWriteOnly:
+			delete(credentials, key)
+		case SecretRetrievalMasked:
+			credentials[key] = MaskSecret(value)
+		}
+	}
+}
+
+// RestoreMaskedSecretCredentials replaces masked secrets sent back by a client
+// (e.g. a dashboard resubmitting the destination it was shown) with the stored
+// values they stand for, so they are not mistaken for new secrets.
+func RestoreMaskedSecretCredentials(credentials, original map[string]string, policy string) {
+	if policy != SecretRetrievalMasked || credentials == nil || original == nil {
+		return
+	}
+	for _, key := range secretCredentialKeys {
+		value := credentials[key]
+		if value == "" || !strings.HasPrefix(value, maskedSecretPrefix) {
+			continue
+		}
+		if stored := original[key]; stored != "" && value == MaskSecret(stored) {
+			credentials[key] = stored
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package destwebhook_test
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDestination_ServerTLS(t *testing.T) {
+	t.Parallel()
+
+	// The test server's certificate is self-signed, like an endpoint on a
+	// private PKI.
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req destwebhook.VerificationRequest
+		json.NewDecoder(r.Body).Decode(&req)
+		w.Write([]byte(req.Challenge))
+	}))
+	defer server.Close()
+	ca := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	provider := NewTestProvider(t)
+	verify := func(config, credentials map[string]string) error {
+		config["url"] = server.URL
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(config),
+			testutil.DestinationFactory.WithCredentials(credentials),
+		)
+		return provider.VerifyEndpoint(context.Background(), &destination, "challenge")
+	}
+
+	t.Run("untrusted certificate fails", func(t *testing.T) {
+		t.Parallel()
+		err := verify(map[string]string{}, map[string]string{"secret": "test-secret"})
+		assert.ErrorIs(t, err, destregistry.ErrEndpointNotVerified)
+	})
+
+	t.Run("trusted with the CA certificate", func(t *testing.T) {
+		t.Parallel()
+		err := verify(map[string]string{}, map[string]string{"secret": "test-secret", "ca_certificate": ca})
+		assert.NoError(t, err)
+	})
+
+	t.Run("trusted with verification skipped", func(t *testing.T) {
+		t.Parallel()
+		err := verify(map[string]string{"tls_skip_verify": "true"}, map[string]string{"secret": "test-secret"})
+		assert.NoError(t, err)
+	})
+
+	t.Run("preprocess keeps the CA certificate", func(t *testing.T) {
+		t.Parallel()
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{"url": server.URL}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{"ca_certificate": ca}),
+		)
+		err := provider.Preprocess(&destination, nil, &destregistry.PreprocessDestinationOpts{Role: "tenant"})
+		require.NoError(t, err)
+		assert.Equal(t, ca, destination.Credentials["ca_certificate"])
+	})
+}
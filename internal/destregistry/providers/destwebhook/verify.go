@@ -0,0 +1,219 @@
+package destwebhook
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+var _ destregistry.SignatureVerifier = (*WebhookDestination)(nil)
+
+// VerifySignature checks a request as received by the consumer against the
+// destination's current and previous secrets, using the same signature scheme
+// as the publisher. When the signature does
+// not verify it looks for the usual causes (timestamp skew, an expired
+// previous secret, the wrong encoding, a re-serialized body) so they can be
+// reported back.
+func (d *WebhookDestination) VerifySignature(ctx context.Context, destination *models.Destination, req destregistry.SignatureVerificationRequest) (*destregistry.SignatureVerification, error) {
+	config, creds, err := d.resolveConfig(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+	scheme := d.signingScheme(config.SignatureScheme)
+	if req.Tolerance <= 0 {
+		req.Tolerance = destregistry.DefaultSignatureTolerance
+	}
+	if req.Now.IsZero() {
+		req.Now = time.Now()
+	}
+
+	headers := make(http.Header, len(req.Headers))
+	for key, value := range req.Headers {
+		headers.Set(key, value)
+	}
+
+	result := &destregistry.SignatureVerification{
+		Scheme:    scheme.name,
+		Algorithm: scheme.algorithm,
+		Encoding:  scheme.encoding,
+		Issues:    []destregistry.SignatureIssue{},
+	}
+
+	payload := SignaturePayload{
+		Timestamp: req.Now,
+		Body:      req.Body,
+	}
+	switch scheme.name {
+	case SignatureSchemeStripe:
+		// The timestamp is the "t=" element of the signature header
+		result.TimestampHeader = scheme.signatureHeader
+		d.verifyTimestamp(result, scheme, stripeTimestamp(headers.Get(scheme.signatureHeader)), req, &payload)
+	case SignatureSchemeSvix:
+		payload.EventID = headers.Get(scheme.idHeader)
+		result.TimestampHeader = scheme.timestampHeader
+		d.verifyTimestamp(result, scheme, headers.Get(scheme.timestampHeader), req, &payload)
+	default:
+		if !d.eventIDHeader.disabled {
+			payload.EventID = headers.Get(resolveHeaderName(d.eventIDHeader, d.headerPrefix, "event-id"))
+		}
+		if !d.topicHeader.disabled {
+			payload.Topic = headers.Get(resolveHeaderName(d.topicHeader, d.headerPrefix, "topic"))
+		}
+		if !d.timestampHeader.disabled {
+			result.TimestampHeader = resolveHeaderName(d.timestampHeader, d.headerPrefix, "timestamp")
+			d.verifyTimestamp(result, scheme, headers.Get(result.TimestampHeader), req, &payload)
+		}
+	}
+
+	formatter := NewSignatureFormatter(scheme.contentTemplate)
+	result.SignedContent = formatter.Format(payload)
+
+	if scheme.signatureHeader == "" && d.signatureHeader.disabled {
+		result.AddIssue(destregistry.SignatureIssueMissingSignature, "the signature header is disabled for this deployment, so requests are not signed")
+		return result, nil
+	}
+	result.SignatureHeader = scheme.signatureHeader
+	if result.SignatureHeader == "" {
+		result.SignatureHeader = resolveHeaderName(d.signatureHeader, d.headerPrefix, "signature")
+	}
+	received := parseSignatureHeader(headers.Get(result.SignatureHeader))
+	if len(received) == 0 {
+		result.AddIssue(destregistry.SignatureIssueMissingSignature, fmt.Sprintf("header %q is missing or empty", result.SignatureHeader))
+		return result, nil
+	}
+	if creds.Secret == "" {
+		result.AddIssue(destregistry.SignatureIssueNoSecret, "the destination has no signing secret")
+		return result, nil
+	}
+
+	type candidate struct {
+		name    string
+		key     string
+		expired bool
+	}
+	// Keys were checked by resolveConfig
+	key, _ := scheme.signingKey(creds.Secret)
+	candidates := []candidate{{name: "secret", key: key}}
+	if creds.PreviousSecret != "" {
+		previousKey, _ := scheme.signingKey(creds.PreviousSecret)
+		candidates = append(candidates, candidate{
+			name:    "previous_secret",
+			key:     previousKey,
+			expired: req.Now.After(creds.PreviousSecretInvalidAt),
+		})
+	}
+
+	algo := GetAlgorithm(scheme.algorithm)
+	encoder := GetEncoder(scheme.encoding)
+	for _, c := range candidates {
+		if !matchesAnySignature(algo, c.key, result.SignedContent, encoder, received) {
+			continue
+		}
+		result.MatchedSecret = c.name
+		if c.expired {
+			result.AddIssue(destregistry.SignatureIssueExpiredSecret, fmt.Sprintf(
+				"the signature was made with the previous secret, which stopped being valid at %s",
+				creds.PreviousSecretInvalidAt.UTC().Format(time.RFC3339)))
+		}
+		result.Valid = len(result.Issues) == 0
+		return result, nil
+	}
+
+	// No secret verifies the signature as received; look for the common causes.
+	altName, altEncoder := "base64", SignatureEncoder(Base64Encoder{})
+	if scheme.encoding == "base64" {
+		altName, altEncoder = "hex", HexEncoder{}
+	}
+	for _, c := range candidates {
+		if matchesAnySignature(algo, c.key, result.SignedContent, altEncoder, received) {
+			result.MatchedSecret = c.name
+			result.AddIssue(destregistry.SignatureIssueEncodingMismatch, fmt.Sprintf(
+				"the signature is %s encoded but this destination signs with %s encoding", altName, scheme.encoding))
+			return result, nil
+		}
+	}
+	if trimmed := strings.TrimSpace(req.Body); trimmed != req.Body {
+		trimmedPayload := payload
+		trimmedPayload.Body = trimmed
+		content := formatter.Format(trimmedPayload)
+		for _, c := range candidates {
+			if matchesAnySignature(algo, c.key, content, encoder, received) {
+				result.AddIssue(destregistry.SignatureIssueBodyModified,
+					"the signature matches the body without surrounding whitespace; verify against the raw body as received")
+				return result, nil
+			}
+		}
+	}
+	result.AddIssue(destregistry.SignatureIssueMismatch,
+		"the signature does not match the current or previous secret; check that the raw, unparsed body and the latest secret are used")
+	return result, nil
+}
+
+// verifyTimestamp parses the timestamp header into the signature payload and
+// records skew beyond the tolerance.
+func (d *WebhookDestination) verifyTimestamp(result *destregistry.SignatureVerification, scheme signingScheme, raw string, req destregistry.SignatureVerificationRequest, payload *SignaturePayload) {
+	if raw == "" {
+		result.AddIssue(destregistry.SignatureIssueMissingTimestamp, fmt.Sprintf("header %q is missing", result.TimestampHeader))
+		return
+	}
+	ts, err := scheme.parseTimestamp(raw)
+	if err != nil {
+		format := "an RFC 3339 timestamp"
+		if scheme.name != SignatureSchemeDefault {
+			format = "a unix timestamp"
+		}
+		result.AddIssue(destregistry.SignatureIssueInvalidTimestamp, fmt.Sprintf("header %q is not %s", result.TimestampHeader, format))
+		return
+	}
+	payload.Timestamp = ts
+	result.Timestamp = &ts
+
+	skew := req.Now.Sub(ts)
+	result.TimestampSkewSeconds = int64(skew.Seconds())
+	if skew.Abs() > req.Tolerance {
+		result.AddIssue(destregistry.SignatureIssueTimestampSkew, fmt.Sprintf(
+			"the timestamp is %s from the current time, beyond the %s tolerance", skew.Abs().Round(time.Second), req.Tolerance))
+	}
+}
+
+// stripeTimestamp returns the "t=" element of a Stripe-Signature header.
+func stripeTimestamp(header string) string {
+	for _, element := range strings.Split(header, ",") {
+		if value, ok := strings.CutPrefix(strings.TrimSpace(element), "t="); ok {
+			return value
+		}
+	}
+	return ""
+}
+
+// parseSignatureHeader extracts candidate signature values from a formatted
+// signature header such as "t=1700000000,v0=abc,def". Each element is kept
+// both as-is and with any "key=" prefix removed, since base64 signatures may
+// themselves contain "=".
+func parseSignatureHeader(header string) []string {
+	fields := strings.FieldsFunc(header, func(r rune) bool {
+		return r == ',' || r == ';' || r == ' '
+	})
+	signatures := make([]string, 0, len(fields)*2)
+	for _, field := range fields {
+		signatures = append(signatures, field)
+		if _, value, ok := strings.Cut(field, "="); ok && value != "" {
+			signatures = append(signatures, value)
+		}
+	}
+	return signatures
+}
+
+func matchesAnySignature(algo SigningAlgorithm, key, content string, encoder SignatureEncoder, signatures []string) bool {
+	for _, signature := range signatures {
+		if algo.Verify(key, content, signature, encoder) {
+			return true
+		}
+	}
+	return false
+}
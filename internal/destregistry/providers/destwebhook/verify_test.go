@@ -0,0 +1,174 @@
+package destwebhook_test
+
+import (
+	"context"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookDestination_VerifySignature(t *testing.T) {
+	t.Parallel()
+
+	// signedRequest formats an event the way the publisher would and returns
+	// the body and headers as a consumer would receive them.
+	signedRequest := func(t *testing.T, provider *destwebhook.WebhookDestination, destination *models.Destination) (string, map[string]string) {
+		t.Helper()
+		publisher, err := provider.CreatePublisher(context.Background(), destination)
+		require.NoError(t, err)
+
+		event := testutil.EventFactory.Any(
+			testutil.EventFactory.WithDataMap(map[string]interface{}{"key": "value"}),
+		)
+		req, err := publisher.(*destwebhook.WebhookPublisher).Format(context.Background(), &event)
+		require.NoError(t, err)
+
+		body, err := io.ReadAll(req.Body)
+		require.NoError(t, err)
+		headers := map[string]string{}
+		for key := range req.Header {
+			headers[key] = req.Header.Get(key)
+		}
+		return string(body), headers
+	}
+
+	newDestination := func(credentials map[string]string) *models.Destination {
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithType("webhook"),
+			testutil.DestinationFactory.WithConfig(map[string]string{
+				"url": "http://example.com/webhook",
+			}),
+			testutil.DestinationFactory.WithCredentials(credentials),
+		)
+		return &destination
+	}
+
+	issueCodes := func(v *destregistry.SignatureVerification) []string {
+		codes := make([]string, 0, len(v.Issues))
+		for _, issue := range v.Issues {
+			codes = append(codes, issue.Code)
+		}
+		return codes
+	}
+
+	t.Run("valid signature", func(t *testing.T) {
+		t.Parallel()
+		provider := NewTestProvider(t)
+		destination := newDestination(map[string]string{"secret": "test-secret"})
+		body, headers := signedRequest(t, provider, destination)
+
+		result, err := provider.VerifySignature(context.Background(), destination, destregistry.SignatureVerificationRequest{
+			Body:    body,
+			Headers: headers,
+		})
+		require.NoError(t, err)
+		assert.True(t, result.Valid)
+		assert.Equal(t, "secret", result.MatchedSecret)
+		assert.Empty(t, result.Issues)
+	})
+
+	t.Run("timestamp skew", func(t *testing.T) {
+		t.Parallel()
+		provider := NewTestProvider(t)
+		destination := newDestination(map[string]string{"secret": "test-secret"})
+		body, headers := signedRequest(t, provider, destination)
+
+		result, err := provider.VerifySignature(context.Background(), destination, destregistry.SignatureVerificationRequest{
+			Body:    body,
+			Headers: headers,
+			Now:     time.Now().Add(time.Hour),
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, "secret", result.MatchedSecret)
+		assert.Equal(t, []string{destregistry.SignatureIssueTimestampSkew}, issueCodes(result))
+	})
+
+	t.Run("wrong secret", func(t *testing.T) {
+		t.Parallel()
+		provider := NewTestProvider(t)
+		body, headers := signedRequest(t, provider, newDestination(map[string]string{"secret": "other-secret"}))
+
+		destination := newDestination(map[string]string{"secret": "test-secret"})
+		result, err := provider.VerifySignature(context.Background(), destination, destregistry.SignatureVerificationRequest{
+			Body:    body,
+			Headers: headers,
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, []string{destregistry.SignatureIssueMismatch}, issueCodes(result))
+	})
+
+	t.Run("expired previous secret", func(t *testing.T) {
+		t.Parallel()
+		provider := NewTestProvider(t)
+		body, headers := signedRequest(t, provider, newDestination(map[string]string{"secret": "old-secret"}))
+
+		destination := newDestination(map[string]string{
+			"secret":                     "new-secret",
+			"previous_secret":            "old-secret",
+			"previous_secret_invalid_at": time.Now().Add(-time.Minute).Format(time.RFC3339),
+		})
+		result, err := provider.VerifySignature(context.Background(), destination, destregistry.SignatureVerificationRequest{
+			Body:    body,
+			Headers: headers,
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, "previous_secret", result.MatchedSecret)
+		assert.Equal(t, []string{destregistry.SignatureIssueExpiredSecret}, issueCodes(result))
+	})
+
+	t.Run("encoding mismatch", func(t *testing.T) {
+		t.Parallel()
+		destination := newDestination(map[string]string{"secret": "test-secret"})
+		body, headers := signedRequest(t, NewTestProvider(t, destwebhook.WithSignatureEncoding("base64")), destination)
+
+		provider := NewTestProvider(t, destwebhook.WithSignatureEncoding("hex"))
+		result, err := provider.VerifySignature(context.Background(), destination, destregistry.SignatureVerificationRequest{
+			Body:    body,
+			Headers: headers,
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, []string{destregistry.SignatureIssueEncodingMismatch}, issueCodes(result))
+	})
+
+	t.Run("body modified", func(t *testing.T) {
+		t.Parallel()
+		provider := NewTestProvider(t)
+		destination := newDestination(map[string]string{"secret": "test-secret"})
+		body, headers := signedRequest(t, provider, destination)
+
+		result, err := provider.VerifySignature(context.Background(), destination, destregistry.SignatureVerificationRequest{
+			Body:    body + "\n",
+			Headers: headers,
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, []string{destregistry.SignatureIssueBodyModified}, issueCodes(result))
+	})
+
+	t.Run("missing signature header", func(t *testing.T) {
+		t.Parallel()
+		provider := NewTestProvider(t)
+		destination := newDestination(map[string]string{"secret": "test-secret"})
+		body, headers := signedRequest(t, provider, destination)
+		delete(headers, "X-Outpost-Signature")
+
+		result, err := provider.VerifySignature(context.Background(), destination, destregistry.SignatureVerificationRequest{
+			Body:    body,
+			Headers: headers,
+		})
+		require.NoError(t, err)
+		assert.False(t, result.Valid)
+		assert.Equal(t, []string{destregistry.SignatureIssueMissingSignature}, issueCodes(result))
+	})
+}
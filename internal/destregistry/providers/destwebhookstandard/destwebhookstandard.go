@@ -52,6 +52,7 @@ Related files to consider for refactoring:
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -62,20 +63,28 @@ import (
 	"github.com/hookdeck/outpost/internal/destregistry"
 	"github.com/hookdeck/outpost/internal/destregistry/metadata"
 	"github.com/hookdeck/outpost/internal/destregistry/providers/destwebhook"
+	"github.com/hookdeck/outpost/internal/dnscache"
 	"github.com/hookdeck/outpost/internal/models"
 )
 
 type StandardWebhookDestination struct {
 	*destregistry.BaseProvider
-	userAgent            string
-	proxyURL             string
-	headerPrefix         string // Prefix for metadata headers (defaults to "webhook-")
-	maxResponseBodyBytes int
+	userAgent             string
+	proxyURL              string
+	dnsCache              *dnscache.Cache
+	headerPrefix          string // Prefix for metadata headers (defaults to "webhook-")
+	maxResponseBodyBytes  int
+	secretRetrievalPolicy string
+	clientCertificates    map[string]tls.Certificate
 }
 
 type StandardWebhookDestinationConfig struct {
 	URL           string            `json:"url"`
 	CustomHeaders map[string]string `json:"custom_headers,omitempty"`
+	// ClientCertificate is presented for mutual TLS, nil without one.
+	ClientCertificate *tls.Certificate `json:"-"`
+	// ServerTLS verifies the endpoint's certificate, nil for the system CAs.
+	ServerTLS *destregistry.ServerTLS `json:"-"`
 }
 
 type StandardWebhookDestinationCredentials struct {
@@ -105,6 +114,13 @@ func WithProxyURL(proxyURL string) Option {
 	}
 }
 
+// WithDNSCache resolves webhook hosts through the given DNS cache
+func WithDNSCache(cache *dnscache.Cache) Option {
+	return func(d *StandardWebhookDestination) {
+		d.dnsCache = cache
+	}
+}
+
 // WithMaxResponseBodyBytes caps how much of the destination response body is
 // stored on the attempt. 0 (default) disables the cap.
 func WithMaxResponseBodyBytes(maxBytes int) Option {
@@ -113,6 +129,22 @@ func WithMaxResponseBodyBytes(maxBytes int) Option {
 	}
 }
 
+// WithSecretRetrievalPolicy sets how signing secrets are returned when the
+// destination is displayed. See destwebhook.SecretRetrievalRetrievable.
+func WithSecretRetrievalPolicy(policy string) Option {
+	return func(d *StandardWebhookDestination) {
+		d.secretRetrievalPolicy = policy
+	}
+}
+
+// WithClientCertificates sets the deployment's client certificates,
+// destinations name in config.client_certificate_name.
+func WithClientCertificates(certificates map[string]tls.Certificate) Option {
+	return func(d *StandardWebhookDestination) {
+		d.clientCertificates = certificates
+	}
+}
+
 // WithHeaderPrefix sets the prefix for metadata headers.
 // The prefix is trimmed of whitespace. An empty string disables the prefix entirely.
 // Config is responsible for providing the appropriate default ("webhook-" for standard mode).
@@ -147,6 +179,15 @@ func (d *StandardWebhookDestination) ComputeTarget(destination *models.Destinati
 }
 
 func (d *StandardWebhookDestination) ObfuscateDestination(destination *models.Destination) *models.Destination {
+	result := d.RevealDestination(destination)
+	destwebhook.RedactSecretCredentials(result.Credentials, d.secretRetrievalPolicy)
+	return result
+}
+
+// RevealDestination returns a copy of the destination with its signing
+// secrets in full regardless of the retrieval policy. The client certificate
+// key is still obfuscated.
+func (d *StandardWebhookDestination) RevealDestination(destination *models.Destination) *models.Destination {
 	result := *destination // shallow copy
 	result.Config = make(map[string]string, len(destination.Config))
 	result.Credentials = make(map[string]string, len(destination.Credentials))
@@ -167,15 +208,13 @@ func (d *StandardWebhookDestination) ObfuscateDestination(destination *models.De
 	}
 
 	// Copy credentials, omitting expired previous_secret fields
-	// NOTE: Secrets are intentionally not obfuscated for now because:
-	// 1. They're needed for secret rotation logic
-	// 2. They're less security-critical than other provider credentials
 	for key, value := range destination.Credentials {
 		if skipPreviousSecret && (key == "previous_secret" || key == "previous_secret_invalid_at") {
 			continue
 		}
 		result.Credentials[key] = value
 	}
+	destwebhook.ObfuscateClientKey(result.Credentials)
 
 	return &result
 }
@@ -233,22 +272,13 @@ func (d *StandardWebhookDestination) CreatePublisher(ctx context.Context, destin
 		destwebhook.WithAlgorithm(destwebhook.GetAlgorithm("hmac-sha256")),
 	)
 
-	var proxyURL *string
-	if d.proxyURL != "" {
-		proxyURL = &d.proxyURL
-	}
-
-	httpClient, err := destregistry.NewHTTPClient(destregistry.HTTPClientConfig{
-		UserAgent:     &d.userAgent,
-		ProxyURL:      proxyURL,
-		WrapTransport: destwebhook.WrapTransport,
-	})
+	httpClient, err := d.newHTTPClient(config.ClientCertificate, config.ServerTLS)
 	if err != nil {
 		return nil, err
 	}
 
 	return &StandardWebhookPublisher{
-		BasePublisher:        d.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata)),
+		BasePublisher:        d.BaseProvider.NewPublisher(destregistry.WithDeliveryMetadata(destination.DeliveryMetadata), destregistry.WithDestinationID(destination.ID)),
 		httpClient:           httpClient,
 		url:                  config.URL,
 		secrets:              secrets,
@@ -259,6 +289,61 @@ func (d *StandardWebhookDestination) CreatePublisher(ctx context.Context, destin
 	}, nil
 }
 
+// newHTTPClient builds the client deliveries are sent with, presenting
+// clientCertificate when set and verifying the endpoint with serverTLS.
+func (d *StandardWebhookDestination) newHTTPClient(clientCertificate *tls.Certificate, serverTLS *destregistry.ServerTLS) (*http.Client, error) {
+	var proxyURL *string
+	if d.proxyURL != "" {
+		proxyURL = &d.proxyURL
+	}
+	return destregistry.NewHTTPClient(destregistry.HTTPClientConfig{
+		UserAgent:         &d.userAgent,
+		ProxyURL:          proxyURL,
+		WrapTransport:     destwebhook.WrapTransport,
+		DNSCache:          d.dnsCache,
+		ClientCertificate: clientCertificate,
+		ServerTLS:         serverTLS,
+	})
+}
+
+var _ destregistry.ConnectivityDiagnoser = (*StandardWebhookDestination)(nil)
+
+// Diagnose checks the destination URL can be reached with the proxy and DNS
+// settings deliveries use.
+func (d *StandardWebhookDestination) Diagnose(ctx context.Context, destination *models.Destination) (*destregistry.ConnectivityReport, error) {
+	config, _, err := d.resolveConfig(ctx, destination)
+	if err != nil {
+		return nil, err
+	}
+	client, err := d.newHTTPClient(config.ClientCertificate, config.ServerTLS)
+	if err != nil {
+		return nil, err
+	}
+	return destwebhook.DiagnoseURL(ctx, config.URL, destwebhook.DiagnoseOptions{
+		Client:            client,
+		ProxyURL:          d.proxyURL,
+		DNSCache:          d.dnsCache,
+		ClientCertificate: config.ClientCertificate,
+		ServerTLS:         config.ServerTLS,
+	})
+}
+
+var _ destregistry.EndpointVerifier = (*StandardWebhookDestination)(nil)
+
+// VerifyEndpoint sends the challenge to the destination URL with the proxy,
+// DNS and custom header settings deliveries use.
+func (d *StandardWebhookDestination) VerifyEndpoint(ctx context.Context, destination *models.Destination, challenge string) error {
+	config, _, err := d.resolveConfig(ctx, destination)
+	if err != nil {
+		return err
+	}
+	client, err := d.newHTTPClient(config.ClientCertificate, config.ServerTLS)
+	if err != nil {
+		return err
+	}
+	return destwebhook.VerifyURL(ctx, client, config.URL, config.CustomHeaders, challenge)
+}
+
 func (d *StandardWebhookDestination) resolveConfig(ctx context.Context, destination *models.Destination) (*StandardWebhookDestinationConfig, *StandardWebhookDestinationCredentials, error) {
 	if err := d.BaseProvider.Validate(ctx, destination); err != nil {
 		return nil, nil, err
@@ -268,6 +353,15 @@ func (d *StandardWebhookDestination) resolveConfig(ctx context.Context, destinat
 		URL: destination.Config["url"],
 	}
 
+	// Standard Webhooks fixes the signature format, so only the default
+	// scheme applies
+	if scheme := destination.Config["signature_scheme"]; scheme != "" && scheme != destwebhook.SignatureSchemeDefault {
+		return nil, nil, destregistry.NewErrDestinationValidation([]destregistry.ValidationErrorDetail{{
+			Field: "config.signature_scheme",
+			Type:  "invalid",
+		}})
+	}
+
 	// Parse custom headers from config
 	if headersJSON, ok := destination.Config["custom_headers"]; ok && headersJSON != "" {
 		if err := json.Unmarshal([]byte(headersJSON), &config.CustomHeaders); err != nil {
@@ -283,6 +377,18 @@ func (d *StandardWebhookDestination) resolveConfig(ctx context.Context, destinat
 		}
 	}
 
+	clientCertificate, err := destwebhook.ResolveClientCertificate(destination, d.clientCertificates)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.ClientCertificate = clientCertificate
+
+	serverTLS, err := destregistry.ResolveServerTLS(destination)
+	if err != nil {
+		return nil, nil, err
+	}
+	config.ServerTLS = serverTLS
+
 	// Parse credentials
 	creds := &StandardWebhookDestinationCredentials{
 		Secret:         destination.Credentials["secret"],
@@ -511,10 +617,17 @@ func (d *StandardWebhookDestination) validateAndSanitizeCredentials(creds map[st
 
 // Preprocess sets a default secret if one isn't provided and handles secret rotation
 func (d *StandardWebhookDestination) Preprocess(newDestination *models.Destination, originalDestination *models.Destination, opts *destregistry.PreprocessDestinationOpts) error {
+	if err := destwebhook.CheckClientCertificateName(newDestination, originalDestination, opts); err != nil {
+		return err
+	}
+
 	// Initialize credentials if nil
 	if newDestination.Credentials == nil {
 		newDestination.Credentials = make(map[string]string)
 	}
+	if originalDestination != nil {
+		destwebhook.RestoreMaskedSecretCredentials(newDestination.Credentials, originalDestination.Credentials, d.secretRetrievalPolicy)
+	}
 
 	// Get clean credentials based on operation type
 	var cleanCredentials map[string]string
@@ -537,6 +650,11 @@ func (d *StandardWebhookDestination) Preprocess(newDestination *models.Destinati
 	if err != nil {
 		return err
 	}
+	var originalCredentials map[string]string
+	if originalDestination != nil {
+		originalCredentials = originalDestination.Credentials
+	}
+	destwebhook.KeepTLSCredentials(cleanCredentials, newDestination.Credentials, originalCredentials)
 
 	newDestination.Credentials = cleanCredentials
 	return nil
@@ -619,6 +737,11 @@ func (p *StandardWebhookPublisher) Format(ctx context.Context, event *models.Eve
 		if key == "event-id" || key == "timestamp" {
 			continue
 		}
+		// The idempotency key uses the conventional unprefixed header.
+		if key == "idempotency-key" {
+			req.Header.Set("Idempotency-Key", value)
+			continue
+		}
 		// Add with configured prefix (defaults to "webhook-")
 		req.Header.Set(p.headerPrefix+key, value)
 	}
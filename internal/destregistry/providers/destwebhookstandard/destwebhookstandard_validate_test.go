@@ -57,6 +57,21 @@ func TestStandardWebhookDestination_Validate(t *testing.T) {
 		assert.Equal(t, "required", validationErr.Errors[0].Type)
 	})
 
+	t.Run("should reject other signature schemes", func(t *testing.T) {
+		t.Parallel()
+		invalidDestination := validDestination
+		invalidDestination.Config = map[string]string{
+			"url":              "https://example.com",
+			"signature_scheme": "stripe",
+		}
+		err := provider.Validate(context.Background(), &invalidDestination)
+
+		var validationErr *destregistry.ErrDestinationValidation
+		assert.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, "config.signature_scheme", validationErr.Errors[0].Field)
+		assert.Equal(t, "invalid", validationErr.Errors[0].Type)
+	})
+
 	t.Run("should validate malformed url", func(t *testing.T) {
 		t.Parallel()
 		invalidDestination := validDestination
@@ -94,6 +94,23 @@ type Config struct {
 	PublisherCacheSize      int
 	PublisherTTL            time.Duration
 	DeliveryTimeout         time.Duration
+	// HeaderLimits caps the headers a destination adds to outbound requests.
+	// Enforced when a destination is validated and again at delivery.
+	HeaderLimits HeaderLimits
+	// PayloadOffloader stores event data over a destination's max payload
+	// size and returns the stub delivered instead. When nil, events are
+	// always delivered inline.
+	PayloadOffloader PayloadOffloader
+	// StripTopicNamespace is removed from event topics before they are
+	// transformed and delivered, so destinations receive topics as they were
+	// published. When empty, topics are delivered as stored.
+	StripTopicNamespace models.TopicNamespace
+}
+
+// PayloadOffloader stores an event's data out of band and returns the data
+// to deliver in its place.
+type PayloadOffloader interface {
+	Offload(ctx context.Context, destination *models.Destination, event *models.Event) (models.Data, error)
 }
 
 func NewRegistry(cfg *Config, logger *logging.Logger) Registry {
@@ -133,23 +150,40 @@ func (r *registry) ValidateDestination(ctx context.Context, destination *models.
 	if err != nil {
 		return err
 	}
+	var details []ValidationErrorDetail
 	if err := provider.Validate(ctx, destination); err != nil {
 		var validateErr *ErrDestinationValidation
-		if errors.As(err, &validateErr) {
-			return validateErr
+		if !errors.As(err, &validateErr) {
+			return NewErrDestinationValidation([]ValidationErrorDetail{
+				{
+					Field: "root",
+					Type:  "unknown",
+				},
+			})
 		}
-		return NewErrDestinationValidation([]ValidationErrorDetail{
-			{
-				Field: "root",
-				Type:  "unknown",
-			},
-		})
+		details = append(details, validateErr.Errors...)
+	}
+	details = append(details, r.config.HeaderLimits.Validate(destination)...)
+	details = append(details, ValidateDeliveryMetadataTemplates(destination)...)
+	if len(details) > 0 {
+		return NewErrDestinationValidation(details)
 	}
 	return nil
 }
 
+// PublishEvent publishes the event to the destination. A failed attempt
+// carries the normalized error code and message shown to tenants alongside
+// the provider's raw response.
 func (r *registry) PublishEvent(ctx context.Context, destination *models.Destination, event *models.Event) (*models.Attempt, error) {
-	publisher, err := r.ResolvePublisher(ctx, destination)
+	attempt, err := r.publishEvent(ctx, destination, event)
+	if attempt != nil {
+		attempt.ErrorCode, attempt.ErrorMessage = NormalizeAttemptError(attempt, err)
+	}
+	return attempt, err
+}
+
+func (r *registry) publishEvent(ctx context.Context, destination *models.Destination, event *models.Event) (*models.Attempt, error) {
+	publisher, err := r.resolveDeliveryPublisher(ctx, destination)
 	if err != nil {
 		// If the provider already signaled a delivery error, create a failed attempt
 		// so it's visible to the customer (instead of silently nacking into DLQ).
@@ -169,6 +203,7 @@ func (r *registry) PublishEvent(ctx context.Context, destination *models.Destina
 			if pubErr != nil {
 				return attempt, pubErr
 			}
+			attempt.ResponseData["errors"] = valErr.Errors
 			return attempt, &ErrDestinationPublishAttempt{
 				Err:      err,
 				Provider: destination.Type,
@@ -185,11 +220,48 @@ func (r *registry) PublishEvent(ctx context.Context, destination *models.Destina
 		EventID:         event.ID,
 	}
 
+	if r.config.StripTopicNamespace != "" {
+		stripped := r.config.StripTopicNamespace.StripEvent(*event)
+		event = &stripped
+	}
+
+	// A transformation that fails fails the attempt like a delivery error.
+	// The delivery worker does not retry it, since the same expression would
+	// fail again on the same event.
+	transformed, err := destination.Transformation.Apply(ctx, event)
+	if err != nil {
+		attempt.Time = time.Now()
+		attempt.Status = "failed"
+		attempt.Code = "ERR"
+		attempt.ResponseData = map[string]interface{}{"error": "transformation_failed", "message": err.Error()}
+		return attempt, &ErrDestinationPublishAttempt{
+			Err:      err,
+			Provider: destination.Type,
+			Data:     attempt.ResponseData,
+		}
+	}
+	event = transformed
+
+	// Data over the destination's size limit is delivered as a stub pointing
+	// at the stored payload. The limit applies to the transformed data, which
+	// is what the destination would receive.
+	if r.config.PayloadOffloader != nil && destination.MaxPayloadBytes > 0 && len(event.Data) > destination.MaxPayloadBytes {
+		stub, err := r.config.PayloadOffloader.Offload(ctx, destination, event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to offload payload: %w", err)
+		}
+		offloaded := *event
+		offloaded.Data = stub
+		event = &offloaded
+	}
+
 	// Create a new context with timeout
 	timeoutCtx, cancel := context.WithTimeout(ctx, r.config.DeliveryTimeout)
 	defer cancel()
 
+	published := time.Now()
 	deliveryData, err := publisher.Publish(timeoutCtx, event)
+	latencyMs := time.Since(published).Milliseconds()
 	if err != nil {
 		// Context canceled = system shutdown, return nil attempt to trigger nack → requeue.
 		// This is handled centrally so individual publishers don't need to check for it.
@@ -203,9 +275,14 @@ func (r *registry) PublishEvent(ctx context.Context, destination *models.Destina
 			attempt.Status = deliveryData.Status
 			attempt.Code = deliveryData.Code
 			attempt.ResponseData = deliveryData.Response
+			attempt.LatencyMs = &latencyMs
 		} else {
 			attempt = nil
 		}
+		var redeliveryErr *ErrRedeliveryRequested
+		if errors.As(err, &redeliveryErr) {
+			return attempt, redeliveryErr
+		}
 		var publishErr *ErrDestinationPublishAttempt
 		if errors.As(err, &publishErr) {
 			// Check if the wrapped error is a timeout
@@ -259,6 +336,7 @@ func (r *registry) PublishEvent(ctx context.Context, destination *models.Destina
 	attempt.Status = deliveryData.Status
 	attempt.Code = deliveryData.Code
 	attempt.ResponseData = deliveryData.Response
+	attempt.LatencyMs = &latencyMs
 
 	return attempt, nil
 }
@@ -278,6 +356,17 @@ func (r *registry) ResolveProvider(destination *models.Destination) (Provider, e
 	return provider, nil
 }
 
+// resolveDeliveryPublisher enforces the header limits before resolving the
+// publisher when they are enforced at delivery, so a destination that exceeds
+// them (e.g. created before the limits were lowered) fails its attempts
+// visibly instead of sending requests the receiver rejects.
+func (r *registry) resolveDeliveryPublisher(ctx context.Context, destination *models.Destination) (Publisher, error) {
+	if details := r.config.HeaderLimits.ValidateDelivery(destination); len(details) > 0 {
+		return nil, NewErrDestinationValidation(details)
+	}
+	return r.ResolvePublisher(ctx, destination)
+}
+
 // MakePublisherKey creates a unique key for a destination that includes type and config
 func MakePublisherKey(dest *models.Destination) string {
 	h := fnv.New64a()
@@ -358,6 +447,23 @@ func (r *registry) DisplayDestination(destination *models.Destination) (*Destina
 	}, nil
 }
 
+// SnapshotDestination describes the destination for delivery records: its
+// type, target and obfuscated config. It returns nil if the destination's
+// provider is not registered.
+func (r *registry) SnapshotDestination(destination *models.Destination) *models.DestinationSnapshot {
+	provider, err := r.ResolveProvider(destination)
+	if err != nil {
+		return nil
+	}
+	target := provider.ComputeTarget(destination)
+	return &models.DestinationSnapshot{
+		Type:      destination.Type,
+		Target:    target.Target,
+		TargetURL: target.TargetURL,
+		Config:    provider.ObfuscateDestination(destination).Config,
+	}
+}
+
 // PreprocessDestination resolves the provider and calls its Preprocess method
 func (r *registry) PreprocessDestination(newDestination *models.Destination, originalDestination *models.Destination, opts *PreprocessDestinationOpts) error {
 	provider, err := r.ResolveProvider(newDestination)
@@ -642,8 +642,11 @@ func TestPublishEventTimeout(t *testing.T) {
 		}
 		event := &models.Event{}
 
-		_, err = registry.PublishEvent(context.Background(), destination, event)
+		attempt, err := registry.PublishEvent(context.Background(), destination, event)
 		assert.NoError(t, err)
+		require.NotNil(t, attempt)
+		require.NotNil(t, attempt.LatencyMs, "the attempt records how long the delivery took")
+		assert.GreaterOrEqual(t, *attempt.LatencyMs, (timeout / 2).Milliseconds())
 	})
 
 	t.Run("should return timeout error when publish exceeds timeout", func(t *testing.T) {
@@ -700,6 +703,92 @@ func TestPublishEventTimeout(t *testing.T) {
 	})
 }
 
+func TestPublishEventTransformationError(t *testing.T) {
+	registry := destregistry.NewRegistry(&destregistry.Config{
+		DeliveryTimeout: time.Second,
+	}, testutil.CreateTestLogger(t))
+	provider, err := newMockProvider()
+	require.NoError(t, err)
+	require.NoError(t, registry.RegisterProvider("test", provider))
+
+	destination := &models.Destination{
+		Type:           "test",
+		Transformation: &models.Transformation{Type: models.TransformationJQ, Expression: ".items[]"},
+	}
+	event := &models.Event{Data: []byte(`{"items":[1,2]}`)}
+
+	attempt, err := registry.PublishEvent(context.Background(), destination, event)
+
+	var publishErr *destregistry.ErrDestinationPublishAttempt
+	require.ErrorAs(t, err, &publishErr)
+	require.NotNil(t, attempt, "a failed transformation is recorded as a failed attempt")
+	assert.Equal(t, "failed", attempt.Status)
+	assert.Equal(t, "transformation_failed", attempt.ResponseData["error"])
+	assert.ErrorIs(t, publishErr.Err, models.ErrTransformationFailed)
+	assert.Equal(t, destregistry.ErrorCodeTransformation, attempt.ErrorCode)
+	assert.NotEmpty(t, attempt.ErrorMessage)
+	assert.Nil(t, attempt.LatencyMs, "no delivery was made")
+}
+
+type mockOffloader struct {
+	offloaded []string
+	err       error
+}
+
+func (o *mockOffloader) Offload(ctx context.Context, destination *models.Destination, event *models.Event) (models.Data, error) {
+	if o.err != nil {
+		return nil, o.err
+	}
+	o.offloaded = append(o.offloaded, event.ID)
+	return models.Data(`{"offloaded":true}`), nil
+}
+
+func TestPublishEventPayloadOffload(t *testing.T) {
+	setup := func(t *testing.T, offloader *mockOffloader) destregistry.Registry {
+		registry := destregistry.NewRegistry(&destregistry.Config{
+			DeliveryTimeout:  time.Second,
+			PayloadOffloader: offloader,
+		}, testutil.CreateTestLogger(t))
+		provider, err := newMockProvider()
+		require.NoError(t, err)
+		require.NoError(t, registry.RegisterProvider("test", provider))
+		return registry
+	}
+
+	t.Run("offloads only events over the destination limit", func(t *testing.T) {
+		offloader := &mockOffloader{}
+		registry := setup(t, offloader)
+		destination := &models.Destination{Type: "test", MaxPayloadBytes: 16}
+
+		_, err := registry.PublishEvent(context.Background(), destination, &models.Event{ID: "small", Data: []byte(`{"a":1}`)})
+		require.NoError(t, err)
+		_, err = registry.PublishEvent(context.Background(), destination, &models.Event{ID: "large", Data: []byte(`{"a":"0123456789abcdef"}`)})
+		require.NoError(t, err)
+
+		assert.Equal(t, []string{"large"}, offloader.offloaded)
+	})
+
+	t.Run("destination without a limit delivers inline", func(t *testing.T) {
+		offloader := &mockOffloader{}
+		registry := setup(t, offloader)
+
+		_, err := registry.PublishEvent(context.Background(), &models.Destination{Type: "test"}, &models.Event{ID: "large", Data: []byte(`{"a":"0123456789abcdef"}`)})
+		require.NoError(t, err)
+
+		assert.Empty(t, offloader.offloaded)
+	})
+
+	t.Run("offload failure returns nil attempt", func(t *testing.T) {
+		registry := setup(t, &mockOffloader{err: errors.New("redis unavailable")})
+		destination := &models.Destination{Type: "test", MaxPayloadBytes: 1}
+
+		attempt, err := registry.PublishEvent(context.Background(), destination, &models.Event{Data: []byte(`{"a":1}`)})
+
+		require.Error(t, err)
+		assert.Nil(t, attempt, "the delivery is requeued without recording an attempt")
+	})
+}
+
 // TestPublishEventCanceled tests that context.Canceled errors are handled centrally
 // and return nil delivery to trigger nack → requeue behavior.
 // See: https://github.com/hookdeck/outpost/issues/571
@@ -0,0 +1,82 @@
+package destregistry
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// Destinations whose servers use a private PKI either trust their own CA
+// bundle in credentials.ca_certificate, or explicitly turn verification off
+// with config.tls_skip_verify. Verification is never disabled deployment-wide.
+
+// ServerTLS is how deliveries to a destination verify its server's
+// certificate.
+type ServerTLS struct {
+	// RootCAs, if set, replaces the system CAs.
+	RootCAs *x509.CertPool
+	// InsecureSkipVerify accepts any certificate.
+	InsecureSkipVerify bool
+}
+
+// ResolveServerTLS returns how deliveries to the destination verify its
+// server's certificate, or nil when they verify it against the system CAs.
+func ResolveServerTLS(destination *models.Destination) (*ServerTLS, error) {
+	invalid := func(field, errType string) error {
+		return NewErrDestinationValidation([]ValidationErrorDetail{{
+			Field: field,
+			Type:  errType,
+		}})
+	}
+
+	skipVerify := false
+	switch destination.Config["tls_skip_verify"] {
+	case "", "false":
+	case "true", "on":
+		skipVerify = true
+	default:
+		return nil, invalid("config.tls_skip_verify", "invalid")
+	}
+
+	ca := destination.Credentials["ca_certificate"]
+	if ca == "" {
+		if !skipVerify {
+			return nil, nil
+		}
+		return &ServerTLS{InsecureSkipVerify: true}, nil
+	}
+	// A CA bundle is pointless when nothing is verified
+	if skipVerify {
+		return nil, invalid("credentials.ca_certificate", "forbidden")
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM([]byte(ca)) {
+		return nil, invalid("credentials.ca_certificate", "invalid")
+	}
+	return &ServerTLS{RootCAs: pool}, nil
+}
+
+// TLSVerificationDisabled reports whether deliveries to the destination skip
+// verifying its server's certificate.
+func TLSVerificationDisabled(destination *models.Destination) bool {
+	if destination == nil {
+		return false
+	}
+	value := destination.Config["tls_skip_verify"]
+	return value == "true" || value == "on"
+}
+
+// Apply sets the verification on a TLS config. A nil ServerTLS leaves it
+// unchanged.
+func (s *ServerTLS) Apply(config *tls.Config) {
+	if s == nil {
+		return
+	}
+	if s.RootCAs != nil {
+		config.RootCAs = s.RootCAs
+	}
+	if s.InsecureSkipVerify {
+		config.InsecureSkipVerify = true
+	}
+}
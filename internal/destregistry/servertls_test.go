@@ -0,0 +1,84 @@
+package destregistry_test
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveServerTLS(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewTLSServer(http.NotFoundHandler())
+	defer server.Close()
+	ca := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	newDestination := func(config, credentials map[string]string) *models.Destination {
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithConfig(config),
+			testutil.DestinationFactory.WithCredentials(credentials),
+		)
+		return &destination
+	}
+	assertInvalid := func(t *testing.T, err error, field, errType string) {
+		t.Helper()
+		var validationErr *destregistry.ErrDestinationValidation
+		require.ErrorAs(t, err, &validationErr)
+		assert.Equal(t, field, validationErr.Errors[0].Field)
+		assert.Equal(t, errType, validationErr.Errors[0].Type)
+	}
+
+	t.Run("system CAs by default", func(t *testing.T) {
+		t.Parallel()
+		serverTLS, err := destregistry.ResolveServerTLS(newDestination(map[string]string{"tls_skip_verify": "false"}, map[string]string{}))
+		require.NoError(t, err)
+		assert.Nil(t, serverTLS)
+	})
+
+	t.Run("CA certificate", func(t *testing.T) {
+		t.Parallel()
+		serverTLS, err := destregistry.ResolveServerTLS(newDestination(map[string]string{}, map[string]string{"ca_certificate": ca}))
+		require.NoError(t, err)
+		require.NotNil(t, serverTLS)
+
+		config := &tls.Config{}
+		serverTLS.Apply(config)
+		assert.NotNil(t, config.RootCAs)
+		assert.False(t, config.InsecureSkipVerify)
+	})
+
+	t.Run("skip verify", func(t *testing.T) {
+		t.Parallel()
+		destination := newDestination(map[string]string{"tls_skip_verify": "on"}, map[string]string{})
+		serverTLS, err := destregistry.ResolveServerTLS(destination)
+		require.NoError(t, err)
+		assert.Equal(t, &destregistry.ServerTLS{InsecureSkipVerify: true}, serverTLS)
+		assert.True(t, destregistry.TLSVerificationDisabled(destination))
+	})
+
+	t.Run("invalid CA certificate", func(t *testing.T) {
+		t.Parallel()
+		_, err := destregistry.ResolveServerTLS(newDestination(map[string]string{}, map[string]string{"ca_certificate": "not a certificate"}))
+		assertInvalid(t, err, "credentials.ca_certificate", "invalid")
+	})
+
+	t.Run("invalid skip verify", func(t *testing.T) {
+		t.Parallel()
+		_, err := destregistry.ResolveServerTLS(newDestination(map[string]string{"tls_skip_verify": "yes"}, map[string]string{}))
+		assertInvalid(t, err, "config.tls_skip_verify", "invalid")
+	})
+
+	t.Run("CA certificate with skip verify", func(t *testing.T) {
+		t.Parallel()
+		_, err := destregistry.ResolveServerTLS(newDestination(map[string]string{"tls_skip_verify": "true"}, map[string]string{"ca_certificate": ca}))
+		assertInvalid(t, err, "credentials.ca_certificate", "forbidden")
+	})
+}
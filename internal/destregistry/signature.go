@@ -0,0 +1,66 @@
+package destregistry
+
+import (
+	"context"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// Signature verification issue codes reported in SignatureVerification.Issues.
+const (
+	SignatureIssueMissingSignature = "missing_signature_header"
+	SignatureIssueMissingTimestamp = "missing_timestamp_header"
+	SignatureIssueInvalidTimestamp = "invalid_timestamp"
+	SignatureIssueTimestampSkew    = "timestamp_skew"
+	SignatureIssueNoSecret         = "no_secret"
+	SignatureIssueExpiredSecret    = "expired_secret"
+	SignatureIssueEncodingMismatch = "encoding_mismatch"
+	SignatureIssueBodyModified     = "body_modified"
+	SignatureIssueMismatch         = "signature_mismatch"
+)
+
+// DefaultSignatureTolerance is the timestamp skew accepted when verifying a
+// signature if the caller does not provide one.
+const DefaultSignatureTolerance = 5 * time.Minute
+
+// SignatureVerificationRequest is a request as received by a consumer's
+// endpoint, to be checked against the destination's signing secrets.
+type SignatureVerificationRequest struct {
+	Body      string
+	Headers   map[string]string
+	Tolerance time.Duration
+	Now       time.Time
+}
+
+// SignatureIssue explains one reason a signature would fail verification.
+type SignatureIssue struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// SignatureVerification is the outcome of a signature check.
+type SignatureVerification struct {
+	Valid                bool             `json:"valid"`
+	MatchedSecret        string           `json:"matched_secret,omitempty"`
+	Scheme               string           `json:"scheme,omitempty"`
+	Algorithm            string           `json:"algorithm"`
+	Encoding             string           `json:"encoding"`
+	SignatureHeader      string           `json:"signature_header"`
+	TimestampHeader      string           `json:"timestamp_header,omitempty"`
+	Timestamp            *time.Time       `json:"timestamp,omitempty"`
+	TimestampSkewSeconds int64            `json:"timestamp_skew_seconds"`
+	SignedContent        string           `json:"signed_content"`
+	Issues               []SignatureIssue `json:"issues"`
+}
+
+// AddIssue records a verification issue.
+func (v *SignatureVerification) AddIssue(code, message string) {
+	v.Issues = append(v.Issues, SignatureIssue{Code: code, Message: message})
+}
+
+// SignatureVerifier is implemented by providers that sign outgoing requests
+// and can explain why a received signature does not verify.
+type SignatureVerifier interface {
+	VerifySignature(ctx context.Context, destination *models.Destination, req SignatureVerificationRequest) (*SignatureVerification, error)
+}
@@ -0,0 +1,46 @@
+package destregistry
+
+import (
+	"maps"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// RecordTargetChange records on destination that its target changed from
+// previous's at now, so the messages already queued for it are delivered as
+// queuedMessages says. Without a choice, the one made for the destination's
+// last target change stands, and new_target otherwise. When the target is
+// unchanged, a choice applies to the messages still queued from the last
+// change.
+func RecordTargetChange(registry Registry, previous, destination *models.Destination, queuedMessages string, now time.Time) {
+	if !targetChanged(registry, previous, destination) {
+		if queuedMessages != "" && destination.TargetChange != nil {
+			change := *destination.TargetChange
+			change.QueuedMessages = queuedMessages
+			destination.TargetChange = &change
+		}
+		return
+	}
+	if queuedMessages == "" && previous.TargetChange != nil {
+		queuedMessages = previous.TargetChange.QueuedMessages
+	}
+	if queuedMessages == "" {
+		queuedMessages = models.QueuedMessagesNewTarget
+	}
+	destination.TargetChange = &models.TargetChange{
+		ChangedAt:      now,
+		QueuedMessages: queuedMessages,
+	}
+}
+
+// targetChanged reports whether destination delivers somewhere else than
+// previous, going by the target its provider computes, or by its config when
+// the provider can't be resolved.
+func targetChanged(registry Registry, previous, destination *models.Destination) bool {
+	provider, err := registry.ResolveProvider(destination)
+	if err != nil || provider == nil {
+		return !maps.Equal(previous.Config, destination.Config)
+	}
+	return provider.ComputeTarget(previous) != provider.ComputeTarget(destination)
+}
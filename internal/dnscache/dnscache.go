@@ -0,0 +1,254 @@
+// Package dnscache caches the DNS lookups of delivery targets in process.
+//
+// Addresses are cached for a TTL. When refreshing them fails for any reason
+// but the name not existing (a resolver timeout or error), the last known
+// addresses keep being served for a stale window, so a resolver blip doesn't
+// fail deliveries. Names that don't exist are cached for the TTL as well, so a
+// destination pointing at a deleted domain doesn't query the resolver on every
+// attempt.
+package dnscache
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/sync/singleflight"
+)
+
+// Lookup results, recorded as the "result" attribute of the
+// outpost.dns.lookups metric.
+const (
+	ResultHit      = "hit"
+	ResultMiss     = "miss"
+	ResultStale    = "stale"
+	ResultNXDomain = "nxdomain"
+	ResultTimeout  = "timeout"
+	ResultError    = "error"
+)
+
+// lookupTimeout bounds a lookup shared by concurrent callers, which outlives
+// the caller that started it.
+const lookupTimeout = 10 * time.Second
+
+// Resolver looks up the addresses of a host. *net.Resolver implements it.
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Cache is a DNS cache safe for concurrent use.
+type Cache struct {
+	resolver Resolver
+	ttl      time.Duration
+	stale    time.Duration
+	dialer   *net.Dialer
+	now      func() time.Time
+	lookups  metric.Int64Counter
+
+	group     singleflight.Group
+	mu        sync.Mutex
+	entries   map[string]*entry
+	lastSweep time.Time
+}
+
+type entry struct {
+	addrs []string
+	// err is the *net.DNSError of a name that doesn't exist.
+	err        error
+	expires    time.Time
+	staleUntil time.Time
+}
+
+// Option configures a Cache.
+type Option func(*Cache)
+
+// WithResolver sets the resolver queried on cache misses. Default:
+// net.DefaultResolver.
+func WithResolver(resolver Resolver) Option {
+	return func(c *Cache) {
+		c.resolver = resolver
+	}
+}
+
+// WithStaleTTL sets how long addresses are served past their TTL while they
+// can't be refreshed. Default: no stale addresses.
+func WithStaleTTL(stale time.Duration) Option {
+	return func(c *Cache) {
+		c.stale = stale
+	}
+}
+
+// WithDialer sets the dialer DialContext connects with.
+func WithDialer(dialer *net.Dialer) Option {
+	return func(c *Cache) {
+		c.dialer = dialer
+	}
+}
+
+// WithNow sets the clock entries expire by.
+func WithNow(now func() time.Time) Option {
+	return func(c *Cache) {
+		c.now = now
+	}
+}
+
+// New creates a cache keeping addresses for ttl.
+func New(ttl time.Duration, opts ...Option) *Cache {
+	c := &Cache{
+		resolver: net.DefaultResolver,
+		ttl:      ttl,
+		dialer: &net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		},
+		now:     time.Now,
+		entries: make(map[string]*entry),
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	// A failing meter leaves lookups unrecorded rather than failing deliveries.
+	c.lookups, _ = otel.Meter("outpost").Int64Counter("outpost.dns.lookups",
+		metric.WithDescription("Number of DNS lookups of delivery targets, by result"),
+	)
+	return c
+}
+
+// LookupHost returns the addresses of host. IP addresses are returned as is.
+// Failures are *net.DNSError, which ClassifyError reports.
+func (c *Cache) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+
+	now := c.now()
+	c.mu.Lock()
+	cached := c.entries[host]
+	c.mu.Unlock()
+	if cached != nil && now.Before(cached.expires) {
+		c.record(ctx, ResultHit)
+		return cached.addrs, cached.err
+	}
+
+	// Concurrent misses share one lookup, which the caller that started it
+	// can't cancel for the others.
+	ch := c.group.DoChan(host, func() (any, error) {
+		lookupCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), lookupTimeout)
+		defer cancel()
+		return c.refresh(lookupCtx, host, cached)
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case res := <-ch:
+		if res.Err != nil {
+			return nil, res.Err
+		}
+		return res.Val.([]string), nil
+	}
+}
+
+func (c *Cache) refresh(ctx context.Context, host string, cached *entry) ([]string, error) {
+	addrs, err := c.resolver.LookupHost(ctx, host)
+	now := c.now()
+
+	result := ClassifyError(err)
+	switch result {
+	case ResultMiss, ResultNXDomain:
+		c.store(host, &entry{
+			addrs:      addrs,
+			err:        err,
+			expires:    now.Add(c.ttl),
+			staleUntil: now.Add(c.ttl + c.stale),
+		}, now)
+	default:
+		if cached != nil && cached.err == nil && now.Before(cached.staleUntil) {
+			c.record(ctx, ResultStale)
+			return cached.addrs, nil
+		}
+	}
+	c.record(ctx, result)
+	return addrs, err
+}
+
+func (c *Cache) store(host string, e *entry, now time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[host] = e
+	// Drop entries past their stale window at most once per TTL, so hosts
+	// no longer delivered to don't accumulate.
+	if now.Sub(c.lastSweep) < c.ttl {
+		return
+	}
+	c.lastSweep = now
+	for h, cached := range c.entries {
+		if !now.Before(cached.staleUntil) {
+			delete(c.entries, h)
+		}
+	}
+}
+
+func (c *Cache) record(ctx context.Context, result string) {
+	if c.lookups == nil {
+		return
+	}
+	c.lookups.Add(ctx, 1, metric.WithAttributes(attribute.String("result", result)))
+}
+
+// DialContext connects to addr, resolving its host through the cache. The
+// addresses are tried in order until one connects. It can be set as an
+// http.Transport's DialContext.
+func (c *Cache) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := c.LookupHost(ctx, host)
+	if err != nil {
+		return nil, &net.OpError{Op: "dial", Net: network, Err: err}
+	}
+
+	var dialErr error
+	for _, ip := range addrs {
+		conn, err := c.dialer.DialContext(ctx, network, net.JoinHostPort(ip, port))
+		if err == nil {
+			return conn, nil
+		}
+		dialErr = err
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	if dialErr == nil {
+		dialErr = &net.OpError{Op: "dial", Net: network, Err: &net.DNSError{Err: "no addresses", Name: host, IsNotFound: true}}
+	}
+	return nil, dialErr
+}
+
+// ClassifyError returns the lookup result of err: ResultMiss for a
+// successful lookup, ResultNXDomain when the name doesn't exist,
+// ResultTimeout when the resolver timed out, and ResultError otherwise.
+func ClassifyError(err error) string {
+	if err == nil {
+		return ResultMiss
+	}
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) {
+		switch {
+		case dnsErr.IsNotFound:
+			return ResultNXDomain
+		case dnsErr.IsTimeout:
+			return ResultTimeout
+		}
+		return ResultError
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return ResultTimeout
+	}
+	return ResultError
+}
@@ -0,0 +1,169 @@
+package dnscache_test
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/dnscache"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeResolver struct {
+	mu    sync.Mutex
+	calls int
+	addrs []string
+	err   error
+}
+
+func (r *fakeResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls++
+	return r.addrs, r.err
+}
+
+func (r *fakeResolver) set(addrs []string, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.addrs, r.err = addrs, err
+}
+
+func (r *fakeResolver) callCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.calls
+}
+
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func newCache(resolver *fakeResolver, clock *fakeClock) *dnscache.Cache {
+	return dnscache.New(time.Minute,
+		dnscache.WithResolver(resolver),
+		dnscache.WithStaleTTL(5*time.Minute),
+		dnscache.WithNow(clock.Now),
+	)
+}
+
+func TestCache_LookupHost(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	resolver := &fakeResolver{addrs: []string{"192.0.2.1"}}
+	clock := &fakeClock{now: time.Date(2026, 3, 20, 10, 0, 0, 0, time.UTC)}
+	cache := newCache(resolver, clock)
+
+	addrs, err := cache.LookupHost(ctx, "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.1"}, addrs)
+
+	_, err = cache.LookupHost(ctx, "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, 1, resolver.callCount(), "cached within the TTL")
+
+	resolver.set([]string{"192.0.2.2"}, nil)
+	clock.Advance(time.Minute)
+	addrs, err = cache.LookupHost(ctx, "example.com")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.0.2.2"}, addrs, "refreshed after the TTL")
+	assert.Equal(t, 2, resolver.callCount())
+
+	t.Run("ip addresses aren't resolved", func(t *testing.T) {
+		addrs, err := cache.LookupHost(ctx, "2001:db8::1")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"2001:db8::1"}, addrs)
+		assert.Equal(t, 2, resolver.callCount())
+	})
+}
+
+func TestCache_LookupHostStale(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	resolver := &fakeResolver{addrs: []string{"192.0.2.1"}}
+	clock := &fakeClock{now: time.Date(2026, 3, 20, 10, 0, 0, 0, time.UTC)}
+	cache := newCache(resolver, clock)
+
+	_, err := cache.LookupHost(ctx, "example.com")
+	require.NoError(t, err)
+
+	timeout := &net.DNSError{Err: "i/o timeout", Name: "example.com", IsTimeout: true}
+	resolver.set(nil, timeout)
+	clock.Advance(2 * time.Minute)
+	addrs, err := cache.LookupHost(ctx, "example.com")
+	require.NoError(t, err, "stale addresses are served while the resolver fails")
+	assert.Equal(t, []string{"192.0.2.1"}, addrs)
+
+	clock.Advance(5 * time.Minute)
+	_, err = cache.LookupHost(ctx, "example.com")
+	assert.ErrorIs(t, err, timeout, "stale addresses expire")
+	assert.Equal(t, dnscache.ResultTimeout, dnscache.ClassifyError(err))
+}
+
+func TestCache_LookupHostNXDomain(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	nxdomain := &net.DNSError{Err: "no such host", Name: "example.invalid", IsNotFound: true}
+	resolver := &fakeResolver{err: nxdomain}
+	clock := &fakeClock{now: time.Date(2026, 3, 20, 10, 0, 0, 0, time.UTC)}
+	cache := newCache(resolver, clock)
+
+	for range 3 {
+		_, err := cache.LookupHost(ctx, "example.invalid")
+		assert.ErrorIs(t, err, nxdomain)
+		assert.Equal(t, dnscache.ResultNXDomain, dnscache.ClassifyError(err))
+	}
+	assert.Equal(t, 1, resolver.callCount(), "nxdomain is cached within the TTL")
+}
+
+func TestCache_DialContext(t *testing.T) {
+	t.Parallel()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+	go func() {
+		conn, err := listener.Accept()
+		if err == nil {
+			conn.Close()
+		}
+	}()
+
+	_, port, err := net.SplitHostPort(listener.Addr().String())
+	require.NoError(t, err)
+
+	resolver := &fakeResolver{addrs: []string{"127.0.0.1"}}
+	cache := dnscache.New(time.Minute, dnscache.WithResolver(resolver))
+	conn, err := cache.DialContext(context.Background(), "tcp", net.JoinHostPort("example.com", port))
+	require.NoError(t, err)
+	conn.Close()
+	assert.Equal(t, 1, resolver.callCount())
+
+	t.Run("reports the dns error", func(t *testing.T) {
+		nxdomain := &net.DNSError{Err: "no such host", Name: "example.invalid", IsNotFound: true}
+		cache := dnscache.New(time.Minute, dnscache.WithResolver(&fakeResolver{err: nxdomain}))
+		_, err := cache.DialContext(context.Background(), "tcp", "example.invalid:443")
+		var dnsErr *net.DNSError
+		require.ErrorAs(t, err, &dnsErr)
+		assert.True(t, dnsErr.IsNotFound)
+	})
+}
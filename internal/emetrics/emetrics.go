@@ -46,6 +46,7 @@ type emetricsImpl struct {
 	eventDeliveredCounter metric.Int64Counter
 	eventPublishedCounter metric.Int64Counter
 	eventEligibleCounter  metric.Int64Counter
+	eventPayloadSize      metric.Int64Histogram
 	apiResponseLatency    metric.Int64Histogram
 	apiCallsCounter       metric.Int64Counter
 }
@@ -79,6 +80,13 @@ func New() (OutpostMetrics, error) {
 		return nil, err
 	}
 
+	if impl.eventPayloadSize, err = meter.Int64Histogram("outpost.event_payload_size",
+		metric.WithUnit("By"),
+		metric.WithDescription("Payload size of published events"),
+	); err != nil {
+		return nil, err
+	}
+
 	if impl.apiResponseLatency, err = meter.Int64Histogram("outpost.api_response_latency",
 		metric.WithUnit("ms"),
 		metric.WithDescription("API response latency"),
@@ -113,7 +121,9 @@ func (e *emetricsImpl) EventDelivered(ctx context.Context, ok bool, destinationT
 }
 
 func (e *emetricsImpl) EventPublished(ctx context.Context, event *models.Event) {
-	e.eventPublishedCounter.Add(ctx, 1, metric.WithAttributes(attribute.String("topic", event.Topic)))
+	attrs := metric.WithAttributes(attribute.String("topic", event.Topic))
+	e.eventPublishedCounter.Add(ctx, 1, attrs)
+	e.eventPayloadSize.Record(ctx, int64(len(event.Data)), attrs)
 }
 
 func (e *emetricsImpl) EventEligbible(ctx context.Context, event *models.Event) {
@@ -0,0 +1,89 @@
+// Package eventrate counts the events each tenant publishes per minute.
+//
+// Counts are kept in fixed one-minute windows: each window is a Redis key
+// that is incremented per event and expires shortly after the window ends,
+// so a tenant's count resets at the start of every minute.
+package eventrate
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/redis"
+)
+
+// window is the length of a counting window.
+const window = time.Minute
+
+type Counter interface {
+	// Incr counts one event for the tenant in the current window and returns
+	// the window's count including it.
+	Incr(ctx context.Context, tenantID string) (int, error)
+}
+
+type options struct {
+	deploymentID string
+	clock        clock.Clock
+}
+
+type Option func(*options)
+
+func WithDeploymentID(deploymentID string) Option {
+	return func(o *options) {
+		o.deploymentID = deploymentID
+	}
+}
+
+// WithClock sets the clock that decides the current window.
+func WithClock(c clock.Clock) Option {
+	return func(o *options) {
+		o.clock = c
+	}
+}
+
+// New returns a Counter backed by Redis.
+func New(redisClient redis.Cmdable, opts ...Option) Counter {
+	o := &options{clock: clock.New()}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &redisCounter{
+		redisClient:  redisClient,
+		deploymentID: o.deploymentID,
+		clock:        o.clock,
+	}
+}
+
+type redisCounter struct {
+	redisClient  redis.Cmdable
+	deploymentID string
+	clock        clock.Clock
+}
+
+// key returns "[<deployment>:]eventrate:{<tenant>}:<window start>". The
+// tenant is a hash tag so a tenant's windows share a cluster slot.
+func (c *redisCounter) key(tenantID string, start time.Time) string {
+	key := "eventrate:{" + tenantID + "}:" + strconv.FormatInt(start.Unix(), 10)
+	if c.deploymentID == "" {
+		return key
+	}
+	return c.deploymentID + ":" + key
+}
+
+func (c *redisCounter) Incr(ctx context.Context, tenantID string) (int, error) {
+	key := c.key(tenantID, c.clock.Now().Truncate(window))
+	var incr *redis.IntCmd
+	_, err := c.redisClient.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		incr = pipe.Incr(ctx, key)
+		// Outlive the window so a slow request near its end still counts
+		// against it.
+		pipe.Expire(ctx, key, 2*window)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(incr.Val()), nil
+}
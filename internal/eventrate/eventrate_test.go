@@ -0,0 +1,60 @@
+package eventrate_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/eventrate"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCounter(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("counts events per tenant within a window", func(t *testing.T) {
+		t.Parallel()
+		counter := eventrate.New(testutil.CreateTestRedisClient(t), eventrate.WithClock(clock.NewFake(start)))
+
+		for want := 1; want <= 3; want++ {
+			got, err := counter.Incr(t.Context(), "t1")
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+		got, err := counter.Incr(t.Context(), "t2")
+		require.NoError(t, err)
+		assert.Equal(t, 1, got)
+	})
+
+	t.Run("resets at the next window", func(t *testing.T) {
+		t.Parallel()
+		clk := clock.NewFake(start.Add(30 * time.Second))
+		counter := eventrate.New(testutil.CreateTestRedisClient(t), eventrate.WithClock(clk))
+
+		_, err := counter.Incr(t.Context(), "t1")
+		require.NoError(t, err)
+		clk.Advance(30 * time.Second)
+
+		got, err := counter.Incr(t.Context(), "t1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, got)
+	})
+
+	t.Run("counts are scoped to the deployment", func(t *testing.T) {
+		t.Parallel()
+		redisClient := testutil.CreateTestRedisClient(t)
+		clk := clock.NewFake(start)
+		counter := eventrate.New(redisClient, eventrate.WithDeploymentID("dp1"), eventrate.WithClock(clk))
+
+		_, err := counter.Incr(t.Context(), "t1")
+		require.NoError(t, err)
+
+		got, err := eventrate.New(redisClient, eventrate.WithClock(clk)).Incr(t.Context(), "t1")
+		require.NoError(t, err)
+		assert.Equal(t, 1, got)
+	})
+}
@@ -0,0 +1,135 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/grpcapi/outpostv1"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// adminOnlyMethods are the calls tenant tokens can't make.
+var adminOnlyMethods = map[string]bool{
+	outpostv1.Outpost_Publish_FullMethodName: true,
+}
+
+// caller is the authenticated caller of a call.
+type caller struct {
+	admin bool
+	// tenant is the tenant of a tenant token.
+	tenant *models.Tenant
+}
+
+// role is the caller's role, as the REST API names it.
+func (c caller) role() string {
+	if c.admin {
+		return apirouter.RoleAdmin
+	}
+	return apirouter.RoleTenant
+}
+
+type callerKey struct{}
+
+func callerFromContext(ctx context.Context) caller {
+	c, _ := ctx.Value(callerKey{}).(caller)
+	return c
+}
+
+// Authenticate is the unary interceptor authenticating calls with the bearer
+// token of their authorization metadata, like the REST API: the API key makes
+// an admin call, and a tenant JWT a call limited to the token's tenant, made
+// from an address the tenant's portal IP allowlist includes. Without an API
+// key configured, every call is an admin call.
+func (s *Server) Authenticate(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if s.cfg.APIKey == "" {
+		return handler(context.WithValue(ctx, callerKey{}, caller{admin: true}), req)
+	}
+	token, err := bearerToken(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if token == s.cfg.APIKey {
+		return handler(context.WithValue(ctx, callerKey{}, caller{admin: true}), req)
+	}
+
+	claims, err := apirouter.JWT.Extract(s.cfg.JWTSecret, token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if adminOnlyMethods[info.FullMethod] {
+		return nil, status.Error(codes.PermissionDenied, "requires API key authentication")
+	}
+	if scoped, ok := req.(interface{ GetTenantId() string }); ok && scoped.GetTenantId() != claims.TenantID {
+		return nil, status.Error(codes.PermissionDenied, "tenant_id does not match the token's tenant")
+	}
+	tenant, err := s.deps.TenantStore.RetrieveTenant(ctx, claims.TenantID)
+	if err != nil && !errors.Is(err, tenantstore.ErrTenantDeleted) {
+		return nil, s.internalError(ctx, err)
+	}
+	if tenant == nil {
+		// The token's tenant is gone, so the token is stale.
+		return nil, status.Error(codes.Unauthenticated, apirouter.ErrInvalidToken.Error())
+	}
+	if ip := peerIP(ctx); !tenant.AllowsPortalIP(ip) {
+		s.deps.Logger.Ctx(ctx).Audit("portal token rejected",
+			zap.String("tenant_id", tenant.ID),
+			zap.String("client_ip", ip),
+			zap.String("reason", "ip_not_allowed"),
+		)
+		return nil, status.Error(codes.PermissionDenied, "portal token not allowed from this IP address")
+	}
+	return handler(context.WithValue(ctx, callerKey{}, caller{tenant: tenant}), req)
+}
+
+// bearerToken returns the bearer token of the call's authorization metadata.
+func bearerToken(ctx context.Context) (string, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get("authorization")
+	if len(values) == 0 || values[0] == "" {
+		return "", apirouter.ErrMissingAuthHeader
+	}
+	token, ok := strings.CutPrefix(values[0], "Bearer ")
+	if !ok || token == "" {
+		return "", apirouter.ErrInvalidBearerToken
+	}
+	return token, nil
+}
+
+// peerIP returns the IP address the call was made from, or "" when unknown.
+func peerIP(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+// tenant returns the tenant a call is for: the tenant of a tenant token, or
+// the stored tenant for an admin call.
+func (s *Server) tenant(ctx context.Context, tenantID string) (*models.Tenant, error) {
+	if tenant := callerFromContext(ctx).tenant; tenant != nil {
+		return tenant, nil
+	}
+	tenant, err := s.deps.TenantStore.RetrieveTenant(ctx, tenantID)
+	if err != nil && !errors.Is(err, tenantstore.ErrTenantDeleted) {
+		return nil, s.internalError(ctx, err)
+	}
+	if tenant == nil {
+		return nil, status.Error(codes.NotFound, "tenant not found")
+	}
+	return tenant, nil
+}
@@ -0,0 +1,51 @@
+package grpcapi
+
+import (
+	"fmt"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/grpcapi/outpostv1"
+	"github.com/hookdeck/outpost/internal/models"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func tenantToProto(t *models.Tenant) *outpostv1.Tenant {
+	return &outpostv1.Tenant{
+		Id:                t.ID,
+		DestinationsCount: int32(t.DestinationsCount),
+		Topics:            t.Topics,
+		Metadata:          t.Metadata,
+		Sandbox:           t.Sandbox,
+		CreatedAt:         timestamppb.New(t.CreatedAt),
+		UpdatedAt:         timestamppb.New(t.UpdatedAt),
+	}
+}
+
+func destinationToProto(d *destregistry.DestinationDisplay) (*outpostv1.Destination, error) {
+	destination := &outpostv1.Destination{
+		Id:               d.ID,
+		TenantId:         d.TenantID,
+		Type:             d.Type,
+		Topics:           d.Topics,
+		Config:           d.Config,
+		Credentials:      d.Credentials,
+		DeliveryMetadata: d.DeliveryMetadata,
+		Metadata:         d.Metadata,
+		Target:           d.Target,
+		TargetUrl:        d.TargetURL,
+		CreatedAt:        timestamppb.New(d.CreatedAt),
+		UpdatedAt:        timestamppb.New(d.UpdatedAt),
+	}
+	if len(d.Filter) > 0 {
+		filter, err := structpb.NewStruct(d.Filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode filter: %w", err)
+		}
+		destination.Filter = filter
+	}
+	if d.DisabledAt != nil {
+		destination.DisabledAt = timestamppb.New(*d.DisabledAt)
+	}
+	return destination, nil
+}
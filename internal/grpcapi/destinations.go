@@ -0,0 +1,454 @@
+package grpcapi
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/grpcapi/outpostv1"
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/opevents"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/util/maputil"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// deprecatedTopicsMetadata lists the deprecated topics a destination is
+// subscribed to in the header of a create or update, like the REST API's
+// X-Outpost-Deprecated-Topics header.
+const deprecatedTopicsMetadata = "x-outpost-deprecated-topics"
+
+func (s *Server) ListDestinations(ctx context.Context, req *outpostv1.ListDestinationsRequest) (*outpostv1.ListDestinationsResponse, error) {
+	if err := requireID("tenant_id", req.GetTenantId()); err != nil {
+		return nil, err
+	}
+	tenant, err := s.tenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+	destinations, err := s.deps.TenantStore.ListDestination(ctx, tenantstore.ListDestinationRequest{
+		TenantID: tenant.ID,
+		Type:     req.GetType(),
+		Topics:   req.GetTopics(),
+	})
+	if err != nil {
+		return nil, s.internalError(ctx, err)
+	}
+	result := make([]*outpostv1.Destination, 0, len(destinations))
+	for i := range destinations {
+		destination, err := s.displayDestination(ctx, &destinations[i], false)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, destination)
+	}
+	return &outpostv1.ListDestinationsResponse{Destinations: result}, nil
+}
+
+func (s *Server) CreateDestination(ctx context.Context, req *outpostv1.CreateDestinationRequest) (*outpostv1.Destination, error) {
+	if err := requireID("tenant_id", req.GetTenantId()); err != nil {
+		return nil, err
+	}
+	tenant, err := s.tenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+	prev := snapshotTenant(tenant)
+
+	destination := newDestination(tenant.ID, req)
+	if !tenant.AllowsDestinationType(destination.Type) {
+		return nil, status.Errorf(codes.PermissionDenied, "destination type %q is not enabled for this tenant", destination.Type)
+	}
+	if err := destination.Validate(s.deps.Topics.Topics(ctx), s.cfg.TopicsAllowWildcards); err != nil {
+		return nil, invalidArgument(err)
+	}
+	destination.Topics = destination.Topics.Normalize()
+	if err := s.checkTopicLifecycle(ctx, destination.Topics, nil); err != nil {
+		return nil, err
+	}
+	if err := s.deps.Registry.ValidateDestination(ctx, &destination); err != nil {
+		return nil, invalidArgument(err)
+	}
+	role := callerFromContext(ctx).role()
+	if err := s.deps.Registry.PreprocessDestination(&destination, nil, &destregistry.PreprocessDestinationOpts{
+		Role: role,
+		Request: destregistry.PreprocessRequest{
+			Config:      destination.Config,
+			Credentials: destination.Credentials,
+		},
+	}); err != nil {
+		return nil, invalidArgument(err)
+	}
+	// A destination whose endpoint fails verification is created disabled,
+	// and is verified again when enabled.
+	if err := s.verifyEndpoint(ctx, tenant, &destination); err != nil {
+		if !errors.Is(err, destregistry.ErrEndpointNotVerified) {
+			return nil, s.internalError(ctx, err)
+		}
+		now := time.Now()
+		destination.DisabledAt = &now
+	}
+	if err := s.deps.TenantStore.CreateDestination(ctx, destination); err != nil {
+		return nil, s.upsertDestinationError(ctx, err)
+	}
+	s.deps.Telemetry.DestinationCreated(ctx, destination.Type)
+	s.emitSubscriptionUpdateIfChanged(ctx, tenant.ID, prev)
+	s.deps.Logger.Ctx(ctx).Audit("destination created",
+		zap.String("tenant_id", tenant.ID),
+		zap.String("destination_id", destination.ID),
+		zap.String("destination_type", destination.Type),
+	)
+	s.auditTLSVerification(ctx, nil, &destination)
+	s.recordVersion(ctx, nil, &destination, role)
+
+	// The response to the create call is the one place a generated secret is
+	// returned regardless of the secret retrieval policy.
+	return s.displayDestination(ctx, &destination, true)
+}
+
+func (s *Server) GetDestination(ctx context.Context, req *outpostv1.GetDestinationRequest) (*outpostv1.Destination, error) {
+	if err := requireIDs(req.GetTenantId(), req.GetDestinationId()); err != nil {
+		return nil, err
+	}
+	tenant, err := s.tenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+	destination, err := s.retrieveDestination(ctx, tenant.ID, req.GetDestinationId())
+	if err != nil {
+		return nil, err
+	}
+	return s.displayDestination(ctx, destination, false)
+}
+
+// UpdateDestination updates the fields set on the call: topics and filter
+// are replaced, and config, credentials, delivery metadata and metadata are
+// merge-patched, a null value removing a key.
+func (s *Server) UpdateDestination(ctx context.Context, req *outpostv1.UpdateDestinationRequest) (*outpostv1.Destination, error) {
+	if err := requireIDs(req.GetTenantId(), req.GetDestinationId()); err != nil {
+		return nil, err
+	}
+	tenant, err := s.tenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+	prev := snapshotTenant(tenant)
+	original, err := s.retrieveDestination(ctx, tenant.ID, req.GetDestinationId())
+	if err != nil {
+		return nil, err
+	}
+	updated := *original
+
+	if req.GetTopics() != nil {
+		updated.Topics = req.GetTopics().GetTopics()
+		if err := updated.Topics.Validate(s.deps.Topics.Topics(ctx), s.cfg.TopicsAllowWildcards); err != nil {
+			return nil, invalidArgument(err)
+		}
+		updated.Topics = updated.Topics.Normalize()
+		if err := s.checkTopicLifecycle(ctx, updated.Topics, original.Topics); err != nil {
+			return nil, err
+		}
+	}
+	if req.GetFilter() != nil {
+		if len(req.GetFilter().GetFields()) == 0 {
+			updated.Filter = nil
+		} else {
+			filter := models.Filter(req.GetFilter().AsMap())
+			if err := filter.Validate(); err != nil {
+				return nil, invalidArgument(err)
+			}
+			updated.Filter = filter
+		}
+	}
+	config, configRequest, configChanged := mergePatch(original.Config, req.GetConfig())
+	updated.Config = config
+	credentials, credentialsRequest, credentialsChanged := mergePatch(original.Credentials, req.GetCredentials())
+	updated.Credentials = credentials
+	updated.DeliveryMetadata, _, _ = mergePatch(original.DeliveryMetadata, req.GetDeliveryMetadata())
+	updated.Metadata, _, _ = mergePatch(original.Metadata, req.GetMetadata())
+
+	role := callerFromContext(ctx).role()
+	if err := s.deps.Registry.PreprocessDestination(&updated, original, &destregistry.PreprocessDestinationOpts{
+		Role: role,
+		Request: destregistry.PreprocessRequest{
+			Config:      configRequest,
+			Credentials: credentialsRequest,
+		},
+	}); err != nil {
+		return nil, invalidArgument(err)
+	}
+	if configChanged || credentialsChanged {
+		if err := s.deps.Registry.ValidateDestination(ctx, &updated); err != nil {
+			return nil, invalidArgument(err)
+		}
+	}
+	// Changing the config of an enabled destination verifies its endpoint
+	// again.
+	if updated.DisabledAt == nil && configChanged {
+		if err := s.verifyEndpoint(ctx, tenant, &updated); err != nil {
+			if errors.Is(err, destregistry.ErrEndpointNotVerified) {
+				return nil, invalidArgument(err)
+			}
+			return nil, s.internalError(ctx, err)
+		}
+	}
+
+	now := time.Now()
+	destregistry.RecordTargetChange(s.deps.Registry, original, &updated, "", now)
+	updated.UpdatedAt = now
+	if err := s.deps.TenantStore.UpsertDestination(ctx, updated); err != nil {
+		return nil, s.upsertDestinationError(ctx, err)
+	}
+	s.emitSubscriptionUpdateIfChanged(ctx, tenant.ID, prev)
+	s.deps.Logger.Ctx(ctx).Audit("destination updated",
+		zap.String("tenant_id", tenant.ID),
+		zap.String("destination_id", updated.ID),
+		zap.String("destination_type", updated.Type),
+	)
+	s.auditTLSVerification(ctx, original, &updated)
+	s.recordVersion(ctx, original, &updated, role)
+
+	// Credentials set or rotated by this call are returned once.
+	return s.displayDestination(ctx, &updated, !maps.Equal(original.Credentials, updated.Credentials))
+}
+
+func (s *Server) DeleteDestination(ctx context.Context, req *outpostv1.DeleteDestinationRequest) (*outpostv1.DeleteDestinationResponse, error) {
+	if err := requireIDs(req.GetTenantId(), req.GetDestinationId()); err != nil {
+		return nil, err
+	}
+	tenant, err := s.tenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+	prev := snapshotTenant(tenant)
+	destination, err := s.retrieveDestination(ctx, tenant.ID, req.GetDestinationId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.deps.TenantStore.DeleteDestination(ctx, destination.TenantID, destination.ID); err != nil {
+		return nil, s.internalError(ctx, err)
+	}
+	s.emitSubscriptionUpdateIfChanged(ctx, tenant.ID, prev)
+	s.deps.Logger.Ctx(ctx).Audit("destination deleted",
+		zap.String("tenant_id", tenant.ID),
+		zap.String("destination_id", destination.ID),
+		zap.String("destination_type", destination.Type),
+	)
+	return &outpostv1.DeleteDestinationResponse{}, nil
+}
+
+// newDestination returns the destination a create call creates.
+func newDestination(tenantID string, req *outpostv1.CreateDestinationRequest) models.Destination {
+	id := req.GetId()
+	if id == "" {
+		id = idgen.Destination()
+	}
+	config := req.GetConfig()
+	if config == nil {
+		config = map[string]string{}
+	}
+	credentials := req.GetCredentials()
+	if credentials == nil {
+		credentials = map[string]string{}
+	}
+	var filter models.Filter
+	if len(req.GetFilter().GetFields()) > 0 {
+		filter = req.GetFilter().AsMap()
+	}
+	now := time.Now()
+	return models.Destination{
+		ID:               id,
+		TenantID:         tenantID,
+		Type:             req.GetType(),
+		Topics:           req.GetTopics(),
+		Filter:           filter,
+		Config:           config,
+		Credentials:      credentials,
+		DeliveryMetadata: req.GetDeliveryMetadata(),
+		Metadata:         req.GetMetadata(),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+}
+
+// mergePatch merge-patches a map field with patch. It returns the result, the
+// patch's values as the caller sent them, and whether the field changed. A
+// nil or empty patch leaves the field unchanged.
+func mergePatch(original map[string]string, patch *structpb.Struct) (map[string]string, map[string]string, bool) {
+	if len(patch.GetFields()) == 0 {
+		return original, nil, false
+	}
+	// AsMap keeps null values, which remove keys.
+	values := patch.AsMap()
+	return maputil.MergePatchStringMap(original, values), maputil.MergePatchStringMap(nil, values), true
+}
+
+func (s *Server) retrieveDestination(ctx context.Context, tenantID, destinationID string) (*models.Destination, error) {
+	destination, err := s.deps.TenantStore.RetrieveDestination(ctx, tenantID, destinationID)
+	if err != nil && !errors.Is(err, tenantstore.ErrDestinationDeleted) {
+		return nil, s.internalError(ctx, err)
+	}
+	if destination == nil {
+		return nil, status.Error(codes.NotFound, "destination not found")
+	}
+	return destination, nil
+}
+
+// displayDestination returns a destination as the provider displays it, with
+// its credentials revealed when they were just set by the call.
+func (s *Server) displayDestination(ctx context.Context, destination *models.Destination, revealed bool) (*outpostv1.Destination, error) {
+	display := s.deps.Registry.DisplayDestination
+	if revealed {
+		display = func(destination *models.Destination) (*destregistry.DestinationDisplay, error) {
+			return destregistry.DisplayRevealed(s.deps.Registry, destination)
+		}
+	}
+	shown, err := display(destination)
+	if err != nil {
+		return nil, s.internalError(ctx, err)
+	}
+	result, err := destinationToProto(shown)
+	if err != nil {
+		return nil, s.internalError(ctx, err)
+	}
+	return result, nil
+}
+
+// checkTopicLifecycle rejects subscriptions to retired topics the
+// destination was not already subscribed to, and lists the deprecated topics
+// in the call's header.
+func (s *Server) checkTopicLifecycle(ctx context.Context, topics, previousTopics models.Topics) error {
+	lifecycle := s.topicLifecycle(ctx)
+	var retired []string
+	for _, topic := range lifecycle.RetiredIn(topics) {
+		if !slices.Contains(previousTopics, topic) {
+			retired = append(retired, fmt.Sprintf("topic %s is retired", topic))
+		}
+	}
+	if len(retired) > 0 {
+		return invalidArgument(errors.New(strings.Join(retired, "; ")))
+	}
+	if deprecated := lifecycle.DeprecatedIn(topics); len(deprecated) > 0 {
+		_ = grpc.SetHeader(ctx, metadata.Pairs(deprecatedTopicsMetadata, strings.Join(deprecated, ",")))
+	}
+	return nil
+}
+
+// verifyEndpoint sends the destination's endpoint a challenge it must echo,
+// when the tenant requires endpoint verification and the destination type
+// supports it. A failed handshake returns an error wrapping
+// destregistry.ErrEndpointNotVerified.
+func (s *Server) verifyEndpoint(ctx context.Context, tenant *models.Tenant, destination *models.Destination) error {
+	if destination.DisabledAt != nil {
+		return nil
+	}
+	verifier, err := destregistry.TenantEndpointVerifier(s.deps.Registry, tenant, destination)
+	if err != nil || verifier == nil {
+		return err
+	}
+	if err := verifier.VerifyEndpoint(ctx, destination, rand.Text()); err != nil {
+		s.deps.Logger.Ctx(ctx).Info("destination endpoint verification failed",
+			zap.Error(err),
+			zap.String("tenant_id", tenant.ID),
+			zap.String("destination_id", destination.ID),
+			zap.String("destination_type", destination.Type))
+		return err
+	}
+	return nil
+}
+
+// auditTLSVerification records turning off, or back on, the verification of
+// a destination server's TLS certificate.
+func (s *Server) auditTLSVerification(ctx context.Context, previous, destination *models.Destination) {
+	disabled := destregistry.TLSVerificationDisabled(destination)
+	if disabled == destregistry.TLSVerificationDisabled(previous) {
+		return
+	}
+	action := "destination TLS verification enabled"
+	if disabled {
+		action = "destination TLS verification disabled"
+	}
+	s.deps.Logger.Ctx(ctx).Audit(action,
+		zap.String("tenant_id", destination.TenantID),
+		zap.String("destination_id", destination.ID),
+		zap.String("destination_type", destination.Type),
+	)
+}
+
+// recordVersion saves destination as a new version when the store keeps
+// versions. The change itself has already been applied, so failures are
+// logged rather than returned.
+func (s *Server) recordVersion(ctx context.Context, previous, destination *models.Destination, role string) {
+	if err := tenantstore.RecordDestinationVersion(ctx, s.deps.TenantStore, previous, destination, role); err != nil {
+		s.deps.Logger.Ctx(ctx).Error("failed to record destination version",
+			zap.Error(err),
+			zap.String("tenant_id", destination.TenantID),
+			zap.String("destination_id", destination.ID),
+		)
+	}
+}
+
+func (s *Server) upsertDestinationError(ctx context.Context, err error) error {
+	switch {
+	case strings.Contains(err.Error(), "validation failed"):
+		return invalidArgument(err)
+	case errors.Is(err, tenantstore.ErrDuplicateDestination):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, tenantstore.ErrMaxDestinationsPerTenantReached):
+		return status.Error(codes.ResourceExhausted, err.Error())
+	default:
+		return s.internalError(ctx, err)
+	}
+}
+
+// tenantSnapshot captures the tenant's derived state before a destination
+// mutation.
+type tenantSnapshot struct {
+	topics            []string
+	destinationsCount int
+}
+
+func snapshotTenant(tenant *models.Tenant) tenantSnapshot {
+	return tenantSnapshot{
+		topics:            tenant.Topics,
+		destinationsCount: tenant.DestinationsCount,
+	}
+}
+
+// emitSubscriptionUpdateIfChanged re-fetches the tenant after a mutation and
+// emits tenant.subscription.updated if topics or destinations_count changed.
+// Best-effort: errors are logged but do not fail the call.
+func (s *Server) emitSubscriptionUpdateIfChanged(ctx context.Context, tenantID string, prev tenantSnapshot) {
+	if s.deps.SubscriptionEmitter == nil {
+		return
+	}
+	tenant, err := s.deps.TenantStore.RetrieveTenant(ctx, tenantID)
+	if err != nil || tenant == nil {
+		s.deps.Logger.Ctx(ctx).Error("failed to retrieve tenant for subscription update", zap.Error(err))
+		return
+	}
+	if slices.Equal(tenant.Topics, prev.topics) && tenant.DestinationsCount == prev.destinationsCount {
+		return
+	}
+	if err := s.deps.SubscriptionEmitter.Emit(ctx, opevents.TenantSubscriptionUpdatedEvent(opevents.TenantSubscriptionUpdatedData{
+		TenantID:                  tenantID,
+		Topics:                    tenant.Topics,
+		PreviousTopics:            prev.topics,
+		DestinationsCount:         tenant.DestinationsCount,
+		PreviousDestinationsCount: prev.destinationsCount,
+	})); err != nil {
+		s.deps.Logger.Ctx(ctx).Error("failed to emit subscription update", zap.Error(err))
+	}
+}
@@ -0,0 +1,182 @@
+// Package grpcapi serves the gRPC API for publishing events and managing
+// tenants and destinations.
+//
+// Calls are served by the same services as the REST API: events are
+// published through the publishmq event handler, after the same idempotency
+// key, rate limit and quota checks, and tenants and destinations are read
+// and written through the tenant store and the destination registry. Calls
+// are authenticated by an interceptor, with the API key or a tenant JWT.
+package grpcapi
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative outpostv1/outpost.proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/grpcapi/outpostv1"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/opevents"
+	"github.com/hookdeck/outpost/internal/publishkey"
+	"github.com/hookdeck/outpost/internal/publishmq"
+	"github.com/hookdeck/outpost/internal/publishrate"
+	"github.com/hookdeck/outpost/internal/telemetry"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// EventHandler publishes events. Satisfied by publishmq.EventHandler.
+type EventHandler interface {
+	Handle(ctx context.Context, event *models.Event) (*publishmq.HandleResult, error)
+}
+
+// TopicLister lists the topics destinations can subscribe to. Satisfied by
+// *topicstore.Store.
+type TopicLister interface {
+	Topics(ctx context.Context) []string
+}
+
+// EventRateCounter counts the events a tenant published in the current
+// minute. Satisfied by eventrate.Counter.
+type EventRateCounter interface {
+	Incr(ctx context.Context, tenantID string) (int, error)
+}
+
+// PublishRateLimiter takes a token from a tenant's publish rate limit.
+// Satisfied by *publishrate.Limiter.
+type PublishRateLimiter interface {
+	Allow(ctx context.Context, tenantID string) (publishrate.Result, error)
+}
+
+// PublishKeys records the events published with idempotency keys. Satisfied
+// by publishkey.Keys.
+type PublishKeys interface {
+	Reserve(ctx context.Context, tenantID, key, eventID string) (publishkey.Entry, error)
+	Complete(ctx context.Context, tenantID, key, eventID string, destinationIDs []string) error
+}
+
+// SubscriptionEmitter emits operator events for subscription changes.
+// Satisfied by opevents.Emitter.
+type SubscriptionEmitter interface {
+	Emit(ctx context.Context, ev opevents.Event) error
+}
+
+// Config configures the gRPC API like the REST API's RouterConfig.
+type Config struct {
+	// APIKey authenticates admin calls. Without it, every call is an admin
+	// call.
+	APIKey                      string
+	JWTSecret                   string
+	TopicsAllowWildcards        bool
+	TopicLifecycle              models.TopicLifecycle
+	MaxEventsPerMinutePerTenant int
+}
+
+// Deps are the services calls are served by.
+type Deps struct {
+	Logger              *logging.Logger
+	Telemetry           telemetry.Telemetry
+	TenantStore         tenantstore.TenantStore
+	Registry            destregistry.Registry
+	EventHandler        EventHandler
+	Topics              TopicLister
+	TopicLifecycle      models.TopicLifecycleSource // optional — manages topic lifecycle at runtime instead of Config.TopicLifecycle
+	EventRates          EventRateCounter            // optional — with MaxEventsPerMinutePerTenant, enforces the event quota
+	PublishRateLimiter  PublishRateLimiter          // optional — enforces the tenant publish rate limit
+	PublishKeys         PublishKeys                 // optional — deduplicates publishes by idempotency key
+	SubscriptionEmitter SubscriptionEmitter         // optional — emits tenant.subscription.updated on destination mutations
+}
+
+func (d Deps) validate() error {
+	if d.Logger == nil {
+		return errors.New("grpcapi: Logger is required")
+	}
+	if d.Telemetry == nil {
+		return errors.New("grpcapi: Telemetry is required")
+	}
+	if d.TenantStore == nil {
+		return errors.New("grpcapi: TenantStore is required")
+	}
+	if d.Registry == nil {
+		return errors.New("grpcapi: Registry is required")
+	}
+	if d.EventHandler == nil {
+		return errors.New("grpcapi: EventHandler is required")
+	}
+	if d.Topics == nil {
+		return errors.New("grpcapi: Topics is required")
+	}
+	return nil
+}
+
+// topicLifecycle returns the current topic lifecycle.
+func (s *Server) topicLifecycle(ctx context.Context) models.TopicLifecycle {
+	if s.deps.TopicLifecycle != nil {
+		return s.deps.TopicLifecycle.Lifecycle(ctx)
+	}
+	return s.cfg.TopicLifecycle
+}
+
+// Server implements the Outpost gRPC service.
+type Server struct {
+	outpostv1.UnimplementedOutpostServer
+	cfg  Config
+	deps Deps
+}
+
+var _ outpostv1.OutpostServer = (*Server)(nil)
+
+// New returns a server serving calls with deps. Calls must go through its
+// Authenticate interceptor.
+func New(cfg Config, deps Deps) (*Server, error) {
+	if err := deps.validate(); err != nil {
+		return nil, err
+	}
+	return &Server{cfg: cfg, deps: deps}, nil
+}
+
+// NewGRPCServer returns a gRPC server serving the Outpost service, with its
+// calls authenticated.
+func NewGRPCServer(cfg Config, deps Deps, opts ...grpc.ServerOption) (*grpc.Server, error) {
+	s, err := New(cfg, deps)
+	if err != nil {
+		return nil, err
+	}
+	opts = append([]grpc.ServerOption{grpc.ChainUnaryInterceptor(s.Authenticate)}, opts...)
+	server := grpc.NewServer(opts...)
+	outpostv1.RegisterOutpostServer(server, s)
+	return server, nil
+}
+
+// internalError returns the status of an unexpected error, logging it since
+// its details aren't returned to the caller.
+func (s *Server) internalError(ctx context.Context, err error) error {
+	s.deps.Logger.Ctx(ctx).Error("grpc call failed", zap.Error(err))
+	return status.Error(codes.Internal, "internal server error")
+}
+
+// invalidArgument returns the status of a call with invalid fields.
+func invalidArgument(err error) error {
+	return status.Error(codes.InvalidArgument, fmt.Sprintf("validation error: %s", err))
+}
+
+// requireID rejects a call missing an ID.
+func requireID(field, id string) error {
+	if id == "" {
+		return status.Errorf(codes.InvalidArgument, "%s is required", field)
+	}
+	return nil
+}
+
+func requireIDs(tenantID, destinationID string) error {
+	if err := requireID("tenant_id", tenantID); err != nil {
+		return err
+	}
+	return requireID("destination_id", destinationID)
+}
@@ -0,0 +1,334 @@
+package grpcapi_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	destregistrydefault "github.com/hookdeck/outpost/internal/destregistry/providers"
+	"github.com/hookdeck/outpost/internal/grpcapi"
+	"github.com/hookdeck/outpost/internal/grpcapi/outpostv1"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/publishmq"
+	"github.com/hookdeck/outpost/internal/telemetry"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	apiKey    = "apikey"
+	jwtSecret = "jwtsecret"
+)
+
+type staticTopics []string
+
+func (t staticTopics) Topics(context.Context) []string {
+	return t
+}
+
+// fakeEventHandler accepts every event, for one destination.
+type fakeEventHandler struct {
+	events []*models.Event
+	err    error
+}
+
+func (h *fakeEventHandler) Handle(_ context.Context, event *models.Event) (*publishmq.HandleResult, error) {
+	h.events = append(h.events, event)
+	if h.err != nil {
+		return nil, h.err
+	}
+	return &publishmq.HandleResult{EventID: event.ID, DestinationIDs: []string{"des_1"}}, nil
+}
+
+type grpcTest struct {
+	client       outpostv1.OutpostClient
+	tenantStore  tenantstore.TenantStore
+	eventHandler *fakeEventHandler
+}
+
+func newGRPCTest(t *testing.T) *grpcTest {
+	t.Helper()
+	logger := testutil.CreateTestLogger(t)
+	registry := destregistry.NewRegistry(&destregistry.Config{}, logger)
+	require.NoError(t, destregistrydefault.RegisterDefault(registry, destregistrydefault.RegisterDefaultDestinationOptions{
+		Webhook: &destregistrydefault.DestWebhookConfig{Mode: "standard"},
+	}))
+	test := &grpcTest{
+		tenantStore:  tenantstore.NewMemTenantStore(),
+		eventHandler: &fakeEventHandler{},
+	}
+
+	server, err := grpcapi.NewGRPCServer(
+		grpcapi.Config{APIKey: apiKey, JWTSecret: jwtSecret},
+		grpcapi.Deps{
+			Logger:       logger,
+			Telemetry:    &telemetry.NoopTelemetry{},
+			TenantStore:  test.tenantStore,
+			Registry:     registry,
+			EventHandler: test.eventHandler,
+			Topics:       staticTopics{"order.created", "user.created"},
+		},
+	)
+	require.NoError(t, err)
+	listener := bufconn.Listen(1 << 20)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	test.client = outpostv1.NewOutpostClient(conn)
+	return test
+}
+
+func withToken(ctx context.Context, token string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
+func (g *grpcTest) admin(t *testing.T) context.Context {
+	return withToken(t.Context(), apiKey)
+}
+
+func (g *grpcTest) tenant(t *testing.T, tenantID string) context.Context {
+	t.Helper()
+	token, err := apirouter.JWT.New(jwtSecret, apirouter.JWTClaims{TenantID: tenantID})
+	require.NoError(t, err)
+	return withToken(t.Context(), token)
+}
+
+func (g *grpcTest) createTenant(t *testing.T, tenant models.Tenant) {
+	t.Helper()
+	require.NoError(t, g.tenantStore.UpsertTenant(t.Context(), tenant))
+}
+
+func TestPublish(t *testing.T) {
+	t.Parallel()
+
+	t.Run("publishes through the event handler", func(t *testing.T) {
+		t.Parallel()
+		g := newGRPCTest(t)
+
+		res, err := g.client.Publish(g.admin(t), &outpostv1.PublishRequest{
+			TenantId: "t1",
+			Topic:    "order.created",
+			Data:     []byte(`{"order_id":"ord_1"}`),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"des_1"}, res.GetDestinationIds())
+
+		require.Len(t, g.eventHandler.events, 1)
+		event := g.eventHandler.events[0]
+		assert.Equal(t, res.GetId(), event.ID)
+		assert.Equal(t, "t1", event.TenantID)
+		assert.Equal(t, "order.created", event.Topic)
+		assert.JSONEq(t, `{"order_id":"ord_1"}`, string(event.Data))
+		assert.True(t, event.EligibleForRetry, "unset fields keep the REST API's defaults")
+		assert.NotNil(t, event.Metadata)
+	})
+
+	t.Run("rejects data that isn't an object", func(t *testing.T) {
+		t.Parallel()
+		g := newGRPCTest(t)
+
+		_, err := g.client.Publish(g.admin(t), &outpostv1.PublishRequest{TenantId: "t1", Data: []byte(`[1]`)})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+		assert.Empty(t, g.eventHandler.events)
+	})
+
+	t.Run("maps publish errors", func(t *testing.T) {
+		t.Parallel()
+		g := newGRPCTest(t)
+		g.eventHandler.err = publishmq.ErrRequiredTopic
+
+		_, err := g.client.Publish(g.admin(t), &outpostv1.PublishRequest{TenantId: "t1", Data: []byte(`{}`)})
+		st, ok := status.FromError(err)
+		require.True(t, ok)
+		assert.Equal(t, codes.InvalidArgument, st.Code())
+		assert.Equal(t, "validation error: topic is required", st.Message())
+	})
+
+	t.Run("requires the API key", func(t *testing.T) {
+		t.Parallel()
+		g := newGRPCTest(t)
+		g.createTenant(t, models.Tenant{ID: "t1"})
+
+		_, err := g.client.Publish(g.tenant(t, "t1"), &outpostv1.PublishRequest{TenantId: "t1", Data: []byte(`{}`)})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+		assert.Empty(t, g.eventHandler.events)
+	})
+}
+
+func TestAuthentication(t *testing.T) {
+	t.Parallel()
+
+	t.Run("rejects calls without a token", func(t *testing.T) {
+		t.Parallel()
+		g := newGRPCTest(t)
+
+		_, err := g.client.GetTenant(t.Context(), &outpostv1.GetTenantRequest{TenantId: "t1"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("rejects invalid tokens", func(t *testing.T) {
+		t.Parallel()
+		g := newGRPCTest(t)
+
+		_, err := g.client.GetTenant(withToken(t.Context(), "nope"), &outpostv1.GetTenantRequest{TenantId: "t1"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("tenant tokens reach their own tenant", func(t *testing.T) {
+		t.Parallel()
+		g := newGRPCTest(t)
+		g.createTenant(t, models.Tenant{ID: "t1"})
+		g.createTenant(t, models.Tenant{ID: "t2"})
+
+		tenant, err := g.client.GetTenant(g.tenant(t, "t1"), &outpostv1.GetTenantRequest{TenantId: "t1"})
+		require.NoError(t, err)
+		assert.Equal(t, "t1", tenant.GetId())
+
+		_, err = g.client.GetTenant(g.tenant(t, "t1"), &outpostv1.GetTenantRequest{TenantId: "t2"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("tenant tokens of deleted tenants are stale", func(t *testing.T) {
+		t.Parallel()
+		g := newGRPCTest(t)
+
+		_, err := g.client.GetTenant(g.tenant(t, "t1"), &outpostv1.GetTenantRequest{TenantId: "t1"})
+		assert.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("tenant tokens are checked against the portal IP allowlist", func(t *testing.T) {
+		t.Parallel()
+		g := newGRPCTest(t)
+		g.createTenant(t, models.Tenant{ID: "t1", PortalAllowedIPs: []string{"203.0.113.7"}})
+
+		_, err := g.client.GetTenant(g.tenant(t, "t1"), &outpostv1.GetTenantRequest{TenantId: "t1"})
+		assert.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestTenants(t *testing.T) {
+	t.Parallel()
+	g := newGRPCTest(t)
+
+	created, err := g.client.UpsertTenant(g.admin(t), &outpostv1.UpsertTenantRequest{
+		TenantId: "t1",
+		Metadata: map[string]string{"plan": "pro"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "t1", created.GetId())
+	assert.False(t, created.GetSandbox())
+
+	sandbox := true
+	updated, err := g.client.UpsertTenant(g.admin(t), &outpostv1.UpsertTenantRequest{TenantId: "t1", Sandbox: &sandbox})
+	require.NoError(t, err)
+	assert.True(t, updated.GetSandbox())
+	assert.Empty(t, updated.GetMetadata(), "upsert replaces the metadata")
+	assert.Equal(t, created.GetCreatedAt().AsTime(), updated.GetCreatedAt().AsTime())
+
+	_, err = g.client.DeleteTenant(g.admin(t), &outpostv1.DeleteTenantRequest{TenantId: "t1"})
+	require.NoError(t, err)
+	_, err = g.client.GetTenant(g.admin(t), &outpostv1.GetTenantRequest{TenantId: "t1"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestDestinations(t *testing.T) {
+	t.Parallel()
+
+	t.Run("create, update, list and delete", func(t *testing.T) {
+		t.Parallel()
+		g := newGRPCTest(t)
+		g.createTenant(t, models.Tenant{ID: "t1", CreatedAt: time.Now(), UpdatedAt: time.Now()})
+		ctx := g.tenant(t, "t1")
+
+		filter, err := structpb.NewStruct(map[string]any{"data": map[string]any{"amount": map[string]any{"$gte": 100}}})
+		require.NoError(t, err)
+		created, err := g.client.CreateDestination(ctx, &outpostv1.CreateDestinationRequest{
+			TenantId: "t1",
+			Type:     "webhook",
+			Topics:   []string{"order.created"},
+			Filter:   filter,
+			Config:   map[string]string{"url": "https://example.com/hook"},
+		})
+		require.NoError(t, err)
+		assert.NotEmpty(t, created.GetId())
+		assert.NotEmpty(t, created.GetCredentials()["secret"], "the create call reveals the generated secret")
+		assert.Equal(t, filter.AsMap(), created.GetFilter().AsMap())
+
+		config, err := structpb.NewStruct(map[string]any{"url": "https://example.com/v2"})
+		require.NoError(t, err)
+		updated, err := g.client.UpdateDestination(ctx, &outpostv1.UpdateDestinationRequest{
+			TenantId:      "t1",
+			DestinationId: created.GetId(),
+			Topics:        &outpostv1.Topics{Topics: []string{"user.created"}},
+			Config:        config,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"user.created"}, updated.GetTopics())
+		assert.Equal(t, "https://example.com/v2", updated.GetConfig()["url"])
+
+		stored, err := g.tenantStore.RetrieveDestination(t.Context(), "t1", created.GetId())
+		require.NoError(t, err)
+		assert.Equal(t, "https://example.com/v2", stored.Config["url"])
+		assert.NotNil(t, stored.TargetChange, "the changed target is recorded")
+
+		list, err := g.client.ListDestinations(ctx, &outpostv1.ListDestinationsRequest{TenantId: "t1", Type: []string{"webhook"}})
+		require.NoError(t, err)
+		require.Len(t, list.GetDestinations(), 1)
+		assert.Equal(t, created.GetId(), list.GetDestinations()[0].GetId())
+
+		_, err = g.client.DeleteDestination(ctx, &outpostv1.DeleteDestinationRequest{TenantId: "t1", DestinationId: created.GetId()})
+		require.NoError(t, err)
+		_, err = g.client.GetDestination(ctx, &outpostv1.GetDestinationRequest{TenantId: "t1", DestinationId: created.GetId()})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("validates topics", func(t *testing.T) {
+		t.Parallel()
+		g := newGRPCTest(t)
+		g.createTenant(t, models.Tenant{ID: "t1"})
+
+		_, err := g.client.CreateDestination(g.admin(t), &outpostv1.CreateDestinationRequest{
+			TenantId: "t1",
+			Type:     "webhook",
+			Topics:   []string{"unknown.topic"},
+			Config:   map[string]string{"url": "https://example.com/hook"},
+		})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("unknown tenant", func(t *testing.T) {
+		t.Parallel()
+		g := newGRPCTest(t)
+
+		_, err := g.client.ListDestinations(g.admin(t), &outpostv1.ListDestinationsRequest{TenantId: "t1"})
+		assert.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("requires ids", func(t *testing.T) {
+		t.Parallel()
+		g := newGRPCTest(t)
+
+		_, err := g.client.DeleteDestination(g.admin(t), &outpostv1.DeleteDestinationRequest{TenantId: "t1"})
+		assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
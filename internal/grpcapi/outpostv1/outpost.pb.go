@@ -0,0 +1,1402 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.11
+// 	protoc        v5.29.3
+// source: outpostv1/outpost.proto
+
+package outpostv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type PublishRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// id is generated when empty.
+	Id       string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TenantId string `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// destination_id publishes the event to a single destination.
+	DestinationId string `protobuf:"bytes,3,opt,name=destination_id,json=destinationId,proto3" json:"destination_id,omitempty"`
+	Topic         string `protobuf:"bytes,4,opt,name=topic,proto3" json:"topic,omitempty"`
+	Source        string `protobuf:"bytes,5,opt,name=source,proto3" json:"source,omitempty"`
+	// eligible_for_retry defaults to true.
+	EligibleForRetry *bool `protobuf:"varint,6,opt,name=eligible_for_retry,json=eligibleForRetry,proto3,oneof" json:"eligible_for_retry,omitempty"`
+	// time defaults to the time the event is received.
+	Time     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=time,proto3" json:"time,omitempty"`
+	Metadata map[string]string      `protobuf:"bytes,8,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// data is the event's data, a JSON object.
+	Data []byte `protobuf:"bytes,9,opt,name=data,proto3" json:"data,omitempty"`
+	// idempotency_key deduplicates retried publishes.
+	IdempotencyKey string `protobuf:"bytes,10,opt,name=idempotency_key,json=idempotencyKey,proto3" json:"idempotency_key,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PublishRequest) Reset() {
+	*x = PublishRequest{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishRequest) ProtoMessage() {}
+
+func (x *PublishRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishRequest.ProtoReflect.Descriptor instead.
+func (*PublishRequest) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *PublishRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PublishRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *PublishRequest) GetDestinationId() string {
+	if x != nil {
+		return x.DestinationId
+	}
+	return ""
+}
+
+func (x *PublishRequest) GetTopic() string {
+	if x != nil {
+		return x.Topic
+	}
+	return ""
+}
+
+func (x *PublishRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *PublishRequest) GetEligibleForRetry() bool {
+	if x != nil && x.EligibleForRetry != nil {
+		return *x.EligibleForRetry
+	}
+	return false
+}
+
+func (x *PublishRequest) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *PublishRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *PublishRequest) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *PublishRequest) GetIdempotencyKey() string {
+	if x != nil {
+		return x.IdempotencyKey
+	}
+	return ""
+}
+
+type PublishResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	// duplicate is true when the event was already published.
+	Duplicate      bool     `protobuf:"varint,2,opt,name=duplicate,proto3" json:"duplicate,omitempty"`
+	DestinationIds []string `protobuf:"bytes,3,rep,name=destination_ids,json=destinationIds,proto3" json:"destination_ids,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *PublishResponse) Reset() {
+	*x = PublishResponse{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PublishResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PublishResponse) ProtoMessage() {}
+
+func (x *PublishResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PublishResponse.ProtoReflect.Descriptor instead.
+func (*PublishResponse) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *PublishResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *PublishResponse) GetDuplicate() bool {
+	if x != nil {
+		return x.Duplicate
+	}
+	return false
+}
+
+func (x *PublishResponse) GetDestinationIds() []string {
+	if x != nil {
+		return x.DestinationIds
+	}
+	return nil
+}
+
+type Tenant struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Id                string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	DestinationsCount int32                  `protobuf:"varint,2,opt,name=destinations_count,json=destinationsCount,proto3" json:"destinations_count,omitempty"`
+	Topics            []string               `protobuf:"bytes,3,rep,name=topics,proto3" json:"topics,omitempty"`
+	Metadata          map[string]string      `protobuf:"bytes,4,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Sandbox           bool                   `protobuf:"varint,5,opt,name=sandbox,proto3" json:"sandbox,omitempty"`
+	CreatedAt         *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt         *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *Tenant) Reset() {
+	*x = Tenant{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Tenant) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Tenant) ProtoMessage() {}
+
+func (x *Tenant) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Tenant.ProtoReflect.Descriptor instead.
+func (*Tenant) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *Tenant) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Tenant) GetDestinationsCount() int32 {
+	if x != nil {
+		return x.DestinationsCount
+	}
+	return 0
+}
+
+func (x *Tenant) GetTopics() []string {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+func (x *Tenant) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Tenant) GetSandbox() bool {
+	if x != nil {
+		return x.Sandbox
+	}
+	return false
+}
+
+func (x *Tenant) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Tenant) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+type UpsertTenantRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TenantId string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// metadata replaces the tenant's metadata.
+	Metadata map[string]string `protobuf:"bytes,2,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// sandbox is left unchanged when unset.
+	Sandbox       *bool `protobuf:"varint,3,opt,name=sandbox,proto3,oneof" json:"sandbox,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpsertTenantRequest) Reset() {
+	*x = UpsertTenantRequest{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpsertTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpsertTenantRequest) ProtoMessage() {}
+
+func (x *UpsertTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpsertTenantRequest.ProtoReflect.Descriptor instead.
+func (*UpsertTenantRequest) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *UpsertTenantRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *UpsertTenantRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *UpsertTenantRequest) GetSandbox() bool {
+	if x != nil && x.Sandbox != nil {
+		return *x.Sandbox
+	}
+	return false
+}
+
+type GetTenantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTenantRequest) Reset() {
+	*x = GetTenantRequest{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTenantRequest) ProtoMessage() {}
+
+func (x *GetTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTenantRequest.ProtoReflect.Descriptor instead.
+func (*GetTenantRequest) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetTenantRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+type DeleteTenantRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTenantRequest) Reset() {
+	*x = DeleteTenantRequest{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTenantRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTenantRequest) ProtoMessage() {}
+
+func (x *DeleteTenantRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTenantRequest.ProtoReflect.Descriptor instead.
+func (*DeleteTenantRequest) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DeleteTenantRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+type DeleteTenantResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteTenantResponse) Reset() {
+	*x = DeleteTenantResponse{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteTenantResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteTenantResponse) ProtoMessage() {}
+
+func (x *DeleteTenantResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteTenantResponse.ProtoReflect.Descriptor instead.
+func (*DeleteTenantResponse) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{6}
+}
+
+type Destination struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	Id               string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	TenantId         string                 `protobuf:"bytes,2,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	Type             string                 `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	Topics           []string               `protobuf:"bytes,4,rep,name=topics,proto3" json:"topics,omitempty"`
+	Filter           *structpb.Struct       `protobuf:"bytes,5,opt,name=filter,proto3" json:"filter,omitempty"`
+	Config           map[string]string      `protobuf:"bytes,6,rep,name=config,proto3" json:"config,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Credentials      map[string]string      `protobuf:"bytes,7,rep,name=credentials,proto3" json:"credentials,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	DeliveryMetadata map[string]string      `protobuf:"bytes,8,rep,name=delivery_metadata,json=deliveryMetadata,proto3" json:"delivery_metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Metadata         map[string]string      `protobuf:"bytes,9,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Target           string                 `protobuf:"bytes,10,opt,name=target,proto3" json:"target,omitempty"`
+	TargetUrl        string                 `protobuf:"bytes,11,opt,name=target_url,json=targetUrl,proto3" json:"target_url,omitempty"`
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,12,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,13,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	// disabled_at is unset while the destination is enabled.
+	DisabledAt    *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=disabled_at,json=disabledAt,proto3" json:"disabled_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Destination) Reset() {
+	*x = Destination{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Destination) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Destination) ProtoMessage() {}
+
+func (x *Destination) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Destination.ProtoReflect.Descriptor instead.
+func (*Destination) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Destination) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Destination) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *Destination) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Destination) GetTopics() []string {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+func (x *Destination) GetFilter() *structpb.Struct {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *Destination) GetConfig() map[string]string {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *Destination) GetCredentials() map[string]string {
+	if x != nil {
+		return x.Credentials
+	}
+	return nil
+}
+
+func (x *Destination) GetDeliveryMetadata() map[string]string {
+	if x != nil {
+		return x.DeliveryMetadata
+	}
+	return nil
+}
+
+func (x *Destination) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Destination) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *Destination) GetTargetUrl() string {
+	if x != nil {
+		return x.TargetUrl
+	}
+	return ""
+}
+
+func (x *Destination) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Destination) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Destination) GetDisabledAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DisabledAt
+	}
+	return nil
+}
+
+type ListDestinationsRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TenantId string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// type and topics filter the destinations listed.
+	Type          []string `protobuf:"bytes,2,rep,name=type,proto3" json:"type,omitempty"`
+	Topics        []string `protobuf:"bytes,3,rep,name=topics,proto3" json:"topics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDestinationsRequest) Reset() {
+	*x = ListDestinationsRequest{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDestinationsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDestinationsRequest) ProtoMessage() {}
+
+func (x *ListDestinationsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDestinationsRequest.ProtoReflect.Descriptor instead.
+func (*ListDestinationsRequest) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ListDestinationsRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *ListDestinationsRequest) GetType() []string {
+	if x != nil {
+		return x.Type
+	}
+	return nil
+}
+
+func (x *ListDestinationsRequest) GetTopics() []string {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+type ListDestinationsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Destinations  []*Destination         `protobuf:"bytes,1,rep,name=destinations,proto3" json:"destinations,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDestinationsResponse) Reset() {
+	*x = ListDestinationsResponse{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDestinationsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDestinationsResponse) ProtoMessage() {}
+
+func (x *ListDestinationsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDestinationsResponse.ProtoReflect.Descriptor instead.
+func (*ListDestinationsResponse) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListDestinationsResponse) GetDestinations() []*Destination {
+	if x != nil {
+		return x.Destinations
+	}
+	return nil
+}
+
+type CreateDestinationRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TenantId string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	// id is generated when empty.
+	Id   string `protobuf:"bytes,2,opt,name=id,proto3" json:"id,omitempty"`
+	Type string `protobuf:"bytes,3,opt,name=type,proto3" json:"type,omitempty"`
+	// topics are the topics the destination subscribes to, or ["*"] for all.
+	Topics           []string          `protobuf:"bytes,4,rep,name=topics,proto3" json:"topics,omitempty"`
+	Filter           *structpb.Struct  `protobuf:"bytes,5,opt,name=filter,proto3" json:"filter,omitempty"`
+	Config           map[string]string `protobuf:"bytes,6,rep,name=config,proto3" json:"config,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Credentials      map[string]string `protobuf:"bytes,7,rep,name=credentials,proto3" json:"credentials,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	DeliveryMetadata map[string]string `protobuf:"bytes,8,rep,name=delivery_metadata,json=deliveryMetadata,proto3" json:"delivery_metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	Metadata         map[string]string `protobuf:"bytes,9,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *CreateDestinationRequest) Reset() {
+	*x = CreateDestinationRequest{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDestinationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDestinationRequest) ProtoMessage() {}
+
+func (x *CreateDestinationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDestinationRequest.ProtoReflect.Descriptor instead.
+func (*CreateDestinationRequest) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateDestinationRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *CreateDestinationRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateDestinationRequest) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *CreateDestinationRequest) GetTopics() []string {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+func (x *CreateDestinationRequest) GetFilter() *structpb.Struct {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *CreateDestinationRequest) GetConfig() map[string]string {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *CreateDestinationRequest) GetCredentials() map[string]string {
+	if x != nil {
+		return x.Credentials
+	}
+	return nil
+}
+
+func (x *CreateDestinationRequest) GetDeliveryMetadata() map[string]string {
+	if x != nil {
+		return x.DeliveryMetadata
+	}
+	return nil
+}
+
+func (x *CreateDestinationRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type GetDestinationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	DestinationId string                 `protobuf:"bytes,2,opt,name=destination_id,json=destinationId,proto3" json:"destination_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDestinationRequest) Reset() {
+	*x = GetDestinationRequest{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDestinationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDestinationRequest) ProtoMessage() {}
+
+func (x *GetDestinationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDestinationRequest.ProtoReflect.Descriptor instead.
+func (*GetDestinationRequest) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *GetDestinationRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *GetDestinationRequest) GetDestinationId() string {
+	if x != nil {
+		return x.DestinationId
+	}
+	return ""
+}
+
+// UpdateDestinationRequest changes the fields that are set. config,
+// credentials, delivery_metadata and metadata are merged into the
+// destination's, and a key set to null is removed.
+type UpdateDestinationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	DestinationId string                 `protobuf:"bytes,2,opt,name=destination_id,json=destinationId,proto3" json:"destination_id,omitempty"`
+	// topics replaces the destination's topics.
+	Topics *Topics `protobuf:"bytes,3,opt,name=topics,proto3" json:"topics,omitempty"`
+	// filter replaces the destination's filter; an empty filter removes it.
+	Filter           *structpb.Struct `protobuf:"bytes,4,opt,name=filter,proto3" json:"filter,omitempty"`
+	Config           *structpb.Struct `protobuf:"bytes,5,opt,name=config,proto3" json:"config,omitempty"`
+	Credentials      *structpb.Struct `protobuf:"bytes,6,opt,name=credentials,proto3" json:"credentials,omitempty"`
+	DeliveryMetadata *structpb.Struct `protobuf:"bytes,7,opt,name=delivery_metadata,json=deliveryMetadata,proto3" json:"delivery_metadata,omitempty"`
+	Metadata         *structpb.Struct `protobuf:"bytes,8,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UpdateDestinationRequest) Reset() {
+	*x = UpdateDestinationRequest{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateDestinationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateDestinationRequest) ProtoMessage() {}
+
+func (x *UpdateDestinationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateDestinationRequest.ProtoReflect.Descriptor instead.
+func (*UpdateDestinationRequest) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *UpdateDestinationRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *UpdateDestinationRequest) GetDestinationId() string {
+	if x != nil {
+		return x.DestinationId
+	}
+	return ""
+}
+
+func (x *UpdateDestinationRequest) GetTopics() *Topics {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+func (x *UpdateDestinationRequest) GetFilter() *structpb.Struct {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *UpdateDestinationRequest) GetConfig() *structpb.Struct {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *UpdateDestinationRequest) GetCredentials() *structpb.Struct {
+	if x != nil {
+		return x.Credentials
+	}
+	return nil
+}
+
+func (x *UpdateDestinationRequest) GetDeliveryMetadata() *structpb.Struct {
+	if x != nil {
+		return x.DeliveryMetadata
+	}
+	return nil
+}
+
+func (x *UpdateDestinationRequest) GetMetadata() *structpb.Struct {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type Topics struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Topics        []string               `protobuf:"bytes,1,rep,name=topics,proto3" json:"topics,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Topics) Reset() {
+	*x = Topics{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Topics) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Topics) ProtoMessage() {}
+
+func (x *Topics) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Topics.ProtoReflect.Descriptor instead.
+func (*Topics) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Topics) GetTopics() []string {
+	if x != nil {
+		return x.Topics
+	}
+	return nil
+}
+
+type DeleteDestinationRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	DestinationId string                 `protobuf:"bytes,2,opt,name=destination_id,json=destinationId,proto3" json:"destination_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDestinationRequest) Reset() {
+	*x = DeleteDestinationRequest{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDestinationRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDestinationRequest) ProtoMessage() {}
+
+func (x *DeleteDestinationRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDestinationRequest.ProtoReflect.Descriptor instead.
+func (*DeleteDestinationRequest) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeleteDestinationRequest) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *DeleteDestinationRequest) GetDestinationId() string {
+	if x != nil {
+		return x.DestinationId
+	}
+	return ""
+}
+
+type DeleteDestinationResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteDestinationResponse) Reset() {
+	*x = DeleteDestinationResponse{}
+	mi := &file_outpostv1_outpost_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteDestinationResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteDestinationResponse) ProtoMessage() {}
+
+func (x *DeleteDestinationResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_outpostv1_outpost_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteDestinationResponse.ProtoReflect.Descriptor instead.
+func (*DeleteDestinationResponse) Descriptor() ([]byte, []int) {
+	return file_outpostv1_outpost_proto_rawDescGZIP(), []int{15}
+}
+
+var File_outpostv1_outpost_proto protoreflect.FileDescriptor
+
+const file_outpostv1_outpost_proto_rawDesc = "" +
+	"\n" +
+	"\x17outpostv1/outpost.proto\x12\n" +
+	"outpost.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\xcc\x03\n" +
+	"\x0ePublishRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12%\n" +
+	"\x0edestination_id\x18\x03 \x01(\tR\rdestinationId\x12\x14\n" +
+	"\x05topic\x18\x04 \x01(\tR\x05topic\x12\x16\n" +
+	"\x06source\x18\x05 \x01(\tR\x06source\x121\n" +
+	"\x12eligible_for_retry\x18\x06 \x01(\bH\x00R\x10eligibleForRetry\x88\x01\x01\x12.\n" +
+	"\x04time\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12D\n" +
+	"\bmetadata\x18\b \x03(\v2(.outpost.v1.PublishRequest.MetadataEntryR\bmetadata\x12\x12\n" +
+	"\x04data\x18\t \x01(\fR\x04data\x12'\n" +
+	"\x0fidempotency_key\x18\n" +
+	" \x01(\tR\x0eidempotencyKey\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\x15\n" +
+	"\x13_eligible_for_retry\"h\n" +
+	"\x0fPublishResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1c\n" +
+	"\tduplicate\x18\x02 \x01(\bR\tduplicate\x12'\n" +
+	"\x0fdestination_ids\x18\x03 \x03(\tR\x0edestinationIds\"\xea\x02\n" +
+	"\x06Tenant\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12-\n" +
+	"\x12destinations_count\x18\x02 \x01(\x05R\x11destinationsCount\x12\x16\n" +
+	"\x06topics\x18\x03 \x03(\tR\x06topics\x12<\n" +
+	"\bmetadata\x18\x04 \x03(\v2 .outpost.v1.Tenant.MetadataEntryR\bmetadata\x12\x18\n" +
+	"\asandbox\x18\x05 \x01(\bR\asandbox\x129\n" +
+	"\n" +
+	"created_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xe5\x01\n" +
+	"\x13UpsertTenantRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12I\n" +
+	"\bmetadata\x18\x02 \x03(\v2-.outpost.v1.UpsertTenantRequest.MetadataEntryR\bmetadata\x12\x1d\n" +
+	"\asandbox\x18\x03 \x01(\bH\x00R\asandbox\x88\x01\x01\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01B\n" +
+	"\n" +
+	"\b_sandbox\"/\n" +
+	"\x10GetTenantRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\"2\n" +
+	"\x13DeleteTenantRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\"\x16\n" +
+	"\x14DeleteTenantResponse\"\xa6\a\n" +
+	"\vDestination\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x1b\n" +
+	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x16\n" +
+	"\x06topics\x18\x04 \x03(\tR\x06topics\x12/\n" +
+	"\x06filter\x18\x05 \x01(\v2\x17.google.protobuf.StructR\x06filter\x12;\n" +
+	"\x06config\x18\x06 \x03(\v2#.outpost.v1.Destination.ConfigEntryR\x06config\x12J\n" +
+	"\vcredentials\x18\a \x03(\v2(.outpost.v1.Destination.CredentialsEntryR\vcredentials\x12Z\n" +
+	"\x11delivery_metadata\x18\b \x03(\v2-.outpost.v1.Destination.DeliveryMetadataEntryR\x10deliveryMetadata\x12A\n" +
+	"\bmetadata\x18\t \x03(\v2%.outpost.v1.Destination.MetadataEntryR\bmetadata\x12\x16\n" +
+	"\x06target\x18\n" +
+	" \x01(\tR\x06target\x12\x1d\n" +
+	"\n" +
+	"target_url\x18\v \x01(\tR\ttargetUrl\x129\n" +
+	"\n" +
+	"created_at\x18\f \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\r \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12;\n" +
+	"\vdisabled_at\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"disabledAt\x1a9\n" +
+	"\vConfigEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a>\n" +
+	"\x10CredentialsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aC\n" +
+	"\x15DeliveryMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"b\n" +
+	"\x17ListDestinationsRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x12\n" +
+	"\x04type\x18\x02 \x03(\tR\x04type\x12\x16\n" +
+	"\x06topics\x18\x03 \x03(\tR\x06topics\"W\n" +
+	"\x18ListDestinationsResponse\x12;\n" +
+	"\fdestinations\x18\x01 \x03(\v2\x17.outpost.v1.DestinationR\fdestinations\"\xfd\x05\n" +
+	"\x18CreateDestinationRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x0e\n" +
+	"\x02id\x18\x02 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x03 \x01(\tR\x04type\x12\x16\n" +
+	"\x06topics\x18\x04 \x03(\tR\x06topics\x12/\n" +
+	"\x06filter\x18\x05 \x01(\v2\x17.google.protobuf.StructR\x06filter\x12H\n" +
+	"\x06config\x18\x06 \x03(\v20.outpost.v1.CreateDestinationRequest.ConfigEntryR\x06config\x12W\n" +
+	"\vcredentials\x18\a \x03(\v25.outpost.v1.CreateDestinationRequest.CredentialsEntryR\vcredentials\x12g\n" +
+	"\x11delivery_metadata\x18\b \x03(\v2:.outpost.v1.CreateDestinationRequest.DeliveryMetadataEntryR\x10deliveryMetadata\x12N\n" +
+	"\bmetadata\x18\t \x03(\v22.outpost.v1.CreateDestinationRequest.MetadataEntryR\bmetadata\x1a9\n" +
+	"\vConfigEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a>\n" +
+	"\x10CredentialsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1aC\n" +
+	"\x15DeliveryMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"[\n" +
+	"\x15GetDestinationRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12%\n" +
+	"\x0edestination_id\x18\x02 \x01(\tR\rdestinationId\"\xa2\x03\n" +
+	"\x18UpdateDestinationRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12%\n" +
+	"\x0edestination_id\x18\x02 \x01(\tR\rdestinationId\x12*\n" +
+	"\x06topics\x18\x03 \x01(\v2\x12.outpost.v1.TopicsR\x06topics\x12/\n" +
+	"\x06filter\x18\x04 \x01(\v2\x17.google.protobuf.StructR\x06filter\x12/\n" +
+	"\x06config\x18\x05 \x01(\v2\x17.google.protobuf.StructR\x06config\x129\n" +
+	"\vcredentials\x18\x06 \x01(\v2\x17.google.protobuf.StructR\vcredentials\x12D\n" +
+	"\x11delivery_metadata\x18\a \x01(\v2\x17.google.protobuf.StructR\x10deliveryMetadata\x123\n" +
+	"\bmetadata\x18\b \x01(\v2\x17.google.protobuf.StructR\bmetadata\" \n" +
+	"\x06Topics\x12\x16\n" +
+	"\x06topics\x18\x01 \x03(\tR\x06topics\"^\n" +
+	"\x18DeleteDestinationRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12%\n" +
+	"\x0edestination_id\x18\x02 \x01(\tR\rdestinationId\"\x1b\n" +
+	"\x19DeleteDestinationResponse2\xdb\x05\n" +
+	"\aOutpost\x12B\n" +
+	"\aPublish\x12\x1a.outpost.v1.PublishRequest\x1a\x1b.outpost.v1.PublishResponse\x12C\n" +
+	"\fUpsertTenant\x12\x1f.outpost.v1.UpsertTenantRequest\x1a\x12.outpost.v1.Tenant\x12=\n" +
+	"\tGetTenant\x12\x1c.outpost.v1.GetTenantRequest\x1a\x12.outpost.v1.Tenant\x12Q\n" +
+	"\fDeleteTenant\x12\x1f.outpost.v1.DeleteTenantRequest\x1a .outpost.v1.DeleteTenantResponse\x12]\n" +
+	"\x10ListDestinations\x12#.outpost.v1.ListDestinationsRequest\x1a$.outpost.v1.ListDestinationsResponse\x12R\n" +
+	"\x11CreateDestination\x12$.outpost.v1.CreateDestinationRequest\x1a\x17.outpost.v1.Destination\x12L\n" +
+	"\x0eGetDestination\x12!.outpost.v1.GetDestinationRequest\x1a\x17.outpost.v1.Destination\x12R\n" +
+	"\x11UpdateDestination\x12$.outpost.v1.UpdateDestinationRequest\x1a\x17.outpost.v1.Destination\x12`\n" +
+	"\x11DeleteDestination\x12$.outpost.v1.DeleteDestinationRequest\x1a%.outpost.v1.DeleteDestinationResponseB8Z6github.com/hookdeck/outpost/internal/grpcapi/outpostv1b\x06proto3"
+
+var (
+	file_outpostv1_outpost_proto_rawDescOnce sync.Once
+	file_outpostv1_outpost_proto_rawDescData []byte
+)
+
+func file_outpostv1_outpost_proto_rawDescGZIP() []byte {
+	file_outpostv1_outpost_proto_rawDescOnce.Do(func() {
+		file_outpostv1_outpost_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_outpostv1_outpost_proto_rawDesc), len(file_outpostv1_outpost_proto_rawDesc)))
+	})
+	return file_outpostv1_outpost_proto_rawDescData
+}
+
+var file_outpostv1_outpost_proto_msgTypes = make([]protoimpl.MessageInfo, 27)
+var file_outpostv1_outpost_proto_goTypes = []any{
+	(*PublishRequest)(nil),            // 0: outpost.v1.PublishRequest
+	(*PublishResponse)(nil),           // 1: outpost.v1.PublishResponse
+	(*Tenant)(nil),                    // 2: outpost.v1.Tenant
+	(*UpsertTenantRequest)(nil),       // 3: outpost.v1.UpsertTenantRequest
+	(*GetTenantRequest)(nil),          // 4: outpost.v1.GetTenantRequest
+	(*DeleteTenantRequest)(nil),       // 5: outpost.v1.DeleteTenantRequest
+	(*DeleteTenantResponse)(nil),      // 6: outpost.v1.DeleteTenantResponse
+	(*Destination)(nil),               // 7: outpost.v1.Destination
+	(*ListDestinationsRequest)(nil),   // 8: outpost.v1.ListDestinationsRequest
+	(*ListDestinationsResponse)(nil),  // 9: outpost.v1.ListDestinationsResponse
+	(*CreateDestinationRequest)(nil),  // 10: outpost.v1.CreateDestinationRequest
+	(*GetDestinationRequest)(nil),     // 11: outpost.v1.GetDestinationRequest
+	(*UpdateDestinationRequest)(nil),  // 12: outpost.v1.UpdateDestinationRequest
+	(*Topics)(nil),                    // 13: outpost.v1.Topics
+	(*DeleteDestinationRequest)(nil),  // 14: outpost.v1.DeleteDestinationRequest
+	(*DeleteDestinationResponse)(nil), // 15: outpost.v1.DeleteDestinationResponse
+	nil,                               // 16: outpost.v1.PublishRequest.MetadataEntry
+	nil,                               // 17: outpost.v1.Tenant.MetadataEntry
+	nil,                               // 18: outpost.v1.UpsertTenantRequest.MetadataEntry
+	nil,                               // 19: outpost.v1.Destination.ConfigEntry
+	nil,                               // 20: outpost.v1.Destination.CredentialsEntry
+	nil,                               // 21: outpost.v1.Destination.DeliveryMetadataEntry
+	nil,                               // 22: outpost.v1.Destination.MetadataEntry
+	nil,                               // 23: outpost.v1.CreateDestinationRequest.ConfigEntry
+	nil,                               // 24: outpost.v1.CreateDestinationRequest.CredentialsEntry
+	nil,                               // 25: outpost.v1.CreateDestinationRequest.DeliveryMetadataEntry
+	nil,                               // 26: outpost.v1.CreateDestinationRequest.MetadataEntry
+	(*timestamppb.Timestamp)(nil),     // 27: google.protobuf.Timestamp
+	(*structpb.Struct)(nil),           // 28: google.protobuf.Struct
+}
+var file_outpostv1_outpost_proto_depIdxs = []int32{
+	27, // 0: outpost.v1.PublishRequest.time:type_name -> google.protobuf.Timestamp
+	16, // 1: outpost.v1.PublishRequest.metadata:type_name -> outpost.v1.PublishRequest.MetadataEntry
+	17, // 2: outpost.v1.Tenant.metadata:type_name -> outpost.v1.Tenant.MetadataEntry
+	27, // 3: outpost.v1.Tenant.created_at:type_name -> google.protobuf.Timestamp
+	27, // 4: outpost.v1.Tenant.updated_at:type_name -> google.protobuf.Timestamp
+	18, // 5: outpost.v1.UpsertTenantRequest.metadata:type_name -> outpost.v1.UpsertTenantRequest.MetadataEntry
+	28, // 6: outpost.v1.Destination.filter:type_name -> google.protobuf.Struct
+	19, // 7: outpost.v1.Destination.config:type_name -> outpost.v1.Destination.ConfigEntry
+	20, // 8: outpost.v1.Destination.credentials:type_name -> outpost.v1.Destination.CredentialsEntry
+	21, // 9: outpost.v1.Destination.delivery_metadata:type_name -> outpost.v1.Destination.DeliveryMetadataEntry
+	22, // 10: outpost.v1.Destination.metadata:type_name -> outpost.v1.Destination.MetadataEntry
+	27, // 11: outpost.v1.Destination.created_at:type_name -> google.protobuf.Timestamp
+	27, // 12: outpost.v1.Destination.updated_at:type_name -> google.protobuf.Timestamp
+	27, // 13: outpost.v1.Destination.disabled_at:type_name -> google.protobuf.Timestamp
+	7,  // 14: outpost.v1.ListDestinationsResponse.destinations:type_name -> outpost.v1.Destination
+	28, // 15: outpost.v1.CreateDestinationRequest.filter:type_name -> google.protobuf.Struct
+	23, // 16: outpost.v1.CreateDestinationRequest.config:type_name -> outpost.v1.CreateDestinationRequest.ConfigEntry
+	24, // 17: outpost.v1.CreateDestinationRequest.credentials:type_name -> outpost.v1.CreateDestinationRequest.CredentialsEntry
+	25, // 18: outpost.v1.CreateDestinationRequest.delivery_metadata:type_name -> outpost.v1.CreateDestinationRequest.DeliveryMetadataEntry
+	26, // 19: outpost.v1.CreateDestinationRequest.metadata:type_name -> outpost.v1.CreateDestinationRequest.MetadataEntry
+	13, // 20: outpost.v1.UpdateDestinationRequest.topics:type_name -> outpost.v1.Topics
+	28, // 21: outpost.v1.UpdateDestinationRequest.filter:type_name -> google.protobuf.Struct
+	28, // 22: outpost.v1.UpdateDestinationRequest.config:type_name -> google.protobuf.Struct
+	28, // 23: outpost.v1.UpdateDestinationRequest.credentials:type_name -> google.protobuf.Struct
+	28, // 24: outpost.v1.UpdateDestinationRequest.delivery_metadata:type_name -> google.protobuf.Struct
+	28, // 25: outpost.v1.UpdateDestinationRequest.metadata:type_name -> google.protobuf.Struct
+	0,  // 26: outpost.v1.Outpost.Publish:input_type -> outpost.v1.PublishRequest
+	3,  // 27: outpost.v1.Outpost.UpsertTenant:input_type -> outpost.v1.UpsertTenantRequest
+	4,  // 28: outpost.v1.Outpost.GetTenant:input_type -> outpost.v1.GetTenantRequest
+	5,  // 29: outpost.v1.Outpost.DeleteTenant:input_type -> outpost.v1.DeleteTenantRequest
+	8,  // 30: outpost.v1.Outpost.ListDestinations:input_type -> outpost.v1.ListDestinationsRequest
+	10, // 31: outpost.v1.Outpost.CreateDestination:input_type -> outpost.v1.CreateDestinationRequest
+	11, // 32: outpost.v1.Outpost.GetDestination:input_type -> outpost.v1.GetDestinationRequest
+	12, // 33: outpost.v1.Outpost.UpdateDestination:input_type -> outpost.v1.UpdateDestinationRequest
+	14, // 34: outpost.v1.Outpost.DeleteDestination:input_type -> outpost.v1.DeleteDestinationRequest
+	1,  // 35: outpost.v1.Outpost.Publish:output_type -> outpost.v1.PublishResponse
+	2,  // 36: outpost.v1.Outpost.UpsertTenant:output_type -> outpost.v1.Tenant
+	2,  // 37: outpost.v1.Outpost.GetTenant:output_type -> outpost.v1.Tenant
+	6,  // 38: outpost.v1.Outpost.DeleteTenant:output_type -> outpost.v1.DeleteTenantResponse
+	9,  // 39: outpost.v1.Outpost.ListDestinations:output_type -> outpost.v1.ListDestinationsResponse
+	7,  // 40: outpost.v1.Outpost.CreateDestination:output_type -> outpost.v1.Destination
+	7,  // 41: outpost.v1.Outpost.GetDestination:output_type -> outpost.v1.Destination
+	7,  // 42: outpost.v1.Outpost.UpdateDestination:output_type -> outpost.v1.Destination
+	15, // 43: outpost.v1.Outpost.DeleteDestination:output_type -> outpost.v1.DeleteDestinationResponse
+	35, // [35:44] is the sub-list for method output_type
+	26, // [26:35] is the sub-list for method input_type
+	26, // [26:26] is the sub-list for extension type_name
+	26, // [26:26] is the sub-list for extension extendee
+	0,  // [0:26] is the sub-list for field type_name
+}
+
+func init() { file_outpostv1_outpost_proto_init() }
+func file_outpostv1_outpost_proto_init() {
+	if File_outpostv1_outpost_proto != nil {
+		return
+	}
+	file_outpostv1_outpost_proto_msgTypes[0].OneofWrappers = []any{}
+	file_outpostv1_outpost_proto_msgTypes[3].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_outpostv1_outpost_proto_rawDesc), len(file_outpostv1_outpost_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   27,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_outpostv1_outpost_proto_goTypes,
+		DependencyIndexes: file_outpostv1_outpost_proto_depIdxs,
+		MessageInfos:      file_outpostv1_outpost_proto_msgTypes,
+	}.Build()
+	File_outpostv1_outpost_proto = out.File
+	file_outpostv1_outpost_proto_goTypes = nil
+	file_outpostv1_outpost_proto_depIdxs = nil
+}
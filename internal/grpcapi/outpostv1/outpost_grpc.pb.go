@@ -0,0 +1,465 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.6.2
+// - protoc             v5.29.3
+// source: outpostv1/outpost.proto
+
+package outpostv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Outpost_Publish_FullMethodName           = "/outpost.v1.Outpost/Publish"
+	Outpost_UpsertTenant_FullMethodName      = "/outpost.v1.Outpost/UpsertTenant"
+	Outpost_GetTenant_FullMethodName         = "/outpost.v1.Outpost/GetTenant"
+	Outpost_DeleteTenant_FullMethodName      = "/outpost.v1.Outpost/DeleteTenant"
+	Outpost_ListDestinations_FullMethodName  = "/outpost.v1.Outpost/ListDestinations"
+	Outpost_CreateDestination_FullMethodName = "/outpost.v1.Outpost/CreateDestination"
+	Outpost_GetDestination_FullMethodName    = "/outpost.v1.Outpost/GetDestination"
+	Outpost_UpdateDestination_FullMethodName = "/outpost.v1.Outpost/UpdateDestination"
+	Outpost_DeleteDestination_FullMethodName = "/outpost.v1.Outpost/DeleteDestination"
+)
+
+// OutpostClient is the client API for Outpost service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Outpost is the gRPC API for publishing events and managing tenants and
+// their destinations. Each call is served by the same services as its REST
+// API endpoint, with the same authentication: the API key, or a tenant JWT,
+// is sent in the "authorization" metadata as "Bearer <token>".
+type OutpostClient interface {
+	// Publish publishes an event, like POST /api/v1/publish.
+	Publish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (*PublishResponse, error)
+	// UpsertTenant creates or updates a tenant, like PUT /api/v1/tenants/{tenant_id}.
+	UpsertTenant(ctx context.Context, in *UpsertTenantRequest, opts ...grpc.CallOption) (*Tenant, error)
+	// GetTenant retrieves a tenant, like GET /api/v1/tenants/{tenant_id}.
+	GetTenant(ctx context.Context, in *GetTenantRequest, opts ...grpc.CallOption) (*Tenant, error)
+	// DeleteTenant deletes a tenant and its destinations, like
+	// DELETE /api/v1/tenants/{tenant_id}.
+	DeleteTenant(ctx context.Context, in *DeleteTenantRequest, opts ...grpc.CallOption) (*DeleteTenantResponse, error)
+	// ListDestinations lists a tenant's destinations, like
+	// GET /api/v1/tenants/{tenant_id}/destinations.
+	ListDestinations(ctx context.Context, in *ListDestinationsRequest, opts ...grpc.CallOption) (*ListDestinationsResponse, error)
+	// CreateDestination creates a destination, like
+	// POST /api/v1/tenants/{tenant_id}/destinations.
+	CreateDestination(ctx context.Context, in *CreateDestinationRequest, opts ...grpc.CallOption) (*Destination, error)
+	// GetDestination retrieves a destination, like
+	// GET /api/v1/tenants/{tenant_id}/destinations/{destination_id}.
+	GetDestination(ctx context.Context, in *GetDestinationRequest, opts ...grpc.CallOption) (*Destination, error)
+	// UpdateDestination updates a destination, like
+	// PATCH /api/v1/tenants/{tenant_id}/destinations/{destination_id}.
+	UpdateDestination(ctx context.Context, in *UpdateDestinationRequest, opts ...grpc.CallOption) (*Destination, error)
+	// DeleteDestination deletes a destination, like
+	// DELETE /api/v1/tenants/{tenant_id}/destinations/{destination_id}.
+	DeleteDestination(ctx context.Context, in *DeleteDestinationRequest, opts ...grpc.CallOption) (*DeleteDestinationResponse, error)
+}
+
+type outpostClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewOutpostClient(cc grpc.ClientConnInterface) OutpostClient {
+	return &outpostClient{cc}
+}
+
+func (c *outpostClient) Publish(ctx context.Context, in *PublishRequest, opts ...grpc.CallOption) (*PublishResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(PublishResponse)
+	err := c.cc.Invoke(ctx, Outpost_Publish_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outpostClient) UpsertTenant(ctx context.Context, in *UpsertTenantRequest, opts ...grpc.CallOption) (*Tenant, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tenant)
+	err := c.cc.Invoke(ctx, Outpost_UpsertTenant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outpostClient) GetTenant(ctx context.Context, in *GetTenantRequest, opts ...grpc.CallOption) (*Tenant, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Tenant)
+	err := c.cc.Invoke(ctx, Outpost_GetTenant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outpostClient) DeleteTenant(ctx context.Context, in *DeleteTenantRequest, opts ...grpc.CallOption) (*DeleteTenantResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteTenantResponse)
+	err := c.cc.Invoke(ctx, Outpost_DeleteTenant_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outpostClient) ListDestinations(ctx context.Context, in *ListDestinationsRequest, opts ...grpc.CallOption) (*ListDestinationsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDestinationsResponse)
+	err := c.cc.Invoke(ctx, Outpost_ListDestinations_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outpostClient) CreateDestination(ctx context.Context, in *CreateDestinationRequest, opts ...grpc.CallOption) (*Destination, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Destination)
+	err := c.cc.Invoke(ctx, Outpost_CreateDestination_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outpostClient) GetDestination(ctx context.Context, in *GetDestinationRequest, opts ...grpc.CallOption) (*Destination, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Destination)
+	err := c.cc.Invoke(ctx, Outpost_GetDestination_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outpostClient) UpdateDestination(ctx context.Context, in *UpdateDestinationRequest, opts ...grpc.CallOption) (*Destination, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Destination)
+	err := c.cc.Invoke(ctx, Outpost_UpdateDestination_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *outpostClient) DeleteDestination(ctx context.Context, in *DeleteDestinationRequest, opts ...grpc.CallOption) (*DeleteDestinationResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteDestinationResponse)
+	err := c.cc.Invoke(ctx, Outpost_DeleteDestination_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// OutpostServer is the server API for Outpost service.
+// All implementations must embed UnimplementedOutpostServer
+// for forward compatibility.
+//
+// Outpost is the gRPC API for publishing events and managing tenants and
+// their destinations. Each call is served by the same services as its REST
+// API endpoint, with the same authentication: the API key, or a tenant JWT,
+// is sent in the "authorization" metadata as "Bearer <token>".
+type OutpostServer interface {
+	// Publish publishes an event, like POST /api/v1/publish.
+	Publish(context.Context, *PublishRequest) (*PublishResponse, error)
+	// UpsertTenant creates or updates a tenant, like PUT /api/v1/tenants/{tenant_id}.
+	UpsertTenant(context.Context, *UpsertTenantRequest) (*Tenant, error)
+	// GetTenant retrieves a tenant, like GET /api/v1/tenants/{tenant_id}.
+	GetTenant(context.Context, *GetTenantRequest) (*Tenant, error)
+	// DeleteTenant deletes a tenant and its destinations, like
+	// DELETE /api/v1/tenants/{tenant_id}.
+	DeleteTenant(context.Context, *DeleteTenantRequest) (*DeleteTenantResponse, error)
+	// ListDestinations lists a tenant's destinations, like
+	// GET /api/v1/tenants/{tenant_id}/destinations.
+	ListDestinations(context.Context, *ListDestinationsRequest) (*ListDestinationsResponse, error)
+	// CreateDestination creates a destination, like
+	// POST /api/v1/tenants/{tenant_id}/destinations.
+	CreateDestination(context.Context, *CreateDestinationRequest) (*Destination, error)
+	// GetDestination retrieves a destination, like
+	// GET /api/v1/tenants/{tenant_id}/destinations/{destination_id}.
+	GetDestination(context.Context, *GetDestinationRequest) (*Destination, error)
+	// UpdateDestination updates a destination, like
+	// PATCH /api/v1/tenants/{tenant_id}/destinations/{destination_id}.
+	UpdateDestination(context.Context, *UpdateDestinationRequest) (*Destination, error)
+	// DeleteDestination deletes a destination, like
+	// DELETE /api/v1/tenants/{tenant_id}/destinations/{destination_id}.
+	DeleteDestination(context.Context, *DeleteDestinationRequest) (*DeleteDestinationResponse, error)
+	mustEmbedUnimplementedOutpostServer()
+}
+
+// UnimplementedOutpostServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedOutpostServer struct{}
+
+func (UnimplementedOutpostServer) Publish(context.Context, *PublishRequest) (*PublishResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method Publish not implemented")
+}
+func (UnimplementedOutpostServer) UpsertTenant(context.Context, *UpsertTenantRequest) (*Tenant, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpsertTenant not implemented")
+}
+func (UnimplementedOutpostServer) GetTenant(context.Context, *GetTenantRequest) (*Tenant, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetTenant not implemented")
+}
+func (UnimplementedOutpostServer) DeleteTenant(context.Context, *DeleteTenantRequest) (*DeleteTenantResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteTenant not implemented")
+}
+func (UnimplementedOutpostServer) ListDestinations(context.Context, *ListDestinationsRequest) (*ListDestinationsResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method ListDestinations not implemented")
+}
+func (UnimplementedOutpostServer) CreateDestination(context.Context, *CreateDestinationRequest) (*Destination, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateDestination not implemented")
+}
+func (UnimplementedOutpostServer) GetDestination(context.Context, *GetDestinationRequest) (*Destination, error) {
+	return nil, status.Error(codes.Unimplemented, "method GetDestination not implemented")
+}
+func (UnimplementedOutpostServer) UpdateDestination(context.Context, *UpdateDestinationRequest) (*Destination, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateDestination not implemented")
+}
+func (UnimplementedOutpostServer) DeleteDestination(context.Context, *DeleteDestinationRequest) (*DeleteDestinationResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteDestination not implemented")
+}
+func (UnimplementedOutpostServer) mustEmbedUnimplementedOutpostServer() {}
+func (UnimplementedOutpostServer) testEmbeddedByValue()                 {}
+
+// UnsafeOutpostServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to OutpostServer will
+// result in compilation errors.
+type UnsafeOutpostServer interface {
+	mustEmbedUnimplementedOutpostServer()
+}
+
+func RegisterOutpostServer(s grpc.ServiceRegistrar, srv OutpostServer) {
+	// If the following call panics, it indicates UnimplementedOutpostServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Outpost_ServiceDesc, srv)
+}
+
+func _Outpost_Publish_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PublishRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutpostServer).Publish(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Outpost_Publish_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutpostServer).Publish(ctx, req.(*PublishRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Outpost_UpsertTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpsertTenantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutpostServer).UpsertTenant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Outpost_UpsertTenant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutpostServer).UpsertTenant(ctx, req.(*UpsertTenantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Outpost_GetTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTenantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutpostServer).GetTenant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Outpost_GetTenant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutpostServer).GetTenant(ctx, req.(*GetTenantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Outpost_DeleteTenant_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteTenantRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutpostServer).DeleteTenant(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Outpost_DeleteTenant_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutpostServer).DeleteTenant(ctx, req.(*DeleteTenantRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Outpost_ListDestinations_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListDestinationsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutpostServer).ListDestinations(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Outpost_ListDestinations_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutpostServer).ListDestinations(ctx, req.(*ListDestinationsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Outpost_CreateDestination_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDestinationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutpostServer).CreateDestination(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Outpost_CreateDestination_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutpostServer).CreateDestination(ctx, req.(*CreateDestinationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Outpost_GetDestination_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetDestinationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutpostServer).GetDestination(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Outpost_GetDestination_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutpostServer).GetDestination(ctx, req.(*GetDestinationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Outpost_UpdateDestination_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateDestinationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutpostServer).UpdateDestination(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Outpost_UpdateDestination_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutpostServer).UpdateDestination(ctx, req.(*UpdateDestinationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Outpost_DeleteDestination_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteDestinationRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OutpostServer).DeleteDestination(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Outpost_DeleteDestination_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OutpostServer).DeleteDestination(ctx, req.(*DeleteDestinationRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Outpost_ServiceDesc is the grpc.ServiceDesc for Outpost service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Outpost_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "outpost.v1.Outpost",
+	HandlerType: (*OutpostServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Publish",
+			Handler:    _Outpost_Publish_Handler,
+		},
+		{
+			MethodName: "UpsertTenant",
+			Handler:    _Outpost_UpsertTenant_Handler,
+		},
+		{
+			MethodName: "GetTenant",
+			Handler:    _Outpost_GetTenant_Handler,
+		},
+		{
+			MethodName: "DeleteTenant",
+			Handler:    _Outpost_DeleteTenant_Handler,
+		},
+		{
+			MethodName: "ListDestinations",
+			Handler:    _Outpost_ListDestinations_Handler,
+		},
+		{
+			MethodName: "CreateDestination",
+			Handler:    _Outpost_CreateDestination_Handler,
+		},
+		{
+			MethodName: "GetDestination",
+			Handler:    _Outpost_GetDestination_Handler,
+		},
+		{
+			MethodName: "UpdateDestination",
+			Handler:    _Outpost_UpdateDestination_Handler,
+		},
+		{
+			MethodName: "DeleteDestination",
+			Handler:    _Outpost_DeleteDestination_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "outpostv1/outpost.proto",
+}
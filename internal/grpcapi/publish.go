@@ -0,0 +1,201 @@
+package grpcapi
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/grpcapi/outpostv1"
+	"github.com/hookdeck/outpost/internal/idempotence"
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/publishmq"
+	"github.com/hookdeck/outpost/internal/publishrate"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// maxIdempotencyKeyLength caps the length of a publish idempotency key, as
+// in the REST API.
+const maxIdempotencyKeyLength = 255
+
+// retryAfterMetadata tells a rate limited caller how many seconds to wait
+// before publishing again, like the REST API's Retry-After header.
+const retryAfterMetadata = "retry-after"
+
+func (s *Server) Publish(ctx context.Context, req *outpostv1.PublishRequest) (*outpostv1.PublishResponse, error) {
+	if err := requireID("tenant_id", req.GetTenantId()); err != nil {
+		return nil, err
+	}
+	data := req.GetData()
+	if !json.Valid(data) || len(data) == 0 || data[0] != '{' {
+		return nil, invalidArgument(errors.New("data must be a valid JSON object"))
+	}
+	event := toEvent(req)
+
+	key, duplicate, err := s.reserveIdempotencyKey(ctx, req, &event)
+	if err != nil || duplicate != nil {
+		return duplicate, err
+	}
+	if err := s.checkRateLimit(ctx, event.TenantID); err != nil {
+		return nil, err
+	}
+	if err := s.checkEventQuota(ctx, event.TenantID); err != nil {
+		return nil, err
+	}
+	result, err := s.deps.EventHandler.Handle(ctx, &event)
+	if err != nil {
+		return nil, s.publishError(ctx, err)
+	}
+	if key != "" {
+		// The event ID stays reserved for the key either way, so a failure
+		// here only lets a retry past the publish queue's own deduplication
+		// window publish again.
+		if err := s.deps.PublishKeys.Complete(ctx, event.TenantID, key, event.ID, result.DestinationIDs); err != nil {
+			s.deps.Logger.Ctx(ctx).Error("failed to complete idempotency key",
+				zap.Error(err),
+				zap.String("tenant_id", event.TenantID),
+				zap.String("event_id", event.ID))
+		}
+	}
+	return &outpostv1.PublishResponse{
+		Id:             result.EventID,
+		Duplicate:      result.Duplicate,
+		DestinationIds: result.DestinationIDs,
+	}, nil
+}
+
+// toEvent returns the event a publish request publishes, with the defaults of
+// the REST API for the fields it leaves unset.
+func toEvent(req *outpostv1.PublishRequest) models.Event {
+	id := req.GetId()
+	if id == "" {
+		id = idgen.Event()
+	}
+	eventTime := time.Now()
+	if req.GetTime() != nil {
+		eventTime = req.GetTime().AsTime()
+	}
+	eligibleForRetry := true
+	if req.EligibleForRetry != nil {
+		eligibleForRetry = req.GetEligibleForRetry()
+	}
+	eventMetadata := req.GetMetadata()
+	if eventMetadata == nil {
+		// metadata is jsonb NOT NULL in the log store; a missing metadata
+		// field must become an empty map, never a nil one.
+		eventMetadata = map[string]string{}
+	}
+	return models.Event{
+		ID:               id,
+		TenantID:         req.GetTenantId(),
+		DestinationID:    req.GetDestinationId(),
+		Topic:            req.GetTopic(),
+		Source:           req.GetSource(),
+		EligibleForRetry: eligibleForRetry,
+		Time:             eventTime,
+		Metadata:         eventMetadata,
+		Data:             req.GetData(),
+	}
+}
+
+// reserveIdempotencyKey resolves the call's idempotency key, from the
+// idempotency-key metadata or the idempotency_key field, and publishes the
+// event under the ID reserved for it. When the key's event was already
+// accepted, it returns the original result. It returns the key, or "" when
+// the call has none or keys aren't enabled.
+func (s *Server) reserveIdempotencyKey(ctx context.Context, req *outpostv1.PublishRequest, event *models.Event) (string, *outpostv1.PublishResponse, error) {
+	key := req.GetIdempotencyKey()
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("idempotency-key"); len(values) > 0 && values[0] != "" {
+			if key != "" && key != values[0] {
+				return "", nil, invalidArgument(errors.New("idempotency-key metadata and idempotency_key must match"))
+			}
+			key = values[0]
+		}
+	}
+	if key == "" || s.deps.PublishKeys == nil {
+		return "", nil, nil
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return "", nil, invalidArgument(errors.New("idempotency_key must be at most 255 characters"))
+	}
+
+	entry, err := s.deps.PublishKeys.Reserve(ctx, event.TenantID, key, event.ID)
+	if err != nil {
+		return "", nil, s.internalError(ctx, err)
+	}
+	if req.GetId() != "" && entry.EventID != req.GetId() {
+		return "", nil, status.Error(codes.AlreadyExists, "idempotency key was already used to publish another event")
+	}
+	if entry.Published {
+		return "", &outpostv1.PublishResponse{
+			Id:             entry.EventID,
+			Duplicate:      true,
+			DestinationIds: entry.DestinationIDs,
+		}, nil
+	}
+	event.ID = entry.EventID
+	return key, nil, nil
+}
+
+// checkRateLimit takes a token from the tenant's publish rate limit. It
+// fails open: when the limit can't be checked the event is published.
+func (s *Server) checkRateLimit(ctx context.Context, tenantID string) error {
+	if s.deps.PublishRateLimiter == nil {
+		return nil
+	}
+	result, err := s.deps.PublishRateLimiter.Allow(ctx, tenantID)
+	if err != nil {
+		s.deps.Logger.Ctx(ctx).Error("failed to check publish rate limit", zap.Error(err), zap.String("tenant_id", tenantID))
+		return nil
+	}
+	if result.Allowed {
+		return nil
+	}
+	_ = grpc.SetHeader(ctx, metadata.Pairs(retryAfterMetadata, publishrate.RetryAfterSeconds(result.RetryAfter)))
+	return status.Error(codes.ResourceExhausted, "publish rate limit exceeded")
+}
+
+// checkEventQuota counts the event against the tenant's per-minute event
+// quota. It fails open: when the count can't be read the event is published.
+func (s *Server) checkEventQuota(ctx context.Context, tenantID string) error {
+	if s.deps.EventRates == nil || s.cfg.MaxEventsPerMinutePerTenant <= 0 {
+		return nil
+	}
+	used, err := s.deps.EventRates.Incr(ctx, tenantID)
+	if err != nil {
+		s.deps.Logger.Ctx(ctx).Error("failed to count event against quota", zap.Error(err), zap.String("tenant_id", tenantID))
+		return nil
+	}
+	if used > s.cfg.MaxEventsPerMinutePerTenant {
+		return status.Error(codes.ResourceExhausted, "event quota exceeded")
+	}
+	return nil
+}
+
+// publishError returns the status of an error publishing an event.
+func (s *Server) publishError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, idempotence.ErrConflict):
+		return status.Error(codes.AlreadyExists, "event is already being published")
+	case errors.Is(err, publishmq.ErrRequiredTopic):
+		return invalidArgument(errors.New("topic is required"))
+	case errors.Is(err, publishmq.ErrInvalidTopic):
+		return invalidArgument(errors.New("topic is invalid"))
+	case errors.Is(err, publishmq.ErrRetiredTopic):
+		return invalidArgument(errors.New("topic is retired"))
+	case errors.Is(err, publishmq.ErrInvalidSource):
+		return invalidArgument(errors.New("source is invalid"))
+	case errors.Is(err, publishmq.ErrEventRejected):
+		return invalidArgument(err)
+	case errors.Is(err, publishmq.ErrValidationFailed):
+		return status.Error(codes.Unavailable, "event validation unavailable")
+	default:
+		return s.internalError(ctx, err)
+	}
+}
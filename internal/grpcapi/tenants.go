@@ -0,0 +1,100 @@
+package grpcapi
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/grpcapi/outpostv1"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpsertTenant creates a tenant, or replaces the metadata of an existing one.
+// The sandbox flag is only changed when set.
+func (s *Server) UpsertTenant(ctx context.Context, req *outpostv1.UpsertTenantRequest) (*outpostv1.Tenant, error) {
+	if err := requireID("tenant_id", req.GetTenantId()); err != nil {
+		return nil, err
+	}
+	existing, err := s.deps.TenantStore.RetrieveTenant(ctx, req.GetTenantId())
+	if err != nil && !errors.Is(err, tenantstore.ErrTenantDeleted) {
+		return nil, s.internalError(ctx, err)
+	}
+
+	if existing != nil {
+		existing.Metadata = req.GetMetadata()
+		if req.Sandbox != nil {
+			existing.Sandbox = req.GetSandbox()
+		}
+		existing.UpdatedAt = time.Now()
+		if err := s.deps.TenantStore.UpsertTenant(ctx, *existing); err != nil {
+			return nil, s.internalError(ctx, err)
+		}
+		s.deps.Logger.Ctx(ctx).Audit("tenant updated",
+			zap.String("tenant_id", existing.ID),
+			zap.Bool("sandbox", existing.Sandbox),
+			zap.Strings("destination_types", existing.DestinationTypes),
+			zap.Any("publish_rate_limit", existing.PublishRateLimit),
+			zap.Strings("portal_allowed_ips", existing.PortalAllowedIPs),
+			zap.Bool("verify_webhook_endpoints", existing.VerifyWebhookEndpoints),
+		)
+		return tenantToProto(existing), nil
+	}
+
+	now := time.Now()
+	tenant := &models.Tenant{
+		ID:        req.GetTenantId(),
+		Topics:    []string{},
+		Metadata:  req.GetMetadata(),
+		Sandbox:   req.GetSandbox(),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := s.deps.TenantStore.UpsertTenant(ctx, *tenant); err != nil {
+		return nil, s.internalError(ctx, err)
+	}
+	s.deps.Telemetry.TenantCreated(ctx)
+	s.deps.Logger.Ctx(ctx).Audit("tenant created",
+		zap.String("tenant_id", tenant.ID),
+		zap.Bool("sandbox", tenant.Sandbox),
+		zap.Strings("destination_types", tenant.DestinationTypes),
+		zap.Any("publish_rate_limit", tenant.PublishRateLimit),
+		zap.Strings("portal_allowed_ips", tenant.PortalAllowedIPs),
+		zap.Bool("verify_webhook_endpoints", tenant.VerifyWebhookEndpoints),
+	)
+	return tenantToProto(tenant), nil
+}
+
+func (s *Server) GetTenant(ctx context.Context, req *outpostv1.GetTenantRequest) (*outpostv1.Tenant, error) {
+	if err := requireID("tenant_id", req.GetTenantId()); err != nil {
+		return nil, err
+	}
+	tenant, err := s.tenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+	return tenantToProto(tenant), nil
+}
+
+func (s *Server) DeleteTenant(ctx context.Context, req *outpostv1.DeleteTenantRequest) (*outpostv1.DeleteTenantResponse, error) {
+	if err := requireID("tenant_id", req.GetTenantId()); err != nil {
+		return nil, err
+	}
+	tenant, err := s.tenant(ctx, req.GetTenantId())
+	if err != nil {
+		return nil, err
+	}
+	if err := s.deps.TenantStore.DeleteTenant(ctx, tenant.ID); err != nil {
+		if errors.Is(err, tenantstore.ErrTenantNotFound) {
+			return nil, status.Error(codes.NotFound, "tenant not found")
+		}
+		return nil, s.internalError(ctx, err)
+	}
+	s.deps.Logger.Ctx(ctx).Audit("tenant deleted",
+		zap.String("tenant_id", tenant.ID),
+	)
+	return &outpostv1.DeleteTenantResponse{}, nil
+}
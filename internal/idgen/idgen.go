@@ -1,7 +1,12 @@
 package idgen
 
 import (
+	"crypto/rand"
+	"encoding/binary"
 	"fmt"
+	"math/big"
+	"sync"
+	"time"
 
 	"github.com/google/uuid"
 	gonanoid "github.com/matoous/go-nanoid/v2"
@@ -69,8 +74,12 @@ func newIDGenerator(idType string) (idGenerator, error) {
 		return &uuidv7Generator{}, nil
 	case "nanoid":
 		return &nanoidGenerator{}, nil
+	case "ulid":
+		return &ulidGenerator{}, nil
+	case "ksuid":
+		return &ksuidGenerator{}, nil
 	default:
-		return nil, fmt.Errorf("invalid id type: %s (must be one of: uuidv4, uuidv7, nanoid)", idType)
+		return nil, fmt.Errorf("invalid id type: %s (must be one of: uuidv4, uuidv7, nanoid, ulid, ksuid)", idType)
 	}
 }
 
@@ -105,6 +114,93 @@ func (g *nanoidGenerator) generate() string {
 	return id
 }
 
+// ulidGenerator generates ULIDs: a 48-bit millisecond timestamp followed by
+// 80 random bits, encoded as 26 characters of Crockford base32. IDs generated
+// within the same millisecond increment the random part of the previous one,
+// so they sort in generation order.
+type ulidGenerator struct {
+	mu         sync.Mutex
+	lastMillis uint64
+	lastRandom [10]byte
+}
+
+func (g *ulidGenerator) generate() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	millis := uint64(time.Now().UnixMilli())
+	if millis <= g.lastMillis && incrementBytes(g.lastRandom[:]) {
+		// Same millisecond, or the clock went back: stay on the last
+		// timestamp so the ID still sorts after the previous one.
+		millis = g.lastMillis
+	} else {
+		if millis <= g.lastMillis {
+			// The random part overflowed; borrow the next millisecond.
+			millis = g.lastMillis + 1
+		}
+		rand.Read(g.lastRandom[:])
+	}
+	g.lastMillis = millis
+
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], millis<<16)
+	copy(b[6:], g.lastRandom[:])
+	return encodeCrockford(b)
+}
+
+// incrementBytes adds one to b as a big-endian number and reports false when
+// it overflows.
+func incrementBytes(b []byte) bool {
+	for i := len(b) - 1; i >= 0; i-- {
+		b[i]++
+		if b[i] != 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func encodeCrockford(b [16]byte) string {
+	const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	hi := binary.BigEndian.Uint64(b[:8])
+	lo := binary.BigEndian.Uint64(b[8:])
+	var out [26]byte
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = alphabet[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
+
+// ksuidEpoch is the KSUID timestamp origin, 2014-05-13T16:53:20Z.
+const ksuidEpoch = 1400000000
+
+// ksuidGenerator generates KSUIDs: a 32-bit timestamp in seconds since
+// ksuidEpoch followed by 128 random bits, encoded as 27 base62 characters.
+// KSUIDs sort by the second they were generated in; order within a second is
+// random.
+type ksuidGenerator struct{}
+
+func (g *ksuidGenerator) generate() string {
+	const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	const length = 27
+
+	var b [20]byte
+	binary.BigEndian.PutUint32(b[:4], uint32(time.Now().Unix()-ksuidEpoch))
+	rand.Read(b[4:])
+
+	n := new(big.Int).SetBytes(b[:])
+	base := big.NewInt(int64(len(alphabet)))
+	digit := new(big.Int)
+	out := make([]byte, length)
+	for i := length - 1; i >= 0; i-- {
+		n.DivMod(n, base, digit)
+		out[i] = alphabet[digit.Int64()]
+	}
+	return string(out)
+}
+
 type IDGenConfig struct {
 	Type              string
 	EventPrefix       string
@@ -3,6 +3,7 @@ package idgen
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -38,6 +39,18 @@ func TestConfigure(t *testing.T) {
 			wantErr:     false,
 			description: "should accept nanoid type",
 		},
+		{
+			name:        "valid ulid type",
+			idType:      "ulid",
+			wantErr:     false,
+			description: "should accept ulid type",
+		},
+		{
+			name:        "valid ksuid type",
+			idType:      "ksuid",
+			wantErr:     false,
+			description: "should accept ksuid type",
+		},
 		{
 			name:        "invalid type",
 			idType:      "invalid",
@@ -112,6 +125,38 @@ func TestEvent_Generate(t *testing.T) {
 				}
 			},
 		},
+		{
+			name:   "ulid generates valid ID",
+			idType: "ulid",
+			prefix: "",
+			validate: func(t *testing.T, id string) {
+				if len(id) != 26 {
+					t.Errorf("ULID should be 26 characters, got %d: %s", len(id), id)
+				}
+				const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+				for _, c := range id {
+					if !strings.ContainsRune(alphabet, c) {
+						t.Errorf("ULID contains invalid character: %c", c)
+					}
+				}
+			},
+		},
+		{
+			name:   "ksuid generates valid ID",
+			idType: "ksuid",
+			prefix: "",
+			validate: func(t *testing.T, id string) {
+				if len(id) != 27 {
+					t.Errorf("KSUID should be 27 characters, got %d: %s", len(id), id)
+				}
+				const alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+				for _, c := range id {
+					if !strings.ContainsRune(alphabet, c) {
+						t.Errorf("KSUID contains invalid character: %c", c)
+					}
+				}
+			},
+		},
 		{
 			name:   "uuidv4 with prefix",
 			idType: "uuidv4",
@@ -168,6 +213,53 @@ func TestEvent_Uniqueness(t *testing.T) {
 	}
 }
 
+func TestEvent_SortsByTime(t *testing.T) {
+	t.Cleanup(func() { Configure(IDGenConfig{}) })
+
+	for _, idType := range []string{"uuidv7", "ulid"} {
+		t.Run(idType, func(t *testing.T) {
+			if err := Configure(IDGenConfig{Type: idType, EventPrefix: "evt_"}); err != nil {
+				t.Fatalf("Configure() error = %v", err)
+			}
+
+			prev := Event()
+			for i := 0; i < 1000; i++ {
+				id := Event()
+				if id <= prev {
+					t.Fatalf("IDs out of order: %s generated after %s", id, prev)
+				}
+				prev = id
+			}
+		})
+	}
+}
+
+func TestULID_Timestamp(t *testing.T) {
+	g := &ulidGenerator{}
+	before := time.Now().UnixMilli()
+	id := g.generate()
+
+	// The first 10 characters encode the millisecond timestamp.
+	const alphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+	var millis int64
+	for _, c := range id[:10] {
+		millis = millis<<5 | int64(strings.IndexRune(alphabet, c))
+	}
+	if millis < before || millis > time.Now().UnixMilli() {
+		t.Errorf("ULID timestamp %d outside generation window", millis)
+	}
+}
+
+func TestKSUID_SortsAcrossSeconds(t *testing.T) {
+	g := &ksuidGenerator{}
+	a := g.generate()
+	time.Sleep(1100 * time.Millisecond)
+	b := g.generate()
+	if b <= a {
+		t.Errorf("KSUID from a later second should sort after: %s <= %s", b, a)
+	}
+}
+
 func TestEvent(t *testing.T) {
 	t.Run("generates UUID v4 by default", func(t *testing.T) {
 		id := Event()
@@ -218,3 +310,19 @@ func BenchmarkEvent_Nanoid(b *testing.B) {
 		Event()
 	}
 }
+
+func BenchmarkEvent_ULID(b *testing.B) {
+	Configure(IDGenConfig{Type: "ulid", EventPrefix: ""})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Event()
+	}
+}
+
+func BenchmarkEvent_KSUID(b *testing.B) {
+	Configure(IDGenConfig{Type: "ksuid", EventPrefix: ""})
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		Event()
+	}
+}
@@ -0,0 +1,114 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// hookdeckExport is a Hookdeck Event Gateway project as returned by the list
+// connections API. Hookdeck signs every delivery of a project with one secret
+// that is not part of the connections, so it is given separately as
+// signing_secret.
+type hookdeckExport struct {
+	SigningSecret string               `json:"signing_secret"`
+	Connections   []hookdeckConnection `json:"connections"`
+}
+
+type hookdeckConnection struct {
+	ID          string              `json:"id"`
+	Name        string              `json:"name"`
+	Source      hookdeckSource      `json:"source"`
+	Destination hookdeckDestination `json:"destination"`
+	Rules       []hookdeckRule      `json:"rules"`
+	DisabledAt  *time.Time          `json:"disabled_at"`
+}
+
+type hookdeckSource struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type hookdeckDestination struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// URL is where older API versions return the destination URL; newer
+	// ones nest it in config.
+	URL    string `json:"url"`
+	Config struct {
+		URL string `json:"url"`
+	} `json:"config"`
+}
+
+type hookdeckRule struct {
+	Type    string          `json:"type"`
+	Body    json.RawMessage `json:"body"`
+	Headers json.RawMessage `json:"headers"`
+	Query   json.RawMessage `json:"query"`
+	Path    json.RawMessage `json:"path"`
+}
+
+// parseHookdeck maps sources to tenants and connections to webhook
+// destinations subscribed to every topic. A filter rule on the body becomes
+// the destination filter on the event data; the other rules have no
+// per-destination equivalent.
+func parseHookdeck(data []byte) (*Result, error) {
+	var export hookdeckExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("invalid hookdeck export: %w", err)
+	}
+	if export.SigningSecret == "" {
+		return nil, fmt.Errorf("invalid hookdeck export: signing_secret is required")
+	}
+
+	result := &Result{Tenants: []Tenant{}}
+	tenants := make(map[string]int)
+	for _, connection := range export.Connections {
+		ref := fmt.Sprintf("connection %s", firstNonEmpty(connection.Name, connection.ID))
+		tenantID := firstNonEmpty(connection.Source.Name, connection.Source.ID)
+		if tenantID == "" {
+			result.warnf("skipped %s without a source", ref)
+			continue
+		}
+		url := firstNonEmpty(connection.Destination.Config.URL, connection.Destination.URL)
+		if url == "" {
+			result.warnf("skipped %s: destination %s has no URL", ref, connection.Destination.Name)
+			continue
+		}
+
+		destination := Destination{
+			ID:          connection.ID,
+			Type:        "webhook",
+			Topics:      []string{"*"},
+			Config:      map[string]string{"url": url},
+			Credentials: map[string]string{"secret": export.SigningSecret},
+			Metadata:    withEntry(nil, "name", connection.Name),
+			DisabledAt:  connection.DisabledAt,
+		}
+		for _, rule := range connection.Rules {
+			if rule.Type != "filter" {
+				result.warnf("%s: %s rules are not supported and were dropped", ref, rule.Type)
+				continue
+			}
+			if len(rule.Headers) > 0 || len(rule.Query) > 0 || len(rule.Path) > 0 {
+				result.warnf("%s: filters on headers, query or path are not supported and were dropped", ref)
+			}
+			if len(rule.Body) > 0 && string(rule.Body) != "null" {
+				var body any
+				if err := json.Unmarshal(rule.Body, &body); err != nil {
+					return nil, fmt.Errorf("invalid hookdeck export: %s: filter body: %w", ref, err)
+				}
+				destination.Filter = map[string]any{"data": body}
+			}
+		}
+
+		i, ok := tenants[tenantID]
+		if !ok {
+			i = len(result.Tenants)
+			tenants[tenantID] = i
+			result.Tenants = append(result.Tenants, Tenant{ID: tenantID})
+		}
+		result.Tenants[i].Destinations = append(result.Tenants[i].Destinations, destination)
+	}
+	return result, nil
+}
@@ -0,0 +1,94 @@
+// Package importer converts webhook configuration exported from other webhook
+// services into Outpost tenants and destinations, so that a migration can be
+// done with one import rather than scripted API calls.
+//
+// Each source has its own export format (see svix.go and hookdeck.go). Signing
+// secrets are carried over unchanged so consumers keep verifying with the
+// secret they already have; settings with no Outpost equivalent are dropped
+// and reported as warnings.
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Source identifies the service an export was taken from.
+type Source string
+
+const (
+	SourceSvix     Source = "svix"
+	SourceHookdeck Source = "hookdeck"
+)
+
+// Sources lists the supported sources.
+var Sources = []Source{SourceSvix, SourceHookdeck}
+
+var ErrUnknownSource = errors.New("unknown import source")
+
+// Result is an export converted to Outpost resources.
+type Result struct {
+	Tenants []Tenant `json:"tenants"`
+	// Warnings describe settings in the export that were not imported.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Tenant is a tenant and the destinations to create for it.
+type Tenant struct {
+	ID           string            `json:"id"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Destinations []Destination     `json:"destinations"`
+}
+
+// Destination mirrors the create destination request.
+type Destination struct {
+	ID          string            `json:"id,omitempty"`
+	Type        string            `json:"type"`
+	Topics      []string          `json:"topics"`
+	Filter      map[string]any    `json:"filter,omitempty"`
+	Config      map[string]string `json:"config"`
+	Credentials map[string]string `json:"credentials,omitempty"`
+	Metadata    map[string]string `json:"metadata,omitempty"`
+	DisabledAt  *time.Time        `json:"disabled_at,omitempty"`
+}
+
+// Parse converts an export from source.
+func Parse(source Source, data []byte) (*Result, error) {
+	switch source {
+	case SourceSvix:
+		return parseSvix(data, time.Now())
+	case SourceHookdeck:
+		return parseHookdeck(data)
+	default:
+		return nil, fmt.Errorf("%w %q, must be one of %v", ErrUnknownSource, source, Sources)
+	}
+}
+
+// warnf records a warning on the result.
+func (r *Result) warnf(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// withEntry returns m with key set to value, allocating m if needed. Empty
+// values are skipped.
+func withEntry(m map[string]string, key, value string) map[string]string {
+	if value == "" {
+		return m
+	}
+	if m == nil {
+		m = make(map[string]string)
+	}
+	m[key] = value
+	return m
+}
@@ -0,0 +1,148 @@
+package importer_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/importer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse_Svix(t *testing.T) {
+	t.Parallel()
+
+	export := `{
+		"applications": [
+			{
+				"id": "app_2a",
+				"uid": "acme",
+				"name": "Acme",
+				"metadata": {"plan": "pro"},
+				"endpoints": [
+					{
+						"id": "ep_1",
+						"uid": "acme-orders",
+						"url": "https://acme.example.com/hooks",
+						"description": "Orders",
+						"filterTypes": ["order.created", "order.paid"],
+						"secret": "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw"
+					},
+					{
+						"id": "ep_2",
+						"url": "https://acme.example.com/all",
+						"disabled": true,
+						"channels": ["eu"],
+						"rateLimit": 10
+					}
+				]
+			},
+			{"id": "app_3b", "endpoints": []}
+		]
+	}`
+
+	result, err := importer.Parse(importer.SourceSvix, []byte(export))
+	require.NoError(t, err)
+	require.Len(t, result.Tenants, 2)
+
+	acme := result.Tenants[0]
+	assert.Equal(t, "acme", acme.ID)
+	assert.Equal(t, map[string]string{"plan": "pro", "name": "Acme"}, acme.Metadata)
+	require.Len(t, acme.Destinations, 2)
+
+	orders := acme.Destinations[0]
+	assert.Equal(t, "acme-orders", orders.ID)
+	assert.Equal(t, "webhook", orders.Type)
+	assert.Equal(t, []string{"order.created", "order.paid"}, orders.Topics)
+	assert.Equal(t, "https://acme.example.com/hooks", orders.Config["url"])
+	assert.Equal(t, "whsec_MfKQ9r8GKYqrTwjUPD8ILPZIo2LaLaSw", orders.Credentials["secret"])
+	assert.Equal(t, "Orders", orders.Metadata["description"])
+	assert.Nil(t, orders.DisabledAt)
+
+	all := acme.Destinations[1]
+	assert.Equal(t, "ep_2", all.ID)
+	assert.Equal(t, []string{"*"}, all.Topics)
+	assert.Nil(t, all.Credentials)
+	assert.NotNil(t, all.DisabledAt)
+
+	assert.Equal(t, "app_3b", result.Tenants[1].ID)
+	assert.Empty(t, result.Tenants[1].Destinations)
+
+	assert.Equal(t, []string{
+		"endpoint ep_2 of application acme has no secret, a new one will be generated",
+		"endpoint ep_2 of application acme: channels are not supported and were dropped",
+		"endpoint ep_2 of application acme: rate limits are not supported and were dropped",
+	}, result.Warnings)
+}
+
+func TestParse_Hookdeck(t *testing.T) {
+	t.Parallel()
+
+	export := `{
+		"signing_secret": "hd_secret",
+		"connections": [
+			{
+				"id": "web_1",
+				"name": "shop-orders",
+				"source": {"id": "src_1", "name": "shop"},
+				"destination": {"id": "des_1", "name": "orders", "config": {"url": "https://orders.example.com"}},
+				"rules": [
+					{"type": "filter", "body": {"type": "order.created"}, "headers": {"x-env": "prod"}},
+					{"type": "retry", "strategy": "linear"}
+				]
+			},
+			{
+				"id": "web_2",
+				"name": "shop-audit",
+				"source": {"id": "src_1", "name": "shop"},
+				"destination": {"id": "des_2", "name": "audit", "url": "https://audit.example.com"},
+				"disabled_at": "2024-05-01T00:00:00Z"
+			},
+			{
+				"id": "web_3",
+				"name": "billing-cli",
+				"source": {"id": "src_2", "name": "billing"},
+				"destination": {"id": "des_3", "name": "cli"}
+			}
+		]
+	}`
+
+	result, err := importer.Parse(importer.SourceHookdeck, []byte(export))
+	require.NoError(t, err)
+	require.Len(t, result.Tenants, 1)
+
+	shop := result.Tenants[0]
+	assert.Equal(t, "shop", shop.ID)
+	require.Len(t, shop.Destinations, 2)
+
+	orders := shop.Destinations[0]
+	assert.Equal(t, "web_1", orders.ID)
+	assert.Equal(t, []string{"*"}, orders.Topics)
+	assert.Equal(t, "https://orders.example.com", orders.Config["url"])
+	assert.Equal(t, "hd_secret", orders.Credentials["secret"])
+	assert.Equal(t, map[string]any{"data": map[string]any{"type": "order.created"}}, orders.Filter)
+
+	audit := shop.Destinations[1]
+	assert.Equal(t, "https://audit.example.com", audit.Config["url"])
+	require.NotNil(t, audit.DisabledAt)
+	assert.Equal(t, time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC), *audit.DisabledAt)
+
+	assert.Equal(t, []string{
+		"connection shop-orders: filters on headers, query or path are not supported and were dropped",
+		"connection shop-orders: retry rules are not supported and were dropped",
+		"skipped connection billing-cli: destination cli has no URL",
+	}, result.Warnings)
+}
+
+func TestParse_Errors(t *testing.T) {
+	t.Parallel()
+
+	_, err := importer.Parse("stripe", []byte(`{}`))
+	assert.ErrorIs(t, err, importer.ErrUnknownSource)
+
+	_, err = importer.Parse(importer.SourceSvix, []byte(`[`))
+	assert.Error(t, err)
+
+	_, err = importer.Parse(importer.SourceHookdeck, []byte(`{"connections": []}`))
+	assert.ErrorContains(t, err, "signing_secret is required")
+}
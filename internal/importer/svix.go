@@ -0,0 +1,93 @@
+package importer
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"time"
+)
+
+// svixExport is a Svix environment as assembled from its API: the list of
+// applications, each with its endpoints and each endpoint's signing secret
+// ("whsec_..." as returned by the get endpoint secret call).
+type svixExport struct {
+	Applications []svixApplication `json:"applications"`
+}
+
+type svixApplication struct {
+	ID        string            `json:"id"`
+	UID       string            `json:"uid"`
+	Name      string            `json:"name"`
+	Metadata  map[string]string `json:"metadata"`
+	Endpoints []svixEndpoint    `json:"endpoints"`
+}
+
+type svixEndpoint struct {
+	ID          string            `json:"id"`
+	UID         string            `json:"uid"`
+	URL         string            `json:"url"`
+	Description string            `json:"description"`
+	FilterTypes []string          `json:"filterTypes"`
+	Channels    []string          `json:"channels"`
+	Disabled    bool              `json:"disabled"`
+	RateLimit   *int              `json:"rateLimit"`
+	Metadata    map[string]string `json:"metadata"`
+	Secret      string            `json:"secret"`
+}
+
+// parseSvix maps applications to tenants and endpoints to webhook
+// destinations. Applications and endpoints keep their uid when they have one,
+// so the IDs consumers already know carry over. Svix secrets are in the
+// Standard Webhooks format, which Outpost verifies and signs with when
+// DESTINATIONS_WEBHOOK_MODE is "standard".
+func parseSvix(data []byte, now time.Time) (*Result, error) {
+	var export svixExport
+	if err := json.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("invalid svix export: %w", err)
+	}
+
+	result := &Result{Tenants: make([]Tenant, 0, len(export.Applications))}
+	for _, app := range export.Applications {
+		tenant := Tenant{
+			ID:           firstNonEmpty(app.UID, app.ID),
+			Metadata:     withEntry(maps.Clone(app.Metadata), "name", app.Name),
+			Destinations: make([]Destination, 0, len(app.Endpoints)),
+		}
+		if tenant.ID == "" {
+			result.warnf("skipped application %q without an id", app.Name)
+			continue
+		}
+		for _, endpoint := range app.Endpoints {
+			id := firstNonEmpty(endpoint.UID, endpoint.ID)
+			ref := fmt.Sprintf("endpoint %s of application %s", id, tenant.ID)
+			destination := Destination{
+				ID:       id,
+				Type:     "webhook",
+				Topics:   endpoint.FilterTypes,
+				Config:   map[string]string{"url": endpoint.URL},
+				Metadata: withEntry(maps.Clone(endpoint.Metadata), "description", endpoint.Description),
+			}
+			// An endpoint without event type filters receives every event.
+			if len(destination.Topics) == 0 {
+				destination.Topics = []string{"*"}
+			}
+			if endpoint.Secret != "" {
+				destination.Credentials = map[string]string{"secret": endpoint.Secret}
+			} else {
+				result.warnf("%s has no secret, a new one will be generated", ref)
+			}
+			if endpoint.Disabled {
+				destination.DisabledAt = &now
+			}
+			if len(endpoint.Channels) > 0 {
+				result.warnf("%s: channels are not supported and were dropped", ref)
+			}
+			if endpoint.RateLimit != nil {
+				result.warnf("%s: rate limits are not supported and were dropped", ref)
+			}
+			tenant.Destinations = append(tenant.Destinations, destination)
+		}
+		result.Tenants = append(result.Tenants, tenant)
+	}
+	return result, nil
+}
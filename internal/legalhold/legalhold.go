@@ -0,0 +1,222 @@
+// Package legalhold exempts delivery logs from retention pruning while they
+// are under a legal hold.
+//
+// A hold covers every event and attempt of a tenant, or a single event of a
+// tenant and its attempts. Holds are stored in a Redis hash shared by every
+// service, and stay in place until released or, when placed with an expiry,
+// until they expire. The log retention worker reads the active holds before
+// each prune.
+package legalhold
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/redis"
+)
+
+// MaxReasonLength caps the length of a hold's reason.
+const MaxReasonLength = 1000
+
+var (
+	ErrInvalidHold  = errors.New("validation failed: invalid legal hold")
+	ErrHoldNotFound = errors.New("legal hold not found")
+	// ErrHoldUnenforceable is returned when placing a hold on a deployment
+	// whose log store expires records on its own, e.g. with ClickHouse TTLs,
+	// so the hold would not keep them.
+	ErrHoldUnenforceable = errors.New("legal holds cannot be enforced by the log store")
+)
+
+// Hold exempts the records of a tenant, or of one of its events when EventID
+// is set, from retention pruning.
+type Hold struct {
+	ID       string `json:"id"`
+	TenantID string `json:"tenant_id"`
+	EventID  string `json:"event_id,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	// ExpiresAt releases the hold automatically. Nil holds until released.
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// Active reports whether the hold is in effect at now.
+func (h *Hold) Active(now time.Time) bool {
+	return h.ExpiresAt == nil || now.Before(*h.ExpiresAt)
+}
+
+// Store places, lists and releases holds.
+type Store struct {
+	redisClient  redis.Cmdable
+	deploymentID string
+	clock        clock.Clock
+	// unenforceable explains why holds cannot be placed, when they can't.
+	unenforceable string
+}
+
+type Option func(*Store)
+
+func WithDeploymentID(deploymentID string) Option {
+	return func(s *Store) {
+		s.deploymentID = deploymentID
+	}
+}
+
+func WithClock(c clock.Clock) Option {
+	return func(s *Store) {
+		s.clock = c
+	}
+}
+
+// WithUnenforceable makes Place fail with ErrHoldUnenforceable and reason,
+// for log stores that delete expired records regardless of holds. Holds
+// already placed can still be listed and released.
+func WithUnenforceable(reason string) Option {
+	return func(s *Store) {
+		s.unenforceable = reason
+	}
+}
+
+// NewStore returns a store of the holds in Redis.
+func NewStore(redisClient redis.Cmdable, opts ...Option) *Store {
+	s := &Store{
+		redisClient: redisClient,
+		clock:       clock.New(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Place records a hold on the tenant's records, or on the event's when
+// eventID is set. A nil expiresAt holds until released.
+func (s *Store) Place(ctx context.Context, tenantID, eventID, reason string, expiresAt *time.Time) (*Hold, error) {
+	if s.unenforceable != "" {
+		return nil, fmt.Errorf("%w: %s", ErrHoldUnenforceable, s.unenforceable)
+	}
+	now := s.clock.Now().UTC()
+	if len(reason) > MaxReasonLength {
+		return nil, fmt.Errorf("%w: reason must not exceed %d characters", ErrInvalidHold, MaxReasonLength)
+	}
+	if expiresAt != nil && !expiresAt.After(now) {
+		return nil, fmt.Errorf("%w: expires_at must be in the future", ErrInvalidHold)
+	}
+	hold := &Hold{
+		ID:        idgen.String(),
+		TenantID:  tenantID,
+		EventID:   eventID,
+		Reason:    reason,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+	}
+	data, err := json.Marshal(hold)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.redisClient.HSet(ctx, s.key(), hold.ID, data).Err(); err != nil {
+		return nil, fmt.Errorf("failed to place legal hold: %w", err)
+	}
+	return hold, nil
+}
+
+// List returns the active holds of a tenant, or of every tenant when tenantID
+// is empty, oldest first.
+func (s *Store) List(ctx context.Context, tenantID string) ([]Hold, error) {
+	holds, err := s.active(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if tenantID == "" {
+		return holds, nil
+	}
+	return slices.DeleteFunc(holds, func(h Hold) bool {
+		return h.TenantID != tenantID
+	}), nil
+}
+
+// Release removes a hold of the tenant and returns it. It returns
+// ErrHoldNotFound when the tenant has no such active hold.
+func (s *Store) Release(ctx context.Context, tenantID, holdID string) (*Hold, error) {
+	data, err := s.redisClient.HGet(ctx, s.key(), holdID).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, ErrHoldNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read legal hold: %w", err)
+	}
+	var hold Hold
+	if err := json.Unmarshal([]byte(data), &hold); err != nil {
+		return nil, fmt.Errorf("failed to decode legal hold: %w", err)
+	}
+	if hold.TenantID != tenantID || !hold.Active(s.clock.Now()) {
+		return nil, ErrHoldNotFound
+	}
+	if err := s.redisClient.HDel(ctx, s.key(), holdID).Err(); err != nil {
+		return nil, fmt.Errorf("failed to release legal hold: %w", err)
+	}
+	return &hold, nil
+}
+
+// Exempt returns req with the records under an active hold exempted from
+// pruning.
+func (s *Store) Exempt(ctx context.Context, req logstore.PruneRequest) (logstore.PruneRequest, error) {
+	holds, err := s.active(ctx)
+	if err != nil {
+		return req, err
+	}
+	for _, hold := range holds {
+		if hold.EventID != "" {
+			req.HeldEventIDs = append(req.HeldEventIDs, hold.EventID)
+		} else {
+			req.HeldTenantIDs = append(req.HeldTenantIDs, hold.TenantID)
+		}
+	}
+	return req, nil
+}
+
+// active returns the holds in effect, oldest first, and deletes the expired
+// ones.
+func (s *Store) active(ctx context.Context) ([]Hold, error) {
+	all, err := s.redisClient.HGetAll(ctx, s.key()).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list legal holds: %w", err)
+	}
+	now := s.clock.Now()
+	holds := make([]Hold, 0, len(all))
+	var expired []string
+	for id, data := range all {
+		var hold Hold
+		if err := json.Unmarshal([]byte(data), &hold); err != nil {
+			return nil, fmt.Errorf("failed to decode legal hold %s: %w", id, err)
+		}
+		if !hold.Active(now) {
+			expired = append(expired, id)
+			continue
+		}
+		holds = append(holds, hold)
+	}
+	if len(expired) > 0 {
+		if err := s.redisClient.HDel(ctx, s.key(), expired...).Err(); err != nil {
+			return nil, fmt.Errorf("failed to delete expired legal holds: %w", err)
+		}
+	}
+	slices.SortFunc(holds, func(a, b Hold) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.ID, b.ID))
+	})
+	return holds, nil
+}
+
+func (s *Store) key() string {
+	if s.deploymentID == "" {
+		return "legal_holds"
+	}
+	return s.deploymentID + ":legal_holds"
+}
@@ -0,0 +1,138 @@
+package legalhold_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/legalhold"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	newStore := func(t *testing.T) (*legalhold.Store, *clock.Fake) {
+		t.Helper()
+		fake := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+		store := legalhold.NewStore(testutil.CreateTestRedisClient(t),
+			legalhold.WithDeploymentID("dp_test"),
+			legalhold.WithClock(fake),
+		)
+		return store, fake
+	}
+
+	t.Run("places and lists holds", func(t *testing.T) {
+		t.Parallel()
+		store, fake := newStore(t)
+
+		tenantHold, err := store.Place(t.Context(), "t1", "", "litigation", nil)
+		require.NoError(t, err)
+		assert.NotEmpty(t, tenantHold.ID)
+		assert.Equal(t, fake.Now(), tenantHold.CreatedAt)
+
+		fake.Advance(time.Second)
+		eventHold, err := store.Place(t.Context(), "t1", "evt_1", "", nil)
+		require.NoError(t, err)
+		_, err = store.Place(t.Context(), "t2", "", "", nil)
+		require.NoError(t, err)
+
+		holds, err := store.List(t.Context(), "t1")
+		require.NoError(t, err)
+		require.Len(t, holds, 2)
+		assert.Equal(t, tenantHold.ID, holds[0].ID)
+		assert.Equal(t, "litigation", holds[0].Reason)
+		assert.Equal(t, eventHold.ID, holds[1].ID)
+		assert.Equal(t, "evt_1", holds[1].EventID)
+
+		all, err := store.List(t.Context(), "")
+		require.NoError(t, err)
+		assert.Len(t, all, 3)
+	})
+
+	t.Run("validates holds", func(t *testing.T) {
+		t.Parallel()
+		store, fake := newStore(t)
+
+		past := fake.Now().Add(-time.Hour)
+		_, err := store.Place(t.Context(), "t1", "", "", &past)
+		assert.ErrorIs(t, err, legalhold.ErrInvalidHold)
+
+		_, err = store.Place(t.Context(), "t1", "", strings.Repeat("x", legalhold.MaxReasonLength+1), nil)
+		assert.ErrorIs(t, err, legalhold.ErrInvalidHold)
+	})
+
+	t.Run("refuses holds the log store cannot enforce", func(t *testing.T) {
+		t.Parallel()
+		store := legalhold.NewStore(testutil.CreateTestRedisClient(t), legalhold.WithUnenforceable("TTLs"))
+
+		_, err := store.Place(t.Context(), "t1", "", "", nil)
+		assert.ErrorIs(t, err, legalhold.ErrHoldUnenforceable)
+
+		holds, err := store.List(t.Context(), "t1")
+		require.NoError(t, err)
+		assert.Empty(t, holds)
+	})
+
+	t.Run("releases holds of the tenant only", func(t *testing.T) {
+		t.Parallel()
+		store, _ := newStore(t)
+
+		hold, err := store.Place(t.Context(), "t1", "", "", nil)
+		require.NoError(t, err)
+
+		_, err = store.Release(t.Context(), "t2", hold.ID)
+		assert.ErrorIs(t, err, legalhold.ErrHoldNotFound)
+
+		released, err := store.Release(t.Context(), "t1", hold.ID)
+		require.NoError(t, err)
+		assert.Equal(t, hold.ID, released.ID)
+
+		_, err = store.Release(t.Context(), "t1", hold.ID)
+		assert.ErrorIs(t, err, legalhold.ErrHoldNotFound)
+		holds, err := store.List(t.Context(), "t1")
+		require.NoError(t, err)
+		assert.Empty(t, holds)
+	})
+
+	t.Run("expires holds", func(t *testing.T) {
+		t.Parallel()
+		store, fake := newStore(t)
+
+		expiresAt := fake.Now().Add(time.Hour)
+		hold, err := store.Place(t.Context(), "t1", "", "", &expiresAt)
+		require.NoError(t, err)
+
+		holds, err := store.List(t.Context(), "t1")
+		require.NoError(t, err)
+		assert.Len(t, holds, 1)
+
+		fake.Advance(time.Hour)
+		holds, err = store.List(t.Context(), "t1")
+		require.NoError(t, err)
+		assert.Empty(t, holds)
+		_, err = store.Release(t.Context(), "t1", hold.ID)
+		assert.ErrorIs(t, err, legalhold.ErrHoldNotFound)
+	})
+
+	t.Run("exempts held records from pruning", func(t *testing.T) {
+		t.Parallel()
+		store, _ := newStore(t)
+
+		_, err := store.Place(t.Context(), "t1", "", "", nil)
+		require.NoError(t, err)
+		_, err = store.Place(t.Context(), "t2", "evt_1", "", nil)
+		require.NoError(t, err)
+
+		cutoff := time.Now()
+		req, err := store.Exempt(t.Context(), logstore.PruneRequest{SuccessBefore: &cutoff})
+		require.NoError(t, err)
+		assert.Equal(t, &cutoff, req.SuccessBefore)
+		assert.Equal(t, []string{"t1"}, req.HeldTenantIDs)
+		assert.Equal(t, []string{"evt_1"}, req.HeldEventIDs)
+	})
+}
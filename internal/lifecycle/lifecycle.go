@@ -0,0 +1,387 @@
+// Package lifecycle notifies producers about the events they publish.
+//
+// A notification is sent when an event is accepted and fanned out to its
+// matched destinations, and when each of those deliveries reaches a terminal
+// status: success, or failure with no retry left. A success the consumer
+// accepted with 202 is terminal only once it is acknowledged. Notifications
+// are batched per tenant and POSTed to the callback URL the tenant's producer
+// registered, or to the deployment's callback URL, signed the same way as the
+// HTTP sink for operator events, so producers get closure on an event without
+// polling the events API.
+//
+// Delivery to the callback is best-effort: a batch that still fails after
+// its retries is logged and dropped, and batches buffered in memory are lost
+// if the process exits without shutting down.
+package lifecycle
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/backoff"
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/deliveryack"
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/mikestefanello/batcher"
+	"go.uber.org/zap"
+)
+
+const (
+	TypeEventAccepted     = "event.accepted"
+	TypeDeliveryCompleted = "delivery.completed"
+)
+
+const signatureHeader = "X-Outpost-Signature"
+
+// sendAttempts is the number of times a batch is POSTed before it is dropped.
+const sendAttempts = 3
+
+// sendTimeout caps a single POST of a batch.
+const sendTimeout = 10 * time.Second
+
+// Notification is one lifecycle notification. Data is an AcceptedData or a
+// DeliveryData depending on Type.
+type Notification struct {
+	ID       string    `json:"id"`
+	Type     string    `json:"type"`
+	Time     time.Time `json:"time"`
+	TenantID string    `json:"tenant_id"`
+	EventID  string    `json:"event_id"`
+	Topic    string    `json:"topic"`
+	Data     any       `json:"data"`
+}
+
+// AcceptedData describes the fan-out of an accepted event.
+type AcceptedData struct {
+	DestinationIDs   []string `json:"destination_ids"`
+	DestinationCount int      `json:"destination_count"`
+}
+
+// DeliveryData describes the terminal attempt of a delivery.
+type DeliveryData struct {
+	DestinationID string `json:"destination_id"`
+	Status        string `json:"status"`
+	AttemptID     string `json:"attempt_id"`
+	AttemptNumber int    `json:"attempt_number"`
+	Code          string `json:"code,omitempty"`
+}
+
+// Batch is the body of a callback request.
+type Batch struct {
+	Notifications []Notification `json:"notifications"`
+}
+
+// Config configures a Notifier.
+type Config struct {
+	// CallbackURL receives the notifications of tenants without a callback
+	// of their own. Optional when TenantCallbacks is set.
+	CallbackURL string
+	// TenantCallbacks sends each tenant's notifications to the callback URL
+	// registered on the tenant.
+	TenantCallbacks bool
+	SigningSecret   string
+	BatchSize       int
+	BatchInterval   time.Duration
+	// RetryMaxLimit is the deployment's retry limit. A failed attempt past
+	// it (or past the destination's own limit) is the delivery's last.
+	RetryMaxLimit int
+}
+
+// Enabled reports whether any callback can receive notifications.
+func (c Config) Enabled() bool {
+	return c.CallbackURL != "" || c.TenantCallbacks
+}
+
+// TenantRetriever looks up the callback a tenant registered.
+type TenantRetriever interface {
+	RetrieveTenant(ctx context.Context, tenantID string) (*models.Tenant, error)
+}
+
+// Option configures a Notifier.
+type Option func(*Notifier)
+
+// WithClock sets the clock used to wait between send attempts.
+func WithClock(c clock.Clock) Option {
+	return func(n *Notifier) {
+		n.clock = c
+	}
+}
+
+// WithBackoff sets the delay between send attempts. Default: exponential
+// from one second.
+func WithBackoff(b backoff.Backoff) Option {
+	return func(n *Notifier) {
+		n.backoff = b
+	}
+}
+
+// Notifier batches notifications and sends them to their tenant's callback.
+type Notifier struct {
+	ctx             context.Context
+	logger          *logging.Logger
+	tenants         TenantRetriever
+	callbackURL     string
+	tenantCallbacks bool
+	signingSecret   string
+	retryMaxLimit   int
+	client          *http.Client
+	batcher         *batcher.Batcher[Notification]
+	clock           clock.Clock
+	backoff         backoff.Backoff
+	// inflight tracks batch sends so Shutdown can drain them.
+	inflight     sync.WaitGroup
+	shutdownOnce sync.Once
+}
+
+// New creates a Notifier. It returns an error when no callback can receive
+// notifications. tenants is only consulted when TenantCallbacks is set.
+func New(ctx context.Context, logger *logging.Logger, tenants TenantRetriever, cfg Config, opts ...Option) (*Notifier, error) {
+	if !cfg.Enabled() {
+		return nil, errors.New("lifecycle: callback URL or tenant callbacks are required")
+	}
+	if cfg.TenantCallbacks && tenants == nil {
+		return nil, errors.New("lifecycle: tenant callbacks require a tenant retriever")
+	}
+	n := &Notifier{
+		ctx:             ctx,
+		logger:          logger,
+		tenants:         tenants,
+		callbackURL:     cfg.CallbackURL,
+		tenantCallbacks: cfg.TenantCallbacks,
+		signingSecret:   cfg.SigningSecret,
+		retryMaxLimit:   cfg.RetryMaxLimit,
+		client:          &http.Client{Timeout: sendTimeout},
+		clock:           clock.New(),
+		backoff:         &backoff.ExponentialBackoff{Interval: time.Second, Base: 2},
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	interval := cfg.BatchInterval
+	if interval <= 0 {
+		interval = time.Millisecond
+	}
+	b, err := batcher.NewBatcher(batcher.Config[Notification]{
+		GroupCountThreshold: 2,
+		ItemCountThreshold:  cfg.BatchSize,
+		DelayThreshold:      interval,
+		NumGoroutines:       1,
+		Processor:           n.processBatch,
+	})
+	if err != nil {
+		return nil, err
+	}
+	n.batcher = b
+	return n, nil
+}
+
+// EventAccepted records that an event was accepted and fanned out to the
+// given destinations.
+func (n *Notifier) EventAccepted(event *models.Event, destinationIDs []string) {
+	n.batcher.Add("", Notification{
+		ID:       idgen.String(),
+		Type:     TypeEventAccepted,
+		Time:     n.clock.Now(),
+		TenantID: event.TenantID,
+		EventID:  event.ID,
+		Topic:    event.Topic,
+		Data: AcceptedData{
+			DestinationIDs:   destinationIDs,
+			DestinationCount: len(destinationIDs),
+		},
+	})
+}
+
+// AttemptLogged records a persisted delivery attempt. Only terminal attempts
+// produce a notification; the rest are ignored.
+func (n *Notifier) AttemptLogged(entry *models.LogEntry) {
+	if !n.Terminal(entry) {
+		return
+	}
+	n.batcher.Add("", Notification{
+		ID:       idgen.String(),
+		Type:     TypeDeliveryCompleted,
+		Time:     entry.Attempt.Time,
+		TenantID: entry.Event.TenantID,
+		EventID:  entry.Event.ID,
+		Topic:    entry.Event.Topic,
+		Data: DeliveryData{
+			DestinationID: entry.Attempt.DestinationID,
+			Status:        entry.Attempt.Status,
+			AttemptID:     entry.Attempt.ID,
+			AttemptNumber: entry.Attempt.AttemptNumber,
+			Code:          entry.Attempt.Code,
+		},
+	})
+}
+
+// DeliveryAcknowledged records that the consumer acknowledged a delivery it
+// accepted with 202, which completes it.
+func (n *Notifier) DeliveryAcknowledged(pending *deliveryack.Pending) {
+	n.batcher.Add("", Notification{
+		ID:       idgen.String(),
+		Type:     TypeDeliveryCompleted,
+		Time:     n.clock.Now(),
+		TenantID: pending.TenantID,
+		EventID:  pending.EventID,
+		Topic:    pending.Topic,
+		Data: DeliveryData{
+			DestinationID: pending.DestinationID,
+			Status:        models.AttemptStatusSuccess,
+			AttemptID:     pending.AttemptID,
+			AttemptNumber: pending.AttemptNumber,
+		},
+	})
+}
+
+// Terminal reports whether the attempt is the delivery's last: a success the
+// consumer does not still have to acknowledge, or a failure that will not be
+// retried automatically. It mirrors the retry decision of the delivery
+// worker, so a later manual retry may still follow.
+func (n *Notifier) Terminal(entry *models.LogEntry) bool {
+	switch entry.Attempt.Status {
+	case models.AttemptStatusSuccess:
+		return !entry.AwaitingAck
+	case models.AttemptStatusFailed:
+	default:
+		return false
+	}
+	if !entry.Event.EligibleForRetry {
+		return true
+	}
+	// A failed transformation is not retried.
+	if entry.Attempt.ResponseData["error"] == "transformation_failed" {
+		return true
+	}
+	retryLimit := n.retryMaxLimit
+	if entry.Destination != nil {
+		if limit, ok := entry.Destination.RetryPolicy.RetryLimit(); ok {
+			retryLimit = limit
+		}
+	}
+	return entry.Attempt.AttemptNumber > retryLimit
+}
+
+// Shutdown sends the buffered notifications and waits for in-flight batches.
+// Idempotent.
+func (n *Notifier) Shutdown() {
+	n.shutdownOnce.Do(func() {
+		n.batcher.Shutdown()
+		n.inflight.Wait()
+	})
+}
+
+// processBatch hands the batch to its own goroutine so a slow callback never
+// blocks the publish or log paths adding to the batcher. The batch is split
+// by tenant, since each tenant's notifications may go to its own callback.
+func (n *Notifier) processBatch(_ string, notifications []Notification) {
+	n.inflight.Go(func() {
+		var tenantIDs []string
+		byTenant := make(map[string][]Notification)
+		for _, notification := range notifications {
+			if _, ok := byTenant[notification.TenantID]; !ok {
+				tenantIDs = append(tenantIDs, notification.TenantID)
+			}
+			byTenant[notification.TenantID] = append(byTenant[notification.TenantID], notification)
+		}
+		for _, tenantID := range tenantIDs {
+			url := n.callbackFor(tenantID)
+			if url == "" {
+				continue
+			}
+			n.deliver(url, Batch{Notifications: byTenant[tenantID]})
+		}
+	})
+}
+
+// callbackFor returns the callback URL for the tenant's notifications, or ""
+// when they have nowhere to go.
+func (n *Notifier) callbackFor(tenantID string) string {
+	if !n.tenantCallbacks {
+		return n.callbackURL
+	}
+	tenant, err := n.tenants.RetrieveTenant(context.WithoutCancel(n.ctx), tenantID)
+	if err != nil {
+		if !errors.Is(err, tenantstore.ErrTenantNotFound) && !errors.Is(err, tenantstore.ErrTenantDeleted) {
+			n.logger.Ctx(n.ctx).Error("failed to retrieve tenant for lifecycle notifications",
+				zap.Error(err),
+				zap.String("tenant_id", tenantID))
+		}
+		return n.callbackURL
+	}
+	if tenant != nil && tenant.LifecycleCallback != nil && tenant.LifecycleCallback.URL != "" {
+		return tenant.LifecycleCallback.URL
+	}
+	return n.callbackURL
+}
+
+func (n *Notifier) deliver(url string, batch Batch) {
+	logger := n.logger.Ctx(n.ctx)
+	body, err := json.Marshal(batch)
+	if err != nil {
+		logger.Error("failed to marshal lifecycle notifications", zap.Error(err))
+		return
+	}
+	for retries := 0; ; retries++ {
+		err = n.send(url, body)
+		if err == nil {
+			return
+		}
+		if retries+1 == sendAttempts {
+			break
+		}
+		<-n.clock.After(n.backoff.Duration(retries))
+	}
+	logger.Error("failed to send lifecycle notifications",
+		zap.Error(err),
+		zap.String("tenant_id", batch.Notifications[0].TenantID),
+		zap.Int("notification_count", len(batch.Notifications)))
+}
+
+func (n *Notifier) send(url string, body []byte) error {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(n.ctx), sendTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("lifecycle: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if n.signingSecret != "" {
+		req.Header.Set(signatureHeader, "v0="+Sign(n.signingSecret, body))
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("lifecycle: failed to send notifications: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("lifecycle: callback returned status %d: %s", resp.StatusCode, string(snippet))
+	}
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of body under secret, as sent in
+// the X-Outpost-Signature header after the "v0=" prefix.
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,262 @@
+package lifecycle_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/backoff"
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/deliveryack"
+	"github.com/hookdeck/outpost/internal/lifecycle"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type callback struct {
+	mu         sync.Mutex
+	batches    []lifecycle.Batch
+	signatures []string
+	bodies     [][]byte
+}
+
+func newCallback(t *testing.T) (*callback, *httptest.Server) {
+	t.Helper()
+	cb := &callback{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		var batch lifecycle.Batch
+		require.NoError(t, json.Unmarshal(body, &batch))
+		cb.mu.Lock()
+		cb.batches = append(cb.batches, batch)
+		cb.signatures = append(cb.signatures, r.Header.Get("X-Outpost-Signature"))
+		cb.bodies = append(cb.bodies, body)
+		cb.mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+	return cb, server
+}
+
+func TestNew_RequiresCallbackURL(t *testing.T) {
+	t.Parallel()
+
+	_, err := lifecycle.New(context.Background(), testutil.CreateTestLogger(t), nil, lifecycle.Config{})
+	assert.Error(t, err)
+
+	_, err = lifecycle.New(context.Background(), testutil.CreateTestLogger(t), nil, lifecycle.Config{TenantCallbacks: true})
+	assert.Error(t, err, "tenant callbacks need a tenant retriever")
+}
+
+func TestNotifier(t *testing.T) {
+	t.Parallel()
+
+	cb, server := newCallback(t)
+	notifier, err := lifecycle.New(context.Background(), testutil.CreateTestLogger(t), nil, lifecycle.Config{
+		CallbackURL:   server.URL,
+		SigningSecret: "secret",
+		BatchSize:     100,
+		BatchInterval: time.Hour,
+		RetryMaxLimit: 2,
+	})
+	require.NoError(t, err)
+
+	event := testutil.EventFactory.AnyPointer(
+		testutil.EventFactory.WithID("e1"),
+		testutil.EventFactory.WithTenantID("t1"),
+		testutil.EventFactory.WithTopic("user.created"),
+		testutil.EventFactory.WithEligibleForRetry(true),
+	)
+	attempt := func(status string, number int) *models.LogEntry {
+		return &models.LogEntry{
+			Event: event,
+			Attempt: testutil.AttemptFactory.AnyPointer(
+				testutil.AttemptFactory.WithEventID("e1"),
+				testutil.AttemptFactory.WithDestinationID("d1"),
+				testutil.AttemptFactory.WithStatus(status),
+				testutil.AttemptFactory.WithAttemptNumber(number),
+			),
+		}
+	}
+
+	notifier.EventAccepted(event, []string{"d1", "d2"})
+	notifier.AttemptLogged(attempt(models.AttemptStatusFailed, 1))
+	notifier.AttemptLogged(attempt(models.AttemptStatusFailed, 3))
+	awaitingAck := attempt(models.AttemptStatusSuccess, 1)
+	awaitingAck.AwaitingAck = true
+	notifier.AttemptLogged(awaitingAck)
+	notifier.DeliveryAcknowledged(&deliveryack.Pending{
+		TenantID:      "t1",
+		EventID:       "e1",
+		DestinationID: "d2",
+		Topic:         "user.created",
+		AttemptID:     "a1",
+		AttemptNumber: 1,
+	})
+	notifier.Shutdown()
+
+	require.Len(t, cb.batches, 1, "notifications are sent in one batch")
+	notifications := cb.batches[0].Notifications
+	require.Len(t, notifications, 3, "the retried and unacknowledged attempts are not terminal")
+
+	assert.Equal(t, lifecycle.TypeEventAccepted, notifications[0].Type)
+	assert.Equal(t, "e1", notifications[0].EventID)
+	assert.Equal(t, "t1", notifications[0].TenantID)
+	assert.Equal(t, "user.created", notifications[0].Topic)
+	assert.Equal(t, map[string]any{
+		"destination_ids":   []any{"d1", "d2"},
+		"destination_count": float64(2),
+	}, notifications[0].Data)
+
+	assert.Equal(t, lifecycle.TypeDeliveryCompleted, notifications[1].Type)
+	data := notifications[1].Data.(map[string]any)
+	assert.Equal(t, "d1", data["destination_id"])
+	assert.Equal(t, models.AttemptStatusFailed, data["status"])
+	assert.Equal(t, float64(3), data["attempt_number"])
+
+	assert.Equal(t, lifecycle.TypeDeliveryCompleted, notifications[2].Type)
+	assert.Equal(t, "user.created", notifications[2].Topic)
+	data = notifications[2].Data.(map[string]any)
+	assert.Equal(t, "d2", data["destination_id"])
+	assert.Equal(t, models.AttemptStatusSuccess, data["status"])
+	assert.Equal(t, "a1", data["attempt_id"])
+
+	assert.Equal(t, "v0="+lifecycle.Sign("secret", cb.bodies[0]), cb.signatures[0])
+}
+
+func TestNotifier_Terminal(t *testing.T) {
+	t.Parallel()
+
+	notifier, err := lifecycle.New(context.Background(), testutil.CreateTestLogger(t), nil, lifecycle.Config{
+		CallbackURL:   "http://localhost",
+		BatchInterval: time.Hour,
+		RetryMaxLimit: 2,
+	})
+	require.NoError(t, err)
+	t.Cleanup(notifier.Shutdown)
+
+	tests := []struct {
+		name         string
+		status       string
+		number       int
+		eligible     bool
+		retryPolicy  *models.RetryPolicy
+		awaitingAck  bool
+		responseData map[string]any
+		want         bool
+	}{
+		{name: "success", status: models.AttemptStatusSuccess, number: 1, eligible: true, want: true},
+		{name: "success awaiting ack", status: models.AttemptStatusSuccess, number: 1, eligible: true, awaitingAck: true, want: false},
+		{name: "failure with retries left", status: models.AttemptStatusFailed, number: 2, eligible: true, want: false},
+		{name: "failure past retry limit", status: models.AttemptStatusFailed, number: 3, eligible: true, want: true},
+		{name: "failure not eligible for retry", status: models.AttemptStatusFailed, number: 1, eligible: false, want: true},
+		{name: "failure past destination retry limit", status: models.AttemptStatusFailed, number: 2, eligible: true, retryPolicy: &models.RetryPolicy{MaxAttempts: 2}, want: true},
+		{name: "failed transformation", status: models.AttemptStatusFailed, number: 1, eligible: true, responseData: map[string]any{"error": "transformation_failed"}, want: true},
+		{name: "deferred", status: models.AttemptStatusDeferred, number: 5, eligible: true, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := testutil.EventFactory.AnyPointer(testutil.EventFactory.WithEligibleForRetry(tt.eligible))
+			destination := testutil.DestinationFactory.Any()
+			destination.RetryPolicy = tt.retryPolicy
+			attempt := testutil.AttemptFactory.AnyPointer(
+				testutil.AttemptFactory.WithStatus(tt.status),
+				testutil.AttemptFactory.WithAttemptNumber(tt.number),
+			)
+			attempt.ResponseData = tt.responseData
+			entry := &models.LogEntry{
+				Event:       event,
+				Attempt:     attempt,
+				Destination: &destination,
+				AwaitingAck: tt.awaitingAck,
+			}
+			assert.Equal(t, tt.want, notifier.Terminal(entry))
+		})
+	}
+}
+
+type tenants map[string]*models.Tenant
+
+func (ts tenants) RetrieveTenant(_ context.Context, tenantID string) (*models.Tenant, error) {
+	tenant, ok := ts[tenantID]
+	if !ok {
+		return nil, tenantstore.ErrTenantNotFound
+	}
+	return tenant, nil
+}
+
+func TestNotifier_TenantCallbacks(t *testing.T) {
+	t.Parallel()
+
+	deployment, deploymentServer := newCallback(t)
+	producer, producerServer := newCallback(t)
+	notifier, err := lifecycle.New(context.Background(), testutil.CreateTestLogger(t), tenants{
+		"t1": {ID: "t1", LifecycleCallback: &models.LifecycleCallback{URL: producerServer.URL}},
+		"t2": {ID: "t2"},
+	}, lifecycle.Config{
+		CallbackURL:     deploymentServer.URL,
+		TenantCallbacks: true,
+		BatchSize:       100,
+		BatchInterval:   time.Hour,
+	})
+	require.NoError(t, err)
+
+	for _, tenantID := range []string{"t1", "t2", "t3", "t1"} {
+		notifier.EventAccepted(testutil.EventFactory.AnyPointer(testutil.EventFactory.WithTenantID(tenantID)), nil)
+	}
+	notifier.Shutdown()
+
+	require.Len(t, producer.batches, 1)
+	require.Len(t, producer.batches[0].Notifications, 2, "the tenant's notifications go to its own callback")
+	for _, notification := range producer.batches[0].Notifications {
+		assert.Equal(t, "t1", notification.TenantID)
+	}
+
+	require.Len(t, deployment.batches, 2, "each tenant without a callback gets its own batch")
+	assert.Equal(t, "t2", deployment.batches[0].Notifications[0].TenantID)
+	assert.Equal(t, "t3", deployment.batches[1].Notifications[0].TenantID)
+}
+
+func TestNotifier_RetriesWithBackoff(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	fake := clock.NewFake(time.Now())
+	notifier, err := lifecycle.New(context.Background(), testutil.CreateTestLogger(t), nil, lifecycle.Config{
+		CallbackURL:   server.URL,
+		BatchSize:     1,
+		BatchInterval: time.Hour,
+	}, lifecycle.WithClock(fake), lifecycle.WithBackoff(&backoff.ConstantBackoff{Interval: time.Minute}))
+	require.NoError(t, err)
+
+	notifier.EventAccepted(testutil.EventFactory.AnyPointer(), nil)
+
+	for range 2 {
+		fake.BlockUntil(1)
+		fake.Advance(time.Minute)
+	}
+	notifier.Shutdown()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 3, requests, "the batch is resent after each backoff until it succeeds")
+}
@@ -0,0 +1,356 @@
+// Package logarchive exports delivery logs to object storage before log
+// retention deletes them, and restores archived time ranges into a log store.
+//
+// Logs are archived by UTC day of the attempt. Each day is written as one or
+// more gzipped JSON-lines chunks holding a line per attempt with its event, in
+// the shape of a models.LogEntry. A manifest at the root of the archive lists
+// the archived days and their chunks; it is what restores read and what bounds
+// how far the log store may be pruned.
+package logarchive
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/models"
+	"go.uber.org/zap"
+)
+
+// ManifestVersion is the manifest format version.
+const ManifestVersion = 1
+
+const (
+	manifestKey      = "manifest.json"
+	dateLayout       = "2006-01-02"
+	oneDay           = 24 * time.Hour
+	defaultPageSize  = 1000
+	defaultChunkSize = 100000
+)
+
+// ErrNotFound is returned by a Store for a key with no object.
+var ErrNotFound = errors.New("logarchive: object not found")
+
+// Store is the object storage the archive is written to. Keys are relative to
+// the archive's root.
+type Store interface {
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+	// Get returns ErrNotFound when the key has no object.
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// Manifest lists the archived days, oldest first. Days are contiguous: a day
+// without delivery logs is listed with no chunks.
+type Manifest struct {
+	Version   int       `json:"version"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Days      []Day     `json:"days"`
+}
+
+// Day is an archived UTC day.
+type Day struct {
+	Date       string    `json:"date"`
+	Records    int       `json:"records"`
+	ArchivedAt time.Time `json:"archived_at"`
+	Chunks     []Chunk   `json:"chunks"`
+}
+
+// Chunk is a gzipped JSON-lines object holding part of a day.
+type Chunk struct {
+	Key     string `json:"key"`
+	Records int    `json:"records"`
+	Bytes   int    `json:"bytes"`
+	// SHA256 is the hex SHA-256 of the object as stored.
+	SHA256 string `json:"sha256"`
+}
+
+// ArchivedBefore returns the end of the last archived day, or the zero time
+// when nothing is archived.
+func (m *Manifest) ArchivedBefore() time.Time {
+	if len(m.Days) == 0 {
+		return time.Time{}
+	}
+	start, err := time.Parse(dateLayout, m.Days[len(m.Days)-1].Date)
+	if err != nil {
+		return time.Time{}
+	}
+	return start.Add(oneDay)
+}
+
+// ReadManifest reads the archive's manifest. An archive that was never
+// written has an empty manifest.
+func ReadManifest(ctx context.Context, store Store) (*Manifest, error) {
+	body, err := store.Get(ctx, manifestKey)
+	if errors.Is(err, ErrNotFound) {
+		return &Manifest{Version: ManifestVersion, Days: []Day{}}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read manifest: %w", err)
+	}
+	var manifest Manifest
+	if err := json.Unmarshal(body, &manifest); err != nil {
+		return nil, fmt.Errorf("decode manifest: %w", err)
+	}
+	if manifest.Version != ManifestVersion {
+		return nil, fmt.Errorf("unsupported manifest version %d", manifest.Version)
+	}
+	return &manifest, nil
+}
+
+func writeManifest(ctx context.Context, store Store, manifest *Manifest) error {
+	body, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := store.Put(ctx, manifestKey, body, "application/json"); err != nil {
+		return fmt.Errorf("write manifest: %w", err)
+	}
+	return nil
+}
+
+// AttemptLister is the subset of the log store read to archive deliveries.
+type AttemptLister interface {
+	ListAttempt(ctx context.Context, req logstore.ListAttemptRequest) (logstore.ListAttemptResponse, error)
+}
+
+// Archiver exports the delivery logs of every UTC day older than its
+// threshold that isn't archived yet.
+type Archiver struct {
+	attempts  AttemptLister
+	store     Store
+	afterDays int
+	logger    *logging.Logger
+	pageSize  int
+	chunkSize int
+	now       func() time.Time
+}
+
+// ArchiverOption configures an Archiver.
+type ArchiverOption func(*Archiver)
+
+// WithPageSize sets how many attempts are read from the log store per query.
+func WithPageSize(pageSize int) ArchiverOption {
+	return func(a *Archiver) {
+		if pageSize > 0 {
+			a.pageSize = pageSize
+		}
+	}
+}
+
+// WithChunkSize sets the maximum number of records in a chunk.
+func WithChunkSize(chunkSize int) ArchiverOption {
+	return func(a *Archiver) {
+		if chunkSize > 0 {
+			a.chunkSize = chunkSize
+		}
+	}
+}
+
+// WithNow sets the clock deciding which days are old enough to archive.
+func WithNow(now func() time.Time) ArchiverOption {
+	return func(a *Archiver) {
+		a.now = now
+	}
+}
+
+// NewArchiver creates an archiver exporting days once they are afterDays old.
+func NewArchiver(attempts AttemptLister, store Store, afterDays int, logger *logging.Logger, opts ...ArchiverOption) *Archiver {
+	a := &Archiver{
+		attempts:  attempts,
+		store:     store,
+		afterDays: afterDays,
+		logger:    logger,
+		pageSize:  defaultPageSize,
+		chunkSize: defaultChunkSize,
+		now:       time.Now,
+	}
+	for _, opt := range opts {
+		opt(a)
+	}
+	return a
+}
+
+// ArchivedBefore returns the time before which delivery logs are archived, or
+// the zero time when nothing is.
+func (a *Archiver) ArchivedBefore(ctx context.Context) (time.Time, error) {
+	manifest, err := ReadManifest(ctx, a.store)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return manifest.ArchivedBefore(), nil
+}
+
+// Run archives every day due, oldest first, and returns the number of days
+// archived. The manifest is written after each day, so a failed run resumes
+// at the failed day.
+func (a *Archiver) Run(ctx context.Context) (int, error) {
+	manifest, err := ReadManifest(ctx, a.store)
+	if err != nil {
+		return 0, err
+	}
+
+	end := a.now().UTC().AddDate(0, 0, -a.afterDays).Truncate(oneDay)
+	next := manifest.ArchivedBefore()
+	if next.IsZero() {
+		first, err := a.firstAttemptTime(ctx, end)
+		if err != nil {
+			return 0, err
+		}
+		if first.IsZero() {
+			return 0, nil
+		}
+		next = first.UTC().Truncate(oneDay)
+	}
+
+	archived := 0
+	for ; next.Before(end); next = next.Add(oneDay) {
+		archivedDay, err := a.archiveDay(ctx, next)
+		if err != nil {
+			return archived, fmt.Errorf("archive %s: %w", next.Format(dateLayout), err)
+		}
+		manifest.Days = append(manifest.Days, *archivedDay)
+		manifest.UpdatedAt = a.now().UTC()
+		if err := writeManifest(ctx, a.store, manifest); err != nil {
+			return archived, err
+		}
+		archived++
+	}
+	return archived, nil
+}
+
+// firstAttemptTime returns the time of the oldest attempt before end, or the
+// zero time when there is none.
+func (a *Archiver) firstAttemptTime(ctx context.Context, end time.Time) (time.Time, error) {
+	resp, err := a.attempts.ListAttempt(ctx, logstore.ListAttemptRequest{
+		TimeFilter: logstore.TimeFilter{LT: &end},
+		Limit:      1,
+		SortOrder:  "asc",
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("list attempts: %w", err)
+	}
+	if len(resp.Data) == 0 || resp.Data[0].Attempt == nil {
+		return time.Time{}, nil
+	}
+	return resp.Data[0].Attempt.Time, nil
+}
+
+func (a *Archiver) archiveDay(ctx context.Context, start time.Time) (*Day, error) {
+	end := start.Add(oneDay)
+	archivedDay := &Day{
+		Date:   start.Format(dateLayout),
+		Chunks: []Chunk{},
+	}
+	req := logstore.ListAttemptRequest{
+		TimeFilter: logstore.TimeFilter{
+			GTE: &start,
+			LT:  &end,
+		},
+		Limit:     a.pageSize,
+		SortOrder: "asc",
+	}
+
+	w := newChunkWriter()
+	flush := func() error {
+		if w.records == 0 {
+			return nil
+		}
+		chunk, err := w.close()
+		if err != nil {
+			return err
+		}
+		chunk.Key = chunkKey(start, len(archivedDay.Chunks))
+		if err := a.store.Put(ctx, chunk.Key, w.buf.Bytes(), "application/gzip"); err != nil {
+			return fmt.Errorf("write chunk: %w", err)
+		}
+		archivedDay.Chunks = append(archivedDay.Chunks, chunk)
+		archivedDay.Records += chunk.Records
+		w = newChunkWriter()
+		return nil
+	}
+
+	skipped := 0
+	for {
+		resp, err := a.attempts.ListAttempt(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("list attempts: %w", err)
+		}
+		for _, record := range resp.Data {
+			// An attempt can't be restored without its event.
+			if record.Attempt == nil || record.Event == nil {
+				skipped++
+				continue
+			}
+			if err := w.write(&models.LogEntry{Event: record.Event, Attempt: record.Attempt}); err != nil {
+				return nil, err
+			}
+			if w.records >= a.chunkSize {
+				if err := flush(); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if resp.Next == "" {
+			break
+		}
+		req.Next = resp.Next
+	}
+	if err := flush(); err != nil {
+		return nil, err
+	}
+	if skipped > 0 {
+		a.logger.Ctx(ctx).Warn("skipped attempts without an event",
+			zap.String("date", archivedDay.Date),
+			zap.Int("skipped", skipped))
+	}
+
+	archivedDay.ArchivedAt = a.now().UTC()
+	return archivedDay, nil
+}
+
+// chunkKey returns e.g. "logs/2026/03/14/part-0000.jsonl.gz".
+func chunkKey(start time.Time, part int) string {
+	return fmt.Sprintf("logs/%s/part-%04d.jsonl.gz", start.Format("2006/01/02"), part)
+}
+
+type chunkWriter struct {
+	buf     bytes.Buffer
+	gz      *gzip.Writer
+	enc     *json.Encoder
+	records int
+}
+
+func newChunkWriter() *chunkWriter {
+	w := &chunkWriter{}
+	w.gz = gzip.NewWriter(&w.buf)
+	w.enc = json.NewEncoder(w.gz)
+	return w
+}
+
+func (w *chunkWriter) write(entry *models.LogEntry) error {
+	if err := w.enc.Encode(entry); err != nil {
+		return fmt.Errorf("encode log entry: %w", err)
+	}
+	w.records++
+	return nil
+}
+
+func (w *chunkWriter) close() (Chunk, error) {
+	if err := w.gz.Close(); err != nil {
+		return Chunk{}, fmt.Errorf("compress chunk: %w", err)
+	}
+	sum := sha256.Sum256(w.buf.Bytes())
+	return Chunk{
+		Records: w.records,
+		Bytes:   w.buf.Len(),
+		SHA256:  hex.EncodeToString(sum[:]),
+	}, nil
+}
@@ -0,0 +1,231 @@
+package logarchive_test
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logarchive"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/logstore/memlogstore"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memStore struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func newMemStore() *memStore {
+	return &memStore{objects: map[string][]byte{}}
+}
+
+func (s *memStore) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.objects[key] = append([]byte(nil), body...)
+	return nil
+}
+
+func (s *memStore) Get(ctx context.Context, key string) ([]byte, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	body, ok := s.objects[key]
+	if !ok {
+		return nil, logarchive.ErrNotFound
+	}
+	return body, nil
+}
+
+func newLogger(t *testing.T) *logging.Logger {
+	t.Helper()
+	logger, err := logging.NewLogger(logging.WithLogLevel("error"))
+	require.NoError(t, err)
+	return logger
+}
+
+func insertAttempt(t *testing.T, store logstore.LogStore, tenantID, attemptID string, at time.Time) {
+	t.Helper()
+	event := testutil.EventFactory.AnyPointer(
+		testutil.EventFactory.WithTenantID(tenantID),
+		testutil.EventFactory.WithTopic("user.created"),
+		testutil.EventFactory.WithTime(at),
+		testutil.EventFactory.WithData(json.RawMessage(`{"id":"`+attemptID+`"}`)),
+	)
+	attempt := testutil.AttemptFactory.AnyPointer(
+		testutil.AttemptFactory.WithID(attemptID),
+		testutil.AttemptFactory.WithTenantID(tenantID),
+		testutil.AttemptFactory.WithEventID(event.ID),
+		testutil.AttemptFactory.WithDestinationID(event.DestinationID),
+		testutil.AttemptFactory.WithStatus("success"),
+		testutil.AttemptFactory.WithTime(at),
+	)
+	require.NoError(t, store.InsertMany(context.Background(), []*models.LogEntry{{Event: event, Attempt: attempt}}))
+}
+
+var now = time.Date(2026, 3, 20, 10, 0, 0, 0, time.UTC)
+
+// seed inserts attempts on March 10 (1), 11 (2) and 18 (1), 2026.
+func seed(t *testing.T) logstore.LogStore {
+	t.Helper()
+	logStore := memlogstore.NewLogStore()
+	insertAttempt(t, logStore, "tenant_a", "atm_1", time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC))
+	insertAttempt(t, logStore, "tenant_a", "atm_2", time.Date(2026, 3, 11, 1, 0, 0, 0, time.UTC))
+	insertAttempt(t, logStore, "tenant_b", "atm_3", time.Date(2026, 3, 11, 23, 0, 0, 0, time.UTC))
+	insertAttempt(t, logStore, "tenant_a", "atm_4", time.Date(2026, 3, 18, 8, 0, 0, 0, time.UTC))
+	return logStore
+}
+
+func TestArchiver_Run(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newMemStore()
+	archiver := logarchive.NewArchiver(seed(t), store, 7, newLogger(t),
+		logarchive.WithNow(func() time.Time { return now }),
+		logarchive.WithPageSize(1),
+		logarchive.WithChunkSize(1),
+	)
+
+	archived, err := archiver.Run(ctx)
+	require.NoError(t, err)
+	// Days up to March 13 are 7 days old on March 20.
+	assert.Equal(t, 3, archived)
+
+	manifest, err := logarchive.ReadManifest(ctx, store)
+	require.NoError(t, err)
+	require.Len(t, manifest.Days, 3)
+	assert.Equal(t, "2026-03-10", manifest.Days[0].Date)
+	assert.Equal(t, 1, manifest.Days[0].Records)
+	assert.Equal(t, "2026-03-11", manifest.Days[1].Date)
+	assert.Equal(t, 2, manifest.Days[1].Records)
+	require.Len(t, manifest.Days[1].Chunks, 2, "chunks are split at the chunk size")
+	assert.Equal(t, "logs/2026/03/11/part-0001.jsonl.gz", manifest.Days[1].Chunks[1].Key)
+	assert.Equal(t, "2026-03-12", manifest.Days[2].Date)
+	assert.Empty(t, manifest.Days[2].Chunks, "days without logs are listed")
+
+	archivedBefore, err := archiver.ArchivedBefore(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC), archivedBefore)
+
+	t.Run("resumes after the last archived day", func(t *testing.T) {
+		archived, err := archiver.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 0, archived)
+
+		later := logarchive.NewArchiver(seed(t), store, 7, newLogger(t),
+			logarchive.WithNow(func() time.Time { return now.AddDate(0, 0, 2) }))
+		archived, err = later.Run(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 2, archived)
+
+		manifest, err := logarchive.ReadManifest(ctx, store)
+		require.NoError(t, err)
+		assert.Len(t, manifest.Days, 5)
+	})
+}
+
+func TestArchiver_RunNothingToArchive(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newMemStore()
+	archiver := logarchive.NewArchiver(memlogstore.NewLogStore(), store, 7, newLogger(t),
+		logarchive.WithNow(func() time.Time { return now }))
+
+	archived, err := archiver.Run(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 0, archived)
+
+	archivedBefore, err := archiver.ArchivedBefore(ctx)
+	require.NoError(t, err)
+	assert.True(t, archivedBefore.IsZero())
+}
+
+func TestRestore(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := newMemStore()
+	archiver := logarchive.NewArchiver(seed(t), store, 7, newLogger(t),
+		logarchive.WithNow(func() time.Time { return now }))
+	_, err := archiver.Run(ctx)
+	require.NoError(t, err)
+
+	target := memlogstore.NewLogStore()
+	start := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	end := time.Date(2026, 3, 11, 12, 0, 0, 0, time.UTC)
+
+	result, err := logarchive.Restore(ctx, store, target, start, end)
+	require.NoError(t, err)
+	assert.Equal(t, logarchive.RestoreResult{Days: 2, Chunks: 2, Records: 1}, result)
+
+	// Restoring again doesn't duplicate records.
+	_, err = logarchive.Restore(ctx, store, target, start, end)
+	require.NoError(t, err)
+
+	resp, err := target.ListAttempt(ctx, logstore.ListAttemptRequest{})
+	require.NoError(t, err)
+	require.Len(t, resp.Data, 1)
+	assert.Equal(t, "atm_2", resp.Data[0].Attempt.ID)
+	require.NotNil(t, resp.Data[0].Event)
+	assert.JSONEq(t, `{"id":"atm_2"}`, string(resp.Data[0].Event.Data))
+
+	t.Run("rejects a modified chunk", func(t *testing.T) {
+		manifest, err := logarchive.ReadManifest(ctx, store)
+		require.NoError(t, err)
+		key := manifest.Days[0].Chunks[0].Key
+		require.NoError(t, store.Put(ctx, key, []byte("tampered"), "application/gzip"))
+
+		_, err = logarchive.Restore(ctx, store, memlogstore.NewLogStore(), start, end)
+		assert.ErrorIs(t, err, logarchive.ErrChecksumMismatch)
+	})
+}
+
+func TestRestore_RetryWithoutFirstAttempt(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	source := memlogstore.NewLogStore()
+	event := testutil.EventFactory.AnyPointer(
+		testutil.EventFactory.WithTenantID("tenant_a"),
+		testutil.EventFactory.WithTime(time.Date(2026, 3, 10, 23, 0, 0, 0, time.UTC)),
+	)
+	for i, at := range []time.Time{
+		time.Date(2026, 3, 10, 23, 0, 0, 0, time.UTC),
+		time.Date(2026, 3, 11, 1, 0, 0, 0, time.UTC),
+	} {
+		attempt := testutil.AttemptFactory.AnyPointer(
+			testutil.AttemptFactory.WithTenantID(event.TenantID),
+			testutil.AttemptFactory.WithEventID(event.ID),
+			testutil.AttemptFactory.WithDestinationID(event.DestinationID),
+			testutil.AttemptFactory.WithAttemptNumber(i+1),
+			testutil.AttemptFactory.WithTime(at),
+		)
+		require.NoError(t, source.InsertMany(ctx, []*models.LogEntry{{Event: event, Attempt: attempt}}))
+	}
+
+	store := newMemStore()
+	archiver := logarchive.NewArchiver(source, store, 7, newLogger(t),
+		logarchive.WithNow(func() time.Time { return now }))
+	_, err := archiver.Run(ctx)
+	require.NoError(t, err)
+
+	// Only the retry is in the range; its event is restored with it.
+	target := memlogstore.NewLogStore()
+	result, err := logarchive.Restore(ctx, store, target,
+		time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC), time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Records)
+
+	restored, err := target.RetrieveEvent(ctx, logstore.RetrieveEventRequest{EventID: event.ID})
+	require.NoError(t, err)
+	require.NotNil(t, restored)
+	assert.Equal(t, event.ID, restored.ID)
+}
@@ -0,0 +1,453 @@
+package logarchive
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/cursor"
+	"github.com/hookdeck/outpost/internal/logstore/driver"
+	"github.com/hookdeck/outpost/internal/logstore/tieredlogstore"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/pagination"
+)
+
+const (
+	cursorResourceEvent   = "aevt"
+	cursorResourceAttempt = "aatt"
+	cursorVersion         = 1
+
+	// manifestTTL is how long a read manifest is reused. Days are archived
+	// hourly at most, and stitched cursors pin the tier boundaries.
+	manifestTTL      = time.Minute
+	defaultCacheDays = 4
+
+	// positionLayout formats record times at a fixed width, so that
+	// positions sort as strings.
+	positionLayout = "2006-01-02T15:04:05.000000000Z07:00"
+)
+
+// Reader lists archived delivery logs, as the archive of a tiered log store.
+// A list reads the archived days it spans one at a time, from the newest or
+// the oldest, until its page is full, and the most recently read days are
+// kept in memory for the pages that follow.
+//
+// Attempts are read from the day of their time. Events are read from the day
+// of their time and the next, which holds the attempts of events published
+// late in a day; an event whose attempts all came later isn't listed.
+type Reader struct {
+	store     Store
+	cacheDays int
+
+	mu             sync.Mutex
+	manifest       *Manifest
+	manifestReadAt time.Time
+	// days holds the cached days, the most recently read last.
+	days []cachedDay
+}
+
+type cachedDay struct {
+	date    string
+	entries []*models.LogEntry
+}
+
+var _ tieredlogstore.Archive = (*Reader)(nil)
+
+// ReaderOption configures a Reader.
+type ReaderOption func(*Reader)
+
+// WithCacheDays sets how many archived days are kept in memory.
+func WithCacheDays(days int) ReaderOption {
+	return func(r *Reader) {
+		if days > 0 {
+			r.cacheDays = days
+		}
+	}
+}
+
+// NewReader creates a reader of the archive in store.
+func NewReader(store Store, opts ...ReaderOption) *Reader {
+	r := &Reader{
+		store:     store,
+		cacheDays: defaultCacheDays,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// ArchivedBefore returns the end of the last archived day for attempts, and
+// the day before for events, whose attempts may be in the next day.
+func (r *Reader) ArchivedBefore(ctx context.Context) (events, attempts time.Time, err error) {
+	manifest, err := r.readManifest(ctx)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	attempts = manifest.ArchivedBefore()
+	if attempts.IsZero() {
+		return time.Time{}, time.Time{}, nil
+	}
+	return attempts.Add(-oneDay), attempts, nil
+}
+
+func (r *Reader) ListEvent(ctx context.Context, req driver.ListEventRequest) (driver.ListEventResponse, error) {
+	manifest, err := r.readManifest(ctx)
+	if err != nil {
+		return driver.ListEventResponse{}, err
+	}
+	res, err := pagination.Run(ctx, pagination.Config[positioned[*models.Event]]{
+		Limit: listLimit(req.Limit),
+		Order: listOrder(req.SortOrder),
+		Next:  req.Next,
+		Prev:  req.Prev,
+		Fetch: func(ctx context.Context, q pagination.QueryInput) ([]positioned[*models.Event], error) {
+			return walk(manifest, q, req.TimeFilter, func(day int) ([]positioned[*models.Event], error) {
+				return r.dayEvents(ctx, manifest, day, req)
+			})
+		},
+		Cursor: positionCursor[*models.Event](cursorResourceEvent),
+	})
+	if err != nil {
+		return driver.ListEventResponse{}, err
+	}
+	data := make([]*models.Event, len(res.Items))
+	for i, item := range res.Items {
+		data[i] = item.item
+	}
+	return driver.ListEventResponse{Data: data, Next: res.Next, Prev: res.Prev}, nil
+}
+
+func (r *Reader) ListAttempt(ctx context.Context, req driver.ListAttemptRequest) (driver.ListAttemptResponse, error) {
+	manifest, err := r.readManifest(ctx)
+	if err != nil {
+		return driver.ListAttemptResponse{}, err
+	}
+	res, err := pagination.Run(ctx, pagination.Config[positioned[*driver.AttemptRecord]]{
+		Limit: listLimit(req.Limit),
+		Order: listOrder(req.SortOrder),
+		Next:  req.Next,
+		Prev:  req.Prev,
+		Fetch: func(ctx context.Context, q pagination.QueryInput) ([]positioned[*driver.AttemptRecord], error) {
+			return walk(manifest, q, req.TimeFilter, func(day int) ([]positioned[*driver.AttemptRecord], error) {
+				return r.dayAttempts(ctx, manifest, day, req)
+			})
+		},
+		Cursor: positionCursor[*driver.AttemptRecord](cursorResourceAttempt),
+	})
+	if err != nil {
+		return driver.ListAttemptResponse{}, err
+	}
+	data := make([]*driver.AttemptRecord, len(res.Items))
+	for i, item := range res.Items {
+		data[i] = item.item
+	}
+	return driver.ListAttemptResponse{Data: data, Next: res.Next, Prev: res.Prev}, nil
+}
+
+// dayEvents returns the events of the day at index day matching req.
+func (r *Reader) dayEvents(ctx context.Context, manifest *Manifest, day int, req driver.ListEventRequest) ([]positioned[*models.Event], error) {
+	start, err := time.Parse(dateLayout, manifest.Days[day].Date)
+	if err != nil {
+		return nil, fmt.Errorf("invalid manifest date %q: %w", manifest.Days[day].Date, err)
+	}
+	end := start.Add(oneDay)
+	seen := map[string]bool{}
+	var items []positioned[*models.Event]
+	for d := day; d < len(manifest.Days) && d <= day+1; d++ {
+		entries, err := r.day(ctx, manifest.Days[d])
+		if err != nil {
+			return nil, err
+		}
+		for _, entry := range entries {
+			event := entry.Event
+			if seen[event.ID] || event.Time.Before(start) || !event.Time.Before(end) {
+				continue
+			}
+			seen[event.ID] = true
+			if !matchesEvent(event, req) {
+				continue
+			}
+			copied := *event
+			items = append(items, positioned[*models.Event]{item: &copied, pos: position(event.Time, event.ID)})
+		}
+	}
+	return items, nil
+}
+
+// dayAttempts returns the attempts of the day at index day matching req.
+func (r *Reader) dayAttempts(ctx context.Context, manifest *Manifest, day int, req driver.ListAttemptRequest) ([]positioned[*driver.AttemptRecord], error) {
+	entries, err := r.day(ctx, manifest.Days[day])
+	if err != nil {
+		return nil, err
+	}
+	var items []positioned[*driver.AttemptRecord]
+	for _, entry := range entries {
+		if !matchesAttempt(entry, req) {
+			continue
+		}
+		attempt, event := *entry.Attempt, *entry.Event
+		items = append(items, positioned[*driver.AttemptRecord]{
+			item: &driver.AttemptRecord{Attempt: &attempt, Event: &event},
+			pos:  position(attempt.Time, attempt.ID),
+		})
+	}
+	return items, nil
+}
+
+// day returns the records of an archived day, reading its chunks unless the
+// day is cached. Archived days don't change, so cached days don't expire.
+func (r *Reader) day(ctx context.Context, archivedDay Day) ([]*models.LogEntry, error) {
+	r.mu.Lock()
+	for i, cached := range r.days {
+		if cached.date == archivedDay.Date {
+			r.days = append(slices.Delete(r.days, i, i+1), cached)
+			r.mu.Unlock()
+			return cached.entries, nil
+		}
+	}
+	r.mu.Unlock()
+
+	var entries []*models.LogEntry
+	for _, chunk := range archivedDay.Chunks {
+		chunkEntries, err := readChunk(ctx, r.store, chunk)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, chunkEntries...)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !slices.ContainsFunc(r.days, func(cached cachedDay) bool { return cached.date == archivedDay.Date }) {
+		r.days = append(r.days, cachedDay{date: archivedDay.Date, entries: entries})
+		if len(r.days) > r.cacheDays {
+			r.days = slices.Delete(r.days, 0, len(r.days)-r.cacheDays)
+		}
+	}
+	return entries, nil
+}
+
+func (r *Reader) readManifest(ctx context.Context) (*Manifest, error) {
+	r.mu.Lock()
+	if r.manifest != nil && time.Since(r.manifestReadAt) < manifestTTL {
+		manifest := r.manifest
+		r.mu.Unlock()
+		return manifest, nil
+	}
+	r.mu.Unlock()
+
+	manifest, err := ReadManifest(ctx, r.store)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	r.manifest, r.manifestReadAt = manifest, time.Now()
+	r.mu.Unlock()
+	return manifest, nil
+}
+
+// positioned pairs a listed record with its position: its time and ID, which
+// order records and locate cursors.
+type positioned[T any] struct {
+	item T
+	pos  string
+}
+
+func position(t time.Time, id string) string {
+	return t.UTC().Format(positionLayout) + "_" + id
+}
+
+func positionCursor[T any](resource string) pagination.Cursor[positioned[T]] {
+	return pagination.Cursor[positioned[T]]{
+		Encode: func(p positioned[T]) string {
+			return cursor.Encode(resource, cursorVersion, p.pos)
+		},
+		Decode: func(c string) (string, error) {
+			pos, err := cursor.Decode(c, resource, cursorVersion)
+			if err != nil {
+				return "", err
+			}
+			if _, err := positionTime(pos); err != nil {
+				return "", cursor.ErrInvalidCursor
+			}
+			return pos, nil
+		},
+	}
+}
+
+func positionTime(pos string) (time.Time, error) {
+	t, _, _ := strings.Cut(pos, "_")
+	return time.Parse(positionLayout, t)
+}
+
+// walk returns up to q.Limit records past q.CursorPos, in q.SortDir order,
+// reading the archived days in range one at a time. Days don't overlap, so
+// the records of a day all come before those of the next.
+func walk[T any](manifest *Manifest, q pagination.QueryInput, filter driver.TimeFilter, read func(day int) ([]positioned[T], error)) ([]positioned[T], error) {
+	lower, upper := timeBounds(filter)
+	if q.CursorPos != "" {
+		t, err := positionTime(q.CursorPos)
+		if err != nil {
+			return nil, cursor.ErrInvalidCursor
+		}
+		if q.Compare == ">" {
+			lower = latest(lower, &t)
+		} else {
+			upper = earliest(upper, &t)
+		}
+	}
+
+	var days []int
+	for i, archivedDay := range manifest.Days {
+		start, err := time.Parse(dateLayout, archivedDay.Date)
+		if err != nil {
+			return nil, fmt.Errorf("invalid manifest date %q: %w", archivedDay.Date, err)
+		}
+		if lower != nil && !start.Add(oneDay).After(*lower) {
+			continue
+		}
+		if upper != nil && start.After(*upper) {
+			continue
+		}
+		days = append(days, i)
+	}
+	desc := q.SortDir == "desc"
+	if desc {
+		slices.Reverse(days)
+	}
+
+	var items []positioned[T]
+	for _, day := range days {
+		dayItems, err := read(day)
+		if err != nil {
+			return nil, err
+		}
+		if q.CursorPos != "" {
+			dayItems = slices.DeleteFunc(dayItems, func(p positioned[T]) bool {
+				if q.Compare == ">" {
+					return p.pos <= q.CursorPos
+				}
+				return p.pos >= q.CursorPos
+			})
+		}
+		slices.SortFunc(dayItems, func(a, b positioned[T]) int {
+			if desc {
+				return strings.Compare(b.pos, a.pos)
+			}
+			return strings.Compare(a.pos, b.pos)
+		})
+		items = append(items, dayItems...)
+		if len(items) >= q.Limit {
+			return items[:q.Limit], nil
+		}
+	}
+	return items, nil
+}
+
+// timeBounds returns the earliest and latest times a filter lets through,
+// inclusively; nil is unbounded.
+func timeBounds(filter driver.TimeFilter) (lower, upper *time.Time) {
+	return latest(filter.GTE, filter.GT), earliest(filter.LTE, filter.LT)
+}
+
+func latest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.After(*a)) {
+		return b
+	}
+	return a
+}
+
+func earliest(a, b *time.Time) *time.Time {
+	if a == nil || (b != nil && b.Before(*a)) {
+		return b
+	}
+	return a
+}
+
+func inTimeFilter(t time.Time, filter driver.TimeFilter) bool {
+	return (filter.GTE == nil || !t.Before(*filter.GTE)) &&
+		(filter.GT == nil || t.After(*filter.GT)) &&
+		(filter.LTE == nil || !t.After(*filter.LTE)) &&
+		(filter.LT == nil || t.Before(*filter.LT))
+}
+
+func matchesEvent(event *models.Event, req driver.ListEventRequest) bool {
+	if len(req.TenantIDs) > 0 && !slices.Contains(req.TenantIDs, event.TenantID) {
+		return false
+	}
+	if len(req.EventIDs) > 0 && !slices.Contains(req.EventIDs, event.ID) {
+		return false
+	}
+	if len(req.DestinationIDs) > 0 && !slices.ContainsFunc(req.DestinationIDs, func(id string) bool {
+		return slices.Contains(event.MatchedDestinationIDs, id)
+	}) {
+		return false
+	}
+	if len(req.Topics) > 0 && !slices.Contains(req.Topics, event.Topic) {
+		return false
+	}
+	if len(req.Sources) > 0 && !slices.Contains(req.Sources, event.Source) {
+		return false
+	}
+	if req.GroupID != "" && event.GroupID != req.GroupID {
+		return false
+	}
+	for key, value := range req.Metadata {
+		if actual, ok := event.Metadata[key]; !ok || actual != value {
+			return false
+		}
+	}
+	if terms := driver.SearchTerms(req.Search); len(terms) > 0 {
+		dataTerms := driver.SearchTerms(string(event.Data))
+		for _, term := range terms {
+			if !slices.Contains(dataTerms, term) {
+				return false
+			}
+		}
+	}
+	return inTimeFilter(event.Time, req.TimeFilter)
+}
+
+func matchesAttempt(entry *models.LogEntry, req driver.ListAttemptRequest) bool {
+	attempt, event := entry.Attempt, entry.Event
+	if len(req.TenantIDs) > 0 && !slices.Contains(req.TenantIDs, event.TenantID) {
+		return false
+	}
+	if len(req.EventIDs) > 0 && !slices.Contains(req.EventIDs, attempt.EventID) {
+		return false
+	}
+	if len(req.DestinationIDs) > 0 && !slices.Contains(req.DestinationIDs, attempt.DestinationID) {
+		return false
+	}
+	if len(req.DestinationTypes) > 0 && !slices.Contains(req.DestinationTypes, attempt.DestinationType) {
+		return false
+	}
+	if req.Status != "" && attempt.Status != req.Status {
+		return false
+	}
+	if len(req.Topics) > 0 && !slices.Contains(req.Topics, event.Topic) {
+		return false
+	}
+	if len(req.Sources) > 0 && !slices.Contains(req.Sources, event.Source) {
+		return false
+	}
+	return inTimeFilter(attempt.Time, req.TimeFilter)
+}
+
+func listLimit(limit int) int {
+	if limit <= 0 {
+		return 100
+	}
+	return limit
+}
+
+func listOrder(order string) string {
+	if order != "asc" {
+		return "desc"
+	}
+	return order
+}
@@ -0,0 +1,149 @@
+package logarchive_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logarchive"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newArchive archives seed's attempts, plus an event published late on March
+// 10 whose attempt came on March 11, and returns the archive's store.
+func newArchive(t *testing.T) (*memStore, *models.Event) {
+	t.Helper()
+	ctx := context.Background()
+	source := seed(t)
+	late := testutil.EventFactory.AnyPointer(
+		testutil.EventFactory.WithID("evt_late"),
+		testutil.EventFactory.WithTenantID("tenant_a"),
+		testutil.EventFactory.WithTime(time.Date(2026, 3, 10, 23, 30, 0, 0, time.UTC)),
+	)
+	attempt := testutil.AttemptFactory.AnyPointer(
+		testutil.AttemptFactory.WithID("atm_late"),
+		testutil.AttemptFactory.WithTenantID(late.TenantID),
+		testutil.AttemptFactory.WithEventID(late.ID),
+		testutil.AttemptFactory.WithDestinationID(late.DestinationID),
+		testutil.AttemptFactory.WithTime(time.Date(2026, 3, 11, 0, 30, 0, 0, time.UTC)),
+	)
+	require.NoError(t, source.InsertMany(ctx, []*models.LogEntry{{Event: late, Attempt: attempt}}))
+
+	store := newMemStore()
+	archiver := logarchive.NewArchiver(source, store, 7, newLogger(t),
+		logarchive.WithNow(func() time.Time { return now }))
+	_, err := archiver.Run(ctx)
+	require.NoError(t, err)
+	return store, late
+}
+
+func attemptIDs(records []*logstore.AttemptRecord) []string {
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.Attempt.ID
+	}
+	return ids
+}
+
+func TestReader(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store, late := newArchive(t)
+	reader := logarchive.NewReader(store, logarchive.WithCacheDays(1))
+
+	t.Run("reports what is archived", func(t *testing.T) {
+		events, attempts, err := reader.ArchivedBefore(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC), attempts)
+		assert.Equal(t, time.Date(2026, 3, 12, 0, 0, 0, 0, time.UTC), events)
+	})
+
+	t.Run("pages through attempts across days", func(t *testing.T) {
+		for _, order := range []string{"desc", "asc"} {
+			t.Run(order, func(t *testing.T) {
+				req := logstore.ListAttemptRequest{SortOrder: order, Limit: 2}
+				var pages []logstore.ListAttemptResponse
+				var got []string
+				for {
+					resp, err := reader.ListAttempt(ctx, req)
+					require.NoError(t, err)
+					pages = append(pages, resp)
+					got = append(got, attemptIDs(resp.Data)...)
+					if resp.Next == "" {
+						break
+					}
+					req.Next = resp.Next
+				}
+
+				want := []string{"atm_3", "atm_2", "atm_late", "atm_1"}
+				if order == "asc" {
+					want = []string{"atm_1", "atm_late", "atm_2", "atm_3"}
+				}
+				assert.Equal(t, want, got)
+				require.Len(t, pages, 2)
+
+				require.NotEmpty(t, pages[1].Prev)
+				resp, err := reader.ListAttempt(ctx, logstore.ListAttemptRequest{SortOrder: order, Limit: 2, Prev: pages[1].Prev})
+				require.NoError(t, err)
+				assert.Equal(t, attemptIDs(pages[0].Data), attemptIDs(resp.Data))
+			})
+		}
+	})
+
+	t.Run("filters attempts", func(t *testing.T) {
+		resp, err := reader.ListAttempt(ctx, logstore.ListAttemptRequest{TenantIDs: []string{"tenant_b"}})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"atm_3"}, attemptIDs(resp.Data))
+
+		start := time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC)
+		resp, err = reader.ListAttempt(ctx, logstore.ListAttemptRequest{
+			TenantIDs:  []string{"tenant_a"},
+			TimeFilter: logstore.TimeFilter{GTE: &start},
+		})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"atm_2", "atm_late"}, attemptIDs(resp.Data))
+	})
+
+	t.Run("lists events by their time", func(t *testing.T) {
+		start := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+		end := start.Add(24 * time.Hour)
+		resp, err := reader.ListEvent(ctx, logstore.ListEventRequest{
+			TenantIDs:  []string{"tenant_a"},
+			TimeFilter: logstore.TimeFilter{GTE: &start, LT: &end},
+		})
+		require.NoError(t, err)
+		require.Len(t, resp.Data, 2)
+		assert.Equal(t, late.ID, resp.Data[0].ID, "an event is listed on its day, though its attempt is on the next")
+		assert.JSONEq(t, `{"id":"atm_1"}`, string(resp.Data[1].Data))
+	})
+
+	t.Run("rejects a cursor of another resource", func(t *testing.T) {
+		resp, err := reader.ListAttempt(ctx, logstore.ListAttemptRequest{Limit: 1})
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.Next)
+
+		_, err = reader.ListEvent(ctx, logstore.ListEventRequest{Limit: 1, Next: resp.Next})
+		assert.Error(t, err)
+	})
+}
+
+func TestReader_EmptyArchive(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	reader := logarchive.NewReader(newMemStore())
+
+	events, attempts, err := reader.ArchivedBefore(ctx)
+	require.NoError(t, err)
+	assert.True(t, events.IsZero())
+	assert.True(t, attempts.IsZero())
+
+	resp, err := reader.ListAttempt(ctx, logstore.ListAttemptRequest{})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Data)
+}
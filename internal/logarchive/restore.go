@@ -0,0 +1,141 @@
+package logarchive
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+const defaultRestoreBatchSize = 1000
+
+// maxLineBytes caps a single archived record.
+const maxLineBytes = 64 << 20
+
+// ErrChecksumMismatch is returned when a chunk no longer matches the SHA-256
+// recorded in the manifest.
+var ErrChecksumMismatch = errors.New("logarchive: chunk checksum mismatch")
+
+// Inserter is the subset of the log store archived records are restored into.
+type Inserter interface {
+	InsertMany(ctx context.Context, entries []*models.LogEntry) error
+}
+
+// RestoreResult counts what a restore read and inserted.
+type RestoreResult struct {
+	Days    int
+	Chunks  int
+	Records int
+}
+
+// Restore inserts the archived attempts, with their events, whose time is in
+// [start, end). Records are inserted as they were archived, so restoring a
+// range twice doesn't duplicate it.
+func Restore(ctx context.Context, store Store, inserter Inserter, start, end time.Time) (RestoreResult, error) {
+	var result RestoreResult
+	if !start.Before(end) {
+		return result, fmt.Errorf("start must be before end")
+	}
+
+	manifest, err := ReadManifest(ctx, store)
+	if err != nil {
+		return result, err
+	}
+
+	// The log store only writes an event with its first attempt, which may
+	// be outside the range, so each event is written with the first of its
+	// attempts restored.
+	restoredEvents := map[string]bool{}
+	batch := make([]*models.LogEntry, 0, defaultRestoreBatchSize)
+	insert := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := inserter.InsertMany(ctx, batch); err != nil {
+			return fmt.Errorf("insert log entries: %w", err)
+		}
+		result.Records += len(batch)
+		batch = batch[:0]
+		return nil
+	}
+
+	for _, archivedDay := range manifest.Days {
+		dayStart, err := time.Parse(dateLayout, archivedDay.Date)
+		if err != nil {
+			return result, fmt.Errorf("invalid manifest date %q: %w", archivedDay.Date, err)
+		}
+		if !dayStart.Before(end) || !dayStart.Add(oneDay).After(start) {
+			continue
+		}
+		result.Days++
+		for _, chunk := range archivedDay.Chunks {
+			entries, err := readChunk(ctx, store, chunk)
+			if err != nil {
+				return result, err
+			}
+			result.Chunks++
+			for _, entry := range entries {
+				if entry.Attempt.Time.Before(start) || !entry.Attempt.Time.Before(end) {
+					continue
+				}
+				if !restoredEvents[entry.Event.ID] {
+					restoredEvents[entry.Event.ID] = true
+					entry.WriteEvent = true
+				}
+				batch = append(batch, entry)
+				if len(batch) == defaultRestoreBatchSize {
+					if err := insert(); err != nil {
+						return result, err
+					}
+				}
+			}
+		}
+	}
+	if err := insert(); err != nil {
+		return result, err
+	}
+	return result, nil
+}
+
+func readChunk(ctx context.Context, store Store, chunk Chunk) ([]*models.LogEntry, error) {
+	body, err := store.Get(ctx, chunk.Key)
+	if err != nil {
+		return nil, fmt.Errorf("read chunk %s: %w", chunk.Key, err)
+	}
+	sum := sha256.Sum256(body)
+	if hex.EncodeToString(sum[:]) != chunk.SHA256 {
+		return nil, fmt.Errorf("%w: %s", ErrChecksumMismatch, chunk.Key)
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("decompress chunk %s: %w", chunk.Key, err)
+	}
+	defer gz.Close()
+
+	entries := make([]*models.LogEntry, 0, chunk.Records)
+	scanner := bufio.NewScanner(gz)
+	scanner.Buffer(make([]byte, 0, 64<<10), maxLineBytes)
+	for scanner.Scan() {
+		var entry models.LogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("decode chunk %s: %w", chunk.Key, err)
+		}
+		if entry.Event == nil || entry.Attempt == nil {
+			return nil, fmt.Errorf("decode chunk %s: record without event or attempt", chunk.Key)
+		}
+		entries = append(entries, &entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read chunk %s: %w", chunk.Key, err)
+	}
+	return entries, nil
+}
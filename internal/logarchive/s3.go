@@ -0,0 +1,113 @@
+package logarchive
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	awscreds "github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	s3types "github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// S3Config configures the bucket the archive is written to. When no static
+// credentials are set, the default AWS credential chain is used (environment,
+// shared config, instance or task role). Google Cloud Storage is supported
+// through its S3-compatible XML API: set Endpoint to
+// https://storage.googleapis.com and use HMAC keys as credentials.
+type S3Config struct {
+	Bucket          string
+	Prefix          string
+	Region          string
+	AccessKeyID     string
+	SecretAccessKey string
+	// Endpoint overrides the S3 endpoint, e.g. for GCS, LocalStack or MinIO.
+	Endpoint string
+}
+
+// S3Store is a Store backed by an S3 bucket.
+type S3Store struct {
+	client *s3.Client
+	bucket string
+	prefix string
+}
+
+var _ Store = (*S3Store)(nil)
+
+// NewS3Store creates a store writing to the configured bucket and prefix.
+func NewS3Store(ctx context.Context, cfg S3Config) (*S3Store, error) {
+	opts := []func(*awsconfig.LoadOptions) error{
+		awsconfig.WithRegion(cfg.Region),
+	}
+	if cfg.AccessKeyID != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(awscreds.NewStaticCredentialsProvider(
+			cfg.AccessKeyID,
+			cfg.SecretAccessKey,
+			"",
+		)))
+	}
+	sdkConfig, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	s3Options := []func(*s3.Options){}
+	if cfg.Endpoint != "" {
+		s3Options = append(s3Options, func(o *s3.Options) {
+			o.BaseEndpoint = awssdk.String(cfg.Endpoint)
+			o.UsePathStyle = true
+		})
+	}
+
+	return &S3Store{
+		client: s3.NewFromConfig(sdkConfig, s3Options...),
+		bucket: cfg.Bucket,
+		prefix: cfg.Prefix,
+	}, nil
+}
+
+func (s *S3Store) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	_, err := s.client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      awssdk.String(s.bucket),
+		Key:         awssdk.String(s.key(key)),
+		Body:        bytes.NewReader(body),
+		ContentType: awssdk.String(contentType),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put s3://%s/%s: %w", s.bucket, s.key(key), err)
+	}
+	return nil
+}
+
+func (s *S3Store) Get(ctx context.Context, key string) ([]byte, error) {
+	out, err := s.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: awssdk.String(s.bucket),
+		Key:    awssdk.String(s.key(key)),
+	})
+	if err != nil {
+		var noSuchKey *s3types.NoSuchKey
+		if errors.As(err, &noSuchKey) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("failed to get s3://%s/%s: %w", s.bucket, s.key(key), err)
+	}
+	defer out.Body.Close()
+
+	body, err := io.ReadAll(out.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read s3://%s/%s: %w", s.bucket, s.key(key), err)
+	}
+	return body, nil
+}
+
+func (s *S3Store) key(key string) string {
+	if s.prefix == "" {
+		return key
+	}
+	return path.Join(s.prefix, key)
+}
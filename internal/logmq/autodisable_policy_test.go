@@ -0,0 +1,142 @@
+package logmq_test
+
+// Tenant auto-disable policies: a tenant's policy overrides the deployment's
+// failure count, whether destinations are disabled, and their probation.
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type probationRecord struct {
+	destinationID string
+	probation     time.Duration
+}
+
+// recordingProbation implements logmq.ProbationScheduler.
+type recordingProbation struct {
+	mu        sync.Mutex
+	scheduled []probationRecord
+}
+
+func (p *recordingProbation) Schedule(ctx context.Context, tenantID, destinationID string, disabledAt time.Time, probation time.Duration) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.scheduled = append(p.scheduled, probationRecord{destinationID: destinationID, probation: probation})
+	return nil
+}
+
+func (p *recordingProbation) snapshot() []probationRecord {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]probationRecord(nil), p.scheduled...)
+}
+
+func policyTenants(tenantID string, policy *models.AutoDisablePolicy) *stubTenantGetter {
+	return &stubTenantGetter{tenants: map[string]*models.Tenant{
+		tenantID: {ID: tenantID, AutoDisablePolicy: policy},
+	}}
+}
+
+// addFailures adds n failed attempts one at a time, waiting for each.
+func addFailures(h *harness, dest, tenant, prefix string, n int) {
+	for i := range n {
+		cm, msg := newCountingMessage(makeEntry(dest, tenant, prefix+string(rune('a'+i)), models.AttemptStatusFailed))
+		h.add(msg)
+		h.waitTerminal([]*countingMessage{cm})
+	}
+}
+
+func TestAutoDisablePolicy_FailureCountOverride(t *testing.T) {
+	t.Parallel()
+	dest, tenant := "dest_adp1", "tenant_adp1"
+	count := 2
+	h := newHarness(t, harnessConfig{
+		batcher: batcherConfig{itemCount: 1},
+		alert:   alertConfig{autoDisableCount: 10, thresholds: []int{100}, withDisabler: true},
+		doubles: doublesConfig{tenants: policyTenants(tenant, &models.AutoDisablePolicy{FailureCount: &count})},
+	})
+
+	addFailures(h, dest, tenant, "att_adp1_", 2)
+
+	assert.Equal(t, []disableRecord{{tenantID: tenant, destinationID: dest}}, h.disabler.snapshot())
+	require.Len(t, forTopic(h.sink.forDest(dest), topicCF), 1)
+}
+
+func TestAutoDisablePolicy_OptOut(t *testing.T) {
+	t.Parallel()
+	dest, tenant := "dest_adp2", "tenant_adp2"
+	autoDisable := false
+	h := newHarness(t, harnessConfig{
+		batcher: batcherConfig{itemCount: 1},
+		alert:   alertConfig{autoDisableCount: 2, thresholds: []int{100}, withDisabler: true},
+		doubles: doublesConfig{tenants: policyTenants(tenant, &models.AutoDisablePolicy{AutoDisable: &autoDisable})},
+	})
+
+	addFailures(h, dest, tenant, "att_adp2_", 2)
+
+	assert.Empty(t, h.disabler.snapshot(), "tenant opted out of auto-disable")
+	recs := h.sink.forDest(dest)
+	assert.Empty(t, forTopic(recs, topicDisabled))
+	assert.Len(t, forTopic(recs, topicCF), 1, "the alert still fires")
+}
+
+func TestAutoDisablePolicy_Probation(t *testing.T) {
+	t.Parallel()
+
+	t.Run("deployment probation", func(t *testing.T) {
+		t.Parallel()
+		dest, tenant := "dest_adp3", "tenant_adp3"
+		probation := &recordingProbation{}
+		h := newHarness(t, harnessConfig{
+			batcher: batcherConfig{itemCount: 1},
+			alert:   alertConfig{autoDisableCount: 2, thresholds: []int{100}, withDisabler: true, probation: time.Hour},
+			doubles: doublesConfig{probation: probation},
+		})
+
+		addFailures(h, dest, tenant, "att_adp3_", 2)
+
+		assert.Equal(t, []probationRecord{{destinationID: dest, probation: time.Hour}}, probation.snapshot())
+	})
+
+	t.Run("tenant probation overrides", func(t *testing.T) {
+		t.Parallel()
+		dest, tenant := "dest_adp4", "tenant_adp4"
+		seconds := 600
+		probation := &recordingProbation{}
+		h := newHarness(t, harnessConfig{
+			batcher: batcherConfig{itemCount: 1},
+			alert:   alertConfig{autoDisableCount: 2, thresholds: []int{100}, withDisabler: true, probation: time.Hour},
+			doubles: doublesConfig{
+				tenants:   policyTenants(tenant, &models.AutoDisablePolicy{ProbationSeconds: &seconds}),
+				probation: probation,
+			},
+		})
+
+		addFailures(h, dest, tenant, "att_adp4_", 2)
+
+		assert.Equal(t, []probationRecord{{destinationID: dest, probation: 10 * time.Minute}}, probation.snapshot())
+	})
+
+	t.Run("no probation", func(t *testing.T) {
+		t.Parallel()
+		dest, tenant := "dest_adp5", "tenant_adp5"
+		probation := &recordingProbation{}
+		h := newHarness(t, harnessConfig{
+			batcher: batcherConfig{itemCount: 1},
+			alert:   alertConfig{autoDisableCount: 2, thresholds: []int{100}, withDisabler: true},
+			doubles: doublesConfig{probation: probation},
+		})
+
+		addFailures(h, dest, tenant, "att_adp5_", 2)
+
+		assert.Len(t, h.disabler.snapshot(), 1)
+		assert.Empty(t, probation.snapshot())
+	})
+}
@@ -0,0 +1,272 @@
+package logmq
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/mqs"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Flush triggers, recorded with the batch metrics.
+const (
+	flushTriggerSize     = "size"
+	flushTriggerDelay    = "delay"
+	flushTriggerShutdown = "shutdown"
+)
+
+// AdaptiveBatching bounds the batch thresholds when they adapt to the load.
+// A batch that fills up before the delay threshold means entries arrive
+// faster than they are written, so both thresholds grow by a quarter to
+// write fewer, larger batches. An insert slower than LatencySLO halves them,
+// as larger batches are slower to write and hold their messages unacked for
+// longer. Batches flushed by the delay threshold leave them unchanged.
+type AdaptiveBatching struct {
+	MinItems int
+	MaxItems int
+	MinDelay time.Duration
+	MaxDelay time.Duration
+	// LatencySLO is the longest an insert may take before the thresholds
+	// shrink.
+	LatencySLO time.Duration
+}
+
+// batchThresholds holds the current thresholds. They are read by the batch
+// loop before each batch and by Thresholds, so they are guarded by a mutex.
+type batchThresholds struct {
+	mu       sync.Mutex
+	items    int
+	delay    time.Duration
+	adaptive *AdaptiveBatching
+}
+
+func newBatchThresholds(items int, delay time.Duration, adaptive *AdaptiveBatching) *batchThresholds {
+	t := &batchThresholds{items: items, delay: delay, adaptive: adaptive}
+	if adaptive != nil {
+		t.items = min(max(t.items, adaptive.MinItems), adaptive.MaxItems)
+		t.delay = min(max(t.delay, adaptive.MinDelay), adaptive.MaxDelay)
+	}
+	return t
+}
+
+func (t *batchThresholds) get() (int, time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.items, t.delay
+}
+
+// maxItems is the largest item threshold the thresholds can reach.
+func (t *batchThresholds) maxItems() int {
+	if t.adaptive != nil {
+		return t.adaptive.MaxItems
+	}
+	return t.items
+}
+
+// observe adapts the thresholds to a written batch, and reports whether they
+// changed.
+func (t *batchThresholds) observe(trigger string, insertDuration time.Duration) bool {
+	if t.adaptive == nil {
+		return false
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	items, delay := t.items, t.delay
+	switch {
+	case insertDuration > t.adaptive.LatencySLO:
+		t.items = max(t.adaptive.MinItems, t.items/2)
+		t.delay = max(t.adaptive.MinDelay, t.delay/2)
+	case trigger == flushTriggerSize:
+		t.items = min(t.adaptive.MaxItems, t.items+max(1, t.items/4))
+		t.delay = min(t.adaptive.MaxDelay, t.delay+t.delay/4)
+	}
+	return t.items != items || t.delay != delay
+}
+
+// batchMetrics records how efficiently log entries are batched.
+type batchMetrics struct {
+	size           metric.Int64Histogram
+	fill           metric.Int64Histogram
+	insertLatency  metric.Int64Histogram
+	sloViolations  metric.Int64Counter
+	itemThreshold  metric.Int64Gauge
+	delayThreshold metric.Int64Gauge
+}
+
+func newBatchMetrics() *batchMetrics {
+	// A failing meter leaves batches unrecorded rather than failing them.
+	meter := otel.Meter("outpost")
+	m := &batchMetrics{}
+	m.size, _ = meter.Int64Histogram("outpost.log_batch.size",
+		metric.WithDescription("Number of messages per log batch"),
+	)
+	m.fill, _ = meter.Int64Histogram("outpost.log_batch.fill",
+		metric.WithUnit("%"),
+		metric.WithDescription("Size of a log batch as a percentage of the item threshold it was flushed at"),
+	)
+	m.insertLatency, _ = meter.Int64Histogram("outpost.log_batch.insert_latency",
+		metric.WithUnit("ms"),
+		metric.WithDescription("Time taken to write a log batch to the log store"),
+	)
+	m.sloViolations, _ = meter.Int64Counter("outpost.log_batch.slo_violations",
+		metric.WithDescription("Number of log batch inserts slower than the adaptive batching latency SLO"),
+	)
+	m.itemThreshold, _ = meter.Int64Gauge("outpost.log_batch.item_threshold",
+		metric.WithDescription("Number of messages a log batch is flushed at"),
+	)
+	m.delayThreshold, _ = meter.Int64Gauge("outpost.log_batch.delay_threshold",
+		metric.WithUnit("ms"),
+		metric.WithDescription("Longest time a message waits in a log batch"),
+	)
+	return m
+}
+
+func (m *batchMetrics) recordBatch(ctx context.Context, trigger string, count, itemThreshold int) {
+	trig := metric.WithAttributes(attribute.String("trigger", trigger))
+	if m.size != nil {
+		m.size.Record(ctx, int64(count), trig)
+	}
+	if m.fill != nil && itemThreshold > 0 {
+		m.fill.Record(ctx, int64(min(count, itemThreshold)*100/itemThreshold), trig)
+	}
+}
+
+func (m *batchMetrics) recordInsert(ctx context.Context, insertDuration time.Duration, slo *AdaptiveBatching) {
+	if m.insertLatency != nil {
+		m.insertLatency.Record(ctx, insertDuration.Milliseconds())
+	}
+	if m.sloViolations != nil && slo != nil && insertDuration > slo.LatencySLO {
+		m.sloViolations.Add(ctx, 1)
+	}
+}
+
+func (m *batchMetrics) recordThresholds(ctx context.Context, items int, delay time.Duration) {
+	if m.itemThreshold != nil {
+		m.itemThreshold.Record(ctx, int64(items))
+	}
+	if m.delayThreshold != nil {
+		m.delayThreshold.Record(ctx, delay.Milliseconds())
+	}
+}
+
+// batchLoop collects messages into batches on a single goroutine: a batch is
+// flushed when it reaches the item threshold, when its first message has
+// waited for the delay threshold, or on shutdown. The thresholds are read
+// when a batch starts, so a change applies from the next batch.
+type batchLoop struct {
+	thresholds *batchThresholds
+	flush      func(msgs []*mqs.Message, trigger string)
+
+	// mu guards stopped: add holds it shared while queueing, so once
+	// shutdown holds it, no message can be queued behind the final drain.
+	mu      sync.RWMutex
+	stopped bool
+	queue   chan *mqs.Message
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+func newBatchLoop(thresholds *batchThresholds, flush func(msgs []*mqs.Message, trigger string)) *batchLoop {
+	l := &batchLoop{
+		thresholds: thresholds,
+		flush:      flush,
+		queue:      make(chan *mqs.Message, thresholds.maxItems()),
+		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+	}
+	go l.run()
+	return l
+}
+
+// add queues a message for the next batch. It blocks while the queue is
+// full. A message added after shutdown is nacked for redelivery.
+func (l *batchLoop) add(msg *mqs.Message) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+	if l.stopped {
+		msg.Nack()
+		return
+	}
+	l.queue <- msg
+}
+
+// shutdown flushes the queued messages and waits for the loop to exit.
+func (l *batchLoop) shutdown() {
+	l.mu.Lock()
+	l.stopped = true
+	l.mu.Unlock()
+	close(l.stop)
+	<-l.done
+}
+
+func (l *batchLoop) run() {
+	defer close(l.done)
+
+	var (
+		pending   []*mqs.Message
+		itemLimit int
+		timer     *time.Timer
+		stopping  bool
+	)
+	stopTimer := func() {
+		if timer != nil {
+			timer.Stop()
+			timer = nil
+		}
+	}
+	timeout := func() <-chan time.Time {
+		if timer == nil {
+			return nil
+		}
+		return timer.C
+	}
+	flush := func(trigger string) {
+		stopTimer()
+		if len(pending) > 0 {
+			l.flush(pending, trigger)
+		}
+		pending = nil
+	}
+	// add appends msg to the pending batch and reports whether it is full.
+	add := func(msg *mqs.Message) bool {
+		if len(pending) == 0 {
+			var delay time.Duration
+			itemLimit, delay = l.thresholds.get()
+			if !stopping {
+				timer = time.NewTimer(delay)
+			}
+		}
+		pending = append(pending, msg)
+		return len(pending) >= itemLimit
+	}
+
+	for {
+		select {
+		case msg := <-l.queue:
+			if add(msg) {
+				flush(flushTriggerSize)
+			}
+		case <-timeout():
+			timer = nil
+			flush(flushTriggerDelay)
+		case <-l.stop:
+			stopping = true
+			stopTimer()
+			for {
+				select {
+				case msg := <-l.queue:
+					if add(msg) {
+						flush(flushTriggerShutdown)
+					}
+				default:
+					flush(flushTriggerShutdown)
+					return
+				}
+			}
+		}
+	}
+}
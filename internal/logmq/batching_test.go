@@ -0,0 +1,157 @@
+package logmq_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logmq"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/mqs"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// slowLogStore takes delay to insert each batch.
+type slowLogStore struct {
+	mockLogStore
+	delay time.Duration
+}
+
+func (s *slowLogStore) InsertMany(ctx context.Context, entries []*models.LogEntry) error {
+	time.Sleep(s.delay)
+	return s.mockLogStore.InsertMany(ctx, entries)
+}
+
+func addEntries(t *testing.T, bp *logmq.BatchProcessor, n int) []*mockQueueMessage {
+	t.Helper()
+	mocks := make([]*mockQueueMessage, 0, n)
+	for range n {
+		event := testutil.EventFactory.Any()
+		attempt := testutil.AttemptFactory.Any()
+		mock, msg := newMockMessage(models.LogEntry{Event: &event, Attempt: &attempt})
+		require.NoError(t, bp.Add(context.Background(), msg))
+		mocks = append(mocks, mock)
+	}
+	return mocks
+}
+
+func allAcked(mocks []*mockQueueMessage) func() bool {
+	return func() bool {
+		for _, mock := range mocks {
+			if !mock.acked.Load() {
+				return false
+			}
+		}
+		return true
+	}
+}
+
+func thresholdReached(bp *logmq.BatchProcessor, want int) func() bool {
+	return func() bool {
+		items, _ := bp.Thresholds()
+		return items == want
+	}
+}
+
+func TestBatchProcessor_AdaptiveThresholds(t *testing.T) {
+	adaptive := func(slo time.Duration) *logmq.AdaptiveBatching {
+		return &logmq.AdaptiveBatching{
+			MinItems:   2,
+			MaxItems:   100,
+			MinDelay:   100 * time.Millisecond,
+			MaxDelay:   time.Minute,
+			LatencySLO: slo,
+		}
+	}
+
+	t.Run("grows when batches fill up", func(t *testing.T) {
+		bp, err := logmq.NewBatchProcessor(context.Background(), testutil.CreateTestLogger(t), &mockLogStore{}, testAlertPipeline(t, &mockAlertEvaluator{}), logmq.BatchProcessorConfig{
+			ItemCountThreshold: 4,
+			DelayThreshold:     time.Second,
+			Adaptive:           adaptive(time.Minute),
+		})
+		require.NoError(t, err)
+		defer bp.Shutdown()
+
+		addEntries(t, bp, 4)
+		require.Eventually(t, thresholdReached(bp, 5), time.Second, 10*time.Millisecond)
+		_, delay := bp.Thresholds()
+		assert.Equal(t, 1250*time.Millisecond, delay)
+	})
+
+	t.Run("shrinks when inserts are slower than the SLO", func(t *testing.T) {
+		logStore := &slowLogStore{delay: 50 * time.Millisecond}
+		bp, err := logmq.NewBatchProcessor(context.Background(), testutil.CreateTestLogger(t), logStore, testAlertPipeline(t, &mockAlertEvaluator{}), logmq.BatchProcessorConfig{
+			ItemCountThreshold: 8,
+			DelayThreshold:     time.Second,
+			Adaptive:           adaptive(10 * time.Millisecond),
+		})
+		require.NoError(t, err)
+		defer bp.Shutdown()
+
+		addEntries(t, bp, 8)
+		require.Eventually(t, thresholdReached(bp, 4), time.Second, 10*time.Millisecond)
+		_, delay := bp.Thresholds()
+		assert.Equal(t, 500*time.Millisecond, delay)
+	})
+
+	t.Run("stays within bounds", func(t *testing.T) {
+		bp, err := logmq.NewBatchProcessor(context.Background(), testutil.CreateTestLogger(t), &mockLogStore{}, testAlertPipeline(t, &mockAlertEvaluator{}), logmq.BatchProcessorConfig{
+			ItemCountThreshold: 1,
+			DelayThreshold:     10 * time.Millisecond,
+			Adaptive:           adaptive(time.Minute),
+		})
+		require.NoError(t, err)
+		defer bp.Shutdown()
+
+		items, delay := bp.Thresholds()
+		assert.Equal(t, 2, items)
+		assert.Equal(t, 100*time.Millisecond, delay)
+	})
+
+	t.Run("stays fixed without adaptive batching", func(t *testing.T) {
+		bp, err := logmq.NewBatchProcessor(context.Background(), testutil.CreateTestLogger(t), &mockLogStore{}, testAlertPipeline(t, &mockAlertEvaluator{}), logmq.BatchProcessorConfig{
+			ItemCountThreshold: 4,
+			DelayThreshold:     time.Second,
+		})
+		require.NoError(t, err)
+		defer bp.Shutdown()
+
+		require.Eventually(t, allAcked(addEntries(t, bp, 4)), time.Second, 10*time.Millisecond)
+		items, delay := bp.Thresholds()
+		assert.Equal(t, 4, items)
+		assert.Equal(t, time.Second, delay)
+	})
+
+	t.Run("rejects an empty range", func(t *testing.T) {
+		_, err := logmq.NewBatchProcessor(context.Background(), testutil.CreateTestLogger(t), &mockLogStore{}, testAlertPipeline(t, &mockAlertEvaluator{}), logmq.BatchProcessorConfig{
+			ItemCountThreshold: 4,
+			DelayThreshold:     time.Second,
+			Adaptive:           &logmq.AdaptiveBatching{MinItems: 10, MaxItems: 5, MinDelay: time.Second, MaxDelay: time.Second, LatencySLO: time.Second},
+		})
+		assert.Error(t, err)
+	})
+}
+
+func TestBatchProcessor_ShutdownFlushesPending(t *testing.T) {
+	logStore := &mockLogStore{}
+	bp, err := logmq.NewBatchProcessor(context.Background(), testutil.CreateTestLogger(t), logStore, testAlertPipeline(t, &mockAlertEvaluator{}), logmq.BatchProcessorConfig{
+		ItemCountThreshold: 100,
+		DelayThreshold:     time.Hour,
+	})
+	require.NoError(t, err)
+
+	mocks := addEntries(t, bp, 3)
+	bp.Shutdown()
+
+	assert.True(t, allAcked(mocks)(), "pending messages should be persisted on shutdown")
+	_, attempts := logStore.getInserted()
+	assert.Len(t, attempts, 3)
+
+	// Messages added after shutdown are redelivered.
+	mock := &mockQueueMessage{}
+	require.NoError(t, bp.Add(context.Background(), &mqs.Message{QueueMessage: mock, LoggableID: "late"}))
+	assert.True(t, mock.nacked.Load())
+}
@@ -13,7 +13,6 @@ import (
 	"github.com/hookdeck/outpost/internal/models"
 	"github.com/hookdeck/outpost/internal/mqs"
 	"github.com/hookdeck/outpost/internal/opevents"
-	"github.com/mikestefanello/batcher"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
 )
@@ -47,9 +46,21 @@ type AlertEvaluator interface {
 }
 
 // DestinationDisabler disables destinations that hit the auto-disable
-// threshold.
+// threshold, as of disabledAt.
 type DestinationDisabler interface {
-	DisableDestination(ctx context.Context, tenantID, destinationID string) error
+	DisableDestination(ctx context.Context, tenantID, destinationID string, disabledAt time.Time) error
+}
+
+// ProbationScheduler puts an auto-disabled destination on probation, after
+// which a successful test delivery re-enables it.
+type ProbationScheduler interface {
+	Schedule(ctx context.Context, tenantID, destinationID string, disabledAt time.Time, probation time.Duration) error
+}
+
+// TenantGetter looks up the tenant an alert belongs to, for its notification
+// preferences.
+type TenantGetter interface {
+	RetrieveTenant(ctx context.Context, tenantID string) (*models.Tenant, error)
 }
 
 // ReplayGate is the split-phase idempotence pair the pipeline uses as the
@@ -79,9 +90,16 @@ type AlertPipeline struct {
 	Evaluator AlertEvaluator
 	// Emitter delivers the operator events. Required.
 	Emitter opevents.Emitter
-	// Disabler auto-disables a destination when the 100% threshold is crossed.
-	// Nil disables auto-disable.
+	// Disabler auto-disables a destination when the 100% threshold is crossed
+	// and its policy auto-disables. Nil disables auto-disable.
 	Disabler DestinationDisabler
+	// Policy is the deployment's consecutive-failure policy. A tenant's
+	// AutoDisablePolicy overrides it for the tenant's destinations, looked up
+	// through Tenants.
+	Policy alert.Policy
+	// Probation puts destinations auto-disabled under a policy with a
+	// probation period on probation. Nil means they stay disabled.
+	Probation ProbationScheduler
 	// ProcessedIdemp is the per-attempt replay gate: a replay of a fully
 	// processed failed attempt is skipped instead of re-counting/re-alerting.
 	// Required.
@@ -91,12 +109,38 @@ type AlertPipeline struct {
 	// window, regardless of which events exhaust. Nil means no suppression
 	// (alert on every exhaustion).
 	ExhaustedIdemp SuppressionWindow
+	// Tenants resolves the tenant's auto-disable policy and notification
+	// preferences, attached to the alert events the tenant opted into. Nil
+	// means every tenant follows Policy and alerts carry no contact.
+	Tenants TenantGetter
+}
+
+// LifecycleNotifier is told about every persisted attempt, to notify the
+// producer when a delivery reaches a terminal status.
+type LifecycleNotifier interface {
+	AttemptLogged(entry *models.LogEntry)
+}
+
+// Journal is told about every persisted batch, so its attempts aren't
+// backfilled.
+type Journal interface {
+	Persisted(ctx context.Context, entries []*models.LogEntry) error
 }
 
 // BatchProcessorConfig configures the batch processor.
 type BatchProcessorConfig struct {
 	ItemCountThreshold int
 	DelayThreshold     time.Duration
+	// Adaptive lets the thresholds adapt to the load within its bounds,
+	// starting from ItemCountThreshold and DelayThreshold. Nil keeps them
+	// fixed.
+	Adaptive *AdaptiveBatching
+	// Lifecycle receives each persisted attempt. Nil disables lifecycle
+	// notifications.
+	Lifecycle LifecycleNotifier
+	// Journal is told about each persisted batch. Nil disables the delivery
+	// journal.
+	Journal Journal
 	// EmitTimeout is a test-only override for the per-send timeout; zero means
 	// the emitTimeout default. Production always runs the default.
 	EmitTimeout time.Duration
@@ -117,7 +161,12 @@ type BatchProcessor struct {
 	logger      *logging.Logger
 	logStore    LogStore
 	alerts      AlertPipeline
-	batcher     *batcher.Batcher[*mqs.Message]
+	lifecycle   LifecycleNotifier
+	journal     Journal
+	loop        *batchLoop
+	thresholds  *batchThresholds
+	adaptive    *AdaptiveBatching
+	metrics     *batchMetrics
 	emitTimeout time.Duration
 	// alertsEnabled and emitsAttemptEvents are derived once from the pipeline's
 	// static config. alertsEnabled=false skips the replay gate on the failed
@@ -141,11 +190,23 @@ func NewBatchProcessor(ctx context.Context, logger *logging.Logger, logStore Log
 	if alerts.ProcessedIdemp == nil {
 		return nil, errors.New("logmq: AlertPipeline requires a ProcessedIdemp")
 	}
+	if cfg.ItemCountThreshold <= 0 || cfg.DelayThreshold <= 0 {
+		return nil, errors.New("logmq: ItemCountThreshold and DelayThreshold must be positive")
+	}
+	if a := cfg.Adaptive; a != nil && (a.MinItems <= 0 || a.MinItems > a.MaxItems ||
+		a.MinDelay <= 0 || a.MinDelay > a.MaxDelay || a.LatencySLO <= 0) {
+		return nil, errors.New("logmq: Adaptive requires positive minimums not above their maximums, and a positive LatencySLO")
+	}
 	bp := &BatchProcessor{
 		ctx:         ctx,
 		logger:      logger,
 		logStore:    logStore,
 		alerts:      alerts,
+		lifecycle:   cfg.Lifecycle,
+		journal:     cfg.Journal,
+		thresholds:  newBatchThresholds(cfg.ItemCountThreshold, cfg.DelayThreshold, cfg.Adaptive),
+		adaptive:    cfg.Adaptive,
+		metrics:     newBatchMetrics(),
 		emitTimeout: cfg.EmitTimeout,
 	}
 	if bp.emitTimeout <= 0 {
@@ -155,41 +216,61 @@ func NewBatchProcessor(ctx context.Context, logger *logging.Logger, logStore Log
 	bp.emitsAttemptEvents = alerts.Emitter.Enabled(opevents.TopicAttemptSuccess) ||
 		alerts.Emitter.Enabled(opevents.TopicAttemptFailed)
 
-	b, err := batcher.NewBatcher(batcher.Config[*mqs.Message]{
-		GroupCountThreshold: 2,
-		ItemCountThreshold:  cfg.ItemCountThreshold,
-		DelayThreshold:      cfg.DelayThreshold,
-		NumGoroutines:       1,
-		Processor:           bp.processBatch,
-	})
-	if err != nil {
-		return nil, err
-	}
-
-	bp.batcher = b
+	items, delay := bp.thresholds.get()
+	bp.metrics.recordThresholds(ctx, items, delay)
+	bp.loop = newBatchLoop(bp.thresholds, bp.flush)
 	return bp, nil
 }
 
 // Add adds a message to the batch.
 func (bp *BatchProcessor) Add(ctx context.Context, msg *mqs.Message) error {
-	bp.batcher.Add("", msg)
+	bp.loop.add(msg)
 	return nil
 }
 
-// Shutdown gracefully shuts down the batch processor: the batcher first
+// Thresholds returns the current item and delay thresholds a batch is
+// flushed at.
+func (bp *BatchProcessor) Thresholds() (int, time.Duration) {
+	return bp.thresholds.get()
+}
+
+// Shutdown gracefully shuts down the batch processor: the batch loop first
 // (flushes pending batches, which may still dispatch entry goroutines), then
 // the in-flight entries drain. Every dispatched message reaches a terminal
 // state before Shutdown returns, and the drain is bounded by emitTimeout.
 // Idempotent.
 func (bp *BatchProcessor) Shutdown() {
 	bp.shutdownOnce.Do(func() {
-		bp.batcher.Shutdown()
+		bp.loop.shutdown()
 		bp.inflight.Wait()
 	})
 }
 
-// processBatch processes a batch of messages.
-func (bp *BatchProcessor) processBatch(_ string, msgs []*mqs.Message) {
+// flush processes a batch of messages, records its metrics and adapts the
+// thresholds to it.
+func (bp *BatchProcessor) flush(msgs []*mqs.Message, trigger string) {
+	itemThreshold, _ := bp.thresholds.get()
+	bp.metrics.recordBatch(bp.ctx, trigger, len(msgs), itemThreshold)
+
+	insertDuration, inserted := bp.processBatch(msgs)
+	if !inserted {
+		return
+	}
+	bp.metrics.recordInsert(bp.ctx, insertDuration, bp.adaptive)
+	if bp.thresholds.observe(trigger, insertDuration) {
+		items, delay := bp.thresholds.get()
+		bp.metrics.recordThresholds(bp.ctx, items, delay)
+		bp.logger.Ctx(bp.ctx).Debug("log batch thresholds adapted",
+			zap.String("trigger", trigger),
+			zap.Int64("insert_duration_ms", insertDuration.Milliseconds()),
+			zap.Int("item_threshold", items),
+			zap.Int64("delay_threshold_ms", delay.Milliseconds()))
+	}
+}
+
+// processBatch processes a batch of messages. It returns how long the insert
+// took, and false when no insert was attempted.
+func (bp *BatchProcessor) processBatch(msgs []*mqs.Message) (time.Duration, bool) {
 	logger := bp.logger.Ctx(bp.ctx)
 	logger.Debug("processing batch", zap.Int("message_count", len(msgs)))
 
@@ -256,27 +337,39 @@ func (bp *BatchProcessor) processBatch(_ string, msgs []*mqs.Message) {
 
 	// Nothing valid to insert
 	if len(entries) == 0 {
-		return
+		return 0, false
 	}
 
 	insertCtx, cancel := context.WithTimeout(bp.ctx, 30*time.Second)
 	defer cancel()
 
 	insertStart := time.Now()
-	if err := bp.logStore.InsertMany(insertCtx, entries); err != nil {
+	err := bp.logStore.InsertMany(insertCtx, entries)
+	insertDuration := time.Since(insertStart)
+	if err != nil {
 		logger.Error("failed to insert log entries",
 			zap.Error(err),
 			zap.Int("entry_count", len(entries)),
-			zap.Int64("insert_duration_ms", time.Since(insertStart).Milliseconds()))
+			zap.Int64("insert_duration_ms", insertDuration.Milliseconds()))
 		for _, msg := range validMsgs {
 			msg.Nack()
 		}
-		return
+		return insertDuration, true
 	}
 
 	logger.Info("batch persisted",
 		zap.Int("count", len(validMsgs)),
-		zap.Int64("insert_duration_ms", time.Since(insertStart).Milliseconds()))
+		zap.Int64("insert_duration_ms", insertDuration.Milliseconds()))
+
+	// An attempt left in the journal is only backfilled again, which the log
+	// store tolerates.
+	if bp.journal != nil {
+		if err := bp.journal.Persisted(insertCtx, entries); err != nil {
+			logger.Warn("failed to remove persisted attempts from the delivery journal",
+				zap.Error(err),
+				zap.Int("entry_count", len(entries)))
+		}
+	}
 
 	// Spawn one goroutine per persisted entry and return — the batch loop
 	// never waits on eval or delivery. In-flight goroutines are bounded by
@@ -284,6 +377,10 @@ func (bp *BatchProcessor) processBatch(_ string, msgs []*mqs.Message) {
 	// and every fetched message reaches ack/nack well inside the broker's
 	// visibility window.
 	for i, entry := range entries {
+		if bp.lifecycle != nil {
+			bp.lifecycle.AttemptLogged(entry)
+		}
+
 		// A pipeline that can't produce anything (every alert signal off and
 		// no attempt topic subscribed): persisted is terminal.
 		if !bp.alertsEnabled && !bp.emitsAttemptEvents {
@@ -305,6 +402,8 @@ func (bp *BatchProcessor) processBatch(_ string, msgs []*mqs.Message) {
 			bp.processEntry(bp.ctx, entry, msg)
 		})
 	}
+
+	return insertDuration, true
 }
 
 // processEntry runs the alert pipeline for one persisted entry and owns the
@@ -324,6 +423,14 @@ func (bp *BatchProcessor) processBatch(_ string, msgs []*mqs.Message) {
 // alert state and alert-event dedup only, so when every signal is disabled the
 // failed path skips it too and just emits attempt.failed.
 func (bp *BatchProcessor) processEntry(ctx context.Context, entry *models.LogEntry, msg *mqs.Message) {
+	// A deferred attempt is neither outcome: the endpoint asked for the event
+	// again later. It must not reset or extend the failure streak, and there
+	// is no attempt event for it.
+	if entry.Attempt.Status == models.AttemptStatusDeferred {
+		msg.Ack()
+		return
+	}
+
 	attempt := alert.Attempt{
 		TenantID:         entry.Destination.TenantID,
 		DestinationID:    entry.Destination.ID,
@@ -332,6 +439,9 @@ func (bp *BatchProcessor) processEntry(ctx context.Context, entry *models.LogEnt
 		Success:          entry.Attempt.Status == models.AttemptStatusSuccess,
 		EligibleForRetry: entry.Event.EligibleForRetry,
 	}
+	if limit, ok := entry.Destination.RetryPolicy.RetryLimit(); ok {
+		attempt.RetryLimit = &limit
+	}
 
 	if attempt.Success {
 		if _, err := bp.alerts.Evaluator.Evaluate(ctx, attempt); err != nil {
@@ -376,13 +486,28 @@ func (bp *BatchProcessor) processEntry(ctx context.Context, entry *models.LogEnt
 		return
 	}
 
+	// The tenant's auto-disable policy decides what the attempt counts
+	// toward, so it's looked up before eval.
+	tenant := bp.tenant(ctx, attempt.TenantID)
+	policy := bp.alerts.Policy
+	if tenant != nil && tenant.AutoDisablePolicy != nil {
+		override := tenant.AutoDisablePolicy
+		policy = policy.Override(override)
+		if override.FailureCount != nil {
+			attempt.FailureCount = &policy.FailureCount
+		}
+		if override.WindowSeconds != nil {
+			attempt.FailureWindow = &policy.Window
+		}
+	}
+
 	eval, err := bp.alerts.Evaluator.Evaluate(ctx, attempt)
 	if err != nil {
 		bp.nackAlertFailure(ctx, err, entry, msg)
 		return
 	}
 
-	events, err := bp.plan(ctx, eval, entry)
+	events, err := bp.plan(ctx, eval, entry, tenant, policy)
 	if err != nil {
 		bp.nackAlertFailure(ctx, err, entry, msg)
 		return
@@ -445,20 +570,27 @@ func (bp *BatchProcessor) sendAll(ctx context.Context, events []deliveryEvent, e
 // action, not a notification, and it must precede event construction so the
 // payloads carry the destination's latest state (disabled) — attempt.failed
 // included, since they share the projection.
-func (bp *BatchProcessor) plan(ctx context.Context, eval alert.Evaluation, entry *models.LogEntry) ([]deliveryEvent, error) {
+func (bp *BatchProcessor) plan(ctx context.Context, eval alert.Evaluation, entry *models.LogEntry, tenant *models.Tenant, policy alert.Policy) ([]deliveryEvent, error) {
 	dest := opevents.NewAlertDestination(entry.Destination)
 	var events []deliveryEvent
 
 	if cf := eval.ConsecutiveFailure; cf != nil {
-		if cf.Level == 100 && bp.alerts.Disabler != nil {
+		if cf.Level == 100 && policy.AutoDisable && bp.alerts.Disabler != nil {
 			// Disable converges on replay: re-disabling rewrites DisabledAt,
 			// but the end state is the same.
-			if err := bp.alerts.Disabler.DisableDestination(ctx, dest.TenantID, dest.ID); err != nil {
+			now := time.Now()
+			if err := bp.alerts.Disabler.DisableDestination(ctx, dest.TenantID, dest.ID, now); err != nil {
 				return nil, fmt.Errorf("failed to disable destination: %w", err)
 			}
+			// So does probation: the rewritten DisabledAt supersedes the
+			// replayed one's.
+			if policy.Probation > 0 && bp.alerts.Probation != nil {
+				if err := bp.alerts.Probation.Schedule(ctx, dest.TenantID, dest.ID, now, policy.Probation); err != nil {
+					return nil, fmt.Errorf("failed to put destination on probation: %w", err)
+				}
+			}
 
 			// The payload carries the destination's latest state: disabled.
-			now := time.Now()
 			dest.DisabledAt = &now
 
 			bp.logger.Ctx(ctx).Audit("destination disabled",
@@ -489,6 +621,12 @@ func (bp *BatchProcessor) plan(ctx context.Context, eval alert.Evaluation, entry
 		events = append(events, de)
 	}
 
+	if len(events) > 0 && tenant != nil && tenant.Notifications != nil {
+		for i := range events {
+			events[i].event = opevents.WithTenantNotifications(events[i].event, tenant.Notifications)
+		}
+	}
+
 	events = append(events, deliveryEvent{
 		event: opevents.AttemptFailedEvent(dest, entry.Event, entry.Attempt),
 	})
@@ -496,6 +634,24 @@ func (bp *BatchProcessor) plan(ctx context.Context, eval alert.Evaluation, entry
 	return events, nil
 }
 
+// tenant returns the tenant, for its auto-disable policy and notification
+// preferences, or nil when it can't be looked up. A lookup failure is logged
+// and the attempt follows the deployment's policy, its alerts going out
+// without a contact, rather than being held back.
+func (bp *BatchProcessor) tenant(ctx context.Context, tenantID string) *models.Tenant {
+	if bp.alerts.Tenants == nil {
+		return nil
+	}
+	tenant, err := bp.alerts.Tenants.RetrieveTenant(ctx, tenantID)
+	if err != nil {
+		bp.logger.Ctx(ctx).Warn("failed to retrieve tenant",
+			zap.Error(err),
+			zap.String("tenant_id", tenantID))
+		return nil
+	}
+	return tenant
+}
+
 // send emits one event, inside the event's suppression window when it has
 // one. A suppressed duplicate (Exec skips the emit) counts as delivered. The
 // emitter owns the delivery audit log — it fires iff an event actually went
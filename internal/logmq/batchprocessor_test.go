@@ -452,3 +452,124 @@ func TestBatchProcessor_AlertEvaluator_Error(t *testing.T) {
 	events, _ := logStore.getInserted()
 	assert.Len(t, events, 1, "log entry should still be persisted despite alert failure")
 }
+
+func TestBatchProcessor_DeferredAttempt_SkipsAlertEvaluation(t *testing.T) {
+	ctx := context.Background()
+	logger := testutil.CreateTestLogger(t)
+	logStore := &mockLogStore{}
+	alertMon := &mockAlertEvaluator{}
+
+	bp, err := logmq.NewBatchProcessor(ctx, logger, logStore, testAlertPipeline(t, alertMon), logmq.BatchProcessorConfig{
+		ItemCountThreshold: 1,
+		DelayThreshold:     1 * time.Second,
+	})
+	require.NoError(t, err)
+	defer bp.Shutdown()
+
+	event := testutil.EventFactory.Any()
+	attempt := testutil.AttemptFactory.Any(testutil.AttemptFactory.WithStatus(models.AttemptStatusDeferred))
+	dest := testutil.DestinationFactory.Any()
+	entry := models.LogEntry{
+		Event:       &event,
+		Attempt:     &attempt,
+		Destination: &dest,
+	}
+
+	mock, msg := newMockMessage(entry)
+	require.NoError(t, bp.Add(ctx, msg))
+
+	time.Sleep(200 * time.Millisecond)
+
+	assert.True(t, mock.acked.Load())
+	assert.False(t, mock.nacked.Load())
+	assert.Empty(t, alertMon.getCalls(), "deferred attempts should not count toward alerts")
+
+	_, attempts := logStore.getInserted()
+	require.Len(t, attempts, 1, "deferred attempt should still be logged")
+	assert.Equal(t, models.AttemptStatusDeferred, attempts[0].Status)
+}
+
+type mockLifecycleNotifier struct {
+	mu      sync.Mutex
+	entries []*models.LogEntry
+}
+
+func (m *mockLifecycleNotifier) AttemptLogged(entry *models.LogEntry) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries = append(m.entries, entry)
+}
+
+func (m *mockLifecycleNotifier) getEntries() []*models.LogEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]*models.LogEntry{}, m.entries...)
+}
+
+func TestBatchProcessor_LifecycleNotifier(t *testing.T) {
+	ctx := context.Background()
+	logger := testutil.CreateTestLogger(t)
+	logStore := &mockLogStore{}
+	notifier := &mockLifecycleNotifier{}
+
+	bp, err := logmq.NewBatchProcessor(ctx, logger, logStore, testAlertPipeline(t, &mockAlertEvaluator{}), logmq.BatchProcessorConfig{
+		ItemCountThreshold: 1,
+		DelayThreshold:     1 * time.Second,
+		Lifecycle:          notifier,
+	})
+	require.NoError(t, err)
+	defer bp.Shutdown()
+
+	event := testutil.EventFactory.Any()
+	attempt := testutil.AttemptFactory.Any()
+	mock, msg := newMockMessage(models.LogEntry{Event: &event, Attempt: &attempt})
+	require.NoError(t, bp.Add(ctx, msg))
+
+	require.Eventually(t, mock.acked.Load, time.Second, 10*time.Millisecond)
+	entries := notifier.getEntries()
+	require.Len(t, entries, 1, "persisted attempt should reach the lifecycle notifier")
+	assert.Equal(t, attempt.ID, entries[0].Attempt.ID)
+}
+
+type mockJournal struct {
+	mu         sync.Mutex
+	attemptIDs []string
+}
+
+func (m *mockJournal) Persisted(_ context.Context, entries []*models.LogEntry) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, entry := range entries {
+		m.attemptIDs = append(m.attemptIDs, entry.Attempt.ID)
+	}
+	return nil
+}
+
+func (m *mockJournal) getAttemptIDs() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string{}, m.attemptIDs...)
+}
+
+func TestBatchProcessor_Journal(t *testing.T) {
+	ctx := context.Background()
+	logger := testutil.CreateTestLogger(t)
+	logStore := &mockLogStore{}
+	journal := &mockJournal{}
+
+	bp, err := logmq.NewBatchProcessor(ctx, logger, logStore, testAlertPipeline(t, &mockAlertEvaluator{}), logmq.BatchProcessorConfig{
+		ItemCountThreshold: 1,
+		DelayThreshold:     1 * time.Second,
+		Journal:            journal,
+	})
+	require.NoError(t, err)
+	defer bp.Shutdown()
+
+	event := testutil.EventFactory.Any()
+	attempt := testutil.AttemptFactory.Any()
+	mock, msg := newMockMessage(models.LogEntry{Event: &event, Attempt: &attempt})
+	require.NoError(t, bp.Add(ctx, msg))
+
+	require.Eventually(t, mock.acked.Load, time.Second, 10*time.Millisecond)
+	assert.Equal(t, []string{attempt.ID}, journal.getAttemptIDs(), "persisted attempts should leave the journal")
+}
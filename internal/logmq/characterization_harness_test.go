@@ -57,6 +57,7 @@ type sinkRecord struct {
 	topic     string
 	destID    string
 	attemptID string
+	notify    string // email of the attached tenant contact, if any
 }
 
 // recordingSink implements opevents.Sink. It records each emitted event, can
@@ -100,6 +101,9 @@ func (s *recordingSink) Send(ctx context.Context, event *opevents.OperatorEvent)
 		Attempt struct {
 			ID string `json:"id"`
 		} `json:"attempt"`
+		Notify struct {
+			Email string `json:"email"`
+		} `json:"notify"`
 	}
 	_ = json.Unmarshal(event.Data, &payload)
 	destID := payload.Destination.ID
@@ -121,7 +125,7 @@ func (s *recordingSink) Send(ctx context.Context, event *opevents.OperatorEvent)
 	if s.failOn[attemptID] || s.failOn[event.Topic] || s.failOn[attemptID+"/"+event.Topic] {
 		return fmt.Errorf("injected send failure topic=%s attempt=%s", event.Topic, attemptID)
 	}
-	s.records = append(s.records, sinkRecord{topic: event.Topic, destID: destID, attemptID: attemptID})
+	s.records = append(s.records, sinkRecord{topic: event.Topic, destID: destID, attemptID: attemptID, notify: payload.Notify.Email})
 	return nil
 }
 
@@ -193,7 +197,7 @@ type recordingDisabler struct {
 	disabled []disableRecord
 }
 
-func (d *recordingDisabler) DisableDestination(ctx context.Context, tenantID, destinationID string) error {
+func (d *recordingDisabler) DisableDestination(ctx context.Context, tenantID, destinationID string, disabledAt time.Time) error {
 	d.mu.Lock()
 	defer d.mu.Unlock()
 	d.disabled = append(d.disabled, disableRecord{tenantID: tenantID, destinationID: destinationID})
@@ -278,7 +282,10 @@ type alertConfig struct {
 	autoDisableCount int
 	retryMaxLimit    int
 	withDisabler     bool // attach the recordingDisabler to the pipeline
-	signalsOff       bool // disable both evaluator signals (cf + exhausted)
+	// probation is the deployment's probation period after an auto-disable;
+	// zero = none.
+	probation  time.Duration
+	signalsOff bool // disable both evaluator signals (cf + exhausted)
 	// opeventTopics is the real emitter's subscription; nil = all ("*").
 	// Non-nil without attempt topics exercises the disabled-path early-outs.
 	opeventTopics []string
@@ -288,11 +295,13 @@ type alertConfig struct {
 // sink injects no failures, blocks nothing, and the harness uses a real
 // memlogstore.
 type doublesConfig struct {
-	sinkFailOn  map[string]bool         // make sink.Send fail for these attemptIDs/topics
-	sinkBlockOn map[string]bool         // block sink.Send for these attemptIDs/topics until h.sink.release()
-	evalBlockOn map[string]bool         // block Evaluate for these attemptIDs until h.eval.release()
-	logStore    logmq.LogStore          // override the store (e.g. failingLogStore); nil = memlogstore
-	idemp       idempotence.Idempotence // exhausted-retries suppression; nil = unsuppressed
+	sinkFailOn  map[string]bool          // make sink.Send fail for these attemptIDs/topics
+	sinkBlockOn map[string]bool          // block sink.Send for these attemptIDs/topics until h.sink.release()
+	evalBlockOn map[string]bool          // block Evaluate for these attemptIDs until h.eval.release()
+	logStore    logmq.LogStore           // override the store (e.g. failingLogStore); nil = memlogstore
+	idemp       idempotence.Idempotence  // exhausted-retries suppression; nil = unsuppressed
+	tenants     logmq.TenantGetter       // tenant policy and preferences lookup; nil = none
+	probation   logmq.ProbationScheduler // probation scheduling; nil = none
 	// failMarkProcessed makes every MarkProcessed call on the replay gate
 	// error (the Processed check still works). Simulates Redis failing after
 	// the attempt's events were delivered.
@@ -401,9 +410,13 @@ func newHarness(t *testing.T, cfg harnessConfig) *harness {
 		Emitter:        emitter,
 		ProcessedIdemp: gate,
 		ExhaustedIdemp: cfg.doubles.idemp,
+		Tenants:        cfg.doubles.tenants,
+		Probation:      cfg.doubles.probation,
 	}
+	pipeline.Policy.Probation = cfg.alert.probation
 	if cfg.alert.withDisabler {
 		pipeline.Disabler = disabler
+		pipeline.Policy.AutoDisable = true
 	}
 	bp, err := logmq.NewBatchProcessor(ctx, logger, logStore, pipeline, logmq.BatchProcessorConfig{
 		ItemCountThreshold: cfg.batcher.itemCount,
@@ -0,0 +1,84 @@
+package logmq_test
+
+// Tenant notification preferences: alert events carry the tenant's contact
+// for the categories the tenant opted into, so operator sinks can route them.
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+type stubTenantGetter struct {
+	tenants map[string]*models.Tenant
+	err     error
+}
+
+func (g *stubTenantGetter) RetrieveTenant(ctx context.Context, tenantID string) (*models.Tenant, error) {
+	if g.err != nil {
+		return nil, g.err
+	}
+	return g.tenants[tenantID], nil
+}
+
+func notifyEmails(recs []sinkRecord) []string {
+	out := make([]string, len(recs))
+	for i, r := range recs {
+		out[i] = r.notify
+	}
+	return out
+}
+
+// Only alerts in an opted-in category carry the contact; attempt.failed never
+// does.
+func TestNotifications_AttachedToOptedInAlerts(t *testing.T) {
+	t.Parallel()
+	dest, tenant := "dest_nt1", "tenant_nt1"
+	tenants := &stubTenantGetter{tenants: map[string]*models.Tenant{
+		tenant: {ID: tenant, Notifications: &models.NotificationPreferences{
+			Email:      "ops@example.com",
+			Categories: []string{models.NotificationCategoryDisables},
+		}},
+	}}
+	h := newHarness(t, harnessConfig{
+		batcher: batcherConfig{itemCount: 1},
+		alert:   alertConfig{autoDisableCount: 2, thresholds: []int{50, 100}, withDisabler: true},
+		doubles: doublesConfig{tenants: tenants},
+	})
+
+	cm1, msg1 := newCountingMessage(makeEntry(dest, tenant, "att_nt_1", models.AttemptStatusFailed))
+	h.add(msg1)
+	h.waitTerminal([]*countingMessage{cm1})
+	cm2, msg2 := newCountingMessage(makeEntry(dest, tenant, "att_nt_2", models.AttemptStatusFailed))
+	h.add(msg2)
+	h.waitTerminal([]*countingMessage{cm2})
+
+	recs := h.sink.forDest(dest)
+	assert.Equal(t, []string{"ops@example.com"}, notifyEmails(forTopic(recs, topicDisabled)))
+	assert.Equal(t, []string{"", ""}, notifyEmails(forTopic(recs, topicCF)), "failures not opted into")
+	assert.Equal(t, []string{"", ""}, notifyEmails(forTopic(recs, topicFailed)))
+}
+
+// A failed tenant lookup does not hold back the alert: it goes out without a
+// contact and the message acks.
+func TestNotifications_LookupFailureStillAlerts(t *testing.T) {
+	t.Parallel()
+	h := newHarness(t, harnessConfig{
+		batcher: batcherConfig{itemCount: 1},
+		alert:   alertConfig{autoDisableCount: 2, thresholds: []int{50, 100}},
+		doubles: doublesConfig{tenants: &stubTenantGetter{err: errors.New("redis down")}},
+	})
+
+	dest, tenant := "dest_nt2", "tenant_nt2"
+	cm, msg := newCountingMessage(makeEntry(dest, tenant, "att_nt_3", models.AttemptStatusFailed))
+	h.add(msg)
+	h.waitTerminal([]*countingMessage{cm})
+
+	cm.requireAcked(t)
+	recs := h.sink.forDest(dest)
+	assert.ElementsMatch(t, []string{topicCF, topicFailed}, topics(recs))
+	assert.Equal(t, []string{""}, notifyEmails(forTopic(recs, topicCF)))
+}
@@ -3,8 +3,10 @@ package logretention
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"github.com/hookdeck/outpost/internal/clickhouse"
+	"github.com/hookdeck/outpost/internal/models"
 )
 
 // ClickHouseExecer is a minimal interface for ClickHouse operations needed by ClickHouseTTL.
@@ -40,32 +42,64 @@ func newClickHouseTTLWithExecer(conn ClickHouseExecer, deploymentID string) *Cli
 }
 
 // ApplyTTL modifies the TTL on ClickHouse tables.
-// If ttlDays is 0, the TTL is removed.
-func (c *ClickHouseTTL) ApplyTTL(ctx context.Context, ttlDays int) error {
+// Statuses retained for 0 days get no TTL; a zero policy removes the TTL.
+func (c *ClickHouseTTL) ApplyTTL(ctx context.Context, policy Policy) error {
 	// Apply TTL to events table
-	if err := c.alterTableTTL(ctx, c.eventsTable, "event_time", ttlDays); err != nil {
+	if err := c.alterTableTTL(ctx, c.eventsTable, ttlExpr("event_time", policy.EventDays())); err != nil {
 		return fmt.Errorf("failed to alter TTL on events table: %w", err)
 	}
 
 	// Apply TTL to attempts table
-	if err := c.alterTableTTL(ctx, c.attemptsTable, "attempt_time", ttlDays); err != nil {
+	if err := c.alterTableTTL(ctx, c.attemptsTable, attemptsTTLExpr(policy)); err != nil {
 		return fmt.Errorf("failed to alter TTL on attempts table: %w", err)
 	}
 
 	return nil
 }
 
-// alterTableTTL modifies the TTL on a single ClickHouse table.
+// alterTableTTL modifies the TTL on a single ClickHouse table. An empty
+// expression removes the TTL.
 // Table/column names are interpolated via fmt.Sprintf because ClickHouse doesn't support
 // parameterized identifiers in DDL. All values are derived from operator config (deploymentID)
 // and hardcoded column names — never from user input.
-func (c *ClickHouseTTL) alterTableTTL(ctx context.Context, tableName, timeColumn string, ttlDays int) error {
+func (c *ClickHouseTTL) alterTableTTL(ctx context.Context, tableName, expr string) error {
 	var query string
-	if ttlDays == 0 {
+	if expr == "" {
 		query = fmt.Sprintf("ALTER TABLE %s REMOVE TTL", tableName)
 	} else {
-		query = fmt.Sprintf("ALTER TABLE %s MODIFY TTL %s + INTERVAL %d DAY", tableName, timeColumn, ttlDays)
+		query = fmt.Sprintf("ALTER TABLE %s MODIFY TTL %s", tableName, expr)
 	}
 
 	return c.conn.Exec(ctx, query)
 }
+
+// ttlExpr returns a TTL deleting rows ttlDays after timeColumn, or "" for no TTL.
+func ttlExpr(timeColumn string, ttlDays int) string {
+	if ttlDays == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s + INTERVAL %d DAY", timeColumn, ttlDays)
+}
+
+// attemptsTTLExpr returns the attempts TTL for a policy: a single rule when
+// uniform, otherwise one conditional DELETE rule per retained status.
+func attemptsTTLExpr(policy Policy) string {
+	if policy.IsUniform() {
+		return ttlExpr("attempt_time", policy.SuccessDays)
+	}
+	var rules []string
+	for _, rule := range []struct {
+		status string
+		days   int
+	}{
+		{models.AttemptStatusSuccess, policy.SuccessDays},
+		{models.AttemptStatusFailed, policy.FailedDays},
+		{models.AttemptStatusDeferred, policy.EventDays()},
+	} {
+		if rule.days == 0 {
+			continue
+		}
+		rules = append(rules, fmt.Sprintf("%s DELETE WHERE status = '%s'", ttlExpr("attempt_time", rule.days), rule.status))
+	}
+	return strings.Join(rules, ", ")
+}
@@ -35,7 +35,7 @@ func TestClickHouseTTL_ApplyTTL(t *testing.T) {
 	tests := []struct {
 		name            string
 		deploymentID    string
-		ttlDays         int
+		policy          Policy
 		wantQueries     []string
 		wantQueryCount  int
 		execErr         error
@@ -46,7 +46,7 @@ func TestClickHouseTTL_ApplyTTL(t *testing.T) {
 		{
 			name:         "set TTL - no deployment",
 			deploymentID: "",
-			ttlDays:      30,
+			policy:       Uniform(30),
 			wantQueries: []string{
 				"ALTER TABLE events MODIFY TTL event_time + INTERVAL 30 DAY",
 				"ALTER TABLE attempts MODIFY TTL attempt_time + INTERVAL 30 DAY",
@@ -56,7 +56,7 @@ func TestClickHouseTTL_ApplyTTL(t *testing.T) {
 		{
 			name:         "set TTL - with deployment",
 			deploymentID: "dpm_001",
-			ttlDays:      7,
+			policy:       Uniform(7),
 			wantQueries: []string{
 				"ALTER TABLE dpm_001_events MODIFY TTL event_time + INTERVAL 7 DAY",
 				"ALTER TABLE dpm_001_attempts MODIFY TTL attempt_time + INTERVAL 7 DAY",
@@ -66,17 +66,37 @@ func TestClickHouseTTL_ApplyTTL(t *testing.T) {
 		{
 			name:         "remove TTL - set to 0",
 			deploymentID: "",
-			ttlDays:      0,
+			policy:       Uniform(0),
 			wantQueries: []string{
 				"ALTER TABLE events REMOVE TTL",
 				"ALTER TABLE attempts REMOVE TTL",
 			},
 			wantQueryCount: 2,
 		},
+		{
+			name:         "per-status TTL",
+			deploymentID: "",
+			policy:       Policy{SuccessDays: 14, FailedDays: 90},
+			wantQueries: []string{
+				"ALTER TABLE events MODIFY TTL event_time + INTERVAL 90 DAY",
+				"ALTER TABLE attempts MODIFY TTL attempt_time + INTERVAL 14 DAY DELETE WHERE status = 'success', attempt_time + INTERVAL 90 DAY DELETE WHERE status = 'failed', attempt_time + INTERVAL 90 DAY DELETE WHERE status = 'deferred'",
+			},
+			wantQueryCount: 2,
+		},
+		{
+			name:         "per-status TTL - failures kept forever",
+			deploymentID: "",
+			policy:       Policy{SuccessDays: 14},
+			wantQueries: []string{
+				"ALTER TABLE events REMOVE TTL",
+				"ALTER TABLE attempts MODIFY TTL attempt_time + INTERVAL 14 DAY DELETE WHERE status = 'success'",
+			},
+			wantQueryCount: 2,
+		},
 		{
 			name:            "events table fails - stops before attempts",
 			deploymentID:    "",
-			ttlDays:         30,
+			policy:          Uniform(30),
 			execErr:         errors.New("table not found"),
 			failOnTable:     "events",
 			wantErr:         true,
@@ -86,7 +106,7 @@ func TestClickHouseTTL_ApplyTTL(t *testing.T) {
 		{
 			name:            "attempts table fails",
 			deploymentID:    "",
-			ttlDays:         30,
+			policy:          Uniform(30),
 			execErr:         errors.New("permission denied"),
 			failOnTable:     "attempts",
 			wantErr:         true,
@@ -103,7 +123,7 @@ func TestClickHouseTTL_ApplyTTL(t *testing.T) {
 			}
 
 			ch := newClickHouseTTLWithExecer(conn, tt.deploymentID)
-			err := ch.ApplyTTL(context.Background(), tt.ttlDays)
+			err := ch.ApplyTTL(context.Background(), tt.policy)
 
 			if tt.wantErr {
 				require.Error(t, err)
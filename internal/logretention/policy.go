@@ -0,0 +1,95 @@
+package logretention
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logstore/driver"
+)
+
+// Policy is the number of days delivery attempts are retained, by attempt
+// status. Zero retains forever. Events are retained until the longest of the
+// two has elapsed, so a failed attempt never outlives its event. Deferred
+// attempts, which await redelivery, are retained as long as events.
+type Policy struct {
+	SuccessDays int
+	FailedDays  int
+}
+
+// Uniform returns a policy retaining every attempt for the same number of days.
+func Uniform(days int) Policy {
+	return Policy{SuccessDays: days, FailedDays: days}
+}
+
+// IsZero reports whether the policy retains everything forever.
+func (p Policy) IsZero() bool {
+	return p.SuccessDays == 0 && p.FailedDays == 0
+}
+
+// IsUniform reports whether both statuses share the same retention.
+func (p Policy) IsUniform() bool {
+	return p.SuccessDays == p.FailedDays
+}
+
+// EventDays is the retention of events: the longest attempt retention, or 0
+// when either status is retained forever.
+func (p Policy) EventDays() int {
+	if p.SuccessDays == 0 || p.FailedDays == 0 {
+		return 0
+	}
+	return max(p.SuccessDays, p.FailedDays)
+}
+
+// Validate rejects negative retention.
+func (p Policy) Validate() error {
+	if p.SuccessDays < 0 || p.FailedDays < 0 {
+		return errors.New("log retention days must be >= 0")
+	}
+	return nil
+}
+
+// PruneRequest returns the cutoffs of the policy at now. Statuses retained
+// forever get no cutoff.
+func (p Policy) PruneRequest(now time.Time) driver.PruneRequest {
+	cutoff := func(days int) *time.Time {
+		if days == 0 {
+			return nil
+		}
+		t := now.AddDate(0, 0, -days)
+		return &t
+	}
+	return driver.PruneRequest{
+		SuccessBefore:  cutoff(p.SuccessDays),
+		FailedBefore:   cutoff(p.FailedDays),
+		DeferredBefore: cutoff(p.EventDays()),
+	}
+}
+
+// String encodes the policy as "<days>" when uniform, or
+// "<success days>/<failed days>" otherwise.
+func (p Policy) String() string {
+	if p.IsUniform() {
+		return strconv.Itoa(p.SuccessDays)
+	}
+	return fmt.Sprintf("%d/%d", p.SuccessDays, p.FailedDays)
+}
+
+// parsePolicy decodes a policy encoded by Policy.String.
+func parsePolicy(s string) (Policy, error) {
+	successPart, failedPart, split := strings.Cut(s, "/")
+	success, err := strconv.Atoi(successPart)
+	if err != nil {
+		return Policy{}, err
+	}
+	if !split {
+		return Uniform(success), nil
+	}
+	failed, err := strconv.Atoi(failedPart)
+	if err != nil {
+		return Policy{}, err
+	}
+	return Policy{SuccessDays: success, FailedDays: failed}, nil
+}
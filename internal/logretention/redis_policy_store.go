@@ -3,7 +3,6 @@ package logretention
 import (
 	"context"
 	"fmt"
-	"strconv"
 
 	"github.com/hookdeck/outpost/internal/redis"
 )
@@ -24,7 +23,7 @@ func NewRedisPolicyStore(client redis.Cmdable, deploymentID string) *RedisPolicy
 	}
 }
 
-// redisKey returns the Redis key for storing the applied TTL.
+// redisKey returns the Redis key for storing the applied policy.
 // In multi-deployment mode, keys are prefixed with <deploymentID>:.
 func (s *RedisPolicyStore) redisKey() string {
 	if s.deploymentID == "" {
@@ -33,26 +32,27 @@ func (s *RedisPolicyStore) redisKey() string {
 	return fmt.Sprintf("%s:outpost:log_retention_ttl", s.deploymentID)
 }
 
-// GetAppliedTTL reads the persisted TTL value from Redis.
-// Returns -1 if the key doesn't exist.
-func (s *RedisPolicyStore) GetAppliedTTL(ctx context.Context) (int, error) {
+// GetAppliedPolicy reads the persisted policy from Redis.
+// Returns false if the key doesn't exist. Values written before per-status
+// retention hold a single number of days, which reads as a uniform policy.
+func (s *RedisPolicyStore) GetAppliedPolicy(ctx context.Context) (Policy, bool, error) {
 	val, err := s.client.Get(ctx, s.redisKey()).Result()
 	if err == redis.Nil {
-		return -1, nil // Key doesn't exist
+		return Policy{}, false, nil // Key doesn't exist
 	}
 	if err != nil {
-		return 0, err
+		return Policy{}, false, err
 	}
 
-	ttl, err := strconv.Atoi(val)
+	policy, err := parsePolicy(val)
 	if err != nil {
-		return 0, fmt.Errorf("invalid TTL value in Redis: %w", err)
+		return Policy{}, false, fmt.Errorf("invalid TTL value in Redis: %w", err)
 	}
 
-	return ttl, nil
+	return policy, true, nil
 }
 
-// SetAppliedTTL writes the TTL value to Redis.
-func (s *RedisPolicyStore) SetAppliedTTL(ctx context.Context, ttlDays int) error {
-	return s.client.Set(ctx, s.redisKey(), strconv.Itoa(ttlDays), 0).Err()
+// SetAppliedPolicy writes the policy to Redis.
+func (s *RedisPolicyStore) SetAppliedPolicy(ctx context.Context, policy Policy) error {
+	return s.client.Set(ctx, s.redisKey(), policy.String(), 0).Err()
 }
@@ -37,24 +37,34 @@ func TestRedisPolicyStore_RedisKey(t *testing.T) {
 	}
 }
 
-func TestRedisPolicyStore_GetAppliedTTL(t *testing.T) {
+func TestRedisPolicyStore_GetAppliedPolicy(t *testing.T) {
 	ctx := context.Background()
 	client := testutil.CreateTestRedisClient(t)
 
 	t.Run("key does not exist", func(t *testing.T) {
 		store := NewRedisPolicyStore(client, "get_nokey")
-		ttl, err := store.GetAppliedTTL(ctx)
+		_, found, err := store.GetAppliedPolicy(ctx)
 		require.NoError(t, err)
-		assert.Equal(t, -1, ttl)
+		assert.False(t, found)
 	})
 
 	t.Run("invalid value in redis", func(t *testing.T) {
 		store := NewRedisPolicyStore(client, "get_invalid")
 		client.Set(ctx, store.redisKey(), "not-a-number", 0)
 
-		_, err := store.GetAppliedTTL(ctx)
+		_, _, err := store.GetAppliedPolicy(ctx)
 		require.Error(t, err)
 	})
+
+	t.Run("legacy single value reads as uniform", func(t *testing.T) {
+		store := NewRedisPolicyStore(client, "get_legacy")
+		client.Set(ctx, store.redisKey(), "30", 0)
+
+		policy, found, err := store.GetAppliedPolicy(ctx)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, Uniform(30), policy)
+	})
 }
 
 func TestRedisPolicyStore_RoundTrip(t *testing.T) {
@@ -62,24 +72,25 @@ func TestRedisPolicyStore_RoundTrip(t *testing.T) {
 	client := testutil.CreateTestRedisClient(t)
 	store := NewRedisPolicyStore(client, "dpm_001")
 
-	// Initially should return -1 (not found)
-	ttl, err := store.GetAppliedTTL(ctx)
+	// Initially not found
+	_, found, err := store.GetAppliedPolicy(ctx)
 	require.NoError(t, err)
-	assert.Equal(t, -1, ttl)
+	assert.False(t, found)
 
 	// Set a value
-	require.NoError(t, store.SetAppliedTTL(ctx, 30))
+	require.NoError(t, store.SetAppliedPolicy(ctx, Uniform(30)))
 
-	ttl, err = store.GetAppliedTTL(ctx)
+	policy, found, err := store.GetAppliedPolicy(ctx)
 	require.NoError(t, err)
-	assert.Equal(t, 30, ttl)
+	assert.True(t, found)
+	assert.Equal(t, Uniform(30), policy)
 
-	// Update the value
-	require.NoError(t, store.SetAppliedTTL(ctx, 7))
+	// Update to a per-status value
+	require.NoError(t, store.SetAppliedPolicy(ctx, Policy{SuccessDays: 14, FailedDays: 90}))
 
-	ttl, err = store.GetAppliedTTL(ctx)
+	policy, _, err = store.GetAppliedPolicy(ctx)
 	require.NoError(t, err)
-	assert.Equal(t, 7, ttl)
+	assert.Equal(t, Policy{SuccessDays: 14, FailedDays: 90}, policy)
 }
 
 func TestRedisPolicyStore_DeploymentIsolation(t *testing.T) {
@@ -90,19 +101,19 @@ func TestRedisPolicyStore_DeploymentIsolation(t *testing.T) {
 	store2 := NewRedisPolicyStore(client, "dpm_002")
 	storeDefault := NewRedisPolicyStore(client, "")
 
-	require.NoError(t, store1.SetAppliedTTL(ctx, 30))
-	require.NoError(t, store2.SetAppliedTTL(ctx, 60))
-	require.NoError(t, storeDefault.SetAppliedTTL(ctx, 90))
+	require.NoError(t, store1.SetAppliedPolicy(ctx, Uniform(30)))
+	require.NoError(t, store2.SetAppliedPolicy(ctx, Uniform(60)))
+	require.NoError(t, storeDefault.SetAppliedPolicy(ctx, Uniform(90)))
 
-	ttl1, err := store1.GetAppliedTTL(ctx)
+	policy1, _, err := store1.GetAppliedPolicy(ctx)
 	require.NoError(t, err)
-	assert.Equal(t, 30, ttl1)
+	assert.Equal(t, Uniform(30), policy1)
 
-	ttl2, err := store2.GetAppliedTTL(ctx)
+	policy2, _, err := store2.GetAppliedPolicy(ctx)
 	require.NoError(t, err)
-	assert.Equal(t, 60, ttl2)
+	assert.Equal(t, Uniform(60), policy2)
 
-	ttlDefault, err := storeDefault.GetAppliedTTL(ctx)
+	policyDefault, _, err := storeDefault.GetAppliedPolicy(ctx)
 	require.NoError(t, err)
-	assert.Equal(t, 90, ttlDefault)
+	assert.Equal(t, Uniform(90), policyDefault)
 }
@@ -2,7 +2,6 @@ package logretention
 
 import (
 	"context"
-	"errors"
 	"fmt"
 
 	"github.com/hookdeck/outpost/internal/clickhouse"
@@ -11,58 +10,63 @@ import (
 	"go.uber.org/zap"
 )
 
-// policyStore abstracts the persistence of applied TTL state.
+// policyStore abstracts the persistence of the applied retention policy.
 type policyStore interface {
-	GetAppliedTTL(ctx context.Context) (int, error)
-	SetAppliedTTL(ctx context.Context, ttlDays int) error
+	// GetAppliedPolicy returns the persisted policy and whether one exists.
+	GetAppliedPolicy(ctx context.Context) (Policy, bool, error)
+	SetAppliedPolicy(ctx context.Context, policy Policy) error
 }
 
 // logStoreTTL abstracts TTL application to a log store.
 type logStoreTTL interface {
-	ApplyTTL(ctx context.Context, ttlDays int) error
+	ApplyTTL(ctx context.Context, policy Policy) error
 }
 
-// Apply compares the desired TTL with the persisted TTL.
-// If they differ, it applies the TTL to ClickHouse and persists the new value to Redis.
-func Apply(ctx context.Context, redisClient redis.Cmdable, chConn clickhouse.DB, deploymentID string, desiredTTLDays int, logger *logging.Logger) error {
-	if desiredTTLDays < 0 {
-		return errors.New("log retention TTL days must be >= 0")
+// Apply compares the desired policy with the persisted policy.
+// If they differ, it applies the TTL to ClickHouse and persists the new policy to Redis.
+func Apply(ctx context.Context, redisClient redis.Cmdable, chConn clickhouse.DB, deploymentID string, desired Policy, logger *logging.Logger) error {
+	if err := desired.Validate(); err != nil {
+		return err
 	}
 
 	ps := NewRedisPolicyStore(redisClient, deploymentID)
 	ls := NewClickHouseTTL(chConn, deploymentID)
 
-	return sync(ctx, ps, ls, desiredTTLDays, logger)
+	return sync(ctx, ps, ls, desired, logger)
 }
 
-// sync compares the desired TTL with the persisted TTL and applies if different.
-func sync(ctx context.Context, ps policyStore, ls logStoreTTL, desiredTTLDays int, logger *logging.Logger) error {
-	persistedTTL, err := ps.GetAppliedTTL(ctx)
+// sync compares the desired policy with the persisted policy and applies if different.
+func sync(ctx context.Context, ps policyStore, ls logStoreTTL, desired Policy, logger *logging.Logger) error {
+	persisted, found, err := ps.GetAppliedPolicy(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to read persisted TTL: %w", err)
 	}
 
-	// No change needed: either TTL matches, or this is a fresh setup with no TTL configured.
-	if persistedTTL == desiredTTLDays || (persistedTTL == -1 && desiredTTLDays == 0) {
+	// No change needed: either the policy matches, or this is a fresh setup with no TTL configured.
+	if (found && persisted == desired) || (!found && desired.IsZero()) {
 		logger.Debug("TTL unchanged, skipping",
-			zap.Int("ttl_days", desiredTTLDays))
+			zap.Stringer("ttl_days", desired))
 		return nil
 	}
 
+	oldPolicy := "none"
+	if found {
+		oldPolicy = persisted.String()
+	}
 	logger.Debug("applying log retention TTL",
-		zap.Int("old_ttl_days", persistedTTL),
-		zap.Int("new_ttl_days", desiredTTLDays))
+		zap.String("old_ttl_days", oldPolicy),
+		zap.Stringer("new_ttl_days", desired))
 
-	if err := ls.ApplyTTL(ctx, desiredTTLDays); err != nil {
+	if err := ls.ApplyTTL(ctx, desired); err != nil {
 		return err
 	}
 
-	if err := ps.SetAppliedTTL(ctx, desiredTTLDays); err != nil {
+	if err := ps.SetAppliedPolicy(ctx, desired); err != nil {
 		return fmt.Errorf("failed to persist TTL: %w", err)
 	}
 
 	logger.Debug("log retention TTL applied successfully",
-		zap.Int("ttl_days", desiredTTLDays))
+		zap.Stringer("ttl_days", desired))
 
 	return nil
 }
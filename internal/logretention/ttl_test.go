@@ -12,36 +12,44 @@ import (
 
 // mockPolicyStore is a test implementation of policyStore.
 type mockPolicyStore struct {
-	ttl       int
+	policy    *Policy // nil when nothing is persisted
 	getErr    error
 	setErr    error
 	setCalled bool
-	setTTL    int
+	setPolicy Policy
 }
 
-func (m *mockPolicyStore) GetAppliedTTL(ctx context.Context) (int, error) {
+func (m *mockPolicyStore) GetAppliedPolicy(ctx context.Context) (Policy, bool, error) {
 	if m.getErr != nil {
-		return 0, m.getErr
+		return Policy{}, false, m.getErr
 	}
-	return m.ttl, nil
+	if m.policy == nil {
+		return Policy{}, false, nil
+	}
+	return *m.policy, true, nil
 }
 
-func (m *mockPolicyStore) SetAppliedTTL(ctx context.Context, ttlDays int) error {
+func (m *mockPolicyStore) SetAppliedPolicy(ctx context.Context, policy Policy) error {
 	m.setCalled = true
-	m.setTTL = ttlDays
+	m.setPolicy = policy
 	return m.setErr
 }
 
+func uniform(days int) *Policy {
+	p := Uniform(days)
+	return &p
+}
+
 // mockLogStoreTTL is a test implementation of logStoreTTL.
 type mockLogStoreTTL struct {
 	applyErr    error
 	applyCalled bool
-	appliedTTL  int
+	appliedTTL  Policy
 }
 
-func (m *mockLogStoreTTL) ApplyTTL(ctx context.Context, ttlDays int) error {
+func (m *mockLogStoreTTL) ApplyTTL(ctx context.Context, policy Policy) error {
 	m.applyCalled = true
-	m.appliedTTL = ttlDays
+	m.appliedTTL = policy
 	return m.applyErr
 }
 
@@ -54,75 +62,97 @@ func TestSync(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("skip when TTL unchanged", func(t *testing.T) {
-		ps := &mockPolicyStore{ttl: 30}
+		ps := &mockPolicyStore{policy: uniform(30)}
 		ls := &mockLogStoreTTL{}
 
-		require.NoError(t, sync(ctx, ps, ls, 30, testLogger()))
+		require.NoError(t, sync(ctx, ps, ls, Uniform(30), testLogger()))
 		assert.False(t, ls.applyCalled)
 		assert.False(t, ps.setCalled)
 	})
 
 	t.Run("apply and persist when TTL changed", func(t *testing.T) {
-		ps := &mockPolicyStore{ttl: 30}
+		ps := &mockPolicyStore{policy: uniform(30)}
 		ls := &mockLogStoreTTL{}
 
-		require.NoError(t, sync(ctx, ps, ls, 7, testLogger()))
-		assert.Equal(t, 7, ls.appliedTTL)
-		assert.Equal(t, 7, ps.setTTL)
+		require.NoError(t, sync(ctx, ps, ls, Uniform(7), testLogger()))
+		assert.Equal(t, Uniform(7), ls.appliedTTL)
+		assert.Equal(t, Uniform(7), ps.setPolicy)
 	})
 
 	t.Run("apply on first startup", func(t *testing.T) {
-		ps := &mockPolicyStore{ttl: -1}
+		ps := &mockPolicyStore{}
 		ls := &mockLogStoreTTL{}
 
-		require.NoError(t, sync(ctx, ps, ls, 30, testLogger()))
-		assert.Equal(t, 30, ls.appliedTTL)
-		assert.Equal(t, 30, ps.setTTL)
+		require.NoError(t, sync(ctx, ps, ls, Uniform(30), testLogger()))
+		assert.Equal(t, Uniform(30), ls.appliedTTL)
+		assert.Equal(t, Uniform(30), ps.setPolicy)
 	})
 
 	t.Run("skip on first startup when TTL is zero", func(t *testing.T) {
-		ps := &mockPolicyStore{ttl: -1}
+		ps := &mockPolicyStore{}
 		ls := &mockLogStoreTTL{}
 
-		require.NoError(t, sync(ctx, ps, ls, 0, testLogger()))
+		require.NoError(t, sync(ctx, ps, ls, Uniform(0), testLogger()))
 		assert.False(t, ls.applyCalled, "no TTL to apply on fresh table")
 		assert.False(t, ps.setCalled, "nothing to persist")
 	})
 
 	t.Run("remove TTL when set to zero", func(t *testing.T) {
-		ps := &mockPolicyStore{ttl: 30}
+		ps := &mockPolicyStore{policy: uniform(30)}
 		ls := &mockLogStoreTTL{}
 
-		require.NoError(t, sync(ctx, ps, ls, 0, testLogger()))
-		assert.Equal(t, 0, ls.appliedTTL)
-		assert.Equal(t, 0, ps.setTTL)
+		require.NoError(t, sync(ctx, ps, ls, Uniform(0), testLogger()))
+		assert.Equal(t, Uniform(0), ls.appliedTTL)
+		assert.Equal(t, Uniform(0), ps.setPolicy)
 	})
 
 	t.Run("reject negative TTL", func(t *testing.T) {
-		require.Error(t, Apply(ctx, nil, nil, "", -1, testLogger()))
+		require.Error(t, Apply(ctx, nil, nil, "", Uniform(-1), testLogger()))
+	})
+
+	t.Run("reject negative status TTL", func(t *testing.T) {
+		require.Error(t, Apply(ctx, nil, nil, "", Policy{SuccessDays: 14, FailedDays: -1}, testLogger()))
+	})
+
+	t.Run("apply when status TTL diverges", func(t *testing.T) {
+		ps := &mockPolicyStore{policy: uniform(30)}
+		ls := &mockLogStoreTTL{}
+		desired := Policy{SuccessDays: 14, FailedDays: 90}
+
+		require.NoError(t, sync(ctx, ps, ls, desired, testLogger()))
+		assert.Equal(t, desired, ls.appliedTTL)
+		assert.Equal(t, desired, ps.setPolicy)
+	})
+
+	t.Run("skip when status TTL unchanged", func(t *testing.T) {
+		ps := &mockPolicyStore{policy: &Policy{SuccessDays: 14, FailedDays: 90}}
+		ls := &mockLogStoreTTL{}
+
+		require.NoError(t, sync(ctx, ps, ls, Policy{SuccessDays: 14, FailedDays: 90}, testLogger()))
+		assert.False(t, ls.applyCalled)
 	})
 
 	t.Run("fail when policy store read fails", func(t *testing.T) {
 		ps := &mockPolicyStore{getErr: errors.New("redis down")}
 		ls := &mockLogStoreTTL{}
 
-		require.Error(t, sync(ctx, ps, ls, 30, testLogger()))
+		require.Error(t, sync(ctx, ps, ls, Uniform(30), testLogger()))
 		assert.False(t, ls.applyCalled)
 	})
 
 	t.Run("fail when log store apply fails", func(t *testing.T) {
-		ps := &mockPolicyStore{ttl: 7}
+		ps := &mockPolicyStore{policy: uniform(7)}
 		ls := &mockLogStoreTTL{applyErr: errors.New("clickhouse down")}
 
-		require.Error(t, sync(ctx, ps, ls, 30, testLogger()))
+		require.Error(t, sync(ctx, ps, ls, Uniform(30), testLogger()))
 		assert.False(t, ps.setCalled, "should not persist if CH failed")
 	})
 
 	t.Run("fail when policy store write fails", func(t *testing.T) {
-		ps := &mockPolicyStore{ttl: 7, setErr: errors.New("redis write failed")}
+		ps := &mockPolicyStore{policy: uniform(7), setErr: errors.New("redis write failed")}
 		ls := &mockLogStoreTTL{}
 
-		require.Error(t, sync(ctx, ps, ls, 30, testLogger()))
+		require.Error(t, sync(ctx, ps, ls, Uniform(30), testLogger()))
 		assert.True(t, ls.applyCalled, "CH should have been applied before Redis persist failed")
 	})
 }
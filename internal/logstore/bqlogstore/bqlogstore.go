@@ -0,0 +1,695 @@
+// Package bqlogstore implements the log store on BigQuery.
+//
+// Events and attempts are kept in two tables, like the PostgreSQL driver,
+// and written with MERGE statements so that retried batches don't duplicate
+// rows. DML results are visible to the next query, so reads are consistent
+// without a merge step. Run EnsureSchema to create the tables.
+package bqlogstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/cursor"
+	"github.com/hookdeck/outpost/internal/logstore/driver"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/pagination"
+	"google.golang.org/api/bigquery/v2"
+)
+
+const (
+	cursorResourceEvent   = "evt"
+	cursorResourceAttempt = "att"
+	cursorVersion         = 1
+)
+
+// Config locates the dataset holding the log tables.
+type Config struct {
+	ProjectID string
+	DatasetID string
+	// Location is the dataset's location (e.g., "US"). It may be left empty
+	// for the multi-region defaults.
+	Location string
+}
+
+type logStore struct {
+	svc *bigquery.Service
+	cfg Config
+}
+
+func NewLogStore(svc *bigquery.Service, cfg Config) driver.LogStore {
+	return &logStore{
+		svc: svc,
+		cfg: cfg,
+	}
+}
+
+// eventWithPosition wraps an event with its cursor position data.
+type eventWithPosition struct {
+	*models.Event
+	eventTime time.Time
+}
+
+// attemptRecordWithPosition wraps an attempt record with its cursor position data.
+type attemptRecordWithPosition struct {
+	*driver.AttemptRecord
+	attemptTime time.Time
+}
+
+const eventColumns = `
+	id,
+	tenant_id,
+	matched_destination_ids,
+	time,
+	topic,
+	eligible_for_retry,
+	data,
+	metadata,
+	checksum,
+	source,
+	group_id`
+
+const attemptColumns = `
+	id,
+	event_id,
+	tenant_id,
+	destination_id,
+	destination_type,
+	topic,
+	status,
+	time,
+	attempt_number,
+	manual,
+	code,
+	response_data,
+	destination_snapshot,
+	event_time,
+	eligible_for_retry,
+	event_data,
+	event_metadata,
+	event_checksum,
+	event_source,
+	error_code,
+	error_message,
+	latency_ms`
+
+func (s *logStore) ListEvent(ctx context.Context, req driver.ListEventRequest) (driver.ListEventResponse, error) {
+	if len(driver.SearchTerms(req.Search)) > 0 {
+		return driver.ListEventResponse{}, driver.ErrSearchNotSupported
+	}
+
+	sortOrder := req.SortOrder
+	if sortOrder != "asc" && sortOrder != "desc" {
+		sortOrder = "desc"
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	res, err := pagination.Run(ctx, pagination.Config[eventWithPosition]{
+		Limit: limit,
+		Order: sortOrder,
+		Next:  req.Next,
+		Prev:  req.Prev,
+		Fetch: func(ctx context.Context, q pagination.QueryInput) ([]eventWithPosition, error) {
+			query, queryParams := buildEventQuery(req, q)
+			result, err := s.query(ctx, query, queryParams)
+			if err != nil {
+				return nil, fmt.Errorf("query failed: %w", err)
+			}
+			return scanEvents(result.rows)
+		},
+		Cursor: pagination.Cursor[eventWithPosition]{
+			Encode: func(e eventWithPosition) string {
+				position := fmt.Sprintf("%d::%s", e.eventTime.UnixMilli(), e.Event.ID)
+				return cursor.Encode(cursorResourceEvent, cursorVersion, position)
+			},
+			Decode: func(c string) (string, error) {
+				return cursor.Decode(c, cursorResourceEvent, cursorVersion)
+			},
+		},
+	})
+	if err != nil {
+		return driver.ListEventResponse{}, err
+	}
+
+	data := make([]*models.Event, len(res.Items))
+	for i, item := range res.Items {
+		data[i] = item.Event
+	}
+
+	return driver.ListEventResponse{
+		Data: data,
+		Next: res.Next,
+		Prev: res.Prev,
+	}, nil
+}
+
+func buildEventQuery(req driver.ListEventRequest, q pagination.QueryInput) (string, []*bigquery.QueryParameter) {
+	var p params
+	var conditions []string
+
+	if len(req.TenantIDs) > 0 {
+		conditions = append(conditions, "tenant_id IN UNNEST("+p.strings(req.TenantIDs)+")")
+	}
+	if len(req.EventIDs) > 0 {
+		conditions = append(conditions, "id IN UNNEST("+p.strings(req.EventIDs)+")")
+	}
+	if len(req.DestinationIDs) > 0 {
+		conditions = append(conditions, matchedDestinationsCondition(p.strings(req.DestinationIDs)))
+	}
+	if len(req.Topics) > 0 {
+		conditions = append(conditions, "topic IN UNNEST("+p.strings(req.Topics)+")")
+	}
+	if len(req.Sources) > 0 {
+		conditions = append(conditions, "source IN UNNEST("+p.strings(req.Sources)+")")
+	}
+	if req.GroupID != "" {
+		conditions = append(conditions, "group_id = "+p.string(req.GroupID))
+	}
+	for _, key := range slices.Sorted(maps.Keys(req.Metadata)) {
+		conditions = append(conditions, "JSON_VALUE(metadata, "+p.string(metadataPath(key))+") = "+p.string(req.Metadata[key]))
+	}
+	conditions = append(conditions, timeFilterConditions(&p, "time", req.TimeFilter)...)
+	if q.CursorPos != "" {
+		conditions = append(conditions, buildCursorCondition(&p, q.Compare, q.CursorPos))
+	}
+
+	dir := strings.ToUpper(q.SortDir)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM events
+		WHERE %s
+		ORDER BY time %s, id %s
+		LIMIT %s
+	`, eventColumns, whereClause(conditions), dir, dir, p.int64(int64(q.Limit)))
+
+	return query, p.list
+}
+
+// matchedDestinationsCondition matches events delivered to any of the
+// destinations in the array parameter.
+func matchedDestinationsCondition(param string) string {
+	return "EXISTS (SELECT 1 FROM UNNEST(matched_destination_ids) AS d WHERE d IN UNNEST(" + param + "))"
+}
+
+func timeFilterConditions(p *params, col string, f driver.TimeFilter) []string {
+	var conditions []string
+	if f.GTE != nil {
+		conditions = append(conditions, col+" >= "+p.timestamp(*f.GTE))
+	}
+	if f.LTE != nil {
+		conditions = append(conditions, col+" <= "+p.timestamp(*f.LTE))
+	}
+	if f.GT != nil {
+		conditions = append(conditions, col+" > "+p.timestamp(*f.GT))
+	}
+	if f.LT != nil {
+		conditions = append(conditions, col+" < "+p.timestamp(*f.LT))
+	}
+	return conditions
+}
+
+// buildCursorCondition resumes a (time, id) ordered list after a
+// "{unix_ms}::{id}" cursor position.
+func buildCursorCondition(p *params, compare, position string) string {
+	parts := strings.SplitN(position, "::", 2)
+	if len(parts) != 2 {
+		return "TRUE" // invalid cursor, return always true
+	}
+	timeMs, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return "TRUE" // invalid timestamp, return always true
+	}
+	cursorTime := "TIMESTAMP_MILLIS(" + p.int64(timeMs) + ")"
+	id := p.string(parts[1])
+
+	return fmt.Sprintf(`(
+		time %s %s
+		OR (time = %s AND id %s %s)
+	)`, compare, cursorTime, cursorTime, compare, id)
+}
+
+func scanEvents(rows []*bigquery.TableRow) ([]eventWithPosition, error) {
+	results := make([]eventWithPosition, 0, len(rows))
+	for _, row := range rows {
+		event, err := scanEvent(row)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, eventWithPosition{
+			Event:     event,
+			eventTime: event.Time,
+		})
+	}
+	return results, nil
+}
+
+// scanEvent reads a row selected with eventColumns.
+func scanEvent(row *bigquery.TableRow) (*models.Event, error) {
+	r := rowReader{cells: row.F}
+	event := &models.Event{
+		ID:                    r.string(0),
+		TenantID:              r.string(1),
+		MatchedDestinationIDs: r.strings(2),
+		Time:                  r.time(3),
+		Topic:                 r.string(4),
+		EligibleForRetry:      r.bool(5),
+		Data:                  []byte(r.string(6)),
+		Checksum:              r.string(8),
+		Source:                r.string(9),
+		GroupID:               r.string(10),
+	}
+	if r.err != nil {
+		return nil, fmt.Errorf("scan failed: %w", r.err)
+	}
+	metadata, err := decodeMetadata(r.string(7))
+	if err != nil {
+		return nil, err
+	}
+	event.Metadata = metadata
+	return event, nil
+}
+
+// scanAttemptRecord reads a row selected with attemptColumns.
+func scanAttemptRecord(row *bigquery.TableRow) (*driver.AttemptRecord, error) {
+	r := rowReader{cells: row.F}
+	var (
+		id               = r.string(0)
+		eventID          = r.string(1)
+		tenantID         = r.string(2)
+		destinationID    = r.string(3)
+		destinationType  = r.string(4)
+		topic            = r.string(5)
+		status           = r.string(6)
+		attemptTime      = r.time(7)
+		attemptNumber    = r.int(8)
+		manual           = r.bool(9)
+		code             = r.string(10)
+		responseDataStr  = r.string(11)
+		snapshotStr      = r.string(12)
+		eventTime        = r.time(13)
+		eligibleForRetry = r.bool(14)
+		eventData        = r.string(15)
+		eventMetadataStr = r.string(16)
+		eventChecksum    = r.string(17)
+		eventSource      = r.string(18)
+		errorCode        = r.string(19)
+		errorMessage     = r.string(20)
+		latencyMs        = r.nullableInt64(21)
+	)
+	if r.err != nil {
+		return nil, fmt.Errorf("scan failed: %w", r.err)
+	}
+
+	var responseData map[string]any
+	if responseDataStr != "" {
+		if err := json.Unmarshal([]byte(responseDataStr), &responseData); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal response_data: %w", err)
+		}
+	}
+	snapshot, err := driver.DecodeDestinationSnapshot(snapshotStr)
+	if err != nil {
+		return nil, err
+	}
+	eventMetadata, err := decodeMetadata(eventMetadataStr)
+	if err != nil {
+		return nil, err
+	}
+
+	return &driver.AttemptRecord{
+		Attempt: &models.Attempt{
+			ID:                  id,
+			TenantID:            tenantID,
+			EventID:             eventID,
+			DestinationID:       destinationID,
+			DestinationType:     destinationType,
+			AttemptNumber:       attemptNumber,
+			Manual:              manual,
+			Status:              status,
+			Time:                attemptTime,
+			Code:                code,
+			ResponseData:        responseData,
+			DestinationSnapshot: snapshot,
+			ErrorCode:           errorCode,
+			ErrorMessage:        errorMessage,
+			LatencyMs:           latencyMs,
+		},
+		Event: &models.Event{
+			ID:               eventID,
+			TenantID:         tenantID,
+			DestinationID:    destinationID,
+			Topic:            topic,
+			EligibleForRetry: eligibleForRetry,
+			Time:             eventTime,
+			Data:             []byte(eventData),
+			Metadata:         eventMetadata,
+			Checksum:         eventChecksum,
+			Source:           eventSource,
+		},
+	}, nil
+}
+
+func decodeMetadata(s string) (map[string]string, error) {
+	metadata := map[string]string{}
+	if s == "" {
+		return metadata, nil
+	}
+	if err := json.Unmarshal([]byte(s), &metadata); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal metadata: %w", err)
+	}
+	return metadata, nil
+}
+
+// metadataPath is the JSONPath of a metadata key.
+func metadataPath(key string) string {
+	return `$."` + strings.ReplaceAll(key, `"`, `\"`) + `"`
+}
+
+// encodeMetadata stores a nil map as an empty object, as the PostgreSQL
+// driver does.
+func encodeMetadata(metadata map[string]string) string {
+	if metadata == nil {
+		metadata = map[string]string{}
+	}
+	b, _ := json.Marshal(metadata)
+	return string(b)
+}
+
+func (s *logStore) ListAttempt(ctx context.Context, req driver.ListAttemptRequest) (driver.ListAttemptResponse, error) {
+	sortOrder := req.SortOrder
+	if sortOrder != "asc" && sortOrder != "desc" {
+		sortOrder = "desc"
+	}
+
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+
+	res, err := pagination.Run(ctx, pagination.Config[attemptRecordWithPosition]{
+		Limit: limit,
+		Order: sortOrder,
+		Next:  req.Next,
+		Prev:  req.Prev,
+		Fetch: func(ctx context.Context, q pagination.QueryInput) ([]attemptRecordWithPosition, error) {
+			query, queryParams := buildAttemptQuery(req, q)
+			result, err := s.query(ctx, query, queryParams)
+			if err != nil {
+				return nil, fmt.Errorf("query failed: %w", err)
+			}
+			records := make([]attemptRecordWithPosition, 0, len(result.rows))
+			for _, row := range result.rows {
+				record, err := scanAttemptRecord(row)
+				if err != nil {
+					return nil, err
+				}
+				records = append(records, attemptRecordWithPosition{
+					AttemptRecord: record,
+					attemptTime:   record.Attempt.Time,
+				})
+			}
+			return records, nil
+		},
+		Cursor: pagination.Cursor[attemptRecordWithPosition]{
+			Encode: func(ar attemptRecordWithPosition) string {
+				position := fmt.Sprintf("%d::%s", ar.attemptTime.UnixMilli(), ar.Attempt.ID)
+				return cursor.Encode(cursorResourceAttempt, cursorVersion, position)
+			},
+			Decode: func(c string) (string, error) {
+				return cursor.Decode(c, cursorResourceAttempt, cursorVersion)
+			},
+		},
+	})
+	if err != nil {
+		return driver.ListAttemptResponse{}, err
+	}
+
+	data := make([]*driver.AttemptRecord, len(res.Items))
+	for i, item := range res.Items {
+		data[i] = item.AttemptRecord
+	}
+
+	return driver.ListAttemptResponse{
+		Data: data,
+		Next: res.Next,
+		Prev: res.Prev,
+	}, nil
+}
+
+func buildAttemptQuery(req driver.ListAttemptRequest, q pagination.QueryInput) (string, []*bigquery.QueryParameter) {
+	var p params
+	var conditions []string
+
+	if len(req.TenantIDs) > 0 {
+		conditions = append(conditions, "tenant_id IN UNNEST("+p.strings(req.TenantIDs)+")")
+	}
+	if len(req.EventIDs) > 0 {
+		conditions = append(conditions, "event_id IN UNNEST("+p.strings(req.EventIDs)+")")
+	}
+	if len(req.DestinationIDs) > 0 {
+		conditions = append(conditions, "destination_id IN UNNEST("+p.strings(req.DestinationIDs)+")")
+	}
+	if len(req.DestinationTypes) > 0 {
+		conditions = append(conditions, "destination_type IN UNNEST("+p.strings(req.DestinationTypes)+")")
+	}
+	if req.Status != "" {
+		conditions = append(conditions, "status = "+p.string(req.Status))
+	}
+	if len(req.Topics) > 0 {
+		conditions = append(conditions, "topic IN UNNEST("+p.strings(req.Topics)+")")
+	}
+	if len(req.Sources) > 0 {
+		conditions = append(conditions, "event_source IN UNNEST("+p.strings(req.Sources)+")")
+	}
+	conditions = append(conditions, timeFilterConditions(&p, "time", req.TimeFilter)...)
+	if q.CursorPos != "" {
+		conditions = append(conditions, buildCursorCondition(&p, q.Compare, q.CursorPos))
+	}
+
+	dir := strings.ToUpper(q.SortDir)
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM attempts
+		WHERE %s
+		ORDER BY time %s, id %s
+		LIMIT %s
+	`, attemptColumns, whereClause(conditions), dir, dir, p.int64(int64(q.Limit)))
+
+	return query, p.list
+}
+
+func (s *logStore) RetrieveEvent(ctx context.Context, req driver.RetrieveEventRequest) (*models.Event, error) {
+	var p params
+	var conditions []string
+
+	if req.TenantID != "" {
+		conditions = append(conditions, "tenant_id = "+p.string(req.TenantID))
+	}
+	conditions = append(conditions, "id = "+p.string(req.EventID))
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM events
+		WHERE %s
+		LIMIT 1`, eventColumns, whereClause(conditions))
+
+	result, err := s.query(ctx, query, p.list)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	if len(result.rows) == 0 {
+		return nil, nil
+	}
+	return scanEvent(result.rows[0])
+}
+
+func (s *logStore) RetrieveAttempt(ctx context.Context, req driver.RetrieveAttemptRequest) (*driver.AttemptRecord, error) {
+	var p params
+	var conditions []string
+
+	if req.TenantID != "" {
+		conditions = append(conditions, "tenant_id = "+p.string(req.TenantID))
+	}
+	conditions = append(conditions, "id = "+p.string(req.AttemptID))
+
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM attempts
+		WHERE %s
+		LIMIT 1`, attemptColumns, whereClause(conditions))
+
+	result, err := s.query(ctx, query, p.list)
+	if err != nil {
+		return nil, fmt.Errorf("query failed: %w", err)
+	}
+	if len(result.rows) == 0 {
+		return nil, nil
+	}
+	return scanAttemptRecord(result.rows[0])
+}
+
+var eventStructFields = []*bigquery.QueryParameterTypeStructTypes{
+	{Name: "id", Type: typeString},
+	{Name: "tenant_id", Type: typeString},
+	{Name: "matched_destination_ids", Type: arrayOf(typeString)},
+	{Name: "time", Type: typeTimestamp},
+	{Name: "topic", Type: typeString},
+	{Name: "eligible_for_retry", Type: typeBool},
+	{Name: "data", Type: typeString},
+	{Name: "metadata", Type: typeString},
+	{Name: "checksum", Type: typeString},
+	{Name: "source", Type: typeString},
+	{Name: "group_id", Type: typeString},
+}
+
+var attemptStructFields = []*bigquery.QueryParameterTypeStructTypes{
+	{Name: "id", Type: typeString},
+	{Name: "event_id", Type: typeString},
+	{Name: "tenant_id", Type: typeString},
+	{Name: "destination_id", Type: typeString},
+	{Name: "destination_type", Type: typeString},
+	{Name: "topic", Type: typeString},
+	{Name: "status", Type: typeString},
+	{Name: "time", Type: typeTimestamp},
+	{Name: "attempt_number", Type: typeInt64},
+	{Name: "manual", Type: typeBool},
+	{Name: "code", Type: typeString},
+	{Name: "response_data", Type: typeString},
+	{Name: "destination_snapshot", Type: typeString},
+	{Name: "event_time", Type: typeTimestamp},
+	{Name: "eligible_for_retry", Type: typeBool},
+	{Name: "event_data", Type: typeString},
+	{Name: "event_metadata", Type: typeString},
+	{Name: "event_checksum", Type: typeString},
+	{Name: "event_source", Type: typeString},
+	{Name: "error_code", Type: typeString},
+	{Name: "error_message", Type: typeString},
+	{Name: "latency_ms", Type: typeInt64},
+}
+
+func (s *logStore) InsertMany(ctx context.Context, entries []*models.LogEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+
+	// A MERGE fails when two source rows match the same target row, so
+	// collapse intra-batch duplicates first.
+	entries = driver.DedupeEntriesByAttemptID(entries)
+
+	// Extract and dedupe events by ID, skipping retry attempts.
+	// Retries (AttemptNumber > 1) carry identical event data — the event row
+	// already exists from the first attempt's batch, unless WriteEvent says
+	// otherwise.
+	eventMap := make(map[string]*models.Event)
+	for _, entry := range entries {
+		if entry.Attempt.AttemptNumber <= 1 || entry.WriteEvent {
+			eventMap[entry.Event.ID] = entry.Event
+		}
+	}
+	events := make([]map[string]bigquery.QueryParameterValue, 0, len(eventMap))
+	for _, e := range eventMap {
+		events = append(events, eventRow(e))
+	}
+
+	// BigQuery runs each statement in its own transaction. Events are
+	// written first so an attempt is never stored without its event; a
+	// failed batch is retried as a whole and both statements are idempotent.
+	if len(events) > 0 {
+		_, err := s.query(ctx, `
+			MERGE events AS t
+			USING (SELECT * FROM UNNEST(@events)) AS s
+			ON t.time = s.time AND t.id = s.id
+			WHEN NOT MATCHED THEN
+				INSERT (`+eventColumns+`)
+				VALUES (s.id, s.tenant_id, s.matched_destination_ids, s.time, s.topic,
+					s.eligible_for_retry, s.data, s.metadata, s.checksum, s.source, s.group_id)
+		`, []*bigquery.QueryParameter{structsParam("events", eventStructFields, events)})
+		if err != nil {
+			return fmt.Errorf("insert events failed: %w", err)
+		}
+	}
+
+	attempts := make([]map[string]bigquery.QueryParameterValue, len(entries))
+	for i, entry := range entries {
+		attempts[i] = attemptRow(entry)
+	}
+	_, err := s.query(ctx, `
+		MERGE attempts AS t
+		USING (SELECT * FROM UNNEST(@attempts)) AS s
+		ON t.time = s.time AND t.id = s.id
+		WHEN MATCHED THEN
+			UPDATE SET
+				status = s.status,
+				code = s.code,
+				response_data = s.response_data,
+				error_code = s.error_code,
+				error_message = s.error_message,
+				latency_ms = s.latency_ms
+		WHEN NOT MATCHED THEN
+			INSERT (`+attemptColumns+`)
+			VALUES (s.id, s.event_id, s.tenant_id, s.destination_id, s.destination_type, s.topic, s.status,
+				s.time, s.attempt_number, s.manual, s.code, s.response_data, s.destination_snapshot,
+				s.event_time, s.eligible_for_retry, s.event_data, s.event_metadata, s.event_checksum,
+				s.event_source, s.error_code, s.error_message, s.latency_ms)
+	`, []*bigquery.QueryParameter{structsParam("attempts", attemptStructFields, attempts)})
+	if err != nil {
+		return fmt.Errorf("insert attempts failed: %w", err)
+	}
+
+	return nil
+}
+
+func eventRow(e *models.Event) map[string]bigquery.QueryParameterValue {
+	return map[string]bigquery.QueryParameterValue{
+		"id":                      *scalarValue(e.ID),
+		"tenant_id":               *scalarValue(e.TenantID),
+		"matched_destination_ids": *stringValues(e.MatchedDestinationIDs),
+		"time":                    *scalarValue(formatTimestamp(e.Time)),
+		"topic":                   *scalarValue(e.Topic),
+		"eligible_for_retry":      *scalarValue(strconv.FormatBool(e.EligibleForRetry)),
+		"data":                    *scalarValue(string(e.Data)),
+		"metadata":                *scalarValue(encodeMetadata(e.Metadata)),
+		"checksum":                *scalarValue(e.Checksum),
+		"source":                  *scalarValue(e.Source),
+		"group_id":                *scalarValue(e.GroupID),
+	}
+}
+
+func attemptRow(entry *models.LogEntry) map[string]bigquery.QueryParameterValue {
+	a := entry.Attempt
+	e := entry.Event
+	responseDataJSON, _ := json.Marshal(a.ResponseData)
+	return map[string]bigquery.QueryParameterValue{
+		"id":                   *scalarValue(a.ID),
+		"event_id":             *scalarValue(a.EventID),
+		"tenant_id":            *scalarValue(e.TenantID),
+		"destination_id":       *scalarValue(a.DestinationID),
+		"destination_type":     *scalarValue(a.DestinationType),
+		"topic":                *scalarValue(e.Topic),
+		"status":               *scalarValue(a.Status),
+		"time":                 *scalarValue(formatTimestamp(a.Time)),
+		"attempt_number":       *scalarValue(strconv.Itoa(a.AttemptNumber)),
+		"manual":               *scalarValue(strconv.FormatBool(a.Manual)),
+		"code":                 *scalarValue(a.Code),
+		"response_data":        *scalarValue(string(responseDataJSON)),
+		"destination_snapshot": *scalarValue(driver.EncodeDestinationSnapshot(a.DestinationSnapshot)),
+		"event_time":           *scalarValue(formatTimestamp(e.Time)),
+		"eligible_for_retry":   *scalarValue(strconv.FormatBool(e.EligibleForRetry)),
+		"event_data":           *scalarValue(string(e.Data)),
+		"event_metadata":       *scalarValue(encodeMetadata(e.Metadata)),
+		"event_checksum":       *scalarValue(e.Checksum),
+		"event_source":         *scalarValue(e.Source),
+		"error_code":           *scalarValue(a.ErrorCode),
+		"error_message":        *scalarValue(a.ErrorMessage),
+		"latency_ms":           *nullableInt64Value(a.LatencyMs),
+	}
+}
@@ -0,0 +1,68 @@
+package bqlogstore
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/logstore/driver"
+	"github.com/hookdeck/outpost/internal/logstore/drivertest"
+	"github.com/hookdeck/outpost/internal/util/testinfra"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/api/bigquery/v2"
+)
+
+func TestConformance(t *testing.T) {
+	testutil.CheckIntegrationTest(t)
+	t.Parallel()
+
+	drivertest.RunConformanceTests(t, newHarness)
+}
+
+type harness struct {
+	svc *bigquery.Service
+	cfg Config
+}
+
+func setupBigQuery(t *testing.T) (*bigquery.Service, Config) {
+	t.Helper()
+	t.Cleanup(testinfra.Start(t))
+
+	bqConfig := testinfra.NewBigQueryConfig(t)
+
+	ctx := context.Background()
+	svc, err := bqConfig.Service(ctx)
+	require.NoError(t, err)
+
+	cfg := Config{
+		ProjectID: bqConfig.ProjectID,
+		DatasetID: bqConfig.DatasetID,
+	}
+	require.NoError(t, EnsureSchema(ctx, svc, cfg))
+	// Idempotent on existing tables.
+	require.NoError(t, EnsureSchema(ctx, svc, cfg))
+
+	return svc, cfg
+}
+
+func newHarness(_ context.Context, t *testing.T) (drivertest.Harness, error) {
+	t.Helper()
+
+	svc, cfg := setupBigQuery(t)
+
+	return &harness{
+		svc: svc,
+		cfg: cfg,
+	}, nil
+}
+
+func (h *harness) Close() {}
+
+func (h *harness) FlushWrites(ctx context.Context) error {
+	// DML writes are visible to the next query, no flush needed
+	return nil
+}
+
+func (h *harness) MakeDriver(ctx context.Context) (driver.LogStore, error) {
+	return NewLogStore(h.svc, h.cfg), nil
+}
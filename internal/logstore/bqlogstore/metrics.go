@@ -0,0 +1,501 @@
+package bqlogstore
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logstore/bucket"
+	"github.com/hookdeck/outpost/internal/logstore/driver"
+)
+
+const (
+	defaultRowLimit     = 100000
+	metricsQueryTimeout = 30 * time.Second
+)
+
+// metricsCtx adds a fallback timeout to the context if the caller didn't set
+// one. The REST client abandons the query when the context is done; BigQuery
+// stops the job on its own once it exceeds its job timeout.
+func metricsCtx(ctx context.Context) (context.Context, context.CancelFunc) {
+	if _, ok := ctx.Deadline(); ok {
+		return ctx, func() {} // caller already set a deadline
+	}
+	return context.WithTimeout(ctx, metricsQueryTimeout)
+}
+
+// timeBucketExpr returns a GoogleSQL expression that truncates a TIMESTAMP
+// column to the given granularity, with the same anchors as the PostgreSQL
+// driver: 2000-01-01 for sub-day units, 1970-01-01 for days, 1970-01-04
+// (a Sunday) for weeks and January 1970 for months.
+func timeBucketExpr(col string, g *driver.Granularity) string {
+	bin := func(stepSeconds, anchorUnix int64) string {
+		return fmt.Sprintf(
+			"TIMESTAMP_SECONDS(%d + DIV(UNIX_SECONDS(%s) - %d, %d) * %d)",
+			anchorUnix, col, anchorUnix, stepSeconds, stepSeconds,
+		)
+	}
+	const anchor2000 = 946684800
+	const anchorSunday = 3 * 86400
+	switch g.Unit {
+	case "s":
+		return bin(int64(g.Value), anchor2000)
+	case "m":
+		return bin(int64(g.Value)*60, anchor2000)
+	case "h":
+		return bin(int64(g.Value)*3600, anchor2000)
+	case "d":
+		if g.Value == 1 {
+			return fmt.Sprintf("TIMESTAMP_TRUNC(%s, DAY, 'UTC')", col)
+		}
+		return bin(int64(g.Value)*86400, 0)
+	case "w":
+		return bin(int64(g.Value)*7*86400, anchorSunday)
+	case "M":
+		if g.Value == 1 {
+			return fmt.Sprintf("TIMESTAMP_TRUNC(%s, MONTH, 'UTC')", col)
+		}
+		return fmt.Sprintf(
+			"TIMESTAMP(DATE_ADD(DATE '1970-01-01', INTERVAL DIV((EXTRACT(YEAR FROM %s AT TIME ZONE 'UTC') - 1970) * 12 + EXTRACT(MONTH FROM %s AT TIME ZONE 'UTC') - 1, %d) * %d MONTH))",
+			col, col, g.Value, g.Value,
+		)
+	default:
+		return col
+	}
+}
+
+// groupBy returns the GROUP BY and ORDER BY clauses for the first n select
+// expressions, by position.
+func groupBy(n int) string {
+	if n == 0 {
+		return ""
+	}
+	positions := make([]string, n)
+	for i := range positions {
+		positions[i] = fmt.Sprintf("%d", i+1)
+	}
+	list := strings.Join(positions, ", ")
+	return " GROUP BY " + list + " HAVING COUNT(*) > 0 ORDER BY " + list
+}
+
+// ── Event Metrics ─────────────────────────────────────────────────────────
+
+func (s *logStore) QueryEventMetrics(ctx context.Context, req driver.MetricsRequest) (*driver.EventMetricsResponse, error) {
+	if err := driver.ValidateMetricsRequest(req); err != nil {
+		return nil, err
+	}
+	req.Measures = driver.EnrichMeasuresForRates(req.Measures)
+	ctx, cancel := metricsCtx(ctx)
+	defer cancel()
+
+	start := time.Now()
+
+	var (
+		selectExprs []string
+		groupCount  int
+		conditions  []string
+		p           params
+	)
+
+	type sf int
+	const (
+		sfTimeBucket sf = iota
+		sfTenantID
+		sfTopic
+		sfSource
+		sfDestID
+		sfCount
+	)
+	var order []sf
+
+	// Time bucket
+	if req.Granularity != nil {
+		selectExprs = append(selectExprs, timeBucketExpr("time", req.Granularity))
+		order = append(order, sfTimeBucket)
+	}
+
+	// Dimensions
+	needsUnnest := false
+	for _, dim := range req.Dimensions {
+		switch dim {
+		case "tenant_id":
+			selectExprs = append(selectExprs, "tenant_id")
+			order = append(order, sfTenantID)
+		case "topic":
+			selectExprs = append(selectExprs, "topic")
+			order = append(order, sfTopic)
+		case "source":
+			selectExprs = append(selectExprs, "source")
+			order = append(order, sfSource)
+		case "destination_id":
+			selectExprs = append(selectExprs, "destination_id")
+			order = append(order, sfDestID)
+			needsUnnest = true
+		}
+	}
+	groupCount = len(selectExprs)
+
+	// Measures
+	for _, measure := range req.Measures {
+		switch measure {
+		case "count":
+			selectExprs = append(selectExprs, "COUNT(*)")
+			order = append(order, sfCount)
+		}
+	}
+
+	// WHERE
+	if tenantIDs, ok := req.Filters["tenant_id"]; ok {
+		conditions = append(conditions, "tenant_id IN UNNEST("+p.strings(tenantIDs)+")")
+	}
+	conditions = append(conditions, "time >= "+p.timestamp(req.TimeRange.Start))
+	conditions = append(conditions, "time < "+p.timestamp(req.TimeRange.End))
+
+	if topics, ok := req.Filters["topic"]; ok {
+		conditions = append(conditions, "topic IN UNNEST("+p.strings(topics)+")")
+	}
+	if sources, ok := req.Filters["source"]; ok {
+		conditions = append(conditions, "source IN UNNEST("+p.strings(sources)+")")
+	}
+	if dests, ok := req.Filters["destination_id"]; ok {
+		conditions = append(conditions, matchedDestinationsCondition(p.strings(dests)))
+	}
+
+	// Build SQL — one row per matched destination when grouping by it
+	fromClause := "events"
+	if needsUnnest {
+		fromClause = "events, UNNEST(matched_destination_ids) AS destination_id"
+	}
+	query := "SELECT " + strings.Join(selectExprs, ", ") +
+		" FROM " + fromClause + " WHERE " + whereClause(conditions)
+	if groupCount > 0 {
+		query += groupBy(groupCount)
+	} else {
+		query += " HAVING COUNT(*) > 0"
+	}
+	query += fmt.Sprintf(" LIMIT %d", defaultRowLimit+1)
+
+	result, err := s.query(ctx, query, p.list)
+	if err != nil {
+		return nil, fmt.Errorf("query event metrics: %w", err)
+	}
+
+	data := []driver.EventMetricsDataPoint{}
+	for _, row := range result.rows {
+		r := rowReader{cells: row.F}
+		dp := driver.EventMetricsDataPoint{}
+		for i, f := range order {
+			switch f {
+			case sfTimeBucket:
+				t := r.time(i)
+				dp.TimeBucket = &t
+			case sfTenantID:
+				v := r.string(i)
+				dp.TenantID = &v
+			case sfTopic:
+				v := r.string(i)
+				dp.Topic = &v
+			case sfSource:
+				v := r.string(i)
+				dp.Source = &v
+			case sfDestID:
+				v := r.string(i)
+				dp.DestinationID = &v
+			case sfCount:
+				v := r.int(i)
+				dp.Count = &v
+			}
+		}
+		if r.err != nil {
+			return nil, fmt.Errorf("scan event metrics: %w", r.err)
+		}
+		data = append(data, dp)
+	}
+
+	truncated := len(data) > defaultRowLimit
+	if truncated {
+		data = data[:defaultRowLimit]
+	}
+
+	data, err = bucket.FillEventBuckets(data, req)
+	if err != nil {
+		return nil, fmt.Errorf("fill event buckets: %w: %w", driver.ErrResourceLimit, err)
+	}
+	driver.ComputeEventRates(data, req)
+
+	elapsed := time.Since(start)
+	return &driver.EventMetricsResponse{
+		Data: data,
+		Metadata: driver.MetricsMetadata{
+			QueryTimeMs: elapsed.Milliseconds(),
+			RowCount:    len(data),
+			RowLimit:    defaultRowLimit,
+			Truncated:   truncated,
+		},
+	}, nil
+}
+
+// ── Attempt Metrics ───────────────────────────────────────────────────────
+
+func (s *logStore) QueryAttemptMetrics(ctx context.Context, req driver.MetricsRequest) (*driver.AttemptMetricsResponse, error) {
+	if err := driver.ValidateMetricsRequest(req); err != nil {
+		return nil, err
+	}
+	req.Measures = driver.EnrichMeasuresForRates(req.Measures)
+	ctx, cancel := metricsCtx(ctx)
+	defer cancel()
+
+	start := time.Now()
+
+	var (
+		selectExprs []string
+		groupCount  int
+		conditions  []string
+		p           params
+	)
+
+	type sf int
+	const (
+		sfTimeBucket sf = iota
+		sfTenantID
+		sfDestID
+		sfDestType
+		sfTopic
+		sfSource
+		sfStatus
+		sfCode
+		sfManual
+		sfAttemptNumber
+		sfCount
+		sfSuccessCount
+		sfFailedCount
+		sfErrorRate
+		sfFirstAttempt
+		sfRetryCount
+		sfManualRetry
+		sfAvgAttemptNum
+		sfP50Latency
+		sfP95Latency
+	)
+	var order []sf
+
+	// Time bucket
+	if req.Granularity != nil {
+		selectExprs = append(selectExprs, timeBucketExpr("time", req.Granularity))
+		order = append(order, sfTimeBucket)
+	}
+
+	// Dimensions
+	for _, dim := range req.Dimensions {
+		switch dim {
+		case "tenant_id":
+			selectExprs = append(selectExprs, "tenant_id")
+			order = append(order, sfTenantID)
+		case "destination_id":
+			selectExprs = append(selectExprs, "destination_id")
+			order = append(order, sfDestID)
+		case "destination_type":
+			selectExprs = append(selectExprs, "destination_type")
+			order = append(order, sfDestType)
+		case "topic":
+			selectExprs = append(selectExprs, "topic")
+			order = append(order, sfTopic)
+		case "source":
+			selectExprs = append(selectExprs, "event_source")
+			order = append(order, sfSource)
+		case "status":
+			selectExprs = append(selectExprs, "status")
+			order = append(order, sfStatus)
+		case "code":
+			selectExprs = append(selectExprs, "code")
+			order = append(order, sfCode)
+		case "manual":
+			selectExprs = append(selectExprs, "manual")
+			order = append(order, sfManual)
+		case "attempt_number":
+			selectExprs = append(selectExprs, "attempt_number")
+			order = append(order, sfAttemptNumber)
+		}
+	}
+	groupCount = len(selectExprs)
+
+	// Measures
+	for _, measure := range req.Measures {
+		switch measure {
+		case "count":
+			selectExprs = append(selectExprs, "COUNT(*)")
+			order = append(order, sfCount)
+		case "successful_count":
+			selectExprs = append(selectExprs, "COUNTIF(status = 'success')")
+			order = append(order, sfSuccessCount)
+		case "failed_count":
+			selectExprs = append(selectExprs, "COUNTIF(status = 'failed')")
+			order = append(order, sfFailedCount)
+		case "error_rate":
+			selectExprs = append(selectExprs, "COUNTIF(status = 'failed') / COUNT(*)")
+			order = append(order, sfErrorRate)
+		case "first_attempt_count":
+			selectExprs = append(selectExprs, "COUNTIF(attempt_number = 1 AND NOT manual)")
+			order = append(order, sfFirstAttempt)
+		case "retry_count":
+			selectExprs = append(selectExprs, "COUNTIF(attempt_number > 1)")
+			order = append(order, sfRetryCount)
+		case "manual_retry_count":
+			selectExprs = append(selectExprs, "COUNTIF(manual)")
+			order = append(order, sfManualRetry)
+		case "avg_attempt_number":
+			selectExprs = append(selectExprs, "AVG(attempt_number)")
+			order = append(order, sfAvgAttemptNum)
+		case "p50_latency_ms":
+			selectExprs = append(selectExprs, "IFNULL(APPROX_QUANTILES(latency_ms, 100)[SAFE_OFFSET(50)], 0)")
+			order = append(order, sfP50Latency)
+		case "p95_latency_ms":
+			selectExprs = append(selectExprs, "IFNULL(APPROX_QUANTILES(latency_ms, 100)[SAFE_OFFSET(95)], 0)")
+			order = append(order, sfP95Latency)
+		}
+	}
+
+	// WHERE
+	if tenantIDs, ok := req.Filters["tenant_id"]; ok {
+		conditions = append(conditions, "tenant_id IN UNNEST("+p.strings(tenantIDs)+")")
+	}
+	conditions = append(conditions, "time >= "+p.timestamp(req.TimeRange.Start))
+	conditions = append(conditions, "time < "+p.timestamp(req.TimeRange.End))
+
+	if statuses, ok := req.Filters["status"]; ok {
+		conditions = append(conditions, "status IN UNNEST("+p.strings(statuses)+")")
+	}
+	if dests, ok := req.Filters["destination_id"]; ok {
+		conditions = append(conditions, "destination_id IN UNNEST("+p.strings(dests)+")")
+	}
+	if destTypes, ok := req.Filters["destination_type"]; ok {
+		conditions = append(conditions, "destination_type IN UNNEST("+p.strings(destTypes)+")")
+	}
+	if topics, ok := req.Filters["topic"]; ok {
+		conditions = append(conditions, "topic IN UNNEST("+p.strings(topics)+")")
+	}
+	if sources, ok := req.Filters["source"]; ok {
+		conditions = append(conditions, "event_source IN UNNEST("+p.strings(sources)+")")
+	}
+	if codes, ok := req.Filters["code"]; ok {
+		conditions = append(conditions, "code IN UNNEST("+p.strings(codes)+")")
+	}
+	if manuals, ok := req.Filters["manual"]; ok {
+		conditions = append(conditions, "manual IN UNNEST("+p.bools(manuals)+")")
+	}
+	if attemptNums, ok := req.Filters["attempt_number"]; ok {
+		conditions = append(conditions, "attempt_number IN UNNEST("+p.int64s(attemptNums)+")")
+	}
+
+	// Build SQL
+	query := "SELECT " + strings.Join(selectExprs, ", ") +
+		" FROM attempts WHERE " + whereClause(conditions)
+	if groupCount > 0 {
+		query += groupBy(groupCount)
+	} else {
+		query += " HAVING COUNT(*) > 0"
+	}
+	query += fmt.Sprintf(" LIMIT %d", defaultRowLimit+1)
+
+	result, err := s.query(ctx, query, p.list)
+	if err != nil {
+		return nil, fmt.Errorf("query attempt metrics: %w", err)
+	}
+
+	data := []driver.AttemptMetricsDataPoint{}
+	for _, row := range result.rows {
+		r := rowReader{cells: row.F}
+		dp := driver.AttemptMetricsDataPoint{}
+		for i, f := range order {
+			switch f {
+			case sfTimeBucket:
+				t := r.time(i)
+				dp.TimeBucket = &t
+			case sfTenantID:
+				v := r.string(i)
+				dp.TenantID = &v
+			case sfDestID:
+				v := r.string(i)
+				dp.DestinationID = &v
+			case sfDestType:
+				v := r.string(i)
+				dp.DestinationType = &v
+			case sfTopic:
+				v := r.string(i)
+				dp.Topic = &v
+			case sfSource:
+				v := r.string(i)
+				dp.Source = &v
+			case sfStatus:
+				v := r.string(i)
+				dp.Status = &v
+			case sfCode:
+				v := r.string(i)
+				dp.Code = &v
+			case sfManual:
+				v := r.bool(i)
+				dp.Manual = &v
+			case sfAttemptNumber:
+				v := r.int(i)
+				dp.AttemptNumber = &v
+			case sfCount:
+				v := r.int(i)
+				dp.Count = &v
+			case sfSuccessCount:
+				v := r.int(i)
+				dp.SuccessfulCount = &v
+			case sfFailedCount:
+				v := r.int(i)
+				dp.FailedCount = &v
+			case sfErrorRate:
+				v := r.float(i)
+				dp.ErrorRate = &v
+			case sfFirstAttempt:
+				v := r.int(i)
+				dp.FirstAttemptCount = &v
+			case sfRetryCount:
+				v := r.int(i)
+				dp.RetryCount = &v
+			case sfManualRetry:
+				v := r.int(i)
+				dp.ManualRetryCount = &v
+			case sfAvgAttemptNum:
+				v := r.float(i)
+				dp.AvgAttemptNumber = &v
+			case sfP50Latency:
+				v := r.float(i)
+				dp.P50LatencyMs = &v
+			case sfP95Latency:
+				v := r.float(i)
+				dp.P95LatencyMs = &v
+			}
+		}
+		if r.err != nil {
+			return nil, fmt.Errorf("scan attempt metrics: %w", r.err)
+		}
+		data = append(data, dp)
+	}
+
+	truncated := len(data) > defaultRowLimit
+	if truncated {
+		data = data[:defaultRowLimit]
+	}
+
+	data, err = bucket.FillAttemptBuckets(data, req)
+	if err != nil {
+		return nil, fmt.Errorf("fill attempt buckets: %w: %w", driver.ErrResourceLimit, err)
+	}
+	driver.ComputeAttemptRates(data, req)
+
+	elapsed := time.Since(start)
+	return &driver.AttemptMetricsResponse{
+		Data: data,
+		Metadata: driver.MetricsMetadata{
+			QueryTimeMs: elapsed.Milliseconds(),
+			RowCount:    len(data),
+			RowLimit:    defaultRowLimit,
+			Truncated:   truncated,
+		},
+	}, nil
+}
@@ -0,0 +1,67 @@
+package bqlogstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logstore/driver"
+	"github.com/hookdeck/outpost/internal/models"
+	"google.golang.org/api/bigquery/v2"
+)
+
+var _ driver.Pruner = (*logStore)(nil)
+
+// Prune deletes attempts past their status cutoff, then the events left
+// without attempts, except those under a legal hold. Each DELETE commits on
+// its own; a prune that fails halfway leaves orphaned events for the next
+// run.
+func (s *logStore) Prune(ctx context.Context, req driver.PruneRequest) (driver.PruneResponse, error) {
+	var resp driver.PruneResponse
+
+	for _, cutoff := range []struct {
+		status string
+		before *time.Time
+	}{
+		{models.AttemptStatusSuccess, req.SuccessBefore},
+		{models.AttemptStatusFailed, req.FailedBefore},
+		{models.AttemptStatusDeferred, req.DeferredBefore},
+	} {
+		if cutoff.before == nil {
+			continue
+		}
+		result, err := s.query(ctx, `
+			DELETE FROM attempts
+			WHERE status = @status AND time < @before
+			AND NOT (tenant_id IN UNNEST(@held_tenant_ids) OR event_id IN UNNEST(@held_event_ids))
+		`, []*bigquery.QueryParameter{
+			stringParam("status", cutoff.status),
+			timestampParam("before", *cutoff.before),
+			stringsParam("held_tenant_ids", req.HeldTenantIDs),
+			stringsParam("held_event_ids", req.HeldEventIDs),
+		})
+		if err != nil {
+			return resp, fmt.Errorf("prune %s attempts failed: %w", cutoff.status, err)
+		}
+		resp.AttemptsDeleted += result.affectedRows
+	}
+
+	if eventsBefore := req.EventsBefore(); eventsBefore != nil {
+		result, err := s.query(ctx, `
+			DELETE FROM events e
+			WHERE e.time < @before
+			AND NOT EXISTS (SELECT 1 FROM attempts a WHERE a.event_id = e.id)
+			AND NOT (e.tenant_id IN UNNEST(@held_tenant_ids) OR e.id IN UNNEST(@held_event_ids))
+		`, []*bigquery.QueryParameter{
+			timestampParam("before", *eventsBefore),
+			stringsParam("held_tenant_ids", req.HeldTenantIDs),
+			stringsParam("held_event_ids", req.HeldEventIDs),
+		})
+		if err != nil {
+			return resp, fmt.Errorf("prune events failed: %w", err)
+		}
+		resp.EventsDeleted = result.affectedRows
+	}
+
+	return resp, nil
+}
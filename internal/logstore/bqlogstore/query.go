@@ -0,0 +1,271 @@
+package bqlogstore
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/api/bigquery/v2"
+)
+
+// queryResult holds the rows of a completed query, or the number of rows a
+// DML statement changed.
+type queryResult struct {
+	rows         []*bigquery.TableRow
+	affectedRows int64
+}
+
+// query runs a GoogleSQL statement with named parameters and waits for it to
+// complete, reading every page of its result.
+func (s *logStore) query(ctx context.Context, sql string, queryParams []*bigquery.QueryParameter) (*queryResult, error) {
+	useLegacySQL := false
+	resp, err := s.svc.Jobs.Query(s.cfg.ProjectID, &bigquery.QueryRequest{
+		Query:           sql,
+		QueryParameters: queryParams,
+		ParameterMode:   "NAMED",
+		UseLegacySql:    &useLegacySQL,
+		DefaultDataset: &bigquery.DatasetReference{
+			ProjectId: s.cfg.ProjectID,
+			DatasetId: s.cfg.DatasetID,
+		},
+		Location:      s.cfg.Location,
+		FormatOptions: &bigquery.DataFormatOptions{UseInt64Timestamp: true},
+	}).Context(ctx).Do()
+	if err != nil {
+		return nil, err
+	}
+
+	result := &queryResult{
+		rows:         resp.Rows,
+		affectedRows: resp.NumDmlAffectedRows,
+	}
+	complete, pageToken := resp.JobComplete, resp.PageToken
+	for !complete || pageToken != "" {
+		if resp.JobReference == nil {
+			return nil, fmt.Errorf("query did not complete and returned no job reference")
+		}
+		call := s.svc.Jobs.GetQueryResults(s.cfg.ProjectID, resp.JobReference.JobId).
+			Location(resp.JobReference.Location).
+			FormatOptionsUseInt64Timestamp(true).
+			Context(ctx)
+		if pageToken != "" {
+			call = call.PageToken(pageToken)
+		}
+		page, err := call.Do()
+		if err != nil {
+			return nil, err
+		}
+		if page.JobComplete {
+			result.rows = append(result.rows, page.Rows...)
+			result.affectedRows = page.NumDmlAffectedRows
+		}
+		complete, pageToken = page.JobComplete, page.PageToken
+	}
+	return result, nil
+}
+
+// ── Parameters ───────────────────────────────────────────────────────────
+
+var (
+	typeString    = &bigquery.QueryParameterType{Type: "STRING"}
+	typeInt64     = &bigquery.QueryParameterType{Type: "INT64"}
+	typeBool      = &bigquery.QueryParameterType{Type: "BOOL"}
+	typeTimestamp = &bigquery.QueryParameterType{Type: "TIMESTAMP"}
+)
+
+func arrayOf(t *bigquery.QueryParameterType) *bigquery.QueryParameterType {
+	return &bigquery.QueryParameterType{Type: "ARRAY", ArrayType: t}
+}
+
+// scalarValue sends v even when empty, which BigQuery would otherwise read as
+// NULL.
+func scalarValue(v string) *bigquery.QueryParameterValue {
+	return &bigquery.QueryParameterValue{Value: v, ForceSendFields: []string{"Value"}}
+}
+
+// nullableInt64Value sends v, or NULL when v is nil.
+func nullableInt64Value(v *int64) *bigquery.QueryParameterValue {
+	if v == nil {
+		return &bigquery.QueryParameterValue{}
+	}
+	return scalarValue(strconv.FormatInt(*v, 10))
+}
+
+func arrayValue(values []*bigquery.QueryParameterValue) *bigquery.QueryParameterValue {
+	if values == nil {
+		values = []*bigquery.QueryParameterValue{}
+	}
+	return &bigquery.QueryParameterValue{ArrayValues: values, ForceSendFields: []string{"ArrayValues"}}
+}
+
+func stringValues(vs []string) *bigquery.QueryParameterValue {
+	values := make([]*bigquery.QueryParameterValue, len(vs))
+	for i, v := range vs {
+		values[i] = scalarValue(v)
+	}
+	return arrayValue(values)
+}
+
+func formatTimestamp(t time.Time) string {
+	return t.UTC().Format("2006-01-02 15:04:05.999999Z07:00")
+}
+
+func stringParam(name, v string) *bigquery.QueryParameter {
+	return &bigquery.QueryParameter{Name: name, ParameterType: typeString, ParameterValue: scalarValue(v)}
+}
+
+func int64Param(name string, v int64) *bigquery.QueryParameter {
+	return &bigquery.QueryParameter{Name: name, ParameterType: typeInt64, ParameterValue: scalarValue(strconv.FormatInt(v, 10))}
+}
+
+func timestampParam(name string, t time.Time) *bigquery.QueryParameter {
+	return &bigquery.QueryParameter{Name: name, ParameterType: typeTimestamp, ParameterValue: scalarValue(formatTimestamp(t))}
+}
+
+func stringsParam(name string, vs []string) *bigquery.QueryParameter {
+	return &bigquery.QueryParameter{Name: name, ParameterType: arrayOf(typeString), ParameterValue: stringValues(vs)}
+}
+
+// typedStringsParam sends vs as an array of t, leaving BigQuery to parse
+// each value (e.g., "true" as a BOOL).
+func typedStringsParam(name string, t *bigquery.QueryParameterType, vs []string) *bigquery.QueryParameter {
+	return &bigquery.QueryParameter{Name: name, ParameterType: arrayOf(t), ParameterValue: stringValues(vs)}
+}
+
+// params names query parameters in the order they're added.
+type params struct {
+	list []*bigquery.QueryParameter
+}
+
+func (p *params) name() string {
+	return fmt.Sprintf("p%d", len(p.list))
+}
+
+func (p *params) add(param *bigquery.QueryParameter) string {
+	p.list = append(p.list, param)
+	return "@" + param.Name
+}
+
+func (p *params) string(v string) string       { return p.add(stringParam(p.name(), v)) }
+func (p *params) int64(v int64) string         { return p.add(int64Param(p.name(), v)) }
+func (p *params) timestamp(t time.Time) string { return p.add(timestampParam(p.name(), t)) }
+func (p *params) strings(vs []string) string   { return p.add(stringsParam(p.name(), vs)) }
+func (p *params) bools(vs []string) string     { return p.add(typedStringsParam(p.name(), typeBool, vs)) }
+func (p *params) int64s(vs []string) string    { return p.add(typedStringsParam(p.name(), typeInt64, vs)) }
+
+func whereClause(conditions []string) string {
+	if len(conditions) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(conditions, " AND ")
+}
+
+// ── Rows ─────────────────────────────────────────────────────────────────
+
+// rowReader reads typed values from the cells of a result row. The REST API
+// returns every scalar as a string; the first parse error is kept in err.
+type rowReader struct {
+	cells []*bigquery.TableCell
+	err   error
+}
+
+func (r *rowReader) raw(i int) any {
+	if i >= len(r.cells) || r.cells[i] == nil {
+		return nil
+	}
+	return r.cells[i].V
+}
+
+func (r *rowReader) string(i int) string {
+	s, _ := r.raw(i).(string)
+	return s
+}
+
+func (r *rowReader) bool(i int) bool {
+	return r.string(i) == "true"
+}
+
+func (r *rowReader) int(i int) int {
+	s := r.string(i)
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("failed to parse integer column %d: %w", i, err)
+	}
+	return int(v)
+}
+
+// nullableInt64 reads a nullable INT64, nil when NULL.
+func (r *rowReader) nullableInt64(i int) *int64 {
+	if r.raw(i) == nil {
+		return nil
+	}
+	v := int64(r.int(i))
+	return &v
+}
+
+func (r *rowReader) float(i int) float64 {
+	s := r.string(i)
+	if s == "" {
+		return 0
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil && r.err == nil {
+		r.err = fmt.Errorf("failed to parse float column %d: %w", i, err)
+	}
+	return v
+}
+
+// time reads a TIMESTAMP, returned as microseconds since the epoch when
+// useInt64Timestamp is honored and as (possibly fractional) seconds
+// otherwise.
+func (r *rowReader) time(i int) time.Time {
+	s := r.string(i)
+	if s == "" {
+		return time.Time{}
+	}
+	if micros, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.UnixMicro(micros).UTC()
+	}
+	secs, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		if r.err == nil {
+			r.err = fmt.Errorf("failed to parse timestamp column %d: %w", i, err)
+		}
+		return time.Time{}
+	}
+	return time.UnixMicro(int64(secs * 1e6)).UTC()
+}
+
+// strings reads an ARRAY<STRING>, returned as a list of {"v": value} cells.
+func (r *rowReader) strings(i int) []string {
+	items, _ := r.raw(i).([]any)
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		cell, _ := item.(map[string]any)
+		v, _ := cell["v"].(string)
+		values = append(values, v)
+	}
+	return values
+}
+
+// structsParam sends rows as an ARRAY<STRUCT<fields>>, e.g. to MERGE a batch
+// with UNNEST.
+func structsParam(name string, fields []*bigquery.QueryParameterTypeStructTypes, rows []map[string]bigquery.QueryParameterValue) *bigquery.QueryParameter {
+	values := make([]*bigquery.QueryParameterValue, len(rows))
+	for i, row := range rows {
+		values[i] = &bigquery.QueryParameterValue{StructValues: row}
+	}
+	return &bigquery.QueryParameter{
+		Name: name,
+		ParameterType: arrayOf(&bigquery.QueryParameterType{
+			Type:        "STRUCT",
+			StructTypes: fields,
+		}),
+		ParameterValue: arrayValue(values),
+	}
+}
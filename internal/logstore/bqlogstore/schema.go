@@ -0,0 +1,123 @@
+package bqlogstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/googleapi"
+)
+
+// Tables are partitioned by day on time and clustered for the tenant-scoped
+// list queries. BigQuery has no primary keys; (time, id) is kept unique by
+// the MERGE statements in InsertMany.
+//
+// Columns added after the first release are NULLABLE, since BigQuery can
+// only add NULLABLE columns to an existing table.
+var tables = []*bigquery.Table{
+	{
+		TableReference: &bigquery.TableReference{TableId: "events"},
+		Schema: &bigquery.TableSchema{Fields: []*bigquery.TableFieldSchema{
+			{Name: "id", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "tenant_id", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "matched_destination_ids", Type: "STRING", Mode: "REPEATED"},
+			{Name: "time", Type: "TIMESTAMP", Mode: "REQUIRED"},
+			{Name: "topic", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "eligible_for_retry", Type: "BOOL", Mode: "REQUIRED"},
+			{Name: "data", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "metadata", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "checksum", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "source", Type: "STRING", Mode: "NULLABLE"},
+			{Name: "group_id", Type: "STRING", Mode: "NULLABLE"},
+		}},
+		TimePartitioning: &bigquery.TimePartitioning{Type: "DAY", Field: "time"},
+		Clustering:       &bigquery.Clustering{Fields: []string{"tenant_id", "id"}},
+	},
+	{
+		TableReference: &bigquery.TableReference{TableId: "attempts"},
+		Schema: &bigquery.TableSchema{Fields: []*bigquery.TableFieldSchema{
+			{Name: "id", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "event_id", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "tenant_id", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "destination_id", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "destination_type", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "topic", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "status", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "time", Type: "TIMESTAMP", Mode: "REQUIRED"},
+			{Name: "attempt_number", Type: "INT64", Mode: "REQUIRED"},
+			{Name: "manual", Type: "BOOL", Mode: "REQUIRED"},
+			{Name: "code", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "response_data", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "destination_snapshot", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "event_time", Type: "TIMESTAMP", Mode: "REQUIRED"},
+			{Name: "eligible_for_retry", Type: "BOOL", Mode: "REQUIRED"},
+			{Name: "event_data", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "event_metadata", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "event_checksum", Type: "STRING", Mode: "REQUIRED"},
+			{Name: "event_source", Type: "STRING", Mode: "NULLABLE"},
+			{Name: "error_code", Type: "STRING", Mode: "NULLABLE"},
+			{Name: "error_message", Type: "STRING", Mode: "NULLABLE"},
+			{Name: "latency_ms", Type: "INT64", Mode: "NULLABLE"},
+		}},
+		TimePartitioning: &bigquery.TimePartitioning{Type: "DAY", Field: "time"},
+		Clustering:       &bigquery.Clustering{Fields: []string{"tenant_id", "id"}},
+	},
+}
+
+// EnsureSchema creates the events and attempts tables in the configured
+// dataset. Existing tables gain the columns they are missing.
+func EnsureSchema(ctx context.Context, svc *bigquery.Service, cfg Config) error {
+	for _, schema := range tables {
+		table := *schema
+		table.TableReference = &bigquery.TableReference{
+			ProjectId: cfg.ProjectID,
+			DatasetId: cfg.DatasetID,
+			TableId:   schema.TableReference.TableId,
+		}
+		_, err := svc.Tables.Insert(cfg.ProjectID, cfg.DatasetID, &table).Context(ctx).Do()
+		var apiErr *googleapi.Error
+		if errors.As(err, &apiErr) && apiErr.Code == http.StatusConflict {
+			if err := addMissingColumns(ctx, svc, cfg, schema); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("create table %s failed: %w", table.TableReference.TableId, err)
+		}
+	}
+	return nil
+}
+
+func addMissingColumns(ctx context.Context, svc *bigquery.Service, cfg Config, schema *bigquery.Table) error {
+	tableID := schema.TableReference.TableId
+	existing, err := svc.Tables.Get(cfg.ProjectID, cfg.DatasetID, tableID).Context(ctx).Do()
+	if err != nil {
+		return fmt.Errorf("get table %s failed: %w", tableID, err)
+	}
+	if existing.Schema == nil {
+		existing.Schema = &bigquery.TableSchema{}
+	}
+
+	present := make(map[string]bool, len(existing.Schema.Fields))
+	for _, field := range existing.Schema.Fields {
+		present[field.Name] = true
+	}
+	fields := existing.Schema.Fields
+	for _, field := range schema.Schema.Fields {
+		if !present[field.Name] {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == len(existing.Schema.Fields) {
+		return nil
+	}
+
+	patch := &bigquery.Table{Schema: &bigquery.TableSchema{Fields: fields}}
+	if _, err := svc.Tables.Patch(cfg.ProjectID, cfg.DatasetID, tableID, patch).Context(ctx).Do(); err != nil {
+		return fmt.Errorf("add columns to table %s failed: %w", tableID, err)
+	}
+	return nil
+}
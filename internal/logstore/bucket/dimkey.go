@@ -19,6 +19,8 @@ func EventDimKey(dp *driver.EventMetricsDataPoint, dims []string) DimKey {
 			parts[i] = derefStr(dp.TenantID)
 		case "topic":
 			parts[i] = derefStr(dp.Topic)
+		case "source":
+			parts[i] = derefStr(dp.Source)
 		case "destination_id":
 			parts[i] = derefStr(dp.DestinationID)
 		}
@@ -39,6 +41,8 @@ func AttemptDimKey(dp *driver.AttemptMetricsDataPoint, dims []string) DimKey {
 			parts[i] = derefStr(dp.DestinationType)
 		case "topic":
 			parts[i] = derefStr(dp.Topic)
+		case "source":
+			parts[i] = derefStr(dp.Source)
 		case "status":
 			parts[i] = derefStr(dp.Status)
 		case "code":
@@ -137,6 +137,10 @@ func copyEventDims(dst, src *driver.EventMetricsDataPoint, dims []string) {
 			if src.Topic != nil {
 				dst.Topic = new(*src.Topic)
 			}
+		case "source":
+			if src.Source != nil {
+				dst.Source = new(*src.Source)
+			}
 		case "destination_id":
 			if src.DestinationID != nil {
 				dst.DestinationID = new(*src.DestinationID)
@@ -233,6 +237,10 @@ func zeroAttemptDP(slot time.Time, measures []string) driver.AttemptMetricsDataP
 			dp.SuccessfulRate = new(0.0)
 		case "failed_rate":
 			dp.FailedRate = new(0.0)
+		case "p50_latency_ms":
+			dp.P50LatencyMs = new(0.0)
+		case "p95_latency_ms":
+			dp.P95LatencyMs = new(0.0)
 		}
 	}
 	return dp
@@ -253,6 +261,10 @@ func copyAttemptDims(dst, src *driver.AttemptMetricsDataPoint, dims []string) {
 			if src.Topic != nil {
 				dst.Topic = new(*src.Topic)
 			}
+		case "source":
+			if src.Source != nil {
+				dst.Source = new(*src.Source)
+			}
 		case "status":
 			if src.Status != nil {
 				dst.Status = new(*src.Status)
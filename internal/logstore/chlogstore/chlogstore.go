@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -194,6 +196,28 @@ func buildEventQuery(table string, req driver.ListEventRequest, q pagination.Que
 		args = append(args, req.Topics)
 	}
 
+	if len(req.Sources) > 0 {
+		conditions = append(conditions, "source IN ?")
+		args = append(args, req.Sources)
+	}
+
+	if req.GroupID != "" {
+		conditions = append(conditions, "group_id = ?")
+		args = append(args, req.GroupID)
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(req.Metadata)) {
+		conditions = append(conditions, "JSONExtractString(metadata, ?) = ?")
+		args = append(args, key, req.Metadata[key])
+	}
+
+	// Matches the expression of the events search index; the terms are
+	// already lowercase.
+	for _, term := range driver.SearchTerms(req.Search) {
+		conditions = append(conditions, "hasToken(lower(data), ?)")
+		args = append(args, term)
+	}
+
 	if req.TimeFilter.GTE != nil {
 		conditions = append(conditions, "event_time >= ?")
 		args = append(args, *req.TimeFilter.GTE)
@@ -234,7 +258,10 @@ func buildEventQuery(table string, req driver.ListEventRequest, q pagination.Que
 			eligible_for_retry,
 			event_time,
 			metadata,
-			data
+			data,
+			checksum,
+			source,
+			group_id
 		FROM %s
 		WHERE %s
 		%s
@@ -256,6 +283,9 @@ func scanEvents(rows clickhouse.Rows) ([]eventWithPosition, error) {
 			eventTime             time.Time
 			metadataStr           string
 			dataStr               string
+			checksum              string
+			source                string
+			groupID               string
 		)
 
 		err := rows.Scan(
@@ -267,6 +297,9 @@ func scanEvents(rows clickhouse.Rows) ([]eventWithPosition, error) {
 			&eventTime,
 			&metadataStr,
 			&dataStr,
+			&checksum,
+			&source,
+			&groupID,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan failed: %w", err)
@@ -294,6 +327,9 @@ func scanEvents(rows clickhouse.Rows) ([]eventWithPosition, error) {
 				Time:                  eventTime,
 				Data:                  json.RawMessage(dataStr),
 				Metadata:              metadata,
+				Checksum:              checksum,
+				Source:                source,
+				GroupID:               groupID,
 			},
 			eventTime: eventTime,
 		})
@@ -418,6 +454,11 @@ func buildAttemptQuery(table string, req driver.ListAttemptRequest, q pagination
 		args = append(args, req.Topics)
 	}
 
+	if len(req.Sources) > 0 {
+		conditions = append(conditions, "event_source IN ?")
+		args = append(args, req.Sources)
+	}
+
 	if req.TimeFilter.GTE != nil {
 		conditions = append(conditions, "attempt_time >= ?")
 		args = append(args, *req.TimeFilter.GTE)
@@ -466,7 +507,13 @@ func buildAttemptQuery(table string, req driver.ListAttemptRequest, q pagination
 			code,
 			response_data,
 			manual,
-			attempt_number
+			attempt_number,
+			destination_snapshot,
+			event_checksum,
+			event_source,
+			error_code,
+			error_message,
+			latency_ms
 		FROM %s
 		WHERE %s
 		%s
@@ -496,6 +543,12 @@ func scanAttemptRecords(rows clickhouse.Rows) ([]attemptRecordWithPosition, erro
 			responseDataStr  string
 			manual           bool
 			attemptNumber    uint32
+			snapshotStr      string
+			eventChecksum    string
+			eventSource      string
+			errorCode        string
+			errorMessage     string
+			latencyMs        *int64
 		)
 
 		err := rows.Scan(
@@ -515,6 +568,12 @@ func scanAttemptRecords(rows clickhouse.Rows) ([]attemptRecordWithPosition, erro
 			&responseDataStr,
 			&manual,
 			&attemptNumber,
+			&snapshotStr,
+			&eventChecksum,
+			&eventSource,
+			&errorCode,
+			&errorMessage,
+			&latencyMs,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan failed: %w", err)
@@ -533,21 +592,29 @@ func scanAttemptRecords(rows clickhouse.Rows) ([]attemptRecordWithPosition, erro
 				return nil, fmt.Errorf("failed to unmarshal response_data: %w", err)
 			}
 		}
+		snapshot, err := driver.DecodeDestinationSnapshot(snapshotStr)
+		if err != nil {
+			return nil, err
+		}
 
 		results = append(results, attemptRecordWithPosition{
 			AttemptRecord: &driver.AttemptRecord{
 				Attempt: &models.Attempt{
-					ID:              attemptID,
-					TenantID:        tenantID,
-					EventID:         eventID,
-					DestinationID:   destinationID,
-					DestinationType: destinationType,
-					AttemptNumber:   int(attemptNumber),
-					Manual:          manual,
-					Status:          status,
-					Time:            attemptTime,
-					Code:            code,
-					ResponseData:    responseData,
+					ID:                  attemptID,
+					TenantID:            tenantID,
+					EventID:             eventID,
+					DestinationID:       destinationID,
+					DestinationType:     destinationType,
+					AttemptNumber:       int(attemptNumber),
+					Manual:              manual,
+					Status:              status,
+					Time:                attemptTime,
+					Code:                code,
+					ResponseData:        responseData,
+					DestinationSnapshot: snapshot,
+					ErrorCode:           errorCode,
+					ErrorMessage:        errorMessage,
+					LatencyMs:           latencyMs,
 				},
 				Event: &models.Event{
 					ID:               eventID,
@@ -558,6 +625,8 @@ func scanAttemptRecords(rows clickhouse.Rows) ([]attemptRecordWithPosition, erro
 					Time:             eventTime,
 					Data:             json.RawMessage(dataStr),
 					Metadata:         metadata,
+					Checksum:         eventChecksum,
+					Source:           eventSource,
 				},
 			},
 			attemptTime: attemptTime,
@@ -594,7 +663,10 @@ func (s *logStoreImpl) RetrieveEvent(ctx context.Context, req driver.RetrieveEve
 			eligible_for_retry,
 			event_time,
 			metadata,
-			data
+			data,
+			checksum,
+			source,
+			group_id
 		FROM %s
 		WHERE %s
 		LIMIT 1`, s.eventsTable, whereClause)
@@ -613,6 +685,9 @@ func (s *logStoreImpl) RetrieveEvent(ctx context.Context, req driver.RetrieveEve
 		&event.Time,
 		&metadataStr,
 		&dataStr,
+		&event.Checksum,
+		&event.Source,
+		&event.GroupID,
 	); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, nil
@@ -669,7 +744,13 @@ func (s *logStoreImpl) RetrieveAttempt(ctx context.Context, req driver.RetrieveA
 			code,
 			response_data,
 			manual,
-			attempt_number
+			attempt_number,
+			destination_snapshot,
+			event_checksum,
+			event_source,
+			error_code,
+			error_message,
+			latency_ms
 		FROM %s
 		WHERE %s
 		LIMIT 1`, s.attemptsTable, whereClause)
@@ -693,6 +774,12 @@ func (s *logStoreImpl) RetrieveAttempt(ctx context.Context, req driver.RetrieveA
 		responseDataStr  string
 		manual           bool
 		attemptNumber    uint32
+		snapshotStr      string
+		eventChecksum    string
+		eventSource      string
+		errorCode        string
+		errorMessage     string
+		latencyMs        *int64
 	)
 
 	err := row.Scan(
@@ -712,6 +799,12 @@ func (s *logStoreImpl) RetrieveAttempt(ctx context.Context, req driver.RetrieveA
 		&responseDataStr,
 		&manual,
 		&attemptNumber,
+		&snapshotStr,
+		&eventChecksum,
+		&eventSource,
+		&errorCode,
+		&errorMessage,
+		&latencyMs,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -733,20 +826,28 @@ func (s *logStoreImpl) RetrieveAttempt(ctx context.Context, req driver.RetrieveA
 			return nil, fmt.Errorf("failed to unmarshal response_data: %w", err)
 		}
 	}
+	snapshot, err := driver.DecodeDestinationSnapshot(snapshotStr)
+	if err != nil {
+		return nil, err
+	}
 
 	return &driver.AttemptRecord{
 		Attempt: &models.Attempt{
-			ID:              attemptID,
-			TenantID:        tenantID,
-			EventID:         eventID,
-			DestinationID:   destinationID,
-			DestinationType: destinationType,
-			AttemptNumber:   int(attemptNumber),
-			Manual:          manual,
-			Status:          status,
-			Time:            attemptTime,
-			Code:            code,
-			ResponseData:    responseData,
+			ID:                  attemptID,
+			TenantID:            tenantID,
+			EventID:             eventID,
+			DestinationID:       destinationID,
+			DestinationType:     destinationType,
+			AttemptNumber:       int(attemptNumber),
+			Manual:              manual,
+			Status:              status,
+			Time:                attemptTime,
+			Code:                code,
+			ResponseData:        responseData,
+			DestinationSnapshot: snapshot,
+			ErrorCode:           errorCode,
+			ErrorMessage:        errorMessage,
+			LatencyMs:           latencyMs,
 		},
 		Event: &models.Event{
 			ID:               eventID,
@@ -757,6 +858,8 @@ func (s *logStoreImpl) RetrieveAttempt(ctx context.Context, req driver.RetrieveA
 			Time:             eventTime,
 			Data:             json.RawMessage(dataStr),
 			Metadata:         metadata,
+			Checksum:         eventChecksum,
+			Source:           eventSource,
 		},
 	}, nil
 }
@@ -773,10 +876,11 @@ func (s *logStoreImpl) InsertMany(ctx context.Context, entries []*models.LogEntr
 
 	// Extract and dedupe events by ID, skipping retry attempts.
 	// Retries (AttemptNumber > 1) carry identical event data — the event row
-	// already exists from the first attempt's batch.
+	// already exists from the first attempt's batch, unless WriteEvent says
+	// otherwise.
 	eventMap := make(map[string]*models.Event)
 	for _, entry := range entries {
-		if entry.Attempt.AttemptNumber <= 1 {
+		if entry.Attempt.AttemptNumber <= 1 || entry.WriteEvent {
 			eventMap[entry.Event.ID] = entry.Event
 		}
 	}
@@ -784,7 +888,7 @@ func (s *logStoreImpl) InsertMany(ctx context.Context, entries []*models.LogEntr
 	if len(eventMap) > 0 {
 		eventBatch, err := s.chDB.PrepareBatch(ctx,
 			fmt.Sprintf(`INSERT INTO %s (
-				event_id, tenant_id, matched_destination_ids, topic, eligible_for_retry, event_time, metadata, data
+				event_id, tenant_id, matched_destination_ids, topic, eligible_for_retry, event_time, metadata, data, checksum, source, group_id
 			)`, s.eventsTable),
 		)
 		if err != nil {
@@ -815,6 +919,9 @@ func (s *logStoreImpl) InsertMany(ctx context.Context, entries []*models.LogEntr
 				e.Time,
 				string(metadataJSON),
 				string(e.Data),
+				e.Checksum,
+				e.Source,
+				e.GroupID,
 			); err != nil {
 				return fmt.Errorf("events batch append failed: %w", err)
 			}
@@ -829,7 +936,8 @@ func (s *logStoreImpl) InsertMany(ctx context.Context, entries []*models.LogEntr
 	attemptBatch, err := s.chDB.PrepareBatch(ctx,
 		fmt.Sprintf(`INSERT INTO %s (
 			event_id, tenant_id, destination_id, destination_type, topic, eligible_for_retry, event_time, metadata, data,
-			attempt_id, status, attempt_time, code, response_data, manual, attempt_number
+			attempt_id, status, attempt_time, code, response_data, manual, attempt_number, destination_snapshot, event_checksum,
+			event_source, error_code, error_message, latency_ms
 		)`, s.attemptsTable),
 	)
 	if err != nil {
@@ -871,6 +979,12 @@ func (s *logStoreImpl) InsertMany(ctx context.Context, entries []*models.LogEntr
 			string(responseDataJSON),
 			a.Manual,
 			uint32(a.AttemptNumber),
+			driver.EncodeDestinationSnapshot(a.DestinationSnapshot),
+			event.Checksum,
+			event.Source,
+			a.ErrorCode,
+			a.ErrorMessage,
+			a.LatencyMs,
 		); err != nil {
 			return fmt.Errorf("attempts batch append failed: %w", err)
 		}
@@ -80,16 +80,7 @@ func (h *harness) Close() {
 
 func (h *harness) FlushWrites(ctx context.Context) error {
 	// Force ClickHouse to merge parts and deduplicate rows on both tables
-	eventsTable := "events"
-	attemptsTable := "attempts"
-	if h.deploymentID != "" {
-		eventsTable = h.deploymentID + "_events"
-		attemptsTable = h.deploymentID + "_attempts"
-	}
-	if err := h.chDB.Exec(ctx, "OPTIMIZE TABLE "+eventsTable+" FINAL"); err != nil {
-		return err
-	}
-	return h.chDB.Exec(ctx, "OPTIMIZE TABLE "+attemptsTable+" FINAL")
+	return NewLogStore(h.chDB, h.deploymentID).(driver.Compactor).Flush(ctx)
 }
 
 func (h *harness) MakeDriver(ctx context.Context) (driver.LogStore, error) {
@@ -0,0 +1,102 @@
+package chlogstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hookdeck/outpost/internal/logstore/driver"
+)
+
+var _ driver.Compactor = (*logStoreImpl)(nil)
+
+// Stats reports the active parts, pending merges and mutations of the events
+// and attempts tables from ClickHouse's system tables.
+func (s *logStoreImpl) Stats(ctx context.Context) (*driver.CompactionStats, error) {
+	stats := &driver.CompactionStats{Tables: []driver.TableCompactionStats{
+		{Table: s.eventsTable},
+		{Table: s.attemptsTable},
+	}}
+	byTable := map[string]*driver.TableCompactionStats{
+		s.eventsTable:   &stats.Tables[0],
+		s.attemptsTable: &stats.Tables[1],
+	}
+
+	rows, err := s.chDB.Query(ctx, `
+		SELECT table, count(), sum(rows), sum(bytes_on_disk)
+		FROM system.parts
+		WHERE database = currentDatabase() AND active AND table IN (?, ?)
+		GROUP BY table
+	`, s.eventsTable, s.attemptsTable)
+	if err != nil {
+		return nil, fmt.Errorf("query parts failed: %w", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var (
+			table                   string
+			parts, rowCount, onDisk uint64
+		)
+		if err := rows.Scan(&table, &parts, &rowCount, &onDisk); err != nil {
+			return nil, fmt.Errorf("scan parts failed: %w", err)
+		}
+		if t, ok := byTable[table]; ok {
+			t.ActiveParts, t.Rows, t.BytesOnDisk = parts, rowCount, onDisk
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("query parts failed: %w", err)
+	}
+
+	merges, err := s.countByTable(ctx, "system.merges", "")
+	if err != nil {
+		return nil, fmt.Errorf("query merges failed: %w", err)
+	}
+	mutations, err := s.countByTable(ctx, "system.mutations", "AND NOT is_done")
+	if err != nil {
+		return nil, fmt.Errorf("query mutations failed: %w", err)
+	}
+	for table, t := range byTable {
+		t.MergesInProgress = merges[table]
+		t.PendingMutations = mutations[table]
+	}
+	return stats, nil
+}
+
+// countByTable counts the rows of a system table per log table.
+func (s *logStoreImpl) countByTable(ctx context.Context, systemTable, condition string) (map[string]uint64, error) {
+	rows, err := s.chDB.Query(ctx, fmt.Sprintf(`
+		SELECT table, count()
+		FROM %s
+		WHERE database = currentDatabase() AND table IN (?, ?) %s
+		GROUP BY table
+	`, systemTable, condition), s.eventsTable, s.attemptsTable)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	counts := make(map[string]uint64, 2)
+	for rows.Next() {
+		var (
+			table string
+			count uint64
+		)
+		if err := rows.Scan(&table, &count); err != nil {
+			return nil, err
+		}
+		counts[table] = count
+	}
+	return counts, rows.Err()
+}
+
+// Flush runs OPTIMIZE ... FINAL on both tables, merging every partition into
+// a single deduplicated part. It rewrites the tables and can take a long time
+// on large deployments.
+func (s *logStoreImpl) Flush(ctx context.Context) error {
+	for _, table := range []string{s.eventsTable, s.attemptsTable} {
+		if err := s.chDB.Exec(ctx, "OPTIMIZE TABLE "+table+" FINAL"); err != nil {
+			return fmt.Errorf("optimize %s failed: %w", table, err)
+		}
+	}
+	return nil
+}
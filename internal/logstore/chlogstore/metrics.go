@@ -91,6 +91,7 @@ func (s *logStoreImpl) QueryEventMetrics(ctx context.Context, req driver.Metrics
 		sfTimeBucket sf = iota
 		sfTenantID
 		sfTopic
+		sfSource
 		sfDestID
 		sfCount
 	)
@@ -115,6 +116,10 @@ func (s *logStoreImpl) QueryEventMetrics(ctx context.Context, req driver.Metrics
 			selectExprs = append(selectExprs, "topic")
 			groupExprs = append(groupExprs, "topic")
 			order = append(order, sfTopic)
+		case "source":
+			selectExprs = append(selectExprs, "source")
+			groupExprs = append(groupExprs, "source")
+			order = append(order, sfSource)
 		case "destination_id":
 			selectExprs = append(selectExprs, "destination_id")
 			groupExprs = append(groupExprs, "destination_id")
@@ -144,6 +149,9 @@ func (s *logStoreImpl) QueryEventMetrics(ctx context.Context, req driver.Metrics
 	if topics, ok := req.Filters["topic"]; ok {
 		conditions, args = addInFilter(conditions, args, "topic", topics)
 	}
+	if sources, ok := req.Filters["source"]; ok {
+		conditions, args = addInFilter(conditions, args, "source", sources)
+	}
 	if dests, ok := req.Filters["destination_id"]; ok {
 		conditions = append(conditions, "hasAny(matched_destination_ids, ?)")
 		args = append(args, dests)
@@ -188,6 +196,7 @@ func (s *logStoreImpl) QueryEventMetrics(ctx context.Context, req driver.Metrics
 		tbVal       time.Time
 		tenantIDVal string
 		topicVal    string
+		sourceVal   string
 		destIDVal   string
 		countVal    uint64
 	)
@@ -200,6 +209,8 @@ func (s *logStoreImpl) QueryEventMetrics(ctx context.Context, req driver.Metrics
 			scanDests[i] = &tenantIDVal
 		case sfTopic:
 			scanDests[i] = &topicVal
+		case sfSource:
+			scanDests[i] = &sourceVal
 		case sfDestID:
 			scanDests[i] = &destIDVal
 		case sfCount:
@@ -225,6 +236,9 @@ func (s *logStoreImpl) QueryEventMetrics(ctx context.Context, req driver.Metrics
 			case sfTopic:
 				v := topicVal
 				dp.Topic = &v
+			case sfSource:
+				v := sourceVal
+				dp.Source = &v
 			case sfDestID:
 				v := destIDVal
 				dp.DestinationID = &v
@@ -288,6 +302,7 @@ func (s *logStoreImpl) QueryAttemptMetrics(ctx context.Context, req driver.Metri
 		sfDestID
 		sfDestType
 		sfTopic
+		sfSource
 		sfStatus
 		sfCode
 		sfManual
@@ -300,6 +315,8 @@ func (s *logStoreImpl) QueryAttemptMetrics(ctx context.Context, req driver.Metri
 		sfRetryCount
 		sfManualRetry
 		sfAvgAttemptNum
+		sfP50Latency
+		sfP95Latency
 	)
 	var order []sf
 
@@ -330,6 +347,10 @@ func (s *logStoreImpl) QueryAttemptMetrics(ctx context.Context, req driver.Metri
 			selectExprs = append(selectExprs, "topic")
 			groupExprs = append(groupExprs, "topic")
 			order = append(order, sfTopic)
+		case "source":
+			selectExprs = append(selectExprs, "event_source")
+			groupExprs = append(groupExprs, "event_source")
+			order = append(order, sfSource)
 		case "status":
 			selectExprs = append(selectExprs, "status")
 			groupExprs = append(groupExprs, "status")
@@ -379,6 +400,12 @@ func (s *logStoreImpl) QueryAttemptMetrics(ctx context.Context, req driver.Metri
 		case "avg_attempt_number":
 			selectExprs = append(selectExprs, "avg(attempt_number)")
 			order = append(order, sfAvgAttemptNum)
+		case "p50_latency_ms":
+			selectExprs = append(selectExprs, "ifNull(quantile(0.5)(latency_ms), 0)")
+			order = append(order, sfP50Latency)
+		case "p95_latency_ms":
+			selectExprs = append(selectExprs, "ifNull(quantile(0.95)(latency_ms), 0)")
+			order = append(order, sfP95Latency)
 		}
 	}
 
@@ -403,6 +430,9 @@ func (s *logStoreImpl) QueryAttemptMetrics(ctx context.Context, req driver.Metri
 	if topics, ok := req.Filters["topic"]; ok {
 		conditions, args = addInFilter(conditions, args, "topic", topics)
 	}
+	if sources, ok := req.Filters["source"]; ok {
+		conditions, args = addInFilter(conditions, args, "event_source", sources)
+	}
 	if codes, ok := req.Filters["code"]; ok {
 		conditions, args = addInFilter(conditions, args, "code", codes)
 	}
@@ -440,6 +470,7 @@ func (s *logStoreImpl) QueryAttemptMetrics(ctx context.Context, req driver.Metri
 		destIDVal        string
 		destTypeVal      string
 		topicVal         string
+		sourceVal        string
 		statusVal        string
 		codeVal          string
 		manualVal        bool
@@ -452,6 +483,8 @@ func (s *logStoreImpl) QueryAttemptMetrics(ctx context.Context, req driver.Metri
 		retryCount       uint64
 		manualRetry      uint64
 		avgAttemptNum    float64
+		p50Latency       float64
+		p95Latency       float64
 	)
 
 	scanDests := make([]any, len(order))
@@ -467,6 +500,8 @@ func (s *logStoreImpl) QueryAttemptMetrics(ctx context.Context, req driver.Metri
 			scanDests[i] = &destTypeVal
 		case sfTopic:
 			scanDests[i] = &topicVal
+		case sfSource:
+			scanDests[i] = &sourceVal
 		case sfStatus:
 			scanDests[i] = &statusVal
 		case sfCode:
@@ -491,6 +526,10 @@ func (s *logStoreImpl) QueryAttemptMetrics(ctx context.Context, req driver.Metri
 			scanDests[i] = &manualRetry
 		case sfAvgAttemptNum:
 			scanDests[i] = &avgAttemptNum
+		case sfP50Latency:
+			scanDests[i] = &p50Latency
+		case sfP95Latency:
+			scanDests[i] = &p95Latency
 		}
 	}
 
@@ -518,6 +557,9 @@ func (s *logStoreImpl) QueryAttemptMetrics(ctx context.Context, req driver.Metri
 			case sfTopic:
 				v := topicVal
 				dp.Topic = &v
+			case sfSource:
+				v := sourceVal
+				dp.Source = &v
 			case sfStatus:
 				v := statusVal
 				dp.Status = &v
@@ -554,6 +596,12 @@ func (s *logStoreImpl) QueryAttemptMetrics(ctx context.Context, req driver.Metri
 			case sfAvgAttemptNum:
 				v := avgAttemptNum
 				dp.AvgAttemptNumber = &v
+			case sfP50Latency:
+				v := p50Latency
+				dp.P50LatencyMs = &v
+			case sfP95Latency:
+				v := p95Latency
+				dp.P95LatencyMs = &v
 			}
 		}
 		data = append(data, dp)
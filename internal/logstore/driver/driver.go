@@ -2,7 +2,13 @@ package driver
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
 	"time"
+	"unicode"
 
 	"github.com/hookdeck/outpost/internal/models"
 )
@@ -30,6 +36,80 @@ type LogStore interface {
 	Metrics
 }
 
+// Pruner is implemented by drivers that delete expired records on request.
+// Drivers that expire records natively (e.g., ClickHouse TTLs) don't
+// implement it.
+type Pruner interface {
+	Prune(context.Context, PruneRequest) (PruneResponse, error)
+}
+
+// PruneRequest sets, per attempt status, the time before which attempts are
+// deleted. A nil cutoff keeps attempts of that status. Events are deleted
+// once they have no attempts left and are older than the latest cutoff.
+type PruneRequest struct {
+	SuccessBefore *time.Time
+	FailedBefore  *time.Time
+	// DeferredBefore applies to attempts the destination deferred, which
+	// await their redelivery.
+	DeferredBefore *time.Time
+	// HeldTenantIDs and HeldEventIDs exempt the attempts and events of those
+	// tenants and events from pruning, for legal holds.
+	HeldTenantIDs []string
+	HeldEventIDs  []string
+}
+
+type PruneResponse struct {
+	AttemptsDeleted int64
+	EventsDeleted   int64
+}
+
+// HasHolds reports whether any record is exempt from pruning.
+func (r PruneRequest) HasHolds() bool {
+	return len(r.HeldTenantIDs) > 0 || len(r.HeldEventIDs) > 0
+}
+
+// IsHeld reports whether the records of an event are exempt from pruning.
+func (r PruneRequest) IsHeld(tenantID, eventID string) bool {
+	return slices.Contains(r.HeldTenantIDs, tenantID) || slices.Contains(r.HeldEventIDs, eventID)
+}
+
+// EventsBefore returns the cutoff for orphaned events: the latest attempt
+// cutoff, or nil when no attempts are pruned.
+func (r PruneRequest) EventsBefore() *time.Time {
+	var latest *time.Time
+	for _, cutoff := range []*time.Time{r.SuccessBefore, r.FailedBefore, r.DeferredBefore} {
+		if cutoff != nil && (latest == nil || cutoff.After(*latest)) {
+			latest = cutoff
+		}
+	}
+	return latest
+}
+
+// Compactor is implemented by eventually consistent drivers, whose reads can
+// return duplicate or superseded rows until background merges complete
+// (e.g., ClickHouse ReplacingMergeTree).
+type Compactor interface {
+	// Stats reports the merge state of the driver's tables.
+	Stats(context.Context) (*CompactionStats, error)
+	// Flush merges all parts so that every write is read in its final form.
+	Flush(context.Context) error
+}
+
+type CompactionStats struct {
+	Tables []TableCompactionStats
+}
+
+// TableCompactionStats is the merge state of one table. More than one active
+// part per partition means rows may still be deduplicated by a merge.
+type TableCompactionStats struct {
+	Table            string
+	ActiveParts      uint64
+	Rows             uint64
+	BytesOnDisk      uint64
+	MergesInProgress uint64
+	PendingMutations uint64
+}
+
 type ListEventRequest struct {
 	Next           string
 	Prev           string
@@ -39,7 +119,28 @@ type ListEventRequest struct {
 	EventIDs       []string   // optional - filter by event ID
 	DestinationIDs []string   // optional
 	Topics         []string   // optional
-	SortOrder      string     // optional: "asc", "desc" (default: "desc")
+	Sources        []string   // optional - filter by event source
+	GroupID        string     // optional - filter by publish group
+	// Metadata filters events by metadata: an event matches when it has
+	// every key with its value.
+	Metadata map[string]string // optional
+	// Search is a full-text search over event data: an event matches when
+	// its data has every search term, case-insensitively. Drivers without
+	// full-text search return ErrSearchNotSupported when it is set.
+	Search    string // optional
+	SortOrder string // optional: "asc", "desc" (default: "desc")
+}
+
+// ErrSearchNotSupported is returned by drivers that can't search event data.
+var ErrSearchNotSupported = errors.New("event data search is not supported by the log store")
+
+// SearchTerms splits a search, or the data it is searched in, into its
+// lowercase terms: the runs of letters and digits, as full-text indexes
+// tokenize text.
+func SearchTerms(search string) []string {
+	return strings.FieldsFunc(strings.ToLower(search), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
 }
 
 type ListEventResponse struct {
@@ -59,6 +160,7 @@ type ListAttemptRequest struct {
 	DestinationTypes []string   // optional - filter by destination type
 	Status           string     // optional: "success", "failed"
 	Topics           []string   // optional
+	Sources          []string   // optional - filter by event source
 	SortOrder        string     // optional: "asc", "desc" (default: "desc")
 }
 
@@ -104,3 +206,25 @@ func DedupeEntriesByAttemptID(entries []*models.LogEntry) []*models.LogEntry {
 	}
 	return deduped
 }
+
+// EncodeDestinationSnapshot serializes an attempt's destination snapshot for
+// a text column, or returns "" when there is none.
+func EncodeDestinationSnapshot(snapshot *models.DestinationSnapshot) string {
+	if snapshot == nil {
+		return ""
+	}
+	b, _ := json.Marshal(snapshot)
+	return string(b)
+}
+
+// DecodeDestinationSnapshot reverses EncodeDestinationSnapshot.
+func DecodeDestinationSnapshot(s string) (*models.DestinationSnapshot, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var snapshot models.DestinationSnapshot
+	if err := json.Unmarshal([]byte(s), &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal destination_snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
@@ -71,6 +71,7 @@ type EventMetricsDataPoint struct {
 	// Dimensions
 	TenantID      *string
 	Topic         *string
+	Source        *string
 	DestinationID *string
 }
 
@@ -101,11 +102,17 @@ type AttemptMetricsDataPoint struct {
 	Rate              *float64
 	SuccessfulRate    *float64
 	FailedRate        *float64
+	// P50LatencyMs and P95LatencyMs are percentiles of the latency of the
+	// attempts that made a delivery; drivers may approximate them. They are 0
+	// when no attempt recorded a latency.
+	P50LatencyMs *float64
+	P95LatencyMs *float64
 	// Dimensions
 	TenantID        *string
 	DestinationID   *string
 	DestinationType *string
 	Topic           *string
+	Source          *string
 	Status          *string
 	Code            *string
 	Manual          *bool
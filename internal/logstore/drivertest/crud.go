@@ -2,6 +2,8 @@ package drivertest
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -29,7 +31,7 @@ func testCRUD(t *testing.T, newHarness HarnessMaker) {
 	// Shared test data
 	tenantID := idgen.String()
 	destinationIDs := []string{idgen.Destination(), idgen.Destination(), idgen.Destination()}
-	baseTime := time.Now().Truncate(time.Second)
+	baseTime := harnessNow(h).Truncate(time.Second)
 	startTime := baseTime.Add(-48 * time.Hour)
 
 	// We'll populate these as we insert
@@ -187,6 +189,377 @@ func testCRUD(t *testing.T, newHarness HarnessMaker) {
 			assert.Empty(t, retrieved.Metadata)
 		})
 
+		t.Run("destination snapshot round-trips", func(t *testing.T) {
+			snapshotTenantID := idgen.String()
+			destID := idgen.Destination()
+			snapshot := &models.DestinationSnapshot{
+				Type:      "webhook",
+				Target:    "example.com/webhooks",
+				TargetURL: "https://example.com/webhooks",
+				Config:    models.Config{"url": "https://example.com/webhooks"},
+			}
+
+			var entries []*models.LogEntry
+			for i, s := range []*models.DestinationSnapshot{snapshot, nil} {
+				event := testutil.EventFactory.AnyPointer(
+					testutil.EventFactory.WithID(fmt.Sprintf("snapshot_evt_%d", i)),
+					testutil.EventFactory.WithTenantID(snapshotTenantID),
+					testutil.EventFactory.WithDestinationID(destID),
+					testutil.EventFactory.WithMatchedDestinationIDs([]string{destID}),
+					testutil.EventFactory.WithTime(baseTime.Add(-7*time.Minute)),
+				)
+				attempt := testutil.AttemptFactory.AnyPointer(
+					testutil.AttemptFactory.WithID(fmt.Sprintf("snapshot_del_%d", i)),
+					testutil.AttemptFactory.WithTenantID(snapshotTenantID),
+					testutil.AttemptFactory.WithEventID(event.ID),
+					testutil.AttemptFactory.WithDestinationID(destID),
+					testutil.AttemptFactory.WithTime(baseTime.Add(-7*time.Minute)),
+				)
+				attempt.DestinationSnapshot = s
+				entries = append(entries, &models.LogEntry{Event: event, Attempt: attempt})
+			}
+			require.NoError(t, logStore.InsertMany(ctx, entries))
+			require.NoError(t, h.FlushWrites(ctx))
+
+			retrieved, err := logStore.RetrieveAttempt(ctx, driver.RetrieveAttemptRequest{
+				TenantID:  snapshotTenantID,
+				AttemptID: "snapshot_del_0",
+			})
+			require.NoError(t, err)
+			require.NotNil(t, retrieved)
+			assert.Equal(t, snapshot, retrieved.Attempt.DestinationSnapshot)
+
+			listed, err := logStore.ListAttempt(ctx, driver.ListAttemptRequest{
+				TenantIDs:  []string{snapshotTenantID},
+				TimeFilter: driver.TimeFilter{GTE: &startTime},
+				Limit:      10,
+			})
+			require.NoError(t, err)
+			require.Len(t, listed.Data, 2)
+			for _, r := range listed.Data {
+				if r.Attempt.ID == "snapshot_del_0" {
+					assert.Equal(t, snapshot, r.Attempt.DestinationSnapshot)
+				} else {
+					assert.Nil(t, r.Attempt.DestinationSnapshot, "attempts without a snapshot stay nil")
+				}
+			}
+		})
+
+		t.Run("attempt error round-trips", func(t *testing.T) {
+			errorTenantID := idgen.String()
+			destID := idgen.Destination()
+			event := testutil.EventFactory.AnyPointer(
+				testutil.EventFactory.WithID("error_evt"),
+				testutil.EventFactory.WithTenantID(errorTenantID),
+				testutil.EventFactory.WithDestinationID(destID),
+				testutil.EventFactory.WithMatchedDestinationIDs([]string{destID}),
+				testutil.EventFactory.WithTime(baseTime.Add(-7*time.Minute)),
+			)
+			attempt := testutil.AttemptFactory.AnyPointer(
+				testutil.AttemptFactory.WithID("error_del"),
+				testutil.AttemptFactory.WithTenantID(errorTenantID),
+				testutil.AttemptFactory.WithEventID(event.ID),
+				testutil.AttemptFactory.WithDestinationID(destID),
+				testutil.AttemptFactory.WithStatus("failed"),
+				testutil.AttemptFactory.WithTime(baseTime.Add(-7*time.Minute)),
+			)
+			attempt.ErrorCode = "connection_failed"
+			attempt.ErrorMessage = "A connection to the destination could not be established."
+			require.NoError(t, logStore.InsertMany(ctx, []*models.LogEntry{{Event: event, Attempt: attempt}}))
+			require.NoError(t, h.FlushWrites(ctx))
+
+			retrieved, err := logStore.RetrieveAttempt(ctx, driver.RetrieveAttemptRequest{
+				TenantID:  errorTenantID,
+				AttemptID: "error_del",
+			})
+			require.NoError(t, err)
+			require.NotNil(t, retrieved)
+			assert.Equal(t, attempt.ErrorCode, retrieved.Attempt.ErrorCode)
+			assert.Equal(t, attempt.ErrorMessage, retrieved.Attempt.ErrorMessage)
+
+			listed, err := logStore.ListAttempt(ctx, driver.ListAttemptRequest{
+				TenantIDs:  []string{errorTenantID},
+				TimeFilter: driver.TimeFilter{GTE: &startTime},
+				Limit:      10,
+			})
+			require.NoError(t, err)
+			require.Len(t, listed.Data, 1)
+			assert.Equal(t, attempt.ErrorCode, listed.Data[0].Attempt.ErrorCode)
+			assert.Equal(t, attempt.ErrorMessage, listed.Data[0].Attempt.ErrorMessage)
+		})
+
+		t.Run("attempt latency round-trips", func(t *testing.T) {
+			latencyTenantID := idgen.String()
+			destID := idgen.Destination()
+			event := testutil.EventFactory.AnyPointer(
+				testutil.EventFactory.WithID("latency_evt"),
+				testutil.EventFactory.WithTenantID(latencyTenantID),
+				testutil.EventFactory.WithDestinationID(destID),
+				testutil.EventFactory.WithMatchedDestinationIDs([]string{destID}),
+				testutil.EventFactory.WithTime(baseTime.Add(-7*time.Minute)),
+			)
+			delivered := testutil.AttemptFactory.AnyPointer(
+				testutil.AttemptFactory.WithID("latency_del"),
+				testutil.AttemptFactory.WithTenantID(latencyTenantID),
+				testutil.AttemptFactory.WithEventID(event.ID),
+				testutil.AttemptFactory.WithDestinationID(destID),
+				testutil.AttemptFactory.WithTime(baseTime.Add(-7*time.Minute)),
+				testutil.AttemptFactory.WithLatencyMs(250),
+			)
+			undelivered := testutil.AttemptFactory.AnyPointer(
+				testutil.AttemptFactory.WithID("latency_none_del"),
+				testutil.AttemptFactory.WithTenantID(latencyTenantID),
+				testutil.AttemptFactory.WithEventID(event.ID),
+				testutil.AttemptFactory.WithDestinationID(destID),
+				testutil.AttemptFactory.WithStatus("failed"),
+				testutil.AttemptFactory.WithTime(baseTime.Add(-6*time.Minute)),
+			)
+			require.NoError(t, logStore.InsertMany(ctx, []*models.LogEntry{
+				{Event: event, Attempt: delivered},
+				{Event: event, Attempt: undelivered},
+			}))
+			require.NoError(t, h.FlushWrites(ctx))
+
+			retrieved, err := logStore.RetrieveAttempt(ctx, driver.RetrieveAttemptRequest{
+				TenantID:  latencyTenantID,
+				AttemptID: "latency_del",
+			})
+			require.NoError(t, err)
+			require.NotNil(t, retrieved)
+			require.NotNil(t, retrieved.Attempt.LatencyMs)
+			assert.Equal(t, int64(250), *retrieved.Attempt.LatencyMs)
+
+			retrieved, err = logStore.RetrieveAttempt(ctx, driver.RetrieveAttemptRequest{
+				TenantID:  latencyTenantID,
+				AttemptID: "latency_none_del",
+			})
+			require.NoError(t, err)
+			require.NotNil(t, retrieved)
+			assert.Nil(t, retrieved.Attempt.LatencyMs)
+		})
+
+		t.Run("event checksum round-trips", func(t *testing.T) {
+			checksumTenantID := idgen.String()
+			destID := idgen.Destination()
+			event := testutil.EventFactory.AnyPointer(
+				testutil.EventFactory.WithID("checksum_evt"),
+				testutil.EventFactory.WithTenantID(checksumTenantID),
+				testutil.EventFactory.WithDestinationID(destID),
+				testutil.EventFactory.WithTime(baseTime.Add(-8*time.Minute)),
+			)
+			event.Checksum = models.DataChecksum(event.Data)
+			attempt := testutil.AttemptFactory.AnyPointer(
+				testutil.AttemptFactory.WithID("checksum_del"),
+				testutil.AttemptFactory.WithTenantID(checksumTenantID),
+				testutil.AttemptFactory.WithEventID(event.ID),
+				testutil.AttemptFactory.WithDestinationID(destID),
+				testutil.AttemptFactory.WithTime(baseTime.Add(-8*time.Minute)),
+			)
+			require.NoError(t, logStore.InsertMany(ctx, []*models.LogEntry{{Event: event, Attempt: attempt}}))
+			require.NoError(t, h.FlushWrites(ctx))
+
+			retrievedEvent, err := logStore.RetrieveEvent(ctx, driver.RetrieveEventRequest{
+				TenantID: checksumTenantID,
+				EventID:  event.ID,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, retrievedEvent)
+			assert.Equal(t, event.Checksum, retrievedEvent.Checksum)
+			assert.NoError(t, retrievedEvent.VerifyChecksum())
+
+			retrievedAttempt, err := logStore.RetrieveAttempt(ctx, driver.RetrieveAttemptRequest{
+				TenantID:  checksumTenantID,
+				AttemptID: attempt.ID,
+			})
+			require.NoError(t, err)
+			require.NotNil(t, retrievedAttempt)
+			assert.Equal(t, event.Checksum, retrievedAttempt.Event.Checksum)
+		})
+
+		t.Run("event source round-trips and filters", func(t *testing.T) {
+			sourceTenantID := idgen.String()
+			destID := idgen.Destination()
+			eventTime := baseTime.Add(-8 * time.Minute)
+			var entries []*models.LogEntry
+			for i, source := range []string{"billing", "shipping", ""} {
+				event := testutil.EventFactory.AnyPointer(
+					testutil.EventFactory.WithID(fmt.Sprintf("source_evt_%d", i)),
+					testutil.EventFactory.WithTenantID(sourceTenantID),
+					testutil.EventFactory.WithDestinationID(destID),
+					testutil.EventFactory.WithSource(source),
+					testutil.EventFactory.WithTime(eventTime),
+				)
+				attempt := testutil.AttemptFactory.AnyPointer(
+					testutil.AttemptFactory.WithID(fmt.Sprintf("source_del_%d", i)),
+					testutil.AttemptFactory.WithTenantID(sourceTenantID),
+					testutil.AttemptFactory.WithEventID(event.ID),
+					testutil.AttemptFactory.WithDestinationID(destID),
+					testutil.AttemptFactory.WithTime(eventTime),
+				)
+				entries = append(entries, &models.LogEntry{Event: event, Attempt: attempt})
+			}
+			require.NoError(t, logStore.InsertMany(ctx, entries))
+			require.NoError(t, h.FlushWrites(ctx))
+
+			retrievedEvent, err := logStore.RetrieveEvent(ctx, driver.RetrieveEventRequest{
+				TenantID: sourceTenantID,
+				EventID:  "source_evt_0",
+			})
+			require.NoError(t, err)
+			require.NotNil(t, retrievedEvent)
+			assert.Equal(t, "billing", retrievedEvent.Source)
+
+			retrievedAttempt, err := logStore.RetrieveAttempt(ctx, driver.RetrieveAttemptRequest{
+				TenantID:  sourceTenantID,
+				AttemptID: "source_del_1",
+			})
+			require.NoError(t, err)
+			require.NotNil(t, retrievedAttempt)
+			assert.Equal(t, "shipping", retrievedAttempt.Event.Source)
+
+			events, err := logStore.ListEvent(ctx, driver.ListEventRequest{
+				TenantIDs:  []string{sourceTenantID},
+				Sources:    []string{"billing"},
+				Limit:      100,
+				TimeFilter: driver.TimeFilter{GTE: &startTime},
+			})
+			require.NoError(t, err)
+			require.Len(t, events.Data, 1)
+			assert.Equal(t, "source_evt_0", events.Data[0].ID)
+			assert.Equal(t, "billing", events.Data[0].Source)
+
+			attempts, err := logStore.ListAttempt(ctx, driver.ListAttemptRequest{
+				TenantIDs:  []string{sourceTenantID},
+				Sources:    []string{"billing", "shipping"},
+				Limit:      100,
+				TimeFilter: driver.TimeFilter{GTE: &startTime},
+			})
+			require.NoError(t, err)
+			require.Len(t, attempts.Data, 2)
+			for _, ar := range attempts.Data {
+				assert.Contains(t, []string{"billing", "shipping"}, ar.Event.Source)
+			}
+		})
+
+		t.Run("event group round-trips and filters", func(t *testing.T) {
+			groupTenantID := idgen.String()
+			destID := idgen.Destination()
+			eventTime := baseTime.Add(-8 * time.Minute)
+			var entries []*models.LogEntry
+			for i, groupID := range []string{"grp_1", "grp_1", "grp_2", ""} {
+				event := testutil.EventFactory.AnyPointer(
+					testutil.EventFactory.WithID(fmt.Sprintf("group_evt_%d", i)),
+					testutil.EventFactory.WithTenantID(groupTenantID),
+					testutil.EventFactory.WithDestinationID(destID),
+					testutil.EventFactory.WithGroupID(groupID),
+					testutil.EventFactory.WithTime(eventTime),
+				)
+				attempt := testutil.AttemptFactory.AnyPointer(
+					testutil.AttemptFactory.WithID(fmt.Sprintf("group_del_%d", i)),
+					testutil.AttemptFactory.WithTenantID(groupTenantID),
+					testutil.AttemptFactory.WithEventID(event.ID),
+					testutil.AttemptFactory.WithDestinationID(destID),
+					testutil.AttemptFactory.WithTime(eventTime),
+				)
+				entries = append(entries, &models.LogEntry{Event: event, Attempt: attempt})
+			}
+			require.NoError(t, logStore.InsertMany(ctx, entries))
+			require.NoError(t, h.FlushWrites(ctx))
+
+			retrievedEvent, err := logStore.RetrieveEvent(ctx, driver.RetrieveEventRequest{
+				TenantID: groupTenantID,
+				EventID:  "group_evt_2",
+			})
+			require.NoError(t, err)
+			require.NotNil(t, retrievedEvent)
+			assert.Equal(t, "grp_2", retrievedEvent.GroupID)
+
+			events, err := logStore.ListEvent(ctx, driver.ListEventRequest{
+				TenantIDs:  []string{groupTenantID},
+				GroupID:    "grp_1",
+				Limit:      100,
+				TimeFilter: driver.TimeFilter{GTE: &startTime},
+			})
+			require.NoError(t, err)
+			require.Len(t, events.Data, 2)
+			for _, e := range events.Data {
+				assert.Equal(t, "grp_1", e.GroupID)
+			}
+		})
+
+		t.Run("event metadata and data search filters", func(t *testing.T) {
+			searchTenantID := idgen.String()
+			destID := idgen.Destination()
+			eventTime := baseTime.Add(-9 * time.Minute)
+			fixtures := []struct {
+				metadata map[string]string
+				data     string
+			}{
+				{map[string]string{"region": "eu", "plan": "pro"}, `{"order_id":"12345","customer":"Ada Lovelace"}`},
+				{map[string]string{"region": "eu", "plan": "free"}, `{"order_id":"67890","customer":"Alan Turing"}`},
+				{map[string]string{"region": "us"}, `{"order_id":12345,"note":"refund"}`},
+			}
+			var entries []*models.LogEntry
+			for i, f := range fixtures {
+				event := testutil.EventFactory.AnyPointer(
+					testutil.EventFactory.WithID(fmt.Sprintf("search_evt_%d", i)),
+					testutil.EventFactory.WithTenantID(searchTenantID),
+					testutil.EventFactory.WithDestinationID(destID),
+					testutil.EventFactory.WithMetadata(f.metadata),
+					testutil.EventFactory.WithData(json.RawMessage(f.data)),
+					testutil.EventFactory.WithTime(eventTime),
+				)
+				attempt := testutil.AttemptFactory.AnyPointer(
+					testutil.AttemptFactory.WithID(fmt.Sprintf("search_del_%d", i)),
+					testutil.AttemptFactory.WithTenantID(searchTenantID),
+					testutil.AttemptFactory.WithEventID(event.ID),
+					testutil.AttemptFactory.WithDestinationID(destID),
+					testutil.AttemptFactory.WithTime(eventTime),
+				)
+				entries = append(entries, &models.LogEntry{Event: event, Attempt: attempt})
+			}
+			require.NoError(t, logStore.InsertMany(ctx, entries))
+			require.NoError(t, h.FlushWrites(ctx))
+
+			list := func(t *testing.T, req driver.ListEventRequest) []string {
+				t.Helper()
+				req.TenantIDs = []string{searchTenantID}
+				req.Limit = 100
+				req.TimeFilter = driver.TimeFilter{GTE: &startTime}
+				events, err := logStore.ListEvent(ctx, req)
+				if errors.Is(err, driver.ErrSearchNotSupported) {
+					t.Skip("driver does not support event data search")
+				}
+				require.NoError(t, err)
+				ids := make([]string, len(events.Data))
+				for i, e := range events.Data {
+					ids[i] = e.ID
+				}
+				return ids
+			}
+
+			t.Run("metadata", func(t *testing.T) {
+				assert.ElementsMatch(t, []string{"search_evt_0", "search_evt_1"},
+					list(t, driver.ListEventRequest{Metadata: map[string]string{"region": "eu"}}))
+				assert.ElementsMatch(t, []string{"search_evt_1"},
+					list(t, driver.ListEventRequest{Metadata: map[string]string{"region": "eu", "plan": "free"}}))
+				assert.Empty(t, list(t, driver.ListEventRequest{Metadata: map[string]string{"region": "apac"}}))
+			})
+
+			t.Run("search", func(t *testing.T) {
+				assert.ElementsMatch(t, []string{"search_evt_0", "search_evt_2"},
+					list(t, driver.ListEventRequest{Search: "12345"}))
+				assert.ElementsMatch(t, []string{"search_evt_0"},
+					list(t, driver.ListEventRequest{Search: "lovelace 12345"}))
+				assert.Empty(t, list(t, driver.ListEventRequest{Search: "lovelace 67890"}))
+			})
+
+			t.Run("metadata and search", func(t *testing.T) {
+				assert.ElementsMatch(t, []string{"search_evt_2"},
+					list(t, driver.ListEventRequest{Search: "12345", Metadata: map[string]string{"region": "us"}}))
+			})
+		})
+
 		t.Run("duplicate entries in batch", func(t *testing.T) {
 			// Duplicates arise from MQ redelivery and producer re-publish;
 			// InsertMany must tolerate intra-batch duplicates (same Attempt.ID)
@@ -4,7 +4,9 @@ package drivertest
 import (
 	"context"
 	"testing"
+	"time"
 
+	"github.com/hookdeck/outpost/internal/clock"
 	"github.com/hookdeck/outpost/internal/logstore/driver"
 )
 
@@ -19,14 +21,30 @@ type Harness interface {
 	Close()
 }
 
+// ClockHarness is implemented by harnesses whose driver reads the current
+// time. The suite then places its records relative to the harness clock
+// rather than the wall clock.
+type ClockHarness interface {
+	Clock() clock.Clock
+}
+
+func harnessNow(h Harness) time.Time {
+	if ch, ok := h.(ClockHarness); ok {
+		return ch.Clock().Now()
+	}
+	return time.Now()
+}
+
 // HarnessMaker creates a new Harness for each test.
 type HarnessMaker func(ctx context.Context, t *testing.T) (Harness, error)
 
 // RunConformanceTests executes the full conformance test suite for a logstore driver.
-// The suite is organized into three parts:
+// The suite is organized into these parts:
 //   - CRUD: basic insert, list, and retrieve operations
 //   - Pagination: cursor-based pagination tests using paginationtest.Suite
 //   - Misc: isolation, edge cases, and cursor validation
+//   - Metrics: aggregation queries
+//   - Prune: status-based retention, for drivers implementing driver.Pruner
 func RunConformanceTests(t *testing.T, newHarness HarnessMaker) {
 	t.Helper()
 
@@ -42,4 +60,10 @@ func RunConformanceTests(t *testing.T, newHarness HarnessMaker) {
 	t.Run("Metrics", func(t *testing.T) {
 		testMetrics(t, newHarness)
 	})
+	t.Run("Prune", func(t *testing.T) {
+		testPrune(t, newHarness)
+	})
+	t.Run("PruneHolds", func(t *testing.T) {
+		testPruneHolds(t, newHarness)
+	})
 }
@@ -214,7 +214,7 @@ func testMetricsCharacteristics(t *testing.T, ctx context.Context, logStore driv
 			Filters:     map[string][]string{"tenant_id": {ds.tenant1}},
 			TimeRange:   ds.denseDayRange.toDriver(),
 			Granularity: &driver.Granularity{Value: 1, Unit: "h"},
-			Measures:    []string{"count", "successful_count", "failed_count", "error_rate", "first_attempt_count", "retry_count", "manual_retry_count", "avg_attempt_number", "rate", "successful_rate", "failed_rate"},
+			Measures:    []string{"count", "successful_count", "failed_count", "error_rate", "first_attempt_count", "retry_count", "manual_retry_count", "avg_attempt_number", "rate", "successful_rate", "failed_rate", "p50_latency_ms", "p95_latency_ms"},
 		})
 		require.NoError(t, err)
 		// Guard: need 24 buckets for this test to be meaningful (not vacuously pass).
@@ -233,6 +233,8 @@ func testMetricsCharacteristics(t *testing.T, ctx context.Context, logStore driv
 				require.NotNil(t, dp.Rate, "rate must not be nil at %s", dp.TimeBucket)
 				require.NotNil(t, dp.SuccessfulRate, "successful_rate must not be nil at %s", dp.TimeBucket)
 				require.NotNil(t, dp.FailedRate, "failed_rate must not be nil at %s", dp.TimeBucket)
+				require.NotNil(t, dp.P50LatencyMs, "p50_latency_ms must not be nil at %s", dp.TimeBucket)
+				require.NotNil(t, dp.P95LatencyMs, "p95_latency_ms must not be nil at %s", dp.TimeBucket)
 				assert.Equal(t, 0, *dp.Count)
 				assert.Equal(t, 0, *dp.SuccessfulCount)
 				assert.Equal(t, 0, *dp.FailedCount)
@@ -244,6 +246,8 @@ func testMetricsCharacteristics(t *testing.T, ctx context.Context, logStore driv
 				assert.Equal(t, 0.0, *dp.Rate, "rate must be 0.0")
 				assert.Equal(t, 0.0, *dp.SuccessfulRate, "successful_rate must be 0.0")
 				assert.Equal(t, 0.0, *dp.FailedRate, "failed_rate must be 0.0")
+				assert.Equal(t, 0.0, *dp.P50LatencyMs, "p50_latency_ms must be 0.0")
+				assert.Equal(t, 0.0, *dp.P95LatencyMs, "p95_latency_ms must be 0.0")
 			}
 		}
 	})
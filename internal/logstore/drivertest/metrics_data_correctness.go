@@ -51,6 +51,26 @@ func testMetricsDataCorrectness(t *testing.T, ctx context.Context, logStore driv
 			assert.Equal(t, 100, tc[testutil.TestTopics[2]]) // user.updated
 		})
 
+		t.Run("by source", func(t *testing.T) {
+			resp, err := logStore.QueryEventMetrics(ctx, driver.MetricsRequest{
+				Filters:    map[string][]string{"tenant_id": {ds.tenant1}},
+				TimeRange:  fullRange,
+				Measures:   []string{"count"},
+				Dimensions: []string{"source"},
+			})
+			require.NoError(t, err)
+			assert.Len(t, resp.Data, 2)
+
+			sc := map[string]int{}
+			for _, dp := range resp.Data {
+				require.NotNil(t, dp.Source)
+				require.NotNil(t, dp.Count)
+				sc[*dp.Source] = *dp.Count
+			}
+			assert.Equal(t, 75, sc["billing"])
+			assert.Equal(t, 225, sc["shipping"])
+		})
+
 		t.Run("by destination_id", func(t *testing.T) {
 			resp, err := logStore.QueryEventMetrics(ctx, driver.MetricsRequest{
 				Filters:    map[string][]string{"tenant_id": {ds.tenant1}},
@@ -102,6 +122,18 @@ func testMetricsDataCorrectness(t *testing.T, ctx context.Context, logStore driv
 			assert.Equal(t, 100, *resp.Data[0].Count)
 		})
 
+		t.Run("filter by source", func(t *testing.T) {
+			resp, err := logStore.QueryEventMetrics(ctx, driver.MetricsRequest{
+				TimeRange: fullRange,
+				Measures:  []string{"count"},
+				Filters:   map[string][]string{"tenant_id": {ds.tenant1}, "source": {"billing"}},
+			})
+			require.NoError(t, err)
+			require.Len(t, resp.Data, 1)
+			require.NotNil(t, resp.Data[0].Count)
+			assert.Equal(t, 75, *resp.Data[0].Count)
+		})
+
 		t.Run("filter by destination_id", func(t *testing.T) {
 			resp, err := logStore.QueryEventMetrics(ctx, driver.MetricsRequest{
 				TimeRange: fullRange,
@@ -377,6 +409,36 @@ func testMetricsDataCorrectness(t *testing.T, ctx context.Context, logStore driv
 			assert.InDelta(t, 1.0, *dp.AvgAttemptNumber, 0.001)
 		})
 
+		t.Run("latency percentiles", func(t *testing.T) {
+			resp, err := logStore.QueryAttemptMetrics(ctx, driver.MetricsRequest{
+				Filters:   map[string][]string{"tenant_id": {ds.tenant1}},
+				TimeRange: fullRange,
+				Measures:  []string{"p50_latency_ms", "p95_latency_ms"},
+			})
+			require.NoError(t, err)
+			require.Len(t, resp.Data, 1)
+			dp := resp.Data[0]
+			require.NotNil(t, dp.P50LatencyMs)
+			require.NotNil(t, dp.P95LatencyMs)
+			assert.InDelta(t, 100, *dp.P50LatencyMs, 0.001)
+			assert.InDelta(t, 1000, *dp.P95LatencyMs, 0.001)
+		})
+
+		t.Run("latency percentiles without latencies", func(t *testing.T) {
+			resp, err := logStore.QueryAttemptMetrics(ctx, driver.MetricsRequest{
+				Filters:   map[string][]string{"tenant_id": {ds.tenant2}},
+				TimeRange: fullRange,
+				Measures:  []string{"count", "p50_latency_ms", "p95_latency_ms"},
+			})
+			require.NoError(t, err)
+			require.Len(t, resp.Data, 1)
+			dp := resp.Data[0]
+			require.NotNil(t, dp.P50LatencyMs)
+			require.NotNil(t, dp.P95LatencyMs)
+			assert.Equal(t, 0.0, *dp.P50LatencyMs)
+			assert.Equal(t, 0.0, *dp.P95LatencyMs)
+		})
+
 		t.Run("rate no granularity", func(t *testing.T) {
 			resp, err := logStore.QueryAttemptMetrics(ctx, driver.MetricsRequest{
 				Filters:   map[string][]string{"tenant_id": {ds.tenant1}},
@@ -435,6 +497,31 @@ func testMetricsDataCorrectness(t *testing.T, ctx context.Context, logStore driv
 			assert.Equal(t, 120, sc["failed"])
 		})
 
+		t.Run("failed by source", func(t *testing.T) {
+			resp, err := logStore.QueryAttemptMetrics(ctx, driver.MetricsRequest{
+				Filters:    map[string][]string{"tenant_id": {ds.tenant1}},
+				TimeRange:  fullRange,
+				Measures:   []string{"count", "failed_count"},
+				Dimensions: []string{"source"},
+			})
+			require.NoError(t, err)
+			assert.Len(t, resp.Data, 2)
+
+			total := map[string]int{}
+			failed := map[string]int{}
+			for _, dp := range resp.Data {
+				require.NotNil(t, dp.Source)
+				require.NotNil(t, dp.Count)
+				require.NotNil(t, dp.FailedCount)
+				total[*dp.Source] = *dp.Count
+				failed[*dp.Source] = *dp.FailedCount
+			}
+			assert.Equal(t, 75, total["billing"])
+			assert.Equal(t, 225, total["shipping"])
+			assert.Equal(t, 30, failed["billing"])
+			assert.Equal(t, 90, failed["shipping"])
+		})
+
 		t.Run("by destination_id", func(t *testing.T) {
 			resp, err := logStore.QueryAttemptMetrics(ctx, driver.MetricsRequest{
 				Filters:    map[string][]string{"tenant_id": {ds.tenant1}},
@@ -540,6 +627,18 @@ func testMetricsDataCorrectness(t *testing.T, ctx context.Context, logStore driv
 			assert.Equal(t, 100, *resp.Data[0].Count)
 		})
 
+		t.Run("filter by source", func(t *testing.T) {
+			resp, err := logStore.QueryAttemptMetrics(ctx, driver.MetricsRequest{
+				TimeRange: fullRange,
+				Measures:  []string{"count"},
+				Filters:   map[string][]string{"tenant_id": {ds.tenant1}, "source": {"shipping"}},
+			})
+			require.NoError(t, err)
+			require.Len(t, resp.Data, 1)
+			require.NotNil(t, resp.Data[0].Count)
+			assert.Equal(t, 225, *resp.Data[0].Count)
+		})
+
 		t.Run("filter by code", func(t *testing.T) {
 			resp, err := logStore.QueryAttemptMetrics(ctx, driver.MetricsRequest{
 				TimeRange: fullRange,
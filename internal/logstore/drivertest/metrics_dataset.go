@@ -54,6 +54,7 @@ import (
 // All 300 events are numbered 0–299 in insertion order. Dimensions cycle:
 //
 //   topic:              i % 3  → 0=user.created, 1=user.deleted, 2=user.updated
+//   source:             i % 4  → 0=billing, 1,2,3=shipping
 //   destination:        i % 2  → 0=dest_1.1, 1=dest_1.2
 //   status:             i % 5  → 0,1,2=success, 3,4=failed
 //   code:               success → i%2==0 ? "200" : "201"
@@ -61,6 +62,7 @@ import (
 //   attempt_number:     1  (each entry is a unique event, not a retry)
 //   manual:             i % 10 == 9
 //   eligible_for_retry: i % 3 != 2
+//   latency_ms:         manual ? 1000 : 100
 //
 // ── Derived Totals (Tenant 1, all 300) ───────────────────────────────────
 //
@@ -68,6 +70,7 @@ import (
 //   count:                        300
 //   by topic:                     user.created=100, user.deleted=100, user.updated=100
 //   by destination:               dest_1.1=150, dest_1.2=150
+//   by source:                    billing=75, shipping=225
 //   by eligible_for_retry:        true=200, false=100
 //
 // Attempt metrics:
@@ -79,18 +82,21 @@ import (
 //   successful_rate (no gran):     180/2678400
 //   failed_rate (no gran):         120/2678400
 //   by code:                       200=90, 201=90, 500=60, 422=60
+//   failed by source:              billing=30, shipping=90
 //   first_attempt (attempt_number==1 AND !manual): 270
 //   retry (attempt_number>1):                      0
 //   manual (i%10==9):              30
 //   avg_attempt_number:            1.0
+//   p50_latency_ms:                100   (270 of 300 attempts at 100)
+//   p95_latency_ms:                1000  (the top 30 attempts at 1000)
 //
 // Dense day — Jan 15 (250 events, indices 50..299):
 //   hourly buckets:  10:00→25, 11:00→50, 12:00→100, 13:00→50, 14:00→25
 //
 // ── Tenant 2 ─────────────────────────────────────────────────────────────
 //
-//   5 events, all topic=user.created, dest=dest_2.1, status=success, code=200,
-//   attempt_number=1, manual=false, eligible_for_retry=true
+//   5 events, all topic=user.created, no source, dest=dest_2.1, status=success, code=200,
+//   attempt_number=1, manual=false, eligible_for_retry=true, no latency
 //
 //   Jan 5 09:00, Jan 10 09:00, Jan 15 12:15, Jan 22 09:00, Jan 27 09:00
 //
@@ -170,6 +176,10 @@ func buildMetricsDataset() *metricsDataset {
 			dest = mDest1_2
 		}
 		topic := topics[idx%3]
+		source := "shipping"
+		if idx%4 == 0 {
+			source = "billing"
+		}
 		status := "success"
 		if idx%5 == 3 || idx%5 == 4 {
 			status = "failed"
@@ -178,6 +188,10 @@ func buildMetricsDataset() *metricsDataset {
 		attemptNum := 1 // Each entry is a unique event, not a retry
 		manual := idx%10 == 9
 		eligible := idx%3 != 2
+		latencyMs := int64(100)
+		if manual {
+			latencyMs = 1000
+		}
 
 		event := testutil.EventFactory.AnyPointer(
 			testutil.EventFactory.WithID(fmt.Sprintf("m_evt_1_%d", idx)),
@@ -185,6 +199,7 @@ func buildMetricsDataset() *metricsDataset {
 			testutil.EventFactory.WithDestinationID(dest),
 			testutil.EventFactory.WithMatchedDestinationIDs([]string{dest}),
 			testutil.EventFactory.WithTopic(topic),
+			testutil.EventFactory.WithSource(source),
 			testutil.EventFactory.WithTime(eventTime),
 			testutil.EventFactory.WithEligibleForRetry(eligible),
 		)
@@ -198,6 +213,7 @@ func buildMetricsDataset() *metricsDataset {
 			testutil.AttemptFactory.WithTime(eventTime.Add(time.Millisecond)),
 			testutil.AttemptFactory.WithAttemptNumber(attemptNum),
 			testutil.AttemptFactory.WithManual(manual),
+			testutil.AttemptFactory.WithLatencyMs(latencyMs),
 		)
 
 		idx++
@@ -44,7 +44,7 @@ func testIsolation(t *testing.T, ctx context.Context, logStore driver.LogStore,
 	tenant1ID := idgen.String()
 	tenant2ID := idgen.String()
 	destinationID := idgen.Destination()
-	baseTime := time.Now().Truncate(time.Second)
+	baseTime := harnessNow(h).Truncate(time.Second)
 	startTime := baseTime.Add(-1 * time.Hour)
 
 	event1 := testutil.EventFactory.AnyPointer(
@@ -195,7 +195,7 @@ func testEdgeCases(t *testing.T, ctx context.Context, logStore driver.LogStore,
 	t.Run("invalid sort values use defaults", func(t *testing.T) {
 		tenantID := idgen.String()
 		destinationID := idgen.Destination()
-		baseTime := time.Now().Truncate(time.Second)
+		baseTime := harnessNow(h).Truncate(time.Second)
 
 		var entries []*models.LogEntry
 		for i := range 3 {
@@ -235,7 +235,7 @@ func testEdgeCases(t *testing.T, ctx context.Context, logStore driver.LogStore,
 	t.Run("empty vs nil filter semantics", func(t *testing.T) {
 		tenantID := idgen.String()
 		destinationID := idgen.Destination()
-		startTime := time.Now().Add(-1 * time.Hour)
+		startTime := harnessNow(h).Add(-1 * time.Hour)
 
 		event := testutil.EventFactory.AnyPointer(
 			testutil.EventFactory.WithTenantID(tenantID),
@@ -274,7 +274,7 @@ func testEdgeCases(t *testing.T, ctx context.Context, logStore driver.LogStore,
 	t.Run("time boundary precision", func(t *testing.T) {
 		tenantID := idgen.String()
 		destinationID := idgen.Destination()
-		boundaryTime := time.Now().Truncate(time.Second)
+		boundaryTime := harnessNow(h).Truncate(time.Second)
 		beforeBoundary := boundaryTime.Add(-1 * time.Second)
 		afterBoundary := boundaryTime.Add(1 * time.Second)
 
@@ -333,7 +333,7 @@ func testEdgeCases(t *testing.T, ctx context.Context, logStore driver.LogStore,
 	t.Run("data immutability", func(t *testing.T) {
 		tenantID := idgen.String()
 		destinationID := idgen.Destination()
-		startTime := time.Now().Add(-1 * time.Hour)
+		startTime := harnessNow(h).Add(-1 * time.Hour)
 
 		event := testutil.EventFactory.AnyPointer(
 			testutil.EventFactory.WithTenantID(tenantID),
@@ -372,7 +372,7 @@ func testEdgeCases(t *testing.T, ctx context.Context, logStore driver.LogStore,
 	t.Run("concurrent duplicate inserts are idempotent", func(t *testing.T) {
 		tenantID := idgen.String()
 		destinationID := idgen.Destination()
-		eventTime := time.Now().Add(-30 * time.Minute).Truncate(time.Second)
+		eventTime := harnessNow(h).Add(-30 * time.Minute).Truncate(time.Second)
 		attemptTime := eventTime.Add(1 * time.Second)
 		startTime := eventTime.Add(-1 * time.Hour)
 
@@ -423,7 +423,7 @@ func testEdgeCases(t *testing.T, ctx context.Context, logStore driver.LogStore,
 		destA := idgen.Destination()
 		destB := idgen.Destination()
 		destC := idgen.Destination()
-		baseTime := time.Now().Truncate(time.Second)
+		baseTime := harnessNow(h).Truncate(time.Second)
 
 		// Event published WITHOUT destination_id (topic-based routing), matched both dest-A and dest-B
 		event := testutil.EventFactory.AnyPointer(
@@ -485,7 +485,7 @@ func testEdgeCases(t *testing.T, ctx context.Context, logStore driver.LogStore,
 func testCursorValidation(t *testing.T, ctx context.Context, logStore driver.LogStore, h Harness) {
 	t.Run("malformed cursor returns error", func(t *testing.T) {
 		tenantID := idgen.String()
-		startTime := time.Now().Add(-1 * time.Hour)
+		startTime := harnessNow(h).Add(-1 * time.Hour)
 
 		testCases := []struct {
 			name   string
@@ -513,7 +513,7 @@ func testCursorValidation(t *testing.T, ctx context.Context, logStore driver.Log
 	t.Run("cursor works with matching sort params", func(t *testing.T) {
 		tenantID := idgen.String()
 		destinationID := idgen.Destination()
-		baseTime := time.Now().Truncate(time.Second)
+		baseTime := harnessNow(h).Truncate(time.Second)
 		startTime := baseTime.Add(-48 * time.Hour)
 
 		for i := range 5 {
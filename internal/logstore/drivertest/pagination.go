@@ -27,7 +27,7 @@ func testPagination(t *testing.T, newHarness HarnessMaker) {
 	logStore, err := h.MakeDriver(ctx)
 	require.NoError(t, err)
 
-	baseTime := time.Now().Truncate(time.Second)
+	baseTime := harnessNow(h).Truncate(time.Second)
 	farPast := baseTime.Add(-48 * time.Hour)
 
 	t.Run("ListAttempt", func(t *testing.T) {
@@ -0,0 +1,229 @@
+package drivertest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/logstore/driver"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPrune tests status-based pruning for drivers implementing driver.Pruner.
+func testPrune(t *testing.T, newHarness HarnessMaker) {
+	t.Helper()
+
+	ctx := context.Background()
+	h, err := newHarness(ctx, t)
+	require.NoError(t, err)
+	t.Cleanup(h.Close)
+
+	logStore, err := h.MakeDriver(ctx)
+	require.NoError(t, err)
+	pruner, ok := logStore.(driver.Pruner)
+	if !ok {
+		t.Skip("driver does not implement driver.Pruner")
+	}
+
+	tenantID := idgen.String()
+	destinationID := idgen.Destination()
+	now := harnessNow(h).Truncate(time.Second)
+	daysAgo := func(days int) time.Time { return now.AddDate(0, 0, -days) }
+
+	entry := func(eventID, attemptID, status string, eventTime, attemptTime time.Time) *models.LogEntry {
+		return &models.LogEntry{
+			Event: testutil.EventFactory.AnyPointer(
+				testutil.EventFactory.WithID(eventID),
+				testutil.EventFactory.WithTenantID(tenantID),
+				testutil.EventFactory.WithDestinationID(destinationID),
+				testutil.EventFactory.WithMatchedDestinationIDs([]string{destinationID}),
+				testutil.EventFactory.WithTime(eventTime),
+			),
+			Attempt: testutil.AttemptFactory.AnyPointer(
+				testutil.AttemptFactory.WithID(attemptID),
+				testutil.AttemptFactory.WithTenantID(tenantID),
+				testutil.AttemptFactory.WithEventID(eventID),
+				testutil.AttemptFactory.WithDestinationID(destinationID),
+				testutil.AttemptFactory.WithStatus(status),
+				testutil.AttemptFactory.WithTime(attemptTime),
+			),
+		}
+	}
+
+	require.NoError(t, logStore.InsertMany(ctx, []*models.LogEntry{
+		entry("prune-old-success", "prune-att-1", models.AttemptStatusSuccess, daysAgo(30), daysAgo(30)),
+		entry("prune-old-failed", "prune-att-2", models.AttemptStatusFailed, daysAgo(30), daysAgo(30)),
+		entry("prune-recent-success", "prune-att-3", models.AttemptStatusSuccess, daysAgo(1), daysAgo(1)),
+		entry("prune-mixed", "prune-att-4", models.AttemptStatusFailed, daysAgo(30), daysAgo(30)),
+	}))
+	retry := entry("prune-mixed", "prune-att-5", models.AttemptStatusSuccess, daysAgo(30), daysAgo(29))
+	retry.Attempt.AttemptNumber = 2
+	require.NoError(t, logStore.InsertMany(ctx, []*models.LogEntry{retry}))
+	require.NoError(t, h.FlushWrites(ctx))
+
+	start := daysAgo(365)
+	listAttemptIDs := func(t *testing.T) []string {
+		t.Helper()
+		resp, err := logStore.ListAttempt(ctx, driver.ListAttemptRequest{
+			TenantIDs:  []string{tenantID},
+			Limit:      100,
+			TimeFilter: driver.TimeFilter{GTE: &start},
+		})
+		require.NoError(t, err)
+		ids := make([]string, len(resp.Data))
+		for i, r := range resp.Data {
+			ids[i] = r.Attempt.ID
+		}
+		return ids
+	}
+	listEventIDs := func(t *testing.T) []string {
+		t.Helper()
+		resp, err := logStore.ListEvent(ctx, driver.ListEventRequest{
+			TenantIDs:  []string{tenantID},
+			Limit:      100,
+			TimeFilter: driver.TimeFilter{GTE: &start},
+		})
+		require.NoError(t, err)
+		ids := make([]string, len(resp.Data))
+		for i, e := range resp.Data {
+			ids[i] = e.ID
+		}
+		return ids
+	}
+
+	t.Run("no cutoffs deletes nothing", func(t *testing.T) {
+		resp, err := pruner.Prune(ctx, driver.PruneRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, driver.PruneResponse{}, resp)
+		assert.Len(t, listAttemptIDs(t), 5)
+	})
+
+	t.Run("prunes by status", func(t *testing.T) {
+		successBefore, failedBefore := daysAgo(14), daysAgo(90)
+		resp, err := pruner.Prune(ctx, driver.PruneRequest{
+			SuccessBefore: &successBefore,
+			FailedBefore:  &failedBefore,
+		})
+		require.NoError(t, err)
+		require.NoError(t, h.FlushWrites(ctx))
+
+		assert.Equal(t, driver.PruneResponse{AttemptsDeleted: 2, EventsDeleted: 1}, resp)
+		assert.ElementsMatch(t, []string{"prune-att-2", "prune-att-3", "prune-att-4"}, listAttemptIDs(t))
+		assert.ElementsMatch(t, []string{"prune-old-failed", "prune-recent-success", "prune-mixed"}, listEventIDs(t),
+			"events are kept while any attempt remains")
+	})
+
+	t.Run("prunes deferred attempts and their events", func(t *testing.T) {
+		require.NoError(t, logStore.InsertMany(ctx, []*models.LogEntry{
+			entry("prune-old-deferred", "prune-att-6", models.AttemptStatusDeferred, daysAgo(120), daysAgo(120)),
+		}))
+		require.NoError(t, h.FlushWrites(ctx))
+
+		deferredBefore := daysAgo(90)
+		resp, err := pruner.Prune(ctx, driver.PruneRequest{DeferredBefore: &deferredBefore})
+		require.NoError(t, err)
+		require.NoError(t, h.FlushWrites(ctx))
+
+		assert.Equal(t, driver.PruneResponse{AttemptsDeleted: 1, EventsDeleted: 1}, resp)
+		assert.NotContains(t, listAttemptIDs(t), "prune-att-6")
+		assert.NotContains(t, listEventIDs(t), "prune-old-deferred")
+	})
+
+	t.Run("prunes failures", func(t *testing.T) {
+		failedBefore := daysAgo(7)
+		_, err := pruner.Prune(ctx, driver.PruneRequest{FailedBefore: &failedBefore})
+		require.NoError(t, err)
+		require.NoError(t, h.FlushWrites(ctx))
+
+		assert.ElementsMatch(t, []string{"prune-att-3"}, listAttemptIDs(t))
+		assert.ElementsMatch(t, []string{"prune-recent-success"}, listEventIDs(t))
+	})
+}
+
+// testPruneHolds tests that pruning skips the records under a legal hold.
+func testPruneHolds(t *testing.T, newHarness HarnessMaker) {
+	t.Helper()
+
+	ctx := context.Background()
+	h, err := newHarness(ctx, t)
+	require.NoError(t, err)
+	t.Cleanup(h.Close)
+
+	logStore, err := h.MakeDriver(ctx)
+	require.NoError(t, err)
+	pruner, ok := logStore.(driver.Pruner)
+	if !ok {
+		t.Skip("driver does not implement driver.Pruner")
+	}
+
+	heldTenantID, otherTenantID := idgen.String(), idgen.String()
+	destinationID := idgen.Destination()
+	old := harnessNow(h).Truncate(time.Second).AddDate(0, 0, -30)
+
+	entry := func(tenantID, eventID, attemptID string) *models.LogEntry {
+		return &models.LogEntry{
+			Event: testutil.EventFactory.AnyPointer(
+				testutil.EventFactory.WithID(eventID),
+				testutil.EventFactory.WithTenantID(tenantID),
+				testutil.EventFactory.WithDestinationID(destinationID),
+				testutil.EventFactory.WithMatchedDestinationIDs([]string{destinationID}),
+				testutil.EventFactory.WithTime(old),
+			),
+			Attempt: testutil.AttemptFactory.AnyPointer(
+				testutil.AttemptFactory.WithID(attemptID),
+				testutil.AttemptFactory.WithTenantID(tenantID),
+				testutil.AttemptFactory.WithEventID(eventID),
+				testutil.AttemptFactory.WithDestinationID(destinationID),
+				testutil.AttemptFactory.WithStatus(models.AttemptStatusSuccess),
+				testutil.AttemptFactory.WithTime(old),
+			),
+		}
+	}
+	require.NoError(t, logStore.InsertMany(ctx, []*models.LogEntry{
+		entry(heldTenantID, "hold-tenant-event", "hold-att-1"),
+		entry(otherTenantID, "hold-event", "hold-att-2"),
+		entry(otherTenantID, "hold-unheld-event", "hold-att-3"),
+	}))
+	require.NoError(t, h.FlushWrites(ctx))
+
+	start := old.AddDate(0, 0, -1)
+	listEventIDs := func(t *testing.T) []string {
+		t.Helper()
+		resp, err := logStore.ListEvent(ctx, driver.ListEventRequest{
+			TenantIDs:  []string{heldTenantID, otherTenantID},
+			Limit:      100,
+			TimeFilter: driver.TimeFilter{GTE: &start},
+		})
+		require.NoError(t, err)
+		ids := make([]string, len(resp.Data))
+		for i, e := range resp.Data {
+			ids[i] = e.ID
+		}
+		return ids
+	}
+
+	cutoff := old.AddDate(0, 0, 1)
+	req := driver.PruneRequest{
+		SuccessBefore:  &cutoff,
+		FailedBefore:   &cutoff,
+		DeferredBefore: &cutoff,
+		HeldTenantIDs:  []string{heldTenantID},
+		HeldEventIDs:   []string{"hold-event"},
+	}
+	resp, err := pruner.Prune(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, h.FlushWrites(ctx))
+	assert.Equal(t, driver.PruneResponse{AttemptsDeleted: 1, EventsDeleted: 1}, resp)
+	assert.ElementsMatch(t, []string{"hold-tenant-event", "hold-event"}, listEventIDs(t))
+
+	req.HeldTenantIDs, req.HeldEventIDs = nil, nil
+	resp, err = pruner.Prune(ctx, req)
+	require.NoError(t, err)
+	require.NoError(t, h.FlushWrites(ctx))
+	assert.Equal(t, driver.PruneResponse{AttemptsDeleted: 2, EventsDeleted: 2}, resp, "released records are pruned")
+	assert.Empty(t, listEventIDs(t))
+}
@@ -3,12 +3,14 @@ package logstore
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/hookdeck/outpost/internal/clickhouse"
 	"github.com/hookdeck/outpost/internal/logstore/chlogstore"
 	"github.com/hookdeck/outpost/internal/logstore/driver"
 	"github.com/hookdeck/outpost/internal/logstore/memlogstore"
 	"github.com/hookdeck/outpost/internal/logstore/pglogstore"
+	"github.com/hookdeck/outpost/internal/logstore/tieredlogstore"
 	"github.com/hookdeck/outpost/internal/models"
 	"github.com/jackc/pgx/v5/pgxpool"
 )
@@ -32,33 +34,73 @@ type EventMetricsResponse = driver.EventMetricsResponse
 type AttemptMetricsDataPoint = driver.AttemptMetricsDataPoint
 type AttemptMetricsResponse = driver.AttemptMetricsResponse
 
+type Pruner = driver.Pruner
+type Compactor = driver.Compactor
+type CompactionStats = driver.CompactionStats
+type TableCompactionStats = driver.TableCompactionStats
+type PruneRequest = driver.PruneRequest
+type PruneResponse = driver.PruneResponse
+
 type LogStore = driver.LogStore
 
+var ErrSearchNotSupported = driver.ErrSearchNotSupported
+
+type Tier = tieredlogstore.Tier
+type Archive = tieredlogstore.Archive
+
 type DriverOpts struct {
 	CH           clickhouse.DB
 	PG           *pgxpool.Pool
 	DeploymentID string
+	// HotTierMaxAge tiers the log store when both CH and PG are set: PG
+	// serves records up to this age and ClickHouse keeps every record.
+	HotTierMaxAge time.Duration
+	// Archive, with ArchiveTierMaxAge, follows the log store with an archive
+	// serving the records older than ArchiveTierMaxAge that it holds.
+	Archive           Archive
+	ArchiveTierMaxAge time.Duration
 }
 
 func (d *DriverOpts) Close() error {
-	if d.CH != nil {
-		return d.CH.Close()
-	}
 	if d.PG != nil {
 		d.PG.Close()
 	}
+	if d.CH != nil {
+		return d.CH.Close()
+	}
 	return nil
 }
 
 func NewLogStore(ctx context.Context, driverOpts DriverOpts) (LogStore, error) {
-	if driverOpts.CH != nil {
-		return chlogstore.NewLogStore(driverOpts.CH, driverOpts.DeploymentID), nil
+	var tiers []Tier
+	switch {
+	case driverOpts.HotTierMaxAge > 0 && driverOpts.CH != nil && driverOpts.PG != nil:
+		tiers = []Tier{
+			{Store: pglogstore.NewLogStore(driverOpts.PG), MaxAge: driverOpts.HotTierMaxAge},
+			{Store: chlogstore.NewLogStore(driverOpts.CH, driverOpts.DeploymentID)},
+		}
+	case driverOpts.CH != nil:
+		tiers = []Tier{{Store: chlogstore.NewLogStore(driverOpts.CH, driverOpts.DeploymentID)}}
+	case driverOpts.PG != nil:
+		tiers = []Tier{{Store: pglogstore.NewLogStore(driverOpts.PG)}}
+	default:
+		return nil, errors.New("no driver provided")
 	}
-	if driverOpts.PG != nil {
-		return pglogstore.NewLogStore(driverOpts.PG), nil
+
+	if driverOpts.Archive != nil && driverOpts.ArchiveTierMaxAge > 0 {
+		tiers[len(tiers)-1].MaxAge = driverOpts.ArchiveTierMaxAge
+		return tieredlogstore.NewLogStore(tiers, tieredlogstore.WithArchive(driverOpts.Archive))
 	}
+	if len(tiers) == 1 {
+		return tiers[0].Store, nil
+	}
+	return NewTieredLogStore(tiers...)
+}
 
-	return nil, errors.New("no driver provided")
+// NewTieredLogStore returns a log store serving records by age from tiers,
+// ordered from the newest records to the oldest.
+func NewTieredLogStore(tiers ...Tier) (LogStore, error) {
+	return tieredlogstore.NewLogStore(tiers)
 }
 
 // NewMemLogStore returns an in-memory log store for testing.
@@ -67,14 +109,16 @@ func NewMemLogStore() LogStore {
 }
 
 type Config struct {
-	ClickHouse   *clickhouse.ClickHouseConfig
-	Postgres     *string
-	DeploymentID string
+	ClickHouse    *clickhouse.ClickHouseConfig
+	Postgres      *string
+	DeploymentID  string
+	HotTierMaxAge time.Duration
 }
 
 func MakeDriverOpts(cfg Config) (DriverOpts, error) {
 	driverOpts := DriverOpts{
-		DeploymentID: cfg.DeploymentID,
+		DeploymentID:  cfg.DeploymentID,
+		HotTierMaxAge: cfg.HotTierMaxAge,
 	}
 
 	if cfg.ClickHouse != nil {
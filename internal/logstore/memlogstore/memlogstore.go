@@ -3,6 +3,7 @@ package memlogstore
 import (
 	"context"
 	"fmt"
+	"maps"
 	"slices"
 	"sort"
 	"sync"
@@ -175,6 +176,29 @@ func (s *memLogStore) matchesEventFilter(event *models.Event, req driver.ListEve
 		}
 	}
 
+	if len(req.Sources) > 0 && !slices.Contains(req.Sources, event.Source) {
+		return false
+	}
+
+	if req.GroupID != "" && event.GroupID != req.GroupID {
+		return false
+	}
+
+	for key, value := range req.Metadata {
+		if actual, ok := event.Metadata[key]; !ok || actual != value {
+			return false
+		}
+	}
+
+	if terms := driver.SearchTerms(req.Search); len(terms) > 0 {
+		dataTerms := driver.SearchTerms(string(event.Data))
+		for _, term := range terms {
+			if !slices.Contains(dataTerms, term) {
+				return false
+			}
+		}
+	}
+
 	if req.TimeFilter.GTE != nil && event.Time.Before(*req.TimeFilter.GTE) {
 		return false
 	}
@@ -197,7 +221,7 @@ func (s *memLogStore) InsertMany(ctx context.Context, entries []*models.LogEntry
 
 	for _, entry := range entries {
 		// Insert event (dedupe by ID, skip retries)
-		if entry.Attempt.AttemptNumber <= 1 {
+		if entry.Attempt.AttemptNumber <= 1 || entry.WriteEvent {
 			s.events[entry.Event.ID] = copyEvent(entry.Event)
 		}
 
@@ -415,6 +439,10 @@ func (s *memLogStore) matchesAttemptFilter(a *models.Attempt, event *models.Even
 		}
 	}
 
+	if len(req.Sources) > 0 && !slices.Contains(req.Sources, event.Source) {
+		return false
+	}
+
 	if req.TimeFilter.GTE != nil && a.Time.Before(*req.TimeFilter.GTE) {
 		return false
 	}
@@ -438,6 +466,9 @@ func copyEvent(e *models.Event) *models.Event {
 		Topic:            e.Topic,
 		EligibleForRetry: e.EligibleForRetry,
 		Time:             e.Time,
+		Checksum:         e.Checksum,
+		Source:           e.Source,
+		GroupID:          e.GroupID,
 	}
 
 	if e.MatchedDestinationIDs != nil {
@@ -478,6 +509,13 @@ func copyAttempt(a *models.Attempt) *models.Attempt {
 		Status:          a.Status,
 		Time:            a.Time,
 		Code:            a.Code,
+		ErrorCode:       a.ErrorCode,
+		ErrorMessage:    a.ErrorMessage,
+	}
+
+	if a.LatencyMs != nil {
+		latencyMs := *a.LatencyMs
+		copied.LatencyMs = &latencyMs
 	}
 
 	if a.ResponseData != nil {
@@ -487,6 +525,12 @@ func copyAttempt(a *models.Attempt) *models.Attempt {
 		}
 	}
 
+	if a.DestinationSnapshot != nil {
+		snapshot := *a.DestinationSnapshot
+		snapshot.Config = maps.Clone(a.DestinationSnapshot.Config)
+		copied.DestinationSnapshot = &snapshot
+	}
+
 	return copied
 }
 
@@ -37,6 +37,7 @@ func (s *memLogStore) QueryEventMetrics(ctx context.Context, req driver.MetricsR
 		timeBucket string
 		tenantID   string
 		topic      string
+		source     string
 		destID     string
 	}
 
@@ -54,6 +55,8 @@ func (s *memLogStore) QueryEventMetrics(ctx context.Context, req driver.MetricsR
 				key.tenantID = event.TenantID
 			case "topic":
 				key.topic = event.Topic
+			case "source":
+				key.source = event.Source
 			case "destination_id":
 				hasDest = true
 			}
@@ -88,6 +91,9 @@ func (s *memLogStore) QueryEventMetrics(ctx context.Context, req driver.MetricsR
 			case "topic":
 				v := key.topic
 				dp.Topic = &v
+			case "source":
+				v := key.source
+				dp.Source = &v
 			case "destination_id":
 				v := key.destID
 				dp.DestinationID = &v
@@ -158,6 +164,7 @@ func (s *memLogStore) QueryAttemptMetrics(ctx context.Context, req driver.Metric
 		destID     string
 		destType   string
 		topic      string
+		source     string
 		status     string
 		code       string
 		manual     string
@@ -181,6 +188,8 @@ func (s *memLogStore) QueryAttemptMetrics(ctx context.Context, req driver.Metric
 				key.destType = ae.attempt.DestinationType
 			case "topic":
 				key.topic = ae.event.Topic
+			case "source":
+				key.source = ae.event.Source
 			case "status":
 				key.status = ae.attempt.Status
 			case "code":
@@ -223,6 +232,9 @@ func (s *memLogStore) QueryAttemptMetrics(ctx context.Context, req driver.Metric
 			case "topic":
 				v := key.topic
 				dp.Topic = &v
+			case "source":
+				v := key.source
+				dp.Source = &v
 			case "status":
 				v := key.status
 				dp.Status = &v
@@ -292,6 +304,12 @@ func (s *memLogStore) QueryAttemptMetrics(ctx context.Context, req driver.Metric
 					avg = float64(total) / float64(len(attempts))
 				}
 				dp.AvgAttemptNumber = &avg
+			case "p50_latency_ms":
+				v := latencyPercentile(attempts, 0.50)
+				dp.P50LatencyMs = &v
+			case "p95_latency_ms":
+				v := latencyPercentile(attempts, 0.95)
+				dp.P95LatencyMs = &v
 			}
 		}
 
@@ -334,6 +352,11 @@ func matchesEventMetricsFilter(event *models.Event, req driver.MetricsRequest) b
 			return false
 		}
 	}
+	if sources, ok := req.Filters["source"]; ok {
+		if !contains(sources, event.Source) {
+			return false
+		}
+	}
 	if dests, ok := req.Filters["destination_id"]; ok {
 		found := false
 		for _, d := range dests {
@@ -378,6 +401,11 @@ func matchesAttemptMetricsFilter(a *models.Attempt, event *models.Event, req dri
 			return false
 		}
 	}
+	if sources, ok := req.Filters["source"]; ok {
+		if !contains(sources, event.Source) {
+			return false
+		}
+	}
 	if codes, ok := req.Filters["code"]; ok {
 		if !contains(codes, a.Code) {
 			return false
@@ -419,6 +447,28 @@ func countByStatus(attempts []attemptWithEvent, status string) int {
 	return c
 }
 
+// latencyPercentile interpolates the p-th percentile of the recorded
+// latencies of attempts, like PostgreSQL's percentile_cont. It is 0 when no
+// attempt recorded a latency.
+func latencyPercentile(attempts []attemptWithEvent, p float64) float64 {
+	var latencies []float64
+	for _, ae := range attempts {
+		if ae.attempt.LatencyMs != nil {
+			latencies = append(latencies, float64(*ae.attempt.LatencyMs))
+		}
+	}
+	if len(latencies) == 0 {
+		return 0
+	}
+	slices.Sort(latencies)
+	pos := p * float64(len(latencies)-1)
+	lower := int(pos)
+	if lower+1 >= len(latencies) {
+		return latencies[lower]
+	}
+	return latencies[lower] + (pos-float64(lower))*(latencies[lower+1]-latencies[lower])
+}
+
 type attemptWithEvent struct {
 	attempt *models.Attempt
 	event   *models.Event
@@ -0,0 +1,53 @@
+package memlogstore
+
+import (
+	"context"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logstore/driver"
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+var _ driver.Pruner = (*memLogStore)(nil)
+
+func (s *memLogStore) Prune(ctx context.Context, req driver.PruneRequest) (driver.PruneResponse, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var resp driver.PruneResponse
+
+	kept := s.attempts[:0]
+	for _, a := range s.attempts {
+		var before *time.Time
+		switch a.Status {
+		case models.AttemptStatusSuccess:
+			before = req.SuccessBefore
+		case models.AttemptStatusFailed:
+			before = req.FailedBefore
+		case models.AttemptStatusDeferred:
+			before = req.DeferredBefore
+		}
+		if before != nil && a.Time.Before(*before) && !req.IsHeld(a.TenantID, a.EventID) {
+			resp.AttemptsDeleted++
+			continue
+		}
+		kept = append(kept, a)
+	}
+	s.attempts = kept
+
+	eventsBefore := req.EventsBefore()
+	if eventsBefore == nil {
+		return resp, nil
+	}
+	referenced := make(map[string]bool, len(s.attempts))
+	for _, a := range s.attempts {
+		referenced[a.EventID] = true
+	}
+	for id, e := range s.events {
+		if !referenced[id] && e.Time.Before(*eventsBefore) && !req.IsHeld(e.TenantID, id) {
+			delete(s.events, id)
+			resp.EventsDeleted++
+		}
+	}
+	return resp, nil
+}
@@ -93,6 +93,7 @@ func (s *logStore) QueryEventMetrics(ctx context.Context, req driver.MetricsRequ
 		sfTimeBucket sf = iota
 		sfTenantID
 		sfTopic
+		sfSource
 		sfDestID
 		sfCount
 	)
@@ -117,6 +118,10 @@ func (s *logStore) QueryEventMetrics(ctx context.Context, req driver.MetricsRequ
 			selectExprs = append(selectExprs, "topic")
 			groupExprs = append(groupExprs, "topic")
 			order = append(order, sfTopic)
+		case "source":
+			selectExprs = append(selectExprs, "source")
+			groupExprs = append(groupExprs, "source")
+			order = append(order, sfSource)
 		case "destination_id":
 			selectExprs = append(selectExprs, "destination_id")
 			groupExprs = append(groupExprs, "destination_id")
@@ -143,6 +148,9 @@ func (s *logStore) QueryEventMetrics(ctx context.Context, req driver.MetricsRequ
 	if topics, ok := req.Filters["topic"]; ok {
 		conditions = append(conditions, "topic = ANY("+arg(topics)+")")
 	}
+	if sources, ok := req.Filters["source"]; ok {
+		conditions = append(conditions, "source = ANY("+arg(sources)+")")
+	}
 	if dests, ok := req.Filters["destination_id"]; ok {
 		conditions = append(conditions, "matched_destination_ids && "+arg(dests)+"::text[]")
 	}
@@ -184,6 +192,7 @@ func (s *logStore) QueryEventMetrics(ctx context.Context, req driver.MetricsRequ
 		tbVal       time.Time
 		tenantIDVal string
 		topicVal    string
+		sourceVal   string
 		destIDVal   string
 		countVal    int
 	)
@@ -196,6 +205,8 @@ func (s *logStore) QueryEventMetrics(ctx context.Context, req driver.MetricsRequ
 			scanDests[i] = &tenantIDVal
 		case sfTopic:
 			scanDests[i] = &topicVal
+		case sfSource:
+			scanDests[i] = &sourceVal
 		case sfDestID:
 			scanDests[i] = &destIDVal
 		case sfCount:
@@ -221,6 +232,9 @@ func (s *logStore) QueryEventMetrics(ctx context.Context, req driver.MetricsRequ
 			case sfTopic:
 				v := topicVal
 				dp.Topic = &v
+			case sfSource:
+				v := sourceVal
+				dp.Source = &v
 			case sfDestID:
 				v := destIDVal
 				dp.DestinationID = &v
@@ -291,6 +305,7 @@ func (s *logStore) QueryAttemptMetrics(ctx context.Context, req driver.MetricsRe
 		sfDestID
 		sfDestType
 		sfTopic
+		sfSource
 		sfStatus
 		sfCode
 		sfManual
@@ -303,6 +318,8 @@ func (s *logStore) QueryAttemptMetrics(ctx context.Context, req driver.MetricsRe
 		sfRetryCount
 		sfManualRetry
 		sfAvgAttemptNum
+		sfP50Latency
+		sfP95Latency
 	)
 	var order []sf
 
@@ -333,6 +350,10 @@ func (s *logStore) QueryAttemptMetrics(ctx context.Context, req driver.MetricsRe
 			selectExprs = append(selectExprs, "topic")
 			groupExprs = append(groupExprs, "topic")
 			order = append(order, sfTopic)
+		case "source":
+			selectExprs = append(selectExprs, "event_source")
+			groupExprs = append(groupExprs, "event_source")
+			order = append(order, sfSource)
 		case "status":
 			selectExprs = append(selectExprs, "status")
 			groupExprs = append(groupExprs, "status")
@@ -379,6 +400,12 @@ func (s *logStore) QueryAttemptMetrics(ctx context.Context, req driver.MetricsRe
 		case "avg_attempt_number":
 			selectExprs = append(selectExprs, "AVG(attempt_number)::float8")
 			order = append(order, sfAvgAttemptNum)
+		case "p50_latency_ms":
+			selectExprs = append(selectExprs, "COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY latency_ms), 0)")
+			order = append(order, sfP50Latency)
+		case "p95_latency_ms":
+			selectExprs = append(selectExprs, "COALESCE(percentile_cont(0.95) WITHIN GROUP (ORDER BY latency_ms), 0)")
+			order = append(order, sfP95Latency)
 		}
 	}
 
@@ -401,6 +428,9 @@ func (s *logStore) QueryAttemptMetrics(ctx context.Context, req driver.MetricsRe
 	if topics, ok := req.Filters["topic"]; ok {
 		conditions = append(conditions, "topic = ANY("+arg(topics)+")")
 	}
+	if sources, ok := req.Filters["source"]; ok {
+		conditions = append(conditions, "event_source = ANY("+arg(sources)+")")
+	}
 	if codes, ok := req.Filters["code"]; ok {
 		conditions = append(conditions, "code = ANY("+arg(codes)+")")
 	}
@@ -435,6 +465,7 @@ func (s *logStore) QueryAttemptMetrics(ctx context.Context, req driver.MetricsRe
 		destIDVal        string
 		destTypeVal      string
 		topicVal         string
+		sourceVal        string
 		statusVal        string
 		codeVal          string
 		manualVal        bool
@@ -447,6 +478,8 @@ func (s *logStore) QueryAttemptMetrics(ctx context.Context, req driver.MetricsRe
 		retryCount       int
 		manualRetry      int
 		avgAttemptNum    float64
+		p50Latency       float64
+		p95Latency       float64
 	)
 
 	scanDests := make([]any, len(order))
@@ -462,6 +495,8 @@ func (s *logStore) QueryAttemptMetrics(ctx context.Context, req driver.MetricsRe
 			scanDests[i] = &destTypeVal
 		case sfTopic:
 			scanDests[i] = &topicVal
+		case sfSource:
+			scanDests[i] = &sourceVal
 		case sfStatus:
 			scanDests[i] = &statusVal
 		case sfCode:
@@ -486,6 +521,10 @@ func (s *logStore) QueryAttemptMetrics(ctx context.Context, req driver.MetricsRe
 			scanDests[i] = &manualRetry
 		case sfAvgAttemptNum:
 			scanDests[i] = &avgAttemptNum
+		case sfP50Latency:
+			scanDests[i] = &p50Latency
+		case sfP95Latency:
+			scanDests[i] = &p95Latency
 		}
 	}
 
@@ -513,6 +552,9 @@ func (s *logStore) QueryAttemptMetrics(ctx context.Context, req driver.MetricsRe
 			case sfTopic:
 				v := topicVal
 				dp.Topic = &v
+			case sfSource:
+				v := sourceVal
+				dp.Source = &v
 			case sfStatus:
 				v := statusVal
 				dp.Status = &v
@@ -549,6 +591,12 @@ func (s *logStore) QueryAttemptMetrics(ctx context.Context, req driver.MetricsRe
 			case sfAvgAttemptNum:
 				v := avgAttemptNum
 				dp.AvgAttemptNumber = &v
+			case sfP50Latency:
+				v := p50Latency
+				dp.P50LatencyMs = &v
+			case sfP95Latency:
+				v := p95Latency
+				dp.P95LatencyMs = &v
 			}
 		}
 		data = append(data, dp)
@@ -130,6 +130,31 @@ func buildEventQuery(req driver.ListEventRequest, q pagination.QueryInput) (stri
 		argNum++
 	}
 
+	if len(req.Sources) > 0 {
+		conditions = append(conditions, fmt.Sprintf("source = ANY($%d)", argNum))
+		args = append(args, req.Sources)
+		argNum++
+	}
+
+	if req.GroupID != "" {
+		conditions = append(conditions, fmt.Sprintf("group_id = $%d", argNum))
+		args = append(args, req.GroupID)
+		argNum++
+	}
+
+	if len(req.Metadata) > 0 {
+		conditions = append(conditions, fmt.Sprintf("metadata @> $%d::jsonb", argNum))
+		args = append(args, req.Metadata)
+		argNum++
+	}
+
+	// Matches the expression of the events search index.
+	if terms := driver.SearchTerms(req.Search); len(terms) > 0 {
+		conditions = append(conditions, fmt.Sprintf("to_tsvector('simple', data) @@ plainto_tsquery('simple', $%d)", argNum))
+		args = append(args, strings.Join(terms, " "))
+		argNum++
+	}
+
 	if req.TimeFilter.GTE != nil {
 		conditions = append(conditions, fmt.Sprintf("time >= $%d", argNum))
 		args = append(args, *req.TimeFilter.GTE)
@@ -175,7 +200,10 @@ func buildEventQuery(req driver.ListEventRequest, q pagination.QueryInput) (stri
 			topic,
 			eligible_for_retry,
 			data,
-			metadata
+			metadata,
+			checksum,
+			source,
+			group_id
 		FROM events
 		WHERE %s
 		%s
@@ -219,6 +247,9 @@ func scanEvents(rows pgx.Rows) ([]eventWithPosition, error) {
 			eligibleForRetry      bool
 			data                  string
 			metadata              map[string]string
+			checksum              string
+			source                string
+			groupID               string
 		)
 
 		if err := rows.Scan(
@@ -230,6 +261,9 @@ func scanEvents(rows pgx.Rows) ([]eventWithPosition, error) {
 			&eligibleForRetry,
 			&data,
 			&metadata,
+			&checksum,
+			&source,
+			&groupID,
 		); err != nil {
 			return nil, fmt.Errorf("scan failed: %w", err)
 		}
@@ -251,6 +285,9 @@ func scanEvents(rows pgx.Rows) ([]eventWithPosition, error) {
 				Time:                  eventTime,
 				Data:                  []byte(data),
 				Metadata:              metadata,
+				Checksum:              checksum,
+				Source:                source,
+				GroupID:               groupID,
 			},
 			eventTime: eventTime,
 		})
@@ -356,6 +393,12 @@ func buildAttemptQuery(req driver.ListAttemptRequest, q pagination.QueryInput) (
 		argNum++
 	}
 
+	if len(req.Sources) > 0 {
+		conditions = append(conditions, fmt.Sprintf("event_source = ANY($%d)", argNum))
+		args = append(args, req.Sources)
+		argNum++
+	}
+
 	if req.TimeFilter.GTE != nil {
 		conditions = append(conditions, fmt.Sprintf("time >= $%d", argNum))
 		args = append(args, *req.TimeFilter.GTE)
@@ -406,10 +449,16 @@ func buildAttemptQuery(req driver.ListAttemptRequest, q pagination.QueryInput) (
 			manual,
 			code,
 			response_data,
+			destination_snapshot,
 			event_time,
 			eligible_for_retry,
 			event_data,
-			event_metadata
+			event_metadata,
+			event_checksum,
+			event_source,
+			error_code,
+			error_message,
+			latency_ms
 		FROM attempts
 		WHERE %s
 		%s
@@ -457,10 +506,16 @@ func scanAttemptRecords(rows pgx.Rows) ([]attemptRecordWithPosition, error) {
 			manual           bool
 			code             string
 			responseDataStr  string
+			snapshotStr      string
 			eventTime        time.Time
 			eligibleForRetry bool
 			eventData        string
 			eventMetadata    map[string]string
+			eventChecksum    string
+			eventSource      string
+			errorCode        string
+			errorMessage     string
+			latencyMs        *int64
 		)
 
 		if err := rows.Scan(
@@ -476,10 +531,16 @@ func scanAttemptRecords(rows pgx.Rows) ([]attemptRecordWithPosition, error) {
 			&manual,
 			&code,
 			&responseDataStr,
+			&snapshotStr,
 			&eventTime,
 			&eligibleForRetry,
 			&eventData,
 			&eventMetadata,
+			&eventChecksum,
+			&eventSource,
+			&errorCode,
+			&errorMessage,
+			&latencyMs,
 		); err != nil {
 			return nil, fmt.Errorf("scan failed: %w", err)
 		}
@@ -490,6 +551,10 @@ func scanAttemptRecords(rows pgx.Rows) ([]attemptRecordWithPosition, error) {
 				return nil, fmt.Errorf("failed to unmarshal response_data: %w", err)
 			}
 		}
+		snapshot, err := driver.DecodeDestinationSnapshot(snapshotStr)
+		if err != nil {
+			return nil, err
+		}
 
 		// Normalize to UTC for consistent behavior across backends.
 		attemptTime = attemptTime.UTC()
@@ -498,17 +563,21 @@ func scanAttemptRecords(rows pgx.Rows) ([]attemptRecordWithPosition, error) {
 		results = append(results, attemptRecordWithPosition{
 			AttemptRecord: &driver.AttemptRecord{
 				Attempt: &models.Attempt{
-					ID:              id,
-					TenantID:        tenantID,
-					EventID:         eventID,
-					DestinationID:   destinationID,
-					DestinationType: destinationType,
-					AttemptNumber:   attemptNumber,
-					Manual:          manual,
-					Status:          status,
-					Time:            attemptTime,
-					Code:            code,
-					ResponseData:    responseData,
+					ID:                  id,
+					TenantID:            tenantID,
+					EventID:             eventID,
+					DestinationID:       destinationID,
+					DestinationType:     destinationType,
+					AttemptNumber:       attemptNumber,
+					Manual:              manual,
+					Status:              status,
+					Time:                attemptTime,
+					Code:                code,
+					ResponseData:        responseData,
+					DestinationSnapshot: snapshot,
+					ErrorCode:           errorCode,
+					ErrorMessage:        errorMessage,
+					LatencyMs:           latencyMs,
 				},
 				Event: &models.Event{
 					ID:               eventID,
@@ -519,6 +588,8 @@ func scanAttemptRecords(rows pgx.Rows) ([]attemptRecordWithPosition, error) {
 					Time:             eventTime,
 					Data:             []byte(eventData),
 					Metadata:         eventMetadata,
+					Checksum:         eventChecksum,
+					Source:           eventSource,
 				},
 			},
 			attemptTime: attemptTime,
@@ -557,7 +628,10 @@ func (s *logStore) RetrieveEvent(ctx context.Context, req driver.RetrieveEventRe
 			eligible_for_retry,
 			time,
 			metadata,
-			data
+			data,
+			checksum,
+			source,
+			group_id
 		FROM events
 		WHERE %s
 		LIMIT 1`, whereClause)
@@ -575,6 +649,9 @@ func (s *logStore) RetrieveEvent(ctx context.Context, req driver.RetrieveEventRe
 		&event.Time,
 		&event.Metadata,
 		&dataStr,
+		&event.Checksum,
+		&event.Source,
+		&event.GroupID,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -621,10 +698,16 @@ func (s *logStore) RetrieveAttempt(ctx context.Context, req driver.RetrieveAttem
 			manual,
 			code,
 			response_data,
+			destination_snapshot,
 			event_time,
 			eligible_for_retry,
 			event_data,
-			event_metadata
+			event_metadata,
+			event_checksum,
+			event_source,
+			error_code,
+			error_message,
+			latency_ms
 		FROM attempts
 		WHERE %s
 		LIMIT 1`, whereClause)
@@ -644,10 +727,16 @@ func (s *logStore) RetrieveAttempt(ctx context.Context, req driver.RetrieveAttem
 		manual           bool
 		code             string
 		responseDataStr  string
+		snapshotStr      string
 		eventTime        time.Time
 		eligibleForRetry bool
 		eventData        string
 		eventMetadata    map[string]string
+		eventChecksum    string
+		eventSource      string
+		errorCode        string
+		errorMessage     string
+		latencyMs        *int64
 	)
 
 	err := row.Scan(
@@ -663,10 +752,16 @@ func (s *logStore) RetrieveAttempt(ctx context.Context, req driver.RetrieveAttem
 		&manual,
 		&code,
 		&responseDataStr,
+		&snapshotStr,
 		&eventTime,
 		&eligibleForRetry,
 		&eventData,
 		&eventMetadata,
+		&eventChecksum,
+		&eventSource,
+		&errorCode,
+		&errorMessage,
+		&latencyMs,
 	)
 	if err == pgx.ErrNoRows {
 		return nil, nil
@@ -681,6 +776,10 @@ func (s *logStore) RetrieveAttempt(ctx context.Context, req driver.RetrieveAttem
 			return nil, fmt.Errorf("failed to unmarshal response_data: %w", err)
 		}
 	}
+	snapshot, err := driver.DecodeDestinationSnapshot(snapshotStr)
+	if err != nil {
+		return nil, err
+	}
 
 	// Normalize to UTC for consistent behavior across backends.
 	attemptTime = attemptTime.UTC()
@@ -688,17 +787,21 @@ func (s *logStore) RetrieveAttempt(ctx context.Context, req driver.RetrieveAttem
 
 	return &driver.AttemptRecord{
 		Attempt: &models.Attempt{
-			ID:              id,
-			TenantID:        tenantID,
-			EventID:         eventID,
-			DestinationID:   destinationID,
-			DestinationType: destinationType,
-			AttemptNumber:   attemptNumber,
-			Manual:          manual,
-			Status:          status,
-			Time:            attemptTime,
-			Code:            code,
-			ResponseData:    responseData,
+			ID:                  id,
+			TenantID:            tenantID,
+			EventID:             eventID,
+			DestinationID:       destinationID,
+			DestinationType:     destinationType,
+			AttemptNumber:       attemptNumber,
+			Manual:              manual,
+			Status:              status,
+			Time:                attemptTime,
+			Code:                code,
+			ResponseData:        responseData,
+			DestinationSnapshot: snapshot,
+			ErrorCode:           errorCode,
+			ErrorMessage:        errorMessage,
+			LatencyMs:           latencyMs,
 		},
 		Event: &models.Event{
 			ID:               eventID,
@@ -709,6 +812,8 @@ func (s *logStore) RetrieveAttempt(ctx context.Context, req driver.RetrieveAttem
 			Time:             eventTime,
 			Data:             []byte(eventData),
 			Metadata:         eventMetadata,
+			Checksum:         eventChecksum,
+			Source:           eventSource,
 		},
 	}, nil
 }
@@ -725,10 +830,11 @@ func (s *logStore) InsertMany(ctx context.Context, entries []*models.LogEntry) e
 
 	// Extract and dedupe events by ID, skipping retry attempts.
 	// Retries (AttemptNumber > 1) carry identical event data — the event row
-	// already exists from the first attempt's batch.
+	// already exists from the first attempt's batch, unless WriteEvent says
+	// otherwise.
 	eventMap := make(map[string]*models.Event)
 	for _, entry := range entries {
-		if entry.Attempt.AttemptNumber <= 1 {
+		if entry.Attempt.AttemptNumber <= 1 || entry.WriteEvent {
 			eventMap[entry.Event.ID] = entry.Event
 		}
 	}
@@ -748,15 +854,16 @@ func (s *logStore) InsertMany(ctx context.Context, entries []*models.LogEntry) e
 	// and cast to text[] per row, because PostgreSQL's unnest flattens 2D text arrays.
 	if len(events) > 0 {
 		_, err = tx.Exec(ctx, `
-			INSERT INTO events (id, tenant_id, matched_destination_ids, time, topic, eligible_for_retry, data, metadata)
+			INSERT INTO events (id, tenant_id, matched_destination_ids, time, topic, eligible_for_retry, data, metadata, checksum, source, group_id)
 			SELECT
 				u.id, u.tenant_id,
 				ARRAY(SELECT jsonb_array_elements_text(u.matched_dest_json)),
-				u.time, u.topic, u.eligible_for_retry, u.data, u.metadata
+				u.time, u.topic, u.eligible_for_retry, u.data, u.metadata, u.checksum, u.source, u.group_id
 			FROM unnest(
 				$1::text[], $2::text[], $3::jsonb[],
-				$4::timestamptz[], $5::text[], $6::boolean[], $7::text[], $8::jsonb[]
-			) AS u(id, tenant_id, matched_dest_json, time, topic, eligible_for_retry, data, metadata)
+				$4::timestamptz[], $5::text[], $6::boolean[], $7::text[], $8::jsonb[], $9::text[], $10::text[],
+				$11::text[]
+			) AS u(id, tenant_id, matched_dest_json, time, topic, eligible_for_retry, data, metadata, checksum, source, group_id)
 			ON CONFLICT (time, id) DO NOTHING
 		`, eventArrays(events)...)
 		if err != nil {
@@ -770,17 +877,22 @@ func (s *logStore) InsertMany(ctx context.Context, entries []*models.LogEntry) e
 			INSERT INTO attempts (
 				id, event_id, tenant_id, destination_id, destination_type, topic, status,
 				time, attempt_number, manual, code, response_data,
-				event_time, eligible_for_retry, event_data, event_metadata
+				event_time, eligible_for_retry, event_data, event_metadata, destination_snapshot, event_checksum,
+				event_source, error_code, error_message, latency_ms
 			)
 			SELECT * FROM unnest(
 				$1::text[], $2::text[], $3::text[], $4::text[], $5::text[], $6::text[], $7::text[],
 				$8::timestamptz[], $9::integer[], $10::boolean[], $11::text[], $12::text[],
-				$13::timestamptz[], $14::boolean[], $15::text[], $16::jsonb[]
+				$13::timestamptz[], $14::boolean[], $15::text[], $16::jsonb[], $17::text[], $18::text[],
+				$19::text[], $20::text[], $21::text[], $22::bigint[]
 			)
 			ON CONFLICT (time, id) DO UPDATE SET
 				status = EXCLUDED.status,
 				code = EXCLUDED.code,
-				response_data = EXCLUDED.response_data
+				response_data = EXCLUDED.response_data,
+				error_code = EXCLUDED.error_code,
+				error_message = EXCLUDED.error_message,
+				latency_ms = EXCLUDED.latency_ms
 		`, attemptArrays(entries)...)
 		if err != nil {
 			return fmt.Errorf("insert attempts failed: %w", err)
@@ -799,6 +911,9 @@ func eventArrays(events []*models.Event) []any {
 	eligibleForRetries := make([]bool, len(events))
 	datas := make([]string, len(events))
 	metadatas := make([]map[string]string, len(events))
+	checksums := make([]string, len(events))
+	sources := make([]string, len(events))
+	groupIDs := make([]string, len(events))
 
 	for i, e := range events {
 		ids[i] = e.ID
@@ -820,6 +935,9 @@ func eventArrays(events []*models.Event) []any {
 			metadata = map[string]string{}
 		}
 		metadatas[i] = metadata
+		checksums[i] = e.Checksum
+		sources[i] = e.Source
+		groupIDs[i] = e.GroupID
 	}
 
 	return []any{
@@ -831,6 +949,9 @@ func eventArrays(events []*models.Event) []any {
 		eligibleForRetries,
 		datas,
 		metadatas,
+		checksums,
+		sources,
+		groupIDs,
 	}
 }
 
@@ -854,6 +975,12 @@ func attemptArrays(entries []*models.LogEntry) []any {
 	eligibleForRetries := make([]bool, n)
 	eventDatas := make([]string, n)
 	eventMetadatas := make([]map[string]string, n)
+	snapshots := make([]string, n)
+	eventChecksums := make([]string, n)
+	eventSources := make([]string, n)
+	errorCodes := make([]string, n)
+	errorMessages := make([]string, n)
+	latencies := make([]*int64, n)
 
 	for i, entry := range entries {
 		a := entry.Attempt
@@ -881,6 +1008,12 @@ func attemptArrays(entries []*models.LogEntry) []any {
 			eventMetadata = map[string]string{}
 		}
 		eventMetadatas[i] = eventMetadata
+		snapshots[i] = driver.EncodeDestinationSnapshot(a.DestinationSnapshot)
+		eventChecksums[i] = e.Checksum
+		eventSources[i] = e.Source
+		errorCodes[i] = a.ErrorCode
+		errorMessages[i] = a.ErrorMessage
+		latencies[i] = a.LatencyMs
 	}
 
 	return []any{
@@ -900,5 +1033,11 @@ func attemptArrays(entries []*models.LogEntry) []any {
 		eligibleForRetries,
 		eventDatas,
 		eventMetadatas,
+		snapshots,
+		eventChecksums,
+		eventSources,
+		errorCodes,
+		errorMessages,
+		latencies,
 	}
 }
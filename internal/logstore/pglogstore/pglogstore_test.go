@@ -3,13 +3,16 @@ package pglogstore
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/hookdeck/outpost/internal/logstore/driver"
 	"github.com/hookdeck/outpost/internal/logstore/drivertest"
 	"github.com/hookdeck/outpost/internal/migrator"
+	"github.com/hookdeck/outpost/internal/models"
 	"github.com/hookdeck/outpost/internal/util/testinfra"
 	"github.com/hookdeck/outpost/internal/util/testutil"
 	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -78,3 +81,61 @@ func (h *harness) FlushWrites(ctx context.Context) error {
 func (h *harness) MakeDriver(ctx context.Context) (driver.LogStore, error) {
 	return NewLogStore(h.db), nil
 }
+
+func TestPrune_DropsExpiredPartitions(t *testing.T) {
+	testutil.CheckIntegrationTest(t)
+	t.Parallel()
+
+	ctx := context.Background()
+	db := setupPGConnection(t)
+	t.Cleanup(db.Close)
+
+	for _, stmt := range []string{
+		"CREATE TABLE events_p20200101 PARTITION OF events FOR VALUES FROM ('2020-01-01T00:00:00Z') TO ('2020-02-01T00:00:00Z')",
+		"CREATE TABLE attempts_p20200101 PARTITION OF attempts FOR VALUES FROM ('2020-01-01T00:00:00Z') TO ('2020-02-01T00:00:00Z')",
+		"CREATE TABLE events_p20200201 PARTITION OF events FOR VALUES FROM ('2020-02-01T00:00:00Z') TO ('2020-03-01T00:00:00Z')",
+	} {
+		_, err := db.Exec(ctx, stmt)
+		require.NoError(t, err)
+	}
+
+	entry := func(eventID string, eventTime, attemptTime time.Time) *models.LogEntry {
+		return &models.LogEntry{
+			Event: testutil.EventFactory.AnyPointer(
+				testutil.EventFactory.WithID(eventID),
+				testutil.EventFactory.WithTime(eventTime),
+			),
+			Attempt: testutil.AttemptFactory.AnyPointer(
+				testutil.AttemptFactory.WithEventID(eventID),
+				testutil.AttemptFactory.WithTime(attemptTime),
+			),
+		}
+	}
+	january := time.Date(2020, 1, 15, 0, 0, 0, 0, time.UTC)
+	february := time.Date(2020, 2, 15, 0, 0, 0, 0, time.UTC)
+	store := NewLogStore(db).(*logStore)
+	require.NoError(t, store.InsertMany(ctx, []*models.LogEntry{
+		entry("evt-january", january, january),
+		// Retried long after, so its event must outlive its partition's range.
+		entry("evt-february", february, time.Now().Add(-time.Hour)),
+	}))
+
+	cutoff := time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC)
+	resp, err := store.Prune(ctx, driver.PruneRequest{
+		SuccessBefore:  &cutoff,
+		FailedBefore:   &cutoff,
+		DeferredBefore: &cutoff,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), resp.AttemptsDeleted)
+	assert.Equal(t, int64(1), resp.EventsDeleted)
+
+	partitionExists := func(name string) bool {
+		var exists bool
+		require.NoError(t, db.QueryRow(ctx, "SELECT to_regclass($1) IS NOT NULL", name).Scan(&exists))
+		return exists
+	}
+	assert.False(t, partitionExists("events_p20200101"))
+	assert.False(t, partitionExists("attempts_p20200101"))
+	assert.True(t, partitionExists("events_p20200201"), "partition with an event still attempted is kept")
+}
@@ -0,0 +1,199 @@
+package pglogstore
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logstore/driver"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/jackc/pgx/v5"
+)
+
+var _ driver.Pruner = (*logStore)(nil)
+
+// Prune deletes attempts past their status cutoff, then the events left
+// without attempts. Partitions entirely past the cutoffs, as created with
+// LOGSTORE_PARTITION_INTERVAL, are dropped first rather than deleted row by
+// row, unless they hold records under a legal hold.
+func (s *logStore) Prune(ctx context.Context, req driver.PruneRequest) (driver.PruneResponse, error) {
+	resp, err := s.dropExpiredPartitions(ctx, req)
+	if err != nil {
+		return resp, err
+	}
+
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return resp, err
+	}
+	defer tx.Rollback(ctx)
+
+	heldTenantIDs, heldEventIDs := heldIDs(req)
+
+	for _, cutoff := range []struct {
+		status string
+		before *time.Time
+	}{
+		{models.AttemptStatusSuccess, req.SuccessBefore},
+		{models.AttemptStatusFailed, req.FailedBefore},
+		{models.AttemptStatusDeferred, req.DeferredBefore},
+	} {
+		if cutoff.before == nil {
+			continue
+		}
+		tag, err := tx.Exec(ctx, `
+			DELETE FROM attempts
+			WHERE status = $1 AND time < $2
+			AND NOT (tenant_id = ANY($3) OR event_id = ANY($4))
+		`, cutoff.status, *cutoff.before, heldTenantIDs, heldEventIDs)
+		if err != nil {
+			return resp, fmt.Errorf("prune %s attempts failed: %w", cutoff.status, err)
+		}
+		resp.AttemptsDeleted += tag.RowsAffected()
+	}
+
+	if eventsBefore := req.EventsBefore(); eventsBefore != nil {
+		tag, err := tx.Exec(ctx, `
+			DELETE FROM events e
+			WHERE e.time < $1
+			AND NOT EXISTS (SELECT 1 FROM attempts a WHERE a.event_id = e.id)
+			AND NOT (e.tenant_id = ANY($2) OR e.id = ANY($3))
+		`, *eventsBefore, heldTenantIDs, heldEventIDs)
+		if err != nil {
+			return resp, fmt.Errorf("prune events failed: %w", err)
+		}
+		resp.EventsDeleted = tag.RowsAffected()
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return driver.PruneResponse{}, err
+	}
+	return resp, nil
+}
+
+// dropExpiredPartitions drops the attempts partitions ending before every
+// status cutoff, then the events partitions ending before the events cutoff
+// whose events have no attempts left. Partitions holding held records are
+// kept for Prune to delete the rest row by row. Each partition is dropped in
+// its own transaction so the parent table is only locked briefly.
+func (s *logStore) dropExpiredPartitions(ctx context.Context, req driver.PruneRequest) (driver.PruneResponse, error) {
+	var resp driver.PruneResponse
+	heldTenantIDs, heldEventIDs := heldIDs(req)
+
+	if req.SuccessBefore != nil && req.FailedBefore != nil && req.DeferredBefore != nil {
+		attemptsBefore := *req.SuccessBefore
+		for _, cutoff := range []time.Time{*req.FailedBefore, *req.DeferredBefore} {
+			if cutoff.Before(attemptsBefore) {
+				attemptsBefore = cutoff
+			}
+		}
+		partitions, err := s.partitionsEndingBefore(ctx, "attempts", attemptsBefore)
+		if err != nil {
+			return resp, err
+		}
+		for _, partition := range partitions {
+			keepQuery := ""
+			if req.HasHolds() {
+				keepQuery = `
+					SELECT EXISTS (
+						SELECT 1 FROM ` + partition + `
+						WHERE tenant_id = ANY($1) OR event_id = ANY($2)
+					)
+				`
+			}
+			rows, err := s.dropPartition(ctx, partition, keepQuery, heldTenantIDs, heldEventIDs)
+			if err != nil {
+				return resp, err
+			}
+			resp.AttemptsDeleted += rows
+		}
+	}
+
+	if eventsBefore := req.EventsBefore(); eventsBefore != nil {
+		partitions, err := s.partitionsEndingBefore(ctx, "events", *eventsBefore)
+		if err != nil {
+			return resp, err
+		}
+		for _, partition := range partitions {
+			rows, err := s.dropPartition(ctx, partition, `
+				SELECT EXISTS (
+					SELECT 1 FROM `+partition+` e
+					JOIN attempts a ON a.event_id = e.id
+				) OR EXISTS (
+					SELECT 1 FROM `+partition+`
+					WHERE tenant_id = ANY($1) OR id = ANY($2)
+				)
+			`, heldTenantIDs, heldEventIDs)
+			if err != nil {
+				return resp, err
+			}
+			resp.EventsDeleted += rows
+		}
+	}
+
+	return resp, nil
+}
+
+// heldIDs returns the held tenant and event IDs as non-nil slices: pgx sends
+// a nil slice as NULL, and NOT (id = ANY(NULL)) would keep every row.
+func heldIDs(req driver.PruneRequest) ([]string, []string) {
+	return append([]string{}, req.HeldTenantIDs...), append([]string{}, req.HeldEventIDs...)
+}
+
+// partitionsEndingBefore returns the quoted names of the range partitions of
+// table whose upper bound is at or before cutoff. The default partition has
+// no bound and is never returned.
+func (s *logStore) partitionsEndingBefore(ctx context.Context, table string, cutoff time.Time) ([]string, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT c.relname
+		FROM pg_inherits i
+		JOIN pg_class c ON c.oid = i.inhrelid
+		WHERE i.inhparent = to_regclass($1)
+		AND (regexp_match(pg_get_expr(c.relpartbound, c.oid), 'TO \(''([^'']+)''\)'))[1]::timestamptz <= $2
+		ORDER BY c.relname
+	`, table, cutoff)
+	if err != nil {
+		return nil, fmt.Errorf("list %s partitions failed: %w", table, err)
+	}
+	names, err := pgx.CollectRows(rows, pgx.RowTo[string])
+	if err != nil {
+		return nil, fmt.Errorf("list %s partitions failed: %w", table, err)
+	}
+	for i, name := range names {
+		names[i] = pgx.Identifier{name}.Sanitize()
+	}
+	return names, nil
+}
+
+// dropPartition drops partition and returns the number of rows it held. When
+// keepQuery is set and returns true given args, the partition is kept and 0
+// returned.
+func (s *logStore) dropPartition(ctx context.Context, partition, keepQuery string, args ...any) (int64, error) {
+	tx, err := s.db.Begin(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	if keepQuery != "" {
+		var keep bool
+		if err := tx.QueryRow(ctx, keepQuery, args...).Scan(&keep); err != nil {
+			return 0, fmt.Errorf("check partition %s failed: %w", partition, err)
+		}
+		if keep {
+			return 0, nil
+		}
+	}
+
+	var rows int64
+	if err := tx.QueryRow(ctx, "SELECT count(*) FROM "+partition).Scan(&rows); err != nil {
+		return 0, fmt.Errorf("count partition %s failed: %w", partition, err)
+	}
+	if _, err := tx.Exec(ctx, "DROP TABLE "+partition); err != nil {
+		return 0, fmt.Errorf("drop partition %s failed: %w", partition, err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return 0, err
+	}
+	return rows, nil
+}
@@ -0,0 +1,301 @@
+package tieredlogstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/cursor"
+	"github.com/hookdeck/outpost/internal/logstore/driver"
+)
+
+// Stitched cursors hold the tier to continue from, the tier's own cursor
+// and the tier boundaries pinned on the first page, so that records crossing
+// a boundary between requests are neither skipped nor listed twice:
+//
+//	{tier}:{boundary unix nanos, comma-separated}:{tier cursor}
+//
+// An empty tier cursor points at the start of the tier when paging forward
+// and at its end when paging backward. Tier cursors encode a record position
+// rather than a direction, which lets a tier read in reverse from its end and
+// hand out a cursor for paging back in the requested order.
+const (
+	cursorResourceEvent   = "tevt"
+	cursorResourceAttempt = "tatt"
+	cursorVersion         = 1
+)
+
+type listRequest struct {
+	resource   string
+	next       string
+	prev       string
+	limit      int
+	sortOrder  string
+	timeFilter driver.TimeFilter
+}
+
+// tierQuery is a list request narrowed to one tier.
+type tierQuery struct {
+	next       string
+	prev       string
+	limit      int
+	sortOrder  string
+	timeFilter driver.TimeFilter
+}
+
+type tierPage[T any] struct {
+	data []T
+	next string
+	prev string
+}
+
+type listFunc[T any] func(ctx context.Context, tier int, q tierQuery) (tierPage[T], error)
+
+// stitcher pages through the tiers in the requested order.
+type stitcher[T any] struct {
+	resource   string
+	limit      int
+	sortOrder  string
+	timeFilter driver.TimeFilter
+	// boundaries[i] separates tier i from the older tier i+1.
+	boundaries []time.Time
+	// order lists the tiers in the requested sort order.
+	order []int
+	list  listFunc[T]
+}
+
+func list[T any](ctx context.Context, s *tieredLogStore, req listRequest, fn listFunc[T]) (tierPage[T], error) {
+	st := &stitcher[T]{
+		resource:   req.resource,
+		limit:      req.limit,
+		sortOrder:  req.sortOrder,
+		timeFilter: req.timeFilter,
+		list:       fn,
+	}
+	if st.sortOrder != "asc" && st.sortOrder != "desc" {
+		st.sortOrder = "desc"
+	}
+	if st.limit <= 0 {
+		st.limit = 100
+	}
+	tiers := len(s.tiers)
+	if s.archive != nil {
+		tiers++
+	}
+	st.order = make([]int, tiers)
+	for i := range st.order {
+		st.order[i] = i
+	}
+	if st.sortOrder == "asc" {
+		slices.Reverse(st.order)
+	}
+
+	if req.next == "" && req.prev == "" {
+		now := s.clock.Now()
+		for _, tier := range s.tiers[:tiers-1] {
+			st.boundaries = append(st.boundaries, now.Add(-tier.MaxAge))
+		}
+		if s.archive != nil {
+			events, attempts, err := s.archive.ArchivedBefore(ctx)
+			if err != nil {
+				return tierPage[T]{}, fmt.Errorf("archive: %w", err)
+			}
+			archived := attempts
+			if req.resource == cursorResourceEvent {
+				archived = events
+			}
+			// The last tier keeps serving what isn't archived yet.
+			if last := len(st.boundaries) - 1; archived.Before(st.boundaries[last]) {
+				st.boundaries[last] = archived
+			}
+		}
+		return st.forward(ctx, 0, "", true)
+	}
+
+	encoded := req.next
+	if req.prev != "" {
+		encoded = req.prev
+	}
+	tier, inner, err := st.decode(encoded, tiers)
+	if err != nil {
+		return tierPage[T]{}, err
+	}
+	pos := slices.Index(st.order, tier)
+	if req.prev != "" {
+		return st.backward(ctx, pos, inner)
+	}
+	return st.forward(ctx, pos, inner, false)
+}
+
+// forward fills a page from the tier at pos onwards, starting after inner.
+func (st *stitcher[T]) forward(ctx context.Context, pos int, inner string, firstPage bool) (tierPage[T], error) {
+	var page tierPage[T]
+	for ; pos < len(st.order); pos++ {
+		tier := st.order[pos]
+		resp, err := st.query(ctx, tier, tierQuery{next: inner, limit: st.limit - len(page.data)})
+		if err != nil {
+			return tierPage[T]{}, err
+		}
+		if len(page.data) == 0 && len(resp.data) > 0 && !firstPage {
+			if inner != "" {
+				page.prev = st.encode(tier, resp.prev)
+			} else if pos > 0 {
+				page.prev = st.encode(st.order[pos-1], "")
+			}
+		}
+		page.data = append(page.data, resp.data...)
+		if len(page.data) == st.limit {
+			if resp.next != "" {
+				page.next = st.encode(tier, resp.next)
+			} else if next, err := st.firstWithData(ctx, pos+1, 1); err != nil {
+				return tierPage[T]{}, err
+			} else if next >= 0 {
+				page.next = st.encode(st.order[next], "")
+			}
+			break
+		}
+		inner = ""
+	}
+	return page, nil
+}
+
+// backward fills a page from the tier at pos back to the first tier, ending
+// before inner.
+func (st *stitcher[T]) backward(ctx context.Context, pos int, inner string) (tierPage[T], error) {
+	var page tierPage[T]
+	for ; pos >= 0; pos-- {
+		tier := st.order[pos]
+		limit := st.limit - len(page.data)
+		var (
+			resp   tierPage[T]
+			before string // tier cursor to the records preceding resp.data
+			err    error
+		)
+		if inner != "" {
+			resp, err = st.query(ctx, tier, tierQuery{prev: inner, limit: limit})
+			before = resp.prev
+		} else {
+			// Read the tier from its end in reverse order.
+			resp, err = st.query(ctx, tier, tierQuery{limit: limit, sortOrder: flip(st.sortOrder)})
+			slices.Reverse(resp.data)
+			before = resp.next
+		}
+		if err != nil {
+			return tierPage[T]{}, err
+		}
+		if len(page.data) == 0 && len(resp.data) > 0 {
+			if inner != "" {
+				page.next = st.encode(tier, resp.next)
+			} else if pos+1 < len(st.order) {
+				page.next = st.encode(st.order[pos+1], "")
+			}
+		}
+		page.data = append(resp.data, page.data...)
+		if len(page.data) == st.limit {
+			if before != "" {
+				page.prev = st.encode(tier, before)
+			} else if prev, err := st.firstWithData(ctx, pos-1, -1); err != nil {
+				return tierPage[T]{}, err
+			} else if prev >= 0 {
+				page.prev = st.encode(st.order[prev], "")
+			}
+			break
+		}
+		inner = ""
+	}
+	return page, nil
+}
+
+// firstWithData returns the position of the first tier from pos, moving by
+// step, with records in range, or -1.
+func (st *stitcher[T]) firstWithData(ctx context.Context, pos, step int) (int, error) {
+	for ; pos >= 0 && pos < len(st.order); pos += step {
+		resp, err := st.query(ctx, st.order[pos], tierQuery{limit: 1})
+		if err != nil {
+			return 0, err
+		}
+		if len(resp.data) > 0 {
+			return pos, nil
+		}
+	}
+	return -1, nil
+}
+
+func (st *stitcher[T]) query(ctx context.Context, tier int, q tierQuery) (tierPage[T], error) {
+	if q.sortOrder == "" {
+		q.sortOrder = st.sortOrder
+	}
+	q.timeFilter = st.tierFilter(tier)
+	page, err := st.list(ctx, tier, q)
+	if err != nil {
+		return tierPage[T]{}, fmt.Errorf("tier %d: %w", tier, err)
+	}
+	return page, nil
+}
+
+// tierFilter narrows the requested time filter to the records the tier
+// serves: from its boundary (inclusive) up to the newer tier's (exclusive).
+func (st *stitcher[T]) tierFilter(tier int) driver.TimeFilter {
+	filter := st.timeFilter
+	if tier < len(st.boundaries) {
+		lower := st.boundaries[tier]
+		if filter.GTE == nil || lower.After(*filter.GTE) {
+			filter.GTE = &lower
+		}
+	}
+	if tier > 0 {
+		upper := st.boundaries[tier-1]
+		if filter.LT == nil || upper.Before(*filter.LT) {
+			filter.LT = &upper
+		}
+	}
+	return filter
+}
+
+func (st *stitcher[T]) encode(tier int, inner string) string {
+	boundaries := make([]string, len(st.boundaries))
+	for i, b := range st.boundaries {
+		boundaries[i] = strconv.FormatInt(b.UnixNano(), 10)
+	}
+	return cursor.Encode(st.resource, cursorVersion, fmt.Sprintf("%d:%s:%s", tier, strings.Join(boundaries, ","), inner))
+}
+
+// decode parses a cursor into its tier and tier cursor and pins the
+// boundaries it carries. Cursors from a different tier layout are invalid.
+func (st *stitcher[T]) decode(encoded string, tiers int) (int, string, error) {
+	data, err := cursor.Decode(encoded, st.resource, cursorVersion)
+	if err != nil {
+		return 0, "", err
+	}
+	parts := strings.SplitN(data, ":", 3)
+	if len(parts) != 3 {
+		return 0, "", cursor.ErrInvalidCursor
+	}
+	tier, err := strconv.Atoi(parts[0])
+	if err != nil || tier < 0 || tier >= tiers {
+		return 0, "", cursor.ErrInvalidCursor
+	}
+	boundaries := strings.Split(parts[1], ",")
+	if len(boundaries) != tiers-1 {
+		return 0, "", cursor.ErrInvalidCursor
+	}
+	st.boundaries = make([]time.Time, len(boundaries))
+	for i, b := range boundaries {
+		nanos, err := strconv.ParseInt(b, 10, 64)
+		if err != nil {
+			return 0, "", cursor.ErrInvalidCursor
+		}
+		st.boundaries[i] = time.Unix(0, nanos)
+	}
+	return tier, parts[2], nil
+}
+
+func flip(order string) string {
+	if order == "asc" {
+		return "desc"
+	}
+	return "asc"
+}
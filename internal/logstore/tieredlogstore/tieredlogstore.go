@@ -0,0 +1,262 @@
+// Package tieredlogstore composes log stores into tiers by record age: recent
+// records are served from a fast store that only holds a short window, older
+// ones from stores with longer retention.
+//
+// Writes go through to every tier, so the last tier is the system of record
+// and each earlier tier only needs to hold its window; Prune trims it to that
+// window. Reads are routed by record time and list results are stitched
+// across tiers behind a single cursor.
+//
+// An archive can follow the last tier, for records the last tier no longer
+// keeps: it is read-only and only lists records, see Archive.
+package tieredlogstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/logstore/driver"
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+var ErrInvalidTiers = errors.New("invalid log store tiers")
+
+// Tier is one store of a tiered log store.
+type Tier struct {
+	Store driver.LogStore
+	// MaxAge is the age up to which the tier serves records that no earlier
+	// tier serves. It must grow from tier to tier, and is 0 on the last tier,
+	// which serves everything older, unless an archive follows it.
+	MaxAge time.Duration
+}
+
+// Archive is a read-only tier following the last one, such as delivery logs
+// exported to object storage. It serves the records older than both the last
+// tier's max age and the time up to which it is complete, so records keep
+// being listed after the last tier deletes them. Records served by the
+// archive are listed but not retrieved by ID, and aren't counted in metrics.
+type Archive interface {
+	ListEvent(context.Context, driver.ListEventRequest) (driver.ListEventResponse, error)
+	ListAttempt(context.Context, driver.ListAttemptRequest) (driver.ListAttemptResponse, error)
+	// ArchivedBefore returns the times before which the archive holds every
+	// event and every attempt.
+	ArchivedBefore(ctx context.Context) (events, attempts time.Time, err error)
+}
+
+type tieredLogStore struct {
+	tiers   []Tier
+	archive Archive
+	clock   clock.Clock
+}
+
+var (
+	_ driver.LogStore = (*tieredLogStore)(nil)
+	_ driver.Pruner   = (*tieredLogStore)(nil)
+)
+
+type Option func(*tieredLogStore)
+
+// WithClock sets the clock that tier windows are measured against.
+func WithClock(c clock.Clock) Option {
+	return func(s *tieredLogStore) {
+		s.clock = c
+	}
+}
+
+// WithArchive sets the archive following the last tier. The last tier must
+// then set a max age.
+func WithArchive(archive Archive) Option {
+	return func(s *tieredLogStore) {
+		s.archive = archive
+	}
+}
+
+// NewLogStore returns a log store over tiers, ordered from the newest records
+// to the oldest. A single tier is enough when an archive follows it.
+func NewLogStore(tiers []Tier, opts ...Option) (driver.LogStore, error) {
+	s := &tieredLogStore{tiers: tiers, clock: clock.New()}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if len(tiers) == 0 || (len(tiers) < 2 && s.archive == nil) {
+		return nil, fmt.Errorf("%w: at least 2 tiers are required", ErrInvalidTiers)
+	}
+	last := len(tiers) - 1
+	for i, tier := range tiers {
+		if tier.Store == nil {
+			return nil, fmt.Errorf("%w: tier %d has no store", ErrInvalidTiers, i)
+		}
+		// Every tier followed by another, or by the archive, has a window.
+		bounded := i < last || s.archive != nil
+		switch {
+		case !bounded && tier.MaxAge != 0:
+			return nil, fmt.Errorf("%w: the last tier must not set a max age without an archive", ErrInvalidTiers)
+		case bounded && tier.MaxAge <= 0:
+			return nil, fmt.Errorf("%w: tier %d must set a max age", ErrInvalidTiers, i)
+		case bounded && i > 0 && tier.MaxAge <= tiers[i-1].MaxAge:
+			return nil, fmt.Errorf("%w: tier %d max age must exceed tier %d's", ErrInvalidTiers, i, i-1)
+		}
+	}
+	return s, nil
+}
+
+// isArchive reports whether a stitched tier index is the archive's, which
+// follows the tiers.
+func (s *tieredLogStore) isArchive(tier int) bool {
+	return tier == len(s.tiers)
+}
+
+// InsertMany writes entries to every tier, starting with the system of
+// record. Stores upsert by ID, so a batch retried after a partial failure
+// converges.
+func (s *tieredLogStore) InsertMany(ctx context.Context, entries []*models.LogEntry) error {
+	for i := len(s.tiers) - 1; i >= 0; i-- {
+		if err := s.tiers[i].Store.InsertMany(ctx, entries); err != nil {
+			return fmt.Errorf("tier %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (s *tieredLogStore) ListEvent(ctx context.Context, req driver.ListEventRequest) (driver.ListEventResponse, error) {
+	page, err := list(ctx, s, listRequest{
+		resource:   cursorResourceEvent,
+		next:       req.Next,
+		prev:       req.Prev,
+		limit:      req.Limit,
+		sortOrder:  req.SortOrder,
+		timeFilter: req.TimeFilter,
+	}, func(ctx context.Context, tier int, q tierQuery) (tierPage[*models.Event], error) {
+		tierReq := req
+		tierReq.Next, tierReq.Prev = q.next, q.prev
+		tierReq.Limit, tierReq.SortOrder, tierReq.TimeFilter = q.limit, q.sortOrder, q.timeFilter
+		var (
+			resp driver.ListEventResponse
+			err  error
+		)
+		if s.isArchive(tier) {
+			resp, err = s.archive.ListEvent(ctx, tierReq)
+		} else {
+			resp, err = s.tiers[tier].Store.ListEvent(ctx, tierReq)
+		}
+		return tierPage[*models.Event]{data: resp.Data, next: resp.Next, prev: resp.Prev}, err
+	})
+	if err != nil {
+		return driver.ListEventResponse{}, err
+	}
+	return driver.ListEventResponse{Data: page.data, Next: page.next, Prev: page.prev}, nil
+}
+
+func (s *tieredLogStore) ListAttempt(ctx context.Context, req driver.ListAttemptRequest) (driver.ListAttemptResponse, error) {
+	page, err := list(ctx, s, listRequest{
+		resource:   cursorResourceAttempt,
+		next:       req.Next,
+		prev:       req.Prev,
+		limit:      req.Limit,
+		sortOrder:  req.SortOrder,
+		timeFilter: req.TimeFilter,
+	}, func(ctx context.Context, tier int, q tierQuery) (tierPage[*driver.AttemptRecord], error) {
+		tierReq := req
+		tierReq.Next, tierReq.Prev = q.next, q.prev
+		tierReq.Limit, tierReq.SortOrder, tierReq.TimeFilter = q.limit, q.sortOrder, q.timeFilter
+		var (
+			resp driver.ListAttemptResponse
+			err  error
+		)
+		if s.isArchive(tier) {
+			resp, err = s.archive.ListAttempt(ctx, tierReq)
+		} else {
+			resp, err = s.tiers[tier].Store.ListAttempt(ctx, tierReq)
+		}
+		return tierPage[*driver.AttemptRecord]{data: resp.Data, next: resp.Next, prev: resp.Prev}, err
+	})
+	if err != nil {
+		return driver.ListAttemptResponse{}, err
+	}
+	return driver.ListAttemptResponse{Data: page.data, Next: page.next, Prev: page.prev}, nil
+}
+
+// RetrieveEvent returns the event from the newest tier holding it. The
+// archive isn't searched.
+func (s *tieredLogStore) RetrieveEvent(ctx context.Context, req driver.RetrieveEventRequest) (*models.Event, error) {
+	for i, tier := range s.tiers {
+		event, err := tier.Store.RetrieveEvent(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("tier %d: %w", i, err)
+		}
+		if event != nil {
+			return event, nil
+		}
+	}
+	return nil, nil
+}
+
+// RetrieveAttempt returns the attempt from the newest tier holding it. The
+// archive isn't searched.
+func (s *tieredLogStore) RetrieveAttempt(ctx context.Context, req driver.RetrieveAttemptRequest) (*driver.AttemptRecord, error) {
+	for i, tier := range s.tiers {
+		record, err := tier.Store.RetrieveAttempt(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("tier %d: %w", i, err)
+		}
+		if record != nil {
+			return record, nil
+		}
+	}
+	return nil, nil
+}
+
+// QueryEventMetrics aggregates over the system of record, which holds every
+// record in range unless an archive follows it.
+func (s *tieredLogStore) QueryEventMetrics(ctx context.Context, req driver.MetricsRequest) (*driver.EventMetricsResponse, error) {
+	return s.tiers[len(s.tiers)-1].Store.QueryEventMetrics(ctx, req)
+}
+
+// QueryAttemptMetrics aggregates over the system of record, which holds every
+// record in range unless an archive follows it.
+func (s *tieredLogStore) QueryAttemptMetrics(ctx context.Context, req driver.MetricsRequest) (*driver.AttemptMetricsResponse, error) {
+	return s.tiers[len(s.tiers)-1].Store.QueryAttemptMetrics(ctx, req)
+}
+
+// Prune applies req to every tier implementing driver.Pruner and trims each
+// tier but the last to its max age. Held records are trimmed too, since the
+// last tier keeps them. The last tier is only pruned by req, even when an
+// archive follows it, as req only prunes what is archived. The response
+// counts the records deleted from the system of record.
+func (s *tieredLogStore) Prune(ctx context.Context, req driver.PruneRequest) (driver.PruneResponse, error) {
+	now := s.clock.Now()
+	var resp driver.PruneResponse
+	for i, tier := range s.tiers {
+		pruner, ok := tier.Store.(driver.Pruner)
+		if !ok {
+			continue
+		}
+		tierReq := req
+		if i < len(s.tiers)-1 {
+			cutoff := now.Add(-tier.MaxAge)
+			tierReq.SuccessBefore = laterCutoff(req.SuccessBefore, cutoff)
+			tierReq.FailedBefore = laterCutoff(req.FailedBefore, cutoff)
+			tierReq.DeferredBefore = laterCutoff(req.DeferredBefore, cutoff)
+			tierReq.HeldTenantIDs = nil
+			tierReq.HeldEventIDs = nil
+		}
+		tierResp, err := pruner.Prune(ctx, tierReq)
+		if err != nil {
+			return driver.PruneResponse{}, fmt.Errorf("tier %d: %w", i, err)
+		}
+		if i == len(s.tiers)-1 {
+			resp = tierResp
+		}
+	}
+	return resp, nil
+}
+
+func laterCutoff(cutoff *time.Time, t time.Time) *time.Time {
+	if cutoff != nil && cutoff.After(t) {
+		return cutoff
+	}
+	return &t
+}
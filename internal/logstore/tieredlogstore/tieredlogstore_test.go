@@ -0,0 +1,307 @@
+package tieredlogstore
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/logstore/driver"
+	"github.com/hookdeck/outpost/internal/logstore/drivertest"
+	"github.com/hookdeck/outpost/internal/logstore/memlogstore"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type tieredLogStoreHarness struct {
+	logStore driver.LogStore
+	clock    clock.Clock
+}
+
+func (h *tieredLogStoreHarness) MakeDriver(ctx context.Context) (driver.LogStore, error) {
+	return h.logStore, nil
+}
+
+func (h *tieredLogStoreHarness) Clock() clock.Clock {
+	return h.clock
+}
+
+func (h *tieredLogStoreHarness) Close() {}
+
+func (h *tieredLogStoreHarness) FlushWrites(ctx context.Context) error {
+	// In-memory tiers are immediately consistent
+	return nil
+}
+
+func newHarness(ctx context.Context, t *testing.T) (drivertest.Harness, error) {
+	// Tier windows are measured against a fixed clock, so records the suite
+	// places relative to it land in the same tier however long the run takes.
+	fakeClock := clock.NewFake(time.Now().Truncate(time.Second))
+	logStore, err := NewLogStore([]Tier{
+		{Store: memlogstore.NewLogStore(), MaxAge: time.Hour},
+		{Store: memlogstore.NewLogStore(), MaxAge: 24 * time.Hour},
+		{Store: memlogstore.NewLogStore()},
+	}, WithClock(fakeClock))
+	if err != nil {
+		return nil, err
+	}
+	return &tieredLogStoreHarness{logStore: logStore, clock: fakeClock}, nil
+}
+
+func TestTieredLogStoreConformance(t *testing.T) {
+	drivertest.RunConformanceTests(t, newHarness)
+}
+
+func TestNewLogStore(t *testing.T) {
+	mem := memlogstore.NewLogStore()
+	archive := &memArchive{LogStore: mem}
+	tests := []struct {
+		name    string
+		tiers   []Tier
+		opts    []Option
+		wantErr bool
+	}{
+		{name: "two tiers", tiers: []Tier{{Store: mem, MaxAge: time.Hour}, {Store: mem}}},
+		{name: "single tier", tiers: []Tier{{Store: mem}}, wantErr: true},
+		{name: "missing store", tiers: []Tier{{MaxAge: time.Hour}, {Store: mem}}, wantErr: true},
+		{name: "missing max age", tiers: []Tier{{Store: mem}, {Store: mem}}, wantErr: true},
+		{name: "last tier with max age", tiers: []Tier{{Store: mem, MaxAge: time.Hour}, {Store: mem, MaxAge: 2 * time.Hour}}, wantErr: true},
+		{name: "shrinking max age", tiers: []Tier{{Store: mem, MaxAge: 2 * time.Hour}, {Store: mem, MaxAge: time.Hour}, {Store: mem}}, wantErr: true},
+		{name: "single tier with archive", tiers: []Tier{{Store: mem, MaxAge: time.Hour}}, opts: []Option{WithArchive(archive)}},
+		{name: "last tier without max age before archive", tiers: []Tier{{Store: mem}}, opts: []Option{WithArchive(archive)}, wantErr: true},
+		{name: "no tier with archive", opts: []Option{WithArchive(archive)}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewLogStore(tt.tiers, tt.opts...)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrInvalidTiers)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestTieredLogStore_Routing(t *testing.T) {
+	ctx := context.Background()
+	hot, warm := memlogstore.NewLogStore(), memlogstore.NewLogStore()
+	now := time.Now().Truncate(time.Second)
+	fakeClock := clock.NewFake(now)
+	logStore, err := NewLogStore([]Tier{{Store: hot, MaxAge: time.Hour}, {Store: warm}}, WithClock(fakeClock))
+	require.NoError(t, err)
+
+	tenantID := "tenant_tiered"
+	var entries []*models.LogEntry
+	// Four events in the hot window, six older.
+	for i := range 10 {
+		eventTime := now.Add(-time.Duration(i*15+5) * time.Minute)
+		event := testutil.EventFactory.AnyPointer(
+			testutil.EventFactory.WithID(fmt.Sprintf("evt_%d", i)),
+			testutil.EventFactory.WithTenantID(tenantID),
+			testutil.EventFactory.WithTime(eventTime),
+		)
+		attempt := testutil.AttemptFactory.AnyPointer(
+			testutil.AttemptFactory.WithID(fmt.Sprintf("att_%d", i)),
+			testutil.AttemptFactory.WithTenantID(tenantID),
+			testutil.AttemptFactory.WithEventID(event.ID),
+			testutil.AttemptFactory.WithTime(eventTime),
+		)
+		entries = append(entries, &models.LogEntry{Event: event, Attempt: attempt})
+	}
+	require.NoError(t, logStore.InsertMany(ctx, entries))
+
+	t.Run("writes go through to every tier", func(t *testing.T) {
+		for _, tier := range []driver.LogStore{hot, warm} {
+			event, err := tier.RetrieveEvent(ctx, driver.RetrieveEventRequest{EventID: "evt_9"})
+			require.NoError(t, err)
+			assert.NotNil(t, event)
+		}
+	})
+
+	t.Run("pages stitch across tiers", func(t *testing.T) {
+		for _, order := range []string{"desc", "asc"} {
+			t.Run(order, func(t *testing.T) {
+				var pages []driver.ListEventResponse
+				var got []string
+				req := driver.ListEventRequest{TenantIDs: []string{tenantID}, SortOrder: order, Limit: 3}
+				for {
+					resp, err := logStore.ListEvent(ctx, req)
+					require.NoError(t, err)
+					pages = append(pages, resp)
+					for _, e := range resp.Data {
+						got = append(got, e.ID)
+					}
+					if resp.Next == "" {
+						break
+					}
+					req.Next = resp.Next
+				}
+
+				want := make([]string, 10)
+				for i := range want {
+					want[i] = fmt.Sprintf("evt_%d", i)
+					if order == "asc" {
+						want[i] = fmt.Sprintf("evt_%d", 9-i)
+					}
+				}
+				assert.Equal(t, want, got)
+				require.Len(t, pages, 4)
+
+				// Page back from the last page.
+				for i := len(pages) - 1; i > 0; i-- {
+					require.NotEmpty(t, pages[i].Prev)
+					resp, err := logStore.ListEvent(ctx, driver.ListEventRequest{
+						TenantIDs: []string{tenantID}, SortOrder: order, Limit: 3, Prev: pages[i].Prev,
+					})
+					require.NoError(t, err)
+					assert.Equal(t, pages[i-1].Data, resp.Data, "page %d", i-1)
+				}
+			})
+		}
+	})
+
+	t.Run("attempts stitch across tiers", func(t *testing.T) {
+		req := driver.ListAttemptRequest{TenantIDs: []string{tenantID}, Limit: 4}
+		first, err := logStore.ListAttempt(ctx, req)
+		require.NoError(t, err)
+		require.NotEmpty(t, first.Next)
+
+		req.Next = first.Next
+		second, err := logStore.ListAttempt(ctx, req)
+		require.NoError(t, err)
+		require.Len(t, second.Data, 4)
+		assert.Equal(t, "att_4", second.Data[0].Attempt.ID)
+		assert.Equal(t, "att_7", second.Data[3].Attempt.ID)
+	})
+
+	t.Run("cursor from another tier layout is invalid", func(t *testing.T) {
+		other, err := NewLogStore([]Tier{
+			{Store: hot, MaxAge: time.Hour},
+			{Store: warm, MaxAge: 2 * time.Hour},
+			{Store: warm},
+		}, WithClock(fakeClock))
+		require.NoError(t, err)
+		resp, err := other.ListEvent(ctx, driver.ListEventRequest{TenantIDs: []string{tenantID}, Limit: 3})
+		require.NoError(t, err)
+		require.NotEmpty(t, resp.Next)
+
+		_, err = logStore.ListEvent(ctx, driver.ListEventRequest{TenantIDs: []string{tenantID}, Limit: 3, Next: resp.Next})
+		assert.Error(t, err)
+	})
+
+	t.Run("prune trims tiers to their max age", func(t *testing.T) {
+		resp, err := logStore.(driver.Pruner).Prune(ctx, driver.PruneRequest{})
+		require.NoError(t, err)
+		assert.Equal(t, driver.PruneResponse{}, resp)
+
+		hotResp, err := hot.ListAttempt(ctx, driver.ListAttemptRequest{TenantIDs: []string{tenantID}, Limit: 100})
+		require.NoError(t, err)
+		assert.Len(t, hotResp.Data, 4)
+
+		all, err := logStore.ListAttempt(ctx, driver.ListAttemptRequest{TenantIDs: []string{tenantID}, Limit: 100})
+		require.NoError(t, err)
+		assert.Len(t, all.Data, 10)
+	})
+}
+
+// memArchive is an archive over an in-memory log store, holding every record
+// before archivedBefore.
+type memArchive struct {
+	driver.LogStore
+	archivedBefore time.Time
+}
+
+func (a *memArchive) ArchivedBefore(ctx context.Context) (time.Time, time.Time, error) {
+	return a.archivedBefore, a.archivedBefore, nil
+}
+
+func TestTieredLogStore_Archive(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().Truncate(time.Second)
+	fakeClock := clock.NewFake(now)
+	last := memlogstore.NewLogStore()
+	archive := &memArchive{LogStore: memlogstore.NewLogStore(), archivedBefore: now.Add(-2 * time.Hour)}
+	logStore, err := NewLogStore([]Tier{{Store: last, MaxAge: time.Hour}}, WithClock(fakeClock), WithArchive(archive))
+	require.NoError(t, err)
+
+	tenantID := "tenant_archived"
+	var entries []*models.LogEntry
+	// Eight events up to two hours old, then two archived ones.
+	for i := range 10 {
+		eventTime := now.Add(-time.Duration(i*15+5) * time.Minute)
+		event := testutil.EventFactory.AnyPointer(
+			testutil.EventFactory.WithID(fmt.Sprintf("evt_%d", i)),
+			testutil.EventFactory.WithTenantID(tenantID),
+			testutil.EventFactory.WithTime(eventTime),
+		)
+		attempt := testutil.AttemptFactory.AnyPointer(
+			testutil.AttemptFactory.WithID(fmt.Sprintf("att_%d", i)),
+			testutil.AttemptFactory.WithTenantID(tenantID),
+			testutil.AttemptFactory.WithEventID(event.ID),
+			testutil.AttemptFactory.WithTime(eventTime),
+		)
+		entries = append(entries, &models.LogEntry{Event: event, Attempt: attempt})
+	}
+	require.NoError(t, logStore.InsertMany(ctx, entries))
+	require.NoError(t, archive.InsertMany(ctx, entries))
+
+	// Prune the archived records from the last tier, as retention would.
+	archived := now.Add(-2 * time.Hour)
+	_, err = logStore.(driver.Pruner).Prune(ctx, driver.PruneRequest{SuccessBefore: &archived, FailedBefore: &archived})
+	require.NoError(t, err)
+
+	t.Run("the last tier is only pruned by the request", func(t *testing.T) {
+		resp, err := last.ListAttempt(ctx, driver.ListAttemptRequest{TenantIDs: []string{tenantID}, Limit: 100})
+		require.NoError(t, err)
+		assert.Len(t, resp.Data, 8)
+	})
+
+	t.Run("pages stitch the archive after the last tier", func(t *testing.T) {
+		for _, order := range []string{"desc", "asc"} {
+			t.Run(order, func(t *testing.T) {
+				var got []string
+				req := driver.ListEventRequest{TenantIDs: []string{tenantID}, SortOrder: order, Limit: 3}
+				for {
+					resp, err := logStore.ListEvent(ctx, req)
+					require.NoError(t, err)
+					for _, e := range resp.Data {
+						got = append(got, e.ID)
+					}
+					if resp.Next == "" {
+						break
+					}
+					req.Next = resp.Next
+				}
+
+				want := make([]string, 10)
+				for i := range want {
+					want[i] = fmt.Sprintf("evt_%d", i)
+					if order == "asc" {
+						want[i] = fmt.Sprintf("evt_%d", 9-i)
+					}
+				}
+				assert.Equal(t, want, got)
+			})
+		}
+	})
+
+	t.Run("the last tier serves what isn't archived yet", func(t *testing.T) {
+		// Nothing archived: the last tier serves past its max age.
+		notArchived := &memArchive{LogStore: memlogstore.NewLogStore()}
+		logStore, err := NewLogStore([]Tier{{Store: last, MaxAge: time.Hour}}, WithClock(fakeClock), WithArchive(notArchived))
+		require.NoError(t, err)
+		resp, err := logStore.ListAttempt(ctx, driver.ListAttemptRequest{TenantIDs: []string{tenantID}, Limit: 100})
+		require.NoError(t, err)
+		assert.Len(t, resp.Data, 8)
+	})
+
+	t.Run("the archive isn't searched by ID", func(t *testing.T) {
+		event, err := logStore.RetrieveEvent(ctx, driver.RetrieveEventRequest{EventID: "evt_9"})
+		require.NoError(t, err)
+		assert.Nil(t, event)
+	})
+}
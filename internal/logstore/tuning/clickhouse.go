@@ -0,0 +1,114 @@
+package tuning
+
+import "fmt"
+
+// clickHouseIndexes mirror the index definitions of the ClickHouse migrations.
+var clickHouseIndexes = map[string][]indexDDL{
+	IndexTopic: {
+		{"events", "idx_topic", "topic TYPE bloom_filter GRANULARITY 1"},
+		{"attempts", "idx_topic", "topic TYPE bloom_filter GRANULARITY 1"},
+	},
+	IndexStatus: {
+		{"attempts", "idx_status", "status TYPE set(100) GRANULARITY 1"},
+	},
+	IndexMetadata: {
+		{"events", "idx_metadata", "metadata TYPE tokenbf_v1(32768, 3, 0) GRANULARITY 1"},
+		{"attempts", "idx_metadata", "metadata TYPE tokenbf_v1(32768, 3, 0) GRANULARITY 1"},
+	},
+}
+
+var clickHousePayloadColumns = []payloadColumns{
+	{"events", []string{"data", "metadata"}},
+	{"attempts", []string{"data", "metadata", "response_data"}},
+}
+
+// ClickHousePartitionBy returns the PARTITION BY expression of a table
+// partitioned on timeColumn.
+func (c Config) ClickHousePartitionBy(timeColumn string) string {
+	switch c.PartitionInterval {
+	case PartitionDay:
+		return fmt.Sprintf("toDate(%s)", timeColumn)
+	case PartitionWeek:
+		return fmt.Sprintf("toMonday(%s)", timeColumn)
+	default:
+		return fmt.Sprintf("toYYYYMM(%s)", timeColumn)
+	}
+}
+
+// ClickHouseTableSettings returns the SETTINGS clause of new tables, or "".
+func (c Config) ClickHouseTableSettings() string {
+	if c.IndexGranularity == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" SETTINGS index_granularity = %d", c.IndexGranularity)
+}
+
+// clickHouseTables are the log tables with their partitioning column and
+// sorting key, as created by the migrations.
+var clickHouseTables = []struct {
+	name       string
+	timeColumn string
+	orderBy    string
+}{
+	{"events", "event_time", "(event_time, event_id)"},
+	{"attempts", "attempt_time", "(attempt_time, attempt_id)"},
+}
+
+// ClickHouseRebuildStatements returns the statements recreating the log
+// tables with the configured partition interval and index granularity, which
+// ClickHouse fixes when a table is created. Each table is copied into a new
+// one and swapped in place, so the cost grows with the stored rows: run it
+// right after the first `outpost migrate apply`, or during a maintenance
+// window, since rows written during the copy are lost. The swap requires an
+// Atomic database, the ClickHouse default.
+func ClickHouseRebuildStatements(c Config, deploymentID string) []string {
+	prefix := ""
+	if deploymentID != "" {
+		prefix = deploymentID + "_"
+	}
+
+	var stmts []string
+	for _, table := range clickHouseTables {
+		name := prefix + table.name
+		rebuild := name + "_rebuild"
+		stmts = append(stmts,
+			fmt.Sprintf("DROP TABLE IF EXISTS %s", rebuild),
+			fmt.Sprintf("CREATE TABLE %s AS %s ENGINE = ReplacingMergeTree PARTITION BY %s ORDER BY %s%s",
+				rebuild, name, c.ClickHousePartitionBy(table.timeColumn), table.orderBy, c.ClickHouseTableSettings()),
+			fmt.Sprintf("INSERT INTO %s SELECT * FROM %s", rebuild, name),
+			fmt.Sprintf("EXCHANGE TABLES %s AND %s", name, rebuild),
+			fmt.Sprintf("DROP TABLE %s", rebuild),
+		)
+	}
+	return stmts
+}
+
+// ClickHouseStatements returns the statements reconciling the optional
+// indexes and payload compression of existing tables with the config. Added
+// indexes cover newly written parts; run MATERIALIZE INDEX to backfill them.
+// Codecs apply to new parts and to existing parts as they merge.
+func ClickHouseStatements(c Config, deploymentID string) []string {
+	prefix := ""
+	if deploymentID != "" {
+		prefix = deploymentID + "_"
+	}
+
+	var stmts []string
+	for _, index := range Indexes {
+		for _, ddl := range clickHouseIndexes[index] {
+			if c.wantsIndex(index) {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s%s ADD INDEX IF NOT EXISTS %s %s", prefix, ddl.table, ddl.name, ddl.definition))
+			} else {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s%s DROP INDEX IF EXISTS %s", prefix, ddl.table, ddl.name))
+			}
+		}
+	}
+	if c.CompressionCodec != "" {
+		for _, payload := range clickHousePayloadColumns {
+			for _, column := range payload.columns {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s%s MODIFY COLUMN %s String CODEC(%s)", prefix, payload.table, column, c.CompressionCodec))
+			}
+		}
+	}
+	return stmts
+}
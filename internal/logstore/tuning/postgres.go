@@ -0,0 +1,97 @@
+package tuning
+
+import (
+	"fmt"
+	"time"
+)
+
+// postgresPartitionsAhead is the number of upcoming partitions created per
+// run. The current period is never created: its rows may already sit in the
+// default partition.
+const postgresPartitionsAhead = 3
+
+// postgresIndexes mirror the index definitions of the PostgreSQL migrations.
+var postgresIndexes = map[string][]indexDDL{
+	IndexTopic: {
+		{"events", "idx_events_tenant_topic_time", "ON events (tenant_id, topic, time DESC, id DESC)"},
+		{"attempts", "idx_attempts_tenant_topic_time", "ON attempts (tenant_id, topic, time DESC, id DESC)"},
+	},
+	IndexStatus: {
+		{"attempts", "idx_attempts_tenant_status_time", "ON attempts (tenant_id, status, time DESC, id DESC)"},
+	},
+	IndexMetadata: {
+		{"events", "idx_events_metadata", "ON events USING GIN (metadata)"},
+		{"attempts", "idx_attempts_event_metadata", "ON attempts USING GIN (event_metadata)"},
+	},
+}
+
+var postgresPayloadColumns = []payloadColumns{
+	{"events", []string{"data", "metadata"}},
+	{"attempts", []string{"event_data", "event_metadata", "response_data"}},
+}
+
+// PostgresStatements returns the statements reconciling the optional
+// indexes, payload compression and upcoming partitions with the config at
+// now. Compression applies to newly written values. Each partition is
+// created while the default partition is scanned for rows in its range, so
+// run this off-peak on large tables.
+func PostgresStatements(c Config, now time.Time) []string {
+	var stmts []string
+	for _, index := range Indexes {
+		for _, ddl := range postgresIndexes[index] {
+			if c.wantsIndex(index) {
+				stmts = append(stmts, fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s %s", ddl.name, ddl.definition))
+			} else {
+				stmts = append(stmts, fmt.Sprintf("DROP INDEX IF EXISTS %s", ddl.name))
+			}
+		}
+	}
+	if c.CompressionCodec != "" {
+		for _, payload := range postgresPayloadColumns {
+			for _, column := range payload.columns {
+				stmts = append(stmts, fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET COMPRESSION %s", payload.table, column, c.CompressionCodec))
+			}
+		}
+	}
+	if c.PartitionInterval != "" {
+		start := c.periodStart(now.UTC())
+		for i := 0; i < postgresPartitionsAhead; i++ {
+			start = c.nextPeriod(start)
+			end := c.nextPeriod(start)
+			for _, table := range []string{"events", "attempts"} {
+				stmts = append(stmts, fmt.Sprintf(
+					"CREATE TABLE IF NOT EXISTS %s_p%s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')",
+					table, start.Format("20060102"), table,
+					start.Format(time.RFC3339), end.Format(time.RFC3339),
+				))
+			}
+		}
+	}
+	return stmts
+}
+
+// periodStart truncates t to the start of its partition period.
+func (c Config) periodStart(t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch c.PartitionInterval {
+	case PartitionDay:
+		return day
+	case PartitionWeek:
+		// Weeks start on Monday.
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	default:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	}
+}
+
+// nextPeriod returns the start of the period after the one starting at start.
+func (c Config) nextPeriod(start time.Time) time.Time {
+	switch c.PartitionInterval {
+	case PartitionDay:
+		return start.AddDate(0, 0, 1)
+	case PartitionWeek:
+		return start.AddDate(0, 0, 7)
+	default:
+		return start.AddDate(0, 1, 0)
+	}
+}
@@ -0,0 +1,123 @@
+// Package tuning holds the operator-tunable parts of the log store schema —
+// partitioning, optional secondary indexes and payload compression — and
+// builds the DDL that applies them.
+//
+// Partition interval and ClickHouse index granularity are fixed when a table
+// is created, so ClickHouse applies them by rebuilding the log tables, which
+// is meant for new installations. Indexes, compression and PostgreSQL
+// partitions are reconciled on existing tables by idempotent statements.
+package tuning
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+)
+
+// Partition intervals.
+const (
+	PartitionDay   = "day"
+	PartitionWeek  = "week"
+	PartitionMonth = "month"
+)
+
+// Optional secondary indexes.
+const (
+	IndexTopic    = "topic"
+	IndexStatus   = "status"
+	IndexMetadata = "metadata"
+)
+
+// Drivers.
+const (
+	DriverClickHouse = "clickhouse"
+	DriverPostgres   = "postgres"
+)
+
+var (
+	// Indexes lists the optional secondary indexes.
+	Indexes = []string{IndexTopic, IndexStatus, IndexMetadata}
+
+	// DefaultIndexes are the optional indexes the base schema creates.
+	DefaultIndexes = []string{IndexTopic, IndexStatus}
+)
+
+var (
+	ErrInvalidPartitionInterval = errors.New("invalid partition interval")
+	ErrInvalidIndexGranularity  = errors.New("invalid index granularity")
+	ErrUnknownIndex             = errors.New("unknown index")
+	ErrInvalidCompressionCodec  = errors.New("invalid compression codec")
+)
+
+// Compression codecs are interpolated into DDL, so they are matched strictly.
+var (
+	clickHouseCodecRegex = regexp.MustCompile(`^(NONE|LZ4|LZ4HC(\([0-9]{1,2}\))?|ZSTD(\([0-9]{1,2}\))?)$`)
+	postgresCodecRegex   = regexp.MustCompile(`^(pglz|lz4)$`)
+)
+
+// Config is the schema tuning of a log store.
+type Config struct {
+	// PartitionInterval is the time range of a partition. Empty keeps the
+	// driver default: monthly on ClickHouse, the single default partition on
+	// PostgreSQL.
+	PartitionInterval string
+	// IndexGranularity is the ClickHouse index_granularity of rebuilt tables.
+	// 0 keeps the server default.
+	IndexGranularity int
+	// Indexes lists the optional secondary indexes to keep; the others are
+	// dropped. Nil means DefaultIndexes.
+	Indexes []string
+	// CompressionCodec is applied to event and response payload columns.
+	// Empty leaves the columns as they are.
+	CompressionCodec string
+}
+
+// Validate checks the config against what driver supports.
+func (c Config) Validate(driver string) error {
+	switch c.PartitionInterval {
+	case "", PartitionDay, PartitionWeek, PartitionMonth:
+	default:
+		return fmt.Errorf("%w %q: must be one of day, week, month", ErrInvalidPartitionInterval, c.PartitionInterval)
+	}
+	if c.IndexGranularity < 0 {
+		return fmt.Errorf("%w: must not be negative", ErrInvalidIndexGranularity)
+	}
+	for _, index := range c.Indexes {
+		if !slices.Contains(Indexes, index) {
+			return fmt.Errorf("%w %q", ErrUnknownIndex, index)
+		}
+	}
+	if c.CompressionCodec != "" {
+		codecRegex := postgresCodecRegex
+		if driver == DriverClickHouse {
+			codecRegex = clickHouseCodecRegex
+		}
+		if !codecRegex.MatchString(c.CompressionCodec) {
+			return fmt.Errorf("%w %q for %s", ErrInvalidCompressionCodec, c.CompressionCodec, driver)
+		}
+	}
+	return nil
+}
+
+// wantsIndex reports whether the optional index should exist.
+func (c Config) wantsIndex(index string) bool {
+	if c.Indexes == nil {
+		return slices.Contains(DefaultIndexes, index)
+	}
+	return slices.Contains(c.Indexes, index)
+}
+
+// indexDDL is an optional index on one table: its name and the definition
+// after the name.
+type indexDDL struct {
+	table      string
+	name       string
+	definition string
+}
+
+// payloadColumns are the large columns compression applies to, by table.
+type payloadColumns struct {
+	table   string
+	columns []string
+}
@@ -0,0 +1,151 @@
+package tuning
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  Config
+		driver  string
+		wantErr error
+	}{
+		{name: "zero config", config: Config{}, driver: DriverPostgres},
+		{name: "weekly partitions", config: Config{PartitionInterval: PartitionWeek}, driver: DriverClickHouse},
+		{name: "unknown interval", config: Config{PartitionInterval: "hour"}, driver: DriverClickHouse, wantErr: ErrInvalidPartitionInterval},
+		{name: "negative granularity", config: Config{IndexGranularity: -1}, driver: DriverClickHouse, wantErr: ErrInvalidIndexGranularity},
+		{name: "unknown index", config: Config{Indexes: []string{"tenant"}}, driver: DriverPostgres, wantErr: ErrUnknownIndex},
+		{name: "clickhouse codec", config: Config{CompressionCodec: "ZSTD(3)"}, driver: DriverClickHouse},
+		{name: "postgres codec", config: Config{CompressionCodec: "lz4"}, driver: DriverPostgres},
+		{name: "clickhouse codec on postgres", config: Config{CompressionCodec: "ZSTD(3)"}, driver: DriverPostgres, wantErr: ErrInvalidCompressionCodec},
+		{name: "codec injection", config: Config{CompressionCodec: "LZ4); DROP TABLE events; --"}, driver: DriverClickHouse, wantErr: ErrInvalidCompressionCodec},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate(tt.driver)
+			if tt.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+			assert.True(t, errors.Is(err, tt.wantErr), "got %v", err)
+		})
+	}
+}
+
+func TestClickHouseTableDefinition(t *testing.T) {
+	assert.Equal(t, "toYYYYMM(event_time)", Config{}.ClickHousePartitionBy("event_time"))
+	assert.Equal(t, "toDate(attempt_time)", Config{PartitionInterval: PartitionDay}.ClickHousePartitionBy("attempt_time"))
+	assert.Equal(t, "toMonday(event_time)", Config{PartitionInterval: PartitionWeek}.ClickHousePartitionBy("event_time"))
+	assert.Equal(t, "", Config{}.ClickHouseTableSettings())
+	assert.Equal(t, " SETTINGS index_granularity = 1024", Config{IndexGranularity: 1024}.ClickHouseTableSettings())
+}
+
+func TestClickHouseRebuildStatements(t *testing.T) {
+	stmts := ClickHouseRebuildStatements(Config{PartitionInterval: PartitionDay, IndexGranularity: 1024}, "dpm_001")
+	assert.Equal(t, []string{
+		"DROP TABLE IF EXISTS dpm_001_events_rebuild",
+		"CREATE TABLE dpm_001_events_rebuild AS dpm_001_events ENGINE = ReplacingMergeTree PARTITION BY toDate(event_time) ORDER BY (event_time, event_id) SETTINGS index_granularity = 1024",
+		"INSERT INTO dpm_001_events_rebuild SELECT * FROM dpm_001_events",
+		"EXCHANGE TABLES dpm_001_events AND dpm_001_events_rebuild",
+		"DROP TABLE dpm_001_events_rebuild",
+		"DROP TABLE IF EXISTS dpm_001_attempts_rebuild",
+		"CREATE TABLE dpm_001_attempts_rebuild AS dpm_001_attempts ENGINE = ReplacingMergeTree PARTITION BY toDate(attempt_time) ORDER BY (attempt_time, attempt_id) SETTINGS index_granularity = 1024",
+		"INSERT INTO dpm_001_attempts_rebuild SELECT * FROM dpm_001_attempts",
+		"EXCHANGE TABLES dpm_001_attempts AND dpm_001_attempts_rebuild",
+		"DROP TABLE dpm_001_attempts_rebuild",
+	}, stmts)
+}
+
+func TestClickHouseStatements(t *testing.T) {
+	t.Run("defaults keep the base schema indexes", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"ALTER TABLE events ADD INDEX IF NOT EXISTS idx_topic topic TYPE bloom_filter GRANULARITY 1",
+			"ALTER TABLE attempts ADD INDEX IF NOT EXISTS idx_topic topic TYPE bloom_filter GRANULARITY 1",
+			"ALTER TABLE attempts ADD INDEX IF NOT EXISTS idx_status status TYPE set(100) GRANULARITY 1",
+			"ALTER TABLE events DROP INDEX IF EXISTS idx_metadata",
+			"ALTER TABLE attempts DROP INDEX IF EXISTS idx_metadata",
+		}, ClickHouseStatements(Config{}, ""))
+	})
+
+	t.Run("indexes and codec with deployment prefix", func(t *testing.T) {
+		stmts := ClickHouseStatements(Config{
+			Indexes:          []string{IndexMetadata},
+			CompressionCodec: "ZSTD(3)",
+		}, "dpm_001")
+		assert.Equal(t, []string{
+			"ALTER TABLE dpm_001_events DROP INDEX IF EXISTS idx_topic",
+			"ALTER TABLE dpm_001_attempts DROP INDEX IF EXISTS idx_topic",
+			"ALTER TABLE dpm_001_attempts DROP INDEX IF EXISTS idx_status",
+			"ALTER TABLE dpm_001_events ADD INDEX IF NOT EXISTS idx_metadata metadata TYPE tokenbf_v1(32768, 3, 0) GRANULARITY 1",
+			"ALTER TABLE dpm_001_attempts ADD INDEX IF NOT EXISTS idx_metadata metadata TYPE tokenbf_v1(32768, 3, 0) GRANULARITY 1",
+			"ALTER TABLE dpm_001_events MODIFY COLUMN data String CODEC(ZSTD(3))",
+			"ALTER TABLE dpm_001_events MODIFY COLUMN metadata String CODEC(ZSTD(3))",
+			"ALTER TABLE dpm_001_attempts MODIFY COLUMN data String CODEC(ZSTD(3))",
+			"ALTER TABLE dpm_001_attempts MODIFY COLUMN metadata String CODEC(ZSTD(3))",
+			"ALTER TABLE dpm_001_attempts MODIFY COLUMN response_data String CODEC(ZSTD(3))",
+		}, stmts)
+	})
+}
+
+func TestPostgresStatements(t *testing.T) {
+	// Wednesday
+	now := time.Date(2026, 10, 14, 15, 4, 5, 0, time.UTC)
+
+	t.Run("defaults create no partitions", func(t *testing.T) {
+		assert.Equal(t, []string{
+			"CREATE INDEX IF NOT EXISTS idx_events_tenant_topic_time ON events (tenant_id, topic, time DESC, id DESC)",
+			"CREATE INDEX IF NOT EXISTS idx_attempts_tenant_topic_time ON attempts (tenant_id, topic, time DESC, id DESC)",
+			"CREATE INDEX IF NOT EXISTS idx_attempts_tenant_status_time ON attempts (tenant_id, status, time DESC, id DESC)",
+			"DROP INDEX IF EXISTS idx_events_metadata",
+			"DROP INDEX IF EXISTS idx_attempts_event_metadata",
+		}, PostgresStatements(Config{}, now))
+	})
+
+	t.Run("compression", func(t *testing.T) {
+		stmts := PostgresStatements(Config{CompressionCodec: "lz4"}, now)
+		assert.Contains(t, stmts, "ALTER TABLE attempts ALTER COLUMN response_data SET COMPRESSION lz4")
+	})
+
+	tests := []struct {
+		interval string
+		want     []string
+	}{
+		{PartitionMonth, []string{
+			"CREATE TABLE IF NOT EXISTS events_p20261101 PARTITION OF events FOR VALUES FROM ('2026-11-01T00:00:00Z') TO ('2026-12-01T00:00:00Z')",
+			"CREATE TABLE IF NOT EXISTS attempts_p20261101 PARTITION OF attempts FOR VALUES FROM ('2026-11-01T00:00:00Z') TO ('2026-12-01T00:00:00Z')",
+			"CREATE TABLE IF NOT EXISTS events_p20261201 PARTITION OF events FOR VALUES FROM ('2026-12-01T00:00:00Z') TO ('2027-01-01T00:00:00Z')",
+			"CREATE TABLE IF NOT EXISTS attempts_p20261201 PARTITION OF attempts FOR VALUES FROM ('2026-12-01T00:00:00Z') TO ('2027-01-01T00:00:00Z')",
+			"CREATE TABLE IF NOT EXISTS events_p20270101 PARTITION OF events FOR VALUES FROM ('2027-01-01T00:00:00Z') TO ('2027-02-01T00:00:00Z')",
+			"CREATE TABLE IF NOT EXISTS attempts_p20270101 PARTITION OF attempts FOR VALUES FROM ('2027-01-01T00:00:00Z') TO ('2027-02-01T00:00:00Z')",
+		}},
+		{PartitionWeek, []string{
+			"CREATE TABLE IF NOT EXISTS events_p20261019 PARTITION OF events FOR VALUES FROM ('2026-10-19T00:00:00Z') TO ('2026-10-26T00:00:00Z')",
+			"CREATE TABLE IF NOT EXISTS attempts_p20261019 PARTITION OF attempts FOR VALUES FROM ('2026-10-19T00:00:00Z') TO ('2026-10-26T00:00:00Z')",
+			"CREATE TABLE IF NOT EXISTS events_p20261026 PARTITION OF events FOR VALUES FROM ('2026-10-26T00:00:00Z') TO ('2026-11-02T00:00:00Z')",
+			"CREATE TABLE IF NOT EXISTS attempts_p20261026 PARTITION OF attempts FOR VALUES FROM ('2026-10-26T00:00:00Z') TO ('2026-11-02T00:00:00Z')",
+			"CREATE TABLE IF NOT EXISTS events_p20261102 PARTITION OF events FOR VALUES FROM ('2026-11-02T00:00:00Z') TO ('2026-11-09T00:00:00Z')",
+			"CREATE TABLE IF NOT EXISTS attempts_p20261102 PARTITION OF attempts FOR VALUES FROM ('2026-11-02T00:00:00Z') TO ('2026-11-09T00:00:00Z')",
+		}},
+		{PartitionDay, []string{
+			"CREATE TABLE IF NOT EXISTS events_p20261015 PARTITION OF events FOR VALUES FROM ('2026-10-15T00:00:00Z') TO ('2026-10-16T00:00:00Z')",
+			"CREATE TABLE IF NOT EXISTS attempts_p20261015 PARTITION OF attempts FOR VALUES FROM ('2026-10-15T00:00:00Z') TO ('2026-10-16T00:00:00Z')",
+			"CREATE TABLE IF NOT EXISTS events_p20261016 PARTITION OF events FOR VALUES FROM ('2026-10-16T00:00:00Z') TO ('2026-10-17T00:00:00Z')",
+			"CREATE TABLE IF NOT EXISTS attempts_p20261016 PARTITION OF attempts FOR VALUES FROM ('2026-10-16T00:00:00Z') TO ('2026-10-17T00:00:00Z')",
+			"CREATE TABLE IF NOT EXISTS events_p20261017 PARTITION OF events FOR VALUES FROM ('2026-10-17T00:00:00Z') TO ('2026-10-18T00:00:00Z')",
+			"CREATE TABLE IF NOT EXISTS attempts_p20261017 PARTITION OF attempts FOR VALUES FROM ('2026-10-17T00:00:00Z') TO ('2026-10-18T00:00:00Z')",
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.interval+" partitions", func(t *testing.T) {
+			stmts := PostgresStatements(Config{PartitionInterval: tt.interval}, now)
+			assert.Equal(t, tt.want, stmts[len(stmts)-len(tt.want):])
+		})
+	}
+}
@@ -0,0 +1 @@
+ALTER TABLE {deployment_prefix}attempts DROP COLUMN IF EXISTS destination_snapshot;
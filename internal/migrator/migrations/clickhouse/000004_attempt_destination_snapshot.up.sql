@@ -0,0 +1 @@
+ALTER TABLE {deployment_prefix}attempts ADD COLUMN destination_snapshot String DEFAULT '';
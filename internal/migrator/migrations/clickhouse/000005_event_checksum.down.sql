@@ -0,0 +1,2 @@
+ALTER TABLE {deployment_prefix}attempts DROP COLUMN IF EXISTS event_checksum;
+ALTER TABLE {deployment_prefix}events DROP COLUMN IF EXISTS checksum;
@@ -0,0 +1,2 @@
+ALTER TABLE {deployment_prefix}events ADD COLUMN checksum String DEFAULT '';
+ALTER TABLE {deployment_prefix}attempts ADD COLUMN event_checksum String DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE {deployment_prefix}attempts DROP INDEX IF EXISTS idx_event_source;
+ALTER TABLE {deployment_prefix}attempts DROP COLUMN IF EXISTS event_source;
+ALTER TABLE {deployment_prefix}events DROP INDEX IF EXISTS idx_source;
+ALTER TABLE {deployment_prefix}events DROP COLUMN IF EXISTS source;
@@ -0,0 +1,4 @@
+ALTER TABLE {deployment_prefix}events ADD COLUMN source String DEFAULT '';
+ALTER TABLE {deployment_prefix}events ADD INDEX idx_source source TYPE bloom_filter GRANULARITY 1;
+ALTER TABLE {deployment_prefix}attempts ADD COLUMN event_source String DEFAULT '';
+ALTER TABLE {deployment_prefix}attempts ADD INDEX idx_event_source event_source TYPE bloom_filter GRANULARITY 1;
@@ -0,0 +1,2 @@
+ALTER TABLE {deployment_prefix}attempts DROP COLUMN IF EXISTS error_message;
+ALTER TABLE {deployment_prefix}attempts DROP COLUMN IF EXISTS error_code;
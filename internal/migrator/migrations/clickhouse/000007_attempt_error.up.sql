@@ -0,0 +1,2 @@
+ALTER TABLE {deployment_prefix}attempts ADD COLUMN error_code String DEFAULT '';
+ALTER TABLE {deployment_prefix}attempts ADD COLUMN error_message String DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE {deployment_prefix}events DROP INDEX IF EXISTS idx_group_id;
+ALTER TABLE {deployment_prefix}events DROP COLUMN IF EXISTS group_id;
@@ -0,0 +1,2 @@
+ALTER TABLE {deployment_prefix}events ADD COLUMN group_id String DEFAULT '';
+ALTER TABLE {deployment_prefix}events ADD INDEX idx_group_id group_id TYPE bloom_filter GRANULARITY 1;
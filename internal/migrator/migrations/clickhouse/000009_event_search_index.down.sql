@@ -0,0 +1 @@
+ALTER TABLE {deployment_prefix}events DROP INDEX IF EXISTS idx_data_tokens;
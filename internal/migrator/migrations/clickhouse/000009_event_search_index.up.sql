@@ -0,0 +1,4 @@
+-- Token index for full-text search over event data, on the lowercase data
+-- searches match. Parts written before the index are searched unindexed
+-- until they are merged.
+ALTER TABLE {deployment_prefix}events ADD INDEX idx_data_tokens lower(data) TYPE tokenbf_v1(32768, 3, 0) GRANULARITY 1;
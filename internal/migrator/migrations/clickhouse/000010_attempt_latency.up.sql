@@ -0,0 +1 @@
+ALTER TABLE {deployment_prefix}attempts ADD COLUMN latency_ms Nullable(Int64);
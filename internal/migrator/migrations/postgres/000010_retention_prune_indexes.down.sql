@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS idx_events_time;
+DROP INDEX IF EXISTS idx_attempts_status_time;
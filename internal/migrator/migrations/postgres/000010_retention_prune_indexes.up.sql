@@ -0,0 +1,10 @@
+-- Support status-based retention pruning, which scans by (status, time)
+-- across tenants.
+--
+-- The tables are partitioned and Postgres cannot build an index
+-- concurrently on a partitioned parent, so the parent indexes are created
+-- ON ONLY here (a catalog-only change that does not scan or lock writes).
+-- The default partitions are indexed concurrently and attached in
+-- 000012-000014. Partitions created afterwards inherit the index.
+CREATE INDEX IF NOT EXISTS idx_attempts_status_time ON ONLY attempts (status, time);
+CREATE INDEX IF NOT EXISTS idx_events_time ON ONLY events (time);
@@ -0,0 +1 @@
+ALTER TABLE attempts ADD COLUMN destination_snapshot text NOT NULL DEFAULT '';
@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS attempts_default_status_time_idx;
@@ -0,0 +1,3 @@
+-- Must stay the only statement in this file: CREATE INDEX CONCURRENTLY
+-- cannot run inside a transaction block.
+CREATE INDEX CONCURRENTLY IF NOT EXISTS events_default_time_idx ON events_default (time);
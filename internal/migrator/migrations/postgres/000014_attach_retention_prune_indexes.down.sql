@@ -0,0 +1,4 @@
+-- Attached partition indexes cannot be detached; dropping the parents drops
+-- them too. 000010 recreates the parents ON ONLY when migrating up again.
+DROP INDEX IF EXISTS idx_attempts_status_time;
+DROP INDEX IF EXISTS idx_events_time;
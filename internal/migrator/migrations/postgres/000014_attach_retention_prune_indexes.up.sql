@@ -0,0 +1,4 @@
+-- Attaching marks the parent indexes from 000010 valid once every partition
+-- has a matching index.
+ALTER INDEX idx_attempts_status_time ATTACH PARTITION attempts_default_status_time_idx;
+ALTER INDEX idx_events_time ATTACH PARTITION events_default_time_idx;
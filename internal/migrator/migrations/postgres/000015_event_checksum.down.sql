@@ -0,0 +1,2 @@
+ALTER TABLE attempts DROP COLUMN IF EXISTS event_checksum;
+ALTER TABLE events DROP COLUMN IF EXISTS checksum;
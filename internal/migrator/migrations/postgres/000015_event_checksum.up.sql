@@ -0,0 +1,2 @@
+ALTER TABLE events ADD COLUMN checksum text NOT NULL DEFAULT '';
+ALTER TABLE attempts ADD COLUMN event_checksum text NOT NULL DEFAULT '';
@@ -0,0 +1,2 @@
+ALTER TABLE attempts DROP COLUMN IF EXISTS event_source;
+ALTER TABLE events DROP COLUMN IF EXISTS source;
@@ -0,0 +1,4 @@
+-- Not indexed: source filters narrow the existing tenant and time index
+-- scans, like the other optional log filters.
+ALTER TABLE events ADD COLUMN source text NOT NULL DEFAULT '';
+ALTER TABLE attempts ADD COLUMN event_source text NOT NULL DEFAULT '';
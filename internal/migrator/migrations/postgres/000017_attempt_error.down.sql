@@ -0,0 +1,2 @@
+ALTER TABLE attempts DROP COLUMN IF EXISTS error_message;
+ALTER TABLE attempts DROP COLUMN IF EXISTS error_code;
@@ -0,0 +1,2 @@
+ALTER TABLE attempts ADD COLUMN error_code text NOT NULL DEFAULT '';
+ALTER TABLE attempts ADD COLUMN error_message text NOT NULL DEFAULT '';
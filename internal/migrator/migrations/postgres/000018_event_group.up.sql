@@ -0,0 +1,3 @@
+-- Not indexed: group filters narrow the existing tenant and time index
+-- scans, like the other optional log filters.
+ALTER TABLE events ADD COLUMN group_id text NOT NULL DEFAULT '';
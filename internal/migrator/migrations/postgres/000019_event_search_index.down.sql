@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_events_data_search;
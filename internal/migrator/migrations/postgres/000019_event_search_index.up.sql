@@ -0,0 +1,7 @@
+-- Support full-text search over event data. Metadata filters are not
+-- indexed: they narrow the existing tenant and time index scans, like the
+-- other optional log filters.
+--
+-- As in 000010, the parent index is created ON ONLY here, and the default
+-- partition is indexed concurrently and attached in 000020-000021.
+CREATE INDEX IF NOT EXISTS idx_events_data_search ON ONLY events USING gin (to_tsvector('simple', data));
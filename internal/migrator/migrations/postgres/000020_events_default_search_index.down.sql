@@ -0,0 +1 @@
+DROP INDEX CONCURRENTLY IF EXISTS events_default_data_search_idx;
@@ -0,0 +1,3 @@
+-- Attached partition indexes cannot be detached; dropping the parent drops
+-- them too. 000019 recreates the parent ON ONLY when migrating up again.
+DROP INDEX IF EXISTS idx_events_data_search;
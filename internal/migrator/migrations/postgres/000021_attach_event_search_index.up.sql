@@ -0,0 +1,3 @@
+-- Attaching marks the parent index from 000019 valid once every partition
+-- has a matching index.
+ALTER INDEX idx_events_data_search ATTACH PARTITION events_default_data_search_idx;
@@ -0,0 +1 @@
+ALTER TABLE attempts ADD COLUMN latency_ms bigint;
@@ -0,0 +1,43 @@
+package models_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDataChecksum(t *testing.T) {
+	t.Parallel()
+
+	compact := models.DataChecksum([]byte(`{"a":1,"b":[1,2]}`))
+	assert.True(t, strings.HasPrefix(compact, "sha256:"))
+	assert.Equal(t, compact, models.DataChecksum([]byte("{ \"a\": 1,\n \"b\": [1, 2] }")), "whitespace does not change the checksum")
+	assert.NotEqual(t, compact, models.DataChecksum([]byte(`{"a":2,"b":[1,2]}`)))
+}
+
+func TestEvent_VerifyChecksum(t *testing.T) {
+	t.Parallel()
+
+	data := json.RawMessage(`{"hello":"world"}`)
+
+	t.Run("matching checksum", func(t *testing.T) {
+		t.Parallel()
+		event := models.Event{Data: data, Checksum: models.DataChecksum(data)}
+		assert.NoError(t, event.VerifyChecksum())
+	})
+
+	t.Run("tampered data", func(t *testing.T) {
+		t.Parallel()
+		event := models.Event{Data: json.RawMessage(`{"hello":"mars"}`), Checksum: models.DataChecksum(data)}
+		assert.ErrorIs(t, event.VerifyChecksum(), models.ErrChecksumMismatch)
+	})
+
+	t.Run("no checksum", func(t *testing.T) {
+		t.Parallel()
+		event := models.Event{Data: data}
+		assert.NoError(t, event.VerifyChecksum())
+	})
+}
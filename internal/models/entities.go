@@ -3,6 +3,8 @@ package models
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"net/netip"
 	"slices"
 	"strings"
 	"time"
@@ -13,6 +15,9 @@ import (
 var (
 	ErrInvalidTopics       = errors.New("validation failed: invalid topics")
 	ErrInvalidTopicsFormat = errors.New("validation failed: invalid topics format")
+	ErrInvalidMaxPayload   = errors.New("validation failed: max_payload_bytes must not be negative")
+	ErrInvalidDeliveryCaps = errors.New("validation failed: max_concurrency and max_deliveries_per_second must not be negative")
+	ErrInvalidFilter       = errors.New("validation failed: invalid filter")
 )
 
 type Tenant struct {
@@ -20,29 +25,263 @@ type Tenant struct {
 	DestinationsCount int       `json:"destinations_count" redis:"-"`
 	Topics            []string  `json:"topics" redis:"-"`
 	Metadata          Metadata  `json:"metadata,omitempty" redis:"-"`
+	Sandbox           bool      `json:"sandbox" redis:"-"`
 	CreatedAt         time.Time `json:"created_at" redis:"created_at"`
 	UpdatedAt         time.Time `json:"updated_at" redis:"updated_at"`
+
+	ReceiptStorage    *ReceiptStorage          `json:"receipt_storage,omitempty" redis:"-"`
+	Notifications     *NotificationPreferences `json:"notifications,omitempty" redis:"-"`
+	LifecycleCallback *LifecycleCallback       `json:"lifecycle_callback,omitempty" redis:"-"`
+
+	// AlertChannels hold secrets, so they are left out of tenant reads and
+	// only managed through the alert channels endpoints.
+	AlertChannels *AlertChannels `json:"-" redis:"-"`
+
+	// DestinationTypes are the destination types the tenant may create, such
+	// as only webhooks on a free plan. Empty means every type the deployment
+	// supports.
+	DestinationTypes []string `json:"destination_types,omitempty" redis:"-"`
+
+	// PublishRateLimit overrides the deployment's publish rate limit for the
+	// tenant, such as a higher limit on a paid plan.
+	PublishRateLimit *PublishRateLimit `json:"publish_rate_limit,omitempty" redis:"-"`
+
+	// PortalAllowedIPs are the IP addresses and CIDR ranges the tenant's
+	// portal tokens may be used from. Empty means any address.
+	PortalAllowedIPs []string `json:"portal_allowed_ips,omitempty" redis:"-"`
+
+	// VerifyWebhookEndpoints requires the tenant's webhook destinations to
+	// echo a verification challenge before they are enabled.
+	VerifyWebhookEndpoints bool `json:"verify_webhook_endpoints,omitempty" redis:"-"`
+
+	// AutoDisablePolicy overrides the deployment's auto-disable policy for
+	// the tenant's destinations.
+	AutoDisablePolicy *AutoDisablePolicy `json:"auto_disable_policy,omitempty" redis:"-"`
+}
+
+// PublishRateLimit is a token bucket limit on the events a tenant publishes:
+// up to Burst events at once, refilled at PerSecond events per second. A
+// PerSecond of 0 means unlimited, and a Burst of 0 means PerSecond.
+type PublishRateLimit struct {
+	PerSecond int `json:"per_second"`
+	Burst     int `json:"burst,omitempty"`
+}
+
+// AllowsDestinationType reports whether the tenant may create destinations of
+// the given type.
+func (t *Tenant) AllowsDestinationType(destinationType string) bool {
+	return len(t.DestinationTypes) == 0 || slices.Contains(t.DestinationTypes, destinationType)
+}
+
+// AllowsPortalIP reports whether the tenant's portal tokens may be used from
+// the given client IP.
+func (t *Tenant) AllowsPortalIP(ip string) bool {
+	if len(t.PortalAllowedIPs) == 0 {
+		return true
+	}
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, entry := range t.PortalAllowedIPs {
+		if prefix, err := ParseIPRange(entry); err == nil && prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseIPRange parses an IP allowlist entry, either a CIDR range or a single
+// IP address.
+func ParseIPRange(s string) (netip.Prefix, error) {
+	if strings.Contains(s, "/") {
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return netip.Prefix{}, err
+		}
+		return prefix.Masked(), nil
+	}
+	addr, err := netip.ParseAddr(s)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	addr = addr.Unmap()
+	return netip.PrefixFrom(addr, addr.BitLen()), nil
+}
+
+// ReceiptStorage is the S3 location where daily delivery receipts for a
+// tenant are written.
+type ReceiptStorage struct {
+	Bucket string `json:"bucket"`
+	Region string `json:"region"`
+	Prefix string `json:"prefix,omitempty"`
+}
+
+// Key returns the object key of the receipt for the given UTC day.
+func (r *ReceiptStorage) Key(day time.Time) string {
+	return r.Prefix + day.UTC().Format("2006-01-02") + ".json"
+}
+
+// Notification categories a tenant can opt into.
+const (
+	// NotificationCategoryFailures covers delivery failure alerts: consecutive
+	// failures and exhausted retries.
+	NotificationCategoryFailures = "failures"
+	// NotificationCategoryDisables covers destinations being auto-disabled.
+	NotificationCategoryDisables = "disables"
+	// NotificationCategoryMaintenance covers operator maintenance notices.
+	NotificationCategoryMaintenance = "maintenance"
+)
+
+// NotificationCategories lists every supported notification category.
+var NotificationCategories = []string{
+	NotificationCategoryFailures,
+	NotificationCategoryDisables,
+	NotificationCategoryMaintenance,
+}
+
+// NotificationPreferences holds where and about what a tenant wants to be
+// notified. Outpost does not send the notifications itself: the contact is
+// attached to the matching operator events for the operator to route.
+type NotificationPreferences struct {
+	Email      string   `json:"email,omitempty"`
+	WebhookURL string   `json:"webhook_url,omitempty"`
+	Categories []string `json:"categories"`
+}
+
+// Wants reports whether the tenant opted into the category and left a
+// contact to reach them at.
+func (n *NotificationPreferences) Wants(category string) bool {
+	if n == nil || (n.Email == "" && n.WebhookURL == "") {
+		return false
+	}
+	return slices.Contains(n.Categories, category)
+}
+
+// LifecycleCallback is where the producer of a tenant's events receives
+// lifecycle notifications for them.
+type LifecycleCallback struct {
+	URL string `json:"url"`
+}
+
+// AlertChannels are where a tenant's alerts are sent, in addition to the
+// deployment's alert channels. Each channel is used when set.
+type AlertChannels struct {
+	SlackWebhookURL     string `json:"slack_webhook_url,omitempty"`
+	PagerDutyRoutingKey string `json:"pagerduty_routing_key,omitempty"`
+	WebhookURL          string `json:"webhook_url,omitempty"`
+}
+
+// AutoDisablePolicy is a tenant's override of the deployment's consecutive
+// failure policy. Each field left unset follows the deployment's.
+type AutoDisablePolicy struct {
+	// FailureCount is the number of consecutive failures that disables a
+	// destination, and that alert thresholds are a percentage of.
+	FailureCount *int `json:"failure_count,omitempty"`
+	// WindowSeconds only counts the failures of a streak within the last
+	// WindowSeconds. 0 counts the whole streak.
+	WindowSeconds *int `json:"window_seconds,omitempty"`
+	// AutoDisable disables a destination that reaches FailureCount.
+	AutoDisable *bool `json:"auto_disable,omitempty"`
+	// ProbationSeconds re-enables an auto-disabled destination once it has
+	// been disabled that long and a test delivery to it succeeds. 0 never
+	// re-enables it.
+	ProbationSeconds *int `json:"probation_seconds,omitempty"`
 }
 
 type Destination struct {
-	ID               string           `json:"id" redis:"id"`
-	TenantID         string           `json:"tenant_id" redis:"-"`
-	Type             string           `json:"type" redis:"type"`
-	Topics           Topics           `json:"topics" redis:"-"`
-	Filter           Filter           `json:"filter,omitempty" redis:"-"`
-	Config           Config           `json:"config" redis:"-"`
-	Credentials      Credentials      `json:"credentials" redis:"-"`
-	DeliveryMetadata DeliveryMetadata `json:"delivery_metadata,omitempty" redis:"-"`
-	Metadata         Metadata         `json:"metadata,omitempty" redis:"-"`
-	CreatedAt        time.Time        `json:"created_at" redis:"created_at"`
-	UpdatedAt        time.Time        `json:"updated_at" redis:"updated_at"`
-	DisabledAt       *time.Time       `json:"disabled_at" redis:"disabled_at"`
+	ID                  string           `json:"id" redis:"id"`
+	TenantID            string           `json:"tenant_id" redis:"-"`
+	Type                string           `json:"type" redis:"type"`
+	Topics              Topics           `json:"topics" redis:"-"`
+	Filter              Filter           `json:"filter,omitempty" redis:"-"`
+	Config              Config           `json:"config" redis:"-"`
+	Credentials         Credentials      `json:"credentials" redis:"-"`
+	DeliveryMetadata    DeliveryMetadata `json:"delivery_metadata,omitempty" redis:"-"`
+	Metadata            Metadata         `json:"metadata,omitempty" redis:"-"`
+	SandboxSafe         bool             `json:"sandbox_safe,omitempty" redis:"-"`
+	Recording           *Recording       `json:"recording,omitempty" redis:"-"`
+	ShadowDestinationID string           `json:"shadow_destination_id,omitempty" redis:"-"`
+	RetryPolicy         *RetryPolicy     `json:"retry_policy,omitempty" redis:"-"`
+	Transformation      *Transformation  `json:"transformation,omitempty" redis:"-"`
+	// MaxPayloadBytes is the largest event data delivered inline. Larger
+	// data is offloaded and replaced by a stub with a fetch URL. 0 means no
+	// limit.
+	MaxPayloadBytes int `json:"max_payload_bytes,omitempty" redis:"-"`
+	// MaxConcurrency caps the deliveries to the destination in flight at once
+	// on each delivery replica. 0 means no limit.
+	MaxConcurrency int `json:"max_concurrency,omitempty" redis:"-"`
+	// MaxDeliveriesPerSecond caps the deliveries to the destination started
+	// per second on each delivery replica. 0 means no limit.
+	MaxDeliveriesPerSecond int `json:"max_deliveries_per_second,omitempty" redis:"-"`
+	// TargetChange is set once the destination's target has changed.
+	TargetChange *TargetChange `json:"target_change,omitempty" redis:"-"`
+	CreatedAt    time.Time     `json:"created_at" redis:"created_at"`
+	UpdatedAt    time.Time     `json:"updated_at" redis:"updated_at"`
+	DisabledAt   *time.Time    `json:"disabled_at" redis:"disabled_at"`
+}
+
+// Recording configures a time-boxed debug recording of deliveries to a
+// destination. While active, a sample of deliveries is mirrored to SinkURL
+// in addition to being delivered normally.
+type Recording struct {
+	SinkURL    string    `json:"sink_url"`
+	SampleRate float64   `json:"sample_rate"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Active reports whether the recording window is open at the given time.
+func (r *Recording) Active(now time.Time) bool {
+	return r != nil && r.SinkURL != "" && now.Before(r.ExpiresAt)
+}
+
+const (
+	// DestinationHealthHealthy means the destination's last attempt succeeded.
+	DestinationHealthHealthy = "healthy"
+	// DestinationHealthFailing means the destination's last attempt failed.
+	DestinationHealthFailing = "failing"
+	// DestinationHealthUnknown means no attempt to the destination was
+	// recorded recently.
+	DestinationHealthUnknown = "unknown"
+)
+
+// DestinationHealth summarizes the recent attempts to a destination.
+type DestinationHealth struct {
+	Status              string                  `json:"status"`
+	ConsecutiveFailures int                     `json:"consecutive_failures"`
+	LastError           *DestinationHealthError `json:"last_error,omitempty"`
+	LastSuccessAt       *time.Time              `json:"last_success_at,omitempty"`
+}
+
+// DestinationHealthError is the error of a destination's last failed attempt,
+// in the terms Attempt.ErrorCode and Attempt.ErrorMessage use.
+type DestinationHealthError struct {
+	AttemptID string    `json:"attempt_id"`
+	Code      string    `json:"code,omitempty"`
+	Message   string    `json:"message,omitempty"`
+	Time      time.Time `json:"time"`
 }
 
 func (d *Destination) Validate(topics []string, allowWildcards bool) error {
 	if err := d.Topics.Validate(topics, allowWildcards); err != nil {
 		return err
 	}
+	if err := d.RetryPolicy.Validate(); err != nil {
+		return err
+	}
+	if err := d.Transformation.Validate(); err != nil {
+		return err
+	}
+	if err := d.Filter.Validate(); err != nil {
+		return err
+	}
+	if d.MaxPayloadBytes < 0 {
+		return ErrInvalidMaxPayload
+	}
+	if d.MaxConcurrency < 0 || d.MaxDeliveriesPerSecond < 0 {
+		return ErrInvalidDeliveryCaps
+	}
 	return nil
 }
 
@@ -58,14 +297,75 @@ func (d *Destination) MatchEvent(event Event) bool {
 	return MatchFilter(d.Filter, event)
 }
 
+// Validate checks that the filter can be evaluated, rejecting malformed
+// $regex patterns that would otherwise never match.
+func (f Filter) Validate() error {
+	if err := simplejsonmatch.Validate(map[string]any(f)); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidFilter, err)
+	}
+	return nil
+}
+
 // MatchFilter checks if the given event matches the filter.
 // Returns true if no filter is set (nil or empty) or if the event matches the filter.
 func MatchFilter(filter Filter, event Event) bool {
 	if len(filter) == 0 {
 		return true
 	}
-	// Build the filter input from the event
-	filterInput := map[string]any{
+	return simplejsonmatch.Match(filterInput(event), map[string]any(filter))
+}
+
+// FilterMismatches returns the dot-separated paths of the filter's fields the
+// event doesn't match, descending into nested objects to the innermost
+// mismatched field. It returns nil when the event matches the filter.
+func FilterMismatches(filter Filter, event Event) []string {
+	if MatchFilter(filter, event) {
+		return nil
+	}
+	return filterMismatches(filterInput(event), map[string]any(filter), "")
+}
+
+func filterMismatches(input any, schema map[string]any, prefix string) []string {
+	keys := make([]string, 0, len(schema))
+	for key := range schema {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	var paths []string
+	for _, key := range keys {
+		field := map[string]any{key: schema[key]}
+		if simplejsonmatch.Match(input, field) {
+			continue
+		}
+		path := prefix + key
+		inputMap, _ := input.(map[string]any)
+		nested, isObject := schema[key].(map[string]any)
+		if !strings.HasPrefix(key, "$") && isObject && !hasOperatorKey(nested) && inputMap != nil {
+			if _, ok := inputMap[key].(map[string]any); ok {
+				if nestedPaths := filterMismatches(inputMap[key], nested, path+"."); len(nestedPaths) > 0 {
+					paths = append(paths, nestedPaths...)
+					continue
+				}
+			}
+		}
+		paths = append(paths, path)
+	}
+	return paths
+}
+
+func hasOperatorKey(schema map[string]any) bool {
+	for key := range schema {
+		if strings.HasPrefix(key, "$") {
+			return true
+		}
+	}
+	return false
+}
+
+// filterInput is the document filters are matched against.
+func filterInput(event Event) map[string]any {
+	input := map[string]any{
 		"id":       event.ID,
 		"topic":    event.Topic,
 		"time":     event.Time.Format("2006-01-02T15:04:05Z07:00"),
@@ -78,7 +378,7 @@ func MatchFilter(filter Filter, event Event) bool {
 		for k, v := range event.Metadata {
 			metadata[k] = v
 		}
-		filterInput["metadata"] = metadata
+		input["metadata"] = metadata
 	}
 	// Parse data from raw JSON.
 	// ParsedData() should never fail here: ingestion validates that Data is a
@@ -86,9 +386,9 @@ func MatchFilter(filter Filter, event Event) bool {
 	// filter runs against no data fields (likely a no-match).
 	parsed, err := event.ParsedData()
 	if err == nil && parsed != nil {
-		filterInput["data"] = parsed
+		input["data"] = parsed
 	}
-	return simplejsonmatch.Match(filterInput, map[string]any(filter))
+	return input
 }
 
 type Event struct {
@@ -101,6 +401,13 @@ type Event struct {
 	Time                  time.Time `json:"time"`
 	Metadata              Metadata  `json:"metadata"`
 	Data                  Data      `json:"data"`
+	// Checksum is the DataChecksum of Data, taken when the event is published.
+	Checksum string `json:"checksum,omitempty"`
+	// Source identifies the upstream system that published the event.
+	Source string `json:"source,omitempty"`
+	// GroupID identifies the group the event was published in, when it was
+	// published together with other events.
+	GroupID string `json:"group_id,omitempty"`
 
 	// Telemetry data, must exist to properly trace events between publish receiver & delivery handler
 	Telemetry *EventTelemetry `json:"telemetry,omitempty"`
@@ -120,11 +427,40 @@ func (e *Event) ParsedData() (map[string]any, error) {
 	return m, nil
 }
 
+// MaxSourceLength is the longest event source accepted on publish.
+const MaxSourceLength = 64
+
+// ValidSource reports whether source is a valid event source: up to
+// MaxSourceLength letters, digits and the characters "-", "_", ".", ":" and
+// "/". An empty source is valid and means the source is unknown.
+func ValidSource(source string) bool {
+	if len(source) > MaxSourceLength {
+		return false
+	}
+	for _, r := range source {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9':
+		case r == '-', r == '_', r == '.', r == ':', r == '/':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 const (
 	AttemptStatusSuccess = "success"
 	AttemptStatusFailed  = "failed"
+	// AttemptStatusDeferred marks an attempt the destination accepted while
+	// asking to receive the event again later. It is neither a success nor a
+	// failure; the event is redelivered after the requested delay.
+	AttemptStatusDeferred = "deferred"
 )
 
+// AttemptCodeSandbox marks an attempt that was recorded for a sandbox tenant
+// without making an external delivery.
+const AttemptCodeSandbox = "SANDBOX"
+
 type Attempt struct {
 	ID              string                 `json:"id"`
 	TenantID        string                 `json:"tenant_id"`
@@ -137,6 +473,27 @@ type Attempt struct {
 	Time            time.Time              `json:"time"`
 	Code            string                 `json:"code"`
 	ResponseData    map[string]interface{} `json:"response_data"`
+	// ErrorCode and ErrorMessage explain why a failed attempt failed in terms
+	// safe to show tenants. The raw provider error stays in ResponseData.
+	ErrorCode    string `json:"error_code,omitempty"`
+	ErrorMessage string `json:"error_message,omitempty"`
+	// LatencyMs is how long the destination took to handle the delivery, in
+	// milliseconds. Nil when no delivery was made (e.g. the transformation
+	// failed) or on attempts recorded before latencies existed.
+	LatencyMs *int64 `json:"latency_ms,omitempty"`
+	// DestinationSnapshot is the destination as configured when the attempt
+	// was made, kept so the record stays readable after the destination is
+	// edited or deleted. Nil on attempts recorded before snapshots existed.
+	DestinationSnapshot *DestinationSnapshot `json:"destination_snapshot,omitempty"`
+}
+
+// DestinationSnapshot describes a destination at delivery time. It holds the
+// obfuscated config only; credentials are never captured.
+type DestinationSnapshot struct {
+	Type      string `json:"type"`
+	Target    string `json:"target"`
+	TargetURL string `json:"target_url,omitempty"`
+	Config    Config `json:"config"`
 }
 
 // ============================== Types ==============================
@@ -185,12 +542,12 @@ func (t *Topics) Validate(availableTopics []string, allowWildcards bool) error {
 			if !allowWildcards {
 				return ErrInvalidTopics
 			}
-			if !topicPatternMatchesAny(topic, availableTopics) {
+			if !topicPatternMatchesAny(topic, availableTopics) && !TopicAvailable(availableTopics, topic) {
 				return ErrInvalidTopics
 			}
 			continue
 		}
-		if !slices.Contains(availableTopics, topic) {
+		if !TopicAvailable(availableTopics, topic) {
 			return ErrInvalidTopics
 		}
 	}
@@ -243,6 +600,19 @@ func coveredByOther(e string, topics Topics) bool {
 	return false
 }
 
+// TopicAvailable reports whether topic is one of the available topics or is
+// covered by an available wildcard pattern such as "user.*". A destination
+// pattern is covered when an available pattern is at least as general
+// ("user.*" covers "user.profile.*").
+func TopicAvailable(availableTopics []string, topic string) bool {
+	for _, available := range availableTopics {
+		if matchTopicPattern(available, topic) {
+			return true
+		}
+	}
+	return false
+}
+
 func topicPatternMatchesAny(pattern string, topics []string) bool {
 	for _, topic := range topics {
 		if matchTopicPattern(pattern, topic) {
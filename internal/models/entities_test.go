@@ -3,6 +3,7 @@ package models_test
 import (
 	"encoding/json"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/hookdeck/outpost/internal/models"
@@ -85,6 +86,29 @@ func TestDestinationTopics_Validate(t *testing.T) {
 			availableTopics: testutil.TestTopics,
 			validated:       false,
 		},
+		// Test cases for available topic patterns
+		{
+			topics:          []string{"order.created", "order.payment.completed"},
+			availableTopics: []string{"order.*"},
+			validated:       true,
+		},
+		{
+			topics:          []string{"user.created"},
+			availableTopics: []string{"order.*"},
+			validated:       false,
+		},
+		{
+			topics:          []string{"order.payment.*"},
+			availableTopics: []string{"order.*"},
+			allowWildcards:  true,
+			validated:       true,
+		},
+		{
+			topics:          []string{"*.created"},
+			availableTopics: []string{"order.*"},
+			allowWildcards:  true,
+			validated:       false,
+		},
 		// Test cases for empty availableTopics
 		{
 			topics:          []string{"any.topic"},
@@ -275,6 +299,32 @@ func TestDestination_JSONUnmarshalEmptyMaps(t *testing.T) {
 	assert.Empty(t, destination.Metadata)
 }
 
+func TestValidSource(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		source   string
+		expected bool
+	}{
+		{source: "", expected: true},
+		{source: "billing-service", expected: true},
+		{source: "team.billing:invoices/v2", expected: true},
+		{source: "Billing_Service_1", expected: true},
+		{source: strings.Repeat("a", models.MaxSourceLength), expected: true},
+		{source: strings.Repeat("a", models.MaxSourceLength+1), expected: false},
+		{source: "billing service", expected: false},
+		{source: "billing\nservice", expected: false},
+		{source: "bïlling", expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.source, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, models.ValidSource(tc.source))
+		})
+	}
+}
+
 func TestTopics_MatchTopic(t *testing.T) {
 	t.Parallel()
 
@@ -782,6 +832,45 @@ func TestMatchFilter(t *testing.T) {
 			event:    baseEvent,
 			expected: false,
 		},
+		{
+			name: "filter by $regex",
+			filter: models.Filter{
+				"data": map[string]any{
+					"customer": map[string]any{"id": map[string]any{"$regex": "^cust_[0-9]+$"}},
+				},
+			},
+			event:    baseEvent,
+			expected: true,
+		},
+		{
+			name: "filter by $regex no match",
+			filter: models.Filter{
+				"data": map[string]any{
+					"customer": map[string]any{"id": map[string]any{"$regex": "^usr_"}},
+				},
+			},
+			event:    baseEvent,
+			expected: false,
+		},
+		{
+			name: "filter by $exists false on missing field",
+			filter: models.Filter{
+				"data": map[string]any{"refunded_at": map[string]any{"$exists": false}},
+			},
+			event:    baseEvent,
+			expected: true,
+		},
+		{
+			name: "filter by $or of threshold and region",
+			filter: models.Filter{
+				"$or": []any{
+					map[string]any{"data": map[string]any{"amount": map[string]any{"$gt": float64(500)}}},
+					map[string]any{"data": map[string]any{"customer": map[string]any{"tier": map[string]any{"$in": []any{"premium", "gold"}}}}},
+				},
+			},
+			event:    baseEvent,
+			expected: true,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -792,6 +881,74 @@ func TestMatchFilter(t *testing.T) {
 	}
 }
 
+func TestFilterMismatches(t *testing.T) {
+	t.Parallel()
+
+	event := testutil.EventFactory.Any(
+		testutil.EventFactory.WithTopic("order.created"),
+		testutil.EventFactory.WithMetadata(map[string]string{"source": "api"}),
+		testutil.EventFactory.WithDataMap(map[string]any{
+			"amount": float64(100),
+			"customer": map[string]any{
+				"id":   "cust_123",
+				"tier": "premium",
+			},
+		}),
+	)
+
+	testCases := []struct {
+		name     string
+		filter   models.Filter
+		expected []string
+	}{
+		{
+			name: "matching filter",
+			filter: models.Filter{
+				"data": map[string]any{"amount": map[string]any{"$gte": 100}},
+			},
+			expected: nil,
+		},
+		{
+			name: "innermost mismatched fields",
+			filter: models.Filter{
+				"topic":    "order.created",
+				"metadata": map[string]any{"source": "dashboard"},
+				"data": map[string]any{
+					"amount":   map[string]any{"$gt": 500},
+					"customer": map[string]any{"id": "cust_123", "tier": "basic"},
+				},
+			},
+			expected: []string{"data.amount", "data.customer.tier", "metadata.source"},
+		},
+		{
+			name: "operator at the top level",
+			filter: models.Filter{
+				"$or": []any{
+					map[string]any{"topic": "order.deleted"},
+					map[string]any{"metadata": map[string]any{"source": "dashboard"}},
+				},
+			},
+			expected: []string{"$or"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, models.FilterMismatches(tc.filter, event))
+		})
+	}
+}
+
+func TestFilter_Validate(t *testing.T) {
+	t.Parallel()
+
+	assert.NoError(t, models.Filter(nil).Validate())
+	assert.NoError(t, models.Filter{"data": map[string]any{"id": map[string]any{"$regex": "^cust_"}}}.Validate())
+	assert.ErrorIs(t, models.Filter{"data": map[string]any{"id": map[string]any{"$regex": "("}}}.Validate(), models.ErrInvalidFilter)
+	assert.ErrorIs(t, models.Filter{"$or": []any{map[string]any{"id": map[string]any{"$regex": float64(1)}}}}.Validate(), models.ErrInvalidFilter)
+}
+
 func TestDestination_JSONMarshalWithFilter(t *testing.T) {
 	t.Parallel()
 
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/backoff"
+)
+
+var ErrInvalidRetryPolicy = errors.New("validation failed: invalid retry_policy")
+
+// Backoff types of a RetryPolicy.
+const (
+	RetryBackoffExponential = "exponential"
+	RetryBackoffConstant    = "constant"
+	RetryBackoffScheduled   = "scheduled"
+)
+
+const (
+	retryPolicyMaxAttempts     = 100
+	retryPolicyMaxDelaySeconds = 7 * 24 * 60 * 60
+)
+
+// RetryPolicy overrides the deployment's retry configuration for a
+// destination. Unset fields fall back to the deployment's configuration.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// Backoff is exponential (base 2), constant or scheduled. When omitted
+	// it is scheduled if Schedule is set and exponential if IntervalSeconds
+	// is set.
+	Backoff         string `json:"backoff,omitempty"`
+	IntervalSeconds int    `json:"interval_seconds,omitempty"`
+	// Schedule is the delay in seconds before each retry. Retries beyond it
+	// wait for the last delay.
+	Schedule []int `json:"schedule,omitempty"`
+}
+
+func (p *RetryPolicy) Validate() error {
+	if p == nil {
+		return nil
+	}
+	if p.MaxAttempts < 0 || p.MaxAttempts > retryPolicyMaxAttempts {
+		return fmt.Errorf("%w: max_attempts must be between 1 and %d", ErrInvalidRetryPolicy, retryPolicyMaxAttempts)
+	}
+	if p.IntervalSeconds < 0 || p.IntervalSeconds > retryPolicyMaxDelaySeconds {
+		return fmt.Errorf("%w: interval_seconds must be between 1 and %d", ErrInvalidRetryPolicy, retryPolicyMaxDelaySeconds)
+	}
+	if len(p.Schedule) > retryPolicyMaxAttempts {
+		return fmt.Errorf("%w: schedule must have at most %d entries", ErrInvalidRetryPolicy, retryPolicyMaxAttempts)
+	}
+	for _, seconds := range p.Schedule {
+		if seconds < 0 || seconds > retryPolicyMaxDelaySeconds {
+			return fmt.Errorf("%w: schedule entries must be between 0 and %d", ErrInvalidRetryPolicy, retryPolicyMaxDelaySeconds)
+		}
+	}
+
+	switch p.Backoff {
+	case "":
+		if p.IntervalSeconds > 0 && len(p.Schedule) > 0 {
+			return fmt.Errorf("%w: interval_seconds and schedule are mutually exclusive", ErrInvalidRetryPolicy)
+		}
+	case RetryBackoffExponential, RetryBackoffConstant:
+		if p.IntervalSeconds == 0 {
+			return fmt.Errorf("%w: %s backoff requires interval_seconds", ErrInvalidRetryPolicy, p.Backoff)
+		}
+		if len(p.Schedule) > 0 {
+			return fmt.Errorf("%w: schedule requires scheduled backoff", ErrInvalidRetryPolicy)
+		}
+	case RetryBackoffScheduled:
+		if len(p.Schedule) == 0 {
+			return fmt.Errorf("%w: scheduled backoff requires schedule", ErrInvalidRetryPolicy)
+		}
+		if p.IntervalSeconds > 0 {
+			return fmt.Errorf("%w: interval_seconds requires exponential or constant backoff", ErrInvalidRetryPolicy)
+		}
+	default:
+		return fmt.Errorf("%w: backoff must be one of %s, %s or %s", ErrInvalidRetryPolicy, RetryBackoffExponential, RetryBackoffConstant, RetryBackoffScheduled)
+	}
+	return nil
+}
+
+// RetryLimit returns the number of retries the policy allows, and false when
+// it leaves the limit to the deployment.
+func (p *RetryPolicy) RetryLimit() (int, bool) {
+	switch {
+	case p == nil:
+		return 0, false
+	case p.MaxAttempts > 0:
+		return p.MaxAttempts - 1, true
+	case len(p.Schedule) > 0:
+		// Like the deployment's retry_schedule, the schedule length is the
+		// retry limit.
+		return len(p.Schedule), true
+	}
+	return 0, false
+}
+
+// RetryBackoff returns the backoff of the policy, and false when it leaves
+// the backoff to the deployment.
+func (p *RetryPolicy) RetryBackoff() (backoff.Backoff, bool) {
+	if p == nil {
+		return nil, false
+	}
+	interval := time.Duration(p.IntervalSeconds) * time.Second
+	switch {
+	case p.Backoff == RetryBackoffConstant:
+		return &backoff.ConstantBackoff{Interval: interval}, true
+	case len(p.Schedule) > 0:
+		schedule := make([]time.Duration, len(p.Schedule))
+		for i, seconds := range p.Schedule {
+			schedule[i] = time.Duration(seconds) * time.Second
+		}
+		return &backoff.ScheduledBackoff{Schedule: schedule}, true
+	case p.IntervalSeconds > 0:
+		return &backoff.ExponentialBackoff{Interval: interval, Base: 2}, true
+	}
+	return nil, false
+}
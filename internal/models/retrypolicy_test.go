@@ -0,0 +1,98 @@
+package models_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/backoff"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryPolicy_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name   string
+		policy *models.RetryPolicy
+		valid  bool
+	}{
+		{name: "nil", policy: nil, valid: true},
+		{name: "max attempts only", policy: &models.RetryPolicy{MaxAttempts: 1}, valid: true},
+		{name: "inferred exponential", policy: &models.RetryPolicy{IntervalSeconds: 10}, valid: true},
+		{name: "inferred scheduled", policy: &models.RetryPolicy{Schedule: []int{5, 60}}, valid: true},
+		{name: "constant", policy: &models.RetryPolicy{Backoff: "constant", IntervalSeconds: 30, MaxAttempts: 5}, valid: true},
+		{name: "scheduled", policy: &models.RetryPolicy{Backoff: "scheduled", Schedule: []int{0, 60}}, valid: true},
+		{name: "unknown backoff", policy: &models.RetryPolicy{Backoff: "linear", IntervalSeconds: 10}},
+		{name: "negative max attempts", policy: &models.RetryPolicy{MaxAttempts: -1}},
+		{name: "too many attempts", policy: &models.RetryPolicy{MaxAttempts: 101}},
+		{name: "interval too long", policy: &models.RetryPolicy{IntervalSeconds: 8 * 24 * 60 * 60}},
+		{name: "exponential without interval", policy: &models.RetryPolicy{Backoff: "exponential"}},
+		{name: "scheduled without schedule", policy: &models.RetryPolicy{Backoff: "scheduled"}},
+		{name: "constant with schedule", policy: &models.RetryPolicy{Backoff: "constant", IntervalSeconds: 10, Schedule: []int{5}}},
+		{name: "interval and schedule", policy: &models.RetryPolicy{IntervalSeconds: 10, Schedule: []int{5}}},
+		{name: "negative schedule entry", policy: &models.RetryPolicy{Schedule: []int{-5}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.policy.Validate()
+			if tt.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, models.ErrInvalidRetryPolicy)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Resolve(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name            string
+		policy          *models.RetryPolicy
+		expectedLimit   int
+		overridesLimit  bool
+		expectedBackoff backoff.Backoff
+	}{
+		{name: "nil"},
+		{
+			name:           "max attempts only",
+			policy:         &models.RetryPolicy{MaxAttempts: 1},
+			expectedLimit:  0,
+			overridesLimit: true,
+		},
+		{
+			name:            "exponential",
+			policy:          &models.RetryPolicy{IntervalSeconds: 10},
+			expectedBackoff: &backoff.ExponentialBackoff{Interval: 10 * time.Second, Base: 2},
+		},
+		{
+			name:            "constant",
+			policy:          &models.RetryPolicy{Backoff: "constant", IntervalSeconds: 30, MaxAttempts: 5},
+			expectedLimit:   4,
+			overridesLimit:  true,
+			expectedBackoff: &backoff.ConstantBackoff{Interval: 30 * time.Second},
+		},
+		{
+			name:            "schedule length is the limit",
+			policy:          &models.RetryPolicy{Schedule: []int{5, 60}},
+			expectedLimit:   2,
+			overridesLimit:  true,
+			expectedBackoff: &backoff.ScheduledBackoff{Schedule: []time.Duration{5 * time.Second, time.Minute}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			limit, ok := tt.policy.RetryLimit()
+			assert.Equal(t, tt.overridesLimit, ok)
+			assert.Equal(t, tt.expectedLimit, limit)
+
+			retryBackoff, ok := tt.policy.RetryBackoff()
+			assert.Equal(t, tt.expectedBackoff != nil, ok)
+			assert.Equal(t, tt.expectedBackoff, retryBackoff)
+		})
+	}
+}
@@ -18,6 +18,29 @@ var _ json.Unmarshaler = &Topics{}
 var _ encoding.BinaryMarshaler = &Filter{}
 var _ encoding.BinaryUnmarshaler = &Filter{}
 
+var _ encoding.BinaryMarshaler = &Recording{}
+var _ encoding.BinaryUnmarshaler = &Recording{}
+
+var _ encoding.BinaryMarshaler = &RetryPolicy{}
+var _ encoding.BinaryUnmarshaler = &RetryPolicy{}
+
+var _ encoding.BinaryMarshaler = &Transformation{}
+var _ encoding.BinaryUnmarshaler = &Transformation{}
+var _ encoding.BinaryMarshaler = &TargetChange{}
+var _ encoding.BinaryUnmarshaler = &TargetChange{}
+
+var _ encoding.BinaryMarshaler = &ReceiptStorage{}
+var _ encoding.BinaryUnmarshaler = &ReceiptStorage{}
+
+var _ encoding.BinaryMarshaler = &NotificationPreferences{}
+var _ encoding.BinaryUnmarshaler = &NotificationPreferences{}
+var _ encoding.BinaryMarshaler = &LifecycleCallback{}
+var _ encoding.BinaryUnmarshaler = &LifecycleCallback{}
+var _ encoding.BinaryMarshaler = &AlertChannels{}
+var _ encoding.BinaryUnmarshaler = &AlertChannels{}
+var _ encoding.BinaryMarshaler = &AutoDisablePolicy{}
+var _ encoding.BinaryUnmarshaler = &AutoDisablePolicy{}
+
 var _ encoding.BinaryMarshaler = &MapStringString{}
 var _ encoding.BinaryUnmarshaler = &MapStringString{}
 var _ json.Unmarshaler = &MapStringString{}
@@ -139,3 +162,110 @@ type Data = json.RawMessage
 // ============================== Metadata ==============================
 
 type Metadata = MapStringString
+
+// ============================== Recording serialization ==============================
+
+func (r *Recording) MarshalBinary() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (r *Recording) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, r)
+}
+
+// ============================== RetryPolicy serialization ==============================
+
+func (p *RetryPolicy) MarshalBinary() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p *RetryPolicy) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+// ============================== Transformation serialization ==============================
+
+func (t *Transformation) MarshalBinary() ([]byte, error) {
+	return json.Marshal(t)
+}
+
+// UnmarshalBinary loads a stored transformation and compiles its expression
+// into the cache. An expression that no longer compiles is left for Apply to
+// report.
+func (t *Transformation) UnmarshalBinary(data []byte) error {
+	if err := json.Unmarshal(data, t); err != nil {
+		return err
+	}
+	_, _ = compileTransformation(t.Expression)
+	return nil
+}
+
+// ============================== ReceiptStorage serialization ==============================
+
+func (r *ReceiptStorage) MarshalBinary() ([]byte, error) {
+	return json.Marshal(r)
+}
+
+func (r *ReceiptStorage) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, r)
+}
+
+// ============================== NotificationPreferences serialization ==============================
+
+func (n *NotificationPreferences) MarshalBinary() ([]byte, error) {
+	return json.Marshal(n)
+}
+
+func (n *NotificationPreferences) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, n)
+}
+
+// ============================== LifecycleCallback serialization ==============================
+
+func (l *LifecycleCallback) MarshalBinary() ([]byte, error) {
+	return json.Marshal(l)
+}
+
+func (l *LifecycleCallback) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, l)
+}
+
+// ============================== AlertChannels serialization ==============================
+
+func (a *AlertChannels) MarshalBinary() ([]byte, error) {
+	return json.Marshal(a)
+}
+
+func (a *AlertChannels) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, a)
+}
+
+// ============================== TargetChange serialization ==============================
+
+func (c *TargetChange) MarshalBinary() ([]byte, error) {
+	return json.Marshal(c)
+}
+
+func (c *TargetChange) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, c)
+}
+
+// ============================== AutoDisablePolicy serialization ==============================
+
+func (p *AutoDisablePolicy) MarshalBinary() ([]byte, error) {
+	return json.Marshal(p)
+}
+
+func (p *AutoDisablePolicy) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, p)
+}
+
+// ============================== PublishRateLimit serialization ==============================
+
+func (l *PublishRateLimit) MarshalBinary() ([]byte, error) {
+	return json.Marshal(l)
+}
+
+func (l *PublishRateLimit) UnmarshalBinary(data []byte) error {
+	return json.Unmarshal(data, l)
+}
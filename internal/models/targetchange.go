@@ -0,0 +1,45 @@
+package models
+
+import (
+	"errors"
+	"time"
+)
+
+var ErrInvalidQueuedMessages = errors.New("validation failed: queued_messages must be new_target, previous_target or hold")
+
+// Where the messages queued for a destination before its target changed are
+// delivered.
+const (
+	// QueuedMessagesNewTarget delivers them to the new target.
+	QueuedMessagesNewTarget = "new_target"
+	// QueuedMessagesPreviousTarget delivers them to the target they were
+	// queued for.
+	QueuedMessagesPreviousTarget = "previous_target"
+	// QueuedMessagesHold holds them until they are released to the new
+	// target or discarded.
+	QueuedMessagesHold = "hold"
+)
+
+// TargetChange records the last change of a destination's target, such as its
+// URL or queue, and where the messages queued before it are delivered.
+type TargetChange struct {
+	ChangedAt      time.Time `json:"changed_at"`
+	QueuedMessages string    `json:"queued_messages"`
+}
+
+// ValidateQueuedMessages checks that queuedMessages is one of the
+// QueuedMessages values.
+func ValidateQueuedMessages(queuedMessages string) error {
+	switch queuedMessages {
+	case QueuedMessagesNewTarget, QueuedMessagesPreviousTarget, QueuedMessagesHold:
+		return nil
+	}
+	return ErrInvalidQueuedMessages
+}
+
+// Precedes reports whether the task was queued before the target changed.
+// Tasks queued before queue times were recorded are treated as queued for
+// the new target.
+func (c *TargetChange) Precedes(task *DeliveryTask) bool {
+	return c != nil && !task.QueuedAt.IsZero() && task.QueuedAt.Before(c.ChangedAt)
+}
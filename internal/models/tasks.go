@@ -3,6 +3,7 @@ package models
 import (
 	"encoding/json"
 	"strconv"
+	"time"
 
 	"github.com/hookdeck/outpost/internal/mqs"
 )
@@ -35,11 +36,17 @@ func (e *Event) ToMessage() (*mqs.Message, error) {
 // DeliveryTask represents a task to deliver an event to a destination.
 // This is a message type (no ID) used by: publishmq -> deliverymq, retry -> deliverymq
 type DeliveryTask struct {
-	Event         Event              `json:"event"`
-	DestinationID string             `json:"destination_id"`
-	Attempt       int                `json:"attempt"`
-	Manual        bool               `json:"manual"`
-	Telemetry     *DeliveryTelemetry `json:"telemetry,omitempty"`
+	Event         Event  `json:"event"`
+	DestinationID string `json:"destination_id"`
+	Attempt       int    `json:"attempt"`
+	Manual        bool   `json:"manual"`
+	// Bulk marks a manual retry enqueued by a bulk retry job. Unlike other
+	// manual retries it counts against the retry limiter.
+	Bulk      bool               `json:"bulk,omitempty"`
+	Telemetry *DeliveryTelemetry `json:"telemetry,omitempty"`
+	// QueuedAt is when the event was first queued for the destination. Its
+	// retries keep it, so they follow the destination's TargetChange.
+	QueuedAt time.Time `json:"queued_at,omitzero"`
 }
 
 var _ mqs.IncomingMessage = &DeliveryTask{}
@@ -77,6 +84,7 @@ func NewDeliveryTask(event Event, destinationID string) DeliveryTask {
 		Event:         event,
 		DestinationID: destinationID,
 		Attempt:       1,
+		QueuedAt:      time.Now(),
 	}
 }
 
@@ -88,9 +96,18 @@ func NewManualDeliveryTask(event Event, destinationID string, attemptNumber int)
 		DestinationID: destinationID,
 		Attempt:       attemptNumber,
 		Manual:        true,
+		QueuedAt:      time.Now(),
 	}
 }
 
+// NewBulkDeliveryTask creates a new DeliveryTask for a manual retry enqueued
+// by a bulk retry job.
+func NewBulkDeliveryTask(event Event, destinationID string, attemptNumber int) DeliveryTask {
+	task := NewManualDeliveryTask(event, destinationID, attemptNumber)
+	task.Bulk = true
+	return task
+}
+
 // LogEntry represents a message for the log queue.
 //
 // IMPORTANT: Both Event and Attempt are REQUIRED. The logstore requires both
@@ -100,6 +117,14 @@ type LogEntry struct {
 	Event       *Event       `json:"event"`
 	Attempt     *Attempt     `json:"attempt"`
 	Destination *Destination `json:"destination,omitempty"` // carried for alert evaluation in logmq; ignored by logstore
+	// AwaitingAck marks a successful attempt the consumer accepted with 202
+	// and has yet to acknowledge. Carried for lifecycle notifications in
+	// logmq; ignored by logstore.
+	AwaitingAck bool `json:"awaiting_ack,omitempty"`
+	// WriteEvent has the logstore write the event of a retry attempt, which
+	// is otherwise assumed written with the first attempt. Set when restoring
+	// archived logs, whose first attempt may be outside the restored range.
+	WriteEvent bool `json:"-"`
 }
 
 var _ mqs.IncomingMessage = &LogEntry{}
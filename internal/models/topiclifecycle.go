@@ -0,0 +1,71 @@
+package models
+
+import (
+	"context"
+	"slices"
+)
+
+// Topic lifecycle states.
+const (
+	// TopicStatusActive topics can be subscribed to and published normally.
+	TopicStatusActive = "active"
+	// TopicStatusDeprecated topics still work, but subscribing to them returns
+	// a warning and their deliveries are flagged as deprecated.
+	TopicStatusDeprecated = "deprecated"
+	// TopicStatusRetired topics reject new subscriptions and publishes.
+	// Existing subscriptions are kept but no longer receive events.
+	TopicStatusRetired = "retired"
+)
+
+// TopicLifecycle holds the deprecated and retired topics of a deployment. The
+// zero value treats every topic as active.
+type TopicLifecycle struct {
+	Deprecated []string
+	Retired    []string
+}
+
+// TopicLifecycleSource returns the current topic lifecycle of a deployment.
+// Satisfied by TopicLifecycle itself, for a lifecycle fixed at startup, and
+// by *topicstore.Store, for one managed at runtime.
+type TopicLifecycleSource interface {
+	Lifecycle(ctx context.Context) TopicLifecycle
+}
+
+// Lifecycle returns l, so a fixed lifecycle is a TopicLifecycleSource.
+func (l TopicLifecycle) Lifecycle(context.Context) TopicLifecycle {
+	return l
+}
+
+// Status returns the lifecycle state of a topic.
+func (l TopicLifecycle) Status(topic string) string {
+	switch {
+	case slices.Contains(l.Retired, topic):
+		return TopicStatusRetired
+	case slices.Contains(l.Deprecated, topic):
+		return TopicStatusDeprecated
+	default:
+		return TopicStatusActive
+	}
+}
+
+// RetiredIn returns the topics of a subscription that are retired. Wildcard
+// entries are not expanded: a pattern covering a retired topic stays valid and
+// simply stops receiving that topic.
+func (l TopicLifecycle) RetiredIn(topics Topics) []string {
+	return l.filter(topics, TopicStatusRetired)
+}
+
+// DeprecatedIn returns the topics of a subscription that are deprecated.
+func (l TopicLifecycle) DeprecatedIn(topics Topics) []string {
+	return l.filter(topics, TopicStatusDeprecated)
+}
+
+func (l TopicLifecycle) filter(topics Topics, status string) []string {
+	var matched []string
+	for _, topic := range topics {
+		if l.Status(topic) == status && !slices.Contains(matched, topic) {
+			matched = append(matched, topic)
+		}
+	}
+	return matched
+}
@@ -0,0 +1,52 @@
+package models
+
+import "strings"
+
+// TopicNamespace is the prefix a deployment applies to the topics of the
+// events it ingests, so products sharing one topic taxonomy keep their events
+// apart in storage, logs and metrics. The configured topics and destination
+// subscriptions stay un-namespaced: the namespace is applied to an event's
+// topic on ingest and stripped to match it against subscriptions. The zero
+// value leaves topics unchanged.
+type TopicNamespace string
+
+func (ns TopicNamespace) prefix() string {
+	return string(ns) + "."
+}
+
+// Apply returns topic in the namespace. Topics already in it, empty topics
+// and the "*" topic are returned unchanged.
+func (ns TopicNamespace) Apply(topic string) string {
+	if ns == "" || topic == "" || topic == "*" || strings.HasPrefix(topic, ns.prefix()) {
+		return topic
+	}
+	return ns.prefix() + topic
+}
+
+// ApplyAll returns topics in the namespace, for filtering stored events by
+// the topics they were published with.
+func (ns TopicNamespace) ApplyAll(topics []string) []string {
+	if ns == "" || len(topics) == 0 {
+		return topics
+	}
+	applied := make([]string, len(topics))
+	for i, topic := range topics {
+		applied[i] = ns.Apply(topic)
+	}
+	return applied
+}
+
+// Strip returns topic without the namespace.
+func (ns TopicNamespace) Strip(topic string) string {
+	if ns == "" {
+		return topic
+	}
+	return strings.TrimPrefix(topic, ns.prefix())
+}
+
+// StripEvent returns a copy of event whose topic is stripped of the
+// namespace, to match against destination subscriptions.
+func (ns TopicNamespace) StripEvent(event Event) Event {
+	event.Topic = ns.Strip(event.Topic)
+	return event
+}
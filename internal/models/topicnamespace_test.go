@@ -0,0 +1,54 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTopicNamespace(t *testing.T) {
+	t.Parallel()
+
+	ns := models.TopicNamespace("acme")
+
+	tests := []struct {
+		name     string
+		topic    string
+		applied  string
+		stripped string
+	}{
+		{name: "bare topic", topic: "order.created", applied: "acme.order.created", stripped: "order.created"},
+		{name: "namespaced topic", topic: "acme.order.created", applied: "acme.order.created", stripped: "order.created"},
+		{name: "other namespace", topic: "acmecorp.order.created", applied: "acme.acmecorp.order.created", stripped: "acmecorp.order.created"},
+		{name: "empty topic", topic: "", applied: "", stripped: ""},
+		{name: "all topics", topic: "*", applied: "*", stripped: "*"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.applied, ns.Apply(tt.topic))
+			assert.Equal(t, tt.stripped, ns.Strip(tt.topic))
+		})
+	}
+
+	t.Run("zero value leaves topics unchanged", func(t *testing.T) {
+		t.Parallel()
+		var none models.TopicNamespace
+		assert.Equal(t, "order.created", none.Apply("order.created"))
+		assert.Equal(t, "acme.order.created", none.Strip("acme.order.created"))
+	})
+
+	t.Run("apply all", func(t *testing.T) {
+		t.Parallel()
+		assert.Equal(t, []string{"acme.order.created", "acme.order.paid"}, ns.ApplyAll([]string{"order.created", "acme.order.paid"}))
+		assert.Nil(t, ns.ApplyAll(nil))
+	})
+
+	t.Run("strip event", func(t *testing.T) {
+		t.Parallel()
+		event := models.Event{ID: "evt_1", Topic: "acme.order.created"}
+		assert.Equal(t, "order.created", ns.StripEvent(event).Topic)
+		assert.Equal(t, "acme.order.created", event.Topic, "the event itself is unchanged")
+	})
+}
@@ -0,0 +1,161 @@
+package models
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/itchyny/gojq"
+)
+
+var (
+	ErrInvalidTransformation = errors.New("validation failed: invalid transformation")
+	ErrTransformationFailed  = errors.New("transformation failed")
+)
+
+// Transformation types.
+const (
+	TransformationJQ = "jq"
+)
+
+const (
+	transformationMaxExpressionBytes = 16 * 1024
+	// transformationTimeout caps a single run, so an expression that loops
+	// cannot hold a delivery worker.
+	transformationTimeout = time.Second
+	// transformationCacheSize bounds the compiled expressions kept in memory.
+	transformationCacheSize = 1024
+)
+
+// transformationVariables are the event fields available to an expression
+// besides the data it transforms.
+var transformationVariables = []string{"$id", "$topic", "$time", "$metadata"}
+
+// Transformation rewrites an event's data before it is delivered to a
+// destination.
+//
+// A jq expression receives the event data as its input, with the event's
+// $id, $topic, $time and $metadata as variables, and must produce exactly one
+// JSON object, which replaces the data. Expressions have no access to the
+// environment, files or the network.
+type Transformation struct {
+	Type       string `json:"type"`
+	Expression string `json:"expression"`
+}
+
+func (t *Transformation) Validate() error {
+	if t == nil {
+		return nil
+	}
+	if t.Type != TransformationJQ {
+		return fmt.Errorf("%w: type must be %s", ErrInvalidTransformation, TransformationJQ)
+	}
+	if t.Expression == "" {
+		return fmt.Errorf("%w: expression is required", ErrInvalidTransformation)
+	}
+	if len(t.Expression) > transformationMaxExpressionBytes {
+		return fmt.Errorf("%w: expression must be at most %d bytes", ErrInvalidTransformation, transformationMaxExpressionBytes)
+	}
+	if _, err := compileTransformation(t.Expression); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidTransformation, err)
+	}
+	return nil
+}
+
+// compiledTransformations caches compiled expressions by source. They are
+// compiled when a transformation is validated or loaded from the store, so
+// deliveries, which load their destination every time, run the cached code.
+// A compiled expression is safe to run concurrently.
+var compiledTransformations = struct {
+	sync.Mutex
+	codes map[string]*gojq.Code
+}{codes: map[string]*gojq.Code{}}
+
+func compileTransformation(expression string) (*gojq.Code, error) {
+	compiledTransformations.Lock()
+	code, ok := compiledTransformations.codes[expression]
+	compiledTransformations.Unlock()
+	if ok {
+		return code, nil
+	}
+
+	query, err := gojq.Parse(expression)
+	if err != nil {
+		return nil, err
+	}
+	code, err = gojq.Compile(query, gojq.WithVariables(transformationVariables))
+	if err != nil {
+		return nil, err
+	}
+
+	compiledTransformations.Lock()
+	defer compiledTransformations.Unlock()
+	if len(compiledTransformations.codes) >= transformationCacheSize {
+		clear(compiledTransformations.codes)
+	}
+	compiledTransformations.codes[expression] = code
+	return code, nil
+}
+
+// Apply returns a copy of the event with its data rewritten by the
+// transformation. A nil transformation returns the event unchanged.
+func (t *Transformation) Apply(ctx context.Context, event *Event) (*Event, error) {
+	if t == nil {
+		return event, nil
+	}
+	code, err := compileTransformation(t.Expression)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTransformationFailed, err)
+	}
+
+	var input any = map[string]any{}
+	if len(event.Data) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(event.Data))
+		decoder.UseNumber()
+		if err := decoder.Decode(&input); err != nil {
+			return nil, fmt.Errorf("%w: invalid event data: %w", ErrTransformationFailed, err)
+		}
+	}
+	metadata := map[string]any{}
+	for key, value := range event.Metadata {
+		metadata[key] = value
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, transformationTimeout)
+	defer cancel()
+	iter := code.RunWithContext(ctx, input, event.ID, event.Topic, event.Time.Format(time.RFC3339Nano), metadata)
+
+	var output any
+	count := 0
+	for {
+		value, ok := iter.Next()
+		if !ok {
+			break
+		}
+		if err, ok := value.(error); ok {
+			return nil, fmt.Errorf("%w: %w", ErrTransformationFailed, err)
+		}
+		output = value
+		if count++; count > 1 {
+			return nil, fmt.Errorf("%w: expression produced more than one value", ErrTransformationFailed)
+		}
+	}
+	if count == 0 {
+		return nil, fmt.Errorf("%w: expression produced no value", ErrTransformationFailed)
+	}
+	if _, ok := output.(map[string]any); !ok {
+		return nil, fmt.Errorf("%w: expression must produce a JSON object", ErrTransformationFailed)
+	}
+	data, err := json.Marshal(output)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrTransformationFailed, err)
+	}
+
+	transformed := *event
+	transformed.Data = data
+	return &transformed, nil
+}
@@ -0,0 +1,110 @@
+package models_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransformation_Validate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name           string
+		transformation *models.Transformation
+		wantErr        bool
+	}{
+		{name: "nil", transformation: nil},
+		{name: "valid jq", transformation: &models.Transformation{Type: "jq", Expression: `{id: $id, name: .user.name}`}},
+		{name: "unknown type", transformation: &models.Transformation{Type: "js", Expression: "."}, wantErr: true},
+		{name: "empty expression", transformation: &models.Transformation{Type: "jq"}, wantErr: true},
+		{name: "syntax error", transformation: &models.Transformation{Type: "jq", Expression: "{id:"}, wantErr: true},
+		{name: "undefined variable", transformation: &models.Transformation{Type: "jq", Expression: "$secret"}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := tt.transformation.Validate()
+			if tt.wantErr {
+				assert.ErrorIs(t, err, models.ErrInvalidTransformation)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestTransformation_Apply(t *testing.T) {
+	t.Parallel()
+
+	event := &models.Event{
+		ID:       "evt_1",
+		Topic:    "user.created",
+		Time:     time.Date(2026, 3, 14, 12, 0, 0, 0, time.UTC),
+		Metadata: models.Metadata{"source": "signup"},
+		Data:     []byte(`{"user":{"id":9007199254740993,"name":"Ada"},"items":[1,2]}`),
+	}
+
+	apply := func(expression string) (*models.Event, error) {
+		transformation := &models.Transformation{Type: models.TransformationJQ, Expression: expression}
+		return transformation.Apply(context.Background(), event)
+	}
+
+	t.Run("nil transformation returns the event", func(t *testing.T) {
+		t.Parallel()
+		var transformation *models.Transformation
+		got, err := transformation.Apply(context.Background(), event)
+		require.NoError(t, err)
+		assert.Same(t, event, got)
+	})
+
+	t.Run("rewrites data with event variables", func(t *testing.T) {
+		t.Parallel()
+		got, err := apply(`{event_id: $id, type: $topic, at: $time, source: $metadata.source, user_id: .user.id}`)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"event_id":"evt_1","type":"user.created","at":"2026-03-14T12:00:00Z","source":"signup","user_id":9007199254740993}`, string(got.Data))
+		assert.Equal(t, "evt_1", got.ID)
+		assert.JSONEq(t, `{"user":{"id":9007199254740993,"name":"Ada"},"items":[1,2]}`, string(event.Data), "the original event is not modified")
+	})
+
+	t.Run("rejects non-object output", func(t *testing.T) {
+		t.Parallel()
+		_, err := apply(`.user.name`)
+		assert.ErrorIs(t, err, models.ErrTransformationFailed)
+	})
+
+	t.Run("rejects multiple outputs", func(t *testing.T) {
+		t.Parallel()
+		_, err := apply(`.items[] | {item: .}`)
+		assert.ErrorIs(t, err, models.ErrTransformationFailed)
+	})
+
+	t.Run("rejects empty output", func(t *testing.T) {
+		t.Parallel()
+		_, err := apply(`empty`)
+		assert.ErrorIs(t, err, models.ErrTransformationFailed)
+	})
+
+	t.Run("reports runtime errors", func(t *testing.T) {
+		t.Parallel()
+		_, err := apply(`error("bad input")`)
+		assert.ErrorIs(t, err, models.ErrTransformationFailed)
+	})
+
+	t.Run("has no access to the environment", func(t *testing.T) {
+		t.Parallel()
+		got, err := apply(`{env: $ENV}`)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"env":{}}`, string(got.Data))
+	})
+
+	t.Run("stops expressions that do not terminate", func(t *testing.T) {
+		t.Parallel()
+		_, err := apply(`{n: last(range(infinite))}`)
+		assert.ErrorIs(t, err, models.ErrTransformationFailed)
+	})
+}
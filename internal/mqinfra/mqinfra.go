@@ -16,6 +16,7 @@ type MQInfraConfig struct {
 	AzureServiceBus *AzureServiceBusInfraConfig
 	GCPPubSub       *GCPPubSubInfraConfig
 	RabbitMQ        *RabbitMQInfraConfig
+	NATSJetStream   *NATSJetStreamInfraConfig
 
 	Policy Policy
 }
@@ -60,6 +61,14 @@ type RabbitMQInfraConfig struct {
 	Queue     string
 }
 
+type NATSJetStreamInfraConfig struct {
+	ServerURL       string
+	CredentialsFile string
+	Stream          string
+	Subject         string
+	Consumer        string
+}
+
 func New(cfg *MQInfraConfig) MQInfra {
 	if cfg.AWSSQS != nil {
 		return &infraAWSSQS{cfg: cfg}
@@ -73,6 +82,9 @@ func New(cfg *MQInfraConfig) MQInfra {
 	if cfg.RabbitMQ != nil {
 		return &infraRabbitMQ{cfg: cfg}
 	}
+	if cfg.NATSJetStream != nil {
+		return &infraNATSJetStream{cfg: cfg}
+	}
 
 	return &infraInvalid{}
 }
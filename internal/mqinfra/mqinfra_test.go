@@ -493,3 +493,90 @@ func TestIntegrationMQInfra_AzureServiceBus(t *testing.T) {
 		},
 	)
 }
+
+// JetStream has no dead-letter queue, so this checks that a nacked message is
+// delivered RetryLimit+1 times and then no more.
+func TestIntegrationMQInfra_NATSJetStream(t *testing.T) {
+	testutil.CheckIntegrationTest(t)
+	t.Parallel()
+	t.Cleanup(testinfra.Start(t))
+
+	name := "test-" + idgen.String()
+	infraCfg := mqinfra.MQInfraConfig{
+		NATSJetStream: &mqinfra.NATSJetStreamInfraConfig{
+			ServerURL: testinfra.EnsureNATS(),
+			Stream:    name,
+			Subject:   name,
+			Consumer:  name,
+		},
+		Policy: mqinfra.Policy{
+			RetryLimit: retryLimit,
+		},
+	}
+	mqCfg := mqs.QueueConfig{
+		NATSJetStream: &mqs.NATSJetStreamConfig{
+			ServerURL: testinfra.EnsureNATS(),
+			Stream:    name,
+			Subject:   name,
+			Consumer:  name,
+		},
+	}
+
+	ctx := context.Background()
+	infra := mqinfra.New(&infraCfg)
+	exists, err := infra.Exist(ctx)
+	require.NoError(t, err)
+	require.False(t, exists)
+	require.NoError(t, infra.Declare(ctx))
+	t.Cleanup(func() {
+		require.NoError(t, infra.TearDown(ctx))
+	})
+	exists, err = infra.Exist(ctx)
+	require.NoError(t, err)
+	require.True(t, exists)
+
+	mq := mqs.NewQueue(&mqCfg)
+	cleanup, err := mq.Init(ctx)
+	require.NoError(t, err)
+	t.Cleanup(cleanup)
+	subscription, err := mq.Subscribe(ctx)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		subscription.Shutdown(ctx)
+	})
+
+	msgchan := make(chan *testutil.MockMsg)
+	go func() {
+		for {
+			msg, err := subscription.Receive(ctx)
+			if err != nil {
+				log.Println(err)
+				return
+			}
+			msg.Nack()
+			mockMsg := &testutil.MockMsg{}
+			if err := mockMsg.FromMessage(msg); err != nil {
+				log.Println("Error parsing message", err)
+			} else {
+				msgchan <- mockMsg
+			}
+		}
+	}()
+
+	msg := &testutil.MockMsg{ID: idgen.String()}
+	require.NoError(t, mq.Publish(ctx, msg))
+
+	for i := 0; i < retryLimit+1; i++ {
+		select {
+		case received := <-msgchan:
+			assert.Equal(t, msg.ID, received.ID)
+		case <-time.After(10 * time.Second):
+			require.FailNow(t, "timeout waiting for message", "delivery %d", i+1)
+		}
+	}
+	select {
+	case <-msgchan:
+		require.Fail(t, "message delivered past the retry limit")
+	case <-time.After(2 * time.Second):
+	}
+}
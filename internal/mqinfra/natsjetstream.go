@@ -0,0 +1,117 @@
+package mqinfra
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+type infraNATSJetStream struct {
+	cfg *MQInfraConfig
+}
+
+func (infra *infraNATSJetStream) Exist(ctx context.Context) (bool, error) {
+	if infra.cfg == nil || infra.cfg.NATSJetStream == nil {
+		return false, errors.New("failed assertion: cfg.NATSJetStream != nil") // IMPOSSIBLE
+	}
+
+	conn, js, err := infra.connect()
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	if _, err := js.Stream(ctx, infra.cfg.NATSJetStream.Stream); err != nil {
+		if errors.Is(err, jetstream.ErrStreamNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	if _, err := js.Consumer(ctx, infra.cfg.NATSJetStream.Stream, infra.cfg.NATSJetStream.Consumer); err != nil {
+		if errors.Is(err, jetstream.ErrConsumerNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	return true, nil
+}
+
+// Declare creates a work queue stream on the subject and a durable consumer
+// of it. JetStream has no dead-letter queue: a message delivered RetryLimit+1
+// times without being acked is no longer delivered and stays in the stream,
+// where it can be inspected.
+func (infra *infraNATSJetStream) Declare(ctx context.Context) error {
+	if infra.cfg == nil || infra.cfg.NATSJetStream == nil {
+		return errors.New("failed assertion: cfg.NATSJetStream != nil") // IMPOSSIBLE
+	}
+
+	conn, js, err := infra.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := js.CreateOrUpdateStream(ctx, jetstream.StreamConfig{
+		Name:      infra.cfg.NATSJetStream.Stream,
+		Subjects:  []string{infra.cfg.NATSJetStream.Subject},
+		Retention: jetstream.WorkQueuePolicy,
+		Storage:   jetstream.FileStorage,
+	}); err != nil {
+		return err
+	}
+
+	consumerConfig := jetstream.ConsumerConfig{
+		Durable:       infra.cfg.NATSJetStream.Consumer,
+		FilterSubject: infra.cfg.NATSJetStream.Subject,
+		AckPolicy:     jetstream.AckExplicitPolicy,
+		MaxDeliver:    infra.cfg.Policy.RetryLimit + 1,
+	}
+	if infra.cfg.Policy.VisibilityTimeout > 0 {
+		consumerConfig.AckWait = time.Duration(infra.cfg.Policy.VisibilityTimeout) * time.Second
+	}
+	if _, err := js.CreateOrUpdateConsumer(ctx, infra.cfg.NATSJetStream.Stream, consumerConfig); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// TearDown deletes the stream, along with its consumer and messages.
+func (infra *infraNATSJetStream) TearDown(ctx context.Context) error {
+	if infra.cfg == nil || infra.cfg.NATSJetStream == nil {
+		return errors.New("failed assertion: cfg.NATSJetStream != nil") // IMPOSSIBLE
+	}
+
+	conn, js, err := infra.connect()
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if err := js.DeleteStream(ctx, infra.cfg.NATSJetStream.Stream); err != nil && !errors.Is(err, jetstream.ErrStreamNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+func (infra *infraNATSJetStream) connect() (*nats.Conn, jetstream.JetStream, error) {
+	var opts []nats.Option
+	if infra.cfg.NATSJetStream.CredentialsFile != "" {
+		opts = append(opts, nats.UserCredentials(infra.cfg.NATSJetStream.CredentialsFile))
+	}
+	conn, err := nats.Connect(infra.cfg.NATSJetStream.ServerURL, opts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+	return conn, js, nil
+}
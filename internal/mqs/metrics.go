@@ -0,0 +1,151 @@
+package mqs
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/metric/noop"
+)
+
+const (
+	systemAWSSQS          = "awssqs"
+	systemAzureServiceBus = "azureservicebus"
+	systemGCPPubSub       = "gcppubsub"
+	systemRabbitMQ        = "rabbitmq"
+	systemKafka           = "kafka"
+	systemNATSJetStream   = "natsjetstream"
+	systemInMemory        = "inmemory"
+)
+
+// queueMetrics records the operations of a queue backend, labelled with its
+// messaging system. Nacked messages are redelivered by the broker, so the
+// nack count is the retry count on the consuming side.
+type queueMetrics struct {
+	*queueInstruments
+	system attribute.KeyValue
+}
+
+type queueInstruments struct {
+	publishDuration metric.Float64Histogram
+	publishRetries  metric.Int64Counter
+	received        metric.Int64Counter
+	settled         metric.Int64Counter
+	extensions      metric.Int64Counter
+	expired         metric.Int64Counter
+}
+
+// instruments are shared by every queue, as OTel instruments are identified
+// by name.
+var instruments = sync.OnceValue(func() *queueInstruments {
+	i, err := newQueueInstruments(otel.Meter("outpost"))
+	if err != nil {
+		otel.Handle(err)
+		i, _ = newQueueInstruments(noop.NewMeterProvider().Meter("outpost"))
+	}
+	return i
+})
+
+func newQueueInstruments(meter metric.Meter) (*queueInstruments, error) {
+	i := &queueInstruments{}
+	var err error
+	if i.publishDuration, err = meter.Float64Histogram("outpost.mq.publish.duration",
+		metric.WithUnit("ms"),
+		metric.WithDescription("Message queue publish latency"),
+	); err != nil {
+		return nil, err
+	}
+
+	if i.publishRetries, err = meter.Int64Counter("outpost.mq.publish.retries",
+		metric.WithDescription("Number of publishes retried after a lost connection"),
+	); err != nil {
+		return nil, err
+	}
+
+	if i.received, err = meter.Int64Counter("outpost.mq.received_messages",
+		metric.WithDescription("Number of messages received from the message queue"),
+	); err != nil {
+		return nil, err
+	}
+
+	if i.settled, err = meter.Int64Counter("outpost.mq.settled_messages",
+		metric.WithDescription("Number of acked and nacked messages"),
+	); err != nil {
+		return nil, err
+	}
+
+	if i.extensions, err = meter.Int64Counter("outpost.mq.visibility_extensions",
+		metric.WithDescription("Number of visibility timeout extensions of messages still being handled"),
+	); err != nil {
+		return nil, err
+	}
+
+	if i.expired, err = meter.Int64Counter("outpost.mq.visibility_extension_expired",
+		metric.WithDescription("Number of messages held past the max visibility extension, left to be redelivered"),
+	); err != nil {
+		return nil, err
+	}
+	return i, nil
+}
+
+func newQueueMetrics(system string) *queueMetrics {
+	return &queueMetrics{
+		queueInstruments: instruments(),
+		system:           attribute.String("system", system),
+	}
+}
+
+func (m *queueMetrics) Published(ctx context.Context, duration time.Duration, err error) {
+	m.publishDuration.Record(ctx, float64(duration)/float64(time.Millisecond), metric.WithAttributes(m.system, statusAttr(err)))
+}
+
+func (m *queueMetrics) PublishRetried(ctx context.Context) {
+	m.publishRetries.Add(ctx, 1, metric.WithAttributes(m.system))
+}
+
+func (m *queueMetrics) Received(ctx context.Context, err error) {
+	m.received.Add(ctx, 1, metric.WithAttributes(m.system, statusAttr(err)))
+}
+
+func (m *queueMetrics) VisibilityExtended(ctx context.Context, err error) {
+	m.extensions.Add(ctx, 1, metric.WithAttributes(m.system, statusAttr(err)))
+}
+
+func (m *queueMetrics) VisibilityExtensionExpired(ctx context.Context) {
+	m.expired.Add(ctx, 1, metric.WithAttributes(m.system))
+}
+
+// wrap returns msg with Ack and Nack counted.
+func (m *queueMetrics) wrap(msg *Message) *Message {
+	msg.QueueMessage = &meteredQueueMessage{QueueMessage: msg.QueueMessage, metrics: m}
+	return msg
+}
+
+func (m *queueMetrics) settle(result string) {
+	m.settled.Add(context.Background(), 1, metric.WithAttributes(m.system, attribute.String("result", result)))
+}
+
+func statusAttr(err error) attribute.KeyValue {
+	if err != nil {
+		return attribute.String("status", "error")
+	}
+	return attribute.String("status", "ok")
+}
+
+type meteredQueueMessage struct {
+	QueueMessage
+	metrics *queueMetrics
+}
+
+func (m *meteredQueueMessage) Ack() {
+	m.QueueMessage.Ack()
+	m.metrics.settle("ack")
+}
+
+func (m *meteredQueueMessage) Nack() {
+	m.QueueMessage.Nack()
+	m.metrics.settle("nack")
+}
@@ -0,0 +1,78 @@
+package mqs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+type testIncomingMessage struct {
+	body string
+}
+
+func (m *testIncomingMessage) ToMessage() (*Message, error) {
+	return &Message{Body: []byte(m.body)}, nil
+}
+
+func (m *testIncomingMessage) FromMessage(msg *Message) error {
+	m.body = string(msg.Body)
+	return nil
+}
+
+func TestQueueMetrics(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+	i, err := newQueueInstruments(provider.Meter("test"))
+	require.NoError(t, err)
+
+	queue := NewInMemoryQueue(&InMemoryConfig{Name: "metrics"})
+	queue.base.metrics = &queueMetrics{queueInstruments: i, system: attribute.String("system", systemInMemory)}
+	cleanup, err := queue.Init(ctx)
+	require.NoError(t, err)
+	defer cleanup()
+
+	subscription, err := queue.Subscribe(ctx)
+	require.NoError(t, err)
+	defer subscription.Shutdown(ctx)
+
+	require.NoError(t, queue.Publish(ctx, &testIncomingMessage{body: "first"}))
+	require.NoError(t, queue.Publish(ctx, &testIncomingMessage{body: "second"}))
+	msg, err := subscription.Receive(ctx)
+	require.NoError(t, err)
+	msg.Ack()
+	msg, err = subscription.Receive(ctx)
+	require.NoError(t, err)
+	msg.Nack()
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	metrics := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	publishes := metrics["outpost.mq.publish.duration"].(metricdata.Histogram[float64]).DataPoints
+	require.Len(t, publishes, 1)
+	assert.Equal(t, uint64(2), publishes[0].Count)
+	system, _ := publishes[0].Attributes.Value("system")
+	assert.Equal(t, systemInMemory, system.AsString())
+
+	received := metrics["outpost.mq.received_messages"].(metricdata.Sum[int64]).DataPoints
+	require.Len(t, received, 1)
+	assert.Equal(t, int64(2), received[0].Value)
+
+	settled := map[string]int64{}
+	for _, dp := range metrics["outpost.mq.settled_messages"].(metricdata.Sum[int64]).DataPoints {
+		result, _ := dp.Attributes.Value("result")
+		settled[result.AsString()] += dp.Value
+	}
+	assert.Equal(t, map[string]int64{"ack": 1, "nack": 1}, settled)
+}
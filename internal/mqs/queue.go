@@ -17,9 +17,15 @@ type QueueConfig struct {
 	AzureServiceBus *AzureServiceBusConfig
 	GCPPubSub       *GCPPubSubConfig
 	RabbitMQ        *RabbitMQConfig
+	Kafka           *KafkaConfig
+	NATSJetStream   *NATSJetStreamConfig
 	InMemory        *InMemoryConfig // mainly for testing purposes
 
 	VisibilityTimeout time.Duration
+	// MaxVisibilityExtension is how long SQS and GCP Pub/Sub messages still
+	// being handled are kept invisible past VisibilityTimeout. 0 disables
+	// extension.
+	MaxVisibilityExtension time.Duration
 }
 
 type InMemoryConfig struct {
@@ -80,6 +86,13 @@ type Message struct {
 	QueueMessage
 	LoggableID string
 	Body       []byte
+
+	// Topic, Key and Headers are the record's metadata on brokers that
+	// carry it, such as Kafka and NATS JetStream. They are empty for other
+	// brokers.
+	Topic   string
+	Key     string
+	Headers map[string]string
 }
 
 func NewQueue(config *QueueConfig) Queue {
@@ -87,13 +100,17 @@ func NewQueue(config *QueueConfig) Queue {
 		return NewInMemoryQueue(nil)
 	}
 	if config.AWSSQS != nil {
-		return NewAWSQueue(config.AWSSQS)
+		return NewAWSQueue(config.AWSSQS, config.VisibilityTimeout, config.MaxVisibilityExtension)
 	} else if config.AzureServiceBus != nil {
 		return NewAzureServiceBusQueue(config.AzureServiceBus)
 	} else if config.GCPPubSub != nil {
-		return NewGCPPubSubQueue(config.GCPPubSub, config.VisibilityTimeout)
+		return NewGCPPubSubQueue(config.GCPPubSub, config.VisibilityTimeout, config.MaxVisibilityExtension)
 	} else if config.RabbitMQ != nil {
 		return NewRabbitMQQueue(config.RabbitMQ)
+	} else if config.Kafka != nil {
+		return NewKafkaQueue(config.Kafka)
+	} else if config.NATSJetStream != nil {
+		return NewNATSJetStreamQueue(config.NATSJetStream, config.VisibilityTimeout, config.MaxVisibilityExtension)
 	} else {
 		return NewInMemoryQueue(config.InMemory)
 	}
@@ -154,7 +171,7 @@ func NewInMemoryQueue(config *InMemoryConfig) *InMemoryQueue {
 		name = config.Name
 	}
 	return &InMemoryQueue{
-		base:      newWrappedBaseQueue(),
+		base:      newWrappedBaseQueue(systemInMemory),
 		topicName: "mem://queue" + name,
 	}
 }
@@ -163,40 +180,49 @@ func NewInMemoryQueue(config *InMemoryConfig) *InMemoryQueue {
 
 type WrappedSubscription struct {
 	subscription *pubsub.Subscription
+	metrics      *queueMetrics
+	extender     *visibilityExtender
+	extend       func(msg *pubsub.Message) extendFunc
 }
 
 var _ Subscription = &WrappedSubscription{}
 
 func (s *WrappedSubscription) Receive(ctx context.Context) (*Message, error) {
 	msg, err := s.subscription.Receive(ctx)
+	s.metrics.Received(ctx, err)
 	if err != nil {
 		return nil, err
 	}
-	return &Message{
+	m := &Message{
 		QueueMessage: msg,
 		LoggableID:   msg.LoggableID,
 		Body:         msg.Body,
-	}, nil
+	}
+	if s.extender != nil {
+		m = s.extender.wrap(m, s.extend(msg))
+	}
+	return s.metrics.wrap(m), nil
 }
 
 func (s *WrappedSubscription) Shutdown(ctx context.Context) error {
 	return s.subscription.Shutdown(ctx)
 }
 
-func wrappedSubscription(subscription *pubsub.Subscription) (Subscription, error) {
-	return &WrappedSubscription{subscription: subscription}, nil
+func wrappedSubscription(subscription *pubsub.Subscription, metrics *queueMetrics) (Subscription, error) {
+	return &WrappedSubscription{subscription: subscription, metrics: metrics}, nil
 }
 
 // ============================== Base Queue Impl ==============================
 
 type wrappedBaseQueue struct {
-	once   *sync.Once
-	tracer trace.Tracer
+	once    *sync.Once
+	tracer  trace.Tracer
+	metrics *queueMetrics
 }
 
-func newWrappedBaseQueue() *wrappedBaseQueue {
+func newWrappedBaseQueue(system string) *wrappedBaseQueue {
 	var once sync.Once
-	return &wrappedBaseQueue{once: &once}
+	return &wrappedBaseQueue{once: &once, metrics: newQueueMetrics(system)}
 }
 
 func (q *wrappedBaseQueue) initTracer() {
@@ -213,7 +239,9 @@ func (q *wrappedBaseQueue) Publish(ctx context.Context, topic *pubsub.Topic, inc
 		span.RecordError(err)
 		return err
 	}
+	start := time.Now()
 	err = topic.Send(ctx, &pubsub.Message{Body: msg.Body, Metadata: metadata})
+	q.metrics.Published(ctx, time.Since(start), err)
 	if err != nil {
 		span.RecordError(err)
 		return err
@@ -222,5 +250,5 @@ func (q *wrappedBaseQueue) Publish(ctx context.Context, topic *pubsub.Topic, inc
 }
 
 func (q *wrappedBaseQueue) Subscribe(ctx context.Context, subscription *pubsub.Subscription) (Subscription, error) {
-	return wrappedSubscription(subscription)
+	return wrappedSubscription(subscription, q.metrics)
 }
@@ -10,6 +10,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	sqstypes "github.com/aws/aws-sdk-go-v2/service/sqs/types"
 	"github.com/aws/aws-sdk-go/aws"
 	"gocloud.dev/pubsub"
 	"gocloud.dev/pubsub/awssnssqs"
@@ -46,19 +47,27 @@ func (c *AWSSQSConfig) ToCredentials() (*credentials.StaticCredentialsProvider,
 }
 
 type AWSQueue struct {
-	once        *sync.Once
-	base        *wrappedBaseQueue
-	sqsQueueURL string
-	sqsClient   *sqs.Client
-	config      *AWSSQSConfig
-	topic       *pubsub.Topic
+	once                   *sync.Once
+	base                   *wrappedBaseQueue
+	sqsQueueURL            string
+	sqsClient              *sqs.Client
+	config                 *AWSSQSConfig
+	visibilityTimeout      time.Duration
+	maxVisibilityExtension time.Duration
+	topic                  *pubsub.Topic
 }
 
 var _ Queue = &AWSQueue{}
 
-func NewAWSQueue(config *AWSSQSConfig) *AWSQueue {
+func NewAWSQueue(config *AWSSQSConfig, visibilityTimeout, maxVisibilityExtension time.Duration) *AWSQueue {
 	var once sync.Once
-	return &AWSQueue{config: config, once: &once, base: newWrappedBaseQueue()}
+	return &AWSQueue{
+		config:                 config,
+		visibilityTimeout:      visibilityTimeout,
+		maxVisibilityExtension: maxVisibilityExtension,
+		once:                   &once,
+		base:                   newWrappedBaseQueue(systemAWSSQS),
+	}
 }
 
 func (q *AWSQueue) Init(ctx context.Context) (func(), error) {
@@ -98,7 +107,29 @@ func (q *AWSQueue) Subscribe(ctx context.Context, opts ...SubscribeOption) (Subs
 	subscription := awssnssqs.OpenSubscriptionV2(ctx, q.sqsClient, q.sqsQueueURL, &awssnssqs.SubscriptionOptions{
 		WaitTime: waitTime,
 	})
-	return q.base.Subscribe(ctx, subscription)
+	return &WrappedSubscription{
+		subscription: subscription,
+		metrics:      q.base.metrics,
+		extender:     newVisibilityExtender(q.visibilityTimeout, q.maxVisibilityExtension, q.base.metrics),
+		extend:       q.extendVisibility,
+	}, nil
+}
+
+// extendVisibility returns a func changing the visibility timeout of msg, or
+// nil when msg has no receipt handle.
+func (q *AWSQueue) extendVisibility(msg *pubsub.Message) extendFunc {
+	var sqsMsg sqstypes.Message
+	if !msg.As(&sqsMsg) || sqsMsg.ReceiptHandle == nil {
+		return nil
+	}
+	return func(ctx context.Context, timeout time.Duration) error {
+		_, err := q.sqsClient.ChangeMessageVisibility(ctx, &sqs.ChangeMessageVisibilityInput{
+			QueueUrl:          aws.String(q.sqsQueueURL),
+			ReceiptHandle:     sqsMsg.ReceiptHandle,
+			VisibilityTimeout: int32(timeout / time.Second),
+		})
+		return err
+	}
 }
 
 func (q *AWSQueue) InitSDK(ctx context.Context) error {
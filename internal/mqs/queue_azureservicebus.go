@@ -147,6 +147,6 @@ func NewAzureServiceBusQueue(config *AzureServiceBusConfig) *AzureServiceBusQueu
 	return &AzureServiceBusQueue{
 		config: config,
 		once:   &once,
-		base:   newWrappedBaseQueue(),
+		base:   newWrappedBaseQueue(systemAzureServiceBus),
 	}
 }
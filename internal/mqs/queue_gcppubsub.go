@@ -23,24 +23,26 @@ type GCPPubSubConfig struct {
 }
 
 type GCPPubSubQueue struct {
-	once              *sync.Once
-	base              *wrappedBaseQueue
-	config            *GCPPubSubConfig
-	visibilityTimeout time.Duration
-	topic             *pubsub.Topic
-	cleanupFns        []func()
+	once                   *sync.Once
+	base                   *wrappedBaseQueue
+	config                 *GCPPubSubConfig
+	visibilityTimeout      time.Duration
+	maxVisibilityExtension time.Duration
+	topic                  *pubsub.Topic
+	cleanupFns             []func()
 }
 
 var _ Queue = &GCPPubSubQueue{}
 
-func NewGCPPubSubQueue(config *GCPPubSubConfig, visibilityTimeout time.Duration) *GCPPubSubQueue {
+func NewGCPPubSubQueue(config *GCPPubSubConfig, visibilityTimeout, maxVisibilityExtension time.Duration) *GCPPubSubQueue {
 	var once sync.Once
 	return &GCPPubSubQueue{
-		config:            config,
-		visibilityTimeout: visibilityTimeout,
-		once:              &once,
-		base:              newWrappedBaseQueue(),
-		cleanupFns:        []func(){},
+		config:                 config,
+		visibilityTimeout:      visibilityTimeout,
+		maxVisibilityExtension: maxVisibilityExtension,
+		once:                   &once,
+		base:                   newWrappedBaseQueue(systemGCPPubSub),
+		cleanupFns:             []func(){},
 	}
 }
 
@@ -149,11 +151,18 @@ func (q *GCPPubSubQueue) Subscribe(ctx context.Context, opts ...SubscribeOption)
 	// control explicit; scaling is done at the subscription level, not via
 	// additional goroutines within a subscription.
 	sub.ReceiveSettings.NumGoroutines = 1
-	// Disable automatic lease extension so messages are not held beyond the
-	// subscription's ack deadline. We are intentional about consumer processing
-	// logic and do not want the SDK silently extending message leases — if a
-	// handler exceeds the ack deadline, the message should be redelivered.
+	// The SDK extends the lease of messages still being handled, by up to the
+	// visibility timeout at a time, until they have been held for
+	// maxVisibilityExtension; a handler holding a message longer is treated as
+	// stuck and the message is redelivered. Without a max extension, automatic
+	// lease extension is disabled and a handler exceeding the ack deadline has
+	// its message redelivered.
 	sub.ReceiveSettings.MaxExtension = -1 * time.Second
+	extender := newVisibilityExtender(q.visibilityTimeout, q.maxVisibilityExtension, q.base.metrics)
+	if extender != nil {
+		sub.ReceiveSettings.MaxExtension = q.maxVisibilityExtension
+		sub.ReceiveSettings.MaxExtensionPeriod = q.visibilityTimeout
+	}
 	// The native SDK sends a "receipt modack" (ModifyAckDeadline) when it first
 	// receives a message, using its internal ack-latency p99 as the deadline
 	// (minimum 10s). This overrides the subscription's ackDeadlineSeconds on the
@@ -168,10 +177,12 @@ func (q *GCPPubSubQueue) Subscribe(ctx context.Context, opts ...SubscribeOption)
 	done := make(chan struct{})
 
 	s := &gcpNativeSubscription{
-		msgChan: msgChan,
-		cancel:  cancel,
-		done:    done,
-		client:  client,
+		msgChan:  msgChan,
+		cancel:   cancel,
+		done:     done,
+		client:   client,
+		metrics:  q.base.metrics,
+		extender: extender,
 	}
 
 	go func() {
@@ -199,11 +210,13 @@ func (q *GCPPubSubQueue) Subscribe(ctx context.Context, opts ...SubscribeOption)
 
 // gcpNativeSubscription bridges the native SDK StreamingPull to the mqs.Subscription interface.
 type gcpNativeSubscription struct {
-	msgChan <-chan *Message
-	cancel  context.CancelFunc
-	done    chan struct{}
-	client  *nativepubsub.Client
-	recvErr error // set by the background goroutine when sub.Receive exits
+	msgChan  <-chan *Message
+	cancel   context.CancelFunc
+	done     chan struct{}
+	client   *nativepubsub.Client
+	metrics  *queueMetrics
+	extender *visibilityExtender // only counts expired messages; the SDK extends leases
+	recvErr  error               // set by the background goroutine when sub.Receive exits
 }
 
 var _ Subscription = &gcpNativeSubscription{}
@@ -213,12 +226,18 @@ func (s *gcpNativeSubscription) Receive(ctx context.Context) (*Message, error) {
 	select {
 	case msg, ok := <-s.msgChan:
 		if !ok {
+			err := fmt.Errorf("subscription closed")
 			if s.recvErr != nil {
-				return nil, fmt.Errorf("subscription closed: %w", s.recvErr)
+				err = fmt.Errorf("subscription closed: %w", s.recvErr)
 			}
-			return nil, fmt.Errorf("subscription closed")
+			s.metrics.Received(ctx, err)
+			return nil, err
+		}
+		s.metrics.Received(ctx, nil)
+		if s.extender != nil {
+			msg = s.extender.wrap(msg, nil)
 		}
-		return msg, nil
+		return s.metrics.wrap(msg), nil
 	case <-ctx.Done():
 		return nil, ctx.Err()
 	}
@@ -0,0 +1,425 @@
+package mqs
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+)
+
+const (
+	kafkaRedeliveryDelay          = time.Second
+	kafkaMaxRedeliveryDelay       = time.Minute
+	kafkaDeadLetterTimeout        = 10 * time.Second
+	defaultKafkaMaxRedeliveries   = 5
+	kafkaSASLMechanismPlain       = "plain"
+	kafkaSASLMechanismSCRAMSHA256 = "scram-sha-256"
+	kafkaSASLMechanismSCRAMSHA512 = "scram-sha-512"
+)
+
+type KafkaConfig struct {
+	Brokers []string
+	Topics  []string
+	GroupID string
+
+	// SASLMechanism is "plain", "scram-sha-256" or "scram-sha-512". Empty
+	// disables SASL authentication.
+	SASLMechanism string
+	Username      string
+	Password      string
+	TLS           bool
+
+	// DeadLetterTopic receives the messages still nacked after
+	// MaxRedeliveries redeliveries, so their partition can move on. Kafka
+	// can't return a single message to the topic, so without one a message
+	// that always fails is redelivered, with backoff, until it succeeds and
+	// holds back the committed offset of its partition meanwhile.
+	DeadLetterTopic string
+	// MaxRedeliveries is how many times a nacked message is redelivered
+	// before it is written to the dead-letter topic. It is counted by each
+	// consumer, so it starts over when the message moves to another one.
+	// 0 uses the default of 5.
+	MaxRedeliveries int
+}
+
+// KafkaQueue consumes Kafka topics through a consumer group. Kafka only
+// tracks a committed offset per partition, so messages are acked by
+// committing the offset below which every received message of the partition
+// is settled, and nacked messages are redelivered by the subscription itself.
+// Messages still unsettled when a consumer stops are received again by the
+// group, so delivery is at least once.
+type KafkaQueue struct {
+	base   *wrappedBaseQueue
+	config *KafkaConfig
+	writer *kafka.Writer
+}
+
+var _ Queue = &KafkaQueue{}
+
+func NewKafkaQueue(config *KafkaConfig) *KafkaQueue {
+	return &KafkaQueue{config: config, base: newWrappedBaseQueue(systemKafka)}
+}
+
+func (q *KafkaQueue) Init(ctx context.Context) (func(), error) {
+	if len(q.config.Topics) == 0 {
+		return nil, errors.New("kafka queue requires at least one topic")
+	}
+	writer, err := newKafkaWriter(q.config, q.config.Topics[0])
+	if err != nil {
+		return nil, err
+	}
+	q.writer = writer
+	return func() {
+		q.writer.Close()
+	}, nil
+}
+
+// Publish writes the message to the first configured topic.
+func (q *KafkaQueue) Publish(ctx context.Context, incomingMessage IncomingMessage) error {
+	if q.writer == nil {
+		return errors.New("kafka queue is not initialized")
+	}
+	msg, err := incomingMessage.ToMessage()
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	err = q.writer.WriteMessages(ctx, kafka.Message{Value: msg.Body})
+	q.base.metrics.Published(ctx, time.Since(start), err)
+	return err
+}
+
+func (q *KafkaQueue) Subscribe(ctx context.Context, opts ...SubscribeOption) (Subscription, error) {
+	mechanism, err := kafkaSASLMechanism(q.config)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &kafka.Dialer{
+		Timeout:       10 * time.Second,
+		DualStack:     true,
+		SASLMechanism: mechanism,
+	}
+	if q.config.TLS {
+		dialer.TLS = &tls.Config{}
+	}
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers:     q.config.Brokers,
+		GroupID:     q.config.GroupID,
+		GroupTopics: q.config.Topics,
+		Dialer:      dialer,
+		StartOffset: kafka.FirstOffset,
+	})
+	maxRedeliveries := q.config.MaxRedeliveries
+	if maxRedeliveries <= 0 {
+		maxRedeliveries = defaultKafkaMaxRedeliveries
+	}
+	var deadLetter kafkaWriter
+	if q.config.DeadLetterTopic != "" {
+		writer, err := newKafkaWriter(q.config, q.config.DeadLetterTopic)
+		if err != nil {
+			reader.Close()
+			return nil, err
+		}
+		deadLetter = writer
+	}
+	return newKafkaSubscription(reader, deadLetter, maxRedeliveries, q.base.metrics), nil
+}
+
+func newKafkaWriter(config *KafkaConfig, topic string) (*kafka.Writer, error) {
+	mechanism, err := kafkaSASLMechanism(config)
+	if err != nil {
+		return nil, err
+	}
+	transport := &kafka.Transport{SASL: mechanism}
+	if config.TLS {
+		transport.TLS = &tls.Config{}
+	}
+	return &kafka.Writer{
+		Addr:      kafka.TCP(config.Brokers...),
+		Topic:     topic,
+		Balancer:  &kafka.Hash{},
+		Transport: transport,
+	}, nil
+}
+
+func kafkaSASLMechanism(config *KafkaConfig) (sasl.Mechanism, error) {
+	switch config.SASLMechanism {
+	case "":
+		return nil, nil
+	case kafkaSASLMechanismPlain:
+		return &plain.Mechanism{Username: config.Username, Password: config.Password}, nil
+	case kafkaSASLMechanismSCRAMSHA256:
+		return scram.Mechanism(scram.SHA256, config.Username, config.Password)
+	case kafkaSASLMechanismSCRAMSHA512:
+		return scram.Mechanism(scram.SHA512, config.Username, config.Password)
+	default:
+		return nil, fmt.Errorf("unsupported kafka SASL mechanism: %s", config.SASLMechanism)
+	}
+}
+
+// kafkaReader is the part of *kafka.Reader the subscription uses.
+type kafkaReader interface {
+	FetchMessage(ctx context.Context) (kafka.Message, error)
+	CommitMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+// kafkaWriter is the part of *kafka.Writer the subscription uses to write to
+// the dead-letter topic.
+type kafkaWriter interface {
+	WriteMessages(ctx context.Context, msgs ...kafka.Message) error
+	Close() error
+}
+
+type kafkaPartition struct {
+	topic     string
+	partition int
+}
+
+// kafkaOffsets are the received, uncommitted offsets of a partition.
+type kafkaOffsets struct {
+	// pending holds the offsets in the order they were received.
+	pending []int64
+	// settled reports, for each pending offset, whether it was settled.
+	settled map[int64]bool
+}
+
+type kafkaSubscription struct {
+	reader          kafkaReader
+	deadLetter      kafkaWriter // nil without a dead-letter topic
+	metrics         *queueMetrics
+	maxRedeliveries int
+	redeliveryDelay time.Duration
+
+	records   chan *kafkaQueueMessage
+	redeliver chan *kafkaQueueMessage
+	cancel    context.CancelFunc
+	done      chan struct{}
+	closeOnce sync.Once
+	fetchErr  error // set by the fetch goroutine before it closes records
+
+	mu         sync.Mutex
+	partitions map[kafkaPartition]*kafkaOffsets
+
+	// commitMu serializes commits so a partition's committed offset never
+	// moves backwards.
+	commitMu  sync.Mutex
+	committed map[kafkaPartition]int64
+}
+
+var _ Subscription = &kafkaSubscription{}
+
+func newKafkaSubscription(reader kafkaReader, deadLetter kafkaWriter, maxRedeliveries int, metrics *queueMetrics) *kafkaSubscription {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &kafkaSubscription{
+		reader:          reader,
+		deadLetter:      deadLetter,
+		metrics:         metrics,
+		maxRedeliveries: maxRedeliveries,
+		redeliveryDelay: kafkaRedeliveryDelay,
+		records:         make(chan *kafkaQueueMessage),
+		redeliver:       make(chan *kafkaQueueMessage),
+		cancel:          cancel,
+		done:            make(chan struct{}),
+		partitions:      make(map[kafkaPartition]*kafkaOffsets),
+		committed:       make(map[kafkaPartition]int64),
+	}
+	go s.fetch(ctx)
+	return s
+}
+
+func (s *kafkaSubscription) fetch(ctx context.Context) {
+	defer close(s.records)
+	for {
+		record, err := s.reader.FetchMessage(ctx)
+		if err != nil {
+			s.fetchErr = err
+			return
+		}
+		s.track(record)
+		select {
+		case s.records <- &kafkaQueueMessage{subscription: s, record: record}:
+		case <-ctx.Done():
+			s.fetchErr = ctx.Err()
+			return
+		}
+	}
+}
+
+// track records a received offset as pending. An offset at or below the
+// partition's pending offsets means the partition was reassigned and is read
+// again from its committed offset, so the stale offsets are dropped.
+func (s *kafkaSubscription) track(record kafka.Message) {
+	key := kafkaPartition{topic: record.Topic, partition: record.Partition}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	offsets := s.partitions[key]
+	if offsets == nil || (len(offsets.pending) > 0 && record.Offset <= offsets.pending[len(offsets.pending)-1]) {
+		offsets = &kafkaOffsets{settled: make(map[int64]bool)}
+		s.partitions[key] = offsets
+	}
+	offsets.pending = append(offsets.pending, record.Offset)
+	offsets.settled[record.Offset] = false
+}
+
+func (s *kafkaSubscription) Receive(ctx context.Context) (*Message, error) {
+	var msg *kafkaQueueMessage
+	select {
+	case msg = <-s.redeliver:
+	case record, ok := <-s.records:
+		if !ok {
+			err := fmt.Errorf("subscription closed: %w", s.fetchErr)
+			s.metrics.Received(ctx, err)
+			return nil, err
+		}
+		msg = record
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	msg.deliveries++
+	s.metrics.Received(ctx, nil)
+	return s.metrics.wrap(msg.toMessage()), nil
+}
+
+func (s *kafkaSubscription) Shutdown(_ context.Context) error {
+	var err error
+	s.closeOnce.Do(func() {
+		s.cancel()
+		close(s.done)
+		err = s.reader.Close()
+		if s.deadLetter != nil {
+			err = errors.Join(err, s.deadLetter.Close())
+		}
+	})
+	return err
+}
+
+// settle marks the record's offset settled and commits the partition up to
+// the first offset that isn't.
+func (s *kafkaSubscription) settle(record kafka.Message) {
+	key := kafkaPartition{topic: record.Topic, partition: record.Partition}
+	s.mu.Lock()
+	offsets := s.partitions[key]
+	if offsets == nil {
+		s.mu.Unlock()
+		return
+	}
+	if _, ok := offsets.settled[record.Offset]; !ok {
+		s.mu.Unlock()
+		return
+	}
+	offsets.settled[record.Offset] = true
+	commit := int64(-1)
+	for len(offsets.pending) > 0 && offsets.settled[offsets.pending[0]] {
+		commit = offsets.pending[0]
+		delete(offsets.settled, commit)
+		offsets.pending = offsets.pending[1:]
+	}
+	s.mu.Unlock()
+	if commit < 0 {
+		return
+	}
+
+	s.commitMu.Lock()
+	defer s.commitMu.Unlock()
+	if committed, ok := s.committed[key]; ok && committed >= commit {
+		return
+	}
+	// A failed commit is retried by the next one; until then the messages are
+	// received again if the partition moves to another consumer.
+	if err := s.reader.CommitMessages(context.Background(), kafka.Message{Topic: record.Topic, Partition: record.Partition, Offset: commit}); err == nil {
+		s.committed[key] = commit
+	}
+}
+
+// nack redelivers the message after a delay growing with each delivery. Once
+// it has been delivered more than maxRedeliveries times, it is written to the
+// dead-letter topic and settled instead, when there is one; a failed write is
+// retried on the next redelivery.
+func (s *kafkaSubscription) nack(msg *kafkaQueueMessage) {
+	if s.deadLetter != nil && msg.deliveries > s.maxRedeliveries {
+		if err := s.writeDeadLetter(msg); err == nil {
+			s.metrics.settle("dead_letter")
+			s.settle(msg.record)
+			return
+		}
+	}
+	time.AfterFunc(s.redeliveryBackoff(msg.deliveries), func() {
+		select {
+		case s.redeliver <- msg:
+		case <-s.done:
+		}
+	})
+}
+
+// writeDeadLetter writes the message to the dead-letter topic with its key and
+// headers, plus headers locating the original message.
+func (s *kafkaSubscription) writeDeadLetter(msg *kafkaQueueMessage) error {
+	ctx, cancel := context.WithTimeout(context.Background(), kafkaDeadLetterTimeout)
+	defer cancel()
+	headers := append([]kafka.Header{}, msg.record.Headers...)
+	headers = append(headers,
+		kafka.Header{Key: "x-outpost-source-topic", Value: []byte(msg.record.Topic)},
+		kafka.Header{Key: "x-outpost-source-partition", Value: []byte(strconv.Itoa(msg.record.Partition))},
+		kafka.Header{Key: "x-outpost-source-offset", Value: []byte(strconv.FormatInt(msg.record.Offset, 10))},
+		kafka.Header{Key: "x-outpost-deliveries", Value: []byte(strconv.Itoa(msg.deliveries))},
+	)
+	return s.deadLetter.WriteMessages(ctx, kafka.Message{
+		Key:     msg.record.Key,
+		Value:   msg.record.Value,
+		Headers: headers,
+	})
+}
+
+// redeliveryBackoff is the delay before redelivering a message nacked after
+// its nth delivery: the redelivery delay, doubling up to a minute.
+func (s *kafkaSubscription) redeliveryBackoff(deliveries int) time.Duration {
+	delay := s.redeliveryDelay
+	for i := 1; i < deliveries && delay < kafkaMaxRedeliveryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, kafkaMaxRedeliveryDelay)
+}
+
+type kafkaQueueMessage struct {
+	subscription *kafkaSubscription
+	record       kafka.Message
+	deliveries   int
+}
+
+func (m *kafkaQueueMessage) toMessage() *Message {
+	headers := make(map[string]string, len(m.record.Headers))
+	for _, header := range m.record.Headers {
+		headers[header.Key] = string(header.Value)
+	}
+	return &Message{
+		QueueMessage: &kafkaAcker{msg: m},
+		LoggableID:   m.record.Topic + "-" + strconv.Itoa(m.record.Partition) + "-" + strconv.FormatInt(m.record.Offset, 10),
+		Body:         m.record.Value,
+		Topic:        m.record.Topic,
+		Key:          string(m.record.Key),
+		Headers:      headers,
+	}
+}
+
+// kafkaAcker settles one delivery of a message; a redelivery gets its own.
+type kafkaAcker struct {
+	msg  *kafkaQueueMessage
+	once sync.Once
+}
+
+func (a *kafkaAcker) Ack() {
+	a.once.Do(func() { a.msg.subscription.settle(a.msg.record) })
+}
+
+func (a *kafkaAcker) Nack() {
+	a.once.Do(func() { a.msg.subscription.nack(a.msg) })
+}
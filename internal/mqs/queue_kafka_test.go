@@ -0,0 +1,198 @@
+package mqs
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeKafkaReader struct {
+	records chan kafka.Message
+
+	mu      sync.Mutex
+	commits []int64
+}
+
+func newFakeKafkaReader(offsets ...int64) *fakeKafkaReader {
+	r := &fakeKafkaReader{records: make(chan kafka.Message, len(offsets))}
+	for _, offset := range offsets {
+		r.records <- kafka.Message{
+			Topic:   "orders",
+			Offset:  offset,
+			Key:     []byte("acme"),
+			Value:   []byte(`{"id":"1"}`),
+			Headers: []kafka.Header{{Key: "tenant-id", Value: []byte("t1")}},
+		}
+	}
+	return r
+}
+
+func (r *fakeKafkaReader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	select {
+	case record := <-r.records:
+		return record, nil
+	case <-ctx.Done():
+		return kafka.Message{}, ctx.Err()
+	}
+}
+
+func (r *fakeKafkaReader) CommitMessages(_ context.Context, msgs ...kafka.Message) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, msg := range msgs {
+		r.commits = append(r.commits, msg.Offset)
+	}
+	return nil
+}
+
+func (r *fakeKafkaReader) Close() error { return nil }
+
+func (r *fakeKafkaReader) committed() []int64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]int64{}, r.commits...)
+}
+
+type fakeKafkaWriter struct {
+	err error
+
+	mu       sync.Mutex
+	messages []kafka.Message
+}
+
+func (w *fakeKafkaWriter) WriteMessages(_ context.Context, msgs ...kafka.Message) error {
+	if w.err != nil {
+		return w.err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.messages = append(w.messages, msgs...)
+	return nil
+}
+
+func (w *fakeKafkaWriter) Close() error { return nil }
+
+func (w *fakeKafkaWriter) written() []kafka.Message {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return append([]kafka.Message{}, w.messages...)
+}
+
+func TestKafkaSubscription(t *testing.T) {
+	t.Parallel()
+
+	receive := func(t *testing.T, sub *kafkaSubscription) *Message {
+		t.Helper()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		msg, err := sub.Receive(ctx)
+		require.NoError(t, err)
+		return msg
+	}
+
+	t.Run("maps record metadata", func(t *testing.T) {
+		t.Parallel()
+		sub := newKafkaSubscription(newFakeKafkaReader(7), nil, 1, newQueueMetrics(systemKafka))
+		defer sub.Shutdown(context.Background())
+
+		msg := receive(t, sub)
+		assert.Equal(t, "orders-0-7", msg.LoggableID)
+		assert.Equal(t, "orders", msg.Topic)
+		assert.Equal(t, "acme", msg.Key)
+		assert.Equal(t, map[string]string{"tenant-id": "t1"}, msg.Headers)
+		assert.JSONEq(t, `{"id":"1"}`, string(msg.Body))
+	})
+
+	t.Run("commits settled offsets in order", func(t *testing.T) {
+		t.Parallel()
+		reader := newFakeKafkaReader(0, 1, 2)
+		sub := newKafkaSubscription(reader, nil, 1, newQueueMetrics(systemKafka))
+		defer sub.Shutdown(context.Background())
+
+		first, second, third := receive(t, sub), receive(t, sub), receive(t, sub)
+		second.Ack()
+		third.Ack()
+		assert.Empty(t, reader.committed(), "offsets after an unsettled one aren't committed")
+
+		first.Ack()
+		assert.Equal(t, []int64{2}, reader.committed())
+	})
+
+	t.Run("redelivers nacked messages until they succeed without a dead-letter topic", func(t *testing.T) {
+		t.Parallel()
+		reader := newFakeKafkaReader(0)
+		sub := newKafkaSubscription(reader, nil, 1, newQueueMetrics(systemKafka))
+		sub.redeliveryDelay = time.Millisecond
+		defer sub.Shutdown(context.Background())
+
+		receive(t, sub).Nack()
+		redelivered := receive(t, sub)
+		assert.Equal(t, "orders-0-0", redelivered.LoggableID)
+		redelivered.Nack()
+		assert.Empty(t, reader.committed(), "the message isn't skipped after the max redeliveries")
+
+		receive(t, sub).Ack()
+		assert.Equal(t, []int64{0}, reader.committed())
+	})
+
+	t.Run("writes exhausted messages to the dead-letter topic", func(t *testing.T) {
+		t.Parallel()
+		reader := newFakeKafkaReader(0)
+		deadLetter := &fakeKafkaWriter{}
+		sub := newKafkaSubscription(reader, deadLetter, 1, newQueueMetrics(systemKafka))
+		sub.redeliveryDelay = time.Millisecond
+		defer sub.Shutdown(context.Background())
+
+		receive(t, sub).Nack()
+		assert.Empty(t, deadLetter.written())
+
+		receive(t, sub).Nack()
+		assert.Equal(t, []int64{0}, reader.committed())
+		written := deadLetter.written()
+		require.Len(t, written, 1)
+		assert.Equal(t, []byte(`{"id":"1"}`), written[0].Value)
+		assert.Equal(t, []byte("acme"), written[0].Key)
+		headers := map[string]string{}
+		for _, header := range written[0].Headers {
+			headers[header.Key] = string(header.Value)
+		}
+		assert.Equal(t, map[string]string{
+			"tenant-id":                  "t1",
+			"x-outpost-source-topic":     "orders",
+			"x-outpost-source-partition": "0",
+			"x-outpost-source-offset":    "0",
+			"x-outpost-deliveries":       "2",
+		}, headers)
+	})
+
+	t.Run("redelivers exhausted messages the dead-letter topic rejects", func(t *testing.T) {
+		t.Parallel()
+		reader := newFakeKafkaReader(0)
+		deadLetter := &fakeKafkaWriter{err: errors.New("broker down")}
+		sub := newKafkaSubscription(reader, deadLetter, 0, newQueueMetrics(systemKafka))
+		sub.redeliveryDelay = time.Millisecond
+		defer sub.Shutdown(context.Background())
+
+		receive(t, sub).Nack()
+		redelivered := receive(t, sub)
+		assert.Equal(t, "orders-0-0", redelivered.LoggableID)
+		assert.Empty(t, reader.committed())
+	})
+}
+
+func TestKafkaSubscription_RedeliveryBackoff(t *testing.T) {
+	t.Parallel()
+
+	sub := &kafkaSubscription{redeliveryDelay: time.Second}
+	assert.Equal(t, time.Second, sub.redeliveryBackoff(1))
+	assert.Equal(t, 2*time.Second, sub.redeliveryBackoff(2))
+	assert.Equal(t, 32*time.Second, sub.redeliveryBackoff(6))
+	assert.Equal(t, time.Minute, sub.redeliveryBackoff(7))
+	assert.Equal(t, time.Minute, sub.redeliveryBackoff(100))
+}
@@ -0,0 +1,194 @@
+package mqs
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/nats-io/nats.go/jetstream"
+)
+
+type NATSJetStreamConfig struct {
+	ServerURL       string
+	CredentialsFile string // optional
+	Stream          string
+	Subject         string // messages are published to Subject
+	Consumer        string // durable consumer messages are received from
+}
+
+// NATSJetStreamQueue publishes to a JetStream subject and receives from a
+// durable pull consumer of the stream. The stream and consumer are declared
+// by mqinfra, or managed externally. JetStream redelivers a message once the
+// consumer's ack wait passes without it being acked, or as soon as it is
+// nacked, up to the consumer's max deliveries.
+type NATSJetStreamQueue struct {
+	base                   *wrappedBaseQueue
+	config                 *NATSJetStreamConfig
+	maxVisibilityExtension time.Duration
+	visibilityTimeout      time.Duration
+	mu                     sync.Mutex
+	conn                   *nats.Conn
+	js                     jetstream.JetStream
+}
+
+var _ Queue = &NATSJetStreamQueue{}
+
+func NewNATSJetStreamQueue(config *NATSJetStreamConfig, visibilityTimeout, maxVisibilityExtension time.Duration) *NATSJetStreamQueue {
+	return &NATSJetStreamQueue{
+		config:                 config,
+		visibilityTimeout:      visibilityTimeout,
+		maxVisibilityExtension: maxVisibilityExtension,
+		base:                   newWrappedBaseQueue(systemNATSJetStream),
+	}
+}
+
+func (q *NATSJetStreamQueue) Init(ctx context.Context) (func(), error) {
+	if _, err := q.connect(); err != nil {
+		return nil, err
+	}
+	return func() {
+		q.mu.Lock()
+		defer q.mu.Unlock()
+		if q.conn != nil {
+			q.conn.Close()
+		}
+	}, nil
+}
+
+func (q *NATSJetStreamQueue) Publish(ctx context.Context, incomingMessage IncomingMessage) error {
+	js, err := q.connect()
+	if err != nil {
+		return err
+	}
+	msg, err := incomingMessage.ToMessage()
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	_, err = js.Publish(ctx, q.config.Subject, msg.Body)
+	q.base.metrics.Published(ctx, time.Since(start), err)
+	return err
+}
+
+func (q *NATSJetStreamQueue) Subscribe(ctx context.Context, opts ...SubscribeOption) (Subscription, error) {
+	js, err := q.connect()
+	if err != nil {
+		return nil, err
+	}
+	consumer, err := js.Consumer(ctx, q.config.Stream, q.config.Consumer)
+	if err != nil {
+		return nil, err
+	}
+	var messagesOpts []jetstream.PullMessagesOpt
+	if o := ApplySubscribeOptions(opts); o.Concurrency > 0 {
+		messagesOpts = append(messagesOpts, jetstream.PullMaxMessages(o.Concurrency))
+	}
+	messages, err := consumer.Messages(messagesOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return &natsJetStreamSubscription{
+		messages: messages,
+		metrics:  q.base.metrics,
+		extender: newVisibilityExtender(q.visibilityTimeout, q.maxVisibilityExtension, q.base.metrics),
+	}, nil
+}
+
+// connect returns the queue's JetStream context, connecting on first use. The
+// client reconnects by itself, so the connection is kept until Init's cleanup.
+func (q *NATSJetStreamQueue) connect() (jetstream.JetStream, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.js != nil {
+		return q.js, nil
+	}
+	opts := []nats.Option{nats.Name("outpost"), nats.MaxReconnects(-1)}
+	if q.config.CredentialsFile != "" {
+		opts = append(opts, nats.UserCredentials(q.config.CredentialsFile))
+	}
+	conn, err := nats.Connect(q.config.ServerURL, opts...)
+	if err != nil {
+		return nil, err
+	}
+	js, err := jetstream.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	q.conn = conn
+	q.js = js
+	return js, nil
+}
+
+type natsJetStreamSubscription struct {
+	messages jetstream.MessagesContext
+	metrics  *queueMetrics
+	extender *visibilityExtender
+}
+
+var _ Subscription = &natsJetStreamSubscription{}
+
+func (s *natsJetStreamSubscription) Receive(ctx context.Context) (*Message, error) {
+	msg, err := s.messages.Next(jetstream.NextContext(ctx))
+	if err != nil && errors.Is(err, jetstream.ErrMsgIteratorClosed) && ctx.Err() != nil {
+		err = ctx.Err()
+	}
+	s.metrics.Received(ctx, err)
+	if err != nil {
+		return nil, err
+	}
+	m := &Message{
+		QueueMessage: &natsJetStreamAcker{msg: msg},
+		LoggableID:   natsJetStreamLoggableID(msg),
+		Body:         msg.Data(),
+		Topic:        msg.Subject(),
+		Headers:      natsJetStreamHeaders(msg.Headers()),
+	}
+	if s.extender != nil {
+		// InProgress restarts the consumer's ack wait, which stands in for the
+		// visibility timeout.
+		m = s.extender.wrap(m, func(ctx context.Context, _ time.Duration) error {
+			return msg.InProgress()
+		})
+	}
+	return s.metrics.wrap(m), nil
+}
+
+func (s *natsJetStreamSubscription) Shutdown(_ context.Context) error {
+	s.messages.Stop()
+	return nil
+}
+
+func natsJetStreamLoggableID(msg jetstream.Msg) string {
+	metadata, err := msg.Metadata()
+	if err != nil {
+		return ""
+	}
+	return metadata.Stream + "-" + strconv.FormatUint(metadata.Sequence.Stream, 10)
+}
+
+func natsJetStreamHeaders(header nats.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(header))
+	for key := range header {
+		headers[key] = header.Get(key)
+	}
+	return headers
+}
+
+type natsJetStreamAcker struct {
+	msg jetstream.Msg
+}
+
+func (a *natsJetStreamAcker) Ack() {
+	a.msg.Ack()
+}
+
+func (a *natsJetStreamAcker) Nack() {
+	a.msg.Nak()
+}
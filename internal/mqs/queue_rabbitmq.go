@@ -61,6 +61,7 @@ func (q *RabbitMQQueue) Publish(ctx context.Context, incomingMessage IncomingMes
 	if rerr != nil {
 		return err
 	}
+	q.base.metrics.PublishRetried(ctx)
 	return q.base.Publish(ctx, topic, incomingMessage, metadata)
 }
 
@@ -110,5 +111,5 @@ func (q *RabbitMQQueue) connectionLost() bool {
 }
 
 func NewRabbitMQQueue(config *RabbitMQConfig) *RabbitMQQueue {
-	return &RabbitMQQueue{config: config, base: newWrappedBaseQueue()}
+	return &RabbitMQQueue{config: config, base: newWrappedBaseQueue(systemRabbitMQ)}
 }
@@ -0,0 +1,88 @@
+package mqs
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// extendFunc hides a received message from other consumers for timeout from
+// now.
+type extendFunc func(ctx context.Context, timeout time.Duration) error
+
+// visibilityExtender keeps messages invisible to other consumers while they
+// are still being handled, so a slow delivery isn't redelivered to another
+// consumer halfway through. Each held message is extended by the visibility
+// timeout every third of it, and extension stops once the message has been
+// held for maxExtension: a handler holding it that long is treated as stuck,
+// and the message is left to become visible and be redelivered.
+type visibilityExtender struct {
+	timeout      time.Duration
+	maxExtension time.Duration
+	metrics      *queueMetrics
+}
+
+// newVisibilityExtender returns nil when extension is disabled.
+func newVisibilityExtender(timeout, maxExtension time.Duration, metrics *queueMetrics) *visibilityExtender {
+	if timeout <= 0 || maxExtension <= 0 {
+		return nil
+	}
+	return &visibilityExtender{timeout: timeout, maxExtension: maxExtension, metrics: metrics}
+}
+
+// wrap extends msg's visibility with extend until it is acked or nacked. A nil
+// extend only watches for the message being held past maxExtension, for
+// brokers whose SDK extends leases itself.
+func (e *visibilityExtender) wrap(msg *Message, extend extendFunc) *Message {
+	done := make(chan struct{})
+	msg.QueueMessage = &extendedQueueMessage{QueueMessage: msg.QueueMessage, done: done}
+	go e.run(extend, done)
+	return msg
+}
+
+func (e *visibilityExtender) run(extend extendFunc, done <-chan struct{}) {
+	expired := time.NewTimer(e.maxExtension)
+	defer expired.Stop()
+	var tick <-chan time.Time
+	if extend != nil {
+		ticker := time.NewTicker(e.timeout / 3)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-expired.C:
+			e.metrics.VisibilityExtensionExpired(context.Background())
+			return
+		case <-tick:
+			ctx, cancel := context.WithTimeout(context.Background(), e.timeout/3)
+			err := extend(ctx, e.timeout)
+			cancel()
+			e.metrics.VisibilityExtended(context.Background(), err)
+		}
+	}
+}
+
+// extendedQueueMessage stops extending the message once it is settled.
+type extendedQueueMessage struct {
+	QueueMessage
+	done chan struct{}
+	once sync.Once
+}
+
+func (m *extendedQueueMessage) stop() {
+	m.once.Do(func() { close(m.done) })
+}
+
+func (m *extendedQueueMessage) Ack() {
+	m.stop()
+	m.QueueMessage.Ack()
+}
+
+func (m *extendedQueueMessage) Nack() {
+	m.stop()
+	m.QueueMessage.Nack()
+}
@@ -0,0 +1,109 @@
+package mqs
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+type testQueueMessage struct {
+	acked, nacked atomic.Bool
+}
+
+func (m *testQueueMessage) Ack()  { m.acked.Store(true) }
+func (m *testQueueMessage) Nack() { m.nacked.Store(true) }
+
+func TestVisibilityExtender(t *testing.T) {
+	t.Parallel()
+
+	newExtender := func(t *testing.T, maxExtension time.Duration) (*visibilityExtender, *sdkmetric.ManualReader) {
+		t.Helper()
+		reader := sdkmetric.NewManualReader()
+		provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+		i, err := newQueueInstruments(provider.Meter("test"))
+		require.NoError(t, err)
+		metrics := &queueMetrics{queueInstruments: i, system: attribute.String("system", systemAWSSQS)}
+		return newVisibilityExtender(30*time.Millisecond, maxExtension, metrics), reader
+	}
+	counter := func(t *testing.T, reader *sdkmetric.ManualReader, name string) int64 {
+		t.Helper()
+		var rm metricdata.ResourceMetrics
+		require.NoError(t, reader.Collect(context.Background(), &rm))
+		var total int64
+		for _, sm := range rm.ScopeMetrics {
+			for _, m := range sm.Metrics {
+				if m.Name != name {
+					continue
+				}
+				for _, dp := range m.Data.(metricdata.Sum[int64]).DataPoints {
+					total += dp.Value
+				}
+			}
+		}
+		return total
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+		assert.Nil(t, newVisibilityExtender(30*time.Second, 0, nil))
+		assert.Nil(t, newVisibilityExtender(0, time.Minute, nil))
+	})
+
+	t.Run("extends until settled", func(t *testing.T) {
+		t.Parallel()
+		extender, reader := newExtender(t, time.Minute)
+		var extensions atomic.Int32
+		queueMsg := &testQueueMessage{}
+		msg := extender.wrap(&Message{QueueMessage: queueMsg}, func(_ context.Context, timeout time.Duration) error {
+			assert.Equal(t, 30*time.Millisecond, timeout)
+			extensions.Add(1)
+			return nil
+		})
+
+		require.Eventually(t, func() bool { return extensions.Load() >= 2 }, time.Second, 5*time.Millisecond)
+		msg.Ack()
+		settled := extensions.Load()
+		time.Sleep(50 * time.Millisecond)
+
+		assert.True(t, queueMsg.acked.Load())
+		assert.LessOrEqual(t, extensions.Load(), settled+1, "extension stops once the message is acked")
+		assert.GreaterOrEqual(t, counter(t, reader, "outpost.mq.visibility_extensions"), int64(2))
+		assert.Zero(t, counter(t, reader, "outpost.mq.visibility_extension_expired"))
+	})
+
+	t.Run("stops at the max extension", func(t *testing.T) {
+		t.Parallel()
+		extender, reader := newExtender(t, 50*time.Millisecond)
+		var extensions atomic.Int32
+		extender.wrap(&Message{QueueMessage: &testQueueMessage{}}, func(context.Context, time.Duration) error {
+			extensions.Add(1)
+			return nil
+		})
+
+		require.Eventually(t, func() bool {
+			return counter(t, reader, "outpost.mq.visibility_extension_expired") == 1
+		}, time.Second, 5*time.Millisecond)
+		stopped := extensions.Load()
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, stopped, extensions.Load())
+	})
+
+	t.Run("broker-managed extension only counts expired messages", func(t *testing.T) {
+		t.Parallel()
+		extender, reader := newExtender(t, 20*time.Millisecond)
+		msg := extender.wrap(&Message{QueueMessage: &testQueueMessage{}}, nil)
+
+		require.Eventually(t, func() bool {
+			return counter(t, reader, "outpost.mq.visibility_extension_expired") == 1
+		}, time.Second, 5*time.Millisecond)
+		msg.Nack()
+		assert.Zero(t, counter(t, reader, "outpost.mq.visibility_extensions"))
+	})
+}
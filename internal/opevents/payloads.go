@@ -30,6 +30,24 @@ func TenantSubscriptionUpdatedEvent(data TenantSubscriptionUpdatedData) Event {
 	}
 }
 
+// TenantQuotaWarningData is the data payload for tenant.quota.warning events.
+type TenantQuotaWarningData struct {
+	TenantID         string `json:"tenant_id"`
+	Quota            string `json:"quota"`
+	Used             int    `json:"used"`
+	Limit            int    `json:"limit"`
+	ThresholdPercent int    `json:"threshold_percent"`
+}
+
+// TenantQuotaWarningEvent builds the tenant.quota.warning event.
+func TenantQuotaWarningEvent(data TenantQuotaWarningData) Event {
+	return Event{
+		Topic:    TopicTenantQuotaWarning,
+		TenantID: data.TenantID,
+		Data:     data,
+	}
+}
+
 // AlertDestination is the destination projection included in alert payloads.
 type AlertDestination struct {
 	ID         string        `json:"id"`
@@ -80,6 +98,9 @@ type DestinationDisabledData struct {
 	Reason      string            `json:"reason"`
 	Event       *models.Event     `json:"event"`
 	Attempt     *models.Attempt   `json:"attempt"`
+	// Notify is the tenant's contact, set when the tenant opted into
+	// notifications about disabled destinations.
+	Notify *models.NotificationPreferences `json:"notify,omitempty"`
 }
 
 // ConsecutiveFailureData is the data payload for alert.destination.consecutive_failure events.
@@ -89,6 +110,9 @@ type ConsecutiveFailureData struct {
 	Attempt             *models.Attempt     `json:"attempt"`
 	Destination         *AlertDestination   `json:"destination"`
 	ConsecutiveFailures ConsecutiveFailures `json:"consecutive_failures"`
+	// Notify is the tenant's contact, set when the tenant opted into
+	// notifications about delivery failures.
+	Notify *models.NotificationPreferences `json:"notify,omitempty"`
 }
 
 // ExhaustedRetriesData is the data payload for alert.attempt.exhausted_retries events.
@@ -97,6 +121,9 @@ type ExhaustedRetriesData struct {
 	Event       *models.Event     `json:"event"`
 	Attempt     *models.Attempt   `json:"attempt"`
 	Destination *AlertDestination `json:"destination"`
+	// Notify is the tenant's contact, set when the tenant opted into
+	// notifications about delivery failures.
+	Notify *models.NotificationPreferences `json:"notify,omitempty"`
 }
 
 // ConsecutiveFailureEvent builds the alert.destination.consecutive_failure event.
@@ -151,6 +178,32 @@ func ExhaustedRetriesEvent(dest *AlertDestination, event *models.Event, attempt
 	}
 }
 
+// WithTenantNotifications attaches the tenant's notification contact to an
+// alert event when the tenant opted into the event's category. Outpost does
+// not contact tenants itself; operator sinks use Notify to route the alert.
+// Non-alert events and tenants without a matching preference are returned
+// unchanged.
+func WithTenantNotifications(e Event, prefs *models.NotificationPreferences) Event {
+	switch data := e.Data.(type) {
+	case ConsecutiveFailureData:
+		if prefs.Wants(models.NotificationCategoryFailures) {
+			data.Notify = prefs
+			e.Data = data
+		}
+	case ExhaustedRetriesData:
+		if prefs.Wants(models.NotificationCategoryFailures) {
+			data.Notify = prefs
+			e.Data = data
+		}
+	case DestinationDisabledData:
+		if prefs.Wants(models.NotificationCategoryDisables) {
+			data.Notify = prefs
+			e.Data = data
+		}
+	}
+	return e
+}
+
 // AttemptData is the data payload for attempt.success and attempt.failed
 // events. The two topics share one shape — the split exists for subscription
 // filtering, and Attempt.Status carries the outcome.
@@ -0,0 +1,129 @@
+// Package payloadoffload stores event payloads that are too large to deliver
+// inline.
+//
+// A destination with a max payload size receives events over that size as a
+// small notification stub instead of the full event data. The data is saved
+// here under a random token, and the stub carries a fetch URL the receiver
+// calls to retrieve it before the payload expires.
+package payloadoffload
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/tokenstore"
+)
+
+var ErrNotFound = tokenstore.ErrNotFound
+
+// Payload is the event data of an offloaded delivery.
+type Payload struct {
+	TenantID      string          `json:"tenant_id"`
+	EventID       string          `json:"event_id"`
+	DestinationID string          `json:"destination_id"`
+	Data          json.RawMessage `json:"data"`
+}
+
+type Store interface {
+	// Save stores the payload under token until ttl elapses.
+	Save(ctx context.Context, token string, payload Payload, ttl time.Duration) error
+	// Retrieve returns the payload for token, or ErrNotFound once it expired.
+	Retrieve(ctx context.Context, token string) (*Payload, error)
+}
+
+type Option = tokenstore.Option
+
+func WithDeploymentID(deploymentID string) Option {
+	return tokenstore.WithDeploymentID(deploymentID)
+}
+
+// New returns a Store backed by Redis. Each payload is a key that expires
+// with its TTL.
+func New(redisClient redis.Cmdable, opts ...Option) Store {
+	return &redisStore{tokens: tokenstore.New[Payload](redisClient, "payloadoffload", opts...)}
+}
+
+type redisStore struct {
+	tokens *tokenstore.Store[Payload]
+}
+
+func (s *redisStore) Save(ctx context.Context, token string, payload Payload, ttl time.Duration) error {
+	return s.tokens.Set(ctx, token, payload, ttl)
+}
+
+func (s *redisStore) Retrieve(ctx context.Context, token string) (*Payload, error) {
+	return s.tokens.Get(ctx, token)
+}
+
+// Stub is the data delivered in place of an offloaded payload.
+type Stub struct {
+	Offloaded bool      `json:"offloaded"`
+	SizeBytes int       `json:"size_bytes"`
+	FetchURL  string    `json:"fetch_url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// Offloader saves payloads to a Store and builds the stubs pointing at them.
+type Offloader struct {
+	store   Store
+	baseURL string
+	ttl     time.Duration
+	clock   clock.Clock
+}
+
+type OffloaderOption func(*Offloader)
+
+// WithClock sets the clock used to compute stub expiry times.
+func WithClock(c clock.Clock) OffloaderOption {
+	return func(o *Offloader) {
+		o.clock = c
+	}
+}
+
+// NewOffloader returns an Offloader whose fetch URLs are
+// "<baseURL>/payloads/<token>". baseURL is the public base URL of the API.
+func NewOffloader(store Store, baseURL string, ttl time.Duration, opts ...OffloaderOption) *Offloader {
+	o := &Offloader{
+		store:   store,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		ttl:     ttl,
+		clock:   clock.New(),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// Offload saves the event data and returns the stub to deliver instead.
+func (o *Offloader) Offload(ctx context.Context, destination *models.Destination, event *models.Event) (models.Data, error) {
+	token, err := tokenstore.NewToken()
+	if err != nil {
+		return nil, err
+	}
+	payload := Payload{
+		TenantID:      event.TenantID,
+		EventID:       event.ID,
+		DestinationID: destination.ID,
+		Data:          json.RawMessage(event.Data),
+	}
+	if err := o.store.Save(ctx, token, payload, o.ttl); err != nil {
+		return nil, fmt.Errorf("failed to save offloaded payload: %w", err)
+	}
+	stub, err := json.Marshal(Stub{
+		Offloaded: true,
+		SizeBytes: len(event.Data),
+		FetchURL:  o.baseURL + "/payloads/" + token,
+		ExpiresAt: o.clock.Now().Add(o.ttl).UTC(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return models.Data(stub), nil
+}
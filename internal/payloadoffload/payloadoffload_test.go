@@ -0,0 +1,84 @@
+package payloadoffload_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/payloadoffload"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	payload := payloadoffload.Payload{
+		TenantID:      "t1",
+		EventID:       "e1",
+		DestinationID: "d1",
+		Data:          json.RawMessage(`{"a":1}`),
+	}
+
+	t.Run("saves and retrieves a payload", func(t *testing.T) {
+		t.Parallel()
+		store := payloadoffload.New(testutil.CreateTestRedisClient(t))
+
+		require.NoError(t, store.Save(t.Context(), "token", payload, time.Minute))
+
+		got, err := store.Retrieve(t.Context(), "token")
+		require.NoError(t, err)
+		assert.Equal(t, payload, *got)
+	})
+
+	t.Run("unknown token is not found", func(t *testing.T) {
+		t.Parallel()
+		store := payloadoffload.New(testutil.CreateTestRedisClient(t))
+
+		_, err := store.Retrieve(t.Context(), "unknown")
+		assert.ErrorIs(t, err, payloadoffload.ErrNotFound)
+	})
+
+	t.Run("deployments do not share payloads", func(t *testing.T) {
+		t.Parallel()
+		redisClient := testutil.CreateTestRedisClient(t)
+		store := payloadoffload.New(redisClient, payloadoffload.WithDeploymentID("dp1"))
+		other := payloadoffload.New(redisClient, payloadoffload.WithDeploymentID("dp2"))
+
+		require.NoError(t, store.Save(t.Context(), "token", payload, time.Minute))
+
+		_, err := other.Retrieve(t.Context(), "token")
+		assert.ErrorIs(t, err, payloadoffload.ErrNotFound)
+	})
+}
+
+func TestOffloader(t *testing.T) {
+	t.Parallel()
+
+	store := payloadoffload.New(testutil.CreateTestRedisClient(t))
+	clk := clock.NewFake(time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	offloader := payloadoffload.NewOffloader(store, "https://outpost.example.com/api/v1/", time.Hour, payloadoffload.WithClock(clk))
+	destination := &models.Destination{ID: "d1"}
+	event := &models.Event{ID: "e1", TenantID: "t1", Data: []byte(`{"large":true}`)}
+
+	data, err := offloader.Offload(t.Context(), destination, event)
+	require.NoError(t, err)
+
+	var stub payloadoffload.Stub
+	require.NoError(t, json.Unmarshal(data, &stub))
+	assert.True(t, stub.Offloaded)
+	assert.Equal(t, len(event.Data), stub.SizeBytes)
+	assert.Equal(t, time.Date(2026, 1, 1, 1, 0, 0, 0, time.UTC), stub.ExpiresAt)
+
+	prefix := "https://outpost.example.com/api/v1/payloads/"
+	require.True(t, strings.HasPrefix(stub.FetchURL, prefix), stub.FetchURL)
+	saved, err := store.Retrieve(t.Context(), strings.TrimPrefix(stub.FetchURL, prefix))
+	require.NoError(t, err)
+	assert.Equal(t, "e1", saved.EventID)
+	assert.Equal(t, "d1", saved.DestinationID)
+	assert.JSONEq(t, `{"large":true}`, string(saved.Data))
+}
@@ -1,6 +1,7 @@
 package portal
 
 import (
+	"context"
 	"embed"
 	"fmt"
 	"io/fs"
@@ -19,6 +20,9 @@ var staticFS embed.FS
 type PortalConfig struct {
 	ProxyURL string
 	Configs  map[string]string
+	// DynamicConfigs, when set, is called on every request for configs that
+	// change at runtime. They override Configs.
+	DynamicConfigs func(ctx context.Context) map[string]string
 }
 
 func createJSONFromConfigs(env map[string]string) string {
@@ -37,7 +41,17 @@ func AddRoutes(router *gin.Engine, config PortalConfig) {
 		c.Header("Pragma", "no-cache")
 		c.Header("Expires", "0")
 		c.Header("Content-Type", "application/javascript")
-		c.String(http.StatusOK, "window.PORTAL_CONFIGS = "+createJSONFromConfigs(config.Configs)+";")
+		configs := config.Configs
+		if config.DynamicConfigs != nil {
+			configs = make(map[string]string, len(config.Configs))
+			for k, v := range config.Configs {
+				configs[k] = v
+			}
+			for k, v := range config.DynamicConfigs(c.Request.Context()) {
+				configs[k] = v
+			}
+		}
+		c.String(http.StatusOK, "window.PORTAL_CONFIGS = "+createJSONFromConfigs(configs)+";")
 	})
 
 	if config.ProxyURL != "" {
@@ -14,12 +14,13 @@ import Table from "../../common/Table/Table";
 import Tooltip from "../../common/Tooltip/Tooltip";
 import CONFIGS from "../../config";
 import { useDestinationTypes } from "../../destination-types";
-import type { Destination } from "../../typings/Destination";
+import type { DestinationListResponse } from "../../typings/Destination";
 import getLogo from "../../utils/logo";
 import DestinationEventsCell from "./DestinationEventsCell";
 
 const DestinationList: React.FC = () => {
-  const { data: destinations } = useSWR<Destination[]>("destinations");
+  const { data } = useSWR<DestinationListResponse>("destinations");
+  const destinations = data?.models;
   const destination_types = useDestinationTypes();
 
   const destinationIds = useMemo(
@@ -136,6 +137,17 @@ const DestinationList: React.FC = () => {
           ) : null,
           destination.disabled_at ? (
             <Badge text="Disabled" />
+          ) : destination.health?.status === "failing" ? (
+            <Tooltip
+              content={
+                <span className="subtitle-s">
+                  {destination.health.last_error?.message ||
+                    "The last delivery failed."}
+                </span>
+              }
+            >
+              <Badge text="Failing" danger />
+            </Tooltip>
           ) : (
             <Badge text="Active" success />
           ),
@@ -1,3 +1,5 @@
+import type { SeekPagination } from "./Event";
+
 interface ConfigField {
   type: "text" | "checkbox" | "key_value_map" | "select";
   label: string;
@@ -39,6 +41,18 @@ interface DestinationTypeReference {
 // Supports operators: $eq, $neq, $gt, $gte, $lt, $lte, $in, $nin, $startsWith, $endsWith, $exist, $or, $and, $not
 type Filter = Record<string, any> | null;
 
+interface DestinationHealth {
+  status: "healthy" | "failing" | "unknown";
+  consecutive_failures: number;
+  last_error?: {
+    attempt_id: string;
+    code?: string;
+    message?: string;
+    time: string;
+  };
+  last_success_at?: string;
+}
+
 interface Destination {
   id: string;
   type: string;
@@ -52,10 +66,19 @@ interface Destination {
   target_url?: string;
   disabled_at: string;
   created_at: string;
+  health?: DestinationHealth;
+}
+
+interface DestinationListResponse {
+  models: Destination[];
+  pagination: SeekPagination;
+  count: number;
 }
 
 export type {
   Destination,
+  DestinationHealth,
+  DestinationListResponse,
   Filter,
   ConfigField,
   CredentialField,
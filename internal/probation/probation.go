@@ -0,0 +1,286 @@
+// Package probation re-enables destinations that were auto-disabled for
+// consecutive failures.
+//
+// When its policy has a probation period, an auto-disabled destination is
+// put on probation: a Redis sorted set scores it by the time the period ends.
+// Once it has, a test event is delivered to the destination, which is
+// re-enabled if the delivery succeeds and put back on probation if it fails.
+// A destination that was deleted, enabled or disabled again since is taken
+// off probation, so a tenant's own decision is never overridden.
+package probation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/alert"
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"go.uber.org/zap"
+)
+
+// TestEventTopic is the topic of the test events delivered to destinations
+// whose probation is over.
+const TestEventTopic = "outpost.probation"
+
+// claimCount is how many destinations are tested per run.
+const claimCount = 100
+
+// Store tracks the destinations on probation.
+type Store struct {
+	redisClient  redis.Cmdable
+	deploymentID string
+	clock        clock.Clock
+}
+
+type Option func(*Store)
+
+func WithDeploymentID(deploymentID string) Option {
+	return func(s *Store) {
+		s.deploymentID = deploymentID
+	}
+}
+
+func WithClock(c clock.Clock) Option {
+	return func(s *Store) {
+		s.clock = c
+	}
+}
+
+// NewStore returns a store of destinations on probation in Redis.
+func NewStore(redisClient redis.Cmdable, opts ...Option) *Store {
+	s := &Store{
+		redisClient: redisClient,
+		clock:       clock.New(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+func (s *Store) key() string {
+	if s.deploymentID == "" {
+		return "probation"
+	}
+	return s.deploymentID + ":probation"
+}
+
+// member identifies a destination on probation, and the disable it is on
+// probation for.
+type member struct {
+	TenantID      string `json:"tenant_id"`
+	DestinationID string `json:"destination_id"`
+	// DisabledAt is in Unix milliseconds, the precision destinations are
+	// stored with.
+	DisabledAt int64 `json:"disabled_at"`
+}
+
+// Schedule puts a destination disabled at disabledAt on probation for the
+// given period.
+func (s *Store) Schedule(ctx context.Context, tenantID, destinationID string, disabledAt time.Time, probation time.Duration) error {
+	m := member{TenantID: tenantID, DestinationID: destinationID, DisabledAt: disabledAt.UnixMilli()}
+	return s.schedule(ctx, m, disabledAt.Add(probation))
+}
+
+func (s *Store) schedule(ctx context.Context, m member, until time.Time) error {
+	data, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	return s.redisClient.ZAdd(ctx, s.key(), redis.Z{Score: float64(until.UnixMilli()), Member: string(data)}).Err()
+}
+
+// claim takes up to count destinations whose probation is over off
+// probation. A destination is claimed by one caller only, so delivery
+// workers never test the same destination twice.
+func (s *Store) claim(ctx context.Context, count int) ([]member, error) {
+	due, err := s.redisClient.ZRangeByScore(ctx, s.key(), &redis.ZRangeBy{
+		Min:   "-inf",
+		Max:   strconv.FormatInt(s.clock.Now().UnixMilli(), 10),
+		Count: int64(count),
+	}).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list destinations on probation: %w", err)
+	}
+	var claimed []member
+	for _, data := range due {
+		removed, err := s.redisClient.ZRem(ctx, s.key(), data).Result()
+		if err != nil {
+			return claimed, fmt.Errorf("failed to claim destination on probation: %w", err)
+		}
+		var m member
+		if removed == 0 || json.Unmarshal([]byte(data), &m) != nil {
+			continue
+		}
+		claimed = append(claimed, m)
+	}
+	return claimed, nil
+}
+
+// TenantStore reads tenants and their destinations, and re-enables
+// destinations.
+type TenantStore interface {
+	RetrieveTenant(ctx context.Context, tenantID string) (*models.Tenant, error)
+	RetrieveDestination(ctx context.Context, tenantID, destinationID string) (*models.Destination, error)
+	UpsertDestination(ctx context.Context, destination models.Destination) error
+}
+
+// Publisher delivers test events.
+type Publisher interface {
+	PublishEvent(ctx context.Context, destination *models.Destination, event *models.Event) (*models.Attempt, error)
+}
+
+// FailureResetter resets a destination's consecutive failures, so it isn't
+// disabled again by its first failure once re-enabled.
+type FailureResetter interface {
+	ResetConsecutiveFailureCount(ctx context.Context, tenantID, destinationID string) error
+}
+
+// Result is the outcome of a run.
+type Result struct {
+	// Reenabled is how many destinations passed their test delivery.
+	Reenabled int
+	// Failed is how many failed it and were put back on probation.
+	Failed int
+}
+
+// Reenabler tests the destinations whose probation is over and re-enables
+// those that pass.
+type Reenabler struct {
+	store     *Store
+	tenants   TenantStore
+	publisher Publisher
+	failures  FailureResetter
+	policy    alert.Policy
+	logger    *logging.Logger
+}
+
+// NewReenabler returns a reenabler of the store's destinations. Policy is the
+// deployment's; a tenant's AutoDisablePolicy overrides its probation period.
+func NewReenabler(store *Store, tenants TenantStore, publisher Publisher, failures FailureResetter, policy alert.Policy, logger *logging.Logger) *Reenabler {
+	return &Reenabler{
+		store:     store,
+		tenants:   tenants,
+		publisher: publisher,
+		failures:  failures,
+		policy:    policy,
+		logger:    logger,
+	}
+}
+
+// Run tests the destinations whose probation is over. A destination that
+// can't be tested, such as when the tenant store is unavailable, is put back
+// on probation to be tested on the next run.
+func (r *Reenabler) Run(ctx context.Context) (Result, error) {
+	var result Result
+	claimed, err := r.store.claim(ctx, claimCount)
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+	for _, m := range claimed {
+		outcome, err := r.test(ctx, m)
+		if err != nil {
+			errs = append(errs, err)
+			if err := r.store.schedule(context.WithoutCancel(ctx), m, r.store.clock.Now()); err != nil {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		switch outcome {
+		case outcomePassed:
+			result.Reenabled++
+		case outcomeFailed:
+			result.Failed++
+		}
+	}
+	return result, errors.Join(errs...)
+}
+
+type outcome int
+
+const (
+	// outcomeDropped is a destination no longer on probation.
+	outcomeDropped outcome = iota
+	outcomePassed
+	outcomeFailed
+)
+
+// test delivers a test event to a destination whose probation is over.
+func (r *Reenabler) test(ctx context.Context, m member) (outcome, error) {
+	logger := r.logger.Ctx(ctx)
+	destination, err := r.tenants.RetrieveDestination(ctx, m.TenantID, m.DestinationID)
+	if errors.Is(err, tenantstore.ErrDestinationDeleted) || errors.Is(err, tenantstore.ErrTenantDeleted) {
+		return outcomeDropped, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve destination on probation: %w", err)
+	}
+	// Deleted, enabled, or disabled again since
+	if destination == nil || destination.DisabledAt == nil || destination.DisabledAt.UnixMilli() != m.DisabledAt {
+		return outcomeDropped, nil
+	}
+	tenant, err := r.tenants.RetrieveTenant(ctx, m.TenantID)
+	if errors.Is(err, tenantstore.ErrTenantDeleted) || errors.Is(err, tenantstore.ErrTenantNotFound) {
+		return outcomeDropped, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve tenant of destination on probation: %w", err)
+	}
+	policy := r.policy
+	if tenant != nil {
+		policy = policy.Override(tenant.AutoDisablePolicy)
+	}
+	// The policy no longer re-enables destinations
+	if policy.Probation <= 0 {
+		return outcomeDropped, nil
+	}
+
+	now := r.store.clock.Now()
+	event := &models.Event{
+		ID:            idgen.Event(),
+		TenantID:      m.TenantID,
+		DestinationID: m.DestinationID,
+		Topic:         TestEventTopic,
+		Time:          now,
+		Metadata:      map[string]string{"probation": "true"},
+		Data:          json.RawMessage(`{"type":"probation"}`),
+	}
+	attempt, publishErr := r.publisher.PublishEvent(ctx, destination, event)
+	if publishErr != nil || attempt == nil || attempt.Status != models.AttemptStatusSuccess {
+		if err := r.store.schedule(ctx, m, now.Add(policy.Probation)); err != nil {
+			return 0, fmt.Errorf("failed to put destination back on probation: %w", err)
+		}
+		logger.Info("destination failed probation test delivery",
+			zap.Error(publishErr),
+			zap.String("tenant_id", m.TenantID),
+			zap.String("destination_id", m.DestinationID),
+			zap.Duration("probation", policy.Probation))
+		return outcomeFailed, nil
+	}
+
+	// Its failures are reset first: a destination enabled with its old
+	// streak would be disabled again by its first failure.
+	if err := r.failures.ResetConsecutiveFailureCount(ctx, m.TenantID, m.DestinationID); err != nil {
+		return 0, fmt.Errorf("failed to reset consecutive failures: %w", err)
+	}
+	destination.DisabledAt = nil
+	destination.UpdatedAt = now
+	if err := r.tenants.UpsertDestination(ctx, *destination); err != nil {
+		return 0, fmt.Errorf("failed to re-enable destination: %w", err)
+	}
+	logger.Audit("destination re-enabled",
+		zap.String("tenant_id", m.TenantID),
+		zap.String("destination_id", m.DestinationID),
+		zap.String("destination_type", destination.Type))
+	return outcomePassed, nil
+}
@@ -0,0 +1,165 @@
+package probation_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/alert"
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/probation"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/tenantstore/memtenantstore"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubPublisher struct {
+	err    error
+	events []*models.Event
+}
+
+func (p *stubPublisher) PublishEvent(ctx context.Context, destination *models.Destination, event *models.Event) (*models.Attempt, error) {
+	p.events = append(p.events, event)
+	if p.err != nil {
+		return &models.Attempt{Status: models.AttemptStatusFailed}, p.err
+	}
+	return &models.Attempt{Status: models.AttemptStatusSuccess}, nil
+}
+
+type stubResetter struct {
+	reset []string
+}
+
+func (r *stubResetter) ResetConsecutiveFailureCount(ctx context.Context, tenantID, destinationID string) error {
+	r.reset = append(r.reset, destinationID)
+	return nil
+}
+
+type fixture struct {
+	clock       *clock.Fake
+	store       *probation.Store
+	tenants     tenantstore.TenantStore
+	destination models.Destination
+	publisher   *stubPublisher
+	resetter    *stubResetter
+	reenabler   *probation.Reenabler
+}
+
+// newFixture puts a destination disabled at start on probation for an hour.
+func newFixture(t *testing.T, start time.Time, publishErr error) *fixture {
+	t.Helper()
+	f := &fixture{
+		clock:     clock.NewFake(start),
+		tenants:   memtenantstore.New(),
+		publisher: &stubPublisher{err: publishErr},
+		resetter:  &stubResetter{},
+	}
+	f.store = probation.NewStore(testutil.CreateTestRedisClient(t), probation.WithClock(f.clock))
+	policy := alert.Policy{AutoDisable: true, Probation: time.Hour}
+	f.reenabler = probation.NewReenabler(f.store, f.tenants, f.publisher, f.resetter, policy, testutil.CreateTestLogger(t))
+
+	tenant := testutil.TenantFactory.Any()
+	require.NoError(t, f.tenants.UpsertTenant(t.Context(), tenant))
+	f.destination = testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithTenantID(tenant.ID),
+		testutil.DestinationFactory.WithDisabledAt(start),
+	)
+	require.NoError(t, f.tenants.UpsertDestination(t.Context(), f.destination))
+	require.NoError(t, f.store.Schedule(t.Context(), tenant.ID, f.destination.ID, start, policy.Probation))
+	return f
+}
+
+func (f *fixture) retrieveDestination(t *testing.T) *models.Destination {
+	t.Helper()
+	destination, err := f.tenants.RetrieveDestination(t.Context(), f.destination.TenantID, f.destination.ID)
+	require.NoError(t, err)
+	return destination
+}
+
+func TestReenabler(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("waits for the probation period", func(t *testing.T) {
+		t.Parallel()
+		f := newFixture(t, start, nil)
+
+		f.clock.Advance(30 * time.Minute)
+		result, err := f.reenabler.Run(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, probation.Result{}, result)
+		assert.Empty(t, f.publisher.events)
+	})
+
+	t.Run("re-enables after a successful test delivery", func(t *testing.T) {
+		t.Parallel()
+		f := newFixture(t, start, nil)
+
+		f.clock.Advance(time.Hour)
+		result, err := f.reenabler.Run(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, probation.Result{Reenabled: 1}, result)
+		require.Len(t, f.publisher.events, 1)
+		assert.Equal(t, probation.TestEventTopic, f.publisher.events[0].Topic)
+		assert.Equal(t, []string{f.destination.ID}, f.resetter.reset)
+		assert.Nil(t, f.retrieveDestination(t).DisabledAt)
+
+		// Taken off probation
+		f.clock.Advance(time.Hour)
+		result, err = f.reenabler.Run(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, probation.Result{}, result)
+	})
+
+	t.Run("a failed test delivery starts a new probation period", func(t *testing.T) {
+		t.Parallel()
+		f := newFixture(t, start, errors.New("connection refused"))
+
+		f.clock.Advance(time.Hour)
+		result, err := f.reenabler.Run(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, probation.Result{Failed: 1}, result)
+		assert.Empty(t, f.resetter.reset)
+		assert.NotNil(t, f.retrieveDestination(t).DisabledAt)
+
+		f.clock.Advance(30 * time.Minute)
+		result, err = f.reenabler.Run(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, probation.Result{}, result)
+
+		f.clock.Advance(30 * time.Minute)
+		result, err = f.reenabler.Run(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, probation.Result{Failed: 1}, result)
+	})
+
+	t.Run("drops probation of an earlier disable", func(t *testing.T) {
+		t.Parallel()
+		f := newFixture(t, start, nil)
+		require.NoError(t, f.store.Schedule(t.Context(), f.destination.TenantID, f.destination.ID, start.Add(-time.Minute), time.Hour))
+
+		f.clock.Advance(time.Hour)
+		result, err := f.reenabler.Run(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, probation.Result{Reenabled: 1}, result)
+		assert.Len(t, f.publisher.events, 1, "only the current disable is tested")
+	})
+
+	t.Run("leaves enabled destinations alone", func(t *testing.T) {
+		t.Parallel()
+		f := newFixture(t, start, nil)
+		f.destination.DisabledAt = nil
+		require.NoError(t, f.tenants.UpsertDestination(t.Context(), f.destination))
+
+		f.clock.Advance(time.Hour)
+		result, err := f.reenabler.Run(t.Context())
+		require.NoError(t, err)
+		assert.Equal(t, probation.Result{}, result)
+		assert.Empty(t, f.publisher.events)
+	})
+}
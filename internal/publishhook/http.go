@@ -0,0 +1,141 @@
+package publishhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+const signatureHeader = "X-Outpost-Signature"
+
+// maxResponseBytes caps the response body read from a validation endpoint.
+const maxResponseBytes = 64 << 10
+
+// HTTPRequest is the body POSTed to a validation endpoint.
+type HTTPRequest struct {
+	TenantID string    `json:"tenant_id"`
+	Event    HTTPEvent `json:"event"`
+}
+
+type HTTPEvent struct {
+	ID            string            `json:"id"`
+	Topic         string            `json:"topic"`
+	DestinationID string            `json:"destination_id,omitempty"`
+	Source        string            `json:"source,omitempty"`
+	Time          time.Time         `json:"time"`
+	Metadata      map[string]string `json:"metadata,omitempty"`
+	Data          json.RawMessage   `json:"data"`
+}
+
+// HTTPResponse is the optional JSON body a validation endpoint responds
+// with. An empty body accepts the event unchanged.
+type HTTPResponse struct {
+	Reject   bool              `json:"reject"`
+	Reason   string            `json:"reason,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// HTTPValidator calls an HTTP endpoint to validate events. Requests are
+// signed like delivery hooks when a signing secret is set. A response status
+// of 400 or above fails the validation rather than rejecting the event.
+type HTTPValidator struct {
+	url           string
+	signingSecret string
+	client        *http.Client
+}
+
+// HTTPValidatorOption configures an HTTPValidator.
+type HTTPValidatorOption func(*HTTPValidator)
+
+// WithSigningSecret signs requests with HMAC-SHA256 in the
+// X-Outpost-Signature header.
+func WithSigningSecret(secret string) HTTPValidatorOption {
+	return func(v *HTTPValidator) {
+		v.signingSecret = secret
+	}
+}
+
+// WithHTTPClient sets the client used to call the endpoint.
+func WithHTTPClient(client *http.Client) HTTPValidatorOption {
+	return func(v *HTTPValidator) {
+		v.client = client
+	}
+}
+
+// NewHTTPValidator creates a validator calling url.
+func NewHTTPValidator(url string, opts ...HTTPValidatorOption) *HTTPValidator {
+	v := &HTTPValidator{
+		url:    url,
+		client: &http.Client{},
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	return v
+}
+
+var _ Validator = (*HTTPValidator)(nil)
+
+func (v *HTTPValidator) Validate(ctx context.Context, event *models.Event) (Verdict, error) {
+	body, err := json.Marshal(HTTPRequest{
+		TenantID: event.TenantID,
+		Event: HTTPEvent{
+			ID:            event.ID,
+			Topic:         event.Topic,
+			DestinationID: event.DestinationID,
+			Source:        event.Source,
+			Time:          event.Time,
+			Metadata:      event.Metadata,
+			Data:          json.RawMessage(event.Data),
+		},
+	})
+	if err != nil {
+		return Verdict{}, fmt.Errorf("publishhook: failed to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, v.url, bytes.NewReader(body))
+	if err != nil {
+		return Verdict{}, fmt.Errorf("publishhook: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if v.signingSecret != "" {
+		mac := hmac.New(sha256.New, []byte(v.signingSecret))
+		mac.Write(body)
+		req.Header.Set(signatureHeader, "v0="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return Verdict{}, fmt.Errorf("publishhook: request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return Verdict{}, fmt.Errorf("publishhook: failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		snippet := respBody
+		if len(snippet) > 512 {
+			snippet = snippet[:512]
+		}
+		return Verdict{}, fmt.Errorf("publishhook: endpoint returned status %d: %s", resp.StatusCode, string(snippet))
+	}
+	if len(bytes.TrimSpace(respBody)) == 0 {
+		return Verdict{}, nil
+	}
+	var verdict HTTPResponse
+	if err := json.Unmarshal(respBody, &verdict); err != nil {
+		return Verdict{}, fmt.Errorf("publishhook: invalid response: %w", err)
+	}
+	return Verdict{Reject: verdict.Reject, Reason: verdict.Reason, Metadata: verdict.Metadata}, nil
+}
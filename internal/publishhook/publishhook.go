@@ -0,0 +1,104 @@
+// Package publishhook lets an external validator vet events as they are
+// published, before they are fanned out to destinations.
+//
+// A validator can reject an event, which is then not published, or annotate
+// it with metadata, which is merged into the event's metadata before
+// matching, so destination filters and deliveries see it. Validators are
+// either in-process (ValidatorFunc) or an HTTP endpoint (HTTPValidator).
+//
+// Validation runs synchronously on publish, so a Hook bounds it with a
+// timeout and only runs for the topics it is configured for.
+package publishhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// DefaultTimeout is the time a validator may take for one event when
+// Hook.Timeout is unset.
+const DefaultTimeout = 500 * time.Millisecond
+
+// ErrTimeout is returned when a validator runs out of time.
+var ErrTimeout = errors.New("publishhook: timeout exceeded")
+
+// Verdict is the outcome of validating an event.
+type Verdict struct {
+	// Reject drops the event: it is not published to any destination.
+	Reject bool
+	// Reason explains a rejection to the publisher.
+	Reason string
+	// Metadata is merged into the event's metadata, overriding it.
+	Metadata map[string]string
+}
+
+// Validator vets an event before it is published. Validators must not modify
+// the event.
+type Validator interface {
+	Validate(ctx context.Context, event *models.Event) (Verdict, error)
+}
+
+// ValidatorFunc adapts a function to a Validator.
+type ValidatorFunc func(ctx context.Context, event *models.Event) (Verdict, error)
+
+func (f ValidatorFunc) Validate(ctx context.Context, event *models.Event) (Verdict, error) {
+	return f(ctx, event)
+}
+
+// Hook runs a validator for the events of some topics.
+type Hook struct {
+	Validator Validator
+	// Topics are the topics validated, which may use wildcards. Empty
+	// validates every topic.
+	Topics []string
+	// Timeout caps the time the validator takes for one event. Default:
+	// DefaultTimeout.
+	Timeout time.Duration
+	// FailClosed fails the publish when the validator returns an error or
+	// times out. By default the failure is logged and the event is published
+	// unvalidated.
+	FailClosed bool
+}
+
+// Enabled reports whether a validator is set.
+func (h Hook) Enabled() bool {
+	return h.Validator != nil
+}
+
+// Applies reports whether events of topic are validated.
+func (h Hook) Applies(topic string) bool {
+	if !h.Enabled() {
+		return false
+	}
+	if len(h.Topics) == 0 {
+		return true
+	}
+	topics := models.Topics(h.Topics)
+	return topics.MatchTopic(topic)
+}
+
+func (h Hook) timeout() time.Duration {
+	if h.Timeout > 0 {
+		return h.Timeout
+	}
+	return DefaultTimeout
+}
+
+// Validate runs the validator on the event within the timeout.
+func (h Hook) Validate(ctx context.Context, event *models.Event) (Verdict, error) {
+	ctx, cancel := context.WithTimeoutCause(ctx, h.timeout(), ErrTimeout)
+	defer cancel()
+
+	verdict, err := h.Validator.Validate(ctx, event)
+	if err != nil {
+		if errors.Is(context.Cause(ctx), ErrTimeout) && errors.Is(err, context.DeadlineExceeded) {
+			return Verdict{}, fmt.Errorf("%w: %w", ErrTimeout, err)
+		}
+		return Verdict{}, err
+	}
+	return verdict, nil
+}
@@ -0,0 +1,117 @@
+package publishhook_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/publishhook"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func accept(ctx context.Context, event *models.Event) (publishhook.Verdict, error) {
+	return publishhook.Verdict{}, nil
+}
+
+func TestHook_Applies(t *testing.T) {
+	t.Parallel()
+
+	assert.False(t, publishhook.Hook{}.Applies("order.created"), "without a validator")
+	assert.True(t, publishhook.Hook{Validator: publishhook.ValidatorFunc(accept)}.Applies("order.created"))
+
+	hook := publishhook.Hook{Validator: publishhook.ValidatorFunc(accept), Topics: []string{"payment.*", "refund.created"}}
+	assert.True(t, hook.Applies("payment.captured"))
+	assert.True(t, hook.Applies("refund.created"))
+	assert.False(t, hook.Applies("order.created"))
+}
+
+func TestHook_Validate(t *testing.T) {
+	t.Parallel()
+
+	t.Run("returns the verdict", func(t *testing.T) {
+		hook := publishhook.Hook{Validator: publishhook.ValidatorFunc(func(ctx context.Context, event *models.Event) (publishhook.Verdict, error) {
+			return publishhook.Verdict{Reject: true, Reason: "fraud"}, nil
+		})}
+		event := testutil.EventFactory.Any()
+		verdict, err := hook.Validate(context.Background(), &event)
+		require.NoError(t, err)
+		assert.True(t, verdict.Reject)
+		assert.Equal(t, "fraud", verdict.Reason)
+	})
+
+	t.Run("times out", func(t *testing.T) {
+		hook := publishhook.Hook{
+			Timeout: 10 * time.Millisecond,
+			Validator: publishhook.ValidatorFunc(func(ctx context.Context, event *models.Event) (publishhook.Verdict, error) {
+				<-ctx.Done()
+				return publishhook.Verdict{}, ctx.Err()
+			}),
+		}
+		event := testutil.EventFactory.Any()
+		_, err := hook.Validate(context.Background(), &event)
+		assert.ErrorIs(t, err, publishhook.ErrTimeout)
+	})
+}
+
+func TestHTTPValidator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sends a signed request and reads the verdict", func(t *testing.T) {
+		var received publishhook.HTTPRequest
+		var signature string
+		var body []byte
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = io.ReadAll(r.Body)
+			signature = r.Header.Get("X-Outpost-Signature")
+			require.NoError(t, json.Unmarshal(body, &received))
+			w.Write([]byte(`{"reject":false,"metadata":{"risk":"low"}}`))
+		}))
+		defer server.Close()
+
+		event := testutil.EventFactory.Any(testutil.EventFactory.WithTopic("payment.captured"))
+		validator := publishhook.NewHTTPValidator(server.URL, publishhook.WithSigningSecret("secret"))
+		verdict, err := validator.Validate(context.Background(), &event)
+		require.NoError(t, err)
+
+		assert.False(t, verdict.Reject)
+		assert.Equal(t, map[string]string{"risk": "low"}, verdict.Metadata)
+		assert.Equal(t, event.ID, received.Event.ID)
+		assert.Equal(t, "payment.captured", received.Event.Topic)
+		assert.JSONEq(t, string(event.Data), string(received.Event.Data))
+
+		mac := hmac.New(sha256.New, []byte("secret"))
+		mac.Write(body)
+		assert.Equal(t, "v0="+hex.EncodeToString(mac.Sum(nil)), signature)
+	})
+
+	t.Run("empty response accepts", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer server.Close()
+
+		event := testutil.EventFactory.Any()
+		verdict, err := publishhook.NewHTTPValidator(server.URL).Validate(context.Background(), &event)
+		require.NoError(t, err)
+		assert.Equal(t, publishhook.Verdict{}, verdict)
+	})
+
+	t.Run("error status fails the validation", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer server.Close()
+
+		event := testutil.EventFactory.Any()
+		_, err := publishhook.NewHTTPValidator(server.URL).Validate(context.Background(), &event)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,130 @@
+// Package publishkey records the event each tenant published with an
+// idempotency key, so a publish request retried with the same key publishes
+// the event only once.
+//
+// A key is reserved for the event ID of the first request that uses it, and
+// later requests with the key publish under that same ID. Once the event is
+// accepted the key is completed, and requests with it return the original
+// result without publishing again, until the key expires.
+package publishkey
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/redis"
+)
+
+// DefaultWindow is how long a key is remembered when no window is set.
+const DefaultWindow = 24 * time.Hour
+
+// Entry is the event published, or being published, with a key.
+type Entry struct {
+	EventID string `json:"event_id"`
+	// Published reports whether the event was accepted.
+	Published bool `json:"published,omitempty"`
+	// DestinationIDs are the destinations the accepted event matched.
+	DestinationIDs []string `json:"destination_ids,omitempty"`
+}
+
+type Keys interface {
+	// Reserve returns the entry of the tenant's key, reserving the key for
+	// eventID if it isn't in use.
+	Reserve(ctx context.Context, tenantID, key, eventID string) (Entry, error)
+	// Complete records that the event reserved with the tenant's key was
+	// accepted and matched destinationIDs.
+	Complete(ctx context.Context, tenantID, key, eventID string, destinationIDs []string) error
+}
+
+type options struct {
+	deploymentID string
+	window       time.Duration
+}
+
+type Option func(*options)
+
+func WithDeploymentID(deploymentID string) Option {
+	return func(o *options) {
+		o.deploymentID = deploymentID
+	}
+}
+
+// WithWindow sets how long a key is remembered after it is last used.
+func WithWindow(window time.Duration) Option {
+	return func(o *options) {
+		if window > 0 {
+			o.window = window
+		}
+	}
+}
+
+// New returns Keys backed by Redis.
+func New(redisClient redis.Cmdable, opts ...Option) Keys {
+	o := &options{window: DefaultWindow}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &redisKeys{
+		redisClient:  redisClient,
+		deploymentID: o.deploymentID,
+		window:       o.window,
+	}
+}
+
+type redisKeys struct {
+	redisClient  redis.Cmdable
+	deploymentID string
+	window       time.Duration
+}
+
+// redisKey returns "[<deployment>:]publishkey:{<tenant>}:<key>".
+func (k *redisKeys) redisKey(tenantID, key string) string {
+	redisKey := "publishkey:{" + tenantID + "}:" + key
+	if k.deploymentID == "" {
+		return redisKey
+	}
+	return k.deploymentID + ":" + redisKey
+}
+
+func (k *redisKeys) Reserve(ctx context.Context, tenantID, key, eventID string) (Entry, error) {
+	redisKey := k.redisKey(tenantID, key)
+	reserved := Entry{EventID: eventID}
+	value, err := json.Marshal(reserved)
+	if err != nil {
+		return Entry{}, err
+	}
+	ok, err := k.redisClient.SetNX(ctx, redisKey, value, k.window).Result()
+	if err != nil {
+		return Entry{}, err
+	}
+	if ok {
+		return reserved, nil
+	}
+
+	raw, err := k.redisClient.Get(ctx, redisKey).Bytes()
+	if err == redis.Nil {
+		// Expired between the two calls; reserve it again
+		return k.Reserve(ctx, tenantID, key, eventID)
+	}
+	if err != nil {
+		return Entry{}, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return Entry{}, err
+	}
+	return entry, nil
+}
+
+func (k *redisKeys) Complete(ctx context.Context, tenantID, key, eventID string, destinationIDs []string) error {
+	value, err := json.Marshal(Entry{
+		EventID:        eventID,
+		Published:      true,
+		DestinationIDs: destinationIDs,
+	})
+	if err != nil {
+		return err
+	}
+	return k.redisClient.Set(ctx, k.redisKey(tenantID, key), value, k.window).Err()
+}
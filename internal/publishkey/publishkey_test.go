@@ -0,0 +1,56 @@
+package publishkey_test
+
+import (
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/publishkey"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeys(t *testing.T) {
+	t.Parallel()
+
+	t.Run("reserves a key for the first event", func(t *testing.T) {
+		t.Parallel()
+		keys := publishkey.New(testutil.CreateTestRedisClient(t))
+
+		entry, err := keys.Reserve(t.Context(), "t1", "key-1", "evt_1")
+		require.NoError(t, err)
+		assert.Equal(t, publishkey.Entry{EventID: "evt_1"}, entry)
+
+		entry, err = keys.Reserve(t.Context(), "t1", "key-1", "evt_2")
+		require.NoError(t, err)
+		assert.Equal(t, publishkey.Entry{EventID: "evt_1"}, entry, "a reserved key keeps its event")
+
+		entry, err = keys.Reserve(t.Context(), "t2", "key-1", "evt_3")
+		require.NoError(t, err)
+		assert.Equal(t, "evt_3", entry.EventID, "keys are scoped to the tenant")
+	})
+
+	t.Run("completed keys return the result", func(t *testing.T) {
+		t.Parallel()
+		keys := publishkey.New(testutil.CreateTestRedisClient(t))
+
+		_, err := keys.Reserve(t.Context(), "t1", "key-1", "evt_1")
+		require.NoError(t, err)
+		require.NoError(t, keys.Complete(t.Context(), "t1", "key-1", "evt_1", []string{"des_1"}))
+
+		entry, err := keys.Reserve(t.Context(), "t1", "key-1", "evt_2")
+		require.NoError(t, err)
+		assert.Equal(t, publishkey.Entry{EventID: "evt_1", Published: true, DestinationIDs: []string{"des_1"}}, entry)
+	})
+
+	t.Run("keys are scoped to the deployment", func(t *testing.T) {
+		t.Parallel()
+		redisClient := testutil.CreateTestRedisClient(t)
+
+		_, err := publishkey.New(redisClient, publishkey.WithDeploymentID("dp1")).Reserve(t.Context(), "t1", "key-1", "evt_1")
+		require.NoError(t, err)
+
+		entry, err := publishkey.New(redisClient).Reserve(t.Context(), "t1", "key-1", "evt_2")
+		require.NoError(t, err)
+		assert.Equal(t, "evt_2", entry.EventID)
+	})
+}
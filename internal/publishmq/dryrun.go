@@ -0,0 +1,131 @@
+package publishmq
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+)
+
+// Reasons a destination of the tenant would not receive an event.
+const (
+	SuppressedDisabled    = "disabled"
+	SuppressedTopic       = "topic_mismatch"
+	SuppressedFilter      = "filter_mismatch"
+	SuppressedNotTargeted = "not_targeted"
+	SuppressedNotFound    = "destination_not_found"
+)
+
+// DryRunResult is the fan-out a publish of the event would have.
+type DryRunResult struct {
+	EventID string `json:"id"`
+	DryRun  bool   `json:"dry_run"`
+	// Duplicate is set when an event with the same ID was already published,
+	// so publishing it again would not enqueue anything.
+	Duplicate      bool                `json:"duplicate"`
+	DestinationIDs []string            `json:"destination_ids"`
+	Destinations   []DryRunDestination `json:"destinations"`
+}
+
+// DryRunDestination is whether a destination of the tenant would receive the
+// event, and why not.
+type DryRunDestination struct {
+	ID      string `json:"id"`
+	Type    string `json:"type,omitempty"`
+	Matched bool   `json:"matched"`
+	// Sandboxed is set on matched destinations whose deliveries would be
+	// recorded without contacting them, because the tenant is in sandbox mode.
+	Sandboxed         bool   `json:"sandboxed,omitempty"`
+	SuppressionReason string `json:"suppression_reason,omitempty"`
+	// FilterMismatches lists the paths of the filter fields the event
+	// doesn't match, when suppressed for a filter mismatch.
+	FilterMismatches []string `json:"filter_mismatches,omitempty"`
+}
+
+// DryRun validates the event and matches it against every destination of the
+// tenant the way Handle does, reporting why unmatched destinations would not
+// receive it. Nothing is enqueued or recorded.
+func (h *eventHandler) DryRun(ctx context.Context, event *models.Event) (*DryRunResult, error) {
+	if err := h.validate(ctx, event); err != nil {
+		return nil, err
+	}
+	matchEvent := h.namespace.StripEvent(*event)
+
+	tenant, err := h.tenantStore.RetrieveTenant(ctx, event.TenantID)
+	if err != nil && !errors.Is(err, tenantstore.ErrTenantDeleted) {
+		return nil, err
+	}
+	sandbox := err == nil && tenant != nil && tenant.Sandbox
+
+	destinations, err := h.tenantStore.ListDestination(ctx, tenantstore.ListDestinationRequest{TenantID: event.TenantID})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(destinations, func(i, j int) bool {
+		return destinations[i].ID < destinations[j].ID
+	})
+
+	result := &DryRunResult{
+		EventID:        event.ID,
+		DryRun:         true,
+		DestinationIDs: []string{},
+		Destinations:   make([]DryRunDestination, 0, len(destinations)),
+	}
+	targetFound := false
+	for _, destination := range destinations {
+		reason := suppressionReason(&destination, &matchEvent)
+		if destination.ID == event.DestinationID {
+			targetFound = true
+		}
+		entry := DryRunDestination{
+			ID:                destination.ID,
+			Type:              destination.Type,
+			Matched:           reason == "",
+			SuppressionReason: reason,
+		}
+		if reason == SuppressedFilter {
+			entry.FilterMismatches = models.FilterMismatches(destination.Filter, matchEvent)
+		}
+		if entry.Matched {
+			entry.Sandboxed = sandbox && !destination.SandboxSafe
+			result.DestinationIDs = append(result.DestinationIDs, destination.ID)
+		}
+		result.Destinations = append(result.Destinations, entry)
+	}
+	if event.DestinationID != "" && !targetFound {
+		result.Destinations = append(result.Destinations, DryRunDestination{
+			ID:                event.DestinationID,
+			SuppressionReason: SuppressedNotFound,
+		})
+	}
+
+	if len(result.DestinationIDs) > 0 {
+		duplicate, err := h.idempotence.Processed(ctx, idempotencyKeyFromEvent(event))
+		if err != nil {
+			return nil, err
+		}
+		result.Duplicate = duplicate
+	}
+	return result, nil
+}
+
+// suppressionReason returns why the destination would not receive the event,
+// or "" if it would. It mirrors matchSpecificDestination and the tenant
+// store's MatchEvent, which ignores topics for events without one.
+func suppressionReason(destination *models.Destination, event *models.Event) string {
+	if event.DestinationID != "" && destination.ID != event.DestinationID {
+		return SuppressedNotTargeted
+	}
+	if destination.DisabledAt != nil {
+		return SuppressedDisabled
+	}
+	if (event.DestinationID != "" || event.Topic != "") && !destination.Topics.MatchTopic(event.Topic) {
+		return SuppressedTopic
+	}
+	if !models.MatchFilter(destination.Filter, *event) {
+		return SuppressedFilter
+	}
+	return ""
+}
@@ -0,0 +1,153 @@
+package publishmq_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/idempotence"
+	"github.com/hookdeck/outpost/internal/idgen"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/publishmq"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestEventHandler_DryRun(t *testing.T) {
+	ctx := context.Background()
+	redisClient := testutil.CreateTestRedisClient(t)
+	tenantStore := tenantstore.New(tenantstore.Config{RedisClient: redisClient, AvailableTopics: testutil.TestTopics})
+	idem := idempotence.New(redisClient, idempotence.WithSuccessfulTTL(24*time.Hour))
+
+	// No delivery queue: a dry run must not enqueue anything
+	eventHandler := publishmq.NewEventHandler(
+		testutil.CreateTestLogger(t),
+		nil,
+		tenantStore,
+		testutil.NewMockEventTracer(tracetest.NewInMemoryExporter()),
+		testutil.TestTopics,
+		nil,
+		idem,
+	)
+
+	tenant := models.Tenant{ID: idgen.String(), Sandbox: true, CreatedAt: time.Now()}
+	require.NoError(t, tenantStore.UpsertTenant(ctx, tenant))
+
+	destFactory := testutil.DestinationFactory
+	matched := destFactory.Any(
+		destFactory.WithID("d1"),
+		destFactory.WithTenantID(tenant.ID),
+		destFactory.WithTopics([]string{"user.created"}),
+	)
+	otherTopic := destFactory.Any(
+		destFactory.WithID("d2"),
+		destFactory.WithTenantID(tenant.ID),
+		destFactory.WithTopics([]string{"user.deleted"}),
+	)
+	filtered := destFactory.Any(
+		destFactory.WithID("d3"),
+		destFactory.WithTenantID(tenant.ID),
+		destFactory.WithTopics([]string{"*"}),
+		destFactory.WithFilter(models.Filter{"data": map[string]any{"type": "order"}}),
+	)
+	disabled := destFactory.Any(
+		destFactory.WithID("d4"),
+		destFactory.WithTenantID(tenant.ID),
+		destFactory.WithTopics([]string{"*"}),
+		destFactory.WithDisabledAt(time.Now()),
+	)
+	sandboxSafe := destFactory.Any(
+		destFactory.WithID("d5"),
+		destFactory.WithTenantID(tenant.ID),
+		destFactory.WithTopics([]string{"*"}),
+	)
+	sandboxSafe.SandboxSafe = true
+	for _, destination := range []models.Destination{matched, otherTopic, filtered, disabled, sandboxSafe} {
+		require.NoError(t, tenantStore.UpsertDestination(ctx, destination))
+	}
+
+	t.Run("reports the fan-out and suppression reasons", func(t *testing.T) {
+		event := testutil.EventFactory.AnyPointer(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithTopic("user.created"),
+		)
+
+		result, err := eventHandler.DryRun(ctx, event)
+		require.NoError(t, err)
+
+		assert.True(t, result.DryRun)
+		assert.False(t, result.Duplicate)
+		assert.Equal(t, []string{"d1", "d5"}, result.DestinationIDs)
+		assert.Equal(t, []publishmq.DryRunDestination{
+			{ID: "d1", Type: matched.Type, Matched: true, Sandboxed: true},
+			{ID: "d2", Type: otherTopic.Type, SuppressionReason: publishmq.SuppressedTopic},
+			{ID: "d3", Type: filtered.Type, SuppressionReason: publishmq.SuppressedFilter, FilterMismatches: []string{"data.type"}},
+			{ID: "d4", Type: disabled.Type, SuppressionReason: publishmq.SuppressedDisabled},
+			{ID: "d5", Type: sandboxSafe.Type, Matched: true},
+		}, result.Destinations)
+
+		processed, err := idem.Processed(ctx, "idempotency:publishmq:"+event.ID)
+		require.NoError(t, err)
+		assert.False(t, processed, "a dry run should not claim the event ID")
+	})
+
+	t.Run("specific destination", func(t *testing.T) {
+		event := testutil.EventFactory.AnyPointer(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithTopic("user.created"),
+			testutil.EventFactory.WithDestinationID("d2"),
+		)
+
+		result, err := eventHandler.DryRun(ctx, event)
+		require.NoError(t, err)
+
+		assert.Empty(t, result.DestinationIDs)
+		for _, destination := range result.Destinations {
+			if destination.ID == "d2" {
+				assert.Equal(t, publishmq.SuppressedTopic, destination.SuppressionReason)
+			} else {
+				assert.Equal(t, publishmq.SuppressedNotTargeted, destination.SuppressionReason)
+			}
+		}
+	})
+
+	t.Run("unknown destination", func(t *testing.T) {
+		event := testutil.EventFactory.AnyPointer(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithDestinationID("missing"),
+		)
+
+		result, err := eventHandler.DryRun(ctx, event)
+		require.NoError(t, err)
+
+		assert.Empty(t, result.DestinationIDs)
+		last := result.Destinations[len(result.Destinations)-1]
+		assert.Equal(t, publishmq.DryRunDestination{ID: "missing", SuppressionReason: publishmq.SuppressedNotFound}, last)
+	})
+
+	t.Run("already published", func(t *testing.T) {
+		event := testutil.EventFactory.AnyPointer(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithTopic("user.created"),
+		)
+		require.NoError(t, idem.MarkProcessed(ctx, "idempotency:publishmq:"+event.ID))
+
+		result, err := eventHandler.DryRun(ctx, event)
+		require.NoError(t, err)
+
+		assert.True(t, result.Duplicate)
+	})
+
+	t.Run("invalid topic", func(t *testing.T) {
+		event := testutil.EventFactory.AnyPointer(
+			testutil.EventFactory.WithTenantID(tenant.ID),
+			testutil.EventFactory.WithTopic("unknown"),
+		)
+
+		_, err := eventHandler.DryRun(ctx, event)
+		assert.ErrorIs(t, err, publishmq.ErrInvalidTopic)
+	})
+}
@@ -3,6 +3,8 @@ package publishmq
 import (
 	"context"
 	"errors"
+	"fmt"
+	"maps"
 	"slices"
 	"sync"
 	"time"
@@ -13,6 +15,7 @@ import (
 	"github.com/hookdeck/outpost/internal/idempotence"
 	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/publishhook"
 	"github.com/hookdeck/outpost/internal/tenantstore"
 	"go.uber.org/zap"
 	"golang.org/x/sync/errgroup"
@@ -21,11 +24,25 @@ import (
 var (
 	ErrInvalidTopic  = errors.New("invalid topic")
 	ErrRequiredTopic = errors.New("topic is required")
+	ErrRetiredTopic  = errors.New("topic is retired")
 	ErrInvalidData   = errors.New("data must be a valid JSON object")
+	ErrInvalidSource = errors.New("invalid source")
+	// ErrEventRejected is returned for events the publish validation hook
+	// rejected. The hook's reason follows it.
+	ErrEventRejected = errors.New("event rejected")
+	// ErrValidationFailed is returned when the publish validation hook
+	// fails or times out and is configured to fail closed.
+	ErrValidationFailed = errors.New("event validation failed")
 )
 
 type EventHandler interface {
 	Handle(ctx context.Context, event *models.Event) (*HandleResult, error)
+	// HandleGroup handles a group of events that are accepted or rejected
+	// together.
+	HandleGroup(ctx context.Context, groupID string, events []*models.Event) ([]*HandleResult, error)
+	// DryRun validates the event and reports the destinations it would be
+	// delivered to, without enqueuing anything.
+	DryRun(ctx context.Context, event *models.Event) (*DryRunResult, error)
 }
 
 type HandleResult struct {
@@ -34,14 +51,91 @@ type HandleResult struct {
 	DestinationIDs []string `json:"destination_ids"`
 }
 
+// LifecycleNotifier is told about every accepted event and the destinations
+// it was fanned out to.
+type LifecycleNotifier interface {
+	EventAccepted(event *models.Event, destinationIDs []string)
+}
+
+type EventHandlerOption func(*eventHandler)
+
+// WithLifecycleNotifier notifies the producer's lifecycle callback of each
+// accepted event. Duplicates of an already accepted event are not notified.
+func WithLifecycleNotifier(notifier LifecycleNotifier) EventHandlerOption {
+	return func(h *eventHandler) {
+		h.lifecycle = notifier
+	}
+}
+
+// WithTopicNamespace applies the deployment's topic namespace to the topics
+// of handled events. Events may be published with or without it.
+func WithTopicNamespace(namespace models.TopicNamespace) EventHandlerOption {
+	return func(h *eventHandler) {
+		h.namespace = namespace
+	}
+}
+
+// TopicStatsRecorder records the topic and payload size of each accepted
+// event. Satisfied by *topicstats.Store.
+type TopicStatsRecorder interface {
+	Record(ctx context.Context, tenantID, topic string, size int) error
+}
+
+// WithTopicStats records each accepted event in the topic stats. Duplicates
+// of an already accepted event are not recorded.
+func WithTopicStats(stats TopicStatsRecorder) EventHandlerOption {
+	return func(h *eventHandler) {
+		h.topicStats = stats
+	}
+}
+
+// TopicLister lists the topics events may be published to. Satisfied by
+// *topicstore.Store.
+type TopicLister interface {
+	Topics(ctx context.Context) []string
+}
+
+// WithTopicLister validates the topics of handled events against the topics
+// listed by topics, such as those created at runtime, instead of the topics
+// the handler was created with.
+func WithTopicLister(topics TopicLister) EventHandlerOption {
+	return func(h *eventHandler) {
+		h.topicLister = topics
+	}
+}
+
+// WithTopicLifecycle rejects events of the topics retired in lifecycle, such
+// as those retired at runtime, instead of the retired topics the handler was
+// created with.
+func WithTopicLifecycle(lifecycle models.TopicLifecycleSource) EventHandlerOption {
+	return func(h *eventHandler) {
+		h.topicLifecycle = lifecycle
+	}
+}
+
+// WithPublishHook validates events of the hook's topics before they are
+// matched, rejecting them or adding metadata. Dry runs skip validation.
+func WithPublishHook(hook publishhook.Hook) EventHandlerOption {
+	return func(h *eventHandler) {
+		h.publishHook = hook
+	}
+}
+
 type eventHandler struct {
-	emeter      emetrics.OutpostMetrics
-	eventTracer eventtracer.EventTracer
-	logger      *logging.Logger
-	idempotence idempotence.Idempotence
-	deliveryMQ  *deliverymq.DeliveryMQ
-	tenantStore tenantstore.TenantStore
-	topics      []string
+	emeter         emetrics.OutpostMetrics
+	eventTracer    eventtracer.EventTracer
+	logger         *logging.Logger
+	idempotence    idempotence.Idempotence
+	deliveryMQ     *deliverymq.DeliveryMQ
+	tenantStore    tenantstore.TenantStore
+	topics         []string
+	topicLister    TopicLister
+	retired        []string
+	topicLifecycle models.TopicLifecycleSource
+	namespace      models.TopicNamespace
+	lifecycle      LifecycleNotifier
+	publishHook    publishhook.Hook
+	topicStats     TopicStatsRecorder
 }
 
 func NewEventHandler(
@@ -50,7 +144,9 @@ func NewEventHandler(
 	tenantStore tenantstore.TenantStore,
 	eventTracer eventtracer.EventTracer,
 	topics []string,
+	retiredTopics []string,
 	idempotence idempotence.Idempotence,
+	opts ...EventHandlerOption,
 ) EventHandler {
 	emeter, _ := emetrics.New()
 	eventHandler := &eventHandler{
@@ -60,24 +156,38 @@ func NewEventHandler(
 		tenantStore: tenantStore,
 		eventTracer: eventTracer,
 		topics:      topics,
+		retired:     retiredTopics,
 		emeter:      emeter,
 	}
+	for _, opt := range opts {
+		opt(eventHandler)
+	}
 	return eventHandler
 }
 
 var _ EventHandler = (*eventHandler)(nil)
 
 func (h *eventHandler) Handle(ctx context.Context, event *models.Event) (*HandleResult, error) {
-	if len(h.topics) > 0 && event.Topic == "" {
-		return nil, ErrRequiredTopic
+	if err := h.validate(ctx, event); err != nil {
+		return nil, err
 	}
-	if len(h.topics) > 0 && event.Topic != "*" && !slices.Contains(h.topics, event.Topic) {
-		return nil, ErrInvalidTopic
+	if err := h.runPublishHook(ctx, event); err != nil {
+		return nil, err
 	}
+	return h.accept(ctx, event)
+}
+
+// accept enqueues a validated event for the destinations it matches.
+func (h *eventHandler) accept(ctx context.Context, event *models.Event) (*HandleResult, error) {
+	event.Topic = h.namespace.Apply(event.Topic)
 
 	logger := h.logger.Ctx(ctx)
 	receivedAt := time.Now()
 
+	// Taken before the event is enqueued, so deliveries, logs and replays
+	// all carry the checksum of the data as published.
+	event.Checksum = models.DataChecksum(event.Data)
+
 	// Wide event state: populated by the rest of Handle and emitted as a single
 	// audit at the end. Replaces the separate "processing event" and per-
 	// destination "delivery task enqueued" audits.
@@ -108,6 +218,12 @@ func (h *eventHandler) Handle(ctx context.Context, event *models.Event) (*Handle
 		if event.DestinationID != "" {
 			fields = append(fields, zap.String("destination_id", event.DestinationID))
 		}
+		if event.Source != "" {
+			fields = append(fields, zap.String("source", event.Source))
+		}
+		if event.GroupID != "" {
+			fields = append(fields, zap.String("group_id", event.GroupID))
+		}
 		if matchFailed {
 			fields = append(fields, zap.Bool("match_failed", true))
 		}
@@ -119,14 +235,16 @@ func (h *eventHandler) Handle(ctx context.Context, event *models.Event) (*Handle
 
 	var err error
 
-	// Branch: specific destination vs topic-based matching
+	// Branch: specific destination vs topic-based matching. Subscriptions
+	// don't carry the topic namespace, so they match the stripped topic.
+	matchEvent := h.namespace.StripEvent(*event)
 	if event.DestinationID != "" {
-		matched, err = h.matchSpecificDestination(ctx, event)
+		matched, err = h.matchSpecificDestination(ctx, &matchEvent)
 		if err != nil {
 			return nil, err
 		}
 	} else {
-		matched, err = h.tenantStore.MatchEvent(ctx, *event)
+		matched, err = h.tenantStore.MatchEvent(ctx, matchEvent)
 		if err != nil {
 			matchFailed = true
 			logger.Error("failed to match event destinations",
@@ -151,6 +269,8 @@ func (h *eventHandler) Handle(ctx context.Context, event *models.Event) (*Handle
 	}
 
 	if len(matched) == 0 {
+		h.notifyAccepted(event, matched)
+		h.recordTopicStats(ctx, event)
 		return result, nil
 	}
 
@@ -169,11 +289,100 @@ func (h *eventHandler) Handle(ctx context.Context, event *models.Event) (*Handle
 	if !executed {
 		duplicate = true
 		result.Duplicate = true
+	} else {
+		h.notifyAccepted(event, matched)
+		h.recordTopicStats(ctx, event)
 	}
 
 	return result, nil
 }
 
+// validate checks the event's topic and source.
+func (h *eventHandler) validate(ctx context.Context, event *models.Event) error {
+	topic := h.namespace.Strip(event.Topic)
+	topics := h.topics
+	if h.topicLister != nil {
+		topics = h.topicLister.Topics(ctx)
+	}
+	if len(topics) > 0 && topic == "" {
+		return ErrRequiredTopic
+	}
+	if len(topics) > 0 && topic != "*" && !models.TopicAvailable(topics, topic) {
+		return ErrInvalidTopic
+	}
+	retired := h.retired
+	if h.topicLifecycle != nil {
+		retired = h.topicLifecycle.Lifecycle(ctx).Retired
+	}
+	if slices.Contains(retired, topic) {
+		return ErrRetiredTopic
+	}
+	if !models.ValidSource(event.Source) {
+		return ErrInvalidSource
+	}
+	return nil
+}
+
+// runPublishHook validates the event with the publish hook, if it applies to
+// the event's topic, and merges the metadata it adds into the event.
+func (h *eventHandler) runPublishHook(ctx context.Context, event *models.Event) error {
+	if !h.publishHook.Applies(h.namespace.Strip(event.Topic)) {
+		return nil
+	}
+	logger := h.logger.Ctx(ctx)
+	verdict, err := h.publishHook.Validate(ctx, event)
+	if err != nil {
+		if h.publishHook.FailClosed {
+			return fmt.Errorf("%w: %w", ErrValidationFailed, err)
+		}
+		logger.Warn("event validation failed, publishing unvalidated",
+			zap.Error(err),
+			zap.String("event_id", event.ID),
+			zap.String("tenant_id", event.TenantID))
+		return nil
+	}
+	if verdict.Reject {
+		logger.Info("event rejected by validation hook",
+			zap.String("event_id", event.ID),
+			zap.String("tenant_id", event.TenantID),
+			zap.String("topic", event.Topic),
+			zap.String("reason", verdict.Reason))
+		if verdict.Reason == "" {
+			return ErrEventRejected
+		}
+		return fmt.Errorf("%w: %s", ErrEventRejected, verdict.Reason)
+	}
+	if len(verdict.Metadata) > 0 {
+		metadata := make(models.Metadata, len(event.Metadata)+len(verdict.Metadata))
+		maps.Copy(metadata, event.Metadata)
+		maps.Copy(metadata, verdict.Metadata)
+		event.Metadata = metadata
+	}
+	return nil
+}
+
+func (h *eventHandler) notifyAccepted(event *models.Event, destinationIDs []string) {
+	if h.lifecycle == nil {
+		return
+	}
+	h.lifecycle.EventAccepted(event, destinationIDs)
+}
+
+// recordTopicStats records the accepted event in the topic stats. A failure
+// is logged and doesn't fail the publish.
+func (h *eventHandler) recordTopicStats(ctx context.Context, event *models.Event) {
+	if h.topicStats == nil {
+		return
+	}
+	if err := h.topicStats.Record(ctx, event.TenantID, event.Topic, len(event.Data)); err != nil {
+		h.logger.Ctx(ctx).Warn("failed to record topic stats",
+			zap.Error(err),
+			zap.String("event_id", event.ID),
+			zap.String("tenant_id", event.TenantID),
+			zap.String("topic", event.Topic))
+	}
+}
+
 func (h *eventHandler) doPublish(ctx context.Context, event *models.Event, matchedDestinations []string, enqueuedMu *sync.Mutex, enqueued *[]string) error {
 	_, span := h.eventTracer.Receive(ctx, event)
 	defer span.End()
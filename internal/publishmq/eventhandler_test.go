@@ -2,6 +2,7 @@ package publishmq_test
 
 import (
 	"context"
+	"errors"
 	"log"
 	"testing"
 	"time"
@@ -10,10 +11,13 @@ import (
 	"github.com/hookdeck/outpost/internal/idempotence"
 	"github.com/hookdeck/outpost/internal/idgen"
 	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/publishhook"
 	"github.com/hookdeck/outpost/internal/publishmq"
 	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/topicstats"
 	"github.com/hookdeck/outpost/internal/util/testinfra"
 	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
@@ -38,6 +42,7 @@ func TestIntegrationPublishMQEventHandler_Concurrency(t *testing.T) {
 		tenantStore,
 		mockEventTracer,
 		testutil.TestTopics,
+		nil,
 		idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
 	)
 
@@ -101,6 +106,7 @@ func TestEventHandler_WildcardTopic(t *testing.T) {
 		tenantStore,
 		mockEventTracer,
 		testutil.TestTopics,
+		nil,
 		idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
 	)
 
@@ -230,6 +236,7 @@ func TestEventHandler_HandleResult(t *testing.T) {
 		tenantStore,
 		testutil.NewMockEventTracer(tracetest.NewInMemoryExporter()),
 		testutil.TestTopics,
+		nil,
 		idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
 	)
 
@@ -260,6 +267,7 @@ func TestEventHandler_HandleResult(t *testing.T) {
 		require.Equal(t, event.ID, result.EventID)
 		require.False(t, result.Duplicate)
 		require.Len(t, result.DestinationIDs, 3)
+		require.Equal(t, models.DataChecksum(event.Data), event.Checksum)
 	})
 
 	t.Run("normal publish with wildcard destination topics", func(t *testing.T) {
@@ -300,6 +308,47 @@ func TestEventHandler_HandleResult(t *testing.T) {
 		require.ElementsMatch(t, []string{matchingDestinations[0].ID, matchingDestinations[1].ID}, result.DestinationIDs)
 	})
 
+	t.Run("topic namespace", func(t *testing.T) {
+		namespacedHandler := publishmq.NewEventHandler(
+			logger,
+			deliveryMQ,
+			tenantStore,
+			testutil.NewMockEventTracer(tracetest.NewInMemoryExporter()),
+			testutil.TestTopics,
+			nil,
+			idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
+			publishmq.WithTopicNamespace("acme"),
+		)
+		namespacedTenant := models.Tenant{
+			ID:        idgen.String(),
+			CreatedAt: time.Now(),
+		}
+		require.NoError(t, tenantStore.UpsertTenant(ctx, namespacedTenant))
+		dest := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithTenantID(namespacedTenant.ID),
+			testutil.DestinationFactory.WithTopics([]string{"user.created"}),
+		)
+		require.NoError(t, tenantStore.UpsertDestination(ctx, dest))
+
+		for _, topic := range []string{"user.created", "acme.user.created"} {
+			event := testutil.EventFactory.AnyPointer(
+				testutil.EventFactory.WithTenantID(namespacedTenant.ID),
+				testutil.EventFactory.WithTopic(topic),
+			)
+
+			result, err := namespacedHandler.Handle(ctx, event)
+			require.NoError(t, err, topic)
+			require.Equal(t, []string{dest.ID}, result.DestinationIDs, "subscriptions match the topic without its namespace")
+			require.Equal(t, "acme.user.created", event.Topic, "the namespace is applied once")
+		}
+
+		_, err := namespacedHandler.Handle(ctx, testutil.EventFactory.AnyPointer(
+			testutil.EventFactory.WithTenantID(namespacedTenant.ID),
+			testutil.EventFactory.WithTopic("acme.unknown"),
+		))
+		require.ErrorIs(t, err, publishmq.ErrInvalidTopic)
+	})
+
 	t.Run("no destinations matched", func(t *testing.T) {
 		event := testutil.EventFactory.AnyPointer(
 			testutil.EventFactory.WithTenantID(tenant.ID),
@@ -460,6 +509,7 @@ func TestEventHandler_Filter(t *testing.T) {
 		tenantStore,
 		testutil.NewMockEventTracer(tracetest.NewInMemoryExporter()),
 		testutil.TestTopics,
+		nil,
 		idempotence.New(testutil.CreateTestRedisClient(t), idempotence.WithSuccessfulTTL(24*time.Hour)),
 	)
 
@@ -639,3 +689,139 @@ func TestEventHandler_Filter(t *testing.T) {
 		msg.Ack()
 	})
 }
+
+func TestEventHandler_RetiredTopic(t *testing.T) {
+	t.Parallel()
+
+	eventHandler := publishmq.NewEventHandler(
+		testutil.CreateTestLogger(t),
+		nil,
+		nil,
+		testutil.NewMockEventTracer(tracetest.NewInMemoryExporter()),
+		testutil.TestTopics,
+		[]string{"user.deleted"},
+		nil,
+	)
+
+	_, err := eventHandler.Handle(context.Background(), testutil.EventFactory.AnyPointer(
+		testutil.EventFactory.WithTopic("user.deleted"),
+	))
+	require.ErrorIs(t, err, publishmq.ErrRetiredTopic)
+}
+
+func TestEventHandler_InvalidSource(t *testing.T) {
+	t.Parallel()
+
+	eventHandler := publishmq.NewEventHandler(
+		testutil.CreateTestLogger(t),
+		nil,
+		nil,
+		testutil.NewMockEventTracer(tracetest.NewInMemoryExporter()),
+		testutil.TestTopics,
+		nil,
+		nil,
+	)
+
+	_, err := eventHandler.Handle(context.Background(), testutil.EventFactory.AnyPointer(
+		testutil.EventFactory.WithSource("billing service"),
+	))
+	require.ErrorIs(t, err, publishmq.ErrInvalidSource)
+}
+
+func TestEventHandler_PublishHook(t *testing.T) {
+	t.Parallel()
+
+	newHandler := func(t *testing.T, hook publishhook.Hook) publishmq.EventHandler {
+		return publishmq.NewEventHandler(
+			testutil.CreateTestLogger(t),
+			nil,
+			tenantstore.NewMemTenantStore(),
+			testutil.NewMockEventTracer(tracetest.NewInMemoryExporter()),
+			testutil.TestTopics,
+			nil,
+			nil,
+			publishmq.WithPublishHook(hook),
+		)
+	}
+	validator := func(verdict publishhook.Verdict, err error) publishhook.ValidatorFunc {
+		return func(ctx context.Context, event *models.Event) (publishhook.Verdict, error) {
+			return verdict, err
+		}
+	}
+
+	t.Run("rejects events", func(t *testing.T) {
+		t.Parallel()
+		eventHandler := newHandler(t, publishhook.Hook{Validator: validator(publishhook.Verdict{Reject: true, Reason: "suspected fraud"}, nil)})
+
+		_, err := eventHandler.Handle(context.Background(), testutil.EventFactory.AnyPointer())
+		require.ErrorIs(t, err, publishmq.ErrEventRejected)
+		assert.Contains(t, err.Error(), "suspected fraud")
+	})
+
+	t.Run("adds metadata", func(t *testing.T) {
+		t.Parallel()
+		eventHandler := newHandler(t, publishhook.Hook{Validator: validator(publishhook.Verdict{Metadata: map[string]string{"risk": "low"}}, nil)})
+
+		event := testutil.EventFactory.AnyPointer(testutil.EventFactory.WithMetadata(map[string]string{"source": "api"}))
+		_, err := eventHandler.Handle(context.Background(), event)
+		require.NoError(t, err)
+		assert.Equal(t, models.Metadata{"source": "api", "risk": "low"}, event.Metadata)
+	})
+
+	t.Run("skips other topics", func(t *testing.T) {
+		t.Parallel()
+		eventHandler := newHandler(t, publishhook.Hook{
+			Validator: validator(publishhook.Verdict{Reject: true}, nil),
+			Topics:    []string{"user.deleted"},
+		})
+
+		_, err := eventHandler.Handle(context.Background(), testutil.EventFactory.AnyPointer(testutil.EventFactory.WithTopic("user.created")))
+		require.NoError(t, err)
+	})
+
+	t.Run("publishes unvalidated when the hook fails", func(t *testing.T) {
+		t.Parallel()
+		eventHandler := newHandler(t, publishhook.Hook{Validator: validator(publishhook.Verdict{}, errors.New("unreachable"))})
+
+		_, err := eventHandler.Handle(context.Background(), testutil.EventFactory.AnyPointer())
+		require.NoError(t, err)
+	})
+
+	t.Run("fails closed", func(t *testing.T) {
+		t.Parallel()
+		eventHandler := newHandler(t, publishhook.Hook{Validator: validator(publishhook.Verdict{}, errors.New("unreachable")), FailClosed: true})
+
+		_, err := eventHandler.Handle(context.Background(), testutil.EventFactory.AnyPointer())
+		require.ErrorIs(t, err, publishmq.ErrValidationFailed)
+	})
+}
+
+func TestEventHandler_TopicStats(t *testing.T) {
+	t.Parallel()
+
+	stats := topicstats.NewStore(testutil.CreateTestRedisClient(t))
+	eventHandler := publishmq.NewEventHandler(
+		testutil.CreateTestLogger(t),
+		nil,
+		tenantstore.NewMemTenantStore(),
+		testutil.NewMockEventTracer(tracetest.NewInMemoryExporter()),
+		testutil.TestTopics,
+		nil,
+		nil,
+		publishmq.WithTopicStats(stats),
+	)
+
+	event := testutil.EventFactory.AnyPointer(
+		testutil.EventFactory.WithTenantID("t1"),
+		testutil.EventFactory.WithTopic("user.created"),
+	)
+	_, err := eventHandler.Handle(context.Background(), event)
+	require.NoError(t, err)
+
+	report, err := stats.Report(context.Background(), "t1", 1, 0)
+	require.NoError(t, err)
+	require.Len(t, report.Topics, 1)
+	assert.Equal(t, "user.created", report.Topics[0].Topic)
+	assert.Equal(t, int64(1), report.Topics[0].Count)
+	assert.Equal(t, int64(len(event.Data)), report.Topics[0].Bytes)
+}
@@ -0,0 +1,122 @@
+package publishmq
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hookdeck/outpost/internal/mqs"
+)
+
+var (
+	ErrInvalidEventMapping = errors.New("invalid event mapping")
+	ErrUnmappedTenant      = errors.New("message has no tenant")
+)
+
+const (
+	mappingSourceHeader = "header:"
+	mappingSourceField  = "field:"
+	mappingSourceTopic  = "topic"
+	mappingSourceKey    = "key"
+)
+
+// EventMapping builds events from messages that aren't published events, such
+// as the domain events of another system's Kafka topics. The message body is
+// the event's data, and each field is read from the source its spec names:
+//
+//   - "header:<name>" reads a message header
+//   - "field:<path>" reads a string or number at a dot-separated path of the body
+//   - "topic" reads the topic the message was consumed from
+//   - "key" reads the message key
+//
+// Topic and ID are optional. Without an ID, the event's ID is derived from the
+// message's position in the queue, so a redelivered message is deduplicated.
+type EventMapping struct {
+	TenantID string
+	Topic    string
+	ID       string
+}
+
+// Validate returns an error if a spec names an unknown source.
+func (m *EventMapping) Validate() error {
+	if m.TenantID == "" {
+		return fmt.Errorf("%w: tenant is required", ErrInvalidEventMapping)
+	}
+	for _, spec := range []string{m.TenantID, m.Topic, m.ID} {
+		if err := validateMappingSpec(spec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateMappingSpec(spec string) error {
+	switch {
+	case spec == "", spec == mappingSourceTopic, spec == mappingSourceKey:
+		return nil
+	case strings.HasPrefix(spec, mappingSourceHeader) && len(spec) > len(mappingSourceHeader):
+		return nil
+	case strings.HasPrefix(spec, mappingSourceField) && len(spec) > len(mappingSourceField):
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown source %q", ErrInvalidEventMapping, spec)
+	}
+}
+
+func (m *EventMapping) publishedEvent(msg *mqs.Message) (PublishedEvent, error) {
+	var body map[string]any
+	if err := json.Unmarshal(msg.Body, &body); err != nil || body == nil {
+		return PublishedEvent{}, ErrInvalidData
+	}
+	tenantID := resolveMappingSpec(m.TenantID, msg, body)
+	if tenantID == "" {
+		return PublishedEvent{}, ErrUnmappedTenant
+	}
+	id := resolveMappingSpec(m.ID, msg, body)
+	if id == "" {
+		id = msg.LoggableID
+	}
+	return PublishedEvent{
+		ID:       id,
+		TenantID: tenantID,
+		Topic:    resolveMappingSpec(m.Topic, msg, body),
+		Data:     bytes.TrimSpace(msg.Body),
+	}, nil
+}
+
+func resolveMappingSpec(spec string, msg *mqs.Message, body map[string]any) string {
+	switch {
+	case spec == mappingSourceTopic:
+		return msg.Topic
+	case spec == mappingSourceKey:
+		return msg.Key
+	case strings.HasPrefix(spec, mappingSourceHeader):
+		return msg.Headers[strings.TrimPrefix(spec, mappingSourceHeader)]
+	case strings.HasPrefix(spec, mappingSourceField):
+		return lookupField(body, strings.Split(strings.TrimPrefix(spec, mappingSourceField), "."))
+	default:
+		return ""
+	}
+}
+
+func lookupField(body map[string]any, path []string) string {
+	var value any = body
+	for _, name := range path {
+		object, ok := value.(map[string]any)
+		if !ok {
+			return ""
+		}
+		value = object[name]
+	}
+	switch v := value.(type) {
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return ""
+	}
+}
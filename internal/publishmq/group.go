@@ -0,0 +1,57 @@
+package publishmq
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// MaxGroupSize is the largest number of events published in one group.
+const MaxGroupSize = 100
+
+// GroupError reports the event that failed validation in a group, which
+// rejects the whole group.
+type GroupError struct {
+	// Index is the position of the event in the group.
+	Index int
+	Err   error
+}
+
+func (e *GroupError) Error() string {
+	return fmt.Sprintf("event %d: %v", e.Index, e.Err)
+}
+
+func (e *GroupError) Unwrap() error {
+	return e.Err
+}
+
+// HandleGroup validates every event of a group, including with the publish
+// hook, before any is enqueued: when one is rejected, none is, and the error
+// is a *GroupError. The events are then accepted in order, tagged with
+// groupID, and each is delivered on its own.
+//
+// Enqueueing can still fail part way through. The events accepted until then
+// stay accepted, so a group retried with the same event IDs is deduplicated
+// like any republished event.
+func (h *eventHandler) HandleGroup(ctx context.Context, groupID string, events []*models.Event) ([]*HandleResult, error) {
+	for i, event := range events {
+		if err := h.validate(ctx, event); err != nil {
+			return nil, &GroupError{Index: i, Err: err}
+		}
+		if err := h.runPublishHook(ctx, event); err != nil {
+			return nil, &GroupError{Index: i, Err: err}
+		}
+	}
+
+	results := make([]*HandleResult, len(events))
+	for i, event := range events {
+		event.GroupID = groupID
+		result, err := h.accept(ctx, event)
+		if err != nil {
+			return nil, fmt.Errorf("failed to accept event %d of group %s: %w", i, groupID, err)
+		}
+		results[i] = result
+	}
+	return results, nil
+}
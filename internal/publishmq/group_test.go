@@ -0,0 +1,75 @@
+package publishmq_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/publishmq"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+type acceptedRecorder struct {
+	accepted []*models.Event
+}
+
+func (r *acceptedRecorder) EventAccepted(event *models.Event, destinationIDs []string) {
+	r.accepted = append(r.accepted, event)
+}
+
+func TestEventHandler_HandleGroup(t *testing.T) {
+	t.Parallel()
+
+	newHandler := func(t *testing.T) (publishmq.EventHandler, *acceptedRecorder) {
+		recorder := &acceptedRecorder{}
+		return publishmq.NewEventHandler(
+			testutil.CreateTestLogger(t),
+			nil,
+			tenantstore.NewMemTenantStore(),
+			testutil.NewMockEventTracer(tracetest.NewInMemoryExporter()),
+			testutil.TestTopics,
+			nil,
+			nil,
+			publishmq.WithLifecycleNotifier(recorder),
+		), recorder
+	}
+
+	t.Run("accepts every event with the group ID", func(t *testing.T) {
+		t.Parallel()
+		eventHandler, recorder := newHandler(t)
+		events := []*models.Event{
+			testutil.EventFactory.AnyPointer(testutil.EventFactory.WithID("e1")),
+			testutil.EventFactory.AnyPointer(testutil.EventFactory.WithID("e2")),
+		}
+
+		results, err := eventHandler.HandleGroup(context.Background(), "grp_1", events)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, "e1", results[0].EventID)
+		assert.Equal(t, "e2", results[1].EventID)
+		require.Len(t, recorder.accepted, 2)
+		for _, event := range recorder.accepted {
+			assert.Equal(t, "grp_1", event.GroupID)
+		}
+	})
+
+	t.Run("rejects the group when one event is invalid", func(t *testing.T) {
+		t.Parallel()
+		eventHandler, recorder := newHandler(t)
+		events := []*models.Event{
+			testutil.EventFactory.AnyPointer(),
+			testutil.EventFactory.AnyPointer(testutil.EventFactory.WithTopic("unknown.topic")),
+		}
+
+		_, err := eventHandler.HandleGroup(context.Background(), "grp_1", events)
+		require.ErrorIs(t, err, publishmq.ErrInvalidTopic)
+		var groupErr *publishmq.GroupError
+		require.ErrorAs(t, err, &groupErr)
+		assert.Equal(t, 1, groupErr.Index)
+		assert.Empty(t, recorder.accepted)
+	})
+}
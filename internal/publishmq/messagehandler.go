@@ -3,6 +3,7 @@ package publishmq
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"time"
 
 	"github.com/hookdeck/outpost/internal/consumer"
@@ -11,21 +12,52 @@ import (
 	"github.com/hookdeck/outpost/internal/mqs"
 )
 
+// RateLimiter holds a tenant's events to its publish rate limit. Satisfied by
+// *publishrate.Limiter.
+type RateLimiter interface {
+	Wait(ctx context.Context, tenantID string) error
+}
+
 type messageHandler struct {
 	eventHandler EventHandler
+	rateLimiter  RateLimiter
+	eventMapping *EventMapping
+}
+
+type MessageHandlerOption func(*messageHandler)
+
+// WithRateLimiter holds each message until the tenant's publish rate limit
+// allows it. Unlike the API, the queue can't reject an event, so throttling
+// slows the consumer down instead.
+func WithRateLimiter(rateLimiter RateLimiter) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.rateLimiter = rateLimiter
+	}
 }
 
-func NewMessageHandler(eventHandler EventHandler) consumer.MessageHandler {
-	return &messageHandler{
+// WithEventMapping builds events from messages with the mapping instead of
+// reading them as published events.
+func WithEventMapping(mapping *EventMapping) MessageHandlerOption {
+	return func(h *messageHandler) {
+		h.eventMapping = mapping
+	}
+}
+
+func NewMessageHandler(eventHandler EventHandler, opts ...MessageHandlerOption) consumer.MessageHandler {
+	h := &messageHandler{
 		eventHandler: eventHandler,
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 var _ consumer.MessageHandler = (*messageHandler)(nil)
 
 func (h *messageHandler) Handle(ctx context.Context, msg *mqs.Message) error {
-	var publishedEvent PublishedEvent
-	if err := json.Unmarshal(msg.Body, &publishedEvent); err != nil {
+	publishedEvent, err := h.publishedEvent(msg)
+	if err != nil {
 		msg.Nack()
 		return err
 	}
@@ -35,8 +67,19 @@ func (h *messageHandler) Handle(ctx context.Context, msg *mqs.Message) error {
 		msg.Nack()
 		return ErrInvalidData
 	}
+	if h.rateLimiter != nil {
+		if err := h.rateLimiter.Wait(ctx, publishedEvent.TenantID); err != nil {
+			msg.Nack()
+			return err
+		}
+	}
 	event := publishedEvent.toEvent()
-	_, err := h.eventHandler.Handle(ctx, &event)
+	_, err = h.eventHandler.Handle(ctx, &event)
+	if errors.Is(err, ErrEventRejected) {
+		// A rejection is final, so redelivering the message wouldn't help.
+		msg.Ack()
+		return err
+	}
 	if err != nil {
 		msg.Nack()
 		return err
@@ -45,11 +88,21 @@ func (h *messageHandler) Handle(ctx context.Context, msg *mqs.Message) error {
 	return nil
 }
 
+func (h *messageHandler) publishedEvent(msg *mqs.Message) (PublishedEvent, error) {
+	if h.eventMapping != nil {
+		return h.eventMapping.publishedEvent(msg)
+	}
+	var publishedEvent PublishedEvent
+	err := json.Unmarshal(msg.Body, &publishedEvent)
+	return publishedEvent, err
+}
+
 type PublishedEvent struct {
 	ID               string            `json:"id"`
 	TenantID         string            `json:"tenant_id" binding:"required"`
 	DestinationID    string            `json:"destination_id"`
 	Topic            string            `json:"topic"`
+	Source           string            `json:"source"`
 	EligibleForRetry *bool             `json:"eligible_for_retry"`
 	Time             time.Time         `json:"time"`
 	Metadata         map[string]string `json:"metadata"`
@@ -74,6 +127,7 @@ func (p *PublishedEvent) toEvent() models.Event {
 		TenantID:         p.TenantID,
 		DestinationID:    p.DestinationID,
 		Topic:            p.Topic,
+		Source:           p.Source,
 		EligibleForRetry: eligibleForRetry,
 		Time:             eventTime,
 		Metadata:         p.Metadata,
@@ -36,6 +36,22 @@ func (m *mockEventHandler) Handle(_ context.Context, event *models.Event) (*publ
 	return &publishmq.HandleResult{EventID: event.ID}, nil
 }
 
+func (m *mockEventHandler) HandleGroup(ctx context.Context, groupID string, events []*models.Event) ([]*publishmq.HandleResult, error) {
+	results := make([]*publishmq.HandleResult, len(events))
+	for i, event := range events {
+		result, err := m.Handle(ctx, event)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+func (m *mockEventHandler) DryRun(_ context.Context, event *models.Event) (*publishmq.DryRunResult, error) {
+	return &publishmq.DryRunResult{EventID: event.ID, DryRun: true}, nil
+}
+
 func TestMessageHandler_InvalidData(t *testing.T) {
 	tests := []struct {
 		name string
@@ -84,3 +100,122 @@ func TestMessageHandler_InvalidMetadata(t *testing.T) {
 	assert.True(t, qm.nacked, "message should be nacked")
 	assert.Empty(t, eh.calls, "event handler should not be called")
 }
+
+type mockRateLimiter struct {
+	tenantIDs []string
+	err       error
+}
+
+func (m *mockRateLimiter) Wait(_ context.Context, tenantID string) error {
+	m.tenantIDs = append(m.tenantIDs, tenantID)
+	return m.err
+}
+
+func TestMessageHandler_RateLimit(t *testing.T) {
+	body := []byte(`{"tenant_id":"t1","topic":"user.created","data":{"key":"value"}}`)
+
+	t.Run("waits for the tenant before handling", func(t *testing.T) {
+		eh := &mockEventHandler{}
+		limiter := &mockRateLimiter{}
+		handler := publishmq.NewMessageHandler(eh, publishmq.WithRateLimiter(limiter))
+
+		qm := &mockQueueMessage{}
+		err := handler.Handle(context.Background(), &mqs.Message{QueueMessage: qm, Body: body})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"t1"}, limiter.tenantIDs)
+		assert.Len(t, eh.calls, 1)
+		assert.True(t, qm.acked, "message should be acked")
+	})
+
+	t.Run("nacks when the wait fails", func(t *testing.T) {
+		eh := &mockEventHandler{}
+		handler := publishmq.NewMessageHandler(eh, publishmq.WithRateLimiter(&mockRateLimiter{err: context.Canceled}))
+
+		qm := &mockQueueMessage{}
+		err := handler.Handle(context.Background(), &mqs.Message{QueueMessage: qm, Body: body})
+
+		require.ErrorIs(t, err, context.Canceled)
+		assert.True(t, qm.nacked, "message should be nacked")
+		assert.Empty(t, eh.calls, "event handler should not be called")
+	})
+}
+
+func TestMessageHandler_EventMapping(t *testing.T) {
+	mapping := &publishmq.EventMapping{
+		TenantID: "header:tenant-id",
+		Topic:    "topic",
+		ID:       "field:order.id",
+	}
+	require.NoError(t, mapping.Validate())
+
+	t.Run("builds the event from the message", func(t *testing.T) {
+		eh := &mockEventHandler{}
+		handler := publishmq.NewMessageHandler(eh, publishmq.WithEventMapping(mapping))
+
+		qm := &mockQueueMessage{}
+		err := handler.Handle(context.Background(), &mqs.Message{
+			QueueMessage: qm,
+			LoggableID:   "orders-0-7",
+			Body:         []byte(`{"order":{"id":42,"total":10}}`),
+			Topic:        "order.created",
+			Headers:      map[string]string{"tenant-id": "t1"},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, eh.calls, 1)
+		assert.Equal(t, "42", eh.calls[0].ID)
+		assert.Equal(t, "t1", eh.calls[0].TenantID)
+		assert.Equal(t, "order.created", eh.calls[0].Topic)
+		assert.JSONEq(t, `{"order":{"id":42,"total":10}}`, string(eh.calls[0].Data))
+		assert.True(t, qm.acked, "message should be acked")
+	})
+
+	t.Run("defaults the ID to the message's position", func(t *testing.T) {
+		eh := &mockEventHandler{}
+		handler := publishmq.NewMessageHandler(eh, publishmq.WithEventMapping(mapping))
+
+		err := handler.Handle(context.Background(), &mqs.Message{
+			QueueMessage: &mockQueueMessage{},
+			LoggableID:   "orders-0-7",
+			Body:         []byte(`{"total":10}`),
+			Headers:      map[string]string{"tenant-id": "t1"},
+		})
+
+		require.NoError(t, err)
+		require.Len(t, eh.calls, 1)
+		assert.Equal(t, "orders-0-7", eh.calls[0].ID)
+	})
+
+	t.Run("nacks messages without a tenant", func(t *testing.T) {
+		eh := &mockEventHandler{}
+		handler := publishmq.NewMessageHandler(eh, publishmq.WithEventMapping(mapping))
+
+		qm := &mockQueueMessage{}
+		err := handler.Handle(context.Background(), &mqs.Message{QueueMessage: qm, Body: []byte(`{"total":10}`)})
+
+		require.ErrorIs(t, err, publishmq.ErrUnmappedTenant)
+		assert.True(t, qm.nacked, "message should be nacked")
+		assert.Empty(t, eh.calls, "event handler should not be called")
+	})
+
+	t.Run("nacks bodies that aren't JSON objects", func(t *testing.T) {
+		eh := &mockEventHandler{}
+		handler := publishmq.NewMessageHandler(eh, publishmq.WithEventMapping(mapping))
+
+		qm := &mockQueueMessage{}
+		err := handler.Handle(context.Background(), &mqs.Message{
+			QueueMessage: qm,
+			Body:         []byte(`[1,2,3]`),
+			Headers:      map[string]string{"tenant-id": "t1"},
+		})
+
+		require.ErrorIs(t, err, publishmq.ErrInvalidData)
+		assert.True(t, qm.nacked, "message should be nacked")
+	})
+
+	t.Run("rejects unknown sources", func(t *testing.T) {
+		invalid := &publishmq.EventMapping{TenantID: "body:tenant"}
+		require.ErrorIs(t, invalid.Validate(), publishmq.ErrInvalidEventMapping)
+	})
+}
@@ -0,0 +1,280 @@
+// Package publishrate limits the rate at which each tenant publishes events.
+//
+// Each tenant has a token bucket in Redis that holds up to a burst of tokens
+// and refills at a steady rate; publishing an event takes a token. The
+// deployment sets the default limit and a tenant's PublishRateLimit
+// overrides it.
+package publishrate
+
+import (
+	"context"
+	"errors"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Throttle modes, recorded as the "mode" attribute of the
+// outpost.publish.throttled metric.
+const (
+	// ModeReject rejects throttled events, as the publish API does with 429.
+	ModeReject = "reject"
+	// ModeWait holds throttled events until a token is available, as the
+	// publish queue consumers do.
+	ModeWait = "wait"
+)
+
+// defaultOverrideTTL is how long a tenant's override is cached before it is
+// read from the tenant store again.
+const defaultOverrideTTL = 30 * time.Second
+
+// takeScript refills the bucket for the time elapsed since it was last used,
+// then takes a token if one is available. It returns whether a token was
+// taken, the tokens left and, when none was, the milliseconds until one is.
+// Tokens are stored as strings to keep their fraction.
+const takeScript = `
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+local now = tonumber(ARGV[3])
+local bucket = redis.call("HMGET", KEYS[1], "tokens", "ts")
+local tokens = tonumber(bucket[1])
+local ts = tonumber(bucket[2])
+if tokens == nil or ts == nil then
+	tokens = burst
+	ts = now
+end
+tokens = math.min(burst, tokens + math.max(0, now - ts) * rate / 1000)
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) * 1000 / rate)
+end
+redis.call("HSET", KEYS[1], "tokens", tostring(tokens), "ts", tostring(now))
+redis.call("PEXPIRE", KEYS[1], math.ceil(burst * 1000 / rate) + 1000)
+return {allowed, math.floor(tokens), wait}
+`
+
+// TenantRetriever reads the tenant's override. Satisfied by
+// tenantstore.TenantStore. Missing and deleted tenants have none, so their
+// events get the default limit.
+type TenantRetriever interface {
+	RetrieveTenant(ctx context.Context, tenantID string) (*models.Tenant, error)
+}
+
+// Result is the outcome of taking a token.
+type Result struct {
+	Allowed bool
+	// Limit is the tenant's effective limit. A zero PerSecond means the
+	// tenant is not limited.
+	Limit     models.PublishRateLimit
+	Remaining int
+	// RetryAfter is how long until a token is available when none was.
+	RetryAfter time.Duration
+}
+
+type Limiter struct {
+	redisClient  redis.Cmdable
+	tenants      TenantRetriever
+	defaultLimit models.PublishRateLimit
+	deploymentID string
+	clock        clock.Clock
+	overrideTTL  time.Duration
+	throttled    metric.Int64Counter
+
+	mu        sync.Mutex
+	overrides map[string]cachedOverride
+	lastSweep time.Time
+}
+
+type cachedOverride struct {
+	limit     *models.PublishRateLimit
+	fetchedAt time.Time
+}
+
+type Option func(*Limiter)
+
+func WithDeploymentID(deploymentID string) Option {
+	return func(l *Limiter) {
+		l.deploymentID = deploymentID
+	}
+}
+
+// WithClock sets the clock buckets are refilled by.
+func WithClock(c clock.Clock) Option {
+	return func(l *Limiter) {
+		l.clock = c
+	}
+}
+
+// WithOverrideTTL sets how long tenant overrides are cached. Changes to a
+// tenant's limit take up to this long to apply.
+func WithOverrideTTL(ttl time.Duration) Option {
+	return func(l *Limiter) {
+		l.overrideTTL = ttl
+	}
+}
+
+// New returns a limiter applying defaultLimit to tenants without an
+// override.
+func New(redisClient redis.Cmdable, tenants TenantRetriever, defaultLimit models.PublishRateLimit, opts ...Option) *Limiter {
+	l := &Limiter{
+		redisClient:  redisClient,
+		tenants:      tenants,
+		defaultLimit: defaultLimit,
+		clock:        clock.New(),
+		overrideTTL:  defaultOverrideTTL,
+		overrides:    make(map[string]cachedOverride),
+	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	// A failing meter leaves throttling unrecorded rather than failing
+	// publishes.
+	l.throttled, _ = otel.Meter("outpost").Int64Counter("outpost.publish.throttled",
+		metric.WithDescription("Number of publishes throttled by the tenant publish rate limit"),
+	)
+	return l
+}
+
+// Allow takes a token from the tenant's bucket, or reports how long until one
+// is available.
+func (l *Limiter) Allow(ctx context.Context, tenantID string) (Result, error) {
+	result, err := l.take(ctx, tenantID)
+	if err == nil && !result.Allowed {
+		l.record(ctx, ModeReject)
+	}
+	return result, err
+}
+
+// Wait blocks until a token is taken from the tenant's bucket or ctx is done.
+func (l *Limiter) Wait(ctx context.Context, tenantID string) error {
+	recorded := false
+	for {
+		result, err := l.take(ctx, tenantID)
+		if err != nil {
+			return err
+		}
+		if result.Allowed {
+			return nil
+		}
+		if !recorded {
+			l.record(ctx, ModeWait)
+			recorded = true
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-l.clock.After(result.RetryAfter):
+		}
+	}
+}
+
+func (l *Limiter) take(ctx context.Context, tenantID string) (Result, error) {
+	limit, err := l.limit(ctx, tenantID)
+	if err != nil {
+		return Result{}, err
+	}
+	if limit.PerSecond <= 0 {
+		return Result{Allowed: true, Limit: limit}, nil
+	}
+
+	now := l.clock.Now().UnixMilli()
+	values, err := l.redisClient.Eval(ctx, takeScript, []string{l.key(tenantID)},
+		limit.PerSecond, limit.Burst, now).Int64Slice()
+	if err != nil {
+		return Result{}, err
+	}
+	if len(values) != 3 {
+		return Result{}, errors.New("publishrate: unexpected script result")
+	}
+	return Result{
+		Allowed:    values[0] == 1,
+		Limit:      limit,
+		Remaining:  int(values[1]),
+		RetryAfter: time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
+
+// limit returns the tenant's effective limit, with Burst defaulted.
+func (l *Limiter) limit(ctx context.Context, tenantID string) (models.PublishRateLimit, error) {
+	limit := l.defaultLimit
+	override, err := l.override(ctx, tenantID)
+	if err != nil {
+		return limit, err
+	}
+	if override != nil {
+		limit = *override
+	}
+	if limit.Burst <= 0 {
+		limit.Burst = limit.PerSecond
+	}
+	return limit, nil
+}
+
+func (l *Limiter) override(ctx context.Context, tenantID string) (*models.PublishRateLimit, error) {
+	now := l.clock.Now()
+	l.mu.Lock()
+	cached, ok := l.overrides[tenantID]
+	l.mu.Unlock()
+	if ok && now.Sub(cached.fetchedAt) < l.overrideTTL {
+		return cached.limit, nil
+	}
+
+	tenant, err := l.tenants.RetrieveTenant(ctx, tenantID)
+	if err != nil && !errors.Is(err, tenantstore.ErrTenantDeleted) {
+		return nil, err
+	}
+	var limit *models.PublishRateLimit
+	if tenant != nil {
+		limit = tenant.PublishRateLimit
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.overrides[tenantID] = cachedOverride{limit: limit, fetchedAt: now}
+	// Drop stale overrides at most once per TTL, so tenants that stopped
+	// publishing don't accumulate.
+	if now.Sub(l.lastSweep) >= l.overrideTTL {
+		l.lastSweep = now
+		for id, entry := range l.overrides {
+			if now.Sub(entry.fetchedAt) >= l.overrideTTL {
+				delete(l.overrides, id)
+			}
+		}
+	}
+	return limit, nil
+}
+
+// key returns "[<deployment>:]publishrate:{<tenant>}". The tenant is a hash
+// tag, in line with the other per-tenant keys.
+func (l *Limiter) key(tenantID string) string {
+	key := "publishrate:{" + tenantID + "}"
+	if l.deploymentID == "" {
+		return key
+	}
+	return l.deploymentID + ":" + key
+}
+
+func (l *Limiter) record(ctx context.Context, mode string) {
+	if l.throttled == nil {
+		return
+	}
+	l.throttled.Add(ctx, 1, metric.WithAttributes(attribute.String("mode", mode)))
+}
+
+// RetryAfterSeconds rounds d up to whole seconds, for a Retry-After header.
+func RetryAfterSeconds(d time.Duration) string {
+	return strconv.Itoa(max(1, int(math.Ceil(d.Seconds()))))
+}
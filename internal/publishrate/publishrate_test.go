@@ -0,0 +1,157 @@
+package publishrate_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/publishrate"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTenants map[string]*models.PublishRateLimit
+
+func (f fakeTenants) RetrieveTenant(_ context.Context, tenantID string) (*models.Tenant, error) {
+	return &models.Tenant{ID: tenantID, PublishRateLimit: f[tenantID]}, nil
+}
+
+func allow(t *testing.T, limiter *publishrate.Limiter, tenantID string) publishrate.Result {
+	t.Helper()
+	result, err := limiter.Allow(t.Context(), tenantID)
+	require.NoError(t, err)
+	return result
+}
+
+func TestLimiter(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	t.Run("allows a burst then throttles", func(t *testing.T) {
+		t.Parallel()
+		limiter := publishrate.New(testutil.CreateTestRedisClient(t), fakeTenants{},
+			models.PublishRateLimit{PerSecond: 2, Burst: 3}, publishrate.WithClock(clock.NewFake(start)))
+
+		for want := 2; want >= 0; want-- {
+			result := allow(t, limiter, "t1")
+			assert.True(t, result.Allowed)
+			assert.Equal(t, want, result.Remaining)
+		}
+		result := allow(t, limiter, "t1")
+		assert.False(t, result.Allowed)
+		assert.Equal(t, 500*time.Millisecond, result.RetryAfter)
+
+		assert.True(t, allow(t, limiter, "t2").Allowed, "buckets are per tenant")
+	})
+
+	t.Run("refills over time", func(t *testing.T) {
+		t.Parallel()
+		clk := clock.NewFake(start)
+		limiter := publishrate.New(testutil.CreateTestRedisClient(t), fakeTenants{},
+			models.PublishRateLimit{PerSecond: 1}, publishrate.WithClock(clk))
+
+		assert.True(t, allow(t, limiter, "t1").Allowed)
+		assert.False(t, allow(t, limiter, "t1").Allowed)
+		clk.Advance(time.Second)
+		assert.True(t, allow(t, limiter, "t1").Allowed)
+	})
+
+	t.Run("tenant override replaces the default", func(t *testing.T) {
+		t.Parallel()
+		tenants := fakeTenants{
+			"limited":   {PerSecond: 1},
+			"unlimited": {PerSecond: 0},
+		}
+		limiter := publishrate.New(testutil.CreateTestRedisClient(t), tenants,
+			models.PublishRateLimit{PerSecond: 100}, publishrate.WithClock(clock.NewFake(start)))
+
+		assert.True(t, allow(t, limiter, "limited").Allowed)
+		assert.False(t, allow(t, limiter, "limited").Allowed)
+		for range 200 {
+			require.True(t, allow(t, limiter, "unlimited").Allowed)
+		}
+	})
+
+	t.Run("no default leaves tenants without an override unlimited", func(t *testing.T) {
+		t.Parallel()
+		limiter := publishrate.New(testutil.CreateTestRedisClient(t), fakeTenants{},
+			models.PublishRateLimit{}, publishrate.WithClock(clock.NewFake(start)))
+
+		for range 10 {
+			result := allow(t, limiter, "t1")
+			require.True(t, result.Allowed)
+			assert.Zero(t, result.Limit.PerSecond)
+		}
+	})
+
+	t.Run("override changes apply after the cache ttl", func(t *testing.T) {
+		t.Parallel()
+		clk := clock.NewFake(start)
+		tenants := fakeTenants{}
+		limiter := publishrate.New(testutil.CreateTestRedisClient(t), tenants,
+			models.PublishRateLimit{}, publishrate.WithClock(clk), publishrate.WithOverrideTTL(time.Minute))
+
+		assert.True(t, allow(t, limiter, "t1").Allowed)
+		tenants["t1"] = &models.PublishRateLimit{PerSecond: 1}
+		assert.Zero(t, allow(t, limiter, "t1").Limit.PerSecond)
+
+		clk.Advance(time.Minute)
+		assert.Equal(t, 1, allow(t, limiter, "t1").Limit.PerSecond)
+	})
+
+	t.Run("buckets are scoped to the deployment", func(t *testing.T) {
+		t.Parallel()
+		redisClient := testutil.CreateTestRedisClient(t)
+		clk := clock.NewFake(start)
+		limit := models.PublishRateLimit{PerSecond: 1}
+		limiter := publishrate.New(redisClient, fakeTenants{}, limit,
+			publishrate.WithDeploymentID("dp1"), publishrate.WithClock(clk))
+
+		assert.True(t, allow(t, limiter, "t1").Allowed)
+		assert.True(t, allow(t, publishrate.New(redisClient, fakeTenants{}, limit, publishrate.WithClock(clk)), "t1").Allowed)
+	})
+
+	t.Run("wait blocks until a token is available", func(t *testing.T) {
+		t.Parallel()
+		clk := clock.NewFake(start)
+		limiter := publishrate.New(testutil.CreateTestRedisClient(t), fakeTenants{},
+			models.PublishRateLimit{PerSecond: 1}, publishrate.WithClock(clk))
+
+		require.NoError(t, limiter.Wait(t.Context(), "t1"))
+		done := make(chan error, 1)
+		go func() { done <- limiter.Wait(t.Context(), "t1") }()
+
+		clk.BlockUntil(1)
+		select {
+		case <-done:
+			t.Fatal("wait returned before a token was available")
+		default:
+		}
+		clk.Advance(time.Second)
+		require.NoError(t, <-done)
+	})
+
+	t.Run("wait returns when the context is done", func(t *testing.T) {
+		t.Parallel()
+		clk := clock.NewFake(start)
+		limiter := publishrate.New(testutil.CreateTestRedisClient(t), fakeTenants{},
+			models.PublishRateLimit{PerSecond: 1}, publishrate.WithClock(clk))
+
+		require.NoError(t, limiter.Wait(t.Context(), "t1"))
+		ctx, cancel := context.WithCancel(t.Context())
+		cancel()
+		require.ErrorIs(t, limiter.Wait(ctx, "t1"), context.Canceled)
+	})
+}
+
+func TestRetryAfterSeconds(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, "1", publishrate.RetryAfterSeconds(0))
+	assert.Equal(t, "1", publishrate.RetryAfterSeconds(200*time.Millisecond))
+	assert.Equal(t, "2", publishrate.RetryAfterSeconds(1500*time.Millisecond))
+}
@@ -0,0 +1,290 @@
+package receipts
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"go.uber.org/zap"
+)
+
+const defaultPageSize = 1000
+
+// ErrTenantListingNotSupported is returned when the tenant store can't list
+// tenants, which receipts need to find the tenants with receipt storage.
+var ErrTenantListingNotSupported = errors.New("receipts: delivery receipts require listing tenants, which the tenant store doesn't support (Redis without RediSearch)")
+
+// AttemptLister is the subset of the log store used to read deliveries.
+type AttemptLister interface {
+	ListAttempt(ctx context.Context, req logstore.ListAttemptRequest) (logstore.ListAttemptResponse, error)
+}
+
+// TenantLister is the subset of the tenant store used to find tenants with
+// receipt storage configured.
+type TenantLister interface {
+	ListTenant(ctx context.Context, req tenantstore.ListTenantRequest) (*tenantstore.TenantPaginatedResult, error)
+}
+
+// Uploader writes a receipt object to a tenant's receipt storage.
+type Uploader interface {
+	Upload(ctx context.Context, storage *models.ReceiptStorage, key string, body io.ReadSeeker) error
+}
+
+// Generator builds daily receipts and writes them to each tenant's storage.
+type Generator struct {
+	attempts AttemptLister
+	tenants  TenantLister
+	uploader Uploader
+	signer   *Signer
+	logger   *logging.Logger
+	pageSize int
+	now      func() time.Time
+}
+
+// GeneratorOption configures a Generator.
+type GeneratorOption func(*Generator)
+
+// WithPageSize sets how many attempts are read from the log store per query.
+func WithPageSize(pageSize int) GeneratorOption {
+	return func(g *Generator) {
+		if pageSize > 0 {
+			g.pageSize = pageSize
+		}
+	}
+}
+
+// WithSigner signs every receipt with the deployment's key.
+func WithSigner(signer *Signer) GeneratorOption {
+	return func(g *Generator) {
+		g.signer = signer
+	}
+}
+
+// NewGenerator creates a receipt generator.
+func NewGenerator(attempts AttemptLister, tenants TenantLister, uploader Uploader, logger *logging.Logger, opts ...GeneratorOption) *Generator {
+	g := &Generator{
+		attempts: attempts,
+		tenants:  tenants,
+		uploader: uploader,
+		logger:   logger,
+		pageSize: defaultPageSize,
+		now:      time.Now,
+	}
+	for _, opt := range opts {
+		opt(g)
+	}
+	return g
+}
+
+// CheckSupported reports whether the tenant store can list tenants, so that
+// receipts can be refused at startup rather than failing every day.
+func (g *Generator) CheckSupported(ctx context.Context) error {
+	_, err := g.tenants.ListTenant(ctx, tenantstore.ListTenantRequest{Limit: 1})
+	if errors.Is(err, tenantstore.ErrListTenantNotSupported) {
+		return ErrTenantListingNotSupported
+	}
+	if err != nil {
+		return fmt.Errorf("list tenants: %w", err)
+	}
+	return nil
+}
+
+// Build builds the receipt of a tenant's successful deliveries on the UTC day
+// containing day, signed when the generator has a signer. It holds the day's
+// deliveries in memory; Run streams them to storage instead.
+func (g *Generator) Build(ctx context.Context, tenantID string, day time.Time) (*Receipt, error) {
+	start, _ := dayBounds(day)
+	deliveries := []Delivery{}
+	err := g.deliveries(ctx, tenantID, day, func(delivery Delivery) error {
+		deliveries = append(deliveries, delivery)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	receipt, err := NewReceipt(tenantID, start, deliveries, g.now())
+	if err != nil {
+		return nil, err
+	}
+	if g.signer != nil {
+		if err := g.signer.Sign(receipt); err != nil {
+			return nil, fmt.Errorf("sign receipt: %w", err)
+		}
+	}
+	return receipt, nil
+}
+
+// deliveries calls fn with each of a tenant's successful deliveries on the
+// UTC day containing day, a page at a time, ordered by time then attempt ID
+// as NewReceipt orders them.
+func (g *Generator) deliveries(ctx context.Context, tenantID string, day time.Time, fn func(Delivery) error) error {
+	start, end := dayBounds(day)
+	req := logstore.ListAttemptRequest{
+		TenantIDs: []string{tenantID},
+		Status:    "success",
+		TimeFilter: logstore.TimeFilter{
+			GTE: &start,
+			LT:  &end,
+		},
+		Limit:     g.pageSize,
+		SortOrder: "asc",
+	}
+
+	for {
+		resp, err := g.attempts.ListAttempt(ctx, req)
+		if err != nil {
+			return fmt.Errorf("list attempts: %w", err)
+		}
+		for _, record := range resp.Data {
+			if record.Attempt == nil {
+				continue
+			}
+			delivery := Delivery{
+				AttemptID:     record.Attempt.ID,
+				EventID:       record.Attempt.EventID,
+				DestinationID: record.Attempt.DestinationID,
+				DeliveredAt:   record.Attempt.Time.UTC(),
+			}
+			if record.Event != nil {
+				delivery.Topic = record.Event.Topic
+				delivery.EventSHA256 = hashPayload(record.Event.Data)
+				delivery.Checksum = record.Event.Checksum
+				if err := record.Event.VerifyChecksum(); err != nil {
+					delivery.ChecksumMismatch = true
+					g.logger.Ctx(ctx).Error("event data does not match its checksum",
+						zap.String("tenant_id", tenantID),
+						zap.String("event_id", record.Event.ID))
+				}
+			}
+			if err := fn(delivery); err != nil {
+				return err
+			}
+		}
+		if resp.Next == "" {
+			return nil
+		}
+		req.Next = resp.Next
+	}
+}
+
+// Run writes the receipts for the UTC day containing day for every tenant
+// with receipt storage configured. A failure for one tenant does not stop the
+// others; all failures are returned together.
+func (g *Generator) Run(ctx context.Context, day time.Time) (int, error) {
+	written := 0
+	var errs []error
+
+	req := tenantstore.ListTenantRequest{Limit: 100, Dir: "asc"}
+	for {
+		page, err := g.tenants.ListTenant(ctx, req)
+		if errors.Is(err, tenantstore.ErrListTenantNotSupported) {
+			return written, ErrTenantListingNotSupported
+		}
+		if err != nil {
+			return written, fmt.Errorf("list tenants: %w", err)
+		}
+		for i := range page.Models {
+			tenant := &page.Models[i]
+			if tenant.ReceiptStorage == nil {
+				continue
+			}
+			if err := g.write(ctx, tenant, day); err != nil {
+				g.logger.Ctx(ctx).Error("failed to write delivery receipt",
+					zap.String("tenant_id", tenant.ID),
+					zap.Error(err))
+				errs = append(errs, fmt.Errorf("tenant %s: %w", tenant.ID, err))
+				continue
+			}
+			written++
+		}
+		if page.Pagination.Next == nil || *page.Pagination.Next == "" {
+			break
+		}
+		req.Next = *page.Pagination.Next
+	}
+
+	return written, errors.Join(errs...)
+}
+
+// write streams a tenant's receipt for the UTC day containing day to its
+// storage. The deliveries are spooled to a temporary file while their digest
+// is taken, then the receipt is assembled around them in a second one, so
+// neither is held in memory.
+func (g *Generator) write(ctx context.Context, tenant *models.Tenant, day time.Time) error {
+	deliveriesFile, err := createTemp("outpost-receipt-deliveries-*")
+	if err != nil {
+		return err
+	}
+	defer removeTemp(deliveriesFile)
+
+	buffered := bufio.NewWriter(deliveriesFile)
+	writer := newDeliveryWriter(buffered)
+	if err := g.deliveries(ctx, tenant.ID, day, writer.Write); err != nil {
+		return err
+	}
+	digest, err := writer.Close()
+	if err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	if _, err := deliveriesFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	start, _ := dayBounds(day)
+	receipt := &Receipt{
+		Version:     Version,
+		TenantID:    tenant.ID,
+		Date:        start.Format("2006-01-02"),
+		GeneratedAt: g.now().UTC(),
+		Count:       writer.count,
+		Digest:      digest,
+	}
+	if g.signer != nil {
+		if err := g.signer.Sign(receipt); err != nil {
+			return fmt.Errorf("sign receipt: %w", err)
+		}
+	}
+
+	bodyFile, err := createTemp("outpost-receipt-*.json")
+	if err != nil {
+		return err
+	}
+	defer removeTemp(bodyFile)
+
+	buffered = bufio.NewWriter(bodyFile)
+	if err := encodeReceipt(buffered, receipt, bufio.NewReader(deliveriesFile)); err != nil {
+		return err
+	}
+	if err := buffered.Flush(); err != nil {
+		return err
+	}
+	if _, err := bodyFile.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	return g.uploader.Upload(ctx, tenant.ReceiptStorage, tenant.ReceiptStorage.Key(day), bodyFile)
+}
+
+func createTemp(pattern string) (*os.File, error) {
+	f, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return nil, fmt.Errorf("create temporary file: %w", err)
+	}
+	return f, nil
+}
+
+func removeTemp(f *os.File) {
+	f.Close()
+	os.Remove(f.Name())
+}
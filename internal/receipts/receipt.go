@@ -0,0 +1,196 @@
+// Package receipts produces daily delivery receipts: per-tenant records of the
+// events Outpost successfully delivered on a UTC day, with a hash of each
+// delivered payload, written to storage the tenant's operator configures.
+package receipts
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"hash"
+	"io"
+	"sort"
+	"time"
+)
+
+// Version is the receipt format version. Version 1 receipts are signed over
+// their deliveries; from version 2 the signature covers the digest instead,
+// so a receipt can be signed without holding its deliveries in memory.
+const Version = 2
+
+// Receipt is the record of a tenant's successful deliveries on one UTC day.
+type Receipt struct {
+	Version     int        `json:"version"`
+	TenantID    string     `json:"tenant_id"`
+	Date        string     `json:"date"`
+	GeneratedAt time.Time  `json:"generated_at"`
+	Count       int        `json:"count"`
+	Deliveries  []Delivery `json:"deliveries"`
+	// Digest is the hex SHA-256 of the JSON-encoded Deliveries. A receipt
+	// whose deliveries were edited, added to or truncated no longer matches.
+	Digest string `json:"digest"`
+	// Signature is set when the deployment has a receipt signing key.
+	Signature *Signature `json:"signature,omitempty"`
+}
+
+// Delivery is a single successful delivery attempt.
+type Delivery struct {
+	AttemptID     string    `json:"attempt_id"`
+	EventID       string    `json:"event_id"`
+	DestinationID string    `json:"destination_id"`
+	Topic         string    `json:"topic"`
+	DeliveredAt   time.Time `json:"delivered_at"`
+	// EventSHA256 is the hex SHA-256 of the event payload as delivered.
+	EventSHA256 string `json:"event_sha256"`
+	// Checksum is the event's checksum taken at publish, if it has one.
+	Checksum string `json:"checksum,omitempty"`
+	// ChecksumMismatch marks a delivery whose stored event data no longer
+	// matches the checksum taken at publish.
+	ChecksumMismatch bool `json:"checksum_mismatch,omitempty"`
+}
+
+// NewReceipt builds the receipt for a tenant's deliveries on the UTC day
+// containing day. Deliveries are ordered by time, then attempt ID, so the same
+// deliveries always produce the same digest.
+func NewReceipt(tenantID string, day time.Time, deliveries []Delivery, now time.Time) (*Receipt, error) {
+	sorted := make([]Delivery, len(deliveries))
+	copy(sorted, deliveries)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if !sorted[i].DeliveredAt.Equal(sorted[j].DeliveredAt) {
+			return sorted[i].DeliveredAt.Before(sorted[j].DeliveredAt)
+		}
+		return sorted[i].AttemptID < sorted[j].AttemptID
+	})
+
+	digest, err := Digest(sorted)
+	if err != nil {
+		return nil, err
+	}
+	return &Receipt{
+		Version:     Version,
+		TenantID:    tenantID,
+		Date:        day.UTC().Format("2006-01-02"),
+		GeneratedAt: now.UTC(),
+		Count:       len(sorted),
+		Deliveries:  sorted,
+		Digest:      digest,
+	}, nil
+}
+
+// Digest returns the hex SHA-256 of the JSON encoding of deliveries.
+func Digest(deliveries []Delivery) (string, error) {
+	if deliveries == nil {
+		deliveries = []Delivery{}
+	}
+	b, err := json.Marshal(deliveries)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// deliveryWriter encodes deliveries one at a time as the JSON array Digest
+// hashes, so a day's deliveries are never held in memory.
+type deliveryWriter struct {
+	w     io.Writer
+	hash  hash.Hash
+	count int
+}
+
+func newDeliveryWriter(w io.Writer) *deliveryWriter {
+	h := sha256.New()
+	return &deliveryWriter{w: io.MultiWriter(w, h), hash: h}
+}
+
+// Write appends a delivery to the array.
+func (d *deliveryWriter) Write(delivery Delivery) error {
+	b, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+	sep := []byte(",")
+	if d.count == 0 {
+		sep = []byte("[")
+	}
+	if _, err := d.w.Write(sep); err != nil {
+		return err
+	}
+	if _, err := d.w.Write(b); err != nil {
+		return err
+	}
+	d.count++
+	return nil
+}
+
+// Close ends the array and returns its digest.
+func (d *deliveryWriter) Close() (string, error) {
+	end := []byte("]")
+	if d.count == 0 {
+		end = []byte("[]")
+	}
+	if _, err := d.w.Write(end); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(d.hash.Sum(nil)), nil
+}
+
+// encodeReceipt writes the JSON encoding of a receipt whose deliveries are
+// read, already encoded, from deliveries.
+func encodeReceipt(w io.Writer, r *Receipt, deliveries io.Reader) error {
+	head, err := json.Marshal(struct {
+		Version     int       `json:"version"`
+		TenantID    string    `json:"tenant_id"`
+		Date        string    `json:"date"`
+		GeneratedAt time.Time `json:"generated_at"`
+		Count       int       `json:"count"`
+	}{r.Version, r.TenantID, r.Date, r.GeneratedAt, r.Count})
+	if err != nil {
+		return err
+	}
+	tail, err := json.Marshal(struct {
+		Digest    string     `json:"digest"`
+		Signature *Signature `json:"signature,omitempty"`
+	}{r.Digest, r.Signature})
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(bytes.TrimSuffix(head, []byte("}"))); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, `,"deliveries":`); err != nil {
+		return err
+	}
+	if _, err := io.Copy(w, deliveries); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(w, ","); err != nil {
+		return err
+	}
+	_, err = w.Write(bytes.TrimPrefix(tail, []byte("{")))
+	return err
+}
+
+// VerifyDigest reports whether the receipt's digest matches its deliveries.
+func (r *Receipt) VerifyDigest() (bool, error) {
+	digest, err := Digest(r.Deliveries)
+	if err != nil {
+		return false, err
+	}
+	return digest == r.Digest && len(r.Deliveries) == r.Count, nil
+}
+
+// hashPayload returns the hex SHA-256 of an event payload.
+func hashPayload(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// dayBounds returns the start of the UTC day containing t and the start of the
+// following day.
+func dayBounds(t time.Time) (time.Time, time.Time) {
+	start := t.UTC().Truncate(24 * time.Hour)
+	return start, start.Add(24 * time.Hour)
+}
@@ -0,0 +1,262 @@
+package receipts_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/logstore/memlogstore"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/receipts"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type upload struct {
+	storage *models.ReceiptStorage
+	key     string
+	body    []byte
+}
+
+type fakeUploader struct {
+	uploads []upload
+	err     error
+}
+
+func (u *fakeUploader) Upload(ctx context.Context, storage *models.ReceiptStorage, key string, body io.ReadSeeker) error {
+	if u.err != nil {
+		return u.err
+	}
+	b, err := io.ReadAll(body)
+	if err != nil {
+		return err
+	}
+	u.uploads = append(u.uploads, upload{storage: storage, key: key, body: b})
+	return nil
+}
+
+type fakeTenantLister struct {
+	tenants []models.Tenant
+	err     error
+}
+
+func (l *fakeTenantLister) ListTenant(ctx context.Context, req tenantstore.ListTenantRequest) (*tenantstore.TenantPaginatedResult, error) {
+	if l.err != nil {
+		return nil, l.err
+	}
+	return &tenantstore.TenantPaginatedResult{Models: l.tenants}, nil
+}
+
+func newLogger(t *testing.T) *logging.Logger {
+	t.Helper()
+	logger, err := logging.NewLogger(logging.WithLogLevel("error"))
+	require.NoError(t, err)
+	return logger
+}
+
+func insertAttempt(t *testing.T, store interface {
+	InsertMany(context.Context, []*models.LogEntry) error
+}, tenantID, attemptID, status string, at time.Time, data string) {
+	t.Helper()
+	event := testutil.EventFactory.AnyPointer(
+		testutil.EventFactory.WithTenantID(tenantID),
+		testutil.EventFactory.WithTopic("user.created"),
+		testutil.EventFactory.WithTime(at),
+		testutil.EventFactory.WithData(json.RawMessage(data)),
+	)
+	attempt := testutil.AttemptFactory.AnyPointer(
+		testutil.AttemptFactory.WithID(attemptID),
+		testutil.AttemptFactory.WithTenantID(tenantID),
+		testutil.AttemptFactory.WithEventID(event.ID),
+		testutil.AttemptFactory.WithDestinationID(event.DestinationID),
+		testutil.AttemptFactory.WithStatus(status),
+		testutil.AttemptFactory.WithTime(at),
+	)
+	require.NoError(t, store.InsertMany(context.Background(), []*models.LogEntry{{Event: event, Attempt: attempt}}))
+}
+
+func TestNewReceipt(t *testing.T) {
+	t.Parallel()
+
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	deliveries := []receipts.Delivery{
+		{AttemptID: "atm_2", DeliveredAt: day.Add(2 * time.Hour)},
+		{AttemptID: "atm_1", DeliveredAt: day.Add(time.Hour)},
+		{AttemptID: "atm_0", DeliveredAt: day.Add(time.Hour)},
+	}
+
+	receipt, err := receipts.NewReceipt("tenant_1", day.Add(5*time.Hour), deliveries, day.Add(30*time.Hour))
+	require.NoError(t, err)
+
+	assert.Equal(t, receipts.Version, receipt.Version)
+	assert.Equal(t, "2026-03-14", receipt.Date)
+	assert.Equal(t, 3, receipt.Count)
+	assert.Equal(t, []string{"atm_0", "atm_1", "atm_2"}, []string{
+		receipt.Deliveries[0].AttemptID,
+		receipt.Deliveries[1].AttemptID,
+		receipt.Deliveries[2].AttemptID,
+	})
+
+	ok, err := receipt.VerifyDigest()
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	t.Run("detects tampering", func(t *testing.T) {
+		tampered := *receipt
+		tampered.Deliveries = tampered.Deliveries[1:]
+		tampered.Count = len(tampered.Deliveries)
+		ok, err := tampered.VerifyDigest()
+		require.NoError(t, err)
+		assert.False(t, ok)
+	})
+}
+
+func TestGenerator_Build(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memlogstore.NewLogStore()
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	insertAttempt(t, store, "tenant_1", "atm_1", "success", day.Add(time.Hour), `{"n":1}`)
+	insertAttempt(t, store, "tenant_1", "atm_2", "success", day.Add(23*time.Hour), `{"n":2}`)
+	insertAttempt(t, store, "tenant_1", "atm_3", "failed", day.Add(2*time.Hour), `{"n":3}`)
+	insertAttempt(t, store, "tenant_1", "atm_4", "success", day.Add(24*time.Hour), `{"n":4}`)
+	insertAttempt(t, store, "tenant_1", "atm_5", "success", day.Add(-time.Second), `{"n":5}`)
+	insertAttempt(t, store, "tenant_2", "atm_6", "success", day.Add(time.Hour), `{"n":6}`)
+
+	generator := receipts.NewGenerator(store, &fakeTenantLister{}, &fakeUploader{}, newLogger(t), receipts.WithPageSize(1))
+
+	receipt, err := generator.Build(ctx, "tenant_1", day.Add(12*time.Hour))
+	require.NoError(t, err)
+
+	require.Equal(t, 2, receipt.Count)
+	assert.Equal(t, "atm_1", receipt.Deliveries[0].AttemptID)
+	assert.Equal(t, "atm_2", receipt.Deliveries[1].AttemptID)
+	assert.Equal(t, "user.created", receipt.Deliveries[0].Topic)
+	assert.Len(t, receipt.Deliveries[0].EventSHA256, 64)
+	assert.NotEqual(t, receipt.Deliveries[0].EventSHA256, receipt.Deliveries[1].EventSHA256)
+
+	ok, err := receipt.VerifyDigest()
+	require.NoError(t, err)
+	assert.True(t, ok)
+}
+
+func TestGenerator_Run(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memlogstore.NewLogStore()
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	insertAttempt(t, store, "tenant_1", "atm_1", "success", day.Add(time.Hour), `{}`)
+
+	tenants := &fakeTenantLister{tenants: []models.Tenant{
+		{ID: "tenant_1", ReceiptStorage: &models.ReceiptStorage{Bucket: "receipts", Region: "us-east-1", Prefix: "outpost/"}},
+		{ID: "tenant_2"},
+	}}
+
+	t.Run("writes receipts for tenants with storage", func(t *testing.T) {
+		uploader := &fakeUploader{}
+		generator := receipts.NewGenerator(store, tenants, uploader, newLogger(t))
+
+		written, err := generator.Run(ctx, day)
+		require.NoError(t, err)
+		assert.Equal(t, 1, written)
+
+		require.Len(t, uploader.uploads, 1)
+		assert.Equal(t, "receipts", uploader.uploads[0].storage.Bucket)
+		assert.Equal(t, "outpost/2026-03-14.json", uploader.uploads[0].key)
+
+		var receipt receipts.Receipt
+		require.NoError(t, json.Unmarshal(uploader.uploads[0].body, &receipt))
+		assert.Equal(t, "tenant_1", receipt.TenantID)
+		assert.Equal(t, 1, receipt.Count)
+	})
+
+	t.Run("streams signed receipts matching Build", func(t *testing.T) {
+		store := memlogstore.NewLogStore()
+		insertAttempt(t, store, "tenant_1", "atm_2", "success", day.Add(2*time.Hour), `{"n":2}`)
+		insertAttempt(t, store, "tenant_1", "atm_1", "success", day.Add(time.Hour), `{"n":1}`)
+		insertAttempt(t, store, "tenant_1", "atm_0", "success", day.Add(time.Hour), `{"n":0}`)
+
+		signingKey, encodedPublicKey, err := receipts.GenerateSigningKey()
+		require.NoError(t, err)
+		signer, err := receipts.NewSigner(signingKey)
+		require.NoError(t, err)
+		publicKey, err := receipts.ParsePublicKey(encodedPublicKey)
+		require.NoError(t, err)
+
+		uploader := &fakeUploader{}
+		generator := receipts.NewGenerator(store, tenants, uploader, newLogger(t),
+			receipts.WithPageSize(2), receipts.WithSigner(signer))
+
+		written, err := generator.Run(ctx, day)
+		require.NoError(t, err)
+		assert.Equal(t, 1, written)
+		require.Len(t, uploader.uploads, 1)
+
+		var receipt receipts.Receipt
+		require.NoError(t, json.Unmarshal(uploader.uploads[0].body, &receipt))
+		assert.Equal(t, receipts.Version, receipt.Version)
+		assert.Equal(t, "2026-03-14", receipt.Date)
+		require.Equal(t, 3, receipt.Count)
+		assert.Equal(t, []string{"atm_0", "atm_1", "atm_2"}, []string{
+			receipt.Deliveries[0].AttemptID,
+			receipt.Deliveries[1].AttemptID,
+			receipt.Deliveries[2].AttemptID,
+		})
+		assert.NoError(t, receipts.Verify(&receipt, publicKey))
+
+		built, err := generator.Build(ctx, "tenant_1", day)
+		require.NoError(t, err)
+		assert.Equal(t, built.Digest, receipt.Digest)
+	})
+
+	t.Run("streams empty receipts", func(t *testing.T) {
+		uploader := &fakeUploader{}
+		generator := receipts.NewGenerator(memlogstore.NewLogStore(), tenants, uploader, newLogger(t))
+
+		_, err := generator.Run(ctx, day)
+		require.NoError(t, err)
+		require.Len(t, uploader.uploads, 1)
+
+		var receipt receipts.Receipt
+		require.NoError(t, json.Unmarshal(uploader.uploads[0].body, &receipt))
+		assert.Equal(t, 0, receipt.Count)
+		ok, err := receipt.VerifyDigest()
+		require.NoError(t, err)
+		assert.True(t, ok)
+	})
+
+	t.Run("returns upload failures", func(t *testing.T) {
+		uploadErr := errors.New("access denied")
+		generator := receipts.NewGenerator(store, tenants, &fakeUploader{err: uploadErr}, newLogger(t))
+
+		written, err := generator.Run(ctx, day)
+		assert.ErrorIs(t, err, uploadErr)
+		assert.Equal(t, 0, written)
+	})
+}
+
+func TestGenerator_CheckSupported(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	store := memlogstore.NewLogStore()
+
+	generator := receipts.NewGenerator(store, &fakeTenantLister{}, &fakeUploader{}, newLogger(t))
+	assert.NoError(t, generator.CheckSupported(ctx))
+
+	unsupported := &fakeTenantLister{err: tenantstore.ErrListTenantNotSupported}
+	generator = receipts.NewGenerator(store, unsupported, &fakeUploader{}, newLogger(t))
+	assert.ErrorIs(t, generator.CheckSupported(ctx), receipts.ErrTenantListingNotSupported)
+
+	_, err := generator.Run(ctx, time.Now())
+	assert.ErrorIs(t, err, receipts.ErrTenantListingNotSupported)
+}
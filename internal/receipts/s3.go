@@ -0,0 +1,97 @@
+package receipts
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	awssdk "github.com/aws/aws-sdk-go-v2/aws"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	awscreds "github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/hookdeck/outpost/internal/models"
+)
+
+// S3Config configures the identity receipts are written with. When no static
+// credentials are set, the default AWS credential chain is used (environment,
+// shared config, instance or task role).
+type S3Config struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	// Endpoint overrides the S3 endpoint, e.g. for LocalStack or MinIO.
+	Endpoint string
+}
+
+// S3Uploader writes receipts to S3 buckets.
+type S3Uploader struct {
+	cfg S3Config
+
+	mu      sync.Mutex
+	clients map[string]*s3.Client
+}
+
+var _ Uploader = (*S3Uploader)(nil)
+
+// NewS3Uploader creates an uploader writing with the given identity.
+func NewS3Uploader(cfg S3Config) *S3Uploader {
+	return &S3Uploader{
+		cfg:     cfg,
+		clients: make(map[string]*s3.Client),
+	}
+}
+
+// Upload writes body to key in the storage's bucket.
+func (u *S3Uploader) Upload(ctx context.Context, storage *models.ReceiptStorage, key string, body io.ReadSeeker) error {
+	client, err := u.client(ctx, storage.Region)
+	if err != nil {
+		return err
+	}
+	_, err = client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:      awssdk.String(storage.Bucket),
+		Key:         awssdk.String(key),
+		Body:        body,
+		ContentType: awssdk.String("application/json"),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to put s3://%s/%s: %w", storage.Bucket, key, err)
+	}
+	return nil
+}
+
+// client returns the S3 client for a region, creating it on first use.
+func (u *S3Uploader) client(ctx context.Context, region string) (*s3.Client, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if client, ok := u.clients[region]; ok {
+		return client, nil
+	}
+
+	opts := []func(*awsconfig.LoadOptions) error{
+		awsconfig.WithRegion(region),
+	}
+	if u.cfg.AccessKeyID != "" {
+		opts = append(opts, awsconfig.WithCredentialsProvider(awscreds.NewStaticCredentialsProvider(
+			u.cfg.AccessKeyID,
+			u.cfg.SecretAccessKey,
+			"",
+		)))
+	}
+	sdkConfig, err := awsconfig.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	s3Options := []func(*s3.Options){}
+	if u.cfg.Endpoint != "" {
+		s3Options = append(s3Options, func(o *s3.Options) {
+			o.BaseEndpoint = awssdk.String(u.cfg.Endpoint)
+			o.UsePathStyle = true
+		})
+	}
+
+	client := s3.NewFromConfig(sdkConfig, s3Options...)
+	u.clients[region] = client
+	return client, nil
+}
@@ -0,0 +1,157 @@
+package receipts
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// SignatureAlgorithm is the algorithm receipts are signed with.
+const SignatureAlgorithm = "ed25519"
+
+var (
+	ErrInvalidSigningKey = errors.New("receipts: signing key must be a base64-encoded Ed25519 seed (32 bytes) or private key (64 bytes)")
+	ErrInvalidPublicKey  = errors.New("receipts: public key must be a base64-encoded Ed25519 public key (32 bytes)")
+	ErrUnsigned          = errors.New("receipts: receipt is not signed")
+	ErrKeyMismatch       = errors.New("receipts: receipt was signed with a different key")
+	ErrInvalidSignature  = errors.New("receipts: signature does not match receipt")
+	ErrDigestMismatch    = errors.New("receipts: digest does not match deliveries")
+)
+
+// Signature is the deployment's signature over a receipt.
+type Signature struct {
+	Algorithm string `json:"algorithm"`
+	// KeyID identifies the public key that verifies the signature.
+	KeyID string `json:"key_id"`
+	// Value is the base64 signature over the receipt encoded without its
+	// signature, and from version 2 without its deliveries.
+	Value string `json:"value"`
+}
+
+// Signer signs receipts with a deployment's Ed25519 key.
+type Signer struct {
+	key   ed25519.PrivateKey
+	keyID string
+}
+
+// NewSigner creates a signer from a base64-encoded Ed25519 seed or private
+// key.
+func NewSigner(encodedKey string) (*Signer, error) {
+	raw, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil {
+		return nil, ErrInvalidSigningKey
+	}
+	var key ed25519.PrivateKey
+	switch len(raw) {
+	case ed25519.SeedSize:
+		key = ed25519.NewKeyFromSeed(raw)
+	case ed25519.PrivateKeySize:
+		key = ed25519.PrivateKey(raw)
+	default:
+		return nil, ErrInvalidSigningKey
+	}
+	publicKey := key.Public().(ed25519.PublicKey)
+	return &Signer{key: key, keyID: KeyID(publicKey)}, nil
+}
+
+// PublicKey returns the base64-encoded public key that verifies this signer's
+// receipts.
+func (s *Signer) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(s.key.Public().(ed25519.PublicKey))
+}
+
+// KeyID returns the ID of the signer's key.
+func (s *Signer) KeyID() string {
+	return s.keyID
+}
+
+// Sign sets the receipt's signature.
+func (s *Signer) Sign(r *Receipt) error {
+	payload, err := r.signingPayload()
+	if err != nil {
+		return err
+	}
+	r.Signature = &Signature{
+		Algorithm: SignatureAlgorithm,
+		KeyID:     s.keyID,
+		Value:     base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, payload)),
+	}
+	return nil
+}
+
+// GenerateSigningKey returns a new base64-encoded Ed25519 seed and its
+// base64-encoded public key.
+func GenerateSigningKey() (string, string, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+	return base64.StdEncoding.EncodeToString(privateKey.Seed()),
+		base64.StdEncoding.EncodeToString(publicKey), nil
+}
+
+// KeyID returns the ID of a public key: the first 16 hex characters of its
+// SHA-256.
+func KeyID(publicKey ed25519.PublicKey) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+// ParsePublicKey decodes a base64-encoded Ed25519 public key.
+func ParsePublicKey(encodedKey string) (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(encodedKey)
+	if err != nil || len(raw) != ed25519.PublicKeySize {
+		return nil, ErrInvalidPublicKey
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// Verify checks that a receipt's digest matches its deliveries and that it
+// was signed by publicKey.
+func Verify(r *Receipt, publicKey ed25519.PublicKey) error {
+	ok, err := r.VerifyDigest()
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return ErrDigestMismatch
+	}
+	if r.Signature == nil {
+		return ErrUnsigned
+	}
+	if r.Signature.Algorithm != SignatureAlgorithm {
+		return fmt.Errorf("receipts: unsupported signature algorithm %q", r.Signature.Algorithm)
+	}
+	if r.Signature.KeyID != KeyID(publicKey) {
+		return ErrKeyMismatch
+	}
+	signature, err := base64.StdEncoding.DecodeString(r.Signature.Value)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	payload, err := r.signingPayload()
+	if err != nil {
+		return err
+	}
+	if !ed25519.Verify(publicKey, payload, signature) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+// signingPayload returns the bytes a receipt's signature covers: its JSON
+// encoding without the signature and, from version 2, without the deliveries
+// the digest already covers.
+func (r *Receipt) signingPayload() ([]byte, error) {
+	unsigned := *r
+	unsigned.Signature = nil
+	if unsigned.Version >= 2 {
+		unsigned.Deliveries = nil
+	}
+	return json.Marshal(unsigned)
+}
@@ -0,0 +1,118 @@
+package receipts_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/receipts"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSignedReceipt(t *testing.T) (*receipts.Receipt, string) {
+	t.Helper()
+	signingKey, publicKey, err := receipts.GenerateSigningKey()
+	require.NoError(t, err)
+	signer, err := receipts.NewSigner(signingKey)
+	require.NoError(t, err)
+	assert.Equal(t, publicKey, signer.PublicKey())
+
+	day := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	receipt, err := receipts.NewReceipt("tenant_1", day, []receipts.Delivery{
+		{AttemptID: "atm_1", EventID: "evt_1", DestinationID: "des_1", Topic: "user.created", DeliveredAt: day.Add(time.Hour), EventSHA256: "abc"},
+	}, day.Add(25*time.Hour))
+	require.NoError(t, err)
+	require.NoError(t, signer.Sign(receipt))
+	return receipt, publicKey
+}
+
+// roundTrip encodes and decodes a receipt the way a verifier reads it from
+// storage.
+func roundTrip(t *testing.T, r *receipts.Receipt) *receipts.Receipt {
+	t.Helper()
+	b, err := json.Marshal(r)
+	require.NoError(t, err)
+	var decoded receipts.Receipt
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	return &decoded
+}
+
+func TestVerify(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid signature", func(t *testing.T) {
+		receipt, encodedKey := newSignedReceipt(t)
+		publicKey, err := receipts.ParsePublicKey(encodedKey)
+		require.NoError(t, err)
+
+		require.NotNil(t, receipt.Signature)
+		assert.Equal(t, receipts.SignatureAlgorithm, receipt.Signature.Algorithm)
+		assert.NoError(t, receipts.Verify(roundTrip(t, receipt), publicKey))
+	})
+
+	t.Run("tampered delivery", func(t *testing.T) {
+		receipt, encodedKey := newSignedReceipt(t)
+		publicKey, err := receipts.ParsePublicKey(encodedKey)
+		require.NoError(t, err)
+
+		tampered := roundTrip(t, receipt)
+		tampered.Deliveries[0].EventSHA256 = "def"
+		assert.ErrorIs(t, receipts.Verify(tampered, publicKey), receipts.ErrDigestMismatch)
+	})
+
+	t.Run("tampered digest", func(t *testing.T) {
+		receipt, encodedKey := newSignedReceipt(t)
+		publicKey, err := receipts.ParsePublicKey(encodedKey)
+		require.NoError(t, err)
+
+		tampered := roundTrip(t, receipt)
+		tampered.Deliveries[0].EventSHA256 = "def"
+		tampered.Digest, err = receipts.Digest(tampered.Deliveries)
+		require.NoError(t, err)
+		assert.ErrorIs(t, receipts.Verify(tampered, publicKey), receipts.ErrInvalidSignature)
+	})
+
+	t.Run("different key", func(t *testing.T) {
+		receipt, _ := newSignedReceipt(t)
+		_, otherKey, err := receipts.GenerateSigningKey()
+		require.NoError(t, err)
+		publicKey, err := receipts.ParsePublicKey(otherKey)
+		require.NoError(t, err)
+
+		assert.ErrorIs(t, receipts.Verify(receipt, publicKey), receipts.ErrKeyMismatch)
+	})
+
+	t.Run("version 1 signature over deliveries", func(t *testing.T) {
+		signingKey, encodedKey, err := receipts.GenerateSigningKey()
+		require.NoError(t, err)
+		signer, err := receipts.NewSigner(signingKey)
+		require.NoError(t, err)
+		publicKey, err := receipts.ParsePublicKey(encodedKey)
+		require.NoError(t, err)
+
+		receipt, _ := newSignedReceipt(t)
+		receipt.Version = 1
+		require.NoError(t, signer.Sign(receipt))
+		assert.NoError(t, receipts.Verify(roundTrip(t, receipt), publicKey))
+	})
+
+	t.Run("unsigned", func(t *testing.T) {
+		receipt, encodedKey := newSignedReceipt(t)
+		publicKey, err := receipts.ParsePublicKey(encodedKey)
+		require.NoError(t, err)
+
+		receipt.Signature = nil
+		assert.ErrorIs(t, receipts.Verify(receipt, publicKey), receipts.ErrUnsigned)
+	})
+}
+
+func TestNewSigner(t *testing.T) {
+	t.Parallel()
+
+	_, err := receipts.NewSigner("not-a-key")
+	assert.ErrorIs(t, err, receipts.ErrInvalidSigningKey)
+
+	_, err = receipts.NewSigner("AAEC")
+	assert.ErrorIs(t, err, receipts.ErrInvalidSigningKey)
+}
@@ -0,0 +1,243 @@
+// Package recorder mirrors a sample of deliveries to a debug sink while a
+// destination recording is active. It lets operators capture real traffic for
+// a destination without asking the customer to add logging on their side.
+package recorder
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultWorkers is how many recordings are sent concurrently when no
+	// worker count is set.
+	DefaultWorkers = 4
+
+	// DefaultQueueSize is how many recordings wait for a worker when no queue
+	// size is set. Recordings past it are dropped.
+	DefaultQueueSize = 1000
+)
+
+// Entry is the JSON document POSTed to the recording sink for each sampled
+// delivery.
+type Entry struct {
+	RecordedAt      time.Time       `json:"recorded_at"`
+	TenantID        string          `json:"tenant_id"`
+	DestinationID   string          `json:"destination_id"`
+	DestinationType string          `json:"destination_type"`
+	Event           *models.Event   `json:"event"`
+	Attempt         *models.Attempt `json:"attempt"`
+}
+
+// Recorder sends sampled deliveries to the sink configured on the
+// destination's recording. Sends happen in the background, from a bounded
+// queue served by a fixed pool of workers, so a slow or unavailable sink
+// never delays or fails the real delivery: when the queue is full, the
+// recording is dropped and counted.
+type Recorder struct {
+	logger    *logging.Logger
+	client    *http.Client
+	timeout   time.Duration
+	workers   int
+	queueSize int
+	now       func() time.Time
+	sample    func() float64
+	dropped   metric.Int64Counter
+
+	queue  chan pendingRecording
+	wg     sync.WaitGroup
+	mu     sync.RWMutex
+	closed bool
+}
+
+// pendingRecording is a recording entry waiting to be sent.
+type pendingRecording struct {
+	ctx           context.Context
+	sinkURL       string
+	body          []byte
+	tenantID      string
+	destinationID string
+	eventID       string
+}
+
+type Option func(*Recorder)
+
+// WithHTTPClient overrides the client used to reach recording sinks.
+func WithHTTPClient(client *http.Client) Option {
+	return func(r *Recorder) {
+		r.client = client
+	}
+}
+
+// WithTimeout bounds each sink request. Default: 10s.
+func WithTimeout(timeout time.Duration) Option {
+	return func(r *Recorder) {
+		r.timeout = timeout
+	}
+}
+
+// WithWorkers sets how many recordings are sent concurrently. Default:
+// DefaultWorkers.
+func WithWorkers(workers int) Option {
+	return func(r *Recorder) {
+		if workers > 0 {
+			r.workers = workers
+		}
+	}
+}
+
+// WithQueueSize sets how many recordings wait for a worker before new ones
+// are dropped. Default: DefaultQueueSize.
+func WithQueueSize(size int) Option {
+	return func(r *Recorder) {
+		if size > 0 {
+			r.queueSize = size
+		}
+	}
+}
+
+// New returns a recorder and starts its workers. Close stops them.
+func New(logger *logging.Logger, opts ...Option) *Recorder {
+	r := &Recorder{
+		logger:    logger,
+		client:    &http.Client{},
+		timeout:   10 * time.Second,
+		workers:   DefaultWorkers,
+		queueSize: DefaultQueueSize,
+		now:       time.Now,
+		sample:    rand.Float64,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	// A failing meter leaves drops uncounted rather than failing deliveries.
+	r.dropped, _ = otel.Meter("outpost").Int64Counter("outpost.recorder.dropped",
+		metric.WithDescription("Number of delivery recordings dropped because the recording queue was full"),
+	)
+	r.queue = make(chan pendingRecording, r.queueSize)
+	r.wg.Add(r.workers)
+	for range r.workers {
+		go r.work()
+	}
+	return r
+}
+
+// Close stops accepting recordings and waits for the queued ones to be sent.
+func (r *Recorder) Close() {
+	r.mu.Lock()
+	if r.closed {
+		r.mu.Unlock()
+		return
+	}
+	r.closed = true
+	close(r.queue)
+	r.mu.Unlock()
+	r.wg.Wait()
+}
+
+// Record mirrors the delivery to the destination's recording sink when the
+// recording window is open and the delivery is selected by the sample rate.
+func (r *Recorder) Record(ctx context.Context, destination *models.Destination, event *models.Event, attempt *models.Attempt) {
+	recording := destination.Recording
+	if !recording.Active(r.now()) {
+		return
+	}
+	if recording.SampleRate < 1 && r.sample() >= recording.SampleRate {
+		return
+	}
+
+	entry := Entry{
+		RecordedAt:      r.now(),
+		TenantID:        destination.TenantID,
+		DestinationID:   destination.ID,
+		DestinationType: destination.Type,
+		Event:           event,
+		Attempt:         attempt,
+	}
+	body, err := json.Marshal(entry)
+	if err != nil {
+		r.logger.Ctx(ctx).Warn("failed to marshal recording entry",
+			zap.Error(err),
+			zap.String("destination_id", destination.ID))
+		return
+	}
+
+	// Detach from the delivery context: the delivery may complete (and cancel
+	// its context) before the sink responds.
+	r.enqueue(ctx, pendingRecording{
+		ctx:           context.WithoutCancel(ctx),
+		sinkURL:       recording.SinkURL,
+		body:          body,
+		tenantID:      destination.TenantID,
+		destinationID: destination.ID,
+		eventID:       event.ID,
+	})
+}
+
+// enqueue queues a recording for the workers, or drops it when the queue is
+// full or the recorder is closed.
+func (r *Recorder) enqueue(ctx context.Context, rec pendingRecording) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if !r.closed {
+		select {
+		case r.queue <- rec:
+			return
+		default:
+		}
+	}
+	if r.dropped != nil {
+		r.dropped.Add(ctx, 1)
+	}
+}
+
+func (r *Recorder) work() {
+	defer r.wg.Done()
+	for rec := range r.queue {
+		if err := r.send(rec.ctx, rec.sinkURL, rec.body); err != nil {
+			r.logger.Ctx(rec.ctx).Warn("failed to send delivery recording",
+				zap.Error(err),
+				zap.String("tenant_id", rec.tenantID),
+				zap.String("destination_id", rec.destinationID),
+				zap.String("event_id", rec.eventID))
+		}
+	}
+}
+
+func (r *Recorder) send(ctx context.Context, sinkURL string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sinkURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("recorder: failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("recorder: failed to send entry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("recorder: sink returned status %d: %s", resp.StatusCode, string(snippet))
+	}
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	return nil
+}
@@ -0,0 +1,138 @@
+package recorder_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/recorder"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecorder_Record(t *testing.T) {
+	t.Parallel()
+
+	received := make(chan recorder.Entry, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var entry recorder.Entry
+		if err := json.NewDecoder(r.Body).Decode(&entry); err == nil {
+			received <- entry
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	rec := recorder.New(testutil.CreateTestLogger(t))
+	t.Cleanup(rec.Close)
+
+	destination := testutil.DestinationFactory.Any()
+	event := testutil.EventFactory.Any()
+	attempt := &models.Attempt{ID: "atm_1", Status: models.AttemptStatusSuccess}
+
+	t.Run("sends entry while recording is active", func(t *testing.T) {
+		destination.Recording = &models.Recording{
+			SinkURL:    server.URL,
+			SampleRate: 1,
+			ExpiresAt:  time.Now().Add(time.Hour),
+		}
+		rec.Record(context.Background(), &destination, &event, attempt)
+
+		select {
+		case entry := <-received:
+			assert.Equal(t, destination.ID, entry.DestinationID)
+			require.NotNil(t, entry.Event)
+			assert.Equal(t, event.ID, entry.Event.ID)
+			require.NotNil(t, entry.Attempt)
+			assert.Equal(t, attempt.ID, entry.Attempt.ID)
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for recording")
+		}
+	})
+
+	t.Run("skips expired recording", func(t *testing.T) {
+		destination.Recording = &models.Recording{
+			SinkURL:    server.URL,
+			SampleRate: 1,
+			ExpiresAt:  time.Now().Add(-time.Minute),
+		}
+		rec.Record(context.Background(), &destination, &event, attempt)
+
+		select {
+		case <-received:
+			t.Fatal("expired recording should not be sent")
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+
+	t.Run("skips when sample rate is zero", func(t *testing.T) {
+		destination.Recording = &models.Recording{
+			SinkURL:    server.URL,
+			SampleRate: 0,
+			ExpiresAt:  time.Now().Add(time.Hour),
+		}
+		rec.Record(context.Background(), &destination, &event, attempt)
+
+		select {
+		case <-received:
+			t.Fatal("unsampled delivery should not be sent")
+		case <-time.After(200 * time.Millisecond):
+		}
+	})
+}
+
+func TestRecorder_DropsWhenQueueIsFull(t *testing.T) {
+	t.Parallel()
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	var mu sync.Mutex
+	received := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		mu.Lock()
+		received++
+		mu.Unlock()
+		w.WriteHeader(http.StatusOK)
+	}))
+	t.Cleanup(server.Close)
+
+	rec := recorder.New(testutil.CreateTestLogger(t), recorder.WithWorkers(1), recorder.WithQueueSize(1))
+
+	destination := testutil.DestinationFactory.Any()
+	destination.Recording = &models.Recording{
+		SinkURL:    server.URL,
+		SampleRate: 1,
+		ExpiresAt:  time.Now().Add(time.Hour),
+	}
+	event := testutil.EventFactory.Any()
+	attempt := &models.Attempt{ID: "atm_1", Status: models.AttemptStatusSuccess}
+
+	// The only worker is busy with the first recording...
+	rec.Record(context.Background(), &destination, &event, attempt)
+	select {
+	case <-started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out waiting for the first recording")
+	}
+	// ...so the second one waits in the queue and the third is dropped.
+	rec.Record(context.Background(), &destination, &event, attempt)
+	rec.Record(context.Background(), &destination, &event, attempt)
+
+	close(release)
+	rec.Close()
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, 2, received)
+}
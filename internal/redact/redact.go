@@ -0,0 +1,339 @@
+// Package redact removes credentials from the request and response data that
+// delivery attempts store in the log store.
+//
+// Header values are redacted by header name pattern in any map held under a
+// "headers" or "<name>_headers" key. Other values are redacted by JSONPath,
+// including inside string values that hold a JSON document, such as a
+// captured body.
+package redact
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// Replacement replaces redacted values.
+const Replacement = "[REDACTED]"
+
+// DefaultHeaders are the header name patterns redacted in addition to the
+// configured ones.
+var DefaultHeaders = []string{
+	"authorization",
+	"proxy-authorization",
+	"cookie",
+	"set-cookie",
+	"x-api-key",
+	"*token*",
+	"*secret*",
+	"*password*",
+}
+
+// Redactor redacts attempt data.
+type Redactor struct {
+	headers []string
+	paths   [][]segment
+}
+
+// segment is one step of a JSONPath: a key, an index, a wildcard, or a
+// recursive descent to a key.
+type segment struct {
+	key       string
+	index     int
+	isIndex   bool
+	wildcard  bool
+	recursive bool
+}
+
+// New returns a redactor of the DefaultHeaders and the given header name
+// patterns, and of the given JSONPaths. Header patterns are case-insensitive
+// globs, such as "x-*-key". JSONPaths support "$.key", "$.key[0]",
+// "$.key[*]", "$.*" and "$..key".
+func New(headers []string, jsonPaths []string) (*Redactor, error) {
+	r := &Redactor{}
+	for _, pattern := range append(append([]string{}, DefaultHeaders...), headers...) {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if pattern == "" {
+			continue
+		}
+		if _, err := path.Match(pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid header pattern %q: %w", pattern, err)
+		}
+		r.headers = append(r.headers, pattern)
+	}
+	for _, jsonPath := range jsonPaths {
+		jsonPath = strings.TrimSpace(jsonPath)
+		if jsonPath == "" {
+			continue
+		}
+		segments, err := parseJSONPath(jsonPath)
+		if err != nil {
+			return nil, err
+		}
+		r.paths = append(r.paths, segments)
+	}
+	return r, nil
+}
+
+// Redact returns data with matching headers and paths replaced by
+// Replacement. data itself is not modified; maps and slices are copied when
+// something under them is redacted.
+func (r *Redactor) Redact(data map[string]interface{}) map[string]interface{} {
+	if r == nil || data == nil {
+		return data
+	}
+	var v interface{} = data
+	v, _ = r.redactHeaders(v, false)
+	for _, segments := range r.paths {
+		v, _ = applyPath(v, segments)
+	}
+	return v.(map[string]interface{})
+}
+
+// redactHeaders walks v and redacts the values of matching headers in maps
+// held under a headers key.
+func (r *Redactor) redactHeaders(v interface{}, inHeaders bool) (interface{}, bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		var out map[string]interface{}
+		for key, value := range v {
+			var redacted interface{}
+			var changed bool
+			if inHeaders && r.matchHeader(key) {
+				redacted, changed = Replacement, true
+			} else {
+				redacted, changed = r.redactHeaders(value, isHeadersKey(key))
+			}
+			if changed {
+				if out == nil {
+					out = copyMap(v)
+				}
+				out[key] = redacted
+			}
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	case map[string]string:
+		if !inHeaders {
+			return v, false
+		}
+		var out map[string]string
+		for key := range v {
+			if r.matchHeader(key) {
+				if out == nil {
+					out = make(map[string]string, len(v))
+					for k, value := range v {
+						out[k] = value
+					}
+				}
+				out[key] = Replacement
+			}
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	case []interface{}:
+		var out []interface{}
+		for i, value := range v {
+			if redacted, changed := r.redactHeaders(value, false); changed {
+				if out == nil {
+					out = append([]interface{}(nil), v...)
+				}
+				out[i] = redacted
+			}
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	}
+	return v, false
+}
+
+func (r *Redactor) matchHeader(name string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range r.headers {
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func isHeadersKey(key string) bool {
+	key = strings.ToLower(key)
+	return key == "headers" || strings.HasSuffix(key, "_headers")
+}
+
+// parseJSONPath parses a JSONPath of the subset supported by redaction.
+func parseJSONPath(jsonPath string) ([]segment, error) {
+	rest, ok := strings.CutPrefix(jsonPath, "$")
+	if !ok {
+		return nil, fmt.Errorf("invalid json path %q: must start with $", jsonPath)
+	}
+	var segments []segment
+	for rest != "" {
+		switch {
+		case strings.HasPrefix(rest, ".."):
+			key, next := cutKey(rest[2:])
+			if key == "" || key == "*" {
+				return nil, fmt.Errorf("invalid json path %q: recursive descent requires a key", jsonPath)
+			}
+			segments = append(segments, segment{key: key, recursive: true})
+			rest = next
+		case rest[0] == '.':
+			key, next := cutKey(rest[1:])
+			if key == "" {
+				return nil, fmt.Errorf("invalid json path %q: empty key", jsonPath)
+			}
+			if key == "*" {
+				segments = append(segments, segment{wildcard: true})
+			} else {
+				segments = append(segments, segment{key: key})
+			}
+			rest = next
+		case rest[0] == '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid json path %q: unclosed bracket", jsonPath)
+			}
+			inner := rest[1:end]
+			rest = rest[end+1:]
+			if inner == "*" {
+				segments = append(segments, segment{wildcard: true})
+				continue
+			}
+			if quoted, ok := unquote(inner); ok {
+				segments = append(segments, segment{key: quoted})
+				continue
+			}
+			index, err := strconv.Atoi(inner)
+			if err != nil || index < 0 {
+				return nil, fmt.Errorf("invalid json path %q: invalid index %q", jsonPath, inner)
+			}
+			segments = append(segments, segment{index: index, isIndex: true})
+		default:
+			return nil, fmt.Errorf("invalid json path %q: unexpected %q", jsonPath, rest[:1])
+		}
+	}
+	if len(segments) == 0 {
+		return nil, fmt.Errorf("invalid json path %q: the root cannot be redacted", jsonPath)
+	}
+	return segments, nil
+}
+
+// cutKey cuts a dot-notation key from the start of s.
+func cutKey(s string) (string, string) {
+	end := strings.IndexAny(s, ".[")
+	if end < 0 {
+		return s, ""
+	}
+	return s[:end], s[end:]
+}
+
+func unquote(s string) (string, bool) {
+	if len(s) >= 2 && (s[0] == '\'' || s[0] == '"') && s[len(s)-1] == s[0] {
+		return s[1 : len(s)-1], true
+	}
+	return "", false
+}
+
+// applyPath replaces the values of v matching segments with Replacement.
+func applyPath(v interface{}, segments []segment) (interface{}, bool) {
+	if len(segments) == 0 {
+		return Replacement, true
+	}
+	seg := segments[0]
+	switch v := v.(type) {
+	case string:
+		return applyPathToJSONString(v, segments)
+	case map[string]interface{}:
+		var out map[string]interface{}
+		set := func(key string, value interface{}) {
+			if out == nil {
+				out = copyMap(v)
+			}
+			out[key] = value
+		}
+		for key, value := range v {
+			switch {
+			case seg.recursive:
+				if key == seg.key {
+					if redacted, changed := applyPath(value, segments[1:]); changed {
+						set(key, redacted)
+						continue
+					}
+				}
+				if redacted, changed := applyPath(value, segments); changed {
+					set(key, redacted)
+				}
+			case seg.wildcard || (!seg.isIndex && key == seg.key):
+				if redacted, changed := applyPath(value, segments[1:]); changed {
+					set(key, redacted)
+				}
+			}
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	case []interface{}:
+		var out []interface{}
+		for i, value := range v {
+			var redacted interface{}
+			var changed bool
+			switch {
+			case seg.recursive:
+				redacted, changed = applyPath(value, segments)
+			case seg.wildcard || (seg.isIndex && i == seg.index):
+				redacted, changed = applyPath(value, segments[1:])
+			}
+			if changed {
+				if out == nil {
+					out = append([]interface{}(nil), v...)
+				}
+				out[i] = redacted
+			}
+		}
+		if out == nil {
+			return v, false
+		}
+		return out, true
+	}
+	return v, false
+}
+
+// applyPathToJSONString applies segments to the JSON document held by s, if
+// any, and re-encodes it when something was redacted.
+func applyPathToJSONString(s string, segments []segment) (interface{}, bool) {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" || (trimmed[0] != '{' && trimmed[0] != '[') {
+		return s, false
+	}
+	var doc interface{}
+	if err := json.Unmarshal([]byte(trimmed), &doc); err != nil {
+		return s, false
+	}
+	redacted, changed := applyPath(doc, segments)
+	if !changed {
+		return s, false
+	}
+	body, err := json.Marshal(redacted)
+	if err != nil {
+		return Replacement, true
+	}
+	return string(body), true
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
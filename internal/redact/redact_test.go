@@ -0,0 +1,141 @@
+package redact_test
+
+import (
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/redact"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRedactor_Headers(t *testing.T) {
+	t.Parallel()
+
+	r, err := redact.New([]string{"X-Internal-*"}, nil)
+	require.NoError(t, err)
+
+	data := map[string]interface{}{
+		"status_code": 200,
+		"body":        `{"ok":true}`,
+		"headers": map[string]interface{}{
+			"Authorization":   "Bearer abc",
+			"Set-Cookie":      []string{"a=1", "b=2"},
+			"X-Auth-Token":    "abc",
+			"X-Internal-Id":   "123",
+			"Content-Type":    "application/json",
+			"X-Request-Id":    "req_1",
+			"X-Client-Secret": "shh",
+		},
+		"request_headers": map[string]string{
+			"authorization": "Basic xyz",
+			"accept":        "*/*",
+		},
+	}
+
+	redacted := r.Redact(data)
+
+	headers := redacted["headers"].(map[string]interface{})
+	assert.Equal(t, redact.Replacement, headers["Authorization"])
+	assert.Equal(t, redact.Replacement, headers["Set-Cookie"])
+	assert.Equal(t, redact.Replacement, headers["X-Auth-Token"])
+	assert.Equal(t, redact.Replacement, headers["X-Internal-Id"])
+	assert.Equal(t, redact.Replacement, headers["X-Client-Secret"])
+	assert.Equal(t, "application/json", headers["Content-Type"])
+	assert.Equal(t, "req_1", headers["X-Request-Id"])
+
+	requestHeaders := redacted["request_headers"].(map[string]string)
+	assert.Equal(t, redact.Replacement, requestHeaders["authorization"])
+	assert.Equal(t, "*/*", requestHeaders["accept"])
+
+	assert.Equal(t, 200, redacted["status_code"])
+	assert.Equal(t, `{"ok":true}`, redacted["body"])
+
+	// The input is left untouched
+	assert.Equal(t, "Bearer abc", data["headers"].(map[string]interface{})["Authorization"])
+}
+
+func TestRedactor_HeadersOnlyUnderHeadersKeys(t *testing.T) {
+	t.Parallel()
+
+	r, err := redact.New(nil, nil)
+	require.NoError(t, err)
+
+	data := map[string]interface{}{
+		"authorization": "not a header",
+		"error":         "invalid token",
+	}
+	assert.Equal(t, data, r.Redact(data))
+}
+
+func TestRedactor_JSONPaths(t *testing.T) {
+	t.Parallel()
+
+	r, err := redact.New(nil, []string{
+		"$.body.access_token",
+		"$.body.cards[*].number",
+		"$..password",
+		"$.meta['api key']",
+		"$.items[1]",
+	})
+	require.NoError(t, err)
+
+	data := map[string]interface{}{
+		"body": `{"access_token":"abc","cards":[{"number":"4242","brand":"visa"},{"number":"5555"}],"user":{"name":"a","password":"p"}}`,
+		"meta": map[string]interface{}{
+			"api key": "k",
+			"other":   "o",
+		},
+		"items": []interface{}{"a", "b", "c"},
+	}
+
+	redacted := r.Redact(data)
+
+	assert.JSONEq(t, `{"access_token":"[REDACTED]","cards":[{"number":"[REDACTED]","brand":"visa"},{"number":"[REDACTED]"}],"user":{"name":"a","password":"[REDACTED]"}}`, redacted["body"].(string))
+	assert.Equal(t, map[string]interface{}{"api key": redact.Replacement, "other": "o"}, redacted["meta"])
+	assert.Equal(t, []interface{}{"a", redact.Replacement, "c"}, redacted["items"])
+}
+
+func TestRedactor_JSONPathsLeaveUnmatchedBodiesAsIs(t *testing.T) {
+	t.Parallel()
+
+	r, err := redact.New(nil, []string{"$.body.token"})
+	require.NoError(t, err)
+
+	for _, body := range []string{
+		`{ "id": 1 }`,
+		`not json {`,
+		`plain text`,
+	} {
+		redacted := r.Redact(map[string]interface{}{"body": body})
+		assert.Equal(t, body, redacted["body"], "body %q should be stored as is", body)
+	}
+}
+
+func TestNew_Invalid(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		name     string
+		headers  []string
+		jsonPath string
+	}{
+		{name: "header pattern", headers: []string{"x-[a"}},
+		{name: "no root", jsonPath: "body.token"},
+		{name: "root", jsonPath: "$"},
+		{name: "empty key", jsonPath: "$.body..."},
+		{name: "recursive wildcard", jsonPath: "$..*"},
+		{name: "unclosed bracket", jsonPath: "$.items[1"},
+		{name: "invalid index", jsonPath: "$.items[-1]"},
+		{name: "unexpected", jsonPath: "$body"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			var paths []string
+			if tc.jsonPath != "" {
+				paths = []string{tc.jsonPath}
+			}
+			_, err := redact.New(tc.headers, paths)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/extra/redisotel/v9"
+	r "github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	commandStatusOK    = "ok"
+	commandStatusNil   = "nil"
+	commandStatusError = "error"
+)
+
+// instrumentMetrics records operation-level metrics for the client: the
+// latency and errors of every command and pipeline, connection dials, and
+// the connection pool stats reported by redisotel (usage, waits, timeouts).
+//
+// Retries done by go-redis happen inside a single command, so they show up
+// as command latency and, when the connection is replaced, as dials.
+func instrumentMetrics(client Client) error {
+	hookable, ok := client.(interface{ AddHook(r.Hook) })
+	if !ok {
+		return nil
+	}
+	hook, err := newMetricsHook(otel.Meter("outpost"))
+	if err != nil {
+		return err
+	}
+	hookable.AddHook(hook)
+
+	switch c := client.(type) {
+	case *r.Client:
+		return redisotel.InstrumentMetrics(c)
+	case *r.ClusterClient:
+		return redisotel.InstrumentMetrics(c)
+	}
+	return nil
+}
+
+type metricsHook struct {
+	commandDuration metric.Float64Histogram
+	commandErrors   metric.Int64Counter
+	dials           metric.Int64Counter
+}
+
+var _ r.Hook = (*metricsHook)(nil)
+
+func newMetricsHook(meter metric.Meter) (*metricsHook, error) {
+	hook := &metricsHook{}
+
+	var err error
+	if hook.commandDuration, err = meter.Float64Histogram("outpost.redis.command.duration",
+		metric.WithUnit("ms"),
+		metric.WithDescription("Redis command latency"),
+	); err != nil {
+		return nil, err
+	}
+
+	if hook.commandErrors, err = meter.Int64Counter("outpost.redis.command.errors",
+		metric.WithDescription("Number of failed Redis commands"),
+	); err != nil {
+		return nil, err
+	}
+
+	if hook.dials, err = meter.Int64Counter("outpost.redis.dials",
+		metric.WithDescription("Number of Redis connection attempts"),
+	); err != nil {
+		return nil, err
+	}
+
+	return hook, nil
+}
+
+func (h *metricsHook) DialHook(next r.DialHook) r.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := next(ctx, network, addr)
+		h.dials.Add(ctx, 1, metric.WithAttributes(attribute.String("status", commandStatus(err))))
+		return conn, err
+	}
+}
+
+func (h *metricsHook) ProcessHook(next r.ProcessHook) r.ProcessHook {
+	return func(ctx context.Context, cmd r.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		h.record(ctx, strings.ToLower(cmd.Name()), time.Since(start), err)
+		return err
+	}
+}
+
+func (h *metricsHook) ProcessPipelineHook(next r.ProcessPipelineHook) r.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []r.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		h.record(ctx, "pipeline", time.Since(start), err)
+		return err
+	}
+}
+
+func (h *metricsHook) record(ctx context.Context, command string, duration time.Duration, err error) {
+	status := commandStatus(err)
+	h.commandDuration.Record(ctx, float64(duration)/float64(time.Millisecond), metric.WithAttributes(
+		attribute.String("command", command),
+		attribute.String("status", status),
+	))
+	if status == commandStatusError {
+		h.commandErrors.Add(ctx, 1, metric.WithAttributes(
+			attribute.String("command", command),
+			attribute.String("error_type", errorType(err)),
+		))
+	}
+}
+
+// commandStatus reports redis.Nil separately, as it means a missing key
+// rather than a failure.
+func commandStatus(err error) string {
+	switch {
+	case err == nil:
+		return commandStatusOK
+	case errors.Is(err, r.Nil):
+		return commandStatusNil
+	default:
+		return commandStatusError
+	}
+}
+
+func errorType(err error) string {
+	var netErr net.Error
+	switch {
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "deadline_exceeded"
+	case errors.Is(err, r.ErrPoolTimeout):
+		return "pool_timeout"
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return "timeout"
+	case errors.As(err, &netErr):
+		return "network"
+	default:
+		return "redis"
+	}
+}
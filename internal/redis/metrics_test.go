@@ -0,0 +1,69 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	r "github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+)
+
+func TestMetricsHook(t *testing.T) {
+	ctx := context.Background()
+	reader := sdkmetric.NewManualReader()
+	provider := sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))
+
+	hook, err := newMetricsHook(provider.Meter("test"))
+	require.NoError(t, err)
+
+	mr := miniredis.RunT(t)
+	client := r.NewClient(&r.Options{Addr: mr.Addr(), DisableIdentity: true})
+	defer client.Close()
+	client.AddHook(hook)
+
+	require.NoError(t, client.Set(ctx, "key", "value", 0).Err())
+	require.ErrorIs(t, client.Get(ctx, "missing").Err(), r.Nil)
+	require.Error(t, client.Incr(ctx, "key").Err())
+	_, err = client.Pipelined(ctx, func(p r.Pipeliner) error {
+		p.Get(ctx, "key")
+		return nil
+	})
+	require.NoError(t, err)
+
+	var rm metricdata.ResourceMetrics
+	require.NoError(t, reader.Collect(ctx, &rm))
+	metrics := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	durations := map[[2]string]uint64{}
+	for _, dp := range metrics["outpost.redis.command.duration"].(metricdata.Histogram[float64]).DataPoints {
+		command, _ := dp.Attributes.Value(attribute.Key("command"))
+		status, _ := dp.Attributes.Value(attribute.Key("status"))
+		durations[[2]string{command.AsString(), status.AsString()}] += dp.Count
+	}
+	assert.Equal(t, uint64(1), durations[[2]string{"set", "ok"}])
+	assert.Equal(t, uint64(1), durations[[2]string{"get", "nil"}])
+	assert.Equal(t, uint64(1), durations[[2]string{"incr", "error"}])
+	assert.Equal(t, uint64(1), durations[[2]string{"pipeline", "ok"}])
+
+	errors := map[string]int64{}
+	for _, dp := range metrics["outpost.redis.command.errors"].(metricdata.Sum[int64]).DataPoints {
+		command, _ := dp.Attributes.Value(attribute.Key("command"))
+		errors[command.AsString()] += dp.Value
+	}
+	assert.Equal(t, int64(1), errors["incr"])
+	assert.Zero(t, errors["get"])
+
+	dials := metrics["outpost.redis.dials"].(metricdata.Sum[int64]).DataPoints
+	require.Len(t, dials, 1)
+	assert.GreaterOrEqual(t, dials[0].Value, int64(1))
+}
@@ -20,9 +20,19 @@ type (
 	MapStringStringCmd = r.MapStringStringCmd
 	SliceCmd           = r.SliceCmd
 	StringCmd          = r.StringCmd
+	IntCmd             = r.IntCmd
 	Pipeliner          = r.Pipeliner
 	Tx                 = r.Tx
 	Cmd                = r.Cmd
+	Z                  = r.Z
+	ZRangeBy           = r.ZRangeBy
+	ClusterShard       = r.ClusterShard
+	ClusterNode        = r.Node
+	SlotRange          = r.SlotRange
+	XAddArgs           = r.XAddArgs
+	XReadArgs          = r.XReadArgs
+	XMessage           = r.XMessage
+	XStream            = r.XStream
 )
 
 type Client interface {
@@ -58,6 +68,10 @@ func New(ctx context.Context, config *RedisConfig) (Client, error) {
 		return nil, err
 	}
 
+	if err := instrumentMetrics(client); err != nil {
+		return nil, err
+	}
+
 	return client, nil
 }
 
@@ -137,3 +151,15 @@ func instrumentOpenTelemetry(client Client) error {
 	}
 	return nil
 }
+
+// ForEachNode calls fn with each master of a cluster client, concurrently, or
+// with client itself otherwise. Commands such as SCAN only see the keys of the
+// node they run on.
+func ForEachNode(ctx context.Context, client Cmdable, fn func(ctx context.Context, node Cmdable) error) error {
+	if clusterClient, ok := client.(*r.ClusterClient); ok {
+		return clusterClient.ForEachMaster(ctx, func(ctx context.Context, node *r.Client) error {
+			return fn(ctx, node)
+		})
+	}
+	return fn(ctx, client)
+}
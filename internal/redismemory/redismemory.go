@@ -0,0 +1,233 @@
+// Package redismemory attributes Redis memory to the families of keys Outpost
+// writes, for capacity planning.
+//
+// Every key of the deployment is scanned and counted by family, and one in
+// every SampleEvery keys of each family is measured with MEMORY USAGE. A
+// family's memory is estimated from the average size of its sampled keys.
+package redismemory
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/hookdeck/outpost/internal/redis"
+)
+
+// Key families.
+const (
+	FamilyTenants      = "tenants"
+	FamilyDestinations = "destinations"
+	FamilyRetries      = "retries"
+	FamilyQueues       = "queues"
+	FamilyIdempotency  = "idempotency"
+	FamilyRateLimits   = "rate_limits"
+	FamilyAlerts       = "alerts"
+	FamilyAnalytics    = "analytics"
+	FamilyOther        = "other"
+)
+
+const (
+	// DefaultSampleEvery is how many keys of a family share one measurement
+	// when Options leave it unset.
+	DefaultSampleEvery = 100
+	scanCount          = 1000
+)
+
+// Options configures an analysis.
+type Options struct {
+	// SampleEvery measures one in every SampleEvery keys of each family. 1
+	// measures every key.
+	SampleEvery int
+	// MaxKeys stops the scan after this many keys. 0 scans every key.
+	MaxKeys int
+}
+
+// Report is the memory attributed to each key family, largest first.
+type Report struct {
+	Families       []FamilyUsage `json:"families"`
+	ScannedKeys    int64         `json:"scanned_keys"`
+	SampledKeys    int64         `json:"sampled_keys"`
+	EstimatedBytes int64         `json:"estimated_bytes"`
+	// Truncated is set when the scan stopped at MaxKeys, so the report only
+	// covers part of the keyspace.
+	Truncated bool `json:"truncated"`
+}
+
+// FamilyUsage is the memory attributed to a key family.
+type FamilyUsage struct {
+	Family         string `json:"family"`
+	Keys           int64  `json:"keys"`
+	SampledKeys    int64  `json:"sampled_keys"`
+	SampledBytes   int64  `json:"sampled_bytes"`
+	EstimatedBytes int64  `json:"estimated_bytes"`
+}
+
+// Analyzer reports the memory used by the keys of a deployment.
+type Analyzer struct {
+	redisClient  redis.Cmdable
+	deploymentID string
+}
+
+// New returns an analyzer of the keys of deploymentID. Without a deployment
+// ID, every key is scanned, and keys of other deployments count as "other".
+func New(redisClient redis.Cmdable, deploymentID string) *Analyzer {
+	return &Analyzer{
+		redisClient:  redisClient,
+		deploymentID: deploymentID,
+	}
+}
+
+// Analyze scans the keyspace, on every master of a cluster, and reports the
+// memory used by each key family.
+func (a *Analyzer) Analyze(ctx context.Context, opts Options) (*Report, error) {
+	if opts.SampleEvery <= 0 {
+		opts.SampleEvery = DefaultSampleEvery
+	}
+
+	var mu sync.Mutex
+	usage := make(map[string]*FamilyUsage)
+	report := &Report{}
+
+	err := redis.ForEachNode(ctx, a.redisClient, func(ctx context.Context, node redis.Cmdable) error {
+		var cursor uint64
+		for {
+			keys, nextCursor, err := node.Scan(ctx, cursor, a.deploymentPrefix()+"*", scanCount).Result()
+			if err != nil {
+				return fmt.Errorf("scan failed: %w", err)
+			}
+
+			mu.Lock()
+			if remaining := int64(opts.MaxKeys) - report.ScannedKeys; opts.MaxKeys > 0 && int64(len(keys)) > remaining {
+				keys = keys[:max(0, remaining)]
+				report.Truncated = true
+			}
+			report.ScannedKeys += int64(len(keys))
+			full := opts.MaxKeys > 0 && report.ScannedKeys >= int64(opts.MaxKeys)
+			var sampled []string
+			var sampledFamilies []*FamilyUsage
+			for _, key := range keys {
+				family := Classify(strings.TrimPrefix(key, a.deploymentPrefix()))
+				u, ok := usage[family]
+				if !ok {
+					u = &FamilyUsage{Family: family}
+					usage[family] = u
+				}
+				if u.Keys%int64(opts.SampleEvery) == 0 {
+					sampled = append(sampled, key)
+					sampledFamilies = append(sampledFamilies, u)
+				}
+				u.Keys++
+			}
+			mu.Unlock()
+
+			if err := a.measure(ctx, node, sampled, sampledFamilies, &mu, report); err != nil {
+				return err
+			}
+
+			cursor = nextCursor
+			if cursor == 0 {
+				return nil
+			}
+			if full {
+				mu.Lock()
+				report.Truncated = true
+				mu.Unlock()
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report.Families = make([]FamilyUsage, 0, len(usage))
+	for _, u := range usage {
+		if u.SampledKeys > 0 {
+			u.EstimatedBytes = u.SampledBytes * u.Keys / u.SampledKeys
+		}
+		report.EstimatedBytes += u.EstimatedBytes
+		report.Families = append(report.Families, *u)
+	}
+	sort.Slice(report.Families, func(i, j int) bool {
+		if report.Families[i].EstimatedBytes != report.Families[j].EstimatedBytes {
+			return report.Families[i].EstimatedBytes > report.Families[j].EstimatedBytes
+		}
+		return report.Families[i].Family < report.Families[j].Family
+	})
+	return report, nil
+}
+
+// measure runs MEMORY USAGE on the sampled keys in one pipeline. Keys that
+// expired since the scan are not counted as sampled.
+func (a *Analyzer) measure(ctx context.Context, node redis.Cmdable, keys []string, families []*FamilyUsage, mu *sync.Mutex, report *Report) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	pipe := node.Pipeline()
+	cmds := make([]*redis.IntCmd, len(keys))
+	for i, key := range keys {
+		cmds[i] = pipe.MemoryUsage(ctx, key)
+	}
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		return fmt.Errorf("memory usage failed: %w", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	for i, cmd := range cmds {
+		bytes, err := cmd.Result()
+		if err == redis.Nil {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("memory usage of %s failed: %w", keys[i], err)
+		}
+		families[i].SampledKeys++
+		families[i].SampledBytes += bytes
+		report.SampledKeys++
+	}
+	return nil
+}
+
+func (a *Analyzer) deploymentPrefix() string {
+	if a.deploymentID == "" {
+		return ""
+	}
+	return a.deploymentID + ":"
+}
+
+// Classify returns the family of a key, without its deployment prefix.
+func Classify(key string) string {
+	prefix, rest, _ := strings.Cut(key, ":")
+	switch prefix {
+	case "tenant":
+		// tenant:{<tenant>}:<kind>[:<destination>]
+		if _, kind, ok := strings.Cut(rest, "}:"); ok {
+			kind, _, _ = strings.Cut(kind, ":")
+			switch kind {
+			case "destinations", "destination", "destination_versions":
+				return FamilyDestinations
+			}
+		}
+		return FamilyTenants
+	case "rsmq":
+		if strings.HasPrefix(rest, "deliverymq-retry") {
+			return FamilyRetries
+		}
+		return FamilyQueues
+	case "bulkretry", "bulkretry-running", "deliveryhold":
+		return FamilyRetries
+	case "idempotency", "logmq":
+		return FamilyIdempotency
+	case "eventrate", "publishrate":
+		return FamilyRateLimits
+	case "alert", "opevents", "desthealth", "probation":
+		return FamilyAlerts
+	case "topicstats":
+		return FamilyAnalytics
+	}
+	return FamilyOther
+}
@@ -0,0 +1,115 @@
+package redismemory_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/redismemory"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+
+	cases := map[string]string{
+		"tenant:{t1}:tenant":                  redismemory.FamilyTenants,
+		"tenant:{t1}:suspended":               redismemory.FamilyTenants,
+		"tenant:{t1}:destinations":            redismemory.FamilyDestinations,
+		"tenant:{t1}:destination:d1":          redismemory.FamilyDestinations,
+		"tenant:{t1}:destination_versions:d1": redismemory.FamilyDestinations,
+		"rsmq:deliverymq-retry:Q":             redismemory.FamilyRetries,
+		"rsmq:deliverymq-retry":               redismemory.FamilyRetries,
+		"bulkretry:t1:job":                    redismemory.FamilyRetries,
+		"bulkretry-running:t1":                redismemory.FamilyRetries,
+		"deliveryhold:t1:d1":                  redismemory.FamilyRetries,
+		"rsmq:QUEUES":                         redismemory.FamilyQueues,
+		"idempotency:publishmq:e1":            redismemory.FamilyIdempotency,
+		"logmq:processed:a1":                  redismemory.FamilyIdempotency,
+		"eventrate:{t1}:ts":                   redismemory.FamilyRateLimits,
+		"publishrate:{t1}":                    redismemory.FamilyRateLimits,
+		"alert:t1:d1":                         redismemory.FamilyAlerts,
+		"opevents:exhausted:t1":               redismemory.FamilyAlerts,
+		"desthealth:t1:d1":                    redismemory.FamilyAlerts,
+		"probation":                           redismemory.FamilyAlerts,
+		"topicstats:tenant:{t1}:2026-01-01":   redismemory.FamilyAnalytics,
+		"deliverywarmup:tenants":              redismemory.FamilyOther,
+		"outpost:migration_lock":              redismemory.FamilyOther,
+	}
+	for key, want := range cases {
+		assert.Equal(t, want, redismemory.Classify(key), key)
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	t.Run("attributes keys of the deployment to families", func(t *testing.T) {
+		t.Parallel()
+		redisClient := testutil.CreateTestRedisClient(t)
+		ctx := t.Context()
+		for i := range 3 {
+			require.NoError(t, redisClient.HSet(ctx, fmt.Sprintf("dp1:tenant:{t%d}:tenant", i), "id", i).Err())
+		}
+		require.NoError(t, redisClient.HSet(ctx, "dp1:tenant:{t0}:destination:d1", "config", "{}").Err())
+		require.NoError(t, redisClient.Set(ctx, "dp1:idempotency:publishmq:e1", "processed", 0).Err())
+		require.NoError(t, redisClient.HSet(ctx, "dp2:tenant:{t9}:tenant", "id", "t9").Err())
+
+		report, err := redismemory.New(redisClient, "dp1").Analyze(ctx, redismemory.Options{SampleEvery: 1})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(5), report.ScannedKeys)
+		assert.Equal(t, int64(5), report.SampledKeys)
+		assert.False(t, report.Truncated)
+
+		keys := map[string]int64{}
+		var total int64
+		for _, family := range report.Families {
+			keys[family.Family] = family.Keys
+			assert.Equal(t, family.SampledBytes, family.EstimatedBytes, family.Family)
+			assert.Positive(t, family.EstimatedBytes, family.Family)
+			total += family.EstimatedBytes
+		}
+		assert.Equal(t, map[string]int64{
+			redismemory.FamilyTenants:      3,
+			redismemory.FamilyDestinations: 1,
+			redismemory.FamilyIdempotency:  1,
+		}, keys)
+		assert.Equal(t, total, report.EstimatedBytes)
+		assert.Equal(t, redismemory.FamilyTenants, report.Families[0].Family, "largest family should be first")
+	})
+
+	t.Run("extrapolates from samples", func(t *testing.T) {
+		t.Parallel()
+		redisClient := testutil.CreateTestRedisClient(t)
+		ctx := t.Context()
+		for i := range 10 {
+			require.NoError(t, redisClient.Set(ctx, fmt.Sprintf("idempotency:publishmq:e%d", i), "processed", 0).Err())
+		}
+
+		report, err := redismemory.New(redisClient, "").Analyze(ctx, redismemory.Options{SampleEvery: 4})
+		require.NoError(t, err)
+
+		require.Len(t, report.Families, 1)
+		family := report.Families[0]
+		assert.Equal(t, int64(10), family.Keys)
+		assert.Equal(t, int64(3), family.SampledKeys)
+		assert.Equal(t, family.SampledBytes*10/3, family.EstimatedBytes)
+	})
+
+	t.Run("stops at max keys", func(t *testing.T) {
+		t.Parallel()
+		redisClient := testutil.CreateTestRedisClient(t)
+		ctx := t.Context()
+		for i := range 10 {
+			require.NoError(t, redisClient.Set(ctx, fmt.Sprintf("publishrate:{t%d}", i), "1", 0).Err())
+		}
+
+		report, err := redismemory.New(redisClient, "").Analyze(ctx, redismemory.Options{SampleEvery: 1, MaxKeys: 4})
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(4), report.ScannedKeys)
+		assert.True(t, report.Truncated)
+	})
+}
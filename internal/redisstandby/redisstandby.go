@@ -0,0 +1,360 @@
+// Package redisstandby checks that Redis is ready to fail over: that the
+// standby replica of a primary, or a replica of every shard of a cluster, is
+// connected, caught up and holds the primary's keys.
+//
+// A standby is checked by comparing the replication offsets and key counts
+// of the primary and the standby, and by writing a canary key to the primary
+// and reading it back from the standby. A cluster is checked with CLUSTER
+// SHARDS, which reports the health and replication offset of every node.
+package redisstandby
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hookdeck/outpost/internal/redis"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const (
+	// DefaultMaxLagBytes is how far a replica may trail its primary when
+	// Options leave it unset.
+	DefaultMaxLagBytes = 1 << 20
+	// DefaultCanaryTimeout is how long the canary key has to reach the standby
+	// when Options leave it unset.
+	DefaultCanaryTimeout = 5 * time.Second
+
+	canaryTTL          = time.Minute
+	canaryPollInterval = 100 * time.Millisecond
+)
+
+// Options configures a check.
+type Options struct {
+	// MaxLagBytes is how many bytes of the replication stream a replica may
+	// trail its primary by.
+	MaxLagBytes int64
+	// MaxMissingKeysPercent is the share of the primary's keys the standby
+	// may lack, as keys written or expired during the check make the counts
+	// differ briefly. It is not checked in cluster mode.
+	MaxMissingKeysPercent float64
+	// CanaryTimeout is how long the canary key has to reach the standby.
+	CanaryTimeout time.Duration
+}
+
+// Status is the result of a check.
+type Status struct {
+	Ready     bool      `json:"ready"`
+	CheckedAt time.Time `json:"checked_at"`
+	// Reason explains why Redis isn't ready to fail over.
+	Reason string `json:"reason,omitempty"`
+	// LagBytes is how far the standby, or the furthest behind shard's most
+	// caught up replica, trails its primary.
+	LagBytes int64 `json:"lag_bytes"`
+	// MissingKeys is how many more keys the primary holds than the standby.
+	// It is always 0 in cluster mode.
+	MissingKeys int64 `json:"missing_keys"`
+}
+
+// Checker checks the failover readiness of a primary and its standby, or of
+// a cluster. It connects on the first check and reconnects after a failed
+// connection, so a standby that is down is reported rather than fatal.
+type Checker struct {
+	primaryConfig *redis.RedisConfig
+	standbyConfig *redis.RedisConfig
+	deploymentID  string
+	opts          Options
+	now           func() time.Time
+
+	primary redis.Cmdable
+	standby redis.Cmdable
+	closers []func() error
+
+	ready       metric.Int64Gauge
+	lag         metric.Int64Gauge
+	missingKeys metric.Int64Gauge
+}
+
+// New returns a checker of the standby of primary. A nil standby checks
+// primary as a cluster instead.
+func New(primary, standby *redis.RedisConfig, deploymentID string, opts Options) *Checker {
+	if opts.MaxLagBytes <= 0 {
+		opts.MaxLagBytes = DefaultMaxLagBytes
+	}
+	if opts.CanaryTimeout <= 0 {
+		opts.CanaryTimeout = DefaultCanaryTimeout
+	}
+	c := &Checker{
+		primaryConfig: primary,
+		standbyConfig: standby,
+		deploymentID:  deploymentID,
+		opts:          opts,
+		now:           time.Now,
+	}
+	// A failing meter leaves checks unrecorded rather than failing them.
+	meter := otel.Meter("outpost")
+	c.ready, _ = meter.Int64Gauge("outpost.redis.standby.ready",
+		metric.WithDescription("1 if the last check found Redis ready to fail over, 0 otherwise"),
+	)
+	c.lag, _ = meter.Int64Gauge("outpost.redis.standby.replication_lag",
+		metric.WithUnit("By"),
+		metric.WithDescription("Bytes of the replication stream the standby trails its primary by"),
+	)
+	c.missingKeys, _ = meter.Int64Gauge("outpost.redis.standby.missing_keys",
+		metric.WithDescription("Number of keys of the primary missing from the standby"),
+	)
+	return c
+}
+
+// Check checks whether Redis is ready to fail over. A check that can't
+// complete reports Redis as not ready.
+func (c *Checker) Check(ctx context.Context) Status {
+	var status Status
+	if err := c.connect(ctx); err != nil {
+		status = notReady("connection failed: %v", err)
+	} else if c.standbyConfig == nil {
+		status = c.checkCluster(ctx)
+	} else {
+		status = c.checkStandby(ctx)
+	}
+	status.CheckedAt = c.now()
+	c.record(ctx, status)
+	return status
+}
+
+// Close closes the checker's connections.
+func (c *Checker) Close() error {
+	var errs []error
+	for _, closer := range c.closers {
+		errs = append(errs, closer())
+	}
+	c.primary, c.standby, c.closers = nil, nil, nil
+	return errors.Join(errs...)
+}
+
+func (c *Checker) connect(ctx context.Context) error {
+	if c.primary == nil {
+		client, err := redis.New(ctx, c.primaryConfig)
+		if err != nil {
+			return fmt.Errorf("primary: %w", err)
+		}
+		c.primary = client
+		c.closers = append(c.closers, client.Close)
+	}
+	if c.standby == nil && c.standbyConfig != nil {
+		client, err := redis.New(ctx, c.standbyConfig)
+		if err != nil {
+			return fmt.Errorf("standby: %w", err)
+		}
+		c.standby = client
+		c.closers = append(c.closers, client.Close)
+	}
+	return nil
+}
+
+func (c *Checker) record(ctx context.Context, status Status) {
+	var ready int64
+	if status.Ready {
+		ready = 1
+	}
+	if c.ready != nil {
+		c.ready.Record(ctx, ready)
+	}
+	if c.lag != nil {
+		c.lag.Record(ctx, status.LagBytes)
+	}
+	if c.missingKeys != nil {
+		c.missingKeys.Record(ctx, status.MissingKeys)
+	}
+}
+
+func (c *Checker) checkStandby(ctx context.Context) Status {
+	primaryInfo, err := replicationInfo(ctx, c.primary)
+	if err != nil {
+		return notReady("primary replication info failed: %v", err)
+	}
+	standbyInfo, err := replicationInfo(ctx, c.standby)
+	if err != nil {
+		return notReady("standby replication info failed: %v", err)
+	}
+
+	status := evaluateReplication(primaryInfo, standbyInfo, c.opts)
+	if !status.Ready {
+		return status
+	}
+
+	if err := c.checkCanary(ctx); err != nil {
+		return notReadyWithLag(status.LagBytes, "%v", err)
+	}
+
+	primaryKeys, err := c.primary.DBSize(ctx).Result()
+	if err != nil {
+		return notReadyWithLag(status.LagBytes, "primary dbsize failed: %v", err)
+	}
+	standbyKeys, err := c.standby.DBSize(ctx).Result()
+	if err != nil {
+		return notReadyWithLag(status.LagBytes, "standby dbsize failed: %v", err)
+	}
+	status.MissingKeys = max(0, primaryKeys-standbyKeys)
+	if float64(status.MissingKeys) > float64(primaryKeys)*c.opts.MaxMissingKeysPercent/100 {
+		status.Ready = false
+		status.Reason = fmt.Sprintf("standby is missing %d of the primary's %d keys", status.MissingKeys, primaryKeys)
+	}
+	return status
+}
+
+// evaluateReplication checks that the standby replicates the primary over a
+// connected link and trails it by at most MaxLagBytes.
+func evaluateReplication(primary, standby map[string]string, opts Options) Status {
+	if standby["role"] != "slave" {
+		return notReady("standby is not a replica (role %q)", standby["role"])
+	}
+	if standby["master_link_status"] != "up" {
+		return notReady("standby's link to its primary is %q", standby["master_link_status"])
+	}
+	if primary["master_replid"] != standby["master_replid"] {
+		return notReady("standby replicates a different primary")
+	}
+	primaryOffset, err := strconv.ParseInt(primary["master_repl_offset"], 10, 64)
+	if err != nil {
+		return notReady("primary reports no replication offset")
+	}
+	standbyOffset, err := strconv.ParseInt(standby["slave_repl_offset"], 10, 64)
+	if err != nil {
+		return notReady("standby reports no replication offset")
+	}
+	lag := max(0, primaryOffset-standbyOffset)
+	if lag > opts.MaxLagBytes {
+		return notReadyWithLag(lag, "standby trails its primary by %d bytes", lag)
+	}
+	return Status{Ready: true, LagBytes: lag}
+}
+
+// checkCanary writes a key to the primary and waits for it to be readable on
+// the standby, which proves writes are replicated end to end.
+func (c *Checker) checkCanary(ctx context.Context) error {
+	key := c.canaryKey()
+	token := uuid.NewString()
+	if err := c.primary.Set(ctx, key, token, canaryTTL).Err(); err != nil {
+		return fmt.Errorf("canary write failed: %w", err)
+	}
+	defer c.primary.Del(context.WithoutCancel(ctx), key)
+
+	ctx, cancel := context.WithTimeout(ctx, c.opts.CanaryTimeout)
+	defer cancel()
+	ticker := time.NewTicker(canaryPollInterval)
+	defer ticker.Stop()
+	for {
+		value, err := c.standby.Get(ctx, key).Result()
+		if err == nil && value == token {
+			return nil
+		}
+		if err != nil && err != redis.Nil && ctx.Err() == nil {
+			return fmt.Errorf("canary read failed: %w", err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("canary key did not reach the standby within %s", c.opts.CanaryTimeout)
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Checker) canaryKey() string {
+	key := "redisstandby:canary:" + uuid.NewString()
+	if c.deploymentID == "" {
+		return key
+	}
+	return c.deploymentID + ":" + key
+}
+
+func (c *Checker) checkCluster(ctx context.Context) Status {
+	shards, err := c.primary.ClusterShards(ctx).Result()
+	if err != nil {
+		return notReady("cluster shards failed: %v", err)
+	}
+	return evaluateShards(shards, c.opts)
+}
+
+// evaluateShards checks that every shard's master has an online replica
+// trailing it by at most MaxLagBytes.
+func evaluateShards(shards []redis.ClusterShard, opts Options) Status {
+	if len(shards) == 0 {
+		return notReady("cluster reports no shards")
+	}
+	status := Status{Ready: true}
+	for _, shard := range shards {
+		var master *redis.ClusterNode
+		for i := range shard.Nodes {
+			if shard.Nodes[i].Role == "master" {
+				master = &shard.Nodes[i]
+				break
+			}
+		}
+		if master == nil {
+			return notReady("shard %s has no master", shardName(shard))
+		}
+		lag := int64(-1)
+		for _, node := range shard.Nodes {
+			if node.Role != "replica" || node.Health != "online" {
+				continue
+			}
+			if nodeLag := max(0, master.ReplicationOffset-node.ReplicationOffset); lag < 0 || nodeLag < lag {
+				lag = nodeLag
+			}
+		}
+		if lag < 0 {
+			return notReadyWithLag(status.LagBytes, "shard %s has no online replica", shardName(shard))
+		}
+		status.LagBytes = max(status.LagBytes, lag)
+		if lag > opts.MaxLagBytes {
+			return notReadyWithLag(status.LagBytes, "shard %s's replicas trail its master by %d bytes", shardName(shard), lag)
+		}
+	}
+	return status
+}
+
+func shardName(shard redis.ClusterShard) string {
+	if len(shard.Slots) == 0 {
+		return "without slots"
+	}
+	return fmt.Sprintf("%d-%d", shard.Slots[0].Start, shard.Slots[0].End)
+}
+
+// replicationInfo returns the fields of INFO replication.
+func replicationInfo(ctx context.Context, client redis.Cmdable) (map[string]string, error) {
+	info, err := client.Info(ctx, "replication").Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseInfo(info), nil
+}
+
+func parseInfo(info string) map[string]string {
+	fields := make(map[string]string)
+	for _, line := range strings.Split(info, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if name, value, ok := strings.Cut(line, ":"); ok {
+			fields[name] = value
+		}
+	}
+	return fields
+}
+
+func notReady(format string, args ...any) Status {
+	return Status{Reason: fmt.Sprintf(format, args...)}
+}
+
+func notReadyWithLag(lag int64, format string, args ...any) Status {
+	status := notReady(format, args...)
+	status.LagBytes = lag
+	return status
+}
@@ -0,0 +1,120 @@
+package redisstandby
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseInfo(t *testing.T) {
+	t.Parallel()
+
+	info := "# Replication\r\nrole:slave\r\nmaster_link_status:up\r\nslave_repl_offset:42\r\n"
+	assert.Equal(t, map[string]string{
+		"role":               "slave",
+		"master_link_status": "up",
+		"slave_repl_offset":  "42",
+	}, parseInfo(info))
+}
+
+func TestEvaluateReplication(t *testing.T) {
+	t.Parallel()
+
+	primary := map[string]string{"role": "master", "master_replid": "r1", "master_repl_offset": "1000"}
+	standby := func(overrides map[string]string) map[string]string {
+		info := map[string]string{"role": "slave", "master_link_status": "up", "master_replid": "r1", "slave_repl_offset": "900"}
+		for name, value := range overrides {
+			info[name] = value
+		}
+		return info
+	}
+	opts := Options{MaxLagBytes: 500}
+
+	status := evaluateReplication(primary, standby(nil), opts)
+	assert.True(t, status.Ready)
+	assert.Equal(t, int64(100), status.LagBytes)
+
+	cases := map[string]map[string]string{
+		"not a replica":          {"role": "master"},
+		"link down":              {"master_link_status": "down"},
+		"replicates another one": {"master_replid": "r2"},
+		"no offset":              {"slave_repl_offset": ""},
+		"lagging":                {"slave_repl_offset": "400"},
+	}
+	for name, overrides := range cases {
+		status := evaluateReplication(primary, standby(overrides), opts)
+		assert.False(t, status.Ready, name)
+		assert.NotEmpty(t, status.Reason, name)
+	}
+}
+
+func TestEvaluateShards(t *testing.T) {
+	t.Parallel()
+
+	shard := func(start int64, replicas ...redis.ClusterNode) redis.ClusterShard {
+		return redis.ClusterShard{
+			Slots: []redis.SlotRange{{Start: start, End: start + 100}},
+			Nodes: append([]redis.ClusterNode{{Role: "master", Health: "online", ReplicationOffset: 1000}}, replicas...),
+		}
+	}
+	replica := func(health string, offset int64) redis.ClusterNode {
+		return redis.ClusterNode{Role: "replica", Health: health, ReplicationOffset: offset}
+	}
+	opts := Options{MaxLagBytes: 500}
+
+	t.Run("ready when every shard has a caught up replica", func(t *testing.T) {
+		t.Parallel()
+		status := evaluateShards([]redis.ClusterShard{
+			shard(0, replica("online", 990)),
+			shard(101, replica("loading", 0), replica("online", 700)),
+		}, opts)
+		assert.True(t, status.Ready, status.Reason)
+		assert.Equal(t, int64(300), status.LagBytes)
+	})
+
+	t.Run("not ready when a shard has no online replica", func(t *testing.T) {
+		t.Parallel()
+		status := evaluateShards([]redis.ClusterShard{
+			shard(0, replica("online", 1000)),
+			shard(101, replica("fail", 1000)),
+		}, opts)
+		assert.False(t, status.Ready)
+		assert.Equal(t, "shard 101-201 has no online replica", status.Reason)
+	})
+
+	t.Run("not ready when a shard's replicas are lagging", func(t *testing.T) {
+		t.Parallel()
+		status := evaluateShards([]redis.ClusterShard{shard(0, replica("online", 100))}, opts)
+		assert.False(t, status.Ready)
+		assert.Equal(t, int64(900), status.LagBytes)
+	})
+}
+
+func TestCheckCanary(t *testing.T) {
+	t.Parallel()
+
+	t.Run("passes when the standby serves the primary's writes", func(t *testing.T) {
+		t.Parallel()
+		client := testutil.CreateTestRedisClient(t)
+		checker := New(&redis.RedisConfig{}, &redis.RedisConfig{}, "dp1", Options{})
+		checker.primary, checker.standby = client, client
+		require.NoError(t, checker.checkCanary(t.Context()))
+
+		keys, err := client.Keys(t.Context(), "*").Result()
+		require.NoError(t, err)
+		assert.Empty(t, keys, "the canary key should be deleted")
+	})
+
+	t.Run("fails when writes don't reach the standby", func(t *testing.T) {
+		t.Parallel()
+		primary := testutil.CreateTestRedisClient(t)
+		standby := testutil.CreateTestRedisClient(t)
+		checker := New(&redis.RedisConfig{}, &redis.RedisConfig{}, "", Options{CanaryTimeout: 300 * time.Millisecond})
+		checker.primary, checker.standby = primary, standby
+		assert.ErrorContains(t, checker.checkCanary(t.Context()), "did not reach the standby")
+	})
+}
@@ -0,0 +1,378 @@
+// Package replication replicates the tenants and destinations of a primary
+// deployment to a secondary one, so an active/passive deployment in another
+// region starts with warm entity state.
+//
+// The primary records changes in the changefeed of its tenant store. The
+// replicator tails it and applies the current state of each changed entity,
+// read from the primary's Redis, to the local tenant store. When it has no
+// position in the changefeed, or the changefeed was trimmed past it, it runs
+// a full sync of every tenant and destination first.
+package replication
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/redislock"
+	"github.com/hookdeck/outpost/internal/tenantstore/driver"
+	"github.com/hookdeck/outpost/internal/tenantstore/redistenantstore"
+)
+
+const (
+	// DefaultBatchSize is the number of changes applied per Sync.
+	DefaultBatchSize = 100
+
+	// lockTTL bounds how long a crashed replica blocks the others.
+	lockTTL = 30 * time.Second
+
+	listPageSize = 100
+
+	// startCursor is the position before the first entry of a stream.
+	startCursor = "0-0"
+)
+
+// ErrFullSyncNotSupported is returned when the primary's tenants can't be
+// listed for a full sync. Replication then only covers the changes still in
+// the changefeed.
+var ErrFullSyncNotSupported = errors.New("full sync requires listing tenants, which the primary tenant store doesn't support")
+
+// Replicator applies the changes of a primary deployment to the local tenant
+// store. Replicas of a deployment take turns through a lock, so a single one
+// replicates at a time.
+type Replicator struct {
+	source       redis.Cmdable
+	sourceStore  driver.TenantStore
+	sourceStream string
+	target       redis.Cmdable
+	targetStore  driver.TenantStore
+	deploymentID string
+	batchSize    int
+	lock         redislock.Lock
+}
+
+// Option configures a Replicator.
+type Option func(*Replicator)
+
+// WithSourceDeploymentID sets the deployment ID of the primary.
+func WithSourceDeploymentID(deploymentID string) Option {
+	return func(r *Replicator) {
+		r.sourceStream = redistenantstore.ChangefeedKey(deploymentID)
+	}
+}
+
+// WithDeploymentID sets the deployment ID of the local deployment, which
+// prefixes the replication state kept in the local Redis.
+func WithDeploymentID(deploymentID string) Option {
+	return func(r *Replicator) {
+		r.deploymentID = deploymentID
+	}
+}
+
+// WithBatchSize sets the number of changes applied per Sync.
+func WithBatchSize(size int) Option {
+	return func(r *Replicator) {
+		r.batchSize = size
+	}
+}
+
+// New creates a replicator from the primary's Redis and tenant store to the
+// local ones. The source store must be able to decrypt the primary's
+// credentials; the target store encrypts them with the local secret.
+func New(source redis.Cmdable, sourceStore driver.TenantStore, target redis.Cmdable, targetStore driver.TenantStore, opts ...Option) *Replicator {
+	r := &Replicator{
+		source:       source,
+		sourceStore:  sourceStore,
+		sourceStream: redistenantstore.ChangefeedKey(""),
+		target:       target,
+		targetStore:  targetStore,
+		batchSize:    DefaultBatchSize,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.lock = redislock.New(target,
+		redislock.WithKey(r.key("replication:lock")),
+		redislock.WithTTL(lockTTL),
+	)
+	return r
+}
+
+func (r *Replicator) key(name string) string {
+	if r.deploymentID == "" {
+		return name
+	}
+	return fmt.Sprintf("%s:%s", r.deploymentID, name)
+}
+
+// Result summarizes a Sync.
+type Result struct {
+	// FullSync reports whether every tenant and destination was synced.
+	FullSync bool
+	// Tenants and Destinations count the entities written or deleted.
+	Tenants      int
+	Destinations int
+	// Pending reports whether more changes are waiting to be applied.
+	Pending bool
+}
+
+// Sync applies the next batch of changes, after a full sync when needed. It
+// does nothing while another replica holds the lock. Changes applied before
+// an error are kept and not applied again.
+func (r *Replicator) Sync(ctx context.Context) (*Result, error) {
+	result := &Result{}
+	locked, err := r.lock.AttemptLock(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to acquire replication lock: %w", err)
+	}
+	if !locked {
+		return result, nil
+	}
+	defer r.lock.Unlock(context.WithoutCancel(ctx))
+
+	cursor, err := r.cursor(ctx)
+	if err != nil {
+		return result, err
+	}
+	if cursor == "" {
+		if err := r.fullSync(ctx, result); err != nil {
+			return result, err
+		}
+		return result, nil
+	}
+
+	entries, err := r.source.XRead(ctx, &redis.XReadArgs{
+		Streams: []string{r.sourceStream, cursor},
+		Count:   int64(r.batchSize),
+		Block:   -1,
+	}).Result()
+	if err != nil && err != redis.Nil {
+		return result, fmt.Errorf("failed to read changefeed: %w", err)
+	}
+	var messages []redis.XMessage
+	if len(entries) > 0 {
+		messages = entries[0].Messages
+	}
+	result.Pending = len(messages) == r.batchSize
+
+	for _, msg := range messages {
+		change, err := redistenantstore.ParseChange(msg)
+		if err != nil {
+			return result, err
+		}
+		if err := r.apply(ctx, change, result); err != nil {
+			return result, fmt.Errorf("failed to apply change %s: %w", change.ID, err)
+		}
+		if err := r.setCursor(ctx, change.ID); err != nil {
+			return result, err
+		}
+	}
+	return result, nil
+}
+
+// cursor returns the ID of the last change applied, or "" when a full sync
+// is needed.
+func (r *Replicator) cursor(ctx context.Context) (string, error) {
+	cursor, err := r.target.Get(ctx, r.key("replication:cursor")).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read replication cursor: %w", err)
+	}
+
+	if cursor == startCursor {
+		return cursor, nil
+	}
+
+	// The changefeed was trimmed past the cursor when the last change applied
+	// is gone and newer entries remain. Changes after the cursor may be lost.
+	first, err := r.source.XRangeN(ctx, r.sourceStream, "-", "+", 1).Result()
+	if err != nil {
+		return "", fmt.Errorf("failed to read changefeed: %w", err)
+	}
+	if len(first) == 0 || compareIDs(first[0].ID, cursor) <= 0 {
+		return cursor, nil
+	}
+	return "", nil
+}
+
+func (r *Replicator) setCursor(ctx context.Context, cursor string) error {
+	if err := r.target.Set(ctx, r.key("replication:cursor"), cursor, 0).Err(); err != nil {
+		return fmt.Errorf("failed to save replication cursor: %w", err)
+	}
+	return nil
+}
+
+func (r *Replicator) apply(ctx context.Context, change redistenantstore.Change, result *Result) error {
+	switch {
+	case change.Entity == redistenantstore.ChangeEntityTenant && change.Op == redistenantstore.ChangeOpUpsert:
+		return r.syncTenant(ctx, change.TenantID, result)
+	case change.Entity == redistenantstore.ChangeEntityTenant:
+		return r.deleteTenant(ctx, change.TenantID, result)
+	case change.Op == redistenantstore.ChangeOpUpsert:
+		return r.syncDestination(ctx, change.TenantID, change.DestinationID, result)
+	default:
+		return r.deleteDestination(ctx, change.TenantID, change.DestinationID, result)
+	}
+}
+
+// syncTenant copies the current state of a tenant. A tenant that was deleted
+// since is deleted, and one suspended on the primary is left as is, as it
+// can't be read without resuming it.
+func (r *Replicator) syncTenant(ctx context.Context, tenantID string, result *Result) error {
+	tenant, err := r.sourceStore.RetrieveTenant(ctx, tenantID)
+	switch {
+	case errors.Is(err, driver.ErrTenantDeleted) || (err == nil && tenant == nil):
+		return r.deleteTenant(ctx, tenantID, result)
+	case errors.Is(err, driver.ErrColdStorageNotConfigured):
+		return nil
+	case err != nil:
+		return err
+	}
+	if err := r.targetStore.UpsertTenant(ctx, *tenant); err != nil {
+		return err
+	}
+	result.Tenants++
+	return nil
+}
+
+func (r *Replicator) deleteTenant(ctx context.Context, tenantID string, result *Result) error {
+	err := r.targetStore.DeleteTenant(ctx, tenantID)
+	if errors.Is(err, driver.ErrTenantNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	result.Tenants++
+	return nil
+}
+
+func (r *Replicator) syncDestination(ctx context.Context, tenantID, destinationID string, result *Result) error {
+	destination, err := r.sourceStore.RetrieveDestination(ctx, tenantID, destinationID)
+	switch {
+	case errors.Is(err, driver.ErrDestinationDeleted) || (err == nil && destination == nil):
+		return r.deleteDestination(ctx, tenantID, destinationID, result)
+	case errors.Is(err, driver.ErrColdStorageNotConfigured):
+		return nil
+	case err != nil:
+		return err
+	}
+	if err := r.targetStore.UpsertDestination(ctx, *destination); err != nil {
+		return err
+	}
+	result.Destinations++
+	return nil
+}
+
+func (r *Replicator) deleteDestination(ctx context.Context, tenantID, destinationID string, result *Result) error {
+	err := r.targetStore.DeleteDestination(ctx, tenantID, destinationID)
+	if errors.Is(err, driver.ErrDestinationNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	result.Destinations++
+	return nil
+}
+
+// fullSync copies every tenant and destination of the primary, and deletes
+// local destinations of those tenants the primary no longer has. The cursor
+// is taken before copying, so changes made during the sync are applied again
+// afterwards.
+//
+// Without tenant listing on the primary, replication starts from the oldest
+// change still in the changefeed.
+func (r *Replicator) fullSync(ctx context.Context, result *Result) error {
+	cursor := startCursor
+	last, err := r.source.XRevRangeN(ctx, r.sourceStream, "+", "-", 1).Result()
+	if err != nil {
+		return fmt.Errorf("failed to read changefeed: %w", err)
+	}
+	if len(last) > 0 {
+		cursor = last[0].ID
+	}
+
+	req := driver.ListTenantRequest{Limit: listPageSize, Dir: "asc"}
+	for {
+		page, err := r.sourceStore.ListTenant(ctx, req)
+		if errors.Is(err, driver.ErrListTenantNotSupported) {
+			if err := r.setCursor(ctx, startCursor); err != nil {
+				return err
+			}
+			return ErrFullSyncNotSupported
+		}
+		if err != nil {
+			return fmt.Errorf("failed to list tenants: %w", err)
+		}
+		for _, tenant := range page.Models {
+			if err := r.targetStore.UpsertTenant(ctx, tenant); err != nil {
+				return err
+			}
+			result.Tenants++
+			if err := r.syncDestinations(ctx, tenant.ID, result); err != nil {
+				return err
+			}
+		}
+		if page.Pagination.Next == nil || *page.Pagination.Next == "" {
+			break
+		}
+		req.Next = *page.Pagination.Next
+	}
+
+	result.FullSync = true
+	return r.setCursor(ctx, cursor)
+}
+
+func (r *Replicator) syncDestinations(ctx context.Context, tenantID string, result *Result) error {
+	destinations, err := r.sourceStore.ListDestination(ctx, driver.ListDestinationRequest{TenantID: tenantID})
+	if err != nil {
+		return fmt.Errorf("failed to list destinations of tenant %s: %w", tenantID, err)
+	}
+	current := make(map[string]bool, len(destinations))
+	for _, destination := range destinations {
+		current[destination.ID] = true
+		if err := r.targetStore.UpsertDestination(ctx, destination); err != nil {
+			return err
+		}
+		result.Destinations++
+	}
+
+	local, err := r.targetStore.ListDestination(ctx, driver.ListDestinationRequest{TenantID: tenantID})
+	if err != nil {
+		return fmt.Errorf("failed to list local destinations of tenant %s: %w", tenantID, err)
+	}
+	for _, destination := range local {
+		if current[destination.ID] {
+			continue
+		}
+		if err := r.deleteDestination(ctx, tenantID, destination.ID, result); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareIDs compares two stream entry IDs.
+func compareIDs(a, b string) int {
+	aMs, aSeq := splitID(a)
+	bMs, bSeq := splitID(b)
+	if c := cmp.Compare(aMs, bMs); c != 0 {
+		return c
+	}
+	return cmp.Compare(aSeq, bSeq)
+}
+
+func splitID(id string) (uint64, uint64) {
+	ms, seq, _ := strings.Cut(id, "-")
+	msValue, _ := strconv.ParseUint(ms, 10, 64)
+	seqValue, _ := strconv.ParseUint(seq, 10, 64)
+	return msValue, seqValue
+}
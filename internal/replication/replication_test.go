@@ -0,0 +1,182 @@
+package replication_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/replication"
+	"github.com/hookdeck/outpost/internal/tenantstore/driver"
+	"github.com/hookdeck/outpost/internal/tenantstore/redistenantstore"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type deployments struct {
+	primaryRedis   redis.Cmdable
+	primary        driver.TenantStore
+	secondaryRedis redis.Cmdable
+	secondary      driver.TenantStore
+	replicator     *replication.Replicator
+}
+
+// newDeployments sets up a primary recording changes and a secondary
+// replicating them, each with its own Redis and AES secret. miniredis has no
+// RediSearch, so the first Sync can't run a full sync and starts from the
+// oldest change.
+func newDeployments(t *testing.T) *deployments {
+	t.Helper()
+	d := &deployments{
+		primaryRedis:   testutil.CreateTestRedisClient(t),
+		secondaryRedis: testutil.CreateTestRedisClient(t),
+	}
+	d.primary = redistenantstore.New(d.primaryRedis,
+		redistenantstore.WithSecret("primary-secret"),
+		redistenantstore.WithAvailableTopics(testutil.TestTopics),
+		redistenantstore.WithDeploymentID("primary"),
+		redistenantstore.WithChangefeed(1000),
+	)
+	d.secondary = redistenantstore.New(d.secondaryRedis,
+		redistenantstore.WithSecret("secondary-secret"),
+		redistenantstore.WithAvailableTopics(testutil.TestTopics),
+		redistenantstore.WithDeploymentID("secondary"),
+	)
+	source := redistenantstore.New(d.primaryRedis,
+		redistenantstore.WithSecret("primary-secret"),
+		redistenantstore.WithDeploymentID("primary"),
+	)
+	require.NoError(t, source.Init(context.Background()))
+	d.replicator = replication.New(d.primaryRedis, source, d.secondaryRedis, d.secondary,
+		replication.WithSourceDeploymentID("primary"),
+		replication.WithDeploymentID("secondary"),
+	)
+	return d
+}
+
+// syncAll runs Sync until no change is pending.
+func (d *deployments) syncAll(t *testing.T) {
+	t.Helper()
+	for {
+		result, err := d.replicator.Sync(context.Background())
+		if errors.Is(err, replication.ErrFullSyncNotSupported) {
+			continue
+		}
+		require.NoError(t, err)
+		if !result.Pending {
+			return
+		}
+	}
+}
+
+func TestReplicator(t *testing.T) {
+	t.Parallel()
+
+	t.Run("replicates tenants and destinations", func(t *testing.T) {
+		t.Parallel()
+		d := newDeployments(t)
+		ctx := t.Context()
+
+		tenant := testutil.TenantFactory.Any()
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithTenantID(tenant.ID),
+			testutil.DestinationFactory.WithCredentials(map[string]string{"secret": "whsec_123"}),
+		)
+		require.NoError(t, d.primary.UpsertTenant(ctx, tenant))
+		require.NoError(t, d.primary.CreateDestination(ctx, destination))
+
+		_, err := d.replicator.Sync(ctx)
+		require.ErrorIs(t, err, replication.ErrFullSyncNotSupported)
+		result, err := d.replicator.Sync(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Tenants)
+		assert.Equal(t, 1, result.Destinations)
+
+		replicated, err := d.secondary.RetrieveTenant(ctx, tenant.ID)
+		require.NoError(t, err)
+		require.NotNil(t, replicated)
+		assert.Equal(t, 1, replicated.DestinationsCount)
+		replicatedDestination, err := d.secondary.RetrieveDestination(ctx, tenant.ID, destination.ID)
+		require.NoError(t, err)
+		require.NotNil(t, replicatedDestination)
+		assert.Equal(t, destination.Credentials, replicatedDestination.Credentials, "credentials are re-encrypted with the local secret")
+
+		require.NoError(t, d.primary.DeleteDestination(ctx, tenant.ID, destination.ID))
+		d.syncAll(t)
+		_, err = d.secondary.RetrieveDestination(ctx, tenant.ID, destination.ID)
+		assert.ErrorIs(t, err, driver.ErrDestinationDeleted)
+
+		require.NoError(t, d.primary.DeleteTenant(ctx, tenant.ID))
+		d.syncAll(t)
+		_, err = d.secondary.RetrieveTenant(ctx, tenant.ID)
+		assert.ErrorIs(t, err, driver.ErrTenantDeleted)
+	})
+
+	t.Run("applies the current state of changed entities", func(t *testing.T) {
+		t.Parallel()
+		d := newDeployments(t)
+		ctx := t.Context()
+
+		tenant := testutil.TenantFactory.Any()
+		require.NoError(t, d.primary.UpsertTenant(ctx, tenant))
+		tenant.Metadata = map[string]string{"region": "eu"}
+		require.NoError(t, d.primary.UpsertTenant(ctx, tenant))
+		gone := testutil.TenantFactory.Any()
+		require.NoError(t, d.primary.UpsertTenant(ctx, gone))
+		require.NoError(t, d.primary.DeleteTenant(ctx, gone.ID))
+
+		d.syncAll(t)
+
+		replicated, err := d.secondary.RetrieveTenant(ctx, tenant.ID)
+		require.NoError(t, err)
+		require.NotNil(t, replicated)
+		assert.Equal(t, tenant.Metadata, replicated.Metadata)
+		replicated, err = d.secondary.RetrieveTenant(ctx, gone.ID)
+		require.NoError(t, err)
+		assert.Nil(t, replicated, "a tenant deleted before it was replicated is never created")
+	})
+
+	t.Run("resumes from the last change applied", func(t *testing.T) {
+		t.Parallel()
+		d := newDeployments(t)
+		ctx := t.Context()
+
+		require.NoError(t, d.primary.UpsertTenant(ctx, testutil.TenantFactory.Any()))
+		d.syncAll(t)
+
+		result, err := d.replicator.Sync(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &replication.Result{}, result, "applied changes are not applied again")
+
+		require.NoError(t, d.primary.UpsertTenant(ctx, testutil.TenantFactory.Any()))
+		result, err = d.replicator.Sync(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 1, result.Tenants)
+	})
+
+	t.Run("runs a full sync when the changefeed was trimmed past it", func(t *testing.T) {
+		t.Parallel()
+		d := newDeployments(t)
+		ctx := t.Context()
+
+		require.NoError(t, d.primary.UpsertTenant(ctx, testutil.TenantFactory.Any()))
+		require.NoError(t, d.secondaryRedis.Set(ctx, "secondary:replication:cursor", "1-0", 0).Err())
+
+		_, err := d.replicator.Sync(ctx)
+		assert.ErrorIs(t, err, replication.ErrFullSyncNotSupported)
+	})
+
+	t.Run("replicates from a single replica at a time", func(t *testing.T) {
+		t.Parallel()
+		d := newDeployments(t)
+		ctx := t.Context()
+
+		require.NoError(t, d.primary.UpsertTenant(ctx, testutil.TenantFactory.Any()))
+		require.NoError(t, d.secondaryRedis.Set(ctx, "secondary:replication:lock", "other-replica", 0).Err())
+
+		result, err := d.replicator.Sync(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, &replication.Result{}, result)
+	})
+}
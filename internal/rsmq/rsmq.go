@@ -7,6 +7,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/hookdeck/outpost/internal/clock"
 	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/redis/go-redis/v9"
 	"go.uber.org/zap"
@@ -109,6 +110,7 @@ type RedisSMQ struct {
 	client RedisClient
 	ns     string
 	logger *logging.Logger
+	clock  clock.Clock
 }
 
 // QueueAttributes contains some attributes and stats of queue
@@ -185,6 +187,13 @@ func NewRedisSMQ(client RedisClient, ns string, logger ...*logging.Logger) *Redi
 	return rsmq
 }
 
+// SetClock makes message visibility follow c instead of the Redis server
+// time. Every client of a queue must share the same clock; it exists so tests
+// can make delayed messages due without waiting.
+func (rsmq *RedisSMQ) SetClock(c clock.Clock) {
+	rsmq.clock = c
+}
+
 // CreateQueue creates a new queue with given attributes
 // to create new queue with default attributes:
 //
@@ -317,6 +326,9 @@ func (rsmq *RedisSMQ) getQueue(qname string, uid bool) (*queueDef, error) {
 	maxsize := convertStringToInt[int](hmGetValues[2])
 
 	t := timeCmd.Val()
+	if rsmq.clock != nil {
+		t = rsmq.clock.Now()
+	}
 
 	randUID := ""
 	if uid {
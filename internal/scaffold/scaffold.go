@@ -0,0 +1,246 @@
+// Package scaffold generates webhook consumer endpoints for a deployment: a
+// small server in Go, Node or Python that verifies Outpost signatures and
+// dispatches events to one handler per topic.
+//
+// Outpost keeps no schema for topic data, so handlers receive the event data
+// as raw JSON for the consumer to decode.
+package scaffold
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"go/format"
+	"path"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+//go:embed templates
+var templates embed.FS
+
+// Languages consumers can be generated in.
+const (
+	LanguageGo     = "go"
+	LanguageNode   = "node"
+	LanguagePython = "python"
+)
+
+// Languages lists the supported languages.
+var Languages = []string{LanguageGo, LanguageNode, LanguagePython}
+
+// Signature schemes consumers can verify.
+const (
+	// SchemeDefault verifies the deployment's signature: the configured
+	// algorithm and encoding over the body, with signatures after Prefix in
+	// the signature header.
+	SchemeDefault = "default"
+	// SchemeStripe verifies "t=<unix>,v1=<hex>" signatures of "<unix>.<body>".
+	SchemeStripe = "stripe"
+	// SchemeStandard verifies Standard Webhooks signatures, as sent in
+	// 'standard' mode and by destinations with the svix signature scheme.
+	SchemeStandard = "standard"
+)
+
+var (
+	ErrUnsupportedLanguage  = errors.New("unsupported language")
+	ErrUnsupportedSignature = errors.New("unsupported signature")
+	ErrNoTopicHeader        = errors.New("the topic header is disabled, so consumers can't dispatch events by topic")
+)
+
+// Signature describes how deliveries are signed.
+type Signature struct {
+	Scheme string
+	// Header carries the signature.
+	Header string
+	// IDHeader and TimestampHeader carry the signed message ID and timestamp
+	// of SchemeStandard.
+	IDHeader        string
+	TimestampHeader string
+	// Prefix, Algorithm and Encoding describe SchemeDefault signatures.
+	Prefix    string
+	Algorithm string
+	Encoding  string
+}
+
+// defaultSignatureSuffix ends the signature header templates consumers can
+// verify: every signature, comma separated, after a literal prefix.
+const defaultSignatureSuffix = `{{.Signatures | join ","}}`
+
+// NewDefaultSignature returns the signature of a deployment in 'default' mode
+// from its webhook signature config. Only signatures of the body, with a
+// header template of a literal prefix followed by the signatures, can be
+// verified by generated consumers.
+func NewDefaultSignature(header, contentTemplate, headerTemplate, algorithm, encoding string) (Signature, error) {
+	if header == "" {
+		return Signature{}, fmt.Errorf("%w: the signature header is disabled", ErrUnsupportedSignature)
+	}
+	if contentTemplate != "{{.Body}}" {
+		return Signature{}, fmt.Errorf("%w: content template %q, only {{.Body}} is supported", ErrUnsupportedSignature, contentTemplate)
+	}
+	prefix, ok := strings.CutSuffix(headerTemplate, defaultSignatureSuffix)
+	if !ok || strings.Contains(prefix, "{{") {
+		return Signature{}, fmt.Errorf("%w: header template %q, only '<prefix>%s' is supported", ErrUnsupportedSignature, headerTemplate, defaultSignatureSuffix)
+	}
+	return Signature{
+		Scheme:    SchemeDefault,
+		Header:    header,
+		Prefix:    prefix,
+		Algorithm: algorithm,
+		Encoding:  encoding,
+	}, nil
+}
+
+// Options configures the generated consumer.
+type Options struct {
+	Language  string
+	Topics    []string
+	Signature Signature
+	// EventIDHeader and TopicHeader are the headers deliveries carry the
+	// event ID and topic in.
+	EventIDHeader string
+	TopicHeader   string
+}
+
+// File is a generated file, with a path relative to the consumer's root.
+type File struct {
+	Path    string
+	Content []byte
+}
+
+type topic struct {
+	Name   string
+	GoName string
+	JSName string
+	PyName string
+}
+
+type templateData struct {
+	Options
+	Topics []topic
+}
+
+// Generate returns the files of a consumer.
+func Generate(opts Options) ([]File, error) {
+	if err := validate(opts); err != nil {
+		return nil, err
+	}
+
+	data := templateData{Options: opts}
+	seen := make(map[string]int)
+	for _, name := range opts.Topics {
+		if name == "" || name == "*" {
+			continue
+		}
+		words := identifierWords(name)
+		// Topics that only differ by punctuation get numbered handlers
+		key := strings.Join(words, "_")
+		if seen[key]++; seen[key] > 1 {
+			words = append(words, fmt.Sprint(seen[key]))
+		}
+		data.Topics = append(data.Topics, topic{
+			Name:   name,
+			GoName: "handle" + camelCase(words),
+			JSName: "handle" + camelCase(words),
+			PyName: "handle_" + strings.Join(words, "_"),
+		})
+	}
+
+	root := path.Join("templates", opts.Language)
+	entries, err := templates.ReadDir(root)
+	if err != nil {
+		return nil, err
+	}
+	tmplPaths := []string{path.Join("templates", "README.md.tmpl")}
+	for _, entry := range entries {
+		tmplPaths = append(tmplPaths, path.Join(root, entry.Name()))
+	}
+
+	files := make([]File, 0, len(tmplPaths))
+	for _, tmplPath := range tmplPaths {
+		content, err := render(tmplPath, data)
+		if err != nil {
+			return nil, err
+		}
+		filePath := strings.TrimSuffix(path.Base(tmplPath), ".tmpl")
+		if path.Ext(filePath) == ".go" {
+			if content, err = format.Source(content); err != nil {
+				return nil, fmt.Errorf("failed to format %s: %w", filePath, err)
+			}
+		}
+		files = append(files, File{Path: filePath, Content: content})
+	}
+	return files, nil
+}
+
+func validate(opts Options) error {
+	switch opts.Language {
+	case LanguageGo, LanguageNode, LanguagePython:
+	default:
+		return fmt.Errorf("%w %q: must be one of %s", ErrUnsupportedLanguage, opts.Language, strings.Join(Languages, ", "))
+	}
+	if opts.TopicHeader == "" {
+		return ErrNoTopicHeader
+	}
+	signature := opts.Signature
+	if signature.Header == "" {
+		return fmt.Errorf("%w: no signature header", ErrUnsupportedSignature)
+	}
+	switch signature.Scheme {
+	case SchemeDefault:
+		if signature.Algorithm != "hmac-sha256" && signature.Algorithm != "hmac-sha1" {
+			return fmt.Errorf("%w: algorithm %q", ErrUnsupportedSignature, signature.Algorithm)
+		}
+		if signature.Encoding != "hex" && signature.Encoding != "base64" {
+			return fmt.Errorf("%w: encoding %q", ErrUnsupportedSignature, signature.Encoding)
+		}
+	case SchemeStripe:
+	case SchemeStandard:
+		if signature.IDHeader == "" || signature.TimestampHeader == "" {
+			return fmt.Errorf("%w: standard signatures need ID and timestamp headers", ErrUnsupportedSignature)
+		}
+	default:
+		return fmt.Errorf("%w: scheme %q", ErrUnsupportedSignature, signature.Scheme)
+	}
+	return nil
+}
+
+func render(tmplPath string, data templateData) ([]byte, error) {
+	tmpl, err := template.New(path.Base(tmplPath)).Funcs(template.FuncMap{
+		"quote": func(s string) string { return fmt.Sprintf("%q", s) },
+		"lower": strings.ToLower,
+	}).ParseFS(templates, tmplPath)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// identifierWords splits a topic into lowercase words of letters and digits,
+// e.g. "user.created" into "user" and "created".
+func identifierWords(name string) []string {
+	words := strings.FieldsFunc(strings.ToLower(name), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return []string{"topic"}
+	}
+	return words
+}
+
+// camelCase joins words, each starting with an uppercase letter.
+func camelCase(words []string) string {
+	var b strings.Builder
+	for _, word := range words {
+		runes := []rune(word)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	return b.String()
+}
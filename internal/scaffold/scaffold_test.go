@@ -0,0 +1,172 @@
+package scaffold_test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/scaffold"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var signatures = map[string]scaffold.Signature{
+	"default": {
+		Scheme:    scaffold.SchemeDefault,
+		Header:    "x-outpost-signature",
+		Prefix:    "v0=",
+		Algorithm: "hmac-sha256",
+		Encoding:  "hex",
+	},
+	"default sha1 base64": {
+		Scheme:    scaffold.SchemeDefault,
+		Header:    "x-outpost-signature",
+		Algorithm: "hmac-sha1",
+		Encoding:  "base64",
+	},
+	"stripe": {
+		Scheme: scaffold.SchemeStripe,
+		Header: "Stripe-Signature",
+	},
+	"standard": {
+		Scheme:          scaffold.SchemeStandard,
+		Header:          "webhook-signature",
+		IDHeader:        "webhook-id",
+		TimestampHeader: "webhook-timestamp",
+	},
+}
+
+func options(language string, signature scaffold.Signature) scaffold.Options {
+	return scaffold.Options{
+		Language:      language,
+		Topics:        []string{"user.created", "user.deleted", "user_deleted", "*"},
+		Signature:     signature,
+		EventIDHeader: "x-outpost-event-id",
+		TopicHeader:   "x-outpost-topic",
+	}
+}
+
+func fileContents(t *testing.T, files []scaffold.File) map[string]string {
+	t.Helper()
+	contents := make(map[string]string, len(files))
+	for _, file := range files {
+		contents[file.Path] = string(file.Content)
+	}
+	return contents
+}
+
+func TestGenerate_Go(t *testing.T) {
+	t.Parallel()
+
+	for name, signature := range signatures {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			files, err := scaffold.Generate(options(scaffold.LanguageGo, signature))
+			require.NoError(t, err)
+
+			contents := fileContents(t, files)
+			require.Contains(t, contents, "go.mod")
+			require.Contains(t, contents, "README.md")
+			main := contents["main.go"]
+			assert.Contains(t, main, `"user.created": handleUserCreated`)
+			assert.Contains(t, main, `"user_deleted": handleUserDeleted2`)
+			assert.NotContains(t, main, `"*"`)
+			assert.Contains(t, main, signature.Header)
+
+			// The generated consumer must compile
+			fset := token.NewFileSet()
+			file, err := parser.ParseFile(fset, "main.go", main, 0)
+			require.NoError(t, err)
+			conf := types.Config{Importer: importer.ForCompiler(fset, "source", nil)}
+			_, err = conf.Check("main", fset, []*ast.File{file}, nil)
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestGenerate_Node(t *testing.T) {
+	t.Parallel()
+
+	for name, signature := range signatures {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			files, err := scaffold.Generate(options(scaffold.LanguageNode, signature))
+			require.NoError(t, err)
+
+			contents := fileContents(t, files)
+			require.Contains(t, contents, "package.json")
+			server := contents["server.js"]
+			assert.Contains(t, server, `"user.created": handleUserCreated,`)
+			assert.Contains(t, server, `async function handleUserDeleted2(event)`)
+			// Node lowercases incoming header names
+			assert.Contains(t, server, `req.headers["x-outpost-topic"]`)
+			assert.Contains(t, server, strings.ToLower(signature.Header))
+		})
+	}
+}
+
+func TestGenerate_Python(t *testing.T) {
+	t.Parallel()
+
+	for name, signature := range signatures {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			files, err := scaffold.Generate(options(scaffold.LanguagePython, signature))
+			require.NoError(t, err)
+
+			app := fileContents(t, files)["app.py"]
+			assert.Contains(t, app, `"user.created": handle_user_created,`)
+			assert.Contains(t, app, `def handle_user_deleted_2(event):`)
+			assert.Contains(t, app, signature.Header)
+		})
+	}
+}
+
+func TestGenerate_Invalid(t *testing.T) {
+	t.Parallel()
+
+	_, err := scaffold.Generate(options("ruby", signatures["default"]))
+	assert.ErrorIs(t, err, scaffold.ErrUnsupportedLanguage)
+
+	opts := options(scaffold.LanguageGo, signatures["default"])
+	opts.TopicHeader = ""
+	_, err = scaffold.Generate(opts)
+	assert.ErrorIs(t, err, scaffold.ErrNoTopicHeader)
+
+	opts = options(scaffold.LanguageGo, signatures["default"])
+	opts.Signature.Encoding = "base32"
+	_, err = scaffold.Generate(opts)
+	assert.ErrorIs(t, err, scaffold.ErrUnsupportedSignature)
+}
+
+func TestNewDefaultSignature(t *testing.T) {
+	t.Parallel()
+
+	signature, err := scaffold.NewDefaultSignature("x-outpost-signature", "{{.Body}}", `v0={{.Signatures | join ","}}`, "hmac-sha256", "hex")
+	require.NoError(t, err)
+	assert.Equal(t, scaffold.Signature{
+		Scheme:    scaffold.SchemeDefault,
+		Header:    "x-outpost-signature",
+		Prefix:    "v0=",
+		Algorithm: "hmac-sha256",
+		Encoding:  "hex",
+	}, signature)
+
+	for _, tc := range []struct {
+		name, header, content, headerTemplate string
+	}{
+		{"disabled header", "", "{{.Body}}", `v0={{.Signatures | join ","}}`},
+		{"timestamped content", "x-outpost-signature", "{{.Timestamp.Unix}}.{{.Body}}", `v0={{.Signatures | join ","}}`},
+		{"timestamped header", "x-outpost-signature", "{{.Body}}", `t={{.Timestamp.Unix}},v0={{.Signatures | join ","}}`},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := scaffold.NewDefaultSignature(tc.header, tc.content, tc.headerTemplate, "hmac-sha256", "hex")
+			assert.ErrorIs(t, err, scaffold.ErrUnsupportedSignature)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/hookdeck/outpost/internal/clock"
 	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/rsmq"
 	"go.uber.org/zap"
@@ -45,6 +46,7 @@ type config struct {
 	maxReceiveCount      uint64
 	maxExecBackoff       time.Duration
 	logger               *logging.Logger
+	clock                clock.Clock
 }
 
 type Option func(*config)
@@ -79,6 +81,14 @@ func WithMaxExecBackoff(d time.Duration) Option {
 	}
 }
 
+// WithClock sets the clock the monitor waits on between polls and after
+// receive errors.
+func WithClock(clk clock.Clock) Option {
+	return func(c *config) {
+		c.clock = clk
+	}
+}
+
 func WithLogger(logger *logging.Logger) Option {
 	return func(c *config) {
 		c.logger = logger
@@ -112,6 +122,7 @@ func New(name string, rsmqClient rsmq.Client, exec func(context.Context, string)
 		maxConsecutiveErrors: 10,
 		maxErrorBackoff:      15 * time.Second,
 		maxExecBackoff:       15 * time.Minute,
+		clock:                clock.New(),
 	}
 	for _, opt := range opts {
 		opt(config)
@@ -184,13 +195,17 @@ func (s *schedulerImpl) Monitor(ctx context.Context) error {
 				select {
 				case <-ctx.Done():
 					return nil
-				case <-time.After(backoff):
+				case <-s.config.clock.After(backoff):
 				}
 				continue
 			}
 			consecutiveErrors = 0
 			if msg == nil {
-				time.Sleep(s.config.pollBackoff)
+				select {
+				case <-ctx.Done():
+					return nil
+				case <-s.config.clock.After(s.config.pollBackoff):
+				}
 				continue
 			}
 			if s.config.maxReceiveCount > 0 && msg.Rc > s.config.maxReceiveCount {
@@ -7,6 +7,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/hookdeck/outpost/internal/clock"
 	"github.com/hookdeck/outpost/internal/idgen"
 	iredis "github.com/hookdeck/outpost/internal/redis"
 	"github.com/hookdeck/outpost/internal/rsmq"
@@ -96,14 +97,19 @@ func TestScheduler_Basic(t *testing.T) {
 	rsmqClient := createRSMQClient(t, redisConfig)
 	logger := testutil.CreateTestLogger(t)
 
-	msgs := []string{}
+	// Message visibility follows a fake clock, so delays elapse by advancing
+	// it rather than sleeping.
+	fakeClock := clock.NewFake(time.Now())
+	rsmqClient.SetClock(fakeClock)
+
+	msgs := make(chan string, 3)
 	exec := func(_ context.Context, id string) error {
-		msgs = append(msgs, id)
+		msgs <- id
 		return nil
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
-	s := scheduler.New("scheduler", rsmqClient, exec, scheduler.WithLogger(logger))
+	s := scheduler.New("scheduler", rsmqClient, exec, scheduler.WithLogger(logger), scheduler.WithPollBackoff(10*time.Millisecond))
 	require.NoError(t, s.Init(ctx))
 	defer func() { cancel(); s.Shutdown() }()
 	go s.Monitor(ctx)
@@ -114,22 +120,39 @@ func TestScheduler_Basic(t *testing.T) {
 		idgen.String(),
 		idgen.String(),
 	}
-	s.Schedule(ctx, ids[0], 1*time.Second)
-	s.Schedule(ctx, ids[1], 2*time.Second)
-	s.Schedule(ctx, ids[2], 3*time.Second)
+	require.NoError(t, s.Schedule(ctx, ids[0], 1*time.Minute))
+	require.NoError(t, s.Schedule(ctx, ids[1], 1*time.Hour))
+	require.NoError(t, s.Schedule(ctx, ids[2], 24*time.Hour))
 
 	// Assert
-	time.Sleep(time.Second / 2)
-	require.Len(t, msgs, 0)
-	time.Sleep(time.Second)
-	require.Len(t, msgs, 1)
-	require.Equal(t, ids[0], msgs[0])
-	time.Sleep(time.Second)
-	require.Len(t, msgs, 2)
-	require.Equal(t, ids[1], msgs[1])
-	time.Sleep(time.Second)
-	require.Len(t, msgs, 3)
-	require.Equal(t, ids[2], msgs[2])
+	receive := func() string {
+		t.Helper()
+		select {
+		case id := <-msgs:
+			return id
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out waiting for scheduled task")
+			return ""
+		}
+	}
+	assertNone := func() {
+		t.Helper()
+		select {
+		case id := <-msgs:
+			t.Fatalf("unexpected task %s executed early", id)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+
+	assertNone()
+	fakeClock.Advance(time.Minute)
+	require.Equal(t, ids[0], receive())
+	assertNone()
+	fakeClock.Advance(time.Hour)
+	require.Equal(t, ids[1], receive())
+	assertNone()
+	fakeClock.Advance(24 * time.Hour)
+	require.Equal(t, ids[2], receive())
 }
 
 func TestScheduler_ParallelMonitor(t *testing.T) {
@@ -517,3 +540,44 @@ func TestScheduler_MonitorCancelsDuringBackoff(t *testing.T) {
 	err := s.Monitor(ctx)
 	require.NoError(t, err, "Monitor should return nil on context cancellation")
 }
+
+func TestScheduler_MonitorBacksOffOnClock(t *testing.T) {
+	t.Parallel()
+
+	logger := testutil.CreateTestLogger(t)
+	fakeClock := clock.NewFake(time.Now())
+
+	mock := &alwaysFailRSMQ{
+		err: errors.New("connection reset"),
+	}
+
+	exec := func(_ context.Context, msg string) error { return nil }
+
+	s := scheduler.New("scheduler", mock, exec,
+		scheduler.WithPollBackoff(5*time.Second),
+		scheduler.WithMaxConsecutiveErrors(3),
+		scheduler.WithLogger(logger),
+		scheduler.WithClock(fakeClock),
+	)
+
+	errc := make(chan error, 1)
+	go func() { errc <- s.Monitor(context.Background()) }()
+
+	// First backoff is the poll backoff, the second doubles it.
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(4 * time.Second)
+	require.Equal(t, 1, fakeClock.Waiters(), "backoff should not elapse early")
+	fakeClock.Advance(time.Second)
+
+	fakeClock.BlockUntil(1)
+	fakeClock.Advance(5 * time.Second)
+	require.Equal(t, 1, fakeClock.Waiters(), "second backoff should double")
+	fakeClock.Advance(5 * time.Second)
+
+	select {
+	case err := <-errc:
+		require.ErrorContains(t, err, "max consecutive errors reached")
+	case <-time.After(5 * time.Second):
+		t.Fatal("monitor did not give up after exhausting retries")
+	}
+}
@@ -0,0 +1,261 @@
+// Package seed creates test tenants and destinations through the Outpost
+// API. It backs both the `outpost seed` subcommand and cmd/seed.
+package seed
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/brianvoe/gofakeit/v6"
+)
+
+// Options configures a seed run.
+type Options struct {
+	ServerURL       string // Outpost API URL, e.g. http://localhost:3333/api/v1
+	APIKey          string
+	Tenants         int
+	MinDestinations int
+	MaxDestinations int
+	Concurrency     int
+	Verbose         bool
+	SkipConfirm     bool // Skip the confirmation prompt
+}
+
+// DefaultOptions returns the options used when no flags are given.
+func DefaultOptions() Options {
+	return Options{
+		ServerURL:       "http://localhost:3333/api/v1",
+		APIKey:          "apikey",
+		Tenants:         100,
+		MinDestinations: 1,
+		MaxDestinations: 10,
+		Concurrency:     10,
+	}
+}
+
+type seedStats struct {
+	mu                  sync.Mutex
+	tenantsCreated      int
+	destinationsCreated int
+	errors              []string
+}
+
+func (s *seedStats) addTenant() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tenantsCreated++
+}
+
+func (s *seedStats) addDestination() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.destinationsCreated++
+}
+
+func (s *seedStats) addError(err string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.errors = append(s.errors, err)
+}
+
+type client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+func (c *client) do(ctx context.Context, method, path string, body any) error {
+	var reader io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.apiKey)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		buf, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("status %d: %s", resp.StatusCode, strings.TrimSpace(string(buf)))
+	}
+	io.Copy(io.Discard, resp.Body)
+	return nil
+}
+
+// Run seeds the Outpost server described by opts, asking for confirmation
+// on stdin unless opts.SkipConfirm is set.
+func Run(ctx context.Context, opts Options) error {
+	if opts.MinDestinations < 0 || opts.MaxDestinations < opts.MinDestinations {
+		return errors.New("max-destinations must be greater than or equal to min-destinations")
+	}
+	if opts.Concurrency < 1 {
+		return errors.New("concurrency must be at least 1")
+	}
+
+	gofakeit.Seed(time.Now().UnixNano())
+
+	avgDestinations := (opts.MinDestinations + opts.MaxDestinations) / 2
+	estimatedTotal := opts.Tenants * avgDestinations
+
+	fmt.Printf("=== Outpost Data Seeder Configuration ===\n")
+	fmt.Printf("Server: %s\n", opts.ServerURL)
+	fmt.Printf("Tenants to create: %d\n", opts.Tenants)
+	fmt.Printf("Destinations per tenant: %d-%d (avg: %d)\n", opts.MinDestinations, opts.MaxDestinations, avgDestinations)
+	fmt.Printf("Estimated total destinations: ~%d\n", estimatedTotal)
+	fmt.Printf("Concurrency: %d workers\n", opts.Concurrency)
+	fmt.Printf("\n")
+
+	if !opts.SkipConfirm {
+		fmt.Printf("This will create approximately %d tenants and %d destinations.\n", opts.Tenants, estimatedTotal)
+		fmt.Printf("Continue? (y/N): ")
+
+		var response string
+		fmt.Scanln(&response)
+
+		if response != "y" && response != "Y" && response != "yes" && response != "Yes" {
+			fmt.Println("Operation cancelled.")
+			return nil
+		}
+		fmt.Println()
+	}
+
+	c := &client{
+		baseURL: strings.TrimRight(opts.ServerURL, "/"),
+		apiKey:  opts.APIKey,
+		http:    &http.Client{Timeout: 30 * time.Second},
+	}
+
+	fmt.Printf("Checking server health...\n")
+	healthURL := strings.TrimSuffix(opts.ServerURL, "/api/v1") + "/healthz"
+	healthReq, err := http.NewRequestWithContext(ctx, http.MethodGet, healthURL, nil)
+	if err != nil {
+		return err
+	}
+	healthResp, err := c.http.Do(healthReq)
+	if err != nil {
+		return fmt.Errorf("health check failed, please ensure the Outpost server is running at %s: %w", opts.ServerURL, err)
+	}
+	defer healthResp.Body.Close()
+	if healthResp.StatusCode != http.StatusOK {
+		return fmt.Errorf("health check failed: status %d", healthResp.StatusCode)
+	}
+	fmt.Printf("✅ Server is healthy\n")
+	fmt.Println()
+
+	stats := &seedStats{}
+
+	fmt.Printf("Starting seed process...\n")
+
+	tenantChan := make(chan int, opts.Tenants)
+	var wg sync.WaitGroup
+
+	for i := 0; i < opts.Concurrency; i++ {
+		wg.Add(1)
+		go worker(ctx, c, opts, tenantChan, stats, &wg)
+	}
+
+	for i := 0; i < opts.Tenants; i++ {
+		tenantChan <- i
+	}
+	close(tenantChan)
+
+	wg.Wait()
+
+	fmt.Printf("\n=== Seeding Complete ===\n")
+	fmt.Printf("Tenants created: %d\n", stats.tenantsCreated)
+	fmt.Printf("Destinations created: %d\n", stats.destinationsCreated)
+	if len(stats.errors) > 0 {
+		fmt.Printf("Errors encountered: %d\n", len(stats.errors))
+		if opts.Verbose {
+			fmt.Println("\nErrors:")
+			for _, err := range stats.errors {
+				fmt.Printf("  - %s\n", err)
+			}
+		}
+	}
+	return nil
+}
+
+func worker(ctx context.Context, c *client, opts Options, tenantChan <-chan int, stats *seedStats, wg *sync.WaitGroup) {
+	defer wg.Done()
+
+	for i := range tenantChan {
+		tenantID := fmt.Sprintf("tenant_%d", i+1)
+
+		if opts.Verbose {
+			fmt.Printf("Creating tenant: %s\n", tenantID)
+		}
+
+		if err := c.do(ctx, http.MethodPut, "/tenants/"+tenantID, nil); err != nil {
+			stats.addError(fmt.Sprintf("Failed to create tenant %s: %v", tenantID, err))
+			continue
+		}
+
+		stats.addTenant()
+
+		numDests := rand.Intn(opts.MaxDestinations-opts.MinDestinations+1) + opts.MinDestinations
+		for j := 0; j < numDests; j++ {
+			if err := createDestination(ctx, c, tenantID); err != nil {
+				stats.addError(fmt.Sprintf("Failed to create destination for tenant %s: %v", tenantID, err))
+			} else {
+				stats.addDestination()
+			}
+		}
+
+		if opts.Verbose {
+			fmt.Printf("  Created %d destinations for tenant %s\n", numDests, tenantID)
+		}
+	}
+}
+
+func createDestination(ctx context.Context, c *client, tenantID string) error {
+	body := map[string]any{
+		"type":   "webhook",
+		"topics": generateTopics(),
+		"config": map[string]string{
+			"url": fmt.Sprintf("https://mock.hookdeck.com/%s", gofakeit.UUID()),
+		},
+	}
+	return c.do(ctx, http.MethodPost, "/tenants/"+tenantID+"/destinations", body)
+}
+
+func generateTopics() any {
+	if rand.Float32() < 0.3 {
+		return "*"
+	}
+
+	allowedTopics := []string{
+		"user.created",
+		"user.updated",
+		"user.deleted",
+	}
+
+	numTopics := rand.Intn(len(allowedTopics)) + 1
+	perm := rand.Perm(len(allowedTopics))
+	selected := make([]string, 0, numTopics)
+	for i := 0; i < numTopics; i++ {
+		selected = append(selected, allowedTopics[perm[i]])
+	}
+	return selected
+}
@@ -4,26 +4,54 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/hookdeck/outpost/internal/alert"
+	"github.com/hookdeck/outpost/internal/alertchannel"
 	apirouter "github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/bulkretry"
+	"github.com/hookdeck/outpost/internal/clock"
 	"github.com/hookdeck/outpost/internal/config"
+	"github.com/hookdeck/outpost/internal/deliveryack"
+	"github.com/hookdeck/outpost/internal/deliveryhold"
+	"github.com/hookdeck/outpost/internal/deliveryhook"
+	"github.com/hookdeck/outpost/internal/deliveryjournal"
 	"github.com/hookdeck/outpost/internal/deliverymq"
+	"github.com/hookdeck/outpost/internal/deliverywarmup"
+	"github.com/hookdeck/outpost/internal/desthealth"
 	"github.com/hookdeck/outpost/internal/destregistry"
 	destregistrydefault "github.com/hookdeck/outpost/internal/destregistry/providers"
+	"github.com/hookdeck/outpost/internal/eventrate"
 	"github.com/hookdeck/outpost/internal/eventtracer"
+	"github.com/hookdeck/outpost/internal/grpcapi"
 	"github.com/hookdeck/outpost/internal/idempotence"
+	"github.com/hookdeck/outpost/internal/legalhold"
+	"github.com/hookdeck/outpost/internal/lifecycle"
+	"github.com/hookdeck/outpost/internal/logarchive"
 	"github.com/hookdeck/outpost/internal/logging"
 	"github.com/hookdeck/outpost/internal/logmq"
 	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/models"
 	"github.com/hookdeck/outpost/internal/opevents"
+	"github.com/hookdeck/outpost/internal/payloadoffload"
+	"github.com/hookdeck/outpost/internal/probation"
+	"github.com/hookdeck/outpost/internal/publishkey"
 	"github.com/hookdeck/outpost/internal/publishmq"
+	"github.com/hookdeck/outpost/internal/publishrate"
+	"github.com/hookdeck/outpost/internal/receipts"
+	"github.com/hookdeck/outpost/internal/recorder"
 	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/redismemory"
+	"github.com/hookdeck/outpost/internal/redisstandby"
+	"github.com/hookdeck/outpost/internal/replication"
 	"github.com/hookdeck/outpost/internal/scheduler"
 	"github.com/hookdeck/outpost/internal/telemetry"
 	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/topicstats"
+	"github.com/hookdeck/outpost/internal/topicstore"
 	"github.com/hookdeck/outpost/internal/worker"
 	"go.uber.org/zap"
 )
@@ -35,6 +63,29 @@ type ServiceBuilder struct {
 	logger     *logging.Logger
 	telemetry  telemetry.Telemetry
 	supervisor *worker.WorkerSupervisor
+	// clock overrides the time source of retry scheduling and log retention.
+	// nil keeps the defaults: Redis server time and the wall clock.
+	clock clock.Clock
+
+	// Dependencies supplied by an embedding program. Services use them
+	// instead of creating their own, and never close them.
+	redisClient redis.Client
+	logStore    logstore.LogStore
+	tenantStore tenantstore.TenantStore
+
+	// withoutHTTPServer skips the HTTP server worker; the embedding program
+	// serves Handler() itself.
+	withoutHTTPServer bool
+	handler           http.Handler
+
+	// API extensions registered by an embedding program.
+	apiMiddlewares []gin.HandlerFunc
+	apiRoutes      []apirouter.RouteDefinition
+
+	// Delivery hooks registered by an embedding program, run before the
+	// ones configured with the delivery hooks config.
+	preDeliveryHooks  []deliveryhook.PreDeliveryHook
+	postDeliveryHooks []deliveryhook.PostDeliveryHook
 
 	// Track service instances for cleanup
 	services []*serviceInstance
@@ -54,14 +105,87 @@ type serviceInstance struct {
 	deliveryMQ     *deliverymq.DeliveryMQ
 	logMQ          *logmq.LogMQ
 	retryScheduler scheduler.Scheduler
+	publishMQ      bool              // consumes the optional publish queue
+	topics         *topicstore.Store // runtime topics and topic lifecycle, see topicStore
 
 	// HTTP server and router
 	router http.Handler
 }
 
+// ServiceBuilderOption configures a ServiceBuilder.
+type ServiceBuilderOption func(*ServiceBuilder)
+
+// WithClock sets the clock for retry scheduling and log retention.
+func WithClock(c clock.Clock) ServiceBuilderOption {
+	return func(b *ServiceBuilder) {
+		b.clock = c
+	}
+}
+
+// WithRedisClient makes services share the given Redis client instead of
+// connecting with the Redis config.
+func WithRedisClient(client redis.Client) ServiceBuilderOption {
+	return func(b *ServiceBuilder) {
+		b.redisClient = client
+	}
+}
+
+// WithLogStore makes services use the given log store instead of the one
+// selected by the log store config.
+func WithLogStore(logStore logstore.LogStore) ServiceBuilderOption {
+	return func(b *ServiceBuilder) {
+		b.logStore = logStore
+	}
+}
+
+// WithTenantStore makes services use the given tenant store, which must
+// already be initialized, instead of the Redis-backed one.
+func WithTenantStore(tenantStore tenantstore.TenantStore) ServiceBuilderOption {
+	return func(b *ServiceBuilder) {
+		b.tenantStore = tenantStore
+	}
+}
+
+// WithoutHTTPServer skips the HTTP server worker, for programs that mount
+// Handler() on their own server.
+func WithoutHTTPServer() ServiceBuilderOption {
+	return func(b *ServiceBuilder) {
+		b.withoutHTTPServer = true
+	}
+}
+
+// WithAPIMiddlewares adds middlewares that run on every API request before
+// authentication.
+func WithAPIMiddlewares(middlewares ...gin.HandlerFunc) ServiceBuilderOption {
+	return func(b *ServiceBuilder) {
+		b.apiMiddlewares = append(b.apiMiddlewares, middlewares...)
+	}
+}
+
+// WithAPIRoutes adds endpoints to the API, under /api/v1.
+func WithAPIRoutes(routes ...apirouter.RouteDefinition) ServiceBuilderOption {
+	return func(b *ServiceBuilder) {
+		b.apiRoutes = append(b.apiRoutes, routes...)
+	}
+}
+
+// WithPreDeliveryHooks adds hooks that run before each delivery attempt.
+func WithPreDeliveryHooks(hooks ...deliveryhook.PreDeliveryHook) ServiceBuilderOption {
+	return func(b *ServiceBuilder) {
+		b.preDeliveryHooks = append(b.preDeliveryHooks, hooks...)
+	}
+}
+
+// WithPostDeliveryHooks adds hooks that run after each delivery attempt.
+func WithPostDeliveryHooks(hooks ...deliveryhook.PostDeliveryHook) ServiceBuilderOption {
+	return func(b *ServiceBuilder) {
+		b.postDeliveryHooks = append(b.postDeliveryHooks, hooks...)
+	}
+}
+
 // NewServiceBuilder creates a new ServiceBuilder.
-func NewServiceBuilder(ctx context.Context, cfg *config.Config, logger *logging.Logger, telemetry telemetry.Telemetry) *ServiceBuilder {
-	return &ServiceBuilder{
+func NewServiceBuilder(ctx context.Context, cfg *config.Config, logger *logging.Logger, telemetry telemetry.Telemetry, opts ...ServiceBuilderOption) *ServiceBuilder {
+	b := &ServiceBuilder{
 		ctx:        ctx,
 		cfg:        cfg,
 		logger:     logger,
@@ -69,6 +193,10 @@ func NewServiceBuilder(ctx context.Context, cfg *config.Config, logger *logging.
 		supervisor: worker.NewWorkerSupervisor(logger),
 		services:   []*serviceInstance{},
 	}
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b
 }
 
 // BuildWorkers builds workers based on the configured service type and returns the supervisor.
@@ -76,9 +204,27 @@ func (b *ServiceBuilder) BuildWorkers() (*worker.WorkerSupervisor, error) {
 	serviceType := b.cfg.MustGetService()
 	b.logger.Debug("building workers for service type", zap.String("service_type", serviceType.String()))
 
+	// Every service checks Redis failover readiness, as each reports it in
+	// its own health check.
+	var healthReporters []HealthReporter
+	if b.cfg.RedisStandby.Enabled {
+		standbyWorker := NewRedisStandbyWorker(
+			redisstandby.New(
+				b.cfg.Redis.ToConfig(),
+				b.cfg.RedisStandby.ToConfig(&b.cfg.Redis),
+				b.cfg.DeploymentID,
+				b.cfg.RedisStandby.Options(),
+			),
+			b.cfg.RedisStandby.Interval(),
+			b.logger,
+		)
+		b.supervisor.Register(standbyWorker)
+		healthReporters = append(healthReporters, standbyWorker)
+	}
+
 	// Create base router with health check that all services will extend
 	b.logger.Debug("creating base router with health check")
-	baseRouter := NewBaseRouter(b.supervisor, b.cfg.GinMode)
+	baseRouter := NewBaseRouter(b.supervisor, b.cfg.GinMode, healthReporters...)
 
 	if serviceType == config.ServiceTypeAPI || serviceType == config.ServiceTypeAll {
 		if err := b.BuildAPIWorkers(baseRouter); err != nil {
@@ -98,6 +244,21 @@ func (b *ServiceBuilder) BuildWorkers() (*worker.WorkerSupervisor, error) {
 			return nil, err
 		}
 	}
+	if serviceType == config.ServiceTypeArchiver || (serviceType == config.ServiceTypeAll && b.cfg.LogArchive.Enabled) {
+		if err := b.BuildArchiverWorker(); err != nil {
+			b.logger.Error("failed to build archiver worker", zap.Error(err))
+			return nil, err
+		}
+	}
+
+	// Every service reports what this process runs, for operators of
+	// split-service deployments.
+	b.registerTopologyRoute(baseRouter)
+
+	b.handler = baseRouter
+	if b.withoutHTTPServer {
+		return b.supervisor, nil
+	}
 
 	// Create HTTP server with the base router
 	if err := b.createHTTPServer(baseRouter); err != nil {
@@ -108,6 +269,26 @@ func (b *ServiceBuilder) BuildWorkers() (*worker.WorkerSupervisor, error) {
 	return b.supervisor, nil
 }
 
+// Handler returns the HTTP handler of the built services: health check, API
+// routes and topology. It is nil until BuildWorkers succeeds.
+func (b *ServiceBuilder) Handler() http.Handler {
+	return b.handler
+}
+
+// newServiceInstance registers a service for cleanup, seeded with the
+// dependencies supplied through builder options.
+func (b *ServiceBuilder) newServiceInstance(name string) *serviceInstance {
+	svc := &serviceInstance{
+		name:         name,
+		cleanupFuncs: []func(context.Context, *logging.LoggerWithCtx){},
+		redisClient:  b.redisClient,
+		logStore:     b.logStore,
+		tenantStore:  b.tenantStore,
+	}
+	b.services = append(b.services, svc)
+	return svc
+}
+
 // createHTTPServer creates and registers the HTTP server worker with the given router
 func (b *ServiceBuilder) createHTTPServer(router http.Handler) error {
 	// Create HTTP server
@@ -147,24 +328,24 @@ func (b *ServiceBuilder) Cleanup(ctx context.Context) {
 // This sets up the infrastructure, creates the API router, and registers workers:
 // 1. Retry scheduler
 // 2. PublishMQ consumer (optional)
+// 3. Credential re-encryption (optional)
+// 4. Delivery receipts (optional)
+// 5. gRPC API server (optional)
 // The baseRouter parameter is extended with API routes (apirouter already has health check)
 func (b *ServiceBuilder) BuildAPIWorkers(baseRouter *gin.Engine) error {
 	b.logger.Debug("building API service workers")
 
-	svc := &serviceInstance{
-		name:         "api",
-		cleanupFuncs: []func(context.Context, *logging.LoggerWithCtx){},
-	}
-	b.services = append(b.services, svc)
+	svc := b.newServiceInstance("api")
 
-	// Initialize common infrastructure
-	if err := svc.initDestRegistry(b.cfg, b.logger); err != nil {
+	// Initialize common infrastructure. Redis comes first, as the destination
+	// registry reads the topic lifecycle from it.
+	if err := svc.initRedis(b.ctx, b.cfg, b.logger); err != nil {
 		return err
 	}
-	if err := svc.initDeliveryMQ(b.ctx, b.cfg, b.logger); err != nil {
+	if err := svc.initDestRegistry(b.cfg, b.logger, b.clock); err != nil {
 		return err
 	}
-	if err := svc.initRedis(b.ctx, b.cfg, b.logger); err != nil {
+	if err := svc.initDeliveryMQ(b.ctx, b.cfg, b.logger); err != nil {
 		return err
 	}
 	if err := svc.initLogStore(b.ctx, b.cfg, b.logger); err != nil {
@@ -178,7 +359,7 @@ func (b *ServiceBuilder) BuildAPIWorkers(baseRouter *gin.Engine) error {
 	}
 
 	// Initialize retry scheduler
-	if err := svc.initRetryScheduler(b.ctx, b.cfg, b.logger); err != nil {
+	if err := svc.initRetryScheduler(b.ctx, b.cfg, b.logger, b.clock); err != nil {
 		return err
 	}
 
@@ -189,13 +370,40 @@ func (b *ServiceBuilder) BuildAPIWorkers(baseRouter *gin.Engine) error {
 		idempotence.WithSuccessfulTTL(time.Duration(b.cfg.PublishIdempotencyKeyTTL)*time.Second),
 		idempotence.WithDeploymentID(b.cfg.DeploymentID),
 	)
+	// Topics created through the API are listed after the configured ones and
+	// picked up by publish and destination validation, as are the lifecycle
+	// states set through the API.
+	topics := svc.topicStore(b.cfg)
+	eventHandlerOpts := []publishmq.EventHandlerOption{
+		publishmq.WithTopicLister(topics),
+		publishmq.WithTopicLifecycle(topics),
+	}
+	lifecycleNotifier, err := b.newLifecycleNotifier(svc)
+	if err != nil {
+		return err
+	}
+	if lifecycleNotifier != nil {
+		eventHandlerOpts = append(eventHandlerOpts, publishmq.WithLifecycleNotifier(lifecycleNotifier))
+	}
+	if b.cfg.TopicNamespace != "" {
+		eventHandlerOpts = append(eventHandlerOpts, publishmq.WithTopicNamespace(b.cfg.GetTopicNamespace()))
+	}
+	if publishHook := b.cfg.PublishValidation.ToConfig(); publishHook.Enabled() {
+		eventHandlerOpts = append(eventHandlerOpts, publishmq.WithPublishHook(publishHook))
+	}
+	topicStats := b.newTopicStats(svc)
+	if topicStats != nil {
+		eventHandlerOpts = append(eventHandlerOpts, publishmq.WithTopicStats(topicStats))
+	}
 	eventHandler := publishmq.NewEventHandler(
 		b.logger,
 		svc.deliveryMQ,
 		svc.tenantStore,
 		svc.eventTracer,
 		b.cfg.Topics,
+		b.cfg.TopicsRetired,
 		publishIdempotence,
+		eventHandlerOpts...,
 	)
 
 	// Create operator events emitter for subscription updates
@@ -206,27 +414,110 @@ func (b *ServiceBuilder) BuildAPIWorkers(baseRouter *gin.Engine) error {
 	}
 	subscriptionEmitter := opevents.NewEmitter(oeSink, b.cfg.DeploymentID, oeCfg.Topics, b.logger)
 
+	// Payloads are only offloaded, and so only served, when a base URL for
+	// their fetch URLs is configured.
+	var payloads payloadoffload.Store
+	if b.cfg.PayloadOffloadBaseURL != "" {
+		payloads = payloadoffload.New(svc.redisClient, payloadoffload.WithDeploymentID(b.cfg.DeploymentID))
+	}
+
+	bulkRetryOpts := []bulkretry.RunnerOption{bulkretry.WithTopicNamespace(b.cfg.GetTopicNamespace())}
+	if b.clock != nil {
+		bulkRetryOpts = append(bulkRetryOpts, bulkretry.WithClock(b.clock))
+	}
+	bulkRetries := bulkretry.NewRunner(
+		b.logger,
+		bulkretry.NewStore(svc.redisClient, bulkretry.WithDeploymentID(b.cfg.DeploymentID)),
+		svc.logStore,
+		svc.tenantStore,
+		svc.deliveryMQ,
+		bulkRetryOpts...,
+	)
+
+	// Events are only counted when a per-tenant event quota is configured.
+	var eventRates eventrate.Counter
+	if b.cfg.MaxEventsPerMinutePerTenant > 0 {
+		eventRateOpts := []eventrate.Option{eventrate.WithDeploymentID(b.cfg.DeploymentID)}
+		if b.clock != nil {
+			eventRateOpts = append(eventRateOpts, eventrate.WithClock(b.clock))
+		}
+		eventRates = eventrate.New(svc.redisClient, eventRateOpts...)
+	}
+
+	// The limiter is always built so tenant overrides apply without a default
+	// limit. Tenants without a limit skip Redis.
+	publishRateOpts := []publishrate.Option{publishrate.WithDeploymentID(b.cfg.DeploymentID)}
+	if b.clock != nil {
+		publishRateOpts = append(publishRateOpts, publishrate.WithClock(b.clock))
+	}
+	publishRates := publishrate.New(svc.redisClient, svc.tenantStore, models.PublishRateLimit{
+		PerSecond: b.cfg.PublishRateLimitPerSecond,
+		Burst:     b.cfg.PublishRateLimitBurst,
+	}, publishRateOpts...)
+
+	// Idempotency-Key is ignored when the window is 0.
+	var publishKeys publishkey.Keys
+	if b.cfg.PublishIdempotencyKeyWindow > 0 {
+		publishKeys = publishkey.New(svc.redisClient,
+			publishkey.WithDeploymentID(b.cfg.DeploymentID),
+			publishkey.WithWindow(time.Duration(b.cfg.PublishIdempotencyKeyWindow)*time.Second))
+	}
+
+	routerDeps := apirouter.RouterDeps{
+		TenantStore:         svc.tenantStore,
+		LogStore:            svc.logStore,
+		Logger:              b.logger,
+		DeliveryPublisher:   svc.deliveryMQ,
+		EventHandler:        eventHandler,
+		Telemetry:           b.telemetry,
+		SubscriptionEmitter: subscriptionEmitter,
+		DeliveryAcks:        deliveryack.New(svc.redisClient, deliveryack.WithDeploymentID(b.cfg.DeploymentID)),
+		RetryCanceler:       svc.retryScheduler,
+		Payloads:            payloads,
+		EventRates:          eventRates,
+		PublishRateLimiter:  publishRates,
+		PublishKeys:         publishKeys,
+		BulkRetries:         bulkRetries,
+		RedisMemory:         redismemory.New(svc.redisClient, b.cfg.DeploymentID),
+		TopicStore:          topics,
+		LegalHolds:          b.newLegalHolds(svc),
+		DestinationHealth:   b.newDestinationHealth(svc),
+		HeldMessages:        b.newDeliveryHold(svc),
+	}
+	if topicStats != nil {
+		routerDeps.TopicStats = topicStats
+	}
+	// Acknowledged deliveries complete here, where the acks are received
+	if lifecycleNotifier != nil {
+		routerDeps.Lifecycle = lifecycleNotifier
+	}
+
+	portalConfig := b.cfg.GetPortalConfig()
+	portalConfig.DynamicConfigs = func(ctx context.Context) map[string]string {
+		return map[string]string{"TOPICS": strings.Join(topics.Topics(ctx), ",")}
+	}
+
 	apiHandler := apirouter.NewRouter(
 		apirouter.RouterConfig{
-			ServiceName:          b.cfg.OpenTelemetry.GetServiceName(),
-			APIKey:               b.cfg.APIKey,
-			JWTSecret:            b.cfg.APIJWTSecret,
-			DeploymentID:         b.cfg.DeploymentID,
-			Topics:               b.cfg.Topics,
-			TopicsAllowWildcards: b.cfg.TopicsAllowWildcards,
-			Registry:             svc.destRegistry,
-			PortalConfig:         b.cfg.GetPortalConfig(),
-			GinMode:              b.cfg.GinMode,
-		},
-		apirouter.RouterDeps{
-			TenantStore:         svc.tenantStore,
-			LogStore:            svc.logStore,
-			Logger:              b.logger,
-			DeliveryPublisher:   svc.deliveryMQ,
-			EventHandler:        eventHandler,
-			Telemetry:           b.telemetry,
-			SubscriptionEmitter: subscriptionEmitter,
+			ServiceName:                 b.cfg.OpenTelemetry.GetServiceName(),
+			APIKey:                      b.cfg.APIKey,
+			JWTSecret:                   b.cfg.APIJWTSecret,
+			DeploymentID:                b.cfg.DeploymentID,
+			Topics:                      b.cfg.Topics,
+			TopicsAllowWildcards:        b.cfg.TopicsAllowWildcards,
+			TopicLifecycle:              b.cfg.TopicLifecycle(),
+			TopicNamespace:              b.cfg.GetTopicNamespace(),
+			Registry:                    svc.destRegistry,
+			PortalConfig:                portalConfig,
+			GinMode:                     b.cfg.GinMode,
+			TrustedProxies:              b.cfg.APITrustedProxies,
+			MaxDestinationsPerTenant:    b.cfg.MaxDestinationsPerTenant,
+			QuotaWarningPercent:         b.cfg.QuotaWarningPercent,
+			MaxEventsPerMinutePerTenant: b.cfg.MaxEventsPerMinutePerTenant,
+			Middlewares:                 b.apiMiddlewares,
+			Routes:                      b.apiRoutes,
 		},
+		routerDeps,
 	)
 
 	// Mount API handler onto base router (everything except /healthz goes to apiHandler)
@@ -238,10 +529,17 @@ func (b *ServiceBuilder) BuildAPIWorkers(baseRouter *gin.Engine) error {
 	retryWorker := NewRetryMQWorker(svc.retryScheduler, b.logger)
 	b.supervisor.Register(retryWorker)
 
+	// Bulk retry jobs run until the service shuts down
+	b.supervisor.Register(NewBulkRetryWorker(bulkRetries, b.logger))
+
 	// Worker 2: PublishMQ Consumer (optional)
 	if b.cfg.PublishMQ.GetQueueConfig() != nil {
 		publishMQ := publishmq.New(publishmq.WithQueue(b.cfg.PublishMQ.GetQueueConfig()))
-		messageHandler := publishmq.NewMessageHandler(eventHandler)
+		messageHandlerOpts := []publishmq.MessageHandlerOption{publishmq.WithRateLimiter(publishRates)}
+		if mapping := b.cfg.PublishMQ.GetEventMapping(); mapping != nil {
+			messageHandlerOpts = append(messageHandlerOpts, publishmq.WithEventMapping(mapping))
+		}
+		messageHandler := publishmq.NewMessageHandler(eventHandler, messageHandlerOpts...)
 		publishMQWorker := NewConsumerWorker(
 			"publishmq-consumer",
 			publishMQ.Subscribe,
@@ -250,6 +548,77 @@ func (b *ServiceBuilder) BuildAPIWorkers(baseRouter *gin.Engine) error {
 			b.logger,
 		)
 		b.supervisor.Register(publishMQWorker)
+		svc.publishMQ = true
+	}
+
+	// Worker 3: Credential re-encryption after an AES secret rotation (optional)
+	if b.cfg.AESEncryptionReEncryptOnStartup {
+		if reEncrypter, ok := svc.tenantStore.(tenantstore.CredentialReEncrypter); ok {
+			b.supervisor.Register(NewReEncryptWorker(reEncrypter, b.logger))
+		}
+	}
+
+	// Worker 4: Daily delivery receipts (optional)
+	if b.cfg.Receipts.Enabled {
+		signer, err := b.cfg.Receipts.Signer()
+		if err != nil {
+			return err
+		}
+		generatorOpts := []receipts.GeneratorOption{}
+		if signer != nil {
+			generatorOpts = append(generatorOpts, receipts.WithSigner(signer))
+		}
+		generator := receipts.NewGenerator(
+			svc.logStore,
+			svc.tenantStore,
+			receipts.NewS3Uploader(b.cfg.Receipts.ToConfig()),
+			b.logger,
+			generatorOpts...,
+		)
+		if err := generator.CheckSupported(b.ctx); err != nil {
+			return fmt.Errorf("receipts.enabled: %w", err)
+		}
+		b.supervisor.Register(NewReceiptsWorker(generator, svc.redisClient, b.cfg.DeploymentID, b.logger))
+	}
+
+	// Worker 5: gRPC API server (optional), served by the same services as the
+	// REST API
+	if b.cfg.GRPCPort > 0 {
+		grpcServer, err := grpcapi.NewGRPCServer(
+			grpcapi.Config{
+				APIKey:                      b.cfg.APIKey,
+				JWTSecret:                   b.cfg.APIJWTSecret,
+				TopicsAllowWildcards:        b.cfg.TopicsAllowWildcards,
+				TopicLifecycle:              b.cfg.TopicLifecycle(),
+				MaxEventsPerMinutePerTenant: b.cfg.MaxEventsPerMinutePerTenant,
+			},
+			grpcapi.Deps{
+				Logger:              b.logger,
+				Telemetry:           b.telemetry,
+				TenantStore:         svc.tenantStore,
+				Registry:            svc.destRegistry,
+				EventHandler:        eventHandler,
+				Topics:              topics,
+				TopicLifecycle:      topics,
+				EventRates:          eventRates,
+				PublishRateLimiter:  publishRates,
+				PublishKeys:         publishKeys,
+				SubscriptionEmitter: subscriptionEmitter,
+			},
+		)
+		if err != nil {
+			return err
+		}
+		b.supervisor.Register(NewGRPCServerWorker(grpcServer, fmt.Sprintf(":%d", b.cfg.GRPCPort), b.logger))
+	}
+
+	// Worker 6: Replication from a primary deployment (optional)
+	if b.cfg.Replication.Enabled {
+		replicator, err := b.newReplicator(svc)
+		if err != nil {
+			return err
+		}
+		b.supervisor.Register(NewReplicationWorker(replicator, b.cfg.Replication.PollInterval(), b.logger))
 	}
 
 	b.logger.Info("API service workers built successfully")
@@ -260,11 +629,7 @@ func (b *ServiceBuilder) BuildAPIWorkers(baseRouter *gin.Engine) error {
 func (b *ServiceBuilder) BuildDeliveryWorker(baseRouter *gin.Engine) error {
 	b.logger.Debug("building delivery service worker")
 
-	svc := &serviceInstance{
-		name:         "delivery",
-		cleanupFuncs: []func(context.Context, *logging.LoggerWithCtx){},
-	}
-	b.services = append(b.services, svc)
+	svc := b.newServiceInstance("delivery")
 
 	// Initialize common infrastructure
 	if err := svc.initRedis(b.ctx, b.cfg, b.logger); err != nil {
@@ -276,7 +641,7 @@ func (b *ServiceBuilder) BuildDeliveryWorker(baseRouter *gin.Engine) error {
 	if err := svc.initDeliveryMQ(b.ctx, b.cfg, b.logger); err != nil {
 		return err
 	}
-	if err := svc.initDestRegistry(b.cfg, b.logger); err != nil {
+	if err := svc.initDestRegistry(b.cfg, b.logger, b.clock); err != nil {
 		return err
 	}
 	if err := svc.initEventTracer(b.cfg, b.logger); err != nil {
@@ -288,7 +653,7 @@ func (b *ServiceBuilder) BuildDeliveryWorker(baseRouter *gin.Engine) error {
 	if err := svc.initLogStore(b.ctx, b.cfg, b.logger); err != nil {
 		return err
 	}
-	if err := svc.initRetryScheduler(b.ctx, b.cfg, b.logger); err != nil {
+	if err := svc.initRetryScheduler(b.ctx, b.cfg, b.logger, b.clock); err != nil {
 		return err
 	}
 
@@ -301,10 +666,81 @@ func (b *ServiceBuilder) BuildDeliveryWorker(baseRouter *gin.Engine) error {
 
 	retryBackoff, retryMaxLimit := b.cfg.GetRetryBackoff()
 
+	redactor, err := b.cfg.LogRedaction.ToRedactor()
+	if err != nil {
+		return fmt.Errorf("failed to create log redactor: %w", err)
+	}
+
+	deliveryRecorder := recorder.New(b.logger)
+	svc.cleanupFuncs = append(svc.cleanupFuncs, func(ctx context.Context, logger *logging.LoggerWithCtx) {
+		deliveryRecorder.Close()
+	})
+
+	handlerOpts := []deliverymq.MessageHandlerOption{
+		deliverymq.WithTenantGetter(svc.tenantStore),
+		deliverymq.WithRecorder(deliveryRecorder),
+		deliverymq.WithRedactor(redactor),
+		deliverymq.WithAckRegistry(deliveryack.New(svc.redisClient, deliveryack.WithDeploymentID(b.cfg.DeploymentID))),
+		deliverymq.WithRetryLimiter(deliverymq.NewRetryLimiter(deliverymq.RetryLimiterConfig{
+			MaxConcurrencyPerHost: b.cfg.RetryMaxConcurrencyPerHost,
+			MaxConcurrency:        b.cfg.RetryMaxConcurrency,
+		})),
+		deliverymq.WithDestinationLimiter(deliverymq.NewDestinationLimiter(b.clock)),
+		deliverymq.WithFanoutLimiter(deliverymq.NewFanoutLimiter(b.cfg.FanoutLimiterConfig())),
+		deliverymq.WithHealthRecorder(b.newDestinationHealth(svc)),
+		deliverymq.WithHeldTasks(b.newDeliveryHold(svc)),
+	}
+	// Deliver messages queued before a target change to the previous target,
+	// when the tenant store keeps destination versions
+	if versions, ok := svc.tenantStore.(tenantstore.DestinationVersioner); ok {
+		handlerOpts = append(handlerOpts, deliverymq.WithDestinationVersions(versions))
+	}
+
+	// Re-enable auto-disabled destinations after their probation, when
+	// consecutive failures are tracked
+	alertSettings, err := b.cfg.Alert.ToConfig()
+	if err != nil {
+		return fmt.Errorf("failed to resolve alert config: %w", err)
+	}
+	if alertSettings.ConsecutiveFailure.Enabled {
+		reenabler := probation.NewReenabler(b.newProbation(svc), svc.tenantStore, svc.destRegistry,
+			alert.NewRedisAlertStore(svc.redisClient, b.cfg.DeploymentID), alertSettings.Policy(), b.logger)
+		b.supervisor.Register(NewProbationWorker(reenabler, b.logger))
+	}
+
+	// Record tenant activity and warm the publishers of recently active
+	// tenants (optional)
+	if b.cfg.DeliveryWarmupTenants > 0 {
+		activity := deliverywarmup.NewActivity(svc.redisClient, deliverywarmup.WithDeploymentID(b.cfg.DeploymentID))
+		handlerOpts = append(handlerOpts, deliverymq.WithActivityTracker(activity))
+		b.supervisor.Register(NewDeliveryWarmupWorker(activity, svc.tenantStore, svc.destRegistry, b.cfg.DeliveryWarmupTenants, b.logger))
+	}
+
+	// Delivery hooks (optional)
+	hooks := b.cfg.DeliveryHooks.ToConfig()
+	hooks.Pre = append(slices.Clone(b.preDeliveryHooks), hooks.Pre...)
+	hooks.Post = append(slices.Clone(b.postDeliveryHooks), hooks.Post...)
+	if hooks.Enabled() {
+		handlerOpts = append(handlerOpts, deliverymq.WithDeliveryHooks(hooks))
+	}
+
+	// Journal attempts until the log worker persists them, so they can be
+	// backfilled after a log store outage (optional)
+	var logPublisher deliverymq.LogPublisher = svc.logMQ
+	if b.cfg.DeliveryJournal.Enabled {
+		logPublisher = deliveryjournal.NewPublisher(svc.logMQ, b.newDeliveryJournal(svc), func(ctx context.Context, entry models.LogEntry, err error) {
+			b.logger.Ctx(ctx).Error("failed to journal attempt",
+				zap.Error(err),
+				zap.String("attempt_id", entry.Attempt.ID),
+				zap.String("event_id", entry.Event.ID),
+				zap.String("tenant_id", entry.Event.TenantID))
+		})
+	}
+
 	// Create delivery handler
 	handler := deliverymq.NewMessageHandler(
 		b.logger,
-		svc.logMQ,
+		logPublisher,
 		svc.tenantStore,
 		svc.destRegistry,
 		svc.eventTracer,
@@ -312,6 +748,7 @@ func (b *ServiceBuilder) BuildDeliveryWorker(baseRouter *gin.Engine) error {
 		retryBackoff,
 		retryMaxLimit,
 		deliveryIdempotence,
+		handlerOpts...,
 	)
 
 	svc.router = baseRouter
@@ -334,11 +771,7 @@ func (b *ServiceBuilder) BuildDeliveryWorker(baseRouter *gin.Engine) error {
 func (b *ServiceBuilder) BuildLogWorker(baseRouter *gin.Engine) error {
 	b.logger.Debug("building log service worker")
 
-	svc := &serviceInstance{
-		name:         "log",
-		cleanupFuncs: []func(context.Context, *logging.LoggerWithCtx){},
-	}
-	b.services = append(b.services, svc)
+	svc := b.newServiceInstance("log")
 
 	// Initialize common infrastructure
 	if err := svc.initRedis(b.ctx, b.cfg, b.logger); err != nil {
@@ -357,15 +790,20 @@ func (b *ServiceBuilder) BuildLogWorker(baseRouter *gin.Engine) error {
 	if err != nil {
 		return fmt.Errorf("failed to create operator events sink: %w", err)
 	}
-	emitter := opevents.NewEmitter(sink, b.cfg.DeploymentID, oeCfg.Topics, b.logger)
+	var emitter opevents.Emitter = opevents.NewEmitter(sink, b.cfg.DeploymentID, oeCfg.Topics, b.logger)
+	// Alerts also go to the deployment's and tenants' alert channels
+	emitter = alertchannel.NewEmitter(emitter, b.cfg.Alert.Channels.ToConfig(), b.cfg.DeploymentID, svc.tenantStore, b.logger)
 
 	alertSettings, err := b.cfg.Alert.ToConfig()
 	if err != nil {
 		return fmt.Errorf("failed to resolve alert config: %w", err)
 	}
 
+	// Tenants may auto-disable their destinations even when the deployment
+	// doesn't, so the disabler is there whenever consecutive failures are
+	// tracked; the policy decides whether it's used.
 	var disabler logmq.DestinationDisabler
-	if alertSettings.AutoDisableDestination {
+	if alertSettings.ConsecutiveFailure.Enabled {
 		disabler = newDestinationDisabler(svc.tenantStore)
 	}
 
@@ -391,6 +829,7 @@ func (b *ServiceBuilder) BuildLogWorker(baseRouter *gin.Engine) error {
 		retryMaxLimit,
 		alert.WithConsecutiveFailureEnabled(alertSettings.ConsecutiveFailure.Enabled),
 		alert.WithAutoDisableFailureCount(alertSettings.ConsecutiveFailure.Count),
+		alert.WithFailureWindow(time.Duration(alertSettings.ConsecutiveFailure.WindowSeconds)*time.Second),
 		alert.WithExhaustedRetriesEnabled(alertSettings.ExhaustedRetries.Enabled),
 	)
 
@@ -408,17 +847,45 @@ func (b *ServiceBuilder) BuildLogWorker(baseRouter *gin.Engine) error {
 		DelayThreshold:     delayThreshold,
 	}
 
+	batchProcessorCfg := logmq.BatchProcessorConfig{
+		ItemCountThreshold: batcherCfg.ItemCountThreshold,
+		DelayThreshold:     batcherCfg.DelayThreshold,
+	}
+	if adaptive := b.cfg.LogBatchAdaptive; adaptive.Enabled {
+		batchProcessorCfg.Adaptive = &logmq.AdaptiveBatching{
+			MinItems:   adaptive.MinSize,
+			MaxItems:   adaptive.MaxSize,
+			MinDelay:   adaptive.MinThreshold(),
+			MaxDelay:   adaptive.MaxThreshold(),
+			LatencySLO: adaptive.LatencySLO(),
+		}
+	}
+	lifecycleNotifier, err := b.newLifecycleNotifier(svc)
+	if err != nil {
+		return err
+	}
+	if lifecycleNotifier != nil {
+		batchProcessorCfg.Lifecycle = lifecycleNotifier
+	}
+
+	// Backfill attempts the log queue never persisted (optional)
+	if b.cfg.DeliveryJournal.Enabled {
+		journal := b.newDeliveryJournal(svc)
+		batchProcessorCfg.Journal = journal
+		b.supervisor.Register(NewDeliveryJournalWorker(journal, svc.logStore, b.cfg.DeliveryJournal.GracePeriod(), b.logger))
+	}
+
 	b.logger.Debug("creating log batcher")
 	batchProcessor, err := logmq.NewBatchProcessor(b.ctx, b.logger, svc.logStore, logmq.AlertPipeline{
 		Evaluator:      alertEvaluator,
 		Emitter:        emitter,
 		Disabler:       disabler,
+		Policy:         alertSettings.Policy(),
+		Probation:      b.newProbation(svc),
 		ProcessedIdemp: processedIdemp,
 		ExhaustedIdemp: exhaustedRetriesIdemp,
-	}, logmq.BatchProcessorConfig{
-		ItemCountThreshold: batcherCfg.ItemCountThreshold,
-		DelayThreshold:     batcherCfg.DelayThreshold,
-	})
+		Tenants:        svc.tenantStore,
+	}, batchProcessorCfg)
 	if err != nil {
 		b.logger.Error("failed to create batcher", zap.Error(err))
 		return err
@@ -439,6 +906,7 @@ func (b *ServiceBuilder) BuildLogWorker(baseRouter *gin.Engine) error {
 	}
 
 	logMQ := logmq.New(logmq.WithQueue(logQueueConfig))
+	svc.logMQ = logMQ
 
 	svc.router = baseRouter
 
@@ -452,10 +920,117 @@ func (b *ServiceBuilder) BuildLogWorker(baseRouter *gin.Engine) error {
 	)
 	b.supervisor.Register(logWorker)
 
+	// ClickHouse expires logs through table TTLs (applied at startup); other
+	// log stores are pruned by a worker, which also trims a tiered log
+	// store's hot tier to its window. With archiving, the worker only prunes
+	// what the archiver has exported.
+	if pruner, ok := svc.logStore.(logstore.Pruner); ok {
+		if policy := b.cfg.LogRetentionPolicy(); !policy.IsZero() || b.cfg.HotTierMaxAge() > 0 {
+			var archiver *logarchive.Archiver
+			if b.cfg.LogArchive.Enabled {
+				archiver, err = b.newLogArchiver(svc)
+				if err != nil {
+					return err
+				}
+			}
+			b.supervisor.Register(NewLogRetentionWorker(pruner, policy, archiver, b.newLegalHolds(svc), svc.redisClient, b.cfg.DeploymentID, b.logger, b.clock))
+		}
+	}
+
 	b.logger.Info("log service worker built successfully")
 	return nil
 }
 
+// BuildArchiverWorker creates and registers the worker archiving delivery logs
+// to object storage.
+func (b *ServiceBuilder) BuildArchiverWorker() error {
+	b.logger.Debug("building archiver service worker")
+
+	svc := b.newServiceInstance("archiver")
+
+	if err := svc.initRedis(b.ctx, b.cfg, b.logger); err != nil {
+		return err
+	}
+	if err := svc.initLogStore(b.ctx, b.cfg, b.logger); err != nil {
+		return err
+	}
+
+	archiver, err := b.newLogArchiver(svc)
+	if err != nil {
+		return err
+	}
+	b.supervisor.Register(NewLogArchiveWorker(archiver, svc.redisClient, b.cfg.DeploymentID, b.logger, b.clock))
+
+	b.logger.Info("archiver service worker built successfully")
+	return nil
+}
+
+// newLogArchiver creates the archiver of the service's log store.
+func (b *ServiceBuilder) newLogArchiver(svc *serviceInstance) (*logarchive.Archiver, error) {
+	store, err := logarchive.NewS3Store(b.ctx, b.cfg.LogArchive.ToConfig())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create log archive store: %w", err)
+	}
+	var opts []logarchive.ArchiverOption
+	if b.clock != nil {
+		opts = append(opts, logarchive.WithNow(b.clock.Now))
+	}
+	return logarchive.NewArchiver(svc.logStore, store, b.cfg.LogArchive.AfterDays, b.logger, opts...), nil
+}
+
+func (b *ServiceBuilder) newDestinationHealth(svc *serviceInstance) *desthealth.Store {
+	opts := []desthealth.Option{desthealth.WithDeploymentID(b.cfg.DeploymentID)}
+	if b.clock != nil {
+		opts = append(opts, desthealth.WithClock(b.clock))
+	}
+	return desthealth.NewStore(svc.redisClient, opts...)
+}
+
+// newTopicStats returns the topic stats store, or nil when topic analytics
+// are disabled.
+func (b *ServiceBuilder) newTopicStats(svc *serviceInstance) *topicstats.Store {
+	if !b.cfg.TopicAnalytics.Enabled {
+		return nil
+	}
+	opts := []topicstats.Option{
+		topicstats.WithDeploymentID(b.cfg.DeploymentID),
+		topicstats.WithRetentionDays(b.cfg.TopicAnalytics.RetentionDays),
+	}
+	if b.clock != nil {
+		opts = append(opts, topicstats.WithClock(b.clock))
+	}
+	return topicstats.NewStore(svc.redisClient, opts...)
+}
+
+func (b *ServiceBuilder) newLegalHolds(svc *serviceInstance) *legalhold.Store {
+	opts := []legalhold.Option{legalhold.WithDeploymentID(b.cfg.DeploymentID)}
+	if b.clock != nil {
+		opts = append(opts, legalhold.WithClock(b.clock))
+	}
+	// ClickHouse expires logs with table TTLs, which holds don't exempt
+	// records from, so refuse holds rather than accept and lose them.
+	if b.cfg.ClickHouse.Addr != "" && !b.cfg.LogRetentionPolicy().IsZero() {
+		opts = append(opts, legalhold.WithUnenforceable("ClickHouse expires delivery logs with table TTLs regardless of holds; unset the log retention settings to place holds"))
+	}
+	return legalhold.NewStore(svc.redisClient, opts...)
+}
+
+func (b *ServiceBuilder) newDeliveryHold(svc *serviceInstance) *deliveryhold.Store {
+	opts := []deliveryhold.Option{deliveryhold.WithDeploymentID(b.cfg.DeploymentID)}
+	if b.clock != nil {
+		opts = append(opts, deliveryhold.WithClock(b.clock))
+	}
+	return deliveryhold.New(svc.redisClient, opts...)
+}
+
+func (b *ServiceBuilder) newProbation(svc *serviceInstance) *probation.Store {
+	opts := []probation.Option{probation.WithDeploymentID(b.cfg.DeploymentID)}
+	if b.clock != nil {
+		opts = append(opts, probation.WithClock(b.clock))
+	}
+	return probation.NewStore(svc.redisClient, opts...)
+}
+
 // destinationDisabler implements logmq.DestinationDisabler by setting DisabledAt on the destination.
 type destinationDisabler struct {
 	tenantStore tenantstore.TenantStore
@@ -465,7 +1040,7 @@ func newDestinationDisabler(tenantStore tenantstore.TenantStore) logmq.Destinati
 	return &destinationDisabler{tenantStore: tenantStore}
 }
 
-func (d *destinationDisabler) DisableDestination(ctx context.Context, tenantID, destinationID string) error {
+func (d *destinationDisabler) DisableDestination(ctx context.Context, tenantID, destinationID string, disabledAt time.Time) error {
 	destination, err := d.tenantStore.RetrieveDestination(ctx, tenantID, destinationID)
 	if err != nil {
 		return err
@@ -473,14 +1048,84 @@ func (d *destinationDisabler) DisableDestination(ctx context.Context, tenantID,
 	if destination == nil {
 		return nil
 	}
-	now := time.Now()
-	destination.DisabledAt = &now
+	destination.DisabledAt = &disabledAt
 	return d.tenantStore.UpsertDestination(ctx, *destination)
 }
 
+// newReplicator connects to the primary deployment's Redis and creates the
+// replicator of its tenants and destinations into the service's tenant store.
+func (b *ServiceBuilder) newReplicator(svc *serviceInstance) (*replication.Replicator, error) {
+	sourceClient, err := redis.New(b.ctx, b.cfg.Replication.ToConfig(&b.cfg.Redis))
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to the replication source: %w", err)
+	}
+	svc.cleanupFuncs = append(svc.cleanupFuncs, func(ctx context.Context, logger *logging.LoggerWithCtx) {
+		sourceClient.Close()
+	})
+
+	encryptionKeys, err := b.cfg.AESEncryptionKeyRing()
+	if err != nil {
+		return nil, err
+	}
+	sourceStore := tenantstore.New(tenantstore.Config{
+		RedisClient:     sourceClient,
+		Secret:          b.cfg.AESEncryptionSecret,
+		PreviousSecrets: b.cfg.AESEncryptionPreviousSecrets,
+		EncryptionKeys:  encryptionKeys,
+		PrimaryKeyID:    b.cfg.AESEncryptionPrimaryKeyID,
+		DeploymentID:    b.cfg.Replication.SourceDeploymentID,
+	})
+	if err := sourceStore.Init(b.ctx); err != nil {
+		return nil, fmt.Errorf("failed to initialize the replication source: %w", err)
+	}
+
+	return replication.New(sourceClient, sourceStore, svc.redisClient, svc.tenantStore,
+		replication.WithSourceDeploymentID(b.cfg.Replication.SourceDeploymentID),
+		replication.WithDeploymentID(b.cfg.DeploymentID),
+	), nil
+}
+
+// newDeliveryJournal creates the journal of attempts yet to be persisted.
+func (b *ServiceBuilder) newDeliveryJournal(svc *serviceInstance) *deliveryjournal.Journal {
+	opts := []deliveryjournal.Option{
+		deliveryjournal.WithDeploymentID(b.cfg.DeploymentID),
+		deliveryjournal.WithRetention(b.cfg.DeliveryJournal.Retention()),
+	}
+	if b.clock != nil {
+		opts = append(opts, deliveryjournal.WithClock(b.clock))
+	}
+	return deliveryjournal.New(svc.redisClient, opts...)
+}
+
+// newLifecycleNotifier creates the service's event lifecycle notifier and
+// registers its shutdown, or returns nil when no callback can receive
+// notifications.
+func (b *ServiceBuilder) newLifecycleNotifier(svc *serviceInstance) (*lifecycle.Notifier, error) {
+	_, retryMaxLimit := b.cfg.GetRetryBackoff()
+	lifecycleCfg := b.cfg.EventLifecycle.ToConfig(retryMaxLimit)
+	if !lifecycleCfg.Enabled() {
+		return nil, nil
+	}
+	var opts []lifecycle.Option
+	if b.clock != nil {
+		opts = append(opts, lifecycle.WithClock(b.clock))
+	}
+	notifier, err := lifecycle.New(b.ctx, b.logger, svc.tenantStore, lifecycleCfg, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create event lifecycle notifier: %w", err)
+	}
+	svc.cleanupFuncs = append(svc.cleanupFuncs, func(ctx context.Context, logger *logging.LoggerWithCtx) {
+		notifier.Shutdown()
+	})
+	return notifier, nil
+}
+
 // Helper methods for serviceInstance to initialize common dependencies
 
 func (s *serviceInstance) initRedis(ctx context.Context, cfg *config.Config, logger *logging.Logger) error {
+	if s.redisClient != nil {
+		return nil
+	}
 	logger.Debug("initializing Redis client", zap.String("service", s.name))
 	redisClient, err := redis.New(ctx, cfg.Redis.ToConfig())
 	if err != nil {
@@ -492,11 +1137,15 @@ func (s *serviceInstance) initRedis(ctx context.Context, cfg *config.Config, log
 }
 
 func (s *serviceInstance) initLogStore(ctx context.Context, cfg *config.Config, logger *logging.Logger) error {
+	if s.logStore != nil {
+		return nil
+	}
 	logger.Debug("configuring log store driver", zap.String("service", s.name))
 	logStoreDriverOpts, err := logstore.MakeDriverOpts(logstore.Config{
-		ClickHouse:   cfg.ClickHouse.ToConfig(),
-		Postgres:     &cfg.PostgresURL,
-		DeploymentID: cfg.DeploymentID,
+		ClickHouse:    cfg.ClickHouse.ToConfig(),
+		Postgres:      &cfg.PostgresURL,
+		DeploymentID:  cfg.DeploymentID,
+		HotTierMaxAge: cfg.HotTierMaxAge(),
 	})
 	if err != nil {
 		logger.Error("log store driver configuration failed", zap.String("service", s.name), zap.Error(err))
@@ -506,6 +1155,17 @@ func (s *serviceInstance) initLogStore(ctx context.Context, cfg *config.Config,
 		logStoreDriverOpts.Close()
 	})
 
+	// Only the API reads from the archive; the archiver in particular must
+	// read the log store alone.
+	if maxAge := cfg.ArchiveTierMaxAge(); maxAge > 0 && s.name == "api" {
+		archiveStore, err := logarchive.NewS3Store(ctx, cfg.LogArchive.ToConfig())
+		if err != nil {
+			return fmt.Errorf("failed to create log archive store: %w", err)
+		}
+		logStoreDriverOpts.Archive = logarchive.NewReader(archiveStore)
+		logStoreDriverOpts.ArchiveTierMaxAge = maxAge
+	}
+
 	logger.Debug("creating log store", zap.String("service", s.name))
 	logStore, err := logstore.NewLogStore(ctx, logStoreDriverOpts)
 	if err != nil {
@@ -517,16 +1177,39 @@ func (s *serviceInstance) initLogStore(ctx context.Context, cfg *config.Config,
 }
 
 func (s *serviceInstance) initTenantStore(ctx context.Context, cfg *config.Config, logger *logging.Logger) error {
+	if s.tenantStore != nil {
+		return nil
+	}
 	if s.redisClient == nil {
 		return fmt.Errorf("redis client must be initialized before tenant store")
 	}
 	logger.Debug("creating tenant store", zap.String("service", s.name))
+	encryptionKeys, err := cfg.AESEncryptionKeyRing()
+	if err != nil {
+		return err
+	}
+	var coldStore tenantstore.ColdStore
+	if cfg.TenantColdStorage.Enabled {
+		coldStore, err = logarchive.NewS3Store(ctx, cfg.TenantColdStorage.ToConfig())
+		if err != nil {
+			return fmt.Errorf("failed to create tenant cold storage: %w", err)
+		}
+	}
+	var changefeedMaxLen int64
+	if cfg.Replication.ChangefeedEnabled {
+		changefeedMaxLen = cfg.Replication.ChangefeedMaxLen
+	}
 	s.tenantStore = tenantstore.New(tenantstore.Config{
 		RedisClient:              s.redisClient,
 		Secret:                   cfg.AESEncryptionSecret,
+		PreviousSecrets:          cfg.AESEncryptionPreviousSecrets,
+		EncryptionKeys:           encryptionKeys,
+		PrimaryKeyID:             cfg.AESEncryptionPrimaryKeyID,
 		AvailableTopics:          cfg.Topics,
 		MaxDestinationsPerTenant: cfg.MaxDestinationsPerTenant,
 		DeploymentID:             cfg.DeploymentID,
+		ColdStore:                coldStore,
+		ChangefeedMaxLen:         changefeedMaxLen,
 	})
 	if err := s.tenantStore.Init(ctx); err != nil {
 		return fmt.Errorf("failed to initialize tenant store: %w", err)
@@ -534,13 +1217,30 @@ func (s *serviceInstance) initTenantStore(ctx context.Context, cfg *config.Confi
 	return nil
 }
 
-func (s *serviceInstance) initDestRegistry(cfg *config.Config, logger *logging.Logger) error {
+// topicStore returns the store of the runtime topics and topic lifecycle,
+// seeded with the configured lifecycle. It needs Redis to be initialized.
+func (s *serviceInstance) topicStore(cfg *config.Config) *topicstore.Store {
+	if s.topics == nil {
+		s.topics = topicstore.New(s.redisClient, cfg.Topics,
+			topicstore.WithDeploymentID(cfg.DeploymentID),
+			topicstore.WithLifecycle(cfg.TopicLifecycle()),
+		)
+	}
+	return s.topics
+}
+
+func (s *serviceInstance) initDestRegistry(cfg *config.Config, logger *logging.Logger, clk clock.Clock) error {
 	logger.Debug("initializing destination registry", zap.String("service", s.name))
 	registry := destregistry.NewRegistry(&destregistry.Config{
 		DestinationMetadataPath: cfg.Destinations.MetadataPath,
 		DeliveryTimeout:         time.Duration(cfg.DeliveryTimeoutSeconds) * time.Second,
+		HeaderLimits:            cfg.Destinations.HeaderLimits(),
+		PayloadOffloader:        s.payloadOffloader(cfg, clk),
+		StripTopicNamespace:     deliveredTopicNamespace(cfg),
 	}, logger)
-	if err := destregistrydefault.RegisterDefault(registry, cfg.Destinations.ToConfig(cfg)); err != nil {
+	opts := cfg.Destinations.ToConfig(cfg)
+	opts.TopicLifecycle = s.topicStore(cfg)
+	if err := destregistrydefault.RegisterDefault(registry, opts); err != nil {
 		logger.Error("destination registry setup failed", zap.String("service", s.name), zap.Error(err))
 		return err
 	}
@@ -548,6 +1248,31 @@ func (s *serviceInstance) initDestRegistry(cfg *config.Config, logger *logging.L
 	return nil
 }
 
+// deliveredTopicNamespace returns the topic namespace removed from event
+// topics before delivery, or "" when topics are delivered namespaced.
+func deliveredTopicNamespace(cfg *config.Config) models.TopicNamespace {
+	if !cfg.TopicNamespaceStripOnDelivery {
+		return ""
+	}
+	return cfg.GetTopicNamespace()
+}
+
+// payloadOffloader returns the offloader for payloads over a destination's
+// size limit, or nil when offloading is not configured. Offloaded payloads
+// live in Redis, so services that don't connect to Redis before building the
+// registry deliver inline; only the delivery worker publishes.
+func (s *serviceInstance) payloadOffloader(cfg *config.Config, clk clock.Clock) destregistry.PayloadOffloader {
+	if cfg.PayloadOffloadBaseURL == "" || s.redisClient == nil {
+		return nil
+	}
+	store := payloadoffload.New(s.redisClient, payloadoffload.WithDeploymentID(cfg.DeploymentID))
+	var opts []payloadoffload.OffloaderOption
+	if clk != nil {
+		opts = append(opts, payloadoffload.WithClock(clk))
+	}
+	return payloadoffload.NewOffloader(store, cfg.PayloadOffloadBaseURL, time.Duration(cfg.PayloadOffloadTTLSeconds)*time.Second, opts...)
+}
+
 func (s *serviceInstance) initEventTracer(cfg *config.Config, logger *logging.Logger) error {
 	logger.Debug("setting up event tracer", zap.String("service", s.name))
 	if cfg.OpenTelemetry.ToConfig() == nil {
@@ -598,7 +1323,7 @@ func (s *serviceInstance) initLogMQ(ctx context.Context, cfg *config.Config, log
 	return nil
 }
 
-func (s *serviceInstance) initRetryScheduler(ctx context.Context, cfg *config.Config, logger *logging.Logger) error {
+func (s *serviceInstance) initRetryScheduler(ctx context.Context, cfg *config.Config, logger *logging.Logger, clk clock.Clock) error {
 	if s.deliveryMQ == nil {
 		return fmt.Errorf("delivery MQ must be initialized before retry scheduler")
 	}
@@ -611,6 +1336,9 @@ func (s *serviceInstance) initRetryScheduler(ctx context.Context, cfg *config.Co
 	if cfg.RetryVisibilityTimeoutSeconds > 0 {
 		retrySchedulerOpts = append(retrySchedulerOpts, deliverymq.WithRetryVisibilityTimeout(uint(cfg.RetryVisibilityTimeoutSeconds)))
 	}
+	if clk != nil {
+		retrySchedulerOpts = append(retrySchedulerOpts, deliverymq.WithRetryClock(clk))
+	}
 	retryScheduler, err := deliverymq.NewRetryScheduler(s.deliveryMQ, cfg.Redis.ToConfig(), cfg.DeploymentID, pollBackoff, logger, s.logStore, retrySchedulerOpts...)
 	if err != nil {
 		logger.Error("failed to create delivery MQ retry scheduler", zap.String("service", s.name), zap.Error(err))
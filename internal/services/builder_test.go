@@ -0,0 +1,37 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/config"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestServiceBuilder_InjectedDependencies(t *testing.T) {
+	ctx := context.Background()
+	redisClient := testutil.CreateTestRedisClient(t)
+	logStore := logstore.NewMemLogStore()
+	tenantStore := tenantstore.NewMemTenantStore()
+
+	b := NewServiceBuilder(ctx, &config.Config{}, testutil.CreateTestLogger(t), nil,
+		WithRedisClient(redisClient),
+		WithLogStore(logStore),
+		WithTenantStore(tenantStore),
+	)
+	svc := b.newServiceInstance("api")
+
+	require.NoError(t, svc.initRedis(ctx, b.cfg, b.logger))
+	require.NoError(t, svc.initLogStore(ctx, b.cfg, b.logger))
+	require.NoError(t, svc.initTenantStore(ctx, b.cfg, b.logger))
+
+	assert.Equal(t, redisClient, svc.redisClient)
+	assert.Equal(t, logStore, svc.logStore)
+	assert.Equal(t, tenantStore, svc.tenantStore)
+	assert.Empty(t, svc.cleanupFuncs, "injected dependencies are owned by the caller")
+	assert.Equal(t, []*serviceInstance{svc}, b.services)
+}
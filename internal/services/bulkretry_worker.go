@@ -0,0 +1,38 @@
+package services
+
+import (
+	"context"
+
+	"github.com/hookdeck/outpost/internal/bulkretry"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/worker"
+)
+
+// BulkRetryWorker ties bulk retry jobs to the service lifecycle. Jobs are
+// started by API requests; on shutdown the worker stops the runner, which
+// interrupts running jobs and saves them as failed before the service exits.
+type BulkRetryWorker struct {
+	runner *bulkretry.Runner
+	logger *logging.Logger
+}
+
+// NewBulkRetryWorker creates a new bulk retry worker.
+func NewBulkRetryWorker(runner *bulkretry.Runner, logger *logging.Logger) worker.Worker {
+	return &BulkRetryWorker{
+		runner: runner,
+		logger: logger,
+	}
+}
+
+// Name returns the worker name.
+func (w *BulkRetryWorker) Name() string {
+	return "bulk-retry"
+}
+
+// Run blocks until the context is cancelled, then stops the runner.
+func (w *BulkRetryWorker) Run(ctx context.Context) error {
+	<-ctx.Done()
+	w.logger.Ctx(ctx).Info("stopping bulk retry jobs")
+	w.runner.Stop()
+	return nil
+}
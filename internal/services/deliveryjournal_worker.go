@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/deliveryjournal"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/worker"
+	"go.uber.org/zap"
+)
+
+const deliveryJournalInterval = time.Minute
+
+// DeliveryJournalWorker backfills the log store with journaled attempts that
+// were never persisted. Failures, such as the log store still being down, are
+// logged rather than returned so they never mark the service unhealthy; the
+// attempts stay journaled and are retried on the next tick.
+type DeliveryJournalWorker struct {
+	journal     *deliveryjournal.Journal
+	logStore    deliveryjournal.LogStore
+	gracePeriod time.Duration
+	logger      *logging.Logger
+}
+
+// NewDeliveryJournalWorker creates a new delivery journal worker.
+func NewDeliveryJournalWorker(journal *deliveryjournal.Journal, logStore deliveryjournal.LogStore, gracePeriod time.Duration, logger *logging.Logger) worker.Worker {
+	return &DeliveryJournalWorker{
+		journal:     journal,
+		logStore:    logStore,
+		gracePeriod: gracePeriod,
+		logger:      logger,
+	}
+}
+
+// Name returns the worker name.
+func (w *DeliveryJournalWorker) Name() string {
+	return "delivery-journal"
+}
+
+// Run backfills journaled attempts until the context is cancelled.
+func (w *DeliveryJournalWorker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(deliveryJournalInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		w.runOnce(ctx)
+	}
+}
+
+func (w *DeliveryJournalWorker) runOnce(ctx context.Context) {
+	logger := w.logger.Ctx(ctx)
+	backfilled, err := w.journal.Reconcile(ctx, w.logStore, w.gracePeriod)
+	if err != nil && ctx.Err() == nil {
+		logger.Error("failed to backfill delivery journal",
+			zap.Int("backfilled", backfilled),
+			zap.Error(err))
+		return
+	}
+	if backfilled > 0 {
+		logger.Warn("backfilled attempts missing from the log store", zap.Int("backfilled", backfilled))
+	}
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"net"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/worker"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+// GRPCServerWorker wraps a gRPC server as a worker.
+type GRPCServerWorker struct {
+	server *grpc.Server
+	addr   string
+	logger *logging.Logger
+}
+
+// NewGRPCServerWorker creates a new gRPC server worker listening on addr.
+func NewGRPCServerWorker(server *grpc.Server, addr string, logger *logging.Logger) worker.Worker {
+	return &GRPCServerWorker{
+		server: server,
+		addr:   addr,
+		logger: logger,
+	}
+}
+
+// Name returns the worker name.
+func (w *GRPCServerWorker) Name() string {
+	return "grpc-server"
+}
+
+// Run starts the gRPC server and blocks until context is cancelled or server fails.
+func (w *GRPCServerWorker) Run(ctx context.Context) error {
+	logger := w.logger.Ctx(ctx)
+
+	listener, err := net.Listen("tcp", w.addr)
+	if err != nil {
+		logger.Error("grpc server error", zap.Error(err))
+		return err
+	}
+	logger.Info("grpc server listening", zap.String("addr", w.addr))
+
+	errChan := make(chan error, 1)
+	go func() {
+		if err := w.server.Serve(listener); err != nil {
+			errChan <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+		// Graceful shutdown, forced once in-flight calls take too long
+		logger.Info("shutting down grpc server")
+		stopped := make(chan struct{})
+		go func() {
+			w.server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-time.After(10 * time.Second):
+			w.server.Stop()
+		}
+		logger.Info("grpc server shut down")
+		return nil
+
+	case err := <-errChan:
+		logger.Error("grpc server error", zap.Error(err))
+		return err
+	}
+}
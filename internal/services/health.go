@@ -7,11 +7,21 @@ import (
 	"github.com/hookdeck/outpost/internal/worker"
 )
 
+// HealthReporter adds the status of a component to the health check response
+// under HealthKey. It is informational and never makes the service unhealthy.
+type HealthReporter interface {
+	HealthKey() string
+	HealthStatus() any
+}
+
 // HealthHandler creates a health check handler that reports worker supervisor health
-func HealthHandler(supervisor *worker.WorkerSupervisor) gin.HandlerFunc {
+func HealthHandler(supervisor *worker.WorkerSupervisor, reporters ...HealthReporter) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		tracker := supervisor.GetHealthTracker()
 		status := tracker.GetStatus()
+		for _, reporter := range reporters {
+			status[reporter.HealthKey()] = reporter.HealthStatus()
+		}
 		if tracker.IsHealthy() {
 			c.JSON(http.StatusOK, status)
 		} else {
@@ -26,12 +36,12 @@ func HealthHandler(supervisor *worker.WorkerSupervisor) gin.HandlerFunc {
 // TODO: Rethink API versioning strategy in the future.
 // For now, we expose health check at both /healthz and /api/v1/healthz for backwards compatibility.
 // The /api/v1 prefix is hardcoded here but should be part of a broader versioning approach.
-func NewBaseRouter(supervisor *worker.WorkerSupervisor, ginMode string) *gin.Engine {
+func NewBaseRouter(supervisor *worker.WorkerSupervisor, ginMode string, reporters ...HealthReporter) *gin.Engine {
 	gin.SetMode(ginMode)
 	r := gin.New()
 	r.Use(gin.Recovery())
 
-	healthHandler := HealthHandler(supervisor)
+	healthHandler := HealthHandler(supervisor, reporters...)
 	r.GET("/healthz", healthHandler)
 	r.GET("/api/v1/healthz", healthHandler)
 
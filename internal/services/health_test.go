@@ -0,0 +1,33 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/hookdeck/outpost/internal/worker"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeHealthReporter struct{}
+
+func (fakeHealthReporter) HealthKey() string { return "redis_standby" }
+func (fakeHealthReporter) HealthStatus() any { return map[string]any{"status": redisStandbyNotReady} }
+
+func TestHealthHandler_Reporters(t *testing.T) {
+	supervisor := worker.NewWorkerSupervisor(testutil.CreateTestLogger(t))
+	router := NewBaseRouter(supervisor, gin.TestMode, fakeHealthReporter{})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+	require.Equal(t, http.StatusOK, w.Code, "a reporter never makes the service unhealthy")
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "healthy", body["status"])
+	assert.Equal(t, map[string]any{"status": redisStandbyNotReady}, body["redis_standby"])
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/logarchive"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/worker"
+	"go.uber.org/zap"
+)
+
+const (
+	logArchiveInterval = time.Hour
+	// logArchiveClaimTTL outlives the hour it claims so a slow replica
+	// cannot start the same run again.
+	logArchiveClaimTTL = 2 * time.Hour
+)
+
+// LogArchiveWorker exports delivery logs to object storage once they are old
+// enough. It runs hourly; the first replica to claim an hour in Redis
+// archives, so the manifest only has one writer.
+type LogArchiveWorker struct {
+	archiver     *logarchive.Archiver
+	redisClient  redis.Cmdable
+	deploymentID string
+	logger       *logging.Logger
+	clock        clock.Clock
+}
+
+// NewLogArchiveWorker creates a new log archive worker. A nil clock uses the
+// wall clock.
+func NewLogArchiveWorker(archiver *logarchive.Archiver, redisClient redis.Cmdable, deploymentID string, logger *logging.Logger, clk clock.Clock) worker.Worker {
+	if clk == nil {
+		clk = clock.New()
+	}
+	return &LogArchiveWorker{
+		archiver:     archiver,
+		redisClient:  redisClient,
+		deploymentID: deploymentID,
+		logger:       logger,
+		clock:        clk,
+	}
+}
+
+// Name returns the worker name.
+func (w *LogArchiveWorker) Name() string {
+	return "log-archive"
+}
+
+// Run archives until the context is cancelled. Failures are logged rather
+// than returned so they never mark the service unhealthy; a failed hour is
+// released and the remaining days are archived on the next tick.
+func (w *LogArchiveWorker) Run(ctx context.Context) error {
+	ticker := w.clock.NewTicker(logArchiveInterval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx, w.clock.Now().UTC())
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
+
+func (w *LogArchiveWorker) runOnce(ctx context.Context, now time.Time) {
+	logger := w.logger.Ctx(ctx)
+	hour := now.Truncate(time.Hour).Format("2006-01-02T15")
+	key := w.claimKey(hour)
+
+	claimed, err := w.redisClient.SetNX(ctx, key, now.Format(time.RFC3339), logArchiveClaimTTL).Result()
+	if err != nil {
+		logger.Error("failed to claim log archive run", zap.String("hour", hour), zap.Error(err))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	archived, err := w.archiver.Run(ctx)
+	if err != nil {
+		logger.Error("failed to archive delivery logs",
+			zap.String("hour", hour),
+			zap.Int("days_archived", archived),
+			zap.Error(err))
+		if err := w.redisClient.Del(context.WithoutCancel(ctx), key).Err(); err != nil {
+			logger.Error("failed to release log archive run", zap.String("hour", hour), zap.Error(err))
+		}
+		return
+	}
+	if archived > 0 {
+		logger.Info("delivery logs archived", zap.Int("days_archived", archived))
+	}
+}
+
+func (w *LogArchiveWorker) claimKey(hour string) string {
+	if w.deploymentID == "" {
+		return fmt.Sprintf("log_archive:%s", hour)
+	}
+	return fmt.Sprintf("%s:log_archive:%s", w.deploymentID, hour)
+}
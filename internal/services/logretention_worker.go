@@ -0,0 +1,153 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/legalhold"
+	"github.com/hookdeck/outpost/internal/logarchive"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/logretention"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/worker"
+	"go.uber.org/zap"
+)
+
+const (
+	logRetentionInterval = time.Hour
+	// logRetentionClaimTTL outlives the hour it claims so a slow replica
+	// cannot start the same run again.
+	logRetentionClaimTTL = 2 * time.Hour
+)
+
+// LogRetentionWorker prunes delivery logs past the retention policy from log
+// stores without native expiry. It runs hourly; the first replica to claim an
+// hour in Redis prunes. When logs are archived, nothing is pruned before it
+// has been archived, and logs under a legal hold are never pruned.
+type LogRetentionWorker struct {
+	pruner       logstore.Pruner
+	policy       logretention.Policy
+	archiver     *logarchive.Archiver
+	holds        *legalhold.Store
+	redisClient  redis.Cmdable
+	deploymentID string
+	logger       *logging.Logger
+	clock        clock.Clock
+}
+
+// NewLogRetentionWorker creates a new log retention worker. A nil archiver
+// prunes regardless of archiving, nil holds prune regardless of legal holds,
+// and a nil clock uses the wall clock.
+func NewLogRetentionWorker(pruner logstore.Pruner, policy logretention.Policy, archiver *logarchive.Archiver, holds *legalhold.Store, redisClient redis.Cmdable, deploymentID string, logger *logging.Logger, clk clock.Clock) worker.Worker {
+	if clk == nil {
+		clk = clock.New()
+	}
+	return &LogRetentionWorker{
+		pruner:       pruner,
+		policy:       policy,
+		archiver:     archiver,
+		holds:        holds,
+		redisClient:  redisClient,
+		deploymentID: deploymentID,
+		logger:       logger,
+		clock:        clk,
+	}
+}
+
+// Name returns the worker name.
+func (w *LogRetentionWorker) Name() string {
+	return "log-retention"
+}
+
+// Run prunes until the context is cancelled. Failures are logged rather than
+// returned so they never mark the service unhealthy; a failed hour is
+// released and retried on the next tick.
+func (w *LogRetentionWorker) Run(ctx context.Context) error {
+	ticker := w.clock.NewTicker(logRetentionInterval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx, w.clock.Now().UTC())
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C():
+		}
+	}
+}
+
+func (w *LogRetentionWorker) runOnce(ctx context.Context, now time.Time) {
+	logger := w.logger.Ctx(ctx)
+	hour := now.Truncate(time.Hour).Format("2006-01-02T15")
+	key := w.claimKey(hour)
+
+	claimed, err := w.redisClient.SetNX(ctx, key, now.Format(time.RFC3339), logRetentionClaimTTL).Result()
+	if err != nil {
+		logger.Error("failed to claim log retention run", zap.String("hour", hour), zap.Error(err))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	release := func() {
+		if err := w.redisClient.Del(context.WithoutCancel(ctx), key).Err(); err != nil {
+			logger.Error("failed to release log retention run", zap.String("hour", hour), zap.Error(err))
+		}
+	}
+
+	req := w.policy.PruneRequest(now)
+	if w.archiver != nil {
+		archivedBefore, err := w.archiver.ArchivedBefore(ctx)
+		if err != nil {
+			logger.Error("failed to read log archive manifest", zap.String("hour", hour), zap.Error(err))
+			release()
+			return
+		}
+		req = clampPruneRequest(req, archivedBefore)
+	}
+	if w.holds != nil {
+		req, err = w.holds.Exempt(ctx, req)
+		if err != nil {
+			logger.Error("failed to read legal holds", zap.String("hour", hour), zap.Error(err))
+			release()
+			return
+		}
+	}
+
+	resp, err := w.pruner.Prune(ctx, req)
+	if err != nil {
+		logger.Error("failed to prune delivery logs", zap.String("hour", hour), zap.Error(err))
+		release()
+		return
+	}
+	logger.Info("delivery logs pruned",
+		zap.Stringer("retention_days", w.policy),
+		zap.Int64("attempts_deleted", resp.AttemptsDeleted),
+		zap.Int64("events_deleted", resp.EventsDeleted))
+}
+
+func (w *LogRetentionWorker) claimKey(hour string) string {
+	if w.deploymentID == "" {
+		return fmt.Sprintf("log_retention:%s", hour)
+	}
+	return fmt.Sprintf("%s:log_retention:%s", w.deploymentID, hour)
+}
+
+// clampPruneRequest moves every cutoff of req back to t, so that only logs
+// older than t are deleted.
+func clampPruneRequest(req logstore.PruneRequest, t time.Time) logstore.PruneRequest {
+	clamp := func(cutoff *time.Time) *time.Time {
+		if cutoff == nil || !cutoff.After(t) {
+			return cutoff
+		}
+		return &t
+	}
+	req.SuccessBefore = clamp(req.SuccessBefore)
+	req.FailedBefore = clamp(req.FailedBefore)
+	req.DeferredBefore = clamp(req.DeferredBefore)
+	return req
+}
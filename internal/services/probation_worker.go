@@ -0,0 +1,68 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/probation"
+	"github.com/hookdeck/outpost/internal/worker"
+	"go.uber.org/zap"
+)
+
+const probationInterval = time.Minute
+
+// ProbationWorker re-enables auto-disabled destinations whose probation is
+// over and whose test delivery succeeds. Failures are logged rather than
+// returned so they never mark the service unhealthy; the destinations stay on
+// probation and are tested on the next tick.
+type ProbationWorker struct {
+	reenabler *probation.Reenabler
+	logger    *logging.Logger
+}
+
+// NewProbationWorker creates a new probation worker.
+func NewProbationWorker(reenabler *probation.Reenabler, logger *logging.Logger) worker.Worker {
+	return &ProbationWorker{
+		reenabler: reenabler,
+		logger:    logger,
+	}
+}
+
+// Name returns the worker name.
+func (w *ProbationWorker) Name() string {
+	return "probation"
+}
+
+// Run tests destinations whose probation is over until the context is
+// cancelled.
+func (w *ProbationWorker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(probationInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+		w.runOnce(ctx)
+	}
+}
+
+func (w *ProbationWorker) runOnce(ctx context.Context) {
+	logger := w.logger.Ctx(ctx)
+	result, err := w.reenabler.Run(ctx)
+	if err != nil && ctx.Err() == nil {
+		logger.Error("failed to test destinations on probation",
+			zap.Int("reenabled", result.Reenabled),
+			zap.Int("failed", result.Failed),
+			zap.Error(err))
+		return
+	}
+	if result.Reenabled > 0 || result.Failed > 0 {
+		logger.Info("tested destinations on probation",
+			zap.Int("reenabled", result.Reenabled),
+			zap.Int("failed", result.Failed))
+	}
+}
@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/receipts"
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/worker"
+	"go.uber.org/zap"
+)
+
+const (
+	receiptsInterval = time.Hour
+	// receiptsClaimTTL keeps a day claimed long enough that no other replica
+	// writes its receipts again once they have been written.
+	receiptsClaimTTL = 48 * time.Hour
+)
+
+// ReceiptsWorker writes the previous UTC day's delivery receipts for every
+// tenant with receipt storage configured. It checks hourly; the first replica
+// to claim a day in Redis writes its receipts.
+type ReceiptsWorker struct {
+	generator    *receipts.Generator
+	redisClient  redis.Cmdable
+	deploymentID string
+	logger       *logging.Logger
+}
+
+// NewReceiptsWorker creates a new delivery receipts worker.
+func NewReceiptsWorker(generator *receipts.Generator, redisClient redis.Cmdable, deploymentID string, logger *logging.Logger) worker.Worker {
+	return &ReceiptsWorker{
+		generator:    generator,
+		redisClient:  redisClient,
+		deploymentID: deploymentID,
+		logger:       logger,
+	}
+}
+
+// Name returns the worker name.
+func (w *ReceiptsWorker) Name() string {
+	return "delivery-receipts"
+}
+
+// Run writes receipts until the context is cancelled. Failures are logged
+// rather than returned so they never mark the service unhealthy; a failed day
+// is released and retried on the next tick.
+func (w *ReceiptsWorker) Run(ctx context.Context) error {
+	ticker := time.NewTicker(receiptsInterval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx, time.Now().UTC().Add(-24*time.Hour))
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *ReceiptsWorker) runOnce(ctx context.Context, day time.Time) {
+	logger := w.logger.Ctx(ctx)
+	date := day.Format("2006-01-02")
+	key := w.claimKey(date)
+
+	claimed, err := w.redisClient.SetNX(ctx, key, time.Now().UTC().Format(time.RFC3339), receiptsClaimTTL).Result()
+	if err != nil {
+		logger.Error("failed to claim delivery receipts day", zap.String("date", date), zap.Error(err))
+		return
+	}
+	if !claimed {
+		return
+	}
+
+	written, err := w.generator.Run(ctx, day)
+	if err != nil {
+		logger.Error("failed to write delivery receipts",
+			zap.String("date", date),
+			zap.Int("written", written),
+			zap.Error(err))
+		if err := w.redisClient.Del(context.WithoutCancel(ctx), key).Err(); err != nil {
+			logger.Error("failed to release delivery receipts day", zap.String("date", date), zap.Error(err))
+		}
+		return
+	}
+	logger.Info("delivery receipts written", zap.String("date", date), zap.Int("written", written))
+}
+
+func (w *ReceiptsWorker) claimKey(date string) string {
+	if w.deploymentID == "" {
+		return fmt.Sprintf("receipts:%s", date)
+	}
+	return fmt.Sprintf("%s:receipts:%s", w.deploymentID, date)
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/redisstandby"
+	"github.com/hookdeck/outpost/internal/worker"
+	"go.uber.org/zap"
+)
+
+const (
+	redisStandbyReady    = "ready"
+	redisStandbyNotReady = "not_ready"
+	redisStandbyPending  = "pending"
+)
+
+// RedisStandbyWorker periodically checks that Redis is ready to fail over and
+// reports the last result in the health check. A failed check is logged but
+// never marks the service unhealthy, as restarting Outpost wouldn't fix the
+// standby.
+type RedisStandbyWorker struct {
+	checker  *redisstandby.Checker
+	interval time.Duration
+	logger   *logging.Logger
+	status   atomic.Pointer[redisstandby.Status]
+}
+
+var (
+	_ worker.Worker  = (*RedisStandbyWorker)(nil)
+	_ HealthReporter = (*RedisStandbyWorker)(nil)
+)
+
+// NewRedisStandbyWorker creates a new Redis failover readiness worker.
+func NewRedisStandbyWorker(checker *redisstandby.Checker, interval time.Duration, logger *logging.Logger) *RedisStandbyWorker {
+	return &RedisStandbyWorker{
+		checker:  checker,
+		interval: interval,
+		logger:   logger,
+	}
+}
+
+// Name returns the worker name.
+func (w *RedisStandbyWorker) Name() string {
+	return "redis-standby-check"
+}
+
+// Run checks Redis until the context is cancelled.
+func (w *RedisStandbyWorker) Run(ctx context.Context) error {
+	defer w.checker.Close()
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+
+	for {
+		w.runOnce(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *RedisStandbyWorker) runOnce(ctx context.Context) {
+	status := w.checker.Check(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	previous := w.status.Swap(&status)
+
+	logger := w.logger.Ctx(ctx)
+	switch {
+	case !status.Ready:
+		logger.Warn("redis is not ready to fail over",
+			zap.String("reason", status.Reason),
+			zap.Int64("lag_bytes", status.LagBytes),
+			zap.Int64("missing_keys", status.MissingKeys))
+	case previous != nil && !previous.Ready:
+		logger.Info("redis is ready to fail over again", zap.Int64("lag_bytes", status.LagBytes))
+	}
+}
+
+// HealthKey returns the key of the check in the health check response.
+func (w *RedisStandbyWorker) HealthKey() string {
+	return "redis_standby"
+}
+
+// HealthStatus returns the result of the last check, without its reason, as
+// the health check doesn't expose error details.
+func (w *RedisStandbyWorker) HealthStatus() any {
+	status := w.status.Load()
+	if status == nil {
+		return map[string]any{"status": redisStandbyPending}
+	}
+	result := redisStandbyNotReady
+	if status.Ready {
+		result = redisStandbyReady
+	}
+	return map[string]any{
+		"status":     result,
+		"checked_at": status.CheckedAt,
+	}
+}
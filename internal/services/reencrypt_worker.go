@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/worker"
+	"go.uber.org/zap"
+)
+
+// ReEncryptWorker re-encrypts destination secrets that are still encrypted
+// with a previous AES secret. It runs a single pass at startup and exits.
+type ReEncryptWorker struct {
+	reEncrypter tenantstore.CredentialReEncrypter
+	logger      *logging.Logger
+}
+
+// NewReEncryptWorker creates a new credential re-encryption worker.
+func NewReEncryptWorker(reEncrypter tenantstore.CredentialReEncrypter, logger *logging.Logger) worker.Worker {
+	return &ReEncryptWorker{
+		reEncrypter: reEncrypter,
+		logger:      logger,
+	}
+}
+
+// Name returns the worker name.
+func (w *ReEncryptWorker) Name() string {
+	return "credential-reencrypt"
+}
+
+// Run re-encrypts stored credentials once. Failures are logged rather than
+// returned: destinations stay readable through the previous secrets, so a
+// failed pass must not mark the service unhealthy.
+func (w *ReEncryptWorker) Run(ctx context.Context) error {
+	logger := w.logger.Ctx(ctx)
+	logger.Info("re-encrypting destination credentials with the current AES secret")
+
+	result, err := w.reEncrypter.ReEncryptCredentials(ctx, tenantstore.ReEncryptOptions{})
+	if err != nil {
+		logger.Error("credential re-encryption failed", zap.Error(err))
+		return nil
+	}
+
+	fields := []zap.Field{
+		zap.Int("scanned", result.Scanned),
+		zap.Int("reencrypted", result.ReEncrypted),
+		zap.Int("failed", result.Failed),
+	}
+	if result.Failed > 0 {
+		logger.Warn("credential re-encryption finished with undecryptable destinations", fields...)
+		return nil
+	}
+	logger.Info("credential re-encryption finished", fields...)
+	return nil
+}
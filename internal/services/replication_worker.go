@@ -0,0 +1,81 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/replication"
+	"github.com/hookdeck/outpost/internal/worker"
+	"go.uber.org/zap"
+)
+
+// ReplicationWorker replicates tenants and destinations from the primary
+// deployment. It applies changes back to back while the secondary is behind,
+// and polls the changefeed once caught up. Failures, such as the primary
+// being unreachable, are logged rather than returned so they never mark the
+// service unhealthy; replication resumes from the last change applied.
+type ReplicationWorker struct {
+	replicator *replication.Replicator
+	interval   time.Duration
+	logger     *logging.Logger
+}
+
+// NewReplicationWorker creates a new replication worker.
+func NewReplicationWorker(replicator *replication.Replicator, interval time.Duration, logger *logging.Logger) worker.Worker {
+	return &ReplicationWorker{
+		replicator: replicator,
+		interval:   interval,
+		logger:     logger,
+	}
+}
+
+// Name returns the worker name.
+func (w *ReplicationWorker) Name() string {
+	return "replication"
+}
+
+// Run replicates changes until the context is cancelled.
+func (w *ReplicationWorker) Run(ctx context.Context) error {
+	timer := time.NewTimer(0)
+	defer timer.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-timer.C:
+		}
+		if w.runOnce(ctx) {
+			timer.Reset(0)
+		} else {
+			timer.Reset(w.interval)
+		}
+	}
+}
+
+// runOnce applies a batch of changes, and reports whether more are pending.
+func (w *ReplicationWorker) runOnce(ctx context.Context) bool {
+	logger := w.logger.Ctx(ctx)
+	result, err := w.replicator.Sync(ctx)
+	fields := []zap.Field{
+		zap.Int("tenants", result.Tenants),
+		zap.Int("destinations", result.Destinations),
+	}
+	if errors.Is(err, replication.ErrFullSyncNotSupported) {
+		logger.Warn("replicating the changefeed without a full sync", zap.Error(err))
+		return true
+	}
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.Error("failed to replicate from the primary deployment", append(fields, zap.Error(err))...)
+		}
+		return false
+	}
+	if result.FullSync {
+		logger.Info("full sync from the primary deployment finished", fields...)
+		return true
+	}
+	return result.Pending
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/config"
+	"github.com/hookdeck/outpost/internal/version"
+)
+
+// Topology describes what this process runs. In split-service deployments
+// each pod reports only the services it was started with.
+type Topology struct {
+	DeploymentID string            `json:"deployment_id,omitempty"`
+	Version      string            `json:"version"`
+	ConfigHash   string            `json:"config_hash"`
+	Services     []TopologyService `json:"services"`
+	Backends     []TopologyBackend `json:"backends"`
+}
+
+// TopologyService is a service running in this process and the backends it
+// is connected to, by TopologyBackend name.
+type TopologyService struct {
+	Name     string   `json:"name"`
+	Version  string   `json:"version"`
+	Backends []string `json:"backends"`
+}
+
+// TopologyBackend is an infrastructure dependency. Address never includes
+// credentials.
+type TopologyBackend struct {
+	Name    string `json:"name"`
+	Type    string `json:"type"`
+	Address string `json:"address,omitempty"`
+}
+
+// Topology returns the topology of the services built so far.
+func (b *ServiceBuilder) Topology() *Topology {
+	topology := &Topology{
+		DeploymentID: b.cfg.DeploymentID,
+		Version:      version.Version(),
+		ConfigHash:   b.cfg.Hash(),
+		Services:     make([]TopologyService, 0, len(b.services)),
+		Backends:     []TopologyBackend{},
+	}
+	seen := map[string]bool{}
+	for _, svc := range b.services {
+		backends := svc.backends(b.cfg)
+		service := TopologyService{
+			Name:     svc.name,
+			Version:  topology.Version,
+			Backends: make([]string, 0, len(backends)),
+		}
+		for _, backend := range backends {
+			service.Backends = append(service.Backends, backend.Name)
+			if !seen[backend.Name] {
+				seen[backend.Name] = true
+				topology.Backends = append(topology.Backends, backend)
+			}
+		}
+		topology.Services = append(topology.Services, service)
+	}
+	return topology
+}
+
+// backends lists the infrastructure the service instance was initialized with.
+func (s *serviceInstance) backends(cfg *config.Config) []TopologyBackend {
+	var backends []TopologyBackend
+	if s.redisClient != nil {
+		redisType := "redis"
+		if cfg.Redis.ClusterEnabled {
+			redisType = "redis_cluster"
+		}
+		backends = append(backends, TopologyBackend{
+			Name:    "redis",
+			Type:    redisType,
+			Address: net.JoinHostPort(cfg.Redis.Host, strconv.Itoa(cfg.Redis.Port)),
+		})
+	}
+	if s.logStore != nil {
+		backends = append(backends, logStoreBackend(cfg))
+	}
+	if s.deliveryMQ != nil {
+		backends = append(backends, TopologyBackend{Name: "delivery_mq", Type: cfg.MQs.GetInfraType()})
+	}
+	if s.logMQ != nil {
+		backends = append(backends, TopologyBackend{Name: "log_mq", Type: cfg.MQs.GetInfraType()})
+	}
+	if s.publishMQ {
+		backends = append(backends, TopologyBackend{Name: "publish_mq", Type: cfg.PublishMQ.GetInfraType()})
+	}
+	return backends
+}
+
+func logStoreBackend(cfg *config.Config) TopologyBackend {
+	// ClickHouse takes precedence, as in logstore.NewLogStore.
+	if cfg.ClickHouse.Addr != "" {
+		return TopologyBackend{Name: "log_store", Type: "clickhouse", Address: cfg.ClickHouse.Addr}
+	}
+	backend := TopologyBackend{Name: "log_store", Type: "postgres"}
+	if u, err := url.Parse(cfg.PostgresURL); err == nil {
+		backend.Address = u.Host
+	}
+	return backend
+}
+
+// registerTopologyRoute mounts the topology handler on the base router, so
+// services without the API routes expose it too. The API key guards it as
+// it describes the infrastructure.
+func (b *ServiceBuilder) registerTopologyRoute(router gin.IRouter) {
+	router.GET("/api/v1/system/topology",
+		apirouter.AuthMiddleware(b.cfg.APIKey, "", nil, apirouter.AuthOptions{AdminOnly: true}),
+		TopologyHandler(b),
+	)
+}
+
+// TopologyHandler reports the topology of this process.
+func TopologyHandler(builder *ServiceBuilder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, builder.Topology())
+	}
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/config"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopologyRoute(t *testing.T) {
+	cfg := &config.Config{APIKey: "test-key", DeploymentID: "dp_test"}
+	b := NewServiceBuilder(context.Background(), cfg, testutil.CreateTestLogger(t), nil)
+	router := NewBaseRouter(b.supervisor, gin.TestMode)
+	b.registerTopologyRoute(router)
+
+	t.Run("requires the API key", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/system/topology", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("reports the topology", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/system/topology", nil)
+		req.Header.Set("Authorization", "Bearer test-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var topology Topology
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &topology))
+		assert.Equal(t, "dp_test", topology.DeploymentID)
+		assert.Equal(t, cfg.Hash(), topology.ConfigHash)
+		assert.Empty(t, topology.Services)
+	})
+
+	t.Run("is only served under the API prefix", func(t *testing.T) {
+		req := httptest.NewRequest(http.MethodGet, "/system/topology", nil)
+		req.Header.Set("Authorization", "Bearer test-key")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
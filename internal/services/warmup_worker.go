@@ -0,0 +1,60 @@
+package services
+
+import (
+	"context"
+
+	"github.com/hookdeck/outpost/internal/deliverywarmup"
+	"github.com/hookdeck/outpost/internal/destregistry"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+	"github.com/hookdeck/outpost/internal/worker"
+	"go.uber.org/zap"
+)
+
+// DeliveryWarmupWorker preloads the publishers of recently active tenants'
+// destinations into the registry's cache. It runs a single pass at startup
+// and exits.
+type DeliveryWarmupWorker struct {
+	activity    deliverywarmup.Activity
+	tenantStore tenantstore.TenantStore
+	registry    destregistry.Registry
+	limit       int
+	logger      *logging.Logger
+}
+
+// NewDeliveryWarmupWorker creates a new delivery warm-up worker.
+func NewDeliveryWarmupWorker(activity deliverywarmup.Activity, tenantStore tenantstore.TenantStore, registry destregistry.Registry, limit int, logger *logging.Logger) worker.Worker {
+	return &DeliveryWarmupWorker{
+		activity:    activity,
+		tenantStore: tenantStore,
+		registry:    registry,
+		limit:       limit,
+		logger:      logger,
+	}
+}
+
+// Name returns the worker name.
+func (w *DeliveryWarmupWorker) Name() string {
+	return "delivery-warmup"
+}
+
+// Run warms the publisher cache once. Failures are logged rather than
+// returned: an unwarmed cache only makes the first deliveries slower, so a
+// failed pass must not mark the service unhealthy.
+func (w *DeliveryWarmupWorker) Run(ctx context.Context) error {
+	logger := w.logger.Ctx(ctx)
+	logger.Info("warming delivery publishers of recently active tenants", zap.Int("limit", w.limit))
+
+	result, err := deliverywarmup.Warm(ctx, w.activity, w.tenantStore, w.registry, w.limit)
+	fields := []zap.Field{
+		zap.Int("tenants", result.Tenants),
+		zap.Int("publishers", result.Publishers),
+		zap.Int("failed", result.Failed),
+	}
+	if err != nil {
+		logger.Error("delivery warm-up failed", append(fields, zap.Error(err))...)
+		return nil
+	}
+	logger.Info("delivery warm-up finished", fields...)
+	return nil
+}
@@ -87,13 +87,9 @@ func matchJSONToSchema(input, schema any) bool {
 			inputValue, exists := inputMap[key]
 			if !exists {
 				// Handle $exist: false case
-				if subSchemaMap, ok := toMap(subSchema); ok {
-					if existVal, hasExist := subSchemaMap[OpExist]; hasExist {
-						if existBool, ok := existVal.(bool); ok && !existBool {
-							// $exist: false and key doesn't exist - this condition passes
-							continue
-						}
-					}
+				if expectsMissing(subSchema) {
+					// $exist: false and key doesn't exist - this condition passes
+					continue
 				}
 				// Key doesn't exist and no $exist: false - fail
 				return false
@@ -110,6 +106,23 @@ func matchJSONToSchema(input, schema any) bool {
 	return !recursivelyMatchValue(input, schema)
 }
 
+// expectsMissing reports whether the schema is satisfied by an absent field,
+// i.e. it carries $exist: false (or its $exists alias).
+func expectsMissing(schema any) bool {
+	schemaMap, ok := toMap(schema)
+	if !ok {
+		return false
+	}
+	for _, op := range []string{OpExist, OpExists} {
+		if existVal, hasExist := schemaMap[op]; hasExist {
+			if existBool, ok := existVal.(bool); ok && !existBool {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // recursivelyMatchValue checks if a value matches a schema pattern.
 // Returns true if there's a MISMATCH (inverted logic for internal use).
 func recursivelyMatchValue(input, schema any) bool {
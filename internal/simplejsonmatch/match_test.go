@@ -2,6 +2,7 @@ package simplejsonmatch
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 )
@@ -306,6 +307,44 @@ func TestMatchRefSkipped(t *testing.T) {
 	}
 }
 
+// TestMatchExtensions covers operators added on top of the original library:
+// $regex and the $exists alias of $exist.
+func TestMatchExtensions(t *testing.T) {
+	tests := []struct {
+		input    any
+		schema   any
+		expected bool
+	}{
+		{map[string]any{"test": "order_123"}, map[string]any{"test": map[string]any{"$regex": "^order_[0-9]+$"}}, true},
+		{map[string]any{"test": "order_abc"}, map[string]any{"test": map[string]any{"$regex": "^order_[0-9]+$"}}, false},
+		{map[string]any{"test": float64(1)}, map[string]any{"test": map[string]any{"$regex": "1"}}, false},
+		{map[string]any{"test": "else"}, map[string]any{"test": map[string]any{"$regex": "("}}, false},
+		{map[string]any{"test": "else"}, map[string]any{"test": map[string]any{"$exists": true}}, true},
+		{map[string]any{"test1": "else"}, map[string]any{"test": map[string]any{"$exists": false}}, true},
+		{map[string]any{"test1": "else"}, map[string]any{"test": map[string]any{"$exists": true}}, false},
+	}
+
+	for i, tt := range tests {
+		t.Run(fmt.Sprintf("extension_case_%d", i), func(t *testing.T) {
+			result := Match(tt.input, tt.schema)
+			if result != tt.expected {
+				inputJSON, _ := json.Marshal(tt.input)
+				schemaJSON, _ := json.Marshal(tt.schema)
+				t.Errorf("Match(%s, %s) = %v, want %v", inputJSON, schemaJSON, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Validate(map[string]any{"test": map[string]any{"$regex": "^a"}}); err != nil {
+		t.Errorf("Validate(valid $regex) = %v, want nil", err)
+	}
+	if err := Validate(map[string]any{"$or": []any{map[string]any{"test": map[string]any{"$regex": "("}}}}); !errors.Is(err, ErrInvalidRegex) {
+		t.Errorf("Validate(invalid $regex) = %v, want %v", err, ErrInvalidRegex)
+	}
+}
+
 // TestMatchNot tests $not operator cases from the original library.
 func TestMatchNot(t *testing.T) {
 	tests := []struct {
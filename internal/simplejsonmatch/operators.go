@@ -3,11 +3,14 @@ package simplejsonmatch
 import (
 	"encoding/json"
 	"errors"
+	"regexp"
 	"strings"
+	"sync"
 )
 
 var (
 	ErrUnsupportedType = errors.New("unsupported type for operator")
+	ErrInvalidRegex    = errors.New("invalid $regex pattern")
 )
 
 // opEq implements the $eq operator - deep equality check.
@@ -269,6 +272,52 @@ func opExist(v any, compare any) (bool, error) {
 	return isUndefined, nil
 }
 
+// maxCachedRegexes bounds regexCache; when full it is cleared and refilled.
+const maxCachedRegexes = 1024
+
+// regexCache holds compiled $regex patterns. Filters are evaluated for every
+// published event, so a pattern is compiled once and reused.
+var regexCache = struct {
+	sync.Mutex
+	patterns map[string]*regexp.Regexp
+}{patterns: make(map[string]*regexp.Regexp)}
+
+// compileRegex returns the compiled form of a $regex pattern. Patterns use Go
+// RE2 syntax.
+func compileRegex(pattern string) (*regexp.Regexp, error) {
+	regexCache.Lock()
+	defer regexCache.Unlock()
+	if re, ok := regexCache.patterns[pattern]; ok {
+		return re, nil
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return nil, ErrInvalidRegex
+	}
+	if len(regexCache.patterns) >= maxCachedRegexes {
+		clear(regexCache.patterns)
+	}
+	regexCache.patterns[pattern] = re
+	return re, nil
+}
+
+// opRegex implements the $regex operator - regular expression matching.
+func opRegex(v, compare any) (bool, error) {
+	vStr, ok := v.(string)
+	if !ok {
+		return false, ErrUnsupportedType
+	}
+	pattern, ok := compare.(string)
+	if !ok {
+		return false, ErrUnsupportedType
+	}
+	re, err := compileRegex(pattern)
+	if err != nil {
+		return false, err
+	}
+	return re.MatchString(vStr), nil
+}
+
 // undefinedType is a special marker type for undefined/missing fields.
 type undefinedType struct{}
 
@@ -298,8 +347,10 @@ func applyOperator(op string, v, compare any) (bool, error) {
 		return opStartsWith(v, compare)
 	case OpEndsWith:
 		return opEndsWith(v, compare)
-	case OpExist:
+	case OpExist, OpExists:
 		return opExist(v, compare)
+	case OpRegex:
+		return opRegex(v, compare)
 	default:
 		return false, errors.New("unknown operator: " + op)
 	}
@@ -29,6 +29,8 @@ const (
 	OpStartsWith = "$startsWith"
 	OpEndsWith   = "$endsWith"
 	OpExist      = "$exist"
+	OpExists     = "$exists" // alias of $exist
+	OpRegex      = "$regex"
 	OpOr         = "$or"
 	OpAnd        = "$and"
 	OpNot        = "$not"
@@ -48,6 +50,8 @@ var operatorKeys = map[string]bool{
 	OpStartsWith: true,
 	OpEndsWith:   true,
 	OpExist:      true,
+	OpExists:     true,
+	OpRegex:      true,
 }
 
 // isOperatorKey returns true if the key is a recognized operator.
@@ -0,0 +1,34 @@
+package simplejsonmatch
+
+// Validate checks that a schema can be evaluated: every $regex operand must be
+// a string holding a valid pattern. Match treats an invalid pattern as a
+// mismatch, so validating up front surfaces the mistake instead of silently
+// matching nothing.
+func Validate(schema any) error {
+	if schemaMap, ok := toMap(schema); ok {
+		for key, value := range schemaMap {
+			if key == OpRegex {
+				pattern, ok := value.(string)
+				if !ok {
+					return ErrInvalidRegex
+				}
+				if _, err := compileRegex(pattern); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := Validate(value); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	if schemaSlice, ok := toSlice(schema); ok {
+		for _, item := range schemaSlice {
+			if err := Validate(item); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -4,6 +4,7 @@ package driver
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/hookdeck/outpost/internal/models"
 )
@@ -23,17 +24,73 @@ type TenantStore interface {
 	MatchEvent(ctx context.Context, event models.Event) ([]string, error)
 }
 
+// CredentialReEncrypter is implemented by stores that encrypt destination
+// secrets at rest and can rewrite them under the current key after the
+// encryption secret is rotated.
+type CredentialReEncrypter interface {
+	ReEncryptCredentials(ctx context.Context, opts ReEncryptOptions) (*ReEncryptResult, error)
+}
+
+// DestinationVersioner is implemented by stores that keep a history of
+// destination changes. Stores retain a bounded number of recent versions per
+// destination.
+type DestinationVersioner interface {
+	// CreateDestinationVersion records destination as its next version.
+	CreateDestinationVersion(ctx context.Context, destination models.Destination, actor string) (*DestinationVersion, error)
+	// ListDestinationVersion returns the retained versions, newest first.
+	ListDestinationVersion(ctx context.Context, tenantID, destinationID string) ([]DestinationVersion, error)
+	RetrieveDestinationVersion(ctx context.Context, tenantID, destinationID string, version int) (*DestinationVersion, error)
+}
+
+// TenantSuspender is implemented by stores that can move a dormant tenant's
+// data out of the store into cold storage. A suspended tenant is resumed
+// transparently the next time it or its destinations are read or written.
+type TenantSuspender interface {
+	SuspendTenant(ctx context.Context, tenantID string) error
+}
+
+// ColdStore is the object storage suspended tenants are written to.
+// Satisfied by logarchive.S3Store.
+type ColdStore interface {
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+}
+
+// DestinationVersion is a destination as saved by one change, credentials
+// included.
+type DestinationVersion struct {
+	Version     int
+	Destination models.Destination
+	Actor       string // who made the change, e.g. the role of the API caller
+	CreatedAt   time.Time
+}
+
+// ReEncryptOptions configures a re-encryption pass.
+type ReEncryptOptions struct {
+	DryRun bool // only count records that need re-encryption
+}
+
+// ReEncryptResult summarizes a re-encryption pass.
+type ReEncryptResult struct {
+	Scanned     int // destinations and destination versions inspected
+	ReEncrypted int // records rewritten (or that would be, on a dry run)
+	Failed      int // records that could not be decrypted with any key
+}
+
 var (
 	ErrTenantNotFound                  = errors.New("tenant does not exist")
 	ErrTenantDeleted                   = errors.New("tenant has been deleted")
 	ErrDuplicateDestination            = errors.New("destination already exists")
 	ErrDestinationNotFound             = errors.New("destination does not exist")
 	ErrDestinationDeleted              = errors.New("destination has been deleted")
+	ErrDestinationVersionNotFound      = errors.New("destination version does not exist")
 	ErrMaxDestinationsPerTenantReached = errors.New("maximum number of destinations per tenant reached")
 	ErrListTenantNotSupported          = errors.New("list tenant feature is not enabled")
 	ErrInvalidCursor                   = errors.New("invalid cursor")
 	ErrInvalidOrder                    = errors.New("invalid order: must be 'asc' or 'desc'")
 	ErrConflictingCursors              = errors.New("cannot specify both next and prev cursors")
+	ErrColdStorageNotConfigured        = errors.New("tenant cold storage is not configured")
+	ErrTenantModified                  = errors.New("tenant was modified while being suspended")
 )
 
 // ListTenantRequest contains parameters for listing tenants.
@@ -54,7 +111,8 @@ type SeekPagination struct {
 	Prev    *string `json:"prev"`
 }
 
-// TenantPaginatedResult contains the paginated list of tenants.
+// TenantPaginatedResult contains the paginated list of tenants. Count is the
+// total number of tenants matching the request, across all pages.
 type TenantPaginatedResult struct {
 	Models     []models.Tenant `json:"models"`
 	Pagination SeekPagination  `json:"pagination"`
@@ -144,6 +144,176 @@ func testCRUD(t *testing.T, newHarness HarnessMaker) {
 			assert.Nil(t, retrieved.Metadata)
 		})
 
+		t.Run("persists sandbox flag", func(t *testing.T) {
+			input.Sandbox = true
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err := store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.True(t, retrieved.Sandbox)
+
+			input.Sandbox = false
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err = store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.False(t, retrieved.Sandbox)
+		})
+
+		t.Run("persists receipt storage", func(t *testing.T) {
+			input.ReceiptStorage = &models.ReceiptStorage{Bucket: "receipts", Region: "us-east-1", Prefix: "outpost/"}
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err := store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Equal(t, input.ReceiptStorage, retrieved.ReceiptStorage)
+
+			input.ReceiptStorage = nil
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err = store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Nil(t, retrieved.ReceiptStorage)
+		})
+
+		t.Run("persists notification preferences", func(t *testing.T) {
+			input.Notifications = &models.NotificationPreferences{
+				Email:      "ops@example.com",
+				Categories: []string{models.NotificationCategoryFailures},
+			}
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err := store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Equal(t, input.Notifications, retrieved.Notifications)
+
+			input.Notifications = nil
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err = store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Nil(t, retrieved.Notifications)
+		})
+
+		t.Run("persists lifecycle callback", func(t *testing.T) {
+			input.LifecycleCallback = &models.LifecycleCallback{URL: "https://producer.example.com/lifecycle"}
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err := store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Equal(t, input.LifecycleCallback, retrieved.LifecycleCallback)
+
+			input.LifecycleCallback = nil
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err = store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Nil(t, retrieved.LifecycleCallback)
+		})
+
+		t.Run("persists alert channels", func(t *testing.T) {
+			input.AlertChannels = &models.AlertChannels{
+				SlackWebhookURL:     "https://hooks.slack.com/services/T000/B000/XXXX",
+				PagerDutyRoutingKey: "R0UT1NGK3Y",
+			}
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err := store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Equal(t, input.AlertChannels, retrieved.AlertChannels)
+
+			input.AlertChannels = nil
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err = store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Nil(t, retrieved.AlertChannels)
+		})
+
+		t.Run("persists auto-disable policy", func(t *testing.T) {
+			failureCount, autoDisable := 20, true
+			input.AutoDisablePolicy = &models.AutoDisablePolicy{
+				FailureCount: &failureCount,
+				AutoDisable:  &autoDisable,
+			}
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err := store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Equal(t, input.AutoDisablePolicy, retrieved.AutoDisablePolicy)
+
+			input.AutoDisablePolicy = nil
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err = store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Nil(t, retrieved.AutoDisablePolicy)
+		})
+
+		t.Run("persists destination types", func(t *testing.T) {
+			input.DestinationTypes = []string{"webhook", "aws_sqs"}
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err := store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Equal(t, input.DestinationTypes, retrieved.DestinationTypes)
+
+			input.DestinationTypes = nil
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err = store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Empty(t, retrieved.DestinationTypes)
+		})
+
+		t.Run("persists portal allowed IPs", func(t *testing.T) {
+			input.PortalAllowedIPs = []string{"10.0.0.0/8", "203.0.113.7"}
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err := store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Equal(t, input.PortalAllowedIPs, retrieved.PortalAllowedIPs)
+
+			input.PortalAllowedIPs = nil
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err = store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Empty(t, retrieved.PortalAllowedIPs)
+		})
+
+		t.Run("persists webhook endpoint verification", func(t *testing.T) {
+			input.VerifyWebhookEndpoints = true
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err := store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.True(t, retrieved.VerifyWebhookEndpoints)
+
+			input.VerifyWebhookEndpoints = false
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err = store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.False(t, retrieved.VerifyWebhookEndpoints)
+		})
+
+		t.Run("persists publish rate limit", func(t *testing.T) {
+			input.PublishRateLimit = &models.PublishRateLimit{PerSecond: 50, Burst: 100}
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err := store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Equal(t, input.PublishRateLimit, retrieved.PublishRateLimit)
+
+			input.PublishRateLimit = nil
+			require.NoError(t, store.UpsertTenant(ctx, input))
+
+			retrieved, err = store.RetrieveTenant(ctx, input.ID)
+			require.NoError(t, err)
+			assert.Nil(t, retrieved.PublishRateLimit)
+		})
+
 		t.Run("sets updated_at on create", func(t *testing.T) {
 			newTenant := testutil.TenantFactory.Any()
 			err := store.UpsertTenant(ctx, newTenant)
@@ -509,6 +679,168 @@ func testCRUD(t *testing.T, newHarness HarnessMaker) {
 			assert.Nil(t, retrieved.Filter)
 		})
 	})
+
+	t.Run("RetryPolicyPersistence", func(t *testing.T) {
+		ctx := context.Background()
+		h, err := newHarness(ctx, t)
+		require.NoError(t, err)
+		t.Cleanup(h.Close)
+
+		store, err := h.MakeDriver(ctx)
+		require.NoError(t, err)
+
+		tenant := models.Tenant{ID: idgen.String()}
+		require.NoError(t, store.UpsertTenant(ctx, tenant))
+
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithTenantID(tenant.ID),
+			testutil.DestinationFactory.WithTopics([]string{"*"}),
+		)
+		destination.RetryPolicy = &models.RetryPolicy{MaxAttempts: 3, Schedule: []int{5, 60}}
+		require.NoError(t, store.CreateDestination(ctx, destination))
+
+		retrieved, err := store.RetrieveDestination(ctx, tenant.ID, destination.ID)
+		require.NoError(t, err)
+		assert.Equal(t, destination.RetryPolicy, retrieved.RetryPolicy)
+
+		destination.RetryPolicy = nil
+		require.NoError(t, store.UpsertDestination(ctx, destination))
+
+		retrieved, err = store.RetrieveDestination(ctx, tenant.ID, destination.ID)
+		require.NoError(t, err)
+		assert.Nil(t, retrieved.RetryPolicy)
+	})
+
+	t.Run("TransformationPersistence", func(t *testing.T) {
+		ctx := context.Background()
+		h, err := newHarness(ctx, t)
+		require.NoError(t, err)
+		t.Cleanup(h.Close)
+
+		store, err := h.MakeDriver(ctx)
+		require.NoError(t, err)
+
+		tenant := models.Tenant{ID: idgen.String()}
+		require.NoError(t, store.UpsertTenant(ctx, tenant))
+
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithTenantID(tenant.ID),
+			testutil.DestinationFactory.WithTopics([]string{"*"}),
+		)
+		destination.Transformation = &models.Transformation{Type: models.TransformationJQ, Expression: "{id: $id}"}
+		require.NoError(t, store.CreateDestination(ctx, destination))
+
+		retrieved, err := store.RetrieveDestination(ctx, tenant.ID, destination.ID)
+		require.NoError(t, err)
+		assert.Equal(t, destination.Transformation, retrieved.Transformation)
+
+		destination.Transformation = nil
+		require.NoError(t, store.UpsertDestination(ctx, destination))
+
+		retrieved, err = store.RetrieveDestination(ctx, tenant.ID, destination.ID)
+		require.NoError(t, err)
+		assert.Nil(t, retrieved.Transformation)
+	})
+
+	t.Run("MaxPayloadBytesPersistence", func(t *testing.T) {
+		ctx := context.Background()
+		h, err := newHarness(ctx, t)
+		require.NoError(t, err)
+		t.Cleanup(h.Close)
+
+		store, err := h.MakeDriver(ctx)
+		require.NoError(t, err)
+
+		tenant := models.Tenant{ID: idgen.String()}
+		require.NoError(t, store.UpsertTenant(ctx, tenant))
+
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithTenantID(tenant.ID),
+			testutil.DestinationFactory.WithTopics([]string{"*"}),
+		)
+		destination.MaxPayloadBytes = 256 * 1024
+		require.NoError(t, store.CreateDestination(ctx, destination))
+
+		retrieved, err := store.RetrieveDestination(ctx, tenant.ID, destination.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 256*1024, retrieved.MaxPayloadBytes)
+
+		destination.MaxPayloadBytes = 0
+		require.NoError(t, store.UpsertDestination(ctx, destination))
+
+		retrieved, err = store.RetrieveDestination(ctx, tenant.ID, destination.ID)
+		require.NoError(t, err)
+		assert.Zero(t, retrieved.MaxPayloadBytes)
+	})
+
+	t.Run("DeliveryCapsPersistence", func(t *testing.T) {
+		ctx := context.Background()
+		h, err := newHarness(ctx, t)
+		require.NoError(t, err)
+		t.Cleanup(h.Close)
+
+		store, err := h.MakeDriver(ctx)
+		require.NoError(t, err)
+
+		tenant := models.Tenant{ID: idgen.String()}
+		require.NoError(t, store.UpsertTenant(ctx, tenant))
+
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithTenantID(tenant.ID),
+			testutil.DestinationFactory.WithTopics([]string{"*"}),
+		)
+		destination.MaxConcurrency = 4
+		destination.MaxDeliveriesPerSecond = 20
+		require.NoError(t, store.CreateDestination(ctx, destination))
+
+		retrieved, err := store.RetrieveDestination(ctx, tenant.ID, destination.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 4, retrieved.MaxConcurrency)
+		assert.Equal(t, 20, retrieved.MaxDeliveriesPerSecond)
+
+		destination.MaxConcurrency = 0
+		destination.MaxDeliveriesPerSecond = 0
+		require.NoError(t, store.UpsertDestination(ctx, destination))
+
+		retrieved, err = store.RetrieveDestination(ctx, tenant.ID, destination.ID)
+		require.NoError(t, err)
+		assert.Zero(t, retrieved.MaxConcurrency)
+		assert.Zero(t, retrieved.MaxDeliveriesPerSecond)
+	})
+
+	t.Run("TargetChangePersistence", func(t *testing.T) {
+		ctx := context.Background()
+		h, err := newHarness(ctx, t)
+		require.NoError(t, err)
+		t.Cleanup(h.Close)
+
+		store, err := h.MakeDriver(ctx)
+		require.NoError(t, err)
+
+		tenant := models.Tenant{ID: idgen.String()}
+		require.NoError(t, store.UpsertTenant(ctx, tenant))
+
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithTenantID(tenant.ID),
+			testutil.DestinationFactory.WithTopics([]string{"*"}),
+		)
+		changedAt := time.Now()
+		destination.TargetChange = &models.TargetChange{ChangedAt: changedAt, QueuedMessages: models.QueuedMessagesHold}
+		require.NoError(t, store.CreateDestination(ctx, destination))
+
+		retrieved, err := store.RetrieveDestination(ctx, tenant.ID, destination.ID)
+		require.NoError(t, err)
+		require.NotNil(t, retrieved.TargetChange)
+		assert.Equal(t, models.QueuedMessagesHold, retrieved.TargetChange.QueuedMessages)
+		assert.True(t, changedAt.Equal(retrieved.TargetChange.ChangedAt))
+
+		destination.TargetChange = nil
+		require.NoError(t, store.UpsertDestination(ctx, destination))
+
+		retrieved, err = store.RetrieveDestination(ctx, tenant.ID, destination.ID)
+		require.NoError(t, err)
+		assert.Nil(t, retrieved.TargetChange)
+	})
 }
 
 // assertEqualTime compares two times by truncating to millisecond precision.
@@ -28,7 +28,7 @@ type HarnessMaker func(ctx context.Context, t *testing.T) (Harness, error)
 //   - CRUD: tenant and destination create/read/update/delete
 //   - List: destination listing and filtering operations
 //   - Match: event matching operations
-//   - Misc: max destinations, deployment isolation
+//   - Misc: max destinations, deployment isolation, destination versions
 func RunConformanceTests(t *testing.T, newHarness HarnessMaker) {
 	t.Helper()
 
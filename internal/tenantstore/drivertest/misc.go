@@ -2,6 +2,7 @@ package drivertest
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -126,4 +127,82 @@ func testMisc(t *testing.T, newHarness HarnessMaker) {
 		require.NoError(t, err)
 		assert.Equal(t, "dp_002", retrieved2Again.Config["deployment"])
 	})
+	t.Run("DestinationVersions", func(t *testing.T) {
+		ctx := context.Background()
+		h, err := newHarness(ctx, t)
+		require.NoError(t, err)
+		t.Cleanup(h.Close)
+
+		store, err := h.MakeDriver(ctx)
+		require.NoError(t, err)
+		versioner, ok := store.(driver.DestinationVersioner)
+		if !ok {
+			t.Skip("driver does not keep destination versions")
+		}
+
+		tenantID := idgen.String()
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithTenantID(tenantID),
+			testutil.DestinationFactory.WithConfig(map[string]string{"url": "https://example.com/v1"}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{"secret": "secret_v1"}),
+		)
+
+		// Version history starts empty.
+		versions, err := versioner.ListDestinationVersion(ctx, tenantID, destination.ID)
+		require.NoError(t, err)
+		assert.Empty(t, versions)
+
+		for i := 1; i <= 3; i++ {
+			destination.Config = map[string]string{"url": fmt.Sprintf("https://example.com/v%d", i)}
+			destination.Credentials = map[string]string{"secret": fmt.Sprintf("secret_v%d", i)}
+			version, err := versioner.CreateDestinationVersion(ctx, destination, "tenant")
+			require.NoError(t, err)
+			assert.Equal(t, i, version.Version)
+		}
+
+		versions, err = versioner.ListDestinationVersion(ctx, tenantID, destination.ID)
+		require.NoError(t, err)
+		require.Len(t, versions, 3)
+		assert.Equal(t, []int{3, 2, 1}, []int{versions[0].Version, versions[1].Version, versions[2].Version})
+		assert.Equal(t, "https://example.com/v3", versions[0].Destination.Config["url"])
+
+		version, err := versioner.RetrieveDestinationVersion(ctx, tenantID, destination.ID, 2)
+		require.NoError(t, err)
+		assert.Equal(t, 2, version.Version)
+		assert.Equal(t, "tenant", version.Actor)
+		assert.False(t, version.CreatedAt.IsZero())
+		assert.Equal(t, destination.ID, version.Destination.ID)
+		assert.Equal(t, tenantID, version.Destination.TenantID)
+		assert.Equal(t, "https://example.com/v2", version.Destination.Config["url"])
+		assert.Equal(t, "secret_v2", version.Destination.Credentials["secret"], "versions keep credentials for rollback")
+
+		_, err = versioner.RetrieveDestinationVersion(ctx, tenantID, destination.ID, 4)
+		require.ErrorIs(t, err, driver.ErrDestinationVersionNotFound)
+	})
+	t.Run("DeleteTenantRemovesDestinationVersions", func(t *testing.T) {
+		ctx := context.Background()
+		h, err := newHarness(ctx, t)
+		require.NoError(t, err)
+		t.Cleanup(h.Close)
+
+		store, err := h.MakeDriver(ctx)
+		require.NoError(t, err)
+		versioner, ok := store.(driver.DestinationVersioner)
+		if !ok {
+			t.Skip("driver does not keep destination versions")
+		}
+
+		tenant := models.Tenant{ID: idgen.String(), CreatedAt: time.Now()}
+		require.NoError(t, store.UpsertTenant(ctx, tenant))
+		destination := testutil.DestinationFactory.Any(testutil.DestinationFactory.WithTenantID(tenant.ID))
+		require.NoError(t, store.UpsertDestination(ctx, destination))
+		_, err = versioner.CreateDestinationVersion(ctx, destination, "tenant")
+		require.NoError(t, err)
+
+		require.NoError(t, store.DeleteTenant(ctx, tenant.ID))
+
+		versions, err := versioner.ListDestinationVersion(ctx, tenant.ID, destination.ID)
+		require.NoError(t, err)
+		assert.Empty(t, versions)
+	})
 }
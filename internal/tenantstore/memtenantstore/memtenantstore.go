@@ -18,6 +18,9 @@ import (
 
 const defaultMaxDestinationsPerTenant = 20
 
+// maxDestinationVersions is the number of versions retained per destination.
+const maxDestinationVersions = 20
+
 const (
 	defaultListTenantLimit = 20
 	maxListTenantLimit     = 100
@@ -36,14 +39,18 @@ type destinationRecord struct {
 type store struct {
 	mu sync.RWMutex
 
-	tenants       map[string]*tenantRecord       // tenantID -> record
-	destinations  map[string]*destinationRecord  // "tenantID\x00destID" -> record
-	destsByTenant map[string]map[string]struct{} // tenantID -> set of destIDs
+	tenants       map[string]*tenantRecord               // tenantID -> record
+	destinations  map[string]*destinationRecord          // "tenantID\x00destID" -> record
+	destsByTenant map[string]map[string]struct{}         // tenantID -> set of destIDs
+	versions      map[string][]driver.DestinationVersion // "tenantID\x00destID" -> versions, oldest first
 
 	maxDestinationsPerTenant int
 }
 
-var _ driver.TenantStore = (*store)(nil)
+var (
+	_ driver.TenantStore          = (*store)(nil)
+	_ driver.DestinationVersioner = (*store)(nil)
+)
 
 // Option configures a memtenantstore.
 type Option func(*store)
@@ -61,6 +68,7 @@ func New(opts ...Option) driver.TenantStore {
 		tenants:                  make(map[string]*tenantRecord),
 		destinations:             make(map[string]*destinationRecord),
 		destsByTenant:            make(map[string]map[string]struct{}),
+		versions:                 make(map[string][]driver.DestinationVersion),
 		maxDestinationsPerTenant: defaultMaxDestinationsPerTenant,
 	}
 	for _, opt := range opts {
@@ -413,6 +421,50 @@ func (s *store) DeleteDestination(_ context.Context, tenantID, destinationID str
 	return nil
 }
 
+func (s *store) CreateDestinationVersion(_ context.Context, destination models.Destination, actor string) (*driver.DestinationVersion, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := destKey(destination.TenantID, destination.ID)
+	versions := s.versions[key]
+	version := driver.DestinationVersion{
+		Version:     1,
+		Destination: destination,
+		Actor:       actor,
+		CreatedAt:   time.Now(),
+	}
+	if len(versions) > 0 {
+		version.Version = versions[len(versions)-1].Version + 1
+	}
+	versions = append(versions, version)
+	if len(versions) > maxDestinationVersions {
+		versions = versions[len(versions)-maxDestinationVersions:]
+	}
+	s.versions[key] = versions
+	return &version, nil
+}
+
+func (s *store) ListDestinationVersion(_ context.Context, tenantID, destinationID string) ([]driver.DestinationVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	versions := slices.Clone(s.versions[destKey(tenantID, destinationID)])
+	slices.Reverse(versions)
+	return versions, nil
+}
+
+func (s *store) RetrieveDestinationVersion(_ context.Context, tenantID, destinationID string, version int) (*driver.DestinationVersion, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, v := range s.versions[destKey(tenantID, destinationID)] {
+		if v.Version == version {
+			return &v, nil
+		}
+	}
+	return nil, driver.ErrDestinationVersionNotFound
+}
+
 func (s *store) MatchEvent(_ context.Context, event models.Event) ([]string, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
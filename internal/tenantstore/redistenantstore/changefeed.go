@@ -0,0 +1,102 @@
+package redistenantstore
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hookdeck/outpost/internal/redis"
+)
+
+// The changefeed is a Redis stream recording which tenants and destinations
+// changed, for secondary deployments to replicate them. Entries only identify
+// the entity: readers fetch its current state, so entries can be applied more
+// than once and out of order.
+//
+// Writes are recorded after they are applied, outside of their transaction,
+// as the stream doesn't share the entity's hash slot in cluster mode.
+// Suspending and resuming tenants, re-encrypting credentials and destination
+// versions leave the entities unchanged and aren't recorded.
+
+// Entities and operations of changefeed entries.
+const (
+	ChangeEntityTenant      = "tenant"
+	ChangeEntityDestination = "destination"
+
+	ChangeOpUpsert = "upsert"
+	ChangeOpDelete = "delete"
+)
+
+// Change is an entry of the changefeed.
+type Change struct {
+	// ID is the stream entry ID.
+	ID            string
+	Entity        string
+	Op            string
+	TenantID      string
+	DestinationID string
+}
+
+// ChangefeedKey returns the key of the changefeed of a deployment.
+func ChangefeedKey(deploymentID string) string {
+	if deploymentID == "" {
+		return "entity_changes"
+	}
+	return fmt.Sprintf("%s:entity_changes", deploymentID)
+}
+
+// ParseChange parses a changefeed entry.
+func ParseChange(msg redis.XMessage) (Change, error) {
+	change := Change{ID: msg.ID}
+	for field, dst := range map[string]*string{
+		"entity":         &change.Entity,
+		"op":             &change.Op,
+		"tenant_id":      &change.TenantID,
+		"destination_id": &change.DestinationID,
+	} {
+		if v, ok := msg.Values[field].(string); ok {
+			*dst = v
+		}
+	}
+	if change.Entity != ChangeEntityTenant && change.Entity != ChangeEntityDestination {
+		return Change{}, fmt.Errorf("invalid change %s: unknown entity %q", msg.ID, change.Entity)
+	}
+	if change.Op != ChangeOpUpsert && change.Op != ChangeOpDelete {
+		return Change{}, fmt.Errorf("invalid change %s: unknown op %q", msg.ID, change.Op)
+	}
+	if change.TenantID == "" || (change.Entity == ChangeEntityDestination && change.DestinationID == "") {
+		return Change{}, fmt.Errorf("invalid change %s: missing entity ID", msg.ID)
+	}
+	return change, nil
+}
+
+// WithChangefeed records tenant and destination changes in the changefeed,
+// keeping about maxLen entries.
+func WithChangefeed(maxLen int64) Option {
+	return func(s *store) {
+		s.changefeedMaxLen = maxLen
+	}
+}
+
+func (s *store) recordChange(ctx context.Context, entity, op, tenantID, destinationID string) error {
+	if s.changefeedMaxLen <= 0 {
+		return nil
+	}
+	values := map[string]interface{}{
+		"entity":    entity,
+		"op":        op,
+		"tenant_id": tenantID,
+	}
+	if destinationID != "" {
+		values["destination_id"] = destinationID
+	}
+	err := s.redisClient.XAdd(ctx, &redis.XAddArgs{
+		Stream: ChangefeedKey(s.deploymentID),
+		MaxLen: s.changefeedMaxLen,
+		Approx: true,
+		Values: values,
+	}).Err()
+	if err != nil {
+		return fmt.Errorf("failed to record %s change: %w", entity, err)
+	}
+	return nil
+}
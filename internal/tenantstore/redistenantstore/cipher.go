@@ -6,11 +6,40 @@ import (
 	"crypto/md5"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"io"
+	"sort"
 )
 
+// aesCipher encrypts with the primary key and decrypts with any known key.
+//
+// Keys come from two places. The key ring holds named keys, one of which is
+// the primary; records store the ID of the key they were written with so
+// reads go straight to it. The legacy secret (and the secrets it replaced)
+// have no ID: they are used for writes only when no key ring is configured,
+// and remain available for reads so values written before a key ring or a
+// rotation stay readable until they are re-encrypted.
 type aesCipher struct {
-	secret string
+	secret          string
+	previousSecrets []string
+	keys            map[string]string
+	primaryID       string
+}
+
+// primaryKeyID returns the ID of the key new values are encrypted with, or ""
+// for the legacy secret.
+func (a *aesCipher) primaryKeyID() string {
+	if _, ok := a.keys[a.primaryID]; ok {
+		return a.primaryID
+	}
+	return ""
+}
+
+func (a *aesCipher) primarySecret() string {
+	if id := a.primaryKeyID(); id != "" {
+		return a.keys[id]
+	}
+	return a.secret
 }
 
 func (a *aesCipher) encrypt(toBeEncrypted []byte) ([]byte, error) {
@@ -29,13 +58,79 @@ func (a *aesCipher) encrypt(toBeEncrypted []byte) ([]byte, error) {
 	return encrypted, nil
 }
 
-func (a *aesCipher) decrypt(toBeDecrypted []byte) ([]byte, error) {
-	aead, err := a.aead()
+// decrypt decrypts a value written with the key identified by keyID ("" when
+// the record predates key IDs).
+func (a *aesCipher) decrypt(toBeDecrypted []byte, keyID string) ([]byte, error) {
+	decrypted, _, err := a.decryptWithFallback(toBeDecrypted, keyID)
+	return decrypted, err
+}
+
+// decryptWithFallback tries the key identified by keyID first, then the
+// primary key, the rest of the key ring, the legacy secret and finally the
+// previous secrets. It reports whether the value is already encrypted with
+// the primary key under the primary key ID, i.e. needs no re-encryption.
+func (a *aesCipher) decryptWithFallback(toBeDecrypted []byte, keyID string) ([]byte, bool, error) {
+	primaryID := a.primaryKeyID()
+	primary := a.primarySecret()
+
+	var firstErr error
+	for _, secret := range a.candidateSecrets(keyID) {
+		decrypted, err := decryptWithSecret(secret, toBeDecrypted)
+		if err == nil {
+			return decrypted, secret == primary && keyID == primaryID, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, false, firstErr
+}
+
+func (a *aesCipher) candidateSecrets(keyID string) []string {
+	candidates := make([]string, 0, len(a.keys)+len(a.previousSecrets)+2)
+	if secret, ok := a.keys[keyID]; ok && keyID != "" {
+		candidates = append(candidates, secret)
+	}
+	candidates = append(candidates, a.primarySecret())
+
+	ids := make([]string, 0, len(a.keys))
+	for id := range a.keys {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		candidates = append(candidates, a.keys[id])
+	}
+	candidates = append(candidates, a.secret)
+	candidates = append(candidates, a.previousSecrets...)
+
+	// Drop duplicates while keeping the order.
+	seen := make(map[string]bool, len(candidates))
+	unique := candidates[:0]
+	for _, secret := range candidates {
+		if seen[secret] {
+			continue
+		}
+		seen[secret] = true
+		unique = append(unique, secret)
+	}
+	return unique
+}
+
+func (a *aesCipher) aead() (cipher.AEAD, error) {
+	return newAEAD(a.primarySecret())
+}
+
+func decryptWithSecret(secret string, toBeDecrypted []byte) ([]byte, error) {
+	aead, err := newAEAD(secret)
 	if err != nil {
 		return nil, err
 	}
 
 	nonceSize := aead.NonceSize()
+	if len(toBeDecrypted) < nonceSize {
+		return nil, errors.New("ciphertext too short")
+	}
 	nonce, encrypted := toBeDecrypted[:nonceSize], toBeDecrypted[nonceSize:]
 
 	decrypted, err := aead.Open(nil, nonce, encrypted, nil)
@@ -46,16 +141,16 @@ func (a *aesCipher) decrypt(toBeDecrypted []byte) ([]byte, error) {
 	return decrypted, nil
 }
 
-func (a *aesCipher) aead() (cipher.AEAD, error) {
-	aesBlock, err := aes.NewCipher([]byte(mdHashing(a.secret)))
+func newAEAD(secret string) (cipher.AEAD, error) {
+	aesBlock, err := aes.NewCipher([]byte(mdHashing(secret)))
 	if err != nil {
 		return nil, err
 	}
 	return cipher.NewGCM(aesBlock)
 }
 
-func newAESCipher(secret string) *aesCipher {
-	return &aesCipher{secret: secret}
+func newAESCipher(secret string, previousSecrets ...string) *aesCipher {
+	return &aesCipher{secret: secret, previousSecrets: previousSecrets}
 }
 
 func mdHashing(input string) string {
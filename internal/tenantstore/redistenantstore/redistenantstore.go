@@ -15,6 +15,7 @@ import (
 	"github.com/hookdeck/outpost/internal/pagination"
 	"github.com/hookdeck/outpost/internal/redis"
 	"github.com/hookdeck/outpost/internal/tenantstore/driver"
+	"golang.org/x/sync/singleflight"
 )
 
 const defaultMaxDestinationsPerTenant = 20
@@ -36,6 +37,9 @@ type store struct {
 	maxDestinationsPerTenant int
 	deploymentID             string
 	listTenantSupported      bool
+	coldStore                driver.ColdStore
+	resumes                  singleflight.Group
+	changefeedMaxLen         int64
 }
 
 var _ driver.TenantStore = (*store)(nil)
@@ -46,7 +50,25 @@ type Option func(*store)
 // WithSecret sets the encryption secret for credentials.
 func WithSecret(secret string) Option {
 	return func(s *store) {
-		s.cipher = newAESCipher(secret)
+		s.cipher.secret = secret
+	}
+}
+
+// WithPreviousSecrets sets secrets that were used before the current one.
+// They are only used to decrypt values that have not been re-encrypted yet.
+func WithPreviousSecrets(secrets []string) Option {
+	return func(s *store) {
+		s.cipher.previousSecrets = secrets
+	}
+}
+
+// WithEncryptionKeys configures a key ring of named encryption keys. New
+// writes use the key identified by primaryKeyID and record its ID; reads use
+// the recorded key and fall back to every other known key.
+func WithEncryptionKeys(keys map[string]string, primaryKeyID string) Option {
+	return func(s *store) {
+		s.cipher.keys = keys
+		s.cipher.primaryID = primaryKeyID
 	}
 }
 
@@ -71,6 +93,13 @@ func WithDeploymentID(deploymentID string) Option {
 	}
 }
 
+// WithColdStore sets the object storage suspended tenants are written to.
+func WithColdStore(coldStore driver.ColdStore) Option {
+	return func(s *store) {
+		s.coldStore = coldStore
+	}
+}
+
 // New creates a new Redis-backed TenantStore.
 func New(redisClient redis.Cmdable, opts ...Option) driver.TenantStore {
 	s := &store{
@@ -180,7 +209,10 @@ func (s *store) RetrieveTenant(ctx context.Context, tenantID string) (*models.Te
 		return nil, err
 	}
 	if len(tenantHash) == 0 {
-		return nil, nil
+		if resumed, err := s.resumeTenant(ctx, tenantID); err != nil || !resumed {
+			return nil, err
+		}
+		return s.RetrieveTenant(ctx, tenantID)
 	}
 	tenant, err := parseTenantHash(tenantHash)
 	if err != nil {
@@ -200,6 +232,10 @@ func (s *store) RetrieveTenant(ctx context.Context, tenantID string) (*models.Te
 func (s *store) UpsertTenant(ctx context.Context, tenant models.Tenant) error {
 	key := s.redisTenantID(tenant.ID)
 
+	if _, err := s.resumeTenant(ctx, tenant.ID); err != nil {
+		return err
+	}
+
 	if err := s.redisClient.Persist(ctx, key).Err(); err != nil && err != redis.Nil {
 		return err
 	}
@@ -235,10 +271,114 @@ func (s *store) UpsertTenant(ctx context.Context, tenant models.Tenant) error {
 		}
 	}
 
-	return nil
+	if tenant.Sandbox {
+		if err := s.redisClient.HSet(ctx, key, "sandbox", "true").Err(); err != nil {
+			return err
+		}
+	} else {
+		if err := s.redisClient.HDel(ctx, key, "sandbox").Err(); err != nil && err != redis.Nil {
+			return err
+		}
+	}
+
+	if tenant.VerifyWebhookEndpoints {
+		if err := s.redisClient.HSet(ctx, key, "verify_webhook_endpoints", "true").Err(); err != nil {
+			return err
+		}
+	} else {
+		if err := s.redisClient.HDel(ctx, key, "verify_webhook_endpoints").Err(); err != nil && err != redis.Nil {
+			return err
+		}
+	}
+
+	if tenant.ReceiptStorage != nil {
+		if err := s.redisClient.HSet(ctx, key, "receipt_storage", tenant.ReceiptStorage).Err(); err != nil {
+			return err
+		}
+	} else {
+		if err := s.redisClient.HDel(ctx, key, "receipt_storage").Err(); err != nil && err != redis.Nil {
+			return err
+		}
+	}
+
+	if tenant.Notifications != nil {
+		if err := s.redisClient.HSet(ctx, key, "notifications", tenant.Notifications).Err(); err != nil {
+			return err
+		}
+	} else {
+		if err := s.redisClient.HDel(ctx, key, "notifications").Err(); err != nil && err != redis.Nil {
+			return err
+		}
+	}
+
+	if tenant.LifecycleCallback != nil {
+		if err := s.redisClient.HSet(ctx, key, "lifecycle_callback", tenant.LifecycleCallback).Err(); err != nil {
+			return err
+		}
+	} else {
+		if err := s.redisClient.HDel(ctx, key, "lifecycle_callback").Err(); err != nil && err != redis.Nil {
+			return err
+		}
+	}
+
+	if tenant.AlertChannels != nil {
+		if err := s.redisClient.HSet(ctx, key, "alert_channels", tenant.AlertChannels).Err(); err != nil {
+			return err
+		}
+	} else {
+		if err := s.redisClient.HDel(ctx, key, "alert_channels").Err(); err != nil && err != redis.Nil {
+			return err
+		}
+	}
+
+	if tenant.AutoDisablePolicy != nil {
+		if err := s.redisClient.HSet(ctx, key, "auto_disable_policy", tenant.AutoDisablePolicy).Err(); err != nil {
+			return err
+		}
+	} else {
+		if err := s.redisClient.HDel(ctx, key, "auto_disable_policy").Err(); err != nil && err != redis.Nil {
+			return err
+		}
+	}
+
+	if len(tenant.DestinationTypes) > 0 {
+		if err := s.redisClient.HSet(ctx, key, "destination_types", strings.Join(tenant.DestinationTypes, ",")).Err(); err != nil {
+			return err
+		}
+	} else {
+		if err := s.redisClient.HDel(ctx, key, "destination_types").Err(); err != nil && err != redis.Nil {
+			return err
+		}
+	}
+
+	if len(tenant.PortalAllowedIPs) > 0 {
+		if err := s.redisClient.HSet(ctx, key, "portal_allowed_ips", strings.Join(tenant.PortalAllowedIPs, ",")).Err(); err != nil {
+			return err
+		}
+	} else {
+		if err := s.redisClient.HDel(ctx, key, "portal_allowed_ips").Err(); err != nil && err != redis.Nil {
+			return err
+		}
+	}
+
+	if tenant.PublishRateLimit != nil {
+		if err := s.redisClient.HSet(ctx, key, "publish_rate_limit", tenant.PublishRateLimit).Err(); err != nil {
+			return err
+		}
+	} else {
+		if err := s.redisClient.HDel(ctx, key, "publish_rate_limit").Err(); err != nil && err != redis.Nil {
+			return err
+		}
+	}
+
+	return s.recordChange(ctx, ChangeEntityTenant, ChangeOpUpsert, tenant.ID, "")
 }
 
 func (s *store) DeleteTenant(ctx context.Context, tenantID string) error {
+	if _, err := s.resumeTenant(ctx, tenantID); err != nil {
+		return err
+	}
+
 	if exists, err := s.redisClient.Exists(ctx, s.redisTenantID(tenantID)).Result(); err != nil {
 		return err
 	} else if exists == 0 {
@@ -257,6 +397,7 @@ func (s *store) DeleteTenant(ctx context.Context, tenantID string) error {
 			destKey := s.redisDestinationID(destinationID, tenantID)
 			pipe.HSet(ctx, destKey, "deleted_at", nowUnixMilli)
 			pipe.Expire(ctx, destKey, 7*24*time.Hour)
+			pipe.Del(ctx, s.redisDestinationVersionsKey(destinationID, tenantID))
 		}
 
 		pipe.Del(ctx, s.redisTenantDestinationSummaryKey(tenantID))
@@ -265,8 +406,11 @@ func (s *store) DeleteTenant(ctx context.Context, tenantID string) error {
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	return s.recordChange(ctx, ChangeEntityTenant, ChangeOpDelete, tenantID, "")
 }
 
 func (s *store) ListTenant(ctx context.Context, req driver.ListTenantRequest) (*driver.TenantPaginatedResult, error) {
@@ -472,6 +616,11 @@ func (s *store) ListDestination(ctx context.Context, req driver.ListDestinationR
 	}
 
 	if len(summaries) == 0 {
+		if resumed, err := s.resumeTenant(ctx, req.TenantID); err != nil {
+			return nil, err
+		} else if resumed {
+			return s.ListDestination(ctx, req)
+		}
 		return []models.Destination{}, nil
 	}
 
@@ -512,15 +661,22 @@ func (s *store) RetrieveDestination(ctx context.Context, tenantID, destinationID
 	cmd := s.redisClient.HGetAll(ctx, s.redisDestinationID(destinationID, tenantID))
 	destination, err := parseDestinationHash(cmd, tenantID, s.cipher)
 	if err != nil {
-		if err == redis.Nil {
-			return nil, nil
+		if err != redis.Nil {
+			return nil, err
 		}
-		return nil, err
+		if resumed, err := s.resumeTenant(ctx, tenantID); err != nil || !resumed {
+			return nil, err
+		}
+		return s.RetrieveDestination(ctx, tenantID, destinationID)
 	}
 	return destination, nil
 }
 
 func (s *store) CreateDestination(ctx context.Context, destination models.Destination) error {
+	if _, err := s.resumeTenant(ctx, destination.TenantID); err != nil {
+		return err
+	}
+
 	key := s.redisDestinationID(destination.ID, destination.TenantID)
 	if fields, err := s.redisClient.HGetAll(ctx, key).Result(); err != nil {
 		return err
@@ -544,6 +700,10 @@ func (s *store) CreateDestination(ctx context.Context, destination models.Destin
 func (s *store) UpsertDestination(ctx context.Context, destination models.Destination) error {
 	key := s.redisDestinationID(destination.ID, destination.TenantID)
 
+	if _, err := s.resumeTenant(ctx, destination.TenantID); err != nil {
+		return err
+	}
+
 	credentialsBytes, err := destination.Credentials.MarshalBinary()
 	if err != nil {
 		return fmt.Errorf("invalid destination credentials: %w", err)
@@ -585,6 +745,11 @@ func (s *store) UpsertDestination(ctx context.Context, destination models.Destin
 		pipe.HSet(ctx, key, "topics", &destination.Topics)
 		pipe.HSet(ctx, key, "config", &destination.Config)
 		pipe.HSet(ctx, key, "credentials", encryptedCredentials)
+		if keyID := s.cipher.primaryKeyID(); keyID != "" {
+			pipe.HSet(ctx, key, "encryption_key_id", keyID)
+		} else {
+			pipe.HDel(ctx, key, "encryption_key_id")
+		}
 		pipe.HSet(ctx, key, "created_at", destination.CreatedAt.UnixMilli())
 		pipe.HSet(ctx, key, "updated_at", destination.UpdatedAt.UnixMilli())
 
@@ -612,17 +777,78 @@ func (s *store) UpsertDestination(ctx context.Context, destination models.Destin
 			pipe.HDel(ctx, key, "filter")
 		}
 
+		if destination.Recording != nil {
+			pipe.HSet(ctx, key, "recording", destination.Recording)
+		} else {
+			pipe.HDel(ctx, key, "recording")
+		}
+
+		if destination.RetryPolicy != nil {
+			pipe.HSet(ctx, key, "retry_policy", destination.RetryPolicy)
+		} else {
+			pipe.HDel(ctx, key, "retry_policy")
+		}
+
+		if destination.Transformation != nil {
+			pipe.HSet(ctx, key, "transformation", destination.Transformation)
+		} else {
+			pipe.HDel(ctx, key, "transformation")
+		}
+
+		if destination.TargetChange != nil {
+			pipe.HSet(ctx, key, "target_change", destination.TargetChange)
+		} else {
+			pipe.HDel(ctx, key, "target_change")
+		}
+
+		if destination.MaxPayloadBytes > 0 {
+			pipe.HSet(ctx, key, "max_payload_bytes", destination.MaxPayloadBytes)
+		} else {
+			pipe.HDel(ctx, key, "max_payload_bytes")
+		}
+
+		if destination.MaxConcurrency > 0 {
+			pipe.HSet(ctx, key, "max_concurrency", destination.MaxConcurrency)
+		} else {
+			pipe.HDel(ctx, key, "max_concurrency")
+		}
+
+		if destination.MaxDeliveriesPerSecond > 0 {
+			pipe.HSet(ctx, key, "max_deliveries_per_second", destination.MaxDeliveriesPerSecond)
+		} else {
+			pipe.HDel(ctx, key, "max_deliveries_per_second")
+		}
+
+		if destination.ShadowDestinationID != "" {
+			pipe.HSet(ctx, key, "shadow_destination_id", destination.ShadowDestinationID)
+		} else {
+			pipe.HDel(ctx, key, "shadow_destination_id")
+		}
+
+		if destination.SandboxSafe {
+			pipe.HSet(ctx, key, "sandbox_safe", "true")
+		} else {
+			pipe.HDel(ctx, key, "sandbox_safe")
+		}
+
 		pipe.HSet(ctx, summaryKey, destination.ID, newDestinationSummary(destination))
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	return s.recordChange(ctx, ChangeEntityDestination, ChangeOpUpsert, destination.TenantID, destination.ID)
 }
 
 func (s *store) DeleteDestination(ctx context.Context, tenantID, destinationID string) error {
 	key := s.redisDestinationID(destinationID, tenantID)
 	summaryKey := s.redisTenantDestinationSummaryKey(tenantID)
 
+	if _, err := s.resumeTenant(ctx, tenantID); err != nil {
+		return err
+	}
+
 	if exists, err := s.redisClient.Exists(ctx, key).Result(); err != nil {
 		return err
 	} else if exists == 0 {
@@ -635,11 +861,15 @@ func (s *store) DeleteDestination(ctx context.Context, tenantID, destinationID s
 		pipe.HDel(ctx, summaryKey, destinationID)
 		pipe.HSet(ctx, key, "deleted_at", nowUnixMilli)
 		pipe.Expire(ctx, key, 7*24*time.Hour)
+		pipe.Expire(ctx, s.redisDestinationVersionsKey(destinationID, tenantID), 7*24*time.Hour)
 
 		return nil
 	})
+	if err != nil {
+		return err
+	}
 
-	return err
+	return s.recordChange(ctx, ChangeEntityDestination, ChangeOpDelete, tenantID, destinationID)
 }
 
 func (s *store) MatchEvent(ctx context.Context, event models.Event) ([]string, error) {
@@ -647,6 +877,13 @@ func (s *store) MatchEvent(ctx context.Context, event models.Event) ([]string, e
 	if err != nil {
 		return nil, err
 	}
+	if len(destinationSummaryList) == 0 {
+		if resumed, err := s.resumeTenant(ctx, event.TenantID); err != nil {
+			return nil, err
+		} else if resumed {
+			return s.MatchEvent(ctx, event)
+		}
+	}
 
 	var matched []string
 
@@ -3,6 +3,7 @@ package redistenantstore_test
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"testing"
 	"time"
@@ -250,6 +251,347 @@ func TestDestinationCredentialsEncryption(t *testing.T) {
 	assert.Equal(t, input.DeliveryMetadata, retrieved.DeliveryMetadata)
 }
 
+// =============================================================================
+// Standalone: Credentials Re-encryption (AES secret rotation)
+// =============================================================================
+
+func TestReEncryptCredentials(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	redisClient := testutil.CreateTestRedisClient(t)
+
+	oldStore := redistenantstore.New(redisClient,
+		redistenantstore.WithSecret("old-secret"),
+		redistenantstore.WithAvailableTopics(testutil.TestTopics),
+	)
+	input := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithCredentials(map[string]string{"password": "guest"}),
+		testutil.DestinationFactory.WithDeliveryMetadata(map[string]string{"Authorization": "Bearer token"}),
+	)
+	require.NoError(t, oldStore.UpsertDestination(ctx, input))
+
+	// Without the previous secret, the destination can no longer be read.
+	newOnlyStore := redistenantstore.New(redisClient,
+		redistenantstore.WithSecret("new-secret"),
+		redistenantstore.WithAvailableTopics(testutil.TestTopics),
+	)
+	_, err := newOnlyStore.RetrieveDestination(ctx, input.TenantID, input.ID)
+	require.Error(t, err)
+
+	rotatingStore := redistenantstore.New(redisClient,
+		redistenantstore.WithSecret("new-secret"),
+		redistenantstore.WithPreviousSecrets([]string{"old-secret"}),
+		redistenantstore.WithAvailableTopics(testutil.TestTopics),
+	)
+	retrieved, err := rotatingStore.RetrieveDestination(ctx, input.TenantID, input.ID)
+	require.NoError(t, err)
+	assert.Equal(t, input.Credentials, retrieved.Credentials)
+
+	reEncrypter := rotatingStore.(driver.CredentialReEncrypter)
+
+	result, err := reEncrypter.ReEncryptCredentials(ctx, driver.ReEncryptOptions{DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, &driver.ReEncryptResult{Scanned: 1, ReEncrypted: 1}, result)
+
+	result, err = reEncrypter.ReEncryptCredentials(ctx, driver.ReEncryptOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, &driver.ReEncryptResult{Scanned: 1, ReEncrypted: 1}, result)
+
+	retrieved, err = newOnlyStore.RetrieveDestination(ctx, input.TenantID, input.ID)
+	require.NoError(t, err)
+	assert.Equal(t, input.Credentials, retrieved.Credentials)
+	assert.Equal(t, input.DeliveryMetadata, retrieved.DeliveryMetadata)
+
+	result, err = reEncrypter.ReEncryptCredentials(ctx, driver.ReEncryptOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, &driver.ReEncryptResult{Scanned: 1}, result, "second pass should be a no-op")
+}
+
+func TestEncryptionKeyRing(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	redisClient := testutil.CreateTestRedisClient(t)
+	keys := map[string]string{"k1": "secret-one", "k2": "secret-two"}
+
+	k1Store := redistenantstore.New(redisClient,
+		redistenantstore.WithSecret("legacy-secret"),
+		redistenantstore.WithEncryptionKeys(keys, "k1"),
+		redistenantstore.WithAvailableTopics(testutil.TestTopics),
+	)
+	input := testutil.DestinationFactory.Any(
+		testutil.DestinationFactory.WithCredentials(map[string]string{"password": "guest"}),
+	)
+	require.NoError(t, k1Store.UpsertDestination(ctx, input))
+
+	key := fmt.Sprintf("tenant:{%s}:destination:%s", input.TenantID, input.ID)
+	keyID, err := redisClient.HGet(ctx, key, "encryption_key_id").Result()
+	require.NoError(t, err)
+	assert.Equal(t, "k1", keyID)
+
+	// Promoting k2 keeps records written with k1 readable.
+	k2Store := redistenantstore.New(redisClient,
+		redistenantstore.WithSecret("legacy-secret"),
+		redistenantstore.WithEncryptionKeys(keys, "k2"),
+		redistenantstore.WithAvailableTopics(testutil.TestTopics),
+	)
+	retrieved, err := k2Store.RetrieveDestination(ctx, input.TenantID, input.ID)
+	require.NoError(t, err)
+	assert.Equal(t, input.Credentials, retrieved.Credentials)
+
+	result, err := k2Store.(driver.CredentialReEncrypter).ReEncryptCredentials(ctx, driver.ReEncryptOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.ReEncrypted)
+
+	keyID, err = redisClient.HGet(ctx, key, "encryption_key_id").Result()
+	require.NoError(t, err)
+	assert.Equal(t, "k2", keyID)
+
+	// Once re-encrypted, k1 can be retired.
+	k2OnlyStore := redistenantstore.New(redisClient,
+		redistenantstore.WithEncryptionKeys(map[string]string{"k2": "secret-two"}, "k2"),
+		redistenantstore.WithAvailableTopics(testutil.TestTopics),
+	)
+	retrieved, err = k2OnlyStore.RetrieveDestination(ctx, input.TenantID, input.ID)
+	require.NoError(t, err)
+	assert.Equal(t, input.Credentials, retrieved.Credentials)
+}
+
+// =============================================================================
+// Standalone: Tenant cold storage
+// =============================================================================
+
+// memColdStore is an in-memory driver.ColdStore. onPut, when set, runs after
+// each write.
+type memColdStore struct {
+	objects map[string][]byte
+	onPut   func()
+}
+
+func (m *memColdStore) Put(_ context.Context, key string, body []byte, _ string) error {
+	m.objects[key] = body
+	if m.onPut != nil {
+		m.onPut()
+	}
+	return nil
+}
+
+func (m *memColdStore) Get(_ context.Context, key string) ([]byte, error) {
+	body, ok := m.objects[key]
+	if !ok {
+		return nil, errors.New("object not found")
+	}
+	return body, nil
+}
+
+func TestSuspendTenant(t *testing.T) {
+	t.Parallel()
+
+	newStore := func(t *testing.T) (redis.Cmdable, *memColdStore, driver.TenantStore) {
+		redisClient := testutil.CreateTestRedisClient(t)
+		coldStore := &memColdStore{objects: map[string][]byte{}}
+		s := redistenantstore.New(redisClient,
+			redistenantstore.WithSecret("test-secret"),
+			redistenantstore.WithAvailableTopics(testutil.TestTopics),
+			redistenantstore.WithDeploymentID("dp_001"),
+			redistenantstore.WithColdStore(coldStore),
+		)
+		return redisClient, coldStore, s
+	}
+	seed := func(t *testing.T, s driver.TenantStore) (models.Tenant, models.Destination) {
+		ctx := context.Background()
+		tenant := models.Tenant{ID: idgen.String(), Metadata: map[string]string{"plan": "free"}}
+		require.NoError(t, s.UpsertTenant(ctx, tenant))
+		destination := testutil.DestinationFactory.Any(
+			testutil.DestinationFactory.WithTenantID(tenant.ID),
+			testutil.DestinationFactory.WithTopics([]string{"*"}),
+			testutil.DestinationFactory.WithCredentials(map[string]string{"password": "guest"}),
+		)
+		require.NoError(t, s.CreateDestination(ctx, destination))
+		_, err := s.(driver.DestinationVersioner).CreateDestinationVersion(ctx, destination, "admin")
+		require.NoError(t, err)
+		return tenant, destination
+	}
+
+	t.Run("removes the tenant from redis and resumes it on read", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		redisClient, coldStore, s := newStore(t)
+		tenant, destination := seed(t, s)
+
+		require.NoError(t, s.(driver.TenantSuspender).SuspendTenant(ctx, tenant.ID))
+
+		keys, err := redisClient.Keys(ctx, "dp_001:tenant:{"+tenant.ID+"}:*").Result()
+		require.NoError(t, err)
+		assert.Equal(t, []string{"dp_001:tenant:{" + tenant.ID + "}:suspended"}, keys)
+		assert.Contains(t, coldStore.objects, "tenants/dp_001/"+tenant.ID+".json.gz")
+
+		retrieved, err := s.RetrieveTenant(ctx, tenant.ID)
+		require.NoError(t, err)
+		require.NotNil(t, retrieved)
+		assert.Equal(t, tenant.Metadata, retrieved.Metadata)
+		assert.Equal(t, 1, retrieved.DestinationsCount)
+
+		retrievedDestination, err := s.RetrieveDestination(ctx, tenant.ID, destination.ID)
+		require.NoError(t, err)
+		assert.Equal(t, destination.Credentials, retrievedDestination.Credentials)
+		versions, err := s.(driver.DestinationVersioner).ListDestinationVersion(ctx, tenant.ID, destination.ID)
+		require.NoError(t, err)
+		assert.Len(t, versions, 1)
+
+		exists, err := redisClient.Exists(ctx, "dp_001:tenant:{"+tenant.ID+"}:suspended").Result()
+		require.NoError(t, err)
+		assert.Zero(t, exists, "resuming should clear the suspended marker")
+	})
+
+	t.Run("resumes on publish", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		_, _, s := newStore(t)
+		tenant, destination := seed(t, s)
+		require.NoError(t, s.(driver.TenantSuspender).SuspendTenant(ctx, tenant.ID))
+
+		matched, err := s.MatchEvent(ctx, testutil.EventFactory.Any(testutil.EventFactory.WithTenantID(tenant.ID)))
+		require.NoError(t, err)
+		assert.Equal(t, []string{destination.ID}, matched)
+	})
+
+	t.Run("resumes before writes", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		_, _, s := newStore(t)
+		tenant, destination := seed(t, s)
+		require.NoError(t, s.(driver.TenantSuspender).SuspendTenant(ctx, tenant.ID))
+
+		tenant.Metadata = map[string]string{"plan": "pro"}
+		require.NoError(t, s.UpsertTenant(ctx, tenant))
+
+		retrieved, err := s.RetrieveTenant(ctx, tenant.ID)
+		require.NoError(t, err)
+		assert.Equal(t, tenant.Metadata, retrieved.Metadata, "the write should not be overwritten by the snapshot")
+		destinations, err := s.ListDestination(ctx, driver.ListDestinationRequest{TenantID: tenant.ID})
+		require.NoError(t, err)
+		require.Len(t, destinations, 1)
+		assert.Equal(t, destination.ID, destinations[0].ID)
+	})
+
+	t.Run("fails when the tenant changes during the suspend", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		redisClient, coldStore, s := newStore(t)
+		tenant, _ := seed(t, s)
+		coldStore.onPut = func() {
+			coldStore.onPut = nil
+			require.NoError(t, s.CreateDestination(ctx, testutil.DestinationFactory.Any(
+				testutil.DestinationFactory.WithTenantID(tenant.ID),
+			)))
+		}
+
+		err := s.(driver.TenantSuspender).SuspendTenant(ctx, tenant.ID)
+		require.ErrorIs(t, err, driver.ErrTenantModified)
+
+		exists, err := redisClient.Exists(ctx, "dp_001:tenant:{"+tenant.ID+"}:tenant").Result()
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), exists, "the tenant should be left in redis")
+		retrieved, err := s.RetrieveTenant(ctx, tenant.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 2, retrieved.DestinationsCount)
+	})
+
+	t.Run("is idempotent", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		_, _, s := newStore(t)
+		tenant, _ := seed(t, s)
+
+		require.NoError(t, s.(driver.TenantSuspender).SuspendTenant(ctx, tenant.ID))
+		require.NoError(t, s.(driver.TenantSuspender).SuspendTenant(ctx, tenant.ID))
+	})
+
+	t.Run("unknown and deleted tenants", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		_, _, s := newStore(t)
+		tenant, _ := seed(t, s)
+
+		err := s.(driver.TenantSuspender).SuspendTenant(ctx, idgen.String())
+		require.ErrorIs(t, err, driver.ErrTenantNotFound)
+
+		require.NoError(t, s.DeleteTenant(ctx, tenant.ID))
+		err = s.(driver.TenantSuspender).SuspendTenant(ctx, tenant.ID)
+		require.ErrorIs(t, err, driver.ErrTenantDeleted)
+	})
+
+	t.Run("requires cold storage", func(t *testing.T) {
+		t.Parallel()
+		ctx := context.Background()
+		redisClient, _, s := newStore(t)
+		tenant, _ := seed(t, s)
+		require.NoError(t, s.(driver.TenantSuspender).SuspendTenant(ctx, tenant.ID))
+
+		withoutColdStore := redistenantstore.New(redisClient,
+			redistenantstore.WithSecret("test-secret"),
+			redistenantstore.WithDeploymentID("dp_001"),
+		)
+		err := withoutColdStore.(driver.TenantSuspender).SuspendTenant(ctx, tenant.ID)
+		require.ErrorIs(t, err, driver.ErrColdStorageNotConfigured)
+		_, err = withoutColdStore.RetrieveTenant(ctx, tenant.ID)
+		require.ErrorIs(t, err, driver.ErrColdStorageNotConfigured, "a suspended tenant should not look missing")
+	})
+}
+
+// =============================================================================
+// Standalone: Changefeed
+// =============================================================================
+
+func TestChangefeed(t *testing.T) {
+	t.Parallel()
+
+	ctx := context.Background()
+	redisClient := testutil.CreateTestRedisClient(t)
+
+	store := redistenantstore.New(redisClient,
+		redistenantstore.WithSecret("test-secret"),
+		redistenantstore.WithAvailableTopics(testutil.TestTopics),
+		redistenantstore.WithDeploymentID("dp_001"),
+		redistenantstore.WithChangefeed(100),
+	)
+
+	tenant := testutil.TenantFactory.Any()
+	destination := testutil.DestinationFactory.Any(testutil.DestinationFactory.WithTenantID(tenant.ID))
+	require.NoError(t, store.UpsertTenant(ctx, tenant))
+	require.NoError(t, store.CreateDestination(ctx, destination))
+	require.NoError(t, store.DeleteDestination(ctx, tenant.ID, destination.ID))
+	require.NoError(t, store.DeleteTenant(ctx, tenant.ID))
+
+	messages, err := redisClient.XRange(ctx, redistenantstore.ChangefeedKey("dp_001"), "-", "+").Result()
+	require.NoError(t, err)
+	var changes []redistenantstore.Change
+	for _, msg := range messages {
+		change, err := redistenantstore.ParseChange(msg)
+		require.NoError(t, err)
+		change.ID = ""
+		changes = append(changes, change)
+	}
+	assert.Equal(t, []redistenantstore.Change{
+		{Entity: redistenantstore.ChangeEntityTenant, Op: redistenantstore.ChangeOpUpsert, TenantID: tenant.ID},
+		{Entity: redistenantstore.ChangeEntityDestination, Op: redistenantstore.ChangeOpUpsert, TenantID: tenant.ID, DestinationID: destination.ID},
+		{Entity: redistenantstore.ChangeEntityDestination, Op: redistenantstore.ChangeOpDelete, TenantID: tenant.ID, DestinationID: destination.ID},
+		{Entity: redistenantstore.ChangeEntityTenant, Op: redistenantstore.ChangeOpDelete, TenantID: tenant.ID},
+	}, changes)
+
+	t.Run("is off by default", func(t *testing.T) {
+		redisClient := testutil.CreateTestRedisClient(t)
+		store := redistenantstore.New(redisClient, redistenantstore.WithSecret("test-secret"))
+		require.NoError(t, store.UpsertTenant(ctx, testutil.TenantFactory.Any()))
+
+		exists, err := redisClient.Exists(ctx, redistenantstore.ChangefeedKey("")).Result()
+		require.NoError(t, err)
+		assert.Zero(t, exists)
+	})
+}
+
 // =============================================================================
 // Standalone: ListTenant not supported (miniredis has no RediSearch)
 // =============================================================================
@@ -0,0 +1,209 @@
+package redistenantstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/tenantstore/driver"
+)
+
+var _ driver.CredentialReEncrypter = (*store)(nil)
+
+// reEncryptScript swaps the encrypted fields of a destination only if they
+// still hold the values that were read, so a concurrent update written with
+// the new key is never overwritten by a stale re-encryption.
+//
+// KEYS[1] = destination key
+// ARGV[1] = expected credentials, ARGV[2] = expected delivery_metadata
+// ARGV[3] = new credentials, ARGV[4] = new delivery_metadata ("" to leave unset)
+// ARGV[5] = ID of the key the new values are encrypted with ("" for none)
+const reEncryptScript = `
+local creds = redis.call('HGET', KEYS[1], 'credentials') or ''
+local dm = redis.call('HGET', KEYS[1], 'delivery_metadata') or ''
+if creds ~= ARGV[1] or dm ~= ARGV[2] then
+	return 0
+end
+redis.call('HSET', KEYS[1], 'credentials', ARGV[3])
+if ARGV[4] ~= '' then
+	redis.call('HSET', KEYS[1], 'delivery_metadata', ARGV[4])
+end
+if ARGV[5] ~= '' then
+	redis.call('HSET', KEYS[1], 'encryption_key_id', ARGV[5])
+else
+	redis.call('HDEL', KEYS[1], 'encryption_key_id')
+end
+return 1
+`
+
+// reEncryptVersionScript swaps a destination version only if it still holds
+// the value that was read.
+//
+// KEYS[1] = versions key
+// ARGV[1] = version field, ARGV[2] = expected value, ARGV[3] = new value
+const reEncryptVersionScript = `
+if redis.call('HGET', KEYS[1], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+redis.call('HSET', KEYS[1], ARGV[1], ARGV[3])
+return 1
+`
+
+// ReEncryptCredentials rewrites the credentials and delivery metadata of every
+// destination, and every retained destination version, that is not encrypted
+// with the primary key so that it is. It is safe to run repeatedly and while
+// the service is serving traffic; once it reports nothing left to
+// re-encrypt, the previous secrets and retired key ring entries can be
+// removed from the configuration.
+func (s *store) ReEncryptCredentials(ctx context.Context, opts driver.ReEncryptOptions) (*driver.ReEncryptResult, error) {
+	result := &driver.ReEncryptResult{}
+	err := s.scanKeys(ctx, s.deploymentPrefix()+"tenant:*:destination:*", func(key string) error {
+		// Skip the per-tenant destination summary hash.
+		if strings.HasSuffix(key, ":destinations") {
+			return nil
+		}
+		return s.reEncryptDestination(ctx, key, opts, result)
+	})
+	if err != nil {
+		return result, err
+	}
+	err = s.scanKeys(ctx, s.deploymentPrefix()+"tenant:*:destination_versions:*", func(key string) error {
+		return s.reEncryptDestinationVersions(ctx, key, opts, result)
+	})
+	return result, err
+}
+
+func (s *store) scanKeys(ctx context.Context, pattern string, fn func(key string) error) error {
+	var cursor uint64
+	for {
+		keys, nextCursor, err := s.redisClient.Scan(ctx, cursor, pattern, 100).Result()
+		if err != nil {
+			return fmt.Errorf("scan failed: %w", err)
+		}
+		for _, key := range keys {
+			if err := fn(key); err != nil {
+				return err
+			}
+		}
+		cursor = nextCursor
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+func (s *store) reEncryptDestination(ctx context.Context, key string, opts driver.ReEncryptOptions, result *driver.ReEncryptResult) error {
+	hash, err := s.redisClient.HGetAll(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	if len(hash) == 0 {
+		return nil
+	}
+	if _, deleted := hash["deleted_at"]; deleted {
+		return nil
+	}
+	result.Scanned++
+
+	credentials, credentialsCurrent, err := s.cipher.decryptWithFallback([]byte(hash["credentials"]), hash["encryption_key_id"])
+	if err != nil {
+		result.Failed++
+		return nil
+	}
+	var deliveryMetadata []byte
+	deliveryMetadataCurrent := true
+	if hash["delivery_metadata"] != "" {
+		deliveryMetadata, deliveryMetadataCurrent, err = s.cipher.decryptWithFallback([]byte(hash["delivery_metadata"]), hash["encryption_key_id"])
+		if err != nil {
+			result.Failed++
+			return nil
+		}
+	}
+	if credentialsCurrent && deliveryMetadataCurrent {
+		return nil
+	}
+
+	result.ReEncrypted++
+	if opts.DryRun {
+		return nil
+	}
+
+	encryptedCredentials, err := s.cipher.encrypt(credentials)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt destination credentials: %w", err)
+	}
+	var encryptedDeliveryMetadata []byte
+	if deliveryMetadata != nil {
+		encryptedDeliveryMetadata, err = s.cipher.encrypt(deliveryMetadata)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt destination delivery_metadata: %w", err)
+		}
+	}
+
+	swapped, err := s.redisClient.Eval(ctx, reEncryptScript, []string{key},
+		hash["credentials"], hash["delivery_metadata"],
+		string(encryptedCredentials), string(encryptedDeliveryMetadata), s.cipher.primaryKeyID(),
+	).Int()
+	if err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to re-encrypt %s: %w", key, err)
+	}
+	if swapped == 0 {
+		// The destination was updated concurrently, and that write used the
+		// current key.
+		result.ReEncrypted--
+	}
+	return nil
+}
+
+func (s *store) reEncryptDestinationVersions(ctx context.Context, key string, opts driver.ReEncryptOptions, result *driver.ReEncryptResult) error {
+	hash, err := s.redisClient.HGetAll(ctx, key).Result()
+	if err != nil {
+		return err
+	}
+	for field, value := range hash {
+		if !strings.HasPrefix(field, destinationVersionFieldPrefix) {
+			continue
+		}
+		result.Scanned++
+
+		var record destinationVersionRecord
+		if err := json.Unmarshal([]byte(value), &record); err != nil {
+			result.Failed++
+			continue
+		}
+		destination, current, err := s.cipher.decryptWithFallback(record.Destination, record.KeyID)
+		if err != nil {
+			result.Failed++
+			continue
+		}
+		if current {
+			continue
+		}
+
+		result.ReEncrypted++
+		if opts.DryRun {
+			continue
+		}
+
+		record.Destination, err = s.cipher.encrypt(destination)
+		if err != nil {
+			return fmt.Errorf("failed to encrypt destination version: %w", err)
+		}
+		record.KeyID = s.cipher.primaryKeyID()
+		updated, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		swapped, err := s.redisClient.Eval(ctx, reEncryptVersionScript, []string{key}, field, value, string(updated)).Int()
+		if err != nil && err != redis.Nil {
+			return fmt.Errorf("failed to re-encrypt %s %s: %w", key, field, err)
+		}
+		if swapped == 0 {
+			// The version fell out of the retention window meanwhile.
+			result.ReEncrypted--
+		}
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/hookdeck/outpost/internal/models"
@@ -73,6 +74,59 @@ func parseTenantHash(hash map[string]string) (*models.Tenant, error) {
 		}
 	}
 
+	t.Sandbox = hash["sandbox"] == "true"
+	t.VerifyWebhookEndpoints = hash["verify_webhook_endpoints"] == "true"
+
+	if receiptStorageStr, exists := hash["receipt_storage"]; exists && receiptStorageStr != "" {
+		t.ReceiptStorage = &models.ReceiptStorage{}
+		if err := t.ReceiptStorage.UnmarshalBinary([]byte(receiptStorageStr)); err != nil {
+			return nil, fmt.Errorf("invalid receipt_storage: %w", err)
+		}
+	}
+
+	if notificationsStr, exists := hash["notifications"]; exists && notificationsStr != "" {
+		t.Notifications = &models.NotificationPreferences{}
+		if err := t.Notifications.UnmarshalBinary([]byte(notificationsStr)); err != nil {
+			return nil, fmt.Errorf("invalid notifications: %w", err)
+		}
+	}
+
+	if lifecycleCallbackStr, exists := hash["lifecycle_callback"]; exists && lifecycleCallbackStr != "" {
+		t.LifecycleCallback = &models.LifecycleCallback{}
+		if err := t.LifecycleCallback.UnmarshalBinary([]byte(lifecycleCallbackStr)); err != nil {
+			return nil, fmt.Errorf("invalid lifecycle_callback: %w", err)
+		}
+	}
+
+	if alertChannelsStr, exists := hash["alert_channels"]; exists && alertChannelsStr != "" {
+		t.AlertChannels = &models.AlertChannels{}
+		if err := t.AlertChannels.UnmarshalBinary([]byte(alertChannelsStr)); err != nil {
+			return nil, fmt.Errorf("invalid alert_channels: %w", err)
+		}
+	}
+
+	if autoDisablePolicyStr, exists := hash["auto_disable_policy"]; exists && autoDisablePolicyStr != "" {
+		t.AutoDisablePolicy = &models.AutoDisablePolicy{}
+		if err := t.AutoDisablePolicy.UnmarshalBinary([]byte(autoDisablePolicyStr)); err != nil {
+			return nil, fmt.Errorf("invalid auto_disable_policy: %w", err)
+		}
+	}
+
+	if destinationTypesStr := hash["destination_types"]; destinationTypesStr != "" {
+		t.DestinationTypes = strings.Split(destinationTypesStr, ",")
+	}
+
+	if portalAllowedIPsStr := hash["portal_allowed_ips"]; portalAllowedIPsStr != "" {
+		t.PortalAllowedIPs = strings.Split(portalAllowedIPsStr, ",")
+	}
+
+	if publishRateLimitStr := hash["publish_rate_limit"]; publishRateLimitStr != "" {
+		t.PublishRateLimit = &models.PublishRateLimit{}
+		if err := t.PublishRateLimit.UnmarshalBinary([]byte(publishRateLimitStr)); err != nil {
+			return nil, fmt.Errorf("invalid publish_rate_limit: %w", err)
+		}
+	}
+
 	return t, nil
 }
 
@@ -121,7 +175,7 @@ func parseDestinationHash(cmd *redis.MapStringStringCmd, tenantID string, cipher
 		return nil, fmt.Errorf("invalid config: %w", err)
 	}
 
-	credentialsBytes, err := cipher.decrypt([]byte(hash["credentials"]))
+	credentialsBytes, err := cipher.decrypt([]byte(hash["credentials"]), hash["encryption_key_id"])
 	if err != nil {
 		return nil, fmt.Errorf("invalid credentials: %w", err)
 	}
@@ -130,7 +184,7 @@ func parseDestinationHash(cmd *redis.MapStringStringCmd, tenantID string, cipher
 	}
 
 	if deliveryMetadataStr, exists := hash["delivery_metadata"]; exists && deliveryMetadataStr != "" {
-		deliveryMetadataBytes, err := cipher.decrypt([]byte(deliveryMetadataStr))
+		deliveryMetadataBytes, err := cipher.decrypt([]byte(deliveryMetadataStr), hash["encryption_key_id"])
 		if err != nil {
 			return nil, fmt.Errorf("invalid delivery_metadata: %w", err)
 		}
@@ -151,6 +205,61 @@ func parseDestinationHash(cmd *redis.MapStringStringCmd, tenantID string, cipher
 		}
 	}
 
+	if recordingStr, exists := hash["recording"]; exists && recordingStr != "" {
+		d.Recording = &models.Recording{}
+		if err := d.Recording.UnmarshalBinary([]byte(recordingStr)); err != nil {
+			return nil, fmt.Errorf("invalid recording: %w", err)
+		}
+	}
+
+	if retryPolicyStr, exists := hash["retry_policy"]; exists && retryPolicyStr != "" {
+		d.RetryPolicy = &models.RetryPolicy{}
+		if err := d.RetryPolicy.UnmarshalBinary([]byte(retryPolicyStr)); err != nil {
+			return nil, fmt.Errorf("invalid retry_policy: %w", err)
+		}
+	}
+
+	if transformationStr, exists := hash["transformation"]; exists && transformationStr != "" {
+		d.Transformation = &models.Transformation{}
+		if err := d.Transformation.UnmarshalBinary([]byte(transformationStr)); err != nil {
+			return nil, fmt.Errorf("invalid transformation: %w", err)
+		}
+	}
+
+	if targetChangeStr, exists := hash["target_change"]; exists && targetChangeStr != "" {
+		d.TargetChange = &models.TargetChange{}
+		if err := d.TargetChange.UnmarshalBinary([]byte(targetChangeStr)); err != nil {
+			return nil, fmt.Errorf("invalid target_change: %w", err)
+		}
+	}
+
+	if maxPayloadStr, exists := hash["max_payload_bytes"]; exists && maxPayloadStr != "" {
+		maxPayload, err := strconv.Atoi(maxPayloadStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_payload_bytes: %w", err)
+		}
+		d.MaxPayloadBytes = maxPayload
+	}
+
+	if maxConcurrencyStr, exists := hash["max_concurrency"]; exists && maxConcurrencyStr != "" {
+		maxConcurrency, err := strconv.Atoi(maxConcurrencyStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_concurrency: %w", err)
+		}
+		d.MaxConcurrency = maxConcurrency
+	}
+
+	if maxRateStr, exists := hash["max_deliveries_per_second"]; exists && maxRateStr != "" {
+		maxRate, err := strconv.Atoi(maxRateStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid max_deliveries_per_second: %w", err)
+		}
+		d.MaxDeliveriesPerSecond = maxRate
+	}
+
+	d.ShadowDestinationID = hash["shadow_destination_id"]
+	d.SandboxSafe = hash["sandbox_safe"] == "true"
+
 	return d, nil
 }
 
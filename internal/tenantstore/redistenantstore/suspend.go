@@ -0,0 +1,308 @@
+package redistenantstore
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/tenantstore/driver"
+)
+
+var _ driver.TenantSuspender = (*store)(nil)
+
+const tenantSnapshotVersion = 1
+
+// suspendTenantScript deletes the tenant's keys and marks it suspended, only
+// if every key still holds what was written to cold storage. A key is
+// fingerprinted by the SHA-1 of its length-prefixed fields and values in field
+// order, or "" when it does not exist.
+//
+// KEYS[1] = suspended marker, KEYS[2..n] = tenant keys
+// ARGV[1] = suspended at (unix milliseconds), ARGV[2..n] = expected fingerprints
+const suspendTenantScript = `
+local function fingerprint(key)
+	local hash = redis.call('HGETALL', key)
+	if #hash == 0 then
+		return ''
+	end
+	local fields = {}
+	local values = {}
+	for i = 1, #hash, 2 do
+		fields[#fields + 1] = hash[i]
+		values[hash[i]] = hash[i + 1]
+	end
+	table.sort(fields)
+	local parts = {}
+	for _, field in ipairs(fields) do
+		local value = values[field]
+		parts[#parts + 1] = #field .. ':' .. field .. #value .. ':' .. value
+	end
+	return redis.sha1hex(table.concat(parts))
+end
+for i = 2, #KEYS do
+	if fingerprint(KEYS[i]) ~= ARGV[i] then
+		return 0
+	end
+end
+for i = 2, #KEYS do
+	redis.call('DEL', KEYS[i])
+end
+redis.call('SET', KEYS[1], ARGV[1])
+return 1
+`
+
+// resumeTenantScript writes the tenant's keys back and clears the suspended
+// marker, unless another caller resumed the tenant first.
+//
+// KEYS[1] = suspended marker, KEYS[2..n] = tenant keys
+// ARGV = for each tenant key, its number of fields followed by its fields and
+// values
+const resumeTenantScript = `
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+local arg = 1
+for i = 2, #KEYS do
+	local count = tonumber(ARGV[arg])
+	arg = arg + 1
+	for _ = 1, count do
+		redis.call('HSET', KEYS[i], ARGV[arg], ARGV[arg + 1])
+		arg = arg + 2
+	end
+end
+redis.call('DEL', KEYS[1])
+return 1
+`
+
+// tenantSnapshot is a suspended tenant as written to cold storage. Hashes
+// holds the tenant's Redis hashes as they were, keyed by the part of their key
+// after "tenant:{<id>}:", so credentials stay encrypted and the snapshot does
+// not depend on the deployment prefix.
+type tenantSnapshot struct {
+	Version     int                          `json:"version"`
+	TenantID    string                       `json:"tenant_id"`
+	SuspendedAt int64                        `json:"suspended_at"` // unix milliseconds
+	Hashes      map[string]map[string]string `json:"hashes"`
+}
+
+func (s *store) redisTenantKey(tenantID, suffix string) string {
+	return fmt.Sprintf("%stenant:{%s}:%s", s.deploymentPrefix(), tenantID, suffix)
+}
+
+func (s *store) redisTenantSuspendedKey(tenantID string) string {
+	return s.redisTenantKey(tenantID, "suspended")
+}
+
+// coldStorageKey returns "tenants/[<deployment>/]<tenant>.json.gz".
+func (s *store) coldStorageKey(tenantID string) string {
+	return path.Join("tenants", s.deploymentID, tenantID+".json.gz")
+}
+
+// SuspendTenant writes the tenant, its destinations and their versions to
+// cold storage, then removes them from Redis. Soft-deleted destinations are
+// left to expire. Suspended tenants are not listed by ListTenant until they
+// are resumed.
+func (s *store) SuspendTenant(ctx context.Context, tenantID string) error {
+	if s.coldStore == nil {
+		return driver.ErrColdStorageNotConfigured
+	}
+
+	suffixes, err := s.tenantKeySuffixes(ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	pipe := s.redisClient.Pipeline()
+	cmds := make([]*redis.MapStringStringCmd, len(suffixes))
+	for i, suffix := range suffixes {
+		cmds[i] = pipe.HGetAll(ctx, s.redisTenantKey(tenantID, suffix))
+	}
+	suspendedCmd := pipe.Exists(ctx, s.redisTenantSuspendedKey(tenantID))
+	if _, err := pipe.Exec(ctx); err != nil {
+		return err
+	}
+
+	hashes := make(map[string]map[string]string, len(suffixes))
+	fingerprints := make([]interface{}, 0, len(suffixes)+1)
+	suspendedAt := time.Now().UnixMilli()
+	fingerprints = append(fingerprints, suspendedAt)
+	for i, suffix := range suffixes {
+		hash, err := cmds[i].Result()
+		if err != nil {
+			return err
+		}
+		if len(hash) > 0 {
+			hashes[suffix] = hash
+		}
+		fingerprints = append(fingerprints, hashFingerprint(hash))
+	}
+
+	tenantHash := hashes["tenant"]
+	if tenantHash == nil {
+		if suspendedCmd.Val() > 0 {
+			return nil
+		}
+		return driver.ErrTenantNotFound
+	}
+	if _, deleted := tenantHash["deleted_at"]; deleted {
+		return driver.ErrTenantDeleted
+	}
+
+	body, err := encodeTenantSnapshot(tenantSnapshot{
+		Version:     tenantSnapshotVersion,
+		TenantID:    tenantID,
+		SuspendedAt: suspendedAt,
+		Hashes:      hashes,
+	})
+	if err != nil {
+		return err
+	}
+	if err := s.coldStore.Put(ctx, s.coldStorageKey(tenantID), body, "application/gzip"); err != nil {
+		return fmt.Errorf("failed to write tenant snapshot: %w", err)
+	}
+
+	keys := make([]string, 0, len(suffixes)+1)
+	keys = append(keys, s.redisTenantSuspendedKey(tenantID))
+	for _, suffix := range suffixes {
+		keys = append(keys, s.redisTenantKey(tenantID, suffix))
+	}
+	suspended, err := s.redisClient.Eval(ctx, suspendTenantScript, keys, fingerprints...).Int()
+	if err != nil {
+		return fmt.Errorf("failed to suspend tenant: %w", err)
+	}
+	if suspended == 0 {
+		return driver.ErrTenantModified
+	}
+	return nil
+}
+
+// tenantKeySuffixes returns the suffixes of the tenant's keys: the tenant, its
+// destination summary, and each live destination and its versions.
+func (s *store) tenantKeySuffixes(ctx context.Context, tenantID string) ([]string, error) {
+	destinationIDs, err := s.redisClient.HKeys(ctx, s.redisTenantDestinationSummaryKey(tenantID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(destinationIDs)
+	suffixes := []string{"tenant", "destinations"}
+	for _, destinationID := range destinationIDs {
+		suffixes = append(suffixes, "destination:"+destinationID, "destination_versions:"+destinationID)
+	}
+	return suffixes, nil
+}
+
+// resumeTenant restores a suspended tenant from cold storage. It reports
+// whether the tenant was suspended. Concurrent calls for a tenant share one
+// restore.
+func (s *store) resumeTenant(ctx context.Context, tenantID string) (bool, error) {
+	resumed, err, _ := s.resumes.Do(tenantID, func() (interface{}, error) {
+		marker := s.redisTenantSuspendedKey(tenantID)
+		suspended, err := s.redisClient.Exists(ctx, marker).Result()
+		if err != nil {
+			return false, err
+		}
+		if suspended == 0 {
+			return false, nil
+		}
+		if s.coldStore == nil {
+			return false, fmt.Errorf("tenant %s is suspended: %w", tenantID, driver.ErrColdStorageNotConfigured)
+		}
+
+		body, err := s.coldStore.Get(ctx, s.coldStorageKey(tenantID))
+		if err != nil {
+			return false, fmt.Errorf("failed to read tenant snapshot: %w", err)
+		}
+		snapshot, err := decodeTenantSnapshot(body)
+		if err != nil {
+			return false, err
+		}
+		if snapshot.TenantID != tenantID {
+			return false, fmt.Errorf("invalid tenant snapshot: holds tenant %q", snapshot.TenantID)
+		}
+
+		suffixes := make([]string, 0, len(snapshot.Hashes))
+		for suffix := range snapshot.Hashes {
+			suffixes = append(suffixes, suffix)
+		}
+		sort.Strings(suffixes)
+		keys := []string{marker}
+		var args []interface{}
+		for _, suffix := range suffixes {
+			hash := snapshot.Hashes[suffix]
+			keys = append(keys, s.redisTenantKey(tenantID, suffix))
+			args = append(args, len(hash))
+			for field, value := range hash {
+				args = append(args, field, value)
+			}
+		}
+		if err := s.redisClient.Eval(ctx, resumeTenantScript, keys, args...).Err(); err != nil {
+			return false, fmt.Errorf("failed to resume tenant: %w", err)
+		}
+		return true, nil
+	})
+	if err != nil {
+		return false, err
+	}
+	return resumed.(bool), nil
+}
+
+// hashFingerprint fingerprints a hash the way suspendTenantScript does.
+func hashFingerprint(hash map[string]string) string {
+	if len(hash) == 0 {
+		return ""
+	}
+	fields := make([]string, 0, len(hash))
+	for field := range hash {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	var b strings.Builder
+	for _, field := range fields {
+		value := hash[field]
+		b.WriteString(strconv.Itoa(len(field)) + ":" + field + strconv.Itoa(len(value)) + ":" + value)
+	}
+	sum := sha1.Sum([]byte(b.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+func encodeTenantSnapshot(snapshot tenantSnapshot) ([]byte, error) {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	if err := json.NewEncoder(gz).Encode(snapshot); err != nil {
+		return nil, fmt.Errorf("failed to encode tenant snapshot: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode tenant snapshot: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func decodeTenantSnapshot(body []byte) (*tenantSnapshot, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid tenant snapshot: %w", err)
+	}
+	defer gz.Close()
+	data, err := io.ReadAll(gz)
+	if err != nil {
+		return nil, fmt.Errorf("invalid tenant snapshot: %w", err)
+	}
+	var snapshot tenantSnapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid tenant snapshot: %w", err)
+	}
+	if snapshot.Version != tenantSnapshotVersion {
+		return nil, fmt.Errorf("invalid tenant snapshot: unsupported version %d", snapshot.Version)
+	}
+	return &snapshot, nil
+}
@@ -0,0 +1,144 @@
+package redistenantstore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/tenantstore/driver"
+)
+
+// maxDestinationVersions is the number of versions retained per destination.
+const maxDestinationVersions = 20
+
+var _ driver.DestinationVersioner = (*store)(nil)
+
+// Versions of a destination live in one hash next to the destination: "seq"
+// holds the last version number and "v:{version}" each retained version.
+const destinationVersionFieldPrefix = "v:"
+
+// createDestinationVersionScript assigns the next version number, stores the
+// version and drops the one falling out of the retention window.
+//
+// KEYS[1] = versions key
+// ARGV[1] = version record, ARGV[2] = number of versions to retain
+const createDestinationVersionScript = `
+local version = redis.call('HINCRBY', KEYS[1], 'seq', 1)
+redis.call('HSET', KEYS[1], 'v:' .. version, ARGV[1])
+local expired = version - tonumber(ARGV[2])
+if expired > 0 then
+	redis.call('HDEL', KEYS[1], 'v:' .. expired)
+end
+redis.call('PERSIST', KEYS[1])
+return version
+`
+
+// destinationVersionRecord is a version as stored in Redis. The destination
+// is encrypted as a whole since it carries credentials.
+type destinationVersionRecord struct {
+	Actor       string `json:"actor"`
+	CreatedAt   int64  `json:"created_at"` // unix milliseconds
+	KeyID       string `json:"key_id,omitempty"`
+	Destination []byte `json:"destination"`
+}
+
+func (s *store) redisDestinationVersionsKey(destinationID, tenantID string) string {
+	return fmt.Sprintf("%stenant:{%s}:destination_versions:%s", s.deploymentPrefix(), tenantID, destinationID)
+}
+
+func (s *store) CreateDestinationVersion(ctx context.Context, destination models.Destination, actor string) (*driver.DestinationVersion, error) {
+	destinationBytes, err := json.Marshal(destination)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination: %w", err)
+	}
+	encryptedDestination, err := s.cipher.encrypt(destinationBytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt destination version: %w", err)
+	}
+	createdAt := time.Now()
+	record, err := json.Marshal(destinationVersionRecord{
+		Actor:       actor,
+		CreatedAt:   createdAt.UnixMilli(),
+		KeyID:       s.cipher.primaryKeyID(),
+		Destination: encryptedDestination,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	key := s.redisDestinationVersionsKey(destination.ID, destination.TenantID)
+	version, err := s.redisClient.Eval(ctx, createDestinationVersionScript, []string{key}, string(record), maxDestinationVersions).Int()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create destination version: %w", err)
+	}
+	return &driver.DestinationVersion{
+		Version:     version,
+		Destination: destination,
+		Actor:       actor,
+		CreatedAt:   time.UnixMilli(createdAt.UnixMilli()),
+	}, nil
+}
+
+func (s *store) ListDestinationVersion(ctx context.Context, tenantID, destinationID string) ([]driver.DestinationVersion, error) {
+	hash, err := s.redisClient.HGetAll(ctx, s.redisDestinationVersionsKey(destinationID, tenantID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	versions := make([]driver.DestinationVersion, 0, len(hash))
+	for field, value := range hash {
+		if !strings.HasPrefix(field, destinationVersionFieldPrefix) {
+			continue
+		}
+		number, err := strconv.Atoi(strings.TrimPrefix(field, destinationVersionFieldPrefix))
+		if err != nil {
+			return nil, fmt.Errorf("invalid destination version %q", field)
+		}
+		version, err := s.parseDestinationVersion(number, value)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		return versions[i].Version > versions[j].Version
+	})
+	return versions, nil
+}
+
+func (s *store) RetrieveDestinationVersion(ctx context.Context, tenantID, destinationID string, version int) (*driver.DestinationVersion, error) {
+	field := destinationVersionFieldPrefix + strconv.Itoa(version)
+	value, err := s.redisClient.HGet(ctx, s.redisDestinationVersionsKey(destinationID, tenantID), field).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, driver.ErrDestinationVersionNotFound
+		}
+		return nil, err
+	}
+	return s.parseDestinationVersion(version, value)
+}
+
+func (s *store) parseDestinationVersion(version int, value string) (*driver.DestinationVersion, error) {
+	var record destinationVersionRecord
+	if err := json.Unmarshal([]byte(value), &record); err != nil {
+		return nil, fmt.Errorf("invalid destination version %d: %w", version, err)
+	}
+	destinationBytes, err := s.cipher.decrypt(record.Destination, record.KeyID)
+	if err != nil {
+		return nil, fmt.Errorf("invalid destination version %d: %w", version, err)
+	}
+	v := &driver.DestinationVersion{
+		Version:   version,
+		Actor:     record.Actor,
+		CreatedAt: time.UnixMilli(record.CreatedAt),
+	}
+	if err := json.Unmarshal(destinationBytes, &v.Destination); err != nil {
+		return nil, fmt.Errorf("invalid destination version %d: %w", version, err)
+	}
+	return v, nil
+}
@@ -2,6 +2,9 @@
 package tenantstore
 
 import (
+	"context"
+
+	"github.com/hookdeck/outpost/internal/models"
 	"github.com/hookdeck/outpost/internal/redis"
 	"github.com/hookdeck/outpost/internal/tenantstore/driver"
 	"github.com/hookdeck/outpost/internal/tenantstore/memtenantstore"
@@ -14,6 +17,13 @@ type ListTenantRequest = driver.ListTenantRequest
 type SeekPagination = driver.SeekPagination
 type TenantPaginatedResult = driver.TenantPaginatedResult
 type ListDestinationRequest = driver.ListDestinationRequest
+type CredentialReEncrypter = driver.CredentialReEncrypter
+type ReEncryptOptions = driver.ReEncryptOptions
+type ReEncryptResult = driver.ReEncryptResult
+type DestinationVersioner = driver.DestinationVersioner
+type DestinationVersion = driver.DestinationVersion
+type TenantSuspender = driver.TenantSuspender
+type ColdStore = driver.ColdStore
 
 // Error sentinels re-exported from driver.
 var (
@@ -22,20 +32,28 @@ var (
 	ErrDuplicateDestination            = driver.ErrDuplicateDestination
 	ErrDestinationNotFound             = driver.ErrDestinationNotFound
 	ErrDestinationDeleted              = driver.ErrDestinationDeleted
+	ErrDestinationVersionNotFound      = driver.ErrDestinationVersionNotFound
 	ErrMaxDestinationsPerTenantReached = driver.ErrMaxDestinationsPerTenantReached
 	ErrListTenantNotSupported          = driver.ErrListTenantNotSupported
 	ErrInvalidCursor                   = driver.ErrInvalidCursor
 	ErrInvalidOrder                    = driver.ErrInvalidOrder
 	ErrConflictingCursors              = driver.ErrConflictingCursors
+	ErrColdStorageNotConfigured        = driver.ErrColdStorageNotConfigured
+	ErrTenantModified                  = driver.ErrTenantModified
 )
 
 // Config holds the configuration for creating a TenantStore.
 type Config struct {
 	RedisClient              redis.Cmdable
 	Secret                   string
+	PreviousSecrets          []string
+	EncryptionKeys           map[string]string // key ring by key ID
+	PrimaryKeyID             string
 	AvailableTopics          []string
 	MaxDestinationsPerTenant int
 	DeploymentID             string
+	ColdStore                ColdStore // optional — enables suspending tenants
+	ChangefeedMaxLen         int64     // optional — records changes for replication
 }
 
 // New creates a new Redis-backed TenantStore.
@@ -44,6 +62,12 @@ func New(cfg Config) TenantStore {
 	if cfg.Secret != "" {
 		opts = append(opts, redistenantstore.WithSecret(cfg.Secret))
 	}
+	if len(cfg.PreviousSecrets) > 0 {
+		opts = append(opts, redistenantstore.WithPreviousSecrets(cfg.PreviousSecrets))
+	}
+	if len(cfg.EncryptionKeys) > 0 {
+		opts = append(opts, redistenantstore.WithEncryptionKeys(cfg.EncryptionKeys, cfg.PrimaryKeyID))
+	}
 	if len(cfg.AvailableTopics) > 0 {
 		opts = append(opts, redistenantstore.WithAvailableTopics(cfg.AvailableTopics))
 	}
@@ -53,6 +77,12 @@ func New(cfg Config) TenantStore {
 	if cfg.DeploymentID != "" {
 		opts = append(opts, redistenantstore.WithDeploymentID(cfg.DeploymentID))
 	}
+	if cfg.ColdStore != nil {
+		opts = append(opts, redistenantstore.WithColdStore(cfg.ColdStore))
+	}
+	if cfg.ChangefeedMaxLen > 0 {
+		opts = append(opts, redistenantstore.WithChangefeed(cfg.ChangefeedMaxLen))
+	}
 	return redistenantstore.New(cfg.RedisClient, opts...)
 }
 
@@ -60,3 +90,27 @@ func New(cfg Config) TenantStore {
 func NewMemTenantStore() TenantStore {
 	return memtenantstore.New()
 }
+
+// RecordDestinationVersion saves destination as a new version, by actor, when
+// the store keeps versions. previous is the destination before the change,
+// nil on create; it is saved first if the destination predates versioning,
+// so the change can be rolled back.
+func RecordDestinationVersion(ctx context.Context, store TenantStore, previous, destination *models.Destination, actor string) error {
+	versioner, ok := store.(DestinationVersioner)
+	if !ok {
+		return nil
+	}
+	if previous != nil {
+		versions, err := versioner.ListDestinationVersion(ctx, previous.TenantID, previous.ID)
+		if err != nil {
+			return err
+		}
+		if len(versions) == 0 {
+			if _, err := versioner.CreateDestinationVersion(ctx, *previous, ""); err != nil {
+				return err
+			}
+		}
+	}
+	_, err := versioner.CreateDestinationVersion(ctx, *destination, actor)
+	return err
+}
@@ -0,0 +1,99 @@
+// Package tokenstore keeps short-lived values in Redis under random,
+// unguessable tokens.
+//
+// The token is handed to a destination as the only credential needed to act
+// on the value, such as acknowledging a delivery or fetching an offloaded
+// payload. Each value is a key that expires with its TTL.
+package tokenstore
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/redis"
+)
+
+var ErrNotFound = errors.New("token not found")
+
+// NewToken returns a random, unguessable token.
+func NewToken() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate token: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Store keeps values of type T as JSON under "[<deployment>:]<prefix>:<token>".
+type Store[T any] struct {
+	redisClient  redis.Cmdable
+	prefix       string
+	deploymentID string
+}
+
+type options struct {
+	deploymentID string
+}
+
+type Option func(*options)
+
+func WithDeploymentID(deploymentID string) Option {
+	return func(o *options) {
+		o.deploymentID = deploymentID
+	}
+}
+
+// New returns a Store whose keys are namespaced by prefix.
+func New[T any](redisClient redis.Cmdable, prefix string, opts ...Option) *Store[T] {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return &Store[T]{
+		redisClient:  redisClient,
+		prefix:       prefix,
+		deploymentID: o.deploymentID,
+	}
+}
+
+func (s *Store[T]) key(token string) string {
+	if s.deploymentID == "" {
+		return s.prefix + ":" + token
+	}
+	return s.deploymentID + ":" + s.prefix + ":" + token
+}
+
+// Set stores value under token until ttl elapses.
+func (s *Store[T]) Set(ctx context.Context, token string, value T, ttl time.Duration) error {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.redisClient.Set(ctx, s.key(token), encoded, ttl).Err()
+}
+
+// Get returns the value for token, or ErrNotFound once it was deleted or
+// expired.
+func (s *Store[T]) Get(ctx context.Context, token string) (*T, error) {
+	encoded, err := s.redisClient.Get(ctx, s.key(token)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	var value T
+	if err := json.Unmarshal(encoded, &value); err != nil {
+		return nil, fmt.Errorf("invalid %s value: %w", s.prefix, err)
+	}
+	return &value, nil
+}
+
+func (s *Store[T]) Delete(ctx context.Context, token string) error {
+	return s.redisClient.Del(ctx, s.key(token)).Err()
+}
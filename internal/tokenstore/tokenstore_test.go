@@ -0,0 +1,66 @@
+package tokenstore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/tokenstore"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type value struct {
+	ID string `json:"id"`
+}
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	t.Run("sets and gets a value", func(t *testing.T) {
+		t.Parallel()
+		store := tokenstore.New[value](testutil.CreateTestRedisClient(t), "test")
+
+		require.NoError(t, store.Set(t.Context(), "token", value{ID: "v1"}, time.Minute))
+
+		got, err := store.Get(t.Context(), "token")
+		require.NoError(t, err)
+		assert.Equal(t, value{ID: "v1"}, *got)
+	})
+
+	t.Run("deleted token is not found", func(t *testing.T) {
+		t.Parallel()
+		store := tokenstore.New[value](testutil.CreateTestRedisClient(t), "test")
+
+		require.NoError(t, store.Set(t.Context(), "token", value{ID: "v1"}, time.Minute))
+		require.NoError(t, store.Delete(t.Context(), "token"))
+
+		_, err := store.Get(t.Context(), "token")
+		assert.ErrorIs(t, err, tokenstore.ErrNotFound)
+	})
+
+	t.Run("tokens are scoped to the prefix and deployment", func(t *testing.T) {
+		t.Parallel()
+		redisClient := testutil.CreateTestRedisClient(t)
+		store := tokenstore.New[value](redisClient, "test", tokenstore.WithDeploymentID("dp1"))
+
+		require.NoError(t, store.Set(t.Context(), "token", value{ID: "v1"}, time.Minute))
+
+		_, err := tokenstore.New[value](redisClient, "test").Get(t.Context(), "token")
+		assert.ErrorIs(t, err, tokenstore.ErrNotFound)
+		_, err = tokenstore.New[value](redisClient, "other", tokenstore.WithDeploymentID("dp1")).Get(t.Context(), "token")
+		assert.ErrorIs(t, err, tokenstore.ErrNotFound)
+		_, err = store.Get(t.Context(), "token")
+		assert.NoError(t, err)
+	})
+
+	t.Run("tokens are unique", func(t *testing.T) {
+		t.Parallel()
+		a, err := tokenstore.NewToken()
+		require.NoError(t, err)
+		b, err := tokenstore.NewToken()
+		require.NoError(t, err)
+		assert.Len(t, a, 64)
+		assert.NotEqual(t, a, b)
+	})
+}
@@ -0,0 +1,338 @@
+// Package topicstats tracks the volume and payload sizes of published events
+// per topic, for the deployment and for each tenant, to plan capacity without
+// exporting raw logs.
+//
+// Each accepted event is recorded in a Redis hash per day: one for the
+// deployment and one for the event's tenant. A hash holds, per topic, the
+// event count, the total payload bytes and a histogram of payload sizes in
+// power-of-two buckets, from which percentiles are estimated. Days are kept
+// for the retention period after they end.
+package topicstats
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/redis"
+)
+
+// DefaultRetentionDays is how many days of stats are kept by default.
+const DefaultRetentionDays = 90
+
+const day = 24 * time.Hour
+
+// Payload sizes are bucketed by the power of two above them, from
+// 2^minBucket bytes; larger sizes fall in the 2^maxBucket bucket.
+const (
+	minBucket = 6
+	maxBucket = 30
+)
+
+const (
+	suffixCount = "|count"
+	suffixBytes = "|bytes"
+	// suffixBucket is followed by the bucket's exponent
+	suffixBucket = "|le"
+)
+
+// ErrInvalidRange is returned when a report covers no days or more days than
+// are kept.
+var ErrInvalidRange = errors.New("topicstats: days must be between 1 and the retention period")
+
+// Store records and reports topic stats.
+type Store struct {
+	redisClient   redis.Cmdable
+	deploymentID  string
+	retentionDays int
+	clock         clock.Clock
+}
+
+type Option func(*Store)
+
+func WithDeploymentID(deploymentID string) Option {
+	return func(s *Store) {
+		s.deploymentID = deploymentID
+	}
+}
+
+// WithRetentionDays sets how many days of stats are kept.
+func WithRetentionDays(days int) Option {
+	return func(s *Store) {
+		s.retentionDays = days
+	}
+}
+
+func WithClock(c clock.Clock) Option {
+	return func(s *Store) {
+		s.clock = c
+	}
+}
+
+// NewStore returns a store of topic stats in Redis.
+func NewStore(redisClient redis.Cmdable, opts ...Option) *Store {
+	s := &Store{
+		redisClient:   redisClient,
+		retentionDays: DefaultRetentionDays,
+		clock:         clock.New(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// RetentionDays returns how many days of stats are kept.
+func (s *Store) RetentionDays() int {
+	return s.retentionDays
+}
+
+// key returns the day's hash of the tenant, or of the deployment when
+// tenantID is empty. The scope is a hash tag so its days share a cluster
+// slot and are read in one pipeline.
+func (s *Store) key(tenantID string, date time.Time) string {
+	scope := "deployment:{deployment}"
+	if tenantID != "" {
+		scope = "tenant:{" + tenantID + "}"
+	}
+	key := "topicstats:" + scope + ":" + date.Format(time.DateOnly)
+	if s.deploymentID == "" {
+		return key
+	}
+	return s.deploymentID + ":" + key
+}
+
+// Record counts an event of the tenant on the topic, with a payload of size
+// bytes.
+func (s *Store) Record(ctx context.Context, tenantID, topic string, size int) error {
+	date := s.clock.Now().UTC().Truncate(day)
+	// Kept for the retention period after the day ends
+	ttl := time.Duration(s.retentionDays+1) * day
+	bucket := suffixBucket + strconv.Itoa(bucketOf(size))
+
+	_, err := s.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for _, key := range []string{s.key("", date), s.key(tenantID, date)} {
+			pipe.HIncrBy(ctx, key, topic+suffixCount, 1)
+			pipe.HIncrBy(ctx, key, topic+suffixBytes, int64(size))
+			pipe.HIncrBy(ctx, key, topic+bucket, 1)
+			pipe.Expire(ctx, key, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// bucketOf returns the exponent of the bucket of a payload size.
+func bucketOf(size int) int {
+	if size <= 1<<minBucket {
+		return minBucket
+	}
+	return min(bits.Len(uint(size-1)), maxBucket)
+}
+
+// Report is the topic stats of the deployment, or of a tenant, over the
+// days up to and including today.
+type Report struct {
+	TenantID string `json:"tenant_id,omitempty"`
+	// Start is the first day covered and End the end of the last.
+	Start time.Time `json:"start"`
+	End   time.Time `json:"end"`
+	// Count and Bytes total the events of every topic, not only the topics
+	// listed.
+	Count  int64        `json:"count"`
+	Bytes  int64        `json:"bytes"`
+	Topics []TopicStats `json:"topics"`
+}
+
+// TopicStats is the volume and payload sizes of a topic's events. Payload
+// sizes are in bytes; percentiles are estimated from the size histogram.
+type TopicStats struct {
+	Topic string `json:"topic"`
+	Count int64  `json:"count"`
+	// Share is the topic's fraction of all events.
+	Share   float64 `json:"share"`
+	Bytes   int64   `json:"bytes"`
+	AvgSize float64 `json:"avg_size"`
+	P50Size int64   `json:"p50_size"`
+	P95Size int64   `json:"p95_size"`
+	P99Size int64   `json:"p99_size"`
+	// Growth is the change in count from the first half of the days to the
+	// second, as a fraction of the first half. It is nil when the first half
+	// has no events or the report covers a single day.
+	Growth *float64 `json:"growth"`
+	Daily  []Day    `json:"daily"`
+}
+
+// Day is a topic's volume on one day.
+type Day struct {
+	Date  string `json:"date"`
+	Count int64  `json:"count"`
+	Bytes int64  `json:"bytes"`
+}
+
+type topicTotals struct {
+	count   int64
+	bytes   int64
+	buckets [maxBucket + 1]int64
+	daily   []Day
+}
+
+// Report returns the stats of the tenant's topics, or the deployment's when
+// tenantID is empty, over the last days days, busiest topics first. With a
+// positive limit, only that many topics are listed.
+func (s *Store) Report(ctx context.Context, tenantID string, days, limit int) (*Report, error) {
+	if days < 1 || days > s.retentionDays {
+		return nil, ErrInvalidRange
+	}
+	today := s.clock.Now().UTC().Truncate(day)
+	start := today.Add(-time.Duration(days-1) * day)
+
+	cmds := make([]*redis.MapStringStringCmd, days)
+	_, err := s.redisClient.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		for i := range days {
+			cmds[i] = pipe.HGetAll(ctx, s.key(tenantID, start.Add(time.Duration(i)*day)))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{
+		TenantID: tenantID,
+		Start:    start,
+		End:      today.Add(day),
+		Topics:   []TopicStats{},
+	}
+	totals := make(map[string]*topicTotals)
+	for i, cmd := range cmds {
+		for field, raw := range cmd.Val() {
+			topic, stat, ok := splitField(field)
+			if !ok {
+				continue
+			}
+			value, err := strconv.ParseInt(raw, 10, 64)
+			if err != nil {
+				continue
+			}
+			t := totals[topic]
+			if t == nil {
+				t = &topicTotals{daily: make([]Day, days)}
+				for j := range t.daily {
+					t.daily[j].Date = start.Add(time.Duration(j) * day).Format(time.DateOnly)
+				}
+				totals[topic] = t
+			}
+			switch {
+			case stat == suffixCount:
+				t.count += value
+				t.daily[i].Count += value
+				report.Count += value
+			case stat == suffixBytes:
+				t.bytes += value
+				t.daily[i].Bytes += value
+				report.Bytes += value
+			case strings.HasPrefix(stat, suffixBucket):
+				exp, err := strconv.Atoi(strings.TrimPrefix(stat, suffixBucket))
+				if err == nil && exp >= minBucket && exp <= maxBucket {
+					t.buckets[exp] += value
+				}
+			}
+		}
+	}
+
+	for topic, t := range totals {
+		if t.count == 0 {
+			continue
+		}
+		stats := TopicStats{
+			Topic:   topic,
+			Count:   t.count,
+			Share:   float64(t.count) / float64(report.Count),
+			Bytes:   t.bytes,
+			AvgSize: float64(t.bytes) / float64(t.count),
+			P50Size: t.percentile(0.50),
+			P95Size: t.percentile(0.95),
+			P99Size: t.percentile(0.99),
+			Growth:  growth(t.daily),
+			Daily:   t.daily,
+		}
+		report.Topics = append(report.Topics, stats)
+	}
+	sort.Slice(report.Topics, func(i, j int) bool {
+		if report.Topics[i].Count != report.Topics[j].Count {
+			return report.Topics[i].Count > report.Topics[j].Count
+		}
+		return report.Topics[i].Topic < report.Topics[j].Topic
+	})
+	if limit > 0 && len(report.Topics) > limit {
+		report.Topics = report.Topics[:limit]
+	}
+	return report, nil
+}
+
+// splitField splits a hash field into its topic and stat. Topics may contain
+// the separator, stats don't.
+func splitField(field string) (topic, stat string, ok bool) {
+	i := strings.LastIndex(field, "|")
+	if i < 0 {
+		return "", "", false
+	}
+	return field[:i], field[i:], true
+}
+
+// percentile estimates the payload size below which the fraction p of the
+// topic's events fall, interpolating within the histogram bucket it lands in.
+func (t *topicTotals) percentile(p float64) int64 {
+	var total int64
+	for _, n := range t.buckets {
+		total += n
+	}
+	if total == 0 {
+		return 0
+	}
+	rank := p * float64(total)
+	var seen int64
+	for exp := minBucket; exp <= maxBucket; exp++ {
+		n := t.buckets[exp]
+		if n == 0 {
+			continue
+		}
+		if float64(seen+n) >= rank {
+			upper := float64(int64(1) << exp)
+			lower := 0.0
+			if exp > minBucket {
+				lower = upper / 2
+			}
+			within := (rank - float64(seen)) / float64(n)
+			return int64(math.Ceil(lower + within*(upper-lower)))
+		}
+		seen += n
+	}
+	return int64(1) << maxBucket
+}
+
+// growth compares the counts of the second half of the days to the first.
+// With an odd number of days, the middle day is left out.
+func growth(daily []Day) *float64 {
+	half := len(daily) / 2
+	if half == 0 {
+		return nil
+	}
+	var first, second int64
+	for i := range half {
+		first += daily[i].Count
+		second += daily[len(daily)-half+i].Count
+	}
+	if first == 0 {
+		return nil
+	}
+	g := float64(second-first) / float64(first)
+	return &g
+}
@@ -0,0 +1,134 @@
+package topicstats_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/topicstats"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	start := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	t.Run("reports topics by volume", func(t *testing.T) {
+		t.Parallel()
+		store := topicstats.NewStore(testutil.CreateTestRedisClient(t), topicstats.WithClock(clock.NewFake(start)))
+
+		for range 3 {
+			require.NoError(t, store.Record(t.Context(), "t1", "user.created", 100))
+		}
+		require.NoError(t, store.Record(t.Context(), "t1", "order.paid", 1000))
+
+		report, err := store.Report(t.Context(), "", 1, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(4), report.Count)
+		assert.Equal(t, int64(1300), report.Bytes)
+		require.Len(t, report.Topics, 2)
+
+		top := report.Topics[0]
+		assert.Equal(t, "user.created", top.Topic)
+		assert.Equal(t, int64(3), top.Count)
+		assert.Equal(t, 0.75, top.Share)
+		assert.Equal(t, 100.0, top.AvgSize)
+		assert.Equal(t, int64(300), top.Bytes)
+		// 100 bytes lands in the (64, 128] bucket
+		assert.Greater(t, top.P50Size, int64(64))
+		assert.LessOrEqual(t, top.P99Size, int64(128))
+		assert.Nil(t, top.Growth, "a single day has no growth")
+		require.Len(t, top.Daily, 1)
+		assert.Equal(t, "2026-01-10", top.Daily[0].Date)
+
+		assert.Equal(t, "order.paid", report.Topics[1].Topic)
+	})
+
+	t.Run("limit keeps the busiest topics", func(t *testing.T) {
+		t.Parallel()
+		store := topicstats.NewStore(testutil.CreateTestRedisClient(t), topicstats.WithClock(clock.NewFake(start)))
+
+		require.NoError(t, store.Record(t.Context(), "t1", "a", 10))
+		require.NoError(t, store.Record(t.Context(), "t1", "b", 10))
+		require.NoError(t, store.Record(t.Context(), "t1", "b", 10))
+
+		report, err := store.Report(t.Context(), "", 1, 1)
+		require.NoError(t, err)
+		require.Len(t, report.Topics, 1)
+		assert.Equal(t, "b", report.Topics[0].Topic)
+		assert.Equal(t, int64(3), report.Count, "totals cover unlisted topics")
+	})
+
+	t.Run("tenant reports only count the tenant's events", func(t *testing.T) {
+		t.Parallel()
+		store := topicstats.NewStore(testutil.CreateTestRedisClient(t), topicstats.WithClock(clock.NewFake(start)))
+
+		require.NoError(t, store.Record(t.Context(), "t1", "user.created", 10))
+		require.NoError(t, store.Record(t.Context(), "t2", "user.created", 10))
+
+		report, err := store.Report(t.Context(), "t1", 1, 0)
+		require.NoError(t, err)
+		assert.Equal(t, "t1", report.TenantID)
+		assert.Equal(t, int64(1), report.Count)
+
+		report, err = store.Report(t.Context(), "", 1, 0)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2), report.Count)
+	})
+
+	t.Run("growth compares the halves of the range", func(t *testing.T) {
+		t.Parallel()
+		clk := clock.NewFake(start)
+		store := topicstats.NewStore(testutil.CreateTestRedisClient(t), topicstats.WithClock(clk))
+
+		for i, count := range []int{2, 2, 3, 5} {
+			if i > 0 {
+				clk.Advance(24 * time.Hour)
+			}
+			for range count {
+				require.NoError(t, store.Record(t.Context(), "t1", "user.created", 10))
+			}
+		}
+
+		report, err := store.Report(t.Context(), "", 4, 0)
+		require.NoError(t, err)
+		require.Len(t, report.Topics, 1)
+		require.NotNil(t, report.Topics[0].Growth)
+		assert.Equal(t, 1.0, *report.Topics[0].Growth)
+		require.Len(t, report.Topics[0].Daily, 4)
+		assert.Equal(t, int64(5), report.Topics[0].Daily[3].Count)
+		assert.Equal(t, "2026-01-13", report.Topics[0].Daily[3].Date)
+	})
+
+	t.Run("percentiles follow the size distribution", func(t *testing.T) {
+		t.Parallel()
+		store := topicstats.NewStore(testutil.CreateTestRedisClient(t), topicstats.WithClock(clock.NewFake(start)))
+
+		for range 98 {
+			require.NoError(t, store.Record(t.Context(), "t1", "user.created", 500))
+		}
+		for range 2 {
+			require.NoError(t, store.Record(t.Context(), "t1", "user.created", 50_000))
+		}
+
+		report, err := store.Report(t.Context(), "", 1, 0)
+		require.NoError(t, err)
+		stats := report.Topics[0]
+		assert.LessOrEqual(t, stats.P50Size, int64(512))
+		assert.LessOrEqual(t, stats.P95Size, int64(512))
+		assert.Greater(t, stats.P99Size, int64(32_768))
+	})
+
+	t.Run("rejects ranges beyond retention", func(t *testing.T) {
+		t.Parallel()
+		store := topicstats.NewStore(testutil.CreateTestRedisClient(t), topicstats.WithRetentionDays(7))
+
+		_, err := store.Report(t.Context(), "", 8, 0)
+		assert.ErrorIs(t, err, topicstats.ErrInvalidRange)
+		_, err = store.Report(t.Context(), "", 0, 0)
+		assert.ErrorIs(t, err, topicstats.ErrInvalidRange)
+	})
+}
@@ -0,0 +1,274 @@
+// Package topicstore manages the topics created at runtime through the API,
+// alongside the topics configured with TOPICS.
+//
+// Runtime topics are stored in a Redis set shared by every service, and each
+// process caches them for the refresh interval, so a topic created or deleted
+// on one API replica is picked up by the others within it.
+//
+// The lifecycle state of topics is managed the same way: the deprecated and
+// retired topics configured with TOPICS_DEPRECATED and TOPICS_RETIRED seed it,
+// and states set through the API, stored in a Redis hash, override them.
+package topicstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/redis"
+)
+
+const (
+	// DefaultRefreshInterval is how long runtime topics are cached when no
+	// refresh interval is set.
+	DefaultRefreshInterval = 5 * time.Second
+
+	// MaxTopicLength caps the length of a runtime topic.
+	MaxTopicLength = 255
+)
+
+var (
+	ErrInvalidTopic    = errors.New("topic must be 1 to 255 characters without whitespace, commas or slashes")
+	ErrConfiguredTopic = errors.New("topic is configured with TOPICS and can't be deleted")
+	ErrTopicNotFound   = errors.New("topic not found")
+	ErrInvalidStatus   = errors.New("status must be one of active, deprecated or retired")
+)
+
+// Store lists the configured and runtime topics, creates and deletes runtime
+// topics, and sets the lifecycle state of topics.
+type Store struct {
+	redisClient     redis.Cmdable
+	configured      []string
+	seed            models.TopicLifecycle
+	deploymentID    string
+	refreshInterval time.Duration
+	clock           clock.Clock
+
+	mu        sync.Mutex
+	topics    []string
+	lifecycle models.TopicLifecycle
+	fetchedAt time.Time
+}
+
+type Option func(*Store)
+
+func WithDeploymentID(deploymentID string) Option {
+	return func(s *Store) {
+		s.deploymentID = deploymentID
+	}
+}
+
+// WithRefreshInterval sets how long runtime topics are cached.
+func WithRefreshInterval(interval time.Duration) Option {
+	return func(s *Store) {
+		if interval > 0 {
+			s.refreshInterval = interval
+		}
+	}
+}
+
+// WithLifecycle seeds the lifecycle state of topics, such as with the topics
+// configured with TOPICS_DEPRECATED and TOPICS_RETIRED. States set with
+// SetStatus override it.
+func WithLifecycle(seed models.TopicLifecycle) Option {
+	return func(s *Store) {
+		s.seed = seed
+	}
+}
+
+func WithClock(c clock.Clock) Option {
+	return func(s *Store) {
+		s.clock = c
+	}
+}
+
+// New returns a store of the runtime topics in Redis, listed after the
+// configured topics.
+func New(redisClient redis.Cmdable, configured []string, opts ...Option) *Store {
+	s := &Store{
+		redisClient:     redisClient,
+		configured:      configured,
+		refreshInterval: DefaultRefreshInterval,
+		clock:           clock.New(),
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	s.topics = configured
+	s.lifecycle = s.seed
+	return s
+}
+
+// Topics returns the configured topics followed by the runtime topics. When
+// the runtime topics can't be read, the ones last read are returned, and
+// reading them is retried after the refresh interval.
+func (s *Store) Topics(ctx context.Context) []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refresh(ctx)
+	return s.topics
+}
+
+// Lifecycle returns the deprecated and retired topics: the seed, overridden
+// by the states set with SetStatus. It is cached and refreshed like Topics.
+func (s *Store) Lifecycle(ctx context.Context) models.TopicLifecycle {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refresh(ctx)
+	return s.lifecycle
+}
+
+// SetStatus sets the lifecycle state of a configured or runtime topic.
+func (s *Store) SetStatus(ctx context.Context, topic, status string) error {
+	switch status {
+	case models.TopicStatusActive, models.TopicStatusDeprecated, models.TopicStatusRetired:
+	default:
+		return ErrInvalidStatus
+	}
+	if !slices.Contains(s.Topics(ctx), topic) {
+		return ErrTopicNotFound
+	}
+	if err := s.redisClient.HSet(ctx, s.lifecycleKey(), topic, status).Err(); err != nil {
+		return fmt.Errorf("failed to set topic status: %w", err)
+	}
+	s.invalidate()
+	return nil
+}
+
+// IsConfigured reports whether topic is configured with TOPICS.
+func (s *Store) IsConfigured(topic string) bool {
+	return slices.Contains(s.configured, topic)
+}
+
+// Create adds a runtime topic, and reports whether it was created rather than
+// already available.
+func (s *Store) Create(ctx context.Context, topic string) (bool, error) {
+	if !ValidTopic(topic) {
+		return false, ErrInvalidTopic
+	}
+	if s.IsConfigured(topic) {
+		return false, nil
+	}
+	added, err := s.redisClient.SAdd(ctx, s.key(), topic).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to create topic: %w", err)
+	}
+	s.invalidate()
+	return added > 0, nil
+}
+
+// Delete removes a runtime topic. Configured topics can't be deleted.
+func (s *Store) Delete(ctx context.Context, topic string) error {
+	if s.IsConfigured(topic) {
+		return ErrConfiguredTopic
+	}
+	removed, err := s.redisClient.SRem(ctx, s.key(), topic).Result()
+	if err != nil {
+		return fmt.Errorf("failed to delete topic: %w", err)
+	}
+	// A topic created again later starts out active.
+	if err := s.redisClient.HDel(ctx, s.lifecycleKey(), topic).Err(); err != nil {
+		return fmt.Errorf("failed to delete topic status: %w", err)
+	}
+	s.invalidate()
+	if removed == 0 {
+		return ErrTopicNotFound
+	}
+	return nil
+}
+
+// ValidTopic reports whether topic can be created at runtime. Commas are
+// rejected as topics are listed comma-separated, such as in the portal, and
+// slashes as a topic is a path segment of the API.
+func ValidTopic(topic string) bool {
+	if topic == "" || len(topic) > MaxTopicLength {
+		return false
+	}
+	return !strings.ContainsAny(topic, ",/ \t\r\n")
+}
+
+// refresh reads the runtime topics and lifecycle states once the refresh
+// interval has passed. When they can't be read, the ones last read are kept.
+// The caller holds s.mu.
+func (s *Store) refresh(ctx context.Context) {
+	if !s.fetchedAt.IsZero() && s.clock.Now().Sub(s.fetchedAt) < s.refreshInterval {
+		return
+	}
+	s.fetchedAt = s.clock.Now()
+	runtime, err := s.redisClient.SMembers(ctx, s.key()).Result()
+	if err != nil {
+		return
+	}
+	statuses, err := s.redisClient.HGetAll(ctx, s.lifecycleKey()).Result()
+	if err != nil {
+		return
+	}
+	s.topics = s.merge(runtime)
+	s.lifecycle = s.mergeLifecycle(statuses)
+}
+
+// invalidate makes the next Topics call read the runtime topics, so this
+// process sees its own changes immediately.
+func (s *Store) invalidate() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fetchedAt = time.Time{}
+}
+
+func (s *Store) merge(runtime []string) []string {
+	slices.Sort(runtime)
+	topics := make([]string, 0, len(s.configured)+len(runtime))
+	topics = append(topics, s.configured...)
+	for _, topic := range runtime {
+		if !slices.Contains(s.configured, topic) {
+			topics = append(topics, topic)
+		}
+	}
+	return topics
+}
+
+// mergeLifecycle applies the states set at runtime over the seed.
+func (s *Store) mergeLifecycle(statuses map[string]string) models.TopicLifecycle {
+	lifecycle := models.TopicLifecycle{}
+	for _, topic := range s.seed.Deprecated {
+		if _, ok := statuses[topic]; !ok {
+			lifecycle.Deprecated = append(lifecycle.Deprecated, topic)
+		}
+	}
+	for _, topic := range s.seed.Retired {
+		if _, ok := statuses[topic]; !ok {
+			lifecycle.Retired = append(lifecycle.Retired, topic)
+		}
+	}
+	overridden := make([]string, 0, len(statuses))
+	for topic := range statuses {
+		overridden = append(overridden, topic)
+	}
+	slices.Sort(overridden)
+	for _, topic := range overridden {
+		switch statuses[topic] {
+		case models.TopicStatusDeprecated:
+			lifecycle.Deprecated = append(lifecycle.Deprecated, topic)
+		case models.TopicStatusRetired:
+			lifecycle.Retired = append(lifecycle.Retired, topic)
+		}
+	}
+	return lifecycle
+}
+
+func (s *Store) lifecycleKey() string {
+	return s.key() + ":lifecycle"
+}
+
+func (s *Store) key() string {
+	if s.deploymentID == "" {
+		return "topics"
+	}
+	return s.deploymentID + ":topics"
+}
@@ -0,0 +1,122 @@
+package topicstore_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/clock"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/topicstore"
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	t.Parallel()
+
+	configured := []string{"user.created", "user.deleted"}
+
+	t.Run("lists runtime topics after configured ones", func(t *testing.T) {
+		t.Parallel()
+		store := topicstore.New(testutil.CreateTestRedisClient(t), configured)
+		ctx := t.Context()
+
+		created, err := store.Create(ctx, "order.shipped")
+		require.NoError(t, err)
+		assert.True(t, created)
+		created, err = store.Create(ctx, "order.created")
+		require.NoError(t, err)
+		assert.True(t, created)
+
+		assert.Equal(t, []string{"user.created", "user.deleted", "order.created", "order.shipped"}, store.Topics(ctx))
+	})
+
+	t.Run("doesn't recreate existing topics", func(t *testing.T) {
+		t.Parallel()
+		store := topicstore.New(testutil.CreateTestRedisClient(t), configured)
+		ctx := t.Context()
+
+		created, err := store.Create(ctx, "user.created")
+		require.NoError(t, err)
+		assert.False(t, created, "configured topics already exist")
+
+		_, err = store.Create(ctx, "order.created")
+		require.NoError(t, err)
+		created, err = store.Create(ctx, "order.created")
+		require.NoError(t, err)
+		assert.False(t, created)
+	})
+
+	t.Run("rejects invalid topics", func(t *testing.T) {
+		t.Parallel()
+		store := topicstore.New(testutil.CreateTestRedisClient(t), configured)
+
+		for _, topic := range []string{"", "order created", "order,created", "orders/created"} {
+			_, err := store.Create(t.Context(), topic)
+			assert.ErrorIs(t, err, topicstore.ErrInvalidTopic, topic)
+		}
+	})
+
+	t.Run("deletes runtime topics only", func(t *testing.T) {
+		t.Parallel()
+		store := topicstore.New(testutil.CreateTestRedisClient(t), configured)
+		ctx := t.Context()
+
+		_, err := store.Create(ctx, "order.created")
+		require.NoError(t, err)
+		require.NoError(t, store.Delete(ctx, "order.created"))
+		assert.Equal(t, configured, store.Topics(ctx))
+
+		assert.ErrorIs(t, store.Delete(ctx, "order.created"), topicstore.ErrTopicNotFound)
+		assert.ErrorIs(t, store.Delete(ctx, "user.created"), topicstore.ErrConfiguredTopic)
+	})
+
+	t.Run("picks up topics created by other processes", func(t *testing.T) {
+		t.Parallel()
+		redisClient := testutil.CreateTestRedisClient(t)
+		clk := clock.NewFake(time.Now())
+		store := topicstore.New(redisClient, configured, topicstore.WithClock(clk))
+		other := topicstore.New(redisClient, configured)
+		ctx := t.Context()
+
+		assert.Equal(t, configured, store.Topics(ctx))
+		_, err := other.Create(ctx, "order.created")
+		require.NoError(t, err)
+		assert.Equal(t, configured, store.Topics(ctx), "runtime topics are cached")
+
+		clk.Advance(topicstore.DefaultRefreshInterval)
+		assert.Contains(t, store.Topics(ctx), "order.created")
+	})
+	t.Run("sets topic lifecycle over the seed", func(t *testing.T) {
+		t.Parallel()
+		store := topicstore.New(testutil.CreateTestRedisClient(t), configured,
+			topicstore.WithLifecycle(models.TopicLifecycle{Deprecated: []string{"user.created"}}))
+		ctx := t.Context()
+
+		assert.Equal(t, models.TopicLifecycle{Deprecated: []string{"user.created"}}, store.Lifecycle(ctx))
+
+		require.NoError(t, store.SetStatus(ctx, "user.created", models.TopicStatusActive))
+		require.NoError(t, store.SetStatus(ctx, "user.deleted", models.TopicStatusRetired))
+		lifecycle := store.Lifecycle(ctx)
+		assert.Equal(t, models.TopicStatusActive, lifecycle.Status("user.created"))
+		assert.Equal(t, models.TopicStatusRetired, lifecycle.Status("user.deleted"))
+
+		assert.ErrorIs(t, store.SetStatus(ctx, "user.deleted", "sunset"), topicstore.ErrInvalidStatus)
+		assert.ErrorIs(t, store.SetStatus(ctx, "order.created", models.TopicStatusRetired), topicstore.ErrTopicNotFound)
+	})
+
+	t.Run("deleted runtime topics lose their lifecycle", func(t *testing.T) {
+		t.Parallel()
+		store := topicstore.New(testutil.CreateTestRedisClient(t), configured)
+		ctx := t.Context()
+
+		_, err := store.Create(ctx, "order.created")
+		require.NoError(t, err)
+		require.NoError(t, store.SetStatus(ctx, "order.created", models.TopicStatusDeprecated))
+		require.NoError(t, store.Delete(ctx, "order.created"))
+		_, err = store.Create(ctx, "order.created")
+		require.NoError(t, err)
+		assert.Equal(t, models.TopicStatusActive, store.Lifecycle(ctx).Status("order.created"))
+	})
+}
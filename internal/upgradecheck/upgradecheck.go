@@ -0,0 +1,161 @@
+// Package upgradecheck checks a deployment against the requirements of this
+// Outpost version, and lists the actions upgrading to it takes, in the order
+// they should be run.
+//
+// The check runs with the binary of the target version, before it is
+// deployed: the Redis and log store schemas are compared with the migrations
+// the binary ships, and the config with the options it supports.
+package upgradecheck
+
+import (
+	"fmt"
+
+	"github.com/hookdeck/outpost/internal/config"
+	"github.com/hookdeck/outpost/internal/migrator/coordinator"
+)
+
+// Input is the state of the deployment to check.
+type Input struct {
+	// TargetVersion is the version being upgraded to.
+	TargetVersion string
+	// Config is the deployment's config, parsed without validation.
+	Config *config.Config
+	// ConfigErr is the error validating Config, if any.
+	ConfigErr error
+	// Migrations lists every migration with its status. nil when the
+	// migrations can't be read.
+	Migrations []coordinator.MigrationInfo
+	// Plan is what applying the migrations would do.
+	Plan *coordinator.Plan
+	// LogTierPlan is what applying the migrations of the ClickHouse log tier
+	// would do. nil without a log tier.
+	LogTierPlan *coordinator.Plan
+}
+
+// Schema is the version of a store's schema.
+type Schema struct {
+	Current string `json:"current"`
+	Latest  string `json:"latest"`
+}
+
+// UpToDate reports whether the schema is at the latest version.
+func (s Schema) UpToDate() bool {
+	return s.Current == s.Latest
+}
+
+// Step is an action of the upgrade.
+type Step struct {
+	Title string `json:"title"`
+	// Details lists what the step changes, such as the migrations it runs.
+	Details []string `json:"details,omitempty"`
+	// Command runs the step, when it can be run with the CLI.
+	Command string `json:"command,omitempty"`
+}
+
+// Report is the result of a check.
+type Report struct {
+	TargetVersion string `json:"target_version"`
+	RedisSchema   Schema `json:"redis_schema"`
+	// LogStoreSchema is empty when no SQL log store is configured.
+	LogStoreSchema Schema `json:"logstore_schema"`
+	// Steps are the actions to take before deploying TargetVersion, in order.
+	Steps []Step `json:"steps"`
+}
+
+// Ready reports whether TargetVersion can be deployed without any action.
+func (r *Report) Ready() bool {
+	return len(r.Steps) == 0
+}
+
+// Check builds the report of in. The config is fixed first, as the
+// migrations need it to connect to the stores, then the log store schema is
+// migrated before Redis, the order `outpost migrate apply` runs them in.
+func Check(in Input) *Report {
+	report := &Report{
+		TargetVersion: in.TargetVersion,
+		RedisSchema:   redisSchema(in.Migrations),
+	}
+	if in.Plan != nil && in.Plan.SQL.LatestVersion > 0 {
+		report.LogStoreSchema = Schema{
+			Current: sqlVersion(in.Plan.SQL.CurrentVersion),
+			Latest:  sqlVersion(in.Plan.SQL.LatestVersion),
+		}
+	}
+
+	if in.ConfigErr != nil {
+		report.Steps = append(report.Steps, Step{
+			Title:   "Fix the config",
+			Details: []string{in.ConfigErr.Error()},
+		})
+	}
+	if in.Config != nil {
+		if deprecations := in.Config.Deprecations(); len(deprecations) > 0 {
+			step := Step{Title: "Rename deprecated config keys"}
+			for _, d := range deprecations {
+				step.Details = append(step.Details, fmt.Sprintf("%s → %s", d.Key, d.Replacement))
+			}
+			report.Steps = append(report.Steps, step)
+		}
+	}
+
+	var sql []string
+	if in.Plan != nil {
+		sql = append(sql, sqlMigrations(in.Plan, "")...)
+	}
+	if in.LogTierPlan != nil {
+		sql = append(sql, sqlMigrations(in.LogTierPlan, "ClickHouse log tier: ")...)
+	}
+	if len(sql) > 0 {
+		report.Steps = append(report.Steps, Step{
+			Title:   "Run the log store migrations",
+			Details: sql,
+			Command: "outpost migrate apply --sql-only",
+		})
+	}
+
+	if in.Plan != nil && len(in.Plan.Redis) > 0 {
+		step := Step{
+			Title:   "Run the Redis migrations",
+			Command: "outpost migrate apply --redis-only",
+		}
+		for _, m := range in.Plan.Redis {
+			step.Details = append(step.Details, fmt.Sprintf("redis/%s  %s", m.Name, m.Description))
+		}
+		report.Steps = append(report.Steps, step)
+	}
+
+	return report
+}
+
+func sqlMigrations(plan *coordinator.Plan, prefix string) []string {
+	details := make([]string, 0, len(plan.SQL.Pending))
+	for _, m := range plan.SQL.Pending {
+		details = append(details, fmt.Sprintf("%ssql/%06d  %s", prefix, m.Version, m.Name))
+	}
+	return details
+}
+
+// redisSchema returns the latest Redis migration that is applied, or that
+// doesn't apply to the deployment, and the latest one shipped.
+func redisSchema(migrations []coordinator.MigrationInfo) Schema {
+	var schema Schema
+	current, latest := -1, -1
+	for _, m := range migrations {
+		if m.Type != coordinator.MigrationTypeRedis {
+			continue
+		}
+		if m.Version > latest {
+			latest = m.Version
+			schema.Latest = m.Name
+		}
+		if m.Status != coordinator.StatusPending && m.Version > current {
+			current = m.Version
+			schema.Current = m.Name
+		}
+	}
+	return schema
+}
+
+func sqlVersion(version int) string {
+	return fmt.Sprintf("sql/%06d", version)
+}
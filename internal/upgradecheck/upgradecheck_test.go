@@ -0,0 +1,97 @@
+package upgradecheck_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/config"
+	"github.com/hookdeck/outpost/internal/migrator/coordinator"
+	"github.com/hookdeck/outpost/internal/upgradecheck"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	migrations := []coordinator.MigrationInfo{
+		{Type: coordinator.MigrationTypeSQL, Version: 1, Name: "init", Status: coordinator.StatusApplied},
+		{Type: coordinator.MigrationTypeRedis, Version: 1, Name: "001_hash_tags", Status: coordinator.StatusApplied},
+		{Type: coordinator.MigrationTypeRedis, Version: 2, Name: "002_timestamps", Status: coordinator.StatusNotApplicable},
+		{Type: coordinator.MigrationTypeRedis, Version: 3, Name: "003_entity", Status: coordinator.StatusPending},
+	}
+
+	t.Run("up to date deployment is ready", func(t *testing.T) {
+		t.Parallel()
+		report := upgradecheck.Check(upgradecheck.Input{
+			TargetVersion: "v1.2.0",
+			Config:        &config.Config{},
+			Migrations:    migrations[:3],
+			Plan:          &coordinator.Plan{SQL: coordinator.SQLPlan{CurrentVersion: 1, LatestVersion: 1}},
+		})
+
+		assert.True(t, report.Ready())
+		assert.Equal(t, upgradecheck.Schema{Current: "002_timestamps", Latest: "002_timestamps"}, report.RedisSchema)
+		assert.Equal(t, upgradecheck.Schema{Current: "sql/000001", Latest: "sql/000001"}, report.LogStoreSchema)
+	})
+
+	t.Run("lists config fixes before migrations", func(t *testing.T) {
+		t.Parallel()
+		cfg := &config.Config{}
+		cfg.Destinations.Webhook.DisableDefaultTopicHeader = true
+
+		report := upgradecheck.Check(upgradecheck.Input{
+			TargetVersion: "v1.2.0",
+			Config:        cfg,
+			ConfigErr:     errors.New("config validation error: invalid grpc port"),
+			Migrations:    migrations,
+			Plan: &coordinator.Plan{
+				SQL: coordinator.SQLPlan{
+					CurrentVersion: 1,
+					LatestVersion:  2,
+					PendingCount:   1,
+					Pending:        []coordinator.SQLMigrationInfo{{Version: 2, Name: "event_source"}},
+				},
+				Redis: []coordinator.RedisMigrationPlan{{Name: "003_entity", Description: "Add entity field"}},
+			},
+			LogTierPlan: &coordinator.Plan{
+				SQL: coordinator.SQLPlan{Pending: []coordinator.SQLMigrationInfo{{Version: 3, Name: "attempt_error"}}},
+			},
+		})
+
+		assert.False(t, report.Ready())
+		assert.Equal(t, upgradecheck.Schema{Current: "002_timestamps", Latest: "003_entity"}, report.RedisSchema)
+		assert.Equal(t, upgradecheck.Schema{Current: "sql/000001", Latest: "sql/000002"}, report.LogStoreSchema)
+		require.Len(t, report.Steps, 4)
+		assert.Equal(t, upgradecheck.Step{
+			Title:   "Fix the config",
+			Details: []string{"config validation error: invalid grpc port"},
+		}, report.Steps[0])
+		assert.Equal(t, upgradecheck.Step{
+			Title:   "Rename deprecated config keys",
+			Details: []string{"DESTINATIONS_WEBHOOK_DISABLE_DEFAULT_TOPIC_HEADER → DESTINATIONS_WEBHOOK_TOPIC_HEADER_NAME"},
+		}, report.Steps[1])
+		assert.Equal(t, upgradecheck.Step{
+			Title:   "Run the log store migrations",
+			Details: []string{"sql/000002  event_source", "ClickHouse log tier: sql/000003  attempt_error"},
+			Command: "outpost migrate apply --sql-only",
+		}, report.Steps[2])
+		assert.Equal(t, upgradecheck.Step{
+			Title:   "Run the Redis migrations",
+			Details: []string{"redis/003_entity  Add entity field"},
+			Command: "outpost migrate apply --redis-only",
+		}, report.Steps[3])
+	})
+
+	t.Run("without a SQL log store", func(t *testing.T) {
+		t.Parallel()
+		report := upgradecheck.Check(upgradecheck.Input{
+			Config:     &config.Config{},
+			Migrations: migrations[1:2],
+			Plan:       &coordinator.Plan{},
+		})
+
+		assert.True(t, report.Ready())
+		assert.Equal(t, upgradecheck.Schema{}, report.LogStoreSchema)
+	})
+}
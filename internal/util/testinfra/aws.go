@@ -17,7 +17,7 @@ import (
 func NewMQAWSConfig(t *testing.T, attributes map[string]string) mqs.QueueConfig {
 	queueConfig := mqs.QueueConfig{
 		AWSSQS: &mqs.AWSSQSConfig{
-			Endpoint:                  EnsureLocalStack(),
+			Endpoint:                  EnsureSQS(),
 			Region:                    "us-east-1",
 			ServiceAccountCredentials: "test:test:",
 			Topic:                     uuid.New().String(),
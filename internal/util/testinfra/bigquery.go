@@ -0,0 +1,102 @@
+package testinfra
+
+import (
+	"context"
+	"log"
+	"sync"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/util/testutil"
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+	"google.golang.org/api/bigquery/v2"
+	"google.golang.org/api/option"
+)
+
+const bigQueryProjectID = "test"
+
+type BigQueryConfig struct {
+	Endpoint  string
+	ProjectID string
+	DatasetID string
+}
+
+// Service returns a BigQuery client for the emulator.
+func (c BigQueryConfig) Service(ctx context.Context) (*bigquery.Service, error) {
+	return bigquery.NewService(ctx,
+		option.WithEndpoint(c.Endpoint),
+		option.WithoutAuthentication(),
+	)
+}
+
+// NewBigQueryConfig creates a dataset for the test on the BigQuery emulator
+// and deletes it, with its tables, on cleanup.
+func NewBigQueryConfig(t *testing.T) BigQueryConfig {
+	ctx := context.Background()
+	bqConfig := BigQueryConfig{
+		Endpoint:  ensureBigQuery(),
+		ProjectID: bigQueryProjectID,
+		DatasetID: "test_" + testutil.RandomString(10),
+	}
+	svc, err := bqConfig.Service(ctx)
+	if err != nil {
+		panic(err)
+	}
+	if _, err := svc.Datasets.Insert(bqConfig.ProjectID, &bigquery.Dataset{
+		DatasetReference: &bigquery.DatasetReference{
+			ProjectId: bqConfig.ProjectID,
+			DatasetId: bqConfig.DatasetID,
+		},
+	}).Context(ctx).Do(); err != nil {
+		panic(err)
+	}
+	t.Cleanup(func() {
+		if err := svc.Datasets.Delete(bqConfig.ProjectID, bqConfig.DatasetID).DeleteContents(true).Context(ctx).Do(); err != nil {
+			log.Printf("failed to delete bigquery dataset %s: %s", bqConfig.DatasetID, err)
+		}
+	})
+	return bqConfig
+}
+
+var bqOnce sync.Once
+
+func ensureBigQuery() string {
+	cfg := ReadConfig()
+	if cfg.BigQueryURL == "" {
+		bqOnce.Do(func() {
+			startBigQueryTestContainer(cfg)
+		})
+	}
+	return cfg.BigQueryURL
+}
+
+func startBigQueryTestContainer(cfg *Config) {
+	ctx := context.Background()
+
+	req := testcontainers.ContainerRequest{
+		Image:        "ghcr.io/goccy/bigquery-emulator:0.6.6",
+		Cmd:          []string{"--project=" + bigQueryProjectID},
+		ExposedPorts: []string{"9050/tcp"},
+		WaitingFor:   wait.ForListeningPort("9050/tcp"),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	endpoint, err := container.PortEndpoint(ctx, "9050/tcp", "http")
+	if err != nil {
+		panic(err)
+	}
+	log.Printf("BigQuery emulator running at %s", endpoint)
+	cfg.BigQueryURL = endpoint
+	cfg.cleanupFns = append(cfg.cleanupFns, func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate bigquery container: %s", err)
+		}
+	})
+}
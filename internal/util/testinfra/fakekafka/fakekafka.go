@@ -0,0 +1,582 @@
+// Package fakekafka is an in-memory Kafka broker for tests that run without
+// Docker. It speaks the Kafka wire protocol, through kafka-go's protocol
+// package, and supports the requests Outpost's Kafka clients make:
+// negotiating API versions, SASL PLAIN authentication, metadata, creating
+// topics, producing, listing offsets and fetching. It is a single broker that
+// leads every partition. SASL credentials aren't checked, and consumer
+// groups and transactions aren't supported.
+package fakekafka
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/protocol"
+	"github.com/segmentio/kafka-go/protocol/apiversions"
+	"github.com/segmentio/kafka-go/protocol/createtopics"
+	"github.com/segmentio/kafka-go/protocol/fetch"
+	"github.com/segmentio/kafka-go/protocol/listoffsets"
+	"github.com/segmentio/kafka-go/protocol/metadata"
+	"github.com/segmentio/kafka-go/protocol/produce"
+	"github.com/segmentio/kafka-go/protocol/saslauthenticate"
+	"github.com/segmentio/kafka-go/protocol/saslhandshake"
+)
+
+const (
+	brokerID  = 0
+	clusterID = "fakekafka"
+
+	// minFetchVersion is the oldest Fetch version served. From v4 record
+	// sets are record batches, the only format the server writes.
+	minFetchVersion = 4
+)
+
+// Server is an in-memory Kafka broker.
+type Server struct {
+	listener net.Listener
+	host     string
+	port     int32
+	wg       sync.WaitGroup
+
+	mu     sync.Mutex
+	topics map[string]*topic
+	conns  map[net.Conn]struct{}
+	closed chan struct{}
+	// notify is closed and replaced whenever records are produced, waking up
+	// pending fetches.
+	notify chan struct{}
+}
+
+type topic struct {
+	partitions [][]record
+}
+
+type record struct {
+	time    time.Time
+	key     []byte
+	value   []byte
+	headers []protocol.Header
+}
+
+// New starts a broker on a local port. Close stops it.
+func New() *Server {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic("fakekafka: failed to listen: " + err.Error())
+	}
+	addr := listener.Addr().(*net.TCPAddr)
+	s := &Server{
+		listener: listener,
+		host:     addr.IP.String(),
+		port:     int32(addr.Port),
+		topics:   make(map[string]*topic),
+		conns:    make(map[net.Conn]struct{}),
+		closed:   make(chan struct{}),
+		notify:   make(chan struct{}),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Addr returns the broker address to configure Kafka clients with.
+func (s *Server) Addr() string {
+	return net.JoinHostPort(s.host, strconv.Itoa(int(s.port)))
+}
+
+// Close stops the broker and closes its connections.
+func (s *Server) Close() {
+	s.mu.Lock()
+	select {
+	case <-s.closed:
+		s.mu.Unlock()
+		return
+	default:
+	}
+	close(s.closed)
+	s.listener.Close()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		select {
+		case <-s.closed:
+			s.mu.Unlock()
+			conn.Close()
+			return
+		default:
+		}
+		s.conns[conn] = struct{}{}
+		s.wg.Add(1)
+		s.mu.Unlock()
+		go s.serveConn(conn)
+	}
+}
+
+// serveConn answers the requests of a connection in order, until the client
+// disconnects or sends a request the server can't decode.
+func (s *Server) serveConn(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	r := bufio.NewReader(conn)
+	for {
+		version, correlationID, _, req, err := protocol.ReadRequest(r)
+		if err != nil {
+			return
+		}
+		res := s.handle(version, req)
+		if res == nil {
+			continue
+		}
+		if err := protocol.WriteResponse(conn, version, correlationID, res); err != nil {
+			return
+		}
+	}
+}
+
+// handle returns the response to a request, or nil for requests that get
+// none, such as produce requests without acknowledgements.
+func (s *Server) handle(version int16, req protocol.Message) protocol.Message {
+	switch req := req.(type) {
+	case *apiversions.Request:
+		return apiVersions()
+	case *saslhandshake.Request:
+		return saslHandshake(req)
+	case *saslauthenticate.Request:
+		return &saslauthenticate.Response{}
+	case *metadata.Request:
+		return s.metadata(version, req)
+	case *createtopics.Request:
+		return s.createTopics(req)
+	case *produce.Request:
+		res := s.produce(req)
+		if !req.HasResponse() {
+			return nil
+		}
+		return res
+	case *listoffsets.Request:
+		return s.listOffsets(req)
+	case *fetch.Request:
+		return s.fetch(req)
+	default:
+		// Unreachable: ReadRequest only decodes the APIs whose packages
+		// are imported, and each is handled above.
+		return nil
+	}
+}
+
+// supportedAPIs are the APIs the server answers, at every version kafka-go's
+// protocol package can encode.
+var supportedAPIs = []protocol.ApiKey{
+	protocol.ApiVersions,
+	protocol.SaslHandshake,
+	protocol.SaslAuthenticate,
+	protocol.Metadata,
+	protocol.CreateTopics,
+	protocol.Produce,
+	protocol.ListOffsets,
+	protocol.Fetch,
+}
+
+func apiVersions() *apiversions.Response {
+	res := &apiversions.Response{}
+	for _, key := range supportedAPIs {
+		minVersion := key.MinVersion()
+		if key == protocol.Fetch {
+			minVersion = minFetchVersion
+		}
+		res.ApiKeys = append(res.ApiKeys, apiversions.ApiKeyResponse{
+			ApiKey:     int16(key),
+			MinVersion: minVersion,
+			MaxVersion: key.MaxVersion(),
+		})
+	}
+	return res
+}
+
+func saslHandshake(req *saslhandshake.Request) *saslhandshake.Response {
+	res := &saslhandshake.Response{Mechanisms: []string{"PLAIN"}}
+	if !strings.EqualFold(req.Mechanism, "PLAIN") {
+		res.ErrorCode = int16(kafka.UnsupportedSASLMechanism)
+	}
+	return res
+}
+
+func (s *Server) metadata(version int16, req *metadata.Request) *metadata.Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names := req.TopicNames
+	if names == nil {
+		for name := range s.topics {
+			names = append(names, name)
+		}
+	}
+	// Before v4 requests can't ask for topics to be created, and brokers
+	// create them by default.
+	autoCreate := version < 4 || req.AllowAutoTopicCreation
+
+	res := &metadata.Response{
+		Brokers: []metadata.ResponseBroker{{
+			NodeID: brokerID,
+			Host:   s.host,
+			Port:   s.port,
+		}},
+		ClusterID:    clusterID,
+		ControllerID: brokerID,
+	}
+	for _, name := range names {
+		t, ok := s.topics[name]
+		if !ok && autoCreate && name != "" {
+			t = &topic{partitions: make([][]record, 1)}
+			s.topics[name] = t
+		}
+		if t == nil {
+			res.Topics = append(res.Topics, metadata.ResponseTopic{
+				ErrorCode: int16(kafka.UnknownTopicOrPartition),
+				Name:      name,
+			})
+			continue
+		}
+		responseTopic := metadata.ResponseTopic{Name: name}
+		for i := range t.partitions {
+			responseTopic.Partitions = append(responseTopic.Partitions, metadata.ResponsePartition{
+				PartitionIndex: int32(i),
+				LeaderID:       brokerID,
+				ReplicaNodes:   []int32{brokerID},
+				IsrNodes:       []int32{brokerID},
+			})
+		}
+		res.Topics = append(res.Topics, responseTopic)
+	}
+	return res
+}
+
+func (s *Server) createTopics(req *createtopics.Request) *createtopics.Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := &createtopics.Response{}
+	for _, requested := range req.Topics {
+		partitions := requested.NumPartitions
+		if partitions == -1 {
+			partitions = 1
+		}
+		responseTopic := createtopics.ResponseTopic{
+			Name:              requested.Name,
+			NumPartitions:     partitions,
+			ReplicationFactor: 1,
+		}
+		switch {
+		case requested.Name == "":
+			responseTopic.ErrorCode = int16(kafka.InvalidTopic)
+		case s.topics[requested.Name] != nil:
+			responseTopic.ErrorCode = int16(kafka.TopicAlreadyExists)
+		case partitions < 1:
+			responseTopic.ErrorCode = int16(kafka.InvalidPartitionNumber)
+		case !req.ValidateOnly:
+			s.topics[requested.Name] = &topic{partitions: make([][]record, partitions)}
+		}
+		res.Topics = append(res.Topics, responseTopic)
+	}
+	return res
+}
+
+func (s *Server) produce(req *produce.Request) *produce.Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	produced := false
+	res := &produce.Response{}
+	for _, requested := range req.Topics {
+		responseTopic := produce.ResponseTopic{Topic: requested.Topic}
+		for _, p := range requested.Partitions {
+			responsePartition := produce.ResponsePartition{
+				Partition:     p.Partition,
+				LogAppendTime: -1,
+			}
+			t := s.topics[requested.Topic]
+			if t == nil || p.Partition < 0 || int(p.Partition) >= len(t.partitions) {
+				responsePartition.ErrorCode = int16(kafka.UnknownTopicOrPartition)
+				responseTopic.Partitions = append(responseTopic.Partitions, responsePartition)
+				continue
+			}
+			records, err := readRecords(p.RecordSet.Records, now)
+			if err != nil {
+				responsePartition.ErrorCode = int16(kafka.InvalidMessage)
+				responseTopic.Partitions = append(responseTopic.Partitions, responsePartition)
+				continue
+			}
+			responsePartition.BaseOffset = int64(len(t.partitions[p.Partition]))
+			t.partitions[p.Partition] = append(t.partitions[p.Partition], records...)
+			produced = produced || len(records) > 0
+			responseTopic.Partitions = append(responseTopic.Partitions, responsePartition)
+		}
+		res.Topics = append(res.Topics, responseTopic)
+	}
+	if produced {
+		close(s.notify)
+		s.notify = make(chan struct{})
+	}
+	return res
+}
+
+// readRecords copies the records of a produce request, timestamping those
+// sent without a time.
+func readRecords(reader protocol.RecordReader, now time.Time) ([]record, error) {
+	if reader == nil {
+		return nil, nil
+	}
+	var records []record
+	for {
+		r, err := reader.ReadRecord()
+		if errors.Is(err, io.EOF) {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		rec := record{time: r.Time, headers: append([]protocol.Header(nil), r.Headers...)}
+		if rec.time.IsZero() {
+			rec.time = now
+		}
+		if rec.key, err = readBytes(r.Key); err != nil {
+			return nil, err
+		}
+		if rec.value, err = readBytes(r.Value); err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+}
+
+func readBytes(b protocol.Bytes) ([]byte, error) {
+	if b == nil {
+		return nil, nil
+	}
+	defer b.Close()
+	return protocol.ReadAll(b)
+}
+
+// Special timestamps of ListOffsets requests.
+const (
+	latestOffset   = -1
+	earliestOffset = -2
+)
+
+func (s *Server) listOffsets(req *listoffsets.Request) *listoffsets.Response {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := &listoffsets.Response{}
+	for _, requested := range req.Topics {
+		responseTopic := listoffsets.ResponseTopic{Topic: requested.Topic}
+		for _, p := range requested.Partitions {
+			responsePartition := listoffsets.ResponsePartition{
+				Partition: p.Partition,
+				Timestamp: -1,
+			}
+			records, ok := s.partition(requested.Topic, p.Partition)
+			switch {
+			case !ok:
+				responsePartition.ErrorCode = int16(kafka.UnknownTopicOrPartition)
+			case p.Timestamp == earliestOffset:
+				responsePartition.Offset = 0
+			case p.Timestamp == latestOffset:
+				responsePartition.Offset = int64(len(records))
+			default:
+				// The first offset whose record is at least as recent as
+				// the timestamp, or the end of the partition.
+				responsePartition.Offset = int64(len(records))
+				for i, r := range records {
+					if r.time.UnixMilli() >= p.Timestamp {
+						responsePartition.Offset = int64(i)
+						responsePartition.Timestamp = r.time.UnixMilli()
+						break
+					}
+				}
+			}
+			responseTopic.Partitions = append(responseTopic.Partitions, responsePartition)
+		}
+		res.Topics = append(res.Topics, responseTopic)
+	}
+	return res
+}
+
+// partition returns the records of a topic partition. It must be called with
+// s.mu held.
+func (s *Server) partition(name string, index int32) ([]record, bool) {
+	t := s.topics[name]
+	if t == nil || index < 0 || int(index) >= len(t.partitions) {
+		return nil, false
+	}
+	return t.partitions[index], true
+}
+
+// fetch answers a fetch request as soon as one of its partitions has records
+// past the requested offset, or with no records once the request's wait time
+// is over.
+func (s *Server) fetch(req *fetch.Request) *fetchResponse {
+	timer := time.NewTimer(time.Duration(req.MaxWaitTime) * time.Millisecond)
+	defer timer.Stop()
+	for {
+		s.mu.Lock()
+		res, found := s.readFetch(req)
+		notify := s.notify
+		s.mu.Unlock()
+		if found {
+			return res
+		}
+		select {
+		case <-notify:
+		case <-timer.C:
+			return res
+		case <-s.closed:
+			return res
+		}
+	}
+}
+
+// readFetch builds the response to a fetch request from the records stored
+// now, reporting whether it holds any record or error. It must be called with
+// s.mu held.
+func (s *Server) readFetch(req *fetch.Request) (*fetchResponse, bool) {
+	found := false
+	res := &fetchResponse{}
+	for _, requested := range req.Topics {
+		responseTopic := fetchResponseTopic{Topic: requested.Topic}
+		for _, p := range requested.Partitions {
+			responsePartition := fetchResponsePartition{Partition: p.Partition}
+			records, ok := s.partition(requested.Topic, p.Partition)
+			switch {
+			case !ok:
+				responsePartition.ErrorCode = int16(kafka.UnknownTopicOrPartition)
+				found = true
+			case p.FetchOffset < 0 || p.FetchOffset > int64(len(records)):
+				responsePartition.ErrorCode = int16(kafka.OffsetOutOfRange)
+				found = true
+			default:
+				responsePartition.RecordSet = newRecordSet(records, p.FetchOffset, p.PartitionMaxBytes)
+				found = found || len(responsePartition.RecordSet.records) > 0
+			}
+			responsePartition.HighWatermark = int64(len(records))
+			responsePartition.LastStableOffset = int64(len(records))
+			responseTopic.Partitions = append(responseTopic.Partitions, responsePartition)
+		}
+		res.Topics = append(res.Topics, responseTopic)
+	}
+	return res, found
+}
+
+// newRecordSet returns the records of a partition from offset, within about
+// maxBytes of keys and values but at least one record.
+func newRecordSet(records []record, offset int64, maxBytes int32) recordSet {
+	set := recordSet{baseOffset: offset}
+	size := 0
+	for i, r := range records[offset:] {
+		size += len(r.key) + len(r.value)
+		if i > 0 && maxBytes > 0 && size > int(maxBytes) {
+			break
+		}
+		record := protocol.Record{
+			Offset:  offset + int64(i),
+			Time:    r.time,
+			Headers: r.headers,
+		}
+		if r.key != nil {
+			record.Key = protocol.NewBytes(r.key)
+		}
+		if r.value != nil {
+			record.Value = protocol.NewBytes(r.value)
+		}
+		set.records = append(set.records, record)
+	}
+	return set
+}
+
+// The fetch response is kafka-go's fetch.Response with a record set that can
+// be empty and starts at its first record's offset: fetch.Response can't
+// encode a partition without records, and always numbers records from 0.
+const fetchOverride protocol.OverrideTypeKey = 1
+
+func init() {
+	protocol.RegisterOverride(&fetch.Request{}, &fetchResponse{}, fetchOverride)
+}
+
+type fetchResponse struct {
+	ThrottleTimeMs int32                `kafka:"min=v0,max=v11"`
+	ErrorCode      int16                `kafka:"min=v7,max=v11"`
+	SessionID      int32                `kafka:"min=v7,max=v11"`
+	Topics         []fetchResponseTopic `kafka:"min=v0,max=v11"`
+}
+
+func (*fetchResponse) ApiKey() protocol.ApiKey { return protocol.Fetch }
+
+func (*fetchResponse) TypeKey() protocol.OverrideTypeKey { return fetchOverride }
+
+type fetchResponseTopic struct {
+	Topic      string                   `kafka:"min=v0,max=v11"`
+	Partitions []fetchResponsePartition `kafka:"min=v0,max=v11"`
+}
+
+type fetchResponsePartition struct {
+	Partition            int32                       `kafka:"min=v0,max=v11"`
+	ErrorCode            int16                       `kafka:"min=v0,max=v11"`
+	HighWatermark        int64                       `kafka:"min=v0,max=v11"`
+	LastStableOffset     int64                       `kafka:"min=v4,max=v11"`
+	LogStartOffset       int64                       `kafka:"min=v5,max=v11"`
+	AbortedTransactions  []fetch.ResponseTransaction `kafka:"min=v4,max=v11"`
+	PreferredReadReplica int32                       `kafka:"min=v11,max=v11"`
+	RecordSet            recordSet                   `kafka:"min=v0,max=v11"`
+}
+
+// recordSet is a record batch of consecutive records starting at baseOffset.
+type recordSet struct {
+	baseOffset int64
+	records    []protocol.Record
+}
+
+func (rs *recordSet) WriteTo(w io.Writer) (int64, error) {
+	if len(rs.records) == 0 {
+		n, err := w.Write(make([]byte, 4))
+		return int64(n), err
+	}
+	var buf bytes.Buffer
+	set := protocol.RecordSet{Version: 2, Records: protocol.NewRecordReader(rs.records...)}
+	if _, err := set.WriteTo(&buf); err != nil {
+		return 0, err
+	}
+	// The batch follows its 4-byte size, and starts with its base offset,
+	// which its checksum doesn't cover.
+	b := buf.Bytes()
+	binary.BigEndian.PutUint64(b[4:12], uint64(rs.baseOffset))
+	n, err := w.Write(b)
+	return int64(n), err
+}
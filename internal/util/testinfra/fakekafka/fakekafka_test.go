@@ -0,0 +1,191 @@
+package fakekafka_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/hookdeck/outpost/internal/util/testinfra/fakekafka"
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var mechanism = plain.Mechanism{Username: "admin", Password: "admin-secret"}
+
+func newServer(t *testing.T) *fakekafka.Server {
+	t.Helper()
+	server := fakekafka.New()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func createTopic(t *testing.T, server *fakekafka.Server, topic string, partitions int) {
+	t.Helper()
+	dialer := &kafka.Dialer{SASLMechanism: mechanism, Timeout: 5 * time.Second}
+	conn, err := dialer.DialContext(t.Context(), "tcp", server.Addr())
+	require.NoError(t, err)
+	defer conn.Close()
+	require.NoError(t, conn.CreateTopics(kafka.TopicConfig{
+		Topic:             topic,
+		NumPartitions:     partitions,
+		ReplicationFactor: 1,
+	}))
+}
+
+func newWriter(t *testing.T, server *fakekafka.Server, topic string) *kafka.Writer {
+	t.Helper()
+	writer := &kafka.Writer{
+		Addr:         kafka.TCP(server.Addr()),
+		Topic:        topic,
+		Balancer:     &kafka.Hash{},
+		Transport:    &kafka.Transport{SASL: mechanism},
+		RequiredAcks: kafka.RequireAll,
+	}
+	t.Cleanup(func() { writer.Close() })
+	return writer
+}
+
+func newReader(t *testing.T, server *fakekafka.Server, topic string, offset int64) *kafka.Reader {
+	t.Helper()
+	reader := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: []string{server.Addr()},
+		Topic:   topic,
+		Dialer:  &kafka.Dialer{SASLMechanism: mechanism, Timeout: 5 * time.Second},
+		MaxWait: 100 * time.Millisecond,
+	})
+	t.Cleanup(func() { reader.Close() })
+	require.NoError(t, reader.SetOffset(offset))
+	return reader
+}
+
+func readMessage(t *testing.T, reader *kafka.Reader) kafka.Message {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+	defer cancel()
+	msg, err := reader.ReadMessage(ctx)
+	require.NoError(t, err)
+	return msg
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("delivers messages with their key and headers", func(t *testing.T) {
+		t.Parallel()
+		server := newServer(t)
+		createTopic(t, server, "orders", 1)
+
+		err := newWriter(t, server, "orders").WriteMessages(t.Context(),
+			kafka.Message{
+				Key:     []byte("order-1"),
+				Value:   []byte(`{"id":1}`),
+				Headers: []kafka.Header{{Key: "content-type", Value: []byte("application/json")}},
+			},
+			kafka.Message{Key: []byte("order-2"), Value: []byte(`{"id":2}`)},
+		)
+		require.NoError(t, err)
+
+		reader := newReader(t, server, "orders", kafka.FirstOffset)
+		first := readMessage(t, reader)
+		assert.Equal(t, int64(0), first.Offset)
+		assert.Equal(t, "order-1", string(first.Key))
+		assert.Equal(t, `{"id":1}`, string(first.Value))
+		assert.Equal(t, []kafka.Header{{Key: "content-type", Value: []byte("application/json")}}, first.Headers)
+		assert.False(t, first.Time.IsZero())
+
+		second := readMessage(t, reader)
+		assert.Equal(t, int64(1), second.Offset)
+		assert.Equal(t, "order-2", string(second.Key))
+	})
+
+	t.Run("wakes up a waiting reader", func(t *testing.T) {
+		t.Parallel()
+		server := newServer(t)
+		createTopic(t, server, "orders", 1)
+		writer := newWriter(t, server, "orders")
+		require.NoError(t, writer.WriteMessages(t.Context(), kafka.Message{Value: []byte("before")}))
+
+		reader := newReader(t, server, "orders", kafka.LastOffset)
+		received := make(chan kafka.Message, 1)
+		go func() {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if msg, err := reader.ReadMessage(ctx); err == nil {
+				received <- msg
+			}
+			close(received)
+		}()
+		// Give the reader time to resolve the last offset first.
+		time.Sleep(200 * time.Millisecond)
+		require.NoError(t, writer.WriteMessages(t.Context(), kafka.Message{Value: []byte("after")}))
+
+		msg, ok := <-received
+		require.True(t, ok, "reader should receive the new message")
+		assert.Equal(t, int64(1), msg.Offset)
+		assert.Equal(t, "after", string(msg.Value))
+	})
+
+	t.Run("routes keys to partitions", func(t *testing.T) {
+		t.Parallel()
+		server := newServer(t)
+		createTopic(t, server, "orders", 3)
+
+		dialer := &kafka.Dialer{SASLMechanism: mechanism, Timeout: 5 * time.Second}
+		conn, err := dialer.DialContext(t.Context(), "tcp", server.Addr())
+		require.NoError(t, err)
+		defer conn.Close()
+		partitions, err := conn.ReadPartitions("orders")
+		require.NoError(t, err)
+		assert.Len(t, partitions, 3)
+
+		require.NoError(t, newWriter(t, server, "orders").WriteMessages(t.Context(), kafka.Message{Key: []byte("order-1"), Value: []byte("a")}))
+		var offsets int64
+		for _, partition := range partitions {
+			leader, err := dialer.DialLeader(t.Context(), "tcp", server.Addr(), "orders", partition.ID)
+			require.NoError(t, err)
+			last, err := leader.ReadLastOffset()
+			require.NoError(t, err)
+			offsets += last
+			leader.Close()
+		}
+		assert.Equal(t, int64(1), offsets, "the message should be in exactly one partition")
+	})
+
+	t.Run("stores messages sent without acknowledgement", func(t *testing.T) {
+		t.Parallel()
+		server := newServer(t)
+		createTopic(t, server, "orders", 1)
+
+		writer := newWriter(t, server, "orders")
+		writer.RequiredAcks = kafka.RequireNone
+		require.NoError(t, writer.WriteMessages(t.Context(), kafka.Message{Value: []byte("a")}))
+
+		msg := readMessage(t, newReader(t, server, "orders", kafka.FirstOffset))
+		assert.Equal(t, "a", string(msg.Value))
+	})
+
+	t.Run("rejects unknown topics", func(t *testing.T) {
+		t.Parallel()
+		server := newServer(t)
+
+		writer := newWriter(t, server, "missing")
+		ctx, cancel := context.WithTimeout(t.Context(), 5*time.Second)
+		defer cancel()
+		err := writer.WriteMessages(ctx, kafka.Message{Value: []byte("a")})
+		require.ErrorIs(t, err, kafka.UnknownTopicOrPartition)
+	})
+
+	t.Run("keeps the records of a topic created again", func(t *testing.T) {
+		t.Parallel()
+		server := newServer(t)
+		createTopic(t, server, "orders", 1)
+		require.NoError(t, newWriter(t, server, "orders").WriteMessages(t.Context(), kafka.Message{Value: []byte("a")}))
+
+		createTopic(t, server, "orders", 1)
+
+		msg := readMessage(t, newReader(t, server, "orders", kafka.FirstOffset))
+		assert.Equal(t, "a", string(msg.Value))
+	})
+}
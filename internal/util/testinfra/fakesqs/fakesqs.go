@@ -0,0 +1,481 @@
+// Package fakesqs is an in-memory Amazon SQS server for tests that run
+// without LocalStack. It speaks the AWS JSON protocol of the SDK's SQS client
+// and supports the operations Outpost uses: creating, looking up and deleting
+// queues, sending, receiving and deleting messages, and changing their
+// visibility. Requests aren't authenticated and FIFO queues aren't supported.
+package fakesqs
+
+import (
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	accountID = "000000000000"
+	region    = "us-east-1"
+
+	defaultVisibilityTimeout = 30 * time.Second
+	maxWaitTime              = 20 * time.Second
+)
+
+// Server is an in-memory SQS server.
+type Server struct {
+	server *httptest.Server
+
+	mu     sync.Mutex
+	queues map[string]*queue
+	// notify is closed and replaced whenever a message is sent, waking up
+	// long polls.
+	notify chan struct{}
+}
+
+type queue struct {
+	name       string
+	attributes map[string]string
+	messages   []*message
+}
+
+type message struct {
+	id            string
+	body          string
+	attributes    map[string]json.RawMessage
+	receiptHandle string
+	visibleAt     time.Time
+	receiveCount  int
+}
+
+// New starts a server on a local port. Close stops it.
+func New() *Server {
+	s := &Server{
+		queues: make(map[string]*queue),
+		notify: make(chan struct{}),
+	}
+	s.server = httptest.NewServer(http.HandlerFunc(s.handle))
+	return s
+}
+
+// URL returns the endpoint to configure SQS clients with.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Close stops the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// apiError is an SQS error, such as QueueDoesNotExist.
+type apiError struct {
+	code    string
+	message string
+}
+
+func errorf(code, format string, args ...any) *apiError {
+	return &apiError{code: code, message: fmt.Sprintf(format, args...)}
+}
+
+func (s *Server) handle(w http.ResponseWriter, r *http.Request) {
+	operation, ok := strings.CutPrefix(r.Header.Get("X-Amz-Target"), "AmazonSQS.")
+	if !ok || r.Method != http.MethodPost {
+		writeError(w, errorf("InvalidAction", "unsupported request"))
+		return
+	}
+
+	var input map[string]json.RawMessage
+	if err := json.NewDecoder(r.Body).Decode(&input); err != nil {
+		writeError(w, errorf("InvalidParameterValue", "malformed request body"))
+		return
+	}
+
+	var output any
+	var err *apiError
+	switch operation {
+	case "CreateQueue":
+		output, err = s.createQueue(input)
+	case "GetQueueUrl":
+		output, err = s.getQueueURL(input)
+	case "DeleteQueue":
+		output, err = s.deleteQueue(input)
+	case "GetQueueAttributes":
+		output, err = s.getQueueAttributes(input)
+	case "SendMessage":
+		output, err = s.sendMessage(input)
+	case "SendMessageBatch":
+		output, err = s.sendMessageBatch(input)
+	case "ReceiveMessage":
+		output, err = s.receiveMessage(r, input)
+	case "DeleteMessage":
+		output, err = s.deleteMessage(input)
+	case "ChangeMessageVisibility":
+		output, err = s.changeMessageVisibility(input)
+	default:
+		err = errorf("UnsupportedOperation", "%s is not supported", operation)
+	}
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	json.NewEncoder(w).Encode(output)
+}
+
+func writeError(w http.ResponseWriter, err *apiError) {
+	w.Header().Set("Content-Type", "application/x-amz-json-1.0")
+	w.WriteHeader(http.StatusBadRequest)
+	json.NewEncoder(w).Encode(map[string]string{
+		"__type":  "com.amazonaws.sqs#" + err.code,
+		"message": err.message,
+	})
+}
+
+func (s *Server) queueURL(name string) string {
+	return fmt.Sprintf("%s/%s/%s", s.server.URL, accountID, name)
+}
+
+// lookupQueue returns the queue of the QueueUrl in input. s.mu must be held.
+func (s *Server) lookupQueue(input map[string]json.RawMessage) (*queue, *apiError) {
+	var queueURL string
+	if err := decodeField(input, "QueueUrl", &queueURL); err != nil {
+		return nil, err
+	}
+	name := queueURL[strings.LastIndex(queueURL, "/")+1:]
+	q, ok := s.queues[name]
+	if !ok {
+		return nil, errorf("QueueDoesNotExist", "the specified queue does not exist")
+	}
+	return q, nil
+}
+
+func (s *Server) createQueue(input map[string]json.RawMessage) (any, *apiError) {
+	var name string
+	if err := decodeField(input, "QueueName", &name); err != nil {
+		return nil, err
+	}
+	if name == "" || strings.HasSuffix(name, ".fifo") {
+		return nil, errorf("InvalidParameterValue", "invalid queue name %q", name)
+	}
+	var attributes map[string]string
+	if _, ok := input["Attributes"]; ok {
+		if err := decodeField(input, "Attributes", &attributes); err != nil {
+			return nil, err
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.queues[name]; !ok {
+		s.queues[name] = &queue{name: name, attributes: attributes}
+	}
+	return map[string]string{"QueueUrl": s.queueURL(name)}, nil
+}
+
+func (s *Server) getQueueURL(input map[string]json.RawMessage) (any, *apiError) {
+	var name string
+	if err := decodeField(input, "QueueName", &name); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.queues[name]; !ok {
+		return nil, errorf("QueueDoesNotExist", "the specified queue does not exist")
+	}
+	return map[string]string{"QueueUrl": s.queueURL(name)}, nil
+}
+
+func (s *Server) deleteQueue(input map[string]json.RawMessage) (any, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q, err := s.lookupQueue(input)
+	if err != nil {
+		return nil, err
+	}
+	delete(s.queues, q.name)
+	return struct{}{}, nil
+}
+
+func (s *Server) getQueueAttributes(input map[string]json.RawMessage) (any, *apiError) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q, err := s.lookupQueue(input)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	visible := 0
+	for _, m := range q.messages {
+		if !m.visibleAt.After(now) {
+			visible++
+		}
+	}
+	attributes := map[string]string{
+		"QueueArn":                              fmt.Sprintf("arn:aws:sqs:%s:%s:%s", region, accountID, q.name),
+		"ApproximateNumberOfMessages":           strconv.Itoa(visible),
+		"ApproximateNumberOfMessagesNotVisible": strconv.Itoa(len(q.messages) - visible),
+	}
+	for k, v := range q.attributes {
+		attributes[k] = v
+	}
+	return map[string]any{"Attributes": attributes}, nil
+}
+
+type sendEntry struct {
+	ID                string                     `json:"Id"`
+	MessageBody       string                     `json:"MessageBody"`
+	MessageAttributes map[string]json.RawMessage `json:"MessageAttributes"`
+	DelaySeconds      int                        `json:"DelaySeconds"`
+}
+
+type sendResult struct {
+	ID               string `json:"Id,omitempty"`
+	MessageID        string `json:"MessageId"`
+	MD5OfMessageBody string `json:"MD5OfMessageBody"`
+}
+
+func (s *Server) sendMessage(input map[string]json.RawMessage) (any, *apiError) {
+	var entry sendEntry
+	if err := decodeInput(input, &entry); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q, err := s.lookupQueue(input)
+	if err != nil {
+		return nil, err
+	}
+	return s.enqueue(q, entry), nil
+}
+
+func (s *Server) sendMessageBatch(input map[string]json.RawMessage) (any, *apiError) {
+	var entries []sendEntry
+	if err := decodeField(input, "Entries", &entries); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q, err := s.lookupQueue(input)
+	if err != nil {
+		return nil, err
+	}
+	successful := make([]sendResult, 0, len(entries))
+	for _, entry := range entries {
+		successful = append(successful, s.enqueue(q, entry))
+	}
+	return map[string]any{"Successful": successful, "Failed": []any{}}, nil
+}
+
+// enqueue adds a message to q. s.mu must be held.
+func (s *Server) enqueue(q *queue, entry sendEntry) sendResult {
+	m := &message{
+		id:         uuid.New().String(),
+		body:       entry.MessageBody,
+		attributes: entry.MessageAttributes,
+		visibleAt:  time.Now().Add(time.Duration(entry.DelaySeconds) * time.Second),
+	}
+	q.messages = append(q.messages, m)
+	close(s.notify)
+	s.notify = make(chan struct{})
+	return sendResult{ID: entry.ID, MessageID: m.id, MD5OfMessageBody: md5Hex(m.body)}
+}
+
+type receiveInput struct {
+	MaxNumberOfMessages   int      `json:"MaxNumberOfMessages"`
+	WaitTimeSeconds       *int     `json:"WaitTimeSeconds"`
+	VisibilityTimeout     *int     `json:"VisibilityTimeout"`
+	MessageAttributeNames []string `json:"MessageAttributeNames"`
+}
+
+type receivedMessage struct {
+	MessageID         string                     `json:"MessageId"`
+	ReceiptHandle     string                     `json:"ReceiptHandle"`
+	MD5OfBody         string                     `json:"MD5OfBody"`
+	Body              string                     `json:"Body"`
+	Attributes        map[string]string          `json:"Attributes,omitempty"`
+	MessageAttributes map[string]json.RawMessage `json:"MessageAttributes,omitempty"`
+}
+
+// receiveMessage long polls for WaitTimeSeconds, or the queue's
+// ReceiveMessageWaitTimeSeconds, until a message is visible.
+func (s *Server) receiveMessage(r *http.Request, input map[string]json.RawMessage) (any, *apiError) {
+	var in receiveInput
+	if err := decodeInput(input, &in); err != nil {
+		return nil, err
+	}
+	maxMessages := min(max(in.MaxNumberOfMessages, 1), 10)
+
+	s.mu.Lock()
+	q, err := s.lookupQueue(input)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	waitTime := queueSeconds(q, "ReceiveMessageWaitTimeSeconds", in.WaitTimeSeconds, 0)
+	visibilityTimeout := queueSeconds(q, "VisibilityTimeout", in.VisibilityTimeout, defaultVisibilityTimeout)
+	deadline := time.NewTimer(min(waitTime, maxWaitTime))
+	defer deadline.Stop()
+
+	for {
+		messages := receive(q, maxMessages, visibilityTimeout, in.MessageAttributeNames)
+		notify := s.notify
+		s.mu.Unlock()
+		if len(messages) > 0 {
+			return map[string]any{"Messages": messages}, nil
+		}
+		select {
+		case <-notify:
+		case <-deadline.C:
+			return struct{}{}, nil
+		case <-r.Context().Done():
+			return struct{}{}, nil
+		}
+		s.mu.Lock()
+		if s.queues[q.name] != q {
+			s.mu.Unlock()
+			return nil, errorf("QueueDoesNotExist", "the specified queue does not exist")
+		}
+	}
+}
+
+// receive takes up to maxMessages visible messages from q, hiding them for
+// visibilityTimeout. s.mu must be held.
+func receive(q *queue, maxMessages int, visibilityTimeout time.Duration, attributeNames []string) []receivedMessage {
+	now := time.Now()
+	var messages []receivedMessage
+	for _, m := range q.messages {
+		if len(messages) == maxMessages {
+			break
+		}
+		if m.visibleAt.After(now) {
+			continue
+		}
+		m.receiptHandle = uuid.New().String()
+		m.visibleAt = now.Add(visibilityTimeout)
+		m.receiveCount++
+		messages = append(messages, receivedMessage{
+			MessageID:     m.id,
+			ReceiptHandle: m.receiptHandle,
+			MD5OfBody:     md5Hex(m.body),
+			Body:          m.body,
+			Attributes: map[string]string{
+				"ApproximateReceiveCount": strconv.Itoa(m.receiveCount),
+			},
+			MessageAttributes: selectAttributes(m.attributes, attributeNames),
+		})
+	}
+	return messages
+}
+
+// selectAttributes returns the message attributes matching names, which hold
+// attribute names, "All", ".*", or prefixes such as "prefix.*".
+func selectAttributes(attributes map[string]json.RawMessage, names []string) map[string]json.RawMessage {
+	selected := make(map[string]json.RawMessage)
+	for key, value := range attributes {
+		for _, name := range names {
+			prefix, wildcard := strings.CutSuffix(name, ".*")
+			if name == "All" || name == key || (wildcard && strings.HasPrefix(key, prefix)) {
+				selected[key] = value
+				break
+			}
+		}
+	}
+	return selected
+}
+
+func (s *Server) deleteMessage(input map[string]json.RawMessage) (any, *apiError) {
+	var receiptHandle string
+	if err := decodeField(input, "ReceiptHandle", &receiptHandle); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q, err := s.lookupQueue(input)
+	if err != nil {
+		return nil, err
+	}
+	for i, m := range q.messages {
+		if m.receiptHandle == receiptHandle {
+			q.messages = append(q.messages[:i], q.messages[i+1:]...)
+			break
+		}
+	}
+	return struct{}{}, nil
+}
+
+func (s *Server) changeMessageVisibility(input map[string]json.RawMessage) (any, *apiError) {
+	var in struct {
+		ReceiptHandle     string `json:"ReceiptHandle"`
+		VisibilityTimeout int    `json:"VisibilityTimeout"`
+	}
+	if err := decodeInput(input, &in); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q, err := s.lookupQueue(input)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range q.messages {
+		if m.receiptHandle == in.ReceiptHandle {
+			m.visibleAt = time.Now().Add(time.Duration(in.VisibilityTimeout) * time.Second)
+			if in.VisibilityTimeout == 0 {
+				close(s.notify)
+				s.notify = make(chan struct{})
+			}
+			return struct{}{}, nil
+		}
+	}
+	return nil, errorf("ReceiptHandleIsInvalid", "the receipt handle is not valid")
+}
+
+// queueSeconds returns the request's value in seconds, or else the queue's
+// attribute, or else fallback.
+func queueSeconds(q *queue, attribute string, requested *int, fallback time.Duration) time.Duration {
+	if requested != nil {
+		return time.Duration(*requested) * time.Second
+	}
+	if v, err := strconv.Atoi(q.attributes[attribute]); err == nil {
+		return time.Duration(v) * time.Second
+	}
+	return fallback
+}
+
+func decodeField(input map[string]json.RawMessage, field string, v any) *apiError {
+	raw, ok := input[field]
+	if !ok {
+		return errorf("MissingParameter", "the request must contain the parameter %s", field)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return errorf("InvalidParameterValue", "invalid value for the parameter %s", field)
+	}
+	return nil
+}
+
+func decodeInput(input map[string]json.RawMessage, v any) *apiError {
+	raw, _ := json.Marshal(input)
+	if err := json.Unmarshal(raw, v); err != nil {
+		return errorf("InvalidParameterValue", "invalid request parameters")
+	}
+	return nil
+}
+
+func md5Hex(s string) string {
+	sum := md5.Sum([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
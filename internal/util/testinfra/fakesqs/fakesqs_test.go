@@ -0,0 +1,127 @@
+package fakesqs_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/service/sqs"
+	"github.com/aws/aws-sdk-go-v2/service/sqs/types"
+	"github.com/hookdeck/outpost/internal/util/testinfra/fakesqs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T) *sqs.Client {
+	t.Helper()
+	server := fakesqs.New()
+	t.Cleanup(server.Close)
+	return sqs.New(sqs.Options{
+		Region:       "us-east-1",
+		BaseEndpoint: aws.String(server.URL()),
+		Credentials:  credentials.NewStaticCredentialsProvider("test", "test", ""),
+	})
+}
+
+func createQueue(t *testing.T, client *sqs.Client, name string) string {
+	t.Helper()
+	out, err := client.CreateQueue(t.Context(), &sqs.CreateQueueInput{QueueName: aws.String(name)})
+	require.NoError(t, err)
+	return *out.QueueUrl
+}
+
+func TestServer(t *testing.T) {
+	t.Parallel()
+
+	t.Run("looks up queues", func(t *testing.T) {
+		t.Parallel()
+		client := newClient(t)
+
+		_, err := client.GetQueueUrl(t.Context(), &sqs.GetQueueUrlInput{QueueName: aws.String("orders")})
+		var notExist *types.QueueDoesNotExist
+		require.ErrorAs(t, err, &notExist)
+
+		queueURL := createQueue(t, client, "orders")
+		out, err := client.GetQueueUrl(t.Context(), &sqs.GetQueueUrlInput{QueueName: aws.String("orders")})
+		require.NoError(t, err)
+		assert.Equal(t, queueURL, *out.QueueUrl)
+
+		_, err = client.DeleteQueue(t.Context(), &sqs.DeleteQueueInput{QueueUrl: aws.String(queueURL)})
+		require.NoError(t, err)
+		_, err = client.GetQueueUrl(t.Context(), &sqs.GetQueueUrlInput{QueueName: aws.String("orders")})
+		require.ErrorAs(t, err, &notExist)
+	})
+
+	t.Run("delivers messages with their attributes", func(t *testing.T) {
+		t.Parallel()
+		client := newClient(t)
+		queueURL := createQueue(t, client, "orders")
+
+		_, err := client.SendMessage(t.Context(), &sqs.SendMessageInput{
+			QueueUrl:    aws.String(queueURL),
+			MessageBody: aws.String(`{"id":"order_1"}`),
+			MessageAttributes: map[string]types.MessageAttributeValue{
+				"metadata": {DataType: aws.String("String"), StringValue: aws.String(`{"topic":"order.created"}`)},
+			},
+		})
+		require.NoError(t, err)
+
+		out, err := client.ReceiveMessage(t.Context(), &sqs.ReceiveMessageInput{
+			QueueUrl:              aws.String(queueURL),
+			MessageAttributeNames: []string{"All"},
+		})
+		require.NoError(t, err)
+		require.Len(t, out.Messages, 1)
+		assert.Equal(t, `{"id":"order_1"}`, *out.Messages[0].Body)
+		assert.Equal(t, `{"topic":"order.created"}`, *out.Messages[0].MessageAttributes["metadata"].StringValue)
+
+		out, err = client.ReceiveMessage(t.Context(), &sqs.ReceiveMessageInput{QueueUrl: aws.String(queueURL)})
+		require.NoError(t, err)
+		assert.Empty(t, out.Messages, "a received message is hidden until its visibility timeout")
+	})
+
+	t.Run("redelivers messages that aren't deleted", func(t *testing.T) {
+		t.Parallel()
+		client := newClient(t)
+		queueURL := createQueue(t, client, "orders")
+
+		for _, body := range []string{"first", "second"} {
+			_, err := client.SendMessage(t.Context(), &sqs.SendMessageInput{QueueUrl: aws.String(queueURL), MessageBody: aws.String(body)})
+			require.NoError(t, err)
+		}
+		out, err := client.ReceiveMessage(t.Context(), &sqs.ReceiveMessageInput{QueueUrl: aws.String(queueURL), MaxNumberOfMessages: 10})
+		require.NoError(t, err)
+		require.Len(t, out.Messages, 2)
+
+		_, err = client.DeleteMessage(t.Context(), &sqs.DeleteMessageInput{QueueUrl: aws.String(queueURL), ReceiptHandle: out.Messages[0].ReceiptHandle})
+		require.NoError(t, err)
+		_, err = client.ChangeMessageVisibility(t.Context(), &sqs.ChangeMessageVisibilityInput{
+			QueueUrl:          aws.String(queueURL),
+			ReceiptHandle:     out.Messages[1].ReceiptHandle,
+			VisibilityTimeout: 0,
+		})
+		require.NoError(t, err)
+
+		out, err = client.ReceiveMessage(t.Context(), &sqs.ReceiveMessageInput{QueueUrl: aws.String(queueURL), MaxNumberOfMessages: 10})
+		require.NoError(t, err)
+		require.Len(t, out.Messages, 1)
+		assert.Equal(t, "second", *out.Messages[0].Body)
+	})
+
+	t.Run("long polls for messages", func(t *testing.T) {
+		t.Parallel()
+		client := newClient(t)
+		queueURL := createQueue(t, client, "orders")
+
+		go func() {
+			time.Sleep(100 * time.Millisecond)
+			client.SendMessage(context.Background(), &sqs.SendMessageInput{QueueUrl: aws.String(queueURL), MessageBody: aws.String("late")})
+		}()
+		out, err := client.ReceiveMessage(t.Context(), &sqs.ReceiveMessageInput{QueueUrl: aws.String(queueURL), WaitTimeSeconds: 5})
+		require.NoError(t, err)
+		require.Len(t, out.Messages, 1)
+		assert.Equal(t, "late", *out.Messages[0].Body)
+	})
+}
@@ -11,7 +11,12 @@ import (
 
 var kafkaOnce sync.Once
 
+// EnsureKafka returns the address of a Kafka broker: an in-memory fake in
+// simulation mode, a container otherwise.
 func EnsureKafka() string {
+	if Simulated {
+		return fakeKafka()
+	}
 	cfg := ReadConfig()
 	if cfg.KafkaURL == "" {
 		kafkaOnce.Do(func() {
@@ -0,0 +1,53 @@
+package testinfra
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+var mqttOnce sync.Once
+
+func EnsureMQTT() string {
+	cfg := ReadConfig()
+	if cfg.MQTTURL == "" {
+		mqttOnce.Do(func() {
+			startMQTTTestContainer(cfg)
+		})
+	}
+	return cfg.MQTTURL
+}
+
+func startMQTTTestContainer(cfg *Config) {
+	ctx := context.Background()
+
+	req := testcontainers.ContainerRequest{
+		Image:        "eclipse-mosquitto:2",
+		Cmd:          []string{"mosquitto", "-c", "/mosquitto-no-auth.conf"},
+		ExposedPorts: []string{"1883/tcp"},
+		WaitingFor:   wait.ForListeningPort("1883/tcp"),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	endpoint, err := container.PortEndpoint(ctx, "1883/tcp", "tcp")
+	if err != nil {
+		panic(err)
+	}
+	log.Printf("MQTT broker running at %s", endpoint)
+	cfg.MQTTURL = endpoint
+	cfg.cleanupFns = append(cfg.cleanupFns, func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate mqtt container: %s", err)
+		}
+	})
+}
@@ -0,0 +1,53 @@
+package testinfra
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/testcontainers/testcontainers-go"
+	"github.com/testcontainers/testcontainers-go/wait"
+)
+
+var natsOnce sync.Once
+
+func EnsureNATS() string {
+	cfg := ReadConfig()
+	if cfg.NATSURL == "" {
+		natsOnce.Do(func() {
+			startNATSTestContainer(cfg)
+		})
+	}
+	return cfg.NATSURL
+}
+
+func startNATSTestContainer(cfg *Config) {
+	ctx := context.Background()
+
+	req := testcontainers.ContainerRequest{
+		Image:        "nats:2",
+		Cmd:          []string{"-js"},
+		ExposedPorts: []string{"4222/tcp"},
+		WaitingFor:   wait.ForListeningPort("4222/tcp"),
+	}
+
+	container, err := testcontainers.GenericContainer(ctx, testcontainers.GenericContainerRequest{
+		ContainerRequest: req,
+		Started:          true,
+	})
+	if err != nil {
+		panic(err)
+	}
+
+	endpoint, err := container.PortEndpoint(ctx, "4222/tcp", "nats")
+	if err != nil {
+		panic(err)
+	}
+	log.Printf("NATS running at %s", endpoint)
+	cfg.NATSURL = endpoint
+	cfg.cleanupFns = append(cfg.cleanupFns, func() {
+		if err := container.Terminate(ctx); err != nil {
+			log.Printf("failed to terminate nats container: %s", err)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package testinfra
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/hookdeck/outpost/internal/util/testinfra/fakekafka"
+	"github.com/hookdeck/outpost/internal/util/testinfra/fakesqs"
+)
+
+// Simulation mode, enabled with the simulation build tag, runs the suites of
+// the backends that have an in-memory fake offline, without Docker:
+//
+//	go test -short -tags simulation ./...
+//
+// Suites opt in with StartSimulation and keep running under -short, since
+// their fakes start instantly. Every other suite started with Start is
+// skipped.
+
+var (
+	fakeSQSOnce sync.Once
+	fakeSQSURL  string
+
+	fakeKafkaOnce sync.Once
+	fakeKafkaAddr string
+)
+
+// StartSimulation is Start for suites whose infrastructure is simulated. In
+// simulation mode they run offline, even with -short.
+func StartSimulation(t *testing.T) func() {
+	if !Simulated {
+		return Start(t)
+	}
+	return func() {}
+}
+
+// EnsureSQS returns the endpoint of an SQS server: an in-memory fake in
+// simulation mode, LocalStack otherwise.
+func EnsureSQS() string {
+	if !Simulated {
+		return EnsureLocalStack()
+	}
+	// The fake lives as long as the test binary, so suites sharing it
+	// don't have to coordinate its shutdown.
+	fakeSQSOnce.Do(func() {
+		fakeSQSURL = fakesqs.New().URL()
+	})
+	return fakeSQSURL
+}
+
+// fakeKafka returns the address of the in-memory Kafka broker EnsureKafka
+// returns in simulation mode.
+func fakeKafka() string {
+	// Like the SQS fake, the broker lives as long as the test binary.
+	fakeKafkaOnce.Do(func() {
+		fakeKafkaAddr = fakekafka.New().Addr()
+	})
+	return fakeKafkaAddr
+}
@@ -0,0 +1,6 @@
+//go:build !simulation
+
+package testinfra
+
+// Simulated reports whether the tests run in simulation mode.
+const Simulated = false
@@ -24,9 +24,12 @@ type Config struct {
 	TestAzure         bool
 	ClickHouseURL     string
 	PostgresURL       string
+	BigQueryURL       string
 	LocalStackURL     string
 	RabbitMQURL       string
 	KafkaURL          string
+	MQTTURL           string
+	NATSURL           string
 	MockServerURL     string
 	GCPURL            string
 	AzureSBConnString string
@@ -63,6 +66,18 @@ func initConfig() {
 		if !strings.Contains(rabbitmqURL, "amqp://") {
 			rabbitmqURL = "amqp://guest:guest@" + rabbitmqURL
 		}
+		mqttURL := v.GetString("TEST_MQTT_URL")
+		if !strings.Contains(mqttURL, "tcp://") {
+			mqttURL = "tcp://" + mqttURL
+		}
+		natsURL := v.GetString("TEST_NATS_URL")
+		if !strings.Contains(natsURL, "nats://") {
+			natsURL = "nats://" + natsURL
+		}
+		bigqueryURL := v.GetString("TEST_BIGQUERY_URL")
+		if bigqueryURL != "" && !strings.Contains(bigqueryURL, "http://") {
+			bigqueryURL = "http://" + bigqueryURL
+		}
 		mockServerURL := v.GetString("TEST_MOCKSERVER_URL")
 		if !strings.Contains(mockServerURL, "http://") {
 			mockServerURL = "http://" + mockServerURL
@@ -72,11 +87,14 @@ func initConfig() {
 			TestAzure:         v.GetBool("TESTAZURE"),
 			ClickHouseURL:     v.GetString("TEST_CLICKHOUSE_URL"),
 			PostgresURL:       v.GetString("TEST_POSTGRES_URL"),
+			BigQueryURL:       bigqueryURL,
 			LocalStackURL:     localstackURL,
 			GCPURL:            v.GetString("TEST_GCP_URL"),
 			AzureSBConnString: v.GetString("TEST_AZURE_SB_CONNSTRING"),
 			RabbitMQURL:       rabbitmqURL,
 			KafkaURL:          v.GetString("TEST_KAFKA_URL"),
+			MQTTURL:           mqttURL,
+			NATSURL:           natsURL,
 			MockServerURL:     mockServerURL,
 		}
 		return
@@ -87,11 +105,14 @@ func initConfig() {
 		TestAzure:         v.GetBool("TESTAZURE"),
 		ClickHouseURL:     "",
 		PostgresURL:       "",
+		BigQueryURL:       "",
 		LocalStackURL:     "",
 		GCPURL:            "",
 		AzureSBConnString: "",
 		RabbitMQURL:       "",
 		KafkaURL:          "",
+		MQTTURL:           "",
+		NATSURL:           "",
 		MockServerURL:     "",
 	}
 }
@@ -102,6 +123,9 @@ func ReadConfig() *Config {
 }
 
 func Start(t *testing.T) func() {
+	if Simulated {
+		t.Skip("skipping integration test: its infrastructure isn't simulated")
+	}
 	testutil.CheckIntegrationTest(t)
 	atomic.AddInt64(&suiteCounter, 1)
 	return func() {
@@ -72,6 +72,18 @@ func (f *mockEventFactory) WithTopic(topic string) func(*models.Event) {
 	}
 }
 
+func (f *mockEventFactory) WithSource(source string) func(*models.Event) {
+	return func(event *models.Event) {
+		event.Source = source
+	}
+}
+
+func (f *mockEventFactory) WithGroupID(groupID string) func(*models.Event) {
+	return func(event *models.Event) {
+		event.GroupID = groupID
+	}
+}
+
 func (f *mockEventFactory) WithEligibleForRetry(eligibleForRetry bool) func(*models.Event) {
 	return func(event *models.Event) {
 		event.EligibleForRetry = eligibleForRetry
@@ -198,3 +210,9 @@ func (f *mockAttemptFactory) WithTime(time time.Time) func(*models.Attempt) {
 		attempt.Time = time
 	}
 }
+
+func (f *mockAttemptFactory) WithLatencyMs(latencyMs int64) func(*models.Attempt) {
+	return func(attempt *models.Attempt) {
+		attempt.LatencyMs = &latencyMs
+	}
+}
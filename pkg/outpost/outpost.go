@@ -0,0 +1,166 @@
+// Package outpost runs Outpost inside another Go program.
+//
+// The outpost binary is a thin wrapper around the same services: an embedding
+// program loads or builds a Config, optionally injects its own Redis client,
+// stores and logger, and then controls the lifecycle itself:
+//
+//	o := outpost.New(cfg, outpost.WithRedisClient(client))
+//	if err := o.Start(ctx); err != nil {
+//		return err
+//	}
+//	defer o.Shutdown()
+//	mux.Handle("/outpost/", http.StripPrefix("/outpost", o.Handler()))
+//	return o.Run(ctx)
+//
+// Which services run (API, delivery, log or all) follows Config.Service, as
+// it does for the binary. ID generation and OpenTelemetry are configured
+// process-wide, so a process embeds at most one Outpost.
+package outpost
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/hookdeck/outpost/internal/apirouter"
+	"github.com/hookdeck/outpost/internal/app"
+	"github.com/hookdeck/outpost/internal/config"
+	"github.com/hookdeck/outpost/internal/deliveryhook"
+	"github.com/hookdeck/outpost/internal/logging"
+	"github.com/hookdeck/outpost/internal/logstore"
+	"github.com/hookdeck/outpost/internal/models"
+	"github.com/hookdeck/outpost/internal/redis"
+	"github.com/hookdeck/outpost/internal/services"
+	"github.com/hookdeck/outpost/internal/tenantstore"
+)
+
+type (
+	// Config is the Outpost configuration, as documented for the binary.
+	Config = config.Config
+	// Logger is the structured logger Outpost writes to.
+	Logger = logging.Logger
+	// RedisClient is the Redis client Outpost stores its state with.
+	RedisClient = redis.Client
+	// LogStore stores events and delivery attempts.
+	LogStore = logstore.LogStore
+	// TenantStore stores tenants and their destinations.
+	TenantStore = tenantstore.TenantStore
+	// Route is an API endpoint. Unless Public is set, requests are
+	// authenticated like built-in endpoints: AdminOnly requires the API key
+	// and RequireTenant scopes the route to its :tenant_id.
+	Route = apirouter.RouteDefinition
+
+	// Delivery describes the delivery attempt a hook runs for.
+	Delivery = deliveryhook.Delivery
+	// Decision is what a pre-delivery hook decides: veto the delivery or
+	// add headers to the delivered event.
+	Decision = deliveryhook.Decision
+	// PreDeliveryHook runs before each delivery attempt.
+	PreDeliveryHook = deliveryhook.PreDeliveryHook
+	// PostDeliveryHook runs after each delivery attempt.
+	PostDeliveryHook = deliveryhook.PostDeliveryHook
+	// PreDeliveryFunc adapts a function to a PreDeliveryHook.
+	PreDeliveryFunc = deliveryhook.PreDeliveryFunc
+	// PostDeliveryFunc adapts a function to a PostDeliveryHook.
+	PostDeliveryFunc = deliveryhook.PostDeliveryFunc
+	// Attempt is a delivery attempt, as passed to post-delivery hooks.
+	Attempt = models.Attempt
+)
+
+// LoadConfig loads the configuration the way the outpost binary does:
+// defaults, then the YAML file at path (if not empty), then environment
+// variables. The result is validated.
+func LoadConfig(path string) (*Config, error) {
+	return config.Parse(config.Flags{Config: path})
+}
+
+// Option configures an embedded Outpost.
+type Option = app.Option
+
+// WithLogger replaces the logger built from Config.LogLevel.
+func WithLogger(logger *Logger) Option {
+	return app.WithLogger(logger)
+}
+
+// WithRedisClient shares an existing Redis client instead of connecting with
+// the Redis config. Outpost does not close it.
+func WithRedisClient(client RedisClient) Option {
+	return app.WithRedisClient(client)
+}
+
+// WithLogStore replaces the log store selected by the ClickHouse or Postgres
+// config. Outpost does not close it.
+func WithLogStore(logStore LogStore) Option {
+	return app.WithServiceBuilderOptions(services.WithLogStore(logStore))
+}
+
+// WithTenantStore replaces the Redis-backed tenant store. The store must
+// already be initialized.
+func WithTenantStore(tenantStore TenantStore) Option {
+	return app.WithServiceBuilderOptions(services.WithTenantStore(tenantStore))
+}
+
+// WithMiddleware adds Gin middlewares that run on every /api/v1 request
+// before authentication, such as extra request checks or logging.
+func WithMiddleware(middlewares ...gin.HandlerFunc) Option {
+	return app.WithServiceBuilderOptions(services.WithAPIMiddlewares(middlewares...))
+}
+
+// WithRoutes adds endpoints under /api/v1. A path already served by Outpost
+// panics when the API is built.
+func WithRoutes(routes ...Route) Option {
+	return app.WithServiceBuilderOptions(services.WithAPIRoutes(routes...))
+}
+
+// WithPreDeliveryHook adds hooks that run, in order and before any configured
+// HTTP hook, ahead of each delivery attempt. They share the time budget of
+// the delivery hooks config.
+func WithPreDeliveryHook(hooks ...PreDeliveryHook) Option {
+	return app.WithServiceBuilderOptions(services.WithPreDeliveryHooks(hooks...))
+}
+
+// WithPostDeliveryHook adds hooks that run after each delivery attempt, for
+// instance to record custom metrics.
+func WithPostDeliveryHook(hooks ...PostDeliveryHook) Option {
+	return app.WithServiceBuilderOptions(services.WithPostDeliveryHooks(hooks...))
+}
+
+// Outpost is an embedded Outpost deployment.
+type Outpost struct {
+	app *app.App
+}
+
+// New creates an embedded Outpost. Nothing connects until Start. Outpost
+// does not listen on Config.APIPort; the embedding program serves Handler().
+func New(cfg *Config, opts ...Option) *Outpost {
+	opts = append([]Option{app.WithServiceBuilderOptions(services.WithoutHTTPServer())}, opts...)
+	return &Outpost{app: app.New(cfg, opts...)}
+}
+
+// Start connects to the infrastructure, checks migrations and builds the
+// configured services.
+func (o *Outpost) Start(ctx context.Context) error {
+	return o.app.PreRun(ctx)
+}
+
+// Handler returns the HTTP handler serving the Outpost API and health
+// check. It is nil before Start.
+func (o *Outpost) Handler() http.Handler {
+	return o.app.Handler()
+}
+
+// Run runs the services until ctx is canceled, which is a clean stop, or a
+// service fails.
+func (o *Outpost) Run(ctx context.Context) error {
+	if err := o.app.RunWorkers(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// Shutdown flushes telemetry and releases what Start created. Injected
+// dependencies are left open.
+func (o *Outpost) Shutdown() {
+	o.app.PostRun(context.Background())
+}
@@ -50,6 +50,11 @@ cmd_unit() {
     run_tests "$TEST" -short
 }
 
+# Command: simulation - run unit tests and the simulated integration suites offline
+cmd_simulation() {
+    run_tests "$TEST" -short -tags simulation
+}
+
 # Command: e2e - run end-to-end tests
 cmd_e2e() {
     run_tests "./cmd/e2e"
@@ -73,6 +78,9 @@ case "${1:-test}" in
     unit)
         cmd_unit
         ;;
+    simulation)
+        cmd_simulation
+        ;;
     e2e)
         cmd_e2e
         ;;
@@ -80,11 +88,12 @@ case "${1:-test}" in
         cmd_full
         ;;
     *)
-        echo "Usage: $0 {test|unit|e2e|full}"
+        echo "Usage: $0 {test|unit|simulation|e2e|full}"
         echo ""
         echo "Commands:"
         echo "  test    Run unit + integration tests (default)"
         echo "  unit    Run tests with -short flag (skip long-running tests)"
+        echo "  simulation  Run unit tests and the integration suites with in-memory fakes, without Docker"
         echo "  e2e     Run end-to-end tests (./cmd/e2e)"
         echo "  full    Run full test suite with TESTCOMPAT=1 (test + e2e)"
         echo ""
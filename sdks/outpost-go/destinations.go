@@ -214,12 +214,12 @@ func (s *Destinations) List(ctx context.Context, tenantID string, type_ []compon
 				return nil, err
 			}
 
-			var out []components.Destination
+			var out components.DestinationPaginatedResult
 			if err := utils.UnmarshalJsonFromResponseBody(bytes.NewBuffer(rawBody), &out, ""); err != nil {
 				return nil, err
 			}
 
-			res.Destinations = out
+			res.DestinationPaginatedResult = &out
 		default:
 			rawBody, err := utils.ConsumeRawBody(httpRes)
 			if err != nil {
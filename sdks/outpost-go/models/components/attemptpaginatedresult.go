@@ -8,6 +8,8 @@ type AttemptPaginatedResult struct {
 	Pagination *SeekPagination `json:"pagination,omitempty"`
 	// Array of attempt objects.
 	Models []Attempt `json:"models,omitempty"`
+	// Deprecated: no longer returned. Attempt lists are paged with cursors and not counted.
+	Count *int64 `json:"count,omitempty"`
 }
 
 func (a *AttemptPaginatedResult) GetPagination() *SeekPagination {
@@ -23,3 +25,10 @@ func (a *AttemptPaginatedResult) GetModels() []Attempt {
 	}
 	return a.Models
 }
+
+func (a *AttemptPaginatedResult) GetCount() *int64 {
+	if a == nil {
+		return nil
+	}
+	return a.Count
+}
@@ -8,6 +8,8 @@ type EventPaginatedResult struct {
 	Pagination *SeekPagination `json:"pagination,omitempty"`
 	// Array of event objects.
 	Models []Event `json:"models,omitempty"`
+	// Deprecated: no longer returned. Event lists are paged with cursors and not counted.
+	Count *int64 `json:"count,omitempty"`
 }
 
 func (e *EventPaginatedResult) GetPagination() *SeekPagination {
@@ -23,3 +25,10 @@ func (e *EventPaginatedResult) GetModels() []Event {
 	}
 	return e.Models
 }
+
+func (e *EventPaginatedResult) GetCount() *int64 {
+	if e == nil {
+		return nil
+	}
+	return e.Count
+}
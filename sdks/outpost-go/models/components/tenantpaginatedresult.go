@@ -8,7 +8,7 @@ type TenantPaginatedResult struct {
 	Models []Tenant `json:"models,omitempty"`
 	// Cursor-based pagination metadata for list responses.
 	Pagination *SeekPagination `json:"pagination,omitempty"`
-	// Total count of all tenants.
+	// Total number of tenants matching the request, across all pages.
 	Count *int64 `json:"count,omitempty"`
 }
 
@@ -39,7 +39,7 @@ func (l *ListTenantDestinationsRequest) GetTopics() []string {
 type ListTenantDestinationsResponse struct {
 	HTTPMeta components.HTTPMetadata `json:"-"`
 	// A list of destinations.
-	Destinations []components.Destination
+	DestinationPaginatedResult *components.DestinationPaginatedResult
 }
 
 func (l *ListTenantDestinationsResponse) GetHTTPMeta() components.HTTPMetadata {
@@ -49,9 +49,9 @@ func (l *ListTenantDestinationsResponse) GetHTTPMeta() components.HTTPMetadata {
 	return l.HTTPMeta
 }
 
-func (l *ListTenantDestinationsResponse) GetDestinations() []components.Destination {
+func (l *ListTenantDestinationsResponse) GetDestinationPaginatedResult() *components.DestinationPaginatedResult {
 	if l == nil {
 		return nil
 	}
-	return l.Destinations
+	return l.DestinationPaginatedResult
 }
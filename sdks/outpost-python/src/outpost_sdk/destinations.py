@@ -5,7 +5,7 @@ from outpost_sdk import errors, models, utils
 from outpost_sdk._hooks import HookContext
 from outpost_sdk.types import BaseModel, OptionalNullable, UNSET
 from outpost_sdk.utils.unmarshal_json_response import unmarshal_json_response
-from typing import Any, List, Mapping, Optional, Union, cast
+from typing import Any, Mapping, Optional, Union, cast
 
 
 class Destinations(BaseSDK):
@@ -37,7 +37,7 @@ class Destinations(BaseSDK):
         server_url: Optional[str] = None,
         timeout_ms: Optional[int] = None,
         http_headers: Optional[Mapping[str, str]] = None,
-    ) -> List[models.Destination]:
+    ) -> models.DestinationPaginatedResult:
         r"""List Destinations
 
         Return a list of the destinations for the tenant. The endpoint is not paged.
@@ -110,7 +110,7 @@ class Destinations(BaseSDK):
 
         response_data: Any = None
         if utils.match_response(http_res, "200", "application/json"):
-            return unmarshal_json_response(List[models.Destination], http_res)
+            return unmarshal_json_response(models.DestinationPaginatedResult, http_res)
         if utils.match_response(http_res, "401", "application/json"):
             response_data = unmarshal_json_response(
                 errors.UnauthorizedErrorData, http_res
@@ -153,7 +153,7 @@ class Destinations(BaseSDK):
         server_url: Optional[str] = None,
         timeout_ms: Optional[int] = None,
         http_headers: Optional[Mapping[str, str]] = None,
-    ) -> List[models.Destination]:
+    ) -> models.DestinationPaginatedResult:
         r"""List Destinations
 
         Return a list of the destinations for the tenant. The endpoint is not paged.
@@ -226,7 +226,7 @@ class Destinations(BaseSDK):
 
         response_data: Any = None
         if utils.match_response(http_res, "200", "application/json"):
-            return unmarshal_json_response(List[models.Destination], http_res)
+            return unmarshal_json_response(models.DestinationPaginatedResult, http_res)
         if utils.match_response(http_res, "401", "application/json"):
             response_data = unmarshal_json_response(
                 errors.UnauthorizedErrorData, http_res
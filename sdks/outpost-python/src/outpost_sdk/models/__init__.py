@@ -154,6 +154,10 @@ if TYPE_CHECKING:
         DestinationKafkaType,
         DestinationKafkaTypedDict,
     )
+    from .destinationpaginatedresult import (
+        DestinationPaginatedResult,
+        DestinationPaginatedResultTypedDict,
+    )
     from .destinationrabbitmq import (
         DestinationRabbitMQ,
         DestinationRabbitMQType,
@@ -565,6 +569,8 @@ __all__ = [
     "DestinationKafka",
     "DestinationKafkaType",
     "DestinationKafkaTypedDict",
+    "DestinationPaginatedResult",
+    "DestinationPaginatedResultTypedDict",
     "DestinationRabbitMQ",
     "DestinationRabbitMQType",
     "DestinationRabbitMQTypedDict",
@@ -940,6 +946,8 @@ _dynamic_imports: dict[str, str] = {
     "DestinationKafka": ".destinationkafka",
     "DestinationKafkaType": ".destinationkafka",
     "DestinationKafkaTypedDict": ".destinationkafka",
+    "DestinationPaginatedResult": ".destinationpaginatedresult",
+    "DestinationPaginatedResultTypedDict": ".destinationpaginatedresult",
     "DestinationRabbitMQ": ".destinationrabbitmq",
     "DestinationRabbitMQType": ".destinationrabbitmq",
     "DestinationRabbitMQTypedDict": ".destinationrabbitmq",
@@ -16,6 +16,8 @@ class AttemptPaginatedResultTypedDict(TypedDict):
     r"""Cursor-based pagination metadata for list responses."""
     models: NotRequired[List[AttemptTypedDict]]
     r"""Array of attempt objects."""
+    count: NotRequired[int]
+    r"""Deprecated: no longer returned. Attempt lists are paged with cursors and not counted."""
 
 
 class AttemptPaginatedResult(BaseModel):
@@ -27,9 +29,12 @@ class AttemptPaginatedResult(BaseModel):
     models: Optional[List[Attempt]] = None
     r"""Array of attempt objects."""
 
+    count: Optional[int] = None
+    r"""Deprecated: no longer returned. Attempt lists are paged with cursors and not counted."""
+
     @model_serializer(mode="wrap")
     def serialize_model(self, handler):
-        optional_fields = set(["pagination", "models"])
+        optional_fields = set(["pagination", "models", "count"])
         serialized = handler(self)
         m = {}
 
@@ -16,6 +16,8 @@ class EventPaginatedResultTypedDict(TypedDict):
     r"""Cursor-based pagination metadata for list responses."""
     models: NotRequired[List[EventTypedDict]]
     r"""Array of event objects."""
+    count: NotRequired[int]
+    r"""Deprecated: no longer returned. Event lists are paged with cursors and not counted."""
 
 
 class EventPaginatedResult(BaseModel):
@@ -27,9 +29,12 @@ class EventPaginatedResult(BaseModel):
     models: Optional[List[Event]] = None
     r"""Array of event objects."""
 
+    count: Optional[int] = None
+    r"""Deprecated: no longer returned. Event lists are paged with cursors and not counted."""
+
     @model_serializer(mode="wrap")
     def serialize_model(self, handler):
-        optional_fields = set(["pagination", "models"])
+        optional_fields = set(["pagination", "models", "count"])
         serialized = handler(self)
         m = {}
 
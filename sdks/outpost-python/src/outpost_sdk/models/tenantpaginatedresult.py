@@ -17,7 +17,7 @@ class TenantPaginatedResultTypedDict(TypedDict):
     pagination: NotRequired[SeekPaginationTypedDict]
     r"""Cursor-based pagination metadata for list responses."""
     count: NotRequired[int]
-    r"""Total count of all tenants."""
+    r"""Total number of tenants matching the request, across all pages."""
 
 
 class TenantPaginatedResult(BaseModel):
@@ -30,7 +30,7 @@ class TenantPaginatedResult(BaseModel):
     r"""Cursor-based pagination metadata for list responses."""
 
     count: Optional[int] = None
-    r"""Total count of all tenants."""
+    r"""Total number of tenants matching the request, across all pages."""
 
     @model_serializer(mode="wrap")
     def serialize_model(self, handler):
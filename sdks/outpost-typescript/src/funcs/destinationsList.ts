@@ -2,7 +2,6 @@
  * Code generated by Speakeasy (https://speakeasy.com). DO NOT EDIT.
  */
 
-import * as z from "zod/v3";
 import { OutpostCore } from "../core.js";
 import { encodeFormQuery, encodeSimple } from "../lib/encodings.js";
 import { matchStatusCode } from "../lib/http.js";
@@ -42,7 +41,7 @@ export function destinationsList(
   options?: RequestOptions,
 ): APIPromise<
   Result<
-    Array<components.Destination>,
+    components.DestinationPaginatedResult,
     | errors.UnauthorizedError
     | errors.NotFoundError
     | errors.InternalServerError
@@ -74,7 +73,7 @@ async function $do(
 ): Promise<
   [
     Result<
-      Array<components.Destination>,
+      components.DestinationPaginatedResult,
       | errors.UnauthorizedError
       | errors.NotFoundError
       | errors.InternalServerError
@@ -177,7 +176,7 @@ async function $do(
   };
 
   const [result] = await M.match<
-    Array<components.Destination>,
+    components.DestinationPaginatedResult,
     | errors.UnauthorizedError
     | errors.NotFoundError
     | errors.InternalServerError
@@ -190,7 +189,7 @@ async function $do(
     | UnexpectedClientError
     | SDKValidationError
   >(
-    M.json(200, z.array(components.Destination$inboundSchema)),
+    M.json(200, components.DestinationPaginatedResult$inboundSchema),
     M.jsonErr(401, errors.UnauthorizedError$inboundSchema),
     M.jsonErr(404, errors.NotFoundError$inboundSchema),
     M.jsonErr(500, errors.InternalServerError$inboundSchema),
@@ -31,6 +31,10 @@ export type AttemptPaginatedResult = {
    * Array of attempt objects.
    */
   models?: Array<Attempt> | undefined;
+  /**
+   * Deprecated: no longer returned. Attempt lists are paged with cursors and not counted.
+   */
+  count?: number | undefined;
 };
 
 /** @internal */
@@ -41,11 +45,13 @@ export const AttemptPaginatedResult$inboundSchema: z.ZodType<
 > = z.object({
   pagination: SeekPagination$inboundSchema.optional(),
   models: z.array(Attempt$inboundSchema).optional(),
+  count: z.number().int().optional(),
 });
 /** @internal */
 export type AttemptPaginatedResult$Outbound = {
   pagination?: SeekPagination$Outbound | undefined;
   models?: Array<Attempt$Outbound> | undefined;
+  count?: number | undefined;
 };
 
 /** @internal */
@@ -56,6 +62,7 @@ export const AttemptPaginatedResult$outboundSchema: z.ZodType<
 > = z.object({
   pagination: SeekPagination$outboundSchema.optional(),
   models: z.array(Attempt$outboundSchema).optional(),
+  count: z.number().int().optional(),
 });
 
 export function attemptPaginatedResultToJSON(
@@ -31,6 +31,10 @@ export type EventPaginatedResult = {
    * Array of event objects.
    */
   models?: Array<Event> | undefined;
+  /**
+   * Deprecated: no longer returned. Event lists are paged with cursors and not counted.
+   */
+  count?: number | undefined;
 };
 
 /** @internal */
@@ -41,11 +45,13 @@ export const EventPaginatedResult$inboundSchema: z.ZodType<
 > = z.object({
   pagination: SeekPagination$inboundSchema.optional(),
   models: z.array(Event$inboundSchema).optional(),
+  count: z.number().int().optional(),
 });
 /** @internal */
 export type EventPaginatedResult$Outbound = {
   pagination?: SeekPagination$Outbound | undefined;
   models?: Array<Event$Outbound> | undefined;
+  count?: number | undefined;
 };
 
 /** @internal */
@@ -56,6 +62,7 @@ export const EventPaginatedResult$outboundSchema: z.ZodType<
 > = z.object({
   pagination: SeekPagination$outboundSchema.optional(),
   models: z.array(Event$outboundSchema).optional(),
+  count: z.number().int().optional(),
 });
 
 export function eventPaginatedResultToJSON(
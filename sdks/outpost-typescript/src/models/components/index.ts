@@ -38,6 +38,7 @@ export * from "./destinationcreatewebhook.js";
 export * from "./destinationgcppubsub.js";
 export * from "./destinationhookdeck.js";
 export * from "./destinationkafka.js";
+export * from "./destinationpaginatedresult.js";
 export * from "./destinationrabbitmq.js";
 export * from "./destinationschemafield.js";
 export * from "./destinationtype.js";
@@ -32,7 +32,7 @@ export type TenantPaginatedResult = {
    */
   pagination?: SeekPagination | undefined;
   /**
-   * Total count of all tenants.
+   * Total number of tenants matching the request, across all pages.
    */
   count?: number | undefined;
 };
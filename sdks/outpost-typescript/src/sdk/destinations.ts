@@ -28,7 +28,7 @@ export class Destinations extends ClientSDK {
     type?: operations.ListTenantDestinationsType | undefined,
     topics?: operations.Topics | undefined,
     options?: RequestOptions,
-  ): Promise<Array<components.Destination>> {
+  ): Promise<components.DestinationPaginatedResult> {
     return unwrapAsync(destinationsList(
       this,
       tenantId,
@@ -95,7 +95,8 @@ export class SdkClient {
   async listDestinations(params?: {
     type?: DestinationType | DestinationType[];
   }): Promise<Destination[]> {
-    return await this.sdk.destinations.list(this.tenantId, params?.type);
+    const result = await this.sdk.destinations.list(this.tenantId, params?.type);
+    return result.models ?? [];
   }
 
   /**